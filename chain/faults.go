@@ -0,0 +1,139 @@
+package chain
+
+import (
+	"math/rand"
+	"time"
+)
+
+// reorderFlushDelay is how long a run of block connected notifications held
+// back to be reordered waits for the next notification before it is sent.
+const reorderFlushDelay = 100 * time.Millisecond
+
+// Faults are the faults an RPCClient injects into its connection and its
+// notifications, so that the resilience of the wallet sync can be tested
+// deterministically.  They are meant for development only.  The zero value
+// injects no fault.
+type Faults struct {
+	// NotifyDelay delays each notification by a random duration of up to
+	// this long.
+	NotifyDelay time.Duration
+
+	// DropEvery drops the websocket connection after every DropEvery
+	// notifications, so that the client reconnects and the wallet syncs
+	// again.
+	DropEvery int
+
+	// ReorderWindow shuffles the runs of up to ReorderWindow consecutive
+	// block connected notifications, which are sent out of order.
+	ReorderWindow int
+
+	// Seed seeds the PRNG choosing the delays and the orders, so that a
+	// run given the same notifications injects the same faults.
+	Seed int64
+}
+
+// enabled returns whether any fault is injected.
+func (f *Faults) enabled() bool {
+	return f.NotifyDelay > 0 || f.DropEvery > 0 || f.ReorderWindow > 1
+}
+
+// InjectFaults makes the client inject faults into its connection and its
+// notifications.  It must be called before Start.
+func (c *RPCClient) InjectFaults(f Faults) {
+	if !f.enabled() {
+		return
+	}
+	c.faults = f
+	c.faultyNotification = make(chan interface{})
+}
+
+// faultInjector forwards the notifications of the handler, injecting the
+// faults of the client.
+func (c *RPCClient) faultInjector() {
+	defer c.wg.Done()
+	defer close(c.faultyNotification)
+
+	log.Warnf("Injecting faults into the notifications of %s: %+v",
+		c.connConfig.Host, c.faults)
+
+	rng := rand.New(rand.NewSource(c.faults.Seed))
+	sent := 0
+	send := func(n interface{}) bool {
+		if c.faults.NotifyDelay > 0 {
+			delay := time.Duration(rng.Int63n(
+				int64(c.faults.NotifyDelay)))
+			select {
+			case <-time.After(delay):
+			case <-c.quit:
+				return false
+			}
+		}
+		select {
+		case c.faultyNotification <- n:
+		case <-c.quit:
+			return false
+		}
+		sent++
+		if c.faults.DropEvery > 0 && sent%c.faults.DropEvery == 0 {
+			log.Warnf("Dropping the connection to %s after %d "+
+				"notifications", c.connConfig.Host, sent)
+			c.Client.Disconnect()
+		}
+		return true
+	}
+
+	// held is the run of block connected notifications to be reordered.
+	var held []interface{}
+	flush := func() bool {
+		rng.Shuffle(len(held), func(i, j int) {
+			held[i], held[j] = held[j], held[i]
+		})
+		for _, n := range held {
+			if !send(n) {
+				return false
+			}
+		}
+		held = held[:0]
+		return true
+	}
+
+	for {
+		var timeout <-chan time.Time
+		if len(held) != 0 {
+			timeout = time.After(reorderFlushDelay)
+		}
+
+		var n interface{}
+		var ok bool
+		select {
+		case n, ok = <-c.dequeueNotification:
+		case <-timeout:
+			if !flush() {
+				return
+			}
+			continue
+		case <-c.quit:
+			return
+		}
+		if !ok {
+			flush()
+			return
+		}
+
+		if _, block := n.(BlockConnected); block &&
+			c.faults.ReorderWindow > 1 {
+
+			held = append(held, n)
+			if len(held) < c.faults.ReorderWindow {
+				continue
+			}
+			n = nil
+		}
+		if !flush() {
+			return
+		}
+		if n != nil && !send(n) {
+			return
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package chain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// injectFaults returns the notifications forwarded by the fault injector of a
+// client given the notifications of its handler.
+func injectFaults(t *testing.T, f Faults, ntfns []interface{}) []interface{} {
+	t.Helper()
+
+	c, err := NewRPCClient(&chainParams, "127.0.0.1:1", "", "", nil, true,
+		false, 0, nil, "", ConnTimeouts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Stop()
+	c.InjectFaults(f)
+
+	// The handler is replaced by the test, which closes its channel.
+	c.started = true
+	c.wg.Add(1)
+	go c.faultInjector()
+	go func() {
+		for _, n := range ntfns {
+			c.dequeueNotification <- n
+		}
+		close(c.dequeueNotification)
+	}()
+
+	var forwarded []interface{}
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case n, ok := <-c.Notifications():
+			if !ok {
+				return forwarded
+			}
+			forwarded = append(forwarded, n)
+		case <-timeout:
+			t.Fatal("notifications were not forwarded")
+		}
+	}
+}
+
+// TestFaultInjection checks that block connected notifications are reordered
+// within their window, the same way for the same seed, and that the other
+// notifications keep their place.
+func TestFaultInjection(t *testing.T) {
+	t.Parallel()
+
+	ntfns := []interface{}{ClientConnected{}}
+	for height := int32(1); height <= 8; height++ {
+		n := BlockConnected{}
+		n.Height = height
+		ntfns = append(ntfns, n)
+	}
+	ntfns = append(ntfns, &RescanFinished{Height: 8})
+
+	f := Faults{
+		NotifyDelay:   time.Millisecond,
+		ReorderWindow: 4,
+		Seed:          7,
+	}
+	forwarded := injectFaults(t, f, ntfns)
+	if len(forwarded) != len(ntfns) {
+		t.Fatalf("forwarded %d notifications, expected %d",
+			len(forwarded), len(ntfns))
+	}
+	if !reflect.DeepEqual(forwarded[0], ntfns[0]) ||
+		!reflect.DeepEqual(forwarded[9], ntfns[9]) {
+
+		t.Fatal("notifications other than blocks were reordered")
+	}
+	for i := 1; i <= 8; i++ {
+		height := forwarded[i].(BlockConnected).Height
+		window := int32((i-1)/4) * 4
+		if height <= window || height > window+4 {
+			t.Fatalf("block %d forwarded out of its window at %d",
+				height, i)
+		}
+	}
+	if reflect.DeepEqual(forwarded, ntfns) {
+		t.Fatal("blocks were not reordered")
+	}
+
+	if !reflect.DeepEqual(injectFaults(t, f, ntfns), forwarded) {
+		t.Fatal("the same seed reordered blocks differently")
+	}
+	if !reflect.DeepEqual(injectFaults(t, Faults{DropEvery: 3}, ntfns),
+		ntfns) {
+
+		t.Fatal("blocks were reordered without a reorder window")
+	}
+}
@@ -0,0 +1,124 @@
+package chain
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+// connectionAttemptDelay is the delay between the connection attempts to the
+// addresses of a host, as recommended by RFC 8305.
+const connectionAttemptDelay = 250 * time.Millisecond
+
+// dialHappyEyeballs connects to the TCP address of a host with the Happy
+// Eyeballs algorithm of RFC 8305, so that an unreachable address family does
+// not delay the connection.  The IPv6 and IPv4 addresses of the host are
+// interleaved, starting with IPv6, and dialed in turn until one connects.
+func dialHappyEyeballs(ctx context.Context, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	if ip := net.ParseIP(host); ip != nil || host == "" {
+		var d net.Dialer
+		return d.DialContext(ctx, "tcp", addr)
+	}
+
+	ipAddrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	var v6, v4 []string
+	for _, ip := range ipAddrs {
+		a := net.JoinHostPort(ip.String(), port)
+		if ip.IP.To4() == nil {
+			v6 = append(v6, a)
+		} else {
+			v4 = append(v4, a)
+		}
+	}
+	addrs := make([]string, 0, len(ipAddrs))
+	for i := 0; i < len(v6) || i < len(v4); i++ {
+		if i < len(v6) {
+			addrs = append(addrs, v6[i])
+		}
+		if i < len(v4) {
+			addrs = append(addrs, v4[i])
+		}
+	}
+	return dialAddrs(ctx, addrs, connectionAttemptDelay)
+}
+
+// dialAddrs dials the addresses in turn, starting the next attempt after
+// delay, or as soon as an attempt fails, and returns the first connection
+// made.  The other attempts are canceled, and their connections closed.
+func dialAddrs(ctx context.Context, addrs []string,
+	delay time.Duration) (net.Conn, error) {
+
+	if len(addrs) == 0 {
+		return nil, &net.AddrError{Err: "no addresses to dial"}
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		conn net.Conn
+		err  error
+	}
+	results := make(chan result, len(addrs))
+	var d net.Dialer
+	next, pending := 0, 0
+	start := func() {
+		addr := addrs[next]
+		next++
+		pending++
+		go func() {
+			conn, err := d.DialContext(ctx, "tcp", addr)
+			results <- result{conn, err}
+		}()
+	}
+
+	start()
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	var firstErr error
+	for pending > 0 {
+		select {
+		case r := <-results:
+			pending--
+			if r.err == nil {
+				// The results of the canceled attempts are
+				// still read to close their connections.
+				go func(n int) {
+					for ; n > 0; n-- {
+						if r := <-results; r.conn != nil {
+							r.conn.Close()
+						}
+					}
+				}(pending)
+				return r.conn, nil
+			}
+			if firstErr == nil {
+				firstErr = r.err
+			}
+			if next < len(addrs) {
+				start()
+				if !timer.Stop() {
+					select {
+					case <-timer.C:
+					default:
+					}
+				}
+				timer.Reset(delay)
+			}
+
+		case <-timer.C:
+			if next < len(addrs) {
+				start()
+				timer.Reset(delay)
+			}
+		}
+	}
+	return nil, firstErr
+}
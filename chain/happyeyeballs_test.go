@@ -0,0 +1,82 @@
+package chain
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestDialAddrs(t *testing.T) {
+	t.Parallel()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	// A closed port, whose attempt fails at once.
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closedAddr := closed.Addr().String()
+	closed.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// The failed attempt starts the next one without waiting the delay.
+	start := time.Now()
+	conn, err := dialAddrs(ctx, []string{closedAddr, l.Addr().String()},
+		time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+	if d := time.Since(start); d > 5*time.Second {
+		t.Fatalf("dial took %v", d)
+	}
+	if conn.RemoteAddr().String() != l.Addr().String() {
+		t.Fatalf("connected to %v", conn.RemoteAddr())
+	}
+
+	if _, err := dialAddrs(ctx, []string{closedAddr}, time.Minute); err == nil {
+		t.Fatal("dial of a closed port succeeded")
+	}
+	if _, err := dialAddrs(ctx, nil, time.Minute); err == nil {
+		t.Fatal("dial of no addresses succeeded")
+	}
+}
+
+func TestDialHappyEyeballsLiteral(t *testing.T) {
+	t.Parallel()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go func() {
+		conn, err := l.Accept()
+		if err == nil {
+			conn.Close()
+		}
+	}()
+
+	conn, err := dialHappyEyeballs(context.Background(), l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+}
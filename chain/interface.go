@@ -1,6 +1,7 @@
 package chain
 
 import (
+	"context"
 	"time"
 
 	"github.com/lbryio/lbcd/chaincfg/chainhash"
@@ -72,11 +73,13 @@ type (
 	// FilterBlocksRequest specifies a range of blocks and the set of
 	// internal and external addresses of interest, indexed by corresponding
 	// scoped-index of the child address. A global set of watched outpoints
-	// is also included to monitor for spends.
+	// is also included to monitor for spends.  The filtering stops between
+	// blocks once the optional Context is done.
 	FilterBlocksRequest struct {
 		Blocks           []wtxmgr.BlockMeta
 		Addresses        map[waddrmgr.ScopedIndex]btcutil.Address
 		WatchedOutPoints map[wire.OutPoint]btcutil.Address
+		Context          context.Context
 	}
 
 	// FilterBlocksResponse reports the set of all internal and external
@@ -0,0 +1,502 @@
+package chain
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lbryio/lbcd/blockchain"
+	"github.com/lbryio/lbcd/chaincfg"
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/wtxmgr"
+)
+
+// ErrMockBlockNotFound is returned by MockInterface for unknown blocks.
+var ErrMockBlockNotFound = errors.New("block not found")
+
+// MockInterface is an in-memory chain backend implementing Interface, so that
+// the wallet can be unit tested without a running lbcd.  Its chain starts at
+// the genesis block of its network, and grows as tests inject transactions
+// and mine blocks, which are not validated.  Its notifications follow those
+// of the lbcd backend: ClientConnected once started, BlockConnected and
+// BlockDisconnected after NotifyBlocks, RelevantTx for the transactions
+// paying the addresses or spending the outputs watched by NotifyReceived and
+// Rescan, and RescanProgress and RescanFinished for rescans.  It is always
+// current.
+type MockInterface struct {
+	params *chaincfg.Params
+
+	mtx       sync.Mutex
+	chain     []*wire.MsgBlock // Best chain, indexed by height.
+	blocks    map[chainhash.Hash]*wire.MsgBlock
+	mempool   []*wire.MsgTx
+	nonce     uint32
+	watched   map[string]struct{}
+	outPoints map[wire.OutPoint]struct{}
+	notify    bool // Whether blocks are notified.
+	started   bool
+	sendErr   error
+
+	queue    *ConcurrentQueue
+	quit     chan struct{}
+	quitOnce sync.Once
+}
+
+var _ Interface = (*MockInterface)(nil)
+
+// NewMockInterface returns a mock chain backend whose chain only holds the
+// genesis block of a network.
+func NewMockInterface(params *chaincfg.Params) *MockInterface {
+	genesis := params.GenesisBlock
+	blocks := map[chainhash.Hash]*wire.MsgBlock{
+		*params.GenesisHash: genesis,
+	}
+	return &MockInterface{
+		params:    params,
+		chain:     []*wire.MsgBlock{genesis},
+		blocks:    blocks,
+		watched:   make(map[string]struct{}),
+		outPoints: make(map[wire.OutPoint]struct{}),
+		queue:     NewConcurrentQueue(20),
+		quit:      make(chan struct{}),
+	}
+}
+
+// Start starts delivering notifications, beginning with ClientConnected.
+func (m *MockInterface) Start() error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if m.started {
+		return errors.New("mock chain already started")
+	}
+	m.started = true
+	m.queue.Start()
+	m.enqueue(ClientConnected{})
+	return nil
+}
+
+// Stop stops delivering notifications.
+func (m *MockInterface) Stop() {
+	m.quitOnce.Do(func() {
+		close(m.quit)
+
+		m.mtx.Lock()
+		if m.started {
+			m.queue.Stop()
+		}
+		m.mtx.Unlock()
+	})
+}
+
+// WaitForShutdown returns at once, as the mock has nothing to wait for.
+func (m *MockInterface) WaitForShutdown() {}
+
+// enqueue queues a notification.  It must be called with the mutex held, so
+// that notifications are delivered in order.
+func (m *MockInterface) enqueue(n interface{}) {
+	if !m.started {
+		return
+	}
+	select {
+	case m.queue.ChanIn() <- n:
+	case <-m.quit:
+	}
+}
+
+// GetBestBlock returns the hash and height of the tip of the chain.
+func (m *MockInterface) GetBestBlock() (*chainhash.Hash, int32, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	hash := m.chain[len(m.chain)-1].BlockHash()
+	return &hash, int32(len(m.chain) - 1), nil
+}
+
+// GetBlock returns a block of the chain, or one disconnected from it.
+func (m *MockInterface) GetBlock(hash *chainhash.Hash) (*wire.MsgBlock,
+	error) {
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	block, ok := m.blocks[*hash]
+	if !ok {
+		return nil, ErrMockBlockNotFound
+	}
+	return block, nil
+}
+
+// GetBlockHash returns the hash of the block of the chain at a height.
+func (m *MockInterface) GetBlockHash(height int64) (*chainhash.Hash, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if height < 0 || height >= int64(len(m.chain)) {
+		return nil, ErrMockBlockNotFound
+	}
+	hash := m.chain[height].BlockHash()
+	return &hash, nil
+}
+
+// GetBlockHeader returns the header of a block of the chain, or one
+// disconnected from it.
+func (m *MockInterface) GetBlockHeader(hash *chainhash.Hash) (
+	*wire.BlockHeader, error) {
+
+	block, err := m.GetBlock(hash)
+	if err != nil {
+		return nil, err
+	}
+	header := block.Header
+	return &header, nil
+}
+
+// IsCurrent returns true, as the mock chain is never behind.
+func (m *MockInterface) IsCurrent() bool {
+	return true
+}
+
+// FilterBlocks returns the first block of the request with a transaction
+// relevant to its addresses and outpoints, or nil when none is.
+func (m *MockInterface) FilterBlocks(req *FilterBlocksRequest) (
+	*FilterBlocksResponse, error) {
+
+	blockFilterer := NewBlockFilterer(m.params, req)
+	for i, blk := range req.Blocks {
+		block, err := m.GetBlock(&blk.Hash)
+		if err != nil {
+			return nil, err
+		}
+		if !blockFilterer.FilterBlock(block) {
+			continue
+		}
+		return &FilterBlocksResponse{
+			BatchIndex:     uint32(i),
+			BlockMeta:      blk,
+			FoundAddresses: blockFilterer.FoundAddresses,
+			FoundOutPoints: blockFilterer.FoundOutPoints,
+			RelevantTxns:   blockFilterer.RelevantTxns,
+		}, nil
+	}
+	return nil, nil
+}
+
+// BlockStamp returns the tip of the chain.
+func (m *MockInterface) BlockStamp() (*waddrmgr.BlockStamp, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	tip := m.chain[len(m.chain)-1]
+	return &waddrmgr.BlockStamp{
+		Height:    int32(len(m.chain) - 1),
+		Hash:      tip.BlockHash(),
+		Timestamp: tip.Header.Timestamp,
+	}, nil
+}
+
+// SendRawTransaction adds a transaction published by the wallet to the
+// mempool, unless SetSendError set an error to return instead.
+func (m *MockInterface) SendRawTransaction(tx *wire.MsgTx, _ bool) (
+	*chainhash.Hash, error) {
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if m.sendErr != nil {
+		return nil, m.sendErr
+	}
+	m.addToMempool(tx)
+	hash := tx.TxHash()
+	return &hash, nil
+}
+
+// SetSendError sets the error SendRawTransaction returns, such as a
+// rejection by the chain server, or clears it when nil.
+func (m *MockInterface) SetSendError(err error) {
+	m.mtx.Lock()
+	m.sendErr = err
+	m.mtx.Unlock()
+}
+
+// Rescan watches addresses and outpoints, and notifies the transactions of
+// the chain relevant to them from a block to the tip, followed by
+// RescanFinished.
+func (m *MockInterface) Rescan(startHash *chainhash.Hash,
+	addrs []btcutil.Address,
+	outPoints map[wire.OutPoint]btcutil.Address) error {
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	start := -1
+	for height, block := range m.chain {
+		if block.BlockHash() == *startHash {
+			start = height
+			break
+		}
+	}
+	if start < 0 {
+		return ErrMockBlockNotFound
+	}
+
+	m.watch(addrs)
+	for op := range outPoints {
+		m.outPoints[op] = struct{}{}
+	}
+	for height := start; height < len(m.chain); height++ {
+		block := m.chain[height]
+		meta := blockMeta(block, int32(height))
+		for _, tx := range block.Transactions {
+			m.notifyIfRelevant(tx, meta)
+		}
+		hash := block.BlockHash()
+		n := &RescanProgress{
+			Hash:   &hash,
+			Height: int32(height),
+			Time:   block.Header.Timestamp,
+		}
+		if height == len(m.chain)-1 {
+			m.enqueue(&RescanFinished{
+				Hash:   n.Hash,
+				Height: n.Height,
+				Time:   n.Time,
+			})
+		} else if (height-start+1)%1000 == 0 {
+			m.enqueue(n)
+		}
+	}
+	return nil
+}
+
+// NotifyReceived watches addresses, notifying the transactions paying them
+// from now on.
+func (m *MockInterface) NotifyReceived(addrs []btcutil.Address) error {
+	m.mtx.Lock()
+	m.watch(addrs)
+	m.mtx.Unlock()
+	return nil
+}
+
+func (m *MockInterface) watch(addrs []btcutil.Address) {
+	for _, addr := range addrs {
+		m.watched[addr.EncodeAddress()] = struct{}{}
+	}
+}
+
+// NotifyBlocks starts notifying the blocks connected to and disconnected
+// from the chain.
+func (m *MockInterface) NotifyBlocks() error {
+	m.mtx.Lock()
+	m.notify = true
+	m.mtx.Unlock()
+	return nil
+}
+
+// Notifications returns the channel of notifications.
+func (m *MockInterface) Notifications() <-chan interface{} {
+	return m.queue.ChanOut()
+}
+
+// BackEnd returns the name of the mock backend.
+func (m *MockInterface) BackEnd() string {
+	return "mock"
+}
+
+// InjectTx adds a transaction to the mempool, as if relayed by a peer, so
+// that it is mined by the next block.
+func (m *MockInterface) InjectTx(tx *wire.MsgTx) {
+	m.mtx.Lock()
+	m.addToMempool(tx)
+	m.mtx.Unlock()
+}
+
+func (m *MockInterface) addToMempool(tx *wire.MsgTx) {
+	m.mempool = append(m.mempool, tx)
+	m.notifyIfRelevant(tx, nil)
+}
+
+// Mempool returns the transactions waiting to be mined.
+func (m *MockInterface) Mempool() []*wire.MsgTx {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	return append([]*wire.MsgTx(nil), m.mempool...)
+}
+
+// MineBlock connects a block to the tip of the chain holding a coinbase, the
+// transactions of the mempool and txs.
+func (m *MockInterface) MineBlock(txs ...*wire.MsgTx) *wire.MsgBlock {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	return m.mineBlock(txs)
+}
+
+func (m *MockInterface) mineBlock(txs []*wire.MsgTx) *wire.MsgBlock {
+	height := int32(len(m.chain))
+	prev := m.chain[len(m.chain)-1]
+
+	// The coinbase commits to the height, as required by BIP0034, which
+	// also makes it unique.
+	coinbaseScript, _ := txscript.NewScriptBuilder().
+		AddInt64(int64(height)).AddInt64(0).Script()
+	coinbase := wire.NewMsgTx(wire.TxVersion)
+	coinbase.AddTxIn(wire.NewTxIn(
+		wire.NewOutPoint(&chainhash.Hash{}, wire.MaxPrevOutIndex),
+		coinbaseScript, nil,
+	))
+	coinbase.AddTxOut(wire.NewTxOut(0, []byte{txscript.OP_TRUE}))
+
+	transactions := append([]*wire.MsgTx{coinbase}, m.mempool...)
+	transactions = append(transactions, txs...)
+	m.mempool = nil
+
+	utilTxs := make([]*btcutil.Tx, 0, len(transactions))
+	for _, tx := range transactions {
+		utilTxs = append(utilTxs, btcutil.NewTx(tx))
+	}
+	merkles := blockchain.BuildMerkleTreeStore(utilTxs, false)
+
+	// Blocks are timestamped now, after their parent, and the nonce
+	// tells apart the blocks mined at the same height of competing
+	// branches.
+	timestamp := time.Unix(time.Now().Unix(), 0)
+	if !timestamp.After(prev.Header.Timestamp) {
+		timestamp = prev.Header.Timestamp.Add(time.Second)
+	}
+	m.nonce++
+	block := &wire.MsgBlock{
+		Header: wire.BlockHeader{
+			Version:    1,
+			PrevBlock:  prev.BlockHash(),
+			MerkleRoot: *merkles[len(merkles)-1],
+			Timestamp:  timestamp,
+			Bits:       m.params.PowLimitBits,
+			Nonce:      m.nonce,
+		},
+		Transactions: transactions,
+	}
+	m.chain = append(m.chain, block)
+	m.blocks[block.BlockHash()] = block
+
+	// Like lbcd, the relevant transactions are notified before the block,
+	// so that the wallet has recorded them once synced to it.
+	meta := blockMeta(block, height)
+	for _, tx := range transactions {
+		m.notifyIfRelevant(tx, meta)
+	}
+	if m.notify {
+		m.enqueue(BlockConnected(*meta))
+	}
+	return block
+}
+
+// DisconnectBlocks disconnects blocks from the tip of the chain, returning
+// their transactions but the coinbases to the mempool.  Mining blocks
+// afterwards simulates a reorg.
+func (m *MockInterface) DisconnectBlocks(n int) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	return m.disconnectBlocks(n)
+}
+
+func (m *MockInterface) disconnectBlocks(n int) error {
+	if n < 0 || n >= len(m.chain) {
+		return fmt.Errorf("unable to disconnect %d blocks from "+
+			"a chain of height %d", n, len(m.chain)-1)
+	}
+
+	var mempool []*wire.MsgTx
+	for i := 0; i < n; i++ {
+		height := int32(len(m.chain) - 1)
+		block := m.chain[height]
+		m.chain = m.chain[:height]
+		txs := append([]*wire.MsgTx(nil), block.Transactions[1:]...)
+		mempool = append(txs, mempool...)
+		if m.notify {
+			m.enqueue(BlockDisconnected(*blockMeta(block, height)))
+		}
+	}
+	m.mempool = append(mempool, m.mempool...)
+	return nil
+}
+
+// Reorg replaces the last depth blocks of the chain by a longer branch of
+// depth+1 blocks, the first of which mines the transactions of the
+// disconnected blocks.
+func (m *MockInterface) Reorg(depth int) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if err := m.disconnectBlocks(depth); err != nil {
+		return err
+	}
+	for i := 0; i <= depth; i++ {
+		m.mineBlock(nil)
+	}
+	return nil
+}
+
+// notifyIfRelevant notifies a transaction of the mempool, or of a block when
+// not nil, which pays a watched address or spends a watched outpoint.  The
+// outputs paying watched addresses are watched from then on, as by lbcd.
+func (m *MockInterface) notifyIfRelevant(tx *wire.MsgTx,
+	block *wtxmgr.BlockMeta) {
+
+	relevant := false
+	for _, in := range tx.TxIn {
+		if _, ok := m.outPoints[in.PreviousOutPoint]; ok {
+			relevant = true
+		}
+	}
+	hash := tx.TxHash()
+	for i, out := range tx.TxOut {
+		_, addrs, _, err := txscript.ExtractPkScriptAddrs(
+			out.PkScript, m.params,
+		)
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			if _, ok := m.watched[addr.EncodeAddress()]; !ok {
+				continue
+			}
+			relevant = true
+			op := wire.OutPoint{Hash: hash, Index: uint32(i)}
+			m.outPoints[op] = struct{}{}
+		}
+	}
+	if !relevant {
+		return
+	}
+
+	received := time.Now()
+	if block != nil {
+		received = block.Time
+	}
+	rec, err := wtxmgr.NewTxRecordFromMsgTx(tx, received)
+	if err != nil {
+		log.Errorf("Cannot create transaction record for relevant "+
+			"tx: %v", err)
+		return
+	}
+	m.enqueue(RelevantTx{TxRecord: rec, Block: block})
+}
+
+// blockMeta returns the metadata of a block at a height.
+func blockMeta(block *wire.MsgBlock, height int32) *wtxmgr.BlockMeta {
+	return &wtxmgr.BlockMeta{
+		Block: wtxmgr.Block{
+			Hash:   block.BlockHash(),
+			Height: height,
+		},
+		Time: block.Header.Timestamp,
+	}
+}
@@ -0,0 +1,127 @@
+package chain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+)
+
+// nextNotification returns the next notification of a mock chain.
+func nextNotification(t *testing.T, m *MockInterface) interface{} {
+	t.Helper()
+
+	select {
+	case n := <-m.Notifications():
+		return n
+	case <-time.After(time.Second):
+		t.Fatal("no notification")
+		return nil
+	}
+}
+
+// TestMockInterface checks the notifications of the mock chain when
+// transactions are injected, blocks mined and reorged, and addresses
+// rescanned.
+func TestMockInterface(t *testing.T) {
+	t.Parallel()
+
+	m := NewMockInterface(&chainParams)
+	if err := m.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer m.Stop()
+	if _, ok := nextNotification(t, m).(ClientConnected); !ok {
+		t.Fatal("expected ClientConnected")
+	}
+
+	addr, err := btcutil.NewAddressPubKeyHash(
+		make([]byte, 20), &chainParams,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pay := wire.NewMsgTx(wire.TxVersion)
+	pay.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 1}, nil, nil))
+	pay.AddTxOut(wire.NewTxOut(1e8, pkScript))
+
+	// Unwatched transactions and blocks are not notified.
+	m.InjectTx(pay)
+	m.MineBlock()
+	if err := m.NotifyBlocks(); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.NotifyReceived([]btcutil.Address{addr}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A spend of the watched output is notified when mined.
+	spend := wire.NewMsgTx(wire.TxVersion)
+	spend.AddTxIn(wire.NewTxIn(
+		&wire.OutPoint{Hash: pay.TxHash()}, nil, nil,
+	))
+	block := m.MineBlock(spend)
+	if n := len(block.Transactions); n != 2 {
+		t.Fatalf("mined %d transactions, expected 2", n)
+	}
+	if _, ok := nextNotification(t, m).(BlockConnected); !ok {
+		t.Fatal("expected BlockConnected")
+	}
+
+	// The rescan finds the payment, and the spend of its output.
+	genesis := chainParams.GenesisHash
+	if err := m.Rescan(genesis, []btcutil.Address{addr}, nil); err != nil {
+		t.Fatal(err)
+	}
+	for height, tx := range []*wire.MsgTx{pay, spend} {
+		n, ok := nextNotification(t, m).(RelevantTx)
+		if !ok || n.TxRecord.Hash != tx.TxHash() ||
+			n.Block.Height != int32(height+1) {
+
+			t.Fatalf("unexpected notification %v", n)
+		}
+	}
+	finished, ok := nextNotification(t, m).(*RescanFinished)
+	if !ok || finished.Height != 2 {
+		t.Fatalf("unexpected notification %v", finished)
+	}
+
+	// The spend is returned to the mempool by the reorg, and notified
+	// again when mined by the new branch.
+	if err := m.Reorg(1); err != nil {
+		t.Fatal(err)
+	}
+	disconnected, ok := nextNotification(t, m).(BlockDisconnected)
+	if !ok || disconnected.Hash != block.BlockHash() {
+		t.Fatalf("unexpected notification %v", disconnected)
+	}
+	n, ok := nextNotification(t, m).(RelevantTx)
+	if !ok || n.TxRecord.Hash != spend.TxHash() || n.Block.Height != 2 {
+		t.Fatalf("unexpected notification %v", n)
+	}
+	for height := int32(2); height <= 3; height++ {
+		connected, ok := nextNotification(t, m).(BlockConnected)
+		if !ok || connected.Height != height {
+			t.Fatalf("unexpected notification %v", connected)
+		}
+	}
+
+	// Stale blocks are still known by hash.
+	hash, height, err := m.GetBestBlock()
+	if err != nil || height != 3 {
+		t.Fatalf("best block at height %d: %v", height, err)
+	}
+	if *hash == block.BlockHash() {
+		t.Fatal("stale block is still in the chain")
+	}
+	stale := block.BlockHash()
+	if _, err := m.GetBlock(&stale); err != nil {
+		t.Fatal(err)
+	}
+}
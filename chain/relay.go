@@ -0,0 +1,254 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ConnTimeouts bounds the connection of an RPCClient to the lbcd server, so
+// that a connection lost without being closed, as happens over flaky links, is
+// detected and reestablished.  A zero duration disables its bound.
+type ConnTimeouts struct {
+	// Dial bounds the time to open a TCP connection to the server, or to
+	// the proxy.
+	Dial time.Duration
+
+	// Handshake bounds the time from the opening of a connection to the
+	// completion of its TLS and websocket handshakes.
+	Handshake time.Duration
+
+	// PingInterval is how often the server is pinged once connected.
+	PingInterval time.Duration
+
+	// PongTimeout bounds the time the server takes to answer a ping,
+	// after which the client reconnects.
+	PongTimeout time.Duration
+}
+
+// SOCKS5 protocol values used by connRelay.
+const (
+	socksVersion      = 5
+	socksAuthNone     = 0
+	socksConnect      = 1
+	socksAddrIPv4     = 1
+	socksAddrDomain   = 3
+	socksAddrIPv6     = 4
+	socksGranted      = 0
+	socksGeneralError = 1
+)
+
+// proxyDialer opens connections through a proxy, such as a SOCKS5 proxy or
+// the SAM bridge of an I2P router.
+type proxyDialer interface {
+	DialTimeout(network, addr string, timeout time.Duration) (net.Conn,
+		error)
+}
+
+// connRelay is a SOCKS5 server on the loopback interface, through which the
+// RPC client connects to the server, as the client takes neither a dialer nor
+// timeouts.  The relay opens the connections to the server with the dial
+// timeout, through the real proxy if there is one or else with Happy
+// Eyeballs, and closes those whose handshakes are not done within the
+// handshake timeout.
+type connRelay struct {
+	listener  net.Listener
+	proxy     proxyDialer
+	dial      time.Duration
+	handshake time.Duration
+
+	mtx     sync.Mutex
+	pending map[*time.Timer]struct{}
+	server  net.Addr // Address of the last connection made.
+}
+
+// newConnRelay starts a relay opening connections through proxy, or directly
+// if it is nil.
+func newConnRelay(proxy proxyDialer, timeouts ConnTimeouts) (*connRelay, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	r := &connRelay{
+		listener:  l,
+		proxy:     proxy,
+		dial:      timeouts.Dial,
+		handshake: timeouts.Handshake,
+		pending:   make(map[*time.Timer]struct{}),
+	}
+	go r.serve()
+	return r, nil
+}
+
+// Addr returns the address of the relay.
+func (r *connRelay) Addr() string {
+	return r.listener.Addr().String()
+}
+
+// Close stops accepting connections.  Relayed connections are closed with the
+// connections of the client.
+func (r *connRelay) Close() error {
+	return r.listener.Close()
+}
+
+// connected marks the handshakes of the relayed connections as done.
+func (r *connRelay) connected() {
+	r.mtx.Lock()
+	for t := range r.pending {
+		t.Stop()
+		delete(r.pending, t)
+	}
+	r.mtx.Unlock()
+}
+
+func (r *connRelay) serve() {
+	for {
+		conn, err := r.listener.Accept()
+		if err != nil {
+			return
+		}
+		go r.relay(conn)
+	}
+}
+
+// relay answers the SOCKS5 request of a client connection and relays it to
+// the requested address.
+func (r *connRelay) relay(conn net.Conn) {
+	// The client is local, so the request is expected at once.
+	_ = conn.SetDeadline(time.Now().Add(10 * time.Second))
+	addr, err := readSocksRequest(conn)
+	if err != nil {
+		log.Debugf("Invalid relay request: %v", err)
+		conn.Close()
+		return
+	}
+
+	server, err := r.dialServer(addr)
+	reply := []byte{socksVersion, socksGranted, 0, socksAddrIPv4,
+		0, 0, 0, 0, 0, 0}
+	if err != nil {
+		log.Infof("Unable to connect to %s: %v", addr, err)
+		reply[1] = socksGeneralError
+		_, _ = conn.Write(reply)
+		conn.Close()
+		return
+	}
+	if _, err := conn.Write(reply); err != nil {
+		conn.Close()
+		server.Close()
+		return
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	r.mtx.Lock()
+	r.server = server.RemoteAddr()
+	if r.handshake > 0 {
+		var t *time.Timer
+		t = time.AfterFunc(r.handshake, func() {
+			r.mtx.Lock()
+			delete(r.pending, t)
+			r.mtx.Unlock()
+			log.Warnf("Handshake with %s timed out after %v", addr,
+				r.handshake)
+			conn.Close()
+			server.Close()
+		})
+		r.pending[t] = struct{}{}
+	}
+	r.mtx.Unlock()
+
+	go func() {
+		_, _ = io.Copy(server, conn)
+		server.Close()
+		conn.Close()
+	}()
+	_, _ = io.Copy(conn, server)
+	conn.Close()
+	server.Close()
+}
+
+// dialServer connects to the server through the proxy, which resolves its
+// host name, or else directly with Happy Eyeballs.
+func (r *connRelay) dialServer(addr string) (net.Conn, error) {
+	if r.proxy != nil {
+		return r.proxy.DialTimeout("tcp", addr, r.dial)
+	}
+	ctx := context.Background()
+	if r.dial > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.dial)
+		defer cancel()
+	}
+	return dialHappyEyeballs(ctx, addr)
+}
+
+// serverAddr returns the address of the last connection made to the server,
+// or nil if none was made.
+func (r *connRelay) serverAddr() net.Addr {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return r.server
+}
+
+// readSocksRequest reads the greeting and the connect request of a SOCKS5
+// client which does not authenticate, and returns the requested address.
+func readSocksRequest(conn net.Conn) (string, error) {
+	var buf [255]byte
+	if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+		return "", err
+	}
+	if buf[0] != socksVersion {
+		return "", fmt.Errorf("unsupported SOCKS version %d", buf[0])
+	}
+	if _, err := io.ReadFull(conn, buf[:buf[1]]); err != nil {
+		return "", err
+	}
+	_, err := conn.Write([]byte{socksVersion, socksAuthNone})
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := io.ReadFull(conn, buf[:4]); err != nil {
+		return "", err
+	}
+	if buf[0] != socksVersion || buf[1] != socksConnect {
+		return "", errors.New("unsupported SOCKS command")
+	}
+	var host string
+	switch buf[3] {
+	case socksAddrIPv4:
+		if _, err := io.ReadFull(conn, buf[:net.IPv4len]); err != nil {
+			return "", err
+		}
+		host = net.IP(buf[:net.IPv4len]).String()
+
+	case socksAddrIPv6:
+		if _, err := io.ReadFull(conn, buf[:net.IPv6len]); err != nil {
+			return "", err
+		}
+		host = net.IP(buf[:net.IPv6len]).String()
+
+	case socksAddrDomain:
+		if _, err := io.ReadFull(conn, buf[:1]); err != nil {
+			return "", err
+		}
+		n := buf[0]
+		if _, err := io.ReadFull(conn, buf[:n]); err != nil {
+			return "", err
+		}
+		host = string(buf[:n])
+
+	default:
+		return "", fmt.Errorf("unsupported SOCKS address type %d", buf[3])
+	}
+	if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+		return "", err
+	}
+	port := int(buf[0])<<8 | int(buf[1])
+	return net.JoinHostPort(host, strconv.Itoa(port)), nil
+}
@@ -0,0 +1,101 @@
+package chain
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/go-socks/socks"
+)
+
+// echoServer starts a TCP server echoing what its clients write.
+func echoServer(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_, _ = io.Copy(conn, conn)
+				conn.Close()
+			}()
+		}
+	}()
+	return l.Addr().String()
+}
+
+// TestConnRelay checks that connections are relayed to the requested address,
+// and closed when their handshakes are not done in time.
+func TestConnRelay(t *testing.T) {
+	t.Parallel()
+
+	addr := echoServer(t)
+	relay, err := newConnRelay(nil, ConnTimeouts{
+		Dial:      time.Second,
+		Handshake: 200 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer relay.Close()
+	proxy := &socks.Proxy{Addr: relay.Addr()}
+
+	echo := func(conn net.Conn) error {
+		if _, err := conn.Write([]byte("ping")); err != nil {
+			return err
+		}
+		var buf [4]byte
+		_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+		if _, err := io.ReadFull(conn, buf[:]); err != nil {
+			return err
+		}
+		if string(buf[:]) != "ping" {
+			t.Fatalf("echoed %q", buf[:])
+		}
+		return nil
+	}
+
+	// A connection whose handshake is done stays open.
+	conn, err := proxy.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if err := echo(conn); err != nil {
+		t.Fatal(err)
+	}
+	relay.connected()
+	time.Sleep(400 * time.Millisecond)
+	if err := echo(conn); err != nil {
+		t.Fatalf("connection closed after its handshake: %v", err)
+	}
+
+	// A connection whose handshake is not done is closed.
+	stalled, err := proxy.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stalled.Close()
+	time.Sleep(400 * time.Millisecond)
+	if err := echo(stalled); err == nil {
+		t.Fatal("stalled handshake not timed out")
+	}
+
+	// Unreachable addresses are refused.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed := l.Addr().String()
+	l.Close()
+	if _, err := proxy.Dial("tcp", closed); err == nil {
+		t.Fatal("connection to a closed port relayed")
+	}
+}
@@ -5,10 +5,15 @@
 package chain
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
+	"net"
+	"net/url"
 	"sync"
 	"time"
 
+	"github.com/btcsuite/go-socks/socks"
 	"github.com/lbryio/lbcd/btcjson"
 	"github.com/lbryio/lbcd/chaincfg"
 	"github.com/lbryio/lbcd/chaincfg/chainhash"
@@ -17,6 +22,7 @@ import (
 	btcutil "github.com/lbryio/lbcutil"
 	"github.com/lbryio/lbcutil/gcs"
 	"github.com/lbryio/lbcutil/gcs/builder"
+	"github.com/lbryio/lbcwallet/internal/i2psam"
 	"github.com/lbryio/lbcwallet/waddrmgr"
 	"github.com/lbryio/lbcwallet/wtxmgr"
 )
@@ -28,11 +34,20 @@ type RPCClient struct {
 	connConfig        *rpcclient.ConnConfig // Work around unexported field
 	chainParams       *chaincfg.Params
 	reconnectAttempts int
+	timeouts          ConnTimeouts
+	proxy             *socks.Proxy
+	relay             *connRelay
+	sam               *i2psam.Dialer
 
 	enqueueNotification chan interface{}
 	dequeueNotification chan interface{}
 	currentBlock        chan *waddrmgr.BlockStamp
 
+	// faults are injected into the notifications dequeued by the
+	// handler, which are then sent on faultyNotification instead.
+	faults             Faults
+	faultyNotification chan interface{}
+
 	quit    chan struct{}
 	wg      sync.WaitGroup
 	started bool
@@ -45,8 +60,17 @@ type RPCClient struct {
 // but must be done using the Start method.  If the remote server does not
 // operate on the same bitcoin network as described by the passed chain
 // parameters, the connection will be disconnected.
+//
+// The connection is made through the SOCKS5 proxy when one is given.  With
+// Tor stream isolation, each client uses random proxy credentials, so that
+// Tor routes it over its own circuit.  When the address of the SAM bridge
+// of an I2P router is given instead, the connection is an I2P stream, and
+// the server is an I2P destination.  The timeouts bound the connection, and
+// keep it alive with pings once established.
 func NewRPCClient(chainParams *chaincfg.Params, connect, user, pass string, certs []byte,
-	disableTLS bool, skipverify bool, reconnectAttempts int) (*RPCClient, error) {
+	disableTLS bool, skipverify bool, reconnectAttempts int,
+	proxy *socks.Proxy, i2pSAM string,
+	timeouts ConnTimeouts) (*RPCClient, error) {
 
 	if reconnectAttempts < 0 {
 		return nil, errors.New("reconnectAttempts must be positive")
@@ -66,11 +90,45 @@ func NewRPCClient(chainParams *chaincfg.Params, connect, user, pass string, cert
 		},
 		chainParams:         chainParams,
 		reconnectAttempts:   reconnectAttempts,
+		timeouts:            timeouts,
 		enqueueNotification: make(chan interface{}),
 		dequeueNotification: make(chan interface{}),
 		currentBlock:        make(chan *waddrmgr.BlockStamp),
 		quit:                make(chan struct{}),
 	}
+	if proxy != nil {
+		isolate := proxy.TorIsolation
+		proxy = &socks.Proxy{
+			Addr:     proxy.Addr,
+			Username: proxy.Username,
+			Password: proxy.Password,
+		}
+		if isolate {
+			var b [16]byte
+			if _, err := rand.Read(b[:]); err != nil {
+				return nil, err
+			}
+			proxy.Username = hex.EncodeToString(b[:8])
+			proxy.Password = hex.EncodeToString(b[8:])
+		}
+	}
+	client.proxy = proxy
+
+	// The client can only be given timeouts and a dialer through a relay.
+	var dialer proxyDialer
+	switch {
+	case i2pSAM != "":
+		client.sam = i2psam.NewDialer(i2pSAM)
+		dialer = client.sam
+	case proxy != nil:
+		dialer = proxy
+	}
+	relay, err := newConnRelay(dialer, timeouts)
+	if err != nil {
+		return nil, err
+	}
+	client.relay = relay
+	client.connConfig.Proxy = relay.Addr()
 	ntfnCallbacks := &rpcclient.NotificationHandlers{
 		OnClientConnected:   client.onClientConnect,
 		OnBlockConnected:    client.onBlockConnected,
@@ -82,6 +140,10 @@ func NewRPCClient(chainParams *chaincfg.Params, connect, user, pass string, cert
 	}
 	rpcClient, err := rpcclient.New(client.connConfig, ntfnCallbacks)
 	if err != nil {
+		client.relay.Close()
+		if client.sam != nil {
+			client.sam.Close()
+		}
 		return nil, err
 	}
 	client.Client = rpcClient
@@ -93,6 +155,45 @@ func (c *RPCClient) BackEnd() string {
 	return "lbcd"
 }
 
+// ConnStatus describes the connection of an RPCClient to the server.
+type ConnStatus struct {
+	// Connected is whether the client is connected to the server.
+	Connected bool
+
+	// Addr is the address of the last connection made to the server, or
+	// empty before the first.  Through a proxy, it is the address given
+	// to the proxy.
+	Addr string
+
+	// Family is the address family of the last connection, which is ipv4
+	// or ipv6, i2p over I2P, or proxy through a proxy.
+	Family string
+}
+
+// ConnStatus returns the status of the connection to the server.
+func (c *RPCClient) ConnStatus() ConnStatus {
+	// The client never connected reports it is not disconnected.
+	var status ConnStatus
+	addr := c.relay.serverAddr()
+	if addr == nil {
+		return status
+	}
+	status.Connected = !c.Client.Disconnected()
+	status.Addr = addr.String()
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	switch {
+	case addr.Network() == "i2p":
+		status.Family = "i2p"
+	case !ok:
+		status.Family = "proxy"
+	case tcpAddr.IP.To4() != nil:
+		status.Family = "ipv4"
+	default:
+		status.Family = "ipv6"
+	}
+	return status
+}
+
 // Start attempts to establish a client connection with the remote server.
 // If successful, handler goroutines are started to process notifications
 // sent by the server.  After a limited number of connection attempts, this
@@ -121,6 +222,14 @@ func (c *RPCClient) Start() error {
 
 	c.wg.Add(1)
 	go c.handler()
+	if c.faultyNotification != nil {
+		c.wg.Add(1)
+		go c.faultInjector()
+	}
+	if c.timeouts.PingInterval > 0 && c.timeouts.PongTimeout > 0 {
+		c.wg.Add(1)
+		go c.keepAlive()
+	}
 	return nil
 }
 
@@ -133,9 +242,16 @@ func (c *RPCClient) Stop() {
 	default:
 		close(c.quit)
 		c.Client.Shutdown()
+		c.relay.Close()
+		if c.sam != nil {
+			c.sam.Close()
+		}
 
 		if !c.started {
 			close(c.dequeueNotification)
+			if c.faultyNotification != nil {
+				close(c.faultyNotification)
+			}
 		}
 	}
 	c.quitMtx.Unlock()
@@ -184,6 +300,9 @@ func (c *RPCClient) WaitForShutdown() {
 // may abort for running out memory, as unread notifications are queued for
 // later reads.
 func (c *RPCClient) Notifications() <-chan interface{} {
+	if c.faultyNotification != nil {
+		return c.faultyNotification
+	}
 	return c.dequeueNotification
 }
 
@@ -204,7 +323,8 @@ func (c *RPCClient) BlockStamp() (*waddrmgr.BlockStamp, error) {
 // anything. If the filter returns a positive match, the full block will be
 // fetched and filtered. This method returns a FilterBlocksResponse for the first
 // block containing a matching address. If no matches are found in the range of
-// blocks requested, the returned response will be nil.
+// blocks requested, the returned response will be nil.  The error of the
+// context of the request is returned once it is done.
 func (c *RPCClient) FilterBlocks(
 	req *FilterBlocksRequest) (*FilterBlocksResponse, error) {
 
@@ -222,6 +342,12 @@ func (c *RPCClient) FilterBlocks(
 	// the filter returns a positive match, the full block is then requested
 	// and scanned for addresses using the block filterer.
 	for i, blk := range req.Blocks {
+		if req.Context != nil {
+			if err := req.Context.Err(); err != nil {
+				return nil, err
+			}
+		}
+
 		rawFilter, err := c.GetCFilter(&blk.Hash, wire.GCSFilterRegular)
 		if err != nil {
 			return nil, err
@@ -306,6 +432,7 @@ func parseBlock(block *btcjson.BlockDetails) (*wtxmgr.BlockMeta, error) {
 }
 
 func (c *RPCClient) onClientConnect() {
+	c.relay.connected()
 	select {
 	case c.enqueueNotification <- ClientConnected{}:
 	case <-c.quit:
@@ -455,9 +582,71 @@ out:
 	c.wg.Done()
 }
 
+// keepAlive pings the server every ping interval, and forces a reconnection
+// when it does not answer within the pong timeout, as a connection lost
+// without being closed would otherwise stall notifications silently.
+func (c *RPCClient) keepAlive() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.timeouts.PingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-c.quit:
+			return
+		}
+
+		pong := make(chan error, 1)
+		ping := c.Client.PingAsync()
+		go func() { pong <- ping.Receive() }()
+
+		timeout := time.NewTimer(c.timeouts.PongTimeout)
+		select {
+		case err := <-pong:
+			if err != nil && err != rpcclient.ErrClientDisconnect {
+				log.Debugf("Ping of %s failed: %v",
+					c.connConfig.Host, err)
+			}
+		case <-timeout.C:
+			log.Warnf("No answer from %s to a ping within %v, "+
+				"reconnecting", c.connConfig.Host,
+				c.timeouts.PongTimeout)
+			c.Client.Disconnect()
+		case <-c.quit:
+			timeout.Stop()
+			return
+		}
+		timeout.Stop()
+	}
+}
+
 // POSTClient creates the equivalent HTTP POST rpcclient.Client.
 func (c *RPCClient) POSTClient() (*rpcclient.Client, error) {
 	configCopy := *c.connConfig
 	configCopy.HTTPPostMode = true
+
+	// The relay only serves the websocket client, except over I2P which
+	// rpcclient can not reach without it.  The HTTP POST client takes the
+	// proxy as a URL, and with the socks5 scheme passes host names to the
+	// proxy to be resolved there, so they do not leak to the local
+	// resolver.
+	configCopy.Proxy = ""
+	configCopy.ProxyUser = ""
+	configCopy.ProxyPass = ""
+	switch {
+	case c.sam != nil:
+		configCopy.Proxy = (&url.URL{
+			Scheme: "socks5",
+			Host:   c.relay.Addr(),
+		}).String()
+	case c.proxy != nil:
+		u := &url.URL{Scheme: "socks5", Host: c.proxy.Addr}
+		if c.proxy.Username != "" || c.proxy.Password != "" {
+			u.User = url.UserPassword(c.proxy.Username,
+				c.proxy.Password)
+		}
+		configCopy.Proxy = u.String()
+	}
 	return rpcclient.New(&configCopy, nil)
 }
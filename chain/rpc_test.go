@@ -0,0 +1,106 @@
+package chain
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/go-socks/socks"
+	"github.com/lbryio/lbcd/chaincfg"
+)
+
+// fakeProxy starts a SOCKS5 proxy which sends the addresses requested by its
+// clients to addrs, and answers their first HTTP request with an empty
+// JSON-RPC result.
+func fakeProxy(t *testing.T, addrs chan<- string) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				addr, err := readSocksRequest(conn)
+				if err != nil {
+					return
+				}
+				addrs <- addr
+				_, err = conn.Write([]byte{socksVersion,
+					socksGranted, 0, socksAddrIPv4,
+					0, 0, 0, 0, 0, 0})
+				if err != nil {
+					return
+				}
+				req, err := http.ReadRequest(bufio.NewReader(conn))
+				if err != nil {
+					return
+				}
+				_, _ = io.Copy(io.Discard, req.Body)
+				body := `{"result":null,"error":null,"id":1}`
+				resp := &http.Response{
+					StatusCode:    http.StatusOK,
+					ProtoMajor:    1,
+					ProtoMinor:    1,
+					Header:        http.Header{},
+					Body:          io.NopCloser(strings.NewReader(body)),
+					ContentLength: int64(len(body)),
+					Close:         true,
+				}
+				_ = resp.Write(conn)
+			}()
+		}
+	}()
+	return l.Addr().String()
+}
+
+// TestProxyResolution checks that the host name of the server is passed to
+// the proxy to be resolved there, by the relay and the HTTP POST client.
+func TestProxyResolution(t *testing.T) {
+	t.Parallel()
+
+	const host = "lbcd.example.invalid:9245"
+	addrs := make(chan string, 10)
+	proxy := &socks.Proxy{Addr: fakeProxy(t, addrs)}
+
+	relay, err := newConnRelay(proxy, ConnTimeouts{Dial: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer relay.Close()
+	conn, err := (&socks.Proxy{Addr: relay.Addr()}).Dial("tcp", host)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+	if addr := <-addrs; addr != host {
+		t.Fatalf("relay requested %q from the proxy", addr)
+	}
+
+	c, err := NewRPCClient(&chaincfg.RegressionNetParams, host, "user",
+		"pass", nil, true, false, 0, proxy, "", ConnTimeouts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Stop()
+	post, err := c.POSTClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer post.Shutdown()
+	if _, err := post.RawRequest("getblockcount", nil); err != nil {
+		t.Fatal(err)
+	}
+	if addr := <-addrs; addr != host {
+		t.Fatalf("POST client requested %q from the proxy", addr)
+	}
+}
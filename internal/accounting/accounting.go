@@ -0,0 +1,273 @@
+// Package accounting writes wallet transactions in formats read by
+// bookkeeping software.
+package accounting
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/wallet"
+)
+
+// Format is a file format of an export.
+type Format string
+
+// The supported formats.
+const (
+	CSV Format = "csv"
+	OFX Format = "ofx"
+	QIF Format = "qif"
+)
+
+// ErrFormat describes an error where the requested format is not supported.
+var ErrFormat = errors.New("unsupported export format")
+
+// currency is the currency of the exported amounts.
+const currency = "LBC"
+
+// Write writes entries to w in the format.  start and end are the bounds of
+// the exported period, written to formats describing a statement, and may be
+// zero.
+func Write(w io.Writer, format Format, entries []wallet.ExportEntry, start,
+	end time.Time) error {
+
+	switch format {
+	case CSV:
+		return WriteCSV(w, entries)
+	case OFX:
+		return WriteOFX(w, entries, start, end)
+	case QIF:
+		return WriteQIF(w, entries)
+	default:
+		return ErrFormat
+	}
+}
+
+// csvHeader names the columns written by WriteCSV.
+var csvHeader = []string{
+	"date", "txid", "vout", "category", "account", "address", "amount",
+	"fee", "label", "claimop", "claimname", "claimid", "fiatcurrency",
+	"fiatrate", "fiatamount", "comment", "commentto",
+}
+
+// WriteCSV writes entries as comma separated values with a header row.  Dates
+// are written in RFC 3339 format in UTC, and amounts in LBC.
+func WriteCSV(w io.Writer, entries []wallet.ExportEntry) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for i := range entries {
+		e := &entries[i]
+		var fee, fiatCurrency, fiatRate, fiatAmount string
+		if e.Fee != 0 {
+			fee = formatAmount(e.Fee)
+		}
+		if e.FiatRate != nil {
+			fiatCurrency = e.FiatRate.Currency
+			fiatRate = strconv.FormatFloat(e.FiatRate.Rate, 'f', -1, 64)
+			fiatAmount = strconv.FormatFloat(math.Round(
+				e.Amount.ToBTC()*e.FiatRate.Rate*100)/100, 'f', 2, 64)
+		}
+		err := cw.Write([]string{
+			e.Time.UTC().Format(time.RFC3339),
+			e.TxHash.String(),
+			strconv.FormatUint(uint64(e.Vout), 10),
+			e.Category,
+			e.Account,
+			e.Address,
+			formatAmount(e.Amount),
+			fee,
+			e.Label,
+			e.ClaimOp,
+			e.ClaimName,
+			e.ClaimID,
+			fiatCurrency,
+			fiatRate,
+			fiatAmount,
+			e.Comment,
+			e.CommentTo,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ofxTime formats a time as an OFX date.
+func ofxTime(t time.Time) string {
+	return t.UTC().Format("20060102150405") + "[0:GMT]"
+}
+
+// WriteOFX writes entries as an OFX 2.2 bank statement.  Fees are written as
+// separate transactions.
+func WriteOFX(w io.Writer, entries []wallet.ExportEntry, start,
+	end time.Time) error {
+
+	now := time.Now()
+	if start.IsZero() && len(entries) != 0 {
+		start = entries[0].Time
+	}
+	if end.IsZero() {
+		end = now
+	}
+
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="no"?>` +
+		"\n")
+	b.WriteString(`<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" ` +
+		`OLDFILEUID="NONE" NEWFILEUID="NONE"?>` + "\n")
+	b.WriteString("<OFX>\n")
+	b.WriteString("<SIGNONMSGSRSV1><SONRS>\n")
+	b.WriteString("<STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>\n")
+	fmt.Fprintf(&b, "<DTSERVER>%s</DTSERVER>\n", ofxTime(now))
+	b.WriteString("<LANGUAGE>ENG</LANGUAGE>\n")
+	b.WriteString("</SONRS></SIGNONMSGSRSV1>\n")
+	b.WriteString("<BANKMSGSRSV1><STMTTRNRS>\n")
+	b.WriteString("<TRNUID>0</TRNUID>\n")
+	b.WriteString("<STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>\n")
+	b.WriteString("<STMTRS>\n")
+	fmt.Fprintf(&b, "<CURDEF>%s</CURDEF>\n", currency)
+	b.WriteString("<BANKACCTFROM><BANKID>lbcwallet</BANKID>" +
+		"<ACCTID>lbcwallet</ACCTID><ACCTTYPE>CHECKING</ACCTTYPE>" +
+		"</BANKACCTFROM>\n")
+	b.WriteString("<BANKTRANLIST>\n")
+	fmt.Fprintf(&b, "<DTSTART>%s</DTSTART>\n", ofxTime(start))
+	fmt.Fprintf(&b, "<DTEND>%s</DTEND>\n", ofxTime(end))
+	for i := range entries {
+		e := &entries[i]
+		trnType := "CREDIT"
+		if e.Amount < 0 {
+			trnType = "DEBIT"
+		}
+		writeOFXTransaction(&b, trnType, e.Time, e.Amount,
+			fmt.Sprintf("%v:%d:%s", e.TxHash, e.Vout, e.Category),
+			payee(e), memo(e))
+		if e.Fee != 0 {
+			writeOFXTransaction(&b, "FEE", e.Time, e.Fee,
+				fmt.Sprintf("%v:fee", e.TxHash), "Transaction fee",
+				e.TxHash.String())
+		}
+	}
+	b.WriteString("</BANKTRANLIST>\n")
+	b.WriteString("</STMTRS>\n")
+	b.WriteString("</STMTTRNRS></BANKMSGSRSV1>\n")
+	b.WriteString("</OFX>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// maxOFXName is the longest NAME of an OFX transaction.
+const maxOFXName = 32
+
+func writeOFXTransaction(b *strings.Builder, trnType string, t time.Time,
+	amount btcutil.Amount, id, name, memo string) {
+
+	if len(name) > maxOFXName {
+		name = name[:maxOFXName]
+	}
+	b.WriteString("<STMTTRN>\n")
+	fmt.Fprintf(b, "<TRNTYPE>%s</TRNTYPE>\n", trnType)
+	fmt.Fprintf(b, "<DTPOSTED>%s</DTPOSTED>\n", ofxTime(t))
+	fmt.Fprintf(b, "<TRNAMT>%s</TRNAMT>\n", formatAmount(amount))
+	fmt.Fprintf(b, "<FITID>%s</FITID>\n", escapeXML(id))
+	fmt.Fprintf(b, "<NAME>%s</NAME>\n", escapeXML(name))
+	if memo != "" {
+		fmt.Fprintf(b, "<MEMO>%s</MEMO>\n", escapeXML(memo))
+	}
+	b.WriteString("</STMTTRN>\n")
+}
+
+func escapeXML(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// WriteQIF writes entries as a QIF bank account.  The claim operation or the
+// category of an entry is written as its QIF category, and fees are written
+// as separate transactions.
+func WriteQIF(w io.Writer, entries []wallet.ExportEntry) error {
+	var b strings.Builder
+	b.WriteString("!Type:Bank\n")
+	for i := range entries {
+		e := &entries[i]
+		category := e.Category
+		if e.ClaimOp != "" {
+			category = e.ClaimOp
+		}
+		writeQIFTransaction(&b, e.Time, e.Amount, payee(e), memo(e),
+			category)
+		if e.Fee != 0 {
+			writeQIFTransaction(&b, e.Time, e.Fee, "Transaction fee",
+				e.TxHash.String(), "fee")
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeQIFTransaction(b *strings.Builder, t time.Time,
+	amount btcutil.Amount, payee, memo, category string) {
+
+	fmt.Fprintf(b, "D%s\n", t.UTC().Format("01/02/2006"))
+	fmt.Fprintf(b, "T%s\n", formatAmount(amount))
+	fmt.Fprintf(b, "P%s\n", qifLine(payee))
+	fmt.Fprintf(b, "M%s\n", qifLine(memo))
+	fmt.Fprintf(b, "L%s\n", qifLine(category))
+	b.WriteString("^\n")
+}
+
+// qifLine removes the line breaks from a QIF field.
+func qifLine(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
+
+// payee returns the counterparty written for an entry: its label, the
+// recipient it was sent to, or otherwise its address.
+func payee(e *wallet.ExportEntry) string {
+	switch {
+	case e.Label != "":
+		return e.Label
+	case e.CommentTo != "":
+		return e.CommentTo
+	}
+	return e.Address
+}
+
+// memo describes the transaction and claim of an entry, after the comment
+// it was sent with.
+func memo(e *wallet.ExportEntry) string {
+	s := fmt.Sprintf("%v:%d", e.TxHash, e.Vout)
+	if e.ClaimOp != "" {
+		s += fmt.Sprintf(" %s %s %s", e.ClaimOp, e.ClaimName, e.ClaimID)
+	}
+	if e.Comment != "" {
+		s = e.Comment + " " + s
+	}
+	return s
+}
+
+// formatAmount formats an amount in LBC with all eight decimals, without the
+// rounding of a float conversion.
+func formatAmount(a btcutil.Amount) string {
+	sign := ""
+	if a < 0 {
+		sign = "-"
+		a = -a
+	}
+	return fmt.Sprintf("%s%d.%08d", sign, a/btcutil.SatoshiPerBitcoin,
+		a%btcutil.SatoshiPerBitcoin)
+}
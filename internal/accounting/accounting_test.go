@@ -0,0 +1,183 @@
+package accounting
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/wallet"
+)
+
+func testEntries(t *testing.T) []wallet.ExportEntry {
+	hash, err := chainhash.NewHashFromStr("0102030405060708091011121314151617181920212223242526272829303132")
+	if err != nil {
+		t.Fatal(err)
+	}
+	when := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	return []wallet.ExportEntry{
+		{
+			Time:     when,
+			TxHash:   *hash,
+			Vout:     1,
+			Category: "receive",
+			Account:  "default",
+			Address:  "bExampleAddress",
+			Amount:   150000000,
+			Label:    "Salary, <March>",
+			FiatRate: &wallet.FiatRate{Currency: "USD", Rate: 0.0215},
+		},
+		{
+			Time:      when.Add(time.Hour),
+			TxHash:    *hash,
+			Vout:      0,
+			Category:  "send",
+			Account:   "default",
+			Address:   "bOtherAddress",
+			Amount:    -1,
+			Fee:       -2250,
+			ClaimOp:   "support",
+			ClaimName: "name",
+			ClaimID:   "beef",
+			Comment:   "Invoice 42",
+			CommentTo: "Alice",
+		},
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := Write(&buf, CSV, testEntries(t), time.Time{}, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want 3", len(records))
+	}
+	want := [][]string{
+		csvHeader,
+		{
+			"2021-03-04T05:06:07Z", testEntries(t)[0].TxHash.String(),
+			"1", "receive", "default", "bExampleAddress", "1.50000000",
+			"", "Salary, <March>", "", "", "", "USD", "0.0215", "0.03",
+			"", "",
+		},
+		{
+			"2021-03-04T06:06:07Z", testEntries(t)[0].TxHash.String(),
+			"0", "send", "default", "bOtherAddress", "-0.00000001",
+			"-0.00002250", "", "support", "name", "beef", "", "", "",
+			"Invoice 42", "Alice",
+		},
+	}
+	for i := range want {
+		if strings.Join(records[i], "|") != strings.Join(want[i], "|") {
+			t.Errorf("record %d:\ngot  %q\nwant %q", i, records[i],
+				want[i])
+		}
+	}
+}
+
+func TestWriteOFX(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	err := Write(&buf, OFX, testEntries(t), time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The document must be well formed XML.
+	dec := xml.NewDecoder(bytes.NewReader(buf.Bytes()))
+	for {
+		_, err := dec.Token()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			t.Fatalf("invalid XML: %v", err)
+		}
+	}
+	out := buf.String()
+	for _, s := range []string{
+		"<TRNTYPE>CREDIT</TRNTYPE>",
+		"<TRNAMT>1.50000000</TRNAMT>",
+		"<NAME>Salary, &lt;March&gt;</NAME>",
+		"<TRNTYPE>DEBIT</TRNTYPE>",
+		"<TRNTYPE>FEE</TRNTYPE>",
+		"<TRNAMT>-0.00002250</TRNAMT>",
+		"<DTSTART>20210304050607[0:GMT]</DTSTART>",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("OFX does not contain %s", s)
+		}
+	}
+	if n := strings.Count(out, "<STMTTRN>"); n != 3 {
+		t.Errorf("got %d transactions, want 3", n)
+	}
+}
+
+func TestWriteQIF(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	err := Write(&buf, QIF, testEntries(t), time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, "!Type:Bank\n") {
+		t.Errorf("QIF does not start with the account type")
+	}
+	if n := strings.Count(out, "^\n"); n != 3 {
+		t.Errorf("got %d transactions, want 3", n)
+	}
+	for _, s := range []string{
+		"D03/04/2021\nT1.50000000\nPSalary, <March>\n",
+		"T-0.00000001\nPAlice\nMInvoice 42 ",
+		"Lsupport\n",
+		"T-0.00002250\nPTransaction fee\n",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("QIF does not contain %q", s)
+		}
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		amount btcutil.Amount
+		want   string
+	}{
+		{0, "0.00000000"},
+		{1, "0.00000001"},
+		{-123456789, "-1.23456789"},
+		{2100000000000000, "21000000.00000000"},
+	}
+	for _, test := range tests {
+		if got := formatAmount(test.amount); got != test.want {
+			t.Errorf("formatAmount(%d) = %s, want %s", int64(test.amount),
+				got, test.want)
+		}
+	}
+}
+
+func TestUnsupportedFormat(t *testing.T) {
+	t.Parallel()
+
+	err := Write(&bytes.Buffer{}, Format("xls"), nil, time.Time{}, time.Time{})
+	if err != ErrFormat {
+		t.Fatalf("got error %v, want %v", err, ErrFormat)
+	}
+}
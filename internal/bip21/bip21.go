@@ -0,0 +1,157 @@
+// Package bip21 creates and parses BIP0021 payment URIs.
+package bip21
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	btcutil "github.com/lbryio/lbcutil"
+)
+
+// Scheme is the URI scheme of LBRY Credits payment URIs.
+const Scheme = "lbry"
+
+// Errors returned when parsing URIs.
+var (
+	ErrScheme    = errors.New("URI scheme is not " + Scheme)
+	ErrNoAddress = errors.New("URI has no address")
+	ErrAmount    = errors.New("URI amount is not a decimal number of coins")
+)
+
+// URI is a payment request.
+type URI struct {
+	Address string
+
+	// Amount is 0 when no amount is requested.
+	Amount btcutil.Amount
+
+	Label   string
+	Message string
+
+	// Params holds the optional parameters which are not otherwise
+	// recognized.
+	Params map[string]string
+}
+
+// String encodes the URI.  The parameters are percent-encoded as described
+// by RFC 3986.
+func (u *URI) String() string {
+	var params []string
+	if u.Amount != 0 {
+		amount := strconv.FormatFloat(u.Amount.ToBTC(), 'f', -1, 64)
+		params = append(params, "amount="+amount)
+	}
+	if u.Label != "" {
+		params = append(params, "label="+escape(u.Label))
+	}
+	if u.Message != "" {
+		params = append(params, "message="+escape(u.Message))
+	}
+	keys := make([]string, 0, len(u.Params))
+	for key := range u.Params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		params = append(params, escape(key)+"="+escape(u.Params[key]))
+	}
+
+	s := Scheme + ":" + u.Address
+	if len(params) != 0 {
+		s += "?" + strings.Join(params, "&")
+	}
+	return s
+}
+
+// Parse parses a payment URI.  The scheme is matched case-insensitively, and
+// unknown parameters prefixed with "req-", which the payer is required to
+// understand, are rejected.  The address is not validated.
+func Parse(s string) (*URI, error) {
+	i := strings.IndexByte(s, ':')
+	if i < 0 || !strings.EqualFold(s[:i], Scheme) {
+		return nil, ErrScheme
+	}
+	rest := s[i+1:]
+	rest = strings.TrimPrefix(rest, "//")
+
+	var query string
+	if i := strings.IndexByte(rest, '?'); i >= 0 {
+		rest, query = rest[:i], rest[i+1:]
+	}
+	if rest == "" {
+		return nil, ErrNoAddress
+	}
+	u := &URI{Address: rest}
+
+	seen := make(map[string]bool)
+	for _, param := range strings.Split(query, "&") {
+		if param == "" {
+			continue
+		}
+		key, value := param, ""
+		if i := strings.IndexByte(param, '='); i >= 0 {
+			key, value = param[:i], param[i+1:]
+		}
+		key, err := url.PathUnescape(key)
+		if err != nil {
+			return nil, err
+		}
+		value, err = url.PathUnescape(value)
+		if err != nil {
+			return nil, err
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("URI parameter %q is repeated", key)
+		}
+		seen[key] = true
+
+		switch key {
+		case "amount":
+			u.Amount, err = parseAmount(value)
+			if err != nil {
+				return nil, err
+			}
+		case "label":
+			u.Label = value
+		case "message":
+			u.Message = value
+		default:
+			if strings.HasPrefix(key, "req-") {
+				return nil, fmt.Errorf("URI requires unsupported "+
+					"parameter %q", key)
+			}
+			if u.Params == nil {
+				u.Params = make(map[string]string)
+			}
+			u.Params[key] = value
+		}
+	}
+	return u, nil
+}
+
+// parseAmount parses an amount in coins, which must be written in decimal
+// without an exponent.
+func parseAmount(s string) (btcutil.Amount, error) {
+	if s == "" || strings.Trim(s, "0123456789.") != "" ||
+		strings.Count(s, ".") > 1 {
+
+		return 0, ErrAmount
+	}
+	if i := strings.IndexByte(s, '.'); i >= 0 && len(s)-i-1 > 8 {
+		return 0, ErrAmount
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, ErrAmount
+	}
+	return btcutil.NewAmount(f)
+}
+
+func escape(s string) string {
+	// QueryEscape encodes spaces as "+", which RFC 3986 does not decode.
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
@@ -0,0 +1,82 @@
+package bip21
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		uri URI
+		s   string
+	}{
+		{
+			uri: URI{Address: "bHW58d37s1hBjj3wPBkn5zpCX3F8ZW3uWf"},
+			s:   "lbry:bHW58d37s1hBjj3wPBkn5zpCX3F8ZW3uWf",
+		},
+		{
+			uri: URI{
+				Address: "bHW58d37s1hBjj3wPBkn5zpCX3F8ZW3uWf",
+				Amount:  150000000,
+				Label:   "Coffee & cake",
+				Message: "Order #12+1",
+			},
+			s: "lbry:bHW58d37s1hBjj3wPBkn5zpCX3F8ZW3uWf?amount=1.5" +
+				"&label=Coffee%20%26%20cake&message=Order%20%2312%2B1",
+		},
+		{
+			uri: URI{
+				Address: "bHW58d37s1hBjj3wPBkn5zpCX3F8ZW3uWf",
+				Amount:  1,
+				Params:  map[string]string{"b": "2", "a": "1"},
+			},
+			s: "lbry:bHW58d37s1hBjj3wPBkn5zpCX3F8ZW3uWf?amount=0.00000001" +
+				"&a=1&b=2",
+		},
+	}
+	for _, test := range tests {
+		if s := test.uri.String(); s != test.s {
+			t.Errorf("got %q, want %q", s, test.s)
+		}
+		u, err := Parse(test.s)
+		if err != nil {
+			t.Errorf("%q: %v", test.s, err)
+			continue
+		}
+		if !reflect.DeepEqual(*u, test.uri) {
+			t.Errorf("%q: got %+v, want %+v", test.s, *u, test.uri)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	u, err := Parse("LBRY:bKxQ?label=a+b&amount=20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Address != "bKxQ" || u.Label != "a+b" || u.Amount != 2e9 {
+		t.Fatalf("unexpected URI %+v", u)
+	}
+
+	invalid := []string{
+		"bitcoin:bKxQ",
+		"bKxQ",
+		"lbry:",
+		"lbry:?amount=1",
+		"lbry:bKxQ?amount=1e3",
+		"lbry:bKxQ?amount=-1",
+		"lbry:bKxQ?amount=1.000000001",
+		"lbry:bKxQ?amount=1&amount=2",
+		"lbry:bKxQ?req-somethingyoudontunderstand=50",
+		"lbry:bKxQ?label=%zz",
+	}
+	for _, s := range invalid {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("%q: expected error", s)
+		}
+	}
+}
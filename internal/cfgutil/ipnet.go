@@ -0,0 +1,52 @@
+package cfgutil
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// ParseIPNets parses IP addresses and networks in CIDR notation, such as
+// 192.168.1.10 and 10.0.0.0/8.  An address is the network of that single
+// address.
+func ParseIPNets(specs []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(specs))
+	for _, spec := range specs {
+		if strings.Contains(spec, "/") {
+			_, ipNet, err := net.ParseCIDR(spec)
+			if err != nil {
+				return nil, err
+			}
+			nets = append(nets, ipNet)
+			continue
+		}
+		ip := net.ParseIP(spec)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid IP address %q", spec)
+		}
+		bits := 8 * net.IPv6len
+		if ip4 := ip.To4(); ip4 != nil {
+			ip, bits = ip4, 8*net.IPv4len
+		}
+		nets = append(nets, &net.IPNet{
+			IP:   ip,
+			Mask: net.CIDRMask(bits, bits),
+		})
+	}
+	return nets, nil
+}
+
+// IsUnspecifiedAddress returns whether the host of a normalized listen
+// address is empty or the unspecified IPv4 or IPv6 address, so that it
+// listens on every interface.
+func IsUnspecifiedAddress(hostport string) bool {
+	host, _, err := net.SplitHostPort(hostport)
+	if err != nil {
+		return false
+	}
+	if host == "" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsUnspecified()
+}
@@ -4,11 +4,15 @@
 
 package cfgutil
 
-import "net"
+import (
+	"net"
+	"strings"
+)
 
 // NormalizeAddress returns the normalized form of the address, adding a default
 // port if necessary.  An error is returned if the address, even without a port,
-// is not valid.
+// is not valid.  IPv6 addresses without a port may be enclosed in brackets, and
+// may have a zone, such as fe80::1%eth0.
 func NormalizeAddress(addr string, defaultPort string) (hostport string, err error) {
 	// If the first SplitHostPort errors because of a missing port and not
 	// for an invalid host, add the port.  If the second SplitHostPort
@@ -18,6 +22,9 @@ func NormalizeAddress(addr string, defaultPort string) (hostport string, err err
 	if origErr == nil {
 		return net.JoinHostPort(host, port), nil
 	}
+	if strings.HasPrefix(addr, "[") && strings.HasSuffix(addr, "]") {
+		addr = addr[1 : len(addr)-1]
+	}
 	addr = net.JoinHostPort(addr, defaultPort)
 	_, _, err = net.SplitHostPort(addr)
 	if err != nil {
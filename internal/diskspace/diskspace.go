@@ -0,0 +1,10 @@
+// Package diskspace reports the free space of the volume holding a path, where
+// the operating system supports it.
+package diskspace
+
+import "errors"
+
+// ErrUnsupported is returned by Free when the free space can not be read on
+// the operating system.
+var ErrUnsupported = errors.New("free disk space is not available on this " +
+	"operating system")
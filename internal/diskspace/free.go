@@ -0,0 +1,11 @@
+//go:build !linux && !darwin && !freebsd && !windows
+// +build !linux,!darwin,!freebsd,!windows
+
+package diskspace
+
+// Free returns the number of bytes available on the volume holding path.  The
+// free space can not be read on this operating system, so ErrUnsupported is
+// returned.
+func Free(path string) (uint64, error) {
+	return 0, ErrUnsupported
+}
@@ -0,0 +1,23 @@
+package diskspace
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestFree(t *testing.T) {
+	free, err := Free(t.TempDir())
+	if err == ErrUnsupported {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if free == 0 {
+		t.Fatal("no free space in the temporary directory")
+	}
+
+	if _, err := Free(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Fatal("free space of a missing path")
+	}
+}
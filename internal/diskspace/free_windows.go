@@ -0,0 +1,18 @@
+package diskspace
+
+import "golang.org/x/sys/windows"
+
+// Free returns the number of bytes available to the user of the process on the
+// volume holding path, which takes disk quotas into account.
+func Free(path string) (uint64, error) {
+	p, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, err
+	}
+	var free, total, totalFree uint64
+	err = windows.GetDiskFreeSpaceEx(p, &free, &total, &totalFree)
+	if err != nil {
+		return 0, err
+	}
+	return free, nil
+}
@@ -0,0 +1,93 @@
+package faucet
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lbryio/lbcd/chaincfg"
+	btcutil "github.com/lbryio/lbcutil"
+)
+
+func TestHTTPFaucet(t *testing.T) {
+	t.Parallel()
+
+	const txid = "4a5e1e4baab89f3a32518a88c31bc87f" +
+		"618f76673e2cc77ab2127b7afdeda33b"
+	var gotQuery, gotAddress, gotAmount string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+		r *http.Request) {
+
+		gotQuery = r.URL.RawQuery
+		gotAddress = r.PostFormValue("address")
+		gotAmount = r.PostFormValue("amount")
+		switch r.URL.Path {
+		case "/object":
+			fmt.Fprintf(w, `{"txid":%q,"message":"sent"}`, txid)
+		case "/string":
+			fmt.Fprintf(w, "%q", txid)
+		case "/text":
+			fmt.Fprint(w, "coins sent")
+		case "/limited":
+			http.Error(w, "try again tomorrow",
+				http.StatusTooManyRequests)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	addr, err := btcutil.NewAddressPubKeyHash(make([]byte, 20),
+		&chaincfg.TestNet3Params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		url    string
+		amount btcutil.Amount
+		query  string
+		txid   bool
+		fails  bool
+	}{
+		{
+			url:    "/object?to={address}&n={amount}",
+			amount: 150000000,
+			query:  "to=" + addr.EncodeAddress() + "&n=1.5",
+			txid:   true,
+		},
+		{url: "/string", txid: true},
+		{url: "/text"},
+		{url: "/limited", fails: true},
+	}
+	for _, test := range tests {
+		f := &HTTPFaucet{URL: srv.URL + test.url}
+		hash, err := f.RequestCoins(addr, test.amount)
+		if test.fails {
+			if err == nil {
+				t.Errorf("%s: expected an error", test.url)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.url, err)
+			continue
+		}
+		if gotQuery != test.query {
+			t.Errorf("%s: requested query %q, expected %q",
+				test.url, gotQuery, test.query)
+		}
+		if gotAddress != addr.EncodeAddress() {
+			t.Errorf("%s: requested address %q", test.url,
+				gotAddress)
+		}
+		if test.amount != 0 && gotAmount != "1.5" {
+			t.Errorf("%s: requested amount %q", test.url, gotAmount)
+		}
+		if test.txid != (hash != nil) ||
+			(hash != nil && hash.String() != txid) {
+
+			t.Errorf("%s: returned txid %v", test.url, hash)
+		}
+	}
+}
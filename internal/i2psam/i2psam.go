@@ -0,0 +1,346 @@
+// Package i2psam implements the client side of the SAM v3 protocol of I2P
+// routers, enough to open streams to I2P destinations from a transient
+// destination.
+package i2psam
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// dialTimeout bounds the time to connect to the SAM bridge when the
+	// dial has no timeout.
+	dialTimeout = 10 * time.Second
+
+	// sessionTimeout bounds the time the router takes to create a
+	// session, which requires building its tunnels.
+	sessionTimeout = 2 * time.Minute
+
+	// maxLineLen is the maximum length of a reply of the SAM bridge.
+	maxLineLen = 64 * 1024
+)
+
+// Error is a reply of the SAM bridge to a command which failed.
+type Error struct {
+	Result  string
+	Message string
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return "i2p sam error " + e.Result
+	}
+	return fmt.Sprintf("i2p sam error %s: %s", e.Result, e.Message)
+}
+
+// Addr is the address of an I2P destination.
+type Addr string
+
+// Network returns "i2p".
+func (a Addr) Network() string { return "i2p" }
+
+// String returns the host name or base 64 destination of the address.
+func (a Addr) String() string { return string(a) }
+
+// conn is a stream to an I2P destination, whose remote address is the
+// destination.
+type conn struct {
+	net.Conn
+	remote Addr
+}
+
+func (c *conn) RemoteAddr() net.Addr { return c.remote }
+
+// Dialer opens streams to I2P destinations through the SAM bridge of an I2P
+// router.  The streams are opened from a transient destination, which is
+// created with the first stream and kept until the Dialer is closed or the
+// router drops it.  It is safe for concurrent use.
+type Dialer struct {
+	addr string
+
+	mtx     sync.Mutex
+	session *session
+	closed  bool
+}
+
+// session is a stream session, which lives as long as its control
+// connection.
+type session struct {
+	id      string
+	control net.Conn
+	done    chan struct{}
+}
+
+// NewDialer returns a dialer using the SAM bridge at addr.
+func NewDialer(addr string) *Dialer {
+	return &Dialer{addr: addr}
+}
+
+// Close closes the session of the dialer.  Open streams are not closed.
+func (d *Dialer) Close() error {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	d.closed = true
+	if d.session != nil {
+		return d.session.control.Close()
+	}
+	return nil
+}
+
+// DialTimeout opens a stream to the I2P host and port of addr, waiting no
+// longer than timeout unless it is zero.  The host is an I2P host name, such
+// as a .b32.i2p address, or a base 64 destination.  Only the tcp network is
+// supported.
+func (d *Dialer) DialTimeout(network, addr string,
+	timeout time.Duration) (net.Conn, error) {
+
+	if network != "tcp" {
+		return nil, fmt.Errorf("unsupported network %q", network)
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+
+	s, err := d.getSession(deadline)
+	if err != nil {
+		return nil, err
+	}
+	c, version, err := d.hello(deadline)
+	if err != nil {
+		return nil, err
+	}
+	dest := host
+	if strings.HasSuffix(strings.ToLower(host), ".i2p") {
+		dest, err = lookup(c, host)
+		if err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	cmd := fmt.Sprintf("STREAM CONNECT ID=%s DESTINATION=%s SILENT=false",
+		s.id, dest)
+	// Ports are supported from version 3.2.
+	if version != "3.1" && port != "" && port != "0" {
+		cmd += " TO_PORT=" + port
+	}
+	if _, err := command(c, cmd, "STREAM", "STATUS"); err != nil {
+		c.Close()
+		return nil, err
+	}
+	_ = c.SetDeadline(time.Time{})
+	return &conn{Conn: c, remote: Addr(host)}, nil
+}
+
+// getSession returns the session of the dialer, creating it if there is none
+// or it was dropped.
+func (d *Dialer) getSession(deadline time.Time) (*session, error) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	if d.closed {
+		return nil, errors.New("i2p sam dialer closed")
+	}
+	if d.session != nil {
+		select {
+		case <-d.session.done:
+		default:
+			return d.session, nil
+		}
+	}
+
+	// Tunnels may take longer than the dial timeout to be built, and the
+	// session is kept for later dials.
+	if deadline.IsZero() || time.Until(deadline) < sessionTimeout {
+		deadline = time.Now().Add(sessionTimeout)
+	}
+	c, _, err := d.hello(deadline)
+	if err != nil {
+		return nil, err
+	}
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		c.Close()
+		return nil, err
+	}
+	id := "lbcwallet-" + hex.EncodeToString(b[:])
+	_, err = command(c, "SESSION CREATE STYLE=STREAM ID="+id+
+		" DESTINATION=TRANSIENT SIGNATURE_TYPE=EdDSA_SHA512_Ed25519",
+		"SESSION", "STATUS")
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	_ = c.SetDeadline(time.Time{})
+
+	s := &session{id: id, control: c, done: make(chan struct{})}
+	go s.keepAlive()
+	d.session = s
+	return s, nil
+}
+
+// keepAlive answers the pings of the bridge on the control connection until
+// it is closed, which ends the session.
+func (s *session) keepAlive() {
+	defer close(s.done)
+	defer s.control.Close()
+	for {
+		line, err := readLine(s.control)
+		if err != nil {
+			return
+		}
+		if strings.HasPrefix(line, "PING") {
+			pong := "PONG" + strings.TrimPrefix(line, "PING") + "\n"
+			if _, err := s.control.Write([]byte(pong)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// hello connects to the bridge and negotiates the protocol version, which is
+// returned.
+func (d *Dialer) hello(deadline time.Time) (net.Conn, string, error) {
+	timeout := dialTimeout
+	if !deadline.IsZero() {
+		timeout = time.Until(deadline)
+	}
+	c, err := net.DialTimeout("tcp", d.addr, timeout)
+	if err != nil {
+		return nil, "", err
+	}
+	if !deadline.IsZero() {
+		_ = c.SetDeadline(deadline)
+	}
+	reply, err := command(c, "HELLO VERSION MIN=3.1 MAX=3.3", "HELLO",
+		"REPLY")
+	if err != nil {
+		c.Close()
+		return nil, "", err
+	}
+	return c, reply["VERSION"], nil
+}
+
+// lookup returns the base 64 destination of an I2P host name.
+func lookup(c net.Conn, name string) (string, error) {
+	reply, err := command(c, "NAMING LOOKUP NAME="+name, "NAMING", "REPLY")
+	if err != nil {
+		return "", err
+	}
+	if reply["VALUE"] == "" {
+		return "", fmt.Errorf("no destination for %s", name)
+	}
+	return reply["VALUE"], nil
+}
+
+// command sends a command and returns the keywords of its reply, which must
+// start with the two words given and succeed.
+func command(c net.Conn, cmd, word1, word2 string) (map[string]string,
+	error) {
+
+	if _, err := c.Write([]byte(cmd + "\n")); err != nil {
+		return nil, err
+	}
+	line, err := readLine(c)
+	if err != nil {
+		return nil, err
+	}
+	fields := strings.SplitN(line, " ", 3)
+	if len(fields) < 2 || fields[0] != word1 || fields[1] != word2 {
+		return nil, fmt.Errorf("invalid i2p sam reply %q", line)
+	}
+	var reply map[string]string
+	if len(fields) == 3 {
+		reply = parseKeywords(fields[2])
+	} else {
+		reply = make(map[string]string)
+	}
+	if result := reply["RESULT"]; result != "OK" {
+		return nil, &Error{Result: result, Message: reply["MESSAGE"]}
+	}
+	return reply, nil
+}
+
+// readLine reads a line from c a byte at a time, so that no data following
+// it is consumed.
+func readLine(c net.Conn) (string, error) {
+	var line []byte
+	var b [1]byte
+	for {
+		if _, err := c.Read(b[:]); err != nil {
+			return "", err
+		}
+		if b[0] == '\n' {
+			return strings.TrimSuffix(string(line), "\r"), nil
+		}
+		if len(line) == maxLineLen {
+			return "", errors.New("i2p sam reply too long")
+		}
+		line = append(line, b[0])
+	}
+}
+
+// parseKeywords parses the space separated KEY=VALUE pairs of a reply, whose
+// values may be quoted.
+func parseKeywords(s string) map[string]string {
+	kw := make(map[string]string)
+	for {
+		s = strings.TrimLeft(s, " ")
+		if s == "" {
+			break
+		}
+		eq := strings.IndexByte(s, '=')
+		sp := strings.IndexByte(s, ' ')
+		if eq < 0 || (sp >= 0 && sp < eq) {
+			// A key without a value.
+			if sp < 0 {
+				kw[s] = ""
+				break
+			}
+			kw[s[:sp]] = ""
+			s = s[sp:]
+			continue
+		}
+		key := s[:eq]
+		s = s[eq+1:]
+		if strings.HasPrefix(s, `"`) {
+			end := 1
+			for end < len(s) && s[end] != '"' {
+				if s[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(s) {
+				end = len(s) - 1
+			}
+			if v, err := strconv.Unquote(s[:end+1]); err == nil {
+				kw[key] = v
+			} else {
+				kw[key] = strings.Trim(s[:end+1], `"`)
+			}
+			s = s[end+1:]
+			continue
+		}
+		sp = strings.IndexByte(s, ' ')
+		if sp < 0 {
+			kw[key] = s
+			break
+		}
+		kw[key] = s[:sp]
+		s = s[sp:]
+	}
+	return kw
+}
@@ -0,0 +1,151 @@
+package i2psam
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeBridge serves the SAM commands of clients on l like an I2P router whose
+// only destination is dest, known as name, and echoes the data of the streams
+// to it.  The commands it received are sent to cmds.
+func fakeBridge(l net.Listener, name, dest string, cmds chan<- string) {
+	for {
+		c, err := l.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer c.Close()
+			r := bufio.NewReader(c)
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				line = strings.TrimRight(line, "\n")
+				cmds <- line
+
+				var reply string
+				kw := parseKeywords(line)
+				switch {
+				case strings.HasPrefix(line, "HELLO VERSION"):
+					reply = "HELLO REPLY RESULT=OK VERSION=3.3"
+				case strings.HasPrefix(line, "SESSION CREATE"):
+					reply = "SESSION STATUS RESULT=OK DESTINATION=priv"
+				case strings.HasPrefix(line, "NAMING LOOKUP"):
+					reply = "NAMING REPLY RESULT=KEY_NOT_FOUND " +
+						"NAME=" + kw["NAME"]
+					if kw["NAME"] == name {
+						reply = "NAMING REPLY RESULT=OK NAME=" +
+							name + " VALUE=" + dest
+					}
+				case strings.HasPrefix(line, "STREAM CONNECT"):
+					if kw["DESTINATION"] != dest {
+						reply = `STREAM STATUS ` +
+							`RESULT=CANT_REACH_PEER ` +
+							`MESSAGE="unknown peer"`
+						break
+					}
+					_, _ = c.Write([]byte("STREAM STATUS RESULT=OK\n"))
+					_, _ = io.Copy(c, r)
+					return
+				default:
+					reply = "UNKNOWN RESULT=I2P_ERROR"
+				}
+				if _, err := c.Write([]byte(reply + "\n")); err != nil {
+					return
+				}
+			}
+		}()
+	}
+}
+
+func TestDialer(t *testing.T) {
+	t.Parallel()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	cmds := make(chan string, 100)
+	const name = "node.b32.i2p"
+	go fakeBridge(l, name, "AAAA~dest", cmds)
+
+	d := NewDialer(l.Addr().String())
+	defer d.Close()
+
+	for i := 0; i < 2; i++ {
+		c, err := d.DialTimeout("tcp", name+":9245", 10*time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.RemoteAddr().Network() != "i2p" ||
+			c.RemoteAddr().String() != name {
+
+			t.Fatalf("remote address %v", c.RemoteAddr())
+		}
+		if _, err := c.Write([]byte("ping")); err != nil {
+			t.Fatal(err)
+		}
+		var buf [4]byte
+		if _, err := io.ReadFull(c, buf[:]); err != nil {
+			t.Fatal(err)
+		}
+		if string(buf[:]) != "ping" {
+			t.Fatalf("read %q", buf[:])
+		}
+		c.Close()
+	}
+
+	// The session is created once and used by both streams.
+	var sessions, connects int
+	close(cmds)
+	for cmd := range cmds {
+		switch {
+		case strings.HasPrefix(cmd, "SESSION CREATE"):
+			sessions++
+		case strings.HasPrefix(cmd, "STREAM CONNECT"):
+			connects++
+			if !strings.Contains(cmd, "TO_PORT=9245") {
+				t.Fatalf("no port in %q", cmd)
+			}
+		}
+	}
+	if sessions != 1 || connects != 2 {
+		t.Fatalf("%d sessions and %d stream connects", sessions,
+			connects)
+	}
+}
+
+func TestDialerErrors(t *testing.T) {
+	t.Parallel()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go fakeBridge(l, "node.b32.i2p", "AAAA~dest", make(chan string, 100))
+
+	d := NewDialer(l.Addr().String())
+	defer d.Close()
+
+	_, err = d.DialTimeout("tcp", "unknown.b32.i2p:9245", 10*time.Second)
+	if e, ok := err.(*Error); !ok || e.Result != "KEY_NOT_FOUND" {
+		t.Fatalf("lookup of an unknown name: %v", err)
+	}
+	_, err = d.DialTimeout("tcp", "BBBB~dest:9245", 10*time.Second)
+	if e, ok := err.(*Error); !ok || e.Result != "CANT_REACH_PEER" ||
+		e.Message != "unknown peer" {
+
+		t.Fatalf("stream to an unknown destination: %v", err)
+	}
+	if _, err := d.DialTimeout("udp", "node.b32.i2p:9245", 0); err == nil {
+		t.Fatal("dial of udp succeeded")
+	}
+}
@@ -0,0 +1,256 @@
+// Package lbrysdk reads the JSON wallet files of lbry-sdk, the LBRY daemon
+// also known as lbrynet (formerly torba), whose accounts are BIP0032 master
+// keys deriving pay-to-pubkey-hash addresses on their receiving (0) and change
+// (1) chains, and whose certificates are the private keys of channels.
+package lbrysdk
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha512"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lbryio/lbcd/btcec"
+	"github.com/lbryio/lbcd/chaincfg"
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcutil/hdkeychain"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// Version is the version of the wallet file format.
+const Version = 1
+
+// DeterministicChain is the address generator of accounts deriving their
+// addresses from their receiving and change chains.  The other generator,
+// "single-address", uses the master key of an account as its only key.
+const DeterministicChain = "deterministic-chain"
+
+// seedSalt is the salt of the PBKDF2 derivation of a seed from its mnemonic,
+// in place of the "electrum" salt of Electrum seeds.
+const seedSalt = "lbryum"
+
+// Errors returned when parsing and decrypting wallets.
+var (
+	ErrVersion  = errors.New("unsupported lbry-sdk wallet version")
+	ErrPassword = errors.New("incorrect lbry-sdk wallet password")
+	ErrNoKey    = errors.New("the account has neither a seed nor a " +
+		"private key")
+)
+
+// ledgers maps the ledgers of accounts to their networks.
+var ledgers = map[string]*chaincfg.Params{
+	"lbc_mainnet": &chaincfg.MainNetParams,
+	"lbc_testnet": &chaincfg.TestNet3Params,
+	"lbc_regtest": &chaincfg.RegressionNetParams,
+}
+
+// AddressGenerator describes how the addresses of an account are derived.
+type AddressGenerator struct {
+	Name string `json:"name"`
+}
+
+// Account is an account of a wallet.
+type Account struct {
+	Ledger string `json:"ledger"`
+	Name   string `json:"name"`
+
+	// Seed and PrivateKey are encrypted with the wallet password when
+	// Encrypted is set.  Either may be empty.
+	Seed       string `json:"seed"`
+	Encrypted  bool   `json:"encrypted"`
+	PrivateKey string `json:"private_key"`
+	PublicKey  string `json:"public_key"`
+
+	AddressGenerator AddressGenerator `json:"address_generator"`
+
+	// Certificates maps the claim IDs of channels, or the addresses of
+	// their keys in later versions, to their PEM encoded private keys.
+	Certificates map[string]string `json:"certificates"`
+}
+
+// Wallet is the content of a wallet file.
+type Wallet struct {
+	Version  int        `json:"version"`
+	Name     string     `json:"name"`
+	Accounts []*Account `json:"accounts"`
+}
+
+// Parse reads a wallet file.
+func Parse(b []byte) (*Wallet, error) {
+	var w Wallet
+	if err := json.Unmarshal(b, &w); err != nil {
+		return nil, err
+	}
+	if w.Version != Version {
+		return nil, ErrVersion
+	}
+	for i, a := range w.Accounts {
+		if _, ok := ledgers[a.Ledger]; !ok {
+			return nil, fmt.Errorf("account %d: unknown ledger %q", i,
+				a.Ledger)
+		}
+	}
+	return &w, nil
+}
+
+// Encrypted returns whether the keys of any account are encrypted.
+func (w *Wallet) Encrypted() bool {
+	for _, a := range w.Accounts {
+		if a.Encrypted {
+			return true
+		}
+	}
+	return false
+}
+
+// Decrypt decrypts the seeds and private keys of the encrypted accounts with
+// the wallet password.
+func (w *Wallet) Decrypt(password string) error {
+	type decrypted struct {
+		seed, privKey string
+	}
+	plain := make([]decrypted, len(w.Accounts))
+	for i, a := range w.Accounts {
+		if !a.Encrypted {
+			continue
+		}
+		var err error
+		if a.Seed != "" {
+			plain[i].seed, err = decrypt(password, a.Seed)
+			if err != nil {
+				return err
+			}
+		}
+		if a.PrivateKey != "" {
+			plain[i].privKey, err = decrypt(password, a.PrivateKey)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	for i, a := range w.Accounts {
+		if a.Encrypted {
+			a.Seed, a.PrivateKey = plain[i].seed, plain[i].privKey
+			a.Encrypted = false
+		}
+	}
+	return nil
+}
+
+// Net returns the network of the account.
+func (a *Account) Net() *chaincfg.Params {
+	return ledgers[a.Ledger]
+}
+
+// Key returns the extended private key of the account, which is its master
+// key, from its private key or else from its seed.
+func (a *Account) Key() (*hdkeychain.ExtendedKey, error) {
+	if a.Encrypted {
+		return nil, ErrPassword
+	}
+	if a.PrivateKey != "" {
+		key, err := hdkeychain.NewKeyFromString(a.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		if !key.IsPrivate() {
+			return nil, errors.New("the private key of the account " +
+				"is a public key")
+		}
+		if !key.IsForNet(a.Net()) {
+			return nil, errors.New("the private key of the account " +
+				"is for another network")
+		}
+		return key, nil
+	}
+	if a.Seed == "" {
+		return nil, ErrNoKey
+	}
+	return seedKey(a.Seed, a.Net())
+}
+
+// seedKey returns the master key of a mnemonic seed.
+func seedKey(mnemonic string,
+	params *chaincfg.Params) (*hdkeychain.ExtendedKey, error) {
+
+	mnemonic = strings.Join(strings.Fields(strings.ToLower(mnemonic)), " ")
+	seed := pbkdf2.Key(
+		[]byte(mnemonic), []byte(seedSalt), 2048, 64, sha512.New,
+	)
+	return hdkeychain.NewMaster(seed, params)
+}
+
+// ChannelKey returns the private key of a certificate.
+func ChannelKey(certificate string) (*btcec.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(certificate))
+	if block == nil || block.Type != "EC PRIVATE KEY" {
+		return nil, errors.New("certificate is not a PEM encoded EC " +
+			"private key")
+	}
+
+	// The SEC1 ECPrivateKey structure, whose curve is secp256k1 and so
+	// unsupported by crypto/x509.
+	var ecKey struct {
+		Version    int
+		PrivateKey []byte
+		Curve      asn1.ObjectIdentifier `asn1:"optional,explicit,tag:0"`
+		PublicKey  asn1.BitString        `asn1:"optional,explicit,tag:1"`
+	}
+	_, err := asn1.Unmarshal(block.Bytes, &ecKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate: %w", err)
+	}
+	if ecKey.Version != 1 || len(ecKey.PrivateKey) == 0 ||
+		len(ecKey.PrivateKey) > 32 {
+
+		return nil, errors.New("invalid certificate private key")
+	}
+	privKey, _ := btcec.PrivKeyFromBytes(btcec.S256(), ecKey.PrivateKey)
+	return privKey, nil
+}
+
+// decrypt decrypts a base64 encoded AES-256-CBC ciphertext prefixed by its IV,
+// whose key is the double SHA256 of the password.
+func decrypt(password, value string) (string, error) {
+	b, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid encrypted value: %w", err)
+	}
+	if len(b) < 2*aes.BlockSize || len(b)%aes.BlockSize != 0 {
+		return "", errors.New("invalid encrypted value length")
+	}
+	block, err := aes.NewCipher(chainhash.DoubleHashB([]byte(password)))
+	if err != nil {
+		return "", err
+	}
+	iv, ciphertext := b[:aes.BlockSize], b[aes.BlockSize:]
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+
+	pad := int(plaintext[len(plaintext)-1])
+	if pad == 0 || pad > aes.BlockSize {
+		return "", ErrPassword
+	}
+	for _, b := range plaintext[len(plaintext)-pad:] {
+		if int(b) != pad {
+			return "", ErrPassword
+		}
+	}
+
+	// Seeds and extended keys are printable, unlike most values
+	// decrypted with a wrong password whose padding happens to be valid.
+	plain := string(plaintext[:len(plaintext)-pad])
+	for _, r := range plain {
+		if r < 0x20 || r == utf8.RuneError {
+			return "", ErrPassword
+		}
+	}
+	return plain, nil
+}
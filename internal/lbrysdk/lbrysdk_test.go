@@ -0,0 +1,149 @@
+package lbrysdk
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"testing"
+
+	"github.com/lbryio/lbcd/btcec"
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+)
+
+// The seed and master key of an account of the lbry-sdk tests.
+const (
+	testSeed = "carbon smart garage balance margin twelve chest sword " +
+		"toast envelope bottom stomach absent"
+	testPrivateKey = "xprv9s21ZrQH143K42ovpZygnjfHdAqSd9jo7zceDfPRogM7bk" +
+		"koNVv7DRNLEoB8HoirMgH969NrgL8jNzLEegqFzPRWM37GXd4uE8uuRkx4LAe"
+)
+
+// encrypt encrypts a value as lbry-sdk does.
+func encrypt(password, value string) string {
+	iv := bytes.Repeat([]byte{1}, aes.BlockSize)
+	pad := aes.BlockSize - len(value)%aes.BlockSize
+	plaintext := append([]byte(value), bytes.Repeat([]byte{byte(pad)}, pad)...)
+	block, _ := aes.NewCipher(chainhash.DoubleHashB([]byte(password)))
+	ciphertext := make([]byte, len(plaintext))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, plaintext)
+	return base64.StdEncoding.EncodeToString(append(iv, ciphertext...))
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	w, err := Parse([]byte(`{
+		"version": 1,
+		"name": "My Wallet",
+		"preferences": {},
+		"accounts": [{
+			"ledger": "lbc_mainnet",
+			"name": "Main Account",
+			"seed": "` + testSeed + `",
+			"encrypted": false,
+			"private_key": "",
+			"public_key": "",
+			"address_generator": {
+				"name": "deterministic-chain",
+				"receiving": {"gap": 20, "maximum_uses_per_address": 1},
+				"change": {"gap": 6, "maximum_uses_per_address": 1}
+			},
+			"modified_on": 1567000000.0,
+			"certificates": {}
+		}]
+	}`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if w.Encrypted() || len(w.Accounts) != 1 {
+		t.Fatal("wrong accounts")
+	}
+	a := w.Accounts[0]
+	if a.Name != "Main Account" || a.AddressGenerator.Name != DeterministicChain {
+		t.Fatalf("wrong account %+v", a)
+	}
+
+	// The key derived from the seed is the master key lbry-sdk derives.
+	key, err := a.Key()
+	if err != nil {
+		t.Fatalf("Key: %v", err)
+	}
+	if key.String() != testPrivateKey {
+		t.Fatalf("got key %v, want %v", key, testPrivateKey)
+	}
+
+	_, err = Parse([]byte(`{"version": 2, "accounts": []}`))
+	if err != ErrVersion {
+		t.Fatalf("got %v, want ErrVersion", err)
+	}
+	_, err = Parse([]byte(`{"version": 1, "accounts": [{"ledger": "btc"}]}`))
+	if err == nil {
+		t.Fatal("parsed an account of an unknown ledger")
+	}
+}
+
+func TestDecrypt(t *testing.T) {
+	t.Parallel()
+
+	w := &Wallet{
+		Version: Version,
+		Accounts: []*Account{{
+			Ledger:     "lbc_mainnet",
+			Seed:       encrypt("password", testSeed),
+			PrivateKey: encrypt("password", testPrivateKey),
+			Encrypted:  true,
+		}},
+	}
+	if !w.Encrypted() {
+		t.Fatal("wallet is not encrypted")
+	}
+	if _, err := w.Accounts[0].Key(); err != ErrPassword {
+		t.Fatalf("got %v, want ErrPassword", err)
+	}
+	if err := w.Decrypt("wrong"); err != ErrPassword {
+		t.Fatalf("got %v, want ErrPassword", err)
+	}
+	if err := w.Decrypt("password"); err != nil {
+		t.Fatalf("Decrypt: %v", err)
+	}
+	a := w.Accounts[0]
+	if a.Encrypted || a.Seed != testSeed || a.PrivateKey != testPrivateKey {
+		t.Fatalf("wrong decrypted account %+v", a)
+	}
+	key, err := a.Key()
+	if err != nil || key.String() != testPrivateKey {
+		t.Fatalf("got key %v, %v", key, err)
+	}
+}
+
+func TestChannelKey(t *testing.T) {
+	t.Parallel()
+
+	privKey, _ := btcec.NewPrivateKey(btcec.S256())
+	der, err := asn1.Marshal(struct {
+		Version    int
+		PrivateKey []byte
+		Curve      asn1.ObjectIdentifier `asn1:"explicit,tag:0"`
+	}{1, privKey.Serialize(), asn1.ObjectIdentifier{1, 3, 132, 0, 10}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	certificate := pem.EncodeToMemory(&pem.Block{
+		Type:  "EC PRIVATE KEY",
+		Bytes: der,
+	})
+
+	key, err := ChannelKey(string(certificate))
+	if err != nil {
+		t.Fatalf("ChannelKey: %v", err)
+	}
+	if key.D.Cmp(privKey.D) != 0 {
+		t.Fatal("wrong channel key")
+	}
+	if _, err := ChannelKey("not a certificate"); err == nil {
+		t.Fatal("parsed an invalid certificate")
+	}
+}
@@ -0,0 +1,117 @@
+// Package prices implements sources of historical LBC exchange rates.
+package prices
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxResponseSize is the largest response body read from a price endpoint.
+const maxResponseSize = 1 << 20
+
+// HTTPSource queries an HTTP endpoint returning JSON for the rate of LBC.
+//
+// The placeholders {currency} and {currencylower} in the URL and Field are
+// replaced by the currency as given and in lower case.  In the URL, {unix} is
+// replaced by the time of the rate in seconds since the epoch, {unixms} by
+// the same in milliseconds, {date} by the UTC date as YYYY-MM-DD, and
+// {date-dmy} by the UTC date as DD-MM-YYYY.
+type HTTPSource struct {
+	// URL is the endpoint to GET.
+	URL string
+
+	// Field is the dot separated path of the rate within the response.
+	// Numeric elements index arrays.  The rate may be a JSON number or a
+	// string holding a number.
+	Field string
+
+	// Client is used to make requests, or http.DefaultClient if nil.
+	Client *http.Client
+}
+
+// Rate returns the price of one LBC in currency at time t.
+func (s *HTTPSource) Rate(currency string, t time.Time) (float64, error) {
+	t = t.UTC()
+	url := strings.NewReplacer(
+		"{currency}", currency,
+		"{currencylower}", strings.ToLower(currency),
+		"{unixms}", strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10),
+		"{unix}", strconv.FormatInt(t.Unix(), 10),
+		"{date-dmy}", t.Format("02-01-2006"),
+		"{date}", t.Format("2006-01-02"),
+	).Replace(s.URL)
+	field := strings.NewReplacer(
+		"{currency}", currency,
+		"{currencylower}", strings.ToLower(currency),
+	).Replace(s.Field)
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Get(url)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("price endpoint returned %s", resp.Status)
+	}
+
+	var body interface{}
+	dec := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize))
+	dec.UseNumber()
+	if err := dec.Decode(&body); err != nil {
+		return 0, fmt.Errorf("invalid price response: %v", err)
+	}
+	return lookupRate(body, field)
+}
+
+// lookupRate returns the rate found at the dot separated path within a
+// decoded JSON value.
+func lookupRate(v interface{}, path string) (float64, error) {
+	if path != "" {
+		for _, elem := range strings.Split(path, ".") {
+			switch x := v.(type) {
+			case map[string]interface{}:
+				var ok bool
+				if v, ok = x[elem]; !ok {
+					return 0, fmt.Errorf("price response has no "+
+						"field %q", path)
+				}
+			case []interface{}:
+				i, err := strconv.Atoi(elem)
+				if err != nil || i < 0 || i >= len(x) {
+					return 0, fmt.Errorf("price response has no "+
+						"field %q", path)
+				}
+				v = x[i]
+			default:
+				return 0, fmt.Errorf("price response has no field %q",
+					path)
+			}
+		}
+	}
+
+	var s string
+	switch x := v.(type) {
+	case json.Number:
+		s = string(x)
+	case string:
+		s = x
+	default:
+		return 0, fmt.Errorf("price response field %q is not a number",
+			path)
+	}
+	rate, err := strconv.ParseFloat(s, 64)
+	if err != nil || rate <= 0 {
+		return 0, fmt.Errorf("price response field %q is not a positive "+
+			"number", path)
+	}
+	return rate, nil
+}
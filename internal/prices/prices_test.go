@@ -0,0 +1,78 @@
+package prices
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHTTPSource(t *testing.T) {
+	t.Parallel()
+
+	var gotPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+		r *http.Request) {
+
+		gotPath = r.URL.RequestURI()
+		switch r.URL.Path {
+		case "/number":
+			fmt.Fprint(w, `{"market_data":{"current_price":{"usd":0.0215}}}`)
+		case "/string":
+			fmt.Fprint(w, `{"data":[{"rate":"1.5e-2"}]}`)
+		case "/negative":
+			fmt.Fprint(w, `{"rate":-1}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	when := time.Date(2021, 3, 4, 5, 6, 7, 0, time.FixedZone("", 3600))
+	tests := []struct {
+		url   string
+		field string
+		path  string
+		rate  float64
+		fails bool
+	}{
+		{
+			url:   "/number?date={date-dmy}&c={currency}",
+			field: "market_data.current_price.{currencylower}",
+			path:  "/number?date=04-03-2021&c=USD",
+			rate:  0.0215,
+		},
+		{
+			url:   "/string?t={unix}&ms={unixms}&d={date}",
+			field: "data.0.rate",
+			path:  "/string?t=1614830767&ms=1614830767000&d=2021-03-04",
+			rate:  0.015,
+		},
+		{url: "/string", field: "data.1.rate", fails: true},
+		{url: "/number", field: "market_data", fails: true},
+		{url: "/negative", field: "rate", fails: true},
+		{url: "/missing", field: "rate", fails: true},
+	}
+	for _, test := range tests {
+		src := &HTTPSource{URL: srv.URL + test.url, Field: test.field}
+		rate, err := src.Rate("USD", when)
+		if test.fails {
+			if err == nil {
+				t.Errorf("%s: expected error", test.url)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.url, err)
+			continue
+		}
+		if gotPath != test.path {
+			t.Errorf("requested %s, want %s", gotPath, test.path)
+		}
+		if rate != test.rate {
+			t.Errorf("%s: got rate %v, want %v", test.url, rate,
+				test.rate)
+		}
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"time"
 
 	"github.com/lbryio/lbcutil/hdkeychain"
+	"github.com/lbryio/lbcwallet/internal/zero"
 	"golang.org/x/crypto/ssh/terminal"
 )
 
@@ -38,6 +39,7 @@ func promptSeed(reader *bufio.Reader) ([]byte, error) {
 				"hexadecimal value that is at least %d bits and "+
 				"at most %d bits\n", hdkeychain.MinSeedBytes*8,
 				hdkeychain.MaxSeedBytes*8)
+			zero.Bytes(seed)
 			continue
 		}
 
@@ -150,11 +152,15 @@ func promptPassphrase(prefix string, confirm bool) ([]byte, error) {
 		fmt.Print("Confirm passphrase: ")
 		confirm, err := terminal.ReadPassword(int(os.Stdin.Fd()))
 		if err != nil {
+			zero.Bytes(pass)
 			return nil, err
 		}
 		fmt.Print("\n")
 		confirm = bytes.TrimSpace(confirm)
-		if !bytes.Equal(pass, confirm) {
+		match := bytes.Equal(pass, confirm)
+		zero.Bytes(confirm)
+		if !match {
+			zero.Bytes(pass)
 			fmt.Println("The entered passphrases do not match")
 			continue
 		}
@@ -176,6 +182,19 @@ func Passphrase(confirm bool) ([]byte, error) {
 		"for your new wallet", confirm)
 }
 
+// PublicPassphrase prompts the user for a new public passphrase of an existing
+// wallet.  All prompts are repeated until the user enters a valid response.
+func PublicPassphrase() ([]byte, error) {
+	return promptPassphrase("Enter the new public passphrase "+
+		"for your wallet", true)
+}
+
+// ExistingSeed prompts the user for the seed of an existing wallet.  The prompt
+// is repeated until the user enters a valid seed.
+func ExistingSeed(reader *bufio.Reader) ([]byte, error) {
+	return promptSeed(reader)
+}
+
 // Seed prompts the user whether they want to use an existing wallet generation
 // seed.  When the user answers no, a seed will be generated and displayed to
 // the user along with prompting them for confirmation.  When the user answers
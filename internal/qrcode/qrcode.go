@@ -0,0 +1,602 @@
+// Package qrcode encodes data as QR Code symbols (ISO/IEC 18004) in byte mode
+// and renders them as PNG images.
+package qrcode
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+)
+
+// Level is an error correction level.  Higher levels recover from more
+// damage at the cost of a larger symbol.
+type Level int
+
+// Error correction levels, from lowest to highest.
+const (
+	Low      Level = iota // Recovers 7% of the data
+	Medium                // Recovers 15% of the data
+	Quartile              // Recovers 25% of the data
+	High                  // Recovers 30% of the data
+)
+
+// formatBits are the bits identifying each level in the format information.
+var formatBits = [...]uint32{Low: 1, Medium: 0, Quartile: 3, High: 2}
+
+// eccCodewordsPerBlock and numECCBlocks are indexed by level, then version.
+var eccCodewordsPerBlock = [4][41]int{
+	{-1, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{-1, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+var numECCBlocks = [4][41]int{
+	{-1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{-1, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{-1, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+// ErrTooLong is returned when the data does not fit in the largest symbol.
+var ErrTooLong = errors.New("data too long for a QR code")
+
+// Code is an encoded QR Code symbol.
+type Code struct {
+	Version int
+	Level   Level
+	Mask    int
+
+	size       int
+	modules    [][]bool // Dark modules, indexed by row then column
+	isFunction [][]bool
+}
+
+// Encode encodes data in the smallest symbol with at least the requested
+// error correction level.  The level is raised when this does not make the
+// symbol larger.
+func Encode(data []byte, level Level) (*Code, error) {
+	var version int
+	for version = 1; ; version++ {
+		if version > 40 {
+			return nil, ErrTooLong
+		}
+		if dataBits(data, version) <= numDataCodewords(version, level)*8 {
+			break
+		}
+	}
+	for l := level + 1; l <= High; l++ {
+		if dataBits(data, version) <= numDataCodewords(version, l)*8 {
+			level = l
+		}
+	}
+
+	// Byte mode segment: mode indicator, character count, and data.
+	var bb bitBuffer
+	bb.append(0x4, 4)
+	bb.append(uint32(len(data)), charCountBits(version))
+	for _, b := range data {
+		bb.append(uint32(b), 8)
+	}
+
+	// Terminator, bit padding and pad codewords.
+	capacity := numDataCodewords(version, level) * 8
+	terminator := capacity - len(bb)
+	if terminator > 4 {
+		terminator = 4
+	}
+	bb.append(0, terminator)
+	bb.append(0, (8-len(bb)%8)%8)
+	for pad := uint32(0xec); len(bb) < capacity; pad ^= 0xec ^ 0x11 {
+		bb.append(pad, 8)
+	}
+
+	codewords := make([]byte, len(bb)/8)
+	for i, bit := range bb {
+		if bit {
+			codewords[i>>3] |= 1 << (7 - uint(i&7))
+		}
+	}
+
+	c := &Code{Version: version, Level: level}
+	c.size = version*4 + 17
+	c.modules = newGrid(c.size)
+	c.isFunction = newGrid(c.size)
+	c.drawFunctionPatterns()
+	c.drawCodewords(c.addECCAndInterleave(codewords))
+
+	// Use the mask with the lowest penalty.
+	minPenalty := -1
+	for mask := 0; mask < 8; mask++ {
+		c.applyMask(mask)
+		c.drawFormatBits(mask)
+		penalty := c.penalty()
+		if minPenalty < 0 || penalty < minPenalty {
+			c.Mask = mask
+			minPenalty = penalty
+		}
+		c.applyMask(mask) // Undo
+	}
+	c.applyMask(c.Mask)
+	c.drawFormatBits(c.Mask)
+	return c, nil
+}
+
+// Size returns the number of modules along each side of the symbol.
+func (c *Code) Size() int {
+	return c.size
+}
+
+// Dark returns whether the module at column x and row y is dark.
+func (c *Code) Dark(x, y int) bool {
+	return c.modules[y][x]
+}
+
+// Image renders the symbol with scale pixels per module, surrounded by the
+// required quiet zone of 4 modules.
+func (c *Code) Image(scale int) image.Image {
+	if scale < 1 {
+		scale = 1
+	}
+	const border = 4
+	n := (c.size + 2*border) * scale
+	img := image.NewPaletted(image.Rect(0, 0, n, n),
+		color.Palette{color.White, color.Black})
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			mx, my := x/scale-border, y/scale-border
+			if mx >= 0 && mx < c.size && my >= 0 && my < c.size &&
+				c.modules[my][mx] {
+
+				img.SetColorIndex(x, y, 1)
+			}
+		}
+	}
+	return img
+}
+
+// WritePNG writes the symbol as a PNG image with scale pixels per module.
+func (c *Code) WritePNG(w io.Writer, scale int) error {
+	return png.Encode(w, c.Image(scale))
+}
+
+// bitBuffer is a sequence of bits, most significant first.
+type bitBuffer []bool
+
+func (bb *bitBuffer) append(val uint32, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*bb = append(*bb, (val>>uint(i))&1 != 0)
+	}
+}
+
+func newGrid(size int) [][]bool {
+	grid := make([][]bool, size)
+	for i := range grid {
+		grid[i] = make([]bool, size)
+	}
+	return grid
+}
+
+// charCountBits returns the length of the byte mode character count.
+func charCountBits(version int) int {
+	if version < 10 {
+		return 8
+	}
+	return 16
+}
+
+// dataBits returns the number of bits needed to encode data in byte mode.
+func dataBits(data []byte, version int) int {
+	n := charCountBits(version)
+	if len(data) >= 1<<uint(n) {
+		return 1 << 30
+	}
+	return 4 + n + len(data)*8
+}
+
+// numRawDataModules returns the number of modules available for data and
+// error correction codewords, including remainder bits.
+func numRawDataModules(version int) int {
+	result := (16*version+128)*version + 64
+	if version >= 2 {
+		numAlign := version/7 + 2
+		result -= (25*numAlign-10)*numAlign - 55
+		if version >= 7 {
+			result -= 36
+		}
+	}
+	return result
+}
+
+// numDataCodewords returns the number of data codewords of a symbol.
+func numDataCodewords(version int, level Level) int {
+	return numRawDataModules(version)/8 -
+		eccCodewordsPerBlock[level][version]*numECCBlocks[level][version]
+}
+
+// alignmentPatternPositions returns the row and column centers of the
+// alignment patterns.
+func alignmentPatternPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+	numAlign := version/7 + 2
+	step := (version*8 + numAlign*3 + 5) / (numAlign*4 - 4) * 2
+	size := version*4 + 17
+	result := make([]int, numAlign)
+	result[0] = 6
+	for i, pos := numAlign-1, size-7; i >= 1; i, pos = i-1, pos-step {
+		result[i] = pos
+	}
+	return result
+}
+
+func (c *Code) setFunctionModule(x, y int, dark bool) {
+	c.modules[y][x] = dark
+	c.isFunction[y][x] = true
+}
+
+func (c *Code) drawFunctionPatterns() {
+	// Timing patterns.
+	for i := 0; i < c.size; i++ {
+		c.setFunctionModule(6, i, i%2 == 0)
+		c.setFunctionModule(i, 6, i%2 == 0)
+	}
+
+	// Finder patterns, including their separators.
+	c.drawFinderPattern(3, 3)
+	c.drawFinderPattern(c.size-4, 3)
+	c.drawFinderPattern(3, c.size-4)
+
+	// Alignment patterns, except where they overlap the finder patterns.
+	positions := alignmentPatternPositions(c.Version)
+	n := len(positions)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if (i == 0 && j == 0) || (i == 0 && j == n-1) ||
+				(i == n-1 && j == 0) {
+
+				continue
+			}
+			c.drawAlignmentPattern(positions[i], positions[j])
+		}
+	}
+
+	// Reserve the format information, which is drawn once the mask is
+	// known, and draw the version information.
+	c.drawFormatBits(0)
+	c.drawVersion()
+}
+
+func (c *Code) drawFinderPattern(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || xx >= c.size || yy < 0 || yy >= c.size {
+				continue
+			}
+			dist := max(abs(dx), abs(dy))
+			c.setFunctionModule(xx, yy, dist != 2 && dist != 4)
+		}
+	}
+}
+
+func (c *Code) drawAlignmentPattern(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			c.setFunctionModule(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// formatInformation returns the 15 format information bits of a level and
+// mask, protected by a BCH code.
+func formatInformation(level Level, mask int) uint32 {
+	data := formatBits[level]<<3 | uint32(mask)
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+func (c *Code) drawFormatBits(mask int) {
+	bits := formatInformation(c.Level, mask)
+	bit := func(i int) bool { return (bits>>uint(i))&1 != 0 }
+
+	// First copy, around the top left finder pattern.
+	for i := 0; i <= 5; i++ {
+		c.setFunctionModule(8, i, bit(i))
+	}
+	c.setFunctionModule(8, 7, bit(6))
+	c.setFunctionModule(8, 8, bit(7))
+	c.setFunctionModule(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		c.setFunctionModule(14-i, 8, bit(i))
+	}
+
+	// Second copy, split between the other two finder patterns.
+	for i := 0; i < 8; i++ {
+		c.setFunctionModule(c.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		c.setFunctionModule(8, c.size-15+i, bit(i))
+	}
+	c.setFunctionModule(8, c.size-8, true) // Always dark
+}
+
+func (c *Code) drawVersion() {
+	if c.Version < 7 {
+		return
+	}
+	rem := uint32(c.Version)
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1f25)
+	}
+	bits := uint32(c.Version)<<12 | rem
+	for i := 0; i < 18; i++ {
+		dark := (bits>>uint(i))&1 != 0
+		a, b := c.size-11+i%3, i/3
+		c.setFunctionModule(a, b, dark)
+		c.setFunctionModule(b, a, dark)
+	}
+}
+
+// addECCAndInterleave splits the data codewords into blocks, appends the
+// error correction codewords of each block, and interleaves the blocks.
+func (c *Code) addECCAndInterleave(data []byte) []byte {
+	numBlocks := numECCBlocks[c.Level][c.Version]
+	blockECCLen := eccCodewordsPerBlock[c.Level][c.Version]
+	rawCodewords := numRawDataModules(c.Version) / 8
+	numShortBlocks := numBlocks - rawCodewords%numBlocks
+	shortBlockLen := rawCodewords / numBlocks
+
+	divisor := rsDivisor(blockECCLen)
+	blocks := make([][]byte, 0, numBlocks)
+	k := 0
+	for i := 0; i < numBlocks; i++ {
+		n := shortBlockLen - blockECCLen
+		if i >= numShortBlocks {
+			n++
+		}
+		dat := data[k : k+n]
+		k += n
+		block := make([]byte, 0, shortBlockLen+1)
+		block = append(block, dat...)
+		if i < numShortBlocks {
+			block = append(block, 0) // Skipped when interleaving
+		}
+		block = append(block, rsRemainder(dat, divisor)...)
+		blocks = append(blocks, block)
+	}
+
+	result := make([]byte, 0, rawCodewords)
+	for i := range blocks[0] {
+		for j, block := range blocks {
+			if i != shortBlockLen-blockECCLen || j >= numShortBlocks {
+				result = append(result, block[i])
+			}
+		}
+	}
+	return result
+}
+
+// drawCodewords places the codewords in the zigzag pattern of two module
+// wide columns, skipping the function modules.
+func (c *Code) drawCodewords(data []byte) {
+	i := 0
+	for right := c.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 // Skip the vertical timing pattern
+		}
+		for vert := 0; vert < c.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = c.size - 1 - vert // Upward
+				}
+				if c.isFunction[y][x] || i >= len(data)*8 {
+					continue
+				}
+				c.modules[y][x] = (data[i>>3]>>(7-uint(i&7)))&1 != 0
+				i++
+			}
+		}
+	}
+}
+
+// applyMask inverts the data modules selected by a mask pattern.  Applying
+// the same mask twice undoes it.
+func (c *Code) applyMask(mask int) {
+	for y := 0; y < c.size; y++ {
+		for x := 0; x < c.size; x++ {
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert && !c.isFunction[y][x] {
+				c.modules[y][x] = !c.modules[y][x]
+			}
+		}
+	}
+}
+
+// Penalty weights of the mask evaluation rules.
+const (
+	penaltyN1 = 3
+	penaltyN2 = 3
+	penaltyN3 = 40
+	penaltyN4 = 10
+)
+
+// penalty scores the symbol by the mask evaluation rules.  Lower is better.
+func (c *Code) penalty() int {
+	result := 0
+	at := func(transpose bool, i, j int) bool {
+		if transpose {
+			return c.modules[j][i]
+		}
+		return c.modules[i][j]
+	}
+
+	// Runs of same colored modules and finder-like patterns in rows and
+	// columns.
+	for _, transpose := range []bool{false, true} {
+		for i := 0; i < c.size; i++ {
+			runColor := false
+			runLen := 0
+			var history [7]int
+			for j := 0; j < c.size; j++ {
+				if at(transpose, i, j) == runColor {
+					runLen++
+					if runLen == 5 {
+						result += penaltyN1
+					} else if runLen > 5 {
+						result++
+					}
+					continue
+				}
+				c.addRunHistory(runLen, &history)
+				if !runColor {
+					result += countFinderPatterns(&history) *
+						penaltyN3
+				}
+				runColor = at(transpose, i, j)
+				runLen = 1
+			}
+			if runColor {
+				c.addRunHistory(runLen, &history)
+				runLen = 0
+			}
+			runLen += c.size // Light border
+			c.addRunHistory(runLen, &history)
+			result += countFinderPatterns(&history) * penaltyN3
+		}
+	}
+
+	// 2x2 blocks of the same color.
+	for y := 0; y < c.size-1; y++ {
+		for x := 0; x < c.size-1; x++ {
+			color := c.modules[y][x]
+			if color == c.modules[y][x+1] &&
+				color == c.modules[y+1][x] &&
+				color == c.modules[y+1][x+1] {
+
+				result += penaltyN2
+			}
+		}
+	}
+
+	// Balance of dark and light modules.
+	dark := 0
+	for _, row := range c.modules {
+		for _, m := range row {
+			if m {
+				dark++
+			}
+		}
+	}
+	total := c.size * c.size
+	k := (abs(dark*20-total*10)+total-1)/total - 1
+	result += k * penaltyN4
+	return result
+}
+
+// addRunHistory pushes a run length onto the history of a row or column,
+// extending the first run with the light border.
+func (c *Code) addRunHistory(runLen int, history *[7]int) {
+	if history[0] == 0 {
+		runLen += c.size
+	}
+	copy(history[1:], history[:6])
+	history[0] = runLen
+}
+
+// countFinderPatterns returns the number of 1:1:3:1:1 patterns with light
+// space on either side that end the history.
+func countFinderPatterns(h *[7]int) int {
+	n := h[1]
+	core := n > 0 && h[2] == n && h[3] == n*3 && h[4] == n && h[5] == n
+	count := 0
+	if core && h[0] >= n*4 && h[6] >= n {
+		count++
+	}
+	if core && h[6] >= n*4 && h[0] >= n {
+		count++
+	}
+	return count
+}
+
+// rsDivisor returns the generator polynomial of a Reed-Solomon code with the
+// given degree, without its leading coefficient.
+func rsDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+	return result
+}
+
+// rsRemainder returns the Reed-Solomon error correction codewords of data.
+func rsRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, coef := range divisor {
+			result[i] ^= gfMultiply(coef, factor)
+		}
+	}
+	return result
+}
+
+// gfMultiply multiplies two elements of GF(2^8) modulo x^8+x^4+x^3+x^2+1.
+func gfMultiply(x, y byte) byte {
+	var z uint32
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11d)
+		z ^= uint32((y>>uint(i))&1) * uint32(x)
+	}
+	return byte(z)
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,257 @@
+package qrcode
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// TestReedSolomon checks the error correction codewords of the version 1-M
+// "HELLO WORLD" example.
+func TestReedSolomon(t *testing.T) {
+	t.Parallel()
+
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236,
+		17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+	got := rsRemainder(data, rsDivisor(len(want)))
+	if !bytes.Equal(got, want) {
+		t.Fatalf("got ECC %v, want %v", got, want)
+	}
+}
+
+// TestFormatInformation checks format information against values listed in
+// the specification.
+func TestFormatInformation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		level Level
+		mask  int
+		want  uint32
+	}{
+		{Medium, 0, 0x5412},
+		{Low, 4, 0x662f},
+		{Quartile, 7, 0x2bed},
+		{High, 2, 0x1ce7},
+	}
+	for _, test := range tests {
+		got := formatInformation(test.level, test.mask)
+		if got != test.want {
+			t.Errorf("level %d mask %d: got %015b, want %015b",
+				test.level, test.mask, got, test.want)
+		}
+	}
+}
+
+// TestCapacity checks the data capacity of symbols against the
+// specification.
+func TestCapacity(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		version int
+		level   Level
+		want    int
+	}{
+		{1, Low, 19},
+		{5, Quartile, 62},
+		{7, High, 66},
+		{10, Medium, 216},
+		{40, Low, 2956},
+		{40, Medium, 2334},
+		{40, Quartile, 1666},
+		{40, High, 1276},
+	}
+	for _, test := range tests {
+		got := numDataCodewords(test.version, test.level)
+		if got != test.want {
+			t.Errorf("version %d level %d: got %d codewords, want %d",
+				test.version, test.level, got, test.want)
+		}
+	}
+}
+
+// TestEncode encodes data of varying lengths and reads it back from the
+// symbol, checking the version, format information and error correction.
+func TestEncode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		data    string
+		level   Level
+		version int
+	}{
+		{"lbry:bKxQ", High, 2},
+		{"lbry:b", High, 1},
+		{"lbry:bHW58d37s1hBjj3wPBkn5zpCX3F8ZW3uWf?amount=1.5", Medium, 4},
+		{strings.Repeat("x", 300), Low, 11},
+		{strings.Repeat("y", 1000), Quartile, 31},
+	}
+	for _, test := range tests {
+		c, err := Encode([]byte(test.data), test.level)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.Version != test.version {
+			t.Errorf("%q: got version %d, want %d", test.data,
+				c.Version, test.version)
+		}
+		if c.Level < test.level {
+			t.Errorf("%q: level lowered to %d", test.data, c.Level)
+		}
+		if got := readData(t, c); got != test.data {
+			t.Errorf("read %q, want %q", got, test.data)
+		}
+	}
+
+	if _, err := Encode(make([]byte, 2954), Low); err != ErrTooLong {
+		t.Fatalf("got error %v, want ErrTooLong", err)
+	}
+}
+
+// TestWritePNG checks the dimensions of rendered symbols.
+func TestWritePNG(t *testing.T) {
+	t.Parallel()
+
+	c, err := Encode([]byte("lbry:bKxQ"), Medium)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := c.WritePNG(&buf, 3); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (21 + 8) * 3; img.Bounds().Dx() != want ||
+		img.Bounds().Dy() != want {
+
+		t.Fatalf("got bounds %v, want %dx%d", img.Bounds(), want, want)
+	}
+}
+
+// readData reads the byte mode data of a symbol, verifying its format
+// information and error correction codewords.
+func readData(t *testing.T, c *Code) string {
+	t.Helper()
+
+	// Both copies of the format information must match the mask.
+	var first, second uint32
+	bit := func(x, y int) uint32 {
+		if c.Dark(x, y) {
+			return 1
+		}
+		return 0
+	}
+	for i := 0; i <= 5; i++ {
+		first |= bit(8, i) << uint(i)
+	}
+	first |= bit(8, 7)<<6 | bit(8, 8)<<7 | bit(7, 8)<<8
+	for i := 9; i < 15; i++ {
+		first |= bit(14-i, 8) << uint(i)
+	}
+	for i := 0; i < 8; i++ {
+		second |= bit(c.size-1-i, 8) << uint(i)
+	}
+	for i := 8; i < 15; i++ {
+		second |= bit(8, c.size-15+i) << uint(i)
+	}
+	want := formatInformation(c.Level, c.Mask)
+	if first != want || second != want {
+		t.Fatalf("format information %015b and %015b, want %015b",
+			first, second, want)
+	}
+
+	// Unmask a copy of the symbol and read the codewords in placement
+	// order.
+	u := *c
+	u.modules = newGrid(c.size)
+	for y := range c.modules {
+		copy(u.modules[y], c.modules[y])
+	}
+	u.applyMask(c.Mask)
+	rawCodewords := numRawDataModules(c.Version) / 8
+	codewords := make([]byte, rawCodewords)
+	i := 0
+	for right := c.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < c.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x, y := right-j, vert
+				if (right+1)&2 == 0 {
+					y = c.size - 1 - vert
+				}
+				if u.isFunction[y][x] || i >= rawCodewords*8 {
+					continue
+				}
+				if u.modules[y][x] {
+					codewords[i>>3] |= 1 << (7 - uint(i&7))
+				}
+				i++
+			}
+		}
+	}
+
+	// Deinterleave the blocks and check their error correction.
+	numBlocks := numECCBlocks[c.Level][c.Version]
+	eccLen := eccCodewordsPerBlock[c.Level][c.Version]
+	numShortBlocks := numBlocks - rawCodewords%numBlocks
+	shortLen := rawCodewords/numBlocks - eccLen
+	blocks := make([][]byte, numBlocks)
+	k := 0
+	for pos := 0; pos <= shortLen; pos++ {
+		for b := range blocks {
+			if pos == shortLen && b < numShortBlocks {
+				continue
+			}
+			blocks[b] = append(blocks[b], codewords[k])
+			k++
+		}
+	}
+	var data []byte
+	divisor := rsDivisor(eccLen)
+	for b := range blocks {
+		ecc := make([]byte, eccLen)
+		for e := range ecc {
+			ecc[e] = codewords[k+e*numBlocks+b]
+		}
+		if got := rsRemainder(blocks[b], divisor); !bytes.Equal(got, ecc) {
+			t.Fatalf("block %d: ECC does not match", b)
+		}
+		data = append(data, blocks[b]...)
+	}
+
+	// Parse the byte mode segment.
+	if data[0]>>4 != 0x4 {
+		t.Fatalf("mode indicator %x, want 4", data[0]>>4)
+	}
+	var bits []bool
+	for _, b := range data {
+		for i := 7; i >= 0; i-- {
+			bits = append(bits, (b>>uint(i))&1 != 0)
+		}
+	}
+	read := func(pos, n int) int {
+		v := 0
+		for _, b := range bits[pos : pos+n] {
+			v <<= 1
+			if b {
+				v |= 1
+			}
+		}
+		return v
+	}
+	n := charCountBits(c.Version)
+	length := read(4, n)
+	out := make([]byte, length)
+	for i := range out {
+		out[i] = byte(read(4+n+i*8, 8))
+	}
+	return string(out)
+}
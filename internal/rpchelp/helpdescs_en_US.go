@@ -24,15 +24,211 @@ var helpDescsEnUS = map[string]string{
 	"createmultisigresult-address":      "The generated pay-to-script-hash address.",
 	"createmultisigresult-redeemScript": "The script required to redeem outputs paid to the multisig address.",
 
+	// ApproveWithdrawalCmd help.
+	"approvewithdrawal--synopsis": "Approves a pending withdrawal as the approver making the request, and returns it.\n" +
+		"The withdrawal is signed and sent once approved by as many distinct approvers as it requires, and is then returned sent.\n" +
+		"When the send fails, as while the wallet is locked, the withdrawal stays approved and the next approvewithdrawal retries the send.\n" +
+		"Only the users of the rpcapprover option may approve withdrawals, and the requester may not approve their own.",
+	"approvewithdrawal-id": "The ID of the withdrawal.",
+
+	// BenchSigningCmd help.
+	"benchsigning--synopsis": "Measures the throughput of the key derivations, ECDSA signatures and signature verifications on the host, with keys of a random seed, and the max rate of the transactions the wallet can create from it, for capacity planning.\n" +
+		"Each input of a transaction needs the derivation of its key, a signature and its verification, and each transaction the derivation of a change address.\n" +
+		"The rates are those of a single core, as the wallet creates transactions one at a time, and exclude the database updates and the broadcast of the transactions.",
+	"benchsigning-duration": "The number of seconds the measurement runs for, between 1 and 60.",
+	"benchsigning-inputs":   "The number of inputs of the transactions whose rate is reported.",
+
+	// BenchSigningResult help.
+	"benchsigningresult-derivationspersec":         "The number of address keys derived per second.",
+	"benchsigningresult-hardenedderivationspersec": "The number of hardened keys, such as account keys, derived per second.",
+	"benchsigningresult-signaturespersec":          "The number of p2wkh inputs signed per second.",
+	"benchsigningresult-verificationspersec":       "The number of p2wkh input scripts verified per second.",
+	"benchsigningresult-inputs":                    "The number of inputs of the transactions of txpersec.",
+	"benchsigningresult-txpersec":                  "The max number of transactions spending inputs inputs created per second.",
+
+	// CancelSendCmd help.
+	"cancelsend--synopsis": "Discards a transaction previewed by a send command while the wallet previews sends, and unlocks the outputs it spends.",
+	"cancelsend-handle":    "The handle of the preview.",
+
+	// CancelWithdrawalCmd help.
+	"cancelwithdrawal--synopsis": "Cancels a withdrawal which was not sent yet, and returns it.",
+	"cancelwithdrawal-id":        "The ID of the withdrawal.",
+
+	// ChangePublicPassphraseCmd help.
+	"changepublicpassphrase--synopsis": "Re-encrypts the public data of the wallet, such as addresses and extended public keys, under a new public passphrase.\n" +
+		"Private keys are not affected.  The wallet must afterwards be opened with the new passphrase using the walletpass option.",
+	"changepublicpassphrase-oldpassphrase": "The current public passphrase (\"public\" unless previously changed).",
+	"changepublicpassphrase-newpassphrase": "The new public passphrase.",
+
+	// CloneWalletCmd help.
+	"clonewallet--synopsis": "Writes a watch-only copy of the wallet database to a new file, for staging or analytics environments which must not hold the keys of the wallet.\n" +
+		"The copy keeps the accounts, addresses, transactions and metadata of the wallet and its public passphrase, but its private keys, encrypted scripts and private passphrase are deleted, so it can never be unlocked.",
+	"clonewallet-destination": "The path of the new wallet database, which must not exist.",
+
+	// CollectDebugInfoCmd help.
+	"collectdebuginfo--synopsis": "Returns a bundle of debug information to attach to bug reports: the version, the config with its credentials redacted, the recent logs, the stack traces of all goroutines and the stats of the wallet databases.\n" +
+		"The same bundle is written by the --collectdebuginfo option when lbcwallet does not start.",
+	"collectdebuginfo--result0": "The base64 encoded gzipped tar bundle.",
+
+	// ConfirmSendCmd help.
+	"confirmsend--synopsis": "Signs and sends a transaction previewed by a send command while the wallet previews sends.\n" +
+		"Previews not confirmed within 10 minutes are discarded, and the send is kept pending when the transaction cannot be signed, as while the wallet is locked.",
+	"confirmsend-handle":   "The handle of the preview.",
+	"confirmsend--result0": "The transaction hash of the sent transaction.",
+
 	// CreateNewAccountCmd help.
 	"createnewaccount--synopsis": "Creates a new account.",
 	"createnewaccount-account":   "Account name.",
 
+	// CreateVaultCmd help.
+	"createvault--synopsis": "Creates a vault, an account whose outputs are only spent by its keys once they have a number of confirmations, and by a recovery key at any time.\n" +
+		"Outputs paid to the addresses of getvaultaddress are never chosen by coin selection, and are spent with sendfromvault and recovervault.",
+	"createvault-name":           "The name of the vault and of its account.",
+	"createvault-recoverypubkey": "The hex-encoded public key which spends the outputs of the vault at any time, whose private key should be kept offline.",
+	"createvault-delay":          "The number of confirmations the outputs of the vault must have before its keys spend them.",
+
+	// CreateInvoiceCmd help.
+	"createinvoice--synopsis": "Creates an invoice requesting a payment to a new address of an account.\n" +
+		"The invoice is paid once the transactions paying its address before it expires total at least its amount.",
+	"createinvoice-amount":  "The amount to request valued in LBC, or 0 to accept any amount.",
+	"createinvoice-memo":    "A memo describing the invoice, included as the message of its payment URI.",
+	"createinvoice-expiry":  "The number of seconds after which the invoice expires if it is not paid.",
+	"createinvoice-account": "The account to receive the payment to.",
+
+	// InvoiceResult help.
+	"invoiceresult-id":       "The ID of the invoice.",
+	"invoiceresult-address":  "The address to pay.",
+	"invoiceresult-uri":      "The BIP0021 payment URI of the invoice.",
+	"invoiceresult-amount":   "The requested amount valued in LBC.",
+	"invoiceresult-memo":     "The memo of the invoice.",
+	"invoiceresult-created":  "The time the invoice was created in seconds since 1 Jan 1970 GMT.",
+	"invoiceresult-expires":  "The time the invoice expires in seconds since 1 Jan 1970 GMT.",
+	"invoiceresult-state":    "The state of the invoice: unpaid, paid, or expired.",
+	"invoiceresult-received": "The total value of the payments to the invoice valued in LBC.",
+	"invoiceresult-paidtime": "The time the invoice was paid in seconds since 1 Jan 1970 GMT.",
+	"invoiceresult-payments": "The transactions paying the invoice, including payments after it was paid or expired.",
+
+	// InvoicePaymentResult help.
+	"invoicepaymentresult-txid":   "The hash of the transaction.",
+	"invoicepaymentresult-amount": "The value paid to the invoice by the transaction valued in LBC.",
+
+	// CreatePaymentURICmd help.
+	"createpaymenturi--synopsis": "Returns a BIP0021 payment URI requesting a payment to an address, optionally with a QR code of the URI.",
+	"createpaymenturi-address":   "The address to pay.",
+	"createpaymenturi-amount":    "The amount to request valued in LBC.",
+	"createpaymenturi-label":     "A label for the payee, such as the name of a merchant.",
+	"createpaymenturi-message":   "A message describing the payment, such as an order number.",
+	"createpaymenturi-qrcode":    "Also return a QR code of the URI as a PNG image.",
+
+	// CreatePaymentURIResult help.
+	"createpaymenturiresult-uri":    "The payment URI.",
+	"createpaymenturiresult-qrcode": "The base64 encoded PNG image of a QR code of the URI, if requested.",
+
+	// DecodePaymentURICmd help.
+	"decodepaymenturi--synopsis": "Returns the address and parameters of a BIP0021 payment URI.\n" +
+		"URIs with parameters prefixed by 'req-' which are not understood are rejected.",
+	"decodepaymenturi-uri": "The payment URI.",
+
+	// DecodePaymentURIResult help.
+	"decodepaymenturiresult-address":       "The address to pay.",
+	"decodepaymenturiresult-amount":        "The requested amount valued in LBC, if any.",
+	"decodepaymenturiresult-label":         "The label of the payee, if any.",
+	"decodepaymenturiresult-message":       "The message describing the payment, if any.",
+	"decodepaymenturiresult-params":        "Other parameters of the URI, keyed by name.",
+	"decodepaymenturiresult-params--key":   "name",
+	"decodepaymenturiresult-params--value": "value",
+	"decodepaymenturiresult-params--desc":  "The value of the parameter",
+
+	// DiagnoseTransactionCmd help.
+	"diagnosetransaction--synopsis": "Explains why an unconfirmed wallet transaction is not confirming and suggests what to do about it.\n" +
+		"The transaction is checked for a fee rate below the estimate of the chain server to confirm within 6 blocks, a missing parent, a conflict with another wallet transaction and a missing broadcast.\n" +
+		"The suggested actions are 'bumpfee' to replace the transaction with one paying a higher fee, 'cpfp' to spend one of its outputs paid to the wallet with a fee high enough for both to be mined, " +
+		"'abandon' to remove it from the wallet so that the outputs it spends can be spent again, 'rebroadcast' to send it or its unconfirmed parent to the network again, and 'wait'.",
+	"diagnosetransaction-txid": "The hash of the transaction.",
+
+	// DiagnoseTransactionResult help.
+	"diagnosetransactionresult-txid":             "The hash of the transaction.",
+	"diagnosetransactionresult-confirmations":    "The number of confirmations of the transaction, which is not diagnosed further when mined.",
+	"diagnosetransactionresult-timereceived":     "The time the wallet received the transaction, in seconds since 1 Jan 1970 GMT.",
+	"diagnosetransactionresult-vsize":            "The virtual size of the transaction.",
+	"diagnosetransactionresult-fee":              "The fee paid by the transaction valued in LBC, omitted when unknown.",
+	"diagnosetransactionresult-feerate":          "The fee rate of the transaction in LBC/kvB, omitted when unknown.",
+	"diagnosetransactionresult-estimatedfeerate": "The fee rate in LBC/kvB estimated by the chain server to confirm within 6 blocks, omitted when it has no estimate.",
+	"diagnosetransactionresult-inmempool":        "Whether the transaction is in the mempool of the chain server.",
+	"diagnosetransactionresult-broadcasttime":    "The time the transaction is broadcast when delayed by the broadcast policy, in seconds since 1 Jan 1970 GMT.",
+	"diagnosetransactionresult-unminedparents":   "The hashes of the unconfirmed wallet transactions whose outputs the transaction spends.",
+	"diagnosetransactionresult-missinginputs":    "The outputs spent by the transaction that are in neither the chain nor the mempool, as txid:vout.",
+	"diagnosetransactionresult-conflicts":        "The hashes of the other unconfirmed wallet transactions spending the same outputs.",
+	"diagnosetransactionresult-reasons":          "The reasons the transaction is not confirming.",
+	"diagnosetransactionresult-actions":          "The suggested actions: bumpfee, cpfp, abandon, rebroadcast or wait.",
+
+	// DumpGoroutinesCmd help.
+	"dumpgoroutines--synopsis": "Returns the stack traces of all goroutines of the running process, for diagnosing hangs and leaks.",
+	"dumpgoroutines--result0":  "The goroutine stack traces in the format of a Go panic.",
+
+	// DumpHeapProfileCmd help.
+	"dumpheapprofile--synopsis": "Returns a profile of the live heap allocations of the running process.",
+	"dumpheapprofile-gc":        "Run a garbage collection before taking the profile so it only includes live objects.",
+	"dumpheapprofile--result0":  "The base64 encoded profile, which can be decoded and read with go tool pprof.",
+
 	// DumpPrivKeyCmd help.
 	"dumpprivkey--synopsis": "Returns the private key in WIF encoding that controls some wallet address.",
 	"dumpprivkey-address":   "The address to return a private key for.",
 	"dumpprivkey--result0":  "The WIF-encoded private key.",
 
+	// DumpWalletCmd help.
+	"dumpwallet--synopsis": "Writes a portable JSON export of the wallet to a new file, to restore it with importwallet or lbcwallet --create --createfromdump on another machine or lbcwallet version.\n" +
+		"The export is versioned (format 'lbcwallet-dump', version 1) and holds the network, creation time, birthday and birthday block, the extended private master key, " +
+		"the HD accounts of every key scope with their xpub and number of derived addresses, the imported keys with their imported-key account, the transaction labels, and the channel claims for reference.\n" +
+		"The export holds the private keys in the clear. The wallet must be unlocked.",
+	"dumpwallet-filename": "The path of the file to write, which must not exist.",
+
+	// DumpWalletResult help.
+	"dumpwalletresult-filename": "The path of the file written.",
+
+	// DumpImportedAccountCmd help.
+	"dumpimportedaccount--synopsis": "Returns the addresses and WIF-encoded private keys of an imported-key account.",
+	"dumpimportedaccount-account":   "The name of the imported-key account.",
+
+	// ImportedKeyResult help.
+	"importedkeyresult-address": "The address of the imported key.",
+	"importedkeyresult-privkey": "The WIF-encoded private key.",
+
+	// ExportSeedSharesCmd help.
+	"exportseedshares--synopsis": "Splits the master key of the wallet, which recovers it as its seed does, into Shamir shares, any threshold of which recover the wallet with lbcwallet --create --createfromshares, while fewer reveal nothing about it.\n" +
+		"Each share should be kept in a different safe place, as any threshold of them holds the private keys of the wallet. The wallet must be unlocked.",
+	"exportseedshares-threshold": "The number of shares recovering the wallet.",
+	"exportseedshares-count":     "The number of shares, at most 16.",
+	"exportseedshares--result0":  "The shares, each starting with 'lbcshare1'.",
+
+	// ExportTransactionsCmd help.
+	"exporttransactions--synopsis": "Exports the wallet transactions received in a range of dates for bookkeeping.\n" +
+		"Every credit and debit is exported with its date, txid, category, amount, fee, label and claim operation, and in CSV the exchange rate recorded when priceurl is set.\n" +
+		"The fee of a transaction is only included with its first send entry, and written as a separate transaction in OFX and QIF.",
+	"exporttransactions-format":    "The format of the export: 'csv', 'ofx' or 'qif'",
+	"exporttransactions-startdate": "The first UTC date (YYYY-MM-DD) of the exported transactions, or all earlier transactions if unset",
+	"exporttransactions-enddate":   "The last UTC date (YYYY-MM-DD) of the exported transactions, or all later transactions if unset",
+	"exporttransactions-account":   "The account of the exported transactions, or '*' for all accounts",
+	"exporttransactions-filename":  "Write the export to this new file on the wallet host instead of returning it; existing files are not overwritten",
+
+	// ExportTransactionsResult help.
+	"exporttransactionsresult-format":   "The format of the export",
+	"exporttransactionsresult-entries":  "The number of exported entries",
+	"exporttransactionsresult-filename": "The file the export was written to, if a filename was given",
+	"exporttransactionsresult-data":     "The exported document, if no filename was given",
+
+	// ExportTxLogCmd help.
+	"exporttxlog--synopsis": "Exports the transactions of the wallet as a log which a fresh wallet created from the same seed replays with --replaytxlog,\n" +
+		"to check that its balance and history are reconstructed the same, such as after a database migration.",
+	"exporttxlog-filename": "Write the log to this new file on the wallet host instead of returning it; existing files are not overwritten",
+
+	// ExportTxLogResult help.
+	"exporttxlogresult-transactions": "The number of transactions in the log",
+	"exporttxlogresult-balance":      "The balance of the wallet with one confirmation, in LBC",
+	"exporttxlogresult-digest":       "The digest of the history of the wallet, which its replay must reconstruct",
+	"exporttxlogresult-filename":     "The file the log was written to, if a filename was given",
+	"exporttxlogresult-data":         "The log, one JSON object per line, if no filename was given",
+
 	// ExportWatchingWalletCmd help.
 	"exportwatchingwallet--synopsis": "Creates and returns a duplicate of the wallet database without any private keys to be used as a watching-only wallet.",
 	"exportwatchingwallet-account":   "Unused (must be unset or \"*\").",
@@ -82,6 +278,263 @@ var helpDescsEnUS = map[string]string{
 	// GetInfoCmd help.
 	"getinfo--synopsis": "Returns a JSON object containing various state info.",
 
+	// GetWalletInfoCmd help.
+	"getwalletinfo--synopsis": "Returns the state of the wallet, its balances and key counts, and the state of its chain server.",
+
+	// GetWalletInfoResult help.
+	"getwalletinforesult-walletversion":           "The version of the address manager database.",
+	"getwalletinforesult-balance":                 "The spendable balance of all accounts with one confirmation valued in LBC.",
+	"getwalletinforesult-unconfirmed_balance":     "The value of the unconfirmed outputs of all accounts valued in LBC.",
+	"getwalletinforesult-immature_balance":        "The value of the immature coinbase outputs of all accounts valued in LBC.",
+	"getwalletinforesult-staked":                  "The value of the unspent claims, supports and claim updates of all accounts valued in LBC.",
+	"getwalletinforesult-txcount":                 "The number of transactions of the wallet, mined and unmined.",
+	"getwalletinforesult-keypoolsize":             "The number of external (receiving) keys derived for all accounts.",
+	"getwalletinforesult-keypoolsize_hd_internal": "The number of internal (change) keys derived for all accounts.",
+	"getwalletinforesult-keypools":                "The key counts of each key scope.",
+	"getwalletinforesult-locked":                  "Whether the wallet is locked.",
+	"getwalletinforesult-unlocked_until":          "The time the unlocked wallet is relocked, in seconds since 1 Jan 1970 GMT, or 0 when locked or unlocked without a timeout.",
+	"getwalletinforesult-private_keys_enabled":    "Whether the wallet has private keys, which a watching-only wallet has not.",
+	"getwalletinforesult-birthday":                "The birthday of the wallet, in seconds since 1 Jan 1970 GMT.",
+	"getwalletinforesult-syncheight":              "The height of the block the wallet is synced to.",
+	"getwalletinforesult-syncblockhash":           "The hash of the block the wallet is synced to.",
+	"getwalletinforesult-synced":                  "Whether the wallet is synced to its chain server.",
+	"getwalletinforesult-backend":                 "The chain server backend, or empty if there is none.",
+	"getwalletinforesult-backendconnected":        "Whether the chain server is connected.",
+
+	// KeyPoolResult help.
+	"keypoolresult-addresstype":    "The address type of the key scope.",
+	"keypoolresult-external":       "The number of external (receiving) keys derived for the accounts of the key scope.",
+	"keypoolresult-internal":       "The number of internal (change) keys derived for the accounts of the key scope.",
+	"keypoolresult-imported":       "The number of keys imported into the accounts of the key scope.",
+	"keypoolresult-pooledexternal": "The number of the next unused external keys of the accounts of the key scope derived in advance.",
+	"keypoolresult-pooledinternal": "The number of the next unused internal keys of the accounts of the key scope derived in advance.",
+
+	// FastForwardCmd help.
+	"fastforward--synopsis": "Advances the wallet clock, so that invoices and output leases expire and the spending limits start a new day without waiting.\n" +
+		"The clock of lbcd is not changed, so neither are the times of the mined blocks.\n" +
+		"Only available on regtest with --regtest-harness.",
+	"fastforward-seconds": "The number of seconds to advance the wallet clock by.",
+
+	// FastForwardResult help.
+	"fastforwardresult-time":   "The time of the wallet clock, in seconds since 1 Jan 1970 GMT.",
+	"fastforwardresult-offset": "The number of seconds the wallet clock is ahead of the system clock.",
+
+	// FundAddressCmd help.
+	"fundaddress--synopsis": "Sends an amount from the default account to an address, and mines blocks to the wallet to confirm the transaction.\n" +
+		"Only available on regtest with --regtest-harness.",
+	"fundaddress-address": "The address to send to.",
+	"fundaddress-amount":  "The amount to send.",
+	"fundaddress-blocks":  "The number of blocks to mine after sending, which may be zero to leave the transaction unconfirmed.",
+
+	// FundAddressResult help.
+	"fundaddressresult-txid":   "The hash of the transaction.",
+	"fundaddressresult-blocks": "The hashes of the mined blocks.",
+
+	// GenerateToWalletCmd help.
+	"generatetowallet--synopsis": "Mines blocks whose coinbases pay to a new address of an account, and returns once the wallet has processed them.\n" +
+		"Coinbases are spendable after 100 confirmations.\n" +
+		"Only available on regtest with --regtest-harness.",
+	"generatetowallet-numblocks": "The number of blocks to mine.",
+	"generatetowallet-account":   "The account the coinbases pay to.",
+	"generatetowallet--result0":  "The hashes of the mined blocks.",
+
+	// GetAccountInfoCmd help.
+	"getaccountinfo--synopsis": "Returns the balances and key counts of an account.",
+	"getaccountinfo-account":   "The name of the account.",
+	"getaccountinfo-minconf":   "Minimum number of block confirmations required before an output is spendable.",
+
+	// AccountInfoResult help.
+	"accountinforesult-account":          "The name of the account.",
+	"accountinforesult-number":           "The number of the account.",
+	"accountinforesult-addresstypes":     "The address types (legacy, p2sh-segwit or bech32) the account is defined for.",
+	"accountinforesult-balance":          "The total value of the unspent outputs of the account valued in LBC.",
+	"accountinforesult-spendable":        "The value of the outputs with at least minconf confirmations valued in LBC.",
+	"accountinforesult-unconfirmed":      "The value of the outputs with fewer than minconf confirmations valued in LBC.",
+	"accountinforesult-immature":         "The value of the immature coinbase outputs valued in LBC.",
+	"accountinforesult-staked":           "The value of the unspent claims, supports and claim updates of the account valued in LBC, which is not part of its balance.",
+	"accountinforesult-moved":            "The net value moved to the account with movebalance valued in LBC.",
+	"accountinforesult-ledgerbalance":    "The balance plus the moved value valued in LBC.",
+	"accountinforesult-externalkeycount": "The number of external (receiving) addresses derived for the account.",
+	"accountinforesult-internalkeycount": "The number of internal (change) addresses derived for the account.",
+	"accountinforesult-importedkeycount": "The number of keys imported into the account.",
+
+	// GetBackupStatusCmd help.
+	"getbackupstatus--synopsis": "Returns the status of the encrypted backups of the wallet database, which are made every backupinterval.",
+
+	// GetBackupStatusResult help.
+	"getbackupstatusresult-enabled":        "Whether the wallet is backed up periodically.",
+	"getbackupstatusresult-store":          "The directory or S3 URL the backups are written to.",
+	"getbackupstatusresult-interval":       "The number of seconds between backups.",
+	"getbackupstatusresult-lastattempt":    "The time of the last backup attempt, in seconds since 1 Jan 1970 GMT.",
+	"getbackupstatusresult-lasterror":      "The error of the last backup attempt, if it failed.",
+	"getbackupstatusresult-lastbackup":     "The time of the last successful backup, in seconds since 1 Jan 1970 GMT.",
+	"getbackupstatusresult-lastbackupname": "The file or object name of the last successful backup.",
+	"getbackupstatusresult-lastbackupsize": "The size in bytes of the last successful backup.",
+	"getbackupstatusresult-nextbackup":     "The time of the next backup, in seconds since 1 Jan 1970 GMT.",
+
+	// GetConnectionStatusCmd help.
+	"getconnectionstatus--synopsis": "Returns the status of the connection to the chain server.",
+
+	// GetConnectionStatusResult help.
+	"getconnectionstatusresult-backend":   "The chain server backend, or empty if there is none.",
+	"getconnectionstatusresult-connected": "Whether the wallet is connected to the chain server.",
+	"getconnectionstatusresult-address":   "The address of the last connection made to lbcd, or the address given to the proxy.",
+	"getconnectionstatusresult-family":    "The address family of the last connection made to lbcd (ipv4 or ipv6), i2p over I2P, or proxy through a proxy.",
+
+	// GetDiskStatusCmd help.
+	"getdiskstatus--synopsis": "Checks the free space of the volume holding the wallet database, which is monitored every diskcheckinterval.",
+
+	// GetDiskStatusResult help.
+	"getdiskstatusresult-enabled":   "Whether the free disk space is monitored.",
+	"getdiskstatusresult-path":      "The path of the wallet database.",
+	"getdiskstatusresult-lastcheck": "The time of the last check, in seconds since 1 Jan 1970 GMT.",
+	"getdiskstatusresult-lasterror": "The error of the last check, if it failed.",
+	"getdiskstatusresult-dbsize":    "The size in bytes of the wallet database.",
+	"getdiskstatusresult-growth":    "The number of bytes the wallet database grew since the previous check.",
+	"getdiskstatusresult-free":      "The free space in bytes of the volume holding the wallet database.",
+	"getdiskstatusresult-warning":   "Whether the free space is below diskwarn.",
+	"getdiskstatusresult-low":       "Whether the free space is below diskfloor, in which case addresses are not derived, transactions are not created and keys are not imported.",
+
+	// GetNetworkInfoCmd help.
+	"getnetworkinfo--synopsis": "Returns the parameters of the network of the wallet.\n" +
+		"Unlike the getnetworkinfo method of lbcd, which describes its peers, the network of the wallet is described.",
+
+	// GetNetworkInfoResult help.
+	"getnetworkinforesult-name":             "The name of the network, as given to the network option.",
+	"getnetworkinforesult-net":              "The magic bytes of the messages of the network, in hex.",
+	"getnetworkinforesult-testnet":          "Whether the network is a test network.",
+	"getnetworkinforesult-genesishash":      "The hash of the genesis block of the network.",
+	"getnetworkinforesult-defaultport":      "The default peer-to-peer port of the nodes of the network.",
+	"getnetworkinforesult-bech32hrp":        "The human-readable part of the segwit addresses of the network.",
+	"getnetworkinforesult-pubkeyhashaddrid": "The version byte of the pay-to-pubkey-hash addresses of the network.",
+	"getnetworkinforesult-scripthashaddrid": "The version byte of the pay-to-script-hash addresses of the network.",
+	"getnetworkinforesult-privatekeyid":     "The version byte of the WIF private keys of the network.",
+	"getnetworkinforesult-hdcointype":       "The BIP0044 coin type of the keys of the network.",
+	"getnetworkinforesult-coinbasematurity": "The number of blocks before a coinbase output can be spent.",
+
+	// GetDecoyAddressesCmd help.
+	"getdecoyaddresses--synopsis": "Derives and returns a batch of decoy addresses for an account, for use as decoys or test sinks.\n" +
+		"Decoy addresses are derived on a separate branch of the account, which the wallet never watches, so that payments to them are not tracked and not part of the balance.\n" +
+		"They must never be given out as deposit addresses, and createpaymenturi refuses them.",
+	"getdecoyaddresses-account":     "Account name the decoy addresses are derived from.",
+	"getdecoyaddresses-count":       "The number of addresses to derive, at most 1000.",
+	"getdecoyaddresses-addresstype": "Address type. Must be one of 'legacy', 'p2sh-segwit', or 'bech32'. Default to 'legacy'.",
+
+	// DecoyAddressResult help.
+	"decoyaddressresult-address": "The decoy address, whose payments are not tracked by the wallet.",
+	"decoyaddressresult-index":   "The derivation index of the address on the decoy branch of the account.",
+	"decoyaddressresult-path":    "The derivation path of the address from the master key.",
+	"decoyaddressresult-decoy":   "Always true, marking the address as a decoy which must not be used to receive payments.",
+
+	// GetInvoiceCmd help.
+	"getinvoice--synopsis": "Returns an invoice created with createinvoice.",
+	"getinvoice-id":        "The ID of the invoice.",
+
+	// GetNewAddressesCmd help.
+	"getnewaddresses--synopsis": "Generates and returns a batch of new payment addresses for an account, in the order of their derivation indexes.\n" +
+		"The addresses are derived at once, so their indexes are sequential.\n" +
+		"Addresses beyond the recovery window of unused addresses are only found again by a recovery with a larger window.",
+	"getnewaddresses-account":     "Account name the new addresses will belong to.",
+	"getnewaddresses-count":       "The number of addresses to generate, at most 1000.",
+	"getnewaddresses-addresstype": "Address type. Must be one of 'legacy', 'p2sh-segwit', or 'bech32'. Default to 'legacy'.",
+
+	// NewAddressResult help.
+	"newaddressresult-address": "The payment address.",
+	"newaddressresult-index":   "The derivation index of the address on the external branch of the account.",
+
+	// GetPrivacyReportCmd help.
+	"getprivacyreport--synopsis": "Analyzes the wallet's own transactions for patterns which link its addresses and accounts together or reveal its change.\n" +
+		"Claim, support and claim update outputs are not counted as address reuse, as a claim update keeps the address of the claim.",
+
+	// GetPrivacyReportResult help.
+	"getprivacyreportresult-transactions":    "The number of wallet transactions analyzed.",
+	"getprivacyreportresult-reusedaddresses": "The addresses of the wallet paid by more than one transaction, ordered by address.",
+	"getprivacyreportresult-mergedinputs":    "The transactions spending the outputs of more than one account, which links the accounts together.",
+	"getprivacyreportresult-roundchange":     "The transactions sent by the wallet paying round amounts (multiples of 0.001 LBC) with change that is not round, so that the change is easily identified.",
+
+	// ReusedAddressResult help.
+	"reusedaddressresult-address": "The reused address.",
+	"reusedaddressresult-account": "The account of the address.",
+	"reusedaddressresult-txids":   "The hashes of the transactions paying the address.",
+
+	// MergedInputsResult help.
+	"mergedinputsresult-txid":     "The hash of the transaction.",
+	"mergedinputsresult-accounts": "The accounts whose outputs the transaction spends.",
+
+	// RoundChangeResult help.
+	"roundchangeresult-txid":   "The hash of the transaction.",
+	"roundchangeresult-change": "The indexes of the change outputs.",
+
+	// GetReceivedByAddressesCmd help.
+	"getreceivedbyaddresses--synopsis": "Returns the total amount received by each of a set of addresses, in the order of the addresses.\n" +
+		"The totals are computed in a single pass over the transaction history, instead of one pass per getreceivedbyaddress request.",
+	"getreceivedbyaddresses-addresses": "The addresses to total, at most 10000.",
+	"getreceivedbyaddresses-minconf":   "Minimum number of block confirmations required before a transaction output is considered.",
+
+	// ReceivedByAddressResult help.
+	"receivedbyaddressresult-address": "The payment address.",
+	"receivedbyaddressresult-amount":  "The total amount received by the address valued in LBC.",
+
+	// GetSpendPolicyCmd help.
+	"getspendpolicy--synopsis": "Returns the spend policy enforced on every transaction published by the wallet.",
+
+	// GetSpendPolicyResult help.
+	"getspendpolicyresult-maxtxamount": "The maximum value a single transaction may pay to external addresses valued in LBC, or 0 if unlimited.",
+	"getspendpolicyresult-dailylimit":  "The maximum value that may be paid to external addresses per UTC day valued in LBC, or 0 if unlimited.",
+	"getspendpolicyresult-spenttoday":  "The value paid to external addresses so far today valued in LBC.",
+	"getspendpolicyresult-allowlist":   "If not empty, the only external addresses the wallet may pay.",
+	"getspendpolicyresult-denylist":    "Addresses the wallet must never pay.",
+
+	// GetVaultAddressCmd help.
+	"getvaultaddress--synopsis": "Returns a new address paying to a vault.",
+	"getvaultaddress-name":      "The name of the vault.",
+	"getvaultaddress--result0":  "The P2WSH address of the vault.",
+
+	// GetUptimeStatsCmd help.
+	"getuptimestats--synopsis": "Returns the availability of the wallet over the last days, as recorded in its database while it runs: its starts, how long it ran connected to the chain server and how far its sync lagged behind.\n" +
+		"The connection is checked every minute and the sync lag recorded every ten minutes, or when the connection changes.\n" +
+		"The history is kept for 90 days.",
+	"getuptimestats-days": "The number of days to return the availability of.",
+
+	// GetUptimeStatsResult help.
+	"getuptimestatsresult-since":        "The start of the period, in seconds since 1 Jan 1970 GMT, which is no earlier than the oldest record of the history.",
+	"getuptimestatsresult-starts":       "The times the wallet was started, in seconds since 1 Jan 1970 GMT.",
+	"getuptimestatsresult-uptime":       "The number of seconds the wallet ran.",
+	"getuptimestatsresult-disconnected": "The number of seconds the wallet ran without a connection to the chain server.",
+	"getuptimestatsresult-availability": "The percentage of the period the wallet ran connected to the chain server.",
+	"getuptimestatsresult-disconnects":  "The periods the wallet ran without a connection to the chain server.",
+	"getuptimestatsresult-maxsynclag":   "The largest number of blocks the wallet was synced behind the chain server.",
+	"getuptimestatsresult-synclag":      "The samples of the sync lag.",
+
+	// DisconnectWindowResult help.
+	"disconnectwindowresult-start": "The time the wallet was found disconnected, in seconds since 1 Jan 1970 GMT.",
+	"disconnectwindowresult-end":   "The time the wallet was connected again or stopped, in seconds since 1 Jan 1970 GMT, or omitted while still disconnected.",
+
+	// SyncLagResult help.
+	"synclagresult-time":   "The time of the sample, in seconds since 1 Jan 1970 GMT.",
+	"synclagresult-height": "The height of the block the wallet was synced to.",
+	"synclagresult-lag":    "The number of blocks the wallet was synced behind the chain server.",
+
+	// GetWalletActivityCmd help.
+	"getwalletactivity--synopsis": "Returns the transactions of an account summed by UTC day or by range of blocks, ordered by time.\n" +
+		"Days are those the transactions were received on, and unmined transactions are in no range of blocks.\n" +
+		"Periods without transactions are omitted.",
+	"getwalletactivity-account": "The account to sum the transactions of, or '*' for every account.",
+	"getwalletactivity-blocks":  "0 to sum the transactions by day, or the number of blocks of each range of blocks, starting at the genesis block.",
+
+	// WalletActivityResult help.
+	"walletactivityresult-date":         "The day of a daily period, as YYYY-MM-DD.",
+	"walletactivityresult-startheight":  "The height of the first block of a range of blocks.",
+	"walletactivityresult-endheight":    "The height of the last block of a range of blocks.",
+	"walletactivityresult-transactions": "The number of transactions of the period.",
+	"walletactivityresult-received":     "The value paid to the wallet, excluding change, valued in LBC.",
+	"walletactivityresult-sent":         "The value paid by the wallet, excluding change and fees, valued in LBC.",
+	"walletactivityresult-fees":         "The fees paid by the wallet valued in LBC.",
+	"walletactivityresult-claims":       "The number of claims created.",
+	"walletactivityresult-supports":     "The number of supports created.",
+	"walletactivityresult-updates":      "The number of claim updates.",
+	"walletactivityresult-newaddresses": "The number of addresses receiving their first payment.",
+
 	// GetUnconfirmedBalanceCmd help.
 	"getunconfirmedbalance--synopsis": "Calculates the unspent output value of all unmined transaction outputs.",
 	"getunconfirmedbalance-account":   "The account name to query the unconfirmed balance for. Default to 'default'.",
@@ -172,6 +625,8 @@ var helpDescsEnUS = map[string]string{
 	"gettransactionresult-walletconflicts": "Unset.",
 	"gettransactionresult-time":            "The earliest Unix time this transaction was known to exist.",
 	"gettransactionresult-timereceived":    "The earliest Unix time this transaction was known to exist.",
+	"gettransactionresult-comment":         "The comment the transaction was sent with, if any.",
+	"gettransactionresult-to":              "The comment naming the recipient the transaction was sent with, if any.",
 	"gettransactionresult-details":         "Additional details for each recorded wallet credit and debit.",
 	"gettransactionresult-hex":             "The transaction encoded as a hexadecimal string.",
 
@@ -193,11 +648,17 @@ var helpDescsEnUS = map[string]string{
 	"help--result1":    "Help for specified command.",
 
 	// ImportPrivKeyCmd help.
-	"importprivkey--synopsis": "Imports a WIF-encoded private key to the 'imported' account.",
+	"importprivkey--synopsis": "Imports a WIF-encoded private key to the 'imported' account, or to a named imported-key account.",
 	"importprivkey-privkey":   "The WIF-encoded private key.",
-	"importprivkey-label":     "Unused (must be unset or 'imported').",
+	"importprivkey-label":     "The imported-key account to add the key to, which must not name an HD account (default 'imported').",
 	"importprivkey-rescan":    "Rescan the blockchain (since the genesis block) for outputs controlled by the imported key.",
 
+	// ImportWalletCmd help.
+	"importwallet--synopsis": "Restores a file written by dumpwallet into the wallet, which must have the master key of the export and be unlocked, and rescans the blockchain from the birthday block of the export.\n" +
+		"Missing accounts are created, accounts are renamed as in the export and their addresses derived, and the imported keys and transaction labels are added.\n" +
+		"An export of another wallet is restored by creating a new wallet from it with lbcwallet --create --createfromdump.",
+	"importwallet-filename": "The path of the file written by dumpwallet.",
+
 	// InfoWalletResult help.
 	"infowalletresult-version":         "The version of the server.",
 	"infowalletresult-protocolversion": "The latest supported protocol version.",
@@ -218,8 +679,8 @@ var helpDescsEnUS = map[string]string{
 	"infowalletresult-keypoololdest":   "Unset.",
 
 	// KeypoolRefillCmd help.
-	"keypoolrefill--synopsis": "DEPRECATED -- This request does nothing since no keypool is maintained.",
-	"keypoolrefill-newsize":   "Unused.",
+	"keypoolrefill--synopsis": "Derives in advance the keys of the next unused external and internal addresses of every account, so that new addresses are handed out without deriving keys.",
+	"keypoolrefill-newsize":   "The number of keys of each branch of the accounts to derive in advance (default=100).",
 
 	// ListAccountsCmd help.
 	"listaccounts--synopsis":       "Returns a JSON object of all accounts and their balances.",
@@ -309,11 +770,15 @@ var helpDescsEnUS = map[string]string{
 	"listtransactionsresult-time":               "The earliest Unix time this transaction was known to exist.",
 	"listtransactionsresult-timereceived":       "The earliest Unix time this transaction was known to exist.",
 	"listtransactionsresult-involveswatchonly":  "Unset.",
-	"listtransactionsresult-comment":            "Unset.",
+	"listtransactionsresult-comment":            "The comment the transaction was sent with, if any.",
+	"listtransactionsresult-to":                 "The comment naming the recipient the transaction was sent with, if any.",
 	"listtransactionsresult-otheraccount":       "Unset.",
 	"listtransactionsresult-trusted":            "Unset.",
 	"listtransactionsresult-bip125-replaceable": "Unset.",
 	"listtransactionsresult-abandoned":          "Unset.",
+	"listtransactionsresult-fiatcurrency":       "The currency of the recorded exchange rate (only if a rate was recorded)",
+	"listtransactionsresult-fiatrate":           "The price of one LBC in the currency when the transaction was received (only if a rate was recorded)",
+	"listtransactionsresult-fiatamount":         "The amount in the currency at the recorded exchange rate (only if a rate was recorded)",
 
 	// ListUnspentCmd help.
 	"listunspent--synopsis": "Returns a JSON array of objects representing unlocked unspent outputs controlled by wallet keys.",
@@ -333,6 +798,8 @@ var helpDescsEnUS = map[string]string{
 	"listunspentresult-solvable":      "Whether the output is solvable.",
 	"listunspentresult-spendable":     "Whether the output is entirely controlled by wallet keys/scripts (false for partially controlled multisig outputs or outputs to watch-only addresses).",
 	"listunspentresult-isstake":       "Whether the output is staked.",
+	"listunspentresult-tainted":       "Whether the output, or the address it pays to, is tainted, so that coin selection never chooses it.",
+	"listunspentresult-taintreason":   "The reason the output or its address was tainted, if any.",
 
 	// LockUnspentCmd help.
 	"lockunspent--synopsis": "Locks or unlocks an unspent output.\n" +
@@ -343,6 +810,237 @@ var helpDescsEnUS = map[string]string{
 	"lockunspent-transactions": "Transaction outputs to lock or unlock.",
 	"lockunspent--result0":     "The boolean 'true'.",
 
+	// TaintUnspentCmd help.
+	"taintunspent--synopsis": "Taints unspent outputs, for instance those received in a dust attack, or clears their taint.\n" +
+		"Tainted outputs are never chosen by coin selection, and are only spent by transactions which explicitly spend them, such as raw or PSBT transactions with their inputs set.\n" +
+		"Unlike locked outputs, taints are kept in the wallet database across restarts.",
+	"taintunspent-taint":        "True to taint the outputs, false to clear their taint.",
+	"taintunspent-transactions": "The outputs to taint or clear.",
+	"taintunspent-reason":       "The reason the outputs are tainted.",
+	"taintunspent--result0":     "The boolean 'true'.",
+
+	// TaintAddressesCmd help.
+	"taintaddresses--synopsis": "Taints every output paying to addresses, including those received later, or clears the taint of the addresses.\n" +
+		"Outputs paying to tainted addresses are never chosen by coin selection, and are only spent by transactions which explicitly spend them.",
+	"taintaddresses-taint":     "True to taint the addresses, false to clear their taint.",
+	"taintaddresses-addresses": "The addresses to taint or clear.",
+	"taintaddresses-reason":    "The reason the addresses are tainted.",
+	"taintaddresses--result0":  "The boolean 'true'.",
+
+	// ListTaintedCmd help.
+	"listtainted--synopsis": "Returns the outputs and addresses tainted with taintunspent and taintaddresses.",
+
+	// ListTaintedResult help.
+	"listtaintedresult-outputs":   "The tainted outputs, ordered by transaction hash and output index.",
+	"listtaintedresult-addresses": "The tainted addresses, ordered by address.",
+
+	// TaintedOutputResult help.
+	"taintedoutputresult-txid":   "The hash of the transaction.",
+	"taintedoutputresult-vout":   "The index of the output.",
+	"taintedoutputresult-reason": "The reason the output was tainted.",
+
+	// TaintedAddressResult help.
+	"taintedaddressresult-address": "The tainted address.",
+	"taintedaddressresult-reason":  "The reason the address was tainted.",
+
+	// ListVaultsCmd help.
+	"listvaults--synopsis": "Returns every vault with its balances.",
+
+	// VaultResult help.
+	"vaultresult-name":           "The name of the vault.",
+	"vaultresult-account":        "The number of the account of the vault.",
+	"vaultresult-recoverypubkey": "The hex-encoded recovery public key of the vault.",
+	"vaultresult-delay":          "The number of confirmations the outputs of the vault must have before its keys spend them.",
+	"vaultresult-balance":        "The total value of the unspent outputs of the vault valued in LBC.",
+	"vaultresult-spendable":      "The value of the unspent outputs of the vault spendable by its keys valued in LBC.",
+
+	// ListWithdrawalsCmd help.
+	"listwithdrawals--synopsis": "Returns the withdrawals, the sends paying more than the withdrawal threshold, in the order they were requested, with the history of their requests, approvals and sends.\n" +
+		"Withdrawals without their approvals expire, and are kept with the sent and canceled withdrawals as the audit trail of the wallet.",
+	"listwithdrawals-state": "Only return the withdrawals in this state: 'pending', 'approved', 'sent', 'canceled' or 'expired'.",
+
+	// WithdrawalResult help.
+	"withdrawalresult-id":        "The ID of the withdrawal.",
+	"withdrawalresult-state":     "The state of the withdrawal: 'pending' until it has its approvals, 'approved' until it is sent, 'sent', 'canceled' or 'expired'.",
+	"withdrawalresult-amount":    "The total paid by the withdrawal valued in LBC.",
+	"withdrawalresult-outputs":   "The outputs paid by the withdrawal.",
+	"withdrawalresult-account":   "The number of the account the withdrawal is paid from.",
+	"withdrawalresult-requester": "The RPC user who requested the withdrawal.",
+	"withdrawalresult-approvers": "The approvers who approved the withdrawal, in the order they approved it.",
+	"withdrawalresult-required":  "The number of approvals the withdrawal needs.",
+	"withdrawalresult-created":   "The time the withdrawal was requested, in seconds since 1 Jan 1970 GMT.",
+	"withdrawalresult-expires":   "The time the withdrawal expires unless approved, in seconds since 1 Jan 1970 GMT.",
+	"withdrawalresult-txid":      "The hash of the transaction of the sent withdrawal.",
+	"withdrawalresult-history":   "The events of the withdrawal, in the order they happened.",
+
+	// WithdrawalOutput help.
+	"withdrawaloutput-address": "The address paid by the output.",
+	"withdrawaloutput-amount":  "The value of the output valued in LBC.",
+
+	// WithdrawalEventResult help.
+	"withdrawaleventresult-time":   "The time of the event, in seconds since 1 Jan 1970 GMT.",
+	"withdrawaleventresult-action": "What happened: 'request', 'approve', 'send', 'fail', 'cancel' or 'expire'.",
+	"withdrawaleventresult-user":   "The RPC user who requested, approved or canceled the withdrawal.",
+	"withdrawaleventresult-detail": "The hash of the sent transaction, or the error of a failed send.",
+
+	// ImportLbrycrdWalletCmd help.
+	"importlbrycrdwallet--synopsis": "Imports the keys of a wallet.dat file of the lbrycrd daemon as imported keys, which requires the wallet to be unlocked, and rescans the blockchain for their addresses from the block of the creation time of the oldest key.\n" +
+		"The address book labels of the keys become the names of their imported-key accounts, and the channels claimed by the keys are returned.",
+	"importlbrycrdwallet-filename":   "The path of the wallet.dat file, which must not be in use by lbrycrd.",
+	"importlbrycrdwallet-passphrase": "The passphrase of the lbrycrd wallet, when it is encrypted.",
+	"importlbrycrdwallet-rescan":     "Rescan the blockchain for outputs controlled by the imported keys.",
+
+	// ImportLbrycrdWalletResult help.
+	"importlbrycrdwalletresult-keys":       "The number of keys imported, not counting the keys already in the wallet.",
+	"importlbrycrdwalletresult-labels":     "The number of labels which became imported-key account names.",
+	"importlbrycrdwalletresult-channels":   "The unspent channel claims and updates of the lbrycrd wallet paying to its keys.",
+	"importlbrycrdwalletresult-rescanfrom": "The height of the block the rescan of the imported addresses starts from.",
+
+	// LbrycrdChannelResult help.
+	"lbrycrdchannelresult-claimid": "The claim ID of the channel.",
+	"lbrycrdchannelresult-name":    "The name of the channel.",
+	"lbrycrdchannelresult-address": "The address of the key signing for the channel.",
+	"lbrycrdchannelresult-txid":    "The hash of the transaction of the current claim or update of the channel.",
+	"lbrycrdchannelresult-vout":    "The index of the output of the claim or update.",
+
+	// ImportLbrySDKWalletCmd help.
+	"importlbrysdkwallet--synopsis": "Imports the accounts of a wallet file of lbry-sdk (lbrynet) as legacy accounts named after them, which requires the wallet to be unlocked, and the certificates of their channels as imported keys, then rescans the blockchain from the genesis block for their addresses.\n" +
+		"The first 1000 receiving and change addresses of each account are derived.",
+	"importlbrysdkwallet-filename": "The path of the lbry-sdk wallet file, such as ~/.lbryum/wallets/default_wallet.",
+	"importlbrysdkwallet-password": "The password of the lbry-sdk wallet, when its accounts are encrypted.",
+	"importlbrysdkwallet-rescan":   "Rescan the blockchain for outputs controlled by the imported accounts and keys.",
+
+	// ImportLbrySDKWalletResult help.
+	"importlbrysdkwalletresult-accounts": "The names of the accounts created.",
+	"importlbrysdkwalletresult-channels": "The channels whose certificates were imported.",
+
+	// ImportWatchPubKeyCmd help.
+	"importwatchpubkey--synopsis": "Imports a public key as a watch-only address of the imported account, and returns the address.\n" +
+		"The chain server is asked at once to notify the transactions paying the address, without restarting the wallet.\n" +
+		"When a start height is given, the blocks from it to the tip are rescanned in the background for the earlier transactions of the address.",
+	"importwatchpubkey-pubkey":      "The hex-encoded public key.",
+	"importwatchpubkey-addresstype": "The type of address of the key: legacy, p2sh-segwit, or bech32.",
+	"importwatchpubkey-startheight": "The block height to rescan from, or none to only watch transactions from now on.",
+	"importwatchpubkey--result0":    "The imported address.",
+
+	// LbrySDKChannelResult help.
+	"lbrysdkchannelresult-id":      "The claim ID of the channel, or the address of its key for later lbry-sdk versions, as keyed in the wallet file.",
+	"lbrysdkchannelresult-address": "The address of the imported channel key.",
+
+	// ListAccountClaimsCmd help.
+	"listaccountclaims--synopsis": "Returns the unspent claims, supports and claim updates paying to the addresses of an account, ordered by account and height.\n" +
+		"Channels are claims too, and the claims signed by a channel report its claim ID as their signing channel.",
+	"listaccountclaims-account": "Only return the claims of this account, or the claims of every account for \"*\".",
+
+	// AccountClaimResult help.
+	"accountclaimresult-account":        "The name of the account owning the output.",
+	"accountclaimresult-txid":           "The hash of the transaction.",
+	"accountclaimresult-vout":           "The index of the output.",
+	"accountclaimresult-address":        "The address the output pays to.",
+	"accountclaimresult-amount":         "The value of the output valued in LBC.",
+	"accountclaimresult-confirmations":  "The number of block confirmations of the output.",
+	"accountclaimresult-type":           "The claim operation of the output: claim, support or update.",
+	"accountclaimresult-name":           "The name claimed or supported.",
+	"accountclaimresult-claimid":        "The ID of the claim, or of the claim supported or updated.",
+	"accountclaimresult-kind":           "The kind of claim (stream, channel, collection or repost), when its value could be decoded.",
+	"accountclaimresult-signingchannel": "The claim ID of the channel which signed the claim, if any.",
+
+	// ListAccountInfoCmd help.
+	"listaccountinfo--synopsis": "Returns the balances and key counts of every account, ordered by account number.",
+	"listaccountinfo-minconf":   "Minimum number of block confirmations required before an output is spendable.",
+
+	// ListAccountUnspentCmd help.
+	"listaccountunspent--synopsis": "Returns the unlocked unspent outputs controlled by the keys of an account.\n" +
+		"These are the outputs that sendfrom and sendmany may spend when sending from the account.",
+	"listaccountunspent-account": "The name of the account.",
+	"listaccountunspent-minconf": "Minimum number of block confirmations required before a transaction output is considered.",
+	"listaccountunspent-maxconf": "Maximum number of block confirmations required before a transaction output is excluded.",
+
+	// ListBalanceMovesCmd help.
+	"listbalancemoves--synopsis": "Returns the moves recorded with movebalance in the order they were recorded.",
+	"listbalancemoves-account":   "Only return the moves from or to this account, or all moves for \"*\".",
+
+	// ListDescriptorsCmd help.
+	"listdescriptors--synopsis": "Returns the output script descriptors, with checksums, of the external and internal branches of every HD account of every key scope, from which descriptor-aware wallets such as Bitcoin Core recreate watch-only copies of the accounts.\n" +
+		"The keys are extended public keys with the key origin: the fingerprint of the master key and the hardened derivation path of the account.",
+
+	// ListDescriptorsResult help.
+	"listdescriptorsresult-descriptors": "The descriptors of the accounts.",
+
+	// DescriptorResult help.
+	"descriptorresult-desc":      "The descriptor with its checksum.",
+	"descriptorresult-timestamp": "The birthday of the wallet, from which the blockchain is scanned for the descriptor, in seconds since 1 Jan 1970 GMT.",
+	"descriptorresult-active":    "Whether the wallet hands out addresses of the descriptor, which is always true.",
+	"descriptorresult-internal":  "Whether the descriptor is of the internal (change) branch of the account.",
+	"descriptorresult-range":     "The first and last index of the addresses derived by the wallet, up to the next address to be handed out.",
+	"descriptorresult-next":      "The index of the next address to be handed out.",
+	"descriptorresult-account":   "The name of the account.",
+
+	// ListImportedAccountsCmd help.
+	"listimportedaccounts--synopsis": "Returns every imported-key account with its addresses and balance, ordered by name.\n" +
+		"Keys imported without an account belong to the 'imported' account.",
+
+	// ImportedAccountResult help.
+	"importedaccountresult-account":   "The name of the imported-key account.",
+	"importedaccountresult-addresses": "The addresses of the keys of the account.",
+	"importedaccountresult-balance":   "The value of the unspent outputs paying to the addresses valued in LBC, including unconfirmed outputs.",
+
+	// ListInvoicesCmd help.
+	"listinvoices--synopsis": "Returns the invoices created with createinvoice in the order they were created.",
+	"listinvoices-state":     "Only return invoices in this state: unpaid, paid, or expired.",
+
+	// ListWalletsCmd help.
+	"listwallets--synopsis": "Returns the names of the loaded wallets.\n" +
+		"The default wallet is named by the empty string, and the other wallets are used by HTTP POST requests to /wallet/<name>.",
+	"listwallets--result0": "The names of the loaded wallets.",
+
+	// LoadWalletCmd help.
+	"loadwallet--synopsis":     "Loads a named wallet from the wallets directory of the network directory, and unlocks it with the default or configured passphrase.",
+	"loadwallet-walletname":    "The name of the wallet.",
+	"loadwalletresult-name":    "The name of the loaded wallet.",
+	"loadwalletresult-warning": "Warnings raised while loading the wallet, if any.",
+
+	// MoveBalanceCmd help.
+	"movebalance--synopsis": "Records a move of value from the ledger balance of an account to another, as the move command of Bitcoin Core did.\n" +
+		"No transaction is created and no fee is paid: the unspent outputs and spendable balances of both accounts are unchanged.\n" +
+		"Moves are kept as an audit trail, returned by listbalancemoves, and summed in the ledger balances of getaccountinfo.\n" +
+		"The ledger balance of the source account may become negative.",
+	"movebalance-fromaccount": "The account to move the value from.",
+	"movebalance-toaccount":   "The account to move the value to.",
+	"movebalance-amount":      "The value to move in LBC.",
+	"movebalance-comment":     "A comment recorded with the move.",
+
+	// BalanceMoveResult help.
+	"balancemoveresult-id":          "The ID of the move.",
+	"balancemoveresult-time":        "The time the move was recorded in seconds since the epoch.",
+	"balancemoveresult-fromaccount": "The account the value was moved from.",
+	"balancemoveresult-toaccount":   "The account the value was moved to.",
+	"balancemoveresult-amount":      "The moved value in LBC.",
+	"balancemoveresult-comment":     "The comment recorded with the move.",
+
+	// NotifyConfirmationsCmd help.
+	"notifyconfirmations--synopsis": "Registers a notification sent once a wallet transaction has been mined with at least the given number of confirmations.\n" +
+		"Websocket clients are sent a txconfirmed notification unless a webhook URL is given, which HTTP POST clients must do.\n" +
+		"Webhooks are sent the confirmation as a JSON object in an HTTP POST request.\n" +
+		"Each registration is notified once, and those of websocket clients are removed when they disconnect.",
+	"notifyconfirmations-txid":          "The hash of the transaction.",
+	"notifyconfirmations-confirmations": "The number of confirmations to notify at.",
+	"notifyconfirmations-url":           "The http or https URL to POST the notification to instead of the websocket connection.",
+	"notifyconfirmations--result0":      "The id of the registration, included in its notification.",
+
+	// PublishPsbtCmd help.
+	"publishpsbt--synopsis": "Publishes the transaction of a PSBT signed by signpsbt, after checking its signatures, and unlocks the outputs it spends.",
+	"publishpsbt-psbt":      "The base64-encoded signed PSBT.",
+	"publishpsbt--result0":  "The transaction hash of the sent transaction.",
+
+	// RecoverVaultCmd help.
+	"recovervault--synopsis":       "Sweeps every unspent output of a vault, confirmed or not, to an address with the recovery key of the vault.",
+	"recovervault-name":            "The name of the vault.",
+	"recovervault-recoveryprivkey": "The recovery private key of the vault encoded in WIF.",
+	"recovervault-address":         "The address to sweep the outputs to.",
+	"recovervault-feerate":         "The fee rate in LBC/kB, defaulting to the minimum relay fee.",
+	"recovervault--result0":        "The hash of the sweeping transaction.",
+
 	// RenameAccountCmd help.
 	"renameaccount--synopsis":  "Renames an account.",
 	"renameaccount-oldaccount": "The old account name to rename.",
@@ -357,21 +1055,41 @@ var helpDescsEnUS = map[string]string{
 	"rescanblockchainresult-start_height": "The block height where the rescan started (the requested height or 0)",
 	"rescanblockchainresult-stop_height":  "The height of the last rescanned block.",
 
+	// SendFromVaultCmd help.
+	"sendfromvault--synopsis":      "Pays addresses from the outputs of a vault which have the confirmations of its delay, and pays the change back to the vault.",
+	"sendfromvault-name":           "The name of the vault.",
+	"sendfromvault-amounts":        "Pairs of payment addresses and the output amount to pay each.",
+	"sendfromvault-amounts--desc":  "JSON object using payment addresses as keys and output amounts valued in LBC to send to each address.",
+	"sendfromvault-amounts--key":   "Address to pay.",
+	"sendfromvault-amounts--value": "Amount to send to the payment address valued in LBC.",
+	"sendfromvault-feerate":        "The fee rate in LBC/kB, defaulting to the minimum relay fee.",
+	"sendfromvault--result0":       "The hash of the transaction.",
+
 	// SendFromCmd help.
 	"sendfrom--synopsis": "Authors, signs, and sends a transaction that outputs some amount to a payment address.\n" +
-		"A change output is automatically included to send extra output value back to the original account.",
+		"A change output is automatically included to send extra output value back to the original account.\n" +
+		"When the wallet previews sends, the transaction is not signed or sent until confirmsend is called with the handle of its preview, which is returned instead.\n" +
+		"Sends paying more than the withdrawal threshold are queued as withdrawals, which are returned instead and sent once approved with approvewithdrawal.\n" +
+		"When the keys of the wallet are offline, an unsigned PSBT is returned instead, to be signed by signpsbt of the offline wallet and sent with publishpsbt.",
 	"sendfrom-fromaccount": "Account to pick unspent outputs from.",
 	"sendfrom-toaddress":   "Address to pay.",
 	"sendfrom-amount":      "Amount to send to the payment address valued in LBC.",
 	"sendfrom-minconf":     "Minimum number of block confirmations required before a transaction output is eligible to be spent.",
 	"sendfrom-addresstype": "Address type filter for UTXOs to spent from. Must be one of 'legacy', 'p2sh-segwit', 'bech32', or '*'. Defaults to '*'.",
-	"sendfrom-comment":     "Unused.",
-	"sendfrom-commentto":   "Unused.",
+	"sendfrom-comment":     "A comment stored with the transaction, returned by gettransaction and listtransactions.",
+	"sendfrom-commentto":   "A comment naming the recipient, stored with the transaction.",
 	"sendfrom--result0":    "The transaction hash of the sent transaction.",
+	"sendfrom--condition0": "sends neither previewed, requiring approval nor signed offline.",
+	"sendfrom--condition1": "sends previewed.",
+	"sendfrom--condition2": "sends requiring approval.",
+	"sendfrom--condition3": "sends signed offline.",
 
 	// SendManyCmd help.
 	"sendmany--synopsis": "Authors, signs, and sends a transaction that outputs to many payment addresses.\n" +
-		"A change output is automatically included to send extra output value back to the original account.",
+		"A change output is automatically included to send extra output value back to the original account.\n" +
+		"When the wallet previews sends, the transaction is not signed or sent until confirmsend is called with the handle of its preview, which is returned instead.\n" +
+		"Sends paying more than the withdrawal threshold are queued as withdrawals, which are returned instead and sent once approved with approvewithdrawal.\n" +
+		"When the keys of the wallet are offline, an unsigned PSBT is returned instead, to be signed by signpsbt of the offline wallet and sent with publishpsbt.",
 	"sendmany-fromaccount":    "Account to pick unspent outputs from.",
 	"sendmany-amounts":        "Pairs of payment addresses and the output amount to pay each.",
 	"sendmany-amounts--desc":  "JSON object using payment addresses as keys and output amounts valued in LBC to send to each address.",
@@ -379,25 +1097,126 @@ var helpDescsEnUS = map[string]string{
 	"sendmany-amounts--value": "Amount to send to the payment address valued in LBC.",
 	"sendmany-minconf":        "Minimum number of block confirmations required before a transaction output is eligible to be spent.",
 	"sendmany-addresstype":    "Address type filter for UTXOs to spent from. Must be one of 'legacy', 'p2sh-segwit', 'bech32', or '*'. Defaults to '*'.",
-	"sendmany-comment":        "Unused.",
+	"sendmany-comment":        "A comment stored with the transaction, returned by gettransaction and listtransactions.",
 	"sendmany--result0":       "The transaction hash of the sent transaction.",
+	"sendmany--condition0":    "sends neither previewed, requiring approval nor signed offline.",
+	"sendmany--condition1":    "sends previewed.",
+	"sendmany--condition2":    "sends requiring approval.",
+	"sendmany--condition3":    "sends signed offline.",
 
 	// SendToAddressCmd help.
 	"sendtoaddress--synopsis": "Authors, signs, and sends a transaction that outputs some amount to a payment address.\n" +
 		"Unlike sendfrom, outputs are always chosen from the default account.\n" +
-		"A change output is automatically included to send extra output value back to the original account.",
+		"A change output is automatically included to send extra output value back to the original account.\n" +
+		"When the wallet previews sends, the transaction is not signed or sent until confirmsend is called with the handle of its preview, which is returned instead.\n" +
+		"Sends paying more than the withdrawal threshold are queued as withdrawals, which are returned instead and sent once approved with approvewithdrawal.\n" +
+		"When the keys of the wallet are offline, an unsigned PSBT is returned instead, to be signed by signpsbt of the offline wallet and sent with publishpsbt.",
 	"sendtoaddress-address":     "Address to pay.",
 	"sendtoaddress-amount":      "Amount to send to the payment address valued in LBC.",
 	"sendtoaddress-addresstype": "Address type filter for UTXOs to spent from. Must be one of 'legacy', 'p2sh-segwit', 'bech32', or '*'. Defaults to '*'.",
-	"sendtoaddress-comment":     "Unused.",
-	"sendtoaddress-commentto":   "Unused.",
+	"sendtoaddress-comment":     "A comment stored with the transaction, returned by gettransaction and listtransactions.",
+	"sendtoaddress-commentto":   "A comment naming the recipient, stored with the transaction.",
 	"sendtoaddress--result0":    "The transaction hash of the sent transaction.",
+	"sendtoaddress--condition0": "sends neither previewed, requiring approval nor signed offline.",
+	"sendtoaddress--condition1": "sends previewed.",
+	"sendtoaddress--condition2": "sends requiring approval.",
+	"sendtoaddress--condition3": "sends signed offline.",
+
+	// ColdSendResult help.
+	"coldsendresult-psbt": "The base64-encoded unsigned PSBT, to be signed by signpsbt of the offline wallet.",
+	"coldsendresult-fee":  "The fee paid by the transaction valued in LBC.",
+	"coldsendresult-file": "The file the PSBT was written to, if the wallet writes them to a directory.",
+
+	// SendPreviewResult help.
+	"sendpreviewresult-handle":  "The handle to pass to confirmsend to sign and send the transaction, or to cancelsend to discard it.",
+	"sendpreviewresult-hex":     "The hex-encoded unsigned transaction.",
+	"sendpreviewresult-inputs":  "The outputs spent by the transaction.",
+	"sendpreviewresult-outputs": "The outputs of the transaction.",
+	"sendpreviewresult-fee":     "The fee paid by the transaction valued in LBC.",
+	"sendpreviewresult-vsize":   "The estimated virtual size of the signed transaction.",
+	"sendpreviewresult-expires": "The time the preview is discarded unless confirmed, in seconds since 1 Jan 1970 GMT.",
+
+	// SendPreviewInput help.
+	"sendpreviewinput-txid":   "The hash of the transaction of the spent output.",
+	"sendpreviewinput-vout":   "The index of the spent output.",
+	"sendpreviewinput-amount": "The value of the spent output valued in LBC.",
+
+	// SendPreviewOutput help.
+	"sendpreviewoutput-vout":    "The index of the output.",
+	"sendpreviewoutput-address": "The address paid by the output, if any.",
+	"sendpreviewoutput-amount":  "The value of the output valued in LBC.",
+	"sendpreviewoutput-change":  "Whether the output pays the change back to the wallet.",
 
 	// SetTxFeeCmd help.
 	"settxfee--synopsis": "Modify the increment used each time more fee is required for an authored transaction.",
 	"settxfee-amount":    "The new fee increment valued in LBC.",
 	"settxfee--result0":  "The boolean 'true'.",
 
+	// RequestTestCoinsCmd help.
+	"requesttestcoins--synopsis": "Requests test coins from the faucet configured with --faucet to a new address of an account, and optionally waits until the deposit is received.\n" +
+		"Refused on mainnet.",
+	"requesttestcoins-amount":  "The amount to request valued in LBC, or the amount of the faucet's choice when omitted.",
+	"requesttestcoins-account": "The account of the new address.",
+	"requesttestcoins-wait":    "Whether to wait until the deposit is received, confirmed or not.",
+
+	// RequestTestCoinsResult help.
+	"requesttestcoinsresult-address":  "The address paid by the faucet.",
+	"requesttestcoinsresult-amount":   "The amount requested valued in LBC, omitted when left to the faucet.",
+	"requesttestcoinsresult-txid":     "The hash of the transaction paying the address, when returned by the faucet.",
+	"requesttestcoinsresult-received": "The amount received by the address valued in LBC, which is only waited for with wait.",
+
+	// RescanImportedAccountCmd help.
+	"rescanimportedaccount--synopsis":   "Rescans the blockchain for the transactions of the addresses of an imported-key account, and returns once the rescan completes.",
+	"rescanimportedaccount-account":     "The name of the imported-key account.",
+	"rescanimportedaccount-startheight": "The block height to rescan from.",
+
+	// SetImportedAccountCmd help.
+	"setimportedaccount--synopsis": "Moves the address of an imported key to an imported-key account, which is created if it has no address yet.\n" +
+		"Moving an address to the 'imported' account removes it from its named account.",
+	"setimportedaccount-address": "The address of the imported key.",
+	"setimportedaccount-account": "The imported-key account, which must not name an HD account.",
+
+	// SetSpendPolicyCmd help.
+	"setspendpolicy--synopsis": "Updates the spend policy enforced on every transaction published by the wallet.\n" +
+		"Only outputs paying addresses the wallet does not hold the keys of, including its watch-only addresses, are subject to the policy.\n" +
+		"Transactions can't be sent with sendrawtransaction while a policy is set.\n" +
+		"Omitted fields keep their current value.\n" +
+		"Raising or removing a limit, adding addresses to or clearing the allowlist, and removing addresses from the denylist require the wallet passphrase.",
+	"setspendpolicy-maxtxamount": "The maximum value a single transaction may pay to external addresses valued in LBC, or 0 to remove the limit.",
+	"setspendpolicy-dailylimit":  "The maximum value that may be paid to external addresses per UTC day valued in LBC, or 0 to remove the limit.",
+	"setspendpolicy-allowlist":   "If not empty, the only external addresses the wallet may pay.",
+	"setspendpolicy-denylist":    "Addresses the wallet must never pay.",
+	"setspendpolicy-passphrase":  "The wallet passphrase, required to loosen the policy.",
+
+	// StopNotifyConfirmationsCmd help.
+	"stopnotifyconfirmations--synopsis": "Removes a registration made with notifyconfirmations before it is notified.",
+	"stopnotifyconfirmations-id":        "The id returned by notifyconfirmations.",
+
+	// SignPsbtCmd help.
+	"signpsbt--synopsis": "Signs the inputs of a PSBT spending outputs of the keys of the wallet, such as one returned by a send of the watch-only copy of the wallet, and finalizes them.\n" +
+		"The keys are derived from the BIP32 derivation paths of the inputs, so the wallet need not have seen their addresses.\n" +
+		"Inputs already signed and those of other keys are left as they are.",
+	"signpsbt-psbt": "The base64-encoded PSBT.",
+
+	// SignPsbtResult help.
+	"signpsbtresult-psbt":     "The base64-encoded PSBT with the inputs signed, to be sent with publishpsbt of the online wallet.",
+	"signpsbtresult-signed":   "The number of inputs signed.",
+	"signpsbtresult-complete": "Whether every input of the PSBT is signed.",
+
+	// SweepAccountPsbtCmd help.
+	"sweepaccountpsbt--synopsis": "Creates an unsigned PSBT spending every eligible output of an account to a single output paying an address, less the fee.\n" +
+		"The inputs include their UTXOs and BIP32 derivation paths, so the PSBT can be signed offline by the holder of the account's keys, such as a watch-only account rebuilt with --recoverxpubs.\n" +
+		"The inputs are not locked.",
+	"sweepaccountpsbt-account": "The account to sweep.",
+	"sweepaccountpsbt-address": "The address paid by the sweep.",
+	"sweepaccountpsbt-minconf": "Minimum number of block confirmations of the outputs to sweep.",
+	"sweepaccountpsbt-feerate": "The fee rate in LBC/kB, defaulting to the minimum relay fee.",
+
+	// SweepAccountPsbtResult help.
+	"sweepaccountpsbtresult-psbt":   "The base64-encoded unsigned PSBT.",
+	"sweepaccountpsbtresult-fee":    "The fee of the transaction valued in LBC.",
+	"sweepaccountpsbtresult-amount": "The amount paid to the address valued in LBC.",
+
 	// SignMessageCmd help.
 	"signmessage--synopsis": "Signs a message using the private key of a payment address.",
 	"signmessage-address":   "Payment address of private key used to sign the message with.",
@@ -424,28 +1243,46 @@ var helpDescsEnUS = map[string]string{
 	"signrawtransactionerror-txid":      "The transaction hash of the referenced previous output.",
 	"signrawtransactionerror-vout":      "The output index of the referenced previous output.",
 
+	// UnloadWalletCmd help.
+	"unloadwallet--synopsis": "Unloads a named wallet once the requests being handled by it have finished.\n" +
+		"The wallet is locked and its database is closed, so its files can be copied or loaded by another process.\n" +
+		"The default wallet can not be unloaded.",
+	"unloadwallet-walletname": "The name of the wallet, which defaults to the wallet of the request path.",
+
+	// UnwatchAddressesCmd help.
+	"unwatchaddresses--synopsis": "Stops sending watchedaddresstx notifications for addresses registered with watchaddresses.\n" +
+		"This method is only available to websocket clients.",
+	"unwatchaddresses-addresses": "The addresses to stop watching.",
+
 	// ValidateAddressCmd help.
 	"validateaddress--synopsis": "Verify that an address is valid.\n" +
 		"Extra details are returned if the address is controlled by this wallet.\n" +
+		"The script type, network and witness fields are returned for any valid address, and the reason an address is invalid is returned in error.\n" +
 		"The following fields are valid only when the address is controlled by this wallet (ismine=true): isscript, pubkey, iscompressed, account, addresses, hex, script, and sigsrequired.\n" +
 		"The following fields are only valid when address has an associated public key: pubkey, iscompressed.\n" +
 		"The following fields are only valid when address is a pay-to-script-hash address: addresses, hex, and script.\n" +
 		"If the address is a multisig address controlled by this wallet, the multisig fields will be left unset if the wallet is locked since the redeem script cannot be decrypted.",
 	"validateaddress-address": "Address to validate.",
 
-	// ValidateAddressWalletResult help.
-	"validateaddresswalletresult-isvalid":      "Whether or not the address is valid.",
-	"validateaddresswalletresult-address":      "The payment address (only when isvalid is true).",
-	"validateaddresswalletresult-ismine":       "Whether this address is controlled by the wallet (only when isvalid is true).",
-	"validateaddresswalletresult-iswatchonly":  "Unset.",
-	"validateaddresswalletresult-isscript":     "Whether the payment address is a pay-to-script-hash address (only when isvalid is true).",
-	"validateaddresswalletresult-pubkey":       "The associated public key of the payment address, if any (only when isvalid is true).",
-	"validateaddresswalletresult-iscompressed": "Whether the address was created by hashing a compressed public key, if any (only when isvalid is true).",
-	"validateaddresswalletresult-account":      "The account this payment address belongs to (only when isvalid is true).",
-	"validateaddresswalletresult-addresses":    "All associated payment addresses of the script if address is a multisig address (only when isvalid is true).",
-	"validateaddresswalletresult-hex":          "The redeem script .",
-	"validateaddresswalletresult-script":       "The class of redeem script for a multisig address.",
-	"validateaddresswalletresult-sigsrequired": "The number of required signatures to redeem outputs to the multisig address.",
+	// ValidateAddressResult help.
+	"validateaddressresult-isvalid":         "Whether or not the address is valid.",
+	"validateaddressresult-address":         "The payment address (only when isvalid is true).",
+	"validateaddressresult-scripttype":      "The class of the output script paying to the address, such as pubkeyhash, scripthash, witness_v0_keyhash or witness_v1_taproot (only when isvalid is true).",
+	"validateaddressresult-network":         "The network of the wallet when isvalid is true, otherwise the network the address is meant for when its prefix is known.",
+	"validateaddressresult-iswitness":       "Whether the address pays to a witness program (only when isvalid is true).",
+	"validateaddressresult-witness_version": "The witness version of a witness address.",
+	"validateaddressresult-witness_program": "The witness program of a witness address, in hex.",
+	"validateaddressresult-error":           "The reason the address is invalid, such as a wrong network prefix, a bad checksum or an unsupported witness version (only when isvalid is false).",
+	"validateaddressresult-ismine":          "Whether this address is controlled by the wallet (only when isvalid is true).",
+	"validateaddressresult-iswatchonly":     "Unset.",
+	"validateaddressresult-isscript":        "Whether the payment address is a pay-to-script-hash address (only when isvalid is true).",
+	"validateaddressresult-pubkey":          "The associated public key of the payment address, if any (only when isvalid is true).",
+	"validateaddressresult-iscompressed":    "Whether the address was created by hashing a compressed public key, if any (only when isvalid is true).",
+	"validateaddressresult-account":         "The account this payment address belongs to (only when isvalid is true).",
+	"validateaddressresult-addresses":       "All associated payment addresses of the script if address is a multisig address (only when isvalid is true).",
+	"validateaddressresult-hex":             "The redeem script .",
+	"validateaddressresult-script":          "The class of redeem script for a multisig address.",
+	"validateaddressresult-sigsrequired":    "The number of required signatures to redeem outputs to the multisig address.",
 
 	// VerifyMessageCmd help.
 	"verifymessage--synopsis": "Verify a message was signed with the associated private key of some address.",
@@ -454,10 +1291,36 @@ var helpDescsEnUS = map[string]string{
 	"verifymessage-message":   "The message to verify.",
 	"verifymessage--result0":  "Whether the message was signed with the private key of 'address'.",
 
+	// WatchAddressesCmd help.
+	"watchaddresses--synopsis": "Registers addresses, which need not belong to the wallet, with the chain server and sends a watchedaddresstx notification to this client for every transaction paying them.\n" +
+		"Transactions are notified when they are accepted to the mempool and again when they are mined.\n" +
+		"They are not recorded by the wallet unless they are otherwise relevant to it.\n" +
+		"Addresses are watched until unwatchaddresses is called or the client disconnects.\n" +
+		"This method is only available to websocket clients.",
+	"watchaddresses-addresses": "The addresses to watch.",
+
+	// WalletGetDataCmd help.
+	"walletgetdata--synopsis":       "Returns a value stored with walletsetdata, or every key and value of a namespace.",
+	"walletgetdata-namespace":       "The namespace of the application data.",
+	"walletgetdata-key":             "The key to return the value of.",
+	"walletgetdata--condition0":     "key specified",
+	"walletgetdata--condition1":     "key omitted",
+	"walletgetdata--result0":        "The value of the key.",
+	"walletgetdata--result1--desc":  "JSON object of the keys and values of the namespace.",
+	"walletgetdata--result1--key":   "The key",
+	"walletgetdata--result1--value": "The value of the key",
+
 	// WalletIsLockedCmd help.
 	"walletislocked--synopsis": "Returns whether or not the wallet is locked.",
 	"walletislocked--result0":  "Whether the wallet is locked.",
 
+	// WalletSetDataCmd help.
+	"walletsetdata--synopsis": "Stores a value in a namespace of the application data kept in the wallet database, where applications persist their own metadata.\n" +
+		"Namespaces and keys are at most 255 bytes and values at most 65536 bytes.",
+	"walletsetdata-namespace": "The namespace of the application data, such as the name of the application.",
+	"walletsetdata-key":       "The key to set.",
+	"walletsetdata-value":     "The value of the key, or omitted to remove the key.",
+
 	// WalletLockCmd help.
 	"walletlock--synopsis": "Lock the wallet.",
 
@@ -9,6 +9,7 @@ package rpchelp
 
 import (
 	"github.com/lbryio/lbcd/btcjson"
+	"github.com/lbryio/lbcwallet/rpc/walletjson"
 )
 
 // Common return types.
@@ -17,7 +18,7 @@ var (
 	returnsNumber      = []interface{}{(*float64)(nil)}
 	returnsString      = []interface{}{(*string)(nil)}
 	returnsStringArray = []interface{}{(*[]string)(nil)}
-	returnsLTRArray    = []interface{}{(*[]btcjson.ListTransactionsResult)(nil)}
+	returnsLTRArray    = []interface{}{(*[]walletjson.ListTransactionsResult)(nil)}
 )
 
 // Methods contains all methods and result types that help is generated for,
@@ -29,6 +30,7 @@ var Methods = []struct {
 	{"addmultisigaddress", returnsString},
 	{"createmultisig", []interface{}{(*btcjson.CreateMultiSigResult)(nil)}},
 	{"dumpprivkey", returnsString},
+	{"dumpwallet", []interface{}{(*btcjson.DumpWalletResult)(nil)}},
 	{"getaccount", returnsString},
 	{"getaccountaddress", returnsString},
 	{"getaddressesbyaccount", returnsStringArray},
@@ -37,13 +39,15 @@ var Methods = []struct {
 	{"getbestblockhash", returnsString},
 	{"getblockcount", returnsNumber},
 	{"getinfo", []interface{}{(*btcjson.InfoWalletResult)(nil)}},
+	{"getwalletinfo", []interface{}{(*walletjson.GetWalletInfoResult)(nil)}},
 	{"getnewaddress", returnsString},
 	{"getrawchangeaddress", returnsString},
 	{"getreceivedbyaccount", returnsNumber},
 	{"getreceivedbyaddress", returnsNumber},
-	{"gettransaction", []interface{}{(*btcjson.GetTransactionResult)(nil)}},
+	{"gettransaction", []interface{}{(*walletjson.GetTransactionResult)(nil)}},
 	{"help", append(returnsString, returnsString[0])},
 	{"importprivkey", nil},
+	{"importwallet", nil},
 	{"keypoolrefill", nil},
 	{"listaccounts", []interface{}{(*map[string]float64)(nil)}},
 	{"listlockunspent", []interface{}{(*[]btcjson.TransactionInput)(nil)}},
@@ -51,27 +55,97 @@ var Methods = []struct {
 	{"listreceivedbyaddress", []interface{}{(*[]btcjson.ListReceivedByAddressResult)(nil)}},
 	{"listsinceblock", []interface{}{(*btcjson.ListSinceBlockResult)(nil)}},
 	{"listtransactions", returnsLTRArray},
-	{"listunspent", []interface{}{(*btcjson.ListUnspentResult)(nil)}},
+	{"listunspent", []interface{}{(*[]walletjson.ListUnspentResult)(nil)}},
 	{"lockunspent", returnsBool},
-	{"sendfrom", returnsString},
-	{"sendmany", returnsString},
-	{"sendtoaddress", returnsString},
+	{"sendfrom", append(returnsString, (*walletjson.SendPreviewResult)(nil), (*walletjson.WithdrawalResult)(nil), (*walletjson.ColdSendResult)(nil))},
+	{"sendmany", append(returnsString, (*walletjson.SendPreviewResult)(nil), (*walletjson.WithdrawalResult)(nil), (*walletjson.ColdSendResult)(nil))},
+	{"sendtoaddress", append(returnsString, (*walletjson.SendPreviewResult)(nil), (*walletjson.WithdrawalResult)(nil), (*walletjson.ColdSendResult)(nil))},
 	{"settxfee", returnsBool},
 	{"signmessage", returnsString},
 	{"signrawtransaction", []interface{}{(*btcjson.SignRawTransactionResult)(nil)}},
-	{"validateaddress", []interface{}{(*btcjson.ValidateAddressWalletResult)(nil)}},
+	{"validateaddress", []interface{}{(*walletjson.ValidateAddressResult)(nil)}},
 	{"verifymessage", returnsBool},
 	{"walletlock", nil},
 	{"walletpassphrase", nil},
 	{"walletpassphrasechange", nil},
+	{"approvewithdrawal", []interface{}{(*walletjson.WithdrawalResult)(nil)}},
+	{"benchsigning", []interface{}{(*walletjson.BenchSigningResult)(nil)}},
+	{"cancelsend", nil},
+	{"cancelwithdrawal", []interface{}{(*walletjson.WithdrawalResult)(nil)}},
+	{"changepublicpassphrase", nil},
+	{"clonewallet", nil},
+	{"collectdebuginfo", returnsString},
+	{"confirmsend", returnsString},
+	{"createinvoice", []interface{}{(*walletjson.InvoiceResult)(nil)}},
 	{"createnewaccount", nil},
+	{"createpaymenturi", []interface{}{(*walletjson.CreatePaymentURIResult)(nil)}},
+	{"createvault", nil},
+	{"decodepaymenturi", []interface{}{(*walletjson.DecodePaymentURIResult)(nil)}},
+	{"diagnosetransaction", []interface{}{(*walletjson.DiagnoseTransactionResult)(nil)}},
+	{"dumpgoroutines", returnsString},
+	{"dumpheapprofile", returnsString},
+	{"dumpimportedaccount", []interface{}{(*[]walletjson.ImportedKeyResult)(nil)}},
+	{"exportseedshares", returnsStringArray},
+	{"exporttransactions", []interface{}{(*walletjson.ExportTransactionsResult)(nil)}},
+	{"exporttxlog", []interface{}{(*walletjson.ExportTxLogResult)(nil)}},
+	{"fastforward", []interface{}{(*walletjson.FastForwardResult)(nil)}},
+	{"fundaddress", []interface{}{(*walletjson.FundAddressResult)(nil)}},
+	{"generatetowallet", returnsStringArray},
+	{"getaccountinfo", []interface{}{(*walletjson.AccountInfoResult)(nil)}},
 	{"getbestblock", []interface{}{(*btcjson.GetBestBlockResult)(nil)}},
+	{"getbackupstatus", []interface{}{(*walletjson.GetBackupStatusResult)(nil)}},
+	{"getconnectionstatus", []interface{}{(*walletjson.GetConnectionStatusResult)(nil)}},
+	{"getdecoyaddresses", []interface{}{(*[]walletjson.DecoyAddressResult)(nil)}},
+	{"getdiskstatus", []interface{}{(*walletjson.GetDiskStatusResult)(nil)}},
+	{"getnetworkinfo", []interface{}{(*walletjson.GetNetworkInfoResult)(nil)}},
+	{"getinvoice", []interface{}{(*walletjson.InvoiceResult)(nil)}},
+	{"getnewaddresses", []interface{}{(*[]walletjson.NewAddressResult)(nil)}},
+	{"getprivacyreport", []interface{}{(*walletjson.GetPrivacyReportResult)(nil)}},
+	{"getreceivedbyaddresses", []interface{}{(*[]walletjson.ReceivedByAddressResult)(nil)}},
+	{"getspendpolicy", []interface{}{(*walletjson.GetSpendPolicyResult)(nil)}},
+	{"getuptimestats", []interface{}{(*walletjson.GetUptimeStatsResult)(nil)}},
+	{"getvaultaddress", returnsString},
+	{"getwalletactivity", []interface{}{(*[]walletjson.WalletActivityResult)(nil)}},
 	{"getunconfirmedbalance", returnsNumber},
+	{"importlbrycrdwallet", []interface{}{(*walletjson.ImportLbrycrdWalletResult)(nil)}},
+	{"importlbrysdkwallet", []interface{}{(*walletjson.ImportLbrySDKWalletResult)(nil)}},
+	{"importwatchpubkey", returnsString},
+	{"listaccountclaims", []interface{}{(*[]walletjson.AccountClaimResult)(nil)}},
+	{"listaccountinfo", []interface{}{(*[]walletjson.AccountInfoResult)(nil)}},
+	{"listaccountunspent", []interface{}{(*[]walletjson.ListUnspentResult)(nil)}},
 	{"listaddresstransactions", returnsLTRArray},
 	{"listalltransactions", returnsLTRArray},
+	{"listbalancemoves", []interface{}{(*[]walletjson.BalanceMoveResult)(nil)}},
+	{"listdescriptors", []interface{}{(*walletjson.ListDescriptorsResult)(nil)}},
+	{"listimportedaccounts", []interface{}{(*[]walletjson.ImportedAccountResult)(nil)}},
+	{"listinvoices", []interface{}{(*[]walletjson.InvoiceResult)(nil)}},
+	{"listtainted", []interface{}{(*walletjson.ListTaintedResult)(nil)}},
+	{"listvaults", []interface{}{(*[]walletjson.VaultResult)(nil)}},
+	{"listwallets", returnsStringArray},
+	{"listwithdrawals", []interface{}{(*[]walletjson.WithdrawalResult)(nil)}},
+	{"loadwallet", []interface{}{(*btcjson.LoadWalletResult)(nil)}},
+	{"movebalance", []interface{}{(*walletjson.BalanceMoveResult)(nil)}},
+	{"notifyconfirmations", returnsNumber},
+	{"publishpsbt", returnsString},
+	{"recovervault", returnsString},
 	{"renameaccount", nil},
+	{"requesttestcoins", []interface{}{(*walletjson.RequestTestCoinsResult)(nil)}},
 	{"rescanblockchain", []interface{}{(*btcjson.RescanBlockchainResult)(nil)}},
+	{"rescanimportedaccount", nil},
+	{"sendfromvault", returnsString},
+	{"setimportedaccount", nil},
+	{"setspendpolicy", nil},
+	{"signpsbt", []interface{}{(*walletjson.SignPsbtResult)(nil)}},
+	{"sweepaccountpsbt", []interface{}{(*walletjson.SweepAccountPsbtResult)(nil)}},
+	{"taintaddresses", returnsBool},
+	{"taintunspent", returnsBool},
+	{"stopnotifyconfirmations", nil},
+	{"unloadwallet", nil},
+	{"unwatchaddresses", nil},
+	{"walletgetdata", []interface{}{(*string)(nil), (*map[string]string)(nil)}},
 	{"walletislocked", returnsBool},
+	{"walletsetdata", nil},
+	{"watchaddresses", nil},
 }
 
 // HelpDescs contains the locale-specific help strings along with the locale.
@@ -0,0 +1,240 @@
+// Package shamir splits secrets into shares with Shamir's secret sharing over
+// GF(256), in the style of SLIP-0039: any threshold of the shares of a secret
+// recover it, while fewer reveal nothing about it.  Each share records the
+// identifier of its set and its threshold, and its encoding carries a checksum
+// detecting mistyped shares, while a digest of the secret split along with it
+// detects shares of different sets.
+package shamir
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcwallet/internal/zero"
+)
+
+const (
+	// MaxShares is the maximum number of shares of a secret.
+	MaxShares = 16
+
+	// Prefix starts the encoding of every share.
+	Prefix = "lbcshare1"
+
+	// digestSize is the size of the digest of the secret appended to it
+	// before it is split.
+	digestSize = 4
+
+	// checksumSize is the size of the checksum ending the encoding of a
+	// share.
+	checksumSize = 4
+
+	// headerSize is the size of the identifier, threshold and index
+	// starting the encoding of a share.
+	headerSize = 4
+)
+
+// Errors returned when splitting and combining shares.
+var (
+	ErrThreshold = fmt.Errorf("the threshold must be between 1 and the "+
+		"number of shares, which is at most %d", MaxShares)
+	ErrEncoding  = errors.New("invalid share encoding")
+	ErrChecksum  = errors.New("invalid share checksum")
+	ErrMismatch  = errors.New("the shares are not of the same secret")
+	ErrTooFew    = errors.New("not enough shares to recover the secret")
+	ErrDuplicate = errors.New("duplicate share")
+)
+
+// Share is a share of a secret.
+type Share struct {
+	// ID identifies the set of shares of the secret.
+	ID uint16
+
+	// Threshold is the number of shares recovering the secret.
+	Threshold uint8
+
+	// Index is the x coordinate of the share, starting at 1.
+	Index uint8
+
+	// Value holds a point of the polynomial of each byte of the secret,
+	// followed by those of its digest.
+	Value []byte
+}
+
+// expTable and logTable are the exponent and logarithm tables of GF(256),
+// with the reduction polynomial x^8 + x^4 + x^3 + x + 1 and the generator
+// x + 1.
+var expTable, logTable [256]byte
+
+func init() {
+	x := byte(1)
+	for i := 0; i < 255; i++ {
+		expTable[i] = x
+		logTable[x] = byte(i)
+
+		// Multiply by the generator x + 1.
+		hi := x & 0x80
+		x2 := x << 1
+		if hi != 0 {
+			x2 ^= 0x1b
+		}
+		x ^= x2
+	}
+	expTable[255] = expTable[0]
+}
+
+// mul multiplies two elements of GF(256).
+func mul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return expTable[(int(logTable[a])+int(logTable[b]))%255]
+}
+
+// div divides an element of GF(256) by another, which must not be zero.
+func div(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+	return expTable[(int(logTable[a])+255-int(logTable[b]))%255]
+}
+
+// digest returns the digest of a secret split along with it.
+func digest(secret []byte) []byte {
+	sum := sha256.Sum256(secret)
+	return sum[:digestSize]
+}
+
+// Split splits a secret into count shares, any threshold of which recover it.
+func Split(secret []byte, threshold, count int) ([]Share, error) {
+	if threshold < 1 || threshold > count || count > MaxShares {
+		return nil, ErrThreshold
+	}
+
+	var id [2]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return nil, err
+	}
+	data := append(append([]byte{}, secret...), digest(secret)...)
+	defer zero.Bytes(data)
+
+	shares := make([]Share, count)
+	for i := range shares {
+		shares[i] = Share{
+			ID:        binary.BigEndian.Uint16(id[:]),
+			Threshold: uint8(threshold),
+			Index:     uint8(i + 1),
+			Value:     make([]byte, len(data)),
+		}
+	}
+
+	// Each byte of the data is the constant term of a random polynomial
+	// of degree threshold-1, which is evaluated at the index of each
+	// share.
+	coeffs := make([]byte, threshold-1)
+	defer zero.Bytes(coeffs)
+	for j, b := range data {
+		if _, err := rand.Read(coeffs); err != nil {
+			return nil, err
+		}
+		for i := range shares {
+			x := shares[i].Index
+			y := byte(0)
+			for k := len(coeffs) - 1; k >= 0; k-- {
+				y = mul(y, x) ^ coeffs[k]
+			}
+			shares[i].Value[j] = mul(y, x) ^ b
+		}
+	}
+	return shares, nil
+}
+
+// Combine recovers the secret of the shares, which must include at least the
+// threshold of distinct shares of the same set.
+func Combine(shares []Share) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, ErrTooFew
+	}
+	first := shares[0]
+	if first.Threshold == 0 || len(first.Value) <= digestSize {
+		return nil, ErrEncoding
+	}
+	seen := make(map[uint8]bool, len(shares))
+	for _, s := range shares {
+		if s.ID != first.ID || s.Threshold != first.Threshold ||
+			len(s.Value) != len(first.Value) {
+
+			return nil, ErrMismatch
+		}
+		if s.Index == 0 || seen[s.Index] {
+			return nil, ErrDuplicate
+		}
+		seen[s.Index] = true
+	}
+	if len(shares) < int(first.Threshold) {
+		return nil, ErrTooFew
+	}
+	shares = shares[:first.Threshold]
+
+	// Interpolate the polynomials at zero with the Lagrange basis, where
+	// subtraction is addition in GF(256).
+	data := make([]byte, len(first.Value))
+	for i, s := range shares {
+		basis := byte(1)
+		for k, o := range shares {
+			if k != i {
+				basis = mul(basis, div(o.Index, o.Index^s.Index))
+			}
+		}
+		for j, y := range s.Value {
+			data[j] ^= mul(basis, y)
+		}
+	}
+
+	secret := data[:len(data)-digestSize]
+	if !bytes.Equal(digest(secret), data[len(secret):]) {
+		zero.Bytes(data)
+		return nil, ErrMismatch
+	}
+	return secret, nil
+}
+
+// String encodes the share as the prefix followed by the hex encoding of its
+// identifier, threshold, index and value, and of a checksum.
+func (s Share) String() string {
+	b := make([]byte, headerSize, headerSize+len(s.Value)+checksumSize)
+	binary.BigEndian.PutUint16(b, s.ID)
+	b[2] = s.Threshold
+	b[3] = s.Index
+	b = append(b, s.Value...)
+	b = append(b, chainhash.DoubleHashB(b)[:checksumSize]...)
+	return Prefix + hex.EncodeToString(b)
+}
+
+// Parse decodes a share encoded by String.
+func Parse(s string) (Share, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if !strings.HasPrefix(s, Prefix) {
+		return Share{}, ErrEncoding
+	}
+	b, err := hex.DecodeString(s[len(Prefix):])
+	if err != nil || len(b) <= headerSize+digestSize+checksumSize {
+		return Share{}, ErrEncoding
+	}
+	n := len(b) - checksumSize
+	if !bytes.Equal(chainhash.DoubleHashB(b[:n])[:checksumSize], b[n:]) {
+		return Share{}, ErrChecksum
+	}
+	return Share{
+		ID:        binary.BigEndian.Uint16(b),
+		Threshold: b[2],
+		Index:     b[3],
+		Value:     b[headerSize:n],
+	}, nil
+}
@@ -0,0 +1,121 @@
+package shamir
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSplitCombine(t *testing.T) {
+	t.Parallel()
+
+	secret := bytes.Repeat([]byte{0xa5, 0x00, 0xff, 0x17}, 8)
+	for _, test := range []struct {
+		threshold, count int
+	}{
+		{1, 1},
+		{1, 3},
+		{2, 3},
+		{3, 5},
+		{MaxShares, MaxShares},
+	} {
+		shares, err := Split(secret, test.threshold, test.count)
+		if err != nil {
+			t.Fatalf("%d-of-%d: Split: %v", test.threshold,
+				test.count, err)
+		}
+		if len(shares) != test.count {
+			t.Fatalf("%d-of-%d: got %d shares", test.threshold,
+				test.count, len(shares))
+		}
+
+		// Any threshold of the shares, in any order, recover the
+		// secret.
+		for i := 0; i+test.threshold <= test.count; i++ {
+			subset := make([]Share, test.threshold)
+			for j := range subset {
+				subset[j] = shares[i+test.threshold-1-j]
+			}
+			got, err := Combine(subset)
+			if err != nil {
+				t.Fatalf("%d-of-%d: Combine: %v", test.threshold,
+					test.count, err)
+			}
+			if !bytes.Equal(got, secret) {
+				t.Fatalf("%d-of-%d: got %x, want %x",
+					test.threshold, test.count, got, secret)
+			}
+		}
+
+		if test.threshold > 1 {
+			_, err := Combine(shares[:test.threshold-1])
+			if err != ErrTooFew {
+				t.Fatalf("%d-of-%d: got %v, want ErrTooFew",
+					test.threshold, test.count, err)
+			}
+		}
+	}
+
+	if _, err := Split(secret, 3, 2); err != ErrThreshold {
+		t.Fatalf("got %v, want ErrThreshold", err)
+	}
+	if _, err := Split(secret, 2, MaxShares+1); err != ErrThreshold {
+		t.Fatalf("got %v, want ErrThreshold", err)
+	}
+}
+
+func TestCombineMismatch(t *testing.T) {
+	t.Parallel()
+
+	secret := []byte("a secret of sixteen bytes or so")
+	a, err := Split(secret, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Split(secret, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Shares of different sets of the same secret do not combine, even
+	// when their identifiers collide.
+	b[1].ID = a[0].ID
+	if _, err := Combine([]Share{a[0], b[1]}); err != ErrMismatch {
+		t.Fatalf("got %v, want ErrMismatch", err)
+	}
+	if _, err := Combine([]Share{a[0], a[0]}); err != ErrDuplicate {
+		t.Fatalf("got %v, want ErrDuplicate", err)
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	t.Parallel()
+
+	shares, err := Split([]byte("0123456789abcdef"), 2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := shares[0].String()
+	parsed, err := Parse(" " + s + "\n")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if parsed.ID != shares[0].ID || parsed.Threshold != 2 ||
+		parsed.Index != 1 || !bytes.Equal(parsed.Value, shares[0].Value) {
+
+		t.Fatalf("got %+v, want %+v", parsed, shares[0])
+	}
+
+	// A mistyped character is detected by the checksum.
+	typo := []byte(s)
+	if typo[len(Prefix)+10] == '0' {
+		typo[len(Prefix)+10] = '1'
+	} else {
+		typo[len(Prefix)+10] = '0'
+	}
+	if _, err := Parse(string(typo)); err != ErrChecksum {
+		t.Fatalf("got %v, want ErrChecksum", err)
+	}
+	if _, err := Parse("0123456789abcdef"); err != ErrEncoding {
+		t.Fatalf("got %v, want ErrEncoding", err)
+	}
+}
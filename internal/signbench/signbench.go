@@ -0,0 +1,169 @@
+// Package signbench measures the throughput of the key derivations, signatures
+// and signature verifications the wallet performs to create transactions.
+package signbench
+
+import (
+	"context"
+	"time"
+
+	"github.com/lbryio/lbcd/chaincfg"
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcutil/hdkeychain"
+)
+
+// Result holds the throughput of each operation, in operations per second of
+// a single core.
+type Result struct {
+	// Derivations is the rate of the normal derivations of address keys
+	// from a branch key, and HardenedDerivations the rate of the hardened
+	// derivations of account keys.
+	Derivations         float64
+	HardenedDerivations float64
+
+	// Signatures is the rate of the signatures of p2wkh inputs, and
+	// Verifications the rate of their script validations.
+	Signatures    float64
+	Verifications float64
+
+	// TxRate is the max rate of the transactions spending Inputs inputs
+	// which can be created, each input needing the derivation of its key,
+	// a signature and its validation, and each transaction the derivation
+	// of a change address.
+	TxRate float64
+	Inputs int
+}
+
+// Run measures the throughput of each operation for a quarter of d with keys
+// derived from a random seed, and the rate of the transactions spending inputs
+// inputs it allows.  It returns early with the error of ctx when canceled.
+func Run(ctx context.Context, d time.Duration, inputs int) (*Result,
+	error) {
+
+	seed, err := hdkeychain.GenerateSeed(hdkeychain.RecommendedSeedLen)
+	if err != nil {
+		return nil, err
+	}
+	master, err := hdkeychain.NewMaster(seed, &chaincfg.MainNetParams)
+	if err != nil {
+		return nil, err
+	}
+	defer master.Zero()
+	account := master
+	for _, i := range []uint32{84, 140, 0} {
+		account, err = account.Derive(hdkeychain.HardenedKeyStart + i)
+		if err != nil {
+			return nil, err
+		}
+	}
+	branch, err := account.Derive(0)
+	if err != nil {
+		return nil, err
+	}
+
+	// The signed transaction spends a p2wkh output of the first address
+	// key to two outputs, as a payment with change.
+	key, err := branch.Derive(0)
+	if err != nil {
+		return nil, err
+	}
+	privKey, err := key.ECPrivKey()
+	if err != nil {
+		return nil, err
+	}
+	pubKeyHash := btcutil.Hash160(privKey.PubKey().SerializeCompressed())
+	pkScript, err := txscript.NewScriptBuilder().AddOp(txscript.OP_0).
+		AddData(pubKeyHash).Script()
+	if err != nil {
+		return nil, err
+	}
+	const amount = 1e8
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{}, nil, nil))
+	tx.AddTxOut(wire.NewTxOut(amount/2, pkScript))
+	tx.AddTxOut(wire.NewTxOut(amount/2-1000, pkScript))
+	sigHashes := txscript.NewTxSigHashes(tx)
+
+	step := d / 4
+	var result Result
+	var i uint32
+	result.Derivations, err = measure(ctx, step, func() error {
+		i++
+		child, err := branch.Derive(i)
+		if err != nil {
+			return err
+		}
+		_, err = child.ECPrivKey()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	i = 0
+	result.HardenedDerivations, err = measure(ctx, step, func() error {
+		i++
+		_, err := account.Derive(hdkeychain.HardenedKeyStart + i)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Signatures, err = measure(ctx, step, func() error {
+		witness, err := txscript.WitnessSignature(
+			tx, sigHashes, 0, amount, pkScript,
+			txscript.SigHashAll, privKey, true,
+		)
+		tx.TxIn[0].Witness = witness
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Verifications, err = measure(ctx, step, func() error {
+		vm, err := txscript.NewEngine(
+			pkScript, tx, 0, txscript.StandardVerifyFlags, nil,
+			sigHashes, amount,
+		)
+		if err != nil {
+			return err
+		}
+		return vm.Execute()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Inputs = inputs
+	perInput := 1/result.Derivations + 1/result.Signatures +
+		1/result.Verifications
+	result.TxRate = 1 / (float64(inputs)*perInput + 1/result.Derivations)
+	return &result, nil
+}
+
+// measure calls op repeatedly for at least d, and returns the number of calls
+// per second.
+func measure(ctx context.Context, d time.Duration, op func() error) (float64,
+	error) {
+
+	start := time.Now()
+	var n int
+	for {
+		if err := op(); err != nil {
+			return 0, err
+		}
+		n++
+
+		// The clock and ctx are only checked every few calls, so that
+		// they add nothing noticeable to the measured time.
+		if n%16 != 0 {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		if elapsed := time.Since(start); elapsed >= d {
+			return float64(n) / elapsed.Seconds(), nil
+		}
+	}
+}
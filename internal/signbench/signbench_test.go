@@ -0,0 +1,29 @@
+package signbench
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	result, err := Run(context.Background(), 40*time.Millisecond, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Derivations <= 0 || result.HardenedDerivations <= 0 ||
+		result.Signatures <= 0 || result.Verifications <= 0 {
+
+		t.Fatalf("missing rates: %+v", result)
+	}
+	if result.TxRate <= 0 || result.TxRate >= result.Signatures/2 {
+		t.Fatalf("tx rate %v not below half the signature rate %v",
+			result.TxRate, result.Signatures)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Run(ctx, time.Minute, 1); err != context.Canceled {
+		t.Fatalf("canceled run returned %v", err)
+	}
+}
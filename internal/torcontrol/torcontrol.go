@@ -0,0 +1,338 @@
+// Package torcontrol implements the client side of the Tor control protocol,
+// enough to authenticate to the control port of a Tor process, create
+// ephemeral onion services and look up its SOCKS listeners.
+package torcontrol
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/textproto"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// dialTimeout bounds the time to open the control connection.
+	dialTimeout = 10 * time.Second
+
+	// commandTimeout bounds the time Tor takes to answer a command.
+	commandTimeout = 30 * time.Second
+
+	// The keys of the HMACs of the SAFECOOKIE authentication.
+	serverHashKey = "Tor safe cookie authentication server-to-controller hash"
+	clientHashKey = "Tor safe cookie authentication controller-to-server hash"
+)
+
+// ErrNoAuthMethod is returned when Tor offers no authentication method which
+// can be used with the given credentials.
+var ErrNoAuthMethod = errors.New("no usable Tor control authentication method")
+
+// Error is a reply of Tor to a command which failed.
+type Error struct {
+	Code int
+	Msg  string
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return fmt.Sprintf("tor control error %d: %s", e.Code, e.Msg)
+}
+
+// Conn is an authenticated connection to the control port of Tor.  The
+// onion services it creates are removed by Tor when it is closed.  It is not
+// safe for concurrent use.
+type Conn struct {
+	conn net.Conn
+	r    *textproto.Reader
+}
+
+// Dial connects to the control port of Tor at addr and authenticates, with
+// the password when it is not empty, or else with the authentication cookie
+// or no credentials, as Tor allows.
+func Dial(addr, password string) (*Conn, error) {
+	conn, err := net.DialTimeout("tcp", addr, dialTimeout)
+	if err != nil {
+		return nil, err
+	}
+	c := newConn(conn)
+	if err := c.authenticate(password); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// newConn returns a connection speaking the control protocol over conn, which
+// is not authenticated yet.
+func newConn(conn net.Conn) *Conn {
+	return &Conn{conn: conn, r: textproto.NewReader(bufio.NewReader(conn))}
+}
+
+// Close closes the connection.
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
+
+// command sends a command and returns the lines of its reply, without their
+// status codes.
+func (c *Conn) command(cmd string) ([]string, error) {
+	_ = c.conn.SetDeadline(time.Now().Add(commandTimeout))
+	defer func() { _ = c.conn.SetDeadline(time.Time{}) }()
+
+	if _, err := c.conn.Write([]byte(cmd + "\r\n")); err != nil {
+		return nil, err
+	}
+	var lines []string
+	for {
+		line, err := c.r.ReadLine()
+		if err != nil {
+			return nil, err
+		}
+		if len(line) < 4 {
+			return nil, fmt.Errorf("invalid tor control reply %q",
+				line)
+		}
+		code, err := strconv.Atoi(line[:3])
+		if err != nil {
+			return nil, fmt.Errorf("invalid tor control reply %q",
+				line)
+		}
+		text := line[4:]
+		if code != 250 {
+			return nil, &Error{Code: code, Msg: text}
+		}
+		switch line[3] {
+		case ' ':
+			return append(lines, text), nil
+		case '-':
+			lines = append(lines, text)
+		case '+':
+			// The data following the line is joined to it.
+			data, err := c.r.ReadDotLines()
+			if err != nil {
+				return nil, err
+			}
+			lines = append(lines, text+strings.Join(data, "\n"))
+		default:
+			return nil, fmt.Errorf("invalid tor control reply %q",
+				line)
+		}
+	}
+}
+
+// authenticate authenticates with the first method offered by Tor which can
+// be used.
+func (c *Conn) authenticate(password string) error {
+	lines, err := c.command("PROTOCOLINFO 1")
+	if err != nil {
+		return err
+	}
+	methods := make(map[string]bool)
+	var cookieFile string
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "AUTH ") {
+			continue
+		}
+		fields := parseKeywords(line[len("AUTH "):])
+		for _, m := range strings.Split(fields["METHODS"], ",") {
+			methods[m] = true
+		}
+		cookieFile = fields["COOKIEFILE"]
+	}
+
+	switch {
+	case methods["NULL"]:
+		_, err = c.command("AUTHENTICATE")
+
+	case password != "" && methods["HASHEDPASSWORD"]:
+		_, err = c.command("AUTHENTICATE " +
+			hex.EncodeToString([]byte(password)))
+
+	case methods["SAFECOOKIE"] && cookieFile != "":
+		err = c.authenticateSafeCookie(cookieFile)
+
+	case methods["COOKIE"] && cookieFile != "":
+		var cookie []byte
+		cookie, err = os.ReadFile(cookieFile)
+		if err == nil {
+			_, err = c.command("AUTHENTICATE " +
+				hex.EncodeToString(cookie))
+		}
+
+	default:
+		err = ErrNoAuthMethod
+	}
+	return err
+}
+
+// authenticateSafeCookie proves the knowledge of the authentication cookie
+// without sending it, and checks that Tor knows it as well.
+func (c *Conn) authenticateSafeCookie(cookieFile string) error {
+	cookie, err := os.ReadFile(cookieFile)
+	if err != nil {
+		return err
+	}
+	var clientNonce [32]byte
+	if _, err := rand.Read(clientNonce[:]); err != nil {
+		return err
+	}
+	lines, err := c.command("AUTHCHALLENGE SAFECOOKIE " +
+		hex.EncodeToString(clientNonce[:]))
+	if err != nil {
+		return err
+	}
+	fields := parseKeywords(
+		strings.TrimPrefix(lines[0], "AUTHCHALLENGE "),
+	)
+	serverHash, err := hex.DecodeString(fields["SERVERHASH"])
+	if err != nil {
+		return err
+	}
+	serverNonce, err := hex.DecodeString(fields["SERVERNONCE"])
+	if err != nil {
+		return err
+	}
+
+	msg := append(append(append([]byte{}, cookie...), clientNonce[:]...),
+		serverNonce...)
+	if !hmac.Equal(serverHash, safeCookieHash(serverHashKey, msg)) {
+		return errors.New("tor does not know the authentication cookie")
+	}
+	_, err = c.command("AUTHENTICATE " +
+		hex.EncodeToString(safeCookieHash(clientHashKey, msg)))
+	return err
+}
+
+// safeCookieHash returns an HMAC of the SAFECOOKIE authentication.
+func safeCookieHash(key string, msg []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write(msg)
+	return mac.Sum(nil)
+}
+
+// GetInfo returns the value of a GETINFO key.
+func (c *Conn) GetInfo(key string) (string, error) {
+	lines, err := c.command("GETINFO " + key)
+	if err != nil {
+		return "", err
+	}
+	for _, line := range lines {
+		if strings.HasPrefix(line, key+"=") {
+			return line[len(key)+1:], nil
+		}
+	}
+	return "", fmt.Errorf("tor did not return %s", key)
+}
+
+// SOCKSListeners returns the TCP addresses of the SOCKS ports of Tor.
+func (c *Conn) SOCKSListeners() ([]string, error) {
+	value, err := c.GetInfo("net/listeners/socks")
+	if err != nil {
+		return nil, err
+	}
+	var addrs []string
+	for _, quoted := range strings.Fields(value) {
+		addr := unquote(quoted)
+		if strings.HasPrefix(addr, "unix:") {
+			continue
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs, nil
+}
+
+// OnionPort maps a virtual port of an onion service to the address Tor
+// connects to.
+type OnionPort struct {
+	Virtual uint16
+	Target  string
+}
+
+// AddOnion creates an ephemeral onion service with a new key for the ports,
+// which lasts until the connection is closed, and returns its address.
+func (c *Conn) AddOnion(ports []OnionPort) (string, error) {
+	if len(ports) == 0 {
+		return "", errors.New("an onion service needs a port")
+	}
+	var cmd bytes.Buffer
+	cmd.WriteString("ADD_ONION NEW:ED25519-V3 Flags=DiscardPK")
+	for _, p := range ports {
+		fmt.Fprintf(&cmd, " Port=%d,%s", p.Virtual, p.Target)
+	}
+	lines, err := c.command(cmd.String())
+	if err != nil {
+		return "", err
+	}
+	for _, line := range lines {
+		if strings.HasPrefix(line, "ServiceID=") {
+			return line[len("ServiceID="):] + ".onion", nil
+		}
+	}
+	return "", errors.New("tor did not return the onion service ID")
+}
+
+// parseKeywords parses the space separated KEY=VALUE pairs of a reply line,
+// whose values may be quoted.
+func parseKeywords(s string) map[string]string {
+	fields := make(map[string]string)
+	for len(s) > 0 {
+		s = strings.TrimLeft(s, " ")
+		eq := strings.IndexByte(s, '=')
+		if eq < 0 {
+			break
+		}
+		key := s[:eq]
+		s = s[eq+1:]
+
+		var value string
+		if strings.HasPrefix(s, `"`) {
+			// Find the closing quote, skipping escaped characters.
+			end := 1
+			for end < len(s) && s[end] != '"' {
+				if s[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(s) {
+				end = len(s) - 1
+			}
+			value = unquote(s[:end+1])
+			s = s[end+1:]
+		} else {
+			sp := strings.IndexByte(s, ' ')
+			if sp < 0 {
+				sp = len(s)
+			}
+			value = s[:sp]
+			s = s[sp:]
+		}
+		fields[key] = value
+	}
+	return fields
+}
+
+// unquote removes the quotes and escapes of a quoted string, and returns
+// other strings unchanged.
+func unquote(s string) string {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return s
+	}
+	var b strings.Builder
+	for i := 1; i < len(s)-1; i++ {
+		if s[i] == '\\' && i+1 < len(s)-1 {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,178 @@
+package torcontrol
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeTor answers the commands of a controller on conn like Tor, with the
+// given authentication methods and cookie, until the controller closes it.
+// The commands it received are sent to cmds.
+func fakeTor(conn net.Conn, methods, cookieFile string, cookie []byte,
+	cmds chan<- string) {
+
+	defer close(cmds)
+	r := bufio.NewReader(conn)
+	var clientNonce, serverNonce []byte
+	serverNonce = make([]byte, 32)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmds <- line
+
+		var reply string
+		switch fields := strings.Fields(line); {
+		case line == "PROTOCOLINFO 1":
+			reply = fmt.Sprintf("250-PROTOCOLINFO 1\r\n"+
+				"250-AUTH METHODS=%s COOKIEFILE=%q\r\n"+
+				"250-VERSION Tor=\"0.4.8.9\"\r\n250 OK\r\n",
+				methods, cookieFile)
+
+		case fields[0] == "AUTHCHALLENGE":
+			clientNonce, _ = hex.DecodeString(fields[2])
+			msg := append(append(append([]byte{}, cookie...),
+				clientNonce...), serverNonce...)
+			reply = fmt.Sprintf("250 AUTHCHALLENGE SERVERHASH=%x "+
+				"SERVERNONCE=%x\r\n",
+				safeCookieHash(serverHashKey, msg), serverNonce)
+
+		case fields[0] == "AUTHENTICATE":
+			reply = "250 OK\r\n"
+			if strings.Contains(methods, "SAFECOOKIE") {
+				msg := append(append(append([]byte{}, cookie...),
+					clientNonce...), serverNonce...)
+				want := hex.EncodeToString(
+					safeCookieHash(clientHashKey, msg))
+				if len(fields) != 2 || fields[1] != want {
+					reply = "515 Authentication failed\r\n"
+				}
+			}
+
+		case line == "GETINFO net/listeners/socks":
+			reply = "250-net/listeners/socks=\"127.0.0.1:9050\" " +
+				"\"unix:/run/tor/socks\"\r\n250 OK\r\n"
+
+		case fields[0] == "ADD_ONION":
+			reply = "250-ServiceID=abcdef\r\n250 OK\r\n"
+
+		default:
+			reply = "510 Unrecognized command\r\n"
+		}
+		if _, err := conn.Write([]byte(reply)); err != nil {
+			return
+		}
+	}
+}
+
+// TestSafeCookie checks the SAFECOOKIE authentication, and the commands run
+// once authenticated.
+func TestSafeCookie(t *testing.T) {
+	t.Parallel()
+
+	cookie := []byte("0123456789abcdef0123456789abcdef")
+	cookieFile := filepath.Join(t.TempDir(), "control_auth_cookie")
+	if err := os.WriteFile(cookieFile, cookie, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	client, server := net.Pipe()
+	cmds := make(chan string, 16)
+	go fakeTor(server, "COOKIE,SAFECOOKIE", cookieFile, cookie, cmds)
+
+	c := newConn(client)
+	if err := c.authenticate(""); err != nil {
+		t.Fatal(err)
+	}
+
+	socks, err := c.SOCKSListeners()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(socks) != 1 || socks[0] != "127.0.0.1:9050" {
+		t.Fatalf("SOCKS listeners %v", socks)
+	}
+
+	onion, err := c.AddOnion([]OnionPort{
+		{Virtual: 9244, Target: "127.0.0.1:9244"},
+		{Virtual: 50001, Target: "127.0.0.1:50001"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if onion != "abcdef.onion" {
+		t.Fatalf("onion address %s", onion)
+	}
+	c.Close()
+
+	var last string
+	for cmd := range cmds {
+		last = cmd
+	}
+	want := "ADD_ONION NEW:ED25519-V3 Flags=DiscardPK " +
+		"Port=9244,127.0.0.1:9244 Port=50001,127.0.0.1:50001"
+	if last != want {
+		t.Fatalf("sent %q, want %q", last, want)
+	}
+}
+
+// TestAuthFailure checks that a wrong cookie is refused, and that a password
+// is sent when Tor asks for one.
+func TestAuthFailure(t *testing.T) {
+	t.Parallel()
+
+	cookieFile := filepath.Join(t.TempDir(), "control_auth_cookie")
+	if err := os.WriteFile(cookieFile, []byte("wrong"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	client, server := net.Pipe()
+	go fakeTor(server, "SAFECOOKIE", cookieFile, []byte("right"),
+		make(chan string, 16))
+	c := newConn(client)
+	if err := c.authenticate(""); err == nil {
+		t.Fatal("authenticated with a wrong cookie")
+	}
+	c.Close()
+
+	client, server = net.Pipe()
+	cmds := make(chan string, 16)
+	go fakeTor(server, "HASHEDPASSWORD", "", nil, cmds)
+	c = newConn(client)
+	if err := c.authenticate(""); err != ErrNoAuthMethod {
+		t.Fatalf("authenticated without a password: %v", err)
+	}
+	if err := c.authenticate("secret"); err != nil {
+		t.Fatal(err)
+	}
+	c.Close()
+
+	var last string
+	for cmd := range cmds {
+		last = cmd
+	}
+	if last != "AUTHENTICATE "+hex.EncodeToString([]byte("secret")) {
+		t.Fatalf("sent %q", last)
+	}
+}
+
+// TestParseKeywords checks the parsing of quoted reply values.
+func TestParseKeywords(t *testing.T) {
+	t.Parallel()
+
+	fields := parseKeywords(`METHODS=COOKIE,SAFECOOKIE ` +
+		`COOKIEFILE="/var/lib/tor/a \"b\"\\c"`)
+	if fields["METHODS"] != "COOKIE,SAFECOOKIE" {
+		t.Fatalf("METHODS=%q", fields["METHODS"])
+	}
+	if fields["COOKIEFILE"] != `/var/lib/tor/a "b"\c` {
+		t.Fatalf("COOKIEFILE=%q", fields["COOKIEFILE"])
+	}
+}
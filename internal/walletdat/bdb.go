@@ -0,0 +1,267 @@
+package walletdat
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Berkeley DB btree page types and item types.
+const (
+	pageInternal  = 3 // P_IBTREE
+	pageLeaf      = 5 // P_LBTREE
+	pageOverflow  = 7 // P_OVERFLOW
+	pageBtreeMeta = 9 // P_BTREEMETA
+
+	itemKeyData  = 1 // B_KEYDATA
+	itemOverflow = 3 // B_OVERFLOW
+	itemDeleted  = 0x80
+
+	btreeMagic = 0x053162
+
+	pageHeaderSize = 26
+	minPageSize    = 512
+	maxPageSize    = 65536
+
+	// mainDatabase is the name of the database of the records of a
+	// wallet in the wallet.dat file.
+	mainDatabase = "main"
+)
+
+// ErrNotBerkeleyDB is returned for files which are not Berkeley DB btree
+// databases.
+var ErrNotBerkeleyDB = errors.New("not a Berkeley DB btree database")
+
+// record is a key/value pair of a database.
+type record struct {
+	key, value []byte
+}
+
+// bdbFile reads the pages of a Berkeley DB btree database file.
+type bdbFile struct {
+	b        []byte
+	order    binary.ByteOrder
+	pageSize uint32
+}
+
+// openBDB checks the metadata page of a database file, whose byte order is
+// that of the machine which wrote it.
+func openBDB(b []byte) (*bdbFile, error) {
+	if len(b) < minPageSize {
+		return nil, ErrNotBerkeleyDB
+	}
+	f := &bdbFile{b: b}
+	switch {
+	case binary.LittleEndian.Uint32(b[12:16]) == btreeMagic:
+		f.order = binary.LittleEndian
+	case binary.BigEndian.Uint32(b[12:16]) == btreeMagic:
+		f.order = binary.BigEndian
+	default:
+		return nil, ErrNotBerkeleyDB
+	}
+	f.pageSize = f.order.Uint32(b[20:24])
+	if f.pageSize < minPageSize || f.pageSize > maxPageSize ||
+		f.pageSize&(f.pageSize-1) != 0 {
+
+		return nil, ErrNotBerkeleyDB
+	}
+	if b[24] != 0 {
+		return nil, errors.New("encrypted Berkeley DB databases are " +
+			"not supported")
+	}
+	return f, nil
+}
+
+// page returns the page with the given number.
+func (f *bdbFile) page(pgno uint32) ([]byte, error) {
+	off := uint64(pgno) * uint64(f.pageSize)
+	if off+uint64(f.pageSize) > uint64(len(f.b)) {
+		return nil, fmt.Errorf("page %d is past the end of the file",
+			pgno)
+	}
+	return f.b[off : off+uint64(f.pageSize)], nil
+}
+
+// records returns the records of the btree whose metadata page has the given
+// number, in key order.
+func (f *bdbFile) records(metaPgno uint32) ([]record, error) {
+	meta, err := f.page(metaPgno)
+	if err != nil {
+		return nil, err
+	}
+	if meta[25] != pageBtreeMeta {
+		return nil, fmt.Errorf("page %d is not a btree metadata page",
+			metaPgno)
+	}
+	root := f.order.Uint32(meta[88:92])
+
+	var records []record
+	seen := make(map[uint32]bool)
+	err = f.walk(root, seen, &records)
+	return records, err
+}
+
+// walk appends the records of the subtree of a page.
+func (f *bdbFile) walk(pgno uint32, seen map[uint32]bool,
+	records *[]record) error {
+
+	if seen[pgno] {
+		return fmt.Errorf("page %d is referenced twice", pgno)
+	}
+	seen[pgno] = true
+
+	p, err := f.page(pgno)
+	if err != nil {
+		return err
+	}
+	entries := int(f.order.Uint16(p[20:22]))
+	if pageHeaderSize+2*entries > len(p) {
+		return fmt.Errorf("page %d has too many entries", pgno)
+	}
+
+	switch p[25] {
+	case pageInternal:
+		for i := 0; i < entries; i++ {
+			item, err := f.item(p, i)
+			if err != nil {
+				return fmt.Errorf("page %d: %w", pgno, err)
+			}
+			// BINTERNAL: len, type, unused, pgno, nrecs, data.
+			if len(item) < 12 {
+				return fmt.Errorf("page %d: short internal "+
+					"item", pgno)
+			}
+			err = f.walk(f.order.Uint32(item[4:8]), seen, records)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case pageLeaf:
+		if entries%2 != 0 {
+			return fmt.Errorf("page %d has an odd number of "+
+				"entries", pgno)
+		}
+		for i := 0; i < entries; i += 2 {
+			key, keyDeleted, err := f.data(p, i)
+			if err != nil {
+				return fmt.Errorf("page %d: %w", pgno, err)
+			}
+			value, valueDeleted, err := f.data(p, i+1)
+			if err != nil {
+				return fmt.Errorf("page %d: %w", pgno, err)
+			}
+			if keyDeleted || valueDeleted {
+				continue
+			}
+			*records = append(*records, record{key, value})
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("page %d of type %d is not a btree page",
+			pgno, p[25])
+	}
+}
+
+// item returns the bytes of an item of a page, from its offset to the end of
+// the page.
+func (f *bdbFile) item(p []byte, i int) ([]byte, error) {
+	off := int(f.order.Uint16(p[pageHeaderSize+2*i:]))
+	if off < pageHeaderSize || off+3 > len(p) {
+		return nil, fmt.Errorf("item %d is out of the page", i)
+	}
+	return p[off:], nil
+}
+
+// data returns the data of a leaf page item, following overflow pages, and
+// whether it is deleted.
+func (f *bdbFile) data(p []byte, i int) ([]byte, bool, error) {
+	item, err := f.item(p, i)
+	if err != nil {
+		return nil, false, err
+	}
+	deleted := item[2]&itemDeleted != 0
+
+	switch item[2] &^ itemDeleted {
+	case itemKeyData:
+		// BKEYDATA: len, type, data.
+		n := int(f.order.Uint16(item[0:2]))
+		if 3+n > len(item) {
+			return nil, false, fmt.Errorf("item %d overflows the "+
+				"page", i)
+		}
+		return item[3 : 3+n], deleted, nil
+
+	case itemOverflow:
+		// BOVERFLOW: unused, type, unused, pgno, tlen.
+		if len(item) < 12 {
+			return nil, false, fmt.Errorf("short overflow item %d",
+				i)
+		}
+		b, err := f.overflow(f.order.Uint32(item[4:8]),
+			f.order.Uint32(item[8:12]))
+		return b, deleted, err
+
+	default:
+		return nil, false, fmt.Errorf("item %d has unsupported type "+
+			"%d", i, item[2])
+	}
+}
+
+// overflow returns the data of the chain of overflow pages starting at a page.
+func (f *bdbFile) overflow(pgno, length uint32) ([]byte, error) {
+	var buf bytes.Buffer
+	for n := 0; uint32(buf.Len()) < length; n++ {
+		if pgno == 0 || n > len(f.b)/int(f.pageSize) {
+			return nil, errors.New("truncated overflow chain")
+		}
+		p, err := f.page(pgno)
+		if err != nil {
+			return nil, err
+		}
+		if p[25] != pageOverflow {
+			return nil, fmt.Errorf("page %d is not an overflow page",
+				pgno)
+		}
+		// The high free offset of overflow pages is the length of
+		// their data.
+		size := int(f.order.Uint16(p[22:24]))
+		if pageHeaderSize+size > len(p) {
+			return nil, fmt.Errorf("overflow page %d is too long",
+				pgno)
+		}
+		buf.Write(p[pageHeaderSize : pageHeaderSize+size])
+		pgno = f.order.Uint32(p[16:20])
+	}
+	if uint32(buf.Len()) != length {
+		return nil, errors.New("overflow data length mismatch")
+	}
+	return buf.Bytes(), nil
+}
+
+// readRecords returns the records of the main database of a wallet.dat file.
+// The file holds a master btree naming its databases, whose values are the
+// page numbers of the metadata pages of the databases in big-endian order.
+func readRecords(b []byte) ([]record, error) {
+	f, err := openBDB(b)
+	if err != nil {
+		return nil, err
+	}
+	master, err := f.records(0)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range master {
+		if string(r.key) != mainDatabase {
+			continue
+		}
+		if len(r.value) != 4 {
+			return nil, errors.New("invalid main database entry")
+		}
+		return f.records(binary.BigEndian.Uint32(r.value))
+	}
+	return nil, errors.New("no main database")
+}
@@ -0,0 +1,366 @@
+// Package walletdat reads the keys, labels and transactions of the wallet.dat
+// files of lbrycrd, the LBRY node and wallet forked from Bitcoin Core, which
+// are Berkeley DB btree databases.
+package walletdat
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/lbryio/lbcd/btcec"
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcd/wire"
+)
+
+// maxRecordSize bounds the variable length fields of records.
+const maxRecordSize = 1 << 24
+
+// Errors returned when unlocking encrypted wallets.
+var (
+	ErrPassphrase    = errors.New("incorrect wallet passphrase")
+	ErrNotEncrypted  = errors.New("the wallet is not encrypted")
+	ErrKeyDerivation = errors.New("unsupported key derivation method")
+)
+
+// Key is a key of a wallet.
+type Key struct {
+	PubKey     *btcec.PublicKey
+	Compressed bool
+
+	// PrivKey is nil for the keys of an encrypted wallet until it is
+	// unlocked.
+	PrivKey *btcec.PrivateKey
+
+	// CreateTime is the time the key was created, or the zero time when
+	// the wallet did not record it.
+	CreateTime time.Time
+
+	pubKey    []byte
+	encrypted []byte
+}
+
+// masterKey is an encrypted key encrypting the keys of a wallet, decrypted by
+// a key derived from the wallet passphrase.
+type masterKey struct {
+	encrypted  []byte
+	salt       []byte
+	method     uint32
+	iterations uint32
+}
+
+// Wallet is the content of a wallet.dat file.
+type Wallet struct {
+	Keys []*Key
+
+	// Labels maps the addresses of the address book, which are those of
+	// the wallet and those it sent to, to their labels.
+	Labels map[string]string
+
+	// Transactions are the transactions of the wallet.
+	Transactions []*wire.MsgTx
+
+	masterKeys []masterKey
+}
+
+// Parse reads the records of a wallet.dat file.  Records of types other than
+// keys, encrypted keys, key metadata, master keys, address book names and
+// transactions are skipped.
+func Parse(b []byte) (*Wallet, error) {
+	records, err := readRecords(b)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Wallet{Labels: make(map[string]string)}
+	var order []*Key
+	keys := make(map[string]*Key)
+	key := func(pubKey []byte) *Key {
+		k, ok := keys[string(pubKey)]
+		if !ok {
+			k = &Key{pubKey: pubKey}
+			keys[string(pubKey)] = k
+			order = append(order, k)
+		}
+		return k
+	}
+	for _, rec := range records {
+		kr := bytes.NewReader(rec.key)
+		vr := bytes.NewReader(rec.value)
+		typ, err := wire.ReadVarString(kr, 0)
+		if err != nil {
+			continue
+		}
+
+		switch typ {
+		case "key":
+			pubKey, err := readVarBytes(kr)
+			if err != nil {
+				return nil, fmt.Errorf("key record: %w", err)
+			}
+			der, err := readVarBytes(vr)
+			if err != nil {
+				return nil, fmt.Errorf("key record: %w", err)
+			}
+			secret, err := parseDERPrivKey(der)
+			if err != nil {
+				return nil, fmt.Errorf("key record: %w", err)
+			}
+			k := key(pubKey)
+			k.PrivKey, _ = btcec.PrivKeyFromBytes(btcec.S256(), secret)
+
+		case "ckey":
+			pubKey, err := readVarBytes(kr)
+			if err != nil {
+				return nil, fmt.Errorf("ckey record: %w", err)
+			}
+			encrypted, err := readVarBytes(vr)
+			if err != nil {
+				return nil, fmt.Errorf("ckey record: %w", err)
+			}
+			key(pubKey).encrypted = encrypted
+
+		case "keymeta":
+			pubKey, err := readVarBytes(kr)
+			if err != nil {
+				return nil, fmt.Errorf("keymeta record: %w", err)
+			}
+			var meta struct {
+				Version    int32
+				CreateTime int64
+			}
+			err = binary.Read(vr, binary.LittleEndian, &meta)
+			if err != nil {
+				return nil, fmt.Errorf("keymeta record: %w", err)
+			}
+			if meta.CreateTime > 1 {
+				key(pubKey).CreateTime = time.Unix(meta.CreateTime, 0)
+			}
+
+		case "mkey":
+			var mk masterKey
+			mk.encrypted, err = readVarBytes(vr)
+			if err == nil {
+				mk.salt, err = readVarBytes(vr)
+			}
+			if err == nil {
+				err = binary.Read(vr, binary.LittleEndian, &mk.method)
+			}
+			if err == nil {
+				err = binary.Read(vr, binary.LittleEndian,
+					&mk.iterations)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("mkey record: %w", err)
+			}
+			w.masterKeys = append(w.masterKeys, mk)
+
+		case "name":
+			addr, err := wire.ReadVarString(kr, 0)
+			if err != nil {
+				return nil, fmt.Errorf("name record: %w", err)
+			}
+			label, err := wire.ReadVarString(vr, 0)
+			if err != nil {
+				return nil, fmt.Errorf("name record: %w", err)
+			}
+			w.Labels[addr] = label
+
+		case "tx":
+			tx := new(wire.MsgTx)
+			if err := tx.Deserialize(vr); err != nil {
+				return nil, fmt.Errorf("tx record: %w", err)
+			}
+			w.Transactions = append(w.Transactions, tx)
+		}
+	}
+
+	// Metadata is also recorded for keys without a private key, such as
+	// the master key of HD wallets, which are skipped.
+	for _, k := range order {
+		if k.PrivKey == nil && k.encrypted == nil {
+			continue
+		}
+		var err error
+		k.PubKey, err = btcec.ParsePubKey(k.pubKey, btcec.S256())
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key %x: %w",
+				k.pubKey, err)
+		}
+		k.Compressed = len(k.pubKey) == btcec.PubKeyBytesLenCompressed
+		if k.PrivKey != nil && !k.PrivKey.PubKey().IsEqual(k.PubKey) {
+			return nil, fmt.Errorf("the private key of %x does not "+
+				"match", k.pubKey)
+		}
+		w.Keys = append(w.Keys, k)
+	}
+	return w, nil
+}
+
+// Encrypted returns whether the keys of the wallet are encrypted.
+func (w *Wallet) Encrypted() bool {
+	return len(w.masterKeys) > 0
+}
+
+// Unlock decrypts the private keys of an encrypted wallet with the wallet
+// passphrase.
+func (w *Wallet) Unlock(passphrase []byte) error {
+	if !w.Encrypted() {
+		return ErrNotEncrypted
+	}
+
+	var err error
+	for _, mk := range w.masterKeys {
+		if mk.method != 0 {
+			err = ErrKeyDerivation
+			continue
+		}
+		key, iv := bytesToKeySHA512AES(passphrase, mk.salt,
+			mk.iterations)
+		master, decErr := decryptAESCBC(key, iv, mk.encrypted)
+		if decErr != nil || len(master) != 32 {
+			err = ErrPassphrase
+			continue
+		}
+		if err = w.decryptKeys(master); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// decryptKeys decrypts the encrypted private keys with the master key.
+func (w *Wallet) decryptKeys(master []byte) error {
+	privKeys := make([]*btcec.PrivateKey, len(w.Keys))
+	for i, k := range w.Keys {
+		if k.encrypted == nil {
+			continue
+		}
+		iv := chainhash.DoubleHashB(k.pubKey)[:aes.BlockSize]
+		secret, err := decryptAESCBC(master, iv, k.encrypted)
+		if err != nil || len(secret) != 32 {
+			return ErrPassphrase
+		}
+		privKey, pubKey := btcec.PrivKeyFromBytes(btcec.S256(), secret)
+		if !pubKey.IsEqual(k.PubKey) {
+			return ErrPassphrase
+		}
+		privKeys[i] = privKey
+	}
+	for i, privKey := range privKeys {
+		if privKey != nil {
+			w.Keys[i].PrivKey = privKey
+		}
+	}
+	return nil
+}
+
+// bytesToKeySHA512AES derives the AES-256 key and IV decrypting a master key
+// from the wallet passphrase, as the OpenSSL EVP_BytesToKey function with
+// SHA512 does for a single block.
+func bytesToKeySHA512AES(passphrase, salt []byte,
+	iterations uint32) ([]byte, []byte) {
+
+	h := sha512.New()
+	h.Write(passphrase)
+	h.Write(salt)
+	buf := h.Sum(nil)
+	for i := uint32(1); i < iterations; i++ {
+		sum := sha512.Sum512(buf)
+		buf = sum[:]
+	}
+	return buf[:32], buf[32 : 32+aes.BlockSize]
+}
+
+// decryptAESCBC decrypts AES-256-CBC ciphertext with PKCS#7 padding.
+func decryptAESCBC(key, iv, ciphertext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		return nil, errors.New("invalid ciphertext length")
+	}
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+
+	pad := int(plaintext[len(plaintext)-1])
+	if pad == 0 || pad > aes.BlockSize {
+		return nil, errors.New("invalid padding")
+	}
+	for _, b := range plaintext[len(plaintext)-pad:] {
+		if int(b) != pad {
+			return nil, errors.New("invalid padding")
+		}
+	}
+	return plaintext[:len(plaintext)-pad], nil
+}
+
+// parseDERPrivKey returns the secret of a DER encoded SEC1 ECPrivateKey, the
+// format of the private keys of unencrypted wallets:
+//
+//	SEQUENCE { INTEGER 1, OCTET STRING secret, [0] parameters, [1] pubkey }
+func parseDERPrivKey(der []byte) ([]byte, error) {
+	errInvalid := errors.New("invalid DER private key")
+
+	r := bytes.NewReader(der)
+	if tag, _ := r.ReadByte(); tag != 0x30 {
+		return nil, errInvalid
+	}
+	if _, err := readDERLength(r); err != nil {
+		return nil, errInvalid
+	}
+	version := make([]byte, 3)
+	if _, err := io.ReadFull(r, version); err != nil ||
+		!bytes.Equal(version, []byte{0x02, 0x01, 0x01}) {
+
+		return nil, errInvalid
+	}
+	if tag, _ := r.ReadByte(); tag != 0x04 {
+		return nil, errInvalid
+	}
+	n, err := readDERLength(r)
+	if err != nil || n == 0 || n > 32 {
+		return nil, errInvalid
+	}
+	secret := make([]byte, 32)
+	if _, err := io.ReadFull(r, secret[32-n:]); err != nil {
+		return nil, errInvalid
+	}
+	return secret, nil
+}
+
+// readDERLength reads a DER length of up to two bytes.
+func readDERLength(r *bytes.Reader) (int, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	if b < 0x80 {
+		return int(b), nil
+	}
+	n := int(b & 0x7f)
+	if n == 0 || n > 2 {
+		return 0, errors.New("unsupported DER length")
+	}
+	length := 0
+	for i := 0; i < n; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		length = length<<8 | int(b)
+	}
+	return length, nil
+}
+
+// readVarBytes reads a byte vector prefixed by its compact size length.
+func readVarBytes(r io.Reader) ([]byte, error) {
+	return wire.ReadVarBytes(r, 0, maxRecordSize, "record field")
+}
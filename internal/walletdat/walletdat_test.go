@@ -0,0 +1,242 @@
+package walletdat
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/lbryio/lbcd/btcec"
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcd/wire"
+)
+
+const testPageSize = 512
+
+// testPage returns a page of the given type holding the given items.
+func testPage(pgno uint32, typ byte, items ...[]byte) []byte {
+	p := make([]byte, testPageSize)
+	binary.LittleEndian.PutUint32(p[8:12], pgno)
+	binary.LittleEndian.PutUint16(p[20:22], uint16(len(items)))
+	p[25] = typ
+	off := pageHeaderSize + 2*len(items)
+	for i, item := range items {
+		binary.LittleEndian.PutUint16(p[pageHeaderSize+2*i:], uint16(off))
+		off += copy(p[off:], item)
+	}
+	return p
+}
+
+// testMetaPage returns a btree metadata page with the given root page.
+func testMetaPage(pgno, root uint32) []byte {
+	p := make([]byte, testPageSize)
+	binary.LittleEndian.PutUint32(p[8:12], pgno)
+	binary.LittleEndian.PutUint32(p[12:16], btreeMagic)
+	binary.LittleEndian.PutUint32(p[16:20], 9)
+	binary.LittleEndian.PutUint32(p[20:24], testPageSize)
+	p[25] = pageBtreeMeta
+	binary.LittleEndian.PutUint32(p[88:92], root)
+	return p
+}
+
+func keyData(b []byte) []byte {
+	item := make([]byte, 3+len(b))
+	binary.LittleEndian.PutUint16(item, uint16(len(b)))
+	item[2] = itemKeyData
+	copy(item[3:], b)
+	return item
+}
+
+func internalItem(pgno uint32) []byte {
+	item := make([]byte, 12)
+	item[2] = itemKeyData
+	binary.LittleEndian.PutUint32(item[4:8], pgno)
+	return item
+}
+
+func overflowItem(pgno uint32, length int) []byte {
+	item := make([]byte, 12)
+	item[2] = itemOverflow
+	binary.LittleEndian.PutUint32(item[4:8], pgno)
+	binary.LittleEndian.PutUint32(item[8:12], uint32(length))
+	return item
+}
+
+func overflowPage(pgno, next uint32, data []byte) []byte {
+	p := testPage(pgno, pageOverflow)
+	binary.LittleEndian.PutUint32(p[16:20], next)
+	binary.LittleEndian.PutUint16(p[22:24], uint16(len(data)))
+	copy(p[pageHeaderSize:], data)
+	return p
+}
+
+// serialize concatenates strings and byte slices prefixed by their compact
+// size lengths, and other values in little-endian order.
+func serialize(fields ...interface{}) []byte {
+	var buf bytes.Buffer
+	for _, f := range fields {
+		switch f := f.(type) {
+		case string:
+			_ = wire.WriteVarString(&buf, 0, f)
+		case []byte:
+			_ = wire.WriteVarBytes(&buf, 0, f)
+		default:
+			_ = binary.Write(&buf, binary.LittleEndian, f)
+		}
+	}
+	return buf.Bytes()
+}
+
+// testWalletFile returns a wallet.dat file holding the records, the last of
+// which is stored in overflow pages, with the main database in a two level
+// btree.
+func testWalletFile(records [][2][]byte, big [2][]byte) []byte {
+	mainPgno := make([]byte, 4)
+	binary.BigEndian.PutUint32(mainPgno, 2)
+
+	var leaf1, leaf2 [][]byte
+	for i, r := range records {
+		items := [][]byte{keyData(r[0]), keyData(r[1])}
+		if i%2 == 0 {
+			leaf1 = append(leaf1, items...)
+		} else {
+			leaf2 = append(leaf2, items...)
+		}
+	}
+	split := len(big[1]) / 2
+	leaf2 = append(leaf2, keyData(big[0]), overflowItem(6, len(big[1])))
+
+	pages := [][]byte{
+		testMetaPage(0, 1),
+		testPage(1, pageLeaf, keyData([]byte("main")), keyData(mainPgno)),
+		testMetaPage(2, 3),
+		testPage(3, pageInternal, internalItem(4), internalItem(5)),
+		testPage(4, pageLeaf, leaf1...),
+		testPage(5, pageLeaf, leaf2...),
+		overflowPage(6, 7, big[1][:split]),
+		overflowPage(7, 0, big[1][split:]),
+	}
+	return bytes.Join(pages, nil)
+}
+
+// derPrivKey encodes a secret as the wallets do, without the optional
+// fields.
+func derPrivKey(secret []byte) []byte {
+	der := []byte{0x30, 0x25, 0x02, 0x01, 0x01, 0x04, 0x20}
+	return append(der, secret...)
+}
+
+func encryptAESCBC(key, iv, plaintext []byte) []byte {
+	pad := aes.BlockSize - len(plaintext)%aes.BlockSize
+	plaintext = append(plaintext, bytes.Repeat([]byte{byte(pad)}, pad)...)
+	block, _ := aes.NewCipher(key)
+	ciphertext := make([]byte, len(plaintext))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, plaintext)
+	return ciphertext
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	privKey, _ := btcec.NewPrivateKey(btcec.S256())
+	pubKey := privKey.PubKey().SerializeCompressed()
+	tx := &wire.MsgTx{
+		Version: 1,
+		TxIn:    []*wire.TxIn{{Sequence: wire.MaxTxInSequenceNum}},
+		TxOut:   []*wire.TxOut{{Value: 5, PkScript: make([]byte, 600)}},
+	}
+	var txBuf bytes.Buffer
+	if err := tx.Serialize(&txBuf); err != nil {
+		t.Fatal(err)
+	}
+	txHash := tx.TxHash()
+
+	records := [][2][]byte{
+		{serialize("key", pubKey), serialize(derPrivKey(privKey.Serialize()))},
+		{serialize("keymeta", pubKey), serialize(int32(10), int64(1500000000))},
+		{serialize("name", "bLabeledAddress"), serialize("savings")},
+		{serialize("version"), serialize(int32(170000))},
+	}
+	b := testWalletFile(records, [2][]byte{
+		serialize("tx", txHash[:]), txBuf.Bytes(),
+	})
+
+	w, err := Parse(b)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if w.Encrypted() {
+		t.Fatal("wallet is encrypted")
+	}
+	if len(w.Keys) != 1 {
+		t.Fatalf("got %d keys, want 1", len(w.Keys))
+	}
+	k := w.Keys[0]
+	if !k.Compressed || !k.PubKey.IsEqual(privKey.PubKey()) ||
+		k.PrivKey.D.Cmp(privKey.D) != 0 {
+
+		t.Fatal("wrong key")
+	}
+	if !k.CreateTime.Equal(time.Unix(1500000000, 0)) {
+		t.Fatalf("got create time %v", k.CreateTime)
+	}
+	if w.Labels["bLabeledAddress"] != "savings" {
+		t.Fatalf("got labels %v", w.Labels)
+	}
+	if len(w.Transactions) != 1 || w.Transactions[0].TxHash() != txHash {
+		t.Fatal("wrong transactions")
+	}
+
+	if _, err := Parse(b[:testPageSize*3]); err == nil {
+		t.Fatal("parsed a truncated file")
+	}
+	if _, err := Parse(make([]byte, testPageSize)); err != ErrNotBerkeleyDB {
+		t.Fatalf("got %v, want ErrNotBerkeleyDB", err)
+	}
+}
+
+func TestUnlock(t *testing.T) {
+	t.Parallel()
+
+	privKey, _ := btcec.NewPrivateKey(btcec.S256())
+	pubKey := privKey.PubKey().SerializeUncompressed()
+
+	master := bytes.Repeat([]byte{7}, 32)
+	salt := []byte("saltsalt")
+	key, iv := bytesToKeySHA512AES([]byte("secret"), salt, 25000)
+	ckey := encryptAESCBC(master, chainhash.DoubleHashB(pubKey)[:16],
+		privKey.Serialize())
+
+	records := [][2][]byte{
+		{serialize("ckey", pubKey), serialize(ckey)},
+		{serialize("mkey", uint32(1)), serialize(
+			encryptAESCBC(key, iv, master), salt, uint32(0),
+			uint32(25000), []byte{},
+		)},
+	}
+	b := testWalletFile(records, [2][]byte{
+		serialize("name", "bAddress"), serialize("x"),
+	})
+
+	w, err := Parse(b)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if !w.Encrypted() || len(w.Keys) != 1 || w.Keys[0].PrivKey != nil {
+		t.Fatal("wallet keys are not encrypted")
+	}
+	if w.Keys[0].Compressed {
+		t.Fatal("uncompressed key is compressed")
+	}
+	if err := w.Unlock([]byte("wrong")); err != ErrPassphrase {
+		t.Fatalf("got %v, want ErrPassphrase", err)
+	}
+	if err := w.Unlock([]byte("secret")); err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+	if w.Keys[0].PrivKey.D.Cmp(privKey.D) != 0 {
+		t.Fatal("wrong decrypted key")
+	}
+}
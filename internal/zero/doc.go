@@ -1,3 +1,5 @@
 // Package zero contains functions to clear data from byte slices and
-// multi-precision integers.
+// multi-precision integers, and to protect the memory holding secret data
+// from being swapped to disk or written to core dumps where the operating
+// system supports it.
 package zero
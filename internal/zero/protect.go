@@ -0,0 +1,11 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd
+// +build !linux,!darwin,!freebsd,!netbsd,!openbsd
+
+package zero
+
+// Protect attempts to keep the memory backing b from being swapped to disk or
+// included in core dumps.  Memory protection is not supported on this
+// operating system, so this is a no-op.
+func Protect(b []byte) error {
+	return nil
+}
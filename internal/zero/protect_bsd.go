@@ -0,0 +1,21 @@
+//go:build darwin || freebsd || netbsd || openbsd
+// +build darwin freebsd netbsd openbsd
+
+package zero
+
+import "golang.org/x/sys/unix"
+
+// Protect attempts to keep the memory backing b from being swapped to disk by
+// locking the pages containing it into RAM.  An error is returned when the
+// process lacks the privilege or resource limits to lock memory, in which case
+// b is left unprotected but otherwise usable.
+//
+// The pages are never unlocked since other data may share them.  Callers
+// should therefore only protect long lived secrets, such as the key cache of
+// an unlocked wallet, rather than every temporary copy of a key.
+func Protect(b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+	return unix.Mlock(b)
+}
@@ -0,0 +1,40 @@
+package zero
+
+import (
+	"os"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// Protect attempts to keep the memory backing b from being swapped to disk or
+// included in core dumps by locking the pages containing it into RAM and
+// marking them with MADV_DONTDUMP.  An error is returned when the process
+// lacks the privilege or resource limits to lock memory, in which case b is
+// left unprotected but otherwise usable.
+//
+// The pages are never unlocked since other data may share them.  Callers
+// should therefore only protect long lived secrets, such as the key cache of
+// an unlocked wallet, rather than every temporary copy of a key.
+func Protect(b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+	if err := unix.Mlock(b); err != nil {
+		return err
+	}
+
+	// Unlike mlock, madvise requires a page aligned address, so advise
+	// every page overlapping b.
+	pageSize := uintptr(os.Getpagesize())
+	addr := uintptr(unsafe.Pointer(&b[0]))
+	start := addr &^ (pageSize - 1)
+	end := (addr + uintptr(len(b)) + pageSize - 1) &^ (pageSize - 1)
+	_, _, errno := unix.Syscall(
+		unix.SYS_MADVISE, start, end-start, unix.MADV_DONTDUMP,
+	)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
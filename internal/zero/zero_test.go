@@ -145,3 +145,23 @@ func TestBytea64(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestProtect(t *testing.T) {
+	const sz = 100
+	b := makeOneBytes(sz)
+
+	// Locking memory may not be permitted by the resource limits of the
+	// test environment, but protection must never alter the data.
+	if err := Protect(b); err != nil {
+		t.Logf("unable to protect memory: %v", err)
+	}
+	for i, v := range b {
+		if v != 1 {
+			t.Fatalf("b[%d] = %d after protection", i, v)
+		}
+	}
+
+	if err := Protect(nil); err != nil {
+		t.Errorf("unexpected error protecting empty slice: %v", err)
+	}
+}
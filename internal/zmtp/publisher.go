@@ -0,0 +1,485 @@
+// Package zmtp implements a ZeroMQ PUB socket speaking the ZMTP 3.0 wire
+// protocol with the NULL security mechanism.  It is interoperable with the
+// SUB and XSUB sockets of libzmq and other ZeroMQ implementations without
+// depending on a native library.
+package zmtp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// sendQueueSize is the number of messages queued for a subscriber
+	// before further messages are dropped, matching the default send high
+	// water mark of libzmq.
+	sendQueueSize = 1000
+
+	// handshakeTimeout is how long a connecting subscriber may take to
+	// complete the greeting and READY command exchange.
+	handshakeTimeout = 10 * time.Second
+
+	// maxFrameSize is the largest frame accepted from a subscriber.
+	// Subscribers only ever send subscriptions and commands, which are
+	// small.
+	maxFrameSize = 64 * 1024
+)
+
+// Frame flags.
+const (
+	flagMore    = 0x01
+	flagLong    = 0x02
+	flagCommand = 0x04
+)
+
+// Publisher is a ZeroMQ PUB socket bound to a TCP endpoint.  Messages are
+// sent to every connected subscriber with a subscription matching the first
+// frame.  As with libzmq, messages are dropped for subscribers that do not
+// keep up instead of blocking the publisher.
+type Publisher struct {
+	listener net.Listener
+
+	mtx  sync.Mutex
+	subs map[*subscriber]struct{}
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// subscriber is a connected SUB socket.  It only receives messages once
+// ready is set after the handshake.
+type subscriber struct {
+	conn  net.Conn
+	queue chan message
+	ready bool
+
+	mtx    sync.Mutex
+	topics map[string]int
+}
+
+// message is a queued multipart message, or a command if command is not nil.
+type message struct {
+	frames  [][]byte
+	command []byte
+}
+
+// Listen binds a Publisher to endpoint, which must be of the form
+// tcp://host:port as used by ZeroMQ.
+func Listen(endpoint string) (*Publisher, error) {
+	addr := strings.TrimPrefix(endpoint, "tcp://")
+	if addr == endpoint {
+		return nil, fmt.Errorf("unsupported ZMQ endpoint %q (only "+
+			"tcp:// endpoints are supported)", endpoint)
+	}
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Publisher{
+		listener: lis,
+		subs:     make(map[*subscriber]struct{}),
+		quit:     make(chan struct{}),
+	}
+	p.wg.Add(1)
+	go p.acceptLoop()
+	return p, nil
+}
+
+// Addr returns the address the publisher is listening on.
+func (p *Publisher) Addr() net.Addr {
+	return p.listener.Addr()
+}
+
+// Publish queues a multipart message for all subscribers with a subscription
+// that is a prefix of the first frame.  It never blocks.
+func (p *Publisher) Publish(frames ...[]byte) {
+	if len(frames) == 0 {
+		return
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	for s := range p.subs {
+		if !s.ready || !s.subscribed(frames[0]) {
+			continue
+		}
+		select {
+		case s.queue <- message{frames: frames}:
+		default:
+		}
+	}
+}
+
+// Close stops accepting subscribers and disconnects the connected ones.
+func (p *Publisher) Close() error {
+	select {
+	case <-p.quit:
+		return nil
+	default:
+	}
+	close(p.quit)
+	err := p.listener.Close()
+
+	p.mtx.Lock()
+	for s := range p.subs {
+		s.conn.Close()
+	}
+	p.mtx.Unlock()
+
+	p.wg.Wait()
+	return err
+}
+
+func (p *Publisher) acceptLoop() {
+	defer p.wg.Done()
+
+	for {
+		conn, err := p.listener.Accept()
+		if err != nil {
+			select {
+			case <-p.quit:
+				return
+			default:
+			}
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				continue
+			}
+			return
+		}
+
+		p.wg.Add(1)
+		go p.serve(conn)
+	}
+}
+
+// serve performs the handshake with a connecting subscriber, then writes the
+// published messages to it while reading its subscriptions.
+func (p *Publisher) serve(conn net.Conn) {
+	defer p.wg.Done()
+
+	s := &subscriber{
+		conn:   conn,
+		queue:  make(chan message, sendQueueSize),
+		topics: make(map[string]int),
+	}
+	p.mtx.Lock()
+	select {
+	case <-p.quit:
+		p.mtx.Unlock()
+		conn.Close()
+		return
+	default:
+	}
+	p.subs[s] = struct{}{}
+	p.mtx.Unlock()
+	defer func() {
+		p.mtx.Lock()
+		delete(p.subs, s)
+		p.mtx.Unlock()
+		conn.Close()
+	}()
+
+	r := bufio.NewReader(conn)
+	_ = conn.SetDeadline(time.Now().Add(handshakeTimeout))
+	if err := handshake(conn, r); err != nil {
+		return
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	p.mtx.Lock()
+	s.ready = true
+	p.mtx.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.readLoop(r)
+		close(done)
+	}()
+
+	w := bufio.NewWriter(conn)
+out:
+	for {
+		select {
+		case msg := <-s.queue:
+			var err error
+			if msg.command != nil {
+				err = writeFrame(w, flagCommand, msg.command)
+			} else {
+				err = writeMessage(w, msg.frames)
+			}
+			if err != nil {
+				break out
+			}
+			// Write the remaining queued messages before flushing.
+			if len(s.queue) != 0 {
+				continue
+			}
+			if err := w.Flush(); err != nil {
+				break out
+			}
+		case <-done:
+			break out
+		case <-p.quit:
+			break out
+		}
+	}
+
+	conn.Close()
+	<-done
+}
+
+// subscribed returns whether the subscriber has a subscription matching the
+// topic frame of a message.
+func (s *subscriber) subscribed(topic []byte) bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	for prefix := range s.topics {
+		if bytes.HasPrefix(topic, []byte(prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// subscribe adds or, if add is false, removes a subscription.
+func (s *subscriber) subscribe(prefix []byte, add bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if add {
+		s.topics[string(prefix)]++
+		return
+	}
+	if s.topics[string(prefix)] <= 1 {
+		delete(s.topics, string(prefix))
+		return
+	}
+	s.topics[string(prefix)]--
+}
+
+// readLoop reads the subscriptions and commands sent by the subscriber until
+// the connection fails.
+func (s *subscriber) readLoop(r *bufio.Reader) {
+	for {
+		flags, body, err := readFrame(r)
+		if err != nil {
+			return
+		}
+
+		if flags&flagCommand != 0 {
+			name, data, err := parseCommand(body)
+			if err != nil {
+				return
+			}
+			switch name {
+			// ZMTP 3.1 subscribers use commands instead of
+			// subscription messages.
+			case "SUBSCRIBE":
+				s.subscribe(data, true)
+			case "CANCEL":
+				s.subscribe(data, false)
+			case "PING":
+				if len(data) < 2 {
+					return
+				}
+				pong := command("PONG", data[2:])
+				select {
+				case s.queue <- message{command: pong}:
+				default:
+				}
+			}
+			continue
+		}
+
+		// Subscription messages are single frames starting with 1 to
+		// subscribe or 0 to unsubscribe.  Everything else sent by a
+		// subscriber is ignored.
+		if flags&flagMore != 0 || len(body) == 0 {
+			continue
+		}
+		switch body[0] {
+		case 1:
+			s.subscribe(body[1:], true)
+		case 0:
+			s.subscribe(body[1:], false)
+		}
+	}
+}
+
+// handshake exchanges the greeting and READY commands with a subscriber.
+func handshake(w io.Writer, r *bufio.Reader) error {
+	var greeting [64]byte
+	greeting[0] = 0xff
+	greeting[9] = 0x7f
+	greeting[10] = 3 // Major version
+	greeting[11] = 0 // Minor version
+	copy(greeting[12:32], "NULL")
+	if _, err := w.Write(greeting[:]); err != nil {
+		return err
+	}
+
+	var peer [64]byte
+	if _, err := io.ReadFull(r, peer[:]); err != nil {
+		return err
+	}
+	if peer[0] != 0xff || peer[9]&0x01 == 0 || peer[10] < 3 {
+		return errors.New("unsupported ZMTP version")
+	}
+	if string(bytes.TrimRight(peer[12:32], "\x00")) != "NULL" {
+		return errors.New("unsupported ZMTP security mechanism")
+	}
+
+	ready := command("READY", property("Socket-Type", "PUB"))
+	if err := writeFrame(w, flagCommand, ready); err != nil {
+		return err
+	}
+
+	flags, body, err := readFrame(r)
+	if err != nil {
+		return err
+	}
+	if flags&flagCommand == 0 {
+		return errors.New("expected READY command")
+	}
+	name, data, err := parseCommand(body)
+	if err != nil {
+		return err
+	}
+	if name != "READY" {
+		return fmt.Errorf("expected READY command, got %s", name)
+	}
+	socketType, err := readProperty(data, "Socket-Type")
+	if err != nil {
+		return err
+	}
+	if socketType != "SUB" && socketType != "XSUB" {
+		return fmt.Errorf("incompatible socket type %s", socketType)
+	}
+	return nil
+}
+
+// command returns the body of a command frame.
+func command(name string, data []byte) []byte {
+	body := make([]byte, 0, 1+len(name)+len(data))
+	body = append(body, byte(len(name)))
+	body = append(body, name...)
+	return append(body, data...)
+}
+
+// parseCommand splits the body of a command frame into its name and data.
+func parseCommand(body []byte) (string, []byte, error) {
+	if len(body) == 0 || len(body) < 1+int(body[0]) {
+		return "", nil, errors.New("malformed command")
+	}
+	n := int(body[0])
+	return string(body[1 : 1+n]), body[1+n:], nil
+}
+
+// property returns a serialized metadata property of a READY command.
+func property(name, value string) []byte {
+	b := make([]byte, 0, 1+len(name)+4+len(value))
+	b = append(b, byte(len(name)))
+	b = append(b, name...)
+	b = binary.BigEndian.AppendUint32(b, uint32(len(value)))
+	return append(b, value...)
+}
+
+// readProperty returns the value of the named metadata property, which are
+// matched case-insensitively.
+func readProperty(data []byte, name string) (string, error) {
+	for len(data) != 0 {
+		n := int(data[0])
+		if len(data) < 1+n+4 {
+			return "", errors.New("malformed metadata")
+		}
+		key := string(data[1 : 1+n])
+		data = data[1+n:]
+		size := binary.BigEndian.Uint32(data)
+		data = data[4:]
+		if uint64(len(data)) < uint64(size) {
+			return "", errors.New("malformed metadata")
+		}
+		if strings.EqualFold(key, name) {
+			return string(data[:size]), nil
+		}
+		data = data[size:]
+	}
+	return "", fmt.Errorf("missing %s property", name)
+}
+
+// writeMessage writes a multipart message.
+func writeMessage(w io.Writer, frames [][]byte) error {
+	for i, frame := range frames {
+		var flags byte
+		if i != len(frames)-1 {
+			flags = flagMore
+		}
+		if err := writeFrame(w, flags, frame); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFrame writes a frame, using the long size encoding when required.
+func writeFrame(w io.Writer, flags byte, body []byte) error {
+	var header [9]byte
+	n := 2
+	if len(body) > 255 {
+		header[0] = flags | flagLong
+		binary.BigEndian.PutUint64(header[1:], uint64(len(body)))
+		n = 9
+	} else {
+		header[0] = flags
+		header[1] = byte(len(body))
+	}
+	if _, err := w.Write(header[:n]); err != nil {
+		return err
+	}
+	_, err := w.Write(body)
+	return err
+}
+
+// readFrame reads a frame, returning its flags and body.
+func readFrame(r *bufio.Reader) (byte, []byte, error) {
+	flags, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var size uint64
+	if flags&flagLong != 0 {
+		var b [8]byte
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return 0, nil, err
+		}
+		size = binary.BigEndian.Uint64(b[:])
+	} else {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		size = uint64(b)
+	}
+	if size > maxFrameSize {
+		return 0, nil, errors.New("frame too large")
+	}
+
+	body := make([]byte, size)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return flags, body, nil
+}
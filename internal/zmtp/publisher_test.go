@@ -0,0 +1,125 @@
+package zmtp
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// dialSubscriber connects a SUB socket to the publisher and subscribes to
+// topic.
+func dialSubscriber(t *testing.T, addr net.Addr, topic string) (net.Conn, *bufio.Reader) {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", addr.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = conn.SetDeadline(time.Now().Add(10 * time.Second))
+	r := bufio.NewReader(conn)
+
+	var greeting [64]byte
+	greeting[0] = 0xff
+	greeting[9] = 0x7f
+	greeting[10] = 3
+	greeting[11] = 1
+	copy(greeting[12:], "NULL")
+	if _, err := conn.Write(greeting[:]); err != nil {
+		t.Fatal(err)
+	}
+	var peer [64]byte
+	if _, err := io.ReadFull(r, peer[:]); err != nil {
+		t.Fatal(err)
+	}
+	if peer[0] != 0xff || peer[9] != 0x7f || peer[10] != 3 {
+		t.Fatalf("bad greeting %x", peer)
+	}
+
+	ready := command("READY", property("Socket-Type", "SUB"))
+	if err := writeFrame(conn, flagCommand, ready); err != nil {
+		t.Fatal(err)
+	}
+	flags, body, err := readFrame(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	name, data, err := parseCommand(body)
+	if err != nil || flags&flagCommand == 0 || name != "READY" {
+		t.Fatalf("expected READY command, got %x", body)
+	}
+	if socketType, err := readProperty(data, "socket-type"); err != nil ||
+		socketType != "PUB" {
+
+		t.Fatalf("expected PUB socket type, got %q (%v)", socketType, err)
+	}
+
+	sub := append([]byte{1}, topic...)
+	if err := writeFrame(conn, 0, sub); err != nil {
+		t.Fatal(err)
+	}
+	return conn, r
+}
+
+// readMessage reads a multipart message.
+func readMessage(r *bufio.Reader) ([][]byte, error) {
+	var frames [][]byte
+	for {
+		flags, body, err := readFrame(r)
+		if err != nil {
+			return nil, err
+		}
+		frames = append(frames, body)
+		if flags&flagMore == 0 {
+			return frames, nil
+		}
+	}
+}
+
+func TestPublisher(t *testing.T) {
+	p, err := Listen("tcp://127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	conn, r := dialSubscriber(t, p.Addr(), "hash")
+	defer conn.Close()
+
+	// The subscription is registered asynchronously, so publish until
+	// the first message is received.
+	long := bytes.Repeat([]byte{0xaa}, 300)
+	received := make(chan [][]byte, 1)
+	errs := make(chan error, 1)
+	go func() {
+		frames, err := readMessage(r)
+		if err != nil {
+			errs <- err
+			return
+		}
+		received <- frames
+	}()
+	var frames [][]byte
+	for frames == nil {
+		p.Publish([]byte("rawtx"), []byte{1})
+		p.Publish([]byte("hashtx"), long)
+		select {
+		case frames = <-received:
+		case err := <-errs:
+			t.Fatal(err)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	if len(frames) != 2 || string(frames[0]) != "hashtx" ||
+		!bytes.Equal(frames[1], long) {
+
+		t.Fatalf("unexpected message %x", frames)
+	}
+
+	if _, err := Listen("ipc:///tmp/socket"); err == nil {
+		t.Fatal("expected error for ipc endpoint")
+	}
+}
@@ -14,6 +14,19 @@ type Params struct {
 	*chaincfg.Params
 	RPCClientPort string
 	RPCServerPort string
+
+	// DataDirName is the name of the data directory of the network, when
+	// it is not the name of the network.
+	DataDirName string
+
+	// TestNet is whether the coins of the network have no value, so that
+	// the options meant for testing, such as faucets, fixture wallets and
+	// fault injection, are allowed.
+	TestNet bool
+
+	// Regtest is whether the blocks of the network are mined on demand,
+	// so that the regtest harness and the mining account are allowed.
+	Regtest bool
 }
 
 // MainNetParams contains parameters specific running lbcwallet and
@@ -32,6 +45,11 @@ var TestNet3Params = Params{
 	Params:        &chaincfg.TestNet3Params,
 	RPCClientPort: "19245",
 	RPCServerPort: "19244",
+	TestNet:       true,
+
+	// The data directory of testnet3 has always been named "testnet", so
+	// it is kept for the existing wallets to be found.
+	DataDirName: "testnet",
 }
 
 // RegNetParams contains parameters specific to the regression test network
@@ -40,4 +58,35 @@ var RegTestParams = Params{
 	Params:        &chaincfg.RegressionNetParams,
 	RPCClientPort: "29245",
 	RPCServerPort: "29244",
+	TestNet:       true,
+	Regtest:       true,
+}
+
+// Networks are the networks lbcwallet runs on, which are selected by the name
+// of their chain parameters.  Adding a network only requires adding its
+// parameters here.
+var Networks = []*Params{
+	&MainNetParams,
+	&TestNet3Params,
+	&RegTestParams,
+}
+
+// ByName returns the parameters of the network with a name, or nil if there
+// is no such network.
+func ByName(name string) *Params {
+	for _, p := range Networks {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// Names returns the names of the networks.
+func Names() []string {
+	names := make([]string, len(Networks))
+	for i, p := range Networks {
+		names[i] = p.Name
+	}
+	return names
 }
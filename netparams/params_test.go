@@ -0,0 +1,43 @@
+package netparams
+
+import "testing"
+
+// TestNetworks checks that every network is found by its name, and that the
+// networks do not share a name, a data directory or an RPC port.
+func TestNetworks(t *testing.T) {
+	names := make(map[string]struct{})
+	dirs := make(map[string]struct{})
+	ports := make(map[string]struct{})
+	for _, p := range Networks {
+		if ByName(p.Name) != p {
+			t.Fatalf("network %s not found by its name", p.Name)
+		}
+
+		dir := p.Name
+		if p.DataDirName != "" {
+			dir = p.DataDirName
+		}
+		for _, unique := range []struct {
+			set   map[string]struct{}
+			value string
+		}{
+			{names, p.Name},
+			{dirs, dir},
+			{ports, p.RPCClientPort},
+			{ports, p.RPCServerPort},
+		} {
+			if _, ok := unique.set[unique.value]; ok {
+				t.Fatalf("network %s reuses %q", p.Name,
+					unique.value)
+			}
+			unique.set[unique.value] = struct{}{}
+		}
+		if p.Regtest && !p.TestNet {
+			t.Fatalf("regtest network %s is not a test network",
+				p.Name)
+		}
+	}
+	if ByName("unknown") != nil {
+		t.Fatal("unknown network found")
+	}
+}
@@ -0,0 +1,11 @@
+package electrum
+
+import "github.com/btcsuite/btclog"
+
+var log = btclog.Disabled
+
+// UseLogger sets the package-wide logger.  Any calls to this function must be
+// made before a server is created and used (it is not concurrent safe).
+func UseLogger(logger btclog.Logger) {
+	log = logger
+}
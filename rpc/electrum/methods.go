@@ -0,0 +1,806 @@
+package electrum
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcd/wire"
+	"github.com/lbryio/lbcwallet/wallet"
+	"github.com/lbryio/lbcwallet/wallet/txrules"
+)
+
+// maxHeaders is the largest number of headers returned by
+// blockchain.block.headers.
+const maxHeaders = 2016
+
+// request is a JSON-RPC request of a client.
+type request struct {
+	JSONRPC string            `json:"jsonrpc"`
+	ID      json.RawMessage   `json:"id"`
+	Method  string            `json:"method"`
+	Params  []json.RawMessage `json:"params"`
+}
+
+// response is the response to a request.  Result is left nil for errors.
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// notification is a message sent to a client for one of its subscriptions.
+type notification struct {
+	JSONRPC string        `json:"jsonrpc"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+// Error is an error returned to a client.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Error codes, as used by ElectrumX.
+const (
+	codeBadRequest     = 1
+	codeDaemonError    = 2
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+var errWalletNotLoaded = &Error{
+	Code:    codeDaemonError,
+	Message: "wallet is not loaded",
+}
+
+func invalidParams(format string, args ...interface{}) *Error {
+	return &Error{Code: codeInvalidParams, Message: fmt.Sprintf(format, args...)}
+}
+
+// handler handles a request of a session, with the wallet served.
+type handler func(sess *session, w *wallet.Wallet,
+	params []json.RawMessage) (interface{}, error)
+
+var handlers map[string]handler
+
+func init() {
+	handlers = map[string]handler{
+		"blockchain.block.header":           blockHeaderByHeight,
+		"blockchain.block.headers":          blockHeaders,
+		"blockchain.estimatefee":            estimateFee,
+		"blockchain.headers.subscribe":      headersSubscribe,
+		"blockchain.relayfee":               relayFee,
+		"blockchain.scripthash.get_balance": scriptHashGetBalance,
+		"blockchain.scripthash.get_history": scriptHashGetHistory,
+		"blockchain.scripthash.get_mempool": scriptHashGetMempool,
+		"blockchain.scripthash.listunspent": scriptHashListUnspent,
+		"blockchain.scripthash.subscribe":   scriptHashSubscribe,
+		"blockchain.scripthash.unsubscribe": scriptHashUnsubscribe,
+		"blockchain.transaction.broadcast":  transactionBroadcast,
+		"blockchain.transaction.get":        transactionGet,
+		"blockchain.transaction.get_merkle": transactionGetMerkle,
+		"server.banner":                     serverBanner,
+		"server.donation_address":           serverDonationAddress,
+		"server.features":                   serverFeatures,
+		"server.peers.subscribe":            serverPeersSubscribe,
+		"server.ping":                       serverPing,
+		"server.version":                    serverVersion,
+	}
+}
+
+// handleLine handles a request, or a batch of requests, read from the client
+// and returns the response to write, if any.
+func (sess *session) handleLine(line []byte) []byte {
+	if line[0] == '[' {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(line, &batch); err != nil {
+			return marshalResponse(errorResponse(nil, &Error{
+				Code:    codeParseError,
+				Message: err.Error(),
+			}))
+		}
+		if len(batch) == 0 {
+			return marshalResponse(errorResponse(nil, &Error{
+				Code:    codeInvalidRequest,
+				Message: "empty batch",
+			}))
+		}
+		responses := make([]*response, 0, len(batch))
+		for _, raw := range batch {
+			if resp := sess.handleRequest(raw); resp != nil {
+				responses = append(responses, resp)
+			}
+		}
+		if len(responses) == 0 {
+			return nil
+		}
+		return marshalResponse(responses)
+	}
+
+	resp := sess.handleRequest(line)
+	if resp == nil {
+		return nil
+	}
+	return marshalResponse(resp)
+}
+
+// handleRequest handles a single request.  Notifications, which have no ID,
+// are handled without a response.
+func (sess *session) handleRequest(raw []byte) *response {
+	var req request
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return errorResponse(nil, &Error{
+			Code:    codeInvalidRequest,
+			Message: err.Error(),
+		})
+	}
+
+	result, err := sess.call(&req)
+	if len(req.ID) == 0 || bytes.Equal(req.ID, []byte("null")) {
+		return nil
+	}
+	if err != nil {
+		rpcErr, ok := err.(*Error)
+		if !ok {
+			rpcErr = &Error{Code: codeDaemonError, Message: err.Error()}
+		}
+		return errorResponse(req.ID, rpcErr)
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		return errorResponse(req.ID, &Error{
+			Code:    codeDaemonError,
+			Message: err.Error(),
+		})
+	}
+	return &response{JSONRPC: "2.0", ID: req.ID, Result: b}
+}
+
+func (sess *session) call(req *request) (interface{}, error) {
+	h, ok := handlers[req.Method]
+	if !ok {
+		return nil, &Error{
+			Code:    codeMethodNotFound,
+			Message: fmt.Sprintf("unknown method %q", req.Method),
+		}
+	}
+	w, err := sess.server.loadedWallet()
+	if err != nil {
+		return nil, err
+	}
+	return h(sess, w, req.Params)
+}
+
+func errorResponse(id json.RawMessage, err *Error) *response {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return &response{JSONRPC: "2.0", ID: id, Error: err}
+}
+
+func marshalResponse(v interface{}) []byte {
+	b, err := json.Marshal(v)
+	if err != nil {
+		log.Errorf("Unable to marshal response: %v", err)
+		return nil
+	}
+	return b
+}
+
+// parseParams decodes the positional parameters into dst, of which the
+// parameters after the first required ones are optional.
+func parseParams(params []json.RawMessage, required int,
+	dst ...interface{}) error {
+
+	if len(params) < required || len(params) > len(dst) {
+		return invalidParams("expected %d to %d parameters, got %d",
+			required, len(dst), len(params))
+	}
+	for i, p := range params {
+		if err := json.Unmarshal(p, dst[i]); err != nil {
+			return invalidParams("invalid parameter %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// scriptHashString returns the script hash of a script as used by the
+// Electrum protocol: the hex of the reversed SHA256 of the script.
+func scriptHashString(script []byte) string {
+	hash := sha256.Sum256(script)
+	for i, j := 0, len(hash)-1; i < j; i, j = i+1, j-1 {
+		hash[i], hash[j] = hash[j], hash[i]
+	}
+	return hex.EncodeToString(hash[:])
+}
+
+func parseScriptHash(params []json.RawMessage) (string, error) {
+	var scriptHash string
+	if err := parseParams(params, 1, &scriptHash); err != nil {
+		return "", err
+	}
+	scriptHash = strings.ToLower(scriptHash)
+	if b, err := hex.DecodeString(scriptHash); err != nil || len(b) != 32 {
+		return "", invalidParams("invalid script hash %q", scriptHash)
+	}
+	return scriptHash, nil
+}
+
+// electrumHeight returns the height of a transaction as reported by the
+// Electrum protocol: 0 for unmined transactions only spending mined outputs,
+// and -1 for unmined transactions spending unmined outputs.
+func electrumHeight(tx *wallet.ScriptTx) int32 {
+	switch {
+	case tx.Height != -1:
+		return tx.Height
+	case tx.UnminedInputs:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// scriptStatus returns the status of a script hash: the hex of the SHA256 of
+// its history, or an empty string without history.
+func scriptStatus(a *wallet.ScriptActivity) string {
+	if a == nil || len(a.Transactions) == 0 {
+		return ""
+	}
+	var buf bytes.Buffer
+	for i := range a.Transactions {
+		tx := &a.Transactions[i]
+		fmt.Fprintf(&buf, "%v:%d:", tx.Hash, electrumHeight(tx))
+	}
+	hash := sha256.Sum256(buf.Bytes())
+	return hex.EncodeToString(hash[:])
+}
+
+// statusResult returns the JSON value of a status, which is null without
+// history.
+func statusResult(status string) interface{} {
+	if status == "" {
+		return nil
+	}
+	return status
+}
+
+type historyResult struct {
+	TxHash string `json:"tx_hash"`
+	Height int32  `json:"height"`
+	Fee    int64  `json:"fee,omitempty"`
+}
+
+func history(a *wallet.ScriptActivity, mempool bool) []historyResult {
+	results := []historyResult{}
+	if a == nil {
+		return results
+	}
+	for i := range a.Transactions {
+		tx := &a.Transactions[i]
+		if mempool && tx.Height != -1 {
+			continue
+		}
+		result := historyResult{
+			TxHash: tx.Hash.String(),
+			Height: electrumHeight(tx),
+		}
+		if tx.Height == -1 {
+			result.Fee = int64(tx.Fee)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+type headerResult struct {
+	Height int32  `json:"height"`
+	Hex    string `json:"hex"`
+}
+
+// blockHeader returns the serialized header of a block.
+func blockHeader(w *wallet.Wallet, hash *chainhash.Hash,
+	height int32) (*headerResult, error) {
+
+	chainClient := w.ChainClient()
+	if chainClient == nil {
+		return nil, &Error{
+			Code:    codeDaemonError,
+			Message: "no chain backend",
+		}
+	}
+	header, err := chainClient.GetBlockHeader(hash)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := header.Serialize(&buf); err != nil {
+		return nil, err
+	}
+	return &headerResult{Height: height, Hex: hex.EncodeToString(buf.Bytes())}, nil
+}
+
+func blockHeaderAt(w *wallet.Wallet, height int32) (*headerResult, error) {
+	chainClient := w.ChainClient()
+	if chainClient == nil {
+		return nil, &Error{
+			Code:    codeDaemonError,
+			Message: "no chain backend",
+		}
+	}
+	hash, err := chainClient.GetBlockHash(int64(height))
+	if err != nil {
+		return nil, err
+	}
+	return blockHeader(w, hash, height)
+}
+
+func blockHeaderByHeight(sess *session, w *wallet.Wallet,
+	params []json.RawMessage) (interface{}, error) {
+
+	var height, cpHeight int32
+	if err := parseParams(params, 1, &height, &cpHeight); err != nil {
+		return nil, err
+	}
+	if cpHeight != 0 {
+		return nil, invalidParams("checkpoint proofs are not supported")
+	}
+	if height < 0 || height > w.Manager.SyncedTo().Height {
+		return nil, invalidParams("height %d out of range", height)
+	}
+	header, err := blockHeaderAt(w, height)
+	if err != nil {
+		return nil, err
+	}
+	return header.Hex, nil
+}
+
+type headersResult struct {
+	Count int    `json:"count"`
+	Hex   string `json:"hex"`
+	Max   int    `json:"max"`
+}
+
+func blockHeaders(sess *session, w *wallet.Wallet,
+	params []json.RawMessage) (interface{}, error) {
+
+	var start, count, cpHeight int32
+	if err := parseParams(params, 2, &start, &count, &cpHeight); err != nil {
+		return nil, err
+	}
+	if cpHeight != 0 {
+		return nil, invalidParams("checkpoint proofs are not supported")
+	}
+	if start < 0 || count < 0 {
+		return nil, invalidParams("negative start height or count")
+	}
+	if count > maxHeaders {
+		count = maxHeaders
+	}
+	tip := w.Manager.SyncedTo().Height
+	var hexHeaders strings.Builder
+	n := 0
+	for height := start; height <= tip && n < int(count); height++ {
+		header, err := blockHeaderAt(w, height)
+		if err != nil {
+			return nil, err
+		}
+		hexHeaders.WriteString(header.Hex)
+		n++
+	}
+	return &headersResult{Count: n, Hex: hexHeaders.String(), Max: maxHeaders}, nil
+}
+
+// estimateFee reports that no fee estimate is available, which the wallet
+// does not track, so that clients fall back to the relay fee.
+func estimateFee(sess *session, w *wallet.Wallet,
+	params []json.RawMessage) (interface{}, error) {
+
+	var blocks int
+	if err := parseParams(params, 1, &blocks); err != nil {
+		return nil, err
+	}
+	return -1, nil
+}
+
+func headersSubscribe(sess *session, w *wallet.Wallet,
+	params []json.RawMessage) (interface{}, error) {
+
+	tip := w.Manager.SyncedTo()
+	header, err := blockHeader(w, &tip.Hash, tip.Height)
+	if err != nil {
+		return nil, err
+	}
+	sess.mtx.Lock()
+	sess.headers = true
+	sess.mtx.Unlock()
+	return header, nil
+}
+
+// relayFee returns the fee rate, in LBC per kilobyte, used by the wallet.
+func relayFee(sess *session, w *wallet.Wallet,
+	params []json.RawMessage) (interface{}, error) {
+
+	return txrules.DefaultRelayFeePerKb.ToBTC(), nil
+}
+
+type balanceResult struct {
+	Confirmed   int64 `json:"confirmed"`
+	Unconfirmed int64 `json:"unconfirmed"`
+}
+
+func scriptHashGetBalance(sess *session, w *wallet.Wallet,
+	params []json.RawMessage) (interface{}, error) {
+
+	scriptHash, err := parseScriptHash(params)
+	if err != nil {
+		return nil, err
+	}
+	a, err := sess.server.scriptActivity(w, scriptHash)
+	if err != nil {
+		return nil, err
+	}
+	result := &balanceResult{}
+	if a != nil {
+		result.Confirmed = int64(a.Confirmed)
+		result.Unconfirmed = int64(a.Unconfirmed)
+	}
+	return result, nil
+}
+
+func scriptHashGetHistory(sess *session, w *wallet.Wallet,
+	params []json.RawMessage) (interface{}, error) {
+
+	scriptHash, err := parseScriptHash(params)
+	if err != nil {
+		return nil, err
+	}
+	a, err := sess.server.scriptActivity(w, scriptHash)
+	if err != nil {
+		return nil, err
+	}
+	return history(a, false), nil
+}
+
+func scriptHashGetMempool(sess *session, w *wallet.Wallet,
+	params []json.RawMessage) (interface{}, error) {
+
+	scriptHash, err := parseScriptHash(params)
+	if err != nil {
+		return nil, err
+	}
+	a, err := sess.server.scriptActivity(w, scriptHash)
+	if err != nil {
+		return nil, err
+	}
+	return history(a, true), nil
+}
+
+type unspentResult struct {
+	TxHash string `json:"tx_hash"`
+	TxPos  uint32 `json:"tx_pos"`
+	Height int32  `json:"height"`
+	Value  int64  `json:"value"`
+}
+
+func scriptHashListUnspent(sess *session, w *wallet.Wallet,
+	params []json.RawMessage) (interface{}, error) {
+
+	scriptHash, err := parseScriptHash(params)
+	if err != nil {
+		return nil, err
+	}
+	a, err := sess.server.scriptActivity(w, scriptHash)
+	if err != nil {
+		return nil, err
+	}
+	results := []unspentResult{}
+	if a == nil {
+		return results, nil
+	}
+	for _, output := range a.Unspent {
+		height := output.Height
+		if height == -1 {
+			height = 0
+		}
+		results = append(results, unspentResult{
+			TxHash: output.OutPoint.Hash.String(),
+			TxPos:  output.OutPoint.Index,
+			Height: height,
+			Value:  int64(output.Amount),
+		})
+	}
+	return results, nil
+}
+
+func scriptHashSubscribe(sess *session, w *wallet.Wallet,
+	params []json.RawMessage) (interface{}, error) {
+
+	scriptHash, err := parseScriptHash(params)
+	if err != nil {
+		return nil, err
+	}
+	a, err := sess.server.scriptActivity(w, scriptHash)
+	if err != nil {
+		return nil, err
+	}
+	status := scriptStatus(a)
+	sess.mtx.Lock()
+	sess.statuses[scriptHash] = status
+	sess.mtx.Unlock()
+	return statusResult(status), nil
+}
+
+func scriptHashUnsubscribe(sess *session, w *wallet.Wallet,
+	params []json.RawMessage) (interface{}, error) {
+
+	scriptHash, err := parseScriptHash(params)
+	if err != nil {
+		return nil, err
+	}
+	sess.mtx.Lock()
+	_, ok := sess.statuses[scriptHash]
+	delete(sess.statuses, scriptHash)
+	sess.mtx.Unlock()
+	return ok, nil
+}
+
+func transactionBroadcast(sess *session, w *wallet.Wallet,
+	params []json.RawMessage) (interface{}, error) {
+
+	var rawTx string
+	if err := parseParams(params, 1, &rawTx); err != nil {
+		return nil, err
+	}
+	serializedTx, err := hex.DecodeString(rawTx)
+	if err != nil {
+		return nil, invalidParams("invalid transaction hex: %v", err)
+	}
+	var tx wire.MsgTx
+	if err := tx.Deserialize(bytes.NewReader(serializedTx)); err != nil {
+		return nil, invalidParams("invalid transaction: %v", err)
+	}
+	if err := w.PublishTransaction(&tx, ""); err != nil {
+		return nil, &Error{Code: codeBadRequest, Message: err.Error()}
+	}
+	return tx.TxHash().String(), nil
+}
+
+func parseTxHash(s string) (*chainhash.Hash, error) {
+	hash, err := chainhash.NewHashFromStr(s)
+	if err != nil {
+		return nil, invalidParams("invalid transaction hash %q", s)
+	}
+	return hash, nil
+}
+
+func transactionGet(sess *session, w *wallet.Wallet,
+	params []json.RawMessage) (interface{}, error) {
+
+	var txid string
+	var verbose bool
+	if err := parseParams(params, 1, &txid, &verbose); err != nil {
+		return nil, err
+	}
+	if verbose {
+		return nil, invalidParams("verbose transactions are not supported")
+	}
+	hash, err := parseTxHash(txid)
+	if err != nil {
+		return nil, err
+	}
+	details, err := wallet.UnstableAPI(w).TxDetails(hash)
+	if err != nil {
+		return nil, err
+	}
+	if details == nil {
+		return nil, &Error{
+			Code:    codeBadRequest,
+			Message: fmt.Sprintf("transaction %v is not a wallet transaction", hash),
+		}
+	}
+	return hex.EncodeToString(details.SerializedTx), nil
+}
+
+type merkleResult struct {
+	BlockHeight int32    `json:"block_height"`
+	Merkle      []string `json:"merkle"`
+	Pos         int      `json:"pos"`
+}
+
+func transactionGetMerkle(sess *session, w *wallet.Wallet,
+	params []json.RawMessage) (interface{}, error) {
+
+	var txid string
+	var height int32
+	if err := parseParams(params, 2, &txid, &height); err != nil {
+		return nil, err
+	}
+	hash, err := parseTxHash(txid)
+	if err != nil {
+		return nil, err
+	}
+	chainClient := w.ChainClient()
+	if chainClient == nil {
+		return nil, &Error{Code: codeDaemonError, Message: "no chain backend"}
+	}
+	blockHash, err := chainClient.GetBlockHash(int64(height))
+	if err != nil {
+		return nil, err
+	}
+	block, err := chainClient.GetBlock(blockHash)
+	if err != nil {
+		return nil, err
+	}
+	txHashes := make([]chainhash.Hash, len(block.Transactions))
+	pos := -1
+	for i, tx := range block.Transactions {
+		txHashes[i] = tx.TxHash()
+		if txHashes[i] == *hash {
+			pos = i
+		}
+	}
+	if pos == -1 {
+		return nil, &Error{
+			Code: codeBadRequest,
+			Message: fmt.Sprintf("transaction %v is not in block %d",
+				hash, height),
+		}
+	}
+	branch := merkleBranch(txHashes, pos)
+	merkle := make([]string, len(branch))
+	for i := range branch {
+		merkle[i] = branch[i].String()
+	}
+	return &merkleResult{BlockHeight: height, Merkle: merkle, Pos: pos}, nil
+}
+
+// merkleBranch returns the hashes needed to prove the inclusion of the
+// transaction at pos in the merkle root of the transaction hashes, from the
+// leaves up.
+func merkleBranch(hashes []chainhash.Hash, pos int) []chainhash.Hash {
+	var branch []chainhash.Hash
+	level := append([]chainhash.Hash(nil), hashes...)
+	for len(level) > 1 {
+		if len(level)%2 == 1 {
+			level = append(level, level[len(level)-1])
+		}
+		branch = append(branch, level[pos^1])
+
+		next := make([]chainhash.Hash, len(level)/2)
+		var buf [chainhash.HashSize * 2]byte
+		for i := range next {
+			copy(buf[:chainhash.HashSize], level[2*i][:])
+			copy(buf[chainhash.HashSize:], level[2*i+1][:])
+			next[i] = chainhash.DoubleHashH(buf[:])
+		}
+		level = next
+		pos /= 2
+	}
+	return branch
+}
+
+func serverBanner(sess *session, w *wallet.Wallet,
+	params []json.RawMessage) (interface{}, error) {
+
+	return sess.server.opts.Banner, nil
+}
+
+func serverDonationAddress(sess *session, w *wallet.Wallet,
+	params []json.RawMessage) (interface{}, error) {
+
+	return "", nil
+}
+
+type featuresResult struct {
+	GenesisHash   string              `json:"genesis_hash"`
+	Hosts         map[string]struct{} `json:"hosts"`
+	ProtocolMax   string              `json:"protocol_max"`
+	ProtocolMin   string              `json:"protocol_min"`
+	Pruning       *int                `json:"pruning"`
+	ServerVersion string              `json:"server_version"`
+	HashFunction  string              `json:"hash_function"`
+}
+
+func serverFeatures(sess *session, w *wallet.Wallet,
+	params []json.RawMessage) (interface{}, error) {
+
+	return &featuresResult{
+		GenesisHash:   w.ChainParams().GenesisHash.String(),
+		Hosts:         map[string]struct{}{},
+		ProtocolMax:   protocolVersion,
+		ProtocolMin:   protocolVersion,
+		ServerVersion: sess.server.serverVersion(),
+		HashFunction:  "sha256",
+	}, nil
+}
+
+// serverPeersSubscribe returns no peers, since lbcwallet serves only its own
+// wallet.
+func serverPeersSubscribe(sess *session, w *wallet.Wallet,
+	params []json.RawMessage) (interface{}, error) {
+
+	return []interface{}{}, nil
+}
+
+func serverPing(sess *session, w *wallet.Wallet,
+	params []json.RawMessage) (interface{}, error) {
+
+	return nil, nil
+}
+
+func (s *Server) serverVersion() string {
+	return "lbcwallet " + s.opts.Version
+}
+
+// serverVersion negotiates the protocol version.  Only a single version is
+// served, which the client must accept.
+func serverVersion(sess *session, w *wallet.Wallet,
+	params []json.RawMessage) (interface{}, error) {
+
+	var client string
+	var versions json.RawMessage
+	if err := parseParams(params, 0, &client, &versions); err != nil {
+		return nil, err
+	}
+	if len(versions) != 0 && !acceptsVersion(versions) {
+		return nil, &Error{
+			Code: codeBadRequest,
+			Message: fmt.Sprintf("unsupported protocol version, "+
+				"only %s is supported", protocolVersion),
+		}
+	}
+	return []string{sess.server.serverVersion(), protocolVersion}, nil
+}
+
+// acceptsVersion returns whether the protocol version, or range of versions
+// given as [min, max], of a client includes the served version.
+func acceptsVersion(versions json.RawMessage) bool {
+	var version string
+	if json.Unmarshal(versions, &version) == nil {
+		return compareVersions(version, protocolVersion) == 0
+	}
+	var versionRange []string
+	if json.Unmarshal(versions, &versionRange) != nil ||
+		len(versionRange) != 2 {
+
+		return false
+	}
+	return compareVersions(versionRange[0], protocolVersion) <= 0 &&
+		compareVersions(protocolVersion, versionRange[1]) <= 0
+}
+
+// compareVersions compares two dotted version numbers.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for len(as) < len(bs) {
+		as = append(as, "0")
+	}
+	for len(bs) < len(as) {
+		bs = append(bs, "0")
+	}
+	for i := range as {
+		var x, y int
+		fmt.Sscan(as[i], &x)
+		fmt.Sscan(bs[i], &y)
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,384 @@
+// Package electrum implements a server for the Electrum protocol, as served by
+// ElectrumX, exposing the addresses and transactions of a wallet so that
+// Electrum based tooling may use lbcwallet as its backend.
+//
+// Only the history of the output scripts paid by the wallet is known, so
+// requests for any other script hash return an empty history.
+package electrum
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/lbryio/lbcwallet/wallet"
+)
+
+const (
+	// protocolVersion is the version of the Electrum protocol served.
+	protocolVersion = "1.4"
+
+	// maxRequestSize is the longest line read from a client.
+	maxRequestSize = 1 << 20
+
+	// sendQueueSize is the number of responses and notifications queued
+	// for a client before it is disconnected for not reading them.
+	sendQueueSize = 256
+
+	// idleTimeout is the time after which a client which sent no request
+	// is disconnected.  Clients are expected to ping in between.
+	idleTimeout = 10 * time.Minute
+)
+
+// Options contains the optional settings of a Server.
+type Options struct {
+	// Banner is returned by server.banner.
+	Banner string
+
+	// Version is the software version returned by server.version.
+	Version string
+}
+
+// Server serves the Electrum protocol for a wallet.
+type Server struct {
+	opts Options
+
+	mtx      sync.Mutex
+	wallet   *wallet.Wallet
+	sessions map[*session]struct{}
+
+	// activity caches the script activity of the wallet, keyed by
+	// script hash, until the wallet transactions change.
+	activityMtx sync.Mutex
+	activity    map[string]*wallet.ScriptActivity
+
+	listeners []net.Listener
+	wg        sync.WaitGroup
+	quit      chan struct{}
+	quitOnce  sync.Once
+}
+
+// NewServer creates a server accepting clients on the listeners.  Requests
+// fail until a wallet is registered with RegisterWallet.
+func NewServer(opts *Options, listeners []net.Listener) *Server {
+	s := &Server{
+		opts:      *opts,
+		sessions:  make(map[*session]struct{}),
+		listeners: listeners,
+		quit:      make(chan struct{}),
+	}
+	for _, lis := range listeners {
+		s.serve(lis)
+	}
+	return s
+}
+
+func (s *Server) serve(lis net.Listener) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		log.Infof("Electrum server listening on %s", lis.Addr())
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				select {
+				case <-s.quit:
+				default:
+					log.Errorf("Electrum listener %s failed: %v",
+						lis.Addr(), err)
+				}
+				return
+			}
+			s.startSession(conn)
+		}
+	}()
+}
+
+// RegisterWallet serves the wallet to the clients and starts notifying their
+// subscriptions of its changes.
+func (s *Server) RegisterWallet(w *wallet.Wallet) {
+	s.mtx.Lock()
+	s.wallet = w
+	s.mtx.Unlock()
+
+	client := w.NtfnServer.TransactionNotifications()
+	s.wg.Add(1)
+	go s.notifySubscriptions(w, client)
+}
+
+// ListenAddrs returns the addresses the server listens on.
+func (s *Server) ListenAddrs() []net.Addr {
+	addrs := make([]net.Addr, len(s.listeners))
+	for i, lis := range s.listeners {
+		addrs[i] = lis.Addr()
+	}
+	return addrs
+}
+
+// Stop closes the listeners and disconnects every client.
+func (s *Server) Stop() {
+	s.quitOnce.Do(func() {
+		close(s.quit)
+		for _, lis := range s.listeners {
+			lis.Close()
+		}
+		s.mtx.Lock()
+		for sess := range s.sessions {
+			sess.conn.Close()
+		}
+		s.mtx.Unlock()
+	})
+	s.wg.Wait()
+}
+
+func (s *Server) loadedWallet() (*wallet.Wallet, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.wallet == nil {
+		return nil, errWalletNotLoaded
+	}
+	return s.wallet, nil
+}
+
+// scriptActivity returns the cached activity of a script hash, which is nil
+// if no wallet transaction pays to it.
+func (s *Server) scriptActivity(w *wallet.Wallet,
+	scriptHash string) (*wallet.ScriptActivity, error) {
+
+	s.activityMtx.Lock()
+	defer s.activityMtx.Unlock()
+
+	if s.activity == nil {
+		activity, err := w.ScriptActivity()
+		if err != nil {
+			return nil, err
+		}
+		s.activity = make(map[string]*wallet.ScriptActivity, len(activity))
+		for script, a := range activity {
+			s.activity[scriptHashString([]byte(script))] = a
+		}
+	}
+	return s.activity[scriptHash], nil
+}
+
+func (s *Server) invalidateActivity() {
+	s.activityMtx.Lock()
+	s.activity = nil
+	s.activityMtx.Unlock()
+}
+
+// notifySubscriptions notifies the clients of the new statuses of the script
+// hashes they subscribed to, and of new blocks, as the wallet changes.
+func (s *Server) notifySubscriptions(w *wallet.Wallet,
+	client wallet.TransactionNotificationsClient) {
+
+	defer s.wg.Done()
+	defer client.Done()
+
+	for {
+		select {
+		case n, ok := <-client.C:
+			if !ok {
+				return
+			}
+			s.invalidateActivity()
+
+			var header *headerResult
+			if len(n.AttachedBlocks) != 0 {
+				tip := n.AttachedBlocks[len(n.AttachedBlocks)-1]
+				h, err := blockHeader(w, tip.Hash, tip.Height)
+				if err != nil {
+					log.Warnf("Unable to notify block %v: %v",
+						tip.Hash, err)
+				} else {
+					header = h
+				}
+			}
+
+			s.mtx.Lock()
+			sessions := make([]*session, 0, len(s.sessions))
+			for sess := range s.sessions {
+				sessions = append(sessions, sess)
+			}
+			s.mtx.Unlock()
+			for _, sess := range sessions {
+				if header != nil {
+					sess.notifyHeader(header)
+				}
+				sess.notifyStatuses(w)
+			}
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// session is a connected client.
+type session struct {
+	server *Server
+	conn   net.Conn
+	send   chan []byte
+	quit   chan struct{}
+
+	mtx sync.Mutex
+
+	// statuses maps the script hashes subscribed to by the client to
+	// the status last sent.
+	statuses map[string]string
+	headers  bool
+}
+
+func (s *Server) startSession(conn net.Conn) {
+	sess := &session{
+		server:   s,
+		conn:     conn,
+		send:     make(chan []byte, sendQueueSize),
+		quit:     make(chan struct{}),
+		statuses: make(map[string]string),
+	}
+
+	s.mtx.Lock()
+	select {
+	case <-s.quit:
+		s.mtx.Unlock()
+		conn.Close()
+		return
+	default:
+	}
+	s.sessions[sess] = struct{}{}
+	s.mtx.Unlock()
+
+	log.Debugf("Electrum client %s connected", conn.RemoteAddr())
+	s.wg.Add(2)
+	go sess.readRequests()
+	go sess.writeMessages()
+}
+
+// readRequests handles the requests of the client, one per line, until it
+// disconnects.
+func (sess *session) readRequests() {
+	defer sess.server.wg.Done()
+	defer func() {
+		sess.server.mtx.Lock()
+		delete(sess.server.sessions, sess)
+		sess.server.mtx.Unlock()
+		close(sess.quit)
+		sess.conn.Close()
+		log.Debugf("Electrum client %s disconnected",
+			sess.conn.RemoteAddr())
+	}()
+
+	scanner := bufio.NewScanner(sess.conn)
+	scanner.Buffer(make([]byte, 4096), maxRequestSize)
+	for {
+		sess.conn.SetReadDeadline(time.Now().Add(idleTimeout))
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				log.Debugf("Electrum client %s: %v",
+					sess.conn.RemoteAddr(), err)
+			}
+			return
+		}
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		resp := sess.handleLine(line)
+		if resp != nil && !sess.queue(resp) {
+			return
+		}
+	}
+}
+
+// writeMessages writes the queued responses and notifications to the client.
+func (sess *session) writeMessages() {
+	defer sess.server.wg.Done()
+
+	for {
+		select {
+		case msg := <-sess.send:
+			_, err := sess.conn.Write(append(msg, '\n'))
+			if err != nil {
+				sess.conn.Close()
+				return
+			}
+		case <-sess.quit:
+			return
+		}
+	}
+}
+
+// queue queues a message for the client, disconnecting it if too many
+// messages are already queued.  It returns false if the client is
+// disconnected.
+func (sess *session) queue(msg []byte) bool {
+	select {
+	case sess.send <- msg:
+		return true
+	case <-sess.quit:
+		return false
+	default:
+		log.Warnf("Disconnecting Electrum client %s which is not "+
+			"reading its messages", sess.conn.RemoteAddr())
+		sess.conn.Close()
+		return false
+	}
+}
+
+func (sess *session) notify(method string, params ...interface{}) {
+	msg, err := json.Marshal(&notification{
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		log.Errorf("Unable to marshal %s notification: %v", method, err)
+		return
+	}
+	sess.queue(msg)
+}
+
+func (sess *session) notifyHeader(header *headerResult) {
+	sess.mtx.Lock()
+	subscribed := sess.headers
+	sess.mtx.Unlock()
+	if subscribed {
+		sess.notify("blockchain.headers.subscribe", header)
+	}
+}
+
+// notifyStatuses notifies the client of the script hashes it subscribed to
+// whose status changed.
+func (sess *session) notifyStatuses(w *wallet.Wallet) {
+	sess.mtx.Lock()
+	scriptHashes := make([]string, 0, len(sess.statuses))
+	for scriptHash := range sess.statuses {
+		scriptHashes = append(scriptHashes, scriptHash)
+	}
+	sess.mtx.Unlock()
+
+	for _, scriptHash := range scriptHashes {
+		a, err := sess.server.scriptActivity(w, scriptHash)
+		if err != nil {
+			log.Errorf("Unable to read the wallet activity: %v", err)
+			return
+		}
+		status := scriptStatus(a)
+
+		sess.mtx.Lock()
+		last, ok := sess.statuses[scriptHash]
+		changed := ok && last != status
+		if changed {
+			sess.statuses[scriptHash] = status
+		}
+		sess.mtx.Unlock()
+		if changed {
+			sess.notify("blockchain.scripthash.subscribe", scriptHash,
+				statusResult(status))
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package electrum
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/lbryio/lbcd/blockchain"
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/wallet"
+)
+
+// TestScriptHash checks the script hash of the example in the protocol
+// documentation.
+func TestScriptHash(t *testing.T) {
+	t.Parallel()
+
+	script, _ := hex.DecodeString("76a91462e907b15cbf27d5425399ebf6f0fb50ebb88f1888ac")
+	want := "8b01df4e368ea28f8dc0423bcf7a4923e3a12d307c875e47a0cfbf90b5c39161"
+	if got := scriptHashString(script); got != want {
+		t.Fatalf("got script hash %s, want %s", got, want)
+	}
+}
+
+func TestScriptStatus(t *testing.T) {
+	t.Parallel()
+
+	if status := scriptStatus(nil); status != "" {
+		t.Fatalf("got status %q without history", status)
+	}
+
+	a := &wallet.ScriptActivity{
+		Transactions: []wallet.ScriptTx{
+			{Hash: chainhash.Hash{1}, Height: 10},
+			{Hash: chainhash.Hash{2}, Height: -1},
+			{Hash: chainhash.Hash{3}, Height: -1, UnminedInputs: true},
+		},
+	}
+	hist := history(a, false)
+	if len(hist) != 3 || hist[0].Height != 10 || hist[1].Height != 0 ||
+		hist[2].Height != -1 {
+
+		t.Fatalf("unexpected history %+v", hist)
+	}
+	if mempool := history(a, true); len(mempool) != 2 {
+		t.Fatalf("got %d mempool transactions, want 2", len(mempool))
+	}
+
+	// Changing the height of a transaction changes the status.
+	status := scriptStatus(a)
+	if len(status) != 64 {
+		t.Fatalf("invalid status %q", status)
+	}
+	a.Transactions[1].Height = 11
+	if scriptStatus(a) == status {
+		t.Fatal("status did not change")
+	}
+}
+
+func TestMerkleBranch(t *testing.T) {
+	t.Parallel()
+
+	for n := 1; n <= 7; n++ {
+		txs := make([]*btcutil.Tx, n)
+		hashes := make([]chainhash.Hash, n)
+		for i := range txs {
+			msgTx := wire.NewMsgTx(wire.TxVersion)
+			msgTx.LockTime = uint32(i)
+			txs[i] = btcutil.NewTx(msgTx)
+			hashes[i] = msgTx.TxHash()
+		}
+		store := blockchain.BuildMerkleTreeStore(txs, false)
+		root := store[len(store)-1]
+
+		for pos := range hashes {
+			hash := hashes[pos]
+			index := pos
+			for _, sibling := range merkleBranch(hashes, pos) {
+				var buf [chainhash.HashSize * 2]byte
+				if index%2 == 0 {
+					copy(buf[:], hash[:])
+					copy(buf[chainhash.HashSize:], sibling[:])
+				} else {
+					copy(buf[:], sibling[:])
+					copy(buf[chainhash.HashSize:], hash[:])
+				}
+				hash = chainhash.DoubleHashH(buf[:])
+				index /= 2
+			}
+			if hash != *root {
+				t.Errorf("%d transactions: branch of %d does not "+
+					"prove the merkle root", n, pos)
+			}
+		}
+	}
+}
+
+func TestAcceptsVersion(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		versions string
+		accepted bool
+	}{
+		{`"1.4"`, true},
+		{`"1.4.0"`, true},
+		{`"1.2"`, false},
+		{`["1.2", "1.4.2"]`, true},
+		{`["1.4.1", "1.5"]`, false},
+		{`["1.4"]`, false},
+	}
+	for _, test := range tests {
+		got := acceptsVersion(json.RawMessage(test.versions))
+		if got != test.accepted {
+			t.Errorf("acceptsVersion(%s) = %v, want %v", test.versions,
+				got, test.accepted)
+		}
+	}
+}
+
+// TestSession ensures requests are answered over a connection, one per line
+// and in batches.
+func TestSession(t *testing.T) {
+	t.Parallel()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewServer(&Options{}, []net.Listener{lis})
+	defer s.Stop()
+
+	conn, err := net.Dial("tcp", lis.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	roundTrip := func(req string) string {
+		if _, err := conn.Write([]byte(req + "\n")); err != nil {
+			t.Fatal(err)
+		}
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		return line
+	}
+
+	var resp response
+	err = json.Unmarshal([]byte(roundTrip(
+		`{"jsonrpc":"2.0","id":1,"method":"wallet.unknown"}`)), &resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Error == nil || resp.Error.Code != codeMethodNotFound ||
+		string(resp.ID) != "1" {
+
+		t.Fatalf("unexpected response %+v", resp)
+	}
+
+	// Requests fail until a wallet is registered.  Notifications are not
+	// answered, so only the response of the second request is expected.
+	var batch []response
+	err = json.Unmarshal([]byte(roundTrip(`[`+
+		`{"jsonrpc":"2.0","method":"server.ping"},`+
+		`{"jsonrpc":"2.0","id":"b","method":"server.ping"}]`)), &batch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(batch) != 1 || string(batch[0].ID) != `"b"` ||
+		batch[0].Error == nil ||
+		batch[0].Error.Message != errWalletNotLoaded.Message {
+
+		t.Fatalf("unexpected batch response %+v", batch)
+	}
+
+	err = json.Unmarshal([]byte(roundTrip(`{"id":`)), &resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Error == nil || resp.Error.Code != codeInvalidRequest {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+}
@@ -0,0 +1,157 @@
+package legacyrpc
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lbryio/lbcd/chaincfg"
+	"github.com/lbryio/lbcd/txscript"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcutil/base58"
+	"github.com/lbryio/lbcutil/bech32"
+	"github.com/lbryio/lbcwallet/netparams"
+)
+
+// witnessAddress is implemented by the addresses paying to a witness program.
+type witnessAddress interface {
+	WitnessVersion() byte
+	WitnessProgram() []byte
+}
+
+// addressScriptType returns the name of the class of the output script paying
+// to an address.
+func addressScriptType(addr btcutil.Address) string {
+	// The script classes of txscript predate taproot.
+	if _, ok := addr.(*btcutil.AddressTaproot); ok {
+		return "witness_v1_taproot"
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return txscript.NonStandardTy.String()
+	}
+	return txscript.GetScriptClass(pkScript).String()
+}
+
+// addressNetwork returns the first network the wallet runs on which matches,
+// or nil if there is none.
+func addressNetwork(match func(*chaincfg.Params) bool) *netparams.Params {
+	for _, p := range netparams.Networks {
+		if match(p.Params) {
+			return p
+		}
+	}
+	return nil
+}
+
+// invalidAddressReason explains why a string is not an address of a network,
+// for the string that failed to decode into one.  The network the address is
+// meant for is also returned when its prefix is known.
+func invalidAddressReason(s string, params *chaincfg.Params) (string, string) {
+	if i := strings.LastIndexByte(s, '1'); i > 1 {
+		hrp := strings.ToLower(s[:i])
+		net := addressNetwork(func(p *chaincfg.Params) bool {
+			return p.Bech32HRPSegwit == hrp
+		})
+		if net != nil {
+			reason := segwitAddressReason(s, net.Params, params)
+			if reason == "" {
+				reason = decodeAddressReason(s, params)
+			}
+			return reason, net.Name
+		}
+	}
+
+	// Serialized public keys are not base58 encoded.
+	if len(s) == 66 || len(s) == 130 {
+		if _, err := hex.DecodeString(s); err == nil {
+			return decodeAddressReason(s, params), ""
+		}
+	}
+
+	payload, version, err := base58.CheckDecode(s)
+	switch {
+	case err == base58.ErrChecksum:
+		return "bad checksum", ""
+	case err != nil:
+		return "unknown address format", ""
+	case len(payload) != 20:
+		return fmt.Sprintf("invalid hash length %d", len(payload)), ""
+	}
+	if version == params.PubKeyHashAddrID ||
+		version == params.ScriptHashAddrID {
+
+		return decodeAddressReason(s, params), ""
+	}
+	net := addressNetwork(func(p *chaincfg.Params) bool {
+		return version == p.PubKeyHashAddrID ||
+			version == p.ScriptHashAddrID
+	})
+	if net == nil {
+		return fmt.Sprintf("unknown address version %d", version), ""
+	}
+	return wrongNetworkReason(net.Name, params), net.Name
+}
+
+// segwitAddressReason explains why a bech32 string with the human-readable
+// part of a network is not a segwit address of the wallet network, or returns
+// an empty string when it finds no reason.
+func segwitAddressReason(s string, net, params *chaincfg.Params) string {
+	_, data, encoding, err := bech32.DecodeGeneric(s)
+	var checksumErr bech32.ErrInvalidChecksum
+	switch {
+	case errors.As(err, &checksumErr):
+		return "bad checksum"
+	case err != nil:
+		return fmt.Sprintf("invalid bech32 encoding: %v", err)
+	case len(data) == 0:
+		return "missing witness version"
+	}
+	if net.Name != params.Name {
+		return wrongNetworkReason(net.Name, params)
+	}
+
+	version := data[0]
+	if version > 1 {
+		return fmt.Sprintf("unsupported witness version %d", version)
+	}
+	program, err := bech32.ConvertBits(data[1:], 5, 8, false)
+	if err != nil {
+		return fmt.Sprintf("invalid witness program: %v", err)
+	}
+	switch {
+	case version == 0 && len(program) != 20 && len(program) != 32,
+		version == 1 && len(program) != 32:
+
+		return fmt.Sprintf("invalid witness program length %d for "+
+			"witness version %d", len(program), version)
+	case version == 0 && encoding != bech32.Version0,
+		version == 1 && encoding != bech32.VersionM:
+
+		return fmt.Sprintf("bad checksum: wrong bech32 variant for "+
+			"witness version %d", version)
+	}
+	return ""
+}
+
+// wrongNetworkReason is the reason an address of a network is invalid on the
+// wallet network.
+func wrongNetworkReason(name string, params *chaincfg.Params) string {
+	return fmt.Sprintf("wrong network prefix: address is for %s, not %s",
+		name, params.Name)
+}
+
+// decodeAddressReason is the reason an address failed to decode when no
+// specific one is found.
+func decodeAddressReason(s string, params *chaincfg.Params) string {
+	addr, err := btcutil.DecodeAddress(s, params)
+	switch {
+	case err != nil:
+		return err.Error()
+	case !addr.IsForNet(params):
+		return fmt.Sprintf("address is not intended for %s",
+			params.Name)
+	}
+	return "invalid address"
+}
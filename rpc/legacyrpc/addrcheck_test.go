@@ -0,0 +1,153 @@
+package legacyrpc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lbryio/lbcd/chaincfg"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcutil/bech32"
+)
+
+// segwitString encodes a witness program of a version with a bech32 variant.
+func segwitString(t *testing.T, hrp string, version byte, program []byte,
+	m bool) string {
+
+	data, err := bech32.ConvertBits(program, 8, 5, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encode := bech32.Encode
+	if m {
+		encode = bech32.EncodeM
+	}
+	s, err := encode(hrp, append([]byte{version}, data...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// flipLast changes the last character of an address, which is part of its
+// checksum.
+func flipLast(s string) string {
+	last := "q"
+	if strings.HasSuffix(s, last) {
+		last = "p"
+	}
+	return s[:len(s)-1] + last
+}
+
+func TestInvalidAddressReason(t *testing.T) {
+	main := &chaincfg.MainNetParams
+	testnet := &chaincfg.TestNet3Params
+	hash := make([]byte, 20)
+
+	p2pkh, err := btcutil.NewAddressPubKeyHash(hash, main)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2wpkh, err := btcutil.NewAddressWitnessPubKeyHash(hash, testnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	badBase58 := flipLast(p2pkh.EncodeAddress())
+	badBech32 := flipLast(segwitString(t, main.Bech32HRPSegwit, 0, hash,
+		false))
+
+	tests := []struct {
+		name    string
+		addr    string
+		params  *chaincfg.Params
+		reason  string
+		network string
+	}{{
+		name:    "base58 of another network",
+		addr:    p2pkh.EncodeAddress(),
+		params:  testnet,
+		reason:  "wrong network prefix",
+		network: "mainnet",
+	}, {
+		name:   "base58 bad checksum",
+		addr:   badBase58,
+		params: main,
+		reason: "bad checksum",
+	}, {
+		name:   "not an address",
+		addr:   "not an address",
+		params: main,
+		reason: "unknown address format",
+	}, {
+		name:    "segwit of another network",
+		addr:    p2wpkh.EncodeAddress(),
+		params:  main,
+		reason:  "wrong network prefix",
+		network: "testnet3",
+	}, {
+		name:    "segwit bad checksum",
+		addr:    badBech32,
+		params:  main,
+		reason:  "bad checksum",
+		network: "mainnet",
+	}, {
+		name: "unsupported witness version",
+		addr: segwitString(t, main.Bech32HRPSegwit, 2,
+			make([]byte, 32), true),
+		params:  main,
+		reason:  "unsupported witness version 2",
+		network: "mainnet",
+	}, {
+		name: "witness program length",
+		addr: segwitString(t, main.Bech32HRPSegwit, 0,
+			make([]byte, 24), false),
+		params:  main,
+		reason:  "invalid witness program length 24",
+		network: "mainnet",
+	}, {
+		name: "bech32m for witness version 0",
+		addr: segwitString(t, main.Bech32HRPSegwit, 0, hash,
+			true),
+		params:  main,
+		reason:  "bad checksum: wrong bech32 variant",
+		network: "mainnet",
+	}}
+	for _, test := range tests {
+		if _, err := decodeAddress(test.addr, test.params); err == nil {
+			t.Fatalf("%s: %s decoded", test.name, test.addr)
+		}
+		reason, network := invalidAddressReason(test.addr, test.params)
+		if !strings.HasPrefix(reason, test.reason) {
+			t.Errorf("%s: reason %q, want %q", test.name, reason,
+				test.reason)
+		}
+		if network != test.network {
+			t.Errorf("%s: network %q, want %q", test.name, network,
+				test.network)
+		}
+	}
+}
+
+func TestAddressScriptType(t *testing.T) {
+	params := &chaincfg.MainNetParams
+	hash := make([]byte, 20)
+
+	p2pkh, _ := btcutil.NewAddressPubKeyHash(hash, params)
+	p2sh, _ := btcutil.NewAddressScriptHashFromHash(hash, params)
+	p2wpkh, _ := btcutil.NewAddressWitnessPubKeyHash(hash, params)
+	p2tr, _ := btcutil.NewAddressTaproot(make([]byte, 32), params)
+	tests := []struct {
+		addr btcutil.Address
+		want string
+	}{
+		{p2pkh, "pubkeyhash"},
+		{p2sh, "scripthash"},
+		{p2wpkh, "witness_v0_keyhash"},
+		{p2tr, "witness_v1_taproot"},
+	}
+	for _, test := range tests {
+		if got := addressScriptType(test.addr); got != test.want {
+			t.Errorf("%s: script type %s, want %s", test.addr, got,
+				test.want)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package legacyrpc
+
+import (
+	"encoding/hex"
+	"sync"
+
+	"github.com/lbryio/lbcd/btcjson"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/rpc/walletjson"
+	"github.com/lbryio/lbcwallet/wallet"
+)
+
+// errWebsocketOnly is returned to HTTP POST clients for requests which are
+// only supported over websockets.
+var errWebsocketOnly = &btcjson.RPCError{
+	Code:    btcjson.ErrRPCInvalidRequest.Code,
+	Message: "Method is only available to websocket clients",
+}
+
+// addrNotifier keeps the addresses each websocket client watches.  Every
+// address is watched with the wallet once per client watching it.
+type addrNotifier struct {
+	mtx     sync.Mutex
+	clients map[*websocketClient]map[string]btcutil.Address
+}
+
+func newAddrNotifier() *addrNotifier {
+	return &addrNotifier{
+		clients: make(map[*websocketClient]map[string]btcutil.Address),
+	}
+}
+
+// add records the addresses watched by a client, returning those it did not
+// already watch.
+func (n *addrNotifier) add(client *websocketClient,
+	addrs []btcutil.Address) []btcutil.Address {
+
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	watched, ok := n.clients[client]
+	if !ok {
+		watched = make(map[string]btcutil.Address)
+		n.clients[client] = watched
+	}
+	var added []btcutil.Address
+	for _, addr := range addrs {
+		key := addr.EncodeAddress()
+		if _, ok := watched[key]; ok {
+			continue
+		}
+		watched[key] = addr
+		added = append(added, addr)
+	}
+	return added
+}
+
+// remove removes addresses watched by a client, returning those it watched.
+func (n *addrNotifier) remove(client *websocketClient,
+	addrs []btcutil.Address) []btcutil.Address {
+
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	watched := n.clients[client]
+	var removed []btcutil.Address
+	for _, addr := range addrs {
+		key := addr.EncodeAddress()
+		if _, ok := watched[key]; !ok {
+			continue
+		}
+		delete(watched, key)
+		removed = append(removed, addr)
+	}
+	if len(watched) == 0 {
+		delete(n.clients, client)
+	}
+	return removed
+}
+
+// removeClient removes all addresses watched by a disconnecting client and
+// returns them.  It must be called before waiting on the wait group of the
+// client, as notifications to the client are added to it.
+func (n *addrNotifier) removeClient(client *websocketClient) []btcutil.Address {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	watched := n.clients[client]
+	delete(n.clients, client)
+	addrs := make([]btcutil.Address, 0, len(watched))
+	for _, addr := range watched {
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
+
+// watchingClients returns the clients watching an address, each added to its
+// wait group for the notification.
+func (n *addrNotifier) watchingClients(addr btcutil.Address) []*websocketClient {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	key := addr.EncodeAddress()
+	var clients []*websocketClient
+	for client, watched := range n.clients {
+		if _, ok := watched[key]; ok {
+			client.wg.Add(1)
+			clients = append(clients, client)
+		}
+	}
+	return clients
+}
+
+// relayWatchedAddresses sends the payments to watched addresses to the
+// websocket clients watching them, until the server is stopped.
+func (s *Server) relayWatchedAddresses(w *wallet.Wallet) {
+	defer s.wg.Done()
+
+	client := w.NtfnServer.WatchedAddressNotifications()
+	defer client.Done()
+	for {
+		select {
+		case n, ok := <-client.C:
+			if !ok {
+				return
+			}
+			s.relayWatchedAddress(n)
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+func (s *Server) relayWatchedAddress(n *wallet.WatchedAddressNotification) {
+	payment := walletjson.WatchedAddressPayment{
+		Address:     n.Address.EncodeAddress(),
+		TxID:        n.TxHash.String(),
+		Hex:         hex.EncodeToString(n.Transaction),
+		Vout:        n.Outputs,
+		Amount:      n.Amount.ToBTC(),
+		BlockHeight: n.Height,
+	}
+	if n.BlockHash != nil {
+		payment.BlockHash = n.BlockHash.String()
+	}
+	ntfn := walletjson.NewWatchedAddressTxNtfn(payment)
+	b, err := btcjson.MarshalCmd(btcjson.RpcVersion1, nil, ntfn)
+	if err != nil {
+		log.Errorf("Unable to marshal watchedaddresstx notification: %v",
+			err)
+		return
+	}
+
+	for _, wsc := range s.addrNtfns.watchingClients(n.Address) {
+		wsc.notify(b)
+		wsc.wg.Done()
+	}
+}
+
+// watchAddresses handles a watchaddresses request from a websocket client.
+func (s *Server) watchAddresses(req *btcjson.Request,
+	wsc *websocketClient) (interface{}, *btcjson.RPCError) {
+
+	icmd, err := btcjson.UnmarshalCmd(req)
+	if err != nil {
+		return nil, btcjson.ErrRPCInvalidRequest
+	}
+	cmd := icmd.(*walletjson.WatchAddressesCmd)
+
+	s.handlerMu.Lock()
+	w := s.wallet
+	s.handlerMu.Unlock()
+	if w == nil {
+		return nil, &ErrUnloadedWallet
+	}
+
+	addrs, jsonErr := decodeAddresses(cmd.Addresses, w)
+	if jsonErr != nil {
+		return nil, jsonErr
+	}
+
+	added := s.addrNtfns.add(wsc, addrs)
+	if err := w.WatchAddresses(added); err != nil {
+		return nil, jsonError(err)
+	}
+	return nil, nil
+}
+
+// unwatchAddresses handles an unwatchaddresses request from a websocket
+// client.
+func (s *Server) unwatchAddresses(req *btcjson.Request,
+	wsc *websocketClient) (interface{}, *btcjson.RPCError) {
+
+	icmd, err := btcjson.UnmarshalCmd(req)
+	if err != nil {
+		return nil, btcjson.ErrRPCInvalidRequest
+	}
+	cmd := icmd.(*walletjson.UnwatchAddressesCmd)
+
+	s.handlerMu.Lock()
+	w := s.wallet
+	s.handlerMu.Unlock()
+	if w == nil {
+		return nil, &ErrUnloadedWallet
+	}
+
+	addrs, jsonErr := decodeAddresses(cmd.Addresses, w)
+	if jsonErr != nil {
+		return nil, jsonErr
+	}
+
+	removed := s.addrNtfns.remove(wsc, addrs)
+	w.UnwatchAddresses(removed)
+	return nil, nil
+}
+
+// unwatchClientAddresses removes the addresses watched by a disconnecting
+// websocket client.
+func (s *Server) unwatchClientAddresses(wsc *websocketClient) {
+	addrs := s.addrNtfns.removeClient(wsc)
+	if len(addrs) == 0 {
+		return
+	}
+
+	s.handlerMu.Lock()
+	w := s.wallet
+	s.handlerMu.Unlock()
+	if w != nil {
+		w.UnwatchAddresses(addrs)
+	}
+}
+
+func decodeAddresses(strs []string, w *wallet.Wallet) ([]btcutil.Address,
+	*btcjson.RPCError) {
+
+	addrs := make([]btcutil.Address, 0, len(strs))
+	for _, s := range strs {
+		addr, err := decodeAddress(s, w.ChainParams())
+		if err != nil {
+			return nil, jsonError(err)
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs, nil
+}
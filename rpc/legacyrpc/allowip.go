@@ -0,0 +1,87 @@
+package legacyrpc
+
+import (
+	"net"
+	"sync"
+)
+
+// allowList holds the networks of the clients whose connections are accepted
+// by the listeners of a server.  The networks may be replaced while the
+// server is running, and apply to the connections accepted afterwards.
+type allowList struct {
+	mtx     sync.RWMutex
+	allowed []*net.IPNet
+}
+
+// set replaces the allowed networks.  Every client is allowed when allowed is
+// empty.
+func (l *allowList) set(allowed []*net.IPNet) {
+	l.mtx.Lock()
+	l.allowed = allowed
+	l.mtx.Unlock()
+}
+
+// allows returns whether a client address is allowed.
+func (l *allowList) allows(addr net.Addr) bool {
+	l.mtx.RLock()
+	allowed := l.allowed
+	l.mtx.RUnlock()
+	if len(allowed) == 0 {
+		return true
+	}
+
+	var ip net.IP
+	switch addr := addr.(type) {
+	case *net.TCPAddr:
+		ip = addr.IP
+	default:
+		host, _, err := net.SplitHostPort(addr.String())
+		if err != nil {
+			return false
+		}
+		ip = net.ParseIP(host)
+	}
+	if ip == nil {
+		return false
+	}
+	if ip.IsLoopback() {
+		return true
+	}
+	for _, n := range allowed {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowListener is a listener which only accepts the connections of clients
+// whose IP address is in one of the allowed networks or is a loopback address.
+// The connections of other clients are closed as soon as they are accepted,
+// before any request is read.
+type allowListener struct {
+	net.Listener
+	list *allowList
+}
+
+// newAllowListener returns a listener accepting the connections of lis from
+// the networks of list.
+func newAllowListener(lis net.Listener, list *allowList) net.Listener {
+	return &allowListener{Listener: lis, list: list}
+}
+
+// Accept waits for and returns the next connection of an allowed client.
+func (l *allowListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+		if l.list.allows(conn.RemoteAddr()) {
+			return conn, nil
+		}
+		log.Warnf("Refusing RPC connection from %s, which is not "+
+			"in rpcallowip", conn.RemoteAddr())
+		conn.Close()
+	}
+}
@@ -0,0 +1,78 @@
+package legacyrpc
+
+import (
+	"net"
+	"testing"
+)
+
+func TestAllowListener(t *testing.T) {
+	_, lan, _ := net.ParseCIDR("192.168.1.0/24")
+	l := &allowList{allowed: []*net.IPNet{lan}}
+
+	tests := []struct {
+		addr  net.Addr
+		allow bool
+	}{
+		{&net.TCPAddr{IP: net.ParseIP("192.168.1.20"), Port: 1}, true},
+		{&net.TCPAddr{IP: net.ParseIP("192.168.2.20"), Port: 1}, false},
+		{&net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 1}, true},
+		{&net.TCPAddr{IP: net.ParseIP("::1"), Port: 1}, true},
+		{&net.TCPAddr{IP: net.ParseIP("fe80::1"), Port: 1, Zone: "eth0"}, false},
+		{&net.UnixAddr{Name: "/tmp/socket", Net: "unix"}, false},
+	}
+	for _, test := range tests {
+		if got := l.allows(test.addr); got != test.allow {
+			t.Errorf("%v: got allowed %v, want %v", test.addr, got,
+				test.allow)
+		}
+	}
+
+	// Every client is allowed without allowed networks.
+	if !(&allowList{}).allows(tests[1].addr) {
+		t.Fatal("client refused without allowed networks")
+	}
+
+	// Loopback clients are accepted even when their network is not
+	// allowed.
+	lis, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+	_, none, _ := net.ParseCIDR("10.0.0.0/8")
+	allowed := newAllowListener(lis, &allowList{
+		allowed: []*net.IPNet{none},
+	})
+	go func() {
+		conn, err := net.Dial("tcp4", lis.Addr().String())
+		if err == nil {
+			conn.Close()
+		}
+	}()
+	conn, err := allowed.Accept()
+	if err != nil {
+		t.Fatalf("Accept: %v", err)
+	}
+	conn.Close()
+}
+
+// TestSetAllowedIPs ensures the allowed networks replaced while the server
+// runs apply to the connections accepted afterwards.
+func TestSetAllowedIPs(t *testing.T) {
+	s := NewServer(&Options{}, nil, nil)
+	lan := &net.TCPAddr{IP: net.ParseIP("192.168.1.20"), Port: 1}
+	if !s.allowed.allows(lan) {
+		t.Fatal("client refused without allowed networks")
+	}
+
+	_, none, _ := net.ParseCIDR("10.0.0.0/8")
+	s.SetAllowedIPs([]*net.IPNet{none})
+	if s.allowed.allows(lan) {
+		t.Fatal("client outside of the allowed networks accepted")
+	}
+
+	s.SetAllowedIPs(nil)
+	if !s.allowed.allows(lan) {
+		t.Fatal("client refused once every client is allowed")
+	}
+}
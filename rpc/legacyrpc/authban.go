@@ -0,0 +1,243 @@
+package legacyrpc
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	// authBackoffBase is the delay a client must wait after its first
+	// failed authentication attempt before it may try again.  The delay
+	// doubles with every further consecutive failure.
+	authBackoffBase = 100 * time.Millisecond
+
+	// authBackoffMax is the maximum delay between authentication attempts
+	// before the failure threshold is reached and the client is banned.
+	authBackoffMax = 10 * time.Second
+
+	// authBanMax is the longest a repeat offender can be banned for.
+	authBanMax = 24 * time.Hour
+
+	// authGlobalFailures is the number of failed authentication attempts
+	// of all hosts together within authGlobalWindow after which every
+	// attempt is refused until the window ends.
+	authGlobalFailures = 20
+	authGlobalWindow   = time.Minute
+)
+
+// authFailures tracks the failed authentication attempts of a single host.
+type authFailures struct {
+	count       int
+	bans        uint
+	retryAfter  time.Time
+	bannedUntil time.Time
+	lastFailure time.Time
+}
+
+// authLimiter protects the RPC server credentials from being brute forced by
+// requiring an exponentially increasing delay between failed authentication
+// attempts of a host, and banning hosts which reach a threshold of
+// consecutive failures.  Every ban of the same host lasts twice as long as the
+// previous one.
+//
+// The connections forwarded by a local reverse proxy or Tor onion service all
+// come from the same loopback address, so their clients are banned together.
+// Operators may exempt such addresses from the bans of single hosts, but the
+// failures of every host, exempt or not, still count against a limit of
+// authGlobalFailures per authGlobalWindow, after which all attempts are
+// refused until the window ends.
+type authLimiter struct {
+	mtx         sync.Mutex
+	threshold   int
+	banDuration time.Duration
+	exempt      []*net.IPNet
+	hosts       map[string]*authFailures
+	lastPrune   time.Time
+
+	// globalStart is the start of the current window of the global
+	// failure limit, and globalCount the failures counted within it.
+	globalStart time.Time
+	globalCount int
+
+	now func() time.Time
+}
+
+// newAuthLimiter returns an authLimiter banning hosts for banDuration after
+// threshold consecutive failures, other than the hosts in the exempt
+// networks.  A threshold of zero disables the limiter.
+func newAuthLimiter(threshold int, banDuration time.Duration,
+	exempt []*net.IPNet) *authLimiter {
+
+	return &authLimiter{
+		threshold:   threshold,
+		banDuration: banDuration,
+		exempt:      exempt,
+		hosts:       make(map[string]*authFailures),
+		now:         time.Now,
+	}
+}
+
+// setLimits replaces the failure threshold, ban duration and exempt
+// networks.  Bans which are already in effect are not changed.
+func (l *authLimiter) setLimits(threshold int, banDuration time.Duration,
+	exempt []*net.IPNet) {
+
+	l.mtx.Lock()
+	l.threshold = threshold
+	l.banDuration = banDuration
+	l.exempt = exempt
+	l.mtx.Unlock()
+}
+
+// authHost returns the host part of a remote address, which is used to
+// track failures regardless of the source port.
+func authHost(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
+
+// exempts returns whether host is in one of the exempt networks, whose
+// hosts are never banned on their own.  This must be called with the mutex
+// held.
+func (l *authLimiter) exempts(host string) bool {
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, n := range l.exempt {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// globalLimited returns whether the global failure limit has been reached
+// within the current window.  This must be called with the mutex held.
+func (l *authLimiter) globalLimited(now time.Time) bool {
+	return l.globalCount >= authGlobalFailures &&
+		now.Before(l.globalStart.Add(authGlobalWindow))
+}
+
+// allowed returns whether the host of remoteAddr may attempt to authenticate.
+func (l *authLimiter) allowed(remoteAddr string) bool {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if l.threshold <= 0 {
+		return true
+	}
+	now := l.now()
+	if l.globalLimited(now) {
+		return false
+	}
+
+	host := authHost(remoteAddr)
+	if l.exempts(host) {
+		return true
+	}
+	f, ok := l.hosts[host]
+	if !ok {
+		return true
+	}
+	return !now.Before(f.bannedUntil) && !now.Before(f.retryAfter)
+}
+
+// failure records a failed authentication attempt by the host of remoteAddr
+// and bans the host once the failure threshold is reached.
+func (l *authLimiter) failure(remoteAddr string) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if l.threshold <= 0 {
+		return
+	}
+
+	now := l.now()
+	l.prune(now)
+
+	if !now.Before(l.globalStart.Add(authGlobalWindow)) {
+		l.globalStart = now
+		l.globalCount = 0
+	}
+	l.globalCount++
+	if l.globalCount == authGlobalFailures {
+		until := l.globalStart.Add(authGlobalWindow)
+		log.Warnf("Refusing all RPC authentication attempts until %v "+
+			"after %d failures within %v",
+			until.Format(time.RFC3339), authGlobalFailures,
+			authGlobalWindow)
+	}
+
+	host := authHost(remoteAddr)
+	if l.exempts(host) {
+		return
+	}
+
+	f, ok := l.hosts[host]
+	if !ok {
+		f = &authFailures{}
+		l.hosts[host] = f
+	}
+	f.count++
+	f.lastFailure = now
+
+	if f.count < l.threshold {
+		backoff := authBackoffBase << uint(f.count-1)
+		if backoff > authBackoffMax || backoff <= 0 {
+			backoff = authBackoffMax
+		}
+		f.retryAfter = now.Add(backoff)
+		return
+	}
+
+	ban := l.banDuration << f.bans
+	if ban > authBanMax || ban <= 0 {
+		ban = authBanMax
+	}
+	f.bans++
+	f.count = 0
+	f.bannedUntil = now.Add(ban)
+	f.retryAfter = f.bannedUntil
+
+	log.Warnf("Banning %s from the RPC server for %v after %d failed "+
+		"authentication attempts", host, ban, l.threshold)
+}
+
+// success clears the consecutive failures of the host of remoteAddr.  Past
+// bans are remembered so that a host alternating between guesses and valid
+// logins is still banned for increasing durations.
+func (l *authLimiter) success(remoteAddr string) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if l.threshold <= 0 {
+		return
+	}
+
+	if f, ok := l.hosts[authHost(remoteAddr)]; ok {
+		f.count = 0
+		f.retryAfter = time.Time{}
+	}
+}
+
+// prune forgets hosts which have neither failed nor been banned for a day.
+// It is only run once a minute.  This must be called with the mutex held.
+func (l *authLimiter) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < time.Minute {
+		return
+	}
+	l.lastPrune = now
+
+	for host, f := range l.hosts {
+		if now.After(f.bannedUntil) &&
+			now.Sub(f.lastFailure) > authBanMax {
+
+			delete(l.hosts, host)
+		}
+	}
+}
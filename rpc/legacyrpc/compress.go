@@ -0,0 +1,73 @@
+package legacyrpc
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// minCompressSize is the size of the smallest response body compressed, as
+// smaller bodies gain little from it.
+const minCompressSize = 1024
+
+// gzipWriters pools the writers compressing response bodies.
+var gzipWriters = sync.Pool{
+	New: func() interface{} {
+		gz, _ := gzip.NewWriterLevel(nil, gzip.BestSpeed)
+		return gz
+	},
+}
+
+// acceptsGzip returns whether the Accept-Encoding header of a request accepts
+// gzip encoded responses, either by name or with a wildcard.
+func acceptsGzip(r *http.Request) bool {
+	gzipQ, anyQ := -1.0, -1.0
+	for _, header := range r.Header.Values("Accept-Encoding") {
+		for _, coding := range strings.Split(header, ",") {
+			params := strings.Split(coding, ";")
+			q := 1.0
+			for _, p := range params[1:] {
+				p = strings.TrimSpace(p)
+				if strings.HasPrefix(p, "q=") {
+					var err error
+					q, err = strconv.ParseFloat(p[len("q="):], 64)
+					if err != nil {
+						q = 0
+					}
+				}
+			}
+			switch strings.ToLower(strings.TrimSpace(params[0])) {
+			case "gzip":
+				gzipQ = q
+			case "*":
+				anyQ = q
+			}
+		}
+	}
+	if gzipQ >= 0 {
+		return gzipQ > 0
+	}
+	return anyQ > 0
+}
+
+// writeResponse writes the body of a response, compressed with gzip when the
+// client accepts it and the body is large enough, as the results of commands
+// such as listtransactions and listunspent are large and compress well.
+func writeResponse(w http.ResponseWriter, r *http.Request, body []byte) error {
+	w.Header().Add("Vary", "Accept-Encoding")
+	if len(body) < minCompressSize || !acceptsGzip(r) {
+		_, err := w.Write(body)
+		return err
+	}
+
+	w.Header().Set("Content-Encoding", "gzip")
+	gz := gzipWriters.Get().(*gzip.Writer)
+	defer gzipWriters.Put(gz)
+	gz.Reset(w)
+	if _, err := gz.Write(body); err != nil {
+		return err
+	}
+	return gz.Close()
+}
@@ -0,0 +1,88 @@
+package legacyrpc
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAcceptsGzip(t *testing.T) {
+	tests := []struct {
+		header string
+		accept bool
+	}{
+		{"", false},
+		{"gzip", true},
+		{"deflate, gzip;q=0.5", true},
+		{"GZIP", true},
+		{"gzip;q=0", false},
+		{"*", true},
+		{"gzip;q=0, *", false},
+		{"identity", false},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest("POST", "/", nil)
+		if test.header != "" {
+			r.Header.Set("Accept-Encoding", test.header)
+		}
+		if got := acceptsGzip(r); got != test.accept {
+			t.Errorf("%q: got %v, want %v", test.header, got,
+				test.accept)
+		}
+	}
+}
+
+func TestWriteResponse(t *testing.T) {
+	large := bytes.Repeat([]byte(`{"txid":"00"},`), minCompressSize)
+	small := []byte(`{"result":1}`)
+
+	tests := []struct {
+		body       []byte
+		gzip       bool
+		compressed bool
+	}{
+		{large, true, true},
+		{large, false, false},
+		{small, true, false},
+	}
+	for i, test := range tests {
+		r := httptest.NewRequest("POST", "/", nil)
+		if test.gzip {
+			r.Header.Set("Accept-Encoding", "gzip")
+		}
+		w := httptest.NewRecorder()
+		if err := writeResponse(w, r, test.body); err != nil {
+			t.Fatal(err)
+		}
+
+		body := w.Body.Bytes()
+		encoding := w.Header().Get("Content-Encoding")
+		if test.compressed {
+			if encoding != "gzip" {
+				t.Fatalf("%d: Content-Encoding %q", i, encoding)
+			}
+			if len(body) >= len(test.body) {
+				t.Fatalf("%d: compressed to %d bytes from %d",
+					i, len(body), len(test.body))
+			}
+			gz, err := gzip.NewReader(bytes.NewReader(body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, err = io.ReadAll(gz)
+			if err != nil {
+				t.Fatal(err)
+			}
+		} else if encoding != "" {
+			t.Fatalf("%d: Content-Encoding %q", i, encoding)
+		}
+		if !bytes.Equal(body, test.body) {
+			t.Fatalf("%d: wrong body", i)
+		}
+		if w.Header().Get("Vary") != "Accept-Encoding" {
+			t.Fatalf("%d: Vary %q", i, w.Header().Get("Vary"))
+		}
+	}
+}
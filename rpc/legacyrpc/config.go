@@ -4,11 +4,83 @@
 
 package legacyrpc
 
+import (
+	"io"
+	"net"
+	"time"
+
+	"github.com/lbryio/lbcwallet/wallet"
+)
+
 // Options contains the required options for running the legacy RPC server.
 type Options struct {
 	Username string
 	Password string
 
+	// Approvers map the names of the users who may only list and approve
+	// the withdrawals requiring approval to their passwords.  They only
+	// authenticate HTTP POST requests.
+	Approvers map[string]string
+
 	MaxPOSTClients      int64
 	MaxWebsocketClients int64
+
+	// MaxRequestSize and MaxMessageSize are the maximum sizes of the
+	// bodies of HTTP POST requests and of websocket messages, which default
+	// to DefaultMaxRequestSize and DefaultMaxMessageSize when zero.
+	// MaxResultItems is the maximum number of items of the result of a
+	// request, such as the transactions listed by listtransactions.  Zero
+	// disables the limit.
+	MaxRequestSize int64
+	MaxMessageSize int64
+	MaxResultItems int
+
+	// AuthFailureThreshold is the number of consecutive failed
+	// authentication attempts after which a host is banned for
+	// AuthBanDuration.  Zero disables the protection.
+	AuthFailureThreshold int
+	AuthBanDuration      time.Duration
+
+	// AuthExemptIPs are the networks of the hosts which are never banned
+	// on their own, such as the address of a local reverse proxy.  Their
+	// failed attempts still count against the limit of failures of all
+	// hosts together.
+	AuthExemptIPs []*net.IPNet
+
+	// AllowedIPs are the networks of the clients whose connections are
+	// accepted, along with those of loopback addresses.  Every client is
+	// accepted when empty.
+	AllowedIPs []*net.IPNet
+
+	// ReadyMaxBlockLag is the number of blocks the wallet may be synced
+	// behind the chain server while the /readyz endpoint reports it ready.
+	// Negative values default to DefaultReadyMaxBlockLag.
+	ReadyMaxBlockLag int32
+
+	// NotificationQueueSize is the number of notifications queued for
+	// each websocket client, which is disconnected when falling further
+	// behind.  It defaults to DefaultNotificationQueueSize when zero.
+	NotificationQueueSize int
+
+	// SlowThreshold is the duration above which requests are logged as
+	// slow, with their database time.  Zero disables the logging.
+	SlowThreshold time.Duration
+
+	// Dial makes the outbound connections of confirmation webhooks.
+	// Connections are made directly when nil.
+	Dial func(network, addr string) (net.Conn, error)
+
+	// DebugInfo writes the debug info bundle returned by the
+	// collectdebuginfo method, given the loaded wallets keyed by name.
+	// The method is unavailable when nil.
+	DebugInfo func(w io.Writer, wallets map[string]*wallet.Wallet) error
+
+	// RegtestHarness enables the methods of the regtest harness, which
+	// mine blocks, fund addresses and fast-forward the wallet clock.
+	RegtestHarness bool
+
+	// MiningAccount is the account of the wallet paid by the blocks mined
+	// by the generate method, which is passed through to the chain server
+	// when empty.
+	MiningAccount string
 }
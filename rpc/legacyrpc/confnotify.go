@@ -0,0 +1,296 @@
+package legacyrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/lbryio/lbcd/btcjson"
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcwallet/rpc/walletjson"
+	"github.com/lbryio/lbcwallet/wallet"
+)
+
+// webhookTimeout is how long a webhook may take to accept a confirmation
+// notification.
+const webhookTimeout = 10 * time.Second
+
+// confWatch is a request to be notified once a wallet transaction reaches a
+// number of confirmations.  The notification is sent to the websocket client
+// which registered it, or POSTed to url if client is nil.
+type confWatch struct {
+	id     uint64
+	txHash chainhash.Hash
+	target int32
+	client *websocketClient
+	url    string
+}
+
+// confNotifier keeps the registered confirmation watches.  Each watch is
+// removed once its notification has been sent.
+type confNotifier struct {
+	mtx     sync.Mutex
+	nextID  uint64
+	watches map[uint64]*confWatch
+
+	webhooks http.Client
+}
+
+func newConfNotifier(dial func(network, addr string) (net.Conn,
+	error)) *confNotifier {
+
+	n := &confNotifier{
+		watches:  make(map[uint64]*confWatch),
+		webhooks: http.Client{Timeout: webhookTimeout},
+	}
+	if dial != nil {
+		n.webhooks.Transport = &http.Transport{Dial: dial}
+	}
+	return n
+}
+
+// add registers a watch and returns its ID.
+func (n *confNotifier) add(watch *confWatch) uint64 {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	n.nextID++
+	watch.id = n.nextID
+	n.watches[watch.id] = watch
+	return watch.id
+}
+
+// remove unregisters a watch of client, or a webhook watch if client is nil.
+// It returns false if there is no such watch.
+func (n *confNotifier) remove(id uint64, client *websocketClient) bool {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	watch, ok := n.watches[id]
+	if !ok || watch.client != client {
+		return false
+	}
+	delete(n.watches, id)
+	return true
+}
+
+// removeClient unregisters all watches of a disconnecting websocket client.
+// It must be called before waiting on the wait group of the client, as
+// notifications to the client are added to it.
+func (n *confNotifier) removeClient(client *websocketClient) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	for id, watch := range n.watches {
+		if watch.client == client {
+			delete(n.watches, id)
+		}
+	}
+}
+
+// check sends the notifications of all watched transactions which have
+// reached their target number of confirmations.
+func (n *confNotifier) check(w *wallet.Wallet) {
+	n.mtx.Lock()
+	watches := make([]*confWatch, 0, len(n.watches))
+	for _, watch := range n.watches {
+		watches = append(watches, watch)
+	}
+	n.mtx.Unlock()
+	if len(watches) == 0 {
+		return
+	}
+
+	syncBlock := w.Manager.SyncedTo()
+	for _, watch := range watches {
+		details, err := wallet.UnstableAPI(w).TxDetails(&watch.txHash)
+		if err != nil {
+			log.Errorf("Unable to look up transaction %v: %v",
+				watch.txHash, err)
+			continue
+		}
+		if details == nil || details.Block.Height == -1 {
+			continue
+		}
+		confs := confirms(details.Block.Height, syncBlock.Height)
+		if confs < watch.target {
+			continue
+		}
+
+		// The watch may have been removed, or notified by a concurrent
+		// check, since it was copied.
+		n.mtx.Lock()
+		_, ok := n.watches[watch.id]
+		if ok {
+			delete(n.watches, watch.id)
+			if watch.client != nil {
+				watch.client.wg.Add(1)
+			}
+		}
+		n.mtx.Unlock()
+		if !ok {
+			continue
+		}
+
+		go n.send(watch, walletjson.TxConfirmation{
+			ID:            watch.id,
+			TxID:          watch.txHash.String(),
+			Confirmations: confs,
+			BlockHash:     details.Block.Hash.String(),
+			BlockHeight:   details.Block.Height,
+		})
+	}
+}
+
+// send delivers the notification of a watch.
+func (n *confNotifier) send(watch *confWatch, conf walletjson.TxConfirmation) {
+	if watch.client != nil {
+		defer watch.client.wg.Done()
+
+		ntfn := walletjson.NewTxConfirmedNtfn(conf)
+		b, err := btcjson.MarshalCmd(btcjson.RpcVersion1, nil, ntfn)
+		if err != nil {
+			log.Errorf("Unable to marshal txconfirmed notification: %v",
+				err)
+			return
+		}
+		watch.client.notify(b)
+		return
+	}
+
+	b, err := json.Marshal(conf)
+	if err != nil {
+		log.Errorf("Unable to marshal confirmation webhook: %v", err)
+		return
+	}
+	resp, err := n.webhooks.Post(watch.url, "application/json",
+		bytes.NewReader(b))
+	if err != nil {
+		log.Warnf("Confirmation webhook %s failed: %v", watch.url, err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		log.Warnf("Confirmation webhook %s failed: %s", watch.url,
+			resp.Status)
+	}
+}
+
+// watchConfirmations checks the confirmation watches whenever blocks are
+// attached to the wallet, until the server is stopped.
+func (s *Server) watchConfirmations(w *wallet.Wallet) {
+	defer s.wg.Done()
+
+	client := w.NtfnServer.TransactionNotifications()
+	defer client.Done()
+	for {
+		select {
+		case ntfn, ok := <-client.C:
+			if !ok {
+				return
+			}
+			if len(ntfn.AttachedBlocks) != 0 {
+				s.confirmations.check(w)
+			}
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// notifyConfirmations handles a notifyconfirmations request.  Websocket
+// clients are notified over their connection unless they pass a webhook URL,
+// which HTTP POST clients must.
+func (s *Server) notifyConfirmations(req *btcjson.Request,
+	wsc *websocketClient) (interface{}, *btcjson.RPCError) {
+
+	icmd, err := btcjson.UnmarshalCmd(req)
+	if err != nil {
+		return nil, btcjson.ErrRPCInvalidRequest
+	}
+	cmd := icmd.(*walletjson.NotifyConfirmationsCmd)
+
+	s.handlerMu.Lock()
+	w := s.wallet
+	s.handlerMu.Unlock()
+	if w == nil {
+		return nil, &ErrUnloadedWallet
+	}
+
+	txHash, err := chainhash.NewHashFromStr(cmd.TxID)
+	if err != nil {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCDecodeHexString,
+			Message: "Transaction hash string decode failed: " + err.Error(),
+		}
+	}
+	if cmd.Confirmations < 1 {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: "confirmations must be positive",
+		}
+	}
+	watch := &confWatch{
+		txHash: *txHash,
+		target: cmd.Confirmations,
+		client: wsc,
+	}
+	if cmd.URL != nil {
+		u, err := url.Parse(*cmd.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") ||
+			u.Host == "" {
+
+			return nil, &btcjson.RPCError{
+				Code:    btcjson.ErrRPCInvalidParameter,
+				Message: "url must be an absolute http or https URL",
+			}
+		}
+		watch.client = nil
+		watch.url = u.String()
+	} else if wsc == nil {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: "url is required for HTTP POST clients",
+		}
+	}
+
+	details, err := wallet.UnstableAPI(w).TxDetails(txHash)
+	if err != nil {
+		return nil, jsonError(err)
+	}
+	if details == nil {
+		return nil, &ErrNoTransactionInfo
+	}
+
+	id := s.confirmations.add(watch)
+	// The transaction may already be confirmed deeply enough.
+	s.confirmations.check(w)
+	return id, nil
+}
+
+// stopNotifyConfirmations handles a stopnotifyconfirmations request.  Clients
+// may remove the watches they registered to be notified over their websocket
+// connection, and any webhook watch.
+func (s *Server) stopNotifyConfirmations(req *btcjson.Request,
+	wsc *websocketClient) (interface{}, *btcjson.RPCError) {
+
+	icmd, err := btcjson.UnmarshalCmd(req)
+	if err != nil {
+		return nil, btcjson.ErrRPCInvalidRequest
+	}
+	cmd := icmd.(*walletjson.StopNotifyConfirmationsCmd)
+
+	if !s.confirmations.remove(cmd.ID, wsc) &&
+		(wsc == nil || !s.confirmations.remove(cmd.ID, nil)) {
+
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: "no confirmation notification with this id",
+		}
+	}
+	return nil, nil
+}
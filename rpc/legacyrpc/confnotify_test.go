@@ -0,0 +1,37 @@
+package legacyrpc
+
+import "testing"
+
+// TestConfNotifierRemove ensures clients can only remove their own
+// confirmation watches, and that a disconnecting client's watches are
+// removed.
+func TestConfNotifierRemove(t *testing.T) {
+	n := newConfNotifier(nil)
+	a := &websocketClient{}
+	b := &websocketClient{}
+
+	idA := n.add(&confWatch{target: 1, client: a})
+	idB := n.add(&confWatch{target: 1, client: b})
+	idHook := n.add(&confWatch{target: 1, url: "http://localhost/"})
+	if idA == idB || idB == idHook {
+		t.Fatal("watch ids are not unique")
+	}
+
+	if n.remove(idA, b) {
+		t.Fatal("removed the watch of another client")
+	}
+	if n.remove(idHook, a) {
+		t.Fatal("removed a webhook watch as a websocket client watch")
+	}
+	if !n.remove(idHook, nil) {
+		t.Fatal("unable to remove webhook watch")
+	}
+
+	n.removeClient(a)
+	if _, ok := n.watches[idA]; ok {
+		t.Fatal("watch of disconnected client was not removed")
+	}
+	if _, ok := n.watches[idB]; !ok {
+		t.Fatal("watch of connected client was removed")
+	}
+}
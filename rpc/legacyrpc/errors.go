@@ -62,6 +62,16 @@ var (
 		Message: "Request requires a wallet but wallet has not loaded yet",
 	}
 
+	ErrWalletNotFound = btcjson.RPCError{
+		Code:    btcjson.ErrRPCWalletNotFound,
+		Message: "Requested wallet does not exist or is not loaded",
+	}
+
+	ErrWalletManagementDisabled = btcjson.RPCError{
+		Code:    btcjson.ErrRPCWallet,
+		Message: "Wallets can not be loaded or unloaded at runtime",
+	}
+
 	ErrWalletUnlockNeeded = btcjson.RPCError{
 		Code:    btcjson.ErrRPCWalletUnlockNeeded,
 		Message: "Enter the wallet passphrase with walletpassphrase first",
@@ -72,13 +82,58 @@ var (
 		Message: "imported addresses must belong to the imported account",
 	}
 
+	ErrHDAccountImport = btcjson.RPCError{
+		Code:    btcjson.ErrRPCWallet,
+		Message: "imported keys must not belong to an HD account",
+	}
+
 	ErrNoTransactionInfo = btcjson.RPCError{
 		Code:    btcjson.ErrRPCNoTxInfo,
 		Message: "No information for transaction",
 	}
 
+	ErrInvoiceNotFound = btcjson.RPCError{
+		Code:    btcjson.ErrRPCInvalidParameter,
+		Message: "Invoice not found",
+	}
+
+	ErrPendingSendNotFound = btcjson.RPCError{
+		Code:    btcjson.ErrRPCInvalidParameter,
+		Message: "No pending send with this handle",
+	}
+
+	ErrVaultNotFound = btcjson.RPCError{
+		Code:    btcjson.ErrRPCInvalidParameter,
+		Message: "No vault with this name",
+	}
+
+	ErrWithdrawalNotFound = btcjson.RPCError{
+		Code:    btcjson.ErrRPCInvalidParameter,
+		Message: "Withdrawal not found",
+	}
+
+	ErrApproverMethod = btcjson.RPCError{
+		Code:    btcjson.ErrRPCMisc,
+		Message: "Approvers may only list and approve withdrawals",
+	}
+
+	ErrNotApprover = btcjson.RPCError{
+		Code:    btcjson.ErrRPCMisc,
+		Message: "Only approvers may approve withdrawals",
+	}
+
+	ErrHarnessDisabled = btcjson.RPCError{
+		Code:    btcjson.ErrRPCMethodNotFound.Code,
+		Message: "Method only available with --regtest-harness",
+	}
+
 	ErrReservedAccountName = btcjson.RPCError{
 		Code:    btcjson.ErrRPCInvalidParameter,
 		Message: "Account name is reserved by RPC server",
 	}
+
+	ErrRequestCanceled = btcjson.RPCError{
+		Code:    btcjson.ErrRPCWallet,
+		Message: "Request canceled by a disconnect or shutdown",
+	}
 )
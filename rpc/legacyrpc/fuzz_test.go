@@ -0,0 +1,79 @@
+package legacyrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lbryio/lbcd/btcjson"
+)
+
+// FuzzParseRequest fuzzes the parsing of the JSON-RPC requests of HTTP POST
+// clients, from the request body through the dispatch of a server without a
+// wallet, and the parsing of the parameters of the commands requested.
+func FuzzParseRequest(f *testing.F) {
+	for _, seed := range []string{
+		`{"jsonrpc":"1.0","id":1,"method":"getbalance","params":[]}`,
+		`{"jsonrpc":"1.0","id":"a","method":"getbalance",` +
+			`"params":["default",6,"*"]}`,
+		`{"jsonrpc":"2.0","id":2,"method":"sendtoaddress",` +
+			`"params":["bMPpSZ9sqnJ9MHQyJmKGF1YLVjAL2Q9j5B",1.5]}`,
+		`{"jsonrpc":"1.0","id":3,"method":"listtransactions",` +
+			`"params":["*",10,0,true]}`,
+		`{"jsonrpc":"1.0","id":4,"method":"walletpassphrase",` +
+			`"params":["pass",60]}`,
+		`{"jsonrpc":"1.0","id":5,"method":"lockunspent",` +
+			`"params":[false,[{"txid":"00","vout":1}]]}`,
+		`{"jsonrpc":"1.0","id":6,"method":"loadwallet","params":["a"]}`,
+		`{"jsonrpc":"1.0","id":null,"method":"help","params":null}`,
+		`{"method":"getinfo"}`,
+		`[]`,
+		`{`,
+	} {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		s := &Server{
+			wallets:        make(map[string]*namedWallet),
+			maxRequestSize: 1 << 16,
+		}
+		r := httptest.NewRequest("POST", "/", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		s.postClientRPC(w, r)
+		if w.Code == http.StatusOK {
+			var resp btcjson.Response
+			err := json.Unmarshal(w.Body.Bytes(), &resp)
+			if err != nil {
+				t.Fatalf("invalid response %q: %v", w.Body, err)
+			}
+		}
+
+		// The commands parsed are marshaled back to the same command.
+		var req btcjson.Request
+		if err := json.Unmarshal(body, &req); err != nil {
+			return
+		}
+		cmd, err := btcjson.UnmarshalCmd(&req)
+		if err != nil {
+			return
+		}
+		marshaled, err := btcjson.MarshalCmd(
+			btcjson.RpcVersion1, 1, cmd,
+		)
+		if err != nil {
+			t.Fatalf("unable to marshal %s command: %v", req.Method,
+				err)
+		}
+		var remarshaled btcjson.Request
+		if err := json.Unmarshal(marshaled, &remarshaled); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := btcjson.UnmarshalCmd(&remarshaled); err != nil {
+			t.Fatalf("unable to parse marshaled %s command %s: %v",
+				req.Method, marshaled, err)
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package legacyrpc
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/lbryio/lbcwallet/chain"
+)
+
+// DefaultReadyMaxBlockLag is the default number of blocks the wallet may be
+// synced behind the chain server while still ready.
+const DefaultReadyMaxBlockLag = 2
+
+// readyTimeout bounds the time the chain server takes to return its best
+// block to a readiness check.
+const readyTimeout = 5 * time.Second
+
+// healthz answers liveness probes, which succeed for as long as the server
+// serves requests.  It requires no authentication.
+func (s *Server) healthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	fmt.Fprintln(w, "ok")
+}
+
+// readyz answers readiness probes, which succeed when the default wallet is
+// loaded, its chain server is connected, and it is synced to within the
+// maximum lag of the best block of the chain server.  It requires no
+// authentication, and the reason of a failure is the body of the response.
+func (s *Server) readyz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := s.ready(); err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintln(w, err)
+		return
+	}
+	fmt.Fprintln(w, "ok")
+}
+
+// ready returns why the server is not ready, or nil if it is.
+func (s *Server) ready() error {
+	s.drainMtx.Lock()
+	draining := s.draining
+	s.drainMtx.Unlock()
+	if draining {
+		return fmt.Errorf("shutting down")
+	}
+
+	s.handlerMu.Lock()
+	wallet, chainClient := s.wallet, s.chainClient
+	s.handlerMu.Unlock()
+	if wallet == nil {
+		return fmt.Errorf("wallet not loaded")
+	}
+	if c := wallet.ChainClient(); c != nil {
+		chainClient = c
+	}
+	if chainClient == nil {
+		return fmt.Errorf("chain server not connected")
+	}
+	if c, ok := chainClient.(*chain.RPCClient); ok && c.Disconnected() {
+		return fmt.Errorf("chain server not connected")
+	}
+
+	type bestBlock struct {
+		height int32
+		err    error
+	}
+	best := make(chan bestBlock, 1)
+	go func() {
+		_, height, err := chainClient.GetBestBlock()
+		best <- bestBlock{height, err}
+	}()
+	var height int32
+	select {
+	case b := <-best:
+		if b.err != nil {
+			return fmt.Errorf("chain server best block: %v", b.err)
+		}
+		height = b.height
+	case <-time.After(readyTimeout):
+		return fmt.Errorf("chain server best block: timed out")
+	}
+
+	synced := wallet.Manager.SyncedTo().Height
+	if lag := height - synced; lag > s.readyMaxBlockLag {
+		return fmt.Errorf("syncing: %d blocks behind the chain server "+
+			"(height %d of %d)", lag, synced, height)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package legacyrpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHealthEndpoints(t *testing.T) {
+	t.Parallel()
+
+	s := &Server{}
+	probe := func(h http.HandlerFunc) (int, string) {
+		w := httptest.NewRecorder()
+		h(w, httptest.NewRequest("GET", "/", nil))
+		return w.Code, w.Body.String()
+	}
+
+	if code, body := probe(s.healthz); code != http.StatusOK ||
+		body != "ok\n" {
+
+		t.Fatalf("healthz: %d %q", code, body)
+	}
+
+	code, body := probe(s.readyz)
+	if code != http.StatusServiceUnavailable ||
+		!strings.Contains(body, "wallet not loaded") {
+
+		t.Fatalf("readyz without a wallet: %d %q", code, body)
+	}
+
+	s.draining = true
+	code, body = probe(s.readyz)
+	if code != http.StatusServiceUnavailable ||
+		!strings.Contains(body, "shutting down") {
+
+		t.Fatalf("readyz while draining: %d %q", code, body)
+	}
+	if code, _ := probe(s.healthz); code != http.StatusOK {
+		t.Fatalf("healthz while draining: %d", code)
+	}
+}
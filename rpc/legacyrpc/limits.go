@@ -0,0 +1,98 @@
+package legacyrpc
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/lbryio/lbcd/btcjson"
+)
+
+// Default limits of the sizes of requests.
+const (
+	// DefaultMaxRequestSize is the default maximum size of the body of an
+	// HTTP POST request.
+	DefaultMaxRequestSize = 4 * 1024 * 1024
+
+	// DefaultMaxMessageSize is the default maximum size of a message read
+	// from a websocket client.
+	DefaultMaxMessageSize = 4 * 1024 * 1024
+)
+
+// requestTooLarge sends a JSON-RPC error back to the client if the body of
+// its request exceeds the maximum size.
+func requestTooLarge(w http.ResponseWriter, maxSize int64) {
+	resp, err := btcjson.MarshalResponse(btcjson.RpcVersion1, nil, nil,
+		&btcjson.RPCError{
+			Code: btcjson.ErrRPCInvalidRequest.Code,
+			Message: fmt.Sprintf("request body exceeds the maximum "+
+				"size of %d bytes", maxSize),
+		})
+	if err != nil {
+		log.Errorf("Unable to marshal response: %v", err)
+		http.Error(w, "413 Request Too Large.",
+			http.StatusRequestEntityTooLarge)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusRequestEntityTooLarge)
+	_, _ = w.Write(resp)
+}
+
+// resultItems returns the number of items of a result, which is its length
+// for a list, and the total length of its list fields for an object.  Byte
+// strings, such as the raw results passed through from the chain server,
+// have no items.
+func resultItems(result interface{}) int {
+	v := reflect.ValueOf(result)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return 0
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return 0
+		}
+		return v.Len()
+
+	case reflect.Struct:
+		n := 0
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Field(i)
+			switch f.Kind() {
+			case reflect.Slice, reflect.Array, reflect.Map:
+				if f.Type().Elem().Kind() != reflect.Uint8 {
+					n += f.Len()
+				}
+			}
+		}
+		return n
+	}
+	return 0
+}
+
+// limitResult wraps a handler to return an error instead of a result with
+// more than maxItems items, unless maxItems is zero.
+func limitResult(f lazyHandler, maxItems int) lazyHandler {
+	if maxItems <= 0 {
+		return f
+	}
+	return func() (interface{}, *btcjson.RPCError) {
+		res, jsonErr := f()
+		if jsonErr != nil {
+			return res, jsonErr
+		}
+		if n := resultItems(res); n > maxItems {
+			return nil, &btcjson.RPCError{
+				Code: btcjson.ErrRPCInvalidParameter,
+				Message: fmt.Sprintf("the result has %d items, "+
+					"more than the maximum of %d -- request "+
+					"fewer items", n, maxItems),
+			}
+		}
+		return res, nil
+	}
+}
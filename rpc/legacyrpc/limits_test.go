@@ -0,0 +1,74 @@
+package legacyrpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lbryio/lbcd/btcjson"
+)
+
+func TestResultItems(t *testing.T) {
+	tests := []struct {
+		result interface{}
+		items  int
+	}{
+		{nil, 0},
+		{"result", 0},
+		{[]string{"a", "b"}, 2},
+		{&[]int{1, 2, 3}, 3},
+		{json.RawMessage(`[1,2,3]`), 0},
+		{map[string]float64{"a": 1}, 1},
+		{btcjson.ListSinceBlockResult{
+			Transactions: make([]btcjson.ListTransactionsResult, 4),
+			LastBlock:    "00",
+		}, 4},
+	}
+	for i, test := range tests {
+		if got := resultItems(test.result); got != test.items {
+			t.Errorf("%d: got %d items, want %d", i, got, test.items)
+		}
+	}
+}
+
+func TestLimitResult(t *testing.T) {
+	f := func() (interface{}, *btcjson.RPCError) {
+		return []int{1, 2, 3}, nil
+	}
+	if res, err := limitResult(f, 0)(); err != nil || res == nil {
+		t.Fatalf("unlimited result failed: %v", err)
+	}
+	if res, err := limitResult(f, 3)(); err != nil || res == nil {
+		t.Fatalf("result within the limit failed: %v", err)
+	}
+	res, err := limitResult(f, 2)()
+	if err == nil || res != nil {
+		t.Fatal("result over the limit returned")
+	}
+	if err.Code != btcjson.ErrRPCInvalidParameter {
+		t.Fatalf("error code %d", err.Code)
+	}
+}
+
+func TestRequestTooLarge(t *testing.T) {
+	s := &Server{maxRequestSize: 16}
+	body := `{"jsonrpc":"1.0","id":1,"method":"getbalance","params":[]}`
+	r := httptest.NewRequest("POST", "/", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	s.postClientRPC(w, r)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("status %d", w.Code)
+	}
+	var resp btcjson.Response
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Error == nil ||
+		resp.Error.Code != btcjson.ErrRPCInvalidRequest.Code {
+
+		t.Fatalf("response error %v", resp.Error)
+	}
+}
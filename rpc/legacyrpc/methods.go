@@ -7,11 +7,17 @@ package legacyrpc
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -25,7 +31,17 @@ import (
 	"github.com/lbryio/lbcd/txscript"
 	"github.com/lbryio/lbcd/wire"
 	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcutil/psbt"
 	"github.com/lbryio/lbcwallet/chain"
+	"github.com/lbryio/lbcwallet/internal/accounting"
+	"github.com/lbryio/lbcwallet/internal/bip21"
+	"github.com/lbryio/lbcwallet/internal/lbrysdk"
+	"github.com/lbryio/lbcwallet/internal/qrcode"
+	"github.com/lbryio/lbcwallet/internal/shamir"
+	"github.com/lbryio/lbcwallet/internal/signbench"
+	"github.com/lbryio/lbcwallet/internal/walletdat"
+	"github.com/lbryio/lbcwallet/internal/zero"
+	"github.com/lbryio/lbcwallet/rpc/walletjson"
 	"github.com/lbryio/lbcwallet/waddrmgr"
 	"github.com/lbryio/lbcwallet/wallet"
 	"github.com/lbryio/lbcwallet/wallet/txrules"
@@ -59,9 +75,22 @@ type requestHandler func(interface{}, *wallet.Wallet) (interface{}, error)
 // requestHandlerChain is a requestHandler that also takes a parameter for
 type requestHandlerChainRequired func(interface{}, *wallet.Wallet, *chain.RPCClient) (interface{}, error)
 
+// requestHandlerContext is a requestHandler for long requests, such as
+// rescans, large queries and sends, which stop with the error of the context
+// once the client disconnects or the server is stopped.
+type requestHandlerContext func(context.Context, interface{},
+	*wallet.Wallet) (interface{}, error)
+
+// requestHandlerChainContext is a requestHandlerChainRequired for long
+// requests, which stop like requestHandlerContext.
+type requestHandlerChainContext func(context.Context, interface{},
+	*wallet.Wallet, *chain.RPCClient) (interface{}, error)
+
 var rpcHandlers = map[string]struct {
-	handler          requestHandler
-	handlerWithChain requestHandlerChainRequired
+	handler                 requestHandler
+	handlerWithChain        requestHandlerChainRequired
+	handlerContext          requestHandlerContext
+	handlerWithChainContext requestHandlerChainContext
 
 	// Function variables cannot be compared against anything but nil, so
 	// use a boolean to record whether help generation is necessary.  This
@@ -77,6 +106,7 @@ var rpcHandlers = map[string]struct {
 	"addmultisigaddress":     {handler: addMultiSigAddress},
 	"createmultisig":         {handler: createMultiSig},
 	"dumpprivkey":            {handler: dumpPrivKey},
+	"dumpwallet":             {handler: dumpWallet},
 	"getaccount":             {handler: getAccount},
 	"getaccountaddress":      {handler: getAccountAddress},
 	"getaddressesbyaccount":  {handler: getAddressesByAccount},
@@ -85,6 +115,7 @@ var rpcHandlers = map[string]struct {
 	"getbestblockhash":       {handler: getBestBlockHash},
 	"getblockcount":          {handler: getBlockCount},
 	"getinfo":                {handlerWithChain: getInfo},
+	"getwalletinfo":          {handler: getWalletInfo},
 	"getnewaddress":          {handler: getNewAddress},
 	"getrawchangeaddress":    {handler: getRawChangeAddress},
 	"getreceivedbyaccount":   {handler: getReceivedByAccount},
@@ -92,19 +123,20 @@ var rpcHandlers = map[string]struct {
 	"gettransaction":         {handler: getTransaction},
 	"help":                   {handler: helpNoChainRPC, handlerWithChain: helpWithChainRPC},
 	"importprivkey":          {handler: importPrivKey},
+	"importwallet":           {handler: importWallet},
 	"keypoolrefill":          {handler: keypoolRefill},
 	"listaccounts":           {handler: listAccounts},
 	"listlockunspent":        {handler: listLockUnspent},
 	"listreceivedbyaccount":  {handler: listReceivedByAccount},
 	"listreceivedbyaddress":  {handler: listReceivedByAddress},
-	"listsinceblock":         {handlerWithChain: listSinceBlock},
-	"listtransactions":       {handler: listTransactions},
+	"listsinceblock":         {handlerWithChainContext: listSinceBlock},
+	"listtransactions":       {handlerContext: listTransactions},
 	"listunspent":            {handler: listUnspent},
 	"lockunspent":            {handler: lockUnspent},
-	"sendfrom":               {handlerWithChain: sendFrom},
-	"rescanblockchain":       {handlerWithChain: rescanBlockchain},
-	"sendmany":               {handler: sendMany},
-	"sendtoaddress":          {handler: sendToAddress},
+	"sendfrom":               {handlerWithChainContext: sendFrom},
+	"rescanblockchain":       {handlerWithChainContext: rescanBlockchain},
+	"sendmany":               {handlerContext: sendMany},
+	"sendtoaddress":          {handlerContext: sendToAddress},
 	"settxfee":               {handler: setTxFee},
 	"signmessage":            {handler: signMessage},
 	"signrawtransaction":     {handlerWithChain: signRawTransaction},
@@ -116,9 +148,6 @@ var rpcHandlers = map[string]struct {
 
 	// Reference implementation methods (still unimplemented)
 	"backupwallet":         {handler: unimplemented, noHelp: true},
-	"dumpwallet":           {handler: unimplemented, noHelp: true},
-	"getwalletinfo":        {handler: unimplemented, noHelp: true},
-	"importwallet":         {handler: unimplemented, noHelp: true},
 	"listaddressgroupings": {handler: unimplemented, noHelp: true},
 
 	// Reference methods which can't be implemented by lbcwallet due to
@@ -126,17 +155,79 @@ var rpcHandlers = map[string]struct {
 	"encryptwallet": {handler: unsupported, noHelp: true},
 
 	// Extensions to the reference client JSON-RPC API
-	"createnewaccount": {handler: createNewAccount},
-	"getbestblock":     {handler: getBestBlock},
+	"approvewithdrawal":      {handlerContext: approveWithdrawal},
+	"benchsigning":           {handlerContext: benchSigning},
+	"cancelsend":             {handler: cancelSend},
+	"cancelwithdrawal":       {handlerContext: cancelWithdrawal},
+	"changepublicpassphrase": {handler: changePublicPassphrase},
+	"clonewallet":            {handler: cloneWallet},
+	"confirmsend":            {handler: confirmSend},
+	"createinvoice":          {handler: createInvoice},
+	"createnewaccount":       {handler: createNewAccount},
+	"createpaymenturi":       {handler: createPaymentURI},
+	"createvault":            {handler: createVault},
+	"decodepaymenturi":       {handler: decodePaymentURI},
+	"diagnosetransaction":    {handlerWithChain: diagnoseTransaction},
+	"dumpgoroutines":         {handler: dumpGoroutines},
+	"dumpheapprofile":        {handler: dumpHeapProfile},
+	"dumpimportedaccount":    {handler: dumpImportedAccount},
+	"exportseedshares":       {handler: exportSeedShares},
+	"exporttransactions":     {handlerContext: exportTransactions},
+	"exporttxlog":            {handler: exportTxLog},
+	"fastforward":            {handler: fastForward},
+	"fundaddress":            {handlerWithChainContext: fundAddress},
+	"generatetowallet":       {handlerWithChain: generateToWallet},
+	"getaccountinfo":         {handler: getAccountInfo},
+	"getbestblock":           {handler: getBestBlock},
+	"getbackupstatus":        {handler: getBackupStatus},
+	"getconnectionstatus":    {handler: getConnectionStatus},
+	"getdecoyaddresses":      {handler: getDecoyAddresses},
+	"getdiskstatus":          {handler: getDiskStatus},
+	"getnetworkinfo":         {handler: getNetworkInfo},
+	"getinvoice":             {handler: getInvoice},
+	"getnewaddresses":        {handler: getNewAddresses},
+	"getprivacyreport":       {handler: getPrivacyReport},
+	"getreceivedbyaddresses": {handlerContext: getReceivedByAddresses},
+	"getspendpolicy":         {handler: getSpendPolicy},
+	"getuptimestats":         {handler: getUptimeStats},
+	"getvaultaddress":        {handler: getVaultAddress},
+	"getwalletactivity":      {handlerContext: getWalletActivity},
+	"importlbrycrdwallet":    {handler: importLbrycrdWallet},
+	"importlbrysdkwallet":    {handler: importLbrySDKWallet},
+	"importwatchpubkey":      {handlerWithChain: importWatchPubKey},
 	// This was an extension but the reference implementation added it as
 	// well, but with a different API (no account parameter).  It's listed
 	// here because it hasn't been update to use the reference
 	// implemenation's API.
 	"getunconfirmedbalance":   {handler: getUnconfirmedBalance},
-	"listaddresstransactions": {handler: listAddressTransactions},
-	"listalltransactions":     {handler: listAllTransactions},
+	"listaccountclaims":       {handler: listAccountClaims},
+	"listaccountinfo":         {handler: listAccountInfo},
+	"listaccountunspent":      {handler: listAccountUnspent},
+	"listaddresstransactions": {handlerContext: listAddressTransactions},
+	"listalltransactions":     {handlerContext: listAllTransactions},
+	"listbalancemoves":        {handler: listBalanceMoves},
+	"listdescriptors":         {handler: listDescriptors},
+	"listimportedaccounts":    {handler: listImportedAccounts},
+	"listinvoices":            {handler: listInvoices},
+	"listtainted":             {handler: listTainted},
+	"listvaults":              {handler: listVaults},
+	"listwithdrawals":         {handler: listWithdrawals},
+	"movebalance":             {handler: moveBalance},
+	"publishpsbt":             {handler: publishPsbt},
+	"recovervault":            {handler: recoverVault},
 	"renameaccount":           {handler: renameAccount},
+	"requesttestcoins":        {handler: requestTestCoins},
+	"rescanimportedaccount":   {handlerWithChainContext: rescanImportedAccount},
+	"sendfromvault":           {handler: sendFromVault},
+	"setimportedaccount":      {handler: setImportedAccount},
+	"setspendpolicy":          {handler: setSpendPolicy},
+	"signpsbt":                {handler: signPsbt},
+	"sweepaccountpsbt":        {handler: sweepAccountPsbt},
+	"taintaddresses":          {handler: taintAddresses},
+	"taintunspent":            {handler: taintUnspent},
+	"walletgetdata":           {handler: walletGetData},
 	"walletislocked":          {handler: walletIsLocked},
+	"walletsetdata":           {handler: walletSetData},
 }
 
 // unimplemented handles an unimplemented RPC request with the
@@ -165,10 +256,22 @@ type lazyHandler func() (interface{}, *btcjson.RPCError)
 // lazyApplyHandler looks up the best request handler func for the method,
 // returning a closure that will execute it with the (required) wallet and
 // (optional) consensus RPC server.  If no handlers are found and the
-// chainClient is not nil, the returned handler performs RPC passthrough.
-func lazyApplyHandler(request *btcjson.Request, w *wallet.Wallet, chainClient chain.Interface) lazyHandler {
+// chainClient is not nil, the returned handler performs RPC passthrough.  The
+// handlers of long requests stop once ctx is done.
+func lazyApplyHandler(ctx context.Context, request *btcjson.Request,
+	w *wallet.Wallet, chainClient chain.Interface) lazyHandler {
+
 	handlerData, ok := rpcHandlers[request.Method]
-	if ok && handlerData.handlerWithChain != nil && w != nil && chainClient != nil {
+	withChain := handlerData.handlerWithChain
+	if handlerData.handlerWithChainContext != nil {
+		withChain = func(cmd interface{}, w *wallet.Wallet,
+			client *chain.RPCClient) (interface{}, error) {
+
+			return handlerData.handlerWithChainContext(ctx, cmd, w,
+				client)
+		}
+	}
+	if ok && withChain != nil && w != nil && chainClient != nil {
 		return func() (interface{}, *btcjson.RPCError) {
 			cmd, err := btcjson.UnmarshalCmd(request)
 			if err != nil {
@@ -176,8 +279,7 @@ func lazyApplyHandler(request *btcjson.Request, w *wallet.Wallet, chainClient ch
 			}
 			switch client := chainClient.(type) {
 			case *chain.RPCClient:
-				resp, err := handlerData.handlerWithChain(cmd,
-					w, client)
+				resp, err := withChain(cmd, w, client)
 				if err != nil {
 					return nil, jsonError(err)
 				}
@@ -190,13 +292,26 @@ func lazyApplyHandler(request *btcjson.Request, w *wallet.Wallet, chainClient ch
 			}
 		}
 	}
-	if ok && handlerData.handler != nil && w != nil {
+	if ok && w == nil {
+		return func() (interface{}, *btcjson.RPCError) {
+			return nil, &ErrUnloadedWallet
+		}
+	}
+	handler := handlerData.handler
+	if handlerData.handlerContext != nil {
+		handler = func(cmd interface{}, w *wallet.Wallet) (interface{},
+			error) {
+
+			return handlerData.handlerContext(ctx, cmd, w)
+		}
+	}
+	if ok && handler != nil && w != nil {
 		return func() (interface{}, *btcjson.RPCError) {
 			cmd, err := btcjson.UnmarshalCmd(request)
 			if err != nil {
 				return nil, btcjson.ErrRPCInvalidRequest
 			}
-			resp, err := handlerData.handler(cmd, w)
+			resp, err := handler(cmd, w)
 			if err != nil {
 				return nil, jsonError(err)
 			}
@@ -212,6 +327,9 @@ func lazyApplyHandler(request *btcjson.Request, w *wallet.Wallet, chainClient ch
 				Message: "Chain RPC is inactive",
 			}
 		}
+		if err := checkPassthrough(request.Method, w); err != nil {
+			return nil, jsonError(err)
+		}
 		switch client := chainClient.(type) {
 		case *chain.RPCClient:
 			resp, err := client.RawRequest(request.Method,
@@ -229,6 +347,46 @@ func lazyApplyHandler(request *btcjson.Request, w *wallet.Wallet, chainClient ch
 	}
 }
 
+// checkPassthrough returns an error when passing the request through to the
+// chain server would bypass a policy of the wallet.  The transactions sent with
+// sendrawtransaction aren't checked against the spend and withdrawal policies,
+// so they are refused while one is set.
+func checkPassthrough(method string, w *wallet.Wallet) error {
+	if method != "sendrawtransaction" || w == nil {
+		return nil
+	}
+
+	if err := checkWithdrawalPolicy(method, w); err != nil {
+		return err
+	}
+	policy, _, err := w.SpendPolicy()
+	if err != nil {
+		return err
+	}
+	if policy.Enabled() {
+		return &btcjson.RPCError{
+			Code: btcjson.ErrRPCWallet,
+			Message: "sendrawtransaction is disabled while a " +
+				"spend policy is set",
+		}
+	}
+	return nil
+}
+
+// checkWithdrawalPolicy returns an error when the wallet has a withdrawal
+// policy, since the transactions the method signs or sends, or the private keys
+// it exports, could otherwise pay more than its threshold without approval.
+func checkWithdrawalPolicy(method string, w *wallet.Wallet) error {
+	if !w.WithdrawalPolicy().Enabled() {
+		return nil
+	}
+	return &btcjson.RPCError{
+		Code: btcjson.ErrRPCWallet,
+		Message: method + " is disabled while a withdrawal policy " +
+			"is set",
+	}
+}
+
 // makeResponse makes the JSON-RPC response struct for the result and error
 // returned by a requestHandler.  The returned response is not ready for
 // marshaling and sending off to a client, but must be
@@ -261,6 +419,9 @@ func jsonError(err error) *btcjson.RPCError {
 	if err == nil {
 		return nil
 	}
+	if errors.Is(err, context.Canceled) {
+		return &ErrRequestCanceled
+	}
 
 	code := btcjson.ErrRPCWallet
 	switch e := err.(type) {
@@ -380,6 +541,10 @@ func createMultiSig(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 func dumpPrivKey(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	cmd := icmd.(*btcjson.DumpPrivKeyCmd)
 
+	if err := checkWithdrawalPolicy("dumpprivkey", w); err != nil {
+		return nil, err
+	}
+
 	addr, err := decodeAddress(cmd.Address, w.ChainParams())
 	if err != nil {
 		return nil, err
@@ -394,6 +559,47 @@ func dumpPrivKey(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	return key, err
 }
 
+// dumpWallet handles a dumpwallet request by writing a portable export of the
+// wallet, with its master key, accounts, imported keys and labels, to a new
+// JSON file.
+func dumpWallet(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*btcjson.DumpWalletCmd)
+
+	if err := checkWithdrawalPolicy("dumpwallet", w); err != nil {
+		return nil, err
+	}
+
+	d, err := w.DumpWallet()
+	if waddrmgr.IsError(err, waddrmgr.ErrLocked) {
+		return nil, &ErrWalletUnlockNeeded
+	}
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	// Never overwrite an existing file.
+	f, err := os.OpenFile(cmd.Filename,
+		os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCWallet,
+			Message: err.Error(),
+		}
+	}
+	_, err = f.Write(append(b, '\n'))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &btcjson.DumpWalletResult{Filename: cmd.Filename}, nil
+}
+
 // getAddressesByAccount handles a getaddressesbyaccount request by returning
 // all addresses for an account, or an error if the requested account does
 // not exist. If addresstype is also specified, only those address types are
@@ -657,6 +863,71 @@ func getInfo(icmd interface{}, w *wallet.Wallet, chainClient *chain.RPCClient) (
 	return info, nil
 }
 
+// getWalletInfo handles a getwalletinfo request by returning the state of the
+// wallet, its balances and key counts, and the state of its chain server.
+func getWalletInfo(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	summaries, err := w.AccountSummaries(1)
+	if err != nil {
+		return nil, err
+	}
+	pools, err := w.KeyPools()
+	if err != nil {
+		return nil, err
+	}
+	txCount, err := w.TxCount()
+	if err != nil {
+		return nil, err
+	}
+
+	syncBlock := w.Manager.SyncedTo()
+	result := &walletjson.GetWalletInfoResult{
+		WalletVersion:      int32(waddrmgr.LatestMgrVersion),
+		TransactionCount:   txCount,
+		KeyPools:           make([]walletjson.KeyPoolResult, 0, len(pools)),
+		Locked:             w.Locked(),
+		PrivateKeysEnabled: !w.Manager.WatchOnly(),
+		Birthday:           w.Manager.Birthday().Unix(),
+		SyncHeight:         syncBlock.Height,
+		SyncHash:           syncBlock.Hash.String(),
+		Synced:             w.ChainSynced(),
+	}
+	var balance, unconfirmed, immature, staked btcutil.Amount
+	for i := range summaries {
+		balance += summaries[i].Spendable
+		unconfirmed += summaries[i].Unconfirmed
+		immature += summaries[i].ImmatureReward
+		staked += summaries[i].Staked
+	}
+	result.Balance = balance.ToBTC()
+	result.UnconfirmedBalance = unconfirmed.ToBTC()
+	result.ImmatureBalance = immature.ToBTC()
+	result.Staked = staked.ToBTC()
+	for _, pool := range pools {
+		result.KeyPoolSize += pool.External
+		result.KeyPoolSizeHDInternal += pool.Internal
+		result.KeyPools = append(result.KeyPools, walletjson.KeyPoolResult{
+			AddressType: keyScopeAddressType(pool.Scope),
+			External:    pool.External,
+			Internal:    pool.Internal,
+			Imported:    pool.Imported,
+
+			PooledExternal: pool.PooledExternal,
+			PooledInternal: pool.PooledInternal,
+		})
+	}
+	if until := w.UnlockedUntil(); !result.Locked && !until.IsZero() {
+		result.UnlockedUntil = until.Unix()
+	}
+	if chainClient := w.ChainClient(); chainClient != nil {
+		result.Backend = chainClient.BackEnd()
+		result.BackendConnected = true
+		if c, ok := chainClient.(*chain.RPCClient); ok {
+			result.BackendConnected = !c.Disconnected()
+		}
+	}
+	return result, nil
+}
+
 func decodeAddress(s string, params *chaincfg.Params) (btcutil.Address, error) {
 	addr, err := btcutil.DecodeAddress(s, params)
 	if err != nil {
@@ -745,16 +1016,271 @@ func getUnconfirmedBalance(icmd interface{}, w *wallet.Wallet) (interface{}, err
 	return (bals.Total - bals.Spendable).ToBTC(), nil
 }
 
+// importWallet handles an importwallet request by restoring a file written by
+// dumpwallet into the wallet, which must have the master key of the dump, and
+// rescanning the blockchain from the birthday block of the dump.
+func importWallet(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*btcjson.ImportWalletCmd)
+
+	b, err := os.ReadFile(cmd.Filename)
+	if err != nil {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCWallet,
+			Message: err.Error(),
+		}
+	}
+	var d wallet.Dump
+	if err := json.Unmarshal(b, &d); err != nil {
+		return nil, InvalidParameterError{wallet.ErrDumpFormat}
+	}
+
+	err = w.ImportWallet(&d, true)
+	switch {
+	case waddrmgr.IsError(err, waddrmgr.ErrLocked):
+		return nil, &ErrWalletUnlockNeeded
+	case errors.Is(err, wallet.ErrDumpFormat),
+		errors.Is(err, wallet.ErrDumpMasterKey):
+		return nil, InvalidParameterError{err}
+	}
+	return nil, err
+}
+
+// importLbrycrdWallet handles an importlbrycrdwallet request by importing the
+// keys and labels of a wallet.dat file of lbrycrd, decrypted with its own
+// passphrase, and rescanning the blockchain for their addresses.
+func importLbrycrdWallet(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.ImportLbrycrdWalletCmd)
+
+	b, err := os.ReadFile(cmd.Filename)
+	if err != nil {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCWallet,
+			Message: err.Error(),
+		}
+	}
+	dat, err := walletdat.Parse(b)
+	if err != nil {
+		return nil, InvalidParameterError{err}
+	}
+	if dat.Encrypted() {
+		if cmd.Passphrase == nil {
+			return nil, InvalidParameterError{wallet.ErrLbrycrdLocked}
+		}
+		passphrase := []byte(*cmd.Passphrase)
+		defer zero.Bytes(passphrase)
+		err := dat.Unlock(passphrase)
+		switch {
+		case errors.Is(err, walletdat.ErrPassphrase):
+			return nil, &btcjson.RPCError{
+				Code:    btcjson.ErrRPCWalletPassphraseIncorrect,
+				Message: "Incorrect lbrycrd wallet passphrase",
+			}
+		case err != nil:
+			return nil, InvalidParameterError{err}
+		}
+	}
+
+	res, err := w.ImportLbrycrdWallet(dat, *cmd.Rescan)
+	if waddrmgr.IsError(err, waddrmgr.ErrLocked) {
+		return nil, &ErrWalletUnlockNeeded
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &walletjson.ImportLbrycrdWalletResult{
+		Keys:       res.Keys,
+		Labels:     res.Labels,
+		Channels:   make([]walletjson.LbrycrdChannelResult, 0, len(res.Channels)),
+		RescanFrom: res.BlockStamp.Height,
+	}
+	for _, c := range res.Channels {
+		result.Channels = append(result.Channels,
+			walletjson.LbrycrdChannelResult{
+				ClaimID: c.ClaimID,
+				Name:    c.Name,
+				Address: c.Address.EncodeAddress(),
+				TxID:    c.OutPoint.Hash.String(),
+				Vout:    c.OutPoint.Index,
+			})
+	}
+	return result, nil
+}
+
+// importLbrySDKWallet handles an importlbrysdkwallet request by importing the
+// accounts and channel certificates of an lbry-sdk wallet file, decrypted with
+// its own password, and rescanning the blockchain for their addresses.
+func importLbrySDKWallet(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.ImportLbrySDKWalletCmd)
+
+	b, err := os.ReadFile(cmd.Filename)
+	if err != nil {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCWallet,
+			Message: err.Error(),
+		}
+	}
+	sdk, err := lbrysdk.Parse(b)
+	if err != nil {
+		return nil, InvalidParameterError{err}
+	}
+	if sdk.Encrypted() {
+		if cmd.Password == nil {
+			return nil, InvalidParameterError{wallet.ErrLbrySDKEncrypted}
+		}
+		err := sdk.Decrypt(*cmd.Password)
+		switch {
+		case errors.Is(err, lbrysdk.ErrPassword):
+			return nil, &btcjson.RPCError{
+				Code:    btcjson.ErrRPCWalletPassphraseIncorrect,
+				Message: "Incorrect lbry-sdk wallet password",
+			}
+		case err != nil:
+			return nil, InvalidParameterError{err}
+		}
+	}
+
+	res, err := w.ImportLbrySDKWallet(
+		sdk, wallet.DefaultRecoveryLookahead, *cmd.Rescan,
+	)
+	switch {
+	case waddrmgr.IsError(err, waddrmgr.ErrLocked):
+		return nil, &ErrWalletUnlockNeeded
+	case err != nil:
+		var managerErr waddrmgr.ManagerError
+		if errors.As(err, &managerErr) &&
+			managerErr.ErrorCode == waddrmgr.ErrDuplicateAccount {
+
+			return nil, InvalidParameterError{err}
+		}
+		return nil, err
+	}
+
+	result := &walletjson.ImportLbrySDKWalletResult{
+		Accounts: make([]string, 0, len(res.Accounts)),
+		Channels: make([]walletjson.LbrySDKChannelResult, 0, len(res.Channels)),
+	}
+	for _, props := range res.Accounts {
+		result.Accounts = append(result.Accounts, props.AccountName)
+	}
+	for _, c := range res.Channels {
+		result.Channels = append(result.Channels,
+			walletjson.LbrySDKChannelResult{
+				ID:      c.ID,
+				Address: c.Address.EncodeAddress(),
+			})
+	}
+	return result, nil
+}
+
+// importWatchPubKey handles an importwatchpubkey request by importing a
+// public key as a watch-only address, which the chain server notifies the
+// transactions of at once, and rescanning the blocks from the start height
+// in the background when one is given.
+func importWatchPubKey(icmd interface{}, w *wallet.Wallet,
+	chainClient *chain.RPCClient) (interface{}, error) {
+
+	cmd := icmd.(*walletjson.ImportWatchPubKeyCmd)
+
+	serializedPubKey, err := decodeHexStr(cmd.PubKey)
+	if err != nil {
+		return nil, err
+	}
+	pubKey, err := btcec.ParsePubKey(serializedPubKey, btcec.S256())
+	if err != nil {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidAddressOrKey,
+			Message: "Pubkey decode failed: " + err.Error(),
+		}
+	}
+
+	scope, err := lookupKeyScope(cmd.AddressType)
+	if err != nil {
+		return nil, InvalidParameterError{err}
+	}
+	addrType := waddrmgr.PubKeyHash
+	switch {
+	case scope == nil:
+		return nil, InvalidParameterError{
+			errors.New("an address type is required"),
+		}
+	case *scope == waddrmgr.KeyScopeBIP0049:
+		addrType = waddrmgr.NestedWitnessPubKey
+	case *scope == waddrmgr.KeyScopeBIP0084:
+		addrType = waddrmgr.WitnessPubKey
+	}
+
+	var rescanFrom *waddrmgr.BlockStamp
+	if cmd.StartHeight != nil {
+		_, bestHeight, err := chainClient.GetBestBlock()
+		if err != nil {
+			return nil, err
+		}
+		startHeight := *cmd.StartHeight
+		if startHeight < 0 || startHeight > bestHeight {
+			return nil, &btcjson.RPCError{
+				Code:    btcjson.ErrRPCInvalidParameter,
+				Message: "Invalid start height",
+			}
+		}
+		hash, err := chainClient.GetBlockHash(int64(startHeight))
+		if err != nil {
+			return nil, err
+		}
+		rescanFrom = &waddrmgr.BlockStamp{
+			Hash:   *hash,
+			Height: startHeight,
+		}
+	}
+
+	addr, err := w.ImportWatchOnlyPublicKey(pubKey, addrType, rescanFrom)
+	switch {
+	case waddrmgr.IsError(err, waddrmgr.ErrDuplicateAddress):
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCWallet,
+			Message: "Address already in wallet",
+		}
+	case err != nil:
+		return nil, err
+	}
+	return addr.EncodeAddress(), nil
+}
+
+// exportSeedShares handles an exportseedshares request by splitting the
+// master root key of the wallet into Shamir shares.
+func exportSeedShares(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.ExportSeedSharesCmd)
+
+	if err := checkWithdrawalPolicy("exportseedshares", w); err != nil {
+		return nil, err
+	}
+
+	shares, err := w.SeedShares(cmd.Threshold, cmd.Count)
+	switch {
+	case errors.Is(err, shamir.ErrThreshold):
+		return nil, InvalidParameterError{err}
+	case waddrmgr.IsError(err, waddrmgr.ErrLocked):
+		return nil, &ErrWalletUnlockNeeded
+	}
+	return shares, err
+}
+
 // importPrivKey handles an importprivkey request by parsing
 // a WIF-encoded private key and adding it to an account.
 func importPrivKey(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	cmd := icmd.(*btcjson.ImportPrivKeyCmd)
 
-	// Ensure that private keys are only imported to the correct account.
+	// Ensure that private keys are only imported to an imported-key
+	// account, which is any account but the HD accounts.
 	//
 	// Yes, Label is the account name.
+	importedAccount := waddrmgr.ImportedAddrAccountName
 	if cmd.Label != nil && *cmd.Label != waddrmgr.ImportedAddrAccountName {
-		return nil, &ErrNotImportedAccount
+		_, err := w.AccountNumber(*cmd.Label)
+		if !waddrmgr.IsError(err, waddrmgr.ErrAccountNotFound) {
+			return nil, &ErrHDAccountImport
+		}
+		importedAccount = *cmd.Label
 	}
 
 	wif, err := btcutil.DecodeWIF(cmd.PrivKey)
@@ -772,22 +1298,44 @@ func importPrivKey(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	}
 
 	// Import the private key, handling any errors.
-	_, err = w.ImportPrivateKey(waddrmgr.KeyScopeBIP0044, wif, nil, *cmd.Rescan)
+	addrStr, err := w.ImportPrivateKey(waddrmgr.KeyScopeBIP0044, wif, nil,
+		*cmd.Rescan)
 	switch {
 	case waddrmgr.IsError(err, waddrmgr.ErrDuplicateAddress):
 		// Do not return duplicate key errors to the client.
 		return nil, nil
 	case waddrmgr.IsError(err, waddrmgr.ErrLocked):
 		return nil, &ErrWalletUnlockNeeded
+	case err != nil:
+		return nil, err
+	}
+	if importedAccount == waddrmgr.ImportedAddrAccountName {
+		return nil, nil
 	}
 
-	return nil, err
+	addr, err := decodeAddress(addrStr, w.ChainParams())
+	if err != nil {
+		return nil, err
+	}
+	return nil, w.SetImportedAccount(addr, importedAccount)
 }
 
-// keypoolRefill handles the keypoolrefill command. Since we handle the keypool
-// automatically this does nothing since refilling is never manually required.
+// keypoolRefill handles the keypoolrefill command by topping the keypools of
+// the accounts up to the requested size, or to the configured one.
 func keypoolRefill(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
-	return nil, nil
+	cmd := icmd.(*btcjson.KeyPoolRefillCmd)
+
+	size := w.KeyPoolSize()
+	if cmd.NewSize != nil {
+		if *cmd.NewSize > wallet.MaxKeyPoolSize {
+			return nil, InvalidParameterError{fmt.Errorf(
+				"newsize must be at most %d",
+				wallet.MaxKeyPoolSize,
+			)}
+		}
+		size = uint32(*cmd.NewSize)
+	}
+	return nil, w.RefillKeyPool(size)
 }
 
 // createNewAccount handles a createnewaccount request by creating and
@@ -816,7 +1364,7 @@ func createNewAccount(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 		}
 	}
 
-	return nil, nil
+	return nil, err
 }
 
 // renameAccount handles a renameaccount request by renaming an account.
@@ -895,51 +1443,211 @@ func getNewAddress(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	return addr.EncodeAddress(), nil
 }
 
-// getRawChangeAddress handles a getrawchangeaddress request by creating
-// and returning a new change address for an account.
-//
-// Note: bitcoind allows specifying the account as an optional parameter,
-// but ignores the parameter.
-func getRawChangeAddress(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+// maxNewAddresses is the largest number of addresses a getnewaddresses request
+// may generate.
+const maxNewAddresses = 1000
 
-	cmd := icmd.(*btcjson.GetRawChangeAddressCmd)
+// getNewAddresses handles a getnewaddresses request by returning a batch of
+// new addresses for an account, with their derivation indexes.
+func getNewAddresses(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.GetNewAddressesCmd)
 
-	account, err := w.AccountNumber(*cmd.Account)
+	if cmd.Count == 0 || cmd.Count > maxNewAddresses {
+		return nil, InvalidParameterError{fmt.Errorf("count must be "+
+			"between 1 and %d", maxNewAddresses)}
+	}
+	account, err := w.AccountNumber(cmd.Account)
 	if err != nil {
 		return nil, err
 	}
-
-	// Use specified scope, if provided.
 	scope, err := lookupKeyScope(cmd.AddressType)
 	if err != nil {
 		return nil, err
 	}
 	if scope == nil {
-		scope = &waddrmgr.DefaultKeyScope
+		scope = &waddrmgr.KeyScopeBIP0044
 	}
 
-	addr, err := w.NewChangeAddress(account, *scope)
+	addrs, err := w.NewAddresses(account, *scope, cmd.Count)
 	if err != nil {
 		return nil, err
 	}
+	results := make([]walletjson.NewAddressResult, 0, len(addrs))
+	for _, addr := range addrs {
+		results = append(results, walletjson.NewAddressResult{
+			Address: addr.Address.EncodeAddress(),
+			Index:   addr.Index,
+		})
+	}
+	return results, nil
+}
 
-	// Return the new payment address string.
-	return addr.EncodeAddress(), nil
+// getBackupStatus handles a getbackupstatus request by returning the status of
+// the backups of the wallet.
+func getBackupStatus(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	status := w.BackupStatus()
+	result := &walletjson.GetBackupStatusResult{
+		Enabled:        status.Enabled,
+		Store:          status.Store,
+		Interval:       int64(status.Interval / time.Second),
+		LastError:      status.LastError,
+		LastBackupName: status.LastName,
+		LastBackupSize: status.LastSize,
+	}
+	if !status.LastAttempt.IsZero() {
+		result.LastAttempt = status.LastAttempt.Unix()
+	}
+	if !status.LastBackup.IsZero() {
+		result.LastBackup = status.LastBackup.Unix()
+	}
+	if !status.NextBackup.IsZero() {
+		result.NextBackup = status.NextBackup.Unix()
+	}
+	return result, nil
 }
 
-// getReceivedByAccount handles a getreceivedbyaccount request by returning
-// the total amount received by addresses of an account.
-func getReceivedByAccount(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+// getConnectionStatus handles a getconnectionstatus request by returning the
+// status of the connection to the chain server, and the address family of the
+// connection to lbcd.
+func getConnectionStatus(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	chainClient := w.ChainClient()
+	if chainClient == nil {
+		return &walletjson.GetConnectionStatusResult{}, nil
+	}
+	result := &walletjson.GetConnectionStatusResult{
+		Backend:   chainClient.BackEnd(),
+		Connected: true,
+	}
+	if client, ok := chainClient.(*chain.RPCClient); ok {
+		status := client.ConnStatus()
+		result.Connected = status.Connected
+		result.Address = status.Addr
+		result.Family = status.Family
+	}
+	return result, nil
+}
 
-	cmd := icmd.(*btcjson.GetReceivedByAccountCmd)
+// getDecoyAddresses handles a getdecoyaddresses request by returning a batch
+// of decoy addresses of an account, which are not tracked by the wallet.
+func getDecoyAddresses(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.GetDecoyAddressesCmd)
 
-	account, err := w.AccountNumber(*cmd.Account)
+	if cmd.Count == 0 || cmd.Count > maxNewAddresses {
+		return nil, InvalidParameterError{fmt.Errorf("count must be "+
+			"between 1 and %d", maxNewAddresses)}
+	}
+	account, err := w.AccountNumber(cmd.Account)
 	if err != nil {
 		return nil, err
 	}
+	scope, err := lookupKeyScope(cmd.AddressType)
+	if err != nil {
+		return nil, err
+	}
+	if scope == nil {
+		scope = &waddrmgr.KeyScopeBIP0044
+	}
+
+	addrs, err := w.NewDecoyAddresses(account, *scope, cmd.Count)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]walletjson.DecoyAddressResult, 0, len(addrs))
+	for _, addr := range addrs {
+		results = append(results, walletjson.DecoyAddressResult{
+			Address: addr.Address.EncodeAddress(),
+			Index:   addr.Index,
+			Path:    addr.Path,
+			Decoy:   true,
+		})
+	}
+	return results, nil
+}
+
+// getDiskStatus handles a getdiskstatus request by checking the free space of
+// the volume holding the wallet database.
+func getDiskStatus(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	status := w.CheckDiskSpace()
+	result := &walletjson.GetDiskStatusResult{
+		Enabled:   status.Enabled,
+		Path:      status.Path,
+		LastError: status.LastError,
+		DBSize:    status.DBSize,
+		Growth:    status.Growth,
+		Free:      status.Free,
+		Warning:   status.Warning,
+		Low:       status.Low,
+	}
+	if !status.LastCheck.IsZero() {
+		result.LastCheck = status.LastCheck.Unix()
+	}
+	return result, nil
+}
+
+// getNetworkInfo handles a getnetworkinfo request by returning the parameters
+// of the network of the wallet.
+func getNetworkInfo(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	params := w.ChainParams()
+	return &walletjson.GetNetworkInfoResult{
+		Name:             params.Name,
+		Net:              fmt.Sprintf("%08x", uint32(params.Net)),
+		TestNet:          params.Net != wire.MainNet,
+		GenesisHash:      params.GenesisHash.String(),
+		DefaultPort:      params.DefaultPort,
+		Bech32HRP:        params.Bech32HRPSegwit,
+		PubKeyHashAddrID: params.PubKeyHashAddrID,
+		ScriptHashAddrID: params.ScriptHashAddrID,
+		PrivateKeyID:     params.PrivateKeyID,
+		HDCoinType:       params.HDCoinType,
+		CoinbaseMaturity: params.CoinbaseMaturity,
+	}, nil
+}
+
+// getRawChangeAddress handles a getrawchangeaddress request by creating
+// and returning a new change address for an account.
+//
+// Note: bitcoind allows specifying the account as an optional parameter,
+// but ignores the parameter.
+func getRawChangeAddress(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+
+	cmd := icmd.(*btcjson.GetRawChangeAddressCmd)
+
+	account, err := w.AccountNumber(*cmd.Account)
+	if err != nil {
+		return nil, err
+	}
+
+	// Use specified scope, if provided.
+	scope, err := lookupKeyScope(cmd.AddressType)
+	if err != nil {
+		return nil, err
+	}
+	if scope == nil {
+		scope = &waddrmgr.DefaultKeyScope
+	}
+
+	addr, err := w.NewChangeAddress(account, *scope)
+	if err != nil {
+		return nil, err
+	}
+
+	// Return the new payment address string.
+	return addr.EncodeAddress(), nil
+}
+
+// getReceivedByAccount handles a getreceivedbyaccount request by returning
+// the total amount received by addresses of an account.
+func getReceivedByAccount(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+
+	cmd := icmd.(*btcjson.GetReceivedByAccountCmd)
+
+	account, err := w.AccountNumber(*cmd.Account)
+	if err != nil {
+		return nil, err
+	}
+
+	totalReceived := 0.0
 
-	totalReceived := 0.0
-
 	var results []wallet.AccountTotalReceivedResult
 
 	fn := func(scope waddrmgr.KeyScope) error {
@@ -981,6 +1689,46 @@ func getReceivedByAddress(icmd interface{}, w *wallet.Wallet) (interface{}, erro
 	return total.ToBTC(), nil
 }
 
+// maxReceivedByAddresses is the largest number of addresses a
+// getreceivedbyaddresses request may total.
+const maxReceivedByAddresses = 10000
+
+// getReceivedByAddresses handles a getreceivedbyaddresses request by returning
+// the total amount received by each address, totaled in a single pass over
+// the transaction history.
+func getReceivedByAddresses(ctx context.Context, icmd interface{},
+	w *wallet.Wallet) (interface{}, error) {
+
+	cmd := icmd.(*walletjson.GetReceivedByAddressesCmd)
+
+	if len(cmd.Addresses) > maxReceivedByAddresses {
+		return nil, InvalidParameterError{fmt.Errorf("at most %d "+
+			"addresses may be totaled", maxReceivedByAddresses)}
+	}
+	addrs := make([]btcutil.Address, 0, len(cmd.Addresses))
+	for _, a := range cmd.Addresses {
+		addr, err := decodeAddress(a, w.ChainParams())
+		if err != nil {
+			return nil, err
+		}
+		addrs = append(addrs, addr)
+	}
+	amounts, err := w.TotalReceivedForAddrs(ctx, addrs,
+		int32(*cmd.MinConf))
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]walletjson.ReceivedByAddressResult, 0, len(addrs))
+	for i, addr := range addrs {
+		results = append(results, walletjson.ReceivedByAddressResult{
+			Address: addr.EncodeAddress(),
+			Amount:  amounts[i].ToBTC(),
+		})
+	}
+	return results, nil
+}
+
 // getTransaction handles a gettransaction request by returning details about
 // a single transaction saved by wallet.
 func getTransaction(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
@@ -1013,8 +1761,15 @@ func getTransaction(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 		return nil, err
 	}
 
-	ret := btcjson.GetTransactionResult{
+	comment, err := w.TxComment(txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := walletjson.GetTransactionResult{
 		TxID:            cmd.Txid,
+		Comment:         comment.Comment,
+		To:              comment.CommentTo,
 		Hex:             hex.EncodeToString(txBuf.Bytes()),
 		Time:            details.Received.Unix(),
 		TimeReceived:    details.Received.Unix(),
@@ -1278,6 +2033,371 @@ func listAccounts(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	return accountBalances, err
 }
 
+// keyScopeAddressType returns the address type of a key scope, as used by the
+// addresstype parameters, or the scope itself if it has none.
+func keyScopeAddressType(scope waddrmgr.KeyScope) string {
+	switch scope {
+	case waddrmgr.KeyScopeBIP0044:
+		return "legacy"
+	case waddrmgr.KeyScopeBIP0049:
+		return "p2sh-segwit"
+	case waddrmgr.KeyScopeBIP0084:
+		return "bech32"
+	}
+	return scope.String()
+}
+
+// accountInfoResult converts an account summary to its JSON result.
+func accountInfoResult(s *wallet.AccountSummary) walletjson.AccountInfoResult {
+	addrTypes := make([]string, 0, len(s.KeyScopes))
+	for _, scope := range s.KeyScopes {
+		addrTypes = append(addrTypes, keyScopeAddressType(scope))
+	}
+	return walletjson.AccountInfoResult{
+		Account:          s.AccountName,
+		Number:           s.AccountNumber,
+		AddressTypes:     addrTypes,
+		Balance:          s.Total.ToBTC(),
+		Spendable:        s.Spendable.ToBTC(),
+		Unconfirmed:      s.Unconfirmed.ToBTC(),
+		Immature:         s.ImmatureReward.ToBTC(),
+		Staked:           s.Staked.ToBTC(),
+		Moved:            s.Moved.ToBTC(),
+		LedgerBalance:    (s.Total + s.Moved).ToBTC(),
+		ExternalKeyCount: s.ExternalKeyCount,
+		InternalKeyCount: s.InternalKeyCount,
+		ImportedKeyCount: s.ImportedKeyCount,
+	}
+}
+
+// getAccountInfo handles a getaccountinfo request by returning the balances
+// and key counts of an account.
+func getAccountInfo(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.GetAccountInfoCmd)
+
+	minConf := int32(*cmd.MinConf)
+	if minConf < 0 {
+		return nil, ErrNeedPositiveMinconf
+	}
+	account, err := w.AccountNumber(cmd.Account)
+	if err != nil {
+		return nil, err
+	}
+	summaries, err := w.AccountSummaries(minConf)
+	if err != nil {
+		return nil, err
+	}
+	for i := range summaries {
+		if summaries[i].AccountNumber == account {
+			return accountInfoResult(&summaries[i]), nil
+		}
+	}
+	return nil, &ErrAccountNameNotFound
+}
+
+// listAccountInfo handles a listaccountinfo request by returning the balances
+// and key counts of every account, ordered by account number.
+func listAccountInfo(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.ListAccountInfoCmd)
+
+	minConf := int32(*cmd.MinConf)
+	if minConf < 0 {
+		return nil, ErrNeedPositiveMinconf
+	}
+	summaries, err := w.AccountSummaries(minConf)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]walletjson.AccountInfoResult, 0, len(summaries))
+	for i := range summaries {
+		results = append(results, accountInfoResult(&summaries[i]))
+	}
+	return results, nil
+}
+
+// listAccountUnspent handles a listaccountunspent request by returning the
+// unspent outputs of an account, which are the outputs a send from the account
+// may spend.
+func listAccountUnspent(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.ListAccountUnspentCmd)
+
+	// Ensure the account exists, as no outputs would match a misspelled
+	// account name.
+	if _, err := w.AccountNumber(cmd.Account); err != nil {
+		return nil, err
+	}
+	unspent, err := w.ListUnspent(int32(*cmd.MinConf),
+		int32(*cmd.MaxConf), cmd.Account)
+	if err != nil {
+		return nil, err
+	}
+	return withTaints(w, unspent)
+}
+
+// listAccountClaims handles a listaccountclaims request by returning the
+// unspent claims, supports and claim updates of an account, or of every
+// account for the "*" account.
+func listAccountClaims(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.ListAccountClaimsCmd)
+
+	all := *cmd.Account == "*"
+	var account uint32
+	if !all {
+		var err error
+		account, err = w.AccountNumber(*cmd.Account)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	claims, err := w.AccountClaims()
+	if err != nil {
+		return nil, err
+	}
+	syncBlock := w.Manager.SyncedTo()
+	results := make([]walletjson.AccountClaimResult, 0, len(claims))
+	for i := range claims {
+		c := &claims[i]
+		if !all && c.Account != account {
+			continue
+		}
+		name, err := w.AccountName(c.Account)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, walletjson.AccountClaimResult{
+			Account:        name,
+			TxID:           c.OutPoint.Hash.String(),
+			Vout:           c.OutPoint.Index,
+			Address:        c.Address.EncodeAddress(),
+			Amount:         c.Amount.ToBTC(),
+			Confirmations:  int64(confirms(c.Height, syncBlock.Height)),
+			Type:           c.Op,
+			Name:           c.Name,
+			ClaimID:        c.ClaimID,
+			Kind:           c.Kind,
+			SigningChannel: c.SigningChannel,
+		})
+	}
+	return results, nil
+}
+
+// listDescriptors handles a listdescriptors request by returning the output
+// script descriptors of the accounts.
+func listDescriptors(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	descs, err := w.AccountDescriptors()
+	if err != nil {
+		return nil, err
+	}
+	timestamp := w.Manager.Birthday().Unix()
+	result := &walletjson.ListDescriptorsResult{
+		Descriptors: make([]walletjson.DescriptorResult, 0, len(descs)),
+	}
+	for _, d := range descs {
+		result.Descriptors = append(result.Descriptors,
+			walletjson.DescriptorResult{
+				Desc:      d.Descriptor,
+				Timestamp: timestamp,
+				Active:    true,
+				Internal:  d.Internal,
+				Range:     []uint32{0, d.Next},
+				Next:      d.Next,
+				Account:   d.Name,
+			})
+	}
+	return result, nil
+}
+
+// listImportedAccounts handles a listimportedaccounts request by returning
+// every imported-key account with its addresses and balance.
+func listImportedAccounts(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	accounts, err := w.ImportedAccounts()
+	if err != nil {
+		return nil, err
+	}
+	results := make([]walletjson.ImportedAccountResult, 0, len(accounts))
+	for _, a := range accounts {
+		addrs := make([]string, 0, len(a.Addresses))
+		for _, addr := range a.Addresses {
+			addrs = append(addrs, addr.EncodeAddress())
+		}
+		results = append(results, walletjson.ImportedAccountResult{
+			Account:   a.Name,
+			Addresses: addrs,
+			Balance:   a.Balance.ToBTC(),
+		})
+	}
+	return results, nil
+}
+
+// setImportedAccount handles a setimportedaccount request by assigning the
+// address of an imported key to an imported-key account.
+func setImportedAccount(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.SetImportedAccountCmd)
+
+	addr, err := decodeAddress(cmd.Address, w.ChainParams())
+	if err != nil {
+		return nil, err
+	}
+	err = w.SetImportedAccount(addr, cmd.Account)
+	if waddrmgr.IsError(err, waddrmgr.ErrAddressNotFound) {
+		return nil, &ErrAddressNotInWallet
+	}
+	if err != nil {
+		return nil, InvalidParameterError{err}
+	}
+	return nil, nil
+}
+
+// dumpImportedAccount handles a dumpimportedaccount request by returning the
+// addresses and private keys of an imported-key account.
+func dumpImportedAccount(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.DumpImportedAccountCmd)
+
+	if err := checkWithdrawalPolicy("dumpimportedaccount", w); err != nil {
+		return nil, err
+	}
+
+	addrs, wifs, err := w.ExportImportedAccount(cmd.Account)
+	switch {
+	case errors.Is(err, wallet.ErrImportedAccountNotFound):
+		return nil, &ErrAccountNameNotFound
+	case waddrmgr.IsError(err, waddrmgr.ErrLocked):
+		return nil, &ErrWalletUnlockNeeded
+	case err != nil:
+		return nil, err
+	}
+	results := make([]walletjson.ImportedKeyResult, 0, len(addrs))
+	for i := range addrs {
+		results = append(results, walletjson.ImportedKeyResult{
+			Address: addrs[i].EncodeAddress(),
+			PrivKey: wifs[i].String(),
+		})
+	}
+	return results, nil
+}
+
+// rescanImportedAccount handles a rescanimportedaccount request by rescanning
+// the blockchain from a height for the addresses of an imported-key account.
+func rescanImportedAccount(ctx context.Context, icmd interface{},
+	w *wallet.Wallet, chainClient *chain.RPCClient) (interface{}, error) {
+
+	cmd := icmd.(*walletjson.RescanImportedAccountCmd)
+
+	_, bestHeight, err := chainClient.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+	startHeight := *cmd.StartHeight
+	if startHeight < 0 || startHeight > bestHeight {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: "Invalid start height",
+		}
+	}
+	hash, err := chainClient.GetBlockHash(int64(startHeight))
+	if err != nil {
+		return nil, err
+	}
+
+	err = w.RescanImportedAccount(ctx, cmd.Account, waddrmgr.BlockStamp{
+		Hash:   *hash,
+		Height: startHeight,
+	})
+	if errors.Is(err, wallet.ErrImportedAccountNotFound) {
+		return nil, &ErrAccountNameNotFound
+	}
+	return nil, err
+}
+
+// balanceMoveResult converts a balance move to its JSON result.
+func balanceMoveResult(w *wallet.Wallet, m *wallet.BalanceMove) (
+	walletjson.BalanceMoveResult, error) {
+
+	from, err := w.AccountName(m.FromAccount)
+	if err != nil {
+		return walletjson.BalanceMoveResult{}, err
+	}
+	to, err := w.AccountName(m.ToAccount)
+	if err != nil {
+		return walletjson.BalanceMoveResult{}, err
+	}
+	return walletjson.BalanceMoveResult{
+		ID:          m.ID,
+		Time:        m.Time.Unix(),
+		FromAccount: from,
+		ToAccount:   to,
+		Amount:      m.Amount.ToBTC(),
+		Comment:     m.Comment,
+	}, nil
+}
+
+// moveBalance handles a movebalance request by recording a move of value
+// between the ledger balances of two accounts, without a transaction.
+func moveBalance(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.MoveBalanceCmd)
+
+	from, err := w.AccountNumber(cmd.FromAccount)
+	if err != nil {
+		return nil, err
+	}
+	to, err := w.AccountNumber(cmd.ToAccount)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := btcutil.NewAmount(cmd.Amount)
+	if err != nil {
+		return nil, err
+	}
+	if amount <= 0 {
+		return nil, ErrNeedPositiveAmount
+	}
+	var comment string
+	if cmd.Comment != nil {
+		comment = *cmd.Comment
+	}
+
+	m, err := w.MoveBalance(from, to, amount, comment)
+	if err != nil {
+		return nil, InvalidParameterError{err}
+	}
+	return balanceMoveResult(w, m)
+}
+
+// listBalanceMoves handles a listbalancemoves request by returning the moves
+// from or to an account, or all moves for the "*" account, in the order they
+// were recorded.
+func listBalanceMoves(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.ListBalanceMovesCmd)
+
+	all := *cmd.Account == "*"
+	var account uint32
+	if !all {
+		var err error
+		account, err = w.AccountNumber(*cmd.Account)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	moves, err := w.BalanceMoves()
+	if err != nil {
+		return nil, err
+	}
+	results := make([]walletjson.BalanceMoveResult, 0, len(moves))
+	for _, m := range moves {
+		if !all && m.FromAccount != account && m.ToAccount != account {
+			continue
+		}
+		result, err := balanceMoveResult(w, m)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
 // listLockUnspent handles a listlockunspent request by returning an slice of
 // all locked outpoints.
 func listLockUnspent(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
@@ -1443,7 +2563,9 @@ func listReceivedByAddress(icmd interface{}, w *wallet.Wallet) (interface{}, err
 
 // listSinceBlock handles a listsinceblock request by returning an array of maps
 // with details of sent and received wallet transactions since the given block.
-func listSinceBlock(icmd interface{}, w *wallet.Wallet, chainClient *chain.RPCClient) (interface{}, error) {
+func listSinceBlock(ctx context.Context, icmd interface{}, w *wallet.Wallet,
+	chainClient *chain.RPCClient) (interface{}, error) {
+
 	cmd := icmd.(*btcjson.ListSinceBlockCmd)
 
 	syncBlock := w.Manager.SyncedTo()
@@ -1467,10 +2589,22 @@ func listSinceBlock(icmd interface{}, w *wallet.Wallet, chainClient *chain.RPCCl
 		start = int32(block.Height) + 1
 	}
 
-	txInfoList, err := w.ListSinceBlock("*", start, -1, syncBlock.Height)
+	txInfoList, err := w.ListSinceBlock(ctx, "*", start, -1,
+		syncBlock.Height)
+	if err != nil {
+		return nil, err
+	}
+	comments, err := w.TxComments()
 	if err != nil {
 		return nil, err
 	}
+	for i := range txInfoList {
+		hash, err := chainhash.NewHashFromStr(txInfoList[i].TxID)
+		if err != nil {
+			continue
+		}
+		txInfoList[i].Comment = comments[*hash].Comment
+	}
 
 	// Done with work, get the response.
 	blockHash, err := gbh.Receive()
@@ -1487,11 +2621,17 @@ func listSinceBlock(icmd interface{}, w *wallet.Wallet, chainClient *chain.RPCCl
 
 // listTransactions handles a listtransactions request by returning an
 // array of maps with details of sent and recevied wallet transactions.
-func listTransactions(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+func listTransactions(ctx context.Context, icmd interface{},
+	w *wallet.Wallet) (interface{}, error) {
 
 	cmd := icmd.(*btcjson.ListTransactionsCmd)
 
-	return w.ListTransactions(*cmd.Account, *cmd.From, *cmd.Count)
+	txs, err := w.ListTransactions(ctx, *cmd.Account, *cmd.From,
+		*cmd.Count)
+	if err != nil {
+		return nil, err
+	}
+	return annotateTransactions(w, txs)
 }
 
 // listAddressTransactions handles a listaddresstransactions request by
@@ -1499,7 +2639,9 @@ func listTransactions(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 // transactions.  The form of the reply is identical to listtransactions,
 // but the array elements are limited to transaction details which are
 // about the addresess included in the request.
-func listAddressTransactions(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+func listAddressTransactions(ctx context.Context, icmd interface{},
+	w *wallet.Wallet) (interface{}, error) {
+
 	cmd := icmd.(*btcjson.ListAddressTransactionsCmd)
 
 	// Decode addresses.
@@ -1512,18 +2654,85 @@ func listAddressTransactions(icmd interface{}, w *wallet.Wallet) (interface{}, e
 		hash160Map[string(addr.ScriptAddress())] = struct{}{}
 	}
 
-	return w.ListAddressTransactions(*cmd.Account, hash160Map)
-}
-
+	txs, err := w.ListAddressTransactions(ctx, *cmd.Account, hash160Map)
+	if err != nil {
+		return nil, err
+	}
+	return annotateTransactions(w, txs)
+}
+
 // listAllTransactions handles a listalltransactions request by returning
 // a map with details of sent and recevied wallet transactions.  This is
 // similar to ListTransactions, except it takes only a single optional
 // argument for the account name and replies with all transactions.
-func listAllTransactions(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+func listAllTransactions(ctx context.Context, icmd interface{},
+	w *wallet.Wallet) (interface{}, error) {
 
 	cmd := icmd.(*btcjson.ListAllTransactionsCmd)
 
-	return w.ListAllTransactions(*cmd.Account)
+	txs, err := w.ListAllTransactions(ctx, *cmd.Account)
+	if err != nil {
+		return nil, err
+	}
+	return annotateTransactions(w, txs)
+}
+
+// annotateTransactions returns the results of listtransactions annotated with
+// the exchange rates and comments recorded for the transactions.
+func annotateTransactions(w *wallet.Wallet,
+	txs []btcjson.ListTransactionsResult) ([]walletjson.ListTransactionsResult,
+	error) {
+
+	rates, err := w.TxFiatRates()
+	if err != nil {
+		return nil, err
+	}
+	comments, err := w.TxComments()
+	if err != nil {
+		return nil, err
+	}
+	results := make([]walletjson.ListTransactionsResult, 0, len(txs))
+	for i := range txs {
+		tx := &txs[i]
+		result := walletjson.ListTransactionsResult{
+			Abandoned:         tx.Abandoned,
+			Account:           tx.Account,
+			Address:           tx.Address,
+			Amount:            tx.Amount,
+			BIP125Replaceable: tx.BIP125Replaceable,
+			BlockHash:         tx.BlockHash,
+			BlockHeight:       tx.BlockHeight,
+			BlockIndex:        tx.BlockIndex,
+			BlockTime:         tx.BlockTime,
+			Category:          tx.Category,
+			Confirmations:     tx.Confirmations,
+			Fee:               tx.Fee,
+			Generated:         tx.Generated,
+			InvolvesWatchOnly: tx.InvolvesWatchOnly,
+			Label:             tx.Label,
+			Time:              tx.Time,
+			TimeReceived:      tx.TimeReceived,
+			Trusted:           tx.Trusted,
+			TxID:              tx.TxID,
+			Vout:              tx.Vout,
+			WalletConflicts:   tx.WalletConflicts,
+			Comment:           tx.Comment,
+			OtherAccount:      tx.OtherAccount,
+		}
+		if hash, err := chainhash.NewHashFromStr(tx.TxID); err == nil {
+			if rate, ok := rates[*hash]; ok {
+				result.FiatCurrency = rate.Currency
+				result.FiatRate = rate.Rate
+				result.FiatAmount = math.Round(tx.Amount*rate.Rate*100) / 100
+			}
+			if c, ok := comments[*hash]; ok {
+				result.Comment = c.Comment
+				result.To = c.CommentTo
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
 }
 
 // listUnspent handles the listunspent command.
@@ -1537,7 +2746,127 @@ func listUnspent(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 		}
 	}
 
-	return w.ListUnspent(int32(*cmd.MinConf), int32(*cmd.MaxConf), "")
+	unspent, err := w.ListUnspent(int32(*cmd.MinConf),
+		int32(*cmd.MaxConf), "")
+	if err != nil {
+		return nil, err
+	}
+	return withTaints(w, unspent)
+}
+
+// withTaints returns the results of listunspent annotated with the taint of
+// the outputs.
+func withTaints(w *wallet.Wallet,
+	unspent []*btcjson.ListUnspentResult) ([]walletjson.ListUnspentResult,
+	error) {
+
+	taints, err := w.Taints()
+	if err != nil {
+		return nil, err
+	}
+	results := make([]walletjson.ListUnspentResult, 0, len(unspent))
+	for _, u := range unspent {
+		result := walletjson.ListUnspentResult{
+			TxID:          u.TxID,
+			Vout:          u.Vout,
+			Address:       u.Address,
+			Account:       u.Account,
+			ScriptPubKey:  u.ScriptPubKey,
+			RedeemScript:  u.RedeemScript,
+			Amount:        u.Amount,
+			Confirmations: u.Confirmations,
+			Solvable:      u.Solvable,
+			Spendable:     u.Spendable,
+			IsStake:       u.IsStake,
+		}
+		if hash, err := chainhash.NewHashFromStr(u.TxID); err == nil {
+			op := wire.OutPoint{Hash: *hash, Index: u.Vout}
+			result.TaintReason, result.Tainted = taints.Reason(
+				op, u.Address)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// taintUnspent handles the taintunspent command by tainting outputs, or
+// clearing their taint, so that coin selection never chooses them.
+func taintUnspent(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.TaintUnspentCmd)
+
+	ops := make([]wire.OutPoint, 0, len(cmd.Transactions))
+	for _, input := range cmd.Transactions {
+		txHash, err := chainhash.NewHashFromStr(input.Txid)
+		if err != nil {
+			return nil, ParseError{err}
+		}
+		ops = append(ops, wire.OutPoint{Hash: *txHash, Index: input.Vout})
+	}
+	if err := w.SetOutputTaint(ops, cmd.Taint, *cmd.Reason); err != nil {
+		return nil, err
+	}
+	return true, nil
+}
+
+// taintAddresses handles the taintaddresses command by tainting every output
+// paying to addresses, or clearing their taint.
+func taintAddresses(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.TaintAddressesCmd)
+
+	addrs := make([]btcutil.Address, 0, len(cmd.Addresses))
+	for _, a := range cmd.Addresses {
+		addr, err := decodeAddress(a, w.ChainParams())
+		if err != nil {
+			return nil, err
+		}
+		addrs = append(addrs, addr)
+	}
+	if err := w.SetAddressTaint(addrs, cmd.Taint, *cmd.Reason); err != nil {
+		return nil, err
+	}
+	return true, nil
+}
+
+// listTainted handles the listtainted command by returning the tainted
+// outputs and addresses.
+func listTainted(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	taints, err := w.Taints()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &walletjson.ListTaintedResult{
+		Outputs: make([]walletjson.TaintedOutputResult, 0,
+			len(taints.Outputs)),
+		Addresses: make([]walletjson.TaintedAddressResult, 0,
+			len(taints.Addresses)),
+	}
+	for op, reason := range taints.Outputs {
+		result.Outputs = append(result.Outputs,
+			walletjson.TaintedOutputResult{
+				TxID:   op.Hash.String(),
+				Vout:   op.Index,
+				Reason: reason,
+			})
+	}
+	for addr, reason := range taints.Addresses {
+		result.Addresses = append(result.Addresses,
+			walletjson.TaintedAddressResult{
+				Address: addr,
+				Reason:  reason,
+			})
+	}
+	sort.Slice(result.Outputs, func(i, j int) bool {
+		a, b := &result.Outputs[i], &result.Outputs[j]
+		if a.TxID != b.TxID {
+			return a.TxID < b.TxID
+		}
+		return a.Vout < b.Vout
+	})
+	sort.Slice(result.Addresses, func(i, j int) bool {
+		return result.Addresses[i].Address < result.Addresses[j].Address
+	})
+	return result, nil
 }
 
 // lockUnspent handles the lockunspent command.
@@ -1561,269 +2890,1747 @@ func lockUnspent(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 			}
 		}
 	}
-	return true, nil
+	return true, nil
+}
+
+// makeOutputs creates a slice of transaction outputs from a pair of address
+// strings to amounts.  This is used to create the outputs to include in newly
+// created transactions from a JSON object describing the output destinations
+// and amounts.
+func makeOutputs(pairs map[string]btcutil.Amount, chainParams *chaincfg.Params) ([]*wire.TxOut, error) {
+	outputs := make([]*wire.TxOut, 0, len(pairs))
+	for addrStr, amt := range pairs {
+		addr, err := btcutil.DecodeAddress(addrStr, chainParams)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode address: %s", err)
+		}
+
+		pkScript, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create txout script: %s", err)
+		}
+
+		outputs = append(outputs, wire.NewTxOut(int64(amt), pkScript))
+	}
+	return outputs, nil
+}
+
+// rescanBlockchain handles a rescanblockhain RPC request.
+func rescanBlockchain(ctx context.Context, icmd interface{},
+	w *wallet.Wallet, chainClient *chain.RPCClient) (interface{}, error) {
+
+	cmd := icmd.(*btcjson.RescanBlockchainCmd)
+
+	_, bestHeight, err := chainClient.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	startHeight := *cmd.StartHeight
+	if startHeight < 0 || startHeight > bestHeight {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: "Invalid start height",
+		}
+	}
+
+	// Scan to the best block if no stopHeight is specified.
+	stopHeight := bestHeight
+	if cmd.StopHeight != nil {
+		stopHeight = *cmd.StopHeight
+	}
+	if stopHeight < 0 || stopHeight > bestHeight {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: "Invalid stop height",
+		}
+	}
+
+	startHeight, stopHeight, err = w.RescanBlockchain(ctx, chainClient,
+		startHeight, stopHeight)
+	if err != nil {
+		return nil, fmt.Errorf("rescanblockchain: %w", err)
+	}
+	ret := btcjson.RescanBlockchainResult{
+		StartHeight: startHeight,
+		StoptHeight: stopHeight,
+	}
+	return ret, nil
+}
+
+// sendPairs creates and sends payment transactions.
+// It returns the transaction hash in string format upon success
+// All errors are returned in btcjson.RPCError format
+func sendPairs(ctx context.Context, w *wallet.Wallet,
+	amounts map[string]btcutil.Amount,
+	keyScope *waddrmgr.KeyScope, account uint32, minconf int32,
+	feeSatPerKb btcutil.Amount, comment *wallet.TxComment) (string, error) {
+
+	outputs, err := makeOutputs(amounts, w.ChainParams())
+	if err != nil {
+		return "", err
+	}
+	tx, err := w.SendOutputsWithComment(
+		ctx, outputs, keyScope, account, minconf, feeSatPerKb,
+		wallet.CoinSelectionLargest, "", comment,
+	)
+	if err != nil {
+		return "", sendError(err)
+	}
+
+	txHashStr := tx.TxHash().String()
+	log.Infof("Successfully sent transaction %v", txHashStr)
+	return txHashStr, nil
+}
+
+// sendError converts an error creating or sending a transaction to the
+// btcjson.RPCError format.
+func sendError(err error) error {
+	if err == txrules.ErrAmountNegative {
+		return ErrNeedPositiveAmount
+	}
+	if waddrmgr.IsError(err, waddrmgr.ErrLocked) {
+		return &ErrWalletUnlockNeeded
+	}
+	if _, ok := err.(btcjson.RPCError); ok {
+		return err
+	}
+	if _, ok := err.(*wallet.ErrSpendPolicy); ok {
+		return &btcjson.RPCError{
+			Code:    btcjson.ErrRPCWallet,
+			Message: err.Error(),
+		}
+	}
+	if errors.Is(err, wallet.ErrApprovalRequired) {
+		return &btcjson.RPCError{
+			Code:    btcjson.ErrRPCWallet,
+			Message: err.Error(),
+		}
+	}
+
+	return &btcjson.RPCError{
+		Code:    btcjson.ErrRPCInternal.Code,
+		Message: err.Error(),
+	}
+}
+
+// sendOrPreviewPairs sends payment transactions with sendPairs, or returns the
+// preview of the unsigned transaction from previewPairs when the wallet
+// previews sends.  Sends requiring approval are queued as withdrawals instead,
+// and the sends of a wallet whose keys are offline are returned as PSBTs.
+func sendOrPreviewPairs(ctx context.Context, w *wallet.Wallet,
+	amounts map[string]btcutil.Amount,
+	keyScope *waddrmgr.KeyScope, account uint32, minconf int32,
+	feeSatPerKb btcutil.Amount, comment *wallet.TxComment) (interface{},
+	error) {
+
+	outputs, err := makeOutputs(amounts, w.ChainParams())
+	if err != nil {
+		return nil, err
+	}
+	if w.ColdSigningPolicy().Enabled {
+		send, err := w.CreateColdSend(
+			ctx, outputs, keyScope, account, minconf, feeSatPerKb,
+			wallet.CoinSelectionLargest,
+		)
+		if err != nil {
+			return nil, sendError(err)
+		}
+		b64, err := send.Packet.B64Encode()
+		if err != nil {
+			return nil, err
+		}
+		return &walletjson.ColdSendResult{
+			Psbt: b64,
+			Fee:  send.Fee.ToBTC(),
+			File: send.File,
+		}, nil
+	}
+	requiresApproval, err := w.RequiresApproval(outputs)
+	if err != nil {
+		return nil, err
+	}
+	if requiresApproval {
+		wd, err := w.RequestWithdrawal(
+			outputs, keyScope, account, minconf, feeSatPerKb,
+			comment, requestUser(ctx).name,
+		)
+		if err != nil {
+			return nil, sendError(err)
+		}
+		return withdrawalResult(w, wd), nil
+	}
+	if w.SendPreview() {
+		return previewPairs(ctx, w, amounts, keyScope, account,
+			minconf, feeSatPerKb, comment)
+	}
+	return sendPairs(ctx, w, amounts, keyScope, account, minconf,
+		feeSatPerKb, comment)
+}
+
+// previewPairs creates an unsigned payment transaction, which is signed and
+// sent by confirmsend, and returns its preview.
+func previewPairs(ctx context.Context, w *wallet.Wallet,
+	amounts map[string]btcutil.Amount,
+	keyScope *waddrmgr.KeyScope, account uint32, minconf int32,
+	feeSatPerKb btcutil.Amount, comment *wallet.TxComment) (
+	*walletjson.SendPreviewResult, error) {
+
+	outputs, err := makeOutputs(amounts, w.ChainParams())
+	if err != nil {
+		return nil, err
+	}
+	p, err := w.PreviewSendOutputs(
+		ctx, outputs, keyScope, account, minconf, feeSatPerKb,
+		wallet.CoinSelectionLargest, "", comment,
+	)
+	if err != nil {
+		return nil, sendError(err)
+	}
+
+	var buf bytes.Buffer
+	if err := p.Tx.Serialize(&buf); err != nil {
+		return nil, err
+	}
+	result := &walletjson.SendPreviewResult{
+		Handle:  p.Handle,
+		Hex:     hex.EncodeToString(buf.Bytes()),
+		Inputs:  []walletjson.SendPreviewInput{},
+		Outputs: []walletjson.SendPreviewOutput{},
+		Fee:     p.Fee.ToBTC(),
+		VSize:   p.VSize,
+		Expires: p.Expires.Unix(),
+	}
+	for i, txIn := range p.Tx.TxIn {
+		input := walletjson.SendPreviewInput{
+			TxID:   txIn.PreviousOutPoint.Hash.String(),
+			Vout:   txIn.PreviousOutPoint.Index,
+			Amount: p.InputValues[i].ToBTC(),
+		}
+		result.Inputs = append(result.Inputs, input)
+	}
+	change := make(map[int]bool, len(p.ChangeIndexes))
+	for _, i := range p.ChangeIndexes {
+		change[i] = true
+	}
+	for i, txOut := range p.Tx.TxOut {
+		output := walletjson.SendPreviewOutput{
+			Vout:   uint32(i),
+			Amount: btcutil.Amount(txOut.Value).ToBTC(),
+			Change: change[i],
+		}
+		_, addrs, _, err := txscript.ExtractPkScriptAddrs(
+			txscript.StripClaimScriptPrefix(txOut.PkScript),
+			w.ChainParams(),
+		)
+		if err == nil && len(addrs) == 1 {
+			output.Address = addrs[0].EncodeAddress()
+		}
+		result.Outputs = append(result.Outputs, output)
+	}
+	return result, nil
+}
+
+// confirmSend handles a confirmsend request by signing and sending the
+// transaction of a send previewed while the wallet previews sends.
+func confirmSend(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.ConfirmSendCmd)
+
+	tx, err := w.ConfirmSend(cmd.Handle)
+	if errors.Is(err, wallet.ErrPendingSendNotFound) {
+		return nil, &ErrPendingSendNotFound
+	}
+	if err != nil {
+		return nil, sendError(err)
+	}
+
+	txHashStr := tx.TxHash().String()
+	log.Infof("Successfully sent transaction %v", txHashStr)
+	return txHashStr, nil
+}
+
+// cancelSend handles a cancelsend request by discarding a previewed send.
+func cancelSend(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.CancelSendCmd)
+
+	err := w.CancelSend(cmd.Handle)
+	if errors.Is(err, wallet.ErrPendingSendNotFound) {
+		return nil, &ErrPendingSendNotFound
+	}
+	return nil, err
+}
+
+// withdrawalResult returns the result of a withdrawal.
+func withdrawalResult(w *wallet.Wallet,
+	wd *wallet.Withdrawal) *walletjson.WithdrawalResult {
+
+	result := &walletjson.WithdrawalResult{
+		ID:        wd.ID,
+		State:     wd.State.String(),
+		Amount:    wd.Amount().ToBTC(),
+		Outputs:   []walletjson.WithdrawalOutput{},
+		Account:   wd.Account,
+		Requester: wd.Requester,
+		Approvers: []string{},
+		Required:  wd.Required,
+		Created:   wd.Created.Unix(),
+		Expires:   wd.Expires.Unix(),
+		History:   []walletjson.WithdrawalEventResult{},
+	}
+	result.Approvers = append(result.Approvers, wd.Approvers()...)
+	if wd.State == wallet.WithdrawalSent {
+		result.TxID = wd.TxHash.String()
+	}
+	for _, txOut := range wd.Outputs {
+		output := walletjson.WithdrawalOutput{
+			Amount: btcutil.Amount(txOut.Value).ToBTC(),
+		}
+		_, addrs, _, err := txscript.ExtractPkScriptAddrs(
+			txOut.PkScript, w.ChainParams(),
+		)
+		if err == nil && len(addrs) == 1 {
+			output.Address = addrs[0].EncodeAddress()
+		}
+		result.Outputs = append(result.Outputs, output)
+	}
+	for _, e := range wd.History {
+		result.History = append(result.History,
+			walletjson.WithdrawalEventResult{
+				Time:   e.Time.Unix(),
+				Action: e.Action.String(),
+				User:   e.User,
+				Detail: e.Detail,
+			})
+	}
+	return result
+}
+
+// withdrawalError converts the errors of the withdrawal methods of the wallet
+// to RPC errors.
+func withdrawalError(err error) error {
+	switch {
+	case errors.Is(err, wallet.ErrWithdrawalNotFound):
+		return &ErrWithdrawalNotFound
+	case errors.Is(err, wallet.ErrWithdrawalClosed),
+		errors.Is(err, wallet.ErrWithdrawalApproved):
+
+		return &btcjson.RPCError{
+			Code:    btcjson.ErrRPCWallet,
+			Message: err.Error(),
+		}
+	}
+	return sendError(err)
+}
+
+// approveWithdrawal handles an approvewithdrawal request by recording the
+// approval of a withdrawal by the approver making the request, and sending the
+// withdrawal once it has its approvals.
+func approveWithdrawal(ctx context.Context, icmd interface{},
+	w *wallet.Wallet) (interface{}, error) {
+
+	cmd := icmd.(*walletjson.ApproveWithdrawalCmd)
+
+	user := requestUser(ctx)
+	if !user.approver {
+		return nil, &ErrNotApprover
+	}
+	wd, err := w.ApproveWithdrawal(ctx, cmd.ID, user.name)
+	if err != nil {
+		return nil, withdrawalError(err)
+	}
+	return withdrawalResult(w, wd), nil
+}
+
+// cancelWithdrawal handles a cancelwithdrawal request by canceling a
+// withdrawal which was not sent yet.
+func cancelWithdrawal(ctx context.Context, icmd interface{},
+	w *wallet.Wallet) (interface{}, error) {
+
+	cmd := icmd.(*walletjson.CancelWithdrawalCmd)
+
+	wd, err := w.CancelWithdrawal(cmd.ID, requestUser(ctx).name)
+	if err != nil {
+		return nil, withdrawalError(err)
+	}
+	return withdrawalResult(w, wd), nil
+}
+
+// listWithdrawals handles a listwithdrawals request by returning the
+// withdrawals, optionally only those in a state.
+func listWithdrawals(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.ListWithdrawalsCmd)
+
+	if cmd.State != nil {
+		switch *cmd.State {
+		case wallet.WithdrawalPending.String(),
+			wallet.WithdrawalApproved.String(),
+			wallet.WithdrawalSent.String(),
+			wallet.WithdrawalCanceled.String(),
+			wallet.WithdrawalExpired.String():
+		default:
+			return nil, InvalidParameterError{
+				fmt.Errorf("unknown withdrawal state %q",
+					*cmd.State),
+			}
+		}
+	}
+
+	wds, err := w.Withdrawals()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*walletjson.WithdrawalResult, 0, len(wds))
+	for _, wd := range wds {
+		if cmd.State != nil && wd.State.String() != *cmd.State {
+			continue
+		}
+		result = append(result, withdrawalResult(w, wd))
+	}
+	return result, nil
+}
+
+func isNilOrEmpty(s *string) bool {
+	return s == nil || *s == ""
+}
+
+// txComment returns the comment of a send request, from its optional comment
+// parameters.
+func txComment(comment, commentTo *string) *wallet.TxComment {
+	c := &wallet.TxComment{}
+	if !isNilOrEmpty(comment) {
+		c.Comment = *comment
+	}
+	if !isNilOrEmpty(commentTo) {
+		c.CommentTo = *commentTo
+	}
+	return c
+}
+
+// sendFrom handles a sendfrom RPC request by creating a new transaction
+// spending unspent transaction outputs for a wallet to another payment
+// address.  Leftover inputs not sent to the payment address or a fee for
+// the miner are sent back to a new address in the wallet.  Upon success,
+// the TxID for the created transaction is returned.
+func sendFrom(ctx context.Context, icmd interface{}, w *wallet.Wallet,
+	chainClient *chain.RPCClient) (interface{}, error) {
+
+	cmd := icmd.(*btcjson.SendFromCmd)
+
+	account, err := w.AccountNumber(cmd.FromAccount)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check that signed integer parameters are positive.
+	if cmd.Amount < 0 {
+		return nil, ErrNeedPositiveAmount
+	}
+	minConf := int32(*cmd.MinConf)
+	if minConf < 0 {
+		return nil, ErrNeedPositiveMinconf
+	}
+
+	// Create map of address and amount pairs.
+	amt, err := btcutil.NewAmount(cmd.Amount)
+	if err != nil {
+		return nil, err
+	}
+	pairs := map[string]btcutil.Amount{
+		cmd.ToAddress: amt,
+	}
+
+	// Use specified scope, if provided.
+	scope, err := lookupKeyScope(cmd.AddressType)
+	if err != nil {
+		return nil, err
+	}
+
+	return sendOrPreviewPairs(ctx, w, pairs, scope, account, minConf,
+		txrules.DefaultRelayFeePerKb,
+		txComment(cmd.Comment, cmd.CommentTo))
+}
+
+// sendMany handles a sendmany RPC request by creating a new transaction
+// spending unspent transaction outputs for a wallet to any number of
+// payment addresses.  Leftover inputs not sent to the payment address
+// or a fee for the miner are sent back to a new address in the wallet.
+// Upon success, the TxID for the created transaction is returned.
+func sendMany(ctx context.Context, icmd interface{}, w *wallet.Wallet) (
+	interface{}, error) {
+
+	cmd := icmd.(*btcjson.SendManyCmd)
+
+	account, err := w.AccountNumber(cmd.FromAccount)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check that minconf is positive.
+	minConf := int32(*cmd.MinConf)
+	if minConf < 0 {
+		return nil, ErrNeedPositiveMinconf
+	}
+
+	// Use specified scope, if provided.
+	scope, err := lookupKeyScope(cmd.AddressType)
+	if err != nil {
+		return nil, err
+	}
+
+	// Recreate address/amount pairs, using dcrutil.Amount.
+	pairs := make(map[string]btcutil.Amount, len(cmd.Amounts))
+	for k, v := range cmd.Amounts {
+		amt, err := btcutil.NewAmount(v)
+		if err != nil {
+			return nil, err
+		}
+		pairs[k] = amt
+	}
+
+	return sendOrPreviewPairs(ctx, w, pairs, scope, account, minConf,
+		txrules.DefaultRelayFeePerKb, txComment(cmd.Comment, nil))
+}
+
+// sendToAddress handles a sendtoaddress RPC request by creating a new
+// transaction spending unspent transaction outputs for a wallet to another
+// payment address.  Leftover inputs not sent to the payment address or a fee
+// for the miner are sent back to a new address in the wallet.  Upon success,
+// the TxID for the created transaction is returned.
+func sendToAddress(ctx context.Context, icmd interface{},
+	w *wallet.Wallet) (interface{}, error) {
+
+	cmd := icmd.(*btcjson.SendToAddressCmd)
+
+	amt, err := btcutil.NewAmount(cmd.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check that signed integer parameters are positive.
+	if amt < 0 {
+		return nil, ErrNeedPositiveAmount
+	}
+
+	// Mock up map of address and amount pairs.
+	pairs := map[string]btcutil.Amount{
+		cmd.Address: amt,
+	}
+
+	// Use specified scope, if provided.
+	scope, err := lookupKeyScope(cmd.AddressType)
+	if err != nil {
+		return nil, err
+	}
+
+	// sendtoaddress always spends from the default account, this matches bitcoind
+	return sendOrPreviewPairs(ctx, w, pairs, scope,
+		waddrmgr.DefaultAccountNum, 1, txrules.DefaultRelayFeePerKb,
+		txComment(cmd.Comment, cmd.CommentTo))
+}
+
+// setTxFee sets the transaction fee per kilobyte added to transactions.
+func setTxFee(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*btcjson.SetTxFeeCmd)
+
+	// Check that amount is not negative.
+	if cmd.Amount < 0 {
+		return nil, ErrNeedPositiveAmount
+	}
+
+	// A boolean true result is returned upon success.
+	return true, nil
+}
+
+// getPrivacyReport handles a getprivacyreport request by analyzing the
+// wallet's transactions for patterns which link its addresses and accounts
+// together or reveal its change.
+func getPrivacyReport(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	report, err := w.PrivacyReport()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &walletjson.GetPrivacyReportResult{
+		Transactions: report.Transactions,
+		ReusedAddresses: make([]walletjson.ReusedAddressResult, 0,
+			len(report.ReusedAddresses)),
+		MergedInputs: make([]walletjson.MergedInputsResult, 0,
+			len(report.MergedInputs)),
+		RoundChange: make([]walletjson.RoundChangeResult, 0,
+			len(report.RoundChange)),
+	}
+	for _, r := range report.ReusedAddresses {
+		name, err := w.AccountName(r.Account)
+		if err != nil {
+			return nil, err
+		}
+		txids := make([]string, 0, len(r.Transactions))
+		for i := range r.Transactions {
+			txids = append(txids, r.Transactions[i].String())
+		}
+		result.ReusedAddresses = append(result.ReusedAddresses,
+			walletjson.ReusedAddressResult{
+				Address: r.Address.EncodeAddress(),
+				Account: name,
+				TxIDs:   txids,
+			})
+	}
+	for _, m := range report.MergedInputs {
+		names := make([]string, 0, len(m.Accounts))
+		for _, acct := range m.Accounts {
+			name, err := w.AccountName(acct)
+			if err != nil {
+				return nil, err
+			}
+			names = append(names, name)
+		}
+		result.MergedInputs = append(result.MergedInputs,
+			walletjson.MergedInputsResult{
+				TxID:     m.Hash.String(),
+				Accounts: names,
+			})
+	}
+	for _, r := range report.RoundChange {
+		result.RoundChange = append(result.RoundChange,
+			walletjson.RoundChangeResult{
+				TxID:   r.Hash.String(),
+				Change: r.Change,
+			})
+	}
+	return result, nil
+}
+
+// getSpendPolicy handles a getspendpolicy request by returning the wallet's
+// spend policy and the amount spent towards today's limit.
+func getSpendPolicy(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	policy, spentToday, err := w.SpendPolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &walletjson.GetSpendPolicyResult{
+		MaxTxAmount: policy.MaxTxAmount.ToBTC(),
+		DailyLimit:  policy.DailyLimit.ToBTC(),
+		SpentToday:  spentToday.ToBTC(),
+		Allowlist:   policy.Allowlist,
+		Denylist:    policy.Denylist,
+	}
+	if result.Allowlist == nil {
+		result.Allowlist = []string{}
+	}
+	if result.Denylist == nil {
+		result.Denylist = []string{}
+	}
+	return result, nil
+}
+
+// getWalletActivity handles a getwalletactivity request by returning the
+// transactions of an account summed by day or by range of blocks.
+func getWalletActivity(ctx context.Context, icmd interface{},
+	w *wallet.Wallet) (interface{}, error) {
+
+	cmd := icmd.(*walletjson.GetWalletActivityCmd)
+
+	if *cmd.Blocks < 0 {
+		return nil, InvalidParameterError{
+			errors.New("blocks must not be negative"),
+		}
+	}
+	account := *cmd.Account
+	if account != "*" {
+		if _, err := w.AccountNumber(account); err != nil {
+			return nil, err
+		}
+	}
+
+	periods, err := w.WalletActivity(ctx, account, *cmd.Blocks)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]walletjson.WalletActivityResult, 0, len(periods))
+	for i := range periods {
+		p := &periods[i]
+		result := walletjson.WalletActivityResult{
+			Transactions: p.Transactions,
+			Received:     p.Received.ToBTC(),
+			Sent:         p.Sent.ToBTC(),
+			Fees:         p.Fees.ToBTC(),
+			Claims:       p.Claims,
+			Supports:     p.Supports,
+			Updates:      p.Updates,
+			NewAddresses: p.NewAddresses,
+		}
+		if *cmd.Blocks == 0 {
+			result.Date = p.Start.Format(exportDateLayout)
+		} else {
+			result.StartHeight = &p.StartHeight
+			result.EndHeight = &p.EndHeight
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// getUptimeStats handles a getuptimestats request by returning the
+// availability of the wallet over the requested number of days.
+func getUptimeStats(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.GetUptimeStatsCmd)
+
+	days := int64(30)
+	if cmd.Days != nil {
+		days = *cmd.Days
+	}
+	if days <= 0 {
+		return nil, InvalidParameterError{errors.New("days must be " +
+			"positive")}
+	}
+	now := time.Now()
+	stats, err := w.UptimeStats(now.Add(-time.Duration(days) * 24 *
+		time.Hour))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &walletjson.GetUptimeStatsResult{
+		Since:        stats.Since.Unix(),
+		Starts:       make([]int64, 0, len(stats.Starts)),
+		Uptime:       int64(stats.Uptime / time.Second),
+		Disconnected: int64(stats.Disconnected / time.Second),
+		Disconnects: make([]walletjson.DisconnectWindowResult, 0,
+			len(stats.Disconnects)),
+		MaxSyncLag: stats.MaxSyncLag,
+		SyncLag: make([]walletjson.SyncLagResult, 0,
+			len(stats.SyncLag)),
+	}
+	if period := now.Sub(stats.Since); period > 0 {
+		connected := stats.Uptime - stats.Disconnected
+		result.Availability = 100 * float64(connected) /
+			float64(period)
+	}
+	for _, start := range stats.Starts {
+		result.Starts = append(result.Starts, start.Unix())
+	}
+	for _, d := range stats.Disconnects {
+		r := walletjson.DisconnectWindowResult{Start: d.Start.Unix()}
+		if !d.End.IsZero() {
+			r.End = d.End.Unix()
+		}
+		result.Disconnects = append(result.Disconnects, r)
+	}
+	for _, s := range stats.SyncLag {
+		result.SyncLag = append(result.SyncLag, walletjson.SyncLagResult{
+			Time:   s.Time.Unix(),
+			Height: s.Height,
+			Lag:    s.Lag,
+		})
+	}
+	return result, nil
+}
+
+// harnessSyncTimeout is how long the regtest harness methods wait for the
+// wallet to process the blocks they mine.
+const harnessSyncTimeout = 30 * time.Second
+
+// harnessMethods are the methods of the regtest harness, which are only
+// available with the --regtest-harness option.
+var harnessMethods = map[string]struct{}{
+	"fastforward":      {},
+	"fundaddress":      {},
+	"generatetowallet": {},
+}
+
+// mineToWallet mines blocks paying to a new address of an account, and waits
+// for the wallet to process them.  The hashes of the blocks are returned.
+func mineToWallet(w *wallet.Wallet, chainClient *chain.RPCClient,
+	account uint32, n int64) ([]string, error) {
+
+	addr, err := w.NewAddress(account, waddrmgr.KeyScopeBIP0044)
+	if err != nil {
+		return nil, err
+	}
+	hashes, err := chainClient.GenerateToAddress(n, addr, nil)
+	if err != nil {
+		return nil, err
+	}
+	_, height, err := chainClient.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+	if err := w.WaitForHeight(height, harnessSyncTimeout); err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0, len(hashes))
+	for _, hash := range hashes {
+		result = append(result, hash.String())
+	}
+	return result, nil
+}
+
+// generateToWallet handles a generatetowallet request by mining blocks whose
+// coinbases pay to an account of the wallet, and returning their hashes once
+// the wallet has processed them.
+func generateToWallet(icmd interface{}, w *wallet.Wallet,
+	chainClient *chain.RPCClient) (interface{}, error) {
+
+	cmd := icmd.(*walletjson.GenerateToWalletCmd)
+
+	if cmd.NumBlocks <= 0 {
+		return nil, InvalidParameterError{errors.New("nblocks must be " +
+			"positive")}
+	}
+	acctName := defaultAccountName
+	if cmd.Account != nil && *cmd.Account != "" {
+		acctName = *cmd.Account
+	}
+	account, err := w.AccountNumber(acctName)
+	if err != nil {
+		return nil, err
+	}
+	return mineToWallet(w, chainClient, account, cmd.NumBlocks)
+}
+
+// fundAddress handles a fundaddress request by sending an amount from the
+// default account to an address, and mining blocks to confirm the
+// transaction.
+func fundAddress(ctx context.Context, icmd interface{}, w *wallet.Wallet,
+	chainClient *chain.RPCClient) (interface{}, error) {
+
+	cmd := icmd.(*walletjson.FundAddressCmd)
+
+	amt, err := btcutil.NewAmount(cmd.Amount)
+	if err != nil {
+		return nil, err
+	}
+	if amt <= 0 {
+		return nil, ErrNeedPositiveAmount
+	}
+	blocks := int64(1)
+	if cmd.Blocks != nil {
+		blocks = *cmd.Blocks
+	}
+	if blocks < 0 {
+		return nil, InvalidParameterError{errors.New("nblocks must not " +
+			"be negative")}
+	}
+
+	txid, err := sendPairs(ctx, w,
+		map[string]btcutil.Amount{cmd.Address: amt}, nil,
+		waddrmgr.DefaultAccountNum, 1, txrules.DefaultRelayFeePerKb,
+		nil)
+	if err != nil {
+		return nil, err
+	}
+	result := &walletjson.FundAddressResult{TxID: txid, Blocks: []string{}}
+	if blocks == 0 {
+		return result, nil
+	}
+	result.Blocks, err = mineToWallet(w, chainClient,
+		waddrmgr.DefaultAccountNum, blocks)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// fastForward handles a fastforward request by advancing the wallet clock.
+func fastForward(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.FastForwardCmd)
+
+	if cmd.Seconds <= 0 {
+		return nil, InvalidParameterError{errors.New("seconds must be " +
+			"positive")}
+	}
+	now, err := w.FastForward(time.Duration(cmd.Seconds) * time.Second)
+	if err != nil {
+		return nil, err
+	}
+	return &walletjson.FastForwardResult{
+		Time:   now.Unix(),
+		Offset: int64(w.ClockOffset() / time.Second),
+	}, nil
+}
+
+// faucetWaitTimeout is how long requesttestcoins waits for the deposit of
+// the faucet when asked to.
+const faucetWaitTimeout = 2 * time.Minute
+
+// requestTestCoins handles a requesttestcoins request by asking the faucet
+// to pay a new address of the account, and waiting for the deposit if asked.
+func requestTestCoins(icmd interface{}, w *wallet.Wallet) (interface{},
+	error) {
+
+	cmd := icmd.(*walletjson.RequestTestCoinsCmd)
+
+	var amount btcutil.Amount
+	if cmd.Amount != nil {
+		if *cmd.Amount <= 0 {
+			return nil, ErrNeedPositiveAmount
+		}
+		var err error
+		amount, err = btcutil.NewAmount(*cmd.Amount)
+		if err != nil {
+			return nil, err
+		}
+	}
+	account, err := w.AccountNumber(*cmd.Account)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := w.RequestTestCoins(account, amount)
+	if err != nil {
+		return nil, err
+	}
+	result := &walletjson.RequestTestCoinsResult{
+		Address: req.Address.EncodeAddress(),
+		Amount:  req.Amount.ToBTC(),
+	}
+	if req.TxHash != nil {
+		result.TxID = req.TxHash.String()
+	}
+	if cmd.Wait != nil && *cmd.Wait {
+		received, err := w.WaitForTestCoins(req, faucetWaitTimeout)
+		if err != nil {
+			return nil, err
+		}
+		result.Received = received.ToBTC()
+	}
+	return result, nil
+}
+
+// qrCodeScale is the number of pixels per module of the QR codes returned by
+// createpaymenturi.
+const qrCodeScale = 4
+
+// createPaymentURI handles a createpaymenturi request by returning a BIP0021
+// payment URI for the address, and optionally a QR code of the URI as a
+// base64 encoded PNG image.
+func createPaymentURI(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.CreatePaymentURICmd)
+
+	addr, err := decodeAddress(cmd.Address, w.ChainParams())
+	if err != nil {
+		return nil, err
+	}
+	_, decoy, err := w.DecoyPath(addr)
+	if err != nil {
+		return nil, err
+	}
+	if decoy {
+		return nil, InvalidParameterError{wallet.ErrDecoyAddress}
+	}
+	uri := bip21.URI{Address: addr.EncodeAddress()}
+	if cmd.Amount != nil {
+		if *cmd.Amount <= 0 {
+			return nil, ErrNeedPositiveAmount
+		}
+		uri.Amount, err = btcutil.NewAmount(*cmd.Amount)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if cmd.Label != nil {
+		uri.Label = *cmd.Label
+	}
+	if cmd.Message != nil {
+		uri.Message = *cmd.Message
+	}
+
+	result := &walletjson.CreatePaymentURIResult{URI: uri.String()}
+	if cmd.QRCode != nil && *cmd.QRCode {
+		code, err := qrcode.Encode([]byte(result.URI), qrcode.Medium)
+		if err != nil {
+			return nil, InvalidParameterError{err}
+		}
+		var buf bytes.Buffer
+		if err := code.WritePNG(&buf, qrCodeScale); err != nil {
+			return nil, err
+		}
+		result.QRCode = base64.StdEncoding.EncodeToString(buf.Bytes())
+	}
+	return result, nil
+}
+
+// decodePaymentURI handles a decodepaymenturi request by returning the
+// address and parameters of a BIP0021 payment URI.
+func decodePaymentURI(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.DecodePaymentURICmd)
+
+	uri, err := bip21.Parse(cmd.URI)
+	if err != nil {
+		return nil, InvalidParameterError{err}
+	}
+	addr, err := decodeAddress(uri.Address, w.ChainParams())
+	if err != nil {
+		return nil, err
+	}
+	return &walletjson.DecodePaymentURIResult{
+		Address: addr.EncodeAddress(),
+		Amount:  uri.Amount.ToBTC(),
+		Label:   uri.Label,
+		Message: uri.Message,
+		Params:  uri.Params,
+	}, nil
+}
+
+// txBackend answers the questions of the wallet about the mempool and fee
+// rates with the chain server.
+type txBackend struct {
+	*chain.RPCClient
+}
+
+// MempoolFee returns the fee of a transaction in the mempool of the chain
+// server, and false if the transaction is not in the mempool.
+func (b txBackend) MempoolFee(hash *chainhash.Hash) (btcutil.Amount, bool,
+	error) {
+
+	entries, err := b.GetRawMempoolVerbose()
+	if err != nil {
+		return 0, false, err
+	}
+	entry, ok := entries[hash.String()]
+	if !ok {
+		return 0, false, nil
+	}
+	fee, err := btcutil.NewAmount(entry.Fee)
+	return fee, true, err
+}
+
+// OutputUnspent reports whether an output is unspent in the chain or in the
+// mempool of the chain server.
+func (b txBackend) OutputUnspent(op *wire.OutPoint) (bool, error) {
+	out, err := b.GetTxOut(&op.Hash, op.Index, true)
+	return out != nil, err
+}
+
+// EstimateFeeRate returns the fee rate per kvB estimated by the chain server
+// to be mined within a number of blocks, or zero when the server refuses to
+// estimate, as without enough data.
+func (b txBackend) EstimateFeeRate(blocks int32) (btcutil.Amount, error) {
+	rate, err := b.EstimateFee(int64(blocks))
+	var rpcErr *btcjson.RPCError
+	switch {
+	case errors.As(err, &rpcErr):
+		return 0, nil
+	case err != nil:
+		return 0, err
+	case rate <= 0:
+		return 0, nil
+	}
+	return btcutil.NewAmount(rate)
+}
+
+// diagnoseTransaction handles a diagnosetransaction request by explaining why
+// an unmined wallet transaction is not confirming.
+func diagnoseTransaction(icmd interface{}, w *wallet.Wallet,
+	chainClient *chain.RPCClient) (interface{}, error) {
+
+	cmd := icmd.(*walletjson.DiagnoseTransactionCmd)
+
+	txHash, err := chainhash.NewHashFromStr(cmd.Txid)
+	if err != nil {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCDecodeHexString,
+			Message: "Transaction hash string decode failed: " + err.Error(),
+		}
+	}
+
+	d, err := w.DiagnoseTransaction(txHash, txBackend{chainClient})
+	if errors.Is(err, wallet.ErrTxNotFound) {
+		return nil, &ErrNoTransactionInfo
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &walletjson.DiagnoseTransactionResult{
+		TxID:          d.Hash.String(),
+		Confirmations: d.Confirmations,
+		TimeReceived:  d.Received.Unix(),
+		VSize:         d.VSize,
+		InMempool:     d.InMempool,
+		Reasons:       append([]string{}, d.Reasons...),
+		Actions:       append([]string{}, d.Actions...),
+	}
+	if d.Fee >= 0 {
+		fee := d.Fee.ToBTC()
+		feeRate := d.FeeRate.ToBTC()
+		result.Fee = &fee
+		result.FeeRate = &feeRate
+	}
+	if d.EstimatedFeeRate > 0 {
+		estimate := d.EstimatedFeeRate.ToBTC()
+		result.EstimatedFeeRate = &estimate
+	}
+	if !d.BroadcastAt.IsZero() {
+		result.BroadcastTime = d.BroadcastAt.Unix()
+	}
+	for _, hash := range d.UnminedParents {
+		result.UnminedParents = append(result.UnminedParents,
+			hash.String())
+	}
+	for _, op := range d.MissingInputs {
+		result.MissingInputs = append(result.MissingInputs, op.String())
+	}
+	for _, hash := range d.Conflicts {
+		result.Conflicts = append(result.Conflicts, hash.String())
+	}
+	return result, nil
+}
+
+// invoiceResult returns the JSON result describing an invoice.
+func invoiceResult(inv *wallet.Invoice) *walletjson.InvoiceResult {
+	uri := bip21.URI{
+		Address: inv.Address.EncodeAddress(),
+		Amount:  inv.Amount,
+		Message: inv.Memo,
+	}
+	result := &walletjson.InvoiceResult{
+		ID:       inv.ID,
+		Address:  inv.Address.EncodeAddress(),
+		URI:      uri.String(),
+		Amount:   inv.Amount.ToBTC(),
+		Memo:     inv.Memo,
+		Created:  inv.Created.Unix(),
+		Expires:  inv.Expires.Unix(),
+		State:    inv.State.String(),
+		Received: inv.Received().ToBTC(),
+		Payments: make([]walletjson.InvoicePaymentResult, 0, len(inv.Payments)),
+	}
+	if !inv.Paid.IsZero() {
+		result.PaidTime = inv.Paid.Unix()
+	}
+	for _, p := range inv.Payments {
+		result.Payments = append(result.Payments, walletjson.InvoicePaymentResult{
+			TxID:   p.TxHash.String(),
+			Amount: p.Amount.ToBTC(),
+		})
+	}
+	return result
+}
+
+// createInvoice handles a createinvoice request by creating an invoice paid
+// to a new address of an account.
+func createInvoice(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.CreateInvoiceCmd)
+
+	if cmd.Amount < 0 {
+		return nil, ErrNeedPositiveAmount
+	}
+	amount, err := btcutil.NewAmount(cmd.Amount)
+	if err != nil {
+		return nil, err
+	}
+	expiry := time.Hour
+	if cmd.Expiry != nil {
+		if *cmd.Expiry <= 0 {
+			return nil, InvalidParameterError{
+				errors.New("expiry must be positive"),
+			}
+		}
+		expiry = time.Duration(*cmd.Expiry) * time.Second
+	}
+	var memo string
+	if cmd.Memo != nil {
+		memo = *cmd.Memo
+	}
+	acctName := defaultAccountName
+	if cmd.Account != nil && *cmd.Account != "" {
+		acctName = *cmd.Account
+	}
+	account, err := w.AccountNumber(acctName)
+	if err != nil {
+		return nil, err
+	}
+
+	inv, err := w.CreateInvoice(
+		account, waddrmgr.KeyScopeBIP0044, amount, memo, expiry,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return invoiceResult(inv), nil
+}
+
+// exportDateLayout is the layout of the dates of an exporttransactions
+// request.
+const exportDateLayout = "2006-01-02"
+
+// exportTransactions handles an exporttransactions request by returning, or
+// writing to a new file, the wallet transactions received between two dates
+// in a format read by bookkeeping software.
+func exportTransactions(ctx context.Context, icmd interface{},
+	w *wallet.Wallet) (interface{}, error) {
+
+	cmd := icmd.(*walletjson.ExportTransactionsCmd)
+
+	format := accounting.Format(strings.ToLower(cmd.Format))
+	switch format {
+	case accounting.CSV, accounting.OFX, accounting.QIF:
+	default:
+		return nil, InvalidParameterError{
+			fmt.Errorf("unknown export format %q", cmd.Format),
+		}
+	}
+
+	// The dates are UTC, and the end date is inclusive.
+	var start, end time.Time
+	if cmd.StartDate != nil && *cmd.StartDate != "" {
+		var err error
+		start, err = time.Parse(exportDateLayout, *cmd.StartDate)
+		if err != nil {
+			return nil, InvalidParameterError{
+				fmt.Errorf("start date must be YYYY-MM-DD: %v", err),
+			}
+		}
+	}
+	if cmd.EndDate != nil && *cmd.EndDate != "" {
+		date, err := time.Parse(exportDateLayout, *cmd.EndDate)
+		if err != nil {
+			return nil, InvalidParameterError{
+				fmt.Errorf("end date must be YYYY-MM-DD: %v", err),
+			}
+		}
+		end = date.AddDate(0, 0, 1)
+	}
+	if !start.IsZero() && !end.IsZero() && !start.Before(end) {
+		return nil, InvalidParameterError{
+			errors.New("start date is after the end date"),
+		}
+	}
+
+	account := *cmd.Account
+	if account != "*" {
+		if _, err := w.AccountNumber(account); err != nil {
+			return nil, err
+		}
+	}
+
+	entries, err := w.ExportTransactions(ctx, account, start, end)
+	if err != nil {
+		return nil, err
+	}
+	result := &walletjson.ExportTransactionsResult{
+		Format:  string(format),
+		Entries: len(entries),
+	}
+
+	// Never overwrite an existing file.
+	if cmd.Filename != nil && *cmd.Filename != "" {
+		f, err := os.OpenFile(*cmd.Filename,
+			os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err != nil {
+			return nil, &btcjson.RPCError{
+				Code:    btcjson.ErrRPCWallet,
+				Message: err.Error(),
+			}
+		}
+		err = accounting.Write(f, format, entries, start, end)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Filename = *cmd.Filename
+		return result, nil
+	}
+
+	var buf bytes.Buffer
+	if err := accounting.Write(&buf, format, entries, start, end); err != nil {
+		return nil, err
+	}
+	result.Data = buf.String()
+	return result, nil
+}
+
+// exportTxLog handles an exporttxlog request by returning, or writing to a new
+// file, the transaction log of the wallet, which can be replayed into a fresh
+// wallet created from the same seed to check that its history is
+// reconstructed.
+func exportTxLog(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.ExportTxLogCmd)
+
+	var buf bytes.Buffer
+	summary, err := w.ExportTxLog(&buf)
+	if err != nil {
+		return nil, err
+	}
+	result := &walletjson.ExportTxLogResult{
+		Transactions: summary.Transactions,
+		Balance:      summary.Balance.ToBTC(),
+		Digest:       summary.Digest.String(),
+	}
+
+	// Never overwrite an existing file.
+	if cmd.Filename != nil && *cmd.Filename != "" {
+		f, err := os.OpenFile(*cmd.Filename,
+			os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err != nil {
+			return nil, &btcjson.RPCError{
+				Code:    btcjson.ErrRPCWallet,
+				Message: err.Error(),
+			}
+		}
+		_, err = buf.WriteTo(f)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Filename = *cmd.Filename
+		return result, nil
+	}
+
+	result.Data = buf.String()
+	return result, nil
+}
+
+// getInvoice handles a getinvoice request by returning an invoice.
+func getInvoice(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.GetInvoiceCmd)
+
+	inv, err := w.Invoice(cmd.ID)
+	if err == wallet.ErrInvoiceNotFound {
+		return nil, &ErrInvoiceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return invoiceResult(inv), nil
+}
+
+// listInvoices handles a listinvoices request by returning all invoices,
+// optionally only those in a state.
+func listInvoices(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.ListInvoicesCmd)
+
+	if cmd.State != nil {
+		switch *cmd.State {
+		case wallet.InvoiceUnpaid.String(), wallet.InvoicePaid.String(),
+			wallet.InvoiceExpired.String():
+		default:
+			return nil, InvalidParameterError{
+				fmt.Errorf("unknown invoice state %q", *cmd.State),
+			}
+		}
+	}
+
+	invs, err := w.Invoices()
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*walletjson.InvoiceResult, 0, len(invs))
+	for _, inv := range invs {
+		if cmd.State != nil && inv.State.String() != *cmd.State {
+			continue
+		}
+		results = append(results, invoiceResult(inv))
+	}
+	return results, nil
+}
+
+// dumpGoroutines handles a dumpgoroutines request by returning the stack
+// traces of all goroutines, as served by /debug/pprof/goroutine?debug=2.
+func dumpGoroutines(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	var buf bytes.Buffer
+	err := pprof.Lookup("goroutine").WriteTo(&buf, 2)
+	if err != nil {
+		return nil, err
+	}
+	return buf.String(), nil
+}
+
+// dumpHeapProfile handles a dumpheapprofile request by returning the heap
+// profile, as served by /debug/pprof/heap, encoded in base64.  The decoded
+// profile can be read by go tool pprof.
+func dumpHeapProfile(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.DumpHeapProfileCmd)
+
+	if cmd.GC != nil && *cmd.GC {
+		runtime.GC()
+	}
+	var buf bytes.Buffer
+	err := pprof.Lookup("heap").WriteTo(&buf, 0)
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// benchSigning handles a benchsigning request by measuring the throughput of
+// the key derivations, signatures and signature verifications of the host,
+// and the max rate of the transactions they allow the wallet to create.
+func benchSigning(ctx context.Context, icmd interface{},
+	w *wallet.Wallet) (interface{}, error) {
+
+	cmd := icmd.(*walletjson.BenchSigningCmd)
+
+	if *cmd.Duration < 1 || *cmd.Duration > 60 {
+		return nil, InvalidParameterError{
+			errors.New("duration must be between 1 and 60 seconds"),
+		}
+	}
+	if *cmd.Inputs < 1 || *cmd.Inputs > 1000 {
+		return nil, InvalidParameterError{
+			errors.New("inputs must be between 1 and 1000"),
+		}
+	}
+
+	d := time.Duration(*cmd.Duration) * time.Second
+	result, err := signbench.Run(ctx, d, *cmd.Inputs)
+	if err != nil {
+		return nil, err
+	}
+	return &walletjson.BenchSigningResult{
+		Derivations:         result.Derivations,
+		HardenedDerivations: result.HardenedDerivations,
+		Signatures:          result.Signatures,
+		Verifications:       result.Verifications,
+		Inputs:              result.Inputs,
+		Transactions:        result.TxRate,
+	}, nil
+}
+
+// setSpendPolicy handles a setspendpolicy request by updating the fields of
+// the wallet's spend policy which are set in the request.  Loosening the
+// policy requires the wallet passphrase, so that the policy restrains the
+// RPC clients which don't know it.
+func setSpendPolicy(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.SetSpendPolicyCmd)
+
+	policy, _, err := w.SpendPolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	if cmd.MaxTxAmount != nil {
+		if *cmd.MaxTxAmount < 0 {
+			return nil, ErrNeedPositiveAmount
+		}
+		policy.MaxTxAmount, err = btcutil.NewAmount(*cmd.MaxTxAmount)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if cmd.DailyLimit != nil {
+		if *cmd.DailyLimit < 0 {
+			return nil, ErrNeedPositiveAmount
+		}
+		policy.DailyLimit, err = btcutil.NewAmount(*cmd.DailyLimit)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if cmd.Allowlist != nil {
+		policy.Allowlist = *cmd.Allowlist
+	}
+	if cmd.Denylist != nil {
+		policy.Denylist = *cmd.Denylist
+	}
+
+	var passphrase []byte
+	if cmd.Passphrase != nil {
+		passphrase = []byte(*cmd.Passphrase)
+		defer zero.Bytes(passphrase)
+	}
+	err = w.SetSpendPolicy(policy, passphrase)
+	switch {
+	case err == wallet.ErrSpendPolicyPassphrase:
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCWalletUnlockNeeded,
+			Message: err.Error(),
+		}
+	case waddrmgr.IsError(err, waddrmgr.ErrWrongPassphrase):
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCWalletPassphraseIncorrect,
+			Message: "Incorrect passphrase",
+		}
+	case err != nil:
+		return nil, InvalidParameterError{err}
+	}
+	return nil, nil
 }
 
-// makeOutputs creates a slice of transaction outputs from a pair of address
-// strings to amounts.  This is used to create the outputs to include in newly
-// created transactions from a JSON object describing the output destinations
-// and amounts.
-func makeOutputs(pairs map[string]btcutil.Amount, chainParams *chaincfg.Params) ([]*wire.TxOut, error) {
-	outputs := make([]*wire.TxOut, 0, len(pairs))
-	for addrStr, amt := range pairs {
-		addr, err := btcutil.DecodeAddress(addrStr, chainParams)
-		if err != nil {
-			return nil, fmt.Errorf("cannot decode address: %s", err)
-		}
+// sweepAccountPsbt handles a sweepaccountpsbt request by returning an
+// unsigned PSBT spending every eligible output of an account to an address,
+// for the holder of the account's keys to sign.
+func sweepAccountPsbt(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.SweepAccountPsbtCmd)
 
-		pkScript, err := txscript.PayToAddrScript(addr)
+	scope, account, err := w.LookupAccount(cmd.Account)
+	if err != nil {
+		return nil, err
+	}
+	addr, err := decodeAddress(cmd.Address, w.ChainParams())
+	if err != nil {
+		return nil, err
+	}
+	feeSatPerKb := txrules.DefaultRelayFeePerKb
+	if cmd.FeeRate != nil {
+		if *cmd.FeeRate < 0 {
+			return nil, ErrNeedPositiveAmount
+		}
+		feeSatPerKb, err = btcutil.NewAmount(*cmd.FeeRate)
 		if err != nil {
-			return nil, fmt.Errorf("cannot create txout script: %s", err)
+			return nil, err
 		}
+	}
 
-		outputs = append(outputs, wire.NewTxOut(int64(amt), pkScript))
+	packet, fee, amount, err := w.SweepAccountPsbt(
+		&scope, account, addr, int32(*cmd.MinConf), feeSatPerKb,
+	)
+	if err != nil {
+		return nil, InvalidParameterError{err}
 	}
-	return outputs, nil
+	b64, err := packet.B64Encode()
+	if err != nil {
+		return nil, err
+	}
+	return &walletjson.SweepAccountPsbtResult{
+		Psbt:   b64,
+		Fee:    fee.ToBTC(),
+		Amount: amount.ToBTC(),
+	}, nil
 }
 
-// rescanBlockchain handles a rescanblockhain RPC request.
-func rescanBlockchain(icmd interface{}, w *wallet.Wallet,
-	chainClient *chain.RPCClient) (interface{}, error) {
+// decodePsbt decodes a base64 encoded PSBT parameter.
+func decodePsbt(b64 string) (*psbt.Packet, error) {
+	packet, err := psbt.NewFromRawBytes(
+		strings.NewReader(strings.TrimSpace(b64)), true,
+	)
+	if err != nil {
+		return nil, InvalidParameterError{
+			fmt.Errorf("invalid PSBT: %v", err),
+		}
+	}
+	return packet, nil
+}
 
-	cmd := icmd.(*btcjson.RescanBlockchainCmd)
+// signPsbt handles a signpsbt request by signing the inputs of a PSBT spending
+// outputs of the keys of the wallet, such as one created by a send of the
+// watch-only copy of the wallet.
+func signPsbt(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.SignPsbtCmd)
 
-	_, bestHeight, err := chainClient.GetBestBlock()
-	if err != nil {
+	if err := checkWithdrawalPolicy("signpsbt", w); err != nil {
 		return nil, err
 	}
 
-	startHeight := *cmd.StartHeight
-	if startHeight < 0 || startHeight > bestHeight {
-		return nil, &btcjson.RPCError{
-			Code:    btcjson.ErrRPCInvalidParameter,
-			Message: "Invalid start height",
-		}
+	packet, err := decodePsbt(cmd.Psbt)
+	if err != nil {
+		return nil, err
 	}
-
-	// Scan to the best block if no stopHeight is specified.
-	stopHeight := bestHeight
-	if cmd.StopHeight != nil {
-		stopHeight = *cmd.StopHeight
+	signed, err := w.SignPsbt(packet)
+	if waddrmgr.IsError(err, waddrmgr.ErrLocked) {
+		return nil, &ErrWalletUnlockNeeded
 	}
-	if stopHeight < 0 || stopHeight > bestHeight {
+	if err != nil {
 		return nil, &btcjson.RPCError{
-			Code:    btcjson.ErrRPCInvalidParameter,
-			Message: "Invalid stop height",
+			Code:    btcjson.ErrRPCWallet,
+			Message: err.Error(),
 		}
 	}
-
-	startHeight, stopHeight, err = w.RescanBlockchain(chainClient,
-		startHeight, stopHeight)
+	b64, err := packet.B64Encode()
 	if err != nil {
-		return nil, fmt.Errorf("rescanblockchain: %w", err)
-	}
-	ret := btcjson.RescanBlockchainResult{
-		StartHeight: startHeight,
-		StoptHeight: stopHeight,
+		return nil, err
 	}
-	return ret, nil
+	return &walletjson.SignPsbtResult{
+		Psbt:     b64,
+		Signed:   signed,
+		Complete: packet.IsComplete(),
+	}, nil
 }
 
-// sendPairs creates and sends payment transactions.
-// It returns the transaction hash in string format upon success
-// All errors are returned in btcjson.RPCError format
-func sendPairs(w *wallet.Wallet, amounts map[string]btcutil.Amount,
-	keyScope *waddrmgr.KeyScope, account uint32, minconf int32,
-	feeSatPerKb btcutil.Amount) (string, error) {
+// publishPsbt handles a publishpsbt request by publishing the transaction of a
+// signed PSBT.
+func publishPsbt(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.PublishPsbtCmd)
 
-	outputs, err := makeOutputs(amounts, w.ChainParams())
+	packet, err := decodePsbt(cmd.Psbt)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	tx, err := w.SendOutputs(
-		outputs, keyScope, account, minconf, feeSatPerKb,
-		wallet.CoinSelectionLargest, "",
-	)
-	if err != nil {
-		if err == txrules.ErrAmountNegative {
-			return "", ErrNeedPositiveAmount
-		}
-		if waddrmgr.IsError(err, waddrmgr.ErrLocked) {
-			return "", &ErrWalletUnlockNeeded
-		}
-		if _, ok := err.(btcjson.RPCError); ok {
-			return "", err
-		}
-
-		return "", &btcjson.RPCError{
-			Code:    btcjson.ErrRPCInternal.Code,
+	tx, err := w.PublishPsbt(packet)
+	if errors.Is(err, wallet.ErrPsbtNotSigned) {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCWallet,
 			Message: err.Error(),
 		}
 	}
+	if err != nil {
+		return nil, sendError(err)
+	}
 
 	txHashStr := tx.TxHash().String()
 	log.Infof("Successfully sent transaction %v", txHashStr)
 	return txHashStr, nil
 }
 
-func isNilOrEmpty(s *string) bool {
-	return s == nil || *s == ""
+// feeRateParam returns the fee rate per kB of an optional feerate parameter,
+// which defaults to the minimum relay fee rate.
+func feeRateParam(feeRate *float64) (btcutil.Amount, error) {
+	if feeRate == nil {
+		return txrules.DefaultRelayFeePerKb, nil
+	}
+	if *feeRate < 0 {
+		return 0, ErrNeedPositiveAmount
+	}
+	return btcutil.NewAmount(*feeRate)
 }
 
-// sendFrom handles a sendfrom RPC request by creating a new transaction
-// spending unspent transaction outputs for a wallet to another payment
-// address.  Leftover inputs not sent to the payment address or a fee for
-// the miner are sent back to a new address in the wallet.  Upon success,
-// the TxID for the created transaction is returned.
-func sendFrom(icmd interface{}, w *wallet.Wallet,
-	chainClient *chain.RPCClient) (interface{}, error) {
-
-	cmd := icmd.(*btcjson.SendFromCmd)
-
-	// Transaction comments are not yet supported.  Error instead of
-	// pretending to save them.
-	if !isNilOrEmpty(cmd.Comment) || !isNilOrEmpty(cmd.CommentTo) {
-		return nil, &btcjson.RPCError{
-			Code:    btcjson.ErrRPCUnimplemented,
-			Message: "Transaction comments are not yet supported",
+// vaultError converts the errors of the vault methods of the wallet to RPC
+// errors.
+func vaultError(err error) error {
+	switch {
+	case errors.Is(err, wallet.ErrVaultNotFound):
+		return &ErrVaultNotFound
+	case errors.Is(err, wallet.ErrVaultInsufficientFunds),
+		errors.Is(err, wallet.ErrVaultRecoveryKey),
+		errors.Is(err, wallet.ErrNothingToSweep):
+
+		return &btcjson.RPCError{
+			Code:    btcjson.ErrRPCWallet,
+			Message: err.Error(),
 		}
+	case waddrmgr.IsError(err, waddrmgr.ErrLocked):
+		return &ErrWalletUnlockNeeded
 	}
+	return err
+}
 
-	account, err := w.AccountNumber(cmd.FromAccount)
-	if err != nil {
-		return nil, err
-	}
+// createVault handles a createvault request by creating a vault account whose
+// outputs are spent by its keys after a delay, and by a recovery key at any
+// time.
+func createVault(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.CreateVaultCmd)
 
-	// Check that signed integer parameters are positive.
-	if cmd.Amount < 0 {
-		return nil, ErrNeedPositiveAmount
-	}
-	minConf := int32(*cmd.MinConf)
-	if minConf < 0 {
-		return nil, ErrNeedPositiveMinconf
+	// The wildcard * is reserved by the rpc server with the special meaning
+	// of "all accounts", so disallow naming accounts to this string.
+	if cmd.Name == "*" {
+		return nil, &ErrReservedAccountName
 	}
-
-	// Create map of address and amount pairs.
-	amt, err := btcutil.NewAmount(cmd.Amount)
+	serializedPubKey, err := decodeHexStr(cmd.RecoveryPubKey)
 	if err != nil {
 		return nil, err
 	}
-	pairs := map[string]btcutil.Amount{
-		cmd.ToAddress: amt,
-	}
-
-	// Use specified scope, if provided.
-	scope, err := lookupKeyScope(cmd.AddressType)
+	recoveryKey, err := btcec.ParsePubKey(serializedPubKey, btcec.S256())
 	if err != nil {
-		return nil, err
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidAddressOrKey,
+			Message: "Pubkey decode failed: " + err.Error(),
+		}
+	}
+	if cmd.Delay == 0 {
+		return nil, InvalidParameterError{
+			errors.New("delay must be positive"),
+		}
 	}
 
-	return sendPairs(w, pairs, scope, account, minConf,
-		txrules.DefaultRelayFeePerKb)
+	_, err = w.CreateVault(cmd.Name, recoveryKey, cmd.Delay)
+	if waddrmgr.IsError(err, waddrmgr.ErrDuplicateAccount) {
+		return nil, InvalidParameterError{err}
+	}
+	return nil, vaultError(err)
 }
 
-// sendMany handles a sendmany RPC request by creating a new transaction
-// spending unspent transaction outputs for a wallet to any number of
-// payment addresses.  Leftover inputs not sent to the payment address
-// or a fee for the miner are sent back to a new address in the wallet.
-// Upon success, the TxID for the created transaction is returned.
-func sendMany(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
-
-	cmd := icmd.(*btcjson.SendManyCmd)
+// getVaultAddress handles a getvaultaddress request by returning a new address
+// of a vault.
+func getVaultAddress(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.GetVaultAddressCmd)
 
-	// Transaction comments are not yet supported.  Error instead of
-	// pretending to save them.
-	if !isNilOrEmpty(cmd.Comment) {
-		return nil, &btcjson.RPCError{
-			Code:    btcjson.ErrRPCUnimplemented,
-			Message: "Transaction comments are not yet supported",
-		}
+	addr, err := w.NewVaultAddress(cmd.Name)
+	if err != nil {
+		return nil, vaultError(err)
 	}
+	return addr.EncodeAddress(), nil
+}
 
-	account, err := w.AccountNumber(cmd.FromAccount)
+// listVaults handles a listvaults request by returning every vault with its
+// balances.
+func listVaults(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	vaults, err := w.Vaults()
 	if err != nil {
 		return nil, err
 	}
-
-	// Check that minconf is positive.
-	minConf := int32(*cmd.MinConf)
-	if minConf < 0 {
-		return nil, ErrNeedPositiveMinconf
+	result := make([]walletjson.VaultResult, 0, len(vaults))
+	for _, v := range vaults {
+		result = append(result, walletjson.VaultResult{
+			Name:    v.Name,
+			Account: v.Account,
+			RecoveryPubKey: hex.EncodeToString(
+				v.RecoveryKey.SerializeCompressed(),
+			),
+			Delay:     v.Delay,
+			Balance:   v.Balance.ToBTC(),
+			Spendable: v.Spendable.ToBTC(),
+		})
 	}
+	return result, nil
+}
 
-	// Use specified scope, if provided.
-	scope, err := lookupKeyScope(cmd.AddressType)
+// sendFromVault handles a sendfromvault request by paying addresses from the
+// outputs of a vault whose delay has elapsed, and returns the transaction
+// hash.
+func sendFromVault(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.SendFromVaultCmd)
+
+	feeSatPerKb, err := feeRateParam(cmd.FeeRate)
 	if err != nil {
 		return nil, err
 	}
-
-	// Recreate address/amount pairs, using dcrutil.Amount.
 	pairs := make(map[string]btcutil.Amount, len(cmd.Amounts))
 	for k, v := range cmd.Amounts {
 		amt, err := btcutil.NewAmount(v)
 		if err != nil {
 			return nil, err
 		}
+		if amt <= 0 {
+			return nil, ErrNeedPositiveAmount
+		}
 		pairs[k] = amt
 	}
+	outputs, err := makeOutputs(pairs, w.ChainParams())
+	if err != nil {
+		return nil, InvalidParameterError{err}
+	}
 
-	return sendPairs(w, pairs, scope, account, minConf, txrules.DefaultRelayFeePerKb)
+	tx, err := w.SendFromVault(cmd.Name, outputs, feeSatPerKb, "")
+	if err != nil {
+		return nil, vaultError(err)
+	}
+	return tx.TxHash().String(), nil
 }
 
-// sendToAddress handles a sendtoaddress RPC request by creating a new
-// transaction spending unspent transaction outputs for a wallet to another
-// payment address.  Leftover inputs not sent to the payment address or a fee
-// for the miner are sent back to a new address in the wallet.  Upon success,
-// the TxID for the created transaction is returned.
-func sendToAddress(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
-	cmd := icmd.(*btcjson.SendToAddressCmd)
+// recoverVault handles a recovervault request by sweeping every output of a
+// vault to an address with its recovery key, and returns the transaction
+// hash.
+func recoverVault(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.RecoverVaultCmd)
 
-	// Transaction comments are not yet supported.  Error instead of
-	// pretending to save them.
-	if !isNilOrEmpty(cmd.Comment) || !isNilOrEmpty(cmd.CommentTo) {
+	wif, err := btcutil.DecodeWIF(cmd.RecoveryPrivKey)
+	if err != nil {
 		return nil, &btcjson.RPCError{
-			Code:    btcjson.ErrRPCUnimplemented,
-			Message: "Transaction comments are not yet supported",
+			Code:    btcjson.ErrRPCInvalidAddressOrKey,
+			Message: "WIF decode failed: " + err.Error(),
 		}
 	}
-
-	amt, err := btcutil.NewAmount(cmd.Amount)
+	addr, err := decodeAddress(cmd.Address, w.ChainParams())
 	if err != nil {
 		return nil, err
 	}
-
-	// Check that signed integer parameters are positive.
-	if amt < 0 {
-		return nil, ErrNeedPositiveAmount
-	}
-
-	// Mock up map of address and amount pairs.
-	pairs := map[string]btcutil.Amount{
-		cmd.Address: amt,
-	}
-
-	// Use specified scope, if provided.
-	scope, err := lookupKeyScope(cmd.AddressType)
+	feeSatPerKb, err := feeRateParam(cmd.FeeRate)
 	if err != nil {
 		return nil, err
 	}
 
-	// sendtoaddress always spends from the default account, this matches bitcoind
-	return sendPairs(w, pairs, scope, waddrmgr.DefaultAccountNum, 1,
-		txrules.DefaultRelayFeePerKb)
-}
-
-// setTxFee sets the transaction fee per kilobyte added to transactions.
-func setTxFee(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
-	cmd := icmd.(*btcjson.SetTxFeeCmd)
-
-	// Check that amount is not negative.
-	if cmd.Amount < 0 {
-		return nil, ErrNeedPositiveAmount
+	tx, err := w.RecoverVault(cmd.Name, wif.PrivKey, addr, feeSatPerKb)
+	if err != nil {
+		return nil, vaultError(err)
 	}
-
-	// A boolean true result is returned upon success.
-	return true, nil
+	return tx.TxHash().String(), nil
 }
 
 // signMessage signs the given message with the private key for the given
@@ -1840,6 +4647,7 @@ func signMessage(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
+	defer zero.BigInt(privKey.D)
 
 	var buf bytes.Buffer
 	_ = wire.WriteVarString(&buf, 0, "Bitcoin Signed Message:\n")
@@ -1858,6 +4666,10 @@ func signMessage(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 func signRawTransaction(icmd interface{}, w *wallet.Wallet, chainClient *chain.RPCClient) (interface{}, error) {
 	cmd := icmd.(*btcjson.SignRawTransactionCmd)
 
+	if err := checkWithdrawalPolicy("signrawtransaction", w); err != nil {
+		return nil, err
+	}
+
 	serializedTx, err := decodeHexStr(cmd.RawTx)
 	if err != nil {
 		return nil, err
@@ -2038,19 +4850,31 @@ func signRawTransaction(icmd interface{}, w *wallet.Wallet, chainClient *chain.R
 func validateAddress(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	cmd := icmd.(*btcjson.ValidateAddressCmd)
 
-	result := btcjson.ValidateAddressWalletResult{}
+	result := walletjson.ValidateAddressResult{}
 	addr, err := decodeAddress(cmd.Address, w.ChainParams())
 	if err != nil {
-		// Use result zero value (IsValid=false).
+		// An invalid address is not an error of the request, so only
+		// the reason is set with IsValid=false.
+		result.Error, result.Network = invalidAddressReason(
+			cmd.Address, w.ChainParams())
 		return result, nil
 	}
 
 	// We could put whether or not the address is a script here,
 	// by checking the type of "addr", however, the reference
 	// implementation only puts that information if the script is
-	// "ismine", and we follow that behaviour.
+	// "ismine", and we follow that behaviour.  The script type tells
+	// the same for any address.
 	result.Address = addr.EncodeAddress()
 	result.IsValid = true
+	result.ScriptType = addressScriptType(addr)
+	result.Network = w.ChainParams().Name
+	if wa, ok := addr.(witnessAddress); ok {
+		version := int(wa.WitnessVersion())
+		result.IsWitness = true
+		result.WitnessVersion = &version
+		result.WitnessProgram = hex.EncodeToString(wa.WitnessProgram())
+	}
 
 	ainfo, err := w.AddressInfo(addr)
 	if err != nil {
@@ -2159,6 +4983,53 @@ func verifyMessage(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	}
 }
 
+// walletGetData handles a walletgetdata request by returning the value of a
+// key of the application data, or every key and value of a namespace when no
+// key is given.
+func walletGetData(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.WalletGetDataCmd)
+
+	if cmd.Key != nil {
+		value, err := w.Data(cmd.Namespace, *cmd.Key)
+		if errors.Is(err, wallet.ErrDataNotFound) {
+			return nil, &btcjson.RPCError{
+				Code:    btcjson.ErrRPCInvalidParameter,
+				Message: "No data for key " + *cmd.Key,
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		return string(value), nil
+	}
+
+	values, err := w.AllData(cmd.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]string, len(values))
+	for k, v := range values {
+		result[k] = string(v)
+	}
+	return result, nil
+}
+
+// walletSetData handles a walletsetdata request by setting or removing a key
+// of the application data.
+func walletSetData(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.WalletSetDataCmd)
+
+	var value []byte
+	if cmd.Value != nil {
+		value = []byte(*cmd.Value)
+	}
+	err := w.SetData(cmd.Namespace, map[string][]byte{cmd.Key: value})
+	if err != nil {
+		return nil, InvalidParameterError{err}
+	}
+	return nil, nil
+}
+
 // walletIsLocked handles the walletislocked extension request by
 // returning the current lock state (false for unlocked, true for locked)
 // of an account.
@@ -2180,11 +5051,13 @@ func walletPassphrase(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	cmd := icmd.(*btcjson.WalletPassphraseCmd)
 
 	timeout := time.Second * time.Duration(cmd.Timeout)
-	var unlockAfter <-chan time.Time
+	var until time.Time
 	if timeout != 0 {
-		unlockAfter = time.After(timeout)
+		until = time.Now().Add(timeout)
 	}
-	err := w.Unlock([]byte(cmd.Passphrase), unlockAfter)
+	passphrase := []byte(cmd.Passphrase)
+	defer zero.Bytes(passphrase)
+	err := w.UnlockUntil(passphrase, until)
 	return nil, err
 }
 
@@ -2198,8 +5071,31 @@ func walletPassphrase(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 func walletPassphraseChange(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	cmd := icmd.(*btcjson.WalletPassphraseChangeCmd)
 
-	err := w.ChangePassphrase([]byte(cmd.OldPassphrase),
-		[]byte(cmd.NewPassphrase))
+	oldPassphrase := []byte(cmd.OldPassphrase)
+	newPassphrase := []byte(cmd.NewPassphrase)
+	defer zero.Bytes(oldPassphrase)
+	defer zero.Bytes(newPassphrase)
+	err := w.ChangePassphrase(oldPassphrase, newPassphrase)
+	if waddrmgr.IsError(err, waddrmgr.ErrWrongPassphrase) {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCWalletPassphraseIncorrect,
+			Message: "Incorrect passphrase",
+		}
+	}
+	return nil, err
+}
+
+// changePublicPassphrase responds to the changepublicpassphrase request by
+// re-encrypting the public data of the wallet under the new public
+// passphrase.
+func changePublicPassphrase(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.ChangePublicPassphraseCmd)
+
+	oldPassphrase := []byte(cmd.OldPassphrase)
+	newPassphrase := []byte(cmd.NewPassphrase)
+	defer zero.Bytes(oldPassphrase)
+	defer zero.Bytes(newPassphrase)
+	err := w.ChangePublicPassphrase(oldPassphrase, newPassphrase)
 	if waddrmgr.IsError(err, waddrmgr.ErrWrongPassphrase) {
 		return nil, &btcjson.RPCError{
 			Code:    btcjson.ErrRPCWalletPassphraseIncorrect,
@@ -2209,6 +5105,25 @@ func walletPassphraseChange(icmd interface{}, w *wallet.Wallet) (interface{}, er
 	return nil, err
 }
 
+// cloneWallet handles a clonewallet request by writing a watch-only copy of
+// the wallet database to the destination file.
+func cloneWallet(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.CloneWalletCmd)
+
+	if cmd.Destination == "" {
+		return nil, InvalidParameterError{
+			errors.New("destination must not be empty"),
+		}
+	}
+	if err := w.Clone(cmd.Destination); err != nil {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCWallet,
+			Message: err.Error(),
+		}
+	}
+	return nil, nil
+}
+
 // decodeHexStr decodes the hex encoding of a string, possibly prepending a
 // leading '0' character if there is an odd number of bytes in the hex string.
 // This is to prevent an error for an invalid hex string when using an odd
@@ -0,0 +1,249 @@
+package legacyrpc
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lbryio/lbcd/btcjson"
+	"github.com/lbryio/lbcd/chaincfg"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcutil/hdkeychain"
+	"github.com/lbryio/lbcwallet/rpc/walletjson"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/wallet"
+	"github.com/lbryio/lbcwallet/walletdb"
+	_ "github.com/lbryio/lbcwallet/walletdb/bdb"
+)
+
+// testPassphrase is the private passphrase of the wallets of testWallet.
+const testPassphrase = "passphrase"
+
+// testWallet returns a new locked wallet, which is unloaded when the test
+// ends.
+func testWallet(t *testing.T) *wallet.Wallet {
+	t.Helper()
+
+	seed, err := hdkeychain.GenerateSeed(hdkeychain.MinSeedBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loader := wallet.NewLoader(
+		&chaincfg.RegressionNetParams, t.TempDir(), true,
+		10*time.Second, 250,
+	)
+	w, err := loader.CreateNewWallet(
+		[]byte(testPassphrase), seed, time.Now(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := loader.UnloadWallet(); err != nil {
+			t.Error(err)
+		}
+	})
+	return w
+}
+
+// rpcErrorCode returns the JSON-RPC error code a handler error is returned
+// to clients with.
+func rpcErrorCode(err error) btcjson.RPCErrorCode {
+	return jsonError(err).Code
+}
+
+// TestSetSpendPolicyLocked ensures the limits of the spend policy can't be
+// raised or cleared without the wallet passphrase while the wallet is locked.
+func TestSetSpendPolicyLocked(t *testing.T) {
+	w := testWallet(t)
+	if !w.Locked() {
+		t.Fatal("wallet is unlocked")
+	}
+
+	amount := func(v float64) *float64 { return &v }
+	passphrase := func(s string) *string { return &s }
+
+	_, err := setSpendPolicy(walletjson.NewSetSpendPolicyCmd(
+		amount(1), amount(2), nil, nil, nil,
+	), w)
+	if err != nil {
+		t.Fatalf("unable to set the limits: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		cmd        *walletjson.SetSpendPolicyCmd
+		code       btcjson.RPCErrorCode
+		maxTx      float64
+		dailyLimit float64
+	}{
+		{
+			name: "lower limits",
+			cmd: walletjson.NewSetSpendPolicyCmd(
+				amount(0.5), amount(1), nil, nil, nil,
+			),
+			maxTx:      0.5,
+			dailyLimit: 1,
+		},
+		{
+			name: "raise limit",
+			cmd: walletjson.NewSetSpendPolicyCmd(
+				amount(10), nil, nil, nil, nil,
+			),
+			code:       btcjson.ErrRPCWalletUnlockNeeded,
+			maxTx:      0.5,
+			dailyLimit: 1,
+		},
+		{
+			name: "clear limits",
+			cmd: walletjson.NewSetSpendPolicyCmd(
+				amount(0), amount(0), nil, nil, nil,
+			),
+			code:       btcjson.ErrRPCWalletUnlockNeeded,
+			maxTx:      0.5,
+			dailyLimit: 1,
+		},
+		{
+			name: "clear limits with wrong passphrase",
+			cmd: walletjson.NewSetSpendPolicyCmd(
+				amount(0), amount(0), nil, nil,
+				passphrase("wrong"),
+			),
+			code:       btcjson.ErrRPCWalletPassphraseIncorrect,
+			maxTx:      0.5,
+			dailyLimit: 1,
+		},
+		{
+			name: "clear limits with passphrase",
+			cmd: walletjson.NewSetSpendPolicyCmd(
+				amount(0), amount(0), nil, nil,
+				passphrase(testPassphrase),
+			),
+		},
+	}
+
+	for _, test := range tests {
+		_, err := setSpendPolicy(test.cmd, w)
+		switch {
+		case test.code == 0 && err != nil:
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		case test.code != 0 && err == nil:
+			t.Fatalf("%s: policy was loosened", test.name)
+		case test.code != 0 && rpcErrorCode(err) != test.code:
+			t.Fatalf("%s: got error %v, want code %d", test.name,
+				err, test.code)
+		}
+
+		policy, _, err := w.SpendPolicy()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if policy.MaxTxAmount.ToBTC() != test.maxTx ||
+			policy.DailyLimit.ToBTC() != test.dailyLimit {
+
+			t.Fatalf("%s: got limits %v and %v, want %v and %v",
+				test.name, policy.MaxTxAmount,
+				policy.DailyLimit, test.maxTx, test.dailyLimit)
+		}
+	}
+	if !w.Locked() {
+		t.Fatal("setspendpolicy unlocked the wallet")
+	}
+}
+
+// TestKeyExportWithdrawalPolicy ensures the private keys of the wallet can't
+// be exported while a withdrawal policy is set, since they could sign sends
+// which were never approved.
+func TestKeyExportWithdrawalPolicy(t *testing.T) {
+	w := testWallet(t)
+	if err := w.Unlock([]byte(testPassphrase), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Derive the address with the address manager, since NewAddress
+	// requires a chain server to be notified of it.
+	scopedMgr, err := w.Manager.FetchScopedKeyManager(
+		waddrmgr.KeyScopeBIP0044,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var addrs []waddrmgr.ManagedAddress
+	db := w.Database()
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket([]byte("waddrmgr"))
+		addrs, err = scopedMgr.NextAddresses(ns, 0, 0, 1)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := addrs[0].Address()
+	dir := t.TempDir()
+
+	tests := []struct {
+		method  string
+		cmd     interface{}
+		handler requestHandler
+
+		// code is the error code of the request without a withdrawal
+		// policy, or zero if it succeeds.
+		code btcjson.RPCErrorCode
+	}{
+		{
+			method: "dumpprivkey",
+			cmd: btcjson.NewDumpPrivKeyCmd(
+				addr.EncodeAddress(),
+			),
+			handler: dumpPrivKey,
+		},
+		{
+			method: "dumpwallet",
+			cmd: btcjson.NewDumpWalletCmd(
+				filepath.Join(dir, "dump.json"),
+			),
+			handler: dumpWallet,
+		},
+		{
+			method:  "dumpimportedaccount",
+			cmd:     walletjson.NewDumpImportedAccountCmd("cold"),
+			handler: dumpImportedAccount,
+			code:    btcjson.ErrRPCWalletInvalidAccountName,
+		},
+		{
+			method:  "exportseedshares",
+			cmd:     walletjson.NewExportSeedSharesCmd(2, 3),
+			handler: exportSeedShares,
+		},
+	}
+
+	for _, test := range tests {
+		w.SetWithdrawalPolicy(wallet.WithdrawalPolicy{
+			Threshold: btcutil.SatoshiPerBitcoin,
+			Approvals: 2,
+		})
+		result, err := test.handler(test.cmd, w)
+		if err == nil {
+			t.Fatalf("%s: keys exported with a withdrawal "+
+				"policy: %v", test.method, result)
+		}
+		if rpcErrorCode(err) != btcjson.ErrRPCWallet ||
+			!strings.Contains(err.Error(), "withdrawal policy") {
+
+			t.Fatalf("%s: unexpected error: %v", test.method, err)
+		}
+
+		w.SetWithdrawalPolicy(wallet.WithdrawalPolicy{})
+		_, err = test.handler(test.cmd, w)
+		switch {
+		case test.code == 0 && err != nil:
+			t.Fatalf("%s: unable to export keys without a "+
+				"withdrawal policy: %v", test.method, err)
+		case test.code != 0 && rpcErrorCode(err) != test.code:
+			t.Fatalf("%s: got error %v without a withdrawal "+
+				"policy, want code %d", test.method, err,
+				test.code)
+		}
+	}
+}
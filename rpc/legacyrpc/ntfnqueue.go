@@ -0,0 +1,93 @@
+package legacyrpc
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/btcsuite/websocket"
+)
+
+// DefaultNotificationQueueSize is the default number of notifications queued
+// for a websocket client, beyond which the client is disconnected.
+const DefaultNotificationQueueSize = 1024
+
+// NotificationStats counts the notifications sent to the websocket clients of
+// the server since it started.
+type NotificationStats struct {
+	// Delivered is the number of notifications written to the clients.
+	Delivered uint64
+
+	// Dropped is the number of notifications which were not written to
+	// their client, as it fell behind or disconnected with them queued.
+	Dropped uint64
+
+	// SlowClients is the number of clients disconnected for falling
+	// behind.
+	SlowClients uint64
+}
+
+// ntfnCounters are the counters of NotificationStats, which are accessed
+// atomically.
+type ntfnCounters struct {
+	delivered   uint64
+	dropped     uint64
+	slowClients uint64
+}
+
+// NotificationStats returns the counts of the notifications sent to websocket
+// clients.
+func (s *Server) NotificationStats() NotificationStats {
+	return NotificationStats{
+		Delivered:   atomic.LoadUint64(&s.ntfnStats.delivered),
+		Dropped:     atomic.LoadUint64(&s.ntfnStats.dropped),
+		SlowClients: atomic.LoadUint64(&s.ntfnStats.slowClients),
+	}
+}
+
+// notify queues a notification to the client without blocking.  When the
+// queue is full, the notification is dropped and the client disconnected, as
+// it would otherwise miss notifications without knowing it.
+func (c *websocketClient) notify(b []byte) {
+	select {
+	case <-c.quit:
+		c.drop(1)
+		return
+	default:
+	}
+	select {
+	case c.notifications <- b:
+	default:
+		c.drop(1)
+		c.lagOnce.Do(func() {
+			atomic.AddUint64(&c.stats.slowClients, 1)
+			close(c.lagging)
+		})
+	}
+}
+
+// drop counts n notifications dropped for the client.
+func (c *websocketClient) drop(n int) {
+	atomic.AddUint64(&c.dropped, uint64(n))
+	atomic.AddUint64(&c.stats.dropped, uint64(n))
+}
+
+// deliver counts a notification written to the client.
+func (c *websocketClient) deliver() {
+	atomic.AddUint64(&c.delivered, 1)
+	atomic.AddUint64(&c.stats.delivered, 1)
+}
+
+// disconnectLagging closes the connection of a client which fell behind its
+// notifications, with a close message giving the reason.
+func (c *websocketClient) disconnectLagging(deadline time.Duration) {
+	reason := fmt.Sprintf("more than %d notifications queued",
+		cap(c.notifications))
+	log.Warnf("Disconnecting websocket client %s, which fell behind "+
+		"with %s", c.remoteAddr, reason)
+	msg := websocket.FormatCloseMessage(websocket.ClosePolicyViolation,
+		"client too slow: "+reason)
+	_ = c.conn.WriteControl(websocket.CloseMessage, msg,
+		time.Now().Add(deadline))
+	c.conn.Close()
+}
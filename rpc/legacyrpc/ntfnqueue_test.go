@@ -0,0 +1,78 @@
+package legacyrpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/websocket"
+)
+
+// TestNotificationQueue ensures a websocket client falling further behind
+// than its notification queue is disconnected with the reason, and that the
+// notifications are counted.
+func TestNotificationQueue(t *testing.T) {
+	s := &Server{quit: make(chan struct{})}
+	done := make(chan *websocketClient, 1)
+	upgrader := websocket.Upgrader{}
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			wsc := newWebsocketClient(conn, true, r.RemoteAddr, 2,
+				&s.ntfnStats)
+
+			// The client falls behind before anything is sent.
+			for i := 0; i < 3; i++ {
+				wsc.notify([]byte(`{"method":"test"}`))
+			}
+			s.wg.Add(1)
+			s.websocketClientSend(wsc)
+			done <- wsc
+		}))
+	defer ts.Close()
+
+	url := "ws" + strings.TrimPrefix(ts.URL, "http")
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	var read int
+	for {
+		_, _, err := conn.ReadMessage()
+		if err != nil {
+			if !strings.Contains(err.Error(), "client too slow") {
+				t.Fatalf("disconnected without the reason: %v",
+					err)
+			}
+			break
+		}
+		read++
+	}
+	wsc := <-done
+
+	stats := s.NotificationStats()
+	if stats.SlowClients != 1 {
+		t.Fatalf("%d slow clients", stats.SlowClients)
+	}
+	if stats.Delivered != uint64(read) || stats.Delivered+stats.Dropped != 3 {
+		t.Fatalf("read %d notifications, %d delivered and %d dropped",
+			read, stats.Delivered, stats.Dropped)
+	}
+	if wsc.delivered != stats.Delivered || wsc.dropped != stats.Dropped {
+		t.Fatalf("client counted %d delivered and %d dropped",
+			wsc.delivered, wsc.dropped)
+	}
+
+	// Notifications to a disconnected client are dropped.
+	wsc.notify([]byte(`{"method":"test"}`))
+	if s.NotificationStats().Dropped != stats.Dropped+1 {
+		t.Fatal("notification to a disconnected client not dropped")
+	}
+}
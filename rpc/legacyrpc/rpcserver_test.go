@@ -5,10 +5,22 @@
 package legacyrpc
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/lbryio/lbcd/btcjson"
+	"github.com/lbryio/lbcwallet/rpc/walletjson"
+	"github.com/lbryio/lbcwallet/wallet"
 )
 
 func TestThrottle(t *testing.T) {
@@ -48,3 +60,435 @@ func TestThrottle(t *testing.T) {
 		t.Fatalf("status codes: want: %v, got: %v", want, got)
 	}
 }
+
+func TestAuthLimiter(t *testing.T) {
+	const (
+		threshold = 3
+		ban       = time.Minute
+		addr      = "10.0.0.1:1234"
+	)
+	now := time.Unix(1e9, 0)
+	l := newAuthLimiter(threshold, ban, nil)
+	l.now = func() time.Time { return now }
+
+	// Every failure below the threshold requires an exponentially
+	// increasing wait before the next attempt, regardless of the port.
+	for i := 0; i < threshold-1; i++ {
+		l.failure(addr)
+		if l.allowed("10.0.0.1:5678") {
+			t.Fatalf("failure %d: attempt allowed during backoff", i)
+		}
+		now = now.Add(authBackoffBase << uint(i))
+		if !l.allowed(addr) {
+			t.Fatalf("failure %d: attempt denied after backoff", i)
+		}
+	}
+	if !l.allowed("10.0.0.2:1234") {
+		t.Fatalf("unrelated host denied")
+	}
+
+	// Reaching the threshold bans the host, and repeated bans double.
+	l.failure(addr)
+	now = now.Add(ban - time.Second)
+	if l.allowed(addr) {
+		t.Fatalf("banned host allowed")
+	}
+	now = now.Add(time.Second)
+	if !l.allowed(addr) {
+		t.Fatalf("host still banned after ban duration")
+	}
+	for i := 0; i < threshold; i++ {
+		l.failure(addr)
+	}
+	now = now.Add(ban)
+	if l.allowed(addr) {
+		t.Fatalf("repeated ban did not increase")
+	}
+	now = now.Add(ban)
+	if !l.allowed(addr) {
+		t.Fatalf("host still banned after doubled ban duration")
+	}
+
+	// A successful login resets the consecutive failures.
+	l.failure(addr)
+	l.success(addr)
+	if !l.allowed(addr) {
+		t.Fatalf("host denied after successful login")
+	}
+
+	// A zero threshold disables the limiter.
+	l = newAuthLimiter(0, ban, nil)
+	for i := 0; i < 10; i++ {
+		l.failure(addr)
+	}
+	if !l.allowed(addr) {
+		t.Fatalf("disabled limiter denied host")
+	}
+}
+
+// TestAuthLimiterExempt ensures only the hosts of the exempt networks, none by
+// default, escape the bans of single hosts, and that the failures of every
+// host count against the global limit.
+func TestAuthLimiterExempt(t *testing.T) {
+	exempt := []*net.IPNet{{
+		IP:   net.IPv4(127, 0, 0, 1),
+		Mask: net.CIDRMask(32, 32),
+	}}
+	tests := []struct {
+		name   string
+		exempt []*net.IPNet
+		addr   string
+		banned bool
+	}{
+		{"loopback by default", nil, "127.0.0.1:1234", true},
+		{"IPv6 loopback by default", nil, "[::1]:1234", true},
+		{"exempt loopback", exempt, "127.0.0.1:1234", false},
+		{"other loopback", exempt, "127.0.0.2:1234", true},
+		{"remote host", exempt, "10.0.0.1:1234", true},
+	}
+	for _, test := range tests {
+		l := newAuthLimiter(1, time.Hour, test.exempt)
+		l.failure(test.addr)
+		if l.allowed(test.addr) == test.banned {
+			t.Errorf("%s: allowed after failure is %v, want %v",
+				test.name, !test.banned, test.banned)
+		}
+	}
+
+	// The failures of an exempt host, such as the clients of an onion
+	// service, are limited together with those of all other hosts.
+	now := time.Unix(1e9, 0)
+	l := newAuthLimiter(1, time.Hour, exempt)
+	l.now = func() time.Time { return now }
+	for i := 0; i < authGlobalFailures-1; i++ {
+		l.failure("127.0.0.1:1234")
+		if !l.allowed("127.0.0.1:1234") {
+			t.Fatalf("failure %d: exempt host denied", i)
+		}
+	}
+	now = now.Add(authGlobalWindow / 2)
+	l.failure("10.0.0.1:1234")
+	for _, addr := range []string{"127.0.0.1:1234", "10.0.0.2:1234"} {
+		if l.allowed(addr) {
+			t.Fatalf("%s allowed after the global limit", addr)
+		}
+	}
+	now = now.Add(authGlobalWindow / 2)
+	if !l.allowed("127.0.0.1:1234") {
+		t.Fatalf("exempt host denied after the global window")
+	}
+	if l.allowed("10.0.0.1:1234") {
+		t.Fatalf("banned host allowed after the global window")
+	}
+
+	// Reloading the limits applies the new exempt networks.
+	l.setLimits(1, time.Hour, nil)
+	l.failure("127.0.0.1:1234")
+	if l.allowed("127.0.0.1:1234") {
+		t.Fatalf("host allowed after its exemption was removed")
+	}
+}
+
+func TestDrain(t *testing.T) {
+	var s Server
+
+	if !s.startRequest() {
+		t.Fatal("request rejected before draining")
+	}
+	if s.Drain(10 * time.Millisecond) {
+		t.Fatal("drain completed with a request in flight")
+	}
+	if s.startRequest() {
+		t.Fatal("request accepted while draining")
+	}
+
+	s.inflight.Done()
+	if !s.Drain(time.Second) {
+		t.Fatal("drain timed out without requests in flight")
+	}
+}
+
+// TestRequestContext checks that the contexts of requests are canceled by the
+// disconnect of their client or the stop of the server, and that the errors
+// of canceled requests are reported as such.
+func TestRequestContext(t *testing.T) {
+	s := Server{quit: make(chan struct{}), canceled: make(chan struct{})}
+	done := func(ctx context.Context) bool {
+		select {
+		case <-ctx.Done():
+			return true
+		case <-time.After(time.Second):
+			return false
+		}
+	}
+
+	disconnected := make(chan struct{})
+	ws, cancel := s.requestContext(context.Background(), disconnected)
+	defer cancel()
+	post, cancel := s.requestContext(context.Background(), nil)
+	defer cancel()
+
+	close(disconnected)
+	if !done(ws) {
+		t.Fatal("request not canceled by the disconnect of its client")
+	}
+	if post.Err() != nil {
+		t.Fatal("request canceled by the disconnect of another client")
+	}
+	s.Stop()
+	if !done(post) {
+		t.Fatal("request not canceled by the stop of the server")
+	}
+
+	err := fmt.Errorf("rescanblockchain: %w", post.Err())
+	if jsonErr := jsonError(err); *jsonErr != ErrRequestCanceled {
+		t.Fatalf("got error %v, want canceled request", jsonErr)
+	}
+}
+
+func TestNamedWalletRouting(t *testing.T) {
+	for path, want := range map[string]string{
+		"/":               "",
+		"/ws":             "",
+		"/wallet/":        "",
+		"/wallet/savings": "savings",
+		"/wallets/hot":    "",
+	} {
+		if got := requestWalletName(path); got != want {
+			t.Errorf("path %q: got wallet %q, want %q", path, got,
+				want)
+		}
+	}
+
+	s := Server{wallets: make(map[string]*namedWallet)}
+	req := btcjson.Request{Method: "getbalance"}
+	_, jsonErr := s.handlerClosure(context.Background(), &req, "savings")()
+	if jsonErr == nil || jsonErr.Code != btcjson.ErrRPCWalletNotFound {
+		t.Fatalf("got error %v, want wallet not found", jsonErr)
+	}
+	_, jsonErr = s.handlerClosure(context.Background(), &req, "")()
+	if jsonErr == nil || jsonErr.Code != ErrUnloadedWallet.Code {
+		t.Fatalf("got error %v, want unloaded wallet", jsonErr)
+	}
+}
+
+// testWalletManager records the wallets it is asked to load and unload.
+type testWalletManager struct {
+	loaded   []string
+	unloaded []string
+}
+
+func (m *testWalletManager) LoadWallet(name string) error {
+	if name == "missing" {
+		return ErrWalletNotFound
+	}
+	m.loaded = append(m.loaded, name)
+	return nil
+}
+
+func (m *testWalletManager) UnloadWallet(name string) error {
+	m.unloaded = append(m.unloaded, name)
+	return nil
+}
+
+func TestWalletManagement(t *testing.T) {
+	s := Server{wallets: map[string]*namedWallet{"hot": {}}}
+	request := func(walletName, method string, params ...interface{}) (
+		interface{}, *btcjson.RPCError) {
+
+		req, err := btcjson.NewRequest(btcjson.RpcVersion1, 1, method,
+			params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s.walletManagementRequest(req, walletName)
+	}
+
+	res, jsonErr := request("", "listwallets")
+	if jsonErr != nil || !reflect.DeepEqual(res, []string{"hot"}) {
+		t.Fatalf("listwallets: got %v, %v", res, jsonErr)
+	}
+
+	_, jsonErr = request("", "loadwallet", "cold")
+	if jsonErr == nil || jsonErr.Code != ErrWalletManagementDisabled.Code {
+		t.Fatalf("got error %v without a wallet manager", jsonErr)
+	}
+
+	m := new(testWalletManager)
+	s.SetWalletManager(m)
+	res, jsonErr = request("", "loadwallet", "cold")
+	if jsonErr != nil {
+		t.Fatalf("loadwallet: %v", jsonErr)
+	}
+	if r, ok := res.(*btcjson.LoadWalletResult); !ok || r.Name != "cold" {
+		t.Fatalf("loadwallet: got result %v", res)
+	}
+	_, jsonErr = request("", "loadwallet", "missing")
+	if jsonErr == nil || jsonErr.Code != btcjson.ErrRPCWalletNotFound {
+		t.Fatalf("got error %v for a missing wallet", jsonErr)
+	}
+
+	// The wallet to unload is named by the parameter or the request path,
+	// and the default wallet can not be unloaded.
+	if _, jsonErr = request("", "unloadwallet", "cold"); jsonErr != nil {
+		t.Fatalf("unloadwallet: %v", jsonErr)
+	}
+	if _, jsonErr = request("hot", "unloadwallet"); jsonErr != nil {
+		t.Fatalf("unloadwallet: %v", jsonErr)
+	}
+	_, jsonErr = request("hot", "unloadwallet", "cold")
+	if jsonErr == nil || jsonErr.Code != btcjson.ErrRPCInvalidParameter {
+		t.Fatalf("got error %v for conflicting wallets", jsonErr)
+	}
+	_, jsonErr = request("", "unloadwallet")
+	if jsonErr == nil || jsonErr.Code != btcjson.ErrRPCWalletNotSpecified {
+		t.Fatalf("got error %v for the default wallet", jsonErr)
+	}
+
+	if !reflect.DeepEqual(m.loaded, []string{"cold"}) ||
+		!reflect.DeepEqual(m.unloaded, []string{"cold", "hot"}) {
+
+		t.Fatalf("loaded %v and unloaded %v", m.loaded, m.unloaded)
+	}
+}
+
+func TestCollectDebugInfo(t *testing.T) {
+	s := Server{wallets: map[string]*namedWallet{"hot": {}}}
+	_, jsonErr := s.collectDebugInfo()
+	if jsonErr == nil || jsonErr.Code != btcjson.ErrRPCMisc {
+		t.Fatalf("got error %v without a debug info writer", jsonErr)
+	}
+
+	var names []string
+	s.debugInfo = func(w io.Writer, wallets map[string]*wallet.Wallet) error {
+		for name := range wallets {
+			names = append(names, name)
+		}
+		_, err := w.Write([]byte("bundle"))
+		return err
+	}
+	res, jsonErr := s.collectDebugInfo()
+	if jsonErr != nil {
+		t.Fatalf("collectdebuginfo: %v", jsonErr)
+	}
+	if want := base64.StdEncoding.EncodeToString([]byte("bundle")); res != want {
+		t.Fatalf("got bundle %v, want %v", res, want)
+	}
+	if !reflect.DeepEqual(names, []string{"hot"}) {
+		t.Fatalf("got wallets %v, want the named wallet", names)
+	}
+}
+
+func TestRegtestHarnessGating(t *testing.T) {
+	s := Server{wallets: make(map[string]*namedWallet)}
+	for method := range harnessMethods {
+		req := &btcjson.Request{Jsonrpc: "1.0", Method: method}
+		_, jsonErr := s.handlerClosure(context.Background(), req, "")()
+		if jsonErr == nil || jsonErr.Message != ErrHarnessDisabled.Message {
+			t.Fatalf("%s: got error %v without the regtest harness",
+				method, jsonErr)
+		}
+	}
+
+	// Once enabled, the methods reach their handlers, which require a
+	// wallet.
+	s.regtestHarness = true
+	req := &btcjson.Request{Jsonrpc: "1.0", Method: "fastforward"}
+	_, jsonErr := s.handlerClosure(context.Background(), req, "")()
+	if jsonErr == nil || jsonErr.Message != ErrUnloadedWallet.Message {
+		t.Fatalf("got error %v with the regtest harness", jsonErr)
+	}
+}
+
+func TestMiningAccount(t *testing.T) {
+	s := Server{wallets: make(map[string]*namedWallet)}
+	req, err := btcjson.NewRequest(btcjson.RpcVersion1, 1, "generate",
+		[]interface{}{101})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a mining account, generate is passed through to the chain
+	// server.
+	_, jsonErr := s.handlerClosure(context.Background(), req, "")()
+	if jsonErr == nil || jsonErr.Message != "Chain RPC is inactive" {
+		t.Fatalf("got error %v without a mining account", jsonErr)
+	}
+
+	s.miningAccount = "mining"
+	r := s.miningRequest(req)
+	cmd, err := btcjson.UnmarshalCmd(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := walletjson.NewGenerateToWalletCmd(101, btcjson.String("mining"))
+	if !reflect.DeepEqual(cmd, want) {
+		t.Fatalf("got command %#v, want %#v", cmd, want)
+	}
+	if req.Method != "generate" || len(req.Params) != 1 {
+		t.Fatal("the generate request was modified")
+	}
+
+	// The request reaches the wallet handler, although the regtest
+	// harness is disabled.
+	_, jsonErr = s.handlerClosure(context.Background(), req, "")()
+	if jsonErr == nil || jsonErr.Message != ErrUnloadedWallet.Message {
+		t.Fatalf("got error %v with a mining account", jsonErr)
+	}
+}
+
+func TestApproverAuth(t *testing.T) {
+	approverAuthSha := sha256.Sum256(httpBasicAuth("alice", "secret"))
+	s := Server{
+		authsha:  sha256.Sum256(httpBasicAuth("user", "pass")),
+		username: "user",
+		approvers: []approverAuth{
+			{username: "alice", authsha: approverAuthSha},
+		},
+		maxRequestSize:      DefaultMaxRequestSize,
+		requestShutdownChan: make(chan struct{}, 1),
+	}
+	alice := rpcUser{name: "alice", approver: true}
+	for _, test := range []struct {
+		username, password string
+		want               rpcUser
+		ok                 bool
+	}{
+		{"user", "pass", rpcUser{name: "user"}, true},
+		{"alice", "secret", alice, true},
+		{"alice", "pass", rpcUser{}, false},
+		{"bob", "secret", rpcUser{}, false},
+	} {
+		r := httptest.NewRequest("POST", "/", nil)
+		r.SetBasicAuth(test.username, test.password)
+		user, err := s.checkUserAuth(r)
+		if (err == nil) != test.ok || user != test.want {
+			t.Errorf("%s:%s: got user %v, error %v", test.username,
+				test.password, user, err)
+		}
+	}
+
+	// Approvers may only list and approve withdrawals.
+	r := httptest.NewRequest("POST", "/", strings.NewReader(
+		`{"jsonrpc":"1.0","id":1,"method":"stop","params":[]}`,
+	))
+	r = r.WithContext(withRPCUser(r.Context(), alice))
+	rec := httptest.NewRecorder()
+	s.postClientRPC(rec, r)
+	if !strings.Contains(rec.Body.String(), ErrApproverMethod.Message) {
+		t.Fatalf("got response %s to an approver", rec.Body)
+	}
+	select {
+	case <-s.requestShutdownChan:
+		t.Fatal("an approver stopped the server")
+	default:
+	}
+
+	// Other users may not approve withdrawals.
+	cmd := walletjson.NewApproveWithdrawalCmd(1)
+	ctx := withRPCUser(context.Background(), rpcUser{name: "user"})
+	_, err := approveWithdrawal(ctx, cmd, nil)
+	if err != &ErrNotApprover {
+		t.Fatalf("got error %v approving as the RPC user", err)
+	}
+}
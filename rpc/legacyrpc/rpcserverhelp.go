@@ -7,6 +7,7 @@ func helpDescsEnUS() map[string]string {
 		"addmultisigaddress":      "addmultisigaddress nrequired [\"key\",...] (\"account\")\n\nGenerates and imports a multisig address and redeeming script to the 'imported' account.\n\nArguments:\n1. nrequired (numeric, required)         The number of signatures required to redeem outputs paid to this address.\n2. keys      (array of string, required) Pubkeys and/or pay-to-pubkey-hash addresses to partially control the multisig address.\n3. account   (string, optional)          DEPRECATED -- Unused (all imported addresses belong to the imported account).\n\nResult:\n\"value\" (string) The imported pay-to-script-hash address.\n",
 		"createmultisig":          "createmultisig nrequired [\"key\",...]\n\nGenerate a multisig address and redeem script.\n\nArguments:\n1. nrequired (numeric, required)         The number of signatures required to redeem outputs paid to this address.\n2. keys      (array of string, required) Pubkeys and/or pay-to-pubkey-hash addresses to partially control the multisig address.\n\nResult:\n{\n \"address\": \"value\",      (string) The generated pay-to-script-hash address.\n \"redeemScript\": \"value\", (string) The script required to redeem outputs paid to the multisig address.\n}                         \n",
 		"dumpprivkey":             "dumpprivkey \"address\"\n\nReturns the private key in WIF encoding that controls some wallet address.\n\nArguments:\n1. address (string, required) The address to return a private key for.\n\nResult:\n\"value\" (string) The WIF-encoded private key.\n",
+		"dumpwallet":              "dumpwallet \"filename\"\n\nWrites a portable JSON export of the wallet to a new file, to restore it with importwallet or lbcwallet --create --createfromdump on another machine or lbcwallet version.\nThe export is versioned (format 'lbcwallet-dump', version 1) and holds the network, creation time, birthday and birthday block, the extended private master key, the HD accounts of every key scope with their xpub and number of derived addresses, the imported keys with their imported-key account, the transaction labels, and the channel claims for reference.\nThe export holds the private keys in the clear. The wallet must be unlocked.\n\nArguments:\n1. filename (string, required) The path of the file to write, which must not exist.\n\nResult:\n{\n \"filename\": \"value\", (string) The path of the file written.\n}                     \n",
 		"getaccount":              "getaccount \"address\"\n\nLookup the account name that some wallet address belongs to.\n\nArguments:\n1. address (string, required) The address to query the account for.\n\nResult:\n\"value\" (string) The name of the account that 'address' belongs to.\n",
 		"getaccountaddress":       "getaccountaddress (account=\"default\" addresstype=\"legacy\")\n\nReturns the most recent external payment address for an account that has not been seen publicly.\nA new address is generated for the account if the most recently generated address has been seen on the blockchain or in mempool.\n\nArguments:\n1. account     (string, optional, default=\"default\") The account of the returned address. Defaults to 'default'\n2. addresstype (string, optional, default=\"legacy\")  Address type. Must be one of 'legacy', 'p2sh-segwit', or 'bech32'. Default to 'legacy'.\n\nResult:\n\"value\" (string) The unused address for 'account'.\n",
 		"getaddressesbyaccount":   "getaddressesbyaccount (account=\"default\" addresstype=\"*\")\n\nReturns all addresses controlled by a single account.\n\nArguments:\n1. account     (string, optional, default=\"default\") Account name to fetch addresses for. Defaults to 'default'\n2. addresstype (string, optional, default=\"*\")       Address type filter. Must be one of 'legacy', 'p2sh-segwit', 'bech32', or '*'. Defaults to '*'.\n\nResult:\n[\"value\",...] (array of string) All addresses controlled by 'account' filtered by 'addresstype'.\n",
@@ -15,41 +16,113 @@ func helpDescsEnUS() map[string]string {
 		"getbestblockhash":        "getbestblockhash\n\nReturns the hash of the newest block in the best chain that wallet has finished syncing with.\n\nArguments:\nNone\n\nResult:\n\"value\" (string) The hash of the most recent synced-to block.\n",
 		"getblockcount":           "getblockcount\n\nReturns the blockchain height of the newest block in the best chain that wallet has finished syncing with.\n\nArguments:\nNone\n\nResult:\nn.nnn (numeric) The blockchain height of the most recent synced-to block.\n",
 		"getinfo":                 "getinfo\n\nReturns a JSON object containing various state info.\n\nArguments:\nNone\n\nResult:\n{\n \"version\": n,          (numeric) The version of the server.\n \"protocolversion\": n,  (numeric) The latest supported protocol version.\n \"walletversion\": n,    (numeric) The version of the address manager database.\n \"balance\": n.nnn,      (numeric) The non-staked balance of all accounts calculated with one block confirmation.\n \"blocks\": n,           (numeric) The number of blocks processed.\n \"timeoffset\": n,       (numeric) The time offset.\n \"connections\": n,      (numeric) The number of connected peers.\n \"proxy\": \"value\",      (string)  The proxy used by the server.\n \"difficulty\": n.nnn,   (numeric) The current target difficulty.\n \"testnet\": true|false, (boolean) Whether or not server is using testnet.\n \"keypoololdest\": n,    (numeric) Unset.\n \"keypoolsize\": n,      (numeric) Unset.\n \"unlocked_until\": n,   (numeric) Unset.\n \"paytxfee\": n.nnn,     (numeric) The increment used each time more fee is required for an authored transaction.\n \"relayfee\": n.nnn,     (numeric) The minimum relay fee for non-free transactions in LBC/KB.\n \"errors\": \"value\",     (string)  Any current errors.\n \"staked\": n.nnn,       (numeric) The staked balance of all accounts calculated with one block confirmation.\n}                       \n",
+		"getwalletinfo":           "getwalletinfo\n\nReturns the state of the wallet, its balances and key counts, and the state of its chain server.\n\nArguments:\nNone\n\nResult:\n{\n \"walletversion\": n,                 (numeric)         The version of the address manager database.\n \"balance\": n.nnn,                   (numeric)         The spendable balance of all accounts with one confirmation valued in LBC.\n \"unconfirmed_balance\": n.nnn,       (numeric)         The value of the unconfirmed outputs of all accounts valued in LBC.\n \"immature_balance\": n.nnn,          (numeric)         The value of the immature coinbase outputs of all accounts valued in LBC.\n \"staked\": n.nnn,                    (numeric)         The value of the unspent claims, supports and claim updates of all accounts valued in LBC.\n \"txcount\": n,                       (numeric)         The number of transactions of the wallet, mined and unmined.\n \"keypoolsize\": n,                   (numeric)         The number of external (receiving) keys derived for all accounts.\n \"keypoolsize_hd_internal\": n,       (numeric)         The number of internal (change) keys derived for all accounts.\n \"keypools\": [{                      (array of object) The key counts of each key scope.\n  \"addresstype\": \"value\",            (string)          The address type of the key scope.\n  \"external\": n,                     (numeric)         The number of external (receiving) keys derived for the accounts of the key scope.\n  \"internal\": n,                     (numeric)         The number of internal (change) keys derived for the accounts of the key scope.\n  \"imported\": n,                     (numeric)         The number of keys imported into the accounts of the key scope.\n  \"pooledexternal\": n,               (numeric)         The number of the next unused external keys of the accounts of the key scope derived in advance.\n  \"pooledinternal\": n,               (numeric)         The number of the next unused internal keys of the accounts of the key scope derived in advance.\n },...],                                               \n \"locked\": true|false,               (boolean)         Whether the wallet is locked.\n \"unlocked_until\": n,                (numeric)         The time the unlocked wallet is relocked, in seconds since 1 Jan 1970 GMT, or 0 when locked or unlocked without a timeout.\n \"private_keys_enabled\": true|false, (boolean)         Whether the wallet has private keys, which a watching-only wallet has not.\n \"birthday\": n,                      (numeric)         The birthday of the wallet, in seconds since 1 Jan 1970 GMT.\n \"syncheight\": n,                    (numeric)         The height of the block the wallet is synced to.\n \"syncblockhash\": \"value\",           (string)          The hash of the block the wallet is synced to.\n \"synced\": true|false,               (boolean)         Whether the wallet is synced to its chain server.\n \"backend\": \"value\",                 (string)          The chain server backend, or empty if there is none.\n \"backendconnected\": true|false,     (boolean)         Whether the chain server is connected.\n}                                    \n",
 		"getnewaddress":           "getnewaddress (account=\"default\" addresstype=\"legacy\")\n\nGenerates and returns a new payment address.\n\nArguments:\n1. account     (string, optional, default=\"default\") Account name the new address will belong to. Defaults to 'default'.\n2. addresstype (string, optional, default=\"legacy\")  Address type. Must be one of 'legacy', 'p2sh-segwit', or 'bech32'. Default to 'legacy'.\n\nResult:\n\"value\" (string) The payment address.\n",
 		"getrawchangeaddress":     "getrawchangeaddress (account=\"default\" addresstype=\"legacy\")\n\nGenerates and returns a new internal payment address for use as a change address in raw transactions.\n\nArguments:\n1. account     (string, optional, default=\"default\") Account name the new internal address will belong to. Defaults to 'default'.\n2. addresstype (string, optional, default=\"legacy\")  Address type. Must be one of 'legacy', 'p2sh-segwit', or 'bech32'. Default to 'legacy'.\n\nResult:\n\"value\" (string) The internal payment address.\n",
 		"getreceivedbyaccount":    "getreceivedbyaccount (account=\"default\" minconf=1)\n\nReturns the total amount received by addresses of some account, including spent outputs.\n\nArguments:\n1. account (string, optional, default=\"default\") Account name to query total received amount for. Defaults to 'default'\n2. minconf (numeric, optional, default=1)        Minimum number of block confirmations required before an output's value is included in the total. Defaults to 0\n\nResult:\nn.nnn (numeric) The total received amount valued in LBC.\n",
 		"getreceivedbyaddress":    "getreceivedbyaddress \"address\" (minconf=1)\n\nReturns the total amount received by a single address, including spent outputs.\n\nArguments:\n1. address (string, required)             Payment address which received outputs to include in total.\n2. minconf (numeric, optional, default=1) Minimum number of block confirmations required before an output's value is included in the total. Defaults to 1\n\nResult:\nn.nnn (numeric) The total received amount valued in LBC.\n",
-		"gettransaction":          "gettransaction \"txid\" (includewatchonly=false)\n\nReturns a JSON object with details regarding a transaction relevant to this wallet.\n\nArguments:\n1. txid             (string, required)                 Hash of the transaction to query.\n2. includewatchonly (boolean, optional, default=false) Also consider transactions involving watched addresses.\n\nResult:\n{\n \"amount\": n.nnn,                  (numeric)         The total amount this transaction credits to the wallet, valued in LBC.\n \"fee\": n.nnn,                     (numeric)         The total input value minus the total output value, or 0 if 'txid' is not a sent transaction.\n \"confirmations\": n,               (numeric)         The number of block confirmations of the transaction.\n \"blockhash\": \"value\",             (string)          The hash of the block this transaction is mined in, or the empty string if unmined.\n \"blockindex\": n,                  (numeric)         Unset.\n \"blocktime\": n,                   (numeric)         The Unix time of the block header this transaction is mined in, or 0 if unmined.\n \"txid\": \"value\",                  (string)          The transaction hash.\n \"walletconflicts\": [\"value\",...], (array of string) Unset.\n \"time\": n,                        (numeric)         The earliest Unix time this transaction was known to exist.\n \"timereceived\": n,                (numeric)         The earliest Unix time this transaction was known to exist.\n \"details\": [{                     (array of object) Additional details for each recorded wallet credit and debit.\n  \"account\": \"value\",              (string)          The account pertaining to this transaction.\n  \"address\": \"value\",              (string)          The address an output was paid to, or the empty string if the output is nonstandard or this detail is regarding a transaction input.\n  \"amount\": n.nnn,                 (numeric)         The amount of a received output.\n  \"category\": \"value\",             (string)          The kind of detail: \"send\" for sent transactions, \"immature\" for immature coinbase outputs, \"generate\" for mature coinbase outputs, or \"recv\" for all other received outputs.\n  \"involveswatchonly\": true|false, (boolean)         Unset.\n  \"fee\": n.nnn,                    (numeric)         The included fee for a sent transaction.\n  \"vout\": n,                       (numeric)         The transaction output index.\n },...],                                             \n \"hex\": \"value\",                   (string)          The transaction encoded as a hexadecimal string.\n \"generated\": true|false,          (boolean)         Only present if transaction only input is a coinbase one.\n}                                  \n",
+		"gettransaction":          "gettransaction \"txid\" (includewatchonly=false)\n\nReturns a JSON object with details regarding a transaction relevant to this wallet.\n\nArguments:\n1. txid             (string, required)                 Hash of the transaction to query.\n2. includewatchonly (boolean, optional, default=false) Also consider transactions involving watched addresses.\n\nResult:\n{\n \"amount\": n.nnn,                  (numeric)         The total amount this transaction credits to the wallet, valued in LBC.\n \"fee\": n.nnn,                     (numeric)         The total input value minus the total output value, or 0 if 'txid' is not a sent transaction.\n \"confirmations\": n,               (numeric)         The number of block confirmations of the transaction.\n \"blockhash\": \"value\",             (string)          The hash of the block this transaction is mined in, or the empty string if unmined.\n \"blockindex\": n,                  (numeric)         Unset.\n \"blocktime\": n,                   (numeric)         The Unix time of the block header this transaction is mined in, or 0 if unmined.\n \"txid\": \"value\",                  (string)          The transaction hash.\n \"walletconflicts\": [\"value\",...], (array of string) Unset.\n \"time\": n,                        (numeric)         The earliest Unix time this transaction was known to exist.\n \"timereceived\": n,                (numeric)         The earliest Unix time this transaction was known to exist.\n \"comment\": \"value\",               (string)          The comment the transaction was sent with, if any.\n \"to\": \"value\",                    (string)          The comment naming the recipient the transaction was sent with, if any.\n \"details\": [{                     (array of object) Additional details for each recorded wallet credit and debit.\n  \"account\": \"value\",              (string)          The account pertaining to this transaction.\n  \"address\": \"value\",              (string)          The address an output was paid to, or the empty string if the output is nonstandard or this detail is regarding a transaction input.\n  \"amount\": n.nnn,                 (numeric)         The amount of a received output.\n  \"category\": \"value\",             (string)          The kind of detail: \"send\" for sent transactions, \"immature\" for immature coinbase outputs, \"generate\" for mature coinbase outputs, or \"recv\" for all other received outputs.\n  \"involveswatchonly\": true|false, (boolean)         Unset.\n  \"fee\": n.nnn,                    (numeric)         The included fee for a sent transaction.\n  \"vout\": n,                       (numeric)         The transaction output index.\n },...],                                             \n \"hex\": \"value\",                   (string)          The transaction encoded as a hexadecimal string.\n \"generated\": true|false,          (boolean)         Only present if transaction only input is a coinbase one.\n}                                  \n",
 		"help":                    "help (\"command\")\n\nReturns a list of all commands or help for a specified command.\n\nArguments:\n1. command (string, optional) The command to retrieve help for.\n\nResult (no command provided.):\n\"value\" (string) List of commands.\n\nResult (command specified.):\n\"value\" (string) Help for specified command.\n",
-		"importprivkey":           "importprivkey \"privkey\" (\"label\" rescan=true)\n\nImports a WIF-encoded private key to the 'imported' account.\n\nArguments:\n1. privkey (string, required)                The WIF-encoded private key.\n2. label   (string, optional)                Unused (must be unset or 'imported').\n3. rescan  (boolean, optional, default=true) Rescan the blockchain (since the genesis block) for outputs controlled by the imported key.\n\nResult:\nNothing\n",
-		"keypoolrefill":           "keypoolrefill (newsize=100)\n\nDEPRECATED -- This request does nothing since no keypool is maintained.\n\nArguments:\n1. newsize (numeric, optional, default=100) Unused.\n\nResult:\nNothing\n",
+		"importprivkey":           "importprivkey \"privkey\" (\"label\" rescan=true)\n\nImports a WIF-encoded private key to the 'imported' account, or to a named imported-key account.\n\nArguments:\n1. privkey (string, required)                The WIF-encoded private key.\n2. label   (string, optional)                The imported-key account to add the key to, which must not name an HD account (default 'imported').\n3. rescan  (boolean, optional, default=true) Rescan the blockchain (since the genesis block) for outputs controlled by the imported key.\n\nResult:\nNothing\n",
+		"importwallet":            "importwallet \"filename\"\n\nRestores a file written by dumpwallet into the wallet, which must have the master key of the export and be unlocked, and rescans the blockchain from the birthday block of the export.\nMissing accounts are created, accounts are renamed as in the export and their addresses derived, and the imported keys and transaction labels are added.\nAn export of another wallet is restored by creating a new wallet from it with lbcwallet --create --createfromdump.\n\nArguments:\n1. filename (string, required) The path of the file written by dumpwallet.\n\nResult:\nNothing\n",
+		"keypoolrefill":           "keypoolrefill (newsize=100)\n\nDerives in advance the keys of the next unused external and internal addresses of every account, so that new addresses are handed out without deriving keys.\n\nArguments:\n1. newsize (numeric, optional, default=100) The number of keys of each branch of the accounts to derive in advance (default=100).\n\nResult:\nNothing\n",
 		"listaccounts":            "listaccounts (minconf=1 addresstype=\"*\")\n\nReturns a JSON object of all accounts and their balances.\n\nArguments:\n1. minconf     (numeric, optional, default=1)  Minimum number of block confirmations required before an unspent output's value is included in the balance.\n2. addresstype (string, optional, default=\"*\") Address type filter. Must be one of 'legacy', 'p2sh-segwit', 'bech32', or '*'. Defaults to '*'.\n\nResult:\n{\n \"Account name\": Total balance and each scope respectively, valued in LBC., (object) JSON object with account names as keys and LBC amounts as values.\n ...\n}\n",
 		"listlockunspent":         "listlockunspent\n\nReturns a JSON array of outpoints marked as locked (with lockunspent) for this wallet session.\n\nArguments:\nNone\n\nResult:\n[{\n \"txid\": \"value\", (string)  The transaction hash of the referenced output.\n \"vout\": n,       (numeric) The output index of the referenced output.\n},...]\n",
 		"listreceivedbyaccount":   "listreceivedbyaccount (minconf=1 includeempty=false includewatchonly=false)\n\nReturns a JSON array of objects listing all accounts and the total amount received by each account.\n\nArguments:\n1. minconf          (numeric, optional, default=1)     Minimum number of block confirmations required before a transaction is considered.\n2. includeempty     (boolean, optional, default=false) Unused.\n3. includewatchonly (boolean, optional, default=false) Unused.\n\nResult:\n[{\n \"account\": \"value\", (string)  Account name.\n \"amount\": n.nnn,    (numeric) Total amount received by payment addresses of the account valued in LBC.\n \"confirmations\": n, (numeric) Number of block confirmations of the most recent transaction relevant to the account.\n},...]\n",
 		"listreceivedbyaddress":   "listreceivedbyaddress (minconf=1 includeempty=false includewatchonly=false)\n\nReturns a JSON array of objects listing wallet payment addresses and their total received amounts.\n\nArguments:\n1. minconf          (numeric, optional, default=1)     Minimum number of block confirmations required before a transaction is considered.\n2. includeempty     (boolean, optional, default=false) Unused.\n3. includewatchonly (boolean, optional, default=false) Unused.\n\nResult:\n[{\n \"address\": \"value\",              (string)          The payment address.\n \"amount\": n.nnn,                 (numeric)         Total amount received by the payment address valued in LBC.\n \"confirmations\": n,              (numeric)         Number of block confirmations of the most recent transaction relevant to the address.\n \"txids\": [\"value\",...],          (array of string) Transaction hashes of all transactions involving this address.\n \"involvesWatchonly\": true|false, (boolean)         Unset.\n},...]\n",
-		"listsinceblock":          "listsinceblock (\"blockhash\" targetconfirmations=1 includewatchonly=false)\n\nReturns a JSON array of objects listing details of all wallet transactions after some block.\n\nArguments:\n1. blockhash           (string, optional)                 Hash of the parent block of the first block to consider transactions from, or unset to list all transactions.\n2. targetconfirmations (numeric, optional, default=1)     Minimum number of block confirmations of the last block in the result object.  Must be 1 or greater.  Note: The transactions array in the result object is not affected by this parameter.\n3. includewatchonly    (boolean, optional, default=false) Unused.\n\nResult:\n{\n \"transactions\": [{                 (array of object) JSON array of objects containing verbose details of the each transaction.\n  \"abandoned\": true|false,          (boolean)         Unset.\n  \"account\": \"value\",               (string)          The account name associated with the transaction.\n  \"address\": \"value\",               (string)          Payment address for a transaction output.\n  \"amount\": n.nnn,                  (numeric)         The value of the transaction output valued in LBC.\n  \"bip125-replaceable\": \"value\",    (string)          Unset.\n  \"blockhash\": \"value\",             (string)          The hash of the block this transaction is mined in, or the empty string if unmined.\n  \"blockheight\": n,                 (numeric)         The block height containing the transaction.\n  \"blockindex\": n,                  (numeric)         Unset.\n  \"blocktime\": n,                   (numeric)         The Unix time of the block header this transaction is mined in, or 0 if unmined.\n  \"category\": \"value\",              (string)          The kind of transaction: \"send\" for sent transactions, \"immature\" for immature coinbase outputs, \"generate\" for mature coinbase outputs, or \"recv\" for all other received outputs.  Note: A single output may be included multiple times under different categories\n  \"confirmations\": n,               (numeric)         The number of block confirmations of the transaction.\n  \"fee\": n.nnn,                     (numeric)         The total input value minus the total output value for sent transactions.\n  \"generated\": true|false,          (boolean)         Whether the transaction output is a coinbase output.\n  \"involveswatchonly\": true|false,  (boolean)         Unset.\n  \"label\": \"value\",                 (string)          A comment for the address/transaction, if any.\n  \"time\": n,                        (numeric)         The earliest Unix time this transaction was known to exist.\n  \"timereceived\": n,                (numeric)         The earliest Unix time this transaction was known to exist.\n  \"trusted\": true|false,            (boolean)         Unset.\n  \"txid\": \"value\",                  (string)          The hash of the transaction.\n  \"vout\": n,                        (numeric)         The transaction output index.\n  \"walletconflicts\": [\"value\",...], (array of string) Unset.\n  \"comment\": \"value\",               (string)          Unset.\n  \"otheraccount\": \"value\",          (string)          Unset.\n },...],                                              \n \"lastblock\": \"value\",              (string)          Hash of the latest-synced block to be used in later calls to listsinceblock.\n}                                   \n",
-		"listtransactions":        "listtransactions (account=\"default\" count=10 from=0 includewatchonly=false)\n\nReturns a JSON array of objects containing verbose details for wallet transactions.\n\nArguments:\n1. account          (string, optional, default=\"default\") Account to filter transactions results by. Defaults to 'default'.\n2. count            (numeric, optional, default=10)       Maximum number of transactions to create results from. Defaults to 10\n3. from             (numeric, optional, default=0)        Number of transactions to skip before results are created.\n4. includewatchonly (boolean, optional, default=false)    Unused.\n\nResult:\n[{\n \"abandoned\": true|false,          (boolean)         Unset.\n \"account\": \"value\",               (string)          The account name associated with the transaction.\n \"address\": \"value\",               (string)          Payment address for a transaction output.\n \"amount\": n.nnn,                  (numeric)         The value of the transaction output valued in LBC.\n \"bip125-replaceable\": \"value\",    (string)          Unset.\n \"blockhash\": \"value\",             (string)          The hash of the block this transaction is mined in, or the empty string if unmined.\n \"blockheight\": n,                 (numeric)         The block height containing the transaction.\n \"blockindex\": n,                  (numeric)         Unset.\n \"blocktime\": n,                   (numeric)         The Unix time of the block header this transaction is mined in, or 0 if unmined.\n \"category\": \"value\",              (string)          The kind of transaction: \"send\" for sent transactions, \"immature\" for immature coinbase outputs, \"generate\" for mature coinbase outputs, or \"recv\" for all other received outputs.  Note: A single output may be included multiple times under different categories\n \"confirmations\": n,               (numeric)         The number of block confirmations of the transaction.\n \"fee\": n.nnn,                     (numeric)         The total input value minus the total output value for sent transactions.\n \"generated\": true|false,          (boolean)         Whether the transaction output is a coinbase output.\n \"involveswatchonly\": true|false,  (boolean)         Unset.\n \"label\": \"value\",                 (string)          A comment for the address/transaction, if any.\n \"time\": n,                        (numeric)         The earliest Unix time this transaction was known to exist.\n \"timereceived\": n,                (numeric)         The earliest Unix time this transaction was known to exist.\n \"trusted\": true|false,            (boolean)         Unset.\n \"txid\": \"value\",                  (string)          The hash of the transaction.\n \"vout\": n,                        (numeric)         The transaction output index.\n \"walletconflicts\": [\"value\",...], (array of string) Unset.\n \"comment\": \"value\",               (string)          Unset.\n \"otheraccount\": \"value\",          (string)          Unset.\n},...]\n",
-		"listunspent":             "listunspent (minconf=1 maxconf=9999999 [\"address\",...])\n\nReturns a JSON array of objects representing unlocked unspent outputs controlled by wallet keys.\n\nArguments:\n1. minconf   (numeric, optional, default=1)       Minimum number of block confirmations required before a transaction output is considered.\n2. maxconf   (numeric, optional, default=9999999) Maximum number of block confirmations required before a transaction output is excluded.\n3. addresses (array of string, optional)          If set, limits the returned details to unspent outputs received by any of these payment addresses.\n\nResult:\n{\n \"txid\": \"value\",         (string)  The transaction hash of the referenced output.\n \"vout\": n,               (numeric) The output index of the referenced output.\n \"address\": \"value\",      (string)  The payment address that received the output.\n \"account\": \"value\",      (string)  The account associated with the receiving payment address.\n \"scriptPubKey\": \"value\", (string)  The output script encoded as a hexadecimal string.\n \"redeemScript\": \"value\", (string)  Unset.\n \"amount\": n.nnn,         (numeric) The amount of the output valued in LBC.\n \"confirmations\": n,      (numeric) The number of block confirmations of the transaction.\n \"solvable\": true|false,  (boolean) Whether the output is solvable.\n \"spendable\": true|false, (boolean) Whether the output is entirely controlled by wallet keys/scripts (false for partially controlled multisig outputs or outputs to watch-only addresses).\n \"isstake\": true|false,   (boolean) Whether the output is staked.\n}                         \n",
+		"listsinceblock":          "listsinceblock (\"blockhash\" targetconfirmations=1 includewatchonly=false)\n\nReturns a JSON array of objects listing details of all wallet transactions after some block.\n\nArguments:\n1. blockhash           (string, optional)                 Hash of the parent block of the first block to consider transactions from, or unset to list all transactions.\n2. targetconfirmations (numeric, optional, default=1)     Minimum number of block confirmations of the last block in the result object.  Must be 1 or greater.  Note: The transactions array in the result object is not affected by this parameter.\n3. includewatchonly    (boolean, optional, default=false) Unused.\n\nResult:\n{\n \"transactions\": [{                 (array of object) JSON array of objects containing verbose details of the each transaction.\n  \"abandoned\": true|false,          (boolean)         Unset.\n  \"account\": \"value\",               (string)          The account name associated with the transaction.\n  \"address\": \"value\",               (string)          Payment address for a transaction output.\n  \"amount\": n.nnn,                  (numeric)         The value of the transaction output valued in LBC.\n  \"bip125-replaceable\": \"value\",    (string)          Unset.\n  \"blockhash\": \"value\",             (string)          The hash of the block this transaction is mined in, or the empty string if unmined.\n  \"blockheight\": n,                 (numeric)         The block height containing the transaction.\n  \"blockindex\": n,                  (numeric)         Unset.\n  \"blocktime\": n,                   (numeric)         The Unix time of the block header this transaction is mined in, or 0 if unmined.\n  \"category\": \"value\",              (string)          The kind of transaction: \"send\" for sent transactions, \"immature\" for immature coinbase outputs, \"generate\" for mature coinbase outputs, or \"recv\" for all other received outputs.  Note: A single output may be included multiple times under different categories\n  \"confirmations\": n,               (numeric)         The number of block confirmations of the transaction.\n  \"fee\": n.nnn,                     (numeric)         The total input value minus the total output value for sent transactions.\n  \"generated\": true|false,          (boolean)         Whether the transaction output is a coinbase output.\n  \"involveswatchonly\": true|false,  (boolean)         Unset.\n  \"label\": \"value\",                 (string)          A comment for the address/transaction, if any.\n  \"time\": n,                        (numeric)         The earliest Unix time this transaction was known to exist.\n  \"timereceived\": n,                (numeric)         The earliest Unix time this transaction was known to exist.\n  \"trusted\": true|false,            (boolean)         Unset.\n  \"txid\": \"value\",                  (string)          The hash of the transaction.\n  \"vout\": n,                        (numeric)         The transaction output index.\n  \"walletconflicts\": [\"value\",...], (array of string) Unset.\n  \"comment\": \"value\",               (string)          The comment the transaction was sent with, if any.\n  \"otheraccount\": \"value\",          (string)          Unset.\n },...],                                              \n \"lastblock\": \"value\",              (string)          Hash of the latest-synced block to be used in later calls to listsinceblock.\n}                                   \n",
+		"listtransactions":        "listtransactions (account=\"default\" count=10 from=0 includewatchonly=false)\n\nReturns a JSON array of objects containing verbose details for wallet transactions.\n\nArguments:\n1. account          (string, optional, default=\"default\") Account to filter transactions results by. Defaults to 'default'.\n2. count            (numeric, optional, default=10)       Maximum number of transactions to create results from. Defaults to 10\n3. from             (numeric, optional, default=0)        Number of transactions to skip before results are created.\n4. includewatchonly (boolean, optional, default=false)    Unused.\n\nResult:\n[{\n \"abandoned\": true|false,          (boolean)         Unset.\n \"account\": \"value\",               (string)          The account name associated with the transaction.\n \"address\": \"value\",               (string)          Payment address for a transaction output.\n \"amount\": n.nnn,                  (numeric)         The value of the transaction output valued in LBC.\n \"bip125-replaceable\": \"value\",    (string)          Unset.\n \"blockhash\": \"value\",             (string)          The hash of the block this transaction is mined in, or the empty string if unmined.\n \"blockheight\": n,                 (numeric)         The block height containing the transaction.\n \"blockindex\": n,                  (numeric)         Unset.\n \"blocktime\": n,                   (numeric)         The Unix time of the block header this transaction is mined in, or 0 if unmined.\n \"category\": \"value\",              (string)          The kind of transaction: \"send\" for sent transactions, \"immature\" for immature coinbase outputs, \"generate\" for mature coinbase outputs, or \"recv\" for all other received outputs.  Note: A single output may be included multiple times under different categories\n \"confirmations\": n,               (numeric)         The number of block confirmations of the transaction.\n \"fee\": n.nnn,                     (numeric)         The total input value minus the total output value for sent transactions.\n \"generated\": true|false,          (boolean)         Whether the transaction output is a coinbase output.\n \"involveswatchonly\": true|false,  (boolean)         Unset.\n \"label\": \"value\",                 (string)          A comment for the address/transaction, if any.\n \"time\": n,                        (numeric)         The earliest Unix time this transaction was known to exist.\n \"timereceived\": n,                (numeric)         The earliest Unix time this transaction was known to exist.\n \"trusted\": true|false,            (boolean)         Unset.\n \"txid\": \"value\",                  (string)          The hash of the transaction.\n \"vout\": n,                        (numeric)         The transaction output index.\n \"walletconflicts\": [\"value\",...], (array of string) Unset.\n \"comment\": \"value\",               (string)          The comment the transaction was sent with, if any.\n \"to\": \"value\",                    (string)          The comment naming the recipient the transaction was sent with, if any.\n \"otheraccount\": \"value\",          (string)          Unset.\n \"fiatcurrency\": \"value\",          (string)          The currency of the recorded exchange rate (only if a rate was recorded)\n \"fiatrate\": n.nnn,                (numeric)         The price of one LBC in the currency when the transaction was received (only if a rate was recorded)\n \"fiatamount\": n.nnn,              (numeric)         The amount in the currency at the recorded exchange rate (only if a rate was recorded)\n},...]\n",
+		"listunspent":             "listunspent (minconf=1 maxconf=9999999 [\"address\",...])\n\nReturns a JSON array of objects representing unlocked unspent outputs controlled by wallet keys.\n\nArguments:\n1. minconf   (numeric, optional, default=1)       Minimum number of block confirmations required before a transaction output is considered.\n2. maxconf   (numeric, optional, default=9999999) Maximum number of block confirmations required before a transaction output is excluded.\n3. addresses (array of string, optional)          If set, limits the returned details to unspent outputs received by any of these payment addresses.\n\nResult:\n[{\n \"txid\": \"value\",         (string)  The transaction hash of the referenced output.\n \"vout\": n,               (numeric) The output index of the referenced output.\n \"address\": \"value\",      (string)  The payment address that received the output.\n \"account\": \"value\",      (string)  The account associated with the receiving payment address.\n \"scriptPubKey\": \"value\", (string)  The output script encoded as a hexadecimal string.\n \"redeemScript\": \"value\", (string)  Unset.\n \"amount\": n.nnn,         (numeric) The amount of the output valued in LBC.\n \"confirmations\": n,      (numeric) The number of block confirmations of the transaction.\n \"solvable\": true|false,  (boolean) Whether the output is solvable.\n \"spendable\": true|false, (boolean) Whether the output is entirely controlled by wallet keys/scripts (false for partially controlled multisig outputs or outputs to watch-only addresses).\n \"isstake\": true|false,   (boolean) Whether the output is staked.\n \"tainted\": true|false,   (boolean) Whether the output, or the address it pays to, is tainted, so that coin selection never chooses it.\n \"taintreason\": \"value\",  (string)  The reason the output or its address was tainted, if any.\n},...]\n",
 		"lockunspent":             "lockunspent unlock [{\"txid\":\"value\",\"vout\":n},...]\n\nLocks or unlocks an unspent output.\nLocked outputs are not chosen for transaction inputs of authored transactions and are not included in 'listunspent' results.\nLocked outputs are volatile and are not saved across wallet restarts.\nIf unlock is true and no transaction outputs are specified, all locked outputs are marked unlocked.\n\nArguments:\n1. unlock       (boolean, required)         True to unlock outputs, false to lock.\n2. transactions (array of object, required) Transaction outputs to lock or unlock.\n[{\n \"txid\": \"value\", (string)  The transaction hash of the referenced output.\n \"vout\": n,       (numeric) The output index of the referenced output.\n},...]\n\nResult:\ntrue|false (boolean) The boolean 'true'.\n",
-		"sendfrom":                "sendfrom \"fromaccount\" \"toaddress\" amount (minconf=1 addresstype=\"*\" \"comment\" \"commentto\")\n\nAuthors, signs, and sends a transaction that outputs some amount to a payment address.\nA change output is automatically included to send extra output value back to the original account.\n\nArguments:\n1. fromaccount (string, required)              Account to pick unspent outputs from.\n2. toaddress   (string, required)              Address to pay.\n3. amount      (numeric, required)             Amount to send to the payment address valued in LBC.\n4. minconf     (numeric, optional, default=1)  Minimum number of block confirmations required before a transaction output is eligible to be spent.\n5. addresstype (string, optional, default=\"*\") Address type filter for UTXOs to spent from. Must be one of 'legacy', 'p2sh-segwit', 'bech32', or '*'. Defaults to '*'.\n6. comment     (string, optional)              Unused.\n7. commentto   (string, optional)              Unused.\n\nResult:\n\"value\" (string) The transaction hash of the sent transaction.\n",
-		"sendmany":                "sendmany \"fromaccount\" {\"address\":amount,...} (minconf=1 addresstype=\"*\" \"comment\")\n\nAuthors, signs, and sends a transaction that outputs to many payment addresses.\nA change output is automatically included to send extra output value back to the original account.\n\nArguments:\n1. fromaccount (string, required) Account to pick unspent outputs from.\n2. amounts     (object, required) Pairs of payment addresses and the output amount to pay each.\n{\n \"Address to pay.\": Amount to send to the payment address valued in LBC., (object) JSON object using payment addresses as keys and output amounts valued in LBC to send to each address.\n ...\n}\n3. minconf     (numeric, optional, default=1)  Minimum number of block confirmations required before a transaction output is eligible to be spent.\n4. addresstype (string, optional, default=\"*\") Address type filter for UTXOs to spent from. Must be one of 'legacy', 'p2sh-segwit', 'bech32', or '*'. Defaults to '*'.\n5. comment     (string, optional)              Unused.\n\nResult:\n\"value\" (string) The transaction hash of the sent transaction.\n",
-		"sendtoaddress":           "sendtoaddress \"address\" amount (addresstype=\"*\" \"comment\" \"commentto\")\n\nAuthors, signs, and sends a transaction that outputs some amount to a payment address.\nUnlike sendfrom, outputs are always chosen from the default account.\nA change output is automatically included to send extra output value back to the original account.\n\nArguments:\n1. address     (string, required)              Address to pay.\n2. amount      (numeric, required)             Amount to send to the payment address valued in LBC.\n3. addresstype (string, optional, default=\"*\") Address type filter for UTXOs to spent from. Must be one of 'legacy', 'p2sh-segwit', 'bech32', or '*'. Defaults to '*'.\n4. comment     (string, optional)              Unused.\n5. commentto   (string, optional)              Unused.\n\nResult:\n\"value\" (string) The transaction hash of the sent transaction.\n",
+		"sendfrom":                "sendfrom \"fromaccount\" \"toaddress\" amount (minconf=1 addresstype=\"*\" \"comment\" \"commentto\")\n\nAuthors, signs, and sends a transaction that outputs some amount to a payment address.\nA change output is automatically included to send extra output value back to the original account.\nWhen the wallet previews sends, the transaction is not signed or sent until confirmsend is called with the handle of its preview, which is returned instead.\nSends paying more than the withdrawal threshold are queued as withdrawals, which are returned instead and sent once approved with approvewithdrawal.\nWhen the keys of the wallet are offline, an unsigned PSBT is returned instead, to be signed by signpsbt of the offline wallet and sent with publishpsbt.\n\nArguments:\n1. fromaccount (string, required)              Account to pick unspent outputs from.\n2. toaddress   (string, required)              Address to pay.\n3. amount      (numeric, required)             Amount to send to the payment address valued in LBC.\n4. minconf     (numeric, optional, default=1)  Minimum number of block confirmations required before a transaction output is eligible to be spent.\n5. addresstype (string, optional, default=\"*\") Address type filter for UTXOs to spent from. Must be one of 'legacy', 'p2sh-segwit', 'bech32', or '*'. Defaults to '*'.\n6. comment     (string, optional)              A comment stored with the transaction, returned by gettransaction and listtransactions.\n7. commentto   (string, optional)              A comment naming the recipient, stored with the transaction.\n\nResult (sends neither previewed, requiring approval nor signed offline.):\n\"value\" (string) The transaction hash of the sent transaction.\n\nResult (sends previewed.):\n{\n \"handle\": \"value\",     (string)          The handle to pass to confirmsend to sign and send the transaction, or to cancelsend to discard it.\n \"hex\": \"value\",        (string)          The hex-encoded unsigned transaction.\n \"inputs\": [{           (array of object) The outputs spent by the transaction.\n  \"txid\": \"value\",      (string)          The hash of the transaction of the spent output.\n  \"vout\": n,            (numeric)         The index of the spent output.\n  \"amount\": n.nnn,      (numeric)         The value of the spent output valued in LBC.\n },...],                                  \n \"outputs\": [{          (array of object) The outputs of the transaction.\n  \"vout\": n,            (numeric)         The index of the output.\n  \"address\": \"value\",   (string)          The address paid by the output, if any.\n  \"amount\": n.nnn,      (numeric)         The value of the output valued in LBC.\n  \"change\": true|false, (boolean)         Whether the output pays the change back to the wallet.\n },...],                                  \n \"fee\": n.nnn,          (numeric)         The fee paid by the transaction valued in LBC.\n \"vsize\": n,            (numeric)         The estimated virtual size of the signed transaction.\n \"expires\": n,          (numeric)         The time the preview is discarded unless confirmed, in seconds since 1 Jan 1970 GMT.\n}                       \n\nResult (sends requiring approval.):\n{\n \"id\": n,                    (numeric)         The ID of the withdrawal.\n \"state\": \"value\",           (string)          The state of the withdrawal: 'pending' until it has its approvals, 'approved' until it is sent, 'sent', 'canceled' or 'expired'.\n \"amount\": n.nnn,            (numeric)         The total paid by the withdrawal valued in LBC.\n \"outputs\": [{               (array of object) The outputs paid by the withdrawal.\n  \"address\": \"value\",        (string)          The address paid by the output.\n  \"amount\": n.nnn,           (numeric)         The value of the output valued in LBC.\n },...],                                       \n \"account\": n,               (numeric)         The number of the account the withdrawal is paid from.\n \"requester\": \"value\",       (string)          The RPC user who requested the withdrawal.\n \"approvers\": [\"value\",...], (array of string) The approvers who approved the withdrawal, in the order they approved it.\n \"required\": n,              (numeric)         The number of approvals the withdrawal needs.\n \"created\": n,               (numeric)         The time the withdrawal was requested, in seconds since 1 Jan 1970 GMT.\n \"expires\": n,               (numeric)         The time the withdrawal expires unless approved, in seconds since 1 Jan 1970 GMT.\n \"txid\": \"value\",            (string)          The hash of the transaction of the sent withdrawal.\n \"history\": [{               (array of object) The events of the withdrawal, in the order they happened.\n  \"time\": n,                 (numeric)         The time of the event, in seconds since 1 Jan 1970 GMT.\n  \"action\": \"value\",         (string)          What happened: 'request', 'approve', 'send', 'fail', 'cancel' or 'expire'.\n  \"user\": \"value\",           (string)          The RPC user who requested, approved or canceled the withdrawal.\n  \"detail\": \"value\",         (string)          The hash of the sent transaction, or the error of a failed send.\n },...],                                       \n}                            \n\nResult (sends signed offline.):\n{\n \"psbt\": \"value\", (string)  The base64-encoded unsigned PSBT, to be signed by signpsbt of the offline wallet.\n \"fee\": n.nnn,    (numeric) The fee paid by the transaction valued in LBC.\n \"file\": \"value\", (string)  The file the PSBT was written to, if the wallet writes them to a directory.\n}                 \n",
+		"sendmany":                "sendmany \"fromaccount\" {\"address\":amount,...} (minconf=1 addresstype=\"*\" \"comment\")\n\nAuthors, signs, and sends a transaction that outputs to many payment addresses.\nA change output is automatically included to send extra output value back to the original account.\nWhen the wallet previews sends, the transaction is not signed or sent until confirmsend is called with the handle of its preview, which is returned instead.\nSends paying more than the withdrawal threshold are queued as withdrawals, which are returned instead and sent once approved with approvewithdrawal.\nWhen the keys of the wallet are offline, an unsigned PSBT is returned instead, to be signed by signpsbt of the offline wallet and sent with publishpsbt.\n\nArguments:\n1. fromaccount (string, required) Account to pick unspent outputs from.\n2. amounts     (object, required) Pairs of payment addresses and the output amount to pay each.\n{\n \"Address to pay.\": Amount to send to the payment address valued in LBC., (object) JSON object using payment addresses as keys and output amounts valued in LBC to send to each address.\n ...\n}\n3. minconf     (numeric, optional, default=1)  Minimum number of block confirmations required before a transaction output is eligible to be spent.\n4. addresstype (string, optional, default=\"*\") Address type filter for UTXOs to spent from. Must be one of 'legacy', 'p2sh-segwit', 'bech32', or '*'. Defaults to '*'.\n5. comment     (string, optional)              A comment stored with the transaction, returned by gettransaction and listtransactions.\n\nResult (sends neither previewed, requiring approval nor signed offline.):\n\"value\" (string) The transaction hash of the sent transaction.\n\nResult (sends previewed.):\n{\n \"handle\": \"value\",     (string)          The handle to pass to confirmsend to sign and send the transaction, or to cancelsend to discard it.\n \"hex\": \"value\",        (string)          The hex-encoded unsigned transaction.\n \"inputs\": [{           (array of object) The outputs spent by the transaction.\n  \"txid\": \"value\",      (string)          The hash of the transaction of the spent output.\n  \"vout\": n,            (numeric)         The index of the spent output.\n  \"amount\": n.nnn,      (numeric)         The value of the spent output valued in LBC.\n },...],                                  \n \"outputs\": [{          (array of object) The outputs of the transaction.\n  \"vout\": n,            (numeric)         The index of the output.\n  \"address\": \"value\",   (string)          The address paid by the output, if any.\n  \"amount\": n.nnn,      (numeric)         The value of the output valued in LBC.\n  \"change\": true|false, (boolean)         Whether the output pays the change back to the wallet.\n },...],                                  \n \"fee\": n.nnn,          (numeric)         The fee paid by the transaction valued in LBC.\n \"vsize\": n,            (numeric)         The estimated virtual size of the signed transaction.\n \"expires\": n,          (numeric)         The time the preview is discarded unless confirmed, in seconds since 1 Jan 1970 GMT.\n}                       \n\nResult (sends requiring approval.):\n{\n \"id\": n,                    (numeric)         The ID of the withdrawal.\n \"state\": \"value\",           (string)          The state of the withdrawal: 'pending' until it has its approvals, 'approved' until it is sent, 'sent', 'canceled' or 'expired'.\n \"amount\": n.nnn,            (numeric)         The total paid by the withdrawal valued in LBC.\n \"outputs\": [{               (array of object) The outputs paid by the withdrawal.\n  \"address\": \"value\",        (string)          The address paid by the output.\n  \"amount\": n.nnn,           (numeric)         The value of the output valued in LBC.\n },...],                                       \n \"account\": n,               (numeric)         The number of the account the withdrawal is paid from.\n \"requester\": \"value\",       (string)          The RPC user who requested the withdrawal.\n \"approvers\": [\"value\",...], (array of string) The approvers who approved the withdrawal, in the order they approved it.\n \"required\": n,              (numeric)         The number of approvals the withdrawal needs.\n \"created\": n,               (numeric)         The time the withdrawal was requested, in seconds since 1 Jan 1970 GMT.\n \"expires\": n,               (numeric)         The time the withdrawal expires unless approved, in seconds since 1 Jan 1970 GMT.\n \"txid\": \"value\",            (string)          The hash of the transaction of the sent withdrawal.\n \"history\": [{               (array of object) The events of the withdrawal, in the order they happened.\n  \"time\": n,                 (numeric)         The time of the event, in seconds since 1 Jan 1970 GMT.\n  \"action\": \"value\",         (string)          What happened: 'request', 'approve', 'send', 'fail', 'cancel' or 'expire'.\n  \"user\": \"value\",           (string)          The RPC user who requested, approved or canceled the withdrawal.\n  \"detail\": \"value\",         (string)          The hash of the sent transaction, or the error of a failed send.\n },...],                                       \n}                            \n\nResult (sends signed offline.):\n{\n \"psbt\": \"value\", (string)  The base64-encoded unsigned PSBT, to be signed by signpsbt of the offline wallet.\n \"fee\": n.nnn,    (numeric) The fee paid by the transaction valued in LBC.\n \"file\": \"value\", (string)  The file the PSBT was written to, if the wallet writes them to a directory.\n}                 \n",
+		"sendtoaddress":           "sendtoaddress \"address\" amount (addresstype=\"*\" \"comment\" \"commentto\")\n\nAuthors, signs, and sends a transaction that outputs some amount to a payment address.\nUnlike sendfrom, outputs are always chosen from the default account.\nA change output is automatically included to send extra output value back to the original account.\nWhen the wallet previews sends, the transaction is not signed or sent until confirmsend is called with the handle of its preview, which is returned instead.\nSends paying more than the withdrawal threshold are queued as withdrawals, which are returned instead and sent once approved with approvewithdrawal.\nWhen the keys of the wallet are offline, an unsigned PSBT is returned instead, to be signed by signpsbt of the offline wallet and sent with publishpsbt.\n\nArguments:\n1. address     (string, required)              Address to pay.\n2. amount      (numeric, required)             Amount to send to the payment address valued in LBC.\n3. addresstype (string, optional, default=\"*\") Address type filter for UTXOs to spent from. Must be one of 'legacy', 'p2sh-segwit', 'bech32', or '*'. Defaults to '*'.\n4. comment     (string, optional)              A comment stored with the transaction, returned by gettransaction and listtransactions.\n5. commentto   (string, optional)              A comment naming the recipient, stored with the transaction.\n\nResult (sends neither previewed, requiring approval nor signed offline.):\n\"value\" (string) The transaction hash of the sent transaction.\n\nResult (sends previewed.):\n{\n \"handle\": \"value\",     (string)          The handle to pass to confirmsend to sign and send the transaction, or to cancelsend to discard it.\n \"hex\": \"value\",        (string)          The hex-encoded unsigned transaction.\n \"inputs\": [{           (array of object) The outputs spent by the transaction.\n  \"txid\": \"value\",      (string)          The hash of the transaction of the spent output.\n  \"vout\": n,            (numeric)         The index of the spent output.\n  \"amount\": n.nnn,      (numeric)         The value of the spent output valued in LBC.\n },...],                                  \n \"outputs\": [{          (array of object) The outputs of the transaction.\n  \"vout\": n,            (numeric)         The index of the output.\n  \"address\": \"value\",   (string)          The address paid by the output, if any.\n  \"amount\": n.nnn,      (numeric)         The value of the output valued in LBC.\n  \"change\": true|false, (boolean)         Whether the output pays the change back to the wallet.\n },...],                                  \n \"fee\": n.nnn,          (numeric)         The fee paid by the transaction valued in LBC.\n \"vsize\": n,            (numeric)         The estimated virtual size of the signed transaction.\n \"expires\": n,          (numeric)         The time the preview is discarded unless confirmed, in seconds since 1 Jan 1970 GMT.\n}                       \n\nResult (sends requiring approval.):\n{\n \"id\": n,                    (numeric)         The ID of the withdrawal.\n \"state\": \"value\",           (string)          The state of the withdrawal: 'pending' until it has its approvals, 'approved' until it is sent, 'sent', 'canceled' or 'expired'.\n \"amount\": n.nnn,            (numeric)         The total paid by the withdrawal valued in LBC.\n \"outputs\": [{               (array of object) The outputs paid by the withdrawal.\n  \"address\": \"value\",        (string)          The address paid by the output.\n  \"amount\": n.nnn,           (numeric)         The value of the output valued in LBC.\n },...],                                       \n \"account\": n,               (numeric)         The number of the account the withdrawal is paid from.\n \"requester\": \"value\",       (string)          The RPC user who requested the withdrawal.\n \"approvers\": [\"value\",...], (array of string) The approvers who approved the withdrawal, in the order they approved it.\n \"required\": n,              (numeric)         The number of approvals the withdrawal needs.\n \"created\": n,               (numeric)         The time the withdrawal was requested, in seconds since 1 Jan 1970 GMT.\n \"expires\": n,               (numeric)         The time the withdrawal expires unless approved, in seconds since 1 Jan 1970 GMT.\n \"txid\": \"value\",            (string)          The hash of the transaction of the sent withdrawal.\n \"history\": [{               (array of object) The events of the withdrawal, in the order they happened.\n  \"time\": n,                 (numeric)         The time of the event, in seconds since 1 Jan 1970 GMT.\n  \"action\": \"value\",         (string)          What happened: 'request', 'approve', 'send', 'fail', 'cancel' or 'expire'.\n  \"user\": \"value\",           (string)          The RPC user who requested, approved or canceled the withdrawal.\n  \"detail\": \"value\",         (string)          The hash of the sent transaction, or the error of a failed send.\n },...],                                       \n}                            \n\nResult (sends signed offline.):\n{\n \"psbt\": \"value\", (string)  The base64-encoded unsigned PSBT, to be signed by signpsbt of the offline wallet.\n \"fee\": n.nnn,    (numeric) The fee paid by the transaction valued in LBC.\n \"file\": \"value\", (string)  The file the PSBT was written to, if the wallet writes them to a directory.\n}                 \n",
 		"settxfee":                "settxfee amount\n\nModify the increment used each time more fee is required for an authored transaction.\n\nArguments:\n1. amount (numeric, required) The new fee increment valued in LBC.\n\nResult:\ntrue|false (boolean) The boolean 'true'.\n",
 		"signmessage":             "signmessage \"address\" \"message\"\n\nSigns a message using the private key of a payment address.\n\nArguments:\n1. address (string, required) Payment address of private key used to sign the message with.\n2. message (string, required) Message to sign.\n\nResult:\n\"value\" (string) The signed message encoded as a base64 string.\n",
 		"signrawtransaction":      "signrawtransaction \"rawtx\" ([{\"txid\":\"value\",\"vout\":n,\"scriptpubkey\":\"value\",\"redeemscript\":\"value\"},...] [\"privkey\",...] flags=\"ALL\")\n\nSigns transaction inputs using private keys from this wallet and request.\nThe valid flags options are ALL, NONE, SINGLE, ALL|ANYONECANPAY, NONE|ANYONECANPAY, and SINGLE|ANYONECANPAY.\n\nArguments:\n1. rawtx    (string, required)                Unsigned or partially unsigned transaction to sign encoded as a hexadecimal string.\n2. inputs   (array of object, optional)       Additional data regarding inputs that this wallet may not be tracking.\n3. privkeys (array of string, optional)       Additional WIF-encoded private keys to use when creating signatures.\n4. flags    (string, optional, default=\"ALL\") Sighash flags.\n\nResult:\n{\n \"hex\": \"value\",         (string)          The resulting transaction encoded as a hexadecimal string.\n \"complete\": true|false, (boolean)         Whether all input signatures have been created.\n \"errors\": [{            (array of object) Script verification errors (if exists).\n  \"txid\": \"value\",       (string)          The transaction hash of the referenced previous output.\n  \"vout\": n,             (numeric)         The output index of the referenced previous output.\n  \"scriptSig\": \"value\",  (string)          The hex-encoded signature script.\n  \"sequence\": n,         (numeric)         Script sequence number.\n  \"error\": \"value\",      (string)          Verification or signing error related to the input.\n },...],                                   \n}                        \n",
-		"validateaddress":         "validateaddress \"address\"\n\nVerify that an address is valid.\nExtra details are returned if the address is controlled by this wallet.\nThe following fields are valid only when the address is controlled by this wallet (ismine=true): isscript, pubkey, iscompressed, account, addresses, hex, script, and sigsrequired.\nThe following fields are only valid when address has an associated public key: pubkey, iscompressed.\nThe following fields are only valid when address is a pay-to-script-hash address: addresses, hex, and script.\nIf the address is a multisig address controlled by this wallet, the multisig fields will be left unset if the wallet is locked since the redeem script cannot be decrypted.\n\nArguments:\n1. address (string, required) Address to validate.\n\nResult:\n{\n \"isvalid\": true|false,      (boolean)         Whether or not the address is valid.\n \"address\": \"value\",         (string)          The payment address (only when isvalid is true).\n \"ismine\": true|false,       (boolean)         Whether this address is controlled by the wallet (only when isvalid is true).\n \"iswatchonly\": true|false,  (boolean)         Unset.\n \"isscript\": true|false,     (boolean)         Whether the payment address is a pay-to-script-hash address (only when isvalid is true).\n \"pubkey\": \"value\",          (string)          The associated public key of the payment address, if any (only when isvalid is true).\n \"iscompressed\": true|false, (boolean)         Whether the address was created by hashing a compressed public key, if any (only when isvalid is true).\n \"account\": \"value\",         (string)          The account this payment address belongs to (only when isvalid is true).\n \"addresses\": [\"value\",...], (array of string) All associated payment addresses of the script if address is a multisig address (only when isvalid is true).\n \"hex\": \"value\",             (string)          The redeem script .\n \"script\": \"value\",          (string)          The class of redeem script for a multisig address.\n \"sigsrequired\": n,          (numeric)         The number of required signatures to redeem outputs to the multisig address.\n}                            \n",
+		"validateaddress":         "validateaddress \"address\"\n\nVerify that an address is valid.\nExtra details are returned if the address is controlled by this wallet.\nThe script type, network and witness fields are returned for any valid address, and the reason an address is invalid is returned in error.\nThe following fields are valid only when the address is controlled by this wallet (ismine=true): isscript, pubkey, iscompressed, account, addresses, hex, script, and sigsrequired.\nThe following fields are only valid when address has an associated public key: pubkey, iscompressed.\nThe following fields are only valid when address is a pay-to-script-hash address: addresses, hex, and script.\nIf the address is a multisig address controlled by this wallet, the multisig fields will be left unset if the wallet is locked since the redeem script cannot be decrypted.\n\nArguments:\n1. address (string, required) Address to validate.\n\nResult:\n{\n \"isvalid\": true|false,      (boolean)         Whether or not the address is valid.\n \"address\": \"value\",         (string)          The payment address (only when isvalid is true).\n \"scripttype\": \"value\",      (string)          The class of the output script paying to the address, such as pubkeyhash, scripthash, witness_v0_keyhash or witness_v1_taproot (only when isvalid is true).\n \"network\": \"value\",         (string)          The network of the wallet when isvalid is true, otherwise the network the address is meant for when its prefix is known.\n \"iswitness\": true|false,    (boolean)         Whether the address pays to a witness program (only when isvalid is true).\n \"witness_version\": n,       (numeric)         The witness version of a witness address.\n \"witness_program\": \"value\", (string)          The witness program of a witness address, in hex.\n \"error\": \"value\",           (string)          The reason the address is invalid, such as a wrong network prefix, a bad checksum or an unsupported witness version (only when isvalid is false).\n \"ismine\": true|false,       (boolean)         Whether this address is controlled by the wallet (only when isvalid is true).\n \"iswatchonly\": true|false,  (boolean)         Unset.\n \"isscript\": true|false,     (boolean)         Whether the payment address is a pay-to-script-hash address (only when isvalid is true).\n \"pubkey\": \"value\",          (string)          The associated public key of the payment address, if any (only when isvalid is true).\n \"iscompressed\": true|false, (boolean)         Whether the address was created by hashing a compressed public key, if any (only when isvalid is true).\n \"account\": \"value\",         (string)          The account this payment address belongs to (only when isvalid is true).\n \"addresses\": [\"value\",...], (array of string) All associated payment addresses of the script if address is a multisig address (only when isvalid is true).\n \"hex\": \"value\",             (string)          The redeem script .\n \"script\": \"value\",          (string)          The class of redeem script for a multisig address.\n \"sigsrequired\": n,          (numeric)         The number of required signatures to redeem outputs to the multisig address.\n}                            \n",
 		"verifymessage":           "verifymessage \"address\" \"signature\" \"message\"\n\nVerify a message was signed with the associated private key of some address.\n\nArguments:\n1. address   (string, required) Address used to sign message.\n2. signature (string, required) The signature to verify.\n3. message   (string, required) The message to verify.\n\nResult:\ntrue|false (boolean) Whether the message was signed with the private key of 'address'.\n",
 		"walletlock":              "walletlock\n\nLock the wallet.\n\nArguments:\nNone\n\nResult:\nNothing\n",
 		"walletpassphrase":        "walletpassphrase \"passphrase\" timeout\n\nUnlock the wallet.\n\nArguments:\n1. passphrase (string, required)  The wallet passphrase.\n2. timeout    (numeric, required) The number of seconds to wait before the wallet automatically locks.\n\nResult:\nNothing\n",
 		"walletpassphrasechange":  "walletpassphrasechange \"oldpassphrase\" \"newpassphrase\"\n\nChange the wallet passphrase.\n\nArguments:\n1. oldpassphrase (string, required) The old wallet passphrase.\n2. newpassphrase (string, required) The new wallet passphrase.\n\nResult:\nNothing\n",
+		"approvewithdrawal":       "approvewithdrawal id\n\nApproves a pending withdrawal as the approver making the request, and returns it.\nThe withdrawal is signed and sent once approved by as many distinct approvers as it requires, and is then returned sent.\nWhen the send fails, as while the wallet is locked, the withdrawal stays approved and the next approvewithdrawal retries the send.\nOnly the users of the rpcapprover option may approve withdrawals, and the requester may not approve their own.\n\nArguments:\n1. id (numeric, required) The ID of the withdrawal.\n\nResult:\n{\n \"id\": n,                    (numeric)         The ID of the withdrawal.\n \"state\": \"value\",           (string)          The state of the withdrawal: 'pending' until it has its approvals, 'approved' until it is sent, 'sent', 'canceled' or 'expired'.\n \"amount\": n.nnn,            (numeric)         The total paid by the withdrawal valued in LBC.\n \"outputs\": [{               (array of object) The outputs paid by the withdrawal.\n  \"address\": \"value\",        (string)          The address paid by the output.\n  \"amount\": n.nnn,           (numeric)         The value of the output valued in LBC.\n },...],                                       \n \"account\": n,               (numeric)         The number of the account the withdrawal is paid from.\n \"requester\": \"value\",       (string)          The RPC user who requested the withdrawal.\n \"approvers\": [\"value\",...], (array of string) The approvers who approved the withdrawal, in the order they approved it.\n \"required\": n,              (numeric)         The number of approvals the withdrawal needs.\n \"created\": n,               (numeric)         The time the withdrawal was requested, in seconds since 1 Jan 1970 GMT.\n \"expires\": n,               (numeric)         The time the withdrawal expires unless approved, in seconds since 1 Jan 1970 GMT.\n \"txid\": \"value\",            (string)          The hash of the transaction of the sent withdrawal.\n \"history\": [{               (array of object) The events of the withdrawal, in the order they happened.\n  \"time\": n,                 (numeric)         The time of the event, in seconds since 1 Jan 1970 GMT.\n  \"action\": \"value\",         (string)          What happened: 'request', 'approve', 'send', 'fail', 'cancel' or 'expire'.\n  \"user\": \"value\",           (string)          The RPC user who requested, approved or canceled the withdrawal.\n  \"detail\": \"value\",         (string)          The hash of the sent transaction, or the error of a failed send.\n },...],                                       \n}                            \n",
+		"benchsigning":            "benchsigning (duration=4 inputs=2)\n\nMeasures the throughput of the key derivations, ECDSA signatures and signature verifications on the host, with keys of a random seed, and the max rate of the transactions the wallet can create from it, for capacity planning.\nEach input of a transaction needs the derivation of its key, a signature and its verification, and each transaction the derivation of a change address.\nThe rates are those of a single core, as the wallet creates transactions one at a time, and exclude the database updates and the broadcast of the transactions.\n\nArguments:\n1. duration (numeric, optional, default=4) The number of seconds the measurement runs for, between 1 and 60.\n2. inputs   (numeric, optional, default=2) The number of inputs of the transactions whose rate is reported.\n\nResult:\n{\n \"derivationspersec\": n.nnn,         (numeric) The number of address keys derived per second.\n \"hardenedderivationspersec\": n.nnn, (numeric) The number of hardened keys, such as account keys, derived per second.\n \"signaturespersec\": n.nnn,          (numeric) The number of p2wkh inputs signed per second.\n \"verificationspersec\": n.nnn,       (numeric) The number of p2wkh input scripts verified per second.\n \"inputs\": n,                        (numeric) The number of inputs of the transactions of txpersec.\n \"txpersec\": n.nnn,                  (numeric) The max number of transactions spending inputs inputs created per second.\n}                                    \n",
+		"cancelsend":              "cancelsend \"handle\"\n\nDiscards a transaction previewed by a send command while the wallet previews sends, and unlocks the outputs it spends.\n\nArguments:\n1. handle (string, required) The handle of the preview.\n\nResult:\nNothing\n",
+		"cancelwithdrawal":        "cancelwithdrawal id\n\nCancels a withdrawal which was not sent yet, and returns it.\n\nArguments:\n1. id (numeric, required) The ID of the withdrawal.\n\nResult:\n{\n \"id\": n,                    (numeric)         The ID of the withdrawal.\n \"state\": \"value\",           (string)          The state of the withdrawal: 'pending' until it has its approvals, 'approved' until it is sent, 'sent', 'canceled' or 'expired'.\n \"amount\": n.nnn,            (numeric)         The total paid by the withdrawal valued in LBC.\n \"outputs\": [{               (array of object) The outputs paid by the withdrawal.\n  \"address\": \"value\",        (string)          The address paid by the output.\n  \"amount\": n.nnn,           (numeric)         The value of the output valued in LBC.\n },...],                                       \n \"account\": n,               (numeric)         The number of the account the withdrawal is paid from.\n \"requester\": \"value\",       (string)          The RPC user who requested the withdrawal.\n \"approvers\": [\"value\",...], (array of string) The approvers who approved the withdrawal, in the order they approved it.\n \"required\": n,              (numeric)         The number of approvals the withdrawal needs.\n \"created\": n,               (numeric)         The time the withdrawal was requested, in seconds since 1 Jan 1970 GMT.\n \"expires\": n,               (numeric)         The time the withdrawal expires unless approved, in seconds since 1 Jan 1970 GMT.\n \"txid\": \"value\",            (string)          The hash of the transaction of the sent withdrawal.\n \"history\": [{               (array of object) The events of the withdrawal, in the order they happened.\n  \"time\": n,                 (numeric)         The time of the event, in seconds since 1 Jan 1970 GMT.\n  \"action\": \"value\",         (string)          What happened: 'request', 'approve', 'send', 'fail', 'cancel' or 'expire'.\n  \"user\": \"value\",           (string)          The RPC user who requested, approved or canceled the withdrawal.\n  \"detail\": \"value\",         (string)          The hash of the sent transaction, or the error of a failed send.\n },...],                                       \n}                            \n",
+		"changepublicpassphrase":  "changepublicpassphrase \"oldpassphrase\" \"newpassphrase\"\n\nRe-encrypts the public data of the wallet, such as addresses and extended public keys, under a new public passphrase.\nPrivate keys are not affected.  The wallet must afterwards be opened with the new passphrase using the walletpass option.\n\nArguments:\n1. oldpassphrase (string, required) The current public passphrase (\"public\" unless previously changed).\n2. newpassphrase (string, required) The new public passphrase.\n\nResult:\nNothing\n",
+		"clonewallet":             "clonewallet \"destination\"\n\nWrites a watch-only copy of the wallet database to a new file, for staging or analytics environments which must not hold the keys of the wallet.\nThe copy keeps the accounts, addresses, transactions and metadata of the wallet and its public passphrase, but its private keys, encrypted scripts and private passphrase are deleted, so it can never be unlocked.\n\nArguments:\n1. destination (string, required) The path of the new wallet database, which must not exist.\n\nResult:\nNothing\n",
+		"collectdebuginfo":        "collectdebuginfo\n\nReturns a bundle of debug information to attach to bug reports: the version, the config with its credentials redacted, the recent logs, the stack traces of all goroutines and the stats of the wallet databases.\nThe same bundle is written by the --collectdebuginfo option when lbcwallet does not start.\n\nArguments:\nNone\n\nResult:\n\"value\" (string) The base64 encoded gzipped tar bundle.\n",
+		"confirmsend":             "confirmsend \"handle\"\n\nSigns and sends a transaction previewed by a send command while the wallet previews sends.\nPreviews not confirmed within 10 minutes are discarded, and the send is kept pending when the transaction cannot be signed, as while the wallet is locked.\n\nArguments:\n1. handle (string, required) The handle of the preview.\n\nResult:\n\"value\" (string) The transaction hash of the sent transaction.\n",
+		"createinvoice":           "createinvoice amount (\"memo\" expiry=3600 account=\"default\")\n\nCreates an invoice requesting a payment to a new address of an account.\nThe invoice is paid once the transactions paying its address before it expires total at least its amount.\n\nArguments:\n1. amount  (numeric, required)                   The amount to request valued in LBC, or 0 to accept any amount.\n2. memo    (string, optional)                    A memo describing the invoice, included as the message of its payment URI.\n3. expiry  (numeric, optional, default=3600)     The number of seconds after which the invoice expires if it is not paid.\n4. account (string, optional, default=\"default\") The account to receive the payment to.\n\nResult:\n{\n \"id\": n,            (numeric)         The ID of the invoice.\n \"address\": \"value\", (string)          The address to pay.\n \"uri\": \"value\",     (string)          The BIP0021 payment URI of the invoice.\n \"amount\": n.nnn,    (numeric)         The requested amount valued in LBC.\n \"memo\": \"value\",    (string)          The memo of the invoice.\n \"created\": n,       (numeric)         The time the invoice was created in seconds since 1 Jan 1970 GMT.\n \"expires\": n,       (numeric)         The time the invoice expires in seconds since 1 Jan 1970 GMT.\n \"state\": \"value\",   (string)          The state of the invoice: unpaid, paid, or expired.\n \"received\": n.nnn,  (numeric)         The total value of the payments to the invoice valued in LBC.\n \"paidtime\": n,      (numeric)         The time the invoice was paid in seconds since 1 Jan 1970 GMT.\n \"payments\": [{      (array of object) The transactions paying the invoice, including payments after it was paid or expired.\n  \"txid\": \"value\",   (string)          The hash of the transaction.\n  \"amount\": n.nnn,   (numeric)         The value paid to the invoice by the transaction valued in LBC.\n },...],                               \n}                    \n",
 		"createnewaccount":        "createnewaccount \"account\"\n\nCreates a new account.\n\nArguments:\n1. account (string, required) Account name.\n\nResult:\nNothing\n",
+		"createpaymenturi":        "createpaymenturi \"address\" (amount \"label\" \"message\" qrcode=false)\n\nReturns a BIP0021 payment URI requesting a payment to an address, optionally with a QR code of the URI.\n\nArguments:\n1. address (string, required)                 The address to pay.\n2. amount  (numeric, optional)                The amount to request valued in LBC.\n3. label   (string, optional)                 A label for the payee, such as the name of a merchant.\n4. message (string, optional)                 A message describing the payment, such as an order number.\n5. qrcode  (boolean, optional, default=false) Also return a QR code of the URI as a PNG image.\n\nResult:\n{\n \"uri\": \"value\",    (string) The payment URI.\n \"qrcode\": \"value\", (string) The base64 encoded PNG image of a QR code of the URI, if requested.\n}                   \n",
+		"createvault":             "createvault \"name\" \"recoverypubkey\" delay\n\nCreates a vault, an account whose outputs are only spent by its keys once they have a number of confirmations, and by a recovery key at any time.\nOutputs paid to the addresses of getvaultaddress are never chosen by coin selection, and are spent with sendfromvault and recovervault.\n\nArguments:\n1. name           (string, required)  The name of the vault and of its account.\n2. recoverypubkey (string, required)  The hex-encoded public key which spends the outputs of the vault at any time, whose private key should be kept offline.\n3. delay          (numeric, required) The number of confirmations the outputs of the vault must have before its keys spend them.\n\nResult:\nNothing\n",
+		"decodepaymenturi":        "decodepaymenturi \"uri\"\n\nReturns the address and parameters of a BIP0021 payment URI.\nURIs with parameters prefixed by 'req-' which are not understood are rejected.\n\nArguments:\n1. uri (string, required) The payment URI.\n\nResult:\n{\n \"address\": \"value\", (string)  The address to pay.\n \"amount\": n.nnn,    (numeric) The requested amount valued in LBC, if any.\n \"label\": \"value\",   (string)  The label of the payee, if any.\n \"message\": \"value\", (string)  The message describing the payment, if any.\n \"params\": {         (object)  Other parameters of the URI, keyed by name.\n  \"name\": value, (object) The value of the parameter\n  ...\n }\n} \n",
+		"diagnosetransaction":     "diagnosetransaction \"txid\"\n\nExplains why an unconfirmed wallet transaction is not confirming and suggests what to do about it.\nThe transaction is checked for a fee rate below the estimate of the chain server to confirm within 6 blocks, a missing parent, a conflict with another wallet transaction and a missing broadcast.\nThe suggested actions are 'bumpfee' to replace the transaction with one paying a higher fee, 'cpfp' to spend one of its outputs paid to the wallet with a fee high enough for both to be mined, 'abandon' to remove it from the wallet so that the outputs it spends can be spent again, 'rebroadcast' to send it or its unconfirmed parent to the network again, and 'wait'.\n\nArguments:\n1. txid (string, required) The hash of the transaction.\n\nResult:\n{\n \"txid\": \"value\",                 (string)          The hash of the transaction.\n \"confirmations\": n,              (numeric)         The number of confirmations of the transaction, which is not diagnosed further when mined.\n \"timereceived\": n,               (numeric)         The time the wallet received the transaction, in seconds since 1 Jan 1970 GMT.\n \"vsize\": n,                      (numeric)         The virtual size of the transaction.\n \"fee\": n.nnn,                    (numeric)         The fee paid by the transaction valued in LBC, omitted when unknown.\n \"feerate\": n.nnn,                (numeric)         The fee rate of the transaction in LBC/kvB, omitted when unknown.\n \"estimatedfeerate\": n.nnn,       (numeric)         The fee rate in LBC/kvB estimated by the chain server to confirm within 6 blocks, omitted when it has no estimate.\n \"inmempool\": true|false,         (boolean)         Whether the transaction is in the mempool of the chain server.\n \"broadcasttime\": n,              (numeric)         The time the transaction is broadcast when delayed by the broadcast policy, in seconds since 1 Jan 1970 GMT.\n \"unminedparents\": [\"value\",...], (array of string) The hashes of the unconfirmed wallet transactions whose outputs the transaction spends.\n \"missinginputs\": [\"value\",...],  (array of string) The outputs spent by the transaction that are in neither the chain nor the mempool, as txid:vout.\n \"conflicts\": [\"value\",...],      (array of string) The hashes of the other unconfirmed wallet transactions spending the same outputs.\n \"reasons\": [\"value\",...],        (array of string) The reasons the transaction is not confirming.\n \"actions\": [\"value\",...],        (array of string) The suggested actions: bumpfee, cpfp, abandon, rebroadcast or wait.\n}                                 \n",
+		"dumpgoroutines":          "dumpgoroutines\n\nReturns the stack traces of all goroutines of the running process, for diagnosing hangs and leaks.\n\nArguments:\nNone\n\nResult:\n\"value\" (string) The goroutine stack traces in the format of a Go panic.\n",
+		"dumpheapprofile":         "dumpheapprofile (gc=false)\n\nReturns a profile of the live heap allocations of the running process.\n\nArguments:\n1. gc (boolean, optional, default=false) Run a garbage collection before taking the profile so it only includes live objects.\n\nResult:\n\"value\" (string) The base64 encoded profile, which can be decoded and read with go tool pprof.\n",
+		"dumpimportedaccount":     "dumpimportedaccount \"account\"\n\nReturns the addresses and WIF-encoded private keys of an imported-key account.\n\nArguments:\n1. account (string, required) The name of the imported-key account.\n\nResult:\n[{\n \"address\": \"value\", (string) The address of the imported key.\n \"privkey\": \"value\", (string) The WIF-encoded private key.\n},...]\n",
+		"exportseedshares":        "exportseedshares threshold count\n\nSplits the master key of the wallet, which recovers it as its seed does, into Shamir shares, any threshold of which recover the wallet with lbcwallet --create --createfromshares, while fewer reveal nothing about it.\nEach share should be kept in a different safe place, as any threshold of them holds the private keys of the wallet. The wallet must be unlocked.\n\nArguments:\n1. threshold (numeric, required) The number of shares recovering the wallet.\n2. count     (numeric, required) The number of shares, at most 16.\n\nResult:\n[\"value\",...] (array of string) The shares, each starting with 'lbcshare1'.\n",
+		"exporttransactions":      "exporttransactions \"format\" (\"startdate\" \"enddate\" account=\"*\" \"filename\")\n\nExports the wallet transactions received in a range of dates for bookkeeping.\nEvery credit and debit is exported with its date, txid, category, amount, fee, label and claim operation, and in CSV the exchange rate recorded when priceurl is set.\nThe fee of a transaction is only included with its first send entry, and written as a separate transaction in OFX and QIF.\n\nArguments:\n1. format    (string, required)              The format of the export: 'csv', 'ofx' or 'qif'\n2. startdate (string, optional)              The first UTC date (YYYY-MM-DD) of the exported transactions, or all earlier transactions if unset\n3. enddate   (string, optional)              The last UTC date (YYYY-MM-DD) of the exported transactions, or all later transactions if unset\n4. account   (string, optional, default=\"*\") The account of the exported transactions, or '*' for all accounts\n5. filename  (string, optional)              Write the export to this new file on the wallet host instead of returning it; existing files are not overwritten\n\nResult:\n{\n \"format\": \"value\",   (string)  The format of the export\n \"entries\": n,        (numeric) The number of exported entries\n \"filename\": \"value\", (string)  The file the export was written to, if a filename was given\n \"data\": \"value\",     (string)  The exported document, if no filename was given\n}                     \n",
+		"exporttxlog":             "exporttxlog (\"filename\")\n\nExports the transactions of the wallet as a log which a fresh wallet created from the same seed replays with --replaytxlog,\nto check that its balance and history are reconstructed the same, such as after a database migration.\n\nArguments:\n1. filename (string, optional) Write the log to this new file on the wallet host instead of returning it; existing files are not overwritten\n\nResult:\n{\n \"transactions\": n,   (numeric) The number of transactions in the log\n \"balance\": n.nnn,    (numeric) The balance of the wallet with one confirmation, in LBC\n \"digest\": \"value\",   (string)  The digest of the history of the wallet, which its replay must reconstruct\n \"filename\": \"value\", (string)  The file the log was written to, if a filename was given\n \"data\": \"value\",     (string)  The log, one JSON object per line, if no filename was given\n}                     \n",
+		"fastforward":             "fastforward seconds\n\nAdvances the wallet clock, so that invoices and output leases expire and the spending limits start a new day without waiting.\nThe clock of lbcd is not changed, so neither are the times of the mined blocks.\nOnly available on regtest with --regtest-harness.\n\nArguments:\n1. seconds (numeric, required) The number of seconds to advance the wallet clock by.\n\nResult:\n{\n \"time\": n,   (numeric) The time of the wallet clock, in seconds since 1 Jan 1970 GMT.\n \"offset\": n, (numeric) The number of seconds the wallet clock is ahead of the system clock.\n}             \n",
+		"fundaddress":             "fundaddress \"address\" amount (blocks=1)\n\nSends an amount from the default account to an address, and mines blocks to the wallet to confirm the transaction.\nOnly available on regtest with --regtest-harness.\n\nArguments:\n1. address (string, required)             The address to send to.\n2. amount  (numeric, required)            The amount to send.\n3. blocks  (numeric, optional, default=1) The number of blocks to mine after sending, which may be zero to leave the transaction unconfirmed.\n\nResult:\n{\n \"txid\": \"value\",         (string)          The hash of the transaction.\n \"blocks\": [\"value\",...], (array of string) The hashes of the mined blocks.\n}                         \n",
+		"generatetowallet":        "generatetowallet numblocks (account=\"default\")\n\nMines blocks whose coinbases pay to a new address of an account, and returns once the wallet has processed them.\nCoinbases are spendable after 100 confirmations.\nOnly available on regtest with --regtest-harness.\n\nArguments:\n1. numblocks (numeric, required)                   The number of blocks to mine.\n2. account   (string, optional, default=\"default\") The account the coinbases pay to.\n\nResult:\n[\"value\",...] (array of string) The hashes of the mined blocks.\n",
+		"getaccountinfo":          "getaccountinfo \"account\" (minconf=1)\n\nReturns the balances and key counts of an account.\n\nArguments:\n1. account (string, required)             The name of the account.\n2. minconf (numeric, optional, default=1) Minimum number of block confirmations required before an output is spendable.\n\nResult:\n{\n \"account\": \"value\",            (string)          The name of the account.\n \"number\": n,                   (numeric)         The number of the account.\n \"addresstypes\": [\"value\",...], (array of string) The address types (legacy, p2sh-segwit or bech32) the account is defined for.\n \"balance\": n.nnn,              (numeric)         The total value of the unspent outputs of the account valued in LBC.\n \"spendable\": n.nnn,            (numeric)         The value of the outputs with at least minconf confirmations valued in LBC.\n \"unconfirmed\": n.nnn,          (numeric)         The value of the outputs with fewer than minconf confirmations valued in LBC.\n \"immature\": n.nnn,             (numeric)         The value of the immature coinbase outputs valued in LBC.\n \"staked\": n.nnn,               (numeric)         The value of the unspent claims, supports and claim updates of the account valued in LBC, which is not part of its balance.\n \"moved\": n.nnn,                (numeric)         The net value moved to the account with movebalance valued in LBC.\n \"ledgerbalance\": n.nnn,        (numeric)         The balance plus the moved value valued in LBC.\n \"externalkeycount\": n,         (numeric)         The number of external (receiving) addresses derived for the account.\n \"internalkeycount\": n,         (numeric)         The number of internal (change) addresses derived for the account.\n \"importedkeycount\": n,         (numeric)         The number of keys imported into the account.\n}                               \n",
 		"getbestblock":            "getbestblock\n\nReturns the hash and height of the newest block in the best chain that wallet has finished syncing with.\n\nArguments:\nNone\n\nResult:\n{\n \"hash\": \"value\", (string)  The hash of the block.\n \"height\": n,     (numeric) The blockchain height of the block.\n}                 \n",
+		"getbackupstatus":         "getbackupstatus\n\nReturns the status of the encrypted backups of the wallet database, which are made every backupinterval.\n\nArguments:\nNone\n\nResult:\n{\n \"enabled\": true|false,     (boolean) Whether the wallet is backed up periodically.\n \"store\": \"value\",          (string)  The directory or S3 URL the backups are written to.\n \"interval\": n,             (numeric) The number of seconds between backups.\n \"lastattempt\": n,          (numeric) The time of the last backup attempt, in seconds since 1 Jan 1970 GMT.\n \"lasterror\": \"value\",      (string)  The error of the last backup attempt, if it failed.\n \"lastbackup\": n,           (numeric) The time of the last successful backup, in seconds since 1 Jan 1970 GMT.\n \"lastbackupname\": \"value\", (string)  The file or object name of the last successful backup.\n \"lastbackupsize\": n,       (numeric) The size in bytes of the last successful backup.\n \"nextbackup\": n,           (numeric) The time of the next backup, in seconds since 1 Jan 1970 GMT.\n}                           \n",
+		"getconnectionstatus":     "getconnectionstatus\n\nReturns the status of the connection to the chain server.\n\nArguments:\nNone\n\nResult:\n{\n \"backend\": \"value\",      (string)  The chain server backend, or empty if there is none.\n \"connected\": true|false, (boolean) Whether the wallet is connected to the chain server.\n \"address\": \"value\",      (string)  The address of the last connection made to lbcd, or the address given to the proxy.\n \"family\": \"value\",       (string)  The address family of the last connection made to lbcd (ipv4 or ipv6), i2p over I2P, or proxy through a proxy.\n}                         \n",
+		"getdecoyaddresses":       "getdecoyaddresses \"account\" count (addresstype=\"legacy\")\n\nDerives and returns a batch of decoy addresses for an account, for use as decoys or test sinks.\nDecoy addresses are derived on a separate branch of the account, which the wallet never watches, so that payments to them are not tracked and not part of the balance.\nThey must never be given out as deposit addresses, and createpaymenturi refuses them.\n\nArguments:\n1. account     (string, required)                   Account name the decoy addresses are derived from.\n2. count       (numeric, required)                  The number of addresses to derive, at most 1000.\n3. addresstype (string, optional, default=\"legacy\") Address type. Must be one of 'legacy', 'p2sh-segwit', or 'bech32'. Default to 'legacy'.\n\nResult:\n[{\n \"address\": \"value\",  (string)  The decoy address, whose payments are not tracked by the wallet.\n \"index\": n,          (numeric) The derivation index of the address on the decoy branch of the account.\n \"path\": \"value\",     (string)  The derivation path of the address from the master key.\n \"decoy\": true|false, (boolean) Always true, marking the address as a decoy which must not be used to receive payments.\n},...]\n",
+		"getdiskstatus":           "getdiskstatus\n\nChecks the free space of the volume holding the wallet database, which is monitored every diskcheckinterval.\n\nArguments:\nNone\n\nResult:\n{\n \"enabled\": true|false, (boolean) Whether the free disk space is monitored.\n \"path\": \"value\",       (string)  The path of the wallet database.\n \"lastcheck\": n,        (numeric) The time of the last check, in seconds since 1 Jan 1970 GMT.\n \"lasterror\": \"value\",  (string)  The error of the last check, if it failed.\n \"dbsize\": n,           (numeric) The size in bytes of the wallet database.\n \"growth\": n,           (numeric) The number of bytes the wallet database grew since the previous check.\n \"free\": n,             (numeric) The free space in bytes of the volume holding the wallet database.\n \"warning\": true|false, (boolean) Whether the free space is below diskwarn.\n \"low\": true|false,     (boolean) Whether the free space is below diskfloor, in which case addresses are not derived, transactions are not created and keys are not imported.\n}                       \n",
+		"getnetworkinfo":          "getnetworkinfo\n\nReturns the parameters of the network of the wallet.\nUnlike the getnetworkinfo method of lbcd, which describes its peers, the network of the wallet is described.\n\nArguments:\nNone\n\nResult:\n{\n \"name\": \"value\",        (string)  The name of the network, as given to the network option.\n \"net\": \"value\",         (string)  The magic bytes of the messages of the network, in hex.\n \"testnet\": true|false,  (boolean) Whether the network is a test network.\n \"genesishash\": \"value\", (string)  The hash of the genesis block of the network.\n \"defaultport\": \"value\", (string)  The default peer-to-peer port of the nodes of the network.\n \"bech32hrp\": \"value\",   (string)  The human-readable part of the segwit addresses of the network.\n \"pubkeyhashaddrid\": n,  (numeric) The version byte of the pay-to-pubkey-hash addresses of the network.\n \"scripthashaddrid\": n,  (numeric) The version byte of the pay-to-script-hash addresses of the network.\n \"privatekeyid\": n,      (numeric) The version byte of the WIF private keys of the network.\n \"hdcointype\": n,        (numeric) The BIP0044 coin type of the keys of the network.\n \"coinbasematurity\": n,  (numeric) The number of blocks before a coinbase output can be spent.\n}                        \n",
+		"getinvoice":              "getinvoice id\n\nReturns an invoice created with createinvoice.\n\nArguments:\n1. id (numeric, required) The ID of the invoice.\n\nResult:\n{\n \"id\": n,            (numeric)         The ID of the invoice.\n \"address\": \"value\", (string)          The address to pay.\n \"uri\": \"value\",     (string)          The BIP0021 payment URI of the invoice.\n \"amount\": n.nnn,    (numeric)         The requested amount valued in LBC.\n \"memo\": \"value\",    (string)          The memo of the invoice.\n \"created\": n,       (numeric)         The time the invoice was created in seconds since 1 Jan 1970 GMT.\n \"expires\": n,       (numeric)         The time the invoice expires in seconds since 1 Jan 1970 GMT.\n \"state\": \"value\",   (string)          The state of the invoice: unpaid, paid, or expired.\n \"received\": n.nnn,  (numeric)         The total value of the payments to the invoice valued in LBC.\n \"paidtime\": n,      (numeric)         The time the invoice was paid in seconds since 1 Jan 1970 GMT.\n \"payments\": [{      (array of object) The transactions paying the invoice, including payments after it was paid or expired.\n  \"txid\": \"value\",   (string)          The hash of the transaction.\n  \"amount\": n.nnn,   (numeric)         The value paid to the invoice by the transaction valued in LBC.\n },...],                               \n}                    \n",
+		"getnewaddresses":         "getnewaddresses \"account\" count (addresstype=\"legacy\")\n\nGenerates and returns a batch of new payment addresses for an account, in the order of their derivation indexes.\nThe addresses are derived at once, so their indexes are sequential.\nAddresses beyond the recovery window of unused addresses are only found again by a recovery with a larger window.\n\nArguments:\n1. account     (string, required)                   Account name the new addresses will belong to.\n2. count       (numeric, required)                  The number of addresses to generate, at most 1000.\n3. addresstype (string, optional, default=\"legacy\") Address type. Must be one of 'legacy', 'p2sh-segwit', or 'bech32'. Default to 'legacy'.\n\nResult:\n[{\n \"address\": \"value\", (string)  The payment address.\n \"index\": n,         (numeric) The derivation index of the address on the external branch of the account.\n},...]\n",
+		"getprivacyreport":        "getprivacyreport\n\nAnalyzes the wallet's own transactions for patterns which link its addresses and accounts together or reveal its change.\nClaim, support and claim update outputs are not counted as address reuse, as a claim update keeps the address of the claim.\n\nArguments:\nNone\n\nResult:\n{\n \"transactions\": n,          (numeric)          The number of wallet transactions analyzed.\n \"reusedaddresses\": [{       (array of object)  The addresses of the wallet paid by more than one transaction, ordered by address.\n  \"address\": \"value\",        (string)           The reused address.\n  \"account\": \"value\",        (string)           The account of the address.\n  \"txids\": [\"value\",...],    (array of string)  The hashes of the transactions paying the address.\n },...],                                        \n \"mergedinputs\": [{          (array of object)  The transactions spending the outputs of more than one account, which links the accounts together.\n  \"txid\": \"value\",           (string)           The hash of the transaction.\n  \"accounts\": [\"value\",...], (array of string)  The accounts whose outputs the transaction spends.\n },...],                                        \n \"roundchange\": [{           (array of object)  The transactions sent by the wallet paying round amounts (multiples of 0.001 LBC) with change that is not round, so that the change is easily identified.\n  \"txid\": \"value\",           (string)           The hash of the transaction.\n  \"change\": [n,...],         (array of numeric) The indexes of the change outputs.\n },...],                                        \n}                            \n",
+		"getreceivedbyaddresses":  "getreceivedbyaddresses [\"address\",...] (minconf=1)\n\nReturns the total amount received by each of a set of addresses, in the order of the addresses.\nThe totals are computed in a single pass over the transaction history, instead of one pass per getreceivedbyaddress request.\n\nArguments:\n1. addresses (array of string, required)    The addresses to total, at most 10000.\n2. minconf   (numeric, optional, default=1) Minimum number of block confirmations required before a transaction output is considered.\n\nResult:\n[{\n \"address\": \"value\", (string)  The payment address.\n \"amount\": n.nnn,    (numeric) The total amount received by the address valued in LBC.\n},...]\n",
+		"getspendpolicy":          "getspendpolicy\n\nReturns the spend policy enforced on every transaction published by the wallet.\n\nArguments:\nNone\n\nResult:\n{\n \"maxtxamount\": n.nnn,       (numeric)         The maximum value a single transaction may pay to external addresses valued in LBC, or 0 if unlimited.\n \"dailylimit\": n.nnn,        (numeric)         The maximum value that may be paid to external addresses per UTC day valued in LBC, or 0 if unlimited.\n \"spenttoday\": n.nnn,        (numeric)         The value paid to external addresses so far today valued in LBC.\n \"allowlist\": [\"value\",...], (array of string) If not empty, the only external addresses the wallet may pay.\n \"denylist\": [\"value\",...],  (array of string) Addresses the wallet must never pay.\n}                            \n",
+		"getuptimestats":          "getuptimestats (days=30)\n\nReturns the availability of the wallet over the last days, as recorded in its database while it runs: its starts, how long it ran connected to the chain server and how far its sync lagged behind.\nThe connection is checked every minute and the sync lag recorded every ten minutes, or when the connection changes.\nThe history is kept for 90 days.\n\nArguments:\n1. days (numeric, optional, default=30) The number of days to return the availability of.\n\nResult:\n{\n \"since\": n,            (numeric)          The start of the period, in seconds since 1 Jan 1970 GMT, which is no earlier than the oldest record of the history.\n \"starts\": [n,...],     (array of numeric) The times the wallet was started, in seconds since 1 Jan 1970 GMT.\n \"uptime\": n,           (numeric)          The number of seconds the wallet ran.\n \"disconnected\": n,     (numeric)          The number of seconds the wallet ran without a connection to the chain server.\n \"availability\": n.nnn, (numeric)          The percentage of the period the wallet ran connected to the chain server.\n \"disconnects\": [{      (array of object)  The periods the wallet ran without a connection to the chain server.\n  \"start\": n,           (numeric)          The time the wallet was found disconnected, in seconds since 1 Jan 1970 GMT.\n  \"end\": n,             (numeric)          The time the wallet was connected again or stopped, in seconds since 1 Jan 1970 GMT, or omitted while still disconnected.\n },...],                                   \n \"maxsynclag\": n,       (numeric)          The largest number of blocks the wallet was synced behind the chain server.\n \"synclag\": [{          (array of object)  The samples of the sync lag.\n  \"time\": n,            (numeric)          The time of the sample, in seconds since 1 Jan 1970 GMT.\n  \"height\": n,          (numeric)          The height of the block the wallet was synced to.\n  \"lag\": n,             (numeric)          The number of blocks the wallet was synced behind the chain server.\n },...],                                   \n}                       \n",
+		"getvaultaddress":         "getvaultaddress \"name\"\n\nReturns a new address paying to a vault.\n\nArguments:\n1. name (string, required) The name of the vault.\n\nResult:\n\"value\" (string) The P2WSH address of the vault.\n",
+		"getwalletactivity":       "getwalletactivity (account=\"*\" blocks=0)\n\nReturns the transactions of an account summed by UTC day or by range of blocks, ordered by time.\nDays are those the transactions were received on, and unmined transactions are in no range of blocks.\nPeriods without transactions are omitted.\n\nArguments:\n1. account (string, optional, default=\"*\") The account to sum the transactions of, or '*' for every account.\n2. blocks  (numeric, optional, default=0)  0 to sum the transactions by day, or the number of blocks of each range of blocks, starting at the genesis block.\n\nResult:\n[{\n \"date\": \"value\",   (string)  The day of a daily period, as YYYY-MM-DD.\n \"startheight\": n,  (numeric) The height of the first block of a range of blocks.\n \"endheight\": n,    (numeric) The height of the last block of a range of blocks.\n \"transactions\": n, (numeric) The number of transactions of the period.\n \"received\": n.nnn, (numeric) The value paid to the wallet, excluding change, valued in LBC.\n \"sent\": n.nnn,     (numeric) The value paid by the wallet, excluding change and fees, valued in LBC.\n \"fees\": n.nnn,     (numeric) The fees paid by the wallet valued in LBC.\n \"claims\": n,       (numeric) The number of claims created.\n \"supports\": n,     (numeric) The number of supports created.\n \"updates\": n,      (numeric) The number of claim updates.\n \"newaddresses\": n, (numeric) The number of addresses receiving their first payment.\n},...]\n",
 		"getunconfirmedbalance":   "getunconfirmedbalance (account=\"default\")\n\nCalculates the unspent output value of all unmined transaction outputs.\n\nArguments:\n1. account (string, optional, default=\"default\") The account name to query the unconfirmed balance for. Default to 'default'.\n\nResult:\nn.nnn (numeric) Total amount of all unmined unspent outputs of the account valued in LBC.\n",
-		"listaddresstransactions": "listaddresstransactions [\"address\",...] (account=\"default\")\n\nReturns a JSON array of objects containing verbose details for wallet transactions pertaining some addresses.\n\nArguments:\n1. addresses (array of string, required)           Addresses to filter transaction results by.\n2. account   (string, optional, default=\"default\") Account to filter transactions results by. Defaults to 'default'.\n\nResult:\n[{\n \"abandoned\": true|false,          (boolean)         Unset.\n \"account\": \"value\",               (string)          The account name associated with the transaction.\n \"address\": \"value\",               (string)          Payment address for a transaction output.\n \"amount\": n.nnn,                  (numeric)         The value of the transaction output valued in LBC.\n \"bip125-replaceable\": \"value\",    (string)          Unset.\n \"blockhash\": \"value\",             (string)          The hash of the block this transaction is mined in, or the empty string if unmined.\n \"blockheight\": n,                 (numeric)         The block height containing the transaction.\n \"blockindex\": n,                  (numeric)         Unset.\n \"blocktime\": n,                   (numeric)         The Unix time of the block header this transaction is mined in, or 0 if unmined.\n \"category\": \"value\",              (string)          The kind of transaction: \"send\" for sent transactions, \"immature\" for immature coinbase outputs, \"generate\" for mature coinbase outputs, or \"recv\" for all other received outputs.  Note: A single output may be included multiple times under different categories\n \"confirmations\": n,               (numeric)         The number of block confirmations of the transaction.\n \"fee\": n.nnn,                     (numeric)         The total input value minus the total output value for sent transactions.\n \"generated\": true|false,          (boolean)         Whether the transaction output is a coinbase output.\n \"involveswatchonly\": true|false,  (boolean)         Unset.\n \"label\": \"value\",                 (string)          A comment for the address/transaction, if any.\n \"time\": n,                        (numeric)         The earliest Unix time this transaction was known to exist.\n \"timereceived\": n,                (numeric)         The earliest Unix time this transaction was known to exist.\n \"trusted\": true|false,            (boolean)         Unset.\n \"txid\": \"value\",                  (string)          The hash of the transaction.\n \"vout\": n,                        (numeric)         The transaction output index.\n \"walletconflicts\": [\"value\",...], (array of string) Unset.\n \"comment\": \"value\",               (string)          Unset.\n \"otheraccount\": \"value\",          (string)          Unset.\n},...]\n",
-		"listalltransactions":     "listalltransactions (account=\"default\")\n\nReturns a JSON array of objects in the same format as 'listtransactions' without limiting the number of returned objects.\n\nArguments:\n1. account (string, optional, default=\"default\") Account to filter transactions results by. Defaults to 'default'.\n\nResult:\n[{\n \"abandoned\": true|false,          (boolean)         Unset.\n \"account\": \"value\",               (string)          The account name associated with the transaction.\n \"address\": \"value\",               (string)          Payment address for a transaction output.\n \"amount\": n.nnn,                  (numeric)         The value of the transaction output valued in LBC.\n \"bip125-replaceable\": \"value\",    (string)          Unset.\n \"blockhash\": \"value\",             (string)          The hash of the block this transaction is mined in, or the empty string if unmined.\n \"blockheight\": n,                 (numeric)         The block height containing the transaction.\n \"blockindex\": n,                  (numeric)         Unset.\n \"blocktime\": n,                   (numeric)         The Unix time of the block header this transaction is mined in, or 0 if unmined.\n \"category\": \"value\",              (string)          The kind of transaction: \"send\" for sent transactions, \"immature\" for immature coinbase outputs, \"generate\" for mature coinbase outputs, or \"recv\" for all other received outputs.  Note: A single output may be included multiple times under different categories\n \"confirmations\": n,               (numeric)         The number of block confirmations of the transaction.\n \"fee\": n.nnn,                     (numeric)         The total input value minus the total output value for sent transactions.\n \"generated\": true|false,          (boolean)         Whether the transaction output is a coinbase output.\n \"involveswatchonly\": true|false,  (boolean)         Unset.\n \"label\": \"value\",                 (string)          A comment for the address/transaction, if any.\n \"time\": n,                        (numeric)         The earliest Unix time this transaction was known to exist.\n \"timereceived\": n,                (numeric)         The earliest Unix time this transaction was known to exist.\n \"trusted\": true|false,            (boolean)         Unset.\n \"txid\": \"value\",                  (string)          The hash of the transaction.\n \"vout\": n,                        (numeric)         The transaction output index.\n \"walletconflicts\": [\"value\",...], (array of string) Unset.\n \"comment\": \"value\",               (string)          Unset.\n \"otheraccount\": \"value\",          (string)          Unset.\n},...]\n",
+		"importlbrycrdwallet":     "importlbrycrdwallet \"filename\" (\"passphrase\" rescan=true)\n\nImports the keys of a wallet.dat file of the lbrycrd daemon as imported keys, which requires the wallet to be unlocked, and rescans the blockchain for their addresses from the block of the creation time of the oldest key.\nThe address book labels of the keys become the names of their imported-key accounts, and the channels claimed by the keys are returned.\n\nArguments:\n1. filename   (string, required)                The path of the wallet.dat file, which must not be in use by lbrycrd.\n2. passphrase (string, optional)                The passphrase of the lbrycrd wallet, when it is encrypted.\n3. rescan     (boolean, optional, default=true) Rescan the blockchain for outputs controlled by the imported keys.\n\nResult:\n{\n \"keys\": n,           (numeric)         The number of keys imported, not counting the keys already in the wallet.\n \"labels\": n,         (numeric)         The number of labels which became imported-key account names.\n \"channels\": [{       (array of object) The unspent channel claims and updates of the lbrycrd wallet paying to its keys.\n  \"claimid\": \"value\", (string)          The claim ID of the channel.\n  \"name\": \"value\",    (string)          The name of the channel.\n  \"address\": \"value\", (string)          The address of the key signing for the channel.\n  \"txid\": \"value\",    (string)          The hash of the transaction of the current claim or update of the channel.\n  \"vout\": n,          (numeric)         The index of the output of the claim or update.\n },...],                                \n \"rescanfrom\": n,     (numeric)         The height of the block the rescan of the imported addresses starts from.\n}                     \n",
+		"importlbrysdkwallet":     "importlbrysdkwallet \"filename\" (\"password\" rescan=true)\n\nImports the accounts of a wallet file of lbry-sdk (lbrynet) as legacy accounts named after them, which requires the wallet to be unlocked, and the certificates of their channels as imported keys, then rescans the blockchain from the genesis block for their addresses.\nThe first 1000 receiving and change addresses of each account are derived.\n\nArguments:\n1. filename (string, required)                The path of the lbry-sdk wallet file, such as ~/.lbryum/wallets/default_wallet.\n2. password (string, optional)                The password of the lbry-sdk wallet, when its accounts are encrypted.\n3. rescan   (boolean, optional, default=true) Rescan the blockchain for outputs controlled by the imported accounts and keys.\n\nResult:\n{\n \"accounts\": [\"value\",...], (array of string) The names of the accounts created.\n \"channels\": [{             (array of object) The channels whose certificates were imported.\n  \"id\": \"value\",            (string)          The claim ID of the channel, or the address of its key for later lbry-sdk versions, as keyed in the wallet file.\n  \"address\": \"value\",       (string)          The address of the imported channel key.\n },...],                                      \n}                           \n",
+		"importwatchpubkey":       "importwatchpubkey \"pubkey\" (addresstype=\"legacy\" startheight)\n\nImports a public key as a watch-only address of the imported account, and returns the address.\nThe chain server is asked at once to notify the transactions paying the address, without restarting the wallet.\nWhen a start height is given, the blocks from it to the tip are rescanned in the background for the earlier transactions of the address.\n\nArguments:\n1. pubkey      (string, required)                   The hex-encoded public key.\n2. addresstype (string, optional, default=\"legacy\") The type of address of the key: legacy, p2sh-segwit, or bech32.\n3. startheight (numeric, optional)                  The block height to rescan from, or none to only watch transactions from now on.\n\nResult:\n\"value\" (string) The imported address.\n",
+		"listaccountclaims":       "listaccountclaims (account=\"*\")\n\nReturns the unspent claims, supports and claim updates paying to the addresses of an account, ordered by account and height.\nChannels are claims too, and the claims signed by a channel report its claim ID as their signing channel.\n\nArguments:\n1. account (string, optional, default=\"*\") Only return the claims of this account, or the claims of every account for \"*\".\n\nResult:\n[{\n \"account\": \"value\",        (string)  The name of the account owning the output.\n \"txid\": \"value\",           (string)  The hash of the transaction.\n \"vout\": n,                 (numeric) The index of the output.\n \"address\": \"value\",        (string)  The address the output pays to.\n \"amount\": n.nnn,           (numeric) The value of the output valued in LBC.\n \"confirmations\": n,        (numeric) The number of block confirmations of the output.\n \"type\": \"value\",           (string)  The claim operation of the output: claim, support or update.\n \"name\": \"value\",           (string)  The name claimed or supported.\n \"claimid\": \"value\",        (string)  The ID of the claim, or of the claim supported or updated.\n \"kind\": \"value\",           (string)  The kind of claim (stream, channel, collection or repost), when its value could be decoded.\n \"signingchannel\": \"value\", (string)  The claim ID of the channel which signed the claim, if any.\n},...]\n",
+		"listaccountinfo":         "listaccountinfo (minconf=1)\n\nReturns the balances and key counts of every account, ordered by account number.\n\nArguments:\n1. minconf (numeric, optional, default=1) Minimum number of block confirmations required before an output is spendable.\n\nResult:\n[{\n \"account\": \"value\",            (string)          The name of the account.\n \"number\": n,                   (numeric)         The number of the account.\n \"addresstypes\": [\"value\",...], (array of string) The address types (legacy, p2sh-segwit or bech32) the account is defined for.\n \"balance\": n.nnn,              (numeric)         The total value of the unspent outputs of the account valued in LBC.\n \"spendable\": n.nnn,            (numeric)         The value of the outputs with at least minconf confirmations valued in LBC.\n \"unconfirmed\": n.nnn,          (numeric)         The value of the outputs with fewer than minconf confirmations valued in LBC.\n \"immature\": n.nnn,             (numeric)         The value of the immature coinbase outputs valued in LBC.\n \"staked\": n.nnn,               (numeric)         The value of the unspent claims, supports and claim updates of the account valued in LBC, which is not part of its balance.\n \"moved\": n.nnn,                (numeric)         The net value moved to the account with movebalance valued in LBC.\n \"ledgerbalance\": n.nnn,        (numeric)         The balance plus the moved value valued in LBC.\n \"externalkeycount\": n,         (numeric)         The number of external (receiving) addresses derived for the account.\n \"internalkeycount\": n,         (numeric)         The number of internal (change) addresses derived for the account.\n \"importedkeycount\": n,         (numeric)         The number of keys imported into the account.\n},...]\n",
+		"listaccountunspent":      "listaccountunspent \"account\" (minconf=1 maxconf=9999999)\n\nReturns the unlocked unspent outputs controlled by the keys of an account.\nThese are the outputs that sendfrom and sendmany may spend when sending from the account.\n\nArguments:\n1. account (string, required)                   The name of the account.\n2. minconf (numeric, optional, default=1)       Minimum number of block confirmations required before a transaction output is considered.\n3. maxconf (numeric, optional, default=9999999) Maximum number of block confirmations required before a transaction output is excluded.\n\nResult:\n[{\n \"txid\": \"value\",         (string)  The transaction hash of the referenced output.\n \"vout\": n,               (numeric) The output index of the referenced output.\n \"address\": \"value\",      (string)  The payment address that received the output.\n \"account\": \"value\",      (string)  The account associated with the receiving payment address.\n \"scriptPubKey\": \"value\", (string)  The output script encoded as a hexadecimal string.\n \"redeemScript\": \"value\", (string)  Unset.\n \"amount\": n.nnn,         (numeric) The amount of the output valued in LBC.\n \"confirmations\": n,      (numeric) The number of block confirmations of the transaction.\n \"solvable\": true|false,  (boolean) Whether the output is solvable.\n \"spendable\": true|false, (boolean) Whether the output is entirely controlled by wallet keys/scripts (false for partially controlled multisig outputs or outputs to watch-only addresses).\n \"isstake\": true|false,   (boolean) Whether the output is staked.\n \"tainted\": true|false,   (boolean) Whether the output, or the address it pays to, is tainted, so that coin selection never chooses it.\n \"taintreason\": \"value\",  (string)  The reason the output or its address was tainted, if any.\n},...]\n",
+		"listaddresstransactions": "listaddresstransactions [\"address\",...] (account=\"default\")\n\nReturns a JSON array of objects containing verbose details for wallet transactions pertaining some addresses.\n\nArguments:\n1. addresses (array of string, required)           Addresses to filter transaction results by.\n2. account   (string, optional, default=\"default\") Account to filter transactions results by. Defaults to 'default'.\n\nResult:\n[{\n \"abandoned\": true|false,          (boolean)         Unset.\n \"account\": \"value\",               (string)          The account name associated with the transaction.\n \"address\": \"value\",               (string)          Payment address for a transaction output.\n \"amount\": n.nnn,                  (numeric)         The value of the transaction output valued in LBC.\n \"bip125-replaceable\": \"value\",    (string)          Unset.\n \"blockhash\": \"value\",             (string)          The hash of the block this transaction is mined in, or the empty string if unmined.\n \"blockheight\": n,                 (numeric)         The block height containing the transaction.\n \"blockindex\": n,                  (numeric)         Unset.\n \"blocktime\": n,                   (numeric)         The Unix time of the block header this transaction is mined in, or 0 if unmined.\n \"category\": \"value\",              (string)          The kind of transaction: \"send\" for sent transactions, \"immature\" for immature coinbase outputs, \"generate\" for mature coinbase outputs, or \"recv\" for all other received outputs.  Note: A single output may be included multiple times under different categories\n \"confirmations\": n,               (numeric)         The number of block confirmations of the transaction.\n \"fee\": n.nnn,                     (numeric)         The total input value minus the total output value for sent transactions.\n \"generated\": true|false,          (boolean)         Whether the transaction output is a coinbase output.\n \"involveswatchonly\": true|false,  (boolean)         Unset.\n \"label\": \"value\",                 (string)          A comment for the address/transaction, if any.\n \"time\": n,                        (numeric)         The earliest Unix time this transaction was known to exist.\n \"timereceived\": n,                (numeric)         The earliest Unix time this transaction was known to exist.\n \"trusted\": true|false,            (boolean)         Unset.\n \"txid\": \"value\",                  (string)          The hash of the transaction.\n \"vout\": n,                        (numeric)         The transaction output index.\n \"walletconflicts\": [\"value\",...], (array of string) Unset.\n \"comment\": \"value\",               (string)          The comment the transaction was sent with, if any.\n \"to\": \"value\",                    (string)          The comment naming the recipient the transaction was sent with, if any.\n \"otheraccount\": \"value\",          (string)          Unset.\n \"fiatcurrency\": \"value\",          (string)          The currency of the recorded exchange rate (only if a rate was recorded)\n \"fiatrate\": n.nnn,                (numeric)         The price of one LBC in the currency when the transaction was received (only if a rate was recorded)\n \"fiatamount\": n.nnn,              (numeric)         The amount in the currency at the recorded exchange rate (only if a rate was recorded)\n},...]\n",
+		"listalltransactions":     "listalltransactions (account=\"default\")\n\nReturns a JSON array of objects in the same format as 'listtransactions' without limiting the number of returned objects.\n\nArguments:\n1. account (string, optional, default=\"default\") Account to filter transactions results by. Defaults to 'default'.\n\nResult:\n[{\n \"abandoned\": true|false,          (boolean)         Unset.\n \"account\": \"value\",               (string)          The account name associated with the transaction.\n \"address\": \"value\",               (string)          Payment address for a transaction output.\n \"amount\": n.nnn,                  (numeric)         The value of the transaction output valued in LBC.\n \"bip125-replaceable\": \"value\",    (string)          Unset.\n \"blockhash\": \"value\",             (string)          The hash of the block this transaction is mined in, or the empty string if unmined.\n \"blockheight\": n,                 (numeric)         The block height containing the transaction.\n \"blockindex\": n,                  (numeric)         Unset.\n \"blocktime\": n,                   (numeric)         The Unix time of the block header this transaction is mined in, or 0 if unmined.\n \"category\": \"value\",              (string)          The kind of transaction: \"send\" for sent transactions, \"immature\" for immature coinbase outputs, \"generate\" for mature coinbase outputs, or \"recv\" for all other received outputs.  Note: A single output may be included multiple times under different categories\n \"confirmations\": n,               (numeric)         The number of block confirmations of the transaction.\n \"fee\": n.nnn,                     (numeric)         The total input value minus the total output value for sent transactions.\n \"generated\": true|false,          (boolean)         Whether the transaction output is a coinbase output.\n \"involveswatchonly\": true|false,  (boolean)         Unset.\n \"label\": \"value\",                 (string)          A comment for the address/transaction, if any.\n \"time\": n,                        (numeric)         The earliest Unix time this transaction was known to exist.\n \"timereceived\": n,                (numeric)         The earliest Unix time this transaction was known to exist.\n \"trusted\": true|false,            (boolean)         Unset.\n \"txid\": \"value\",                  (string)          The hash of the transaction.\n \"vout\": n,                        (numeric)         The transaction output index.\n \"walletconflicts\": [\"value\",...], (array of string) Unset.\n \"comment\": \"value\",               (string)          The comment the transaction was sent with, if any.\n \"to\": \"value\",                    (string)          The comment naming the recipient the transaction was sent with, if any.\n \"otheraccount\": \"value\",          (string)          Unset.\n \"fiatcurrency\": \"value\",          (string)          The currency of the recorded exchange rate (only if a rate was recorded)\n \"fiatrate\": n.nnn,                (numeric)         The price of one LBC in the currency when the transaction was received (only if a rate was recorded)\n \"fiatamount\": n.nnn,              (numeric)         The amount in the currency at the recorded exchange rate (only if a rate was recorded)\n},...]\n",
+		"listbalancemoves":        "listbalancemoves (account=\"*\")\n\nReturns the moves recorded with movebalance in the order they were recorded.\n\nArguments:\n1. account (string, optional, default=\"*\") Only return the moves from or to this account, or all moves for \"*\".\n\nResult:\n[{\n \"id\": n,                (numeric) The ID of the move.\n \"time\": n,              (numeric) The time the move was recorded in seconds since the epoch.\n \"fromaccount\": \"value\", (string)  The account the value was moved from.\n \"toaccount\": \"value\",   (string)  The account the value was moved to.\n \"amount\": n.nnn,        (numeric) The moved value in LBC.\n \"comment\": \"value\",     (string)  The comment recorded with the move.\n},...]\n",
+		"listdescriptors":         "listdescriptors\n\nReturns the output script descriptors, with checksums, of the external and internal branches of every HD account of every key scope, from which descriptor-aware wallets such as Bitcoin Core recreate watch-only copies of the accounts.\nThe keys are extended public keys with the key origin: the fingerprint of the master key and the hardened derivation path of the account.\n\nArguments:\nNone\n\nResult:\n{\n \"descriptors\": [{        (array of object)  The descriptors of the accounts.\n  \"desc\": \"value\",        (string)           The descriptor with its checksum.\n  \"timestamp\": n,         (numeric)          The birthday of the wallet, from which the blockchain is scanned for the descriptor, in seconds since 1 Jan 1970 GMT.\n  \"active\": true|false,   (boolean)          Whether the wallet hands out addresses of the descriptor, which is always true.\n  \"internal\": true|false, (boolean)          Whether the descriptor is of the internal (change) branch of the account.\n  \"range\": [n,...],       (array of numeric) The first and last index of the addresses derived by the wallet, up to the next address to be handed out.\n  \"next\": n,              (numeric)          The index of the next address to be handed out.\n  \"account\": \"value\",     (string)           The name of the account.\n },...],                                     \n}                         \n",
+		"listimportedaccounts":    "listimportedaccounts\n\nReturns every imported-key account with its addresses and balance, ordered by name.\nKeys imported without an account belong to the 'imported' account.\n\nArguments:\nNone\n\nResult:\n[{\n \"account\": \"value\",         (string)          The name of the imported-key account.\n \"addresses\": [\"value\",...], (array of string) The addresses of the keys of the account.\n \"balance\": n.nnn,           (numeric)         The value of the unspent outputs paying to the addresses valued in LBC, including unconfirmed outputs.\n},...]\n",
+		"listinvoices":            "listinvoices (\"state\")\n\nReturns the invoices created with createinvoice in the order they were created.\n\nArguments:\n1. state (string, optional) Only return invoices in this state: unpaid, paid, or expired.\n\nResult:\n[{\n \"id\": n,            (numeric)         The ID of the invoice.\n \"address\": \"value\", (string)          The address to pay.\n \"uri\": \"value\",     (string)          The BIP0021 payment URI of the invoice.\n \"amount\": n.nnn,    (numeric)         The requested amount valued in LBC.\n \"memo\": \"value\",    (string)          The memo of the invoice.\n \"created\": n,       (numeric)         The time the invoice was created in seconds since 1 Jan 1970 GMT.\n \"expires\": n,       (numeric)         The time the invoice expires in seconds since 1 Jan 1970 GMT.\n \"state\": \"value\",   (string)          The state of the invoice: unpaid, paid, or expired.\n \"received\": n.nnn,  (numeric)         The total value of the payments to the invoice valued in LBC.\n \"paidtime\": n,      (numeric)         The time the invoice was paid in seconds since 1 Jan 1970 GMT.\n \"payments\": [{      (array of object) The transactions paying the invoice, including payments after it was paid or expired.\n  \"txid\": \"value\",   (string)          The hash of the transaction.\n  \"amount\": n.nnn,   (numeric)         The value paid to the invoice by the transaction valued in LBC.\n },...],                               \n},...]\n",
+		"listtainted":             "listtainted\n\nReturns the outputs and addresses tainted with taintunspent and taintaddresses.\n\nArguments:\nNone\n\nResult:\n{\n \"outputs\": [{        (array of object) The tainted outputs, ordered by transaction hash and output index.\n  \"txid\": \"value\",    (string)          The hash of the transaction.\n  \"vout\": n,          (numeric)         The index of the output.\n  \"reason\": \"value\",  (string)          The reason the output was tainted.\n },...],                                \n \"addresses\": [{      (array of object) The tainted addresses, ordered by address.\n  \"address\": \"value\", (string)          The tainted address.\n  \"reason\": \"value\",  (string)          The reason the address was tainted.\n },...],                                \n}                     \n",
+		"listvaults":              "listvaults\n\nReturns every vault with its balances.\n\nArguments:\nNone\n\nResult:\n[{\n \"name\": \"value\",           (string)  The name of the vault.\n \"account\": n,              (numeric) The number of the account of the vault.\n \"recoverypubkey\": \"value\", (string)  The hex-encoded recovery public key of the vault.\n \"delay\": n,                (numeric) The number of confirmations the outputs of the vault must have before its keys spend them.\n \"balance\": n.nnn,          (numeric) The total value of the unspent outputs of the vault valued in LBC.\n \"spendable\": n.nnn,        (numeric) The value of the unspent outputs of the vault spendable by its keys valued in LBC.\n},...]\n",
+		"listwallets":             "listwallets\n\nReturns the names of the loaded wallets.\nThe default wallet is named by the empty string, and the other wallets are used by HTTP POST requests to /wallet/<name>.\n\nArguments:\nNone\n\nResult:\n[\"value\",...] (array of string) The names of the loaded wallets.\n",
+		"listwithdrawals":         "listwithdrawals (\"state\")\n\nReturns the withdrawals, the sends paying more than the withdrawal threshold, in the order they were requested, with the history of their requests, approvals and sends.\nWithdrawals without their approvals expire, and are kept with the sent and canceled withdrawals as the audit trail of the wallet.\n\nArguments:\n1. state (string, optional) Only return the withdrawals in this state: 'pending', 'approved', 'sent', 'canceled' or 'expired'.\n\nResult:\n[{\n \"id\": n,                    (numeric)         The ID of the withdrawal.\n \"state\": \"value\",           (string)          The state of the withdrawal: 'pending' until it has its approvals, 'approved' until it is sent, 'sent', 'canceled' or 'expired'.\n \"amount\": n.nnn,            (numeric)         The total paid by the withdrawal valued in LBC.\n \"outputs\": [{               (array of object) The outputs paid by the withdrawal.\n  \"address\": \"value\",        (string)          The address paid by the output.\n  \"amount\": n.nnn,           (numeric)         The value of the output valued in LBC.\n },...],                                       \n \"account\": n,               (numeric)         The number of the account the withdrawal is paid from.\n \"requester\": \"value\",       (string)          The RPC user who requested the withdrawal.\n \"approvers\": [\"value\",...], (array of string) The approvers who approved the withdrawal, in the order they approved it.\n \"required\": n,              (numeric)         The number of approvals the withdrawal needs.\n \"created\": n,               (numeric)         The time the withdrawal was requested, in seconds since 1 Jan 1970 GMT.\n \"expires\": n,               (numeric)         The time the withdrawal expires unless approved, in seconds since 1 Jan 1970 GMT.\n \"txid\": \"value\",            (string)          The hash of the transaction of the sent withdrawal.\n \"history\": [{               (array of object) The events of the withdrawal, in the order they happened.\n  \"time\": n,                 (numeric)         The time of the event, in seconds since 1 Jan 1970 GMT.\n  \"action\": \"value\",         (string)          What happened: 'request', 'approve', 'send', 'fail', 'cancel' or 'expire'.\n  \"user\": \"value\",           (string)          The RPC user who requested, approved or canceled the withdrawal.\n  \"detail\": \"value\",         (string)          The hash of the sent transaction, or the error of a failed send.\n },...],                                       \n},...]\n",
+		"loadwallet":              "loadwallet \"walletname\"\n\nLoads a named wallet from the wallets directory of the network directory, and unlocks it with the default or configured passphrase.\n\nArguments:\n1. walletname (string, required) The name of the wallet.\n\nResult:\n{\n \"name\": \"value\",    (string) The name of the loaded wallet.\n \"warning\": \"value\", (string) Warnings raised while loading the wallet, if any.\n}                    \n",
+		"movebalance":             "movebalance \"fromaccount\" \"toaccount\" amount (\"comment\")\n\nRecords a move of value from the ledger balance of an account to another, as the move command of Bitcoin Core did.\nNo transaction is created and no fee is paid: the unspent outputs and spendable balances of both accounts are unchanged.\nMoves are kept as an audit trail, returned by listbalancemoves, and summed in the ledger balances of getaccountinfo.\nThe ledger balance of the source account may become negative.\n\nArguments:\n1. fromaccount (string, required)  The account to move the value from.\n2. toaccount   (string, required)  The account to move the value to.\n3. amount      (numeric, required) The value to move in LBC.\n4. comment     (string, optional)  A comment recorded with the move.\n\nResult:\n{\n \"id\": n,                (numeric) The ID of the move.\n \"time\": n,              (numeric) The time the move was recorded in seconds since the epoch.\n \"fromaccount\": \"value\", (string)  The account the value was moved from.\n \"toaccount\": \"value\",   (string)  The account the value was moved to.\n \"amount\": n.nnn,        (numeric) The moved value in LBC.\n \"comment\": \"value\",     (string)  The comment recorded with the move.\n}                        \n",
+		"notifyconfirmations":     "notifyconfirmations \"txid\" confirmations (\"url\")\n\nRegisters a notification sent once a wallet transaction has been mined with at least the given number of confirmations.\nWebsocket clients are sent a txconfirmed notification unless a webhook URL is given, which HTTP POST clients must do.\nWebhooks are sent the confirmation as a JSON object in an HTTP POST request.\nEach registration is notified once, and those of websocket clients are removed when they disconnect.\n\nArguments:\n1. txid          (string, required)  The hash of the transaction.\n2. confirmations (numeric, required) The number of confirmations to notify at.\n3. url           (string, optional)  The http or https URL to POST the notification to instead of the websocket connection.\n\nResult:\nn.nnn (numeric) The id of the registration, included in its notification.\n",
+		"publishpsbt":             "publishpsbt \"psbt\"\n\nPublishes the transaction of a PSBT signed by signpsbt, after checking its signatures, and unlocks the outputs it spends.\n\nArguments:\n1. psbt (string, required) The base64-encoded signed PSBT.\n\nResult:\n\"value\" (string) The transaction hash of the sent transaction.\n",
+		"recovervault":            "recovervault \"name\" \"recoveryprivkey\" \"address\" (feerate)\n\nSweeps every unspent output of a vault, confirmed or not, to an address with the recovery key of the vault.\n\nArguments:\n1. name            (string, required)  The name of the vault.\n2. recoveryprivkey (string, required)  The recovery private key of the vault encoded in WIF.\n3. address         (string, required)  The address to sweep the outputs to.\n4. feerate         (numeric, optional) The fee rate in LBC/kB, defaulting to the minimum relay fee.\n\nResult:\n\"value\" (string) The hash of the sweeping transaction.\n",
 		"renameaccount":           "renameaccount \"oldaccount\" \"newaccount\"\n\nRenames an account.\n\nArguments:\n1. oldaccount (string, required) The old account name to rename.\n2. newaccount (string, required) The new name for the account.\n\nResult:\nNothing\n",
+		"requesttestcoins":        "requesttestcoins (amount account=\"default\" wait=false)\n\nRequests test coins from the faucet configured with --faucet to a new address of an account, and optionally waits until the deposit is received.\nRefused on mainnet.\n\nArguments:\n1. amount  (numeric, optional)                   The amount to request valued in LBC, or the amount of the faucet's choice when omitted.\n2. account (string, optional, default=\"default\") The account of the new address.\n3. wait    (boolean, optional, default=false)    Whether to wait until the deposit is received, confirmed or not.\n\nResult:\n{\n \"address\": \"value\", (string)  The address paid by the faucet.\n \"amount\": n.nnn,    (numeric) The amount requested valued in LBC, omitted when left to the faucet.\n \"txid\": \"value\",    (string)  The hash of the transaction paying the address, when returned by the faucet.\n \"received\": n.nnn,  (numeric) The amount received by the address valued in LBC, which is only waited for with wait.\n}                    \n",
 		"rescanblockchain":        "rescanblockchain (startheight=0 stopheight)\n\nRenames an account.\n\nArguments:\n1. startheight (numeric, optional, default=0) Block height where the rescan should start.\n2. stopheight  (numeric, optional)            The last block height that should be scanned. If none is provided it will rescan up to the tip at return time of this call.\n\nResult:\n{\n \"start_height\": n, (numeric) The block height where the rescan started (the requested height or 0)\n \"stop_height\": n,  (numeric) The height of the last rescanned block.\n}                   \n",
+		"rescanimportedaccount":   "rescanimportedaccount \"account\" (startheight=0)\n\nRescans the blockchain for the transactions of the addresses of an imported-key account, and returns once the rescan completes.\n\nArguments:\n1. account     (string, required)             The name of the imported-key account.\n2. startheight (numeric, optional, default=0) The block height to rescan from.\n\nResult:\nNothing\n",
+		"sendfromvault":           "sendfromvault \"name\" {\"address\":amount,...} (feerate)\n\nPays addresses from the outputs of a vault which have the confirmations of its delay, and pays the change back to the vault.\n\nArguments:\n1. name    (string, required) The name of the vault.\n2. amounts (object, required) Pairs of payment addresses and the output amount to pay each.\n{\n \"Address to pay.\": Amount to send to the payment address valued in LBC., (object) JSON object using payment addresses as keys and output amounts valued in LBC to send to each address.\n ...\n}\n3. feerate (numeric, optional) The fee rate in LBC/kB, defaulting to the minimum relay fee.\n\nResult:\n\"value\" (string) The hash of the transaction.\n",
+		"setimportedaccount":      "setimportedaccount \"address\" \"account\"\n\nMoves the address of an imported key to an imported-key account, which is created if it has no address yet.\nMoving an address to the 'imported' account removes it from its named account.\n\nArguments:\n1. address (string, required) The address of the imported key.\n2. account (string, required) The imported-key account, which must not name an HD account.\n\nResult:\nNothing\n",
+		"setspendpolicy":          "setspendpolicy (maxtxamount dailylimit [\"allowlist\",...] [\"denylist\",...] \"passphrase\")\n\nUpdates the spend policy enforced on every transaction published by the wallet.\nOnly outputs paying addresses the wallet does not hold the keys of, including its watch-only addresses, are subject to the policy.\nTransactions can't be sent with sendrawtransaction while a policy is set.\nOmitted fields keep their current value.\nRaising or removing a limit, adding addresses to or clearing the allowlist, and removing addresses from the denylist require the wallet passphrase.\n\nArguments:\n1. maxtxamount (numeric, optional)         The maximum value a single transaction may pay to external addresses valued in LBC, or 0 to remove the limit.\n2. dailylimit  (numeric, optional)         The maximum value that may be paid to external addresses per UTC day valued in LBC, or 0 to remove the limit.\n3. allowlist   (array of string, optional) If not empty, the only external addresses the wallet may pay.\n4. denylist    (array of string, optional) Addresses the wallet must never pay.\n5. passphrase  (string, optional)          The wallet passphrase, required to loosen the policy.\n\nResult:\nNothing\n",
+		"signpsbt":                "signpsbt \"psbt\"\n\nSigns the inputs of a PSBT spending outputs of the keys of the wallet, such as one returned by a send of the watch-only copy of the wallet, and finalizes them.\nThe keys are derived from the BIP32 derivation paths of the inputs, so the wallet need not have seen their addresses.\nInputs already signed and those of other keys are left as they are.\n\nArguments:\n1. psbt (string, required) The base64-encoded PSBT.\n\nResult:\n{\n \"psbt\": \"value\",        (string)  The base64-encoded PSBT with the inputs signed, to be sent with publishpsbt of the online wallet.\n \"signed\": n,            (numeric) The number of inputs signed.\n \"complete\": true|false, (boolean) Whether every input of the PSBT is signed.\n}                        \n",
+		"sweepaccountpsbt":        "sweepaccountpsbt \"account\" \"address\" (minconf=1 feerate)\n\nCreates an unsigned PSBT spending every eligible output of an account to a single output paying an address, less the fee.\nThe inputs include their UTXOs and BIP32 derivation paths, so the PSBT can be signed offline by the holder of the account's keys, such as a watch-only account rebuilt with --recoverxpubs.\nThe inputs are not locked.\n\nArguments:\n1. account (string, required)             The account to sweep.\n2. address (string, required)             The address paid by the sweep.\n3. minconf (numeric, optional, default=1) Minimum number of block confirmations of the outputs to sweep.\n4. feerate (numeric, optional)            The fee rate in LBC/kB, defaulting to the minimum relay fee.\n\nResult:\n{\n \"psbt\": \"value\", (string)  The base64-encoded unsigned PSBT.\n \"fee\": n.nnn,    (numeric) The fee of the transaction valued in LBC.\n \"amount\": n.nnn, (numeric) The amount paid to the address valued in LBC.\n}                 \n",
+		"taintaddresses":          "taintaddresses taint [\"address\",...] (reason=\"\")\n\nTaints every output paying to addresses, including those received later, or clears the taint of the addresses.\nOutputs paying to tainted addresses are never chosen by coin selection, and are only spent by transactions which explicitly spend them.\n\nArguments:\n1. taint     (boolean, required)            True to taint the addresses, false to clear their taint.\n2. addresses (array of string, required)    The addresses to taint or clear.\n3. reason    (string, optional, default=\"\") The reason the addresses are tainted.\n\nResult:\ntrue|false (boolean) The boolean 'true'.\n",
+		"taintunspent":            "taintunspent taint [{\"txid\":\"value\",\"vout\":n},...] (reason=\"\")\n\nTaints unspent outputs, for instance those received in a dust attack, or clears their taint.\nTainted outputs are never chosen by coin selection, and are only spent by transactions which explicitly spend them, such as raw or PSBT transactions with their inputs set.\nUnlike locked outputs, taints are kept in the wallet database across restarts.\n\nArguments:\n1. taint        (boolean, required)         True to taint the outputs, false to clear their taint.\n2. transactions (array of object, required) The outputs to taint or clear.\n[{\n \"txid\": \"value\", (string)  The transaction hash of the referenced output.\n \"vout\": n,       (numeric) The output index of the referenced output.\n},...]\n3. reason (string, optional, default=\"\") The reason the outputs are tainted.\n\nResult:\ntrue|false (boolean) The boolean 'true'.\n",
+		"stopnotifyconfirmations": "stopnotifyconfirmations id\n\nRemoves a registration made with notifyconfirmations before it is notified.\n\nArguments:\n1. id (numeric, required) The id returned by notifyconfirmations.\n\nResult:\nNothing\n",
+		"unloadwallet":            "unloadwallet (\"walletname\")\n\nUnloads a named wallet once the requests being handled by it have finished.\nThe wallet is locked and its database is closed, so its files can be copied or loaded by another process.\nThe default wallet can not be unloaded.\n\nArguments:\n1. walletname (string, optional) The name of the wallet, which defaults to the wallet of the request path.\n\nResult:\nNothing\n",
+		"unwatchaddresses":        "unwatchaddresses [\"address\",...]\n\nStops sending watchedaddresstx notifications for addresses registered with watchaddresses.\nThis method is only available to websocket clients.\n\nArguments:\n1. addresses (array of string, required) The addresses to stop watching.\n\nResult:\nNothing\n",
+		"walletgetdata":           "walletgetdata \"namespace\" (\"key\")\n\nReturns a value stored with walletsetdata, or every key and value of a namespace.\n\nArguments:\n1. namespace (string, required) The namespace of the application data.\n2. key       (string, optional) The key to return the value of.\n\nResult (key specified):\n\"value\" (string) The value of the key.\n\nResult (key omitted):\n{\n \"The key\": The value of the key, (object) JSON object of the keys and values of the namespace.\n ...\n}\n",
 		"walletislocked":          "walletislocked\n\nReturns whether or not the wallet is locked.\n\nArguments:\nNone\n\nResult:\ntrue|false (boolean) Whether the wallet is locked.\n",
+		"walletsetdata":           "walletsetdata \"namespace\" \"key\" (\"value\")\n\nStores a value in a namespace of the application data kept in the wallet database, where applications persist their own metadata.\nNamespaces and keys are at most 255 bytes and values at most 65536 bytes.\n\nArguments:\n1. namespace (string, required) The namespace of the application data, such as the name of the application.\n2. key       (string, required) The key to set.\n3. value     (string, optional) The value of the key, or omitted to remove the key.\n\nResult:\nNothing\n",
+		"watchaddresses":          "watchaddresses [\"address\",...]\n\nRegisters addresses, which need not belong to the wallet, with the chain server and sends a watchedaddresstx notification to this client for every transaction paying them.\nTransactions are notified when they are accepted to the mempool and again when they are mined.\nThey are not recorded by the wallet unless they are otherwise relevant to it.\nAddresses are watched until unwatchaddresses is called or the client disconnects.\nThis method is only available to websocket clients.\n\nArguments:\n1. addresses (array of string, required) The addresses to watch.\n\nResult:\nNothing\n",
 	}
 }
 
@@ -57,4 +130,4 @@ var localeHelpDescs = map[string]func() map[string]string{
 	"en_US": helpDescsEnUS,
 }
 
-var requestUsages = "addmultisigaddress nrequired [\"key\",...] (\"account\")\ncreatemultisig nrequired [\"key\",...]\ndumpprivkey \"address\"\ngetaccount \"address\"\ngetaccountaddress (account=\"default\" addresstype=\"legacy\")\ngetaddressesbyaccount (account=\"default\" addresstype=\"*\")\ngetaddressinfo \"address\"\ngetbalance (account=\"default\" minconf=1 addresstype=\"*\")\ngetbestblockhash\ngetblockcount\ngetinfo\ngetnewaddress (account=\"default\" addresstype=\"legacy\")\ngetrawchangeaddress (account=\"default\" addresstype=\"legacy\")\ngetreceivedbyaccount (account=\"default\" minconf=1)\ngetreceivedbyaddress \"address\" (minconf=1)\ngettransaction \"txid\" (includewatchonly=false)\nhelp (\"command\")\nimportprivkey \"privkey\" (\"label\" rescan=true)\nkeypoolrefill (newsize=100)\nlistaccounts (minconf=1 addresstype=\"*\")\nlistlockunspent\nlistreceivedbyaccount (minconf=1 includeempty=false includewatchonly=false)\nlistreceivedbyaddress (minconf=1 includeempty=false includewatchonly=false)\nlistsinceblock (\"blockhash\" targetconfirmations=1 includewatchonly=false)\nlisttransactions (account=\"default\" count=10 from=0 includewatchonly=false)\nlistunspent (minconf=1 maxconf=9999999 [\"address\",...])\nlockunspent unlock [{\"txid\":\"value\",\"vout\":n},...]\nsendfrom \"fromaccount\" \"toaddress\" amount (minconf=1 addresstype=\"*\" \"comment\" \"commentto\")\nsendmany \"fromaccount\" {\"address\":amount,...} (minconf=1 addresstype=\"*\" \"comment\")\nsendtoaddress \"address\" amount (addresstype=\"*\" \"comment\" \"commentto\")\nsettxfee amount\nsignmessage \"address\" \"message\"\nsignrawtransaction \"rawtx\" ([{\"txid\":\"value\",\"vout\":n,\"scriptpubkey\":\"value\",\"redeemscript\":\"value\"},...] [\"privkey\",...] flags=\"ALL\")\nvalidateaddress \"address\"\nverifymessage \"address\" \"signature\" \"message\"\nwalletlock\nwalletpassphrase \"passphrase\" timeout\nwalletpassphrasechange \"oldpassphrase\" \"newpassphrase\"\ncreatenewaccount \"account\"\ngetbestblock\ngetunconfirmedbalance (account=\"default\")\nlistaddresstransactions [\"address\",...] (account=\"default\")\nlistalltransactions (account=\"default\")\nrenameaccount \"oldaccount\" \"newaccount\"\nrescanblockchain (startheight=0 stopheight)\nwalletislocked"
+var requestUsages = "addmultisigaddress nrequired [\"key\",...] (\"account\")\ncreatemultisig nrequired [\"key\",...]\ndumpprivkey \"address\"\ndumpwallet \"filename\"\ngetaccount \"address\"\ngetaccountaddress (account=\"default\" addresstype=\"legacy\")\ngetaddressesbyaccount (account=\"default\" addresstype=\"*\")\ngetaddressinfo \"address\"\ngetbalance (account=\"default\" minconf=1 addresstype=\"*\")\ngetbestblockhash\ngetblockcount\ngetinfo\ngetwalletinfo\ngetnewaddress (account=\"default\" addresstype=\"legacy\")\ngetrawchangeaddress (account=\"default\" addresstype=\"legacy\")\ngetreceivedbyaccount (account=\"default\" minconf=1)\ngetreceivedbyaddress \"address\" (minconf=1)\ngettransaction \"txid\" (includewatchonly=false)\nhelp (\"command\")\nimportprivkey \"privkey\" (\"label\" rescan=true)\nimportwallet \"filename\"\nkeypoolrefill (newsize=100)\nlistaccounts (minconf=1 addresstype=\"*\")\nlistlockunspent\nlistreceivedbyaccount (minconf=1 includeempty=false includewatchonly=false)\nlistreceivedbyaddress (minconf=1 includeempty=false includewatchonly=false)\nlistsinceblock (\"blockhash\" targetconfirmations=1 includewatchonly=false)\nlisttransactions (account=\"default\" count=10 from=0 includewatchonly=false)\nlistunspent (minconf=1 maxconf=9999999 [\"address\",...])\nlockunspent unlock [{\"txid\":\"value\",\"vout\":n},...]\nsendfrom \"fromaccount\" \"toaddress\" amount (minconf=1 addresstype=\"*\" \"comment\" \"commentto\")\nsendmany \"fromaccount\" {\"address\":amount,...} (minconf=1 addresstype=\"*\" \"comment\")\nsendtoaddress \"address\" amount (addresstype=\"*\" \"comment\" \"commentto\")\nsettxfee amount\nsignmessage \"address\" \"message\"\nsignrawtransaction \"rawtx\" ([{\"txid\":\"value\",\"vout\":n,\"scriptpubkey\":\"value\",\"redeemscript\":\"value\"},...] [\"privkey\",...] flags=\"ALL\")\nvalidateaddress \"address\"\nverifymessage \"address\" \"signature\" \"message\"\nwalletlock\nwalletpassphrase \"passphrase\" timeout\nwalletpassphrasechange \"oldpassphrase\" \"newpassphrase\"\napprovewithdrawal id\nbenchsigning (duration=4 inputs=2)\ncancelsend \"handle\"\ncancelwithdrawal id\nchangepublicpassphrase \"oldpassphrase\" \"newpassphrase\"\nclonewallet \"destination\"\ncollectdebuginfo\nconfirmsend \"handle\"\ncreateinvoice amount (\"memo\" expiry=3600 account=\"default\")\ncreatenewaccount \"account\"\ncreatepaymenturi \"address\" (amount \"label\" \"message\" qrcode=false)\ncreatevault \"name\" \"recoverypubkey\" delay\ndecodepaymenturi \"uri\"\ndiagnosetransaction \"txid\"\ndumpgoroutines\ndumpheapprofile (gc=false)\ndumpimportedaccount \"account\"\nexportseedshares threshold count\nexporttransactions \"format\" (\"startdate\" \"enddate\" account=\"*\" \"filename\")\nexporttxlog (\"filename\")\nfastforward seconds\nfundaddress \"address\" amount (blocks=1)\ngeneratetowallet numblocks (account=\"default\")\ngetaccountinfo \"account\" (minconf=1)\ngetbestblock\ngetbackupstatus\ngetconnectionstatus\ngetdecoyaddresses \"account\" count (addresstype=\"legacy\")\ngetdiskstatus\ngetnetworkinfo\ngetinvoice id\ngetnewaddresses \"account\" count (addresstype=\"legacy\")\ngetprivacyreport\ngetreceivedbyaddresses [\"address\",...] (minconf=1)\ngetspendpolicy\ngetuptimestats (days=30)\ngetvaultaddress \"name\"\ngetwalletactivity (account=\"*\" blocks=0)\ngetunconfirmedbalance (account=\"default\")\nimportlbrycrdwallet \"filename\" (\"passphrase\" rescan=true)\nimportlbrysdkwallet \"filename\" (\"password\" rescan=true)\nimportwatchpubkey \"pubkey\" (addresstype=\"legacy\" startheight)\nlistaccountclaims (account=\"*\")\nlistaccountinfo (minconf=1)\nlistaccountunspent \"account\" (minconf=1 maxconf=9999999)\nlistaddresstransactions [\"address\",...] (account=\"default\")\nlistalltransactions (account=\"default\")\nlistbalancemoves (account=\"*\")\nlistdescriptors\nlistimportedaccounts\nlistinvoices (\"state\")\nlisttainted\nlistvaults\nlistwallets\nlistwithdrawals (\"state\")\nloadwallet \"walletname\"\nmovebalance \"fromaccount\" \"toaccount\" amount (\"comment\")\nnotifyconfirmations \"txid\" confirmations (\"url\")\npublishpsbt \"psbt\"\nrecovervault \"name\" \"recoveryprivkey\" \"address\" (feerate)\nrenameaccount \"oldaccount\" \"newaccount\"\nrequesttestcoins (amount account=\"default\" wait=false)\nrescanblockchain (startheight=0 stopheight)\nrescanimportedaccount \"account\" (startheight=0)\nsendfromvault \"name\" {\"address\":amount,...} (feerate)\nsetimportedaccount \"address\" \"account\"\nsetspendpolicy (maxtxamount dailylimit [\"allowlist\",...] [\"denylist\",...] \"passphrase\")\nsignpsbt \"psbt\"\nsweepaccountpsbt \"account\" \"address\" (minconf=1 feerate)\ntaintaddresses taint [\"address\",...] (reason=\"\")\ntaintunspent taint [{\"txid\":\"value\",\"vout\":n},...] (reason=\"\")\nstopnotifyconfirmations id\nunloadwallet (\"walletname\")\nunwatchaddresses [\"address\",...]\nwalletgetdata \"namespace\" (\"key\")\nwalletislocked\nwalletsetdata \"namespace\" \"key\" (\"value\")\nwatchaddresses [\"address\",...]"
@@ -5,6 +5,8 @@
 package legacyrpc
 
 import (
+	"bytes"
+	"context"
 	"crypto/sha256"
 	"crypto/subtle"
 	"encoding/base64"
@@ -14,6 +16,7 @@ import (
 	"io/ioutil"
 	"net"
 	"net/http"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -32,9 +35,24 @@ type websocketClient struct {
 	responses     chan []byte
 	quit          chan struct{} // closed on disconnect
 	wg            sync.WaitGroup
+
+	// notifications queues the notifications to the client, which is
+	// disconnected once lagging is closed, when it falls further behind
+	// than the queue holds.
+	notifications chan []byte
+	lagging       chan struct{}
+	lagOnce       sync.Once
+
+	// The notifications delivered and dropped, accessed atomically, and
+	// the counters of the server.
+	delivered uint64
+	dropped   uint64
+	stats     *ntfnCounters
 }
 
-func newWebsocketClient(c *websocket.Conn, authenticated bool, remoteAddr string) *websocketClient {
+func newWebsocketClient(c *websocket.Conn, authenticated bool, remoteAddr string,
+	queueSize int, stats *ntfnCounters) *websocketClient {
+
 	return &websocketClient{
 		conn:          c,
 		authenticated: authenticated,
@@ -42,6 +60,9 @@ func newWebsocketClient(c *websocket.Conn, authenticated bool, remoteAddr string
 		allRequests:   make(chan []byte),
 		responses:     make(chan []byte),
 		quit:          make(chan struct{}),
+		notifications: make(chan []byte, queueSize),
+		lagging:       make(chan struct{}),
+		stats:         stats,
 	}
 }
 
@@ -57,24 +78,93 @@ func (c *websocketClient) send(b []byte) error {
 // Server holds the items the RPC server may need to access (auth,
 // config, shutdown, etc.)
 type Server struct {
-	httpServer   http.Server
-	wallet       *wallet.Wallet
-	walletLoader *wallet.Loader
-	chainClient  chain.Interface
-	handlerMu    sync.Mutex
-
-	listeners []net.Listener
-	authsha   [sha256.Size]byte
-	upgrader  websocket.Upgrader
+	httpServer    http.Server
+	wallet        *wallet.Wallet
+	wallets       map[string]*namedWallet // Named wallets.
+	walletLoader  *wallet.Loader
+	walletManager WalletManager
+	chainClient   chain.Interface
+	handlerMu     sync.Mutex
+
+	listeners   []net.Listener
+	authsha     [sha256.Size]byte
+	authLimiter *authLimiter
+	allowed     *allowList
+	upgrader    websocket.Upgrader
+
+	// username is the name of the RPC user, and approvers are the users
+	// who may only list and approve withdrawals.
+	username  string
+	approvers []approverAuth
 
 	maxPostClients      int64 // Max concurrent HTTP POST clients.
 	maxWebsocketClients int64 // Max concurrent websocket clients.
+	maxRequestSize      int64 // Max size of HTTP POST request bodies.
+	maxMessageSize      int64 // Max size of websocket messages.
+	maxResultItems      int   // Max items of results, or zero.
+	readyMaxBlockLag    int32 // Max blocks behind the chain when ready.
+
+	// slowThreshold is the duration above which requests are logged as
+	// slow, or zero.
+	slowThreshold time.Duration
+
+	websockets int64 // Connected websocket clients, accessed atomically.
+
+	// ntfnQueueSize is the number of notifications queued for each
+	// websocket client, and ntfnStats counts their delivery.
+	ntfnQueueSize int
+	ntfnStats     ntfnCounters
 
 	wg      sync.WaitGroup
 	quit    chan struct{}
 	quitMtx sync.Mutex
 
+	// canceled is closed once the server is stopping, canceling the
+	// requests still in flight.
+	canceled chan struct{}
+
+	// inflight tracks the requests being handled.  Once draining is set,
+	// no new requests are accepted.
+	inflight sync.WaitGroup
+	drainMtx sync.Mutex
+	draining bool
+
+	confirmations *confNotifier
+	addrNtfns     *addrNotifier
+
+	// debugInfo writes the bundle of the collectdebuginfo method, or is
+	// nil.
+	debugInfo func(w io.Writer, wallets map[string]*wallet.Wallet) error
+
+	// regtestHarness enables the methods of harnessMethods.
+	regtestHarness bool
+
+	// miningAccount is the account the generate method mines blocks to,
+	// or empty to pass the method through to the chain server.
+	miningAccount string
+
 	requestShutdownChan chan struct{}
+	requestReloadChan   chan struct{}
+}
+
+// errShuttingDown is returned to websocket clients for requests received
+// while the server is draining.
+var errShuttingDown = &btcjson.RPCError{
+	Code:    btcjson.ErrRPCMisc,
+	Message: "lbcwallet is shutting down",
+}
+
+// shuttingDown sends a message back to the client if a request is received
+// while the server is draining.
+func shuttingDown(w http.ResponseWriter) {
+	http.Error(w, "503 Service Unavailable: lbcwallet is shutting down",
+		http.StatusServiceUnavailable)
+}
+
+// authLimited sends a message back to the client if it must wait before
+// attempting to authenticate again.
+func authLimited(w http.ResponseWriter) {
+	http.Error(w, "429 Too Many Requests", http.StatusTooManyRequests)
 }
 
 // jsonAuthFail sends a message back to the client if the http auth is rejected.
@@ -89,6 +179,27 @@ func NewServer(opts *Options, walletLoader *wallet.Loader, listeners []net.Liste
 	serveMux := http.NewServeMux()
 	const rpcAuthTimeoutSeconds = 10
 
+	allowed := &allowList{allowed: opts.AllowedIPs}
+	for i, lis := range listeners {
+		listeners[i] = newAllowListener(lis, allowed)
+	}
+	maxRequestSize := opts.MaxRequestSize
+	if maxRequestSize <= 0 {
+		maxRequestSize = DefaultMaxRequestSize
+	}
+	maxMessageSize := opts.MaxMessageSize
+	if maxMessageSize <= 0 {
+		maxMessageSize = DefaultMaxMessageSize
+	}
+	readyMaxBlockLag := opts.ReadyMaxBlockLag
+	if readyMaxBlockLag < 0 {
+		readyMaxBlockLag = DefaultReadyMaxBlockLag
+	}
+	ntfnQueueSize := opts.NotificationQueueSize
+	if ntfnQueueSize <= 0 {
+		ntfnQueueSize = DefaultNotificationQueueSize
+	}
+
 	server := &Server{
 		httpServer: http.Server{
 			Handler: serveMux,
@@ -97,19 +208,48 @@ func NewServer(opts *Options, walletLoader *wallet.Loader, listeners []net.Liste
 			// handshake within the allowed timeframe.
 			ReadTimeout: time.Second * rpcAuthTimeoutSeconds,
 		},
+		wallets:             make(map[string]*namedWallet),
 		walletLoader:        walletLoader,
 		maxPostClients:      opts.MaxPOSTClients,
 		maxWebsocketClients: opts.MaxWebsocketClients,
+		maxRequestSize:      maxRequestSize,
+		maxMessageSize:      maxMessageSize,
+		maxResultItems:      opts.MaxResultItems,
+		readyMaxBlockLag:    readyMaxBlockLag,
+		slowThreshold:       opts.SlowThreshold,
+		ntfnQueueSize:       ntfnQueueSize,
 		listeners:           listeners,
 		// A hash of the HTTP basic auth string is used for a constant
 		// time comparison.
 		authsha: sha256.Sum256(httpBasicAuth(opts.Username, opts.Password)),
+		authLimiter: newAuthLimiter(
+			opts.AuthFailureThreshold, opts.AuthBanDuration,
+			opts.AuthExemptIPs,
+		),
+		allowed:  allowed,
+		username: opts.Username,
 		upgrader: websocket.Upgrader{
 			// Allow all origins.
 			CheckOrigin: func(r *http.Request) bool { return true },
 		},
+		confirmations:       newConfNotifier(opts.Dial),
+		addrNtfns:           newAddrNotifier(),
+		debugInfo:           opts.DebugInfo,
+		regtestHarness:      opts.RegtestHarness,
+		miningAccount:       opts.MiningAccount,
 		quit:                make(chan struct{}),
+		canceled:            make(chan struct{}),
 		requestShutdownChan: make(chan struct{}, 1),
+		requestReloadChan:   make(chan struct{}, 1),
+	}
+
+	for username, password := range opts.Approvers {
+		server.approvers = append(server.approvers, approverAuth{
+			username: username,
+			authsha: sha256.Sum256(
+				httpBasicAuth(username, password),
+			),
+		})
 	}
 
 	serveMux.Handle("/", throttledFn(opts.MaxPOSTClients,
@@ -118,29 +258,57 @@ func NewServer(opts *Options, walletLoader *wallet.Loader, listeners []net.Liste
 			w.Header().Set("Content-Type", "application/json")
 			r.Close = true
 
-			if err := server.checkAuthHeader(r); err != nil {
-				log.Warnf("Unauthorized client connection attempt")
+			if !server.authLimiter.allowed(r.RemoteAddr) {
+				authLimited(w)
+				return
+			}
+			user, err := server.checkUserAuth(r)
+			if err != nil {
+				log.Warnf("Unauthorized client connection attempt "+
+					"from %s", r.RemoteAddr)
+				if err != ErrNoAuth {
+					server.authLimiter.failure(r.RemoteAddr)
+				}
 				jsonAuthFail(w)
 				return
 			}
+			server.authLimiter.success(r.RemoteAddr)
+			if !server.startRequest() {
+				shuttingDown(w)
+				return
+			}
 			server.wg.Add(1)
-			server.postClientRPC(w, r)
+			server.postClientRPC(w, r.WithContext(
+				withRPCUser(r.Context(), user),
+			))
 			server.wg.Done()
+			server.inflight.Done()
 		}))
 
+	// The probes of orchestrators and load balancers are not
+	// authenticated.
+	serveMux.HandleFunc("/healthz", server.healthz)
+	serveMux.HandleFunc("/readyz", server.readyz)
+
 	serveMux.Handle("/ws", throttledFn(opts.MaxWebsocketClients,
 		func(w http.ResponseWriter, r *http.Request) {
+			if !server.authLimiter.allowed(r.RemoteAddr) {
+				authLimited(w)
+				return
+			}
 			authenticated := false
 			switch server.checkAuthHeader(r) {
 			case nil:
 				authenticated = true
+				server.authLimiter.success(r.RemoteAddr)
 			case ErrNoAuth:
 				// nothing
 			default:
 				// If auth was supplied but incorrect, rather than simply
 				// being missing, immediately terminate the connection.
-				log.Warnf("Disconnecting improperly authorized " +
-					"websocket client")
+				log.Warnf("Disconnecting improperly authorized "+
+					"websocket client %s", r.RemoteAddr)
+				server.authLimiter.failure(r.RemoteAddr)
 				jsonAuthFail(w)
 				return
 			}
@@ -151,7 +319,10 @@ func NewServer(opts *Options, walletLoader *wallet.Loader, listeners []net.Liste
 					r.RemoteAddr, err)
 				return
 			}
-			wsc := newWebsocketClient(conn, authenticated, r.RemoteAddr)
+			conn.SetReadLimit(server.maxMessageSize)
+			wsc := newWebsocketClient(conn, authenticated,
+				r.RemoteAddr, server.ntfnQueueSize,
+				&server.ntfnStats)
 			server.websocketClientRPC(wsc)
 		}))
 
@@ -200,6 +371,65 @@ func (s *Server) RegisterWallet(w *wallet.Wallet) {
 	s.handlerMu.Lock()
 	s.wallet = w
 	s.handlerMu.Unlock()
+
+	s.wg.Add(2)
+	go s.watchConfirmations(w)
+	go s.relayWatchedAddresses(w)
+}
+
+// RegisterNamedWallet associates the legacy RPC server with a wallet loaded
+// alongside the default one.  HTTP POST requests for the path /wallet/<name>
+// are handled by the named wallet, while websocket clients and requests for
+// any other path use the default wallet.
+func (s *Server) RegisterNamedWallet(name string, w *wallet.Wallet) {
+	s.handlerMu.Lock()
+	s.wallets[name] = &namedWallet{w: w}
+	s.handlerMu.Unlock()
+}
+
+// startRequest registers a request as in flight.  It returns false, and the
+// request must be rejected, when the server is draining.  Otherwise
+// s.inflight.Done must be called once the request has been handled.
+func (s *Server) startRequest() bool {
+	s.drainMtx.Lock()
+	defer s.drainMtx.Unlock()
+
+	if s.draining {
+		return false
+	}
+	s.inflight.Add(1)
+	return true
+}
+
+// Drain stops the server from accepting new requests and waits up to timeout
+// for the requests which are already being handled, such as sends, to
+// complete.  Clients remain connected and are answered with an error until
+// the server is stopped.  It returns whether all in-flight requests finished
+// before the timeout.  A timeout of zero waits without bound.
+func (s *Server) Drain(timeout time.Duration) bool {
+	s.drainMtx.Lock()
+	s.draining = true
+	s.drainMtx.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.inflight.Wait()
+		close(done)
+	}()
+
+	if timeout <= 0 {
+		<-done
+		return true
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		return false
+	}
 }
 
 // Stop gracefully shuts down the rpc server by stopping and disconnecting all
@@ -214,14 +444,24 @@ func (s *Server) Stop() {
 	default:
 	}
 
-	// Stop the connected wallet and chain server, if any.
+	// Cancel the requests in flight before their wallets are stopped.
+	close(s.canceled)
+
+	// Stop the connected wallets and chain server, if any.
 	s.handlerMu.Lock()
+	named := make([]*wallet.Wallet, 0, len(s.wallets))
+	for _, nw := range s.wallets {
+		named = append(named, nw.w)
+	}
 	wallet := s.wallet
 	chainClient := s.chainClient
 	s.handlerMu.Unlock()
 	if wallet != nil {
 		wallet.Stop()
 	}
+	for _, w := range named {
+		w.Stop()
+	}
 	if chainClient != nil {
 		chainClient.Stop()
 	}
@@ -244,6 +484,9 @@ func (s *Server) Stop() {
 	if wallet != nil {
 		wallet.WaitForShutdown()
 	}
+	for _, w := range named {
+		w.WaitForShutdown()
+	}
 	if chainClient != nil {
 		chainClient.WaitForShutdown()
 	}
@@ -262,25 +505,110 @@ func (s *Server) SetChainServer(chainClient chain.Interface) {
 	s.handlerMu.Unlock()
 }
 
+// requestContext returns the context of a request, derived from parent and
+// canceled once disconnected is closed or the server is stopping.  The cancel
+// function must be called once the request is handled.
+func (s *Server) requestContext(parent context.Context,
+	disconnected <-chan struct{}) (context.Context, context.CancelFunc) {
+
+	ctx, cancel := context.WithCancel(parent)
+	go func() {
+		select {
+		case <-disconnected:
+		case <-s.canceled:
+		case <-ctx.Done():
+		}
+		cancel()
+	}()
+	return ctx, cancel
+}
+
 // handlerClosure creates a closure function for handling requests of the given
 // method.  This may be a request that is handled directly by lbcwallet, or
 // a chain server request that is handled by passing the request down to .
 //
+// The request is handled by the named wallet, or the default wallet when
+// walletName is empty.  A named wallet uses its own chain server client.  The
+// handlers of long requests stop once ctx is done.
+//
 // NOTE: These handlers do not handle special cases, such as the authenticate
 // method.  Each of these must be checked beforehand (the method is already
 // known) and handled accordingly.
-func (s *Server) handlerClosure(request *btcjson.Request) lazyHandler {
+func (s *Server) handlerClosure(ctx context.Context, request *btcjson.Request,
+	walletName string) lazyHandler {
+
+	if _, ok := harnessMethods[request.Method]; ok && !s.regtestHarness {
+		return func() (interface{}, *btcjson.RPCError) {
+			return nil, &ErrHarnessDisabled
+		}
+	}
+	if request.Method == "generate" && s.miningAccount != "" {
+		request = s.miningRequest(request)
+	}
+
 	s.handlerMu.Lock()
 	// With the lock held, make copies of these pointers for the closure.
 	wallet := s.wallet
 	chainClient := s.chainClient
+	if walletName != "" {
+		nw, ok := s.wallets[walletName]
+		if !ok {
+			s.handlerMu.Unlock()
+			return func() (interface{}, *btcjson.RPCError) {
+				return nil, &ErrWalletNotFound
+			}
+		}
+		// The wallet is not closed before the request has been
+		// handled.
+		nw.inflight.Add(1)
+		s.handlerMu.Unlock()
+
+		if c := nw.w.ChainClient(); c != nil {
+			chainClient = c
+		}
+		f := limitResult(
+			lazyApplyHandler(ctx, request, nw.w, chainClient),
+			s.maxResultItems,
+		)
+		f = logSlow(f, request.Method, s.slowThreshold)
+		return func() (interface{}, *btcjson.RPCError) {
+			defer nw.inflight.Done()
+			return f()
+		}
+	}
 	if wallet != nil && chainClient == nil {
 		chainClient = wallet.ChainClient()
 		s.chainClient = chainClient
 	}
 	s.handlerMu.Unlock()
 
-	return lazyApplyHandler(request, wallet, chainClient)
+	f := limitResult(lazyApplyHandler(ctx, request, wallet, chainClient),
+		s.maxResultItems)
+	return logSlow(f, request.Method, s.slowThreshold)
+}
+
+// miningRequest returns a generatetowallet request mining the blocks of a
+// generate request to the mining account, so that they pay the wallet rather
+// than the mining address of the chain server.
+func (s *Server) miningRequest(request *btcjson.Request) *btcjson.Request {
+	account, _ := json.Marshal(s.miningAccount)
+	r := *request
+	r.Method = "generatetowallet"
+	r.Params = append(append([]json.RawMessage(nil), request.Params...),
+		account)
+	return &r
+}
+
+// walletPathPrefix is the prefix of the HTTP POST paths of named wallets.
+const walletPathPrefix = "/wallet/"
+
+// requestWalletName returns the name of the wallet, if any, of the path of an
+// HTTP POST request.
+func requestWalletName(path string) string {
+	if !strings.HasPrefix(path, walletPathPrefix) {
+		return ""
+	}
+	return strings.TrimPrefix(path, walletPathPrefix)
 }
 
 // ErrNoAuth represents an error where authentication could not succeed
@@ -307,6 +635,72 @@ func (s *Server) checkAuthHeader(r *http.Request) error {
 	return nil
 }
 
+// approverAuth is the HTTP Basic authentication of a user who may only list
+// and approve withdrawals.
+type approverAuth struct {
+	username string
+	authsha  [sha256.Size]byte
+}
+
+// approverMethods are the methods the approvers of withdrawals may call.
+var approverMethods = map[string]struct{}{
+	"approvewithdrawal": {},
+	"listwithdrawals":   {},
+}
+
+// rpcUser is the user who authenticated a request.
+type rpcUser struct {
+	name string
+
+	// approver is whether the user may only list and approve
+	// withdrawals.
+	approver bool
+}
+
+// rpcUserKey is the key of the user of a request in its context.
+type rpcUserKey struct{}
+
+// withRPCUser returns a copy of the context of a request carrying its user.
+func withRPCUser(ctx context.Context, user rpcUser) context.Context {
+	return context.WithValue(ctx, rpcUserKey{}, user)
+}
+
+// requestUser returns the user of a request carried by its context, or the
+// zero user when unknown.
+func requestUser(ctx context.Context) rpcUser {
+	user, _ := ctx.Value(rpcUserKey{}).(rpcUser)
+	return user
+}
+
+// checkUserAuth checks the HTTP Basic authentication supplied by a client in
+// the HTTP request r against the RPC user and the approvers, and returns the
+// user who authenticated.  It errors like checkAuthHeader when neither
+// matches.
+//
+// This check is time-constant.
+func (s *Server) checkUserAuth(r *http.Request) (rpcUser, error) {
+	err := s.checkAuthHeader(r)
+	if err != ErrNoAuth && len(s.approvers) != 0 {
+		authsha := sha256.Sum256([]byte(r.Header["Authorization"][0]))
+		var approver string
+		for _, a := range s.approvers {
+			cmp := subtle.ConstantTimeCompare(
+				authsha[:], a.authsha[:],
+			)
+			if cmp == 1 {
+				approver = a.username
+			}
+		}
+		if err != nil && approver != "" {
+			return rpcUser{name: approver, approver: true}, nil
+		}
+	}
+	if err != nil {
+		return rpcUser{}, err
+	}
+	return rpcUser{name: s.username}, nil
+}
+
 // throttledFn wraps an http.HandlerFunc with throttling of concurrent active
 // clients by responding with an HTTP 429 when the threshold is crossed.
 func throttledFn(threshold int64, f http.HandlerFunc) http.Handler {
@@ -366,7 +760,14 @@ func (s *Server) websocketClientRead(wsc *websocketClient) {
 	for {
 		_, request, err := wsc.conn.ReadMessage()
 		if err != nil {
-			if err != io.EOF && err != io.ErrUnexpectedEOF {
+			switch {
+			case err == websocket.ErrReadLimit:
+				// The client is sent a close message with
+				// the status of messages too big.
+				log.Warnf("Disconnecting websocket client %s, "+
+					"which sent a message of more than %d "+
+					"bytes", wsc.remoteAddr, s.maxMessageSize)
+			case err != io.EOF && err != io.ErrUnexpectedEOF:
 				log.Warnf("Websocket receive failed from client %s: %v",
 					wsc.remoteAddr, err)
 			}
@@ -416,10 +817,18 @@ out:
 			}
 
 			if req.Method == "authenticate" {
-				if wsc.authenticated || s.invalidAuth(&req) {
+				if wsc.authenticated {
 					// Disconnect immediately.
 					break out
 				}
+				if !s.authLimiter.allowed(wsc.remoteAddr) {
+					break out
+				}
+				if s.invalidAuth(&req) {
+					s.authLimiter.failure(wsc.remoteAddr)
+					break out
+				}
+				s.authLimiter.success(wsc.remoteAddr)
 				wsc.authenticated = true
 				resp := makeResponse(req.ID, nil, nil)
 				// Expected to never fail.
@@ -455,12 +864,112 @@ out:
 				s.requestProcessShutdown()
 				break out
 
+			case "reloadconfig":
+				s.requestReload()
+				resp := makeResponse(req.ID,
+					"lbcwallet reloading configuration", nil)
+				mresp, err := json.Marshal(resp)
+				// Expected to never fail.
+				if err != nil {
+					panic(err)
+				}
+				err = wsc.send(mresp)
+				if err != nil {
+					break out
+				}
+
+			case "notifyconfirmations", "stopnotifyconfirmations":
+				var res interface{}
+				var jsonErr *btcjson.RPCError
+				if req.Method == "notifyconfirmations" {
+					res, jsonErr = s.notifyConfirmations(&req, wsc)
+				} else {
+					res, jsonErr = s.stopNotifyConfirmations(&req, wsc)
+				}
+				mresp, err := btcjson.MarshalResponse(
+					btcjson.RpcVersion1, req.ID, res, jsonErr,
+				)
+				// Expected to never fail.
+				if err != nil {
+					panic(err)
+				}
+				err = wsc.send(mresp)
+				if err != nil {
+					break out
+				}
+
+			case "collectdebuginfo":
+				res, jsonErr := s.collectDebugInfo()
+				mresp, err := btcjson.MarshalResponse(
+					btcjson.RpcVersion1, req.ID, res, jsonErr,
+				)
+				// Expected to never fail.
+				if err != nil {
+					panic(err)
+				}
+				err = wsc.send(mresp)
+				if err != nil {
+					break out
+				}
+
+			case "loadwallet", "unloadwallet", "listwallets":
+				res, jsonErr := s.walletManagementRequest(&req, "")
+				mresp, err := btcjson.MarshalResponse(
+					btcjson.RpcVersion1, req.ID, res, jsonErr,
+				)
+				// Expected to never fail.
+				if err != nil {
+					panic(err)
+				}
+				err = wsc.send(mresp)
+				if err != nil {
+					break out
+				}
+
+			case "watchaddresses", "unwatchaddresses":
+				var jsonErr *btcjson.RPCError
+				if req.Method == "watchaddresses" {
+					_, jsonErr = s.watchAddresses(&req, wsc)
+				} else {
+					_, jsonErr = s.unwatchAddresses(&req, wsc)
+				}
+				mresp, err := btcjson.MarshalResponse(
+					btcjson.RpcVersion1, req.ID, nil, jsonErr,
+				)
+				// Expected to never fail.
+				if err != nil {
+					panic(err)
+				}
+				err = wsc.send(mresp)
+				if err != nil {
+					break out
+				}
+
 			default:
+				if !s.startRequest() {
+					resp := makeResponse(req.ID, nil,
+						errShuttingDown)
+					mresp, err := json.Marshal(resp)
+					// Expected to never fail.
+					if err != nil {
+						panic(err)
+					}
+					err = wsc.send(mresp)
+					if err != nil {
+						break out
+					}
+					continue
+				}
 				req := req // Copy for the closure
-				f := s.handlerClosure(&req)
+				ctx, cancel := s.requestContext(
+					context.Background(), wsc.quit,
+				)
+				f := s.handlerClosure(ctx, &req, "")
 				wsc.wg.Add(1)
 				go func() {
+					defer s.inflight.Done()
 					resp, jsonErr := f()
+					cancel()
 					mresp, err := btcjson.MarshalResponse(
 						btcjson.RpcVersion1, req.ID,
 						resp, jsonErr,
@@ -480,7 +989,10 @@ out:
 		}
 	}
 
-	// allow client to disconnect after all handler goroutines are done
+	// allow client to disconnect after all handler goroutines and
+	// notifications are done
+	s.confirmations.removeClient(wsc)
+	s.unwatchClientAddresses(wsc)
 	wsc.wg.Wait()
 	close(wsc.responses)
 	s.wg.Done()
@@ -509,12 +1021,37 @@ out:
 				break out
 			}
 
+		case ntfn := <-wsc.notifications:
+			err := wsc.conn.SetWriteDeadline(time.Now().Add(deadline))
+			if err != nil {
+				log.Warnf("Cannot set write deadline on "+
+					"client %s: %v", wsc.remoteAddr, err)
+			}
+			err = wsc.conn.WriteMessage(websocket.TextMessage, ntfn)
+			if err != nil {
+				log.Warnf("Failed websocket send to client "+
+					"%s: %v", wsc.remoteAddr, err)
+				wsc.drop(1)
+				break out
+			}
+			wsc.deliver()
+
+		case <-wsc.lagging:
+			wsc.disconnectLagging(deadline)
+			break out
+
 		case <-s.quit:
 			break out
 		}
 	}
 	close(wsc.quit)
-	log.Infof("Disconnected websocket client %s", wsc.remoteAddr)
+
+	// The notifications left in the queue are never written.
+	wsc.drop(len(wsc.notifications))
+	log.Infof("Disconnected websocket client %s (%d notifications "+
+		"delivered, %d dropped)", wsc.remoteAddr,
+		atomic.LoadUint64(&wsc.delivered),
+		atomic.LoadUint64(&wsc.dropped))
 	s.wg.Done()
 }
 
@@ -522,6 +1059,8 @@ out:
 // websocket connection for a single client.
 func (s *Server) websocketClientRPC(wsc *websocketClient) {
 	log.Infof("New websocket client %s", wsc.remoteAddr)
+	atomic.AddInt64(&s.websockets, 1)
+	defer atomic.AddInt64(&s.websockets, -1)
 
 	// Clear the read deadline set before the websocket hijacked
 	// the connection.
@@ -542,18 +1081,17 @@ func (s *Server) websocketClientRPC(wsc *websocketClient) {
 	<-wsc.quit
 }
 
-// maxRequestSize specifies the maximum number of bytes in the request body
-// that may be read from a client.  This is currently limited to 4MB.
-const maxRequestSize = 1024 * 1024 * 4
-
 // postClientRPC processes and replies to a JSON-RPC client request.
 func (s *Server) postClientRPC(w http.ResponseWriter, r *http.Request) {
-	body := http.MaxBytesReader(w, r.Body, maxRequestSize)
+	body := http.MaxBytesReader(w, r.Body, s.maxRequestSize)
 	rpcRequest, err := ioutil.ReadAll(body)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		requestTooLarge(w, s.maxRequestSize)
+		return
+	}
 	if err != nil {
-		// TODO: what if the underlying reader errored?
-		http.Error(w, "413 Request Too Large.",
-			http.StatusRequestEntityTooLarge)
+		http.Error(w, "400 Bad Request.", http.StatusBadRequest)
 		return
 	}
 
@@ -582,20 +1120,45 @@ func (s *Server) postClientRPC(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Create the response and error from the request.  Two special cases
-	// are handled for the authenticate and stop request methods.
+	// Create the response and error from the request.  Special cases are
+	// handled for the requests of approvers, and the authenticate, stop,
+	// reloadconfig, collectdebuginfo, wallet management and notification
+	// request methods.
+	walletName := requestWalletName(r.URL.Path)
+	_, approverMethod := approverMethods[req.Method]
 	var res interface{}
 	var jsonErr *btcjson.RPCError
 	var stop bool
-	switch req.Method {
-	case "authenticate":
-		// Drop it.
-		return
-	case "stop":
-		stop = true
-		res = "lbcwallet stopping"
-	default:
-		res, jsonErr = s.handlerClosure(&req)()
+	if requestUser(r.Context()).approver && !approverMethod {
+		jsonErr = &ErrApproverMethod
+	} else {
+		switch req.Method {
+		case "authenticate":
+			// Drop it.
+			return
+		case "stop":
+			stop = true
+			res = "lbcwallet stopping"
+		case "reloadconfig":
+			s.requestReload()
+			res = "lbcwallet reloading configuration"
+		case "collectdebuginfo":
+			res, jsonErr = s.collectDebugInfo()
+		case "notifyconfirmations":
+			res, jsonErr = s.notifyConfirmations(&req, nil)
+		case "stopnotifyconfirmations":
+			res, jsonErr = s.stopNotifyConfirmations(&req, nil)
+		case "loadwallet", "unloadwallet", "listwallets":
+			res, jsonErr = s.walletManagementRequest(
+				&req, walletName,
+			)
+		case "watchaddresses", "unwatchaddresses":
+			jsonErr = errWebsocketOnly
+		default:
+			ctx, cancel := s.requestContext(r.Context(), nil)
+			res, jsonErr = s.handlerClosure(ctx, &req, walletName)()
+			cancel()
+		}
 	}
 
 	// Marshal and send.
@@ -607,7 +1170,7 @@ func (s *Server) postClientRPC(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "500 Internal Server Error", http.StatusInternalServerError)
 		return
 	}
-	_, err = w.Write(mresp)
+	err = writeResponse(w, r, mresp)
 	if err != nil {
 		log.Warnf("Unable to respond to client: %v", err)
 	}
@@ -629,3 +1192,76 @@ func (s *Server) requestProcessShutdown() {
 func (s *Server) RequestProcessShutdown() <-chan struct{} {
 	return s.requestShutdownChan
 }
+
+func (s *Server) requestReload() {
+	select {
+	case s.requestReloadChan <- struct{}{}:
+	default:
+	}
+}
+
+// RequestReload returns a channel that is sent to when an authorized client
+// requests the configuration to be reloaded.
+func (s *Server) RequestReload() <-chan struct{} {
+	return s.requestReloadChan
+}
+
+// collectDebugInfo handles a collectdebuginfo request by returning the debug
+// info bundle of the process, with the stats of every loaded wallet, encoded
+// in base64.
+func (s *Server) collectDebugInfo() (interface{}, *btcjson.RPCError) {
+	if s.debugInfo == nil {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCMisc,
+			Message: "Debug info collection is unavailable",
+		}
+	}
+
+	s.handlerMu.Lock()
+	wallets := make(map[string]*wallet.Wallet, len(s.wallets)+1)
+	if s.wallet != nil {
+		wallets[""] = s.wallet
+	}
+	for name, nw := range s.wallets {
+		// The wallet is not closed before the bundle is written.
+		nw.inflight.Add(1)
+		defer nw.inflight.Done()
+		wallets[name] = nw.w
+	}
+	s.handlerMu.Unlock()
+
+	var buf bytes.Buffer
+	if err := s.debugInfo(&buf, wallets); err != nil {
+		return nil, jsonError(err)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// SetAuthLimits replaces the failed authentication threshold, ban duration
+// and exempt networks of the server.  See Options.
+func (s *Server) SetAuthLimits(threshold int, banDuration time.Duration,
+	exempt []*net.IPNet) {
+
+	s.authLimiter.setLimits(threshold, banDuration, exempt)
+}
+
+// SetAllowedIPs replaces the networks of the clients whose connections are
+// accepted.  Connections which are already established are not closed.  See
+// Options.
+func (s *Server) SetAllowedIPs(allowed []*net.IPNet) {
+	s.allowed.set(allowed)
+}
+
+// WebsocketClients returns the number of connected websocket clients.
+func (s *Server) WebsocketClients() int64 {
+	return atomic.LoadInt64(&s.websockets)
+}
+
+// ListenAddrs returns the addresses the server listens on.
+func (s *Server) ListenAddrs() []net.Addr {
+	addrs := make([]net.Addr, len(s.listeners))
+	for i, lis := range s.listeners {
+		addrs[i] = lis.Addr()
+	}
+	return addrs
+}
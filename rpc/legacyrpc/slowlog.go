@@ -0,0 +1,37 @@
+package legacyrpc
+
+import (
+	"time"
+
+	"github.com/lbryio/lbcd/btcjson"
+	"github.com/lbryio/lbcwallet/walletdb"
+)
+
+// logSlow wraps a handler to log the requests it takes longer than threshold
+// to handle, with the time spent in database transactions and waiting for the
+// database write lock meanwhile, unless threshold is zero.  The database time
+// includes that of the requests and wallet operations run concurrently.
+func logSlow(f lazyHandler, method string,
+	threshold time.Duration) lazyHandler {
+
+	if threshold <= 0 {
+		return f
+	}
+	return func() (interface{}, *btcjson.RPCError) {
+		start := time.Now()
+		before := walletdb.ReadStats()
+		res, jsonErr := f()
+		elapsed := time.Since(start)
+		if elapsed >= threshold {
+			db := walletdb.ReadStats().Sub(before)
+			log.Warnf("Slow request %s took %v: %d database reads "+
+				"in %v, %d database writes in %v of which %v "+
+				"waiting for the write lock", method,
+				elapsed.Round(time.Millisecond), db.Views,
+				db.ViewTime.Round(time.Millisecond), db.Updates,
+				db.UpdateTime.Round(time.Millisecond),
+				db.UpdateWait.Round(time.Millisecond))
+		}
+		return res, jsonErr
+	}
+}
@@ -0,0 +1,22 @@
+package legacyrpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lbryio/lbcd/btcjson"
+)
+
+func TestLogSlow(t *testing.T) {
+	f := func() (interface{}, *btcjson.RPCError) {
+		time.Sleep(5 * time.Millisecond)
+		return "result", nil
+	}
+	for _, threshold := range []time.Duration{0, time.Millisecond, time.Hour} {
+		res, err := logSlow(f, "getbalance", threshold)()
+		if err != nil || res != "result" {
+			t.Fatalf("threshold %v: result %v, error %v", threshold,
+				res, err)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package legacyrpc
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/lbryio/lbcd/btcjson"
+	"github.com/lbryio/lbcwallet/rpc/walletjson"
+	"github.com/lbryio/lbcwallet/wallet"
+)
+
+// WalletManager loads and unloads the named wallets served by the RPC server
+// at runtime.  Loaded wallets are registered with RegisterNamedWallet, and
+// must be unregistered with UnregisterNamedWallet before they are closed.
+// Both methods return ErrWalletNotFound when no wallet of the name exists or
+// is loaded.
+type WalletManager interface {
+	LoadWallet(name string) error
+	UnloadWallet(name string) error
+}
+
+// namedWallet is a wallet loaded alongside the default wallet.
+type namedWallet struct {
+	w *wallet.Wallet
+
+	// inflight tracks the requests being handled by the wallet, which
+	// must finish before it is closed.
+	inflight sync.WaitGroup
+}
+
+// SetWalletManager sets the manager of the named wallets, which is required
+// by the loadwallet and unloadwallet requests.
+func (s *Server) SetWalletManager(m WalletManager) {
+	s.handlerMu.Lock()
+	s.walletManager = m
+	s.handlerMu.Unlock()
+}
+
+// UnregisterNamedWallet stops serving a named wallet.  New requests for the
+// wallet fail with ErrWalletNotFound, and this returns once the requests
+// already being handled by it have finished, so the wallet can be closed.
+func (s *Server) UnregisterNamedWallet(name string) {
+	s.handlerMu.Lock()
+	nw, ok := s.wallets[name]
+	delete(s.wallets, name)
+	s.handlerMu.Unlock()
+
+	if ok {
+		nw.inflight.Wait()
+	}
+}
+
+// walletManagementRequest handles the loadwallet, unloadwallet and
+// listwallets requests.  walletName is the wallet of the request path, if
+// any.
+func (s *Server) walletManagementRequest(req *btcjson.Request,
+	walletName string) (interface{}, *btcjson.RPCError) {
+
+	icmd, err := btcjson.UnmarshalCmd(req)
+	if err != nil {
+		return nil, btcjson.ErrRPCInvalidRequest
+	}
+
+	s.handlerMu.Lock()
+	m := s.walletManager
+	s.handlerMu.Unlock()
+
+	switch cmd := icmd.(type) {
+	case *walletjson.ListWalletsCmd:
+		return s.listWallets(), nil
+
+	case *btcjson.LoadWalletCmd:
+		if m == nil {
+			return nil, &ErrWalletManagementDisabled
+		}
+		if err := m.LoadWallet(cmd.WalletName); err != nil {
+			return nil, jsonError(err)
+		}
+		return &btcjson.LoadWalletResult{Name: cmd.WalletName}, nil
+
+	case *btcjson.UnloadWalletCmd:
+		name := walletName
+		if cmd.WalletName != nil {
+			if walletName != "" && *cmd.WalletName != walletName {
+				return nil, &btcjson.RPCError{
+					Code: btcjson.ErrRPCInvalidParameter,
+					Message: "The wallet of the request path " +
+						"and the wallet name parameter " +
+						"differ",
+				}
+			}
+			name = *cmd.WalletName
+		}
+		if name == "" {
+			return nil, &btcjson.RPCError{
+				Code:    btcjson.ErrRPCWalletNotSpecified,
+				Message: "The default wallet can not be unloaded",
+			}
+		}
+		if m == nil {
+			return nil, &ErrWalletManagementDisabled
+		}
+		if err := m.UnloadWallet(name); err != nil {
+			return nil, jsonError(err)
+		}
+		return nil, nil
+
+	default:
+		return nil, btcjson.ErrRPCMethodNotFound
+	}
+}
+
+// listWallets returns the names of the loaded wallets in sorted order.  As with
+// Bitcoin Core, the default wallet is named by the empty string.
+func (s *Server) listWallets() []string {
+	s.handlerMu.Lock()
+	defer s.handlerMu.Unlock()
+
+	names := make([]string, 0, len(s.wallets)+1)
+	if s.wallet != nil {
+		names = append(names, "")
+	}
+	for name := range s.wallets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,10 @@
+/*
+Package walletjson provides the lbcwallet-specific JSON-RPC commands and
+results that are not part of the btcjson package.
+
+Every command is registered with the btcjson command registry during package
+initialization, so the usual btcjson.UnmarshalCmd, btcjson.MarshalCmd and help
+generation functions work with the types defined here exactly as they do with
+the types in btcjson.
+*/
+package walletjson
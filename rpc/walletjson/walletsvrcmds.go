@@ -0,0 +1,1134 @@
+// NOTE: This file is intended to house the RPC commands that are supported by
+// lbcwallet but are not provided by the btcjson package.
+
+package walletjson
+
+import (
+	"github.com/lbryio/lbcd/btcjson"
+)
+
+// ApproveWithdrawalCmd defines the approvewithdrawal JSON-RPC command.
+type ApproveWithdrawalCmd struct {
+	ID uint64
+}
+
+// NewApproveWithdrawalCmd returns a new instance which can be used to issue
+// an approvewithdrawal JSON-RPC command.
+func NewApproveWithdrawalCmd(id uint64) *ApproveWithdrawalCmd {
+	return &ApproveWithdrawalCmd{ID: id}
+}
+
+// BenchSigningCmd defines the benchsigning JSON-RPC command.
+type BenchSigningCmd struct {
+	Duration *int `jsonrpcdefault:"4"`
+	Inputs   *int `jsonrpcdefault:"2"`
+}
+
+// NewBenchSigningCmd returns a new instance which can be used to issue a
+// benchsigning JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewBenchSigningCmd(duration, inputs *int) *BenchSigningCmd {
+	return &BenchSigningCmd{
+		Duration: duration,
+		Inputs:   inputs,
+	}
+}
+
+// CancelSendCmd defines the cancelsend JSON-RPC command.
+type CancelSendCmd struct {
+	Handle string
+}
+
+// NewCancelSendCmd returns a new instance which can be used to issue a
+// cancelsend JSON-RPC command.
+func NewCancelSendCmd(handle string) *CancelSendCmd {
+	return &CancelSendCmd{Handle: handle}
+}
+
+// CancelWithdrawalCmd defines the cancelwithdrawal JSON-RPC command.
+type CancelWithdrawalCmd struct {
+	ID uint64
+}
+
+// NewCancelWithdrawalCmd returns a new instance which can be used to issue a
+// cancelwithdrawal JSON-RPC command.
+func NewCancelWithdrawalCmd(id uint64) *CancelWithdrawalCmd {
+	return &CancelWithdrawalCmd{ID: id}
+}
+
+// ChangePublicPassphraseCmd defines the changepublicpassphrase JSON-RPC
+// command.
+type ChangePublicPassphraseCmd struct {
+	OldPassphrase string
+	NewPassphrase string
+}
+
+// NewChangePublicPassphraseCmd returns a new instance which can be used to
+// issue a changepublicpassphrase JSON-RPC command.
+func NewChangePublicPassphraseCmd(oldPassphrase,
+	newPassphrase string) *ChangePublicPassphraseCmd {
+
+	return &ChangePublicPassphraseCmd{
+		OldPassphrase: oldPassphrase,
+		NewPassphrase: newPassphrase,
+	}
+}
+
+// CloneWalletCmd defines the clonewallet JSON-RPC command.
+type CloneWalletCmd struct {
+	Destination string
+}
+
+// NewCloneWalletCmd returns a new instance which can be used to issue a
+// clonewallet JSON-RPC command.
+func NewCloneWalletCmd(destination string) *CloneWalletCmd {
+	return &CloneWalletCmd{
+		Destination: destination,
+	}
+}
+
+// CollectDebugInfoCmd defines the collectdebuginfo JSON-RPC command.
+type CollectDebugInfoCmd struct{}
+
+// NewCollectDebugInfoCmd returns a new instance which can be used to issue a
+// collectdebuginfo JSON-RPC command.
+func NewCollectDebugInfoCmd() *CollectDebugInfoCmd {
+	return &CollectDebugInfoCmd{}
+}
+
+// ConfirmSendCmd defines the confirmsend JSON-RPC command.
+type ConfirmSendCmd struct {
+	Handle string
+}
+
+// NewConfirmSendCmd returns a new instance which can be used to issue a
+// confirmsend JSON-RPC command.
+func NewConfirmSendCmd(handle string) *ConfirmSendCmd {
+	return &ConfirmSendCmd{Handle: handle}
+}
+
+// CreateInvoiceCmd defines the createinvoice JSON-RPC command.
+type CreateInvoiceCmd struct {
+	Amount  float64
+	Memo    *string
+	Expiry  *int64  `jsonrpcdefault:"3600"`
+	Account *string `jsonrpcdefault:"\"default\""`
+}
+
+// NewCreateInvoiceCmd returns a new instance which can be used to issue a
+// createinvoice JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewCreateInvoiceCmd(amount float64, memo *string, expiry *int64,
+	account *string) *CreateInvoiceCmd {
+
+	return &CreateInvoiceCmd{
+		Amount:  amount,
+		Memo:    memo,
+		Expiry:  expiry,
+		Account: account,
+	}
+}
+
+// CreatePaymentURICmd defines the createpaymenturi JSON-RPC command.
+type CreatePaymentURICmd struct {
+	Address string
+	Amount  *float64
+	Label   *string
+	Message *string
+	QRCode  *bool `jsonrpcdefault:"false"`
+}
+
+// NewCreatePaymentURICmd returns a new instance which can be used to issue a
+// createpaymenturi JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewCreatePaymentURICmd(address string, amount *float64, label,
+	message *string, qrCode *bool) *CreatePaymentURICmd {
+
+	return &CreatePaymentURICmd{
+		Address: address,
+		Amount:  amount,
+		Label:   label,
+		Message: message,
+		QRCode:  qrCode,
+	}
+}
+
+// CreateVaultCmd defines the createvault JSON-RPC command.
+type CreateVaultCmd struct {
+	Name           string
+	RecoveryPubKey string
+	Delay          uint16
+}
+
+// NewCreateVaultCmd returns a new instance which can be used to issue a
+// createvault JSON-RPC command.
+func NewCreateVaultCmd(name, recoveryPubKey string,
+	delay uint16) *CreateVaultCmd {
+
+	return &CreateVaultCmd{
+		Name:           name,
+		RecoveryPubKey: recoveryPubKey,
+		Delay:          delay,
+	}
+}
+
+// DecodePaymentURICmd defines the decodepaymenturi JSON-RPC command.
+type DecodePaymentURICmd struct {
+	URI string
+}
+
+// NewDecodePaymentURICmd returns a new instance which can be used to issue a
+// decodepaymenturi JSON-RPC command.
+func NewDecodePaymentURICmd(uri string) *DecodePaymentURICmd {
+	return &DecodePaymentURICmd{URI: uri}
+}
+
+// DiagnoseTransactionCmd defines the diagnosetransaction JSON-RPC command.
+type DiagnoseTransactionCmd struct {
+	Txid string
+}
+
+// NewDiagnoseTransactionCmd returns a new instance which can be used to issue
+// a diagnosetransaction JSON-RPC command.
+func NewDiagnoseTransactionCmd(txid string) *DiagnoseTransactionCmd {
+	return &DiagnoseTransactionCmd{Txid: txid}
+}
+
+// DumpGoroutinesCmd defines the dumpgoroutines JSON-RPC command.
+type DumpGoroutinesCmd struct{}
+
+// NewDumpGoroutinesCmd returns a new instance which can be used to issue a
+// dumpgoroutines JSON-RPC command.
+func NewDumpGoroutinesCmd() *DumpGoroutinesCmd {
+	return &DumpGoroutinesCmd{}
+}
+
+// DumpHeapProfileCmd defines the dumpheapprofile JSON-RPC command.
+type DumpHeapProfileCmd struct {
+	GC *bool `jsonrpcdefault:"false"`
+}
+
+// NewDumpHeapProfileCmd returns a new instance which can be used to issue a
+// dumpheapprofile JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewDumpHeapProfileCmd(gc *bool) *DumpHeapProfileCmd {
+	return &DumpHeapProfileCmd{
+		GC: gc,
+	}
+}
+
+// DumpImportedAccountCmd defines the dumpimportedaccount JSON-RPC command.
+type DumpImportedAccountCmd struct {
+	Account string
+}
+
+// NewDumpImportedAccountCmd returns a new instance which can be used to issue
+// a dumpimportedaccount JSON-RPC command.
+func NewDumpImportedAccountCmd(account string) *DumpImportedAccountCmd {
+	return &DumpImportedAccountCmd{Account: account}
+}
+
+// ExportSeedSharesCmd defines the exportseedshares JSON-RPC command.
+type ExportSeedSharesCmd struct {
+	Threshold int
+	Count     int
+}
+
+// NewExportSeedSharesCmd returns a new instance which can be used to issue an
+// exportseedshares JSON-RPC command.
+func NewExportSeedSharesCmd(threshold, count int) *ExportSeedSharesCmd {
+	return &ExportSeedSharesCmd{
+		Threshold: threshold,
+		Count:     count,
+	}
+}
+
+// ExportTransactionsCmd defines the exporttransactions JSON-RPC command.
+type ExportTransactionsCmd struct {
+	Format    string
+	StartDate *string
+	EndDate   *string
+	Account   *string `jsonrpcdefault:"\"*\""`
+	Filename  *string
+}
+
+// NewExportTransactionsCmd returns a new instance which can be used to issue
+// an exporttransactions JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewExportTransactionsCmd(format string, startDate, endDate,
+	account, filename *string) *ExportTransactionsCmd {
+
+	return &ExportTransactionsCmd{
+		Format:    format,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Account:   account,
+		Filename:  filename,
+	}
+}
+
+// ExportTxLogCmd defines the exporttxlog JSON-RPC command.
+type ExportTxLogCmd struct {
+	Filename *string
+}
+
+// NewExportTxLogCmd returns a new instance which can be used to issue an
+// exporttxlog JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewExportTxLogCmd(filename *string) *ExportTxLogCmd {
+	return &ExportTxLogCmd{Filename: filename}
+}
+
+// FastForwardCmd defines the fastforward JSON-RPC command.
+type FastForwardCmd struct {
+	Seconds int64
+}
+
+// NewFastForwardCmd returns a new instance which can be used to issue a
+// fastforward JSON-RPC command.
+func NewFastForwardCmd(seconds int64) *FastForwardCmd {
+	return &FastForwardCmd{Seconds: seconds}
+}
+
+// FundAddressCmd defines the fundaddress JSON-RPC command.
+type FundAddressCmd struct {
+	Address string
+	Amount  float64
+	Blocks  *int64 `jsonrpcdefault:"1"`
+}
+
+// NewFundAddressCmd returns a new instance which can be used to issue a
+// fundaddress JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewFundAddressCmd(address string, amount float64,
+	blocks *int64) *FundAddressCmd {
+
+	return &FundAddressCmd{
+		Address: address,
+		Amount:  amount,
+		Blocks:  blocks,
+	}
+}
+
+// GenerateToWalletCmd defines the generatetowallet JSON-RPC command.
+type GenerateToWalletCmd struct {
+	NumBlocks int64
+	Account   *string `jsonrpcdefault:"\"default\""`
+}
+
+// NewGenerateToWalletCmd returns a new instance which can be used to issue a
+// generatetowallet JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGenerateToWalletCmd(numBlocks int64,
+	account *string) *GenerateToWalletCmd {
+
+	return &GenerateToWalletCmd{
+		NumBlocks: numBlocks,
+		Account:   account,
+	}
+}
+
+// GetAccountInfoCmd defines the getaccountinfo JSON-RPC command.
+type GetAccountInfoCmd struct {
+	Account string
+	MinConf *int `jsonrpcdefault:"1"`
+}
+
+// NewGetAccountInfoCmd returns a new instance which can be used to issue a
+// getaccountinfo JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetAccountInfoCmd(account string, minConf *int) *GetAccountInfoCmd {
+	return &GetAccountInfoCmd{
+		Account: account,
+		MinConf: minConf,
+	}
+}
+
+// GetDiskStatusCmd defines the getdiskstatus JSON-RPC command.
+type GetDiskStatusCmd struct{}
+
+// NewGetDiskStatusCmd returns a new instance which can be used to issue a
+// getdiskstatus JSON-RPC command.
+func NewGetDiskStatusCmd() *GetDiskStatusCmd {
+	return &GetDiskStatusCmd{}
+}
+
+// GetInvoiceCmd defines the getinvoice JSON-RPC command.
+type GetInvoiceCmd struct {
+	ID uint64
+}
+
+// NewGetInvoiceCmd returns a new instance which can be used to issue a
+// getinvoice JSON-RPC command.
+func NewGetInvoiceCmd(id uint64) *GetInvoiceCmd {
+	return &GetInvoiceCmd{ID: id}
+}
+
+// GetBackupStatusCmd defines the getbackupstatus JSON-RPC command.
+type GetBackupStatusCmd struct{}
+
+// NewGetBackupStatusCmd returns a new instance which can be used to issue a
+// getbackupstatus JSON-RPC command.
+func NewGetBackupStatusCmd() *GetBackupStatusCmd {
+	return &GetBackupStatusCmd{}
+}
+
+// GetConnectionStatusCmd defines the getconnectionstatus JSON-RPC command.
+type GetConnectionStatusCmd struct{}
+
+// NewGetConnectionStatusCmd returns a new instance which can be used to issue
+// a getconnectionstatus JSON-RPC command.
+func NewGetConnectionStatusCmd() *GetConnectionStatusCmd {
+	return &GetConnectionStatusCmd{}
+}
+
+// GetDecoyAddressesCmd defines the getdecoyaddresses JSON-RPC command.
+type GetDecoyAddressesCmd struct {
+	Account     string
+	Count       uint32
+	AddressType *string `jsonrpcdefault:"\"legacy\""`
+}
+
+// NewGetDecoyAddressesCmd returns a new instance which can be used to issue a
+// getdecoyaddresses JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetDecoyAddressesCmd(account string, count uint32,
+	addressType *string) *GetDecoyAddressesCmd {
+
+	return &GetDecoyAddressesCmd{
+		Account:     account,
+		Count:       count,
+		AddressType: addressType,
+	}
+}
+
+// GetNewAddressesCmd defines the getnewaddresses JSON-RPC command.
+type GetNewAddressesCmd struct {
+	Account     string
+	Count       uint32
+	AddressType *string `jsonrpcdefault:"\"legacy\""`
+}
+
+// NewGetNewAddressesCmd returns a new instance which can be used to issue a
+// getnewaddresses JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetNewAddressesCmd(account string, count uint32,
+	addressType *string) *GetNewAddressesCmd {
+
+	return &GetNewAddressesCmd{
+		Account:     account,
+		Count:       count,
+		AddressType: addressType,
+	}
+}
+
+// GetReceivedByAddressesCmd defines the getreceivedbyaddresses JSON-RPC
+// command.
+type GetReceivedByAddressesCmd struct {
+	Addresses []string
+	MinConf   *int `jsonrpcdefault:"1"`
+}
+
+// NewGetReceivedByAddressesCmd returns a new instance which can be used to
+// issue a getreceivedbyaddresses JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetReceivedByAddressesCmd(addresses []string,
+	minConf *int) *GetReceivedByAddressesCmd {
+
+	return &GetReceivedByAddressesCmd{
+		Addresses: addresses,
+		MinConf:   minConf,
+	}
+}
+
+// GetPrivacyReportCmd defines the getprivacyreport JSON-RPC command.
+type GetPrivacyReportCmd struct{}
+
+// NewGetPrivacyReportCmd returns a new instance which can be used to issue a
+// getprivacyreport JSON-RPC command.
+func NewGetPrivacyReportCmd() *GetPrivacyReportCmd {
+	return &GetPrivacyReportCmd{}
+}
+
+// GetSpendPolicyCmd defines the getspendpolicy JSON-RPC command.
+type GetSpendPolicyCmd struct{}
+
+// NewGetSpendPolicyCmd returns a new instance which can be used to issue a
+// getspendpolicy JSON-RPC command.
+func NewGetSpendPolicyCmd() *GetSpendPolicyCmd {
+	return &GetSpendPolicyCmd{}
+}
+
+// GetUptimeStatsCmd defines the getuptimestats JSON-RPC command.
+type GetUptimeStatsCmd struct {
+	Days *int64 `jsonrpcdefault:"30"`
+}
+
+// NewGetUptimeStatsCmd returns a new instance which can be used to issue a
+// getuptimestats JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetUptimeStatsCmd(days *int64) *GetUptimeStatsCmd {
+	return &GetUptimeStatsCmd{Days: days}
+}
+
+// GetVaultAddressCmd defines the getvaultaddress JSON-RPC command.
+type GetVaultAddressCmd struct {
+	Name string
+}
+
+// NewGetVaultAddressCmd returns a new instance which can be used to issue a
+// getvaultaddress JSON-RPC command.
+func NewGetVaultAddressCmd(name string) *GetVaultAddressCmd {
+	return &GetVaultAddressCmd{Name: name}
+}
+
+// GetWalletActivityCmd defines the getwalletactivity JSON-RPC command.
+type GetWalletActivityCmd struct {
+	Account *string `jsonrpcdefault:"\"*\""`
+	Blocks  *int32  `jsonrpcdefault:"0"`
+}
+
+// NewGetWalletActivityCmd returns a new instance which can be used to issue a
+// getwalletactivity JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetWalletActivityCmd(account *string,
+	blocks *int32) *GetWalletActivityCmd {
+
+	return &GetWalletActivityCmd{
+		Account: account,
+		Blocks:  blocks,
+	}
+}
+
+// ImportLbrycrdWalletCmd defines the importlbrycrdwallet JSON-RPC command.
+type ImportLbrycrdWalletCmd struct {
+	Filename   string
+	Passphrase *string
+	Rescan     *bool `jsonrpcdefault:"true"`
+}
+
+// NewImportLbrycrdWalletCmd returns a new instance which can be used to issue
+// an importlbrycrdwallet JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewImportLbrycrdWalletCmd(filename string, passphrase *string,
+	rescan *bool) *ImportLbrycrdWalletCmd {
+
+	return &ImportLbrycrdWalletCmd{
+		Filename:   filename,
+		Passphrase: passphrase,
+		Rescan:     rescan,
+	}
+}
+
+// ImportLbrySDKWalletCmd defines the importlbrysdkwallet JSON-RPC command.
+type ImportLbrySDKWalletCmd struct {
+	Filename string
+	Password *string
+	Rescan   *bool `jsonrpcdefault:"true"`
+}
+
+// NewImportLbrySDKWalletCmd returns a new instance which can be used to issue
+// an importlbrysdkwallet JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewImportLbrySDKWalletCmd(filename string, password *string,
+	rescan *bool) *ImportLbrySDKWalletCmd {
+
+	return &ImportLbrySDKWalletCmd{
+		Filename: filename,
+		Password: password,
+		Rescan:   rescan,
+	}
+}
+
+// ImportWatchPubKeyCmd defines the importwatchpubkey JSON-RPC command.
+type ImportWatchPubKeyCmd struct {
+	PubKey      string
+	AddressType *string `jsonrpcdefault:"\"legacy\""`
+	StartHeight *int32
+}
+
+// NewImportWatchPubKeyCmd returns a new instance which can be used to issue
+// an importwatchpubkey JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewImportWatchPubKeyCmd(pubKey string, addressType *string,
+	startHeight *int32) *ImportWatchPubKeyCmd {
+
+	return &ImportWatchPubKeyCmd{
+		PubKey:      pubKey,
+		AddressType: addressType,
+		StartHeight: startHeight,
+	}
+}
+
+// ListAccountClaimsCmd defines the listaccountclaims JSON-RPC command.
+type ListAccountClaimsCmd struct {
+	Account *string `jsonrpcdefault:"\"*\""`
+}
+
+// NewListAccountClaimsCmd returns a new instance which can be used to issue a
+// listaccountclaims JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewListAccountClaimsCmd(account *string) *ListAccountClaimsCmd {
+	return &ListAccountClaimsCmd{Account: account}
+}
+
+// ListTaintedCmd defines the listtainted JSON-RPC command.
+type ListTaintedCmd struct{}
+
+// NewListTaintedCmd returns a new instance which can be used to issue a
+// listtainted JSON-RPC command.
+func NewListTaintedCmd() *ListTaintedCmd {
+	return &ListTaintedCmd{}
+}
+
+// ListAccountInfoCmd defines the listaccountinfo JSON-RPC command.
+type ListAccountInfoCmd struct {
+	MinConf *int `jsonrpcdefault:"1"`
+}
+
+// NewListAccountInfoCmd returns a new instance which can be used to issue a
+// listaccountinfo JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewListAccountInfoCmd(minConf *int) *ListAccountInfoCmd {
+	return &ListAccountInfoCmd{MinConf: minConf}
+}
+
+// ListAccountUnspentCmd defines the listaccountunspent JSON-RPC command.
+type ListAccountUnspentCmd struct {
+	Account string
+	MinConf *int `jsonrpcdefault:"1"`
+	MaxConf *int `jsonrpcdefault:"9999999"`
+}
+
+// NewListAccountUnspentCmd returns a new instance which can be used to issue a
+// listaccountunspent JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewListAccountUnspentCmd(account string, minConf,
+	maxConf *int) *ListAccountUnspentCmd {
+
+	return &ListAccountUnspentCmd{
+		Account: account,
+		MinConf: minConf,
+		MaxConf: maxConf,
+	}
+}
+
+// ListBalanceMovesCmd defines the listbalancemoves JSON-RPC command.
+type ListBalanceMovesCmd struct {
+	Account *string `jsonrpcdefault:"\"*\""`
+}
+
+// NewListBalanceMovesCmd returns a new instance which can be used to issue a
+// listbalancemoves JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewListBalanceMovesCmd(account *string) *ListBalanceMovesCmd {
+	return &ListBalanceMovesCmd{Account: account}
+}
+
+// ListDescriptorsCmd defines the listdescriptors JSON-RPC command.
+type ListDescriptorsCmd struct{}
+
+// NewListDescriptorsCmd returns a new instance which can be used to issue a
+// listdescriptors JSON-RPC command.
+func NewListDescriptorsCmd() *ListDescriptorsCmd {
+	return &ListDescriptorsCmd{}
+}
+
+// ListImportedAccountsCmd defines the listimportedaccounts JSON-RPC command.
+type ListImportedAccountsCmd struct{}
+
+// NewListImportedAccountsCmd returns a new instance which can be used to
+// issue a listimportedaccounts JSON-RPC command.
+func NewListImportedAccountsCmd() *ListImportedAccountsCmd {
+	return &ListImportedAccountsCmd{}
+}
+
+// ListInvoicesCmd defines the listinvoices JSON-RPC command.
+type ListInvoicesCmd struct {
+	State *string
+}
+
+// NewListInvoicesCmd returns a new instance which can be used to issue a
+// listinvoices JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewListInvoicesCmd(state *string) *ListInvoicesCmd {
+	return &ListInvoicesCmd{State: state}
+}
+
+// ListVaultsCmd defines the listvaults JSON-RPC command.
+type ListVaultsCmd struct{}
+
+// NewListVaultsCmd returns a new instance which can be used to issue a
+// listvaults JSON-RPC command.
+func NewListVaultsCmd() *ListVaultsCmd {
+	return &ListVaultsCmd{}
+}
+
+// ListWalletsCmd defines the listwallets JSON-RPC command.
+type ListWalletsCmd struct{}
+
+// NewListWalletsCmd returns a new instance which can be used to issue a
+// listwallets JSON-RPC command.
+func NewListWalletsCmd() *ListWalletsCmd {
+	return &ListWalletsCmd{}
+}
+
+// ListWithdrawalsCmd defines the listwithdrawals JSON-RPC command.
+type ListWithdrawalsCmd struct {
+	State *string
+}
+
+// NewListWithdrawalsCmd returns a new instance which can be used to issue a
+// listwithdrawals JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewListWithdrawalsCmd(state *string) *ListWithdrawalsCmd {
+	return &ListWithdrawalsCmd{State: state}
+}
+
+// MoveBalanceCmd defines the movebalance JSON-RPC command.
+type MoveBalanceCmd struct {
+	FromAccount string
+	ToAccount   string
+	Amount      float64
+	Comment     *string
+}
+
+// NewMoveBalanceCmd returns a new instance which can be used to issue a
+// movebalance JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewMoveBalanceCmd(fromAccount, toAccount string, amount float64,
+	comment *string) *MoveBalanceCmd {
+
+	return &MoveBalanceCmd{
+		FromAccount: fromAccount,
+		ToAccount:   toAccount,
+		Amount:      amount,
+		Comment:     comment,
+	}
+}
+
+// NotifyConfirmationsCmd defines the notifyconfirmations JSON-RPC command.
+type NotifyConfirmationsCmd struct {
+	TxID          string
+	Confirmations int32
+	URL           *string
+}
+
+// NewNotifyConfirmationsCmd returns a new instance which can be used to issue
+// a notifyconfirmations JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewNotifyConfirmationsCmd(txID string, confirmations int32,
+	url *string) *NotifyConfirmationsCmd {
+
+	return &NotifyConfirmationsCmd{
+		TxID:          txID,
+		Confirmations: confirmations,
+		URL:           url,
+	}
+}
+
+// PublishPsbtCmd defines the publishpsbt JSON-RPC command.
+type PublishPsbtCmd struct {
+	Psbt string
+}
+
+// NewPublishPsbtCmd returns a new instance which can be used to issue a
+// publishpsbt JSON-RPC command.
+func NewPublishPsbtCmd(psbt string) *PublishPsbtCmd {
+	return &PublishPsbtCmd{
+		Psbt: psbt,
+	}
+}
+
+// RecoverVaultCmd defines the recovervault JSON-RPC command.
+type RecoverVaultCmd struct {
+	Name            string
+	RecoveryPrivKey string
+	Address         string
+	FeeRate         *float64
+}
+
+// NewRecoverVaultCmd returns a new instance which can be used to issue a
+// recovervault JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewRecoverVaultCmd(name, recoveryPrivKey, address string,
+	feeRate *float64) *RecoverVaultCmd {
+
+	return &RecoverVaultCmd{
+		Name:            name,
+		RecoveryPrivKey: recoveryPrivKey,
+		Address:         address,
+		FeeRate:         feeRate,
+	}
+}
+
+// ReloadConfigCmd defines the reloadconfig JSON-RPC command.
+type ReloadConfigCmd struct{}
+
+// NewReloadConfigCmd returns a new instance which can be used to issue a
+// reloadconfig JSON-RPC command.
+func NewReloadConfigCmd() *ReloadConfigCmd {
+	return &ReloadConfigCmd{}
+}
+
+// RequestTestCoinsCmd defines the requesttestcoins JSON-RPC command.
+type RequestTestCoinsCmd struct {
+	Amount  *float64
+	Account *string `jsonrpcdefault:"\"default\""`
+	Wait    *bool   `jsonrpcdefault:"false"`
+}
+
+// NewRequestTestCoinsCmd returns a new instance which can be used to issue a
+// requesttestcoins JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewRequestTestCoinsCmd(amount *float64, account *string,
+	wait *bool) *RequestTestCoinsCmd {
+
+	return &RequestTestCoinsCmd{
+		Amount:  amount,
+		Account: account,
+		Wait:    wait,
+	}
+}
+
+// RescanImportedAccountCmd defines the rescanimportedaccount JSON-RPC
+// command.
+type RescanImportedAccountCmd struct {
+	Account     string
+	StartHeight *int32 `jsonrpcdefault:"0"`
+}
+
+// NewRescanImportedAccountCmd returns a new instance which can be used to
+// issue a rescanimportedaccount JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewRescanImportedAccountCmd(account string,
+	startHeight *int32) *RescanImportedAccountCmd {
+
+	return &RescanImportedAccountCmd{
+		Account:     account,
+		StartHeight: startHeight,
+	}
+}
+
+// SendFromVaultCmd defines the sendfromvault JSON-RPC command.
+type SendFromVaultCmd struct {
+	Name    string
+	Amounts map[string]float64 `jsonrpcusage:"{\"address\":amount,...}"`
+	FeeRate *float64
+}
+
+// NewSendFromVaultCmd returns a new instance which can be used to issue a
+// sendfromvault JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewSendFromVaultCmd(name string, amounts map[string]float64,
+	feeRate *float64) *SendFromVaultCmd {
+
+	return &SendFromVaultCmd{
+		Name:    name,
+		Amounts: amounts,
+		FeeRate: feeRate,
+	}
+}
+
+// SetImportedAccountCmd defines the setimportedaccount JSON-RPC command.
+type SetImportedAccountCmd struct {
+	Address string
+	Account string
+}
+
+// NewSetImportedAccountCmd returns a new instance which can be used to issue
+// a setimportedaccount JSON-RPC command.
+func NewSetImportedAccountCmd(address, account string) *SetImportedAccountCmd {
+	return &SetImportedAccountCmd{
+		Address: address,
+		Account: account,
+	}
+}
+
+// TaintAddressesCmd defines the taintaddresses JSON-RPC command.
+type TaintAddressesCmd struct {
+	Taint     bool
+	Addresses []string
+	Reason    *string `jsonrpcdefault:"\"\""`
+}
+
+// NewTaintAddressesCmd returns a new instance which can be used to issue a
+// taintaddresses JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewTaintAddressesCmd(taint bool, addresses []string,
+	reason *string) *TaintAddressesCmd {
+
+	return &TaintAddressesCmd{
+		Taint:     taint,
+		Addresses: addresses,
+		Reason:    reason,
+	}
+}
+
+// TaintUnspentCmd defines the taintunspent JSON-RPC command.
+type TaintUnspentCmd struct {
+	Taint        bool
+	Transactions []btcjson.TransactionInput
+	Reason       *string `jsonrpcdefault:"\"\""`
+}
+
+// NewTaintUnspentCmd returns a new instance which can be used to issue a
+// taintunspent JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewTaintUnspentCmd(taint bool, transactions []btcjson.TransactionInput,
+	reason *string) *TaintUnspentCmd {
+
+	return &TaintUnspentCmd{
+		Taint:        taint,
+		Transactions: transactions,
+		Reason:       reason,
+	}
+}
+
+// SetSpendPolicyCmd defines the setspendpolicy JSON-RPC command.  Fields which
+// are left unset keep their current value.  The passphrase is required to
+// loosen the policy.
+type SetSpendPolicyCmd struct {
+	MaxTxAmount *float64
+	DailyLimit  *float64
+	Allowlist   *[]string
+	Denylist    *[]string
+	Passphrase  *string
+}
+
+// NewSetSpendPolicyCmd returns a new instance which can be used to issue a
+// setspendpolicy JSON-RPC command.
+func NewSetSpendPolicyCmd(maxTxAmount, dailyLimit *float64, allowlist,
+	denylist *[]string, passphrase *string) *SetSpendPolicyCmd {
+
+	return &SetSpendPolicyCmd{
+		MaxTxAmount: maxTxAmount,
+		DailyLimit:  dailyLimit,
+		Allowlist:   allowlist,
+		Denylist:    denylist,
+		Passphrase:  passphrase,
+	}
+}
+
+// SignPsbtCmd defines the signpsbt JSON-RPC command.
+type SignPsbtCmd struct {
+	Psbt string
+}
+
+// NewSignPsbtCmd returns a new instance which can be used to issue a signpsbt
+// JSON-RPC command.
+func NewSignPsbtCmd(psbt string) *SignPsbtCmd {
+	return &SignPsbtCmd{
+		Psbt: psbt,
+	}
+}
+
+// StopNotifyConfirmationsCmd defines the stopnotifyconfirmations JSON-RPC
+// command.
+type StopNotifyConfirmationsCmd struct {
+	ID uint64
+}
+
+// NewStopNotifyConfirmationsCmd returns a new instance which can be used to
+// issue a stopnotifyconfirmations JSON-RPC command.
+func NewStopNotifyConfirmationsCmd(id uint64) *StopNotifyConfirmationsCmd {
+	return &StopNotifyConfirmationsCmd{ID: id}
+}
+
+// SweepAccountPsbtCmd defines the sweepaccountpsbt JSON-RPC command.
+type SweepAccountPsbtCmd struct {
+	Account string
+	Address string
+	MinConf *int `jsonrpcdefault:"1"`
+	FeeRate *float64
+}
+
+// NewSweepAccountPsbtCmd returns a new instance which can be used to issue a
+// sweepaccountpsbt JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewSweepAccountPsbtCmd(account, address string, minConf *int,
+	feeRate *float64) *SweepAccountPsbtCmd {
+
+	return &SweepAccountPsbtCmd{
+		Account: account,
+		Address: address,
+		MinConf: minConf,
+		FeeRate: feeRate,
+	}
+}
+
+// WalletGetDataCmd defines the walletgetdata JSON-RPC command.
+type WalletGetDataCmd struct {
+	Namespace string
+	Key       *string
+}
+
+// NewWalletGetDataCmd returns a new instance which can be used to issue a
+// walletgetdata JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewWalletGetDataCmd(namespace string, key *string) *WalletGetDataCmd {
+	return &WalletGetDataCmd{
+		Namespace: namespace,
+		Key:       key,
+	}
+}
+
+// WalletSetDataCmd defines the walletsetdata JSON-RPC command.
+type WalletSetDataCmd struct {
+	Namespace string
+	Key       string
+	Value     *string
+}
+
+// NewWalletSetDataCmd returns a new instance which can be used to issue a
+// walletsetdata JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewWalletSetDataCmd(namespace, key string,
+	value *string) *WalletSetDataCmd {
+
+	return &WalletSetDataCmd{
+		Namespace: namespace,
+		Key:       key,
+		Value:     value,
+	}
+}
+
+func init() {
+	// The commands in this file are only usable with a wallet server.
+	flags := btcjson.UFWalletOnly
+
+	btcjson.MustRegisterCmd("approvewithdrawal", (*ApproveWithdrawalCmd)(nil), flags)
+	btcjson.MustRegisterCmd("benchsigning", (*BenchSigningCmd)(nil), flags)
+	btcjson.MustRegisterCmd("cancelsend", (*CancelSendCmd)(nil), flags)
+	btcjson.MustRegisterCmd("cancelwithdrawal", (*CancelWithdrawalCmd)(nil), flags)
+	btcjson.MustRegisterCmd("changepublicpassphrase", (*ChangePublicPassphraseCmd)(nil), flags)
+	btcjson.MustRegisterCmd("clonewallet", (*CloneWalletCmd)(nil), flags)
+	btcjson.MustRegisterCmd("collectdebuginfo", (*CollectDebugInfoCmd)(nil), flags)
+	btcjson.MustRegisterCmd("confirmsend", (*ConfirmSendCmd)(nil), flags)
+	btcjson.MustRegisterCmd("createinvoice", (*CreateInvoiceCmd)(nil), flags)
+	btcjson.MustRegisterCmd("createpaymenturi", (*CreatePaymentURICmd)(nil), flags)
+	btcjson.MustRegisterCmd("createvault", (*CreateVaultCmd)(nil), flags)
+	btcjson.MustRegisterCmd("decodepaymenturi", (*DecodePaymentURICmd)(nil), flags)
+	btcjson.MustRegisterCmd("diagnosetransaction", (*DiagnoseTransactionCmd)(nil), flags)
+	btcjson.MustRegisterCmd("dumpgoroutines", (*DumpGoroutinesCmd)(nil), flags)
+	btcjson.MustRegisterCmd("dumpheapprofile", (*DumpHeapProfileCmd)(nil), flags)
+	btcjson.MustRegisterCmd("dumpimportedaccount", (*DumpImportedAccountCmd)(nil), flags)
+	btcjson.MustRegisterCmd("exportseedshares", (*ExportSeedSharesCmd)(nil), flags)
+	btcjson.MustRegisterCmd("exporttransactions", (*ExportTransactionsCmd)(nil), flags)
+	btcjson.MustRegisterCmd("exporttxlog", (*ExportTxLogCmd)(nil), flags)
+	btcjson.MustRegisterCmd("fastforward", (*FastForwardCmd)(nil), flags)
+	btcjson.MustRegisterCmd("fundaddress", (*FundAddressCmd)(nil), flags)
+	btcjson.MustRegisterCmd("generatetowallet", (*GenerateToWalletCmd)(nil), flags)
+	btcjson.MustRegisterCmd("getaccountinfo", (*GetAccountInfoCmd)(nil), flags)
+	btcjson.MustRegisterCmd("getbackupstatus", (*GetBackupStatusCmd)(nil), flags)
+	btcjson.MustRegisterCmd("getconnectionstatus", (*GetConnectionStatusCmd)(nil), flags)
+	btcjson.MustRegisterCmd("getdecoyaddresses", (*GetDecoyAddressesCmd)(nil), flags)
+	btcjson.MustRegisterCmd("getdiskstatus", (*GetDiskStatusCmd)(nil), flags)
+	btcjson.MustRegisterCmd("getinvoice", (*GetInvoiceCmd)(nil), flags)
+	btcjson.MustRegisterCmd("getnewaddresses", (*GetNewAddressesCmd)(nil), flags)
+	btcjson.MustRegisterCmd("getprivacyreport", (*GetPrivacyReportCmd)(nil), flags)
+	btcjson.MustRegisterCmd("getreceivedbyaddresses", (*GetReceivedByAddressesCmd)(nil), flags)
+	btcjson.MustRegisterCmd("getspendpolicy", (*GetSpendPolicyCmd)(nil), flags)
+	btcjson.MustRegisterCmd("getuptimestats", (*GetUptimeStatsCmd)(nil), flags)
+	btcjson.MustRegisterCmd("getvaultaddress", (*GetVaultAddressCmd)(nil), flags)
+	btcjson.MustRegisterCmd("getwalletactivity", (*GetWalletActivityCmd)(nil), flags)
+	btcjson.MustRegisterCmd("importlbrycrdwallet", (*ImportLbrycrdWalletCmd)(nil), flags)
+	btcjson.MustRegisterCmd("importlbrysdkwallet", (*ImportLbrySDKWalletCmd)(nil), flags)
+	btcjson.MustRegisterCmd("importwatchpubkey", (*ImportWatchPubKeyCmd)(nil), flags)
+	btcjson.MustRegisterCmd("listaccountclaims", (*ListAccountClaimsCmd)(nil), flags)
+	btcjson.MustRegisterCmd("listaccountinfo", (*ListAccountInfoCmd)(nil), flags)
+	btcjson.MustRegisterCmd("listaccountunspent", (*ListAccountUnspentCmd)(nil), flags)
+	btcjson.MustRegisterCmd("listbalancemoves", (*ListBalanceMovesCmd)(nil), flags)
+	btcjson.MustRegisterCmd("listdescriptors", (*ListDescriptorsCmd)(nil), flags)
+	btcjson.MustRegisterCmd("listimportedaccounts", (*ListImportedAccountsCmd)(nil), flags)
+	btcjson.MustRegisterCmd("listinvoices", (*ListInvoicesCmd)(nil), flags)
+	btcjson.MustRegisterCmd("listtainted", (*ListTaintedCmd)(nil), flags)
+	btcjson.MustRegisterCmd("listvaults", (*ListVaultsCmd)(nil), flags)
+	btcjson.MustRegisterCmd("listwallets", (*ListWalletsCmd)(nil), flags)
+	btcjson.MustRegisterCmd("listwithdrawals", (*ListWithdrawalsCmd)(nil), flags)
+	btcjson.MustRegisterCmd("movebalance", (*MoveBalanceCmd)(nil), flags)
+	btcjson.MustRegisterCmd("notifyconfirmations", (*NotifyConfirmationsCmd)(nil), flags)
+	btcjson.MustRegisterCmd("publishpsbt", (*PublishPsbtCmd)(nil), flags)
+	btcjson.MustRegisterCmd("recovervault", (*RecoverVaultCmd)(nil), flags)
+	btcjson.MustRegisterCmd("reloadconfig", (*ReloadConfigCmd)(nil), flags)
+	btcjson.MustRegisterCmd("requesttestcoins", (*RequestTestCoinsCmd)(nil), flags)
+	btcjson.MustRegisterCmd("rescanimportedaccount", (*RescanImportedAccountCmd)(nil), flags)
+	btcjson.MustRegisterCmd("sendfromvault", (*SendFromVaultCmd)(nil), flags)
+	btcjson.MustRegisterCmd("setimportedaccount", (*SetImportedAccountCmd)(nil), flags)
+	btcjson.MustRegisterCmd("setspendpolicy", (*SetSpendPolicyCmd)(nil), flags)
+	btcjson.MustRegisterCmd("signpsbt", (*SignPsbtCmd)(nil), flags)
+	btcjson.MustRegisterCmd("stopnotifyconfirmations", (*StopNotifyConfirmationsCmd)(nil), flags)
+	btcjson.MustRegisterCmd("sweepaccountpsbt", (*SweepAccountPsbtCmd)(nil), flags)
+	btcjson.MustRegisterCmd("taintaddresses", (*TaintAddressesCmd)(nil), flags)
+	btcjson.MustRegisterCmd("taintunspent", (*TaintUnspentCmd)(nil), flags)
+	btcjson.MustRegisterCmd("walletgetdata", (*WalletGetDataCmd)(nil), flags)
+	btcjson.MustRegisterCmd("walletsetdata", (*WalletSetDataCmd)(nil), flags)
+}
@@ -0,0 +1,653 @@
+package walletjson
+
+import "github.com/lbryio/lbcd/btcjson"
+
+// AccountClaimResult models the data returned for a claim, support or claim
+// update by the listaccountclaims command.
+type AccountClaimResult struct {
+	Account        string  `json:"account"`
+	TxID           string  `json:"txid"`
+	Vout           uint32  `json:"vout"`
+	Address        string  `json:"address"`
+	Amount         float64 `json:"amount"`
+	Confirmations  int64   `json:"confirmations"`
+	Type           string  `json:"type"`
+	Name           string  `json:"name"`
+	ClaimID        string  `json:"claimid"`
+	Kind           string  `json:"kind,omitempty"`
+	SigningChannel string  `json:"signingchannel,omitempty"`
+}
+
+// AccountInfoResult models the data returned for an account by the
+// getaccountinfo and listaccountinfo commands.
+type AccountInfoResult struct {
+	Account          string   `json:"account"`
+	Number           uint32   `json:"number"`
+	AddressTypes     []string `json:"addresstypes"`
+	Balance          float64  `json:"balance"`
+	Spendable        float64  `json:"spendable"`
+	Unconfirmed      float64  `json:"unconfirmed"`
+	Immature         float64  `json:"immature"`
+	Staked           float64  `json:"staked"`
+	Moved            float64  `json:"moved"`
+	LedgerBalance    float64  `json:"ledgerbalance"`
+	ExternalKeyCount uint32   `json:"externalkeycount"`
+	InternalKeyCount uint32   `json:"internalkeycount"`
+	ImportedKeyCount uint32   `json:"importedkeycount"`
+}
+
+// BalanceMoveResult models the data returned for a move by the movebalance
+// and listbalancemoves commands.
+type BalanceMoveResult struct {
+	ID          uint64  `json:"id"`
+	Time        int64   `json:"time"`
+	FromAccount string  `json:"fromaccount"`
+	ToAccount   string  `json:"toaccount"`
+	Amount      float64 `json:"amount"`
+	Comment     string  `json:"comment,omitempty"`
+}
+
+// CreatePaymentURIResult models the data returned from the createpaymenturi
+// command.
+type CreatePaymentURIResult struct {
+	URI    string `json:"uri"`
+	QRCode string `json:"qrcode,omitempty"`
+}
+
+// DecodePaymentURIResult models the data returned from the decodepaymenturi
+// command.
+type DecodePaymentURIResult struct {
+	Address string            `json:"address"`
+	Amount  float64           `json:"amount,omitempty"`
+	Label   string            `json:"label,omitempty"`
+	Message string            `json:"message,omitempty"`
+	Params  map[string]string `json:"params,omitempty"`
+}
+
+// DiagnoseTransactionResult models the data returned from the
+// diagnosetransaction command.
+type DiagnoseTransactionResult struct {
+	TxID             string   `json:"txid"`
+	Confirmations    int32    `json:"confirmations"`
+	TimeReceived     int64    `json:"timereceived"`
+	VSize            int      `json:"vsize"`
+	Fee              *float64 `json:"fee,omitempty"`
+	FeeRate          *float64 `json:"feerate,omitempty"`
+	EstimatedFeeRate *float64 `json:"estimatedfeerate,omitempty"`
+	InMempool        bool     `json:"inmempool"`
+	BroadcastTime    int64    `json:"broadcasttime,omitempty"`
+	UnminedParents   []string `json:"unminedparents,omitempty"`
+	MissingInputs    []string `json:"missinginputs,omitempty"`
+	Conflicts        []string `json:"conflicts,omitempty"`
+	Reasons          []string `json:"reasons"`
+	Actions          []string `json:"actions"`
+}
+
+// ExportTransactionsResult models the data returned from the
+// exporttransactions command.
+type ExportTransactionsResult struct {
+	Format   string `json:"format"`
+	Entries  int    `json:"entries"`
+	Filename string `json:"filename,omitempty"`
+	Data     string `json:"data,omitempty"`
+}
+
+// ExportTxLogResult models the data returned from the exporttxlog command.
+type ExportTxLogResult struct {
+	Transactions int     `json:"transactions"`
+	Balance      float64 `json:"balance"`
+	Digest       string  `json:"digest"`
+	Filename     string  `json:"filename,omitempty"`
+	Data         string  `json:"data,omitempty"`
+}
+
+// FastForwardResult models the data returned from the fastforward command.
+type FastForwardResult struct {
+	Time   int64 `json:"time"`
+	Offset int64 `json:"offset"`
+}
+
+// FundAddressResult models the data returned from the fundaddress command.
+type FundAddressResult struct {
+	TxID   string   `json:"txid"`
+	Blocks []string `json:"blocks"`
+}
+
+// GetBackupStatusResult models the data returned from the getbackupstatus
+// command.
+type GetBackupStatusResult struct {
+	Enabled        bool   `json:"enabled"`
+	Store          string `json:"store,omitempty"`
+	Interval       int64  `json:"interval"`
+	LastAttempt    int64  `json:"lastattempt,omitempty"`
+	LastError      string `json:"lasterror,omitempty"`
+	LastBackup     int64  `json:"lastbackup,omitempty"`
+	LastBackupName string `json:"lastbackupname,omitempty"`
+	LastBackupSize int    `json:"lastbackupsize,omitempty"`
+	NextBackup     int64  `json:"nextbackup,omitempty"`
+}
+
+// GetConnectionStatusResult models the data returned from the
+// getconnectionstatus command.
+type GetConnectionStatusResult struct {
+	Backend   string `json:"backend"`
+	Connected bool   `json:"connected"`
+	Address   string `json:"address,omitempty"`
+	Family    string `json:"family,omitempty"`
+}
+
+// GetDiskStatusResult models the data returned from the getdiskstatus command.
+type GetDiskStatusResult struct {
+	Enabled   bool   `json:"enabled"`
+	Path      string `json:"path,omitempty"`
+	LastCheck int64  `json:"lastcheck,omitempty"`
+	LastError string `json:"lasterror,omitempty"`
+	DBSize    int64  `json:"dbsize"`
+	Growth    int64  `json:"growth"`
+	Free      uint64 `json:"free"`
+	Warning   bool   `json:"warning"`
+	Low       bool   `json:"low"`
+}
+
+// GetNetworkInfoResult models the data returned from the getnetworkinfo
+// command.
+type GetNetworkInfoResult struct {
+	Name             string `json:"name"`
+	Net              string `json:"net"`
+	TestNet          bool   `json:"testnet"`
+	GenesisHash      string `json:"genesishash"`
+	DefaultPort      string `json:"defaultport"`
+	Bech32HRP        string `json:"bech32hrp"`
+	PubKeyHashAddrID byte   `json:"pubkeyhashaddrid"`
+	ScriptHashAddrID byte   `json:"scripthashaddrid"`
+	PrivateKeyID     byte   `json:"privatekeyid"`
+	HDCoinType       uint32 `json:"hdcointype"`
+	CoinbaseMaturity uint16 `json:"coinbasematurity"`
+}
+
+// ValidateAddressResult models the data returned from the validateaddress
+// command.  It extends the result of the reference implementation with the
+// script type and network of a valid address, and the reason an invalid
+// address was rejected.
+type ValidateAddressResult struct {
+	IsValid        bool     `json:"isvalid"`
+	Address        string   `json:"address,omitempty"`
+	ScriptType     string   `json:"scripttype,omitempty"`
+	Network        string   `json:"network,omitempty"`
+	IsWitness      bool     `json:"iswitness,omitempty"`
+	WitnessVersion *int     `json:"witness_version,omitempty"`
+	WitnessProgram string   `json:"witness_program,omitempty"`
+	Error          string   `json:"error,omitempty"`
+	IsMine         bool     `json:"ismine,omitempty"`
+	IsWatchOnly    bool     `json:"iswatchonly,omitempty"`
+	IsScript       bool     `json:"isscript,omitempty"`
+	PubKey         string   `json:"pubkey,omitempty"`
+	IsCompressed   bool     `json:"iscompressed,omitempty"`
+	Account        string   `json:"account,omitempty"`
+	Addresses      []string `json:"addresses,omitempty"`
+	Hex            string   `json:"hex,omitempty"`
+	Script         string   `json:"script,omitempty"`
+	SigsRequired   int32    `json:"sigsrequired,omitempty"`
+}
+
+// DecoyAddressResult models the data returned for an address by the
+// getdecoyaddresses command.
+type DecoyAddressResult struct {
+	Address string `json:"address"`
+	Index   uint32 `json:"index"`
+	Path    string `json:"path"`
+	Decoy   bool   `json:"decoy"`
+}
+
+// NewAddressResult models the data returned for an address by the
+// getnewaddresses command.
+type NewAddressResult struct {
+	Address string `json:"address"`
+	Index   uint32 `json:"index"`
+}
+
+// ReceivedByAddressResult models the data returned for an address by the
+// getreceivedbyaddresses command.
+type ReceivedByAddressResult struct {
+	Address string  `json:"address"`
+	Amount  float64 `json:"amount"`
+}
+
+// GetPrivacyReportResult models the data returned from the getprivacyreport
+// command.
+type GetPrivacyReportResult struct {
+	Transactions    int                   `json:"transactions"`
+	ReusedAddresses []ReusedAddressResult `json:"reusedaddresses"`
+	MergedInputs    []MergedInputsResult  `json:"mergedinputs"`
+	RoundChange     []RoundChangeResult   `json:"roundchange"`
+}
+
+// ReusedAddressResult models the data returned for a reused address by the
+// getprivacyreport command.
+type ReusedAddressResult struct {
+	Address string   `json:"address"`
+	Account string   `json:"account"`
+	TxIDs   []string `json:"txids"`
+}
+
+// MergedInputsResult models the data returned for a transaction spending the
+// outputs of several accounts by the getprivacyreport command.
+type MergedInputsResult struct {
+	TxID     string   `json:"txid"`
+	Accounts []string `json:"accounts"`
+}
+
+// RoundChangeResult models the data returned for a transaction whose change
+// is told apart by its amount by the getprivacyreport command.
+type RoundChangeResult struct {
+	TxID   string   `json:"txid"`
+	Change []uint32 `json:"change"`
+}
+
+// GetSpendPolicyResult models the data returned from the getspendpolicy
+// command.
+type GetSpendPolicyResult struct {
+	MaxTxAmount float64  `json:"maxtxamount"`
+	DailyLimit  float64  `json:"dailylimit"`
+	SpentToday  float64  `json:"spenttoday"`
+	Allowlist   []string `json:"allowlist"`
+	Denylist    []string `json:"denylist"`
+}
+
+// GetUptimeStatsResult models the data returned from the getuptimestats
+// command.
+type GetUptimeStatsResult struct {
+	Since        int64                    `json:"since"`
+	Starts       []int64                  `json:"starts"`
+	Uptime       int64                    `json:"uptime"`
+	Disconnected int64                    `json:"disconnected"`
+	Availability float64                  `json:"availability"`
+	Disconnects  []DisconnectWindowResult `json:"disconnects"`
+	MaxSyncLag   int32                    `json:"maxsynclag"`
+	SyncLag      []SyncLagResult          `json:"synclag"`
+}
+
+// DisconnectWindowResult models a period without a connection to the chain
+// server returned by the getuptimestats command.
+type DisconnectWindowResult struct {
+	Start int64 `json:"start"`
+	End   int64 `json:"end,omitempty"`
+}
+
+// SyncLagResult models a sample of the sync lag returned by the
+// getuptimestats command.
+type SyncLagResult struct {
+	Time   int64 `json:"time"`
+	Height int32 `json:"height"`
+	Lag    int32 `json:"lag"`
+}
+
+// ListDescriptorsResult models the data returned from the listdescriptors
+// command.
+type ListDescriptorsResult struct {
+	Descriptors []DescriptorResult `json:"descriptors"`
+}
+
+// DescriptorResult models the data returned for a descriptor by the
+// listdescriptors command.
+type DescriptorResult struct {
+	Desc      string   `json:"desc"`
+	Timestamp int64    `json:"timestamp"`
+	Active    bool     `json:"active"`
+	Internal  bool     `json:"internal"`
+	Range     []uint32 `json:"range"`
+	Next      uint32   `json:"next"`
+	Account   string   `json:"account"`
+}
+
+// ImportedAccountResult models the data returned for an imported-key account
+// by the listimportedaccounts command.
+type ImportedAccountResult struct {
+	Account   string   `json:"account"`
+	Addresses []string `json:"addresses"`
+	Balance   float64  `json:"balance"`
+}
+
+// ImportedKeyResult models the data returned for a key by the
+// dumpimportedaccount command.
+type ImportedKeyResult struct {
+	Address string `json:"address"`
+	PrivKey string `json:"privkey"`
+}
+
+// ImportLbrycrdWalletResult models the data returned from the
+// importlbrycrdwallet command.
+type ImportLbrycrdWalletResult struct {
+	Keys       int                    `json:"keys"`
+	Labels     int                    `json:"labels"`
+	Channels   []LbrycrdChannelResult `json:"channels"`
+	RescanFrom int32                  `json:"rescanfrom"`
+}
+
+// LbrycrdChannelResult models the data returned for a channel by the
+// importlbrycrdwallet command.
+type LbrycrdChannelResult struct {
+	ClaimID string `json:"claimid"`
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	TxID    string `json:"txid"`
+	Vout    uint32 `json:"vout"`
+}
+
+// ImportLbrySDKWalletResult models the data returned from the
+// importlbrysdkwallet command.
+type ImportLbrySDKWalletResult struct {
+	Accounts []string               `json:"accounts"`
+	Channels []LbrySDKChannelResult `json:"channels"`
+}
+
+// LbrySDKChannelResult models the data returned for a channel by the
+// importlbrysdkwallet command.
+type LbrySDKChannelResult struct {
+	ID      string `json:"id"`
+	Address string `json:"address"`
+}
+
+// InvoiceResult models the data returned for an invoice by the
+// createinvoice, getinvoice and listinvoices commands.
+type InvoiceResult struct {
+	ID       uint64                 `json:"id"`
+	Address  string                 `json:"address"`
+	URI      string                 `json:"uri"`
+	Amount   float64                `json:"amount"`
+	Memo     string                 `json:"memo,omitempty"`
+	Created  int64                  `json:"created"`
+	Expires  int64                  `json:"expires"`
+	State    string                 `json:"state"`
+	Received float64                `json:"received"`
+	PaidTime int64                  `json:"paidtime,omitempty"`
+	Payments []InvoicePaymentResult `json:"payments"`
+}
+
+// InvoicePaymentResult models a transaction paying an invoice.
+type InvoicePaymentResult struct {
+	TxID   string  `json:"txid"`
+	Amount float64 `json:"amount"`
+}
+
+// ListTaintedResult models the data returned from the listtainted command.
+type ListTaintedResult struct {
+	Outputs   []TaintedOutputResult  `json:"outputs"`
+	Addresses []TaintedAddressResult `json:"addresses"`
+}
+
+// TaintedOutputResult models the data returned for a tainted output by the
+// listtainted command.
+type TaintedOutputResult struct {
+	TxID   string `json:"txid"`
+	Vout   uint32 `json:"vout"`
+	Reason string `json:"reason"`
+}
+
+// TaintedAddressResult models the data returned for a tainted address by the
+// listtainted command.
+type TaintedAddressResult struct {
+	Address string `json:"address"`
+	Reason  string `json:"reason"`
+}
+
+// ListUnspentResult models the data returned from the listunspent and
+// listaccountunspent commands.  It extends the result of btcjson with the
+// taint of the output, as tainted outputs are never chosen by coin selection.
+type ListUnspentResult struct {
+	TxID          string  `json:"txid"`
+	Vout          uint32  `json:"vout"`
+	Address       string  `json:"address"`
+	Account       string  `json:"account"`
+	ScriptPubKey  string  `json:"scriptPubKey"`
+	RedeemScript  string  `json:"redeemScript,omitempty"`
+	Amount        float64 `json:"amount"`
+	Confirmations int64   `json:"confirmations"`
+	Solvable      bool    `json:"solvable"`
+	Spendable     bool    `json:"spendable"`
+	IsStake       bool    `json:"isstake"`
+	Tainted       bool    `json:"tainted"`
+	TaintReason   string  `json:"taintreason,omitempty"`
+}
+
+// GetTransactionResult models the data returned from the gettransaction
+// command.  It extends the result of lbcd with the comments the transaction
+// was sent with.
+type GetTransactionResult struct {
+	Amount          float64                               `json:"amount"`
+	Fee             float64                               `json:"fee,omitempty"`
+	Confirmations   int64                                 `json:"confirmations"`
+	BlockHash       string                                `json:"blockhash"`
+	BlockIndex      int64                                 `json:"blockindex"`
+	BlockTime       int64                                 `json:"blocktime"`
+	TxID            string                                `json:"txid"`
+	WalletConflicts []string                              `json:"walletconflicts"`
+	Time            int64                                 `json:"time"`
+	TimeReceived    int64                                 `json:"timereceived"`
+	Comment         string                                `json:"comment,omitempty"`
+	To              string                                `json:"to,omitempty"`
+	Details         []btcjson.GetTransactionDetailsResult `json:"details"`
+	Hex             string                                `json:"hex"`
+	Generated       bool                                  `json:"generated"`
+}
+
+// ListTransactionsResult models the data returned from the listtransactions,
+// listalltransactions and listaddresstransactions commands.  It extends the
+// result of btcjson with the exchange rate recorded when the transaction was
+// received, if the wallet records rates.
+type ListTransactionsResult struct {
+	Abandoned         bool     `json:"abandoned"`
+	Account           string   `json:"account"`
+	Address           string   `json:"address,omitempty"`
+	Amount            float64  `json:"amount"`
+	BIP125Replaceable string   `json:"bip125-replaceable,omitempty"`
+	BlockHash         string   `json:"blockhash,omitempty"`
+	BlockHeight       *int32   `json:"blockheight,omitempty"`
+	BlockIndex        *int64   `json:"blockindex,omitempty"`
+	BlockTime         int64    `json:"blocktime,omitempty"`
+	Category          string   `json:"category"`
+	Confirmations     int64    `json:"confirmations"`
+	Fee               *float64 `json:"fee,omitempty"`
+	Generated         bool     `json:"generated,omitempty"`
+	InvolvesWatchOnly bool     `json:"involveswatchonly,omitempty"`
+	Label             *string  `json:"label,omitempty"`
+	Time              int64    `json:"time"`
+	TimeReceived      int64    `json:"timereceived"`
+	Trusted           bool     `json:"trusted"`
+	TxID              string   `json:"txid"`
+	Vout              uint32   `json:"vout"`
+	WalletConflicts   []string `json:"walletconflicts"`
+	Comment           string   `json:"comment,omitempty"`
+	To                string   `json:"to,omitempty"`
+	OtherAccount      string   `json:"otheraccount,omitempty"`
+	FiatCurrency      string   `json:"fiatcurrency,omitempty"`
+	FiatRate          float64  `json:"fiatrate,omitempty"`
+	FiatAmount        float64  `json:"fiatamount,omitempty"`
+}
+
+// TxConfirmation describes a transaction which reached the number of
+// confirmations requested with notifyconfirmations.  It is the parameter of
+// the txconfirmed notification, and the body POSTed to webhooks.
+type TxConfirmation struct {
+	ID            uint64 `json:"id"`
+	TxID          string `json:"txid"`
+	Confirmations int32  `json:"confirmations"`
+	BlockHash     string `json:"blockhash"`
+	BlockHeight   int32  `json:"blockheight"`
+}
+
+// WatchedAddressPayment describes a transaction paying an address registered
+// with watchaddresses.  It is the parameter of the watchedaddresstx
+// notification.
+type WatchedAddressPayment struct {
+	Address     string   `json:"address"`
+	TxID        string   `json:"txid"`
+	Hex         string   `json:"hex"`
+	Vout        []uint32 `json:"vout"`
+	Amount      float64  `json:"amount"`
+	BlockHash   string   `json:"blockhash,omitempty"`
+	BlockHeight int32    `json:"blockheight"`
+}
+
+// RequestTestCoinsResult models the data returned from the requesttestcoins
+// command.
+type RequestTestCoinsResult struct {
+	Address  string  `json:"address"`
+	Amount   float64 `json:"amount,omitempty"`
+	TxID     string  `json:"txid,omitempty"`
+	Received float64 `json:"received"`
+}
+
+// SendPreviewResult models the data returned from the send commands while the
+// wallet previews sends.
+type SendPreviewResult struct {
+	Handle  string              `json:"handle"`
+	Hex     string              `json:"hex"`
+	Inputs  []SendPreviewInput  `json:"inputs"`
+	Outputs []SendPreviewOutput `json:"outputs"`
+	Fee     float64             `json:"fee"`
+	VSize   int                 `json:"vsize"`
+	Expires int64               `json:"expires"`
+}
+
+// SendPreviewInput models an input of a SendPreviewResult.
+type SendPreviewInput struct {
+	TxID   string  `json:"txid"`
+	Vout   uint32  `json:"vout"`
+	Amount float64 `json:"amount"`
+}
+
+// SendPreviewOutput models an output of a SendPreviewResult.
+type SendPreviewOutput struct {
+	Vout    uint32  `json:"vout"`
+	Address string  `json:"address,omitempty"`
+	Amount  float64 `json:"amount"`
+	Change  bool    `json:"change"`
+}
+
+// BenchSigningResult models the data returned from the benchsigning command.
+type BenchSigningResult struct {
+	Derivations         float64 `json:"derivationspersec"`
+	HardenedDerivations float64 `json:"hardenedderivationspersec"`
+	Signatures          float64 `json:"signaturespersec"`
+	Verifications       float64 `json:"verificationspersec"`
+	Inputs              int     `json:"inputs"`
+	Transactions        float64 `json:"txpersec"`
+}
+
+// ColdSendResult models the data returned from the send commands while the
+// wallet creates its sends as PSBTs to be signed offline.
+type ColdSendResult struct {
+	Psbt string  `json:"psbt"`
+	Fee  float64 `json:"fee"`
+	File string  `json:"file,omitempty"`
+}
+
+// SignPsbtResult models the data returned from the signpsbt command.
+type SignPsbtResult struct {
+	Psbt     string `json:"psbt"`
+	Signed   int    `json:"signed"`
+	Complete bool   `json:"complete"`
+}
+
+// SweepAccountPsbtResult models the data returned from the sweepaccountpsbt
+// command.
+type SweepAccountPsbtResult struct {
+	Psbt   string  `json:"psbt"`
+	Fee    float64 `json:"fee"`
+	Amount float64 `json:"amount"`
+}
+
+// VaultResult models the data returned for a vault by the listvaults
+// command.
+type VaultResult struct {
+	Name           string  `json:"name"`
+	Account        uint32  `json:"account"`
+	RecoveryPubKey string  `json:"recoverypubkey"`
+	Delay          uint16  `json:"delay"`
+	Balance        float64 `json:"balance"`
+	Spendable      float64 `json:"spendable"`
+}
+
+// WalletActivityResult models the data returned for a period by the
+// getwalletactivity command.
+type WalletActivityResult struct {
+	Date         string  `json:"date,omitempty"`
+	StartHeight  *int32  `json:"startheight,omitempty"`
+	EndHeight    *int32  `json:"endheight,omitempty"`
+	Transactions int     `json:"transactions"`
+	Received     float64 `json:"received"`
+	Sent         float64 `json:"sent"`
+	Fees         float64 `json:"fees"`
+	Claims       int     `json:"claims"`
+	Supports     int     `json:"supports"`
+	Updates      int     `json:"updates"`
+	NewAddresses int     `json:"newaddresses"`
+}
+
+// GetWalletInfoResult models the data returned from the getwalletinfo
+// command.
+type GetWalletInfoResult struct {
+	WalletVersion         int32           `json:"walletversion"`
+	Balance               float64         `json:"balance"`
+	UnconfirmedBalance    float64         `json:"unconfirmed_balance"`
+	ImmatureBalance       float64         `json:"immature_balance"`
+	Staked                float64         `json:"staked"`
+	TransactionCount      int             `json:"txcount"`
+	KeyPoolSize           uint32          `json:"keypoolsize"`
+	KeyPoolSizeHDInternal uint32          `json:"keypoolsize_hd_internal"`
+	KeyPools              []KeyPoolResult `json:"keypools"`
+	Locked                bool            `json:"locked"`
+	UnlockedUntil         int64           `json:"unlocked_until"`
+	PrivateKeysEnabled    bool            `json:"private_keys_enabled"`
+	Birthday              int64           `json:"birthday"`
+	SyncHeight            int32           `json:"syncheight"`
+	SyncHash              string          `json:"syncblockhash"`
+	Synced                bool            `json:"synced"`
+	Backend               string          `json:"backend,omitempty"`
+	BackendConnected      bool            `json:"backendconnected"`
+}
+
+// KeyPoolResult models the key counts of a key scope returned by the
+// getwalletinfo command.
+type KeyPoolResult struct {
+	AddressType string `json:"addresstype"`
+	External    uint32 `json:"external"`
+	Internal    uint32 `json:"internal"`
+	Imported    uint32 `json:"imported"`
+
+	PooledExternal uint32 `json:"pooledexternal"`
+	PooledInternal uint32 `json:"pooledinternal"`
+}
+
+// WithdrawalResult models the data returned for a withdrawal by the send,
+// approvewithdrawal, cancelwithdrawal and listwithdrawals commands.
+type WithdrawalResult struct {
+	ID        uint64                  `json:"id"`
+	State     string                  `json:"state"`
+	Amount    float64                 `json:"amount"`
+	Outputs   []WithdrawalOutput      `json:"outputs"`
+	Account   uint32                  `json:"account"`
+	Requester string                  `json:"requester"`
+	Approvers []string                `json:"approvers"`
+	Required  int                     `json:"required"`
+	Created   int64                   `json:"created"`
+	Expires   int64                   `json:"expires"`
+	TxID      string                  `json:"txid,omitempty"`
+	History   []WithdrawalEventResult `json:"history"`
+}
+
+// WithdrawalOutput models an output of a WithdrawalResult.
+type WithdrawalOutput struct {
+	Address string  `json:"address,omitempty"`
+	Amount  float64 `json:"amount"`
+}
+
+// WithdrawalEventResult models an event of the history of a
+// WithdrawalResult.
+type WithdrawalEventResult struct {
+	Time   int64  `json:"time"`
+	Action string `json:"action"`
+	User   string `json:"user,omitempty"`
+	Detail string `json:"detail,omitempty"`
+}
@@ -0,0 +1,39 @@
+// NOTE: This file is intended to house the RPC commands that are supported by
+// lbcwallet over websockets only and are not provided by the btcjson package.
+
+package walletjson
+
+import (
+	"github.com/lbryio/lbcd/btcjson"
+)
+
+// UnwatchAddressesCmd defines the unwatchaddresses JSON-RPC command.
+type UnwatchAddressesCmd struct {
+	Addresses []string
+}
+
+// NewUnwatchAddressesCmd returns a new instance which can be used to issue an
+// unwatchaddresses JSON-RPC command.
+func NewUnwatchAddressesCmd(addresses []string) *UnwatchAddressesCmd {
+	return &UnwatchAddressesCmd{Addresses: addresses}
+}
+
+// WatchAddressesCmd defines the watchaddresses JSON-RPC command.
+type WatchAddressesCmd struct {
+	Addresses []string
+}
+
+// NewWatchAddressesCmd returns a new instance which can be used to issue a
+// watchaddresses JSON-RPC command.
+func NewWatchAddressesCmd(addresses []string) *WatchAddressesCmd {
+	return &WatchAddressesCmd{Addresses: addresses}
+}
+
+func init() {
+	// The commands in this file are only usable with a wallet server over
+	// websockets.
+	flags := btcjson.UFWalletOnly | btcjson.UFWebsocketOnly
+
+	btcjson.MustRegisterCmd("unwatchaddresses", (*UnwatchAddressesCmd)(nil), flags)
+	btcjson.MustRegisterCmd("watchaddresses", (*WatchAddressesCmd)(nil), flags)
+}
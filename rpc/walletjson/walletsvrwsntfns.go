@@ -0,0 +1,52 @@
+// NOTE: This file is intended to house the RPC websocket notifications that are
+// sent by lbcwallet but are not provided by the btcjson package.
+
+package walletjson
+
+import (
+	"github.com/lbryio/lbcd/btcjson"
+)
+
+const (
+	// TxConfirmedNtfnMethod is the method used for notifications that a
+	// transaction registered with notifyconfirmations has reached the
+	// requested number of confirmations.
+	TxConfirmedNtfnMethod = "txconfirmed"
+
+	// WatchedAddressTxNtfnMethod is the method used for notifications that
+	// a transaction paying an address registered with watchaddresses was
+	// accepted to the mempool or mined.
+	WatchedAddressTxNtfnMethod = "watchedaddresstx"
+)
+
+// TxConfirmedNtfn defines the txconfirmed JSON-RPC notification.
+type TxConfirmedNtfn struct {
+	Confirmation TxConfirmation
+}
+
+// NewTxConfirmedNtfn returns a new instance which can be used to issue a
+// txconfirmed JSON-RPC notification.
+func NewTxConfirmedNtfn(confirmation TxConfirmation) *TxConfirmedNtfn {
+	return &TxConfirmedNtfn{Confirmation: confirmation}
+}
+
+// WatchedAddressTxNtfn defines the watchedaddresstx JSON-RPC notification.
+type WatchedAddressTxNtfn struct {
+	Payment WatchedAddressPayment
+}
+
+// NewWatchedAddressTxNtfn returns a new instance which can be used to issue a
+// watchedaddresstx JSON-RPC notification.
+func NewWatchedAddressTxNtfn(payment WatchedAddressPayment) *WatchedAddressTxNtfn {
+	return &WatchedAddressTxNtfn{Payment: payment}
+}
+
+func init() {
+	// The commands in this file are only usable by websockets and are
+	// notifications.
+	flags := btcjson.UFWalletOnly | btcjson.UFWebsocketOnly |
+		btcjson.UFNotification
+
+	btcjson.MustRegisterCmd(TxConfirmedNtfnMethod, (*TxConfirmedNtfn)(nil), flags)
+	btcjson.MustRegisterCmd(WatchedAddressTxNtfnMethod, (*WatchedAddressTxNtfn)(nil), flags)
+}
@@ -162,6 +162,7 @@ func (a *managedAddress) unlock(key EncryptorDecryptor) ([]byte, error) {
 			return nil, managerError(ErrCrypto, str, err)
 		}
 
+		protectKey(privKey)
 		a.privKeyCT = privKey
 	}
 
@@ -1724,6 +1724,14 @@ func forEachActiveAddress(ns walletdb.ReadBucket, scope *KeyScope,
 	return nil
 }
 
+// DeletePrivateKeys removes all private key material from the database of an
+// address manager, which becomes watch-only once loaded again: its addresses
+// are kept, but it can never be unlocked.  It must not be called on the
+// database of a loaded manager.
+func DeletePrivateKeys(ns walletdb.ReadWriteBucket) error {
+	return deletePrivateKeys(ns)
+}
+
 // deletePrivateKeys removes all private key material from the database.
 func deletePrivateKeys(ns walletdb.ReadWriteBucket) error {
 	bucket := ns.NestedReadWriteBucket(mainBucketName)
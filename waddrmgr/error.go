@@ -134,6 +134,10 @@ const (
 	// ErrAccountNotCached is returned when we attempt to perform an
 	// operation that relies on an account begin cached but it isn't.
 	ErrAccountNotCached
+
+	// ErrWatchingOnly is returned when an operation requiring the private
+	// keys is attempted on a manager whose private keys were deleted.
+	ErrWatchingOnly
 )
 
 // Map of ErrorCode values back to their constant names for pretty printing.
@@ -160,6 +164,7 @@ var errorCodeStrings = map[ErrorCode]string{
 	ErrEmptyPassphrase:   "ErrEmptyPassphrase",
 	ErrScopeNotFound:     "ErrScopeNotFound",
 	ErrAccountNotCached:  "ErrAccountNotCached",
+	ErrWatchingOnly:      "ErrWatchingOnly",
 }
 
 // String returns the ErrorCode as a human-readable name.
@@ -0,0 +1,323 @@
+package waddrmgr
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/lbryio/lbcd/btcec"
+	"github.com/lbryio/lbcutil/hdkeychain"
+	"github.com/lbryio/lbcwallet/walletdb"
+)
+
+// keyPoolBucketName is the name of the bucket of a scope holding the public
+// keys of the addresses of its accounts derived in advance, encrypted with the
+// public crypto key like those of the derived addresses.  It's created the
+// first time the keypool of the scope is filled.
+var keyPoolBucketName = []byte("keypool")
+
+// KeyPoolKey is the public key of an address of an account derived in advance
+// of its use.
+type KeyPoolKey struct {
+	Account uint32
+	Branch  uint32
+	Index   uint32
+	PubKey  *btcec.PublicKey
+}
+
+// keyPoolKey returns the key of an entry of the keypool bucket.  The numbers
+// are serialized in big-endian order, so that the entries of a branch are
+// contiguous and sorted by index.
+func keyPoolKey(account, branch, index uint32) []byte {
+	k := make([]byte, 12)
+	binary.BigEndian.PutUint32(k[0:4], account)
+	binary.BigEndian.PutUint32(k[4:8], branch)
+	binary.BigEndian.PutUint32(k[8:12], index)
+	return k
+}
+
+// fetchReadKeyPoolBucket returns the keypool bucket of the scope, or nil when
+// it hasn't been created yet.
+func fetchReadKeyPoolBucket(ns walletdb.ReadBucket,
+	scope *KeyScope) (walletdb.ReadBucket, error) {
+
+	scopedBucket, err := fetchReadScopeBucket(ns, scope)
+	if err != nil {
+		return nil, err
+	}
+	return scopedBucket.NestedReadBucket(keyPoolBucketName), nil
+}
+
+// forEachKeyPoolIndex calls fn with the index of each entry of the keypool
+// bucket for the branch of the account, in order.
+func forEachKeyPoolIndex(bucket walletdb.ReadBucket, account, branch uint32,
+	fn func(index uint32) error) error {
+
+	prefix := keyPoolKey(account, branch, 0)[:8]
+	c := bucket.ReadCursor()
+	for k, _ := c.Seek(prefix); k != nil; k, _ = c.Next() {
+		if len(k) != 12 || !bytes.HasPrefix(k, prefix) {
+			break
+		}
+		if err := fn(binary.BigEndian.Uint32(k[8:])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetchKeyPoolPubKey returns the public key at the index of the branch of the
+// account from the keypool, or nil when it isn't pooled.
+//
+// This function MUST be called with the manager lock held for reads.
+func (s *ScopedKeyManager) fetchKeyPoolPubKey(ns walletdb.ReadBucket, account,
+	branch, index uint32) (*btcec.PublicKey, error) {
+
+	bucket, err := fetchReadKeyPoolBucket(ns, &s.scope)
+	if err != nil || bucket == nil {
+		return nil, err
+	}
+	v := bucket.Get(keyPoolKey(account, branch, index))
+	if v == nil {
+		return nil, nil
+	}
+	serializedKey, err := s.rootManager.cryptoKeyPub.Decrypt(v)
+	if err != nil {
+		str := fmt.Sprintf("failed to decrypt pooled key %d/%d of "+
+			"account %d", branch, index, account)
+		return nil, managerError(ErrCrypto, str, err)
+	}
+	pubKey, err := btcec.ParsePubKey(serializedKey, btcec.S256())
+	if err != nil {
+		str := fmt.Sprintf("invalid pooled key %d/%d of account %d",
+			branch, index, account)
+		return nil, managerError(ErrDatabase, str, err)
+	}
+	return pubKey, nil
+}
+
+// deleteKeyPoolKey removes the entry at the index of the branch of the
+// account from the keypool, if any.
+func deleteKeyPoolKey(ns walletdb.ReadWriteBucket, scope *KeyScope, account,
+	branch, index uint32) error {
+
+	scopedBucket, err := fetchWriteScopeBucket(ns, scope)
+	if err != nil {
+		return err
+	}
+	bucket := scopedBucket.NestedReadWriteBucket(keyPoolBucketName)
+	if bucket == nil {
+		return nil
+	}
+	return bucket.Delete(keyPoolKey(account, branch, index))
+}
+
+// DeriveKeyPool derives the public keys of the addresses of both branches of
+// the account missing from its keypool, so that it holds the keys of the size
+// next unused addresses of each branch.  The keys aren't stored: they are
+// passed to PutKeyPool in a later transaction, so that the derivations are
+// performed without holding the database write lock.
+//
+// The keys are derived from the account public key, which is available
+// whether or not the manager is locked.
+func (s *ScopedKeyManager) DeriveKeyPool(ns walletdb.ReadBucket, account,
+	size uint32) ([]KeyPoolKey, error) {
+
+	if account > MaxAccountNum {
+		err := managerError(ErrAccountNumTooHigh, errAcctTooHigh, nil)
+		return nil, err
+	}
+
+	// Only the account key and the next indexes are read with the manager
+	// lock held, the derivations are performed once it's released.
+	s.mtx.Lock()
+	acctInfo, err := s.loadAccountInfo(ns, account)
+	if err != nil {
+		s.mtx.Unlock()
+		return nil, err
+	}
+	acctKey := acctInfo.acctKeyPub
+	nextIndex := acctInfo.nextIndex
+	s.mtx.Unlock()
+
+	pool, err := fetchReadKeyPoolBucket(ns, &s.scope)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []KeyPoolKey
+	for _, branch := range []uint32{ExternalBranch, InternalBranch} {
+		pooled := make(map[uint32]struct{})
+		if pool != nil {
+			err := forEachKeyPoolIndex(pool, account, branch,
+				func(index uint32) error {
+					pooled[index] = struct{}{}
+					return nil
+				})
+			if err != nil {
+				return nil, maybeConvertDbError(err)
+			}
+		}
+
+		var branchKey *hdkeychain.ExtendedKey
+		end := uint64(nextIndex[branch]) + uint64(size)
+		if end > MaxAddressesPerAccount {
+			end = MaxAddressesPerAccount
+		}
+		for index := nextIndex[branch]; uint64(index) < end; index++ {
+			if _, ok := pooled[index]; ok {
+				continue
+			}
+			if branchKey == nil {
+				branchKey, err = acctKey.Derive(branch)
+				if err != nil {
+					str := fmt.Sprintf("failed to derive "+
+						"extended key branch %d",
+						branch)
+					return nil, managerError(
+						ErrKeyChain, str, err,
+					)
+				}
+			}
+
+			key, err := branchKey.Derive(index)
+			if err == hdkeychain.ErrInvalidChild {
+				// The invalid children are skipped, just as
+				// when the addresses are derived on demand.
+				continue
+			}
+			if err != nil {
+				str := fmt.Sprintf("failed to generate "+
+					"child %d", index)
+				return nil, managerError(ErrKeyChain, str, err)
+			}
+			pubKey, err := key.ECPubKey()
+			if err != nil {
+				str := fmt.Sprintf("failed to generate "+
+					"child %d", index)
+				return nil, managerError(ErrKeyChain, str, err)
+			}
+			keys = append(keys, KeyPoolKey{
+				Account: account,
+				Branch:  branch,
+				Index:   index,
+				PubKey:  pubKey,
+			})
+		}
+	}
+
+	return keys, nil
+}
+
+// PutKeyPool stores the keys returned by DeriveKeyPool in the keypool of their
+// accounts.  The keys of addresses which were derived in the meantime are
+// skipped, and the entries of the keypool of the accounts below their next
+// indexes are removed.
+func (s *ScopedKeyManager) PutKeyPool(ns walletdb.ReadWriteBucket,
+	keys []KeyPoolKey) error {
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	scopedBucket, err := fetchWriteScopeBucket(ns, &s.scope)
+	if err != nil {
+		return err
+	}
+	pool, err := scopedBucket.CreateBucketIfNotExists(keyPoolBucketName)
+	if err != nil {
+		return maybeConvertDbError(err)
+	}
+
+	nextIndexes := make(map[uint32][2]uint32)
+	for _, key := range keys {
+		nextIndex, ok := nextIndexes[key.Account]
+		if !ok {
+			acctInfo, err := s.loadAccountInfo(ns, key.Account)
+			if err != nil {
+				return err
+			}
+			nextIndex = acctInfo.nextIndex
+			nextIndexes[key.Account] = nextIndex
+		}
+		if key.Index < nextIndex[key.Branch] {
+			continue
+		}
+
+		encryptedKey, err := s.rootManager.cryptoKeyPub.Encrypt(
+			key.PubKey.SerializeCompressed(),
+		)
+		if err != nil {
+			str := fmt.Sprintf("failed to encrypt pooled key %d/%d "+
+				"of account %d", key.Branch, key.Index,
+				key.Account)
+			return managerError(ErrCrypto, str, err)
+		}
+		k := keyPoolKey(key.Account, key.Branch, key.Index)
+		if err := pool.Put(k, encryptedKey); err != nil {
+			return maybeConvertDbError(err)
+		}
+	}
+
+	branches := []uint32{ExternalBranch, InternalBranch}
+	for account, nextIndex := range nextIndexes {
+		for _, branch := range branches {
+			var stale [][]byte
+			err := forEachKeyPoolIndex(pool, account, branch,
+				func(index uint32) error {
+					if index >= nextIndex[branch] {
+						return nil
+					}
+					stale = append(stale, keyPoolKey(
+						account, branch, index,
+					))
+					return nil
+				})
+			if err != nil {
+				return maybeConvertDbError(err)
+			}
+			for _, k := range stale {
+				if err := pool.Delete(k); err != nil {
+					return maybeConvertDbError(err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// KeyPoolSize returns the number of the next unused external and internal
+// addresses of the account whose keys are pooled.
+func (s *ScopedKeyManager) KeyPoolSize(ns walletdb.ReadBucket,
+	account uint32) (uint32, uint32, error) {
+
+	s.mtx.Lock()
+	acctInfo, err := s.loadAccountInfo(ns, account)
+	if err != nil {
+		s.mtx.Unlock()
+		return 0, 0, err
+	}
+	nextIndex := acctInfo.nextIndex
+	s.mtx.Unlock()
+
+	pool, err := fetchReadKeyPoolBucket(ns, &s.scope)
+	if err != nil || pool == nil {
+		return 0, 0, err
+	}
+
+	var sizes [2]uint32
+	for _, branch := range []uint32{ExternalBranch, InternalBranch} {
+		err := forEachKeyPoolIndex(pool, account, branch,
+			func(index uint32) error {
+				if index >= nextIndex[branch] {
+					sizes[branch]++
+				}
+				return nil
+			})
+		if err != nil {
+			return 0, 0, maybeConvertDbError(err)
+		}
+	}
+
+	return sizes[ExternalBranch], sizes[InternalBranch], nil
+}
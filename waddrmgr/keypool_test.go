@@ -0,0 +1,84 @@
+package waddrmgr
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lbryio/lbcwallet/walletdb"
+	"github.com/stretchr/testify/require"
+)
+
+// TestKeyPoolEncrypted ensures the public keys of the keypool are stored
+// encrypted, and that the addresses derived next are those of the pooled keys.
+func TestKeyPoolEncrypted(t *testing.T) {
+	t.Parallel()
+
+	teardown, db, mgr := setupManager(t)
+	defer teardown()
+
+	scopedMgr, err := mgr.FetchScopedKeyManager(KeyScopeBIP0084)
+	require.NoError(t, err)
+
+	var keys []KeyPoolKey
+	err = walletdb.View(db, func(tx walletdb.ReadTx) error {
+		ns := tx.ReadBucket(waddrmgrNamespaceKey)
+		keys, err = scopedMgr.DeriveKeyPool(ns, DefaultAccountNum, 5)
+		return err
+	})
+	require.NoError(t, err)
+	require.Len(t, keys, 10)
+
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		return scopedMgr.PutKeyPool(ns, keys)
+	})
+	require.NoError(t, err)
+
+	// None of the entries of the keypool bucket may hold a serialized
+	// public key, or any of the pooled keys.
+	var entries int
+	err = walletdb.View(db, func(tx walletdb.ReadTx) error {
+		ns := tx.ReadBucket(waddrmgrNamespaceKey)
+		pool, err := fetchReadKeyPoolBucket(ns, &scopedMgr.scope)
+		if err != nil {
+			return err
+		}
+		require.NotNil(t, pool)
+
+		return pool.ForEach(func(_, v []byte) error {
+			entries++
+			require.NotEqual(t, 33, len(v))
+			for _, key := range keys {
+				pubKey := key.PubKey.SerializeCompressed()
+				require.False(t, bytes.Contains(v, pubKey))
+			}
+			return nil
+		})
+	})
+	require.NoError(t, err)
+	require.Equal(t, len(keys), entries)
+
+	// The next external address is the one of the first pooled key, which
+	// is then removed from the keypool.
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		addrs, err := scopedMgr.NextAddresses(
+			ns, DefaultAccountNum, ExternalBranch, 1,
+		)
+		if err != nil {
+			return err
+		}
+		pubKeyAddr, ok := addrs[0].(ManagedPubKeyAddress)
+		require.True(t, ok)
+		require.True(t, pubKeyAddr.PubKey().IsEqual(keys[0].PubKey))
+
+		external, internal, err := scopedMgr.KeyPoolSize(
+			ns, DefaultAccountNum,
+		)
+		require.NoError(t, err)
+		require.Equal(t, uint32(4), external)
+		require.Equal(t, uint32(5), internal)
+		return nil
+	})
+	require.NoError(t, err)
+}
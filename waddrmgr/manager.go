@@ -319,11 +319,31 @@ const (
 	CKTPublic
 )
 
+// DefaultPublicPassphrase is the passphrase securing the public data of every
+// address manager when it is created.  It may later be replaced with
+// ChangePublicPassphrase, after which the new passphrase is required to open
+// the manager.
+const DefaultPublicPassphrase = "public"
+
 // newCryptoKey is used as a way to replace the new crypto key generation
 // function used so tests can provide a version that fails for testing error
 // paths.
 var newCryptoKey = defaultNewCryptoKey
 
+// protectFailure ensures a failure to protect key memory is only logged once.
+var protectFailure sync.Once
+
+// protectKey attempts to keep the memory holding long lived secret key
+// material out of swap and core dumps.  This is a best effort as the process
+// may lack the privilege or resource limits to lock memory.
+func protectKey(key []byte) {
+	if err := zero.Protect(key); err != nil {
+		protectFailure.Do(func() {
+			log.Debugf("Unable to protect key memory: %v", err)
+		})
+	}
+}
+
 // Manager represents a concurrency safe crypto currency address manager and
 // key store.
 type Manager struct {
@@ -555,6 +575,8 @@ func (m *Manager) NewScopedKeyManager(ns walletdb.ReadWriteBucket,
 		addrSchema:   addrSchema,
 		rootManager:  m,
 		addrs:        make(map[addrKey]ManagedAddress),
+		missingAddrs: newMissingAddrCache(),
+		addrAccounts: make(map[addrKey]uint32),
 		acctInfo:     make(map[uint32]*accountInfo),
 		privKeyCache: map[DerivationPath]*list.Element{},
 		privKeyLru:   list.New(),
@@ -569,6 +591,75 @@ func (m *Manager) NewScopedKeyManager(ns walletdb.ReadWriteBucket,
 	return m.scopedManagers[scope], nil
 }
 
+// RootPrivKey returns the master root extended private key of the manager,
+// which every scoped manager is derived from.  The manager must be unlocked,
+// and the root key must not have been neutered from the database.
+func (m *Manager) RootPrivKey(ns walletdb.ReadBucket) (*hdkeychain.ExtendedKey,
+	error) {
+
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	if m.locked {
+		return nil, managerError(ErrLocked, errLocked, nil)
+	}
+
+	masterRootPrivEnc, _ := fetchMasterHDKeys(ns)
+	if masterRootPrivEnc == nil {
+		str := "no master root private key found"
+		return nil, managerError(ErrKeyChain, str, nil)
+	}
+
+	serializedMasterRootPriv, err :=
+		m.cryptoKeyPriv.Decrypt(masterRootPrivEnc)
+	if err != nil {
+		str := "failed to decrypt master root serialized private key"
+		return nil, managerError(ErrLocked, str, err)
+	}
+	defer zero.Bytes(serializedMasterRootPriv)
+
+	rootPriv, err := hdkeychain.NewKeyFromString(
+		string(serializedMasterRootPriv),
+	)
+	if err != nil {
+		str := "failed to create master extended private key"
+		return nil, managerError(ErrKeyChain, str, err)
+	}
+	return rootPriv, nil
+}
+
+// RootPubKey returns the master root extended public key of the manager,
+// whose fingerprint identifies the root of the key derivation paths.  Unlike
+// RootPrivKey, it is available while the manager is locked.
+func (m *Manager) RootPubKey(ns walletdb.ReadBucket) (*hdkeychain.ExtendedKey,
+	error) {
+
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	_, masterRootPubEnc := fetchMasterHDKeys(ns)
+	if masterRootPubEnc == nil {
+		str := "no master root public key found"
+		return nil, managerError(ErrKeyChain, str, nil)
+	}
+
+	serializedMasterRootPub, err :=
+		m.cryptoKeyPub.Decrypt(masterRootPubEnc)
+	if err != nil {
+		str := "failed to decrypt master root serialized public key"
+		return nil, managerError(ErrCrypto, str, err)
+	}
+
+	rootPub, err := hdkeychain.NewKeyFromString(
+		string(serializedMasterRootPub),
+	)
+	if err != nil {
+		str := "failed to create master extended public key"
+		return nil, managerError(ErrKeyChain, str, err)
+	}
+	return rootPub, nil
+}
+
 // FetchScopedKeyManager attempts to fetch an active scoped manager according to
 // its registered scope. If the manger is found, then a nil error is returned
 // along with the active scoped manager. Otherwise, a nil manager and a non-nil
@@ -600,6 +691,30 @@ func (m *Manager) ActiveScopedKeyManagers() []*ScopedKeyManager {
 	return scopedManagers
 }
 
+// IsForNet returns whether the extended public keys of the default accounts,
+// as they were stored when the accounts were created, belong to the network
+// the manager was opened for.  Scopes without a default account are skipped.
+// Note that networks sharing the same HD key versions, such as testnet and
+// regtest, can not be told apart.
+func (m *Manager) IsForNet(ns walletdb.ReadBucket) (bool, error) {
+	for _, s := range m.ActiveScopedKeyManagers() {
+		s.mtx.Lock()
+		acctInfo, err := s.loadAccountInfo(ns, DefaultAccountNum)
+		s.mtx.Unlock()
+		if err != nil {
+			if IsError(err, ErrAccountNotFound) {
+				continue
+			}
+			return false, err
+		}
+		if !acctInfo.acctKeyPub.IsForNet(m.chainParams) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
 // ScopesForExternalAddrType returns the set of key scopes that are able to
 // produce the target address type as external addresses.
 func (m *Manager) ScopesForExternalAddrType(addrType AddressType) []KeyScope {
@@ -670,6 +785,39 @@ func (m *Manager) Address(ns walletdb.ReadBucket,
 	return nil, managerError(ErrAddressNotFound, str, nil)
 }
 
+// HasPrivateKey returns whether the private key of a managed address is held
+// by the manager, so that its outputs can be spent once the manager is
+// unlocked.  It is false for watch-only addresses, the addresses of
+// watch-only accounts, script addresses, and every address once the private
+// keys of the manager were deleted.
+func (m *Manager) HasPrivateKey(ns walletdb.ReadBucket,
+	addr ManagedAddress) (bool, error) {
+
+	if m.WatchOnly() {
+		return false, nil
+	}
+
+	a, ok := addr.(*managedAddress)
+	if !ok {
+		return false, nil
+	}
+	if a.imported {
+		return len(a.privKeyEncrypted) != 0, nil
+	}
+
+	// The private keys of derived addresses are only derived once the
+	// manager is unlocked, so the account key tells whether they can be.
+	s := a.manager
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	acctInfo, err := s.loadAccountInfo(ns, a.derivationPath.InternalAccount)
+	if err != nil {
+		return false, err
+	}
+	return len(acctInfo.acctKeyEncrypted) != 0, nil
+}
+
 // MarkUsed updates the used flag for the provided address.
 func (m *Manager) MarkUsed(ns walletdb.ReadWriteBucket, address btcutil.Address) error {
 	m.mtx.RLock()
@@ -825,6 +973,15 @@ func (m *Manager) ChainParams() *chaincfg.Params {
 	return m.chainParams
 }
 
+// WatchOnly returns whether the private keys of the manager were deleted, in
+// which case it can never be unlocked.
+func (m *Manager) WatchOnly() bool {
+	// NOTE: No need for mutex here since the encrypted crypto private key
+	// is only missing when loaded without it, and is never removed.
+
+	return m.cryptoKeyPrivEncrypted == nil
+}
+
 // ChangePassphrase changes passphrase to the provided value.  The new
 // passphrase keys are derived using the scrypt parameters in the options, so
 // changing the passphrase may be used to bump the computational difficulty
@@ -832,37 +989,65 @@ func (m *Manager) ChainParams() *chaincfg.Params {
 func (m *Manager) ChangePassphrase(ns walletdb.ReadWriteBucket, oldPassphrase,
 	newPassphrase []byte, config *ScryptOptions) error {
 
-	m.mtx.Lock()
-	defer m.mtx.Unlock()
+	if m.WatchOnly() {
+		str := "the private keys of the manager were deleted"
+		return managerError(ErrWatchingOnly, str, nil)
+	}
 
 	// Ensure the provided old passphrase is correct.  This check is done
 	// using a copy of the appropriate master key depending on the private
 	// flag to ensure the current state is not altered.  The temp key is
-	// cleared when done to avoid leaving a copy in memory.
-	var keyName string
-	secretKey := snacl.SecretKey{Key: &snacl.CryptoKey{}}
-	keyName = "private"
-	secretKey.Parameters = m.masterKeyPriv.Parameters
+	// cleared when done to avoid leaving a copy in memory.  Both keys are
+	// derived without holding the manager lock, which is taken again
+	// once the old key is known to still be current.
+	var (
+		keyName      = "private"
+		secretKey    *snacl.SecretKey
+		newMasterKey *snacl.SecretKey
+	)
+	for {
+		m.mtx.RLock()
+		params := m.masterKeyPriv.Parameters
+		m.mtx.RUnlock()
 
-	if err := secretKey.DeriveKey(&oldPassphrase); err != nil {
-		if err == snacl.ErrInvalidPassword {
-			str := fmt.Sprintf("invalid passphrase for %s master "+
-				"key", keyName)
-			return managerError(ErrWrongPassphrase, str, nil)
+		var err error
+		secretKey, err = deriveMasterKey(params, oldPassphrase)
+		if err != nil {
+			secretKey.Zero()
+			if err == snacl.ErrInvalidPassword {
+				str := fmt.Sprintf("invalid passphrase for %s "+
+					"master key", keyName)
+				return managerError(ErrWrongPassphrase, str, nil)
+			}
+
+			str := fmt.Sprintf("failed to derive %s master key",
+				keyName)
+			return managerError(ErrCrypto, str, err)
 		}
 
-		str := fmt.Sprintf("failed to derive %s master key", keyName)
-		return managerError(ErrCrypto, str, err)
+		// Generate a new master key from the passphrase which is used
+		// to secure the actual secret keys.
+		newMasterKey, err = newSecretKey(&newPassphrase, config)
+		if err != nil {
+			secretKey.Zero()
+			str := "failed to create new master private key"
+			return managerError(ErrCrypto, str, err)
+		}
+
+		m.mtx.Lock()
+		if m.masterKeyPriv.Parameters == params {
+			break
+		}
+
+		// The passphrase was changed during the derivation.
+		m.mtx.Unlock()
+		secretKey.Zero()
+		newMasterKey.Zero()
 	}
+	defer m.mtx.Unlock()
 	defer secretKey.Zero()
 
-	// Generate a new master key from the passphrase which is used to secure
-	// the actual secret keys.
-	newMasterKey, err := newSecretKey(&newPassphrase, config)
-	if err != nil {
-		str := "failed to create new master private key"
-		return managerError(ErrCrypto, str, err)
-	}
+	protectKey(newMasterKey.Key[:])
 	newKeyParams := newMasterKey.Marshal()
 
 	// Technically, the locked state could be checked here to only
@@ -875,7 +1060,7 @@ func (m *Manager) ChangePassphrase(ns walletdb.ReadWriteBucket, oldPassphrase,
 	// Create a new salt that will be used for hashing the new
 	// passphrase each unlock.
 	var passphraseSalt [saltSize]byte
-	_, err = rand.Read(passphraseSalt[:])
+	_, err := rand.Read(passphraseSalt[:])
 	if err != nil {
 		str := "failed to read random source for passhprase salt"
 		return managerError(ErrCrypto, str, err)
@@ -947,6 +1132,64 @@ func (m *Manager) ChangePassphrase(ns walletdb.ReadWriteBucket, oldPassphrase,
 	return nil
 }
 
+// ChangePublicPassphrase re-encrypts the crypto public key, which protects all
+// public data of the manager, under a master key derived from the new public
+// passphrase.  No private key material is touched, so this works whether or
+// not the manager is locked.  The new passphrase key is derived using the
+// scrypt parameters in the options.
+func (m *Manager) ChangePublicPassphrase(ns walletdb.ReadWriteBucket,
+	oldPassphrase, newPassphrase []byte, config *ScryptOptions) error {
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	// Ensure the provided old passphrase is correct using a copy of the
+	// master public key so the current state is not altered.
+	secretKey := snacl.SecretKey{Key: &snacl.CryptoKey{}}
+	secretKey.Parameters = m.masterKeyPub.Parameters
+	if err := secretKey.DeriveKey(&oldPassphrase); err != nil {
+		if err == snacl.ErrInvalidPassword {
+			str := "invalid passphrase for public master key"
+			return managerError(ErrWrongPassphrase, str, nil)
+		}
+
+		str := "failed to derive public master key"
+		return managerError(ErrCrypto, str, err)
+	}
+	defer secretKey.Zero()
+
+	newMasterKey, err := newSecretKey(&newPassphrase, config)
+	if err != nil {
+		str := "failed to create new master public key"
+		return managerError(ErrCrypto, str, err)
+	}
+
+	// The clear text crypto public key is always in memory, so it only
+	// needs to be encrypted with the new master key.
+	encPub, err := newMasterKey.Encrypt(m.cryptoKeyPub.Bytes())
+	if err != nil {
+		newMasterKey.Zero()
+		str := "failed to encrypt crypto public key"
+		return managerError(ErrCrypto, str, err)
+	}
+
+	err = putCryptoKeys(ns, encPub, nil, nil)
+	if err != nil {
+		newMasterKey.Zero()
+		return maybeConvertDbError(err)
+	}
+	err = putMasterKeyParams(ns, newMasterKey.Marshal(), nil)
+	if err != nil {
+		newMasterKey.Zero()
+		return maybeConvertDbError(err)
+	}
+
+	m.masterKeyPub.Zero()
+	m.masterKeyPub = newMasterKey
+
+	return nil
+}
+
 // IsLocked returns whether or not the address managed is locked.  When it is
 // unlocked, the decryption key needed to decrypt private keys used for signing
 // is in memory.
@@ -982,32 +1225,114 @@ func (m *Manager) Lock() error {
 	return nil
 }
 
+// deriveMasterKey derives the master private key with the given parameters
+// from a passphrase.  The derivation is deliberately slow, so it is done
+// without holding the manager mutex to not block the readers of the manager
+// in the meantime.  The returned key is never nil, even on error, and must be
+// zeroed by the caller.
+func deriveMasterKey(params snacl.Parameters, passphrase []byte) (*snacl.SecretKey,
+	error) {
+
+	secretKey := &snacl.SecretKey{
+		Key:        &snacl.CryptoKey{},
+		Parameters: params,
+	}
+	return secretKey, secretKey.DeriveKey(&passphrase)
+}
+
+// checkPassphrase checks the passphrase of an unlocked manager against the
+// hash of the passphrase it was unlocked with, locking the manager when they
+// do not match.
+//
+// This function MUST be called with the manager lock held for writes.
+func (m *Manager) checkPassphrase(passphrase []byte) error {
+	saltedPassphrase := append(m.passphraseSalt[:], passphrase...)
+	hashedPassphrase := sha512.Sum512(saltedPassphrase)
+	zero.Bytes(saltedPassphrase)
+	if hashedPassphrase != m.hashedPassphrase {
+		m.lock()
+		str := "invalid passphrase for master private key"
+		return managerError(ErrWrongPassphrase, str, nil)
+	}
+	return nil
+}
+
+// CheckPassphrase returns an error with ErrWrongPassphrase unless passphrase
+// is the private passphrase of the manager.  Unlike Unlock, the manager is
+// neither unlocked nor locked.
+func (m *Manager) CheckPassphrase(passphrase []byte) error {
+	if m.WatchOnly() {
+		str := "the private keys of the manager were deleted"
+		return managerError(ErrWatchingOnly, str, nil)
+	}
+
+	m.mtx.RLock()
+	params := m.masterKeyPriv.Parameters
+	m.mtx.RUnlock()
+
+	secretKey, err := deriveMasterKey(params, passphrase)
+	secretKey.Zero()
+	switch {
+	case err == snacl.ErrInvalidPassword:
+		str := "invalid passphrase for master private key"
+		return managerError(ErrWrongPassphrase, str, nil)
+	case err != nil:
+		str := "failed to derive master private key"
+		return managerError(ErrCrypto, str, err)
+	}
+	return nil
+}
+
 // Unlock derives the master private key from the specified passphrase.  An
 // invalid passphrase will return an error.  Otherwise, the derived secret key
 // is stored in memory until the address manager is locked.  Any failures that
 // occur during this function will result in the address manager being locked,
 // even if it was already unlocked prior to calling this function.
+//
+// The manager remains readable while the key is derived.
 func (m *Manager) Unlock(ns walletdb.ReadBucket, passphrase []byte) error {
+	if m.WatchOnly() {
+		str := "the private keys of the manager were deleted"
+		return managerError(ErrWatchingOnly, str, nil)
+	}
 
-	m.mtx.Lock()
+	var (
+		masterKeyPriv *snacl.SecretKey
+		err           error
+	)
+	for {
+		m.mtx.Lock()
+
+		// Avoid actually unlocking if the manager is already unlocked
+		// and the passphrases match.
+		if !m.locked {
+			defer m.mtx.Unlock()
+			return m.checkPassphrase(passphrase)
+		}
+		params := m.masterKeyPriv.Parameters
+		m.mtx.Unlock()
+
+		// Derive the master private key using the provided passphrase.
+		masterKeyPriv, err = deriveMasterKey(params, passphrase)
+
+		m.mtx.Lock()
+		if m.masterKeyPriv.Parameters == params {
+			break
+		}
+
+		// The passphrase was changed during the derivation.
+		m.mtx.Unlock()
+		masterKeyPriv.Zero()
+	}
 	defer m.mtx.Unlock()
+	defer masterKeyPriv.Zero()
 
-	// Avoid actually unlocking if the manager is already unlocked
-	// and the passphrases match.
+	// Another unlock may have completed during the derivation.
 	if !m.locked {
-		saltedPassphrase := append(m.passphraseSalt[:], passphrase...)
-		hashedPassphrase := sha512.Sum512(saltedPassphrase)
-		zero.Bytes(saltedPassphrase)
-		if hashedPassphrase != m.hashedPassphrase {
-			m.lock()
-			str := "invalid passphrase for master private key"
-			return managerError(ErrWrongPassphrase, str, nil)
-		}
-		return nil
+		return m.checkPassphrase(passphrase)
 	}
 
-	// Derive the master private key using the provided passphrase.
-	if err := m.masterKeyPriv.DeriveKey(&passphrase); err != nil {
+	if err != nil {
 		m.lock()
 		if err == snacl.ErrInvalidPassword {
 			str := "invalid passphrase for master private key"
@@ -1017,6 +1342,7 @@ func (m *Manager) Unlock(ns walletdb.ReadBucket, passphrase []byte) error {
 		str := "failed to derive master private key"
 		return managerError(ErrCrypto, str, err)
 	}
+	copy(m.masterKeyPriv.Key[:], masterKeyPriv.Key[:])
 
 	// Use the master private key to decrypt the crypto private key.
 	decryptedKey, err := m.masterKeyPriv.Decrypt(m.cryptoKeyPrivEncrypted)
@@ -1032,6 +1358,11 @@ func (m *Manager) Unlock(ns walletdb.ReadBucket, passphrase []byte) error {
 	// extended keys.
 	for _, manager := range m.scopedManagers {
 		for account, acctInfo := range manager.acctInfo {
+			// Watch-only accounts have no private key.
+			if len(acctInfo.acctKeyEncrypted) == 0 {
+				continue
+			}
+
 			decrypted, err := m.cryptoKeyPriv.Decrypt(acctInfo.acctKeyEncrypted)
 			if err != nil {
 				m.lock()
@@ -1063,6 +1394,15 @@ func (m *Manager) Unlock(ns walletdb.ReadBucket, passphrase []byte) error {
 				return err
 			}
 
+			// The keys of watch-only accounts are derived from
+			// their public key, and have no private key to store.
+			if !addressKey.IsPrivate() {
+				addressKey.Zero()
+				manager.deriveOnUnlock[0] = nil
+				manager.deriveOnUnlock = manager.deriveOnUnlock[1:]
+				continue
+			}
+
 			// It's ok to ignore the error here since it can only
 			// fail if the extended key is not private, however it
 			// was just derived as a private key.
@@ -1081,6 +1421,7 @@ func (m *Manager) Unlock(ns walletdb.ReadBucket, passphrase []byte) error {
 
 			switch a := info.managedAddr.(type) {
 			case *managedAddress:
+				protectKey(privKeyBytes)
 				a.privKeyEncrypted = privKeyEncrypted
 				a.privKeyCT = privKeyBytes
 			case *scriptAddress:
@@ -1223,6 +1564,15 @@ func newManager(chainParams *chaincfg.Params, masterKeyPub *snacl.SecretKey,
 		internalAddrSchemas:      make(map[AddressType][]KeyScope),
 	}
 
+	// The clear text crypto keys and the master private key remain in
+	// place for the lifetime of the manager, so they are protected once
+	// here rather than on every unlock.
+	protectKey(m.cryptoKeyPriv.Bytes())
+	protectKey(m.cryptoKeyScript.Bytes())
+	if masterKeyPriv != nil && masterKeyPriv.Key != nil {
+		protectKey(masterKeyPriv.Key[:])
+	}
+
 	for _, sMgr := range m.scopedManagers {
 		externalType := sMgr.AddrSchema().ExternalAddrType
 		internalType := sMgr.AddrSchema().InternalAddrType
@@ -1329,8 +1679,8 @@ func checkBranchKeys(acctKey *hdkeychain.ExtendedKey) error {
 
 // loadManager returns a new address manager that results from loading it from
 // the passed opened database.
-func loadManager(ns walletdb.ReadBucket, chainParams *chaincfg.Params) (
-	*Manager, error) {
+func loadManager(ns walletdb.ReadBucket, pubPassphrase []byte,
+	chainParams *chaincfg.Params) (*Manager, error) {
 
 	// Verify the version is neither too old or too new.
 	version, err := fetchManagerVersion(ns)
@@ -1374,12 +1724,17 @@ func loadManager(ns walletdb.ReadBucket, chainParams *chaincfg.Params) (
 	}
 
 	// Set the master private key params, but don't derive it now since the
-	// manager starts off locked.
+	// manager starts off locked.  The params are missing once the private
+	// keys were deleted, and the manager can then never be unlocked.
 	var masterKeyPriv snacl.SecretKey
-	err = masterKeyPriv.Unmarshal(masterKeyparams)
-	if err != nil {
-		str := "failed to unmarshal master private key"
-		return nil, managerError(ErrCrypto, str, err)
+	if masterKeyparams != nil {
+		err = masterKeyPriv.Unmarshal(masterKeyparams)
+		if err != nil {
+			str := "failed to unmarshal master private key"
+			return nil, managerError(ErrCrypto, str, err)
+		}
+	} else {
+		masterKeyPriv.Key = &snacl.CryptoKey{}
 	}
 
 	// Derive the master public key using the serialized params and provided
@@ -1390,7 +1745,6 @@ func loadManager(ns walletdb.ReadBucket, chainParams *chaincfg.Params) (
 		return nil, managerError(ErrCrypto, str, err)
 	}
 
-	pubPassphrase := []byte("public") // Hardcoded salt.
 	if err := masterKeyPub.DeriveKey(&pubPassphrase); err != nil {
 		str := "invalid passphrase for master public key"
 		return nil, managerError(ErrWrongPassphrase, str, nil)
@@ -1430,6 +1784,8 @@ func loadManager(ns walletdb.ReadBucket, chainParams *chaincfg.Params) (
 			scope:        scope,
 			addrSchema:   *scopeSchema,
 			addrs:        make(map[addrKey]ManagedAddress),
+			missingAddrs: newMissingAddrCache(),
+			addrAccounts: make(map[addrKey]uint32),
 			acctInfo:     make(map[uint32]*accountInfo),
 			privKeyCache: map[DerivationPath]*list.Element{},
 			privKeyLru:   list.New(),
@@ -1457,16 +1813,28 @@ func loadManager(ns walletdb.ReadBucket, chainParams *chaincfg.Params) (
 	return mgr, nil
 }
 
-// Open loads an existing address manager from the given namespace.
-//
-// If a config structure is passed to the function, that configuration will
-// override the defaults.
+// Open loads an existing address manager from the given namespace using the
+// default public passphrase.
 //
 // A ManagerError with an error code of ErrNoExist will be returned if the
 // passed manager does not exist in the specified namespace.
 func Open(ns walletdb.ReadBucket, chainParams *chaincfg.Params) (
 	*Manager, error) {
 
+	return OpenWithPublicPassphrase(
+		ns, []byte(DefaultPublicPassphrase), chainParams,
+	)
+}
+
+// OpenWithPublicPassphrase loads an existing address manager from the given
+// namespace, decrypting its public data with the passed public passphrase.
+//
+// A ManagerError with an error code of ErrNoExist will be returned if the
+// passed manager does not exist in the specified namespace, and one with
+// ErrWrongPassphrase if the public passphrase is incorrect.
+func OpenWithPublicPassphrase(ns walletdb.ReadBucket, pubPassphrase []byte,
+	chainParams *chaincfg.Params) (*Manager, error) {
+
 	// Return an error if the manager has NOT already been created in the
 	// given database namespace.
 	exists := managerExists(ns)
@@ -1475,7 +1843,7 @@ func Open(ns walletdb.ReadBucket, chainParams *chaincfg.Params) (
 		return nil, managerError(ErrNoExist, str, nil)
 	}
 
-	return loadManager(ns, chainParams)
+	return loadManager(ns, pubPassphrase, chainParams)
 }
 
 // createManagerKeyScope creates a new key scoped for a target manager's scope.
@@ -1626,7 +1994,7 @@ func Create(ns walletdb.ReadWriteBucket, rootKey *hdkeychain.ExtendedKey,
 
 	// Generate new master keys.  These master keys are used to protect the
 	// crypto keys that will be generated next.
-	pubPassphrase := []byte("public") // Hardcoded salt.
+	pubPassphrase := []byte(DefaultPublicPassphrase)
 	masterKeyPub, err := newSecretKey(&pubPassphrase, config)
 	if err != nil {
 		str := "failed to master public key"
@@ -2459,3 +2459,249 @@ func TestDeriveFromKeyPathCache(t *testing.T) {
 	require.Equal(t, cachedKey.Serialize(), cachedKey2.Serialize())
 	require.Equal(t, derivedKey.Serialize(), cachedKey2.Serialize())
 }
+
+// TestChangePublicPassphrase ensures the public passphrase can be changed and
+// that the manager can only be opened with the new passphrase afterwards.
+func TestChangePublicPassphrase(t *testing.T) {
+	t.Parallel()
+
+	teardown, db := emptyDB(t)
+	defer teardown()
+
+	newPubPassphrase := []byte("newpublic")
+	err := walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns, err := tx.CreateTopLevelBucket(waddrmgrNamespaceKey)
+		if err != nil {
+			return err
+		}
+		err = Create(
+			ns, rootKey, passphrase,
+			&chaincfg.MainNetParams, fastScrypt, time.Time{},
+		)
+		if err != nil {
+			return err
+		}
+
+		mgr, err := Open(ns, &chaincfg.MainNetParams)
+		if err != nil {
+			return err
+		}
+		defer mgr.Close()
+
+		err = mgr.ChangePublicPassphrase(
+			ns, []byte("wrong"), newPubPassphrase, fastScrypt,
+		)
+		if !IsError(err, ErrWrongPassphrase) {
+			t.Fatalf("expected ErrWrongPassphrase, got %v", err)
+		}
+
+		return mgr.ChangePublicPassphrase(
+			ns, []byte(DefaultPublicPassphrase), newPubPassphrase,
+			fastScrypt,
+		)
+	})
+	if err != nil {
+		t.Fatalf("unable to change public passphrase: %v", err)
+	}
+
+	err = walletdb.View(db, func(tx walletdb.ReadTx) error {
+		ns := tx.ReadBucket(waddrmgrNamespaceKey)
+
+		_, err := Open(ns, &chaincfg.MainNetParams)
+		if !IsError(err, ErrWrongPassphrase) {
+			t.Fatalf("expected ErrWrongPassphrase opening with "+
+				"the default passphrase, got %v", err)
+		}
+
+		mgr, err := OpenWithPublicPassphrase(
+			ns, newPubPassphrase, &chaincfg.MainNetParams,
+		)
+		if err != nil {
+			return err
+		}
+		defer mgr.Close()
+
+		// The private passphrase must not have been affected.
+		return mgr.Unlock(ns, passphrase)
+	})
+	if err != nil {
+		t.Fatalf("unable to open with new public passphrase: %v", err)
+	}
+}
+
+// TestAddressLookupCache ensures addresses which were looked up before being
+// known to the manager are found once they are derived.
+func TestAddressLookupCache(t *testing.T) {
+	t.Parallel()
+
+	teardown, db, mgr := setupManager(t)
+	defer teardown()
+
+	scopedMgr, err := mgr.FetchScopedKeyManager(KeyScopeBIP0044)
+	require.NoError(t, err)
+
+	keyPath := DerivationPath{Branch: ExternalBranch}
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+
+		// Derive the next external address without storing it, and
+		// look it up twice so the second lookup hits the cache.
+		derived, err := scopedMgr.DeriveFromKeyPath(ns, keyPath)
+		if err != nil {
+			return err
+		}
+		addr := derived.Address()
+		for i := 0; i < 2; i++ {
+			_, err = mgr.Address(ns, addr)
+			if !IsError(err, ErrAddressNotFound) {
+				t.Fatalf("lookup %d: got error %v, want "+
+					"ErrAddressNotFound", i, err)
+			}
+		}
+		_, _, err = mgr.AddrAccount(ns, addr)
+		if !IsError(err, ErrAddressNotFound) {
+			t.Fatalf("got error %v, want ErrAddressNotFound", err)
+		}
+
+		// Once the address is derived by the manager, it must be
+		// found in its account.
+		_, err = scopedMgr.NextAddresses(ns, 0, ExternalBranch, 1)
+		if err != nil {
+			return err
+		}
+		ma, err := mgr.Address(ns, addr)
+		if err != nil {
+			return err
+		}
+		require.Equal(t, addr.String(), ma.Address().String())
+		for i := 0; i < 2; i++ {
+			_, account, err := mgr.AddrAccount(ns, addr)
+			if err != nil {
+				return err
+			}
+			require.Equal(t, uint32(0), account)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+// TestConcurrentUnlock ensures unlocks racing each other, whose key
+// derivations run without the manager lock held, all succeed with the correct
+// passphrase, and that a wrong passphrase still locks the manager.
+func TestConcurrentUnlock(t *testing.T) {
+	t.Parallel()
+
+	teardown, db, mgr := setupManager(t)
+	defer teardown()
+
+	unlock := func(pass []byte) error {
+		return walletdb.View(db, func(tx walletdb.ReadTx) error {
+			ns := tx.ReadBucket(waddrmgrNamespaceKey)
+			return mgr.Unlock(ns, pass)
+		})
+	}
+
+	const numUnlocks = 4
+	errs := make(chan error, numUnlocks)
+	for i := 0; i < numUnlocks; i++ {
+		go func() {
+			errs <- unlock(passphrase)
+		}()
+	}
+	for i := 0; i < numUnlocks; i++ {
+		require.NoError(t, <-errs)
+	}
+	require.False(t, mgr.IsLocked())
+
+	err := unlock(privPassphrase2)
+	if !IsError(err, ErrWrongPassphrase) {
+		t.Fatalf("got error %v, want ErrWrongPassphrase", err)
+	}
+	require.True(t, mgr.IsLocked())
+
+	require.NoError(t, unlock(passphrase))
+	require.False(t, mgr.IsLocked())
+}
+
+// TestAddrAccountCacheRollback ensures the accounts of addresses looked up in
+// a write transaction which is rolled back aren't cached.
+func TestAddrAccountCacheRollback(t *testing.T) {
+	t.Parallel()
+
+	teardown, db, mgr := setupManager(t)
+	defer teardown()
+
+	scopedMgr, err := mgr.FetchScopedKeyManager(KeyScopeBIP0044)
+	require.NoError(t, err)
+
+	errRollback := errors.New("rollback")
+	var addr btcutil.Address
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		addrs, err := scopedMgr.NextAddresses(ns, 0, ExternalBranch, 1)
+		if err != nil {
+			return err
+		}
+		addr = addrs[0].Address()
+		_, account, err := mgr.AddrAccount(ns, addr)
+		if err != nil {
+			return err
+		}
+		require.Equal(t, uint32(0), account)
+		return errRollback
+	})
+	require.ErrorIs(t, err, errRollback)
+
+	scopedMgr.mtx.RLock()
+	_, ok := scopedMgr.addrAccounts[addrKey(addr.ScriptAddress())]
+	scopedMgr.mtx.RUnlock()
+	require.False(t, ok)
+
+	// Once a write of addresses is committed, the accounts are cached
+	// again.
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		_, err := scopedMgr.NextAddresses(ns, 0, ExternalBranch, 1)
+		return err
+	})
+	require.NoError(t, err)
+	err = walletdb.View(db, func(tx walletdb.ReadTx) error {
+		ns := tx.ReadBucket(waddrmgrNamespaceKey)
+		_, _, err := mgr.AddrAccount(ns, addr)
+		return err
+	})
+	require.NoError(t, err)
+
+	scopedMgr.mtx.RLock()
+	account, ok := scopedMgr.addrAccounts[addrKey(addr.ScriptAddress())]
+	scopedMgr.mtx.RUnlock()
+	require.True(t, ok)
+	require.Equal(t, uint32(0), account)
+}
+
+// TestMissingAddrCacheBounded ensures the cache of unknown addresses forgets
+// the least recently looked up addresses once full.
+func TestMissingAddrCacheBounded(t *testing.T) {
+	t.Parallel()
+
+	cache := newMissingAddrCache()
+	cache.size = 3
+
+	for _, id := range []addrKey{"a", "b", "c"} {
+		cache.add(id)
+	}
+
+	// Looking "a" up makes "b" the least recently used address.
+	require.True(t, cache.contains("a"))
+	cache.add("d")
+	require.Equal(t, 3, cache.lru.Len())
+	require.False(t, cache.contains("b"))
+	for _, id := range []addrKey{"a", "c", "d"} {
+		require.True(t, cache.contains(id))
+	}
+
+	cache.reset()
+	require.False(t, cache.contains("a"))
+	require.Zero(t, cache.lru.Len())
+}
@@ -53,6 +53,11 @@ const (
 	// wallet. With the default sisize, we'll allocate up to 320 KB to
 	// caching private keys (ignoring pointer overhead, etc).
 	defaultPrivKeyCacheSize = 10_000
+
+	// defaultMissingAddrCacheSize is the number of unknown addresses
+	// remembered by each scoped manager, the least recently looked up ones
+	// being forgotten first.
+	defaultMissingAddrCacheSize = 10_000
 )
 
 // DerivationPath represents a derivation path from a particular key manager's
@@ -231,6 +236,24 @@ type ScopedKeyManager struct {
 	// manage.
 	addrs map[addrKey]ManagedAddress
 
+	// missingAddrs caches the IDs of addresses looked up but not known to
+	// this manager, so that checking the outputs of transactions which
+	// pay other wallets doesn't read the database every time.  It is
+	// cleared whenever addresses are written.
+	missingAddrs *missingAddrCache
+
+	// addrAccounts caches the account of each address looked up with
+	// AddrAccount, from committed state only.
+	addrAccounts map[addrKey]uint32
+
+	// addrWriteGen is incremented when addresses start being written and
+	// again once the write is committed, so that lookups which overlapped
+	// a write don't fill the caches above.  addrWritePending is set
+	// while a write of addresses isn't known to be committed, which
+	// includes writes that were rolled back, until the next commit.
+	addrWriteGen     uint64
+	addrWritePending bool
+
 	// acctInfo houses information about accounts including what is needed
 	// to generate deterministic chained keys for each created account.
 	acctInfo map[uint32]*accountInfo
@@ -336,9 +359,10 @@ func (s *ScopedKeyManager) deriveKey(acctInfo *accountInfo, branch,
 
 	// Choose the public or private extended key based on whether or not
 	// the private flag was specified.  This, in turn, allows for public or
-	// private child derivation.
+	// private child derivation.  Watch-only accounts only have a public
+	// key.
 	acctKey := acctInfo.acctKeyPub
-	if private {
+	if private && acctInfo.acctKeyPriv != nil {
 		acctKey = acctInfo.acctKeyPriv
 	}
 
@@ -422,7 +446,8 @@ func (s *ScopedKeyManager) loadAccountInfo(ns walletdb.ReadBucket,
 			return nil, managerError(ErrCrypto, str, err)
 		}
 
-		if hasPrivateKey {
+		// Watch-only accounts are stored without a private key.
+		if hasPrivateKey && len(row.privKeyEncrypted) > 0 {
 			// Use the crypto private key to decrypt the account
 			// private extended keys.
 			acctInfo.acctKeyPriv, err = decryptKey(
@@ -847,15 +872,97 @@ func (s *ScopedKeyManager) loadAndCacheAddress(ns walletdb.ReadBucket,
 	return managedAddr, nil
 }
 
+// forgetMissingAddrs clears the cache of unknown addresses before addresses
+// are written, and again once the write is committed, since lookups from other
+// database transactions don't see the new addresses until then.  The accounts
+// of the addresses aren't cached until the write is committed.
+//
+// This function MUST be called with the manager lock held for writes.
+func (s *ScopedKeyManager) forgetMissingAddrs(ns walletdb.ReadWriteBucket) {
+	s.missingAddrs.reset()
+	s.addrWriteGen++
+	s.addrWritePending = true
+	ns.Tx().OnCommit(func() {
+		s.mtx.Lock()
+		s.missingAddrs.reset()
+		s.addrWriteGen++
+		s.addrWritePending = false
+		s.mtx.Unlock()
+	})
+}
+
+// missingAddrCache is a bounded set of the IDs of unknown addresses, which
+// forgets the least recently looked up ones first.  It has its own mutex, so
+// that lookups made with the manager lock held for reads can update it.
+type missingAddrCache struct {
+	mtx      sync.Mutex
+	elements map[addrKey]*list.Element
+	lru      *list.List
+	size     int
+}
+
+// newMissingAddrCache returns an empty cache of up to
+// defaultMissingAddrCacheSize unknown addresses.
+func newMissingAddrCache() *missingAddrCache {
+	return &missingAddrCache{
+		elements: make(map[addrKey]*list.Element),
+		lru:      list.New(),
+		size:     defaultMissingAddrCacheSize,
+	}
+}
+
+// contains returns whether the address is known to be missing, marking it as
+// the most recently looked up one.
+func (c *missingAddrCache) contains(id addrKey) bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	element, ok := c.elements[id]
+	if ok {
+		c.lru.MoveToFront(element)
+	}
+	return ok
+}
+
+// add remembers the address as missing, forgetting the least recently looked
+// up one when the cache is full.
+func (c *missingAddrCache) add(id addrKey) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if element, ok := c.elements[id]; ok {
+		c.lru.MoveToFront(element)
+		return
+	}
+	if c.lru.Len() >= c.size {
+		element := c.lru.Back()
+		delete(c.elements, element.Value.(addrKey))
+		c.lru.Remove(element)
+	}
+	c.elements[id] = c.lru.PushFront(id)
+}
+
+// reset forgets all the addresses of the cache.
+func (c *missingAddrCache) reset() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.elements = make(map[addrKey]*list.Element)
+	c.lru.Init()
+}
+
 // existsAddress returns whether or not the passed address is known to the
 // address manager.
 //
 // This function MUST be called with the manager lock held for reads.
 func (s *ScopedKeyManager) existsAddress(ns walletdb.ReadBucket, addressID []byte) bool {
-	// Check the in-memory map first since it's faster than a db access.
+	// Check the in-memory maps first since it's faster than a db access.
 	if _, ok := s.addrs[addrKey(addressID)]; ok {
 		return true
 	}
+	if s.missingAddrs.contains(addrKey(addressID)) {
+		return false
+	}
 
 	// Check the database if not already found above.
 	return existsAddress(ns, &s.scope, addressID)
@@ -882,29 +989,61 @@ func (s *ScopedKeyManager) Address(ns walletdb.ReadBucket,
 	//
 	// NOTE: Not using a defer on the lock here since a write lock is
 	// needed if the lookup fails.
+	id := addrKey(address.ScriptAddress())
 	s.mtx.RLock()
-	if ma, ok := s.addrs[addrKey(address.ScriptAddress())]; ok {
+	if ma, ok := s.addrs[id]; ok {
 		s.mtx.RUnlock()
 		return ma, nil
 	}
+	missing := s.missingAddrs.contains(id)
+	gen := s.addrWriteGen
 	s.mtx.RUnlock()
+	if missing {
+		str := fmt.Sprintf("failed to fetch address '%s': address not "+
+			"found", address.ScriptAddress())
+		return nil, managerError(ErrAddressNotFound, str, nil)
+	}
 
 	s.mtx.Lock()
 	defer s.mtx.Unlock()
 
-	// Attempt to load the address from the database.
-	return s.loadAndCacheAddress(ns, address)
+	// Attempt to load the address from the database, remembering it if it
+	// isn't known, unless addresses were written since the lookup started
+	// as it may have been made against the state preceding the write.
+	ma, err := s.loadAndCacheAddress(ns, address)
+	if IsError(err, ErrAddressNotFound) && s.addrWriteGen == gen {
+		s.missingAddrs.add(id)
+	}
+	return ma, err
 }
 
 // AddrAccount returns the account to which the given address belongs.
 func (s *ScopedKeyManager) AddrAccount(ns walletdb.ReadBucket,
 	address btcutil.Address) (uint32, error) {
 
+	id := addrKey(address.ScriptAddress())
+	s.mtx.RLock()
+	account, ok := s.addrAccounts[id]
+	gen := s.addrWriteGen
+	s.mtx.RUnlock()
+	if ok {
+		return account, nil
+	}
+
 	account, err := fetchAddrAccount(ns, &s.scope, address.ScriptAddress())
 	if err != nil {
 		return 0, maybeConvertDbError(err)
 	}
 
+	// The account is only cached when no write of addresses is pending,
+	// since it may have been read from a write transaction which is later
+	// rolled back.
+	s.mtx.Lock()
+	if !s.addrWritePending && s.addrWriteGen == gen {
+		s.addrAccounts[id] = account
+	}
+	s.mtx.Unlock()
+
 	return account, nil
 }
 
@@ -942,7 +1081,7 @@ func (s *ScopedKeyManager) nextAddresses(ns walletdb.ReadWriteBucket,
 	// Choose the account key to used based on whether the address manager
 	// is locked.
 	acctKey := acctInfo.acctKeyPub
-	if !s.rootManager.IsLocked() {
+	if !s.rootManager.IsLocked() && acctInfo.acctKeyPriv != nil {
 		acctKey = acctInfo.acctKeyPriv
 	}
 
@@ -965,18 +1104,70 @@ func (s *ScopedKeyManager) nextAddresses(ns walletdb.ReadWriteBucket,
 		return nil, managerError(ErrTooManyAddresses, str, nil)
 	}
 
-	// Derive the appropriate branch key and ensure it is zeroed when done.
-	branchKey, err := acctKey.Derive(branch)
-	if err != nil {
-		str := fmt.Sprintf("failed to derive extended key branch %d", branch)
-		return nil, managerError(ErrKeyChain, str, err)
-	}
-	defer branchKey.Zero() // Ensure branch key is zeroed when done.
+	// The branch key is only derived when an address isn't found in the
+	// keypool, and is zeroed when done.
+	var branchKey *hdkeychain.ExtendedKey
+	defer func() {
+		if branchKey != nil {
+			branchKey.Zero()
+		}
+	}()
 
 	// Create the requested number of addresses and keep track of the index
 	// with each one.
 	addressInfo := make([]*unlockDeriveInfo, 0, numAddresses)
+	pooled := make(map[*unlockDeriveInfo]struct{})
 	for i := uint32(0); i < numAddresses; i++ {
+		// Use the public key of the next address from the keypool when
+		// it was derived in advance.
+		pubKey, err := s.fetchKeyPoolPubKey(
+			ns, account, branch, nextIndex,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if pubKey != nil {
+			err := deleteKeyPoolKey(
+				ns, &s.scope, account, branch, nextIndex,
+			)
+			if err != nil {
+				return nil, maybeConvertDbError(err)
+			}
+
+			derivationPath := DerivationPath{
+				InternalAccount: account,
+				Account:         acctKey.ChildIndex(),
+				Branch:          branch,
+				Index:           nextIndex,
+			}
+			addr, err := newManagedAddressWithoutPrivKey(
+				s, derivationPath, pubKey, true, addrType,
+			)
+			if err != nil {
+				return nil, err
+			}
+			addr.internal = branch == InternalBranch
+
+			info := &unlockDeriveInfo{
+				managedAddr: addr,
+				branch:      branch,
+				index:       nextIndex,
+			}
+			addressInfo = append(addressInfo, info)
+			pooled[info] = struct{}{}
+			nextIndex++
+			continue
+		}
+
+		if branchKey == nil {
+			branchKey, err = acctKey.Derive(branch)
+			if err != nil {
+				str := fmt.Sprintf("failed to derive extended "+
+					"key branch %d", branch)
+				return nil, managerError(ErrKeyChain, str, err)
+			}
+		}
+
 		// There is an extremely small chance that a particular child is
 		// invalid, so use a loop to derive the next valid child.
 		var nextKey *hdkeychain.ExtendedKey
@@ -1036,6 +1227,7 @@ func (s *ScopedKeyManager) nextAddresses(ns walletdb.ReadWriteBucket,
 
 	// Now that all addresses have been successfully generated, update the
 	// database in a single transaction.
+	s.forgetMissingAddrs(ns)
 	for _, info := range addressInfo {
 		ma := info.managedAddr
 		addressID := ma.Address().ScriptAddress()
@@ -1086,6 +1278,14 @@ func (s *ScopedKeyManager) nextAddresses(ns walletdb.ReadWriteBucket,
 
 		for _, info := range addressInfo {
 			ma := info.managedAddr
+
+			// The addresses from the keypool only hold their
+			// public key, so they aren't cached while unlocked, to
+			// be loaded with their private key on their next use.
+			_, isPooled := pooled[info]
+			if isPooled && !s.rootManager.isLocked() {
+				continue
+			}
 			s.addrs[addrKey(ma.Address().ScriptAddress())] = ma
 
 			// Add the new managed address to the list of addresses
@@ -1143,7 +1343,7 @@ func (s *ScopedKeyManager) extendAddresses(ns walletdb.ReadWriteBucket,
 	// Choose the account key to used based on whether the address manager
 	// is locked.
 	acctKey := acctInfo.acctKeyPub
-	if !s.rootManager.IsLocked() {
+	if !s.rootManager.IsLocked() && acctInfo.acctKeyPriv != nil {
 		acctKey = acctInfo.acctKeyPriv
 	}
 
@@ -1245,6 +1445,7 @@ func (s *ScopedKeyManager) extendAddresses(ns walletdb.ReadWriteBucket,
 
 	// Now that all addresses have been successfully generated, update the
 	// database in a single transaction.
+	s.forgetMissingAddrs(ns)
 	for _, info := range addressInfo {
 		ma := info.managedAddr
 		addressID := ma.Address().ScriptAddress()
@@ -1420,6 +1621,112 @@ func (s *ScopedKeyManager) NewAccount(ns walletdb.ReadWriteBucket, name string)
 	return account, nil
 }
 
+// NewAccountWatchingOnly creates and returns a new account, following the last
+// account of the manager, backed by the given account extended public key
+// alone.  The addresses of the account are derived from the key with the
+// address schema of the scope, and their private keys are never available,
+// even while the manager is unlocked.  Since no private key is involved, the
+// manager may be locked.
+func (s *ScopedKeyManager) NewAccountWatchingOnly(ns walletdb.ReadWriteBucket,
+	name string, pubKey *hdkeychain.ExtendedKey) (uint32, error) {
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if pubKey.IsPrivate() {
+		str := "watch-only accounts require a public key"
+		return 0, managerError(ErrKeyChain, str, nil)
+	}
+
+	acctPubEnc, err := s.rootManager.cryptoKeyPub.Encrypt(
+		[]byte(pubKey.String()),
+	)
+	if err != nil {
+		str := "failed to  encrypt public key for account"
+		return 0, managerError(ErrCrypto, str, err)
+	}
+
+	// The account is stored without an encrypted private key, as the
+	// accounts of a manager whose private keys were deleted.
+	return s.newAccountWithKeys(ns, name, acctPubEnc, nil)
+}
+
+// NewAccountFromKey creates and returns a new account, following the last
+// account of the manager, whose addresses are derived from the given account
+// extended private key rather than from the cointype key of the manager, such
+// as the account keys of other wallets.  The addresses of the account follow
+// the address schema of the scope.  Since the private key is encrypted, the
+// manager must be unlocked.
+func (s *ScopedKeyManager) NewAccountFromKey(ns walletdb.ReadWriteBucket,
+	name string, privKey *hdkeychain.ExtendedKey) (uint32, error) {
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.rootManager.IsLocked() {
+		return 0, managerError(ErrLocked, errLocked, nil)
+	}
+	if !privKey.IsPrivate() {
+		str := "the account key is not a private key"
+		return 0, managerError(ErrKeyChain, str, nil)
+	}
+	pubKey, err := privKey.Neuter()
+	if err != nil {
+		str := "failed to convert public key for account"
+		return 0, managerError(ErrKeyChain, str, err)
+	}
+
+	acctPubEnc, err := s.rootManager.cryptoKeyPub.Encrypt(
+		[]byte(pubKey.String()),
+	)
+	if err != nil {
+		str := "failed to  encrypt public key for account"
+		return 0, managerError(ErrCrypto, str, err)
+	}
+	acctPrivEnc, err := s.rootManager.cryptoKeyPriv.Encrypt(
+		[]byte(privKey.String()),
+	)
+	if err != nil {
+		str := "failed to encrypt private key for account"
+		return 0, managerError(ErrCrypto, str, err)
+	}
+	return s.newAccountWithKeys(ns, name, acctPubEnc, acctPrivEnc)
+}
+
+// newAccountWithKeys stores a new account following the last account of the
+// manager with the given encrypted account keys.
+//
+// NOTE: This function MUST be called with the manager lock held for writes.
+func (s *ScopedKeyManager) newAccountWithKeys(ns walletdb.ReadWriteBucket,
+	name string, acctPubEnc, acctPrivEnc []byte) (uint32, error) {
+
+	if err := ValidateAccountName(name); err != nil {
+		return 0, err
+	}
+	_, err := s.lookupAccount(ns, name)
+	if err == nil {
+		str := fmt.Sprintf("account with the same name already exists")
+		return 0, managerError(ErrDuplicateAccount, str, err)
+	}
+
+	account, err := fetchLastAccount(ns, &s.scope)
+	if err != nil {
+		return 0, err
+	}
+	account++
+
+	err = putDefaultAccountInfo(
+		ns, &s.scope, account, acctPubEnc, acctPrivEnc, 0, 0, name,
+	)
+	if err != nil {
+		return 0, err
+	}
+	if err := putLastAccount(ns, &s.scope, account); err != nil {
+		return 0, err
+	}
+	return account, nil
+}
+
 // newAccount is a helper function that derives a new precise account number,
 // and creates a mapping from the passed name to the account number in the
 // database.
@@ -1737,6 +2044,7 @@ func (s *ScopedKeyManager) importPublicKey(ns walletdb.ReadWriteBucket,
 
 	// Save the new imported address to the db and update start block (if
 	// needed) in a single transaction.
+	s.forgetMissingAddrs(ns)
 	err = putImportedAddress(
 		ns, &s.scope, addressID, ImportedAddrAccount, ssNone,
 		encryptedPubKey, encryptedPrivKey,
@@ -1910,6 +2218,7 @@ func (s *ScopedKeyManager) importScriptAddress(ns walletdb.ReadWriteBucket,
 
 	// Save the new imported address to the db and update start block (if
 	// needed) in a single transaction.
+	s.forgetMissingAddrs(ns)
 	switch addrType {
 	case WitnessScript:
 		err = putWitnessScriptAddress(
@@ -2163,7 +2472,8 @@ func (s *ScopedKeyManager) cloneKeyWithVersion(key *hdkeychain.ExtendedKey) (
 			return nil, fmt.Errorf("unsupported scope %v", s.scope)
 		}
 
-	case wire.TestNet, wire.TestNet3:
+	// Every other network uses the versions of the test networks.
+	default:
 		switch s.scope {
 		case KeyScopeBIP0044:
 			version = HDVersionTestNetBIP0044
@@ -2174,9 +2484,6 @@ func (s *ScopedKeyManager) cloneKeyWithVersion(key *hdkeychain.ExtendedKey) (
 		default:
 			return nil, fmt.Errorf("unsupported scope %v", s.scope)
 		}
-
-	default:
-		return nil, fmt.Errorf("unsupported net %v", net)
 	}
 
 	var versionBytes [4]byte
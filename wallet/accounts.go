@@ -0,0 +1,205 @@
+package wallet
+
+import (
+	"sort"
+
+	"github.com/lbryio/lbcd/txscript"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/walletdb"
+)
+
+// AccountSummary describes an account and its balances.  Accounts are
+// identified by their number, and are defined in each of the key scopes
+// listed by KeyScopes.  Key counts are the sums over those scopes.
+type AccountSummary struct {
+	AccountNumber    uint32
+	AccountName      string
+	KeyScopes        []waddrmgr.KeyScope
+	ExternalKeyCount uint32
+	InternalKeyCount uint32
+	ImportedKeyCount uint32
+
+	// The balances of the account.  Total includes outputs with fewer
+	// confirmations than required, which are also summed by Unconfirmed.
+	Balances
+	Unconfirmed btcutil.Amount
+
+	// Staked is the value of the unspent claims, supports and claim
+	// updates of the account, which are not part of its balances.
+	Staked btcutil.Amount
+
+	// Moved is the net value moved to the account by balance moves.  The
+	// ledger balance of the account is Total plus Moved.
+	Moved btcutil.Amount
+}
+
+// AccountSummaries returns a summary of every account of the wallet, sorted by
+// account number.  Outputs with fewer than minconf confirmations are not
+// spendable.
+func (w *Wallet) AccountSummaries(minconf int32) ([]AccountSummary, error) {
+	var summaries []AccountSummary
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
+		txmgrNs := tx.ReadBucket(wtxmgrNamespaceKey)
+
+		accounts := make(map[uint32]*AccountSummary)
+		for _, manager := range w.Manager.ActiveScopedKeyManagers() {
+			err := manager.ForEachAccount(addrmgrNs, func(acct uint32) error {
+				props, err := manager.AccountProperties(
+					addrmgrNs, acct,
+				)
+				if err != nil {
+					return err
+				}
+				s, ok := accounts[acct]
+				if !ok {
+					s = &AccountSummary{
+						AccountNumber: acct,
+						AccountName:   props.AccountName,
+					}
+					accounts[acct] = s
+				}
+				s.KeyScopes = append(s.KeyScopes, manager.Scope())
+				s.ExternalKeyCount += props.ExternalKeyCount
+				s.InternalKeyCount += props.InternalKeyCount
+				s.ImportedKeyCount += props.ImportedKeyCount
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		syncBlock := w.Manager.SyncedTo()
+		unspent, err := w.TxStore.UnspentOutputs(txmgrNs)
+		if err != nil {
+			return err
+		}
+		for i := range unspent {
+			output := &unspent[i]
+			_, addrs, _, err := txscript.ExtractPkScriptAddrs(
+				output.PkScript, w.chainParams)
+			if err != nil || len(addrs) == 0 {
+				continue
+			}
+			_, acct, err := w.Manager.AddrAccount(addrmgrNs, addrs[0])
+			if err != nil {
+				continue
+			}
+			s, ok := accounts[acct]
+			if !ok {
+				continue
+			}
+			if isStake(output.PkScript) {
+				s.Staked += output.Amount
+				continue
+			}
+
+			s.Total += output.Amount
+			switch {
+			case output.FromCoinBase && !confirmed(
+				int32(w.chainParams.CoinbaseMaturity),
+				output.Height, syncBlock.Height):
+
+				s.ImmatureReward += output.Amount
+			case confirmed(minconf, output.Height, syncBlock.Height):
+				s.Spendable += output.Amount
+			default:
+				s.Unconfirmed += output.Amount
+			}
+		}
+
+		ns := tx.ReadBucket(walletNamespaceKey)
+		err = forEachMove(ns, func(m *BalanceMove) error {
+			if s, ok := accounts[m.FromAccount]; ok {
+				s.Moved -= m.Amount
+			}
+			if s, ok := accounts[m.ToAccount]; ok {
+				s.Moved += m.Amount
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		summaries = make([]AccountSummary, 0, len(accounts))
+		for _, s := range accounts {
+			summaries = append(summaries, *s)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].AccountNumber < summaries[j].AccountNumber
+	})
+	return summaries, nil
+}
+
+// KeyPool is the number of keys of the accounts of a key scope, and the number
+// of them derived in advance of their use.
+type KeyPool struct {
+	Scope    waddrmgr.KeyScope
+	External uint32
+	Internal uint32
+	Imported uint32
+
+	PooledExternal uint32
+	PooledInternal uint32
+}
+
+// KeyPools returns the number of keys of every active key scope.
+func (w *Wallet) KeyPools() ([]KeyPool, error) {
+	var pools []KeyPool
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
+		for _, manager := range w.Manager.ActiveScopedKeyManagers() {
+			pool := KeyPool{Scope: manager.Scope()}
+			err := manager.ForEachAccount(addrmgrNs, func(acct uint32) error {
+				props, err := manager.AccountProperties(
+					addrmgrNs, acct,
+				)
+				if err != nil {
+					return err
+				}
+				pool.External += props.ExternalKeyCount
+				pool.Internal += props.InternalKeyCount
+				pool.Imported += props.ImportedKeyCount
+				if acct == waddrmgr.ImportedAddrAccount {
+					return nil
+				}
+
+				external, internal, err := manager.KeyPoolSize(
+					addrmgrNs, acct,
+				)
+				if err != nil {
+					return err
+				}
+				pool.PooledExternal += external
+				pool.PooledInternal += internal
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			pools = append(pools, pool)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(pools, func(i, j int) bool {
+		a, b := pools[i].Scope, pools[j].Scope
+		if a.Purpose != b.Purpose {
+			return a.Purpose < b.Purpose
+		}
+		return a.Coin < b.Coin
+	})
+	return pools, nil
+}
@@ -0,0 +1,215 @@
+package wallet
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAccountSummaries ensures the balances of each account only include the
+// outputs paying to its addresses.
+func TestAccountSummaries(t *testing.T) {
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	acct, err := w.NextAccount(waddrmgr.KeyScopeBIP0084, "savings")
+	require.NoError(t, err)
+
+	pay := func(account uint32, scope waddrmgr.KeyScope, value int64) {
+		addr, err := w.NewAddress(account, scope)
+		require.NoError(t, err)
+		pkScript, err := txscript.PayToAddrScript(addr)
+		require.NoError(t, err)
+		addUtxo(t, w, &wire.MsgTx{
+			TxIn:  []*wire.TxIn{{}},
+			TxOut: []*wire.TxOut{wire.NewTxOut(value, pkScript)},
+		})
+	}
+	pay(waddrmgr.DefaultAccountNum, waddrmgr.KeyScopeBIP0044, 1e8)
+	pay(waddrmgr.DefaultAccountNum, waddrmgr.KeyScopeBIP0084, 2e8)
+	pay(acct, waddrmgr.KeyScopeBIP0084, 5e8)
+
+	summaries, err := w.AccountSummaries(0)
+	require.NoError(t, err)
+	require.Len(t, summaries, 3)
+
+	def := summaries[0]
+	require.Equal(t, "default", def.AccountName)
+	require.Len(t, def.KeyScopes, len(waddrmgr.DefaultKeyScopes))
+	require.Equal(t, btcutil.Amount(3e8), def.Total)
+	require.Equal(t, btcutil.Amount(3e8), def.Spendable)
+	require.Equal(t, uint32(2), def.ExternalKeyCount)
+
+	savings := summaries[1]
+	require.Equal(t, acct, savings.AccountNumber)
+	require.Equal(t, "savings", savings.AccountName)
+	require.Equal(t, []waddrmgr.KeyScope{waddrmgr.KeyScopeBIP0084},
+		savings.KeyScopes)
+	require.Equal(t, btcutil.Amount(5e8), savings.Total)
+
+	imported := summaries[2]
+	require.Equal(t, uint32(waddrmgr.ImportedAddrAccount),
+		imported.AccountNumber)
+	require.Zero(t, imported.Total)
+
+	// The outputs are mined after the synced to block, so they have no
+	// confirmations.
+	summaries, err = w.AccountSummaries(1)
+	require.NoError(t, err)
+	require.Zero(t, summaries[1].Spendable)
+	require.Equal(t, btcutil.Amount(5e8), summaries[1].Unconfirmed)
+}
+
+// TestMoveBalance ensures balance moves are recorded and summed in the ledger
+// balances of both accounts.
+func TestMoveBalance(t *testing.T) {
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	acct, err := w.NextAccount(waddrmgr.KeyScopeBIP0044, "savings")
+	require.NoError(t, err)
+
+	_, err = w.MoveBalance(0, acct, 0, "")
+	require.Error(t, err)
+	_, err = w.MoveBalance(acct, acct, 1, "")
+	require.Error(t, err)
+	_, err = w.MoveBalance(0, acct+1, 1, "")
+	require.Error(t, err)
+
+	m1, err := w.MoveBalance(0, acct, 3e8, "rebalance")
+	require.NoError(t, err)
+	m2, err := w.MoveBalance(acct, 0, 1e8, "")
+	require.NoError(t, err)
+
+	moves, err := w.BalanceMoves()
+	require.NoError(t, err)
+	require.Len(t, moves, 2)
+	require.Equal(t, m1.ID, moves[0].ID)
+	require.Equal(t, "rebalance", moves[0].Comment)
+	require.Equal(t, m1.Time.UnixNano(), moves[0].Time.UnixNano())
+	require.Equal(t, m2.ID, moves[1].ID)
+	require.Equal(t, btcutil.Amount(1e8), moves[1].Amount)
+
+	summaries, err := w.AccountSummaries(1)
+	require.NoError(t, err)
+	require.Equal(t, btcutil.Amount(-2e8), summaries[0].Moved)
+	require.Equal(t, btcutil.Amount(2e8), summaries[1].Moved)
+}
+
+// TestAccountClaims ensures claims are attributed to the account of the
+// address they pay to, and are summed in its staked value rather than its
+// balances.
+func TestAccountClaims(t *testing.T) {
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	acct, err := w.NextAccount(waddrmgr.KeyScopeBIP0044, "creator")
+	require.NoError(t, err)
+	addr, err := w.NewAddress(acct, waddrmgr.KeyScopeBIP0044)
+	require.NoError(t, err)
+	pkScript, err := txscript.PayToAddrScript(addr)
+	require.NoError(t, err)
+
+	// A stream signed by a channel, whose claim ID is stored in reverse.
+	channelID := make([]byte, 20)
+	channelID[19] = 0xab
+	value := append([]byte{0x01}, channelID...)
+	value = append(value, make([]byte, 64)...)
+	value = append(value, 0x0a, 0x00)
+	claimScript, err := txscript.ClaimNameScript("video", string(value))
+	require.NoError(t, err)
+	claimScript = append(claimScript[:len(claimScript)-1], pkScript...)
+
+	msgTx := &wire.MsgTx{
+		TxIn:  []*wire.TxIn{{}},
+		TxOut: []*wire.TxOut{wire.NewTxOut(1e7, claimScript)},
+	}
+	addUtxo(t, w, msgTx)
+
+	claims, err := w.AccountClaims()
+	require.NoError(t, err)
+	require.Len(t, claims, 1)
+	c := claims[0]
+	require.Equal(t, acct, c.Account)
+	require.Equal(t, addr.EncodeAddress(), c.Address.EncodeAddress())
+	require.Equal(t, "claim", c.Op)
+	require.Equal(t, "video", c.Name)
+	require.Equal(t, "stream", c.Kind)
+	require.Equal(t, "ab"+strings.Repeat("00", 19), c.SigningChannel)
+
+	summaries, err := w.AccountSummaries(0)
+	require.NoError(t, err)
+	require.Equal(t, btcutil.Amount(1e7), summaries[1].Staked)
+	require.Zero(t, summaries[1].Total)
+}
+
+// TestNewAddresses ensures a batch of addresses is derived at sequential
+// indexes following the addresses already derived.
+func TestNewAddresses(t *testing.T) {
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	first, err := w.NewAddress(0, waddrmgr.KeyScopeBIP0084)
+	require.NoError(t, err)
+
+	addrs, err := w.NewAddresses(0, waddrmgr.KeyScopeBIP0084, 5)
+	require.NoError(t, err)
+	require.Len(t, addrs, 5)
+	for i, addr := range addrs {
+		require.Equal(t, uint32(i+1), addr.Index)
+		require.NotEqual(t, first.EncodeAddress(),
+			addr.Address.EncodeAddress())
+	}
+
+	props, err := w.AccountProperties(waddrmgr.KeyScopeBIP0084, 0)
+	require.NoError(t, err)
+	require.Equal(t, uint32(6), props.ExternalKeyCount)
+}
+
+// TestKeyPools ensures the keys of the accounts are counted by key scope, and
+// the transactions of the wallet are counted.
+func TestKeyPools(t *testing.T) {
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	acct, err := w.NextAccount(waddrmgr.KeyScopeBIP0084, "savings")
+	require.NoError(t, err)
+	addr, err := w.NewAddress(acct, waddrmgr.KeyScopeBIP0084)
+	require.NoError(t, err)
+	_, err = w.NewChangeAddress(waddrmgr.DefaultAccountNum,
+		waddrmgr.KeyScopeBIP0084)
+	require.NoError(t, err)
+
+	pools, err := w.KeyPools()
+	require.NoError(t, err)
+	require.Len(t, pools, len(waddrmgr.DefaultKeyScopes))
+	for _, pool := range pools {
+		switch pool.Scope {
+		case waddrmgr.KeyScopeBIP0084:
+			require.Equal(t, uint32(1), pool.External)
+			require.Equal(t, uint32(1), pool.Internal)
+		default:
+			require.Zero(t, pool.External)
+			require.Zero(t, pool.Internal)
+		}
+	}
+
+	n, err := w.TxCount()
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	pkScript, err := txscript.PayToAddrScript(addr)
+	require.NoError(t, err)
+	addUtxo(t, w, &wire.MsgTx{
+		TxIn:  []*wire.TxIn{{}},
+		TxOut: []*wire.TxOut{wire.NewTxOut(1e8, pkScript)},
+	})
+	n, err = w.TxCount()
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+}
@@ -0,0 +1,190 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/lbryio/lbcd/btcjson"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/walletdb"
+	"github.com/lbryio/lbcwallet/wtxmgr"
+)
+
+// ActivityPeriod sums the transactions of a wallet in a day or in a range of
+// blocks.
+type ActivityPeriod struct {
+	// Start is the UTC midnight starting the day of a daily period.
+	Start time.Time
+
+	// StartHeight and EndHeight are the first and last heights of the
+	// range of blocks of a period, inclusive.
+	StartHeight int32
+	EndHeight   int32
+
+	Transactions int
+
+	// Received is the value of the outputs paid to the wallet, which
+	// excludes change, and Sent the value of the outputs paid by it.
+	// Fees is the total fee paid by the wallet.
+	Received btcutil.Amount
+	Sent     btcutil.Amount
+	Fees     btcutil.Amount
+
+	// Claims, Supports and Updates count the claim operations of the
+	// outputs of the transactions.
+	Claims   int
+	Supports int
+	Updates  int
+
+	// NewAddresses counts the addresses of the wallet receiving their
+	// first payment.
+	NewAddresses int
+}
+
+// WalletActivity sums the transactions of an account, or of every account if
+// accountName is "*", by UTC day when blockSpan is zero, or otherwise by
+// ranges of blockSpan blocks starting at the genesis block.  Days are those
+// the transactions were received on, and unmined transactions are in no range
+// of blocks.  Periods without transactions are omitted, and the others are
+// ordered by time.  The summary stops with the error of ctx once it is done.
+func (w *Wallet) WalletActivity(ctx context.Context, accountName string,
+	blockSpan int32) ([]ActivityPeriod, error) {
+
+	if blockSpan < 0 {
+		return nil, errors.New("block span must not be negative")
+	}
+
+	var periods []*ActivityPeriod
+	byKey := make(map[int64]*ActivityPeriod)
+	period := func(detail *wtxmgr.TxDetails) *ActivityPeriod {
+		p := &ActivityPeriod{}
+		var key int64
+		if blockSpan == 0 {
+			day := detail.Received.UTC().Truncate(24 * time.Hour)
+			key = day.Unix()
+			p.Start = day
+		} else {
+			start := detail.Block.Height / blockSpan * blockSpan
+			key = int64(start)
+			p.StartHeight = start
+			p.EndHeight = start + blockSpan - 1
+		}
+		if existing, ok := byKey[key]; ok {
+			return existing
+		}
+		byKey[key] = p
+		periods = append(periods, p)
+		return p
+	}
+
+	// seen are the addresses paid by the transactions summed so far, in
+	// the order of the history.
+	seen := make(map[string]struct{})
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		txmgrNs := tx.ReadBucket(wtxmgrNamespaceKey)
+		syncBlock := w.Manager.SyncedTo()
+
+		rangeFn := func(details []wtxmgr.TxDetails) (bool, error) {
+			if err := ctx.Err(); err != nil {
+				return false, err
+			}
+
+			for i := range details {
+				detail := &details[i]
+				if blockSpan != 0 && detail.Block.Height == -1 {
+					continue
+				}
+				results := listTransactions(accountName, tx,
+					detail, w.Manager, syncBlock.Height,
+					w.chainParams)
+				if len(results) == 0 {
+					continue
+				}
+
+				err := period(detail).add(detail, results, seen)
+				if err != nil {
+					return false, err
+				}
+			}
+			return false, nil
+		}
+
+		return w.TxStore.RangeTransactions(txmgrNs, 0, -1, rangeFn)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmined transactions come last in the history, but may have been
+	// received before mined ones.
+	sort.SliceStable(periods, func(i, j int) bool {
+		if blockSpan == 0 {
+			return periods[i].Start.Before(periods[j].Start)
+		}
+		return periods[i].StartHeight < periods[j].StartHeight
+	})
+	result := make([]ActivityPeriod, 0, len(periods))
+	for _, p := range periods {
+		result = append(result, *p)
+	}
+	return result, nil
+}
+
+// add sums a transaction to a period, from its listtransactions results.  seen
+// are the addresses paid by the transactions summed before.
+func (p *ActivityPeriod) add(detail *wtxmgr.TxDetails,
+	results []btcjson.ListTransactionsResult,
+	seen map[string]struct{}) error {
+
+	p.Transactions++
+	feeCounted := false
+	vouts := make(map[uint32]struct{})
+	for _, result := range results {
+		amount, err := btcutil.NewAmount(result.Amount)
+		if err != nil {
+			return err
+		}
+		vouts[result.Vout] = struct{}{}
+		if result.Category != "send" {
+			p.Received += amount
+			_, ok := seen[result.Address]
+			if !ok && result.Address != "" {
+				seen[result.Address] = struct{}{}
+				p.NewAddresses++
+			}
+			continue
+		}
+
+		// Sends and fees are listed as negative amounts.
+		p.Sent -= amount
+		if result.Fee != nil && !feeCounted {
+			fee, err := btcutil.NewAmount(*result.Fee)
+			if err != nil {
+				return err
+			}
+			p.Fees -= fee
+			feeCounted = true
+		}
+	}
+
+	for vout := range vouts {
+		op := wire.OutPoint{Hash: detail.Hash, Index: vout}
+		pkScript := detail.MsgTx.TxOut[vout].PkScript
+		c, ok := decodeClaimOutput(op, pkScript)
+		if !ok {
+			continue
+		}
+		switch c.Op {
+		case "claim":
+			p.Claims++
+		case "support":
+			p.Supports++
+		case "update":
+			p.Updates++
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package wallet
+
+import (
+	"context"
+	"testing"
+
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWalletActivity checks that the activity summed by day and by range of
+// blocks adds up to the history of the wallet.
+func TestWalletActivity(t *testing.T) {
+	w := fixtureWallet(t, &FixtureParams{
+		Receives: 10, Spends: 4, Claims: 3,
+	})
+	ctx := context.Background()
+
+	txs, err := w.ListAllTransactions(ctx, "*")
+	require.NoError(t, err)
+	var received, sent btcutil.Amount
+	addrs := make(map[string]struct{})
+	for _, tx := range txs {
+		amount, err := btcutil.NewAmount(tx.Amount)
+		require.NoError(t, err)
+		if tx.Category == "send" {
+			sent -= amount
+			continue
+		}
+		received += amount
+		addrs[tx.Address] = struct{}{}
+	}
+
+	_, err = w.WalletActivity(ctx, "*", -1)
+	require.Error(t, err)
+
+	// The synthetic history is mined within a day.
+	days, err := w.WalletActivity(ctx, "*", 0)
+	require.NoError(t, err)
+	require.Len(t, days, 1)
+	require.Equal(t, FixtureStart, days[0].Start)
+	require.Equal(t, 17, days[0].Transactions)
+	require.Equal(t, received, days[0].Received)
+	require.Equal(t, sent, days[0].Sent)
+	require.Equal(t, btcutil.Amount(7*fixtureFee), days[0].Fees)
+	require.Equal(t, 3, days[0].Claims)
+	require.Equal(t, len(addrs), days[0].NewAddresses)
+
+	// The blocks of the history are at heights 1 to 17.
+	ranges, err := w.WalletActivity(ctx, "*", 5)
+	require.NoError(t, err)
+	require.Len(t, ranges, 4)
+	var sum ActivityPeriod
+	for i, p := range ranges {
+		require.EqualValues(t, 5*i, p.StartHeight)
+		require.EqualValues(t, 5*i+4, p.EndHeight)
+		sum.Transactions += p.Transactions
+		sum.Received += p.Received
+		sum.Sent += p.Sent
+		sum.Fees += p.Fees
+		sum.Claims += p.Claims
+		sum.NewAddresses += p.NewAddresses
+	}
+	require.Equal(t, 4, ranges[0].Transactions)
+	require.Equal(t, days[0].Transactions, sum.Transactions)
+	require.Equal(t, days[0].Received, sum.Received)
+	require.Equal(t, days[0].Sent, sum.Sent)
+	require.Equal(t, days[0].Fees, sum.Fees)
+	require.Equal(t, days[0].Claims, sum.Claims)
+	require.Equal(t, days[0].NewAddresses, sum.NewAddresses)
+
+	// The fixture wallet has no other account.
+	none, err := w.WalletActivity(ctx, "nonexistent", 0)
+	require.NoError(t, err)
+	require.Empty(t, none)
+}
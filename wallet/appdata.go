@@ -0,0 +1,127 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lbryio/lbcwallet/walletdb"
+)
+
+// bucketAppData is the name of the sub bucket of the wallet namespace that
+// holds a bucket of application data for each namespace.
+var bucketAppData = []byte("appdata")
+
+const (
+	// MaxDataKeySize is the maximum size of a namespace or a key of the
+	// application data.
+	MaxDataKeySize = 255
+
+	// MaxDataValueSize is the maximum size of a value of the application
+	// data.
+	MaxDataValueSize = 64 * 1024
+)
+
+// ErrDataNotFound is returned for a key without a value in the application
+// data.
+var ErrDataNotFound = errors.New("no data for key")
+
+// checkDataKey returns an error for an empty or oversized namespace or key.
+func checkDataKey(what, key string) error {
+	if key == "" {
+		return fmt.Errorf("%s must not be empty", what)
+	}
+	if len(key) > MaxDataKeySize {
+		return fmt.Errorf("%s is longer than %d bytes", what,
+			MaxDataKeySize)
+	}
+	return nil
+}
+
+// SetData stores values in a namespace of the application data, which
+// applications use to keep their own metadata in the wallet database.  Keys
+// mapped to a nil value are removed.  All values are stored in a single
+// database transaction.
+func (w *Wallet) SetData(namespace string, values map[string][]byte) error {
+	if err := checkDataKey("namespace", namespace); err != nil {
+		return err
+	}
+	for k, v := range values {
+		if err := checkDataKey("key", k); err != nil {
+			return err
+		}
+		if len(v) > MaxDataValueSize {
+			return fmt.Errorf("value of key %q is larger than %d "+
+				"bytes", k, MaxDataValueSize)
+		}
+	}
+
+	return walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(walletNamespaceKey)
+		appData, err := ns.CreateBucketIfNotExists(bucketAppData)
+		if err != nil {
+			return err
+		}
+		bucket, err := appData.CreateBucketIfNotExists([]byte(namespace))
+		if err != nil {
+			return err
+		}
+		for k, v := range values {
+			if v == nil {
+				err = bucket.Delete([]byte(k))
+			} else {
+				err = bucket.Put([]byte(k), v)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Data returns the value of a key in a namespace of the application data, or
+// ErrDataNotFound when the key has no value.
+func (w *Wallet) Data(namespace, key string) ([]byte, error) {
+	var value []byte
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		bucket := appDataBucket(tx, namespace)
+		if bucket == nil {
+			return ErrDataNotFound
+		}
+		v := bucket.Get([]byte(key))
+		if v == nil {
+			return ErrDataNotFound
+		}
+		value = append([]byte{}, v...)
+		return nil
+	})
+	return value, err
+}
+
+// AllData returns every key and value of a namespace of the application
+// data.
+func (w *Wallet) AllData(namespace string) (map[string][]byte, error) {
+	values := make(map[string][]byte)
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		bucket := appDataBucket(tx, namespace)
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			values[string(k)] = append([]byte{}, v...)
+			return nil
+		})
+	})
+	return values, err
+}
+
+// appDataBucket returns the bucket of a namespace of the application data, or
+// nil if nothing was stored in it.
+func appDataBucket(tx walletdb.ReadTx, namespace string) walletdb.ReadBucket {
+	appData := tx.ReadBucket(walletNamespaceKey).NestedReadBucket(
+		bucketAppData)
+	if appData == nil {
+		return nil
+	}
+	return appData.NestedReadBucket([]byte(namespace))
+}
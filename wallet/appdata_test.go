@@ -0,0 +1,42 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestAppData ensures application data is stored per namespace and that keys
+// mapped to nil values are removed.
+func TestAppData(t *testing.T) {
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	_, err := w.Data("app", "missing")
+	require.ErrorIs(t, err, ErrDataNotFound)
+	require.Error(t, w.SetData("", map[string][]byte{"k": nil}))
+	require.Error(t, w.SetData("app", map[string][]byte{
+		"k": make([]byte, MaxDataValueSize+1),
+	}))
+
+	err = w.SetData("app", map[string][]byte{
+		"channel": []byte("@lbry"),
+		"empty":   {},
+	})
+	require.NoError(t, err)
+	require.NoError(t, w.SetData("other", map[string][]byte{
+		"channel": []byte("@other"),
+	}))
+
+	value, err := w.Data("app", "channel")
+	require.NoError(t, err)
+	require.Equal(t, []byte("@lbry"), value)
+	value, err = w.Data("app", "empty")
+	require.NoError(t, err)
+	require.Empty(t, value)
+
+	require.NoError(t, w.SetData("app", map[string][]byte{"channel": nil}))
+	values, err := w.AllData("app")
+	require.NoError(t, err)
+	require.Equal(t, map[string][]byte{"empty": {}}, values)
+}
@@ -0,0 +1,340 @@
+package wallet
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/nacl/box"
+)
+
+const (
+	// backupTimeFormat is the format of the UTC time in the names of
+	// backups, which sort in the order they were made.
+	backupTimeFormat = "20060102T150405Z"
+
+	// BackupExt is the extension of the names of backups.
+	BackupExt = ".db.sealed"
+)
+
+// ErrBackupsDisabled is returned when a backup is requested without a backup
+// store and key.
+var ErrBackupsDisabled = errors.New("wallet backups are not configured")
+
+// BackupStore is where the backups of a wallet are written.
+type BackupStore interface {
+	// Put writes a backup with the given name.
+	Put(name string, data []byte) error
+
+	// List returns the names of the backups in the store.
+	List() ([]string, error)
+
+	// Remove removes the backup with the given name.
+	Remove(name string) error
+
+	// String describes the store, such as its directory or URL.
+	String() string
+}
+
+// BackupDir is a BackupStore writing each backup to a file of a directory,
+// which is created when missing.
+type BackupDir string
+
+// Put writes a backup to a file of the directory, which is only renamed to its
+// name once complete.
+func (d BackupDir) Put(name string, data []byte) error {
+	if err := os.MkdirAll(string(d), 0700); err != nil {
+		return err
+	}
+	path := filepath.Join(string(d), name)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// List returns the names of the files of the directory.
+func (d BackupDir) List() ([]string, error) {
+	entries, err := os.ReadDir(string(d))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Type().IsRegular() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+// Remove removes the file of a backup.
+func (d BackupDir) Remove(name string) error {
+	return os.Remove(filepath.Join(string(d), name))
+}
+
+// String returns the path of the directory.
+func (d BackupDir) String() string {
+	return string(d)
+}
+
+// BackupPolicy controls the periodic backups of the wallet database.  Each
+// backup is a snapshot of the database sealed to the public key with a NaCl
+// anonymous box, which is compatible with crypto_box_seal of libsodium, so
+// that only the holder of the private key can read it.
+type BackupPolicy struct {
+	// Interval is the time between backups.  The first backup is made
+	// once the policy is set.  Backups are only made on request when
+	// zero.
+	Interval time.Duration
+
+	// Keep is the number of backups of the wallet kept in the store, the
+	// older ones being removed after each backup.  Every backup is kept
+	// when zero.
+	Keep int
+
+	// Key is the Curve25519 public key the backups are sealed to.
+	Key *[32]byte
+
+	// Store is where the backups are written.
+	Store BackupStore
+
+	// Prefix starts the names of the backups of the wallet, followed by
+	// a dash, the UTC time of the backup and BackupExt.
+	Prefix string
+}
+
+// BackupStatus describes the backups of the wallet.
+type BackupStatus struct {
+	// Enabled is true when the backups are made periodically.
+	Enabled  bool
+	Store    string
+	Interval time.Duration
+
+	// LastAttempt is the time of the last backup attempt, whose error is
+	// LastError if it failed.
+	LastAttempt time.Time
+	LastError   string
+
+	// LastBackup, LastName and LastSize describe the last successful
+	// backup.
+	LastBackup time.Time
+	LastName   string
+	LastSize   int
+
+	// NextBackup is the time of the next scheduled backup.
+	NextBackup time.Time
+}
+
+// SetBackupPolicy sets the policy for the backups of the wallet, and
+// reschedules the periodic backups.
+func (w *Wallet) SetBackupPolicy(policy BackupPolicy) {
+	w.backupMtx.Lock()
+	w.backupPolicy = policy
+	w.backupStatus.NextBackup = time.Time{}
+	w.backupMtx.Unlock()
+
+	select {
+	case w.backupPolicyChanged <- struct{}{}:
+	default:
+	}
+}
+
+// BackupPolicy returns the policy for backups set by SetBackupPolicy.
+func (w *Wallet) BackupPolicy() BackupPolicy {
+	w.backupMtx.Lock()
+	defer w.backupMtx.Unlock()
+	return w.backupPolicy
+}
+
+// BackupStatus returns the status of the backups of the wallet.
+func (w *Wallet) BackupStatus() BackupStatus {
+	w.backupMtx.Lock()
+	defer w.backupMtx.Unlock()
+
+	status := w.backupStatus
+	policy := w.backupPolicy
+	status.Enabled = policy.Interval > 0 && policy.Store != nil &&
+		policy.Key != nil
+	if policy.Store != nil {
+		status.Store = policy.Store.String()
+	}
+	status.Interval = policy.Interval
+	if !status.Enabled {
+		status.NextBackup = time.Time{}
+	}
+	return status
+}
+
+// Backup writes a sealed snapshot of the wallet database to the backup store
+// at once, removes the backups beyond those to keep, and returns the name of
+// the backup.
+func (w *Wallet) Backup() (string, error) {
+	policy := w.BackupPolicy()
+	if policy.Store == nil || policy.Key == nil {
+		return "", ErrBackupsDisabled
+	}
+
+	now := time.Now()
+	name, size, err := w.backup(&policy, now)
+
+	w.backupMtx.Lock()
+	w.backupStatus.LastAttempt = now
+	if err != nil {
+		w.backupStatus.LastError = err.Error()
+	} else {
+		w.backupStatus.LastError = ""
+		w.backupStatus.LastBackup = now
+		w.backupStatus.LastName = name
+		w.backupStatus.LastSize = size
+	}
+	w.backupMtx.Unlock()
+
+	if err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// backup writes a backup of the database made at time now, and rotates the
+// older backups.  The name and size of the backup are returned.
+func (w *Wallet) backup(policy *BackupPolicy, now time.Time) (string, int,
+	error) {
+
+	var snapshot bytes.Buffer
+	if err := w.db.Copy(&snapshot); err != nil {
+		return "", 0, err
+	}
+	sealed, err := box.SealAnonymous(nil, snapshot.Bytes(), policy.Key,
+		rand.Reader)
+	if err != nil {
+		return "", 0, err
+	}
+
+	name := fmt.Sprintf("%s-%s%s", policy.Prefix,
+		now.UTC().Format(backupTimeFormat), BackupExt)
+	if err := policy.Store.Put(name, sealed); err != nil {
+		return "", 0, err
+	}
+	if policy.Keep > 0 {
+		if err := rotateBackups(policy); err != nil {
+			return "", 0, fmt.Errorf("unable to remove old "+
+				"backups: %w", err)
+		}
+	}
+	return name, len(sealed), nil
+}
+
+// rotateBackups removes the oldest backups of the wallet from the store,
+// keeping the number of the policy.
+func rotateBackups(policy *BackupPolicy) error {
+	names, err := policy.Store.List()
+	if err != nil {
+		return err
+	}
+	var backups []string
+	for _, name := range names {
+		if isBackupName(name, policy.Prefix) {
+			backups = append(backups, name)
+		}
+	}
+	if len(backups) <= policy.Keep {
+		return nil
+	}
+	sort.Strings(backups)
+	for _, name := range backups[:len(backups)-policy.Keep] {
+		if err := policy.Store.Remove(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isBackupName returns whether name is the name of a backup made with the
+// given prefix.
+func isBackupName(name, prefix string) bool {
+	if !strings.HasPrefix(name, prefix+"-") ||
+		!strings.HasSuffix(name, BackupExt) {
+
+		return false
+	}
+	t := strings.TrimSuffix(name[len(prefix)+1:], BackupExt)
+	_, err := time.Parse(backupTimeFormat, t)
+	return err == nil
+}
+
+// OpenBackup decrypts a backup sealed to the public key, returning the wallet
+// database.
+func OpenBackup(sealed []byte, publicKey, privateKey *[32]byte) ([]byte,
+	error) {
+
+	db, ok := box.OpenAnonymous(nil, sealed, publicKey, privateKey)
+	if !ok {
+		return nil, errors.New("unable to decrypt backup: wrong key " +
+			"or corrupted backup")
+	}
+	return db, nil
+}
+
+// backupScheduler makes the periodic backups of the backup policy until the
+// wallet is stopped.
+func (w *Wallet) backupScheduler() {
+	defer w.wg.Done()
+
+	quit := w.quitChan()
+	for {
+		// Without periodic backups, wait until the policy changes.
+		var timer *time.Timer
+		policy := w.BackupPolicy()
+		if policy.Interval > 0 && policy.Store != nil &&
+			policy.Key != nil {
+
+			w.backupMtx.Lock()
+			next := w.backupStatus.NextBackup
+			if next.IsZero() {
+				next = time.Now()
+				w.backupStatus.NextBackup = next
+			}
+			w.backupMtx.Unlock()
+			timer = time.NewTimer(time.Until(next))
+		}
+
+		var timeout <-chan time.Time
+		if timer != nil {
+			timeout = timer.C
+		}
+		select {
+		case <-timeout:
+			name, err := w.Backup()
+			if err != nil {
+				log.Errorf("Unable to back up wallet: %v", err)
+			} else {
+				log.Infof("Backed up wallet to %v", name)
+			}
+			w.backupMtx.Lock()
+			w.backupStatus.NextBackup = time.Now().Add(
+				policy.Interval)
+			w.backupMtx.Unlock()
+		case <-w.backupPolicyChanged:
+		case <-quit:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		select {
+		case <-quit:
+			return
+		default:
+		}
+	}
+}
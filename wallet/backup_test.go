@@ -0,0 +1,77 @@
+package wallet
+
+import (
+	"bytes"
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/nacl/box"
+)
+
+// TestBackup checks that backups decrypt to the wallet database with the
+// private key, and that only the most recent backups are kept.
+func TestBackup(t *testing.T) {
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	publicKey, privateKey, err := box.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	dir := t.TempDir()
+	policy := BackupPolicy{
+		Keep:   2,
+		Key:    publicKey,
+		Store:  BackupDir(dir),
+		Prefix: "wallet",
+	}
+	w.SetBackupPolicy(policy)
+
+	// An unrelated file is never removed.
+	other := filepath.Join(dir, "wallet-notes.txt")
+	require.NoError(t, os.WriteFile(other, nil, 0600))
+
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	var names []string
+	for i := 0; i < 3; i++ {
+		name, _, err := w.backup(&policy, start.Add(time.Duration(i)*
+			time.Hour))
+		require.NoError(t, err)
+		names = append(names, name)
+	}
+	require.Equal(t, "wallet-20240102T030405Z"+BackupExt, names[0])
+
+	stored, err := BackupDir(dir).List()
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{names[1], names[2],
+		"wallet-notes.txt"}, stored)
+
+	sealed, err := os.ReadFile(filepath.Join(dir, names[2]))
+	require.NoError(t, err)
+	db, err := OpenBackup(sealed, publicKey, privateKey)
+	require.NoError(t, err)
+	var snapshot bytes.Buffer
+	require.NoError(t, w.db.Copy(&snapshot))
+	require.Equal(t, snapshot.Bytes(), db)
+
+	_, otherKey, err := box.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	_, err = OpenBackup(sealed, publicKey, otherKey)
+	require.Error(t, err)
+
+	// Setting an interval backs up the wallet at once.
+	policy.Interval = time.Hour
+	w.SetBackupPolicy(policy)
+	require.Eventually(t, func() bool {
+		status := w.BackupStatus()
+		return !status.LastBackup.IsZero() &&
+			status.NextBackup.After(status.LastBackup)
+	}, 10*time.Second, 10*time.Millisecond)
+	status := w.BackupStatus()
+	require.True(t, status.Enabled)
+	require.Equal(t, dir, status.Store)
+	require.Empty(t, status.LastError)
+	require.True(t, isBackupName(status.LastName, "wallet"))
+}
@@ -0,0 +1,222 @@
+package wallet
+
+import (
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lbryio/lbcd/chaincfg"
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	"github.com/lbryio/lbcutil/hdkeychain"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/wallet/txrules"
+	"github.com/lbryio/lbcwallet/walletdb"
+	"github.com/lbryio/lbcwallet/wtxmgr"
+)
+
+// benchTxs lists the sizes of the synthetic wallets of
+// BenchmarkSyntheticWallet.  Larger wallets take a while to create, so only
+// the smallest one is benchmarked by default, e.g.:
+//
+//	go test -run='^$' -bench=SyntheticWallet ./wallet \
+//		-args -benchtxs=10000,100000,1000000
+var benchTxs = flag.String("benchtxs", "10000", "comma separated numbers of "+
+	"transactions of the synthetic wallets to benchmark")
+
+const (
+	// benchTxsPerBlock is the number of transactions of each block of a
+	// synthetic wallet.
+	benchTxsPerBlock = 100
+
+	// benchTxValue is the value of the output paying to the wallet of each
+	// transaction of a synthetic wallet.
+	benchTxValue = 1e6
+)
+
+// benchWallet is a wallet filled with synthetic transactions, each mined in one
+// of consecutive blocks and paying to the same address of the default
+// account.
+type benchWallet struct {
+	loader     *Loader
+	w          *Wallet
+	passphrase []byte
+	pkScript   []byte
+	numTxs     int
+}
+
+func newBenchWallet(b *testing.B, numTxs int) *benchWallet {
+	seed, err := hdkeychain.GenerateSeed(hdkeychain.MinSeedBytes)
+	if err != nil {
+		b.Fatalf("unable to create seed: %v", err)
+	}
+
+	bw := &benchWallet{passphrase: []byte("hello world")}
+	bw.loader = NewLoader(
+		&chaincfg.TestNet3Params, b.TempDir(), true, defaultDBTimeout,
+		250,
+	)
+	bw.w, err = bw.loader.CreateNewWallet(bw.passphrase, seed, time.Now())
+	if err != nil {
+		b.Fatalf("unable to create wallet: %v", err)
+	}
+	bw.unlock(b)
+
+	addr, err := bw.w.NewAddress(0, waddrmgr.KeyScopeBIP0044)
+	if err != nil {
+		b.Fatal(err)
+	}
+	bw.pkScript, err = txscript.PayToAddrScript(addr)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	bw.addTxs(b, numTxs)
+	return bw
+}
+
+// unlock attaches a chain client to the loaded wallet and unlocks it.
+func (bw *benchWallet) unlock(b *testing.B) {
+	bw.w.chainClient = &mockChainClient{}
+	if err := bw.w.Unlock(bw.passphrase, nil); err != nil {
+		b.Fatalf("unable to unlock wallet: %v", err)
+	}
+}
+
+// addTxs writes n more transactions the way they are written by a rescan, and
+// marks the wallet synced to the block of the last one.
+func (bw *benchWallet) addTxs(b *testing.B, n int) {
+	var pending relevantTxs
+	for i := 0; i < n; i++ {
+		rec, block := bw.tx(b, bw.numTxs)
+		bw.numTxs++
+
+		pending.add(rec, block)
+		if pending.numBlocks < rescanCommitBlocks && i != n-1 {
+			continue
+		}
+		if err := pending.commit(bw.w); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	_, block := bw.tx(b, bw.numTxs-1)
+	err := walletdb.Update(bw.w.db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		return bw.w.Manager.SetSyncedTo(ns, &waddrmgr.BlockStamp{
+			Hash:   block.Hash,
+			Height: block.Height,
+		})
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+}
+
+// tx creates the i-th synthetic transaction and its block.
+func (bw *benchWallet) tx(b *testing.B, i int) (*wtxmgr.TxRecord,
+	*wtxmgr.BlockMeta) {
+
+	height := int32(i/benchTxsPerBlock) + 1
+	block := &wtxmgr.BlockMeta{
+		Block: wtxmgr.Block{Height: height},
+		Time:  time.Unix(1600000000+int64(height)*150, 0),
+	}
+	binary.LittleEndian.PutUint32(block.Hash[:], uint32(height))
+
+	prevOut := wire.OutPoint{Hash: chainhash.Hash{1}, Index: uint32(i)}
+	msgTx := wire.NewMsgTx(wire.TxVersion)
+	msgTx.AddTxIn(wire.NewTxIn(&prevOut, nil, nil))
+	msgTx.AddTxOut(wire.NewTxOut(benchTxValue, bw.pkScript))
+	rec, err := wtxmgr.NewTxRecordFromMsgTx(msgTx, block.Time)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return rec, block
+}
+
+// BenchmarkSyntheticWallet measures the time to open a wallet, to query its
+// balance and to create a transaction, as well as the rate at which rescanned
+// transactions are written, for wallets of the sizes listed by -benchtxs.  The
+// open time includes the derivation of the public passphrase key with the
+// default scrypt parameters.
+func BenchmarkSyntheticWallet(b *testing.B) {
+	for _, s := range strings.Split(*benchTxs, ",") {
+		numTxs, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil || numTxs <= 0 {
+			b.Fatalf("invalid number of transactions %q", s)
+		}
+
+		b.Run(fmt.Sprintf("txs=%d", numTxs), func(b *testing.B) {
+			bw := newBenchWallet(b, numTxs)
+			defer func() {
+				if err := bw.loader.UnloadWallet(); err != nil {
+					b.Error(err)
+				}
+			}()
+			benchmarkSyntheticWallet(b, bw)
+		})
+	}
+}
+
+func benchmarkSyntheticWallet(b *testing.B, bw *benchWallet) {
+	b.Run("open", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			if err := bw.loader.UnloadWallet(); err != nil {
+				b.Fatal(err)
+			}
+			b.StartTimer()
+
+			w, err := bw.loader.OpenExistingWallet()
+			if err != nil {
+				b.Fatal(err)
+			}
+			bw.w = w
+		}
+		b.StopTimer()
+		bw.unlock(b)
+	})
+
+	b.Run("balance", func(b *testing.B) {
+		want := float64(bw.numTxs) * benchTxValue
+		for i := 0; i < b.N; i++ {
+			balance, _, err := bw.w.CalculateBalance(1)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if float64(balance) != want {
+				b.Fatalf("got balance %v, want %v", balance,
+					want)
+			}
+		}
+	})
+
+	b.Run("createtx", func(b *testing.B) {
+		outputs := []*wire.TxOut{
+			wire.NewTxOut(benchTxValue/2, bw.pkScript),
+		}
+		for i := 0; i < b.N; i++ {
+			_, err := bw.w.txToOutputs(
+				outputs, nil, 0, 1, txrules.DefaultRelayFeePerKb,
+				CoinSelectionLargest, false,
+			)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	// The rescan is benchmarked last, as it adds to the wallet.
+	b.Run("rescan", func(b *testing.B) {
+		start := time.Now()
+		bw.addTxs(b, b.N)
+		b.ReportMetric(float64(b.N)/time.Since(start).Seconds(),
+			"txs/s")
+	})
+}
@@ -0,0 +1,157 @@
+package wallet
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcd/wire"
+	"github.com/lbryio/lbcwallet/chain"
+)
+
+// BroadcastPolicy controls how the transactions created by the wallet are
+// broadcast, so that the node they are first seen from is harder to tell.
+type BroadcastPolicy struct {
+	// MaxDelay delays the broadcast of each new transaction by a random
+	// duration of up to MaxDelay.  The transaction is recorded by the
+	// wallet at once, and its publication does not wait for the
+	// broadcast, whose failure is only logged.  Transactions are
+	// broadcast at once when zero.
+	MaxDelay time.Duration
+
+	// Broadcast, when set, sends transactions to the network in place of
+	// the chain backend, for instance over another proxy circuit than its
+	// connection.
+	Broadcast func(tx *wire.MsgTx) error
+}
+
+// SetBroadcastPolicy sets the policy for the broadcast of the transactions
+// published from now on.
+func (w *Wallet) SetBroadcastPolicy(policy BroadcastPolicy) {
+	w.broadcastPolicyMtx.Lock()
+	w.broadcastPolicy = policy
+	w.broadcastPolicyMtx.Unlock()
+}
+
+// BroadcastPolicy returns the policy for broadcasts set by SetBroadcastPolicy.
+func (w *Wallet) BroadcastPolicy() BroadcastPolicy {
+	w.broadcastPolicyMtx.Lock()
+	defer w.broadcastPolicyMtx.Unlock()
+	return w.broadcastPolicy
+}
+
+// ErrSendsPaused is returned when a transaction is created or published while
+// sends are paused.
+type ErrSendsPaused struct {
+	Reason error
+}
+
+// Error returns the reason sends are paused.
+func (e *ErrSendsPaused) Error() string {
+	return fmt.Sprintf("sends are paused: %v", e.Reason)
+}
+
+// Unwrap returns the reason sends are paused.
+func (e *ErrSendsPaused) Unwrap() error {
+	return e.Reason
+}
+
+// PauseSends refuses the creation and publication of transactions, which fail
+// with an ErrSendsPaused of reason, until ResumeSends is called.  Unmined
+// transactions are still rebroadcast.
+func (w *Wallet) PauseSends(reason error) {
+	w.sendsPausedMtx.Lock()
+	w.sendsPaused = reason
+	w.sendsPausedMtx.Unlock()
+}
+
+// ResumeSends lets transactions be created and published again after
+// PauseSends.
+func (w *Wallet) ResumeSends() {
+	w.PauseSends(nil)
+}
+
+// SendsPaused returns the reason given to PauseSends while sends are paused,
+// or nil.
+func (w *Wallet) SendsPaused() error {
+	w.sendsPausedMtx.Lock()
+	defer w.sendsPausedMtx.Unlock()
+	return w.sendsPaused
+}
+
+// requireSendsAllowed returns an ErrSendsPaused while sends are paused.
+func (w *Wallet) requireSendsAllowed() error {
+	if reason := w.SendsPaused(); reason != nil {
+		return &ErrSendsPaused{Reason: reason}
+	}
+	return nil
+}
+
+// sendRawTransaction sends a transaction to the network, with the broadcast
+// function of the policy if any, or else with the chain backend.
+func (w *Wallet) sendRawTransaction(chainClient chain.Interface,
+	tx *wire.MsgTx) error {
+
+	if broadcast := w.BroadcastPolicy().Broadcast; broadcast != nil {
+		return broadcast(tx)
+	}
+	_, err := chainClient.SendRawTransaction(tx, false)
+	return err
+}
+
+// publishTransactionDelayed publishes a transaction after the random delay of
+// the broadcast policy, and returns its hash at once.  The transaction is
+// rebroadcast at the next startup if the wallet is stopped first, as it is
+// already recorded as unmined.  False is returned when the policy does not
+// delay broadcasts.
+func (w *Wallet) publishTransactionDelayed(tx *wire.MsgTx) (*chainhash.Hash,
+	bool) {
+
+	maxDelay := w.BroadcastPolicy().MaxDelay
+	if maxDelay <= 0 {
+		return nil, false
+	}
+	delay := time.Duration(rand.Int63n(int64(maxDelay) + 1))
+	txid := tx.TxHash()
+	quit := w.quitChan()
+
+	w.delayedBroadcastsMtx.Lock()
+	if w.delayedBroadcasts == nil {
+		w.delayedBroadcasts = make(map[chainhash.Hash]time.Time)
+	}
+	w.delayedBroadcasts[txid] = time.Now().Add(delay)
+	w.delayedBroadcastsMtx.Unlock()
+
+	go func() {
+		defer func() {
+			w.delayedBroadcastsMtx.Lock()
+			delete(w.delayedBroadcasts, txid)
+			w.delayedBroadcastsMtx.Unlock()
+		}()
+
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-quit:
+			return
+		}
+		if _, err := w.publishTransaction(tx); err != nil {
+			log.Errorf("Unable to broadcast transaction %v: %v",
+				txid, err)
+		}
+	}()
+
+	log.Debugf("Broadcasting transaction %v in %v", txid, delay)
+	return &txid, true
+}
+
+// delayedBroadcast returns the time a transaction waiting for the delay of the
+// broadcast policy is broadcast, and false if it is not waiting.
+func (w *Wallet) delayedBroadcast(txid *chainhash.Hash) (time.Time, bool) {
+	w.delayedBroadcastsMtx.Lock()
+	defer w.delayedBroadcastsMtx.Unlock()
+	t, ok := w.delayedBroadcasts[*txid]
+	return t, ok
+}
@@ -0,0 +1,97 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDelayedBroadcast checks that sent transactions are recorded at once and
+// broadcast later with the broadcast function of the policy.
+func TestDelayedBroadcast(t *testing.T) {
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	addr, err := w.CurrentAddress(0, waddrmgr.KeyScopeBIP0084)
+	require.NoError(t, err)
+	pkScript, err := txscript.PayToAddrScript(addr)
+	require.NoError(t, err)
+	addUtxo(t, w, &wire.MsgTx{
+		TxIn:  []*wire.TxIn{{}},
+		TxOut: []*wire.TxOut{wire.NewTxOut(1000000, pkScript)},
+	})
+
+	broadcasts := make(chan *wire.MsgTx, 1)
+	w.SetBroadcastPolicy(BroadcastPolicy{
+		MaxDelay: 100 * time.Millisecond,
+		Broadcast: func(tx *wire.MsgTx) error {
+			broadcasts <- tx
+			return nil
+		},
+	})
+
+	tx, err := w.SendOutputs(context.Background(),
+		[]*wire.TxOut{wire.NewTxOut(100000, pkScript)}, nil, 0, 1,
+		1000, CoinSelectionLargest, "",
+	)
+	require.NoError(t, err)
+
+	// The transaction is recorded before it is broadcast.
+	txHash := tx.TxHash()
+	details, err := UnstableAPI(w).TxDetails(&txHash)
+	require.NoError(t, err)
+	require.NotNil(t, details)
+
+	select {
+	case broadcast := <-broadcasts:
+		require.Equal(t, txHash, broadcast.TxHash())
+	case <-time.After(5 * time.Second):
+		t.Fatalf("transaction was not broadcast")
+	}
+}
+
+// TestPauseSends checks that transactions are neither created nor published
+// while sends are paused.
+func TestPauseSends(t *testing.T) {
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	addr, err := w.CurrentAddress(0, waddrmgr.KeyScopeBIP0084)
+	require.NoError(t, err)
+	pkScript, err := txscript.PayToAddrScript(addr)
+	require.NoError(t, err)
+	addUtxo(t, w, &wire.MsgTx{
+		TxIn:  []*wire.TxIn{{}},
+		TxOut: []*wire.TxOut{wire.NewTxOut(1000000, pkScript)},
+	})
+	outputs := []*wire.TxOut{wire.NewTxOut(100000, pkScript)}
+
+	reason := errors.New("chain backends diverged")
+	w.PauseSends(reason)
+	require.Equal(t, reason, w.SendsPaused())
+
+	_, err = w.SendOutputs(context.Background(), outputs, nil, 0, 1, 1000,
+		CoinSelectionLargest, "")
+	var paused *ErrSendsPaused
+	require.ErrorAs(t, err, &paused)
+	require.ErrorIs(t, err, reason)
+	err = w.PublishTransaction(&wire.MsgTx{TxOut: outputs}, "")
+	require.ErrorAs(t, err, &paused)
+
+	// Dry runs do not send, so they are not refused.
+	_, err = w.CreateSimpleTx(nil, 0, outputs, 1, 1000,
+		CoinSelectionLargest, true)
+	require.NoError(t, err)
+
+	w.ResumeSends()
+	require.NoError(t, w.SendsPaused())
+	_, err = w.SendOutputs(context.Background(), outputs, nil, 0, 1, 1000,
+		CoinSelectionLargest, "")
+	require.NoError(t, err)
+}
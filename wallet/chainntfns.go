@@ -23,8 +23,96 @@ const (
 	// birthday timestamp and our birthday block's timestamp when searching
 	// for a better birthday block candidate (if possible).
 	birthdayBlockDelta = 2 * time.Hour
+
+	// rescanCommitBlocks is the number of blocks whose relevant
+	// transactions are written in a single database transaction while the
+	// wallet is rescanning.
+	rescanCommitBlocks = 100
+
+	// rescanCommitInterval is the longest time the relevant transactions
+	// of a rescan are held before being written when no further
+	// notifications arrive.
+	rescanCommitInterval = time.Second
+
+	// relevantCommitTxs is the number of relevant transactions reported
+	// while the wallet is synced which are written in a single database
+	// transaction.
+	relevantCommitTxs = 100
+
+	// relevantCommitLatency is the longest time a relevant transaction
+	// reported while the wallet is synced is held before being written.
+	relevantCommitLatency = 100 * time.Millisecond
+
+	// catchUpBatchSize is the number of block hashes fetched from the
+	// chain server before they are written in a single database
+	// transaction when catching up after a rescan.
+	catchUpBatchSize = 500
 )
 
+// relevantTxs buffers the relevant transactions reported by the chain server,
+// so that they are written together rather than in a database transaction
+// each.  While the wallet is rescanning, the mined transactions are written
+// once per rescanCommitBlocks blocks.  Once it's synced, the transactions of
+// the mempool and of new blocks are written once per relevantCommitTxs
+// transactions, or relevantCommitLatency after the first is buffered.
+type relevantTxs struct {
+	recs      []*wtxmgr.TxRecord
+	blocks    []*wtxmgr.BlockMeta
+	numBlocks int
+	lastBlock chainhash.Hash
+}
+
+// add buffers a transaction of a block, or an unmined one when block is nil.
+func (b *relevantTxs) add(rec *wtxmgr.TxRecord, block *wtxmgr.BlockMeta) {
+	if block != nil && (b.numBlocks == 0 || block.Hash != b.lastBlock) {
+		b.numBlocks++
+		b.lastBlock = block.Hash
+	}
+	b.recs = append(b.recs, rec)
+	b.blocks = append(b.blocks, block)
+}
+
+// commit writes the buffered transactions in a single database transaction
+// and empties the buffer.
+func (b *relevantTxs) commit(w *Wallet) error {
+	if len(b.recs) == 0 {
+		return nil
+	}
+	err := walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		for i, rec := range b.recs {
+			if err := w.addChainTx(tx, rec, b.blocks[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		log.Debugf("Wrote %d relevant transactions from %d blocks",
+			len(b.recs), b.numBlocks)
+	} else if len(b.recs) > 1 {
+		// A transaction which can't be written doesn't lose the
+		// others, which are written in a database transaction each.
+		// The notifications of the transactions are only sent once
+		// they are committed, so none was sent for the failed batch.
+		log.Warnf("Unable to write %d relevant transactions at once, "+
+			"writing them separately: %v", len(b.recs), err)
+		err = nil
+		for i, rec := range b.recs {
+			block := b.blocks[i]
+			e := walletdb.Update(w.db, func(
+				tx walletdb.ReadWriteTx) error {
+
+				return w.addChainTx(tx, rec, block)
+			})
+			if e != nil {
+				err = e
+			}
+		}
+	}
+	*b = relevantTxs{recs: b.recs[:0], blocks: b.blocks[:0]}
+	return err
+}
+
 func (w *Wallet) handleChainNotifications() {
 	defer w.wg.Done()
 
@@ -48,41 +136,81 @@ func (w *Wallet) handleChainNotifications() {
 		// rescan.
 		log.Infof("Catching up block hashes to height %d, this"+
 			" might take a while", height)
-		err := walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
-			ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
 
-			startBlock := w.Manager.SyncedTo()
+		// The hashes are fetched without a database transaction open,
+		// and written in batches, so that the chain server round trips
+		// don't hold up the other writers of the wallet.
+		startBlock := w.Manager.SyncedTo()
+		for start := startBlock.Height + 1; start <= height; {
+			end := start + catchUpBatchSize - 1
+			if end > height {
+				end = height
+			}
 
-			for i := startBlock.Height + 1; i <= height; i++ {
+			stamps := make([]waddrmgr.BlockStamp, 0, end-start+1)
+			for i := start; i <= end; i++ {
 				hash, err := client.GetBlockHash(int64(i))
 				if err != nil {
+					log.Errorf("Failed to fetch "+
+						"block hash for height %d: %v",
+						i, err)
 					return err
 				}
 				header, err := chainClient.GetBlockHeader(hash)
 				if err != nil {
+					log.Errorf("Failed to fetch "+
+						"block header for height %d: "+
+						"%v", i, err)
 					return err
 				}
-
-				bs := waddrmgr.BlockStamp{
+				stamps = append(stamps, waddrmgr.BlockStamp{
 					Height:    i,
 					Hash:      *hash,
 					Timestamp: header.Timestamp,
+				})
+			}
+
+			err := walletdb.Update(w.db, func(
+				tx walletdb.ReadWriteTx) error {
+
+				ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+				for i := range stamps {
+					err := w.Manager.SetSyncedTo(
+						ns, &stamps[i],
+					)
+					if err != nil {
+						return err
+					}
 				}
-				err = w.Manager.SetSyncedTo(ns, &bs)
-				if err != nil {
-					return err
-				}
+				return nil
+			})
+			if err != nil {
+				log.Errorf("Failed to update address "+
+					"manager sync state for height %d: %v",
+					end, err)
+				return err
 			}
-			return nil
-		})
-		if err != nil {
-			log.Errorf("Failed to update address manager "+
-				"sync state for height %d: %v", height, err)
+			start = end + 1
 		}
 
 		log.Info("Done catching up block hashes")
-		return err
+		return nil
+	}
+
+	// The relevant transactions are buffered and written together, before
+	// any other notification is handled.
+	var (
+		pending relevantTxs
+		commitC <-chan time.Time
+	)
+	commitPending := func() {
+		commitC = nil
+		if err := pending.commit(w); err != nil {
+			log.Errorf("Unable to write relevant transactions: %v",
+				err)
+		}
 	}
+	defer commitPending()
 
 	for {
 		select {
@@ -91,6 +219,26 @@ func (w *Wallet) handleChainNotifications() {
 				return
 			}
 
+			if !w.ChainSynced() && w.bufferRescanTxs(&pending, n) {
+				if pending.numBlocks >= rescanCommitBlocks {
+					commitPending()
+				} else if commitC == nil && len(pending.recs) > 0 {
+					commitC = time.After(rescanCommitInterval)
+				}
+				continue
+			}
+			if w.ChainSynced() && w.bufferRelevantTx(&pending, n) {
+				if len(pending.recs) >= relevantCommitTxs {
+					commitPending()
+				} else if commitC == nil {
+					commitC = time.After(
+						relevantCommitLatency,
+					)
+				}
+				continue
+			}
+			commitPending()
+
 			var notificationName string
 			var err error
 			switch n := n.(type) {
@@ -129,7 +277,7 @@ func (w *Wallet) handleChainNotifications() {
 				notificationName = "block disconnected"
 			case chain.RelevantTx:
 				err = walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
-					return w.addRelevantTx(tx, n.TxRecord, n.Block)
+					return w.addChainTx(tx, n.TxRecord, n.Block)
 				})
 				notificationName = "relevant transaction"
 			case chain.FilteredBlockConnected:
@@ -139,7 +287,7 @@ func (w *Wallet) handleChainNotifications() {
 						tx walletdb.ReadWriteTx) error {
 						var err error
 						for _, rec := range n.RelevantTxs {
-							err = w.addRelevantTx(tx, rec,
+							err = w.addChainTx(tx, rec,
 								n.Block)
 							if err != nil {
 								return err
@@ -190,12 +338,47 @@ func (w *Wallet) handleChainNotifications() {
 					"%v notification: %v", notificationName,
 					err)
 			}
+		case <-commitC:
+			commitPending()
 		case <-w.quit:
 			return
 		}
 	}
 }
 
+// bufferRescanTxs adds the mined transactions of a RelevantTx or
+// FilteredBlockConnected notification to the pending rescan transactions,
+// returning whether the notification was handled.
+func (w *Wallet) bufferRescanTxs(pending *relevantTxs, n interface{}) bool {
+	switch n := n.(type) {
+	case chain.RelevantTx:
+		if n.Block == nil {
+			return false
+		}
+		pending.add(n.TxRecord, n.Block)
+		return true
+
+	case chain.FilteredBlockConnected:
+		for _, rec := range n.RelevantTxs {
+			pending.add(rec, n.Block)
+		}
+		return true
+	}
+	return false
+}
+
+// bufferRelevantTx adds the transaction of a RelevantTx notification, mined or
+// not, to the pending transactions, returning whether the notification was
+// handled.
+func (w *Wallet) bufferRelevantTx(pending *relevantTxs, n interface{}) bool {
+	rel, ok := n.(chain.RelevantTx)
+	if !ok {
+		return false
+	}
+	pending.add(rel.TxRecord, rel.Block)
+	return true
+}
+
 // connectBlock handles a chain server notification by marking a wallet
 // that's currently in-sync with the chain server as being synced up to
 // the passed block.
@@ -329,6 +512,10 @@ func (w *Wallet) addRelevantTx(dbtx walletdb.ReadWriteTx, rec *wtxmgr.TxRecord,
 		}
 	}
 
+	if err := w.creditInvoices(dbtx, rec); err != nil {
+		return err
+	}
+
 	// Send notification of mined or unmined transaction to any interested
 	// clients.
 	//
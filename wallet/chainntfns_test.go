@@ -8,9 +8,13 @@ import (
 
 	"github.com/lbryio/lbcd/chaincfg"
 	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcd/txscript"
 	"github.com/lbryio/lbcd/wire"
+	"github.com/lbryio/lbcwallet/chain"
 	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/walletdb"
 	_ "github.com/lbryio/lbcwallet/walletdb/bdb"
+	"github.com/lbryio/lbcwallet/wtxmgr"
 )
 
 const (
@@ -308,3 +312,255 @@ func TestBirthdaySanityCheckHigherEstimate(t *testing.T) {
 			"%v vs %v", birthdayStore.syncedTo, birthdayBlock)
 	}
 }
+
+// TestRescanTxsCommit ensures the transactions buffered while rescanning are
+// written with their blocks in a single commit.
+func TestRescanTxsCommit(t *testing.T) {
+	t.Parallel()
+
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	addr, err := w.NewAddress(0, waddrmgr.KeyScopeBIP0044)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newRec := func(i uint32) *wtxmgr.TxRecord {
+		msgTx := wire.NewMsgTx(wire.TxVersion)
+		msgTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: i}, nil, nil))
+		msgTx.AddTxOut(wire.NewTxOut(1000, pkScript))
+		rec, err := wtxmgr.NewTxRecordFromMsgTx(msgTx, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rec
+	}
+	block1 := &wtxmgr.BlockMeta{
+		Block: wtxmgr.Block{Hash: chainhash.Hash{1}, Height: 1},
+	}
+	block2 := &wtxmgr.BlockMeta{
+		Block: wtxmgr.Block{Hash: chainhash.Hash{2}, Height: 2},
+	}
+	recs := []*wtxmgr.TxRecord{newRec(0), newRec(1), newRec(2)}
+
+	var pending relevantTxs
+	ntfns := []interface{}{
+		chain.RelevantTx{TxRecord: recs[0], Block: block1},
+		chain.RelevantTx{TxRecord: recs[1], Block: block1},
+		chain.FilteredBlockConnected{
+			Block:       block2,
+			RelevantTxs: recs[2:],
+		},
+	}
+	for _, n := range ntfns {
+		if !w.bufferRescanTxs(&pending, n) {
+			t.Fatalf("notification %T was not buffered", n)
+		}
+	}
+	unmined := chain.RelevantTx{TxRecord: newRec(3)}
+	if w.bufferRescanTxs(&pending, unmined) {
+		t.Fatal("unmined transaction was buffered")
+	}
+	if pending.numBlocks != 2 || len(pending.recs) != 3 {
+		t.Fatalf("got %d transactions of %d blocks, want 3 of 2",
+			len(pending.recs), pending.numBlocks)
+	}
+
+	if err := pending.commit(w); err != nil {
+		t.Fatal(err)
+	}
+	if len(pending.recs) != 0 || pending.numBlocks != 0 {
+		t.Fatal("commit did not empty the buffer")
+	}
+	err = walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		ns := tx.ReadBucket(wtxmgrNamespaceKey)
+		for i, rec := range recs {
+			details, err := w.TxStore.TxDetails(ns, &rec.Hash)
+			if err != nil {
+				return err
+			}
+			want := int32(1)
+			if i == 2 {
+				want = 2
+			}
+			if details == nil || details.Block.Height != want {
+				t.Errorf("transaction %d is not mined in block %d",
+					i, want)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestRelevantTxsCommit ensures the mined and unmined transactions reported
+// once the wallet is synced are buffered, and written in a single commit.
+func TestRelevantTxsCommit(t *testing.T) {
+	t.Parallel()
+
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	addr, err := w.NewAddress(0, waddrmgr.KeyScopeBIP0044)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newRec := func(i uint32) *wtxmgr.TxRecord {
+		msgTx := wire.NewMsgTx(wire.TxVersion)
+		msgTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: i}, nil, nil))
+		msgTx.AddTxOut(wire.NewTxOut(1000, pkScript))
+		rec, err := wtxmgr.NewTxRecordFromMsgTx(msgTx, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rec
+	}
+	block := &wtxmgr.BlockMeta{
+		Block: wtxmgr.Block{Hash: chainhash.Hash{1}, Height: 1},
+	}
+	recs := []*wtxmgr.TxRecord{newRec(0), newRec(1), newRec(2)}
+
+	var pending relevantTxs
+	ntfns := []interface{}{
+		chain.RelevantTx{TxRecord: recs[0]},
+		chain.RelevantTx{TxRecord: recs[1], Block: block},
+		chain.RelevantTx{TxRecord: recs[2]},
+	}
+	for _, n := range ntfns {
+		if !w.bufferRelevantTx(&pending, n) {
+			t.Fatalf("notification %T was not buffered", n)
+		}
+	}
+	if w.bufferRelevantTx(&pending, chain.BlockConnected(*block)) {
+		t.Fatal("block connected notification was buffered")
+	}
+	if pending.numBlocks != 1 || len(pending.recs) != 3 {
+		t.Fatalf("got %d transactions of %d blocks, want 3 of 1",
+			len(pending.recs), pending.numBlocks)
+	}
+
+	if err := pending.commit(w); err != nil {
+		t.Fatal(err)
+	}
+	if len(pending.recs) != 0 || pending.numBlocks != 0 {
+		t.Fatal("commit did not empty the buffer")
+	}
+	err = walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		ns := tx.ReadBucket(wtxmgrNamespaceKey)
+		for i, rec := range recs {
+			details, err := w.TxStore.TxDetails(ns, &rec.Hash)
+			if err != nil {
+				return err
+			}
+			want := int32(-1)
+			if i == 1 {
+				want = 1
+			}
+			if details == nil || details.Block.Height != want {
+				t.Errorf("transaction %d is not at height %d",
+					i, want)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestRelevantTxsCommitNotifications ensures the transactions written
+// separately after their batch failed are notified once, as none of the
+// notifications of the failed batch is sent.
+func TestRelevantTxsCommitNotifications(t *testing.T) {
+	t.Parallel()
+
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	newRec := func(i uint32) *wtxmgr.TxRecord {
+		addr, err := w.NewAddress(0, waddrmgr.KeyScopeBIP0044)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pkScript, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgTx := wire.NewMsgTx(wire.TxVersion)
+		msgTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: i}, nil, nil))
+		msgTx.AddTxOut(wire.NewTxOut(1000, pkScript))
+		rec, err := wtxmgr.NewTxRecordFromMsgTx(msgTx, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rec
+	}
+	good, bad := newRec(0), newRec(1)
+
+	// The transaction paying the address of an invoice which can't be
+	// found fails to be written.
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(
+		bad.MsgTx.TxOut[0].PkScript, w.chainParams,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(walletNamespaceKey)
+		invoiceAddrs, err := ns.CreateBucketIfNotExists(
+			bucketInvoiceAddrs,
+		)
+		if err != nil {
+			return err
+		}
+		return invoiceAddrs.Put(
+			[]byte(addrs[0].EncodeAddress()), []byte("missing"),
+		)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := w.NtfnServer.TransactionNotifications()
+	defer client.Done()
+	notified := make(chan chainhash.Hash, 10)
+	go func() {
+		for n := range client.C {
+			for _, tx := range n.UnminedTransactions {
+				notified <- *tx.Hash
+			}
+		}
+	}()
+
+	var pending relevantTxs
+	pending.add(good, nil)
+	pending.add(bad, nil)
+	if err := pending.commit(w); err == nil {
+		t.Fatal("expected the invalid transaction to fail")
+	}
+
+	select {
+	case hash := <-notified:
+		if hash != good.Hash {
+			t.Fatalf("got notification of %v, want %v", hash,
+				good.Hash)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the written transaction was not notified")
+	}
+	select {
+	case hash := <-notified:
+		t.Fatalf("got another notification of %v", hash)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,154 @@
+package wallet
+
+import (
+	"math/rand"
+
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/wallet/txauthor"
+	"github.com/lbryio/lbcwallet/wallet/txrules"
+	"github.com/lbryio/lbcwallet/walletdb"
+)
+
+// ChangePolicy controls the change outputs of the transactions created by the
+// wallet, so that they are harder to tell apart from the payments.  The
+// position of the change is always random.
+type ChangePolicy struct {
+	// MatchOutputType pays the change to an address of the type of the
+	// payment outputs, when a transaction may spend from every address
+	// type and all its outputs are of one type the wallet derives.  The
+	// change is otherwise paid to a bech32 address.
+	MatchOutputType bool
+
+	// SplitChange splits the change into two outputs of random amounts,
+	// when it is large enough to pay for the extra output and leave both
+	// above the dust limit.
+	SplitChange bool
+}
+
+// SetChangePolicy sets the policy for the change outputs of the transactions
+// created from now on.
+func (w *Wallet) SetChangePolicy(policy ChangePolicy) {
+	w.changePolicyMtx.Lock()
+	w.changePolicy = policy
+	w.changePolicyMtx.Unlock()
+}
+
+// ChangePolicy returns the policy for change outputs set by SetChangePolicy.
+func (w *Wallet) ChangePolicy() ChangePolicy {
+	w.changePolicyMtx.Lock()
+	defer w.changePolicyMtx.Unlock()
+	return w.changePolicy
+}
+
+// outputsKeyScope returns the key scope whose internal addresses are of the
+// type of every output, or nil when the outputs are of several types or of a
+// type the wallet does not derive.
+func outputsKeyScope(outputs []*wire.TxOut) *waddrmgr.KeyScope {
+	var scope *waddrmgr.KeyScope
+	for _, output := range outputs {
+		pkScript := txscript.StripClaimScriptPrefix(output.PkScript)
+
+		var s *waddrmgr.KeyScope
+		switch {
+		case txscript.IsPayToPubKeyHash(pkScript):
+			s = &waddrmgr.KeyScopeBIP0044
+		case txscript.IsPayToScriptHash(pkScript):
+			s = &waddrmgr.KeyScopeBIP0049
+		case txscript.IsPayToWitnessPubKeyHash(pkScript):
+			s = &waddrmgr.KeyScopeBIP0084
+		default:
+			return nil
+		}
+		if scope != nil && *scope != *s {
+			return nil
+		}
+		scope = s
+	}
+	return scope
+}
+
+// matchingChangeScope returns the key scope of the change of a transaction
+// paying outputs from an account, following the change policy.  Nil is
+// returned to use the default change scope.
+func (w *Wallet) matchingChangeScope(addrmgrNs walletdb.ReadBucket,
+	outputs []*wire.TxOut, account uint32) *waddrmgr.KeyScope {
+
+	if !w.ChangePolicy().MatchOutputType {
+		return nil
+	}
+	scope := outputsKeyScope(outputs)
+	if scope == nil {
+		return nil
+	}
+
+	// The change of the imported account is paid to the default
+	// account, and other accounts need not be defined in every scope.
+	if account == waddrmgr.ImportedAddrAccount {
+		account = waddrmgr.DefaultAccountNum
+	}
+	manager, err := w.Manager.FetchScopedKeyManager(*scope)
+	if err != nil {
+		return nil
+	}
+	if _, err := manager.AccountProperties(addrmgrNs, account); err != nil {
+		return nil
+	}
+	return scope
+}
+
+// splitChange splits the change output of a transaction into two outputs at
+// random positions, when the change policy asks for it and the change is
+// large enough.  The fee of the second output is taken from the change.  The
+// indexes of the change outputs are returned.
+func (w *Wallet) splitChange(tx *txauthor.AuthoredTx,
+	feeRatePerKb btcutil.Amount,
+	changeSource *txauthor.ChangeSource) ([]int, error) {
+
+	if tx.ChangeIndex < 0 {
+		return nil, nil
+	}
+	changeIndexes := []int{tx.ChangeIndex}
+	if !w.ChangePolicy().SplitChange {
+		return changeIndexes, nil
+	}
+
+	change := tx.Tx.TxOut[tx.ChangeIndex]
+	outputSize := 8 + wire.VarIntSerializeSize(
+		uint64(changeSource.ScriptSize)) + changeSource.ScriptSize
+	remaining := btcutil.Amount(change.Value) -
+		txrules.FeeForSerializeSize(feeRatePerKb, outputSize)
+
+	// The first output keeps between a quarter and three quarters of the
+	// remaining change.
+	quarter := int64(remaining / 4)
+	if quarter <= 0 {
+		return changeIndexes, nil
+	}
+	first := quarter + rand.Int63n(2*quarter+1)
+	second := int64(remaining) - first
+
+	script, err := changeSource.NewScript()
+	if err != nil {
+		return nil, err
+	}
+	firstOut := wire.NewTxOut(first, change.PkScript)
+	secondOut := wire.NewTxOut(second, script)
+	if txrules.IsDustOutput(firstOut, txrules.DefaultRelayFeePerKb) ||
+		txrules.IsDustOutput(secondOut, txrules.DefaultRelayFeePerKb) {
+
+		return changeIndexes, nil
+	}
+
+	change.Value = first
+	tx.Tx.TxOut = append(tx.Tx.TxOut, secondOut)
+	secondIndex := txauthor.RandomizeOutputPosition(
+		tx.Tx.TxOut, len(tx.Tx.TxOut)-1,
+	)
+	if secondIndex == tx.ChangeIndex {
+		tx.ChangeIndex = len(tx.Tx.TxOut) - 1
+	}
+	return []int{tx.ChangeIndex, secondIndex}, nil
+}
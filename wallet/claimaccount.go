@@ -0,0 +1,84 @@
+package wallet
+
+import (
+	"fmt"
+
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	"github.com/lbryio/lbcwallet/walletdb"
+	"github.com/lbryio/lbcwallet/wtxmgr"
+)
+
+// SetClaimAccount designates the account, by name, which funds every
+// transaction created from now on with a claim, support or claim update
+// output, whichever account it is requested from.  Only the outputs of the
+// account received from transactions spending the wallet's own outputs alone,
+// such as a transfer from another account, are spent, so that publishing is
+// not trivially linked to the balance of the other accounts.  The change is
+// paid back to the claim account.  An empty name funds claims like any other
+// transaction.
+func (w *Wallet) SetClaimAccount(name string) {
+	w.claimAccountMtx.Lock()
+	w.claimAccount = name
+	w.claimAccountMtx.Unlock()
+}
+
+// ClaimAccount returns the name of the account set by SetClaimAccount.
+func (w *Wallet) ClaimAccount() string {
+	w.claimAccountMtx.Lock()
+	defer w.claimAccountMtx.Unlock()
+	return w.claimAccount
+}
+
+// hasClaimOutput returns whether any output has a claim script.
+func hasClaimOutput(outputs []*wire.TxOut) bool {
+	for _, output := range outputs {
+		if _, err := txscript.ExtractClaimScript(output.PkScript); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// claimFundingAccount returns the number of the claim account when it must
+// fund a transaction paying outputs, and false when the transaction is funded
+// from the requested account.
+func (w *Wallet) claimFundingAccount(addrmgrNs walletdb.ReadBucket,
+	outputs []*wire.TxOut) (uint32, bool, error) {
+
+	name := w.ClaimAccount()
+	if name == "" || !hasClaimOutput(outputs) {
+		return 0, false, nil
+	}
+	_, account, err := w.Manager.LookupAccount(addrmgrNs, name)
+	if err != nil {
+		return 0, false, fmt.Errorf("claim account %q: %w", name, err)
+	}
+	return account, true, nil
+}
+
+// selfTransferredCredits returns the credits received from transactions which
+// only spend outputs of the wallet.
+func (w *Wallet) selfTransferredCredits(txmgrNs walletdb.ReadBucket,
+	credits []wtxmgr.Credit) ([]wtxmgr.Credit, error) {
+
+	selfTransferred := make([]wtxmgr.Credit, 0, len(credits))
+	for _, credit := range credits {
+		if credit.FromCoinBase {
+			continue
+		}
+		details, err := w.TxStore.TxDetails(
+			txmgrNs, &credit.OutPoint.Hash,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if details == nil ||
+			len(details.Debits) != len(details.MsgTx.TxIn) {
+
+			continue
+		}
+		selfTransferred = append(selfTransferred, credit)
+	}
+	return selfTransferred, nil
+}
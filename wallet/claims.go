@@ -0,0 +1,191 @@
+package wallet
+
+import (
+	"sort"
+
+	"github.com/lbryio/lbcd/claimtrie/change"
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/walletdb"
+)
+
+// claimOutput describes the claim script of an output.
+type claimOutput struct {
+	// Op is "claim", "support" or "update".
+	Op      string
+	Name    string
+	ClaimID string
+
+	// Kind and SigningChannel describe the value of claims and updates,
+	// when it could be decoded.
+	Kind           string
+	SigningChannel string
+}
+
+// decodeClaimOutput decodes the claim script of an output.  The claim ID of a
+// new claim is derived from its outpoint.  False is returned for outputs
+// without a claim script.
+func decodeClaimOutput(op wire.OutPoint, pkScript []byte) (*claimOutput, bool) {
+	cs, err := txscript.ExtractClaimScript(pkScript)
+	if err != nil {
+		return nil, false
+	}
+
+	c := &claimOutput{Name: string(cs.Name)}
+	var claimID change.ClaimID
+	switch cs.Opcode {
+	case txscript.OP_CLAIMNAME:
+		c.Op = "claim"
+		claimID = change.NewClaimID(op)
+	case txscript.OP_SUPPORTCLAIM:
+		c.Op = "support"
+		copy(claimID[:], cs.ClaimID)
+	case txscript.OP_UPDATECLAIM:
+		c.Op = "update"
+		copy(claimID[:], cs.ClaimID)
+	default:
+		return nil, false
+	}
+	c.ClaimID = claimID.String()
+	if c.Op != "support" {
+		c.Kind, c.SigningChannel = decodeClaimValue(cs.Value)
+	}
+	return c, true
+}
+
+// decodeClaimValue returns the kind of a claim value, which is "stream",
+// "channel", "collection" or "repost", and the claim ID of the channel which
+// signed it, if any.  Values are an unsigned (0x00) or signed (0x01) header
+// followed by a protobuf Claim message, whose first field is the kind of the
+// claim.  A signed header holds the 20 byte claim ID of the channel and a 64
+// byte signature.  Empty strings are returned for values of other formats.
+func decodeClaimValue(value []byte) (kind, channel string) {
+	if len(value) == 0 {
+		return "", ""
+	}
+	var message []byte
+	switch value[0] {
+	case 0x00:
+		message = value[1:]
+	case 0x01:
+		if len(value) < 1+change.ClaimIDSize+64 {
+			return "", ""
+		}
+		var channelID change.ClaimID
+		copy(channelID[:], value[1:1+change.ClaimIDSize])
+		channel = channelID.String()
+		message = value[1+change.ClaimIDSize+64:]
+	default:
+		return "", ""
+	}
+	if len(message) == 0 {
+		return "", channel
+	}
+
+	// The field tags of the length delimited fields 1 to 4.
+	switch message[0] {
+	case 0x0a:
+		kind = "stream"
+	case 0x12:
+		kind = "channel"
+	case 0x1a:
+		kind = "collection"
+	case 0x22:
+		kind = "repost"
+	}
+	return kind, channel
+}
+
+// AccountClaim is an unspent claim, support or claim update output of an
+// account.
+type AccountClaim struct {
+	OutPoint wire.OutPoint
+	Account  uint32
+	Address  btcutil.Address
+	Amount   btcutil.Amount
+
+	// Height is the height of the block the output is mined in, or -1 for
+	// unmined outputs.
+	Height int32
+
+	// Op is "claim", "support" or "update".  ClaimID is the ID of the
+	// claim, or of the claim supported or updated.
+	Op      string
+	Name    string
+	ClaimID string
+
+	// Kind is "stream", "channel", "collection" or "repost", and
+	// SigningChannel is the claim ID of the channel which signed the
+	// claim, for claims and updates whose value could be decoded.
+	Kind           string
+	SigningChannel string
+}
+
+// AccountClaims returns the unspent claims, supports and claim updates of
+// every account, which are the outputs paying to the account's addresses,
+// sorted by account and then by height with unmined outputs last.  Channels
+// are claims too, so the account of a channel is the account of its current
+// claim or update.
+func (w *Wallet) AccountClaims() ([]AccountClaim, error) {
+	var claims []AccountClaim
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
+		txmgrNs := tx.ReadBucket(wtxmgrNamespaceKey)
+
+		unspent, err := w.TxStore.UnspentOutputs(txmgrNs)
+		if err != nil {
+			return err
+		}
+		for i := range unspent {
+			output := &unspent[i]
+			if !isStake(output.PkScript) {
+				continue
+			}
+			c, ok := decodeClaimOutput(output.OutPoint, output.PkScript)
+			if !ok {
+				continue
+			}
+			_, addrs, _, err := txscript.ExtractPkScriptAddrs(
+				output.PkScript, w.chainParams)
+			if err != nil || len(addrs) == 0 {
+				continue
+			}
+			_, acct, err := w.Manager.AddrAccount(addrmgrNs, addrs[0])
+			if err != nil {
+				continue
+			}
+			claims = append(claims, AccountClaim{
+				OutPoint:       output.OutPoint,
+				Account:        acct,
+				Address:        addrs[0],
+				Amount:         output.Amount,
+				Height:         output.Height,
+				Op:             c.Op,
+				Name:           c.Name,
+				ClaimID:        c.ClaimID,
+				Kind:           c.Kind,
+				SigningChannel: c.SigningChannel,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(claims, func(i, j int) bool {
+		a, b := &claims[i], &claims[j]
+		if a.Account != b.Account {
+			return a.Account < b.Account
+		}
+		if a.Height != b.Height {
+			if a.Height == -1 || b.Height == -1 {
+				return b.Height == -1
+			}
+			return a.Height < b.Height
+		}
+		return a.ClaimID < b.ClaimID
+	})
+	return claims, nil
+}
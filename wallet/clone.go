@@ -0,0 +1,102 @@
+package wallet
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/walletdb"
+)
+
+// Clone writes a watch-only copy of the wallet database to a new file at path.
+// The copy holds the accounts, addresses, transactions and metadata of the
+// wallet, but its private keys, encrypted scripts and the parameters of its
+// private passphrase are deleted, so that it can be loaded where the keys of
+// the wallet must not be held, and never unlocked.  The copy keeps the public
+// passphrase of the wallet.  An existing file is never overwritten.
+func (w *Wallet) Clone(path string) error {
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		if err == nil {
+			err = fmt.Errorf("%s already exists", path)
+		}
+		return err
+	}
+
+	// The private keys are deleted from a snapshot of the database, which
+	// is then compacted into the clone, as the pages freed by the deletion
+	// would still hold them.
+	var snapshot bytes.Buffer
+	if err := w.db.Copy(&snapshot); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	err := os.WriteFile(tmp, snapshot.Bytes(), 0600)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+
+	src, err := walletdb.Open("bdb", tmp, true, DefaultDBTimeout)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	err = walletdb.Update(src, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		return waddrmgr.DeletePrivateKeys(ns)
+	})
+	if err != nil {
+		return err
+	}
+
+	dst, err := walletdb.Create("bdb", path, true, DefaultDBTimeout)
+	if err != nil {
+		return err
+	}
+	err = compactDB(src, dst)
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		if rerr := os.Remove(path); rerr != nil {
+			log.Errorf("Unable to remove the clone %s: %v", path, rerr)
+		}
+		return fmt.Errorf("unable to clone the wallet: %w", err)
+	}
+	return nil
+}
+
+// compactDB copies the buckets of a database into an empty one.
+func compactDB(src, dst walletdb.DB) error {
+	return walletdb.Update(src, func(srcTx walletdb.ReadWriteTx) error {
+		return walletdb.Update(dst, func(dstTx walletdb.ReadWriteTx) error {
+			return srcTx.ForEachBucket(func(key []byte) error {
+				to, err := dstTx.CreateTopLevelBucket(key)
+				if err != nil {
+					return err
+				}
+				return copyBucket(srcTx.ReadWriteBucket(key), to)
+			})
+		})
+	})
+}
+
+// copyBucket copies the pairs, nested buckets and sequence of a bucket.
+func copyBucket(from, to walletdb.ReadWriteBucket) error {
+	err := from.ForEach(func(k, v []byte) error {
+		fromNested := from.NestedReadWriteBucket(k)
+		if fromNested == nil {
+			return to.Put(k, v)
+		}
+		nested, err := to.CreateBucket(k)
+		if err != nil {
+			return err
+		}
+		return copyBucket(fromNested, nested)
+	})
+	if err != nil {
+		return err
+	}
+	return to.SetSequence(from.Sequence())
+}
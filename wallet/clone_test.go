@@ -0,0 +1,46 @@
+package wallet
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/walletdb"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClone checks that a clone of the wallet holds its addresses, but none of
+// its private keys.
+func TestClone(t *testing.T) {
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	addr, err := w.NewAddress(0, waddrmgr.KeyScopeBIP0044)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "clone.db")
+	require.NoError(t, w.Clone(path))
+	require.Error(t, w.Clone(path), "an existing file was overwritten")
+
+	db, err := walletdb.Open("bdb", path, true, DefaultDBTimeout)
+	require.NoError(t, err)
+	defer db.Close()
+	clone, err := Open(db, w.ChainParams(), 0)
+	require.NoError(t, err)
+	require.True(t, clone.Manager.WatchOnly())
+
+	info, err := clone.AddressInfo(addr)
+	require.NoError(t, err)
+	require.Equal(t, uint32(0), info.InternalAccount())
+
+	err = walletdb.View(db, func(tx walletdb.ReadTx) error {
+		ns := tx.ReadBucket(waddrmgrNamespaceKey)
+		return clone.Manager.Unlock(ns, []byte("hello world"))
+	})
+	var managerErr waddrmgr.ManagerError
+	require.ErrorAs(t, err, &managerErr)
+	require.Equal(t, waddrmgr.ErrWatchingOnly, managerErr.ErrorCode)
+
+	_, err = clone.DumpWIFPrivateKey(addr)
+	require.Error(t, err)
+}
@@ -0,0 +1,391 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcutil/hdkeychain"
+	"github.com/lbryio/lbcutil/psbt"
+	"github.com/lbryio/lbcwallet/internal/zero"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/wallet/txauthor"
+	"github.com/lbryio/lbcwallet/wallet/txrules"
+	"github.com/lbryio/lbcwallet/walletdb"
+)
+
+// ErrPsbtNotSigned is returned when a PSBT passed to PublishPsbt has inputs
+// without their final scripts.
+var ErrPsbtNotSigned = errors.New("PSBT is not fully signed")
+
+// ColdSigningPolicy controls the split of a wallet between an online wallet
+// holding only the public keys of its accounts, such as a copy written by
+// Clone, and an offline one holding their private keys.  The online wallet
+// creates the unsigned transactions of its sends as PSBTs with CreateColdSend,
+// the offline wallet signs them with SignPsbt, and the online wallet publishes
+// the signed PSBTs with PublishPsbt.
+type ColdSigningPolicy struct {
+	// Enabled is whether the sends requested over RPC are created as
+	// PSBTs for the offline wallet instead of being signed.
+	Enabled bool
+
+	// Dir is the directory each PSBT created by CreateColdSend is also
+	// written to, base64 encoded in a file named by the hash of its
+	// unsigned transaction with the .psbt extension, or empty not to
+	// write them.
+	Dir string
+}
+
+// SetColdSigningPolicy sets the policy for the offline signing of the sends
+// requested from now on.
+func (w *Wallet) SetColdSigningPolicy(policy ColdSigningPolicy) {
+	w.coldSigningPolicyMtx.Lock()
+	w.coldSigningPolicy = policy
+	w.coldSigningPolicyMtx.Unlock()
+}
+
+// ColdSigningPolicy returns the policy for the offline signing of sends set
+// by SetColdSigningPolicy.
+func (w *Wallet) ColdSigningPolicy() ColdSigningPolicy {
+	w.coldSigningPolicyMtx.Lock()
+	defer w.coldSigningPolicyMtx.Unlock()
+	return w.coldSigningPolicy
+}
+
+// ColdSend is an unsigned transaction created by CreateColdSend.
+type ColdSend struct {
+	// Packet is the PSBT of the transaction, whose inputs carry their
+	// UTXO information and BIP0032 derivation paths.
+	Packet *psbt.Packet
+
+	Fee btcutil.Amount
+
+	// File is the path the PSBT was written to, or empty when the policy
+	// has no directory.
+	File string
+}
+
+// CreateColdSend creates the transaction SendOutputs would send as an
+// unsigned PSBT, to be signed by the offline wallet holding the keys of the
+// account with SignPsbt, and published with PublishPsbt.  The PSBT is also
+// written to the directory of the cold signing policy, if any.  The outputs
+// it spends are locked until it is published, or unlocked with
+// UnlockOutpoint, and its change addresses are not handed out again.
+func (w *Wallet) CreateColdSend(ctx context.Context, outputs []*wire.TxOut,
+	keyScope *waddrmgr.KeyScope, account uint32, minconf int32,
+	satPerKb btcutil.Amount, coinSelectionStrategy CoinSelectionStrategy) (
+	*ColdSend, error) {
+
+	for _, output := range outputs {
+		err := txrules.CheckOutput(
+			output, txrules.DefaultRelayFeePerKb,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tx, err := w.createTx(ctx, createTxRequest{
+		keyScope:              keyScope,
+		account:               account,
+		outputs:               outputs,
+		minconf:               minconf,
+		feeSatPerKB:           satPerKb,
+		coinSelectionStrategy: coinSelectionStrategy,
+		unsigned:              true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	packet, err := psbt.NewFromUnsignedTx(tx.Tx)
+	if err == nil {
+		err = w.addPsbtInputInfo(packet, packet.UnsignedTx.TxIn)
+	}
+	if err != nil {
+		w.unlockInputs(tx.Tx)
+		return nil, err
+	}
+	send := &ColdSend{
+		Packet: packet,
+		Fee:    tx.TotalInput - txauthor.SumOutputValues(tx.Tx.TxOut),
+	}
+	if dir := w.ColdSigningPolicy().Dir; dir != "" {
+		send.File, err = writePsbtFile(dir, packet)
+		if err != nil {
+			w.unlockInputs(tx.Tx)
+			return nil, err
+		}
+	}
+	return send, nil
+}
+
+// writePsbtFile writes a PSBT base64 encoded to a new file of a directory,
+// named by the hash of its unsigned transaction, and returns its path.
+func writePsbtFile(dir string, packet *psbt.Packet) (string, error) {
+	b64, err := packet.B64Encode()
+	if err != nil {
+		return "", err
+	}
+	name := packet.UnsignedTx.TxHash().String() + ".psbt"
+	path := filepath.Join(dir, name)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return "", err
+	}
+	_, err = f.WriteString(b64 + "\n")
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", err
+	}
+	return path, nil
+}
+
+// SignPsbt adds the final scripts of the inputs of a PSBT spending outputs of
+// the keys of the wallet, and returns how many inputs it signed.  The keys are
+// derived from the BIP0032 derivation paths of the inputs, rather than looked
+// up from the addresses of the wallet, so that an offline wallet which never
+// saw the addresses signs for them.  Inputs already signed, and those of
+// other keys, are left as they are.  The wallet must be unlocked.
+func (w *Wallet) SignPsbt(packet *psbt.Packet) (int, error) {
+	err := psbt.VerifyInputOutputLen(packet, true, true)
+	if err != nil {
+		return 0, err
+	}
+
+	heldUnlock, err := w.holdUnlock()
+	if err != nil {
+		return 0, err
+	}
+	defer heldUnlock.release()
+
+	tx := packet.UnsignedTx
+	sigHashes := txscript.NewTxSigHashes(tx)
+	var signed int
+	err = walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
+		for idx := range tx.TxIn {
+			in := &packet.Inputs[idx]
+			if len(in.FinalScriptSig) > 0 ||
+				len(in.FinalScriptWitness) > 0 {
+
+				continue
+			}
+			utxo, err := psbtInputUtxo(packet, idx)
+			if err != nil {
+				return err
+			}
+			if utxo == nil {
+				continue
+			}
+			addr := w.psbtInputAddr(addrmgrNs, in, utxo.PkScript)
+			if addr == nil {
+				continue
+			}
+			err = signPsbtInput(packet, idx, sigHashes, utxo, addr)
+			if err != nil {
+				return fmt.Errorf("unable to sign input %d: %w",
+					idx, err)
+			}
+			signed++
+		}
+		return nil
+	})
+	return signed, err
+}
+
+// psbtInputUtxo returns the output spent by an input of a PSBT, or nil if the
+// input carries no UTXO information.
+func psbtInputUtxo(packet *psbt.Packet, idx int) (*wire.TxOut, error) {
+	in := packet.Inputs[idx]
+	prevOut := packet.UnsignedTx.TxIn[idx].PreviousOutPoint
+	if in.NonWitnessUtxo == nil {
+		return in.WitnessUtxo, nil
+	}
+	if in.NonWitnessUtxo.TxHash() != prevOut.Hash ||
+		int(prevOut.Index) >= len(in.NonWitnessUtxo.TxOut) {
+
+		return nil, fmt.Errorf("UTXO of input %d doesn't match its "+
+			"outpoint %v", idx, prevOut)
+	}
+	return in.NonWitnessUtxo.TxOut[prevOut.Index], nil
+}
+
+// psbtInputAddr derives the address of the wallet of a BIP0032 derivation
+// path of a PSBT input paying to pkScript, or returns nil if there is none.
+func (w *Wallet) psbtInputAddr(addrmgrNs walletdb.ReadBucket,
+	in *psbt.PInput, pkScript []byte) waddrmgr.ManagedPubKeyAddress {
+
+	pkScript = txscript.StripClaimScriptPrefix(pkScript)
+	for _, derivation := range in.Bip32Derivation {
+		path := derivation.Bip32Path
+		if len(path) != 5 || path[0] < hdkeychain.HardenedKeyStart ||
+			path[1] < hdkeychain.HardenedKeyStart ||
+			path[2] < hdkeychain.HardenedKeyStart {
+
+			continue
+		}
+		scope := waddrmgr.KeyScope{
+			Purpose: path[0] - hdkeychain.HardenedKeyStart,
+			Coin:    path[1] - hdkeychain.HardenedKeyStart,
+		}
+		manager, err := w.Manager.FetchScopedKeyManager(scope)
+		if err != nil {
+			continue
+		}
+		addr, err := manager.DeriveFromKeyPath(
+			addrmgrNs, waddrmgr.DerivationPath{
+				InternalAccount: path[2] -
+					hdkeychain.HardenedKeyStart,
+				Branch: path[3],
+				Index:  path[4],
+			},
+		)
+		if err != nil {
+			continue
+		}
+		pubKeyAddr, ok := addr.(waddrmgr.ManagedPubKeyAddress)
+		if !ok {
+			continue
+		}
+
+		// The derived key must be the one of the path, as the account
+		// of the path may be another of the same number, and pay to
+		// the script of the input.
+		pubKey := pubKeyAddr.PubKey().SerializeCompressed()
+		if !bytes.Equal(pubKey, derivation.PubKey) {
+			continue
+		}
+		addrScript, err := txscript.PayToAddrScript(addr.Address())
+		if err != nil || !bytes.Equal(addrScript, pkScript) {
+			continue
+		}
+		return pubKeyAddr
+	}
+	return nil
+}
+
+// signPsbtInput adds the final scripts of a PSBT input spending utxo, which
+// pays to an address of the wallet.
+func signPsbtInput(packet *psbt.Packet, idx int,
+	sigHashes *txscript.TxSigHashes, utxo *wire.TxOut,
+	addr waddrmgr.ManagedPubKeyAddress) error {
+
+	privKey, err := addr.PrivKey()
+	if err != nil {
+		return err
+	}
+	defer zero.BigInt(privKey.D)
+
+	in := &packet.Inputs[idx]
+	tx := packet.UnsignedTx
+	hashType := in.SighashType
+	if hashType == 0 {
+		hashType = txscript.SigHashAll
+	}
+
+	var (
+		witness   wire.TxWitness
+		sigScript []byte
+	)
+	switch addr.AddrType() {
+	case waddrmgr.WitnessPubKey:
+		witness, err = txscript.WitnessSignature(
+			tx, sigHashes, idx, utxo.Value,
+			txscript.StripClaimScriptPrefix(utxo.PkScript),
+			hashType, privKey, true,
+		)
+
+	// The witness program of a nested p2wkh output is both the
+	// subscript of the signature and the redeem script pushed by the
+	// sigScript.
+	case waddrmgr.NestedWitnessPubKey:
+		var witnessProgram []byte
+		pubKey := addr.PubKey().SerializeCompressed()
+		witnessProgram, err = txscript.NewScriptBuilder().
+			AddOp(txscript.OP_0).AddData(btcutil.Hash160(pubKey)).
+			Script()
+		if err != nil {
+			return err
+		}
+		witness, err = txscript.WitnessSignature(
+			tx, sigHashes, idx, utxo.Value, witnessProgram,
+			hashType, privKey, true,
+		)
+		if err != nil {
+			return err
+		}
+		sigScript, err = txscript.NewScriptBuilder().
+			AddData(witnessProgram).Script()
+
+	default:
+		sigScript, err = txscript.SignatureScript(
+			tx, idx, utxo.PkScript, hashType, privKey, true,
+		)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(witness) > 0 {
+		var witnessBytes bytes.Buffer
+		err := psbt.WriteTxWitness(&witnessBytes, witness)
+		if err != nil {
+			return err
+		}
+		in.FinalScriptWitness = witnessBytes.Bytes()
+	}
+	in.FinalScriptSig = sigScript
+	return nil
+}
+
+// PublishPsbt extracts the transaction of a PSBT signed with SignPsbt,
+// validates its input scripts and publishes it, unlocking the outputs it
+// spends.  ErrPsbtNotSigned is returned when it has unsigned inputs.
+func (w *Wallet) PublishPsbt(packet *psbt.Packet) (*wire.MsgTx, error) {
+	err := psbt.VerifyInputOutputLen(packet, true, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := psbt.MaybeFinalizeAll(packet); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPsbtNotSigned, err)
+	}
+	tx, err := psbt.Extract(packet)
+	if err != nil {
+		return nil, err
+	}
+
+	prevScripts := make([][]byte, len(tx.TxIn))
+	inputValues := make([]btcutil.Amount, len(tx.TxIn))
+	for idx := range tx.TxIn {
+		utxo, err := psbtInputUtxo(packet, idx)
+		if err != nil {
+			return nil, err
+		}
+		if utxo == nil {
+			return nil, fmt.Errorf("input %d has no UTXO "+
+				"information", idx)
+		}
+		prevScripts[idx] = utxo.PkScript
+		inputValues[idx] = btcutil.Amount(utxo.Value)
+	}
+	if err := validateMsgTx(tx, prevScripts, inputValues); err != nil {
+		return nil, err
+	}
+
+	_, err = w.reliablyPublishTransaction(tx, "", nil, true, false)
+	w.unlockInputs(tx)
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
@@ -0,0 +1,94 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	"github.com/lbryio/lbcutil/psbt"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/walletdb"
+	"github.com/stretchr/testify/require"
+)
+
+// TestColdSigning checks that the PSBTs of the sends of a watch-only clone of
+// a wallet are signed by the wallet, and published by the clone once signed.
+func TestColdSigning(t *testing.T) {
+	cold, cleanup := testWallet(t)
+	defer cleanup()
+
+	path := filepath.Join(t.TempDir(), "hot.db")
+	require.NoError(t, cold.Clone(path))
+	db, err := walletdb.Open("bdb", path, true, DefaultDBTimeout)
+	require.NoError(t, err)
+	defer db.Close()
+	hot, err := Open(db, cold.ChainParams(), 0)
+	require.NoError(t, err)
+	hot.chainClient = &mockChainClient{}
+	hot.Start()
+	defer func() {
+		hot.Stop()
+		hot.WaitForShutdown()
+	}()
+
+	// The clone receives to addresses of every type, which the wallet
+	// holding the keys never hands out.
+	deposit := &wire.MsgTx{TxIn: []*wire.TxIn{{}}}
+	for _, scope := range []waddrmgr.KeyScope{
+		waddrmgr.KeyScopeBIP0044, waddrmgr.KeyScopeBIP0049,
+		waddrmgr.KeyScopeBIP0084,
+	} {
+		addr, err := hot.NewAddress(0, scope)
+		require.NoError(t, err)
+		pkScript, err := txscript.PayToAddrScript(addr)
+		require.NoError(t, err)
+		deposit.TxOut = append(
+			deposit.TxOut, wire.NewTxOut(100000, pkScript),
+		)
+	}
+	addUtxo(t, hot, deposit)
+
+	dir := t.TempDir()
+	hot.SetColdSigningPolicy(ColdSigningPolicy{Enabled: true, Dir: dir})
+	payee := wire.NewTxOut(250000, []byte{txscript.OP_TRUE})
+	send, err := hot.CreateColdSend(
+		context.Background(), []*wire.TxOut{payee}, nil, 0, 1, 1000,
+		CoinSelectionLargest,
+	)
+	require.NoError(t, err)
+	require.Len(t, send.Packet.UnsignedTx.TxIn, 3)
+	unsignedHash := send.Packet.UnsignedTx.TxHash()
+	require.Equal(t, filepath.Join(dir, unsignedHash.String()+".psbt"),
+		send.File)
+
+	b, err := os.ReadFile(send.File)
+	require.NoError(t, err)
+	packet, err := psbt.NewFromRawBytes(
+		bytes.NewReader(bytes.TrimSpace(b)), true,
+	)
+	require.NoError(t, err)
+
+	// Only the wallet holding the keys signs the PSBT.
+	_, err = hot.SignPsbt(packet)
+	require.Error(t, err)
+	_, err = hot.PublishPsbt(packet)
+	require.ErrorIs(t, err, ErrPsbtNotSigned)
+
+	signed, err := cold.SignPsbt(packet)
+	require.NoError(t, err)
+	require.Equal(t, 3, signed)
+	signed, err = cold.SignPsbt(packet)
+	require.NoError(t, err)
+	require.Zero(t, signed)
+
+	tx, err := hot.PublishPsbt(packet)
+	require.NoError(t, err)
+	txHash := tx.TxHash()
+	details, err := UnstableAPI(hot).TxDetails(&txHash)
+	require.NoError(t, err)
+	require.NotNil(t, details)
+}
@@ -0,0 +1,119 @@
+package wallet
+
+import (
+	"bytes"
+
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcd/wire"
+	"github.com/lbryio/lbcwallet/walletdb"
+)
+
+// bucketTxComments is the name of the sub bucket of the wallet namespace that
+// maps the hashes of transactions to the comments they were sent with.
+var bucketTxComments = []byte("txcomments")
+
+// TxComment is the free-form text a transaction was sent with, as with the
+// comment and comment_to parameters of the send commands of Bitcoin Core.
+// Comments are only kept by the wallet, and are not part of the transaction.
+type TxComment struct {
+	// Comment describes what the transaction is for.
+	Comment string
+
+	// CommentTo names the person or organization the transaction is
+	// sent to.
+	CommentTo string
+}
+
+// serializeTxComment returns the serialization of a comment, which is the
+// comment followed by the recipient comment, both as varstrings.
+func serializeTxComment(c *TxComment) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := wire.WriteVarString(&buf, 0, c.Comment); err != nil {
+		return nil, err
+	}
+	if err := wire.WriteVarString(&buf, 0, c.CommentTo); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// deserializeTxComment decodes a comment serialized by serializeTxComment.
+func deserializeTxComment(v []byte) (*TxComment, error) {
+	r := bytes.NewReader(v)
+	comment, err := wire.ReadVarString(r, 0)
+	if err != nil {
+		return nil, err
+	}
+	commentTo, err := wire.ReadVarString(r, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &TxComment{Comment: comment, CommentTo: commentTo}, nil
+}
+
+// putTxComment stores the comment of a transaction, unless it is empty.
+func putTxComment(ns walletdb.ReadWriteBucket, hash *chainhash.Hash,
+	c *TxComment) error {
+
+	if c == nil || (c.Comment == "" && c.CommentTo == "") {
+		return nil
+	}
+	v, err := serializeTxComment(c)
+	if err != nil {
+		return err
+	}
+	bucket, err := ns.CreateBucketIfNotExists(bucketTxComments)
+	if err != nil {
+		return err
+	}
+	return bucket.Put(hash[:], v)
+}
+
+// TxComments returns the comments of the wallet transactions sent with one.
+func (w *Wallet) TxComments() (map[chainhash.Hash]TxComment, error) {
+	comments := make(map[chainhash.Hash]TxComment)
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		ns := tx.ReadBucket(walletNamespaceKey)
+		bucket := ns.NestedReadBucket(bucketTxComments)
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			var hash chainhash.Hash
+			if err := hash.SetBytes(k); err != nil {
+				return err
+			}
+			c, err := deserializeTxComment(v)
+			if err != nil {
+				return err
+			}
+			comments[hash] = *c
+			return nil
+		})
+	})
+	return comments, err
+}
+
+// TxComment returns the comment a transaction was sent with, which is empty
+// when it was sent without one.
+func (w *Wallet) TxComment(hash *chainhash.Hash) (TxComment, error) {
+	var c TxComment
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		ns := tx.ReadBucket(walletNamespaceKey)
+		bucket := ns.NestedReadBucket(bucketTxComments)
+		if bucket == nil {
+			return nil
+		}
+		v := bucket.Get(hash[:])
+		if v == nil {
+			return nil
+		}
+		comment, err := deserializeTxComment(v)
+		if err != nil {
+			return err
+		}
+		c = *comment
+		return nil
+	})
+	return c, err
+}
@@ -0,0 +1,72 @@
+package wallet
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTxComments checks that the comment a transaction is sent with is
+// stored with it and exported, and that no comment is stored without one.
+func TestTxComments(t *testing.T) {
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	addr, err := w.CurrentAddress(0, waddrmgr.KeyScopeBIP0084)
+	require.NoError(t, err)
+	pkScript, err := txscript.PayToAddrScript(addr)
+	require.NoError(t, err)
+	addUtxo(t, w, &wire.MsgTx{
+		TxIn:  []*wire.TxIn{{}},
+		TxOut: []*wire.TxOut{wire.NewTxOut(1000000, pkScript)},
+	})
+
+	comment := TxComment{Comment: "invoice 42", CommentTo: "Alice"}
+	tx, err := w.SendOutputsWithComment(context.Background(),
+		[]*wire.TxOut{wire.NewTxOut(100000, pkScript)}, nil, 0, 1,
+		1000, CoinSelectionLargest, "", &comment,
+	)
+	require.NoError(t, err)
+	txHash := tx.TxHash()
+
+	got, err := w.TxComment(&txHash)
+	require.NoError(t, err)
+	require.Equal(t, comment, got)
+	comments, err := w.TxComments()
+	require.NoError(t, err)
+	require.Equal(t, map[chainhash.Hash]TxComment{txHash: comment},
+		comments)
+
+	entries, err := w.ExportTransactions(context.Background(), "*",
+		time.Time{}, time.Time{})
+	require.NoError(t, err)
+	var exported int
+	for _, e := range entries {
+		if e.TxHash == txHash {
+			require.Equal(t, comment.Comment, e.Comment)
+			require.Equal(t, comment.CommentTo, e.CommentTo)
+			exported++
+		}
+	}
+	require.NotZero(t, exported)
+
+	// A send without a comment stores none.
+	tx, err = w.SendOutputsWithComment(context.Background(),
+		[]*wire.TxOut{wire.NewTxOut(100000, pkScript)}, nil, 0, 0,
+		1000, CoinSelectionLargest, "", &TxComment{},
+	)
+	require.NoError(t, err)
+	txHash = tx.TxHash()
+	got, err = w.TxComment(&txHash)
+	require.NoError(t, err)
+	require.Zero(t, got)
+	comments, err = w.TxComments()
+	require.NoError(t, err)
+	require.Len(t, comments, 1)
+}
@@ -0,0 +1,69 @@
+package wallet
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/walletdb"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCanceledContext checks that the long operations of a wallet stop with
+// the error of a canceled context, and that a canceled send publishes
+// nothing.
+func TestCanceledContext(t *testing.T) {
+	w := fixtureWallet(t, &FixtureParams{Receives: 5})
+	require.NoError(t, w.Unlock([]byte("password"), nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := w.ListTransactions(ctx, "*", 0, 10)
+	require.ErrorIs(t, err, context.Canceled)
+	_, err = w.ListAllTransactions(ctx, "*")
+	require.ErrorIs(t, err, context.Canceled)
+	_, err = w.ListSinceBlock(ctx, "*", 0, -1, 0)
+	require.ErrorIs(t, err, context.Canceled)
+	_, err = w.ListAddressTransactions(ctx, "*", nil)
+	require.ErrorIs(t, err, context.Canceled)
+	_, err = w.ExportTransactions(ctx, "*", time.Time{}, time.Time{})
+	require.ErrorIs(t, err, context.Canceled)
+	_, _, err = w.RescanBlockchain(ctx, &mockChainClient{}, 0, 1)
+	require.ErrorIs(t, err, context.Canceled)
+	_, err = w.TotalReceivedForAddrs(ctx, nil, 1)
+	require.ErrorIs(t, err, context.Canceled)
+
+	// The same requests succeed with a live context.
+	txs, err := w.ListAllTransactions(context.Background(), "*")
+	require.NoError(t, err)
+	require.Len(t, txs, 5)
+
+	unmined := func() int {
+		var n int
+		err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+			ns := tx.ReadBucket(wtxmgrNamespaceKey)
+			txs, err := w.TxStore.UnminedTxs(ns)
+			n = len(txs)
+			return err
+		})
+		require.NoError(t, err)
+		return n
+	}
+	before := unmined()
+
+	w.chainClient = &mockChainClient{}
+	addr, err := w.CurrentAddress(0, waddrmgr.KeyScopeBIP0084)
+	require.NoError(t, err)
+	pkScript, err := txscript.PayToAddrScript(addr)
+	require.NoError(t, err)
+	_, err = w.SendOutputs(ctx,
+		[]*wire.TxOut{wire.NewTxOut(100000, pkScript)}, nil, 0, 1,
+		1000, CoinSelectionLargest, "",
+	)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, before, unmined())
+}
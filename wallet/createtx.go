@@ -14,6 +14,7 @@ import (
 	"github.com/lbryio/lbcd/txscript"
 	"github.com/lbryio/lbcd/wire"
 	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/internal/zero"
 	"github.com/lbryio/lbcwallet/waddrmgr"
 	"github.com/lbryio/lbcwallet/wallet/txauthor"
 	"github.com/lbryio/lbcwallet/wallet/txsizes"
@@ -54,13 +55,23 @@ func makeInputSource(eligible []wtxmgr.Credit) txauthor.InputSource {
 }
 
 // secretSource is an implementation of txauthor.SecretSource for the wallet's
-// address manager.
+// address manager.  It keeps track of every private key it hands out so they
+// can be cleared once signing is done.
 type secretSource struct {
 	*waddrmgr.Manager
 	addrmgrNs walletdb.ReadBucket
+	keys      []*btcec.PrivateKey
 }
 
-func (s secretSource) GetKey(addr btcutil.Address) (*btcec.PrivateKey, bool, error) {
+// zeroKeys clears all private keys returned by GetKey.
+func (s *secretSource) zeroKeys() {
+	for _, key := range s.keys {
+		zero.BigInt(key.D)
+	}
+	s.keys = nil
+}
+
+func (s *secretSource) GetKey(addr btcutil.Address) (*btcec.PrivateKey, bool, error) {
 	ma, err := s.Address(s.addrmgrNs, addr)
 	if err != nil {
 		return nil, false, err
@@ -76,10 +87,11 @@ func (s secretSource) GetKey(addr btcutil.Address) (*btcec.PrivateKey, bool, err
 	if err != nil {
 		return nil, false, err
 	}
+	s.keys = append(s.keys, privKey)
 	return privKey, ma.Compressed(), nil
 }
 
-func (s secretSource) GetScript(addr btcutil.Address) ([]byte, error) {
+func (s *secretSource) GetScript(addr btcutil.Address) ([]byte, error) {
 	ma, err := s.Address(s.addrmgrNs, addr)
 	if err != nil {
 		return nil, err
@@ -100,8 +112,9 @@ func (s secretSource) GetScript(addr btcutil.Address) ([]byte, error) {
 // change to the wallet. This output will have an address generated from the
 // given key scope and account. If a key scope is not specified, the address
 // will always be generated from the P2WKH key scope. An appropriate fee is
-// included based on the wallet's current relay fee. The wallet must be
-// unlocked to create the transaction.
+// included based on the wallet's current relay fee. Transactions with claim
+// outputs are funded from the claim account instead, when one is set. The
+// wallet must be unlocked to create the transaction.
 //
 // NOTE: The dryRun argument can be set true to create a tx that doesn't alter
 // the database. A tx created with this set to true will intentionally have no
@@ -111,6 +124,31 @@ func (w *Wallet) txToOutputs(outputs []*wire.TxOut, keyScope *waddrmgr.KeyScope,
 	coinSelectionStrategy CoinSelectionStrategy, dryRun bool) (
 	*txauthor.AuthoredTx, error) {
 
+	return w.authorTxToOutputs(
+		outputs, keyScope, account, minconf, feeSatPerKb,
+		coinSelectionStrategy, dryRun, true,
+	)
+}
+
+// authorTxToOutputs is txToOutputs adding no input scripts when sign is false,
+// for a transaction signed later with signAuthoredTx.  Unlike a dry run, the
+// creation of an unsigned transaction alters the database as that of a signed
+// one, so that its change addresses are not handed out again.
+func (w *Wallet) authorTxToOutputs(outputs []*wire.TxOut,
+	keyScope *waddrmgr.KeyScope, account uint32, minconf int32,
+	feeSatPerKb btcutil.Amount,
+	coinSelectionStrategy CoinSelectionStrategy, dryRun, sign bool) (
+	*txauthor.AuthoredTx, error) {
+
+	if !dryRun {
+		if err := w.requireSendsAllowed(); err != nil {
+			return nil, err
+		}
+		if err := w.requireDiskSpace(); err != nil {
+			return nil, err
+		}
+	}
+
 	chainClient, err := w.requireChainClient()
 	if err != nil {
 		return nil, err
@@ -122,10 +160,30 @@ func (w *Wallet) txToOutputs(outputs []*wire.TxOut, keyScope *waddrmgr.KeyScope,
 		return nil, err
 	}
 
-	var tx *txauthor.AuthoredTx
+	var (
+		tx            *txauthor.AuthoredTx
+		changeIndexes []int
+	)
 	err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
-		addrmgrNs, changeSource, err := w.addrMgrWithChangeSource(
-			dbtx, keyScope, account,
+		claimAccount, fundClaim, err := w.claimFundingAccount(
+			dbtx.ReadBucket(waddrmgrNamespaceKey), outputs,
+		)
+		if err != nil {
+			return err
+		}
+		if fundClaim {
+			account = claimAccount
+		}
+
+		changeKeyScope := keyScope
+		if changeKeyScope == nil {
+			changeKeyScope = w.matchingChangeScope(
+				dbtx.ReadBucket(waddrmgrNamespaceKey), outputs,
+				account,
+			)
+		}
+		_, changeSource, err := w.addrMgrWithChangeSource(
+			dbtx, changeKeyScope, account,
 		)
 		if err != nil {
 			return err
@@ -137,6 +195,14 @@ func (w *Wallet) txToOutputs(outputs []*wire.TxOut, keyScope *waddrmgr.KeyScope,
 		if err != nil {
 			return err
 		}
+		if fundClaim {
+			eligible, err = w.selfTransferredCredits(
+				dbtx.ReadBucket(wtxmgrNamespaceKey), eligible,
+			)
+			if err != nil {
+				return err
+			}
+		}
 
 		var inputSource txauthor.InputSource
 
@@ -186,6 +252,12 @@ func (w *Wallet) txToOutputs(outputs []*wire.TxOut, keyScope *waddrmgr.KeyScope,
 		if tx.ChangeIndex >= 0 {
 			tx.RandomizeChangePosition()
 		}
+		changeIndexes, err = w.splitChange(
+			tx, feeSatPerKb, changeSource,
+		)
+		if err != nil {
+			return err
+		}
 
 		// If a dry run was requested, we return now before adding the
 		// input scripts, and don't commit the database transaction.
@@ -197,54 +269,80 @@ func (w *Wallet) txToOutputs(outputs []*wire.TxOut, keyScope *waddrmgr.KeyScope,
 			return walletdb.ErrDryRunRollBack
 		}
 
-		err = tx.AddAllInputScripts(
-			secretSource{w.Manager, addrmgrNs},
-		)
-		if err != nil {
-			return err
-		}
-
-		err = validateMsgTx(
-			tx.Tx, tx.PrevScripts, tx.PrevInputValues,
-		)
-		if err != nil {
-			return err
-		}
-
 		if tx.ChangeIndex >= 0 && account == waddrmgr.ImportedAddrAccount {
-			changeAmount := btcutil.Amount(
-				tx.Tx.TxOut[tx.ChangeIndex].Value,
-			)
+			var changeAmount btcutil.Amount
+			for _, i := range changeIndexes {
+				changeAmount += btcutil.Amount(tx.Tx.TxOut[i].Value)
+			}
 			log.Warnf("Spend from imported account produced "+
 				"change: moving %v from imported account into "+
 				"default account.", changeAmount)
 		}
 
-		// Finally, we'll request the backend to notify us of the
-		// transaction that pays to the change address, if there is one,
-		// when it confirms.
-		if tx.ChangeIndex >= 0 {
-			changePkScript := tx.Tx.TxOut[tx.ChangeIndex].PkScript
-			_, addrs, _, err := txscript.ExtractPkScriptAddrs(
-				changePkScript, w.chainParams,
-			)
-			if err != nil {
-				return err
-			}
-			if err := chainClient.NotifyReceived(addrs); err != nil {
-				return err
-			}
-		}
-
 		return nil
 	})
-	if err != nil && err != walletdb.ErrDryRunRollBack {
+	if err == walletdb.ErrDryRunRollBack {
+		return tx, nil
+	}
+	if err != nil {
 		return nil, err
 	}
 
+	// The inputs are signed once the change addresses are written, so that
+	// the database write lock isn't held while signing.  The held unlock
+	// of the request keeps the private keys available.
+	if sign {
+		err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+			addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
+			return w.signAuthoredTx(addrmgrNs, tx)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Finally, we'll request the backend to notify us of the transaction
+	// that pays to the change addresses, if there are any, when it
+	// confirms.
+	for _, i := range changeIndexes {
+		changePkScript := tx.Tx.TxOut[i].PkScript
+		_, addrs, _, err := txscript.ExtractPkScriptAddrs(
+			changePkScript, w.chainParams,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if err := chainClient.NotifyReceived(addrs); err != nil {
+			return nil, err
+		}
+	}
+
+	// The inputs of an unsigned transaction are locked before another
+	// transaction is created, so that it is not funded by them too.
+	if !sign {
+		for _, txIn := range tx.Tx.TxIn {
+			w.LockOutpoint(txIn.PreviousOutPoint)
+		}
+	}
+
 	return tx, nil
 }
 
+// signAuthoredTx adds the input scripts of a transaction spending outputs of
+// the wallet, and validates them.
+func (w *Wallet) signAuthoredTx(addrmgrNs walletdb.ReadBucket,
+	tx *txauthor.AuthoredTx) error {
+
+	secrets := &secretSource{Manager: w.Manager, addrmgrNs: addrmgrNs}
+	err := tx.AddAllInputScripts(secrets)
+	secrets.zeroKeys()
+	if err != nil {
+		return err
+	}
+
+	return validateMsgTx(tx.Tx, tx.PrevScripts, tx.PrevInputValues)
+}
+
 func (w *Wallet) findEligibleOutputs(dbtx walletdb.ReadTx,
 	keyScope *waddrmgr.KeyScope, account uint32, minconf int32,
 	bs *waddrmgr.BlockStamp) ([]wtxmgr.Credit, error) {
@@ -256,6 +354,12 @@ func (w *Wallet) findEligibleOutputs(dbtx walletdb.ReadTx,
 	if err != nil {
 		return nil, err
 	}
+	taints, err := fetchTaints(dbtx.ReadBucket(walletNamespaceKey))
+	if err != nil {
+		return nil, err
+	}
+
+	policy := w.MinConfPolicy()
 
 	// TODO: Eventually all of these filters (except perhaps output locking)
 	// should be handled by the call to UnspentOutputs (or similar).
@@ -279,10 +383,15 @@ func (w *Wallet) findEligibleOutputs(dbtx walletdb.ReadTx,
 			}
 		}
 
-		// Locked unspent outputs are skipped.
+		// Locked and tainted unspent outputs are skipped.
 		if w.LockedOutpoint(output.OutPoint) {
 			continue
 		}
+		if taints.tainted(output.OutPoint, output.PkScript,
+			w.chainParams) {
+
+			continue
+		}
 
 		// Only include the output if it is associated with the passed
 		// account.
@@ -299,6 +408,12 @@ func (w *Wallet) findEligibleOutputs(dbtx walletdb.ReadTx,
 			continue
 		}
 
+		// Witness script outputs, such as those of vaults, are only
+		// spent by the transactions of their scripts.
+		if txscript.IsPayToWitnessScriptHash(output.PkScript) {
+			continue
+		}
+
 		scopedMgr, addrAcct, err := w.Manager.AddrAccount(addrmgrNs, addrs[0])
 		if err != nil {
 			continue
@@ -309,6 +424,17 @@ func (w *Wallet) findEligibleOutputs(dbtx walletdb.ReadTx,
 		if addrAcct != account {
 			continue
 		}
+
+		// The confirmations the policy requires depend on the
+		// transaction of the output, which is only looked up for the
+		// outputs passing the other filters.
+		required, err := w.requiredConfs(txmgrNs, &policy, output)
+		if err != nil {
+			return nil, err
+		}
+		if !confirmed(required, output.Height, bs.Height) {
+			continue
+		}
 		eligible = append(eligible, *output)
 	}
 	return eligible, nil
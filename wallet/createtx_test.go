@@ -299,3 +299,138 @@ func TestTxToOutputsRandom(t *testing.T) {
 
 	require.True(t, isRandom)
 }
+
+// TestTxToOutputsChangePolicy checks that the change matches the type of the
+// payment outputs and is split into two outputs when the change policy asks
+// for it.
+func TestTxToOutputsChangePolicy(t *testing.T) {
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	addr, err := w.CurrentAddress(0, waddrmgr.KeyScopeBIP0049)
+	require.NoError(t, err)
+	p2shAddr, err := txscript.PayToAddrScript(addr)
+	require.NoError(t, err)
+
+	incomingTx := &wire.MsgTx{
+		TxIn:  []*wire.TxIn{{}},
+		TxOut: []*wire.TxOut{wire.NewTxOut(1000000, p2shAddr)},
+	}
+	addUtxo(t, w, incomingTx)
+
+	txOuts := []*wire.TxOut{wire.NewTxOut(100000, p2shAddr)}
+
+	// By default the change is paid to a single bech32 address.
+	tx, err := w.txToOutputs(
+		txOuts, nil, 0, 1, 1000, CoinSelectionLargest, true,
+	)
+	require.NoError(t, err)
+	require.Len(t, tx.Tx.TxOut, 2)
+	change := tx.Tx.TxOut[tx.ChangeIndex]
+	require.True(t, txscript.IsPayToWitnessPubKeyHash(change.PkScript))
+
+	w.SetChangePolicy(ChangePolicy{
+		MatchOutputType: true,
+		SplitChange:     true,
+	})
+	tx, err = w.txToOutputs(
+		txOuts, nil, 0, 1, 1000, CoinSelectionLargest, true,
+	)
+	require.NoError(t, err)
+	require.Len(t, tx.Tx.TxOut, 3)
+
+	var totalOut int64
+	for _, out := range tx.Tx.TxOut {
+		totalOut += out.Value
+
+		// Both change outputs pay to P2SH addresses, as the payment.
+		require.True(t, txscript.IsPayToScriptHash(out.PkScript))
+	}
+	require.True(t, txscript.IsPayToScriptHash(
+		tx.Tx.TxOut[tx.ChangeIndex].PkScript))
+	require.Less(t, totalOut, int64(1000000))
+}
+
+// TestTxToOutputsClaimAccount checks that claims are funded from the claim
+// account, with only the coins it received from the wallet's own
+// transactions.
+func TestTxToOutputsClaimAccount(t *testing.T) {
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	acct, err := w.NextAccount(waddrmgr.KeyScopeBIP0084, "publishing")
+	require.NoError(t, err)
+
+	pkScript := func(account uint32) []byte {
+		addr, err := w.NewAddress(account, waddrmgr.KeyScopeBIP0084)
+		require.NoError(t, err)
+		script, err := txscript.PayToAddrScript(addr)
+		require.NoError(t, err)
+		return script
+	}
+
+	// The default account and the claim account are both paid from
+	// outside the wallet, and the default account then transfers coins
+	// to the claim account.
+	incomingTx := &wire.MsgTx{
+		TxIn: []*wire.TxIn{{}},
+		TxOut: []*wire.TxOut{
+			wire.NewTxOut(2000000, pkScript(0)),
+			wire.NewTxOut(2000000, pkScript(0)),
+			wire.NewTxOut(3000000, pkScript(acct)),
+		},
+	}
+	addUtxo(t, w, incomingTx)
+	transferTx := &wire.MsgTx{
+		TxIn: []*wire.TxIn{wire.NewTxIn(
+			&wire.OutPoint{Hash: incomingTx.TxHash(), Index: 0},
+			nil, nil,
+		)},
+		TxOut: []*wire.TxOut{wire.NewTxOut(1000000, pkScript(acct))},
+	}
+	addUtxo(t, w, transferTx)
+
+	claimScript, err := txscript.ClaimNameScript("name", "value")
+	require.NoError(t, err)
+	txOuts := []*wire.TxOut{
+		wire.NewTxOut(100000, append(claimScript, pkScript(0)...)),
+	}
+
+	// Without a claim account, the claim is funded from the requested
+	// account.
+	tx, err := w.txToOutputs(
+		txOuts, nil, 0, 1, 1000, CoinSelectionLargest, true,
+	)
+	require.NoError(t, err)
+	require.Len(t, tx.Tx.TxIn, 1)
+	require.Equal(t, incomingTx.TxHash(),
+		tx.Tx.TxIn[0].PreviousOutPoint.Hash)
+
+	// With a claim account, only the transferred coins are spent,
+	// although the account holds a larger output, and the change is
+	// paid back to the claim account.
+	w.SetClaimAccount("publishing")
+	tx, err = w.txToOutputs(
+		txOuts, nil, 0, 1, 1000, CoinSelectionLargest, false,
+	)
+	require.NoError(t, err)
+	require.Len(t, tx.Tx.TxIn, 1)
+	require.Equal(t, transferTx.TxHash(),
+		tx.Tx.TxIn[0].PreviousOutPoint.Hash)
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(
+		tx.Tx.TxOut[tx.ChangeIndex].PkScript, w.chainParams,
+	)
+	require.NoError(t, err)
+	changeAccount, err := w.AccountOfAddress(addrs[0])
+	require.NoError(t, err)
+	require.Equal(t, acct, changeAccount)
+
+	// Other transactions are still funded from the requested account.
+	tx, err = w.txToOutputs(
+		[]*wire.TxOut{wire.NewTxOut(100000, pkScript(0))}, nil, 0, 1,
+		1000, CoinSelectionLargest, true,
+	)
+	require.NoError(t, err)
+	require.Equal(t, incomingTx.TxHash(),
+		tx.Tx.TxIn[0].PreviousOutPoint.Hash)
+}
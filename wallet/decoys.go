@@ -0,0 +1,218 @@
+package wallet
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/lbryio/lbcd/chaincfg"
+	"github.com/lbryio/lbcd/txscript"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcutil/hdkeychain"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/walletdb"
+)
+
+// DecoyBranch is the branch of the account keys on which decoy addresses are
+// derived.  The wallet only watches the external and internal branches, so
+// payments to decoys are never tracked nor part of its balance.  The branch
+// spells "DECY" in ASCII.
+const DecoyBranch uint32 = 0x44454359
+
+var (
+	// bucketDecoys is the name of the sub bucket of the wallet namespace
+	// that maps a key scope and account to the index of its next decoy
+	// address, and holds the bucket of the decoy addresses.
+	bucketDecoys = []byte("decoys")
+
+	// bucketDecoyAddrs is the name of the sub bucket of the decoys bucket
+	// that maps each encoded decoy address to its derivation path.
+	bucketDecoyAddrs = []byte("addrs")
+)
+
+// ErrDecoyAddress is returned when a decoy address is used where an address
+// receiving payments for the wallet is expected.
+var ErrDecoyAddress = errors.New("address is a decoy whose payments are not " +
+	"tracked by the wallet")
+
+// DecoyAddress is an address derived on the decoy branch of an account.
+type DecoyAddress struct {
+	Address btcutil.Address
+	Index   uint32
+
+	// Path is the derivation path of the address from the master key,
+	// such as m/44'/140'/0'/1145389913/0.
+	Path string
+}
+
+// NewDecoyAddresses derives n addresses on the decoy branch of an account, for
+// use as decoys or test sinks.  The addresses are of the type of the key scope
+// and are never watched, so that nothing paid to them is part of the wallet
+// balance, although they can be recovered from the seed.  Each call derives
+// addresses which were not returned before.
+func (w *Wallet) NewDecoyAddresses(account uint32, scope waddrmgr.KeyScope,
+	n uint32) ([]DecoyAddress, error) {
+
+	manager, err := w.Manager.FetchScopedKeyManager(scope)
+	if err != nil {
+		return nil, err
+	}
+	addrType := manager.AddrSchema().ExternalAddrType
+
+	var addrs []DecoyAddress
+	err = walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
+		props, err := manager.AccountProperties(addrmgrNs, account)
+		if err != nil {
+			return err
+		}
+		if props.AccountPubKey == nil {
+			return fmt.Errorf("account %q has no extended key to "+
+				"derive decoys from", props.AccountName)
+		}
+		branchKey, err := props.AccountPubKey.Derive(DecoyBranch)
+		if err != nil {
+			return err
+		}
+
+		ns := tx.ReadWriteBucket(walletNamespaceKey)
+		decoys, err := ns.CreateBucketIfNotExists(bucketDecoys)
+		if err != nil {
+			return err
+		}
+		decoyAddrs, err := decoys.CreateBucketIfNotExists(
+			bucketDecoyAddrs)
+		if err != nil {
+			return err
+		}
+		k := decoyIndexKey(scope, account)
+		var index uint32
+		if v := decoys.Get(k); len(v) == 4 {
+			index = binary.BigEndian.Uint32(v)
+		}
+
+		for uint32(len(addrs)) < n {
+			if index >= hdkeychain.HardenedKeyStart {
+				return errors.New("decoy addresses exhausted")
+			}
+			key, err := branchKey.Derive(index)
+			if errors.Is(err, hdkeychain.ErrInvalidChild) {
+				index++
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			addr, err := decoyAddress(key, addrType, w.chainParams)
+			if err != nil {
+				return err
+			}
+			path := waddrmgr.DerivationPath{
+				InternalAccount:      account,
+				Account:              props.AccountPubKey.ChildIndex(),
+				Branch:               DecoyBranch,
+				Index:                index,
+				MasterKeyFingerprint: props.MasterKeyFingerprint,
+			}
+			decoy := DecoyAddress{
+				Address: addr,
+				Index:   index,
+				Path:    decoyPath(scope, path),
+			}
+			err = decoyAddrs.Put([]byte(addr.EncodeAddress()),
+				[]byte(decoy.Path))
+			if err != nil {
+				return err
+			}
+			addrs = append(addrs, decoy)
+			index++
+		}
+
+		var v [4]byte
+		binary.BigEndian.PutUint32(v[:], index)
+		return decoys.Put(k, v[:])
+	})
+	if err != nil {
+		return nil, err
+	}
+	return addrs, nil
+}
+
+// DecoyPath returns the derivation path of a decoy address, and false if the
+// address is not a decoy derived by the wallet.
+func (w *Wallet) DecoyPath(addr btcutil.Address) (string, bool, error) {
+	var path string
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		decoys := tx.ReadBucket(walletNamespaceKey).NestedReadBucket(
+			bucketDecoys)
+		if decoys == nil {
+			return nil
+		}
+		decoyAddrs := decoys.NestedReadBucket(bucketDecoyAddrs)
+		if decoyAddrs == nil {
+			return nil
+		}
+		path = string(decoyAddrs.Get([]byte(addr.EncodeAddress())))
+		return nil
+	})
+	return path, path != "", err
+}
+
+// decoyIndexKey returns the key of the next decoy index of an account:
+//
+//	[0:4]  key scope purpose (4 bytes)
+//	[4:8]  key scope coin type (4 bytes)
+//	[8:12] account (4 bytes)
+func decoyIndexKey(scope waddrmgr.KeyScope, account uint32) []byte {
+	k := make([]byte, 12)
+	binary.BigEndian.PutUint32(k[0:4], scope.Purpose)
+	binary.BigEndian.PutUint32(k[4:8], scope.Coin)
+	binary.BigEndian.PutUint32(k[8:12], account)
+	return k
+}
+
+// decoyPath formats the derivation path of a decoy address from the master
+// key.
+func decoyPath(scope waddrmgr.KeyScope, path waddrmgr.DerivationPath) string {
+	account := fmt.Sprint(path.Account)
+	if path.Account >= hdkeychain.HardenedKeyStart {
+		account = fmt.Sprintf("%d'",
+			path.Account-hdkeychain.HardenedKeyStart)
+	}
+	return fmt.Sprintf("m/%d'/%d'/%s/%d/%d", scope.Purpose, scope.Coin,
+		account, path.Branch, path.Index)
+}
+
+// decoyAddress returns the address of a decoy key of the given type.
+func decoyAddress(key *hdkeychain.ExtendedKey, addrType waddrmgr.AddressType,
+	chainParams *chaincfg.Params) (btcutil.Address, error) {
+
+	pubKey, err := key.ECPubKey()
+	if err != nil {
+		return nil, err
+	}
+	pkHash := btcutil.Hash160(pubKey.SerializeCompressed())
+
+	switch addrType {
+	case waddrmgr.PubKeyHash:
+		return btcutil.NewAddressPubKeyHash(pkHash, chainParams)
+
+	case waddrmgr.WitnessPubKey:
+		return btcutil.NewAddressWitnessPubKeyHash(pkHash, chainParams)
+
+	case waddrmgr.NestedWitnessPubKey:
+		witAddr, err := btcutil.NewAddressWitnessPubKeyHash(
+			pkHash, chainParams,
+		)
+		if err != nil {
+			return nil, err
+		}
+		witnessProgram, err := txscript.PayToAddrScript(witAddr)
+		if err != nil {
+			return nil, err
+		}
+		return btcutil.NewAddressScriptHash(witnessProgram, chainParams)
+	}
+	return nil, fmt.Errorf("unsupported address type %v for decoys",
+		addrType)
+}
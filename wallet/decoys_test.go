@@ -0,0 +1,48 @@
+package wallet
+
+import (
+	"testing"
+
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDecoyAddresses checks that decoy addresses are derived on their own
+// branch without ever being tracked by the address manager, and that they are
+// recognized as decoys.
+func TestDecoyAddresses(t *testing.T) {
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	first, err := w.NewDecoyAddresses(0, waddrmgr.KeyScopeBIP0084, 2)
+	require.NoError(t, err)
+	second, err := w.NewDecoyAddresses(0, waddrmgr.KeyScopeBIP0084, 1)
+	require.NoError(t, err)
+
+	decoys := append(first, second...)
+	require.Len(t, decoys, 3)
+	seen := make(map[string]bool)
+	for i, decoy := range decoys {
+		require.Equal(t, uint32(i), decoy.Index)
+		require.IsType(t, &btcutil.AddressWitnessPubKeyHash{},
+			decoy.Address)
+		require.False(t, seen[decoy.Address.EncodeAddress()])
+		seen[decoy.Address.EncodeAddress()] = true
+
+		_, err := w.AddressInfo(decoy.Address)
+		require.True(t, waddrmgr.IsError(err, waddrmgr.ErrAddressNotFound))
+
+		path, ok, err := w.DecoyPath(decoy.Address)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, decoy.Path, path)
+	}
+	require.Equal(t, "m/84'/140'/0'/1145389913/2", decoys[2].Path)
+
+	addr, err := w.NewAddress(0, waddrmgr.KeyScopeBIP0084)
+	require.NoError(t, err)
+	_, ok, err := w.DecoyPath(addr)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
@@ -0,0 +1,210 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcutil/hdkeychain"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/walletdb"
+)
+
+const (
+	// descriptorInputCharset is the character set of output script
+	// descriptors, ordered as required by their checksum.
+	descriptorInputCharset = "0123456789()[],'/*abcdefgh@:$%{}" +
+		"IJKLMNOPQRSTUVWXYZ&+-.;<=>?!^_|~" +
+		"ijklmnopqrstuvwxyzABCDEFGH`#\"\\ "
+
+	// descriptorChecksumCharset is the character set of the checksums of
+	// output script descriptors.
+	descriptorChecksumCharset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+)
+
+// AccountDescriptor is the output script descriptor of a branch of an HD
+// account, such as "wpkh([d34db33f/84'/140'/0']xpub.../0/*)#checksum", from
+// which descriptor-aware wallets derive the addresses of the account without
+// its private keys.
+type AccountDescriptor struct {
+	Descriptor string
+	Scope      waddrmgr.KeyScope
+	Account    uint32
+	Name       string
+	Internal   bool
+
+	// Next is the index of the next address of the branch to be handed
+	// out.
+	Next uint32
+}
+
+// AccountDescriptors returns the descriptors of the external and internal
+// branches of every HD account of every key scope, with checksums.  The keys
+// are extended public keys with the standard version of the network, and the
+// key origin is the fingerprint of the master key followed by the hardened
+// derivation path of the account.
+func (w *Wallet) AccountDescriptors() ([]AccountDescriptor, error) {
+	var descs []AccountDescriptor
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
+		rootKey, err := w.Manager.RootPubKey(addrmgrNs)
+		if err != nil {
+			return err
+		}
+		rootPubKey, err := rootKey.ECPubKey()
+		if err != nil {
+			return err
+		}
+		fingerprint := hex.EncodeToString(
+			btcutil.Hash160(rootPubKey.SerializeCompressed())[:4],
+		)
+
+		for _, scopedMgr := range w.Manager.ActiveScopedKeyManagers() {
+			scope := scopedMgr.Scope()
+			last, err := scopedMgr.LastAccount(addrmgrNs)
+			if err != nil {
+				return err
+			}
+			for account := uint32(0); account <= last; account++ {
+				props, err := scopedMgr.AccountProperties(
+					addrmgrNs, account,
+				)
+				if err != nil {
+					return err
+				}
+				schema := scopedMgr.AddrSchema()
+				if props.AddrSchema != nil {
+					schema = *props.AddrSchema
+				}
+				key, err := props.AccountPubKey.CloneWithVersion(
+					w.chainParams.HDPublicKeyID[:],
+				)
+				if err != nil {
+					return err
+				}
+				origin := fmt.Sprintf("[%s/%d'/%d'/%d']", fingerprint,
+					scope.Purpose, scope.Coin,
+					key.ChildIndex()-hdkeychain.HardenedKeyStart)
+
+				branches := []struct {
+					internal bool
+					addrType waddrmgr.AddressType
+					next     uint32
+				}{
+					{false, schema.ExternalAddrType,
+						props.ExternalKeyCount},
+					{true, schema.InternalAddrType,
+						props.InternalKeyCount},
+				}
+				for i, b := range branches {
+					desc, err := accountDescriptor(b.addrType,
+						fmt.Sprintf("%s%s/%d/*", origin,
+							key, i))
+					if err != nil {
+						return fmt.Errorf("account %q: %w",
+							props.AccountName, err)
+					}
+					descs = append(descs, AccountDescriptor{
+						Descriptor: desc,
+						Scope:      scope,
+						Account:    account,
+						Name:       props.AccountName,
+						Internal:   b.internal,
+						Next:       b.next,
+					})
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(descs, func(i, j int) bool {
+		a, b := &descs[i].Scope, &descs[j].Scope
+		if a.Purpose != b.Purpose {
+			return a.Purpose < b.Purpose
+		}
+		return a.Coin < b.Coin
+	})
+	return descs, nil
+}
+
+// accountDescriptor returns the descriptor, with its checksum, of the scripts
+// of an address type paying to the keys of a key expression.
+func accountDescriptor(addrType waddrmgr.AddressType, key string) (string,
+	error) {
+
+	var desc string
+	switch addrType {
+	case waddrmgr.PubKeyHash:
+		desc = "pkh(" + key + ")"
+	case waddrmgr.NestedWitnessPubKey:
+		desc = "sh(wpkh(" + key + "))"
+	case waddrmgr.WitnessPubKey:
+		desc = "wpkh(" + key + ")"
+	default:
+		return "", fmt.Errorf("no descriptor for address type %v",
+			addrType)
+	}
+	return desc + "#" + DescriptorChecksum(desc), nil
+}
+
+// DescriptorChecksum returns the checksum of an output script descriptor, as
+// defined by BIP0380, or an empty string when the descriptor has characters
+// outside of the descriptor character set.
+func DescriptorChecksum(desc string) string {
+	c := uint64(1)
+	cls, clsCount := uint64(0), 0
+	for _, ch := range desc {
+		pos := strings.IndexRune(descriptorInputCharset, ch)
+		if pos < 0 {
+			return ""
+		}
+		c = descriptorPolyMod(c, uint64(pos&31))
+		cls = cls*3 + uint64(pos>>5)
+		clsCount++
+		if clsCount == 3 {
+			c = descriptorPolyMod(c, cls)
+			cls, clsCount = 0, 0
+		}
+	}
+	if clsCount > 0 {
+		c = descriptorPolyMod(c, cls)
+	}
+	for i := 0; i < 8; i++ {
+		c = descriptorPolyMod(c, 0)
+	}
+	c ^= 1
+
+	checksum := make([]byte, 8)
+	for i := range checksum {
+		checksum[i] = descriptorChecksumCharset[(c>>(5*(7-i)))&31]
+	}
+	return string(checksum)
+}
+
+// descriptorPolyMod updates the checksum c of a descriptor with the value val.
+func descriptorPolyMod(c, val uint64) uint64 {
+	c0 := c >> 35
+	c = ((c & 0x7ffffffff) << 5) ^ val
+	if c0&1 != 0 {
+		c ^= 0xf5dee51989
+	}
+	if c0&2 != 0 {
+		c ^= 0xa9fdca3312
+	}
+	if c0&4 != 0 {
+		c ^= 0x1bab10e32d
+	}
+	if c0&8 != 0 {
+		c ^= 0x3706b1677a
+	}
+	if c0&16 != 0 {
+		c ^= 0x644d626ffd
+	}
+	return c
+}
@@ -0,0 +1,64 @@
+package wallet
+
+import (
+	"strings"
+	"testing"
+
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcutil/hdkeychain"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDescriptorChecksum checks the checksum of the BIP0380 test vector.
+func TestDescriptorChecksum(t *testing.T) {
+	require.Equal(t, "89f8spxm", DescriptorChecksum("raw(deadbeef)"))
+	require.Empty(t, DescriptorChecksum("raw(deadbeef)é"))
+}
+
+// TestAccountDescriptors checks that the descriptors of the accounts derive
+// the addresses of the wallet.
+func TestAccountDescriptors(t *testing.T) {
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	addr, err := w.NewAddress(0, waddrmgr.KeyScopeBIP0084)
+	require.NoError(t, err)
+
+	descs, err := w.AccountDescriptors()
+	require.NoError(t, err)
+
+	var desc *AccountDescriptor
+	for i := range descs {
+		d := &descs[i]
+		require.True(t, strings.HasSuffix(d.Descriptor,
+			"#"+DescriptorChecksum(strings.Split(d.Descriptor, "#")[0])))
+		if d.Scope == waddrmgr.KeyScopeBIP0084 && d.Account == 0 &&
+			!d.Internal {
+
+			desc = d
+		}
+	}
+	require.NotNil(t, desc)
+	require.Equal(t, "default", desc.Name)
+	require.Equal(t, uint32(1), desc.Next)
+	require.True(t, strings.HasPrefix(desc.Descriptor, "wpkh(["))
+	require.Contains(t, desc.Descriptor, "/84'/140'/0']tpub")
+
+	// Derive the first address from the key of the descriptor.
+	s := desc.Descriptor[strings.Index(desc.Descriptor, "]")+1:]
+	s = s[:strings.Index(s, "/0/*)")]
+	key, err := hdkeychain.NewKeyFromString(s)
+	require.NoError(t, err)
+	key, err = key.Derive(0)
+	require.NoError(t, err)
+	key, err = key.Derive(0)
+	require.NoError(t, err)
+	pubKey, err := key.ECPubKey()
+	require.NoError(t, err)
+	derived, err := btcutil.NewAddressWitnessPubKeyHash(
+		btcutil.Hash160(pubKey.SerializeCompressed()), w.chainParams,
+	)
+	require.NoError(t, err)
+	require.Equal(t, addr.EncodeAddress(), derived.EncodeAddress())
+}
@@ -0,0 +1,337 @@
+package wallet
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/wallet/txrules"
+	"github.com/lbryio/lbcwallet/walletdb"
+	"github.com/lbryio/lbcwallet/wtxmgr"
+)
+
+// DiagnoseConfTarget is the number of blocks an unmined transaction is
+// expected to confirm within, for which DiagnoseTransaction asks the backend
+// to estimate a fee rate.
+const DiagnoseConfTarget = 6
+
+// maxRBFSequence is the highest input sequence number signaling that a
+// transaction may be replaced by one paying a higher fee.
+const maxRBFSequence = 0xfffffffd
+
+// The actions DiagnoseTransaction suggests to get a transaction confirmed.
+const (
+	// TxActionWait waits for the transaction to be mined, as nothing
+	// keeps it from being so.
+	TxActionWait = "wait"
+
+	// TxActionRebroadcast sends the transaction, or the unmined parent it
+	// spends from, to the network again.
+	TxActionRebroadcast = "rebroadcast"
+
+	// TxActionBumpFee replaces the transaction with one paying a higher
+	// fee, which its inputs signal they allow.
+	TxActionBumpFee = "bumpfee"
+
+	// TxActionCPFP spends an output of the transaction paid to the wallet
+	// with a fee high enough for both transactions to be mined (child
+	// pays for parent).
+	TxActionCPFP = "cpfp"
+
+	// TxActionAbandon removes the transaction from the wallet, so that
+	// the outputs it spends may be spent again.
+	TxActionAbandon = "abandon"
+)
+
+// TxBackend is what DiagnoseTransaction asks the chain backend about the
+// mempool and the fee rates of the network.
+type TxBackend interface {
+	// MempoolFee returns the fee of a transaction in the mempool, and
+	// false if the transaction is not in the mempool.
+	MempoolFee(hash *chainhash.Hash) (btcutil.Amount, bool, error)
+
+	// OutputUnspent reports whether an output is unspent in the chain or
+	// in the mempool.
+	OutputUnspent(op *wire.OutPoint) (bool, error)
+
+	// EstimateFeeRate returns the fee rate per kvB for a transaction to
+	// be mined within a number of blocks, or zero when the backend has no
+	// estimate.
+	EstimateFeeRate(blocks int32) (btcutil.Amount, error)
+}
+
+// TxDiagnosis explains why a wallet transaction is not confirming.
+type TxDiagnosis struct {
+	Hash chainhash.Hash
+
+	// Confirmations is the number of confirmations of a mined
+	// transaction, which is not diagnosed further.
+	Confirmations int32
+
+	Received time.Time
+	VSize    int
+
+	// Fee is the fee paid by the transaction and FeeRate its fee per
+	// kvB, both -1 when unknown, as for a transaction spending outputs of
+	// others which is not in the mempool.
+	Fee     btcutil.Amount
+	FeeRate btcutil.Amount
+
+	// EstimatedFeeRate is the fee rate per kvB estimated by the backend
+	// for a transaction to be mined within DiagnoseConfTarget blocks, or
+	// zero when it has no estimate.
+	EstimatedFeeRate btcutil.Amount
+
+	InMempool bool
+
+	// BroadcastAt is the time the transaction is broadcast when it waits
+	// for the delay of the broadcast policy, and zero otherwise.
+	BroadcastAt time.Time
+
+	// UnminedParents are the unmined wallet transactions whose outputs
+	// the transaction spends.
+	UnminedParents []chainhash.Hash
+
+	// MissingInputs are the outputs spent by a transaction which is not
+	// in the mempool that are in neither the chain nor the mempool.
+	MissingInputs []wire.OutPoint
+
+	// Conflicts are the other unmined wallet transactions spending an
+	// output the transaction spends.
+	Conflicts []chainhash.Hash
+
+	// Reasons explain why the transaction is not confirming, and Actions
+	// are the TxAction values suggested to get it confirmed.
+	Reasons []string
+	Actions []string
+}
+
+// addAction suggests an action, unless it already is.
+func (d *TxDiagnosis) addAction(action string) {
+	for _, a := range d.Actions {
+		if a == action {
+			return
+		}
+	}
+	d.Actions = append(d.Actions, action)
+}
+
+// DiagnoseTransaction explains why an unmined wallet transaction is not
+// confirming, which is for a fee rate too low for the current estimates of
+// the backend, a missing parent, a conflict with another transaction or a
+// transaction that was not broadcast, and suggests what to do about it.
+// ErrTxNotFound is returned when the wallet has no such transaction.
+func (w *Wallet) DiagnoseTransaction(txHash *chainhash.Hash,
+	backend TxBackend) (*TxDiagnosis, error) {
+
+	var (
+		details *wtxmgr.TxDetails
+		unmined []*wire.MsgTx
+		parents = make(map[chainhash.Hash]*wtxmgr.TxDetails)
+	)
+	syncBlock := w.Manager.SyncedTo()
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		ns := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		var err error
+		details, err = w.TxStore.TxDetails(ns, txHash)
+		if err != nil || details == nil || details.Block.Height != -1 {
+			return err
+		}
+		unmined, err = w.TxStore.UnminedTxs(ns)
+		if err != nil {
+			return err
+		}
+		for _, in := range details.MsgTx.TxIn {
+			prev := in.PreviousOutPoint.Hash
+			if _, ok := parents[prev]; ok {
+				continue
+			}
+			parents[prev], err = w.TxStore.TxDetails(ns, &prev)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if details == nil {
+		return nil, ErrTxNotFound
+	}
+
+	tx := &details.MsgTx
+	d := &TxDiagnosis{
+		Hash:     details.Hash,
+		Received: details.Received,
+		VSize: (tx.SerializeSizeStripped()*3 + tx.SerializeSize() +
+			3) / 4,
+		Fee:     -1,
+		FeeRate: -1,
+	}
+	if details.Block.Height != -1 {
+		d.Confirmations = confirms(details.Block.Height,
+			syncBlock.Height)
+		return d, nil
+	}
+
+	if len(details.Debits) == len(tx.TxIn) {
+		d.Fee = 0
+		for _, debit := range details.Debits {
+			d.Fee += debit.Amount
+		}
+		for _, out := range tx.TxOut {
+			d.Fee -= btcutil.Amount(out.Value)
+		}
+	}
+	mempoolFee, inMempool, err := backend.MempoolFee(txHash)
+	if err != nil {
+		return nil, err
+	}
+	d.InMempool = inMempool
+	if inMempool {
+		d.Fee = mempoolFee
+	}
+	if d.Fee >= 0 && d.VSize > 0 {
+		d.FeeRate = d.Fee * 1000 / btcutil.Amount(d.VSize)
+	}
+	d.EstimatedFeeRate, err = backend.EstimateFeeRate(DiagnoseConfTarget)
+	if err != nil {
+		return nil, err
+	}
+	d.BroadcastAt, _ = w.delayedBroadcast(txHash)
+
+	spends := make(map[wire.OutPoint]struct{}, len(tx.TxIn))
+	for _, in := range tx.TxIn {
+		spends[in.PreviousOutPoint] = struct{}{}
+		parent := parents[in.PreviousOutPoint.Hash]
+		if parent != nil && parent.Block.Height == -1 &&
+			!containsHash(d.UnminedParents, parent.Hash) {
+
+			d.UnminedParents = append(d.UnminedParents, parent.Hash)
+		}
+	}
+	conflicting := make(map[wire.OutPoint]struct{})
+	for _, other := range unmined {
+		otherHash := other.TxHash()
+		if otherHash == *txHash {
+			continue
+		}
+		for _, in := range other.TxIn {
+			if _, ok := spends[in.PreviousOutPoint]; !ok {
+				continue
+			}
+			conflicting[in.PreviousOutPoint] = struct{}{}
+			if !containsHash(d.Conflicts, otherHash) {
+				d.Conflicts = append(d.Conflicts, otherHash)
+			}
+		}
+	}
+	if !inMempool {
+		for _, in := range tx.TxIn {
+			op := in.PreviousOutPoint
+			if _, ok := conflicting[op]; ok {
+				continue
+			}
+			unspent, err := backend.OutputUnspent(&op)
+			if err != nil {
+				return nil, err
+			}
+			if !unspent {
+				d.MissingInputs = append(d.MissingInputs, op)
+			}
+		}
+	}
+
+	d.diagnose(details, parents)
+	return d, nil
+}
+
+// diagnose fills the reasons and actions of the diagnosis of an unmined
+// transaction from what is known of it.
+func (d *TxDiagnosis) diagnose(details *wtxmgr.TxDetails,
+	parents map[chainhash.Hash]*wtxmgr.TxDetails) {
+
+	for _, hash := range d.Conflicts {
+		d.Reasons = append(d.Reasons, fmt.Sprintf("conflicts with "+
+			"wallet transaction %v spending the same outputs",
+			hash))
+	}
+
+	relayFee := txrules.DefaultRelayFeePerKb
+	switch {
+	// The transaction that made it to the mempool is the one to keep.
+	case !d.InMempool && len(d.Conflicts) != 0:
+		d.addAction(TxActionAbandon)
+
+	case !d.InMempool && !d.BroadcastAt.IsZero():
+		d.Reasons = append(d.Reasons, fmt.Sprintf("broadcast is "+
+			"delayed by the broadcast policy until %v",
+			d.BroadcastAt.Format(time.RFC3339)))
+		d.addAction(TxActionWait)
+
+	case !d.InMempool && len(d.MissingInputs) != 0:
+		for _, op := range d.MissingInputs {
+			parent := parents[op.Hash]
+			if parent != nil && parent.Block.Height == -1 {
+				d.Reasons = append(d.Reasons, fmt.Sprintf(
+					"unconfirmed parent transaction %v is "+
+						"not in the mempool", op.Hash))
+				d.addAction(TxActionRebroadcast)
+				continue
+			}
+			d.Reasons = append(d.Reasons, fmt.Sprintf("spent "+
+				"output %v is missing or already spent", op))
+			d.addAction(TxActionAbandon)
+		}
+
+	case !d.InMempool && d.FeeRate >= 0 && d.FeeRate < relayFee:
+		d.Reasons = append(d.Reasons, fmt.Sprintf("fee rate %d "+
+			"sat/kvB is below the minimum relay fee rate %d "+
+			"sat/kvB", d.FeeRate, relayFee))
+		d.addAction(TxActionAbandon)
+
+	case !d.InMempool:
+		d.Reasons = append(d.Reasons, "transaction is not in the "+
+			"mempool of the backend")
+		d.addAction(TxActionRebroadcast)
+
+	case d.EstimatedFeeRate > 0 && d.FeeRate < d.EstimatedFeeRate:
+		d.Reasons = append(d.Reasons, fmt.Sprintf("fee rate %d "+
+			"sat/kvB is below the estimate of %d sat/kvB to "+
+			"confirm within %d blocks", d.FeeRate,
+			d.EstimatedFeeRate, DiagnoseConfTarget))
+		for _, in := range details.MsgTx.TxIn {
+			if in.Sequence <= maxRBFSequence {
+				d.addAction(TxActionBumpFee)
+			}
+		}
+		for _, credit := range details.Credits {
+			if !credit.Spent {
+				d.addAction(TxActionCPFP)
+			}
+		}
+	}
+
+	if d.InMempool {
+		for _, hash := range d.UnminedParents {
+			d.Reasons = append(d.Reasons, fmt.Sprintf("waits for "+
+				"unconfirmed parent transaction %v", hash))
+		}
+	}
+	if len(d.Actions) == 0 {
+		d.addAction(TxActionWait)
+	}
+}
+
+// containsHash reports whether a hash is in a slice.
+func containsHash(hashes []chainhash.Hash, hash chainhash.Hash) bool {
+	for i := range hashes {
+		if hashes[i] == hash {
+			return true
+		}
+	}
+	return false
+}
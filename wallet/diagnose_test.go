@@ -0,0 +1,128 @@
+package wallet
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/walletdb"
+	"github.com/lbryio/lbcwallet/wtxmgr"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTxBackend is a TxBackend with a fixed mempool, set of spent outputs
+// and fee estimate.
+type fakeTxBackend struct {
+	mempool  map[chainhash.Hash]btcutil.Amount
+	spent    map[wire.OutPoint]bool
+	estimate btcutil.Amount
+}
+
+func (b *fakeTxBackend) MempoolFee(hash *chainhash.Hash) (btcutil.Amount,
+	bool, error) {
+
+	fee, ok := b.mempool[*hash]
+	return fee, ok, nil
+}
+
+func (b *fakeTxBackend) OutputUnspent(op *wire.OutPoint) (bool, error) {
+	return !b.spent[*op], nil
+}
+
+func (b *fakeTxBackend) EstimateFeeRate(int32) (btcutil.Amount, error) {
+	return b.estimate, nil
+}
+
+// TestDiagnoseTransaction checks the reasons and actions given for an unmined
+// transaction in the situations keeping it from confirming.
+func TestDiagnoseTransaction(t *testing.T) {
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	addr, err := w.CurrentAddress(0, waddrmgr.KeyScopeBIP0084)
+	require.NoError(t, err)
+	pkScript, err := txscript.PayToAddrScript(addr)
+	require.NoError(t, err)
+	incomingTx := &wire.MsgTx{
+		TxIn:  []*wire.TxIn{{}},
+		TxOut: []*wire.TxOut{wire.NewTxOut(1000000, pkScript)},
+	}
+	addUtxo(t, w, incomingTx)
+
+	backend := &fakeTxBackend{
+		mempool: make(map[chainhash.Hash]btcutil.Amount),
+		spent:   make(map[wire.OutPoint]bool),
+	}
+	unknown := chainhash.Hash{1}
+	_, err = w.DiagnoseTransaction(&unknown, backend)
+	require.ErrorIs(t, err, ErrTxNotFound)
+
+	// Mined transactions are not diagnosed.
+	incomingHash := incomingTx.TxHash()
+	d, err := w.DiagnoseTransaction(&incomingHash, backend)
+	require.NoError(t, err)
+	require.Empty(t, d.Reasons)
+	require.Empty(t, d.Actions)
+
+	tx, err := w.SendOutputs(context.Background(),
+		[]*wire.TxOut{wire.NewTxOut(100000, pkScript)}, nil, 0, 1,
+		1000, CoinSelectionLargest, "",
+	)
+	require.NoError(t, err)
+	txHash := tx.TxHash()
+
+	// A transaction missing from the mempool is rebroadcast.
+	d, err = w.DiagnoseTransaction(&txHash, backend)
+	require.NoError(t, err)
+	require.False(t, d.InMempool)
+	require.Positive(t, int64(d.Fee))
+	require.Equal(t, d.Fee*1000/btcutil.Amount(d.VSize), d.FeeRate)
+	require.Equal(t, []string{TxActionRebroadcast}, d.Actions)
+
+	// A transaction paying less than the estimate is accelerated by
+	// spending its change, as its inputs do not signal replacement.
+	backend.mempool[txHash] = d.Fee
+	backend.estimate = 5 * d.FeeRate
+	d, err = w.DiagnoseTransaction(&txHash, backend)
+	require.NoError(t, err)
+	require.True(t, d.InMempool)
+	require.Len(t, d.Reasons, 1)
+	require.Equal(t, []string{TxActionCPFP}, d.Actions)
+
+	// Nothing is to be done when the fee rate is high enough.
+	backend.estimate = d.FeeRate
+	d, err = w.DiagnoseTransaction(&txHash, backend)
+	require.NoError(t, err)
+	require.Empty(t, d.Reasons)
+	require.Equal(t, []string{TxActionWait}, d.Actions)
+
+	// A transaction spending a missing output is abandoned.
+	delete(backend.mempool, txHash)
+	op := tx.TxIn[0].PreviousOutPoint
+	backend.spent[op] = true
+	d, err = w.DiagnoseTransaction(&txHash, backend)
+	require.NoError(t, err)
+	require.Equal(t, []wire.OutPoint{op}, d.MissingInputs)
+	require.Equal(t, []string{TxActionAbandon}, d.Actions)
+
+	// So is a transaction conflicting with another wallet transaction.
+	conflict := tx.Copy()
+	conflict.TxOut[0].Value--
+	rec, err := wtxmgr.NewTxRecordFromMsgTx(conflict, time.Now())
+	require.NoError(t, err)
+	err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		ns := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+		return w.TxStore.InsertTx(ns, rec, nil)
+	})
+	require.NoError(t, err)
+	d, err = w.DiagnoseTransaction(&txHash, backend)
+	require.NoError(t, err)
+	require.Equal(t, []chainhash.Hash{conflict.TxHash()}, d.Conflicts)
+	require.Empty(t, d.MissingInputs)
+	require.Equal(t, []string{TxActionAbandon}, d.Actions)
+}
@@ -0,0 +1,232 @@
+package wallet
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/lbryio/lbcwallet/internal/diskspace"
+)
+
+// ErrLowDiskSpace is returned by the operations writing to the database on
+// request, such as the derivation of addresses or the creation of
+// transactions, while the free space of its volume is below the floor of the
+// disk policy.  The sync of the wallet goes on, so that the database is not
+// left behind the chain.
+var ErrLowDiskSpace = errors.New("free disk space is below the floor, " +
+	"refusing to write to the wallet database")
+
+// DiskPolicy controls the monitoring of the free space of the volume holding
+// the wallet database, which is corrupted when the volume fills in the middle
+// of a commit.
+type DiskPolicy struct {
+	// Path is the path of the database file.  The volume is not
+	// monitored when empty.
+	Path string
+
+	// Interval is the time between the periodic checks of the free
+	// space, which is also checked before each write refused below
+	// Floor.  There are no periodic checks when zero.
+	Interval time.Duration
+
+	// Warn is the free space in bytes below which warnings are logged
+	// and Notify is called.
+	Warn uint64
+
+	// Floor is the free space in bytes below which writes to the
+	// database on request are refused with ErrLowDiskSpace.  No writes
+	// are refused when zero.
+	Floor uint64
+
+	// Notify, when set, is called with the status of the volume each
+	// time its free space crosses Warn or Floor.
+	Notify func(status DiskStatus)
+}
+
+// DiskStatus describes the free space of the volume holding the wallet
+// database, as of the last check.
+type DiskStatus struct {
+	// Enabled is true when the volume is monitored.
+	Enabled bool
+	Path    string
+
+	// LastCheck is the time of the last check, whose error is LastError
+	// if it failed.
+	LastCheck time.Time
+	LastError string
+
+	// DBSize is the size in bytes of the database file, and Growth how
+	// much it grew since the previous check.
+	DBSize int64
+	Growth int64
+
+	// Free is the free space in bytes of the volume.
+	Free uint64
+
+	// Warning is true when the free space is below the warning level of
+	// the policy, and Low when it is below its floor.
+	Warning bool
+	Low     bool
+}
+
+// SetDiskPolicy sets the policy for the monitoring of the free disk space, and
+// checks the volume at once.
+func (w *Wallet) SetDiskPolicy(policy DiskPolicy) {
+	w.diskMtx.Lock()
+	w.diskPolicy = policy
+	w.diskStatus = DiskStatus{}
+	w.diskMtx.Unlock()
+
+	select {
+	case w.diskPolicyChanged <- struct{}{}:
+	default:
+	}
+}
+
+// DiskPolicy returns the policy for the monitoring of the free disk space set
+// by SetDiskPolicy.
+func (w *Wallet) DiskPolicy() DiskPolicy {
+	w.diskMtx.Lock()
+	defer w.diskMtx.Unlock()
+	return w.diskPolicy
+}
+
+// DiskStatus returns the status of the volume holding the wallet database.
+func (w *Wallet) DiskStatus() DiskStatus {
+	w.diskMtx.Lock()
+	defer w.diskMtx.Unlock()
+
+	status := w.diskStatus
+	status.Enabled = w.diskPolicy.Path != ""
+	status.Path = w.diskPolicy.Path
+	return status
+}
+
+// CheckDiskSpace checks the free space of the volume holding the wallet
+// database at once, and returns its status.
+func (w *Wallet) CheckDiskSpace() DiskStatus {
+	w.diskMtx.Lock()
+	policy := w.diskPolicy
+	if policy.Path == "" {
+		w.diskMtx.Unlock()
+		return w.DiskStatus()
+	}
+
+	prev := w.diskStatus
+	status := DiskStatus{
+		Enabled:   true,
+		Path:      policy.Path,
+		LastCheck: time.Now(),
+		DBSize:    prev.DBSize,
+		Free:      prev.Free,
+		Warning:   prev.Warning,
+		Low:       prev.Low,
+	}
+	free, err := diskspace.Free(filepath.Dir(policy.Path))
+	if err == nil {
+		var fi os.FileInfo
+		fi, err = os.Stat(policy.Path)
+		if err == nil {
+			if !prev.LastCheck.IsZero() {
+				status.Growth = fi.Size() - prev.DBSize
+			}
+			status.DBSize = fi.Size()
+			status.Free = free
+			status.Warning = free < policy.Warn
+			status.Low = free < policy.Floor
+		}
+	}
+	if err != nil {
+		status.LastError = err.Error()
+	}
+	w.diskStatus = status
+	w.diskMtx.Unlock()
+
+	if err != nil {
+		// Only the first failure is logged, as the volume is checked
+		// before each write.
+		if prev.LastError != status.LastError {
+			log.Errorf("Unable to check the free disk space of %s: "+
+				"%v", policy.Path, err)
+		}
+		return status
+	}
+	if status.Warning == prev.Warning && status.Low == prev.Low {
+		if status.Growth != 0 {
+			log.Debugf("Wallet database %s grew %d bytes to %d "+
+				"bytes, with %d MiB free", policy.Path,
+				status.Growth, status.DBSize, status.Free>>20)
+		}
+		return status
+	}
+
+	switch {
+	case status.Low:
+		log.Warnf("Free disk space of %s is %d MiB, below the floor of "+
+			"%d MiB: refusing to derive addresses, create "+
+			"transactions and import keys until space is freed",
+			policy.Path, status.Free>>20, policy.Floor>>20)
+	case status.Warning:
+		log.Warnf("Free disk space of %s is %d MiB, below %d MiB",
+			policy.Path, status.Free>>20, policy.Warn>>20)
+	default:
+		log.Infof("Free disk space of %s is back to %d MiB",
+			policy.Path, status.Free>>20)
+	}
+	if policy.Notify != nil {
+		policy.Notify(status)
+	}
+	return status
+}
+
+// requireDiskSpace returns ErrLowDiskSpace when the free space of the volume
+// holding the database is below the floor of the disk policy.  It is called by
+// the operations writing to the database on request, but not by the sync of
+// the wallet.
+func (w *Wallet) requireDiskSpace() error {
+	policy := w.DiskPolicy()
+	if policy.Path == "" || policy.Floor == 0 {
+		return nil
+	}
+	if w.CheckDiskSpace().Low {
+		return ErrLowDiskSpace
+	}
+	return nil
+}
+
+// diskMonitor checks the free disk space periodically until the wallet is
+// stopped.
+func (w *Wallet) diskMonitor() {
+	defer w.wg.Done()
+
+	quit := w.quitChan()
+	for {
+		var timer *time.Timer
+		policy := w.DiskPolicy()
+		if policy.Path != "" {
+			w.CheckDiskSpace()
+			if policy.Interval > 0 {
+				timer = time.NewTimer(policy.Interval)
+			}
+		}
+
+		var timeout <-chan time.Time
+		if timer != nil {
+			timeout = timer.C
+		}
+		select {
+		case <-timeout:
+		case <-w.diskPolicyChanged:
+		case <-quit:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		select {
+		case <-quit:
+			return
+		default:
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package wallet
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lbryio/lbcwallet/internal/diskspace"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDiskSpace checks that writes on request are refused while the free disk
+// space is below the floor, and that crossing it is notified.
+func TestDiskSpace(t *testing.T) {
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	path := filepath.Join(t.TempDir(), WalletDBName)
+	require.NoError(t, os.WriteFile(path, make([]byte, 100), 0600))
+	if _, err := diskspace.Free(path); err == diskspace.ErrUnsupported {
+		t.Skip(err)
+	}
+
+	// Without a policy, nothing is monitored or refused.
+	require.False(t, w.DiskStatus().Enabled)
+	_, err := w.NewAddress(0, waddrmgr.KeyScopeBIP0044)
+	require.NoError(t, err)
+
+	notified := make(chan DiskStatus, 10)
+	notify := func(status DiskStatus) {
+		notified <- status
+	}
+	nextNotification := func() DiskStatus {
+		select {
+		case status := <-notified:
+			return status
+		case <-time.After(10 * time.Second):
+			t.Fatal("no notification")
+			return DiskStatus{}
+		}
+	}
+
+	w.SetDiskPolicy(DiskPolicy{
+		Path:   path,
+		Warn:   math.MaxUint64,
+		Floor:  math.MaxUint64,
+		Notify: notify,
+	})
+	_, err = w.NewAddress(0, waddrmgr.KeyScopeBIP0044)
+	require.ErrorIs(t, err, ErrLowDiskSpace)
+	_, err = w.NextAccount(waddrmgr.KeyScopeBIP0044, "refused")
+	require.ErrorIs(t, err, ErrLowDiskSpace)
+
+	status := nextNotification()
+	require.True(t, status.Warning)
+	require.True(t, status.Low)
+	status = w.DiskStatus()
+	require.True(t, status.Enabled)
+	require.True(t, status.Low)
+	require.NotZero(t, status.Free)
+	require.EqualValues(t, 100, status.DBSize)
+	require.Empty(t, status.LastError)
+
+	// Writes resume once the free space is above the floor.
+	w.SetDiskPolicy(DiskPolicy{
+		Path:   path,
+		Warn:   math.MaxUint64,
+		Floor:  1,
+		Notify: notify,
+	})
+	_, err = w.NewAddress(0, waddrmgr.KeyScopeBIP0044)
+	require.NoError(t, err)
+
+	status = nextNotification()
+	require.True(t, status.Warning)
+	require.False(t, status.Low)
+
+	// Checks without crossing a level are not notified.
+	require.NoError(t, os.WriteFile(path, make([]byte, 150), 0600))
+	status = w.CheckDiskSpace()
+	require.EqualValues(t, 150, status.DBSize)
+	select {
+	case status := <-notified:
+		t.Fatalf("notified without crossing a level: %+v", status)
+	default:
+	}
+}
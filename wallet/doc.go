@@ -6,6 +6,37 @@
 Package wallet provides ...
 TODO: Flesh out this section
 
-Overview
+# Overview
+
+# Locking
+
+The wallet has no lock of its own covering all of its state.  Each subsystem
+is guarded separately:
+
+  - The database allows a single write transaction at a time, and read
+    transactions alongside it.  The transaction store has no other lock.
+  - The address manager and each of its scoped managers have a mutex guarding
+    their caches and key material, and each address one guarding its private
+    key.
+  - Signing holds an unlock of the wallet with holdUnlock, so that the private
+    keys stay available without blocking the other subsystems.
+  - The policies and the other fields of the wallet are guarded by a mutex of
+    their own.
+
+The locks are taken in this order, and a lock is never held while taking one
+which precedes it:
+
+ 1. The held unlock of the walletLocker goroutine.
+ 2. A database transaction.
+ 3. The mutex of the address manager.
+ 4. The mutex of a scoped manager.
+ 5. The mutex of an address.
+
+The mutexes of the wallet fields are held on their own.
+
+A database write transaction is never held during a round trip to the chain
+server, or while signing: a rescan of the chain writes each step in a
+transaction of its own, so that new addresses are derived and balances read
+while it runs.
 */
 package wallet
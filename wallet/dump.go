@@ -0,0 +1,587 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/lbryio/lbcd/btcec"
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/walletdb"
+	"github.com/lbryio/lbcwallet/wtxmgr"
+)
+
+const (
+	// DumpFormat is the format field of every wallet dump.
+	DumpFormat = "lbcwallet-dump"
+
+	// DumpVersion is the version of the dumps written by DumpWallet.
+	// Dumps of a later version are rejected, as they may hold data which
+	// would be lost on import.
+	DumpVersion = 1
+)
+
+var (
+	// ErrDumpFormat is returned when importing a file which is not a
+	// wallet dump, or a dump of an unsupported version.
+	ErrDumpFormat = errors.New("not a supported lbcwallet dump")
+
+	// ErrDumpMasterKey is returned when importing a dump of another
+	// wallet, whose keys can only be restored by creating a new wallet
+	// from the dump.
+	ErrDumpMasterKey = errors.New("the dump is of a wallet with another " +
+		"master key; create a new wallet from it instead")
+)
+
+// Dump is a portable export of a wallet, written by the dumpwallet RPC as
+// JSON.  It holds everything needed to restore the wallet with any later
+// version of lbcwallet, on any machine:
+//
+//   - format and version identify the dump, and network is the name of the
+//     network of the wallet, such as "mainnet".
+//   - created is the time of the dump, and birthday the time of the wallet's
+//     earliest key, in seconds since the Unix epoch.  birthdayblock is the
+//     block the wallet starts scanning from, when known.
+//   - masterkey is the extended private root key of the wallet, which every
+//     account key derives from.
+//   - accounts lists the HD accounts of every key scope, identified by their
+//     BIP0043 purpose and coin type, with the number of external and internal
+//     addresses derived.
+//   - importedkeys lists the imported private keys as WIF, watch-only public
+//     keys and P2SH redeem scripts, with their imported-key account.
+//   - labels lists the transaction labels.
+//   - channels lists the channel claims of the wallet, for reference only, as
+//     they are found again by the rescan which follows an import.
+//
+// The dump holds the private keys of the wallet in the clear and must be
+// protected accordingly.
+type Dump struct {
+	Format        string            `json:"format"`
+	Version       int               `json:"version"`
+	Network       string            `json:"network"`
+	Created       int64             `json:"created"`
+	Birthday      int64             `json:"birthday"`
+	BirthdayBlock *DumpBlock        `json:"birthdayblock,omitempty"`
+	MasterKey     string            `json:"masterkey"`
+	Accounts      []DumpAccount     `json:"accounts"`
+	ImportedKeys  []DumpImportedKey `json:"importedkeys"`
+	Labels        []DumpLabel       `json:"labels"`
+	Channels      []DumpChannel     `json:"channels"`
+}
+
+// DumpBlock identifies a block of a wallet dump.
+type DumpBlock struct {
+	Hash   string `json:"hash"`
+	Height int32  `json:"height"`
+}
+
+// DumpAccount is an HD account of a wallet dump.  XPub is the extended public
+// key of the account, which must derive from the master key of the dump.
+type DumpAccount struct {
+	Purpose      uint32 `json:"purpose"`
+	Coin         uint32 `json:"coin"`
+	Number       uint32 `json:"number"`
+	Name         string `json:"name"`
+	XPub         string `json:"xpub"`
+	ExternalKeys uint32 `json:"externalkeys"`
+	InternalKeys uint32 `json:"internalkeys"`
+}
+
+// DumpImportedKey is an imported key or script of a wallet dump, in the key
+// scope identified by Purpose and Coin.  Exactly one of PrivKey, a WIF encoded
+// private key, PubKey, a hex encoded watch-only public key, and Script, a hex
+// encoded P2SH redeem script, is set.  Account is the name of the imported-key
+// account of the address.
+type DumpImportedKey struct {
+	Purpose uint32 `json:"purpose"`
+	Coin    uint32 `json:"coin"`
+	Address string `json:"address"`
+	PrivKey string `json:"privkey,omitempty"`
+	PubKey  string `json:"pubkey,omitempty"`
+	Script  string `json:"script,omitempty"`
+	Account string `json:"account"`
+}
+
+// DumpLabel is a transaction label of a wallet dump.
+type DumpLabel struct {
+	TxID  string `json:"txid"`
+	Label string `json:"label"`
+}
+
+// DumpChannel is a channel claim of a wallet dump.
+type DumpChannel struct {
+	ClaimID string `json:"claimid"`
+	Name    string `json:"name"`
+	Account string `json:"account"`
+	TxID    string `json:"txid"`
+	Vout    uint32 `json:"vout"`
+}
+
+// DumpWallet returns a portable export of the wallet.  The wallet must be
+// unlocked.
+func (w *Wallet) DumpWallet() (*Dump, error) {
+	d := &Dump{
+		Format:   DumpFormat,
+		Version:  DumpVersion,
+		Network:  w.chainParams.Name,
+		Created:  time.Now().Unix(),
+		Birthday: w.Manager.Birthday().Unix(),
+	}
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
+		rootKey, err := w.Manager.RootPrivKey(addrmgrNs)
+		if err != nil {
+			return err
+		}
+		d.MasterKey = rootKey.String()
+
+		bs, _, err := w.Manager.BirthdayBlock(addrmgrNs)
+		if err == nil {
+			d.BirthdayBlock = &DumpBlock{
+				Hash:   bs.Hash.String(),
+				Height: bs.Height,
+			}
+		}
+
+		accts := tx.ReadBucket(walletNamespaceKey).NestedReadBucket(
+			bucketImportedAccounts)
+		for _, scopedMgr := range w.Manager.ActiveScopedKeyManagers() {
+			scope := scopedMgr.Scope()
+			last, err := scopedMgr.LastAccount(addrmgrNs)
+			if err != nil {
+				return err
+			}
+			for account := uint32(0); account <= last; account++ {
+				props, err := scopedMgr.AccountProperties(
+					addrmgrNs, account,
+				)
+				if err != nil {
+					return err
+				}
+				d.Accounts = append(d.Accounts, DumpAccount{
+					Purpose:      scope.Purpose,
+					Coin:         scope.Coin,
+					Number:       account,
+					Name:         props.AccountName,
+					XPub:         props.AccountPubKey.String(),
+					ExternalKeys: props.ExternalKeyCount,
+					InternalKeys: props.InternalKeyCount,
+				})
+			}
+
+			// The keys are exported once the addresses are listed,
+			// as the scoped manager is locked while listing them.
+			var imported []waddrmgr.ManagedAddress
+			err = scopedMgr.ForEachAccountAddress(addrmgrNs,
+				waddrmgr.ImportedAddrAccount,
+				func(maddr waddrmgr.ManagedAddress) error {
+					imported = append(imported, maddr)
+					return nil
+				})
+			if err != nil {
+				return err
+			}
+			for _, maddr := range imported {
+				key, ok, err := dumpImportedKey(maddr)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					continue
+				}
+				key.Purpose = scope.Purpose
+				key.Coin = scope.Coin
+				key.Account = waddrmgr.ImportedAddrAccountName
+				if accts != nil {
+					v := accts.Get([]byte(key.Address))
+					if v != nil {
+						key.Account = string(v)
+					}
+				}
+				d.ImportedKeys = append(d.ImportedKeys, key)
+			}
+		}
+
+		txmgrNs := tx.ReadBucket(wtxmgrNamespaceKey)
+		labels := txmgrNs.NestedReadBucket(bucketTxLabels)
+		if labels == nil {
+			return nil
+		}
+		return labels.ForEach(func(k, v []byte) error {
+			txid, err := chainhash.NewHash(k)
+			if err != nil {
+				return err
+			}
+			label, err := wtxmgr.DeserializeLabel(v)
+			if err != nil {
+				return err
+			}
+			d.Labels = append(d.Labels, DumpLabel{
+				TxID:  txid.String(),
+				Label: label,
+			})
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(d.Accounts, func(i, j int) bool {
+		a, b := &d.Accounts[i], &d.Accounts[j]
+		if a.Purpose != b.Purpose {
+			return a.Purpose < b.Purpose
+		}
+		if a.Coin != b.Coin {
+			return a.Coin < b.Coin
+		}
+		return a.Number < b.Number
+	})
+	sort.Slice(d.ImportedKeys, func(i, j int) bool {
+		a, b := &d.ImportedKeys[i], &d.ImportedKeys[j]
+		if a.Purpose != b.Purpose {
+			return a.Purpose < b.Purpose
+		}
+		if a.Coin != b.Coin {
+			return a.Coin < b.Coin
+		}
+		return a.Address < b.Address
+	})
+	sort.Slice(d.Labels, func(i, j int) bool {
+		return d.Labels[i].TxID < d.Labels[j].TxID
+	})
+
+	claims, err := w.AccountClaims()
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range claims {
+		if c.Kind != "channel" || c.Op == "support" {
+			continue
+		}
+		account, err := w.AccountName(c.Account)
+		if err != nil {
+			return nil, err
+		}
+		d.Channels = append(d.Channels, DumpChannel{
+			ClaimID: c.ClaimID,
+			Name:    c.Name,
+			Account: account,
+			TxID:    c.OutPoint.Hash.String(),
+			Vout:    c.OutPoint.Index,
+		})
+	}
+	return d, nil
+}
+
+// dumpImportedKey returns the dump of an imported address, and false for
+// addresses which can not be restored from a dump, such as witness scripts.
+func dumpImportedKey(maddr waddrmgr.ManagedAddress) (DumpImportedKey, bool,
+	error) {
+
+	key := DumpImportedKey{Address: maddr.Address().EncodeAddress()}
+	switch a := maddr.(type) {
+	case waddrmgr.ManagedPubKeyAddress:
+		wif, err := a.ExportPrivKey()
+		switch {
+		case err == nil:
+			key.PrivKey = wif.String()
+
+		// Watch-only keys have no private key to decrypt.
+		case waddrmgr.IsError(err, waddrmgr.ErrCrypto):
+			key.PubKey = hex.EncodeToString(
+				a.PubKey().SerializeCompressed(),
+			)
+
+		default:
+			return key, false, err
+		}
+		return key, true, nil
+
+	case waddrmgr.ManagedScriptAddress:
+		if a.AddrType() != waddrmgr.Script {
+			log.Warnf("Not dumping imported %v address %v",
+				a.AddrType(), key.Address)
+			return key, false, nil
+		}
+		script, err := a.Script()
+		if err != nil {
+			return key, false, err
+		}
+		key.Script = hex.EncodeToString(script)
+		return key, true, nil
+	}
+	return key, false, nil
+}
+
+// ImportWallet restores a dump into the wallet, which must have the master key
+// of the dump and be unlocked: the missing accounts are created and the
+// accounts renamed as in the dump, their addresses are derived up to those of
+// the dump, and the imported keys and labels are added.  The birthday is moved
+// back to the one of the dump when earlier.  When rescan is true, the
+// blockchain is then rescanned from the birthday block of the dump for the
+// transactions of every address, without waiting for the rescan to complete.
+func (w *Wallet) ImportWallet(d *Dump, rescan bool) error {
+	if d.Format != DumpFormat || d.Version < 1 || d.Version > DumpVersion {
+		return ErrDumpFormat
+	}
+	if d.Network != w.chainParams.Name {
+		return fmt.Errorf("the dump is of a %s wallet", d.Network)
+	}
+
+	var bs *waddrmgr.BlockStamp
+	if d.BirthdayBlock != nil {
+		hash, err := chainhash.NewHashFromStr(d.BirthdayBlock.Hash)
+		if err != nil {
+			return fmt.Errorf("invalid birthday block: %w", err)
+		}
+		bs = &waddrmgr.BlockStamp{
+			Hash:      *hash,
+			Height:    d.BirthdayBlock.Height,
+			Timestamp: time.Unix(d.Birthday, 0),
+		}
+	}
+
+	err := walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		addrmgrNs := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		rootKey, err := w.Manager.RootPrivKey(addrmgrNs)
+		if err != nil {
+			return err
+		}
+		if rootKey.String() != d.MasterKey {
+			return ErrDumpMasterKey
+		}
+
+		for i := range d.Accounts {
+			err := w.importDumpAccount(addrmgrNs, &d.Accounts[i])
+			if err != nil {
+				return err
+			}
+		}
+
+		ns := tx.ReadWriteBucket(walletNamespaceKey)
+		for i := range d.ImportedKeys {
+			err := w.importDumpKey(addrmgrNs, ns, &d.ImportedKeys[i],
+				bs)
+			if err != nil {
+				return err
+			}
+		}
+
+		txmgrNs := tx.ReadWriteBucket(wtxmgrNamespaceKey)
+		for _, l := range d.Labels {
+			hash, err := chainhash.NewHashFromStr(l.TxID)
+			if err != nil {
+				return fmt.Errorf("invalid label txid: %w", err)
+			}
+			err = w.TxStore.PutTxLabel(txmgrNs, *hash, l.Label)
+			if err != nil {
+				return err
+			}
+		}
+
+		birthday := time.Unix(d.Birthday, 0)
+		if birthday.Before(w.Manager.Birthday()) {
+			err := w.Manager.SetBirthday(addrmgrNs, birthday)
+			if err != nil {
+				return err
+			}
+		}
+		if bs == nil {
+			return nil
+		}
+		birthdayBlock, _, err := w.Manager.BirthdayBlock(addrmgrNs)
+		if err == nil && birthdayBlock.Height <= bs.Height {
+			return nil
+		}
+
+		// The birthday block is checked against the chain at the next
+		// start, as for imported private keys.
+		return w.Manager.SetBirthdayBlock(addrmgrNs, *bs, false)
+	})
+	if err != nil || !rescan {
+		return err
+	}
+
+	var addrs []btcutil.Address
+	err = walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
+		return w.Manager.ForEachActiveAddress(addrmgrNs,
+			func(addr btcutil.Address) error {
+				addrs = append(addrs, addr)
+				return nil
+			})
+	})
+	if err != nil {
+		return err
+	}
+	if bs == nil {
+		bs = &waddrmgr.BlockStamp{
+			Hash:      *w.chainParams.GenesisHash,
+			Height:    0,
+			Timestamp: w.chainParams.GenesisBlock.Header.Timestamp,
+		}
+	}
+	_ = w.SubmitRescan(&RescanJob{
+		Addrs:      addrs,
+		BlockStamp: *bs,
+	})
+	return nil
+}
+
+// importDumpAccount creates or renames an account of a dump, and derives its
+// addresses up to those of the dump.
+func (w *Wallet) importDumpAccount(ns walletdb.ReadWriteBucket,
+	a *DumpAccount) error {
+
+	scope := waddrmgr.KeyScope{Purpose: a.Purpose, Coin: a.Coin}
+	scopedMgr, err := w.dumpScopedKeyManager(ns, scope)
+	if err != nil {
+		return err
+	}
+
+	last, err := scopedMgr.LastAccount(ns)
+	if err != nil {
+		return err
+	}
+	switch {
+	case a.Number == last+1:
+		if _, err := scopedMgr.NewAccount(ns, a.Name); err != nil {
+			return err
+		}
+	case a.Number > last+1:
+		return fmt.Errorf("account %d of scope %v follows missing "+
+			"accounts", a.Number, scope)
+	default:
+		name, err := scopedMgr.AccountName(ns, a.Number)
+		if err != nil {
+			return err
+		}
+		if name != a.Name {
+			err := scopedMgr.RenameAccount(ns, a.Number, a.Name)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	props, err := scopedMgr.AccountProperties(ns, a.Number)
+	if err != nil {
+		return err
+	}
+	if props.AccountPubKey.String() != a.XPub {
+		return fmt.Errorf("account %q of scope %v does not derive "+
+			"from the master key", a.Name, scope)
+	}
+	if a.ExternalKeys > 0 {
+		err := scopedMgr.ExtendAddresses(ns, a.Number,
+			waddrmgr.ExternalBranch, a.ExternalKeys-1)
+		if err != nil {
+			return err
+		}
+	}
+	if a.InternalKeys > 0 {
+		err := scopedMgr.ExtendAddresses(ns, a.Number,
+			waddrmgr.InternalBranch, a.InternalKeys-1)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importDumpKey imports a key or script of a dump, and assigns it to its
+// imported-key account.
+func (w *Wallet) importDumpKey(addrmgrNs, ns walletdb.ReadWriteBucket,
+	k *DumpImportedKey, bs *waddrmgr.BlockStamp) error {
+
+	scope := waddrmgr.KeyScope{Purpose: k.Purpose, Coin: k.Coin}
+	scopedMgr, err := w.dumpScopedKeyManager(addrmgrNs, scope)
+	if err != nil {
+		return err
+	}
+
+	var maddr waddrmgr.ManagedAddress
+	switch {
+	case k.PrivKey != "":
+		wif, err := btcutil.DecodeWIF(k.PrivKey)
+		if err != nil {
+			return fmt.Errorf("invalid key of %s: %w", k.Address, err)
+		}
+		maddr, err = scopedMgr.ImportPrivateKey(addrmgrNs, wif, bs)
+		if err != nil && !waddrmgr.IsError(err,
+			waddrmgr.ErrDuplicateAddress) {
+
+			return err
+		}
+
+	case k.PubKey != "":
+		b, err := hex.DecodeString(k.PubKey)
+		if err != nil {
+			return fmt.Errorf("invalid key of %s: %w", k.Address, err)
+		}
+		pubKey, err := btcec.ParsePubKey(b, btcec.S256())
+		if err != nil {
+			return fmt.Errorf("invalid key of %s: %w", k.Address, err)
+		}
+		maddr, err = scopedMgr.ImportPublicKey(addrmgrNs, pubKey, bs)
+		if err != nil && !waddrmgr.IsError(err,
+			waddrmgr.ErrDuplicateAddress) {
+
+			return err
+		}
+
+	case k.Script != "":
+		script, err := hex.DecodeString(k.Script)
+		if err != nil {
+			return fmt.Errorf("invalid script of %s: %w", k.Address,
+				err)
+		}
+		maddr, err = scopedMgr.ImportScript(addrmgrNs, script, bs)
+		if err != nil && !waddrmgr.IsError(err,
+			waddrmgr.ErrDuplicateAddress) {
+
+			return err
+		}
+
+	default:
+		return fmt.Errorf("no key or script for %s", k.Address)
+	}
+
+	// Duplicates return no address, but are the address of the dump.
+	if maddr != nil && maddr.Address().EncodeAddress() != k.Address {
+		return fmt.Errorf("the key of %s is for %s", k.Address,
+			maddr.Address().EncodeAddress())
+	}
+
+	if k.Account == "" || k.Account == waddrmgr.ImportedAddrAccountName {
+		return nil
+	}
+	if err := w.validImportedAccountName(addrmgrNs, k.Account); err != nil {
+		return err
+	}
+	accts, err := ns.CreateBucketIfNotExists(bucketImportedAccounts)
+	if err != nil {
+		return err
+	}
+	return accts.Put([]byte(k.Address), []byte(k.Account))
+}
+
+// dumpScopedKeyManager returns the scoped manager of a scope of a dump, which
+// is created with the default address schema of the scope when missing.
+func (w *Wallet) dumpScopedKeyManager(ns walletdb.ReadWriteBucket,
+	scope waddrmgr.KeyScope) (*waddrmgr.ScopedKeyManager, error) {
+
+	scopedMgr, err := w.Manager.FetchScopedKeyManager(scope)
+	if err == nil {
+		return scopedMgr, nil
+	}
+	schema, ok := waddrmgr.ScopeAddrMap[scope]
+	if !ok {
+		return nil, fmt.Errorf("unknown key scope %v", scope)
+	}
+	return w.Manager.NewScopedKeyManager(ns, scope, schema)
+}
@@ -0,0 +1,129 @@
+package wallet
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/lbryio/lbcd/btcec"
+	"github.com/lbryio/lbcd/chaincfg"
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcutil/hdkeychain"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/walletdb"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDumpWallet checks that a dump restores the accounts, imported keys and
+// labels of a wallet into a new wallet created from its master key, and that
+// it is only imported into a wallet with the same master key.
+func TestDumpWallet(t *testing.T) {
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	_, err := w.NextAccount(waddrmgr.KeyScopeBIP0044, "savings")
+	require.NoError(t, err)
+	_, err = w.NewAddress(1, waddrmgr.KeyScopeBIP0044)
+	require.NoError(t, err)
+	_, err = w.NewChangeAddress(0, waddrmgr.KeyScopeBIP0084)
+	require.NoError(t, err)
+
+	err = walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		return w.Manager.SetBirthdayBlock(ns, waddrmgr.BlockStamp{
+			Hash:   chainhash.Hash{2},
+			Height: 100,
+		}, true)
+	})
+	require.NoError(t, err)
+
+	privKey, err := btcec.NewPrivateKey(btcec.S256())
+	require.NoError(t, err)
+	wif, err := btcutil.NewWIF(privKey, w.chainParams, true)
+	require.NoError(t, err)
+	bs := &waddrmgr.BlockStamp{
+		Hash:      chainhash.Hash{3},
+		Height:    50,
+		Timestamp: time.Unix(1600000000, 0),
+	}
+	encoded, err := w.ImportPrivateKey(waddrmgr.KeyScopeBIP0044, wif, bs,
+		false)
+	require.NoError(t, err)
+	addr, err := btcutil.DecodeAddress(encoded, w.chainParams)
+	require.NoError(t, err)
+	require.NoError(t, w.SetImportedAccount(addr, "cold"))
+
+	txid := chainhash.Hash{1}
+	err = walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(wtxmgrNamespaceKey)
+		return w.TxStore.PutTxLabel(ns, txid, "rent")
+	})
+	require.NoError(t, err)
+
+	d, err := w.DumpWallet()
+	require.NoError(t, err)
+	require.Equal(t, DumpFormat, d.Format)
+	require.Equal(t, &DumpBlock{
+		Hash:   chainhash.Hash{3}.String(),
+		Height: 50,
+	}, d.BirthdayBlock)
+	require.Equal(t, []DumpImportedKey{{
+		Purpose: waddrmgr.KeyScopeBIP0044.Purpose,
+		Coin:    waddrmgr.KeyScopeBIP0044.Coin,
+		Address: encoded,
+		PrivKey: wif.String(),
+		Account: "cold",
+	}}, d.ImportedKeys)
+	require.Equal(t, []DumpLabel{{TxID: txid.String(), Label: "rent"}},
+		d.Labels)
+
+	// The dump survives its JSON encoding.
+	b, err := json.Marshal(d)
+	require.NoError(t, err)
+	var decoded Dump
+	require.NoError(t, json.Unmarshal(b, &decoded))
+
+	// Restore the dump into a new wallet created from its master key.
+	rootKey, err := hdkeychain.NewKeyFromString(decoded.MasterKey)
+	require.NoError(t, err)
+	passphrase := []byte("hello world")
+	loader := NewLoader(
+		&chaincfg.TestNet3Params, t.TempDir(), true, defaultDBTimeout,
+		250,
+	)
+	restored, err := loader.CreateNewWalletExtendedKey(passphrase, rootKey,
+		time.Now())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, loader.UnloadWallet())
+	}()
+	restored.chainClient = &mockChainClient{}
+
+	require.Error(t, restored.ImportWallet(&decoded, false))
+	require.NoError(t, restored.Unlock(passphrase, nil))
+	require.NoError(t, restored.ImportWallet(&decoded, false))
+	require.Equal(t, time.Unix(d.Birthday, 0), restored.Manager.Birthday())
+
+	redump, err := restored.DumpWallet()
+	require.NoError(t, err)
+	require.Equal(t, d.Accounts, redump.Accounts)
+	require.Equal(t, d.ImportedKeys, redump.ImportedKeys)
+	require.Equal(t, d.Labels, redump.Labels)
+
+	// Importing the dump again changes nothing.
+	require.NoError(t, restored.ImportWallet(&decoded, false))
+	redump, err = restored.DumpWallet()
+	require.NoError(t, err)
+	require.Equal(t, d.Accounts, redump.Accounts)
+
+	// A wallet with another master key rejects the dump.
+	other, cleanupOther := testWallet(t)
+	defer cleanupOther()
+	require.ErrorIs(t, other.ImportWallet(&decoded, false),
+		ErrDumpMasterKey)
+
+	decoded.Version = DumpVersion + 1
+	require.ErrorIs(t, restored.ImportWallet(&decoded, false),
+		ErrDumpFormat)
+}
@@ -0,0 +1,162 @@
+package wallet
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/walletdb"
+	"github.com/lbryio/lbcwallet/wtxmgr"
+)
+
+// ExportEntry is a single credit or debit of a wallet transaction, as
+// exported for bookkeeping.
+type ExportEntry struct {
+	Time     time.Time
+	TxHash   chainhash.Hash
+	Vout     uint32
+	Category string
+	Account  string
+	Address  string
+
+	// Amount is negative for sends.
+	Amount btcutil.Amount
+
+	// Fee is the fee paid by the wallet for the transaction, as a
+	// negative amount.  It is only set for the first send entry of a
+	// transaction, so that it is not counted more than once.
+	Fee btcutil.Amount
+
+	Label string
+
+	// Comment and CommentTo are the comment the transaction was sent
+	// with, if any.
+	Comment   string
+	CommentTo string
+
+	// ClaimOp is "claim", "support" or "update" when the output is a claim
+	// script, in which case ClaimName and ClaimID are set too.
+	ClaimOp   string
+	ClaimName string
+	ClaimID   string
+
+	// FiatRate is the exchange rate recorded by RecordFiatRates, if any.
+	FiatRate *FiatRate
+}
+
+// ExportTransactions returns the entries of the wallet transactions for an
+// account, or for every account if accountName is "*", received at or after
+// start and before end.  A zero start or end leaves the range open on that
+// side.  The entries are sorted by time.  The export stops with the error of
+// ctx once it is done.
+func (w *Wallet) ExportTransactions(ctx context.Context, accountName string,
+	start, end time.Time) ([]ExportEntry, error) {
+
+	rates, err := w.TxFiatRates()
+	if err != nil {
+		return nil, err
+	}
+	comments, err := w.TxComments()
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []ExportEntry
+	err = walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		txmgrNs := tx.ReadBucket(wtxmgrNamespaceKey)
+		syncBlock := w.Manager.SyncedTo()
+
+		rangeFn := func(details []wtxmgr.TxDetails) (bool, error) {
+			if err := ctx.Err(); err != nil {
+				return false, err
+			}
+
+			for i := range details {
+				detail := &details[i]
+				if !start.IsZero() && detail.Received.Before(start) {
+					continue
+				}
+				if !end.IsZero() && !detail.Received.Before(end) {
+					continue
+				}
+
+				results := listTransactions(accountName, tx,
+					detail, w.Manager, syncBlock.Height,
+					w.chainParams)
+				if len(results) == 0 {
+					continue
+				}
+				label, err := w.TxStore.TxLabel(txmgrNs, detail.Hash)
+				if err != nil {
+					return false, err
+				}
+				var rate *FiatRate
+				if r, ok := rates[detail.Hash]; ok {
+					rate = &r
+				}
+				comment := comments[detail.Hash]
+
+				feeRecorded := false
+				for _, result := range results {
+					amount, err := btcutil.NewAmount(result.Amount)
+					if err != nil {
+						return false, err
+					}
+					entry := ExportEntry{
+						Time:      detail.Received,
+						TxHash:    detail.Hash,
+						Vout:      result.Vout,
+						Category:  result.Category,
+						Account:   result.Account,
+						Address:   result.Address,
+						Amount:    amount,
+						Label:     label,
+						Comment:   comment.Comment,
+						CommentTo: comment.CommentTo,
+						FiatRate:  rate,
+					}
+					if result.Fee != nil && !feeRecorded {
+						entry.Fee, err = btcutil.NewAmount(
+							*result.Fee)
+						if err != nil {
+							return false, err
+						}
+						feeRecorded = true
+					}
+					setClaimOp(&entry, detail)
+					entries = append(entries, entry)
+				}
+			}
+			return false, nil
+		}
+
+		return w.TxStore.RangeTransactions(txmgrNs, 0, -1, rangeFn)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time.Before(entries[j].Time)
+	})
+	return entries, nil
+}
+
+// setClaimOp sets the claim fields of an entry when its output is a claim
+// script.
+func setClaimOp(entry *ExportEntry, detail *wtxmgr.TxDetails) {
+	if int(entry.Vout) >= len(detail.MsgTx.TxOut) {
+		return
+	}
+	op := wire.OutPoint{Hash: detail.Hash, Index: entry.Vout}
+	c, ok := decodeClaimOutput(op, detail.MsgTx.TxOut[entry.Vout].PkScript)
+	if !ok {
+		return
+	}
+	entry.ClaimOp = c.Op
+	entry.ClaimName = c.Name
+	entry.ClaimID = c.ClaimID
+}
@@ -0,0 +1,109 @@
+package wallet
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/walletdb"
+	"github.com/lbryio/lbcwallet/wtxmgr"
+)
+
+// TestExportTransactions ensures the entries of the transactions received in
+// a range are exported with their labels and claim operations.
+func TestExportTransactions(t *testing.T) {
+	t.Parallel()
+
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	addr, err := w.NewAddress(0, waddrmgr.KeyScopeBIP0044)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The script of a claim is followed by OP_TRUE, which is replaced by
+	// the script paying the wallet.
+	claimScript, err := txscript.ClaimNameScript("name", "value")
+	if err != nil {
+		t.Fatal(err)
+	}
+	claimScript = append(claimScript[:len(claimScript)-1], pkScript...)
+
+	addTx := func(received time.Time, script []byte) *wtxmgr.TxRecord {
+		msgTx := wire.NewMsgTx(wire.TxVersion)
+		msgTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{
+			Index: uint32(received.Unix()),
+		}, nil, nil))
+		msgTx.AddTxOut(wire.NewTxOut(1000, script))
+		rec, err := wtxmgr.NewTxRecordFromMsgTx(msgTx, received)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+			return w.addRelevantTx(tx, rec, nil)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rec
+	}
+
+	day := time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC)
+	addTx(day.Add(-time.Hour), pkScript)
+	paid := addTx(day.Add(2*time.Hour), pkScript)
+	claim := addTx(day.Add(time.Hour), claimScript)
+	addTx(day.Add(24*time.Hour), pkScript)
+	if err := w.LabelTransaction(paid.Hash, "paid", false); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := w.ExportTransactions(context.Background(), "*", day,
+		day.Add(24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+
+	// The entries are sorted by time.
+	if entries[0].TxHash != claim.Hash || entries[1].TxHash != paid.Hash {
+		t.Fatalf("unexpected entries %v and %v", entries[0].TxHash,
+			entries[1].TxHash)
+	}
+	if entries[0].ClaimOp != "claim" || entries[0].ClaimName != "name" ||
+		entries[0].ClaimID == "" {
+
+		t.Errorf("unexpected claim %q %q %q", entries[0].ClaimOp,
+			entries[0].ClaimName, entries[0].ClaimID)
+	}
+	if entries[1].Label != "paid" || entries[1].ClaimOp != "" {
+		t.Errorf("unexpected label %q and claim %q", entries[1].Label,
+			entries[1].ClaimOp)
+	}
+	for _, entry := range entries {
+		if entry.Amount != 1000 || entry.Category != "receive" ||
+			entry.Fee != 0 {
+
+			t.Errorf("unexpected entry %+v", entry)
+		}
+	}
+
+	// No account other than the default account has transactions.
+	entries, err = w.ExportTransactions(context.Background(),
+		waddrmgr.ImportedAddrAccountName, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("got %d entries of the imported account", len(entries))
+	}
+}
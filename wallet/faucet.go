@@ -0,0 +1,125 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+)
+
+var (
+	// ErrNoFaucet describes an error where test coins are requested from
+	// a wallet without a faucet.
+	ErrNoFaucet = errors.New("no faucet is configured")
+
+	// ErrFaucetMainNet describes an error where test coins are requested
+	// by a mainnet wallet.
+	ErrFaucetMainNet = errors.New("test coins can not be requested on " +
+		"mainnet")
+)
+
+// Faucet pays test coins to the addresses of a wallet.
+type Faucet interface {
+	// RequestCoins asks the faucet to pay an amount to an address, or
+	// the amount of its choice when zero.  It returns the hash of the
+	// transaction paying the address, or nil when it is unknown.
+	RequestCoins(addr btcutil.Address, amount btcutil.Amount) (
+		*chainhash.Hash, error)
+}
+
+// TestCoinRequest is a request of test coins made to the faucet of a wallet.
+type TestCoinRequest struct {
+	// Address is the new address of the wallet paid by the faucet.
+	Address btcutil.Address
+
+	// Amount is the amount requested, or zero when left to the faucet.
+	Amount btcutil.Amount
+
+	// TxHash is the hash of the transaction paying the address, when
+	// returned by the faucet.
+	TxHash *chainhash.Hash
+
+	// Time is when the coins were requested.
+	Time time.Time
+}
+
+// SetFaucet sets the faucet from which RequestTestCoins requests coins, or
+// disables the requests when nil.
+func (w *Wallet) SetFaucet(f Faucet) {
+	w.faucetMtx.Lock()
+	w.faucet = f
+	w.faucetMtx.Unlock()
+}
+
+// RequestTestCoins asks the faucet of the wallet to pay an amount to a new
+// address of an account, or the amount of its choice when zero, so that test
+// environments can be funded without sending coins by hand.  It is refused on
+// mainnet.  The deposit is tracked like any payment to the wallet: the
+// address is watched by the chain backend, and WaitForTestCoins waits until
+// the payment is received.
+func (w *Wallet) RequestTestCoins(account uint32,
+	amount btcutil.Amount) (*TestCoinRequest, error) {
+
+	if w.chainParams.Net == wire.MainNet {
+		return nil, ErrFaucetMainNet
+	}
+	w.faucetMtx.Lock()
+	f := w.faucet
+	w.faucetMtx.Unlock()
+	if f == nil {
+		return nil, ErrNoFaucet
+	}
+	if amount < 0 {
+		return nil, errors.New("the amount of test coins must not be " +
+			"negative")
+	}
+
+	addr, err := w.NewAddress(account, waddrmgr.KeyScopeBIP0044)
+	if err != nil {
+		return nil, err
+	}
+	req := &TestCoinRequest{Address: addr, Amount: amount, Time: w.now()}
+	req.TxHash, err = f.RequestCoins(addr, amount)
+	if err != nil {
+		return nil, fmt.Errorf("unable to request test coins: %v", err)
+	}
+	log.Infof("Requested test coins from the faucet to %v", addr)
+	return req, nil
+}
+
+// WaitForTestCoins waits until the address of a request of test coins has
+// been paid, confirmed or not, and returns the amount received.  It returns
+// an error if nothing was received within the timeout.
+func (w *Wallet) WaitForTestCoins(req *TestCoinRequest,
+	timeout time.Duration) (btcutil.Amount, error) {
+
+	const pollInterval = 500 * time.Millisecond
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	quit := w.quitChan()
+	for {
+		received, err := w.TotalReceivedForAddr(req.Address, 0)
+		if err != nil {
+			return 0, err
+		}
+		if received > 0 {
+			return received, nil
+		}
+		select {
+		case <-ticker.C:
+		case <-deadline.C:
+			return 0, fmt.Errorf("the faucet did not pay %v "+
+				"within %v", req.Address, timeout)
+		case <-quit:
+			return 0, ErrWalletShuttingDown
+		}
+	}
+}
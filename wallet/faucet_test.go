@@ -0,0 +1,66 @@
+package wallet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lbryio/lbcd/chaincfg"
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/walletdb"
+	"github.com/lbryio/lbcwallet/wtxmgr"
+	"github.com/stretchr/testify/require"
+)
+
+// testFaucet pays the requested addresses with unmined transactions added to
+// a wallet.
+type testFaucet struct {
+	w *Wallet
+}
+
+func (f *testFaucet) RequestCoins(addr btcutil.Address,
+	amount btcutil.Amount) (*chainhash.Hash, error) {
+
+	if amount == 0 {
+		amount = 1e8
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return nil, err
+	}
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 1}, nil, nil))
+	tx.AddTxOut(wire.NewTxOut(int64(amount), pkScript))
+	rec, err := wtxmgr.NewTxRecordFromMsgTx(tx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	err = walletdb.Update(f.w.db, func(dbtx walletdb.ReadWriteTx) error {
+		return f.w.addRelevantTx(dbtx, rec, nil)
+	})
+	return &rec.Hash, err
+}
+
+// TestRequestTestCoins checks that test coins are requested from the faucet
+// of a wallet to a new address, and that the deposit is waited for.
+func TestRequestTestCoins(t *testing.T) {
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	_, err := w.RequestTestCoins(0, 0)
+	require.ErrorIs(t, err, ErrNoFaucet)
+
+	w.SetFaucet(&testFaucet{w: w})
+	req, err := w.RequestTestCoins(0, 5e7)
+	require.NoError(t, err)
+	require.NotNil(t, req.TxHash)
+	received, err := w.WaitForTestCoins(req, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, btcutil.Amount(5e7), received)
+
+	w.chainParams = &chaincfg.MainNetParams
+	_, err = w.RequestTestCoins(0, 0)
+	require.ErrorIs(t, err, ErrFaucetMainNet)
+}
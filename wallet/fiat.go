@@ -0,0 +1,295 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcd/wire"
+	"github.com/lbryio/lbcwallet/walletdb"
+	"github.com/lbryio/lbcwallet/wtxmgr"
+)
+
+// bucketFiatRates is the name of the sub bucket of the wallet namespace that
+// maps transaction hashes to the exchange rate of LBC when the transaction
+// was received.
+var bucketFiatRates = []byte("fiatrates")
+
+// fiatRateRetryInterval is the time after which the rates which could not be
+// fetched are requested again.
+const fiatRateRetryInterval = 5 * time.Minute
+
+// PriceSource provides historical exchange rates of LBC.
+type PriceSource interface {
+	// Rate returns the price of one LBC in the currency at time t.
+	Rate(currency string, t time.Time) (float64, error)
+}
+
+// FiatRate is the exchange rate of LBC recorded for a transaction.
+type FiatRate struct {
+	Currency string
+	Rate     float64
+}
+
+// serializeFiatRate returns the serialization of a rate: the rate as the
+// 8 byte IEEE 754 representation of the float, followed by the currency as a
+// varstring.
+func serializeFiatRate(r *FiatRate) ([]byte, error) {
+	var buf bytes.Buffer
+	var rate [8]byte
+	binary.BigEndian.PutUint64(rate[:], math.Float64bits(r.Rate))
+	buf.Write(rate[:])
+	if err := wire.WriteVarString(&buf, 0, r.Currency); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// deserializeFiatRate decodes a rate serialized by serializeFiatRate.
+func deserializeFiatRate(v []byte) (*FiatRate, error) {
+	if len(v) < 8 {
+		return nil, fmt.Errorf("short fiat rate: %d bytes", len(v))
+	}
+	currency, err := wire.ReadVarString(bytes.NewReader(v[8:]), 0)
+	if err != nil {
+		return nil, err
+	}
+	return &FiatRate{
+		Currency: currency,
+		Rate:     math.Float64frombits(binary.BigEndian.Uint64(v[:8])),
+	}, nil
+}
+
+// TxFiatRates returns the exchange rates recorded for wallet transactions by
+// RecordFiatRates.
+func (w *Wallet) TxFiatRates() (map[chainhash.Hash]FiatRate, error) {
+	rates := make(map[chainhash.Hash]FiatRate)
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		ns := tx.ReadBucket(walletNamespaceKey)
+		bucket := ns.NestedReadBucket(bucketFiatRates)
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			var hash chainhash.Hash
+			if err := hash.SetBytes(k); err != nil {
+				return err
+			}
+			rate, err := deserializeFiatRate(v)
+			if err != nil {
+				return err
+			}
+			rates[hash] = *rate
+			return nil
+		})
+	})
+	return rates, err
+}
+
+// hasFiatRate returns whether a rate is recorded for a transaction.
+func (w *Wallet) hasFiatRate(hash *chainhash.Hash) (bool, error) {
+	var recorded bool
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		ns := tx.ReadBucket(walletNamespaceKey)
+		bucket := ns.NestedReadBucket(bucketFiatRates)
+		recorded = bucket != nil && bucket.Get(hash[:]) != nil
+		return nil
+	})
+	return recorded, err
+}
+
+// recordFiatRate stores the rate of a transaction unless one is already
+// recorded.
+func (w *Wallet) recordFiatRate(hash *chainhash.Hash, rate *FiatRate) error {
+	v, err := serializeFiatRate(rate)
+	if err != nil {
+		return err
+	}
+	return walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(walletNamespaceKey)
+		bucket, err := ns.CreateBucketIfNotExists(bucketFiatRates)
+		if err != nil {
+			return err
+		}
+		if bucket.Get(hash[:]) != nil {
+			return nil
+		}
+		return bucket.Put(hash[:], v)
+	})
+}
+
+// txsWithoutFiatRate returns the time each wallet transaction without a
+// recorded rate was received.
+func (w *Wallet) txsWithoutFiatRate() (map[chainhash.Hash]time.Time, error) {
+	txs := make(map[chainhash.Hash]time.Time)
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		ns := tx.ReadBucket(walletNamespaceKey)
+		rates := ns.NestedReadBucket(bucketFiatRates)
+		txmgrNs := tx.ReadBucket(wtxmgrNamespaceKey)
+		return w.TxStore.RangeTransactions(txmgrNs, 0, -1,
+			func(details []wtxmgr.TxDetails) (bool, error) {
+				for i := range details {
+					hash := details[i].Hash
+					if rates != nil && rates.Get(hash[:]) != nil {
+						continue
+					}
+					txs[hash] = details[i].Received
+				}
+				return false, nil
+			})
+	})
+	return txs, err
+}
+
+// RecordFiatRates records the exchange rate of LBC in currency, as provided
+// by src, for every wallet transaction at the time it was received.  The
+// rates are recorded for new transactions and for the existing transactions
+// without a rate, until the wallet is stopped.  Rates which can not be
+// fetched are requested again later.
+func (w *Wallet) RecordFiatRates(src PriceSource, currency string) {
+	r := &fiatRateRecorder{
+		w:        w,
+		src:      src,
+		currency: currency,
+		pending:  make(map[chainhash.Hash]time.Time),
+		added:    make(chan struct{}, 1),
+	}
+	// Subscribe before the existing transactions are read, so that no
+	// transaction is missed in between.
+	client := w.NtfnServer.TransactionNotifications()
+	w.wg.Add(2)
+	go r.queueTransactions(client)
+	go r.fetchRates()
+}
+
+// fiatRateRecorder fetches the rates of the transactions queued by the
+// transaction notifications of a wallet.  The notifications are read without
+// waiting for the rates to be fetched so that they do not block the wallet.
+type fiatRateRecorder struct {
+	w        *Wallet
+	src      PriceSource
+	currency string
+
+	mtx     sync.Mutex
+	pending map[chainhash.Hash]time.Time
+	added   chan struct{}
+}
+
+func (r *fiatRateRecorder) queue(hash *chainhash.Hash, received time.Time) {
+	r.mtx.Lock()
+	r.pending[*hash] = received
+	r.mtx.Unlock()
+
+	select {
+	case r.added <- struct{}{}:
+	default:
+	}
+}
+
+func (r *fiatRateRecorder) queueTransactions(
+	client TransactionNotificationsClient) {
+
+	defer r.w.wg.Done()
+	defer client.Done()
+	quit := r.w.quitChan()
+	for {
+		select {
+		case n, ok := <-client.C:
+			if !ok {
+				return
+			}
+			for _, block := range n.AttachedBlocks {
+				for _, tx := range block.Transactions {
+					r.queue(tx.Hash, time.Unix(tx.Timestamp, 0))
+				}
+			}
+			for _, tx := range n.UnminedTransactions {
+				r.queue(tx.Hash, time.Unix(tx.Timestamp, 0))
+			}
+		case <-quit:
+			return
+		}
+	}
+}
+
+func (r *fiatRateRecorder) fetchRates() {
+	defer r.w.wg.Done()
+
+	quit := r.w.quitChan()
+	existing, err := r.w.txsWithoutFiatRate()
+	if err != nil {
+		log.Errorf("Unable to find transactions without a fiat rate: %v",
+			err)
+	}
+	for hash, received := range existing {
+		hash := hash
+		r.queue(&hash, received)
+	}
+
+	retry := time.NewTicker(fiatRateRetryInterval)
+	defer retry.Stop()
+	for {
+		r.mtx.Lock()
+		pending := r.pending
+		r.pending = make(map[chainhash.Hash]time.Time)
+		r.mtx.Unlock()
+
+		failed := 0
+		for hash, received := range pending {
+			select {
+			case <-quit:
+				return
+			default:
+			}
+
+			hash := hash
+			err := r.record(&hash, received)
+			if err != nil {
+				log.Debugf("Unable to record the fiat rate of "+
+					"transaction %v: %v", hash, err)
+				r.mtx.Lock()
+				if _, ok := r.pending[hash]; !ok {
+					r.pending[hash] = received
+				}
+				r.mtx.Unlock()
+				failed++
+			}
+		}
+		if failed != 0 {
+			log.Warnf("Unable to record the fiat rate of %d "+
+				"transactions, retrying in %v", failed,
+				fiatRateRetryInterval)
+		}
+
+		select {
+		case <-r.added:
+		case <-retry.C:
+		case <-quit:
+			return
+		}
+	}
+}
+
+// record fetches and stores the rate of a transaction, unless it is already
+// recorded.
+func (r *fiatRateRecorder) record(hash *chainhash.Hash,
+	received time.Time) error {
+
+	recorded, err := r.w.hasFiatRate(hash)
+	if err != nil || recorded {
+		return err
+	}
+
+	rate, err := r.src.Rate(r.currency, received)
+	if err != nil {
+		return err
+	}
+	return r.w.recordFiatRate(hash, &FiatRate{
+		Currency: r.currency,
+		Rate:     rate,
+	})
+}
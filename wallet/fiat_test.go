@@ -0,0 +1,108 @@
+package wallet
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/walletdb"
+	"github.com/lbryio/lbcwallet/wtxmgr"
+)
+
+// testPriceSource returns the unix time of the request in seconds as the
+// rate, and fails the first request, closing failed.
+type testPriceSource struct {
+	mtx      sync.Mutex
+	requests int
+	failed   chan struct{}
+}
+
+func (s *testPriceSource) Rate(currency string, t time.Time) (float64, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.requests++
+	if s.requests == 1 {
+		close(s.failed)
+		return 0, errors.New("unavailable")
+	}
+	return float64(t.Unix()), nil
+}
+
+// TestRecordFiatRates ensures rates are recorded for existing and new wallet
+// transactions at the time they were received.
+func TestRecordFiatRates(t *testing.T) {
+	t.Parallel()
+
+	w, cleanup := testWallet(t)
+	defer cleanup()
+	defer w.WaitForShutdown()
+	defer w.Stop()
+
+	addr, err := w.NewAddress(0, waddrmgr.KeyScopeBIP0044)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addTx := func(received time.Time) chainhash.Hash {
+		msgTx := wire.NewMsgTx(wire.TxVersion)
+		msgTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{
+			Index: uint32(received.Unix()),
+		}, nil, nil))
+		msgTx.AddTxOut(wire.NewTxOut(1000, pkScript))
+		rec, err := wtxmgr.NewTxRecordFromMsgTx(msgTx, received)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+			return w.addRelevantTx(tx, rec, nil)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rec.Hash
+	}
+	waitForRate := func(hash chainhash.Hash, want float64) {
+		deadline := time.Now().Add(10 * time.Second)
+		for time.Now().Before(deadline) {
+			rates, err := w.TxFiatRates()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rate, ok := rates[hash]; ok {
+				if rate.Rate != want || rate.Currency != "USD" {
+					t.Fatalf("got rate %+v, want %v USD", rate,
+						want)
+				}
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("rate of %v was not recorded", hash)
+	}
+
+	// The first request fails, so the rate of the existing transaction is
+	// recorded when it is retried with the new transaction.
+	existing := addTx(time.Unix(1600000000, 0))
+	src := &testPriceSource{failed: make(chan struct{})}
+	w.RecordFiatRates(src, "USD")
+
+	// Wait for the failed request before adding a transaction, so that the
+	// new transaction is notified to the recorder.
+	select {
+	case <-src.failed:
+	case <-time.After(10 * time.Second):
+		t.Fatal("rate of the existing transaction was not requested")
+	}
+	added := addTx(time.Unix(1700000000, 0))
+	waitForRate(added, 1700000000)
+	waitForRate(existing, 1600000000)
+}
@@ -0,0 +1,289 @@
+package wallet
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/walletdb"
+	"github.com/lbryio/lbcwallet/wtxmgr"
+)
+
+// FixtureSeed is the fixed seed of fixture wallets, so that they derive the
+// same keys and addresses every time they are created.
+var FixtureSeed = chainhash.HashB([]byte("lbcwallet fixture"))
+
+const (
+	// fixtureBlockInterval is the time between the blocks of a synthetic
+	// history.
+	fixtureBlockInterval = 150 * time.Second
+
+	// fixtureFee is the fee of the spends and claims of a synthetic
+	// history.
+	fixtureFee = 10000
+
+	// fixtureClaimAmount is the amount staked by the claims of a
+	// synthetic history.
+	fixtureClaimAmount = 1e6
+)
+
+// FixtureStart is the time of the first block of a synthetic history when
+// none is given, and the birthday of the fixture wallets created by
+// lbcwallet.
+var FixtureStart = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// FixtureParams describes the synthetic history of a fixture wallet.
+type FixtureParams struct {
+	// Receives, Spends and Claims are the numbers of transactions paying
+	// the wallet, paying an address outside the wallet with change, and
+	// creating a claim with change.  Spends and claims require at least
+	// one receive.
+	Receives int
+	Spends   int
+	Claims   int
+
+	// Start is the time of the first block of the history, which
+	// defaults to FixtureStart.
+	Start time.Time
+}
+
+// fixtureCredit is an unspent output of a synthetic history.
+type fixtureCredit struct {
+	outPoint wire.OutPoint
+	amount   int64
+}
+
+// fixtureKind is the kind of a transaction of a synthetic history.
+type fixtureKind int
+
+const (
+	fixtureReceive fixtureKind = iota
+	fixtureSpend
+	fixtureClaim
+)
+
+// fixtureHistory synthesizes the transactions of a history.
+type fixtureHistory struct {
+	w       *Wallet
+	rng     *rand.Rand
+	credits []fixtureCredit
+}
+
+// SynthesizeHistory adds a deterministic history of transactions to a new
+// wallet, for tests and benchmarks which need a wallet with a realistic
+// state.  Each transaction is mined in a synthetic block, one after another
+// from the start of the history, and the wallet is marked synced to the last
+// of them.  The history only depends on the params and on the seed of the
+// wallet, such as FixtureSeed: the amounts and the order of the transactions
+// are chosen by a PRNG with a fixed seed, and the wallet addresses are
+// derived in order.  The inputs of the transactions are not signed, and the
+// blocks are not part of any chain.
+func (w *Wallet) SynthesizeHistory(p *FixtureParams) error {
+	if p.Receives < 0 || p.Spends < 0 || p.Claims < 0 {
+		return errors.New("the numbers of transactions must not be " +
+			"negative")
+	}
+	if p.Receives == 0 && p.Spends+p.Claims > 0 {
+		return errors.New("spends and claims require at least one " +
+			"receive")
+	}
+	start := p.Start
+	if start.IsZero() {
+		start = FixtureStart
+	}
+
+	h := &fixtureHistory{w: w, rng: rand.New(rand.NewSource(1))}
+	remaining := [...]int{p.Receives, p.Spends, p.Claims}
+	var block *wtxmgr.BlockMeta
+	for height := int32(1); ; height++ {
+		total := remaining[0] + remaining[1] + remaining[2]
+		if total == 0 {
+			break
+		}
+
+		// The first transaction is a receive, and the next ones are
+		// picked with the odds of the remaining numbers of each kind.
+		kind := fixtureReceive
+		if len(h.credits) != 0 {
+			n := h.rng.Intn(total)
+			for n >= remaining[kind] {
+				n -= remaining[kind]
+				kind++
+			}
+		}
+		remaining[kind]--
+
+		block = fixtureBlock(height, start)
+		add := func(dbtx walletdb.ReadWriteTx) error {
+			return h.addTx(dbtx, kind, block)
+		}
+		if err := walletdb.Update(w.db, add); err != nil {
+			return err
+		}
+	}
+	if block == nil {
+		return nil
+	}
+
+	return walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		addrmgrNs := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		return w.Manager.SetSyncedTo(addrmgrNs, &waddrmgr.BlockStamp{
+			Height:    block.Height,
+			Hash:      block.Hash,
+			Timestamp: block.Time,
+		})
+	})
+}
+
+// addTx synthesizes a transaction of a kind mined in a block.  Receives are
+// funded by an outpoint unique to the block, and spends and claims spend an
+// unspent output of the history, with their change paid back to the wallet.
+func (h *fixtureHistory) addTx(dbtx walletdb.ReadWriteTx, kind fixtureKind,
+	block *wtxmgr.BlockMeta) error {
+
+	addrmgrNs := dbtx.ReadWriteBucket(waddrmgrNamespaceKey)
+	scope := waddrmgr.KeyScopeBIP0044
+	payToWallet := func(change bool) ([]byte, error) {
+		var (
+			addr btcutil.Address
+			err  error
+		)
+		if change {
+			addr, err = h.w.newChangeAddress(addrmgrNs, 0, scope)
+		} else {
+			addr, _, err = h.w.newAddress(addrmgrNs, 0, scope)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return txscript.PayToAddrScript(addr)
+	}
+
+	msgTx := wire.NewMsgTx(wire.TxVersion)
+	var value int64
+	if kind == fixtureReceive {
+		var prev wire.OutPoint
+		binary.BigEndian.PutUint32(prev.Hash[:], uint32(block.Height))
+		msgTx.AddTxIn(wire.NewTxIn(&prev, nil, nil))
+		value = 1e7 + h.rng.Int63n(1e9)
+	} else {
+		// The largest output is spent, as by CoinSelectionLargest.
+		largest := 0
+		for i, c := range h.credits {
+			if c.amount > h.credits[largest].amount {
+				largest = i
+			}
+		}
+		c := h.credits[largest]
+		if c.amount <= fixtureFee+fixtureClaimAmount {
+			return errors.New("too few receives to fund the " +
+				"spends and claims")
+		}
+		h.credits = append(h.credits[:largest],
+			h.credits[largest+1:]...)
+		msgTx.AddTxIn(wire.NewTxIn(&c.outPoint, nil, nil))
+		value = c.amount - fixtureFee
+	}
+
+	// owned are the indexes of the outputs paying the wallet which later
+	// transactions may spend.  The claims are not spent, so that they
+	// remain in the history.
+	var owned []int
+	switch kind {
+	case fixtureReceive:
+		pkScript, err := payToWallet(false)
+		if err != nil {
+			return err
+		}
+		owned = append(owned, len(msgTx.TxOut))
+		msgTx.AddTxOut(wire.NewTxOut(value, pkScript))
+		value = 0
+
+	case fixtureSpend:
+		// The address paid is derived from the height, and does not
+		// belong to the wallet.
+		hash := chainhash.HashB([]byte(fmt.Sprintf("fixture payee %d",
+			block.Height)))
+		addr, err := btcutil.NewAddressPubKeyHash(hash[:20],
+			h.w.chainParams)
+		if err != nil {
+			return err
+		}
+		pkScript, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			return err
+		}
+		amount := 1e5 + h.rng.Int63n(1e7)
+		if amount > value/2 {
+			amount = value / 2
+		}
+		msgTx.AddTxOut(wire.NewTxOut(amount, pkScript))
+		value -= amount
+
+	case fixtureClaim:
+		pkScript, err := payToWallet(false)
+		if err != nil {
+			return err
+		}
+		claimScript, err := txscript.ClaimNameScript(
+			fmt.Sprintf("fixture-%d", block.Height),
+			fmt.Sprintf("claim %d", block.Height),
+		)
+		if err != nil {
+			return err
+		}
+
+		// The script of a claim is followed by OP_TRUE, which is
+		// replaced by the script paying the wallet.
+		claimScript = append(claimScript[:len(claimScript)-1],
+			pkScript...)
+		msgTx.AddTxOut(wire.NewTxOut(fixtureClaimAmount, claimScript))
+		value -= fixtureClaimAmount
+	}
+	if value > 0 {
+		pkScript, err := payToWallet(true)
+		if err != nil {
+			return err
+		}
+		owned = append(owned, len(msgTx.TxOut))
+		msgTx.AddTxOut(wire.NewTxOut(value, pkScript))
+	}
+
+	rec, err := wtxmgr.NewTxRecordFromMsgTx(msgTx, block.Time)
+	if err != nil {
+		return err
+	}
+	if err := h.w.addRelevantTx(dbtx, rec, block); err != nil {
+		return err
+	}
+	for _, i := range owned {
+		h.credits = append(h.credits, fixtureCredit{
+			outPoint: wire.OutPoint{
+				Hash:  rec.Hash,
+				Index: uint32(i),
+			},
+			amount: msgTx.TxOut[i].Value,
+		})
+	}
+	return nil
+}
+
+// fixtureBlock returns the synthetic block of a height of a history starting
+// at a time.
+func fixtureBlock(height int32, start time.Time) *wtxmgr.BlockMeta {
+	return &wtxmgr.BlockMeta{
+		Block: wtxmgr.Block{
+			Hash: chainhash.HashH([]byte(fmt.Sprintf(
+				"fixture block %d", height))),
+			Height: height,
+		},
+		Time: start.Add(time.Duration(height) * fixtureBlockInterval),
+	}
+}
@@ -0,0 +1,80 @@
+package wallet
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/lbryio/lbcd/chaincfg"
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcwallet/walletdb"
+	"github.com/lbryio/lbcwallet/wtxmgr"
+	"github.com/stretchr/testify/require"
+)
+
+// fixtureWallet creates a wallet from the fixture seed with a synthetic
+// history.
+func fixtureWallet(t *testing.T, p *FixtureParams) *Wallet {
+	dir, err := ioutil.TempDir("", "test_fixture")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	loader := NewLoader(
+		&chaincfg.RegressionNetParams, dir, true, defaultDBTimeout, 250,
+	)
+	w, err := loader.CreateNewWallet([]byte("password"), FixtureSeed,
+		FixtureStart)
+	require.NoError(t, err)
+	t.Cleanup(func() { loader.UnloadWallet() })
+
+	require.NoError(t, w.SynthesizeHistory(p))
+	return w
+}
+
+// TestSynthesizeHistory checks that the synthetic history of a fixture wallet
+// is the same every time it is created.
+func TestSynthesizeHistory(t *testing.T) {
+	p := &FixtureParams{Receives: 20, Spends: 12, Claims: 5}
+
+	// Spends and claims need a receive to fund them.
+	w := fixtureWallet(t, &FixtureParams{})
+	require.Error(t, w.SynthesizeHistory(&FixtureParams{Spends: 1}))
+	require.NoError(t, w.SynthesizeHistory(p))
+
+	wallets := []*Wallet{w, fixtureWallet(t, p)}
+	histories := make([][]chainhash.Hash, len(wallets))
+	balances := make([]int64, len(wallets))
+	for i, w := range wallets {
+
+		err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+			ns := tx.ReadBucket(wtxmgrNamespaceKey)
+			return w.TxStore.RangeTransactions(ns, 0, -1,
+				func(details []wtxmgr.TxDetails) (bool, error) {
+					for _, d := range details {
+						histories[i] = append(
+							histories[i], d.Hash,
+						)
+					}
+					return false, nil
+				})
+		})
+		require.NoError(t, err)
+		require.Len(t, histories[i], 37)
+
+		synced := w.Manager.SyncedTo()
+		require.EqualValues(t, 37, synced.Height)
+		require.Equal(t, FixtureStart.Add(37*fixtureBlockInterval),
+			synced.Timestamp)
+
+		claims, err := w.AccountClaims()
+		require.NoError(t, err)
+		require.Len(t, claims, 5)
+
+		balance, _, err := w.CalculateBalance(1)
+		require.NoError(t, err)
+		require.Positive(t, balance)
+		balances[i] = int64(balance)
+	}
+	require.Equal(t, histories[0], histories[1])
+	require.Equal(t, balances[0], balances[1])
+}
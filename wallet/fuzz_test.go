@@ -0,0 +1,115 @@
+package wallet
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lbryio/lbcd/claimtrie/change"
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	"github.com/lbryio/lbcutil/psbt"
+)
+
+// FuzzDecodePsbt fuzzes the decoding of PSBT packets, in binary and base64,
+// checking that the packets decoded serialize back to packets which decode
+// the same.
+func FuzzDecodePsbt(f *testing.F) {
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 1}, nil, nil))
+	tx.AddTxOut(wire.NewTxOut(1e8, testScriptP2WKH))
+	tx.AddTxOut(wire.NewTxOut(5e7, testScriptP2WSH))
+	packet, err := psbt.NewFromUnsignedTx(tx)
+	if err != nil {
+		f.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := packet.Serialize(&buf); err != nil {
+		f.Fatal(err)
+	}
+	b64, err := packet.B64Encode()
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(buf.Bytes(), false)
+	f.Add([]byte(b64), true)
+	f.Add([]byte("psbt\xff"), false)
+
+	f.Fuzz(func(t *testing.T, data []byte, b64 bool) {
+		packet, err := psbt.NewFromRawBytes(bytes.NewReader(data), b64)
+		if err != nil {
+			return
+		}
+		_ = packet.SanityCheck()
+		_ = psbt.VerifyInputOutputLen(packet, false, false)
+
+		var first bytes.Buffer
+		if err := packet.Serialize(&first); err != nil {
+			return
+		}
+		decoded, err := psbt.NewFromRawBytes(
+			bytes.NewReader(first.Bytes()), false,
+		)
+		if err != nil {
+			t.Fatalf("unable to decode serialized packet %x: %v",
+				first.Bytes(), err)
+		}
+		var second bytes.Buffer
+		if err := decoded.Serialize(&second); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(first.Bytes(), second.Bytes()) {
+			t.Fatalf("packet %x serialized again as %x",
+				first.Bytes(), second.Bytes())
+		}
+	})
+}
+
+// FuzzDecodeClaimOutput fuzzes the decoding of the claim scripts of outputs
+// and of the values of their claims.
+func FuzzDecodeClaimOutput(f *testing.F) {
+	// The scripts of claims end with OP_TRUE, which is replaced by the
+	// script paying an address.
+	claimID := make([]byte, change.ClaimIDSize)
+	signed := append(append([]byte{0x01}, make([]byte, 84)...), 0x12)
+	for _, build := range []func() ([]byte, error){
+		func() ([]byte, error) {
+			return txscript.ClaimNameScript("name", "\x00\x0a\x01")
+		},
+		func() ([]byte, error) {
+			return txscript.ClaimNameScript("name", string(signed))
+		},
+		func() ([]byte, error) {
+			return txscript.ClaimSupportScript("name", claimID, nil)
+		},
+		func() ([]byte, error) {
+			return txscript.ClaimUpdateScript("name", claimID, "")
+		},
+	} {
+		script, err := build()
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(append(script[:len(script)-1], testScriptP2WKH...))
+	}
+	f.Add(testScriptP2WKH)
+
+	f.Fuzz(func(t *testing.T, pkScript []byte) {
+		c, ok := decodeClaimOutput(wire.OutPoint{Index: 1}, pkScript)
+		if !ok {
+			return
+		}
+		switch c.Op {
+		case "claim", "support", "update":
+		default:
+			t.Fatalf("unknown claim operation %q", c.Op)
+		}
+		if len(c.ClaimID) != 2*change.ClaimIDSize {
+			t.Fatalf("invalid claim ID %q", c.ClaimID)
+		}
+		if c.SigningChannel != "" &&
+			len(c.SigningChannel) != 2*change.ClaimIDSize {
+
+			t.Fatalf("invalid signing channel %q", c.SigningChannel)
+		}
+	})
+}
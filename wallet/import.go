@@ -107,12 +107,11 @@ func (w *Wallet) isPubKeyForNet(pubKey *hdkeychain.ExtendedKey) bool {
 			version == waddrmgr.HDVersionMainNetBIP0049 ||
 			version == waddrmgr.HDVersionMainNetBIP0084
 
-	case wire.TestNet, wire.TestNet3:
+	// Every other network uses the versions of the test networks.
+	default:
 		return version == waddrmgr.HDVersionTestNetBIP0044 ||
 			version == waddrmgr.HDVersionTestNetBIP0049 ||
 			version == waddrmgr.HDVersionTestNetBIP0084
-	default:
-		return false
 	}
 }
 
@@ -203,6 +202,10 @@ func (w *Wallet) ImportAccountWithScope(name string,
 	keyScope waddrmgr.KeyScope, addrSchema waddrmgr.ScopeAddrSchema) (
 	*waddrmgr.AccountProperties, error) {
 
+	if err := w.requireDiskSpace(); err != nil {
+		return nil, err
+	}
+
 	var accountProps *waddrmgr.AccountProperties
 	err := walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
 		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
@@ -256,14 +259,15 @@ func (w *Wallet) importAccountScope(ns walletdb.ReadWriteBucket, name string,
 		}
 	}
 
-	// FIXME
-	// account, err := scopedMgr.NewAccountWatchingOnly(
-	// 	ns, name, accountPubKey, masterKeyFingerprint, addrSchema,
-	// )
-	// if err != nil {
-	// 	return nil, err
-	// }
-	var account uint32
+	// The accounts of the manager don't record a master key fingerprint
+	// or an address schema of their own, so the addresses of the account
+	// follow the schema of its scope.
+	account, err := scopedMgr.NewAccountWatchingOnly(
+		ns, name, accountPubKey,
+	)
+	if err != nil {
+		return nil, err
+	}
 	return scopedMgr.AccountProperties(ns, account)
 }
 
@@ -355,42 +359,103 @@ func (w *Wallet) ImportAccountDryRun(name string,
 func (w *Wallet) ImportPublicKey(pubKey *btcec.PublicKey,
 	addrType waddrmgr.AddressType) error {
 
+	switch addrType {
+	case waddrmgr.NestedWitnessPubKey, waddrmgr.WitnessPubKey:
+	default:
+		return fmt.Errorf("address type %v is not supported", addrType)
+	}
+	_, err := w.ImportWatchOnlyPublicKey(pubKey, addrType, nil)
+	return err
+}
+
+// ImportWatchOnlyPublicKey imports a public key into the default imported
+// account of the key scope of the address type, as a watch-only address, and
+// returns the address.  The backend notifies the transactions paying the
+// address from then on, without the wallet being restarted.  When rescanFrom
+// is not nil, the blocks from it to the tip are also rescanned in the
+// background for the earlier transactions of the address, which requires a
+// backend.
+func (w *Wallet) ImportWatchOnlyPublicKey(pubKey *btcec.PublicKey,
+	addrType waddrmgr.AddressType,
+	rescanFrom *waddrmgr.BlockStamp) (btcutil.Address, error) {
+
+	if err := w.requireDiskSpace(); err != nil {
+		return nil, err
+	}
+
 	// Determine what key scope the public key should belong to and import
 	// it into the key scope's default imported account.
 	var keyScope waddrmgr.KeyScope
 	switch addrType {
+	case waddrmgr.PubKeyHash:
+		keyScope = waddrmgr.KeyScopeBIP0044
 	case waddrmgr.NestedWitnessPubKey:
 		keyScope = waddrmgr.KeyScopeBIP0049
 	case waddrmgr.WitnessPubKey:
 		keyScope = waddrmgr.KeyScopeBIP0084
 	default:
-		return fmt.Errorf("address type %v is not supported", addrType)
+		return nil, fmt.Errorf("address type %v is not supported",
+			addrType)
 	}
 
 	scopedKeyManager, err := w.Manager.FetchScopedKeyManager(keyScope)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if rescanFrom != nil {
+		if _, err := w.requireChainClient(); err != nil {
+			return nil, err
+		}
 	}
 
-	// TODO: Perform rescan if requested.
 	var addr waddrmgr.ManagedAddress
 	err = walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
 		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
-		addr, err = scopedKeyManager.ImportPublicKey(ns, pubKey, nil)
+		addr, err = scopedKeyManager.ImportPublicKey(
+			ns, pubKey, rescanFrom,
+		)
 		return err
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	log.Infof("Imported address %v", addr.Address())
 
-	err = w.chainClient.NotifyReceived([]btcutil.Address{addr.Address()})
+	err = w.watchImportedAddress(addr.Address(), rescanFrom)
+	if err != nil {
+		return nil, err
+	}
+	return addr.Address(), nil
+}
+
+// watchImportedAddress asks the backend to notify the transactions paying an
+// address imported into the wallet, and submits a rescan of the blocks from
+// rescanFrom for its earlier transactions unless rescanFrom is nil.  Without
+// a backend, the address is registered with the next one the wallet
+// synchronizes with.
+func (w *Wallet) watchImportedAddress(addr btcutil.Address,
+	rescanFrom *waddrmgr.BlockStamp) error {
+
+	chainClient := w.ChainClient()
+	if chainClient == nil {
+		return nil
+	}
+	err := chainClient.NotifyReceived([]btcutil.Address{addr})
 	if err != nil {
 		return fmt.Errorf("unable to subscribe for address "+
 			"notifications: %v", err)
 	}
+	if rescanFrom == nil {
+		return nil
+	}
 
+	// The rescan success or failure is logged by the rescan handler, and
+	// the channel does not need to be read.
+	_ = w.SubmitRescan(&RescanJob{
+		Addrs:      []btcutil.Address{addr},
+		BlockStamp: *rescanFrom,
+	})
 	return nil
 }
 
@@ -402,6 +467,10 @@ func (w *Wallet) ImportPublicKey(pubKey *btcec.PublicKey,
 func (w *Wallet) ImportPrivateKey(scope waddrmgr.KeyScope, wif *btcutil.WIF,
 	bs *waddrmgr.BlockStamp, rescan bool) (string, error) {
 
+	if err := w.requireDiskSpace(); err != nil {
+		return "", err
+	}
+
 	manager, err := w.Manager.FetchScopedKeyManager(scope)
 	if err != nil {
 		return "", err
@@ -2,13 +2,19 @@ package wallet
 
 import (
 	"encoding/binary"
+	"io/ioutil"
+	"os"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/lbryio/lbcd/btcec"
 	"github.com/lbryio/lbcd/chaincfg"
 	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
 	btcutil "github.com/lbryio/lbcutil"
 	"github.com/lbryio/lbcutil/hdkeychain"
+	"github.com/lbryio/lbcwallet/chain"
 	"github.com/lbryio/lbcwallet/waddrmgr"
 	"github.com/stretchr/testify/require"
 )
@@ -272,3 +278,73 @@ func testImportAccount(t *testing.T, w *Wallet, tc *testCase, name string) {
 	require.NoError(t, err)
 	require.Equal(t, true, addrManaged.Imported())
 }
+
+// TestImportWatchOnlyPublicKey checks that a watch-only public key imported
+// into a synced wallet picks up the transactions paying its address from the
+// rescanned blocks and from the blocks mined later.
+func TestImportWatchOnlyPublicKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test_importwatch")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	params := &chaincfg.RegressionNetParams
+	loader := NewLoader(params, dir, true, defaultDBTimeout, 250)
+	birthday := params.GenesisBlock.Header.Timestamp
+	w, err := loader.CreateNewWallet(
+		[]byte("password"), FixtureSeed, birthday,
+	)
+	require.NoError(t, err)
+	defer loader.UnloadWallet()
+
+	backend := chain.NewMockInterface(params)
+	require.NoError(t, backend.Start())
+	defer backend.Stop()
+	w.Start()
+	w.SynchronizeRPC(backend)
+	backend.MineBlock()
+	require.NoError(t, w.WaitForHeight(1, 5*time.Second))
+
+	privKey, err := btcec.NewPrivateKey(btcec.S256())
+	require.NoError(t, err)
+	pubKey := privKey.PubKey()
+	addr, err := btcutil.NewAddressPubKeyHash(
+		btcutil.Hash160(pubKey.SerializeCompressed()), params,
+	)
+	require.NoError(t, err)
+	pkScript, err := txscript.PayToAddrScript(addr)
+	require.NoError(t, err)
+	pay := func(index uint32) *wire.MsgTx {
+		tx := wire.NewMsgTx(wire.TxVersion)
+		tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: index}, nil, nil))
+		tx.AddTxOut(wire.NewTxOut(1e8, pkScript))
+		return tx
+	}
+	recorded := func(tx *wire.MsgTx) bool {
+		hash := tx.TxHash()
+		details, err := UnstableAPI(w).TxDetails(&hash)
+		require.NoError(t, err)
+		return details != nil
+	}
+
+	before := pay(1)
+	block := backend.MineBlock(before)
+	require.NoError(t, w.WaitForHeight(2, 5*time.Second))
+	require.False(t, recorded(before))
+
+	imported, err := w.ImportWatchOnlyPublicKey(
+		pubKey, waddrmgr.PubKeyHash, &waddrmgr.BlockStamp{
+			Hash:   block.BlockHash(),
+			Height: 2,
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, addr.EncodeAddress(), imported.EncodeAddress())
+	require.Eventually(t, func() bool {
+		return recorded(before)
+	}, 5*time.Second, 10*time.Millisecond)
+
+	after := pay(2)
+	backend.MineBlock(after)
+	require.NoError(t, w.WaitForHeight(3, 5*time.Second))
+	require.True(t, recorded(after))
+}
@@ -0,0 +1,238 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/lbryio/lbcd/txscript"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/walletdb"
+)
+
+// bucketImportedAccounts is the name of the sub bucket of the wallet
+// namespace that maps the encoded addresses of imported keys to the name of
+// the imported-key account they belong to.
+var bucketImportedAccounts = []byte("importedaccts")
+
+// ErrImportedAccountNotFound is returned for an imported-key account without
+// any address.
+var ErrImportedAccountNotFound = errors.New("imported-key account not found")
+
+// ImportedAccount is a named account of imported keys.  The keys remain in the
+// imported account of the address manager, and the named accounts organize
+// them, so that each can be listed, exported and rescanned on its own.  Keys
+// which were not assigned an account belong to the account named "imported".
+type ImportedAccount struct {
+	Name      string
+	Addresses []btcutil.Address
+
+	// Balance is the value of the unspent outputs paying to the
+	// addresses, including unconfirmed outputs.
+	Balance btcutil.Amount
+}
+
+// validImportedAccountName returns an error when name can not name an
+// imported-key account, as it is empty or already names an HD account.
+func (w *Wallet) validImportedAccountName(addrmgrNs walletdb.ReadBucket,
+	name string) error {
+
+	if name == "" {
+		return errors.New("imported-key accounts must be named")
+	}
+	if name == waddrmgr.ImportedAddrAccountName {
+		return nil
+	}
+	manager, err := w.Manager.FetchScopedKeyManager(waddrmgr.DefaultKeyScope)
+	if err != nil {
+		return err
+	}
+	_, err = manager.LookupAccount(addrmgrNs, name)
+	switch {
+	case err == nil:
+		return fmt.Errorf("account %q is not an imported-key account",
+			name)
+	case waddrmgr.IsError(err, waddrmgr.ErrAccountNotFound):
+		return nil
+	default:
+		return err
+	}
+}
+
+// SetImportedAccount assigns the address of an imported key to the named
+// imported-key account, which is created if it has no address yet.  Assigning
+// an address to the "imported" account removes it from its named account.
+func (w *Wallet) SetImportedAccount(addr btcutil.Address, name string) error {
+	return walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
+		if err := w.validImportedAccountName(addrmgrNs, name); err != nil {
+			return err
+		}
+		_, acct, err := w.Manager.AddrAccount(addrmgrNs, addr)
+		if err != nil {
+			return err
+		}
+		if acct != waddrmgr.ImportedAddrAccount {
+			return fmt.Errorf("address %s is not an imported key",
+				addr.EncodeAddress())
+		}
+
+		ns := tx.ReadWriteBucket(walletNamespaceKey)
+		accts, err := ns.CreateBucketIfNotExists(bucketImportedAccounts)
+		if err != nil {
+			return err
+		}
+		k := []byte(addr.EncodeAddress())
+		if name == waddrmgr.ImportedAddrAccountName {
+			return accts.Delete(k)
+		}
+		return accts.Put(k, []byte(name))
+	})
+}
+
+// ImportedAccounts returns every imported-key account sorted by name,
+// including the "imported" account when it has addresses.
+func (w *Wallet) ImportedAccounts() ([]*ImportedAccount, error) {
+	byName := make(map[string]*ImportedAccount)
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
+		txmgrNs := tx.ReadBucket(wtxmgrNamespaceKey)
+		accts := tx.ReadBucket(walletNamespaceKey).NestedReadBucket(
+			bucketImportedAccounts)
+
+		byAddr := make(map[string]*ImportedAccount)
+		err := w.Manager.ForEachAccountAddress(addrmgrNs,
+			waddrmgr.ImportedAddrAccount,
+			func(maddr waddrmgr.ManagedAddress) error {
+				addr := maddr.Address()
+				encoded := addr.EncodeAddress()
+				name := waddrmgr.ImportedAddrAccountName
+				if accts != nil {
+					if v := accts.Get([]byte(encoded)); v != nil {
+						name = string(v)
+					}
+				}
+				a, ok := byName[name]
+				if !ok {
+					a = &ImportedAccount{Name: name}
+					byName[name] = a
+				}
+				a.Addresses = append(a.Addresses, addr)
+				byAddr[encoded] = a
+				return nil
+			})
+		if err != nil {
+			return err
+		}
+
+		unspent, err := w.TxStore.UnspentOutputs(txmgrNs)
+		if err != nil {
+			return err
+		}
+		for i := range unspent {
+			_, addrs, _, err := txscript.ExtractPkScriptAddrs(
+				unspent[i].PkScript, w.chainParams)
+			if err != nil || len(addrs) == 0 {
+				continue
+			}
+			if a, ok := byAddr[addrs[0].EncodeAddress()]; ok {
+				a.Balance += unspent[i].Amount
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := make([]*ImportedAccount, 0, len(byName))
+	for _, a := range byName {
+		accounts = append(accounts, a)
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].Name < accounts[j].Name
+	})
+	return accounts, nil
+}
+
+// ImportedAccount returns the imported-key account with the given name.
+func (w *Wallet) ImportedAccount(name string) (*ImportedAccount, error) {
+	accounts, err := w.ImportedAccounts()
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range accounts {
+		if a.Name == name {
+			return a, nil
+		}
+	}
+	return nil, ErrImportedAccountNotFound
+}
+
+// ExportImportedAccount returns the private keys of the addresses of an
+// imported-key account, with the addresses they are for.  Addresses of
+// scripts are skipped.  The wallet must be unlocked.
+func (w *Wallet) ExportImportedAccount(name string) ([]btcutil.Address,
+	[]*btcutil.WIF, error) {
+
+	a, err := w.ImportedAccount(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		addrs []btcutil.Address
+		wifs  []*btcutil.WIF
+	)
+	err = walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
+		for _, addr := range a.Addresses {
+			maddr, err := w.Manager.Address(addrmgrNs, addr)
+			if err != nil {
+				return err
+			}
+			pka, ok := maddr.(waddrmgr.ManagedPubKeyAddress)
+			if !ok {
+				continue
+			}
+			wif, err := pka.ExportPrivKey()
+			if err != nil {
+				return err
+			}
+			addrs = append(addrs, addr)
+			wifs = append(wifs, wif)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return addrs, wifs, nil
+}
+
+// RescanImportedAccount rescans the blockchain from the given block for the
+// transactions of the addresses of an imported-key account, and returns once
+// the rescan completes.  The error of ctx is returned when it is done first,
+// while the rescan submitted completes in the background.
+func (w *Wallet) RescanImportedAccount(ctx context.Context, name string,
+	bs waddrmgr.BlockStamp) error {
+
+	a, err := w.ImportedAccount(name)
+	if err != nil {
+		return err
+	}
+	job := &RescanJob{
+		Addrs:      a.Addresses,
+		BlockStamp: bs,
+	}
+	select {
+	case err := <-w.SubmitRescan(job):
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-w.quitChan():
+		return ErrWalletShuttingDown
+	}
+}
@@ -0,0 +1,87 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/lbryio/lbcd/btcec"
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/walletdb"
+	"github.com/stretchr/testify/require"
+)
+
+// TestImportedAccounts ensures imported keys can be organized in named
+// imported-key accounts, each with its own balance and exported keys.
+func TestImportedAccounts(t *testing.T) {
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	// Importing a key may lower the birthday block, so one must be set.
+	err := walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		return w.Manager.SetBirthdayBlock(ns, w.Manager.SyncedTo(), true)
+	})
+	require.NoError(t, err)
+
+	importKey := func() btcutil.Address {
+		privKey, err := btcec.NewPrivateKey(btcec.S256())
+		require.NoError(t, err)
+		wif, err := btcutil.NewWIF(privKey, w.chainParams, true)
+		require.NoError(t, err)
+		addrStr, err := w.ImportPrivateKey(
+			waddrmgr.KeyScopeBIP0044, wif, nil, false,
+		)
+		require.NoError(t, err)
+		addr, err := btcutil.DecodeAddress(addrStr, w.chainParams)
+		require.NoError(t, err)
+		return addr
+	}
+	kept := importKey()
+	swept := importKey()
+
+	// HD accounts and derived addresses can not be used.
+	require.Error(t, w.SetImportedAccount(swept, "default"))
+	require.Error(t, w.SetImportedAccount(swept, ""))
+	derived, err := w.NewAddress(0, waddrmgr.KeyScopeBIP0044)
+	require.NoError(t, err)
+	require.Error(t, w.SetImportedAccount(derived, "swept"))
+
+	require.NoError(t, w.SetImportedAccount(swept, "swept"))
+	pkScript, err := txscript.PayToAddrScript(swept)
+	require.NoError(t, err)
+	addUtxo(t, w, &wire.MsgTx{
+		TxIn:  []*wire.TxIn{{}},
+		TxOut: []*wire.TxOut{wire.NewTxOut(1e8, pkScript)},
+	})
+
+	accounts, err := w.ImportedAccounts()
+	require.NoError(t, err)
+	require.Len(t, accounts, 2)
+	require.Equal(t, waddrmgr.ImportedAddrAccountName, accounts[0].Name)
+	require.Equal(t, []btcutil.Address{kept}, accounts[0].Addresses)
+	require.Zero(t, accounts[0].Balance)
+	require.Equal(t, "swept", accounts[1].Name)
+	require.Equal(t, []btcutil.Address{swept}, accounts[1].Addresses)
+	require.Equal(t, btcutil.Amount(1e8), accounts[1].Balance)
+
+	addrs, wifs, err := w.ExportImportedAccount("swept")
+	require.NoError(t, err)
+	require.Len(t, wifs, 1)
+	require.Equal(t, swept, addrs[0])
+	wifAddr, err := btcutil.NewAddressPubKey(
+		wifs[0].SerializePubKey(), w.chainParams,
+	)
+	require.NoError(t, err)
+	require.Equal(t, swept.EncodeAddress(),
+		wifAddr.AddressPubKeyHash().EncodeAddress())
+
+	// Moving the address back to the imported account removes the named
+	// account.
+	require.NoError(t, w.SetImportedAccount(
+		swept, waddrmgr.ImportedAddrAccountName,
+	))
+	_, err = w.ImportedAccount("swept")
+	require.ErrorIs(t, err, ErrImportedAccountNotFound)
+}
@@ -0,0 +1,489 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/walletdb"
+	"github.com/lbryio/lbcwallet/wtxmgr"
+)
+
+var (
+	// bucketInvoices is the name of the sub bucket of the wallet namespace
+	// that maps invoice IDs to serialized invoices.
+	bucketInvoices = []byte("invoices")
+
+	// bucketInvoiceAddrs is the name of the sub bucket of the wallet
+	// namespace that maps the address of every invoice to its ID.
+	bucketInvoiceAddrs = []byte("invoiceaddrs")
+)
+
+// ErrInvoiceNotFound is returned when an invoice does not exist.
+var ErrInvoiceNotFound = errors.New("invoice not found")
+
+// invoiceRetryInterval is the time after which expiring invoices is retried
+// when it fails.
+const invoiceRetryInterval = time.Minute
+
+// InvoiceState describes whether an invoice has been paid.
+type InvoiceState uint8
+
+// Invoice states.  An unpaid invoice becomes either paid or expired, after
+// which its state no longer changes.
+const (
+	InvoiceUnpaid InvoiceState = iota
+	InvoicePaid
+	InvoiceExpired
+)
+
+// String returns the name of the state.
+func (s InvoiceState) String() string {
+	switch s {
+	case InvoiceUnpaid:
+		return "unpaid"
+	case InvoicePaid:
+		return "paid"
+	case InvoiceExpired:
+		return "expired"
+	default:
+		return fmt.Sprintf("unknown (%d)", uint8(s))
+	}
+}
+
+// InvoicePayment is a transaction paying the address of an invoice.
+type InvoicePayment struct {
+	TxHash chainhash.Hash
+	Amount btcutil.Amount
+}
+
+// Invoice is a request for a payment to a fresh wallet address.  It is paid
+// once the transactions paying its address before it expires total at least
+// its amount.  Payments received after an invoice is paid or has expired are
+// still recorded.
+type Invoice struct {
+	ID      uint64
+	Address btcutil.Address
+
+	// Amount is the requested amount.  An invoice with a zero amount is
+	// paid by any payment.
+	Amount btcutil.Amount
+
+	Memo    string
+	Created time.Time
+	Expires time.Time
+	State   InvoiceState
+
+	// Paid is the time the invoice was paid, and is zero unless its state
+	// is InvoicePaid.
+	Paid time.Time
+
+	Payments []InvoicePayment
+}
+
+// Received returns the total value of the payments of the invoice.
+func (inv *Invoice) Received() btcutil.Amount {
+	var total btcutil.Amount
+	for _, p := range inv.Payments {
+		total += p.Amount
+	}
+	return total
+}
+
+// invoiceKey returns the key of an invoice in the invoices bucket.
+func invoiceKey(id uint64) []byte {
+	var k [8]byte
+	binary.BigEndian.PutUint64(k[:], id)
+	return k[:]
+}
+
+// serializeInvoice returns the serialization of an invoice, without its ID
+// which is its key:
+//
+//	[0:8]   amount (8 bytes)
+//	[8:16]  creation time (8 bytes)
+//	[16:24] expiry time (8 bytes)
+//	[24:32] payment time, or 0 (8 bytes)
+//	[32]    state (1 byte)
+//	[33:]   address and memo as varstrings, then a varint count of
+//	        payments, each a tx hash (32 bytes) and amount (8 bytes)
+func serializeInvoice(inv *Invoice) ([]byte, error) {
+	var buf bytes.Buffer
+	var fixed [33]byte
+	binary.BigEndian.PutUint64(fixed[0:8], uint64(inv.Amount))
+	binary.BigEndian.PutUint64(fixed[8:16], uint64(inv.Created.Unix()))
+	binary.BigEndian.PutUint64(fixed[16:24], uint64(inv.Expires.Unix()))
+	if !inv.Paid.IsZero() {
+		binary.BigEndian.PutUint64(fixed[24:32], uint64(inv.Paid.Unix()))
+	}
+	fixed[32] = byte(inv.State)
+	buf.Write(fixed[:])
+
+	err := wire.WriteVarString(&buf, 0, inv.Address.EncodeAddress())
+	if err != nil {
+		return nil, err
+	}
+	if err := wire.WriteVarString(&buf, 0, inv.Memo); err != nil {
+		return nil, err
+	}
+	err = wire.WriteVarInt(&buf, 0, uint64(len(inv.Payments)))
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range inv.Payments {
+		var amount [8]byte
+		binary.BigEndian.PutUint64(amount[:], uint64(p.Amount))
+		buf.Write(p.TxHash[:])
+		buf.Write(amount[:])
+	}
+
+	return buf.Bytes(), nil
+}
+
+// deserializeInvoice decodes an invoice serialized by serializeInvoice.
+func (w *Wallet) deserializeInvoice(k, v []byte) (*Invoice, error) {
+	if len(k) != 8 || len(v) < 33 {
+		return nil, fmt.Errorf("short invoice: %d bytes", len(v))
+	}
+
+	inv := &Invoice{
+		ID:      binary.BigEndian.Uint64(k),
+		Amount:  btcutil.Amount(binary.BigEndian.Uint64(v[0:8])),
+		Created: time.Unix(int64(binary.BigEndian.Uint64(v[8:16])), 0),
+		Expires: time.Unix(int64(binary.BigEndian.Uint64(v[16:24])), 0),
+		State:   InvoiceState(v[32]),
+	}
+	if paid := int64(binary.BigEndian.Uint64(v[24:32])); paid != 0 {
+		inv.Paid = time.Unix(paid, 0)
+	}
+
+	r := bytes.NewReader(v[33:])
+	addr, err := wire.ReadVarString(r, 0)
+	if err != nil {
+		return nil, err
+	}
+	inv.Address, err = btcutil.DecodeAddress(addr, w.chainParams)
+	if err != nil {
+		return nil, err
+	}
+	if inv.Memo, err = wire.ReadVarString(r, 0); err != nil {
+		return nil, err
+	}
+	n, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(r.Len())/40 {
+		return nil, io.ErrUnexpectedEOF
+	}
+	inv.Payments = make([]InvoicePayment, n)
+	for i := range inv.Payments {
+		var amount [8]byte
+		if _, err := io.ReadFull(r, inv.Payments[i].TxHash[:]); err != nil {
+			return nil, err
+		}
+		if _, err := io.ReadFull(r, amount[:]); err != nil {
+			return nil, err
+		}
+		inv.Payments[i].Amount = btcutil.Amount(
+			binary.BigEndian.Uint64(amount[:]),
+		)
+	}
+
+	return inv, nil
+}
+
+// putInvoice stores an invoice, indexing it by its address.
+func putInvoice(ns walletdb.ReadWriteBucket, inv *Invoice) error {
+	v, err := serializeInvoice(inv)
+	if err != nil {
+		return err
+	}
+	invoices, err := ns.CreateBucketIfNotExists(bucketInvoices)
+	if err != nil {
+		return err
+	}
+	addrs, err := ns.CreateBucketIfNotExists(bucketInvoiceAddrs)
+	if err != nil {
+		return err
+	}
+	k := invoiceKey(inv.ID)
+	if err := invoices.Put(k, v); err != nil {
+		return err
+	}
+	return addrs.Put([]byte(inv.Address.EncodeAddress()), k)
+}
+
+// fetchInvoice returns the invoice with the given key.
+func (w *Wallet) fetchInvoice(ns walletdb.ReadBucket, k []byte) (*Invoice,
+	error) {
+
+	invoices := ns.NestedReadBucket(bucketInvoices)
+	if invoices == nil {
+		return nil, ErrInvoiceNotFound
+	}
+	v := invoices.Get(k)
+	if v == nil {
+		return nil, ErrInvoiceNotFound
+	}
+	return w.deserializeInvoice(k, v)
+}
+
+// forEachInvoice calls f with every invoice in order of their IDs.
+func (w *Wallet) forEachInvoice(ns walletdb.ReadBucket,
+	f func(*Invoice) error) error {
+
+	invoices := ns.NestedReadBucket(bucketInvoices)
+	if invoices == nil {
+		return nil
+	}
+	return invoices.ForEach(func(k, v []byte) error {
+		inv, err := w.deserializeInvoice(k, v)
+		if err != nil {
+			return err
+		}
+		return f(inv)
+	})
+}
+
+// CreateInvoice creates an invoice for amount, which expires after expiry,
+// paid to a new external address of the account.
+func (w *Wallet) CreateInvoice(account uint32, scope waddrmgr.KeyScope,
+	amount btcutil.Amount, memo string, expiry time.Duration) (*Invoice,
+	error) {
+
+	if amount < 0 {
+		return nil, errors.New("invoice amount must not be negative")
+	}
+	if expiry < time.Second {
+		return nil, errors.New("invoice expiry must be at least a second")
+	}
+
+	addr, err := w.NewAddress(account, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Unix(w.now().Unix(), 0)
+	inv := &Invoice{
+		Address: addr,
+		Amount:  amount,
+		Memo:    memo,
+		Created: now,
+		Expires: now.Add(expiry.Truncate(time.Second)),
+		State:   InvoiceUnpaid,
+	}
+	err = walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(walletNamespaceKey)
+		invoices, err := ns.CreateBucketIfNotExists(bucketInvoices)
+		if err != nil {
+			return err
+		}
+		if inv.ID, err = invoices.NextSequence(); err != nil {
+			return err
+		}
+		return putInvoice(ns, inv)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Reschedule the expiry of invoices.
+	select {
+	case w.invoicesChanged <- struct{}{}:
+	default:
+	}
+
+	return inv, nil
+}
+
+// Invoice returns the invoice with the given ID.
+func (w *Wallet) Invoice(id uint64) (*Invoice, error) {
+	var inv *Invoice
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		ns := tx.ReadBucket(walletNamespaceKey)
+
+		var err error
+		inv, err = w.fetchInvoice(ns, invoiceKey(id))
+		return err
+	})
+	return inv, err
+}
+
+// Invoices returns all invoices in the order they were created.
+func (w *Wallet) Invoices() ([]*Invoice, error) {
+	var invs []*Invoice
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		ns := tx.ReadBucket(walletNamespaceKey)
+		return w.forEachInvoice(ns, func(inv *Invoice) error {
+			invs = append(invs, inv)
+			return nil
+		})
+	})
+	return invs, err
+}
+
+// creditInvoices records the payments of a transaction new to the wallet to
+// the addresses of invoices, and notifies the invoices which are paid by it.
+func (w *Wallet) creditInvoices(dbtx walletdb.ReadWriteTx,
+	rec *wtxmgr.TxRecord) error {
+
+	ns := dbtx.ReadWriteBucket(walletNamespaceKey)
+	addrs := ns.NestedReadBucket(bucketInvoiceAddrs)
+	if addrs == nil {
+		return nil
+	}
+
+	// Total the outputs paying each invoice, keeping the order of the
+	// outputs.
+	var keys []string
+	amounts := make(map[string]btcutil.Amount)
+	for _, output := range rec.MsgTx.TxOut {
+		_, outAddrs, _, err := txscript.ExtractPkScriptAddrs(
+			output.PkScript, w.chainParams,
+		)
+		if err != nil {
+			continue
+		}
+		for _, addr := range outAddrs {
+			k := addrs.Get([]byte(addr.EncodeAddress()))
+			if k == nil {
+				continue
+			}
+			if _, ok := amounts[string(k)]; !ok {
+				keys = append(keys, string(k))
+			}
+			amounts[string(k)] += btcutil.Amount(output.Value)
+		}
+	}
+
+	for _, k := range keys {
+		inv, err := w.fetchInvoice(ns, []byte(k))
+		if err != nil {
+			return err
+		}
+
+		// Transactions are notified again when they are mined.
+		recorded := false
+		for _, p := range inv.Payments {
+			if p.TxHash == rec.Hash {
+				recorded = true
+				break
+			}
+		}
+		if recorded {
+			continue
+		}
+
+		inv.Payments = append(inv.Payments, InvoicePayment{
+			TxHash: rec.Hash,
+			Amount: amounts[k],
+		})
+		changed := false
+		if inv.State == InvoiceUnpaid {
+			switch {
+			case rec.Received.After(inv.Expires):
+				inv.State = InvoiceExpired
+				changed = true
+			case inv.Received() >= inv.Amount:
+				inv.State = InvoicePaid
+				inv.Paid = time.Unix(rec.Received.Unix(), 0)
+				changed = true
+			}
+		}
+		if err := putInvoice(ns, inv); err != nil {
+			return err
+		}
+		if changed {
+			log.Infof("Invoice %d is %v by transaction %v", inv.ID,
+				inv.State, rec.Hash)
+			dbtx.OnCommit(func() {
+				w.NtfnServer.notifyInvoice(inv)
+			})
+		}
+	}
+
+	return nil
+}
+
+// expireInvoices marks the unpaid invoices which expired by now as expired,
+// and returns when the next unpaid invoice expires, or the zero time if none
+// are unpaid.
+func (w *Wallet) expireInvoices(now time.Time) (time.Time, error) {
+	var next time.Time
+	err := walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(walletNamespaceKey)
+
+		var expired []*Invoice
+		err := w.forEachInvoice(ns, func(inv *Invoice) error {
+			switch {
+			case inv.State != InvoiceUnpaid:
+			case !inv.Expires.After(now):
+				expired = append(expired, inv)
+			case next.IsZero() || inv.Expires.Before(next):
+				next = inv.Expires
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, inv := range expired {
+			inv.State = InvoiceExpired
+			if err := putInvoice(ns, inv); err != nil {
+				return err
+			}
+			log.Infof("Invoice %d has expired", inv.ID)
+			w.NtfnServer.notifyInvoice(inv)
+		}
+		return nil
+	})
+	return next, err
+}
+
+// invoiceExpirer marks invoices as expired when they expire until the wallet
+// is stopped.
+func (w *Wallet) invoiceExpirer() {
+	defer w.wg.Done()
+
+	quit := w.quitChan()
+	for {
+		// Without unpaid invoices, wait until one is created.
+		var timer *time.Timer
+		next, err := w.expireInvoices(w.now())
+		switch {
+		case err != nil:
+			log.Errorf("Unable to expire invoices: %v", err)
+			timer = time.NewTimer(invoiceRetryInterval)
+		case !next.IsZero():
+			timer = time.NewTimer(next.Sub(w.now()))
+		}
+
+		var timeout <-chan time.Time
+		if timer != nil {
+			timeout = timer.C
+		}
+		select {
+		case <-timeout:
+		case <-w.invoicesChanged:
+		case <-quit:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		select {
+		case <-quit:
+			return
+		default:
+		}
+	}
+}
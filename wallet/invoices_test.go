@@ -0,0 +1,134 @@
+package wallet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/walletdb"
+	"github.com/lbryio/lbcwallet/wtxmgr"
+)
+
+// TestInvoices ensures invoices are paid by transactions paying their
+// address, and expire if they are not paid in time.
+func TestInvoices(t *testing.T) {
+	t.Parallel()
+
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	client := w.NtfnServer.InvoiceNotifications()
+	defer client.Done()
+	ntfns := make(chan *Invoice, 4)
+	go func() {
+		for inv := range client.C {
+			ntfns <- inv
+		}
+	}()
+	nextNtfn := func() *Invoice {
+		select {
+		case inv := <-ntfns:
+			return inv
+		case <-time.After(5 * time.Second):
+			t.Fatal("invoice was not notified")
+			return nil
+		}
+	}
+
+	payTo := func(addr btcutil.Address, value int64, block *wtxmgr.BlockMeta) *wtxmgr.TxRecord {
+		pkScript, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgTx := wire.NewMsgTx(wire.TxVersion)
+		msgTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: uint32(value)}, nil, nil))
+		msgTx.AddTxOut(wire.NewTxOut(value, pkScript))
+		rec, err := wtxmgr.NewTxRecordFromMsgTx(msgTx, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+			return w.addRelevantTx(tx, rec, block)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rec
+	}
+
+	paid, err := w.CreateInvoice(
+		0, waddrmgr.KeyScopeBIP0044, 3000, "order 1", time.Hour,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expiring, err := w.CreateInvoice(
+		0, waddrmgr.KeyScopeBIP0044, 1000, "order 2", time.Hour,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if paid.ID == expiring.ID || paid.Address.String() == expiring.Address.String() {
+		t.Fatal("invoices share an ID or address")
+	}
+
+	// A partial payment does not pay the invoice, and seeing it again when
+	// it is mined does not count it twice.
+	rec := payTo(paid.Address, 2000, nil)
+	payTo(paid.Address, 2000, &wtxmgr.BlockMeta{
+		Block: wtxmgr.Block{Height: 100}, Time: time.Now(),
+	})
+	inv, err := w.Invoice(paid.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inv.State != InvoiceUnpaid || inv.Received() != 2000 ||
+		inv.Payments[0].TxHash != rec.Hash {
+
+		t.Fatalf("unexpected invoice after partial payment: %+v", inv)
+	}
+
+	payTo(paid.Address, 1500, nil)
+	inv = nextNtfn()
+	if inv.ID != paid.ID || inv.State != InvoicePaid ||
+		inv.Received() != 3500 || inv.Paid.IsZero() {
+
+		t.Fatalf("unexpected paid invoice notification: %+v", inv)
+	}
+
+	// The remaining invoice expires once its expiry has passed.
+	next, err := w.expireInvoices(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !next.Equal(expiring.Expires) {
+		t.Fatalf("next expiry is %v, want %v", next, expiring.Expires)
+	}
+	if _, err := w.expireInvoices(expiring.Expires); err != nil {
+		t.Fatal(err)
+	}
+	inv = nextNtfn()
+	if inv.ID != expiring.ID || inv.State != InvoiceExpired {
+		t.Fatalf("unexpected expired invoice notification: %+v", inv)
+	}
+
+	// Late payments are recorded without paying the invoice.
+	payTo(expiring.Address, 1000, nil)
+	invs, err := w.Invoices()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(invs) != 2 || invs[0].State != InvoicePaid ||
+		invs[1].State != InvoiceExpired || invs[1].Received() != 1000 ||
+		invs[1].Memo != "order 2" {
+
+		t.Fatalf("unexpected invoices %+v %+v", invs[0], invs[1])
+	}
+
+	if _, err := w.Invoice(expiring.ID + 1); err != ErrInvoiceNotFound {
+		t.Fatalf("got error %v, want ErrInvoiceNotFound", err)
+	}
+}
@@ -0,0 +1,128 @@
+package wallet
+
+import (
+	"time"
+
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/walletdb"
+)
+
+// keyPoolRefillInterval is how often the keypools are topped up when no
+// address is derived, to catch the accounts created in the meantime.
+const keyPoolRefillInterval = time.Minute
+
+// MaxKeyPoolSize is the largest size of the keypools, which bounds the time
+// taken by a refill.
+const MaxKeyPoolSize = 100000
+
+// SetKeyPoolSize sets the number of the next unused external and internal
+// addresses of each account whose keys are derived in advance.  A size of 0
+// disables the refills, and the addresses left in the keypools are still
+// used.
+func (w *Wallet) SetKeyPoolSize(size uint32) {
+	w.keyPoolSizeMtx.Lock()
+	w.keyPoolSize = size
+	w.keyPoolSizeMtx.Unlock()
+
+	w.signalKeyPoolRefill()
+}
+
+// KeyPoolSize returns the size of the keypools set by SetKeyPoolSize.
+func (w *Wallet) KeyPoolSize() uint32 {
+	w.keyPoolSizeMtx.Lock()
+	defer w.keyPoolSizeMtx.Unlock()
+
+	return w.keyPoolSize
+}
+
+// signalKeyPoolRefill wakes the keypool refiller up without blocking, once
+// addresses were derived or the size of the keypools changed.
+func (w *Wallet) signalKeyPoolRefill() {
+	select {
+	case w.keyPoolRefill <- struct{}{}:
+	default:
+	}
+}
+
+// RefillKeyPool derives the keys missing from the keypools of the accounts of
+// every active key scope, so that each holds the keys of the size next unused
+// addresses of both branches.  The keys are derived in a read transaction and
+// stored in a short write transaction, so that the derivation of addresses is
+// never held up by it.
+func (w *Wallet) RefillKeyPool(size uint32) error {
+	if size == 0 {
+		return nil
+	}
+
+	for _, manager := range w.Manager.ActiveScopedKeyManagers() {
+		var accounts []uint32
+		err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+			addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
+			return manager.ForEachAccount(addrmgrNs,
+				func(acct uint32) error {
+					if acct != waddrmgr.ImportedAddrAccount {
+						accounts = append(accounts, acct)
+					}
+					return nil
+				})
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, account := range accounts {
+			err := w.refillAccountKeyPool(manager, account, size)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// refillAccountKeyPool derives the keys missing from the keypool of an
+// account, and stores them.
+func (w *Wallet) refillAccountKeyPool(manager *waddrmgr.ScopedKeyManager,
+	account, size uint32) error {
+
+	var keys []waddrmgr.KeyPoolKey
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
+		var err error
+		keys, err = manager.DeriveKeyPool(addrmgrNs, account, size)
+		return err
+	})
+	if err != nil || len(keys) == 0 {
+		return err
+	}
+
+	return walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		addrmgrNs := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		return manager.PutKeyPool(addrmgrNs, keys)
+	})
+}
+
+// keyPoolRefiller tops the keypools up to the size set by SetKeyPoolSize when
+// the wallet starts, after addresses are derived, and periodically, until the
+// wallet is stopped.
+func (w *Wallet) keyPoolRefiller() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(keyPoolRefillInterval)
+	defer ticker.Stop()
+
+	quit := w.quitChan()
+	for {
+		if err := w.RefillKeyPool(w.KeyPoolSize()); err != nil {
+			log.Errorf("Unable to refill keypool: %v", err)
+		}
+
+		select {
+		case <-w.keyPoolRefill:
+		case <-ticker.C:
+		case <-quit:
+			return
+		}
+	}
+}
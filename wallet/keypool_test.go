@@ -0,0 +1,97 @@
+package wallet
+
+import (
+	"testing"
+
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/walletdb"
+	"github.com/stretchr/testify/require"
+)
+
+// TestKeyPool ensures the addresses handed out after a refill are those of the
+// pooled keys, consume the keypool, and can be signed for whether or not the
+// wallet was locked when they were handed out.
+func TestKeyPool(t *testing.T) {
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	manager, err := w.Manager.FetchScopedKeyManager(
+		waddrmgr.KeyScopeBIP0084,
+	)
+	require.NoError(t, err)
+
+	var keys []waddrmgr.KeyPoolKey
+	err = walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
+		keys, err = manager.DeriveKeyPool(
+			addrmgrNs, waddrmgr.DefaultAccountNum, 3,
+		)
+		return err
+	})
+	require.NoError(t, err)
+	require.Len(t, keys, 6)
+
+	require.NoError(t, w.RefillKeyPool(3))
+	pooled := func() (uint32, uint32) {
+		pools, err := w.KeyPools()
+		require.NoError(t, err)
+		for _, pool := range pools {
+			if pool.Scope == waddrmgr.KeyScopeBIP0084 {
+				return pool.PooledExternal, pool.PooledInternal
+			}
+		}
+		t.Fatal("no keypool for scope")
+		return 0, 0
+	}
+	external, internal := pooled()
+	require.Equal(t, uint32(3), external)
+	require.Equal(t, uint32(3), internal)
+
+	expected := func(index uint32) btcutil.Address {
+		for _, key := range keys {
+			if key.Branch != waddrmgr.ExternalBranch ||
+				key.Index != index {
+
+				continue
+			}
+			pubKey := key.PubKey.SerializeCompressed()
+			addr, err := btcutil.NewAddressWitnessPubKeyHash(
+				btcutil.Hash160(pubKey), w.ChainParams(),
+			)
+			require.NoError(t, err)
+			return addr
+		}
+		t.Fatalf("no pooled key %d", index)
+		return nil
+	}
+
+	addr, err := w.NewAddress(
+		waddrmgr.DefaultAccountNum, waddrmgr.KeyScopeBIP0084,
+	)
+	require.NoError(t, err)
+	require.Equal(t, expected(0).String(), addr.String())
+	privKey, err := w.PrivKeyForAddress(addr)
+	require.NoError(t, err)
+	require.True(t, privKey.PubKey().IsEqual(keys[0].PubKey))
+
+	// The address handed out while locked gets its private key once the
+	// wallet is unlocked again.
+	require.NoError(t, w.Manager.Lock())
+	addr, err = w.NewAddress(
+		waddrmgr.DefaultAccountNum, waddrmgr.KeyScopeBIP0084,
+	)
+	require.NoError(t, err)
+	require.Equal(t, expected(1).String(), addr.String())
+	err = walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
+		return w.Manager.Unlock(addrmgrNs, []byte("hello world"))
+	})
+	require.NoError(t, err)
+	_, err = w.PrivKeyForAddress(addr)
+	require.NoError(t, err)
+
+	external, internal = pooled()
+	require.Equal(t, uint32(1), external)
+	require.Equal(t, uint32(3), internal)
+}
@@ -0,0 +1,236 @@
+package wallet
+
+import (
+	"errors"
+	"time"
+
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/internal/walletdat"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/walletdb"
+)
+
+// ErrLbrycrdLocked is returned when importing the keys of an encrypted
+// lbrycrd wallet which has not been unlocked.
+var ErrLbrycrdLocked = errors.New("the lbrycrd wallet is encrypted and " +
+	"must be unlocked with its passphrase")
+
+// LbrycrdChannel is a channel claimed by a key of an lbrycrd wallet, found in
+// the transactions of the wallet.  Its current claim or update output is
+// signed by the imported key of its address.
+type LbrycrdChannel struct {
+	ClaimID  string
+	Name     string
+	Address  btcutil.Address
+	OutPoint wire.OutPoint
+}
+
+// LbrycrdImport describes the keys, labels and channels imported from an
+// lbrycrd wallet.
+type LbrycrdImport struct {
+	// Keys is the number of keys imported, not counting the keys already
+	// in the wallet.
+	Keys int
+
+	// Labels is the number of address labels which became imported-key
+	// accounts.
+	Labels   int
+	Channels []LbrycrdChannel
+
+	// BlockStamp is the block the rescan of the imported addresses starts
+	// from, which is the birthday block of the oldest key.
+	BlockStamp waddrmgr.BlockStamp
+}
+
+// ImportLbrycrdWallet imports the private keys of a wallet.dat file of
+// lbrycrd, which must be unlocked when encrypted, as legacy imported keys.
+// The labels of the address book entries of the keys become the names of
+// their imported-key accounts, skipping labels which are not valid account
+// names, and the channel claims of the keys are reported.  The rescan of the
+// imported addresses, when requested, starts from the block of the creation
+// time of the oldest key, or from the genesis block when a key has no
+// creation time or the chain backend is unavailable.
+func (w *Wallet) ImportLbrycrdWallet(dat *walletdat.Wallet,
+	rescan bool) (*LbrycrdImport, error) {
+
+	var oldest time.Time
+	for _, k := range dat.Keys {
+		if k.PrivKey == nil {
+			return nil, ErrLbrycrdLocked
+		}
+		if k.CreateTime.IsZero() {
+			oldest = time.Time{}
+			break
+		}
+		if oldest.IsZero() || k.CreateTime.Before(oldest) {
+			oldest = k.CreateTime
+		}
+	}
+
+	bs := &waddrmgr.BlockStamp{
+		Hash:      *w.chainParams.GenesisHash,
+		Height:    0,
+		Timestamp: w.chainParams.GenesisBlock.Header.Timestamp,
+	}
+	if chainClient, err := w.requireChainClient(); err == nil &&
+		!oldest.IsZero() {
+
+		// Keys are created before they are first paid to, so the
+		// located block can only be early by the margin of the lookup.
+		stamp, err := locateBirthdayBlock(
+			chainClient, oldest.Add(-birthdayBlockDelta),
+		)
+		if err != nil {
+			return nil, err
+		}
+		bs = stamp
+	}
+
+	scopedMgr, err := w.Manager.FetchScopedKeyManager(
+		waddrmgr.KeyScopeBIP0044,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &LbrycrdImport{BlockStamp: *bs}
+	addrs := make(map[string]btcutil.Address, len(dat.Keys))
+	err = walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		addrmgrNs := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		for _, k := range dat.Keys {
+			wif, err := btcutil.NewWIF(k.PrivKey, w.chainParams,
+				k.Compressed)
+			if err != nil {
+				return err
+			}
+			_, err = scopedMgr.ImportPrivateKey(addrmgrNs, wif, bs)
+			switch {
+			case err == nil:
+				res.Keys++
+			case !waddrmgr.IsError(err, waddrmgr.ErrDuplicateAddress):
+				return err
+			}
+			addr, err := btcutil.NewAddressPubKeyHash(
+				btcutil.Hash160(wif.SerializePubKey()),
+				w.chainParams,
+			)
+			if err != nil {
+				return err
+			}
+			addrs[addr.EncodeAddress()] = addr
+		}
+
+		ns := tx.ReadWriteBucket(walletNamespaceKey)
+		for addr, label := range dat.Labels {
+			if _, ok := addrs[addr]; !ok || label == "" ||
+				label == waddrmgr.ImportedAddrAccountName {
+
+				continue
+			}
+			err := w.validImportedAccountName(addrmgrNs, label)
+			if err != nil {
+				log.Warnf("Skipping the lbrycrd label %q of %s: %v",
+					label, addr, err)
+				continue
+			}
+			_, acct, err := w.Manager.AddrAccount(
+				addrmgrNs, addrs[addr],
+			)
+			if err != nil {
+				return err
+			}
+			if acct != waddrmgr.ImportedAddrAccount {
+				continue
+			}
+			accts, err := ns.CreateBucketIfNotExists(
+				bucketImportedAccounts,
+			)
+			if err != nil {
+				return err
+			}
+			if err := accts.Put([]byte(addr), []byte(label)); err != nil {
+				return err
+			}
+			res.Labels++
+		}
+
+		return w.moveBirthdayBack(addrmgrNs, bs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res.Channels = w.lbrycrdChannels(dat.Transactions, addrs)
+
+	if rescan {
+		job := &RescanJob{BlockStamp: *bs}
+		for _, addr := range addrs {
+			job.Addrs = append(job.Addrs, addr)
+		}
+		_ = w.SubmitRescan(job)
+	}
+	return res, nil
+}
+
+// moveBirthdayBack sets the birthday block of the wallet to the block of
+// imported keys when it is earlier.  As for imported private keys, the birthday
+// is only moved back, and the birthday block is checked at the next start.
+func (w *Wallet) moveBirthdayBack(addrmgrNs walletdb.ReadWriteBucket,
+	bs *waddrmgr.BlockStamp) error {
+
+	birthdayBlock, _, err := w.Manager.BirthdayBlock(addrmgrNs)
+	if err == nil && birthdayBlock.Height <= bs.Height {
+		return nil
+	}
+	err = w.Manager.SetBirthday(addrmgrNs, bs.Timestamp)
+	if err != nil {
+		return err
+	}
+	return w.Manager.SetBirthdayBlock(addrmgrNs, *bs, false)
+}
+
+// lbrycrdChannels returns the unspent channel claims and updates of the
+// transactions of an lbrycrd wallet which pay to its addresses.
+func (w *Wallet) lbrycrdChannels(txs []*wire.MsgTx,
+	addrs map[string]btcutil.Address) []LbrycrdChannel {
+
+	spent := make(map[wire.OutPoint]bool)
+	for _, tx := range txs {
+		for _, in := range tx.TxIn {
+			spent[in.PreviousOutPoint] = true
+		}
+	}
+
+	var channels []LbrycrdChannel
+	for _, tx := range txs {
+		hash := tx.TxHash()
+		for i, out := range tx.TxOut {
+			op := wire.OutPoint{Hash: hash, Index: uint32(i)}
+			if spent[op] {
+				continue
+			}
+			c, ok := decodeClaimOutput(op, out.PkScript)
+			if !ok || c.Op == "support" || c.Kind != "channel" {
+				continue
+			}
+			_, outAddrs, _, err := txscript.ExtractPkScriptAddrs(
+				out.PkScript, w.chainParams)
+			if err != nil || len(outAddrs) == 0 {
+				continue
+			}
+			addr, ok := addrs[outAddrs[0].EncodeAddress()]
+			if !ok {
+				continue
+			}
+			channels = append(channels, LbrycrdChannel{
+				ClaimID:  c.ClaimID,
+				Name:     c.Name,
+				Address:  addr,
+				OutPoint: op,
+			})
+		}
+	}
+	return channels
+}
@@ -0,0 +1,113 @@
+package wallet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lbryio/lbcd/btcec"
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/internal/walletdat"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/stretchr/testify/require"
+)
+
+// TestImportLbrycrdWallet checks that the keys of an lbrycrd wallet are
+// imported with their labels as imported-key accounts, and that its channels
+// are found.
+func TestImportLbrycrdWallet(t *testing.T) {
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	// The second key predates key metadata, so the block stamp of the
+	// keys is the genesis block.
+	created := []time.Time{time.Unix(1500000000, 0), {}}
+	var keys []*walletdat.Key
+	var addrs []btcutil.Address
+	for i, compressed := range []bool{true, false} {
+		privKey, err := btcec.NewPrivateKey(btcec.S256())
+		require.NoError(t, err)
+		pubKey := privKey.PubKey().SerializeUncompressed()
+		if compressed {
+			pubKey = privKey.PubKey().SerializeCompressed()
+		}
+		addr, err := btcutil.NewAddressPubKeyHash(
+			btcutil.Hash160(pubKey), w.ChainParams())
+		require.NoError(t, err)
+
+		keys = append(keys, &walletdat.Key{
+			PubKey:     privKey.PubKey(),
+			Compressed: compressed,
+			PrivKey:    privKey,
+			CreateTime: created[i],
+		})
+		addrs = append(addrs, addr)
+	}
+
+	// Two channel claims paying to the first key, the second of which was
+	// spent.
+	pkScript, err := txscript.PayToAddrScript(addrs[0])
+	require.NoError(t, err)
+	channelScript, err := txscript.ClaimNameScript("@channel",
+		string([]byte{0x00, 0x12, 0x00}))
+	require.NoError(t, err)
+	channelScript = append(channelScript[:len(channelScript)-1],
+		pkScript...)
+	claimTx := &wire.MsgTx{
+		TxIn: []*wire.TxIn{{}},
+		TxOut: []*wire.TxOut{
+			wire.NewTxOut(1e7, channelScript),
+			wire.NewTxOut(1e7, channelScript),
+		},
+	}
+	spendTx := &wire.MsgTx{
+		TxIn: []*wire.TxIn{{PreviousOutPoint: wire.OutPoint{
+			Hash:  claimTx.TxHash(),
+			Index: 1,
+		}}},
+		TxOut: []*wire.TxOut{wire.NewTxOut(1e7, pkScript)},
+	}
+
+	dat := &walletdat.Wallet{
+		Keys: keys,
+		Labels: map[string]string{
+			addrs[0].EncodeAddress(): "creator",
+			addrs[1].EncodeAddress(): "default",
+			"bNotOurs":               "friend",
+		},
+		Transactions: []*wire.MsgTx{claimTx, spendTx},
+	}
+
+	res, err := w.ImportLbrycrdWallet(dat, false)
+	require.NoError(t, err)
+	require.Equal(t, 2, res.Keys)
+	require.Equal(t, 1, res.Labels)
+	require.Equal(t, int32(0), res.BlockStamp.Height)
+	require.Len(t, res.Channels, 1)
+	require.Equal(t, "@channel", res.Channels[0].Name)
+	require.Equal(t, wire.OutPoint{Hash: claimTx.TxHash()},
+		res.Channels[0].OutPoint)
+
+	for _, addr := range addrs {
+		_, err := w.DumpWIFPrivateKey(addr)
+		require.NoError(t, err)
+	}
+	accts, err := w.ImportedAccounts()
+	require.NoError(t, err)
+	require.Len(t, accts, 2)
+	require.Equal(t, "creator", accts[0].Name)
+	require.Equal(t, addrs[0].EncodeAddress(),
+		accts[0].Addresses[0].EncodeAddress())
+	require.Equal(t, waddrmgr.ImportedAddrAccountName, accts[1].Name)
+
+	// Importing again imports no new keys.
+	res, err = w.ImportLbrycrdWallet(dat, false)
+	require.NoError(t, err)
+	require.Zero(t, res.Keys)
+
+	// The keys of an encrypted wallet must be decrypted first.
+	dat.Keys[0].PrivKey = nil
+	_, err = w.ImportLbrycrdWallet(dat, false)
+	require.ErrorIs(t, err, ErrLbrycrdLocked)
+}
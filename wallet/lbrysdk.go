@@ -0,0 +1,177 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcutil/hdkeychain"
+	"github.com/lbryio/lbcwallet/internal/lbrysdk"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/walletdb"
+)
+
+// ErrLbrySDKEncrypted is returned when importing an lbry-sdk wallet whose
+// accounts have not been decrypted.
+var ErrLbrySDKEncrypted = errors.New("the lbry-sdk wallet is encrypted and " +
+	"must be decrypted with its password")
+
+// LbrySDKChannel is a channel whose certificate, the private key signing the
+// claims of the channel, was imported from an lbry-sdk wallet.
+type LbrySDKChannel struct {
+	// ID is the key of the certificate in the wallet, which is the claim
+	// ID of the channel, or the address of its key in later versions of
+	// lbry-sdk.
+	ID string
+
+	// Address is the address of the imported key.
+	Address btcutil.Address
+}
+
+// LbrySDKImport describes the accounts and channels imported from an lbry-sdk
+// wallet.
+type LbrySDKImport struct {
+	Accounts []*waddrmgr.AccountProperties
+	Channels []LbrySDKChannel
+}
+
+// ImportLbrySDKWallet imports the accounts of an lbry-sdk wallet, which must be
+// decrypted when encrypted, as legacy accounts of the wallet named after them,
+// and the certificates of their channels as imported keys.  lbry-sdk accounts
+// are master keys rather than BIP0044 account keys, but derive their
+// addresses from their receiving and change chains as accounts do.  The first
+// lookahead addresses of both branches of the accounts are derived, and the
+// blockchain is rescanned from the genesis block when requested, as the
+// accounts record no birthday.
+func (w *Wallet) ImportLbrySDKWallet(sdk *lbrysdk.Wallet, lookahead uint32,
+	rescan bool) (*LbrySDKImport, error) {
+
+	if sdk.Encrypted() {
+		return nil, ErrLbrySDKEncrypted
+	}
+
+	type channelKey struct {
+		id  string
+		wif *btcutil.WIF
+	}
+	keys := make([]*hdkeychain.ExtendedKey, len(sdk.Accounts))
+	var channels []channelKey
+	for i, a := range sdk.Accounts {
+		if a.Net().Name != w.chainParams.Name {
+			return nil, fmt.Errorf("account %d is for %s", i,
+				a.Net().Name)
+		}
+		if a.AddressGenerator.Name != lbrysdk.DeterministicChain {
+			return nil, fmt.Errorf("account %d: unsupported address "+
+				"generator %q", i, a.AddressGenerator.Name)
+		}
+		key, err := a.Key()
+		if err != nil {
+			return nil, fmt.Errorf("account %d: %w", i, err)
+		}
+		keys[i] = key
+
+		for id, certificate := range a.Certificates {
+			privKey, err := lbrysdk.ChannelKey(certificate)
+			if err != nil {
+				return nil, fmt.Errorf("certificate %s: %w", id, err)
+			}
+			wif, err := btcutil.NewWIF(privKey, w.chainParams, true)
+			if err != nil {
+				return nil, err
+			}
+			channels = append(channels, channelKey{id, wif})
+		}
+	}
+
+	scopedMgr, err := w.Manager.FetchScopedKeyManager(
+		waddrmgr.KeyScopeBIP0044,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	bs := &waddrmgr.BlockStamp{
+		Hash:      *w.chainParams.GenesisHash,
+		Height:    0,
+		Timestamp: w.chainParams.GenesisBlock.Header.Timestamp,
+	}
+	res := new(LbrySDKImport)
+	var addrs []btcutil.Address
+	err = walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		for i, a := range sdk.Accounts {
+			name := a.Name
+			if name == "" {
+				name = fmt.Sprintf("lbry-sdk-%d", i)
+			}
+			account, err := scopedMgr.NewAccountFromKey(
+				ns, name, keys[i],
+			)
+			if err != nil {
+				return fmt.Errorf("account %d: %w", i, err)
+			}
+			if lookahead > 0 {
+				for _, branch := range []uint32{
+					waddrmgr.ExternalBranch,
+					waddrmgr.InternalBranch,
+				} {
+					err := scopedMgr.ExtendAddresses(
+						ns, account, branch, lookahead-1,
+					)
+					if err != nil {
+						return err
+					}
+				}
+			}
+			props, err := scopedMgr.AccountProperties(ns, account)
+			if err != nil {
+				return err
+			}
+			res.Accounts = append(res.Accounts, props)
+
+			err = scopedMgr.ForEachAccountAddress(ns, account,
+				func(maddr waddrmgr.ManagedAddress) error {
+					addrs = append(addrs, maddr.Address())
+					return nil
+				})
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, c := range channels {
+			_, err := scopedMgr.ImportPrivateKey(ns, c.wif, bs)
+			if err != nil && !waddrmgr.IsError(err,
+				waddrmgr.ErrDuplicateAddress) {
+
+				return err
+			}
+			addr, err := btcutil.NewAddressPubKeyHash(
+				btcutil.Hash160(c.wif.SerializePubKey()),
+				w.chainParams,
+			)
+			if err != nil {
+				return err
+			}
+			res.Channels = append(res.Channels, LbrySDKChannel{
+				ID:      c.id,
+				Address: addr,
+			})
+			addrs = append(addrs, addr)
+		}
+
+		return w.moveBirthdayBack(ns, bs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if rescan {
+		_ = w.SubmitRescan(&RescanJob{
+			Addrs:      addrs,
+			BlockStamp: *bs,
+		})
+	}
+	return res, nil
+}
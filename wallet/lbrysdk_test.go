@@ -0,0 +1,97 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"encoding/pem"
+	"testing"
+
+	"github.com/lbryio/lbcd/btcec"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcutil/hdkeychain"
+	"github.com/lbryio/lbcwallet/internal/lbrysdk"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/stretchr/testify/require"
+)
+
+// TestImportLbrySDKWallet checks that the accounts of an lbry-sdk wallet derive
+// its addresses with their private keys, and that the keys of its channels
+// are imported.
+func TestImportLbrySDKWallet(t *testing.T) {
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	master, err := hdkeychain.NewMaster(
+		bytes.Repeat([]byte{1}, 32), w.ChainParams(),
+	)
+	require.NoError(t, err)
+	receiving, err := master.Derive(0)
+	require.NoError(t, err)
+	first, err := receiving.Derive(0)
+	require.NoError(t, err)
+	firstAddr, err := first.Address(w.ChainParams())
+	require.NoError(t, err)
+
+	channelKey, err := btcec.NewPrivateKey(btcec.S256())
+	require.NoError(t, err)
+	der, err := asn1.Marshal(struct {
+		Version    int
+		PrivateKey []byte
+	}{1, channelKey.Serialize()})
+	require.NoError(t, err)
+	certificate := pem.EncodeToMemory(&pem.Block{
+		Type:  "EC PRIVATE KEY",
+		Bytes: der,
+	})
+
+	sdk := &lbrysdk.Wallet{
+		Version: lbrysdk.Version,
+		Accounts: []*lbrysdk.Account{{
+			Ledger:     "lbc_testnet",
+			Name:       "Creator Account",
+			PrivateKey: master.String(),
+			AddressGenerator: lbrysdk.AddressGenerator{
+				Name: lbrysdk.DeterministicChain,
+			},
+			Certificates: map[string]string{
+				"abcdef": string(certificate),
+			},
+		}},
+	}
+
+	res, err := w.ImportLbrySDKWallet(sdk, 5, false)
+	require.NoError(t, err)
+	require.Len(t, res.Accounts, 1)
+	props := res.Accounts[0]
+	require.Equal(t, "Creator Account", props.AccountName)
+	require.Equal(t, uint32(5), props.ExternalKeyCount)
+	require.Equal(t, uint32(5), props.InternalKeyCount)
+
+	// The first receiving address of the account is that of lbry-sdk, and
+	// its private key is available.
+	info, err := w.AddressInfo(firstAddr)
+	require.NoError(t, err)
+	require.Equal(t, props.AccountNumber, info.InternalAccount())
+	wif, err := w.DumpWIFPrivateKey(firstAddr)
+	require.NoError(t, err)
+	firstKey, err := first.ECPrivKey()
+	require.NoError(t, err)
+	wantWIF, err := btcutil.NewWIF(firstKey, w.ChainParams(), true)
+	require.NoError(t, err)
+	require.Equal(t, wantWIF.String(), wif)
+
+	require.Len(t, res.Channels, 1)
+	require.Equal(t, "abcdef", res.Channels[0].ID)
+	_, err = w.DumpWIFPrivateKey(res.Channels[0].Address)
+	require.NoError(t, err)
+
+	// Accounts are not imported twice.
+	_, err = w.ImportLbrySDKWallet(sdk, 5, false)
+	var managerErr waddrmgr.ManagerError
+	require.ErrorAs(t, err, &managerErr)
+	require.Equal(t, waddrmgr.ErrDuplicateAccount, managerErr.ErrorCode)
+
+	sdk.Accounts[0].Encrypted = true
+	_, err = w.ImportLbrySDKWallet(sdk, 5, false)
+	require.ErrorIs(t, err, ErrLbrySDKEncrypted)
+}
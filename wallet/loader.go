@@ -14,6 +14,7 @@ import (
 
 	"github.com/lbryio/lbcd/chaincfg"
 	"github.com/lbryio/lbcutil/hdkeychain"
+	"github.com/lbryio/lbcwallet/waddrmgr"
 	"github.com/lbryio/lbcwallet/walletdb"
 )
 
@@ -59,6 +60,7 @@ type Loader struct {
 	walletExists   func() (bool, error)
 	walletCreated  func(db walletdb.ReadWriteTx) error
 	db             walletdb.DB
+	pubPassphrase  []byte
 	mu             sync.Mutex
 }
 
@@ -103,6 +105,15 @@ func NewLoaderWithDB(chainParams *chaincfg.Params, recoveryWindow uint32,
 	}, nil
 }
 
+// SetPublicPassphrase sets the public passphrase used to open existing
+// wallets.  It is only needed when the public passphrase of the wallet has
+// been changed from the default.
+func (l *Loader) SetPublicPassphrase(pubPassphrase []byte) {
+	l.mu.Lock()
+	l.pubPassphrase = pubPassphrase
+	l.mu.Unlock()
+}
+
 // onLoaded executes each added callback and prevents loader from loading any
 // additional wallets.  Requires mutex to be locked.
 func (l *Loader) onLoaded(w *Wallet) {
@@ -263,7 +274,13 @@ func (l *Loader) OpenExistingWallet() (*Wallet, error) {
 		}
 	}
 
-	w, err := Open(l.db, l.chainParams, l.recoveryWindow)
+	pubPassphrase := l.pubPassphrase
+	if pubPassphrase == nil {
+		pubPassphrase = []byte(waddrmgr.DefaultPublicPassphrase)
+	}
+	w, err := OpenWithPublicPassphrase(
+		l.db, pubPassphrase, l.chainParams, l.recoveryWindow,
+	)
 	if err != nil {
 		// If opening the wallet fails (e.g. because of wrong
 		// passphrase), we must close the backing database to
@@ -0,0 +1,99 @@
+package wallet
+
+import (
+	"github.com/lbryio/lbcwallet/walletdb"
+	"github.com/lbryio/lbcwallet/wtxmgr"
+)
+
+// MinConfPolicy requires the outputs of the wallet to have a number of
+// confirmations before coin selection spends them, depending on the
+// transaction they come from.  The requirements apply on top of the minimum
+// number of confirmations of each request, and zero adds none.
+type MinConfPolicy struct {
+	// Coinbase applies to the outputs of coinbase transactions, which
+	// must also have reached maturity.
+	Coinbase int32
+
+	// Claim applies to the outputs of transactions creating, updating or
+	// spending claims and supports, such as the change of a claim or the
+	// value of an abandoned one.
+	Claim int32
+
+	// Change applies to the outputs of the other transactions spending
+	// only outputs of the wallet, such as their change.
+	Change int32
+
+	// External applies to the outputs received from the transactions of
+	// others.
+	External int32
+}
+
+// SetMinConfPolicy sets the policy for the confirmations of the outputs spent
+// by the transactions created from now on.
+func (w *Wallet) SetMinConfPolicy(policy MinConfPolicy) {
+	w.minConfPolicyMtx.Lock()
+	w.minConfPolicy = policy
+	w.minConfPolicyMtx.Unlock()
+}
+
+// MinConfPolicy returns the policy for the confirmations of spent outputs set
+// by SetMinConfPolicy.
+func (w *Wallet) MinConfPolicy() MinConfPolicy {
+	w.minConfPolicyMtx.Lock()
+	defer w.minConfPolicyMtx.Unlock()
+	return w.minConfPolicy
+}
+
+// requiredConfs returns the number of confirmations the policy requires of an
+// unspent output of the wallet before it is spent.
+func (w *Wallet) requiredConfs(txmgrNs walletdb.ReadBucket,
+	policy *MinConfPolicy, output *wtxmgr.Credit) (int32, error) {
+
+	if output.FromCoinBase {
+		return policy.Coinbase, nil
+	}
+	if policy.Claim == 0 && policy.Change == 0 && policy.External == 0 {
+		return 0, nil
+	}
+
+	details, err := w.TxStore.TxDetails(txmgrNs, &output.OutPoint.Hash)
+	if err != nil || details == nil {
+		return policy.External, err
+	}
+	claim, err := w.isClaimTx(txmgrNs, details)
+	switch {
+	case err != nil:
+		return 0, err
+	case claim:
+		return policy.Claim, nil
+	case len(details.Debits) == len(details.MsgTx.TxIn):
+		return policy.Change, nil
+	}
+	return policy.External, nil
+}
+
+// isClaimTx returns whether a transaction has a claim, support or claim update
+// output, or spends one of the wallet.
+func (w *Wallet) isClaimTx(txmgrNs walletdb.ReadBucket,
+	details *wtxmgr.TxDetails) (bool, error) {
+
+	for _, txOut := range details.MsgTx.TxOut {
+		if isStake(txOut.PkScript) {
+			return true, nil
+		}
+	}
+	for _, debit := range details.Debits {
+		prevOut := details.MsgTx.TxIn[debit.Index].PreviousOutPoint
+		prev, err := w.TxStore.TxDetails(txmgrNs, &prevOut.Hash)
+		if err != nil {
+			return false, err
+		}
+		if prev == nil || int(prevOut.Index) >= len(prev.MsgTx.TxOut) {
+			continue
+		}
+		if isStake(prev.MsgTx.TxOut[prevOut.Index].PkScript) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,115 @@
+package wallet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/walletdb"
+	"github.com/lbryio/lbcwallet/wtxmgr"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMinConfPolicy checks that coin selection only spends the outputs with
+// the confirmations the policy requires of their source.
+func TestMinConfPolicy(t *testing.T) {
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	addr, err := w.CurrentAddress(0, waddrmgr.KeyScopeBIP0084)
+	require.NoError(t, err)
+	pkScript, err := txscript.PayToAddrScript(addr)
+	require.NoError(t, err)
+
+	// insertTx records a transaction mined at a height, whose outputs
+	// paying to the wallet are the last ones.
+	insertTx := func(tx *wire.MsgTx, height int32, credits int) {
+		rec, err := wtxmgr.NewTxRecordFromMsgTx(tx, time.Now())
+		require.NoError(t, err)
+		block := &wtxmgr.BlockMeta{
+			Block: wtxmgr.Block{Hash: chainhash.Hash{byte(height)},
+				Height: height},
+			Time: time.Now(),
+		}
+		err = walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+			ns := tx.ReadWriteBucket(wtxmgrNamespaceKey)
+			err := w.TxStore.InsertTx(ns, rec, block)
+			if err != nil {
+				return err
+			}
+			n := len(rec.MsgTx.TxOut)
+			for i := n - credits; i < n; i++ {
+				err := w.TxStore.AddCredit(
+					ns, rec, block, uint32(i), false,
+				)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		require.NoError(t, err)
+	}
+
+	// The external transaction has 6 confirmations, the transfer of its
+	// second output 3 and the claim 2.
+	const tip = 100
+	external := &wire.MsgTx{
+		TxIn: []*wire.TxIn{{}},
+		TxOut: []*wire.TxOut{
+			wire.NewTxOut(100000, pkScript),
+			wire.NewTxOut(200000, pkScript),
+		},
+	}
+	insertTx(external, tip-5, 2)
+	transfer := &wire.MsgTx{
+		TxIn: []*wire.TxIn{{PreviousOutPoint: wire.OutPoint{
+			Hash: external.TxHash(), Index: 1,
+		}}},
+		TxOut: []*wire.TxOut{wire.NewTxOut(190000, pkScript)},
+	}
+	insertTx(transfer, tip-2, 1)
+	claimScript, err := txscript.ClaimNameScript("name", "value")
+	require.NoError(t, err)
+	claim := &wire.MsgTx{
+		TxIn: []*wire.TxIn{{PreviousOutPoint: wire.OutPoint{Index: 1}}},
+		TxOut: []*wire.TxOut{
+			wire.NewTxOut(1000, append(claimScript, pkScript...)),
+			wire.NewTxOut(50000, pkScript),
+		},
+	}
+	insertTx(claim, tip-1, 1)
+
+	eligible := func(policy MinConfPolicy) []chainhash.Hash {
+		w.SetMinConfPolicy(policy)
+		var hashes []chainhash.Hash
+		err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+			bs := waddrmgr.BlockStamp{Height: tip}
+			credits, err := w.findEligibleOutputs(
+				tx, nil, 0, 0, &bs,
+			)
+			for _, c := range credits {
+				hashes = append(hashes, c.OutPoint.Hash)
+			}
+			return err
+		})
+		require.NoError(t, err)
+		return hashes
+	}
+
+	require.ElementsMatch(t, []chainhash.Hash{
+		external.TxHash(), transfer.TxHash(), claim.TxHash(),
+	}, eligible(MinConfPolicy{}))
+	require.ElementsMatch(t, []chainhash.Hash{
+		transfer.TxHash(), claim.TxHash(),
+	}, eligible(MinConfPolicy{External: 7}))
+	require.ElementsMatch(t, []chainhash.Hash{
+		external.TxHash(), claim.TxHash(),
+	}, eligible(MinConfPolicy{Change: 4}))
+	require.ElementsMatch(t, []chainhash.Hash{
+		external.TxHash(), transfer.TxHash(),
+	}, eligible(MinConfPolicy{Claim: 3, Change: 3, External: 6}))
+}
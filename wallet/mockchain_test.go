@@ -0,0 +1,65 @@
+package wallet
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/lbryio/lbcd/chaincfg"
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/chain"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSyncMockChain checks that a wallet synced to the mock chain backend
+// records a payment once mined, and follows it when reorged into another
+// block.
+func TestSyncMockChain(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test_mockchain")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	params := &chaincfg.RegressionNetParams
+	loader := NewLoader(params, dir, true, defaultDBTimeout, 250)
+	birthday := params.GenesisBlock.Header.Timestamp
+	w, err := loader.CreateNewWallet(
+		[]byte("password"), FixtureSeed, birthday,
+	)
+	require.NoError(t, err)
+	defer loader.UnloadWallet()
+
+	backend := chain.NewMockInterface(params)
+	require.NoError(t, backend.Start())
+	defer backend.Stop()
+	w.Start()
+	w.SynchronizeRPC(backend)
+	backend.MineBlock()
+	require.NoError(t, w.WaitForHeight(1, 5*time.Second))
+
+	addr, err := w.NewAddress(0, waddrmgr.KeyScopeBIP0044)
+	require.NoError(t, err)
+	pkScript, err := txscript.PayToAddrScript(addr)
+	require.NoError(t, err)
+	pay := wire.NewMsgTx(wire.TxVersion)
+	pay.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 1}, nil, nil))
+	pay.AddTxOut(wire.NewTxOut(1e8, pkScript))
+	block := backend.MineBlock(pay)
+	require.NoError(t, w.WaitForHeight(2, 5*time.Second))
+
+	balance, _, err := w.CalculateBalance(1)
+	require.NoError(t, err)
+	require.Equal(t, btcutil.Amount(1e8), balance)
+
+	// The payment is unmined by the disconnect, and mined again by the
+	// new branch.
+	require.NoError(t, backend.Reorg(1))
+	require.NoError(t, w.WaitForHeight(3, 5*time.Second))
+	require.NotEqual(t, block.BlockHash(), w.Manager.SyncedTo().Hash)
+	balance, _, err = w.CalculateBalance(2)
+	require.NoError(t, err)
+	require.Equal(t, btcutil.Amount(1e8), balance)
+}
@@ -0,0 +1,155 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/walletdb"
+)
+
+// bucketMoves is the name of the sub bucket of the wallet namespace that maps
+// the IDs of balance moves to serialized moves.
+var bucketMoves = []byte("moves")
+
+// BalanceMove is a bookkeeping transfer of value between two accounts.  No
+// transaction is created, and the outputs of both accounts are unchanged, so
+// a move only affects the ledger balances of the accounts.  Moves are never
+// removed, and serve as the audit trail of the ledger.
+type BalanceMove struct {
+	ID          uint64
+	Time        time.Time
+	FromAccount uint32
+	ToAccount   uint32
+	Amount      btcutil.Amount
+	Comment     string
+}
+
+// moveKey returns the key of a move in the moves bucket.
+func moveKey(id uint64) []byte {
+	var k [8]byte
+	binary.BigEndian.PutUint64(k[:], id)
+	return k[:]
+}
+
+// serializeMove returns the serialization of a move, without its ID which is
+// its key:
+//
+//	[0:8]   time in nanoseconds since the epoch (8 bytes)
+//	[8:12]  from account (4 bytes)
+//	[12:16] to account (4 bytes)
+//	[16:24] amount (8 bytes)
+//	[24:]   comment as a varstring
+func serializeMove(m *BalanceMove) ([]byte, error) {
+	var buf bytes.Buffer
+	var fixed [24]byte
+	binary.BigEndian.PutUint64(fixed[0:8], uint64(m.Time.UnixNano()))
+	binary.BigEndian.PutUint32(fixed[8:12], m.FromAccount)
+	binary.BigEndian.PutUint32(fixed[12:16], m.ToAccount)
+	binary.BigEndian.PutUint64(fixed[16:24], uint64(m.Amount))
+	buf.Write(fixed[:])
+	if err := wire.WriteVarString(&buf, 0, m.Comment); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// deserializeMove decodes a move serialized by serializeMove.
+func deserializeMove(k, v []byte) (*BalanceMove, error) {
+	if len(k) != 8 || len(v) < 24 {
+		return nil, fmt.Errorf("short balance move: %d bytes", len(v))
+	}
+	m := &BalanceMove{
+		ID:          binary.BigEndian.Uint64(k),
+		Time:        time.Unix(0, int64(binary.BigEndian.Uint64(v[0:8]))),
+		FromAccount: binary.BigEndian.Uint32(v[8:12]),
+		ToAccount:   binary.BigEndian.Uint32(v[12:16]),
+		Amount:      btcutil.Amount(binary.BigEndian.Uint64(v[16:24])),
+	}
+	comment, err := wire.ReadVarString(bytes.NewReader(v[24:]), 0)
+	if err != nil {
+		return nil, err
+	}
+	m.Comment = comment
+	return m, nil
+}
+
+// forEachMove calls f with every move in the order they were recorded.
+func forEachMove(ns walletdb.ReadBucket, f func(*BalanceMove) error) error {
+	moves := ns.NestedReadBucket(bucketMoves)
+	if moves == nil {
+		return nil
+	}
+	return moves.ForEach(func(k, v []byte) error {
+		m, err := deserializeMove(k, v)
+		if err != nil {
+			return err
+		}
+		return f(m)
+	})
+}
+
+// MoveBalance records a move of amount from the ledger balance of one account
+// to another.  As with the move command of Bitcoin Core, the ledger balance of
+// the source account may become negative.
+func (w *Wallet) MoveBalance(from, to uint32, amount btcutil.Amount,
+	comment string) (*BalanceMove, error) {
+
+	if amount <= 0 {
+		return nil, errors.New("moved amount must be positive")
+	}
+	if from == to {
+		return nil, errors.New("can not move a balance to the same " +
+			"account")
+	}
+	for _, acct := range []uint32{from, to} {
+		if _, err := w.AccountName(acct); err != nil {
+			return nil, err
+		}
+	}
+
+	m := &BalanceMove{
+		Time:        time.Now(),
+		FromAccount: from,
+		ToAccount:   to,
+		Amount:      amount,
+		Comment:     comment,
+	}
+	err := walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(walletNamespaceKey)
+		moves, err := ns.CreateBucketIfNotExists(bucketMoves)
+		if err != nil {
+			return err
+		}
+		if m.ID, err = moves.NextSequence(); err != nil {
+			return err
+		}
+		v, err := serializeMove(m)
+		if err != nil {
+			return err
+		}
+		return moves.Put(moveKey(m.ID), v)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// BalanceMoves returns the moves recorded by MoveBalance in the order they
+// were recorded.
+func (w *Wallet) BalanceMoves() ([]*BalanceMove, error) {
+	var moves []*BalanceMove
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		ns := tx.ReadBucket(walletNamespaceKey)
+		return forEachMove(ns, func(m *BalanceMove) error {
+			moves = append(moves, m)
+			return nil
+		})
+	})
+	return moves, err
+}
@@ -71,7 +71,8 @@ func (w *Wallet) MakeMultiSigScript(addrs []btcutil.Address, nRequired int) ([]b
 	return txscript.MultiSigScript(pubKeys, nRequired)
 }
 
-// ImportP2SHRedeemScript adds a P2SH redeem script to the wallet.
+// ImportP2SHRedeemScript adds a P2SH redeem script to the wallet, whose
+// address is watched by the backend from then on.
 func (w *Wallet) ImportP2SHRedeemScript(script []byte) (*btcutil.AddressScriptHash, error) {
 	var p2shAddr *btcutil.AddressScriptHash
 	err := walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
@@ -110,5 +111,8 @@ func (w *Wallet) ImportP2SHRedeemScript(script []byte) (*btcutil.AddressScriptHa
 		p2shAddr = addrInfo.Address().(*btcutil.AddressScriptHash)
 		return nil
 	})
-	return p2shAddr, err
+	if err != nil {
+		return nil, err
+	}
+	return p2shAddr, w.watchImportedAddress(p2shAddr, nil)
 }
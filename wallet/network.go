@@ -0,0 +1,82 @@
+package wallet
+
+import (
+	"fmt"
+
+	"github.com/lbryio/lbcd/chaincfg"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/walletdb"
+)
+
+// keyNetwork is the key of the wallet namespace under which the name of the
+// network the wallet was created for is stored.
+var keyNetwork = []byte("network")
+
+// ErrWrongNetwork is returned when opening a wallet database which was
+// created for a different network than the active one.
+type ErrWrongNetwork struct {
+	// DBNetwork is the name of the network recorded in the database.  It
+	// is empty when the network is not recorded but the keys of the
+	// wallet belong to another network.
+	DBNetwork string
+
+	// ActiveNetwork is the name of the network the wallet was opened on.
+	ActiveNetwork string
+}
+
+// Error returns a human readable description of the mismatch and how to
+// resolve it.
+func (e *ErrWrongNetwork) Error() string {
+	db := "a different network"
+	if e.DBNetwork != "" {
+		db = "the " + e.DBNetwork + " network"
+	}
+	return fmt.Sprintf("wallet database was created for %s and can not "+
+		"be opened on %s -- select the network of the wallet, or move "+
+		"the database to the data directory of that network", db,
+		e.ActiveNetwork)
+}
+
+// putWalletNetwork records the network of the wallet.
+func putWalletNetwork(ns walletdb.ReadWriteBucket,
+	params *chaincfg.Params) error {
+
+	return ns.Put(keyNetwork, []byte(params.Name))
+}
+
+// checkWalletNetwork returns an ErrWrongNetwork if the wallet records a
+// network other than params.  It returns false if the wallet does not
+// record its network, in which case inferWalletNetwork must be used once the
+// address manager is open.
+func checkWalletNetwork(ns walletdb.ReadBucket,
+	params *chaincfg.Params) (bool, error) {
+
+	name := ns.Get(keyNetwork)
+	if name == nil {
+		return false, nil
+	}
+	if string(name) != params.Name {
+		return true, &ErrWrongNetwork{
+			DBNetwork:     string(name),
+			ActiveNetwork: params.Name,
+		}
+	}
+	return true, nil
+}
+
+// inferWalletNetwork checks a wallet which does not record its network yet
+// using the network of its account keys, and then records the active network.
+func inferWalletNetwork(ns walletdb.ReadWriteBucket,
+	addrMgrNs walletdb.ReadBucket, addrMgr *waddrmgr.Manager,
+	params *chaincfg.Params) error {
+
+	forNet, err := addrMgr.IsForNet(addrMgrNs)
+	if err != nil {
+		return err
+	}
+	if !forNet {
+		return &ErrWrongNetwork{ActiveNetwork: params.Name}
+	}
+
+	return putWalletNetwork(ns, params)
+}
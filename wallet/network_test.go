@@ -0,0 +1,67 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/lbryio/lbcd/chaincfg"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/walletdb"
+)
+
+// TestWalletNetwork ensures a wallet can not be opened on a network other
+// than the one it was created for, whether or not the network is recorded.
+func TestWalletNetwork(t *testing.T) {
+	t.Parallel()
+
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	pubPass := []byte(waddrmgr.DefaultPublicPassphrase)
+	_, err := OpenWithPublicPassphrase(
+		w.db, pubPass, &chaincfg.MainNetParams, 0,
+	)
+	netErr, ok := err.(*ErrWrongNetwork)
+	if !ok {
+		t.Fatalf("expected ErrWrongNetwork, got %v", err)
+	}
+	if netErr.DBNetwork != chaincfg.TestNet3Params.Name {
+		t.Fatalf("expected database network %v, got %v",
+			chaincfg.TestNet3Params.Name, netErr.DBNetwork)
+	}
+
+	// Remove the record to simulate a database created before the network
+	// was recorded.  The network must then be inferred from the keys.
+	err = walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		return tx.ReadWriteBucket(walletNamespaceKey).Delete(keyNetwork)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = OpenWithPublicPassphrase(
+		w.db, pubPass, &chaincfg.MainNetParams, 0,
+	)
+	if _, ok := err.(*ErrWrongNetwork); !ok {
+		t.Fatalf("expected ErrWrongNetwork, got %v", err)
+	}
+
+	// Opening on the right network records it again.
+	_, err = OpenWithPublicPassphrase(
+		w.db, pubPass, &chaincfg.TestNet3Params, 0,
+	)
+	if err != nil {
+		t.Fatalf("unable to open wallet: %v", err)
+	}
+	err = walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		recorded, err := checkWalletNetwork(
+			tx.ReadBucket(walletNamespaceKey),
+			&chaincfg.TestNet3Params,
+		)
+		if !recorded {
+			t.Fatal("network was not recorded")
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -35,6 +35,8 @@ type NotificationServer struct {
 	currentTxNtfn  *TransactionNotifications // coalesce this since wallet does not add mined txs together
 	spentness      map[uint32][]chan *SpentnessNotifications
 	accountClients []chan *AccountNotification
+	watchedAddrs   []chan *WatchedAddressNotification
+	invoices       []chan *Invoice
 	mu             sync.Mutex // Only protects registered client channels
 	wallet         *Wallet    // smells like hacks
 }
@@ -194,7 +196,12 @@ func relevantAccounts(_ *Wallet, m map[uint32]btcutil.Amount, txs []TransactionS
 	}
 }
 
-func (s *NotificationServer) notifyUnminedTransaction(dbtx walletdb.ReadTx, details *wtxmgr.TxDetails) {
+// notifyUnminedTransaction sends the notification of an unmined transaction
+// once dbtx, which records it, is committed, so that it isn't sent again when
+// the transaction is rolled back and recorded anew.
+func (s *NotificationServer) notifyUnminedTransaction(
+	dbtx walletdb.ReadWriteTx, details *wtxmgr.TxDetails) {
+
 	// Sanity check: should not be currently coalescing a notification for
 	// mined transactions at the same time that an unmined tx is notified.
 	if s.currentTxNtfn != nil {
@@ -202,10 +209,10 @@ func (s *NotificationServer) notifyUnminedTransaction(dbtx walletdb.ReadTx, deta
 			details.Hash)
 	}
 
-	defer s.mu.Unlock()
 	s.mu.Lock()
-	clients := s.transactions
-	if len(clients) == 0 {
+	numClients := len(s.transactions)
+	s.mu.Unlock()
+	if numClients == 0 {
 		return
 	}
 
@@ -227,9 +234,13 @@ func (s *NotificationServer) notifyUnminedTransaction(dbtx walletdb.ReadTx, deta
 		UnminedTransactionHashes: unminedHashes,
 		NewBalances:              flattenBalanceMap(bals),
 	}
-	for _, c := range clients {
-		c <- n
-	}
+	dbtx.OnCommit(func() {
+		defer s.mu.Unlock()
+		s.mu.Lock()
+		for _, c := range s.transactions {
+			c <- n
+		}
+	})
 }
 
 func (s *NotificationServer) notifyDetachedBlock(hash *chainhash.Hash) {
@@ -239,22 +250,32 @@ func (s *NotificationServer) notifyDetachedBlock(hash *chainhash.Hash) {
 	s.currentTxNtfn.DetachedBlocks = append(s.currentTxNtfn.DetachedBlocks, hash)
 }
 
-func (s *NotificationServer) notifyMinedTransaction(dbtx walletdb.ReadTx, details *wtxmgr.TxDetails, block *wtxmgr.BlockMeta) {
-	if s.currentTxNtfn == nil {
-		s.currentTxNtfn = &TransactionNotifications{}
-	}
-	n := len(s.currentTxNtfn.AttachedBlocks)
-	if n == 0 || *s.currentTxNtfn.AttachedBlocks[n-1].Hash != block.Hash {
-		s.currentTxNtfn.AttachedBlocks = append(s.currentTxNtfn.AttachedBlocks, Block{
-			Hash:      &block.Hash,
-			Height:    block.Height,
-			Timestamp: block.Time.Unix(),
-		})
-		n++
-	}
-	txs := s.currentTxNtfn.AttachedBlocks[n-1].Transactions
-	s.currentTxNtfn.AttachedBlocks[n-1].Transactions =
-		append(txs, makeTxSummary(dbtx, s.wallet, details)) //  nolint:gocritic
+// notifyMinedTransaction adds a mined transaction to the notification of its
+// block once dbtx, which records it, is committed, so that it isn't added
+// again when the transaction is rolled back and recorded anew.
+func (s *NotificationServer) notifyMinedTransaction(
+	dbtx walletdb.ReadWriteTx, details *wtxmgr.TxDetails,
+	block *wtxmgr.BlockMeta) {
+
+	summary := makeTxSummary(dbtx, s.wallet, details)
+	dbtx.OnCommit(func() {
+		if s.currentTxNtfn == nil {
+			s.currentTxNtfn = &TransactionNotifications{}
+		}
+		ntfn := s.currentTxNtfn
+		n := len(ntfn.AttachedBlocks)
+		if n == 0 || *ntfn.AttachedBlocks[n-1].Hash != block.Hash {
+			ntfn.AttachedBlocks = append(ntfn.AttachedBlocks, Block{
+				Hash:      &block.Hash,
+				Height:    block.Height,
+				Timestamp: block.Time.Unix(),
+			})
+			n++
+		}
+		txs := ntfn.AttachedBlocks[n-1].Transactions
+		ntfn.AttachedBlocks[n-1].Transactions =
+			append(txs, summary) //  nolint:gocritic
+	})
 }
 
 func (s *NotificationServer) notifyAttachedBlock(dbtx walletdb.ReadTx, block *wtxmgr.BlockMeta) {
@@ -613,3 +634,111 @@ func (c *AccountNotificationsClient) Done() {
 		s.mu.Unlock()
 	}()
 }
+
+func (s *NotificationServer) notifyWatchedAddress(n *WatchedAddressNotification) {
+	defer s.mu.Unlock()
+	s.mu.Lock()
+	for _, c := range s.watchedAddrs {
+		c <- n
+	}
+}
+
+// WatchedAddressNotificationsClient receives WatchedAddressNotifications over
+// the channel C.
+type WatchedAddressNotificationsClient struct {
+	C      chan *WatchedAddressNotification
+	server *NotificationServer
+}
+
+// WatchedAddressNotifications returns a client for receiving the payments to
+// addresses watched with WatchAddresses over a channel.  The channel is
+// unbuffered.  When finished, the client's Done method should be called to
+// disassociate the client from the server.
+func (s *NotificationServer) WatchedAddressNotifications() WatchedAddressNotificationsClient {
+	c := make(chan *WatchedAddressNotification)
+	s.mu.Lock()
+	s.watchedAddrs = append(s.watchedAddrs, c)
+	s.mu.Unlock()
+	return WatchedAddressNotificationsClient{
+		C:      c,
+		server: s,
+	}
+}
+
+// Done deregisters the client from the server and drains any remaining
+// messages.  It must be called exactly once when the client is finished
+// receiving notifications.
+func (c *WatchedAddressNotificationsClient) Done() {
+	go func() {
+		for range c.C {
+		}
+	}()
+	go func() {
+		s := c.server
+		s.mu.Lock()
+		clients := s.watchedAddrs
+		for i, ch := range clients {
+			if c.C == ch {
+				clients[i] = clients[len(clients)-1]
+				s.watchedAddrs = clients[:len(clients)-1]
+				close(ch)
+				break
+			}
+		}
+		s.mu.Unlock()
+	}()
+}
+
+func (s *NotificationServer) notifyInvoice(inv *Invoice) {
+	defer s.mu.Unlock()
+	s.mu.Lock()
+	for _, c := range s.invoices {
+		c <- inv
+	}
+}
+
+// InvoiceNotificationsClient receives invoices over the channel C when they
+// are paid or expire.
+type InvoiceNotificationsClient struct {
+	C      chan *Invoice
+	server *NotificationServer
+}
+
+// InvoiceNotifications returns a client for receiving invoices when they are
+// paid or expire over a channel.  The channel is unbuffered.  When finished,
+// the client's Done method should be called to disassociate the client from
+// the server.
+func (s *NotificationServer) InvoiceNotifications() InvoiceNotificationsClient {
+	c := make(chan *Invoice)
+	s.mu.Lock()
+	s.invoices = append(s.invoices, c)
+	s.mu.Unlock()
+	return InvoiceNotificationsClient{
+		C:      c,
+		server: s,
+	}
+}
+
+// Done deregisters the client from the server and drains any remaining
+// messages.  It must be called exactly once when the client is finished
+// receiving notifications.
+func (c *InvoiceNotificationsClient) Done() {
+	go func() {
+		for range c.C {
+		}
+	}()
+	go func() {
+		s := c.server
+		s.mu.Lock()
+		clients := s.invoices
+		for i, ch := range clients {
+			if c.C == ch {
+				clients[i] = clients[len(clients)-1]
+				s.invoices = clients[:len(clients)-1]
+				close(ch)
+				break
+			}
+		}
+		s.mu.Unlock()
+	}()
+}
@@ -0,0 +1,204 @@
+package wallet
+
+import (
+	"sort"
+
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/walletdb"
+	"github.com/lbryio/lbcwallet/wtxmgr"
+)
+
+// roundAmountUnit is the unit of round amounts: payments of a whole number of
+// units are likely to be picked by a person, unlike the change.
+const roundAmountUnit = btcutil.Amount(100000)
+
+// PrivacyReport describes the patterns of the wallet's own transactions which
+// link its addresses together or tell its change apart from its payments.
+type PrivacyReport struct {
+	// Transactions is the number of wallet transactions analyzed.
+	Transactions int
+
+	ReusedAddresses []ReusedAddress
+	MergedInputs    []MergedInputs
+	RoundChange     []RoundChange
+}
+
+// ReusedAddress is an address of the wallet paid by several transactions,
+// which are linked together by it.  Claim, support and claim update outputs
+// are not counted, as a claim update keeps the address of the claim.
+type ReusedAddress struct {
+	Address      btcutil.Address
+	Account      uint32
+	Transactions []chainhash.Hash
+}
+
+// MergedInputs is a transaction spending the outputs of several accounts,
+// which links the accounts together.
+type MergedInputs struct {
+	Hash     chainhash.Hash
+	Accounts []uint32
+}
+
+// RoundChange is a transaction sent by the wallet whose payments are of round
+// amounts while its change is not, so that the change is easily identified.
+type RoundChange struct {
+	Hash   chainhash.Hash
+	Change []uint32
+}
+
+// PrivacyReport analyzes the transaction graph of the wallet for address
+// reuse, inputs merged across accounts and round-amount payments with change
+// that is not round.  Reports are ordered as their transactions, by height
+// with unmined transactions last, and reused addresses by address.
+func (w *Wallet) PrivacyReport() (*PrivacyReport, error) {
+	var details []wtxmgr.TxDetails
+
+	// The account of every output paying to the wallet, which is needed
+	// to know the accounts of the spent outputs.
+	accounts := make(map[wire.OutPoint]uint32)
+	reused := make(map[string]*ReusedAddress)
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
+		txmgrNs := tx.ReadBucket(wtxmgrNamespaceKey)
+		err := w.TxStore.RangeTransactions(txmgrNs, 0, -1,
+			func(d []wtxmgr.TxDetails) (bool, error) {
+				details = append(details, d...)
+				return false, nil
+			})
+		if err != nil {
+			return err
+		}
+
+		for i := range details {
+			d := &details[i]
+			for _, c := range d.Credits {
+				pkScript := d.MsgTx.TxOut[c.Index].PkScript
+				_, addrs, _, err := txscript.ExtractPkScriptAddrs(
+					pkScript, w.chainParams)
+				if err != nil || len(addrs) == 0 {
+					continue
+				}
+				_, acct, err := w.Manager.AddrAccount(
+					addrmgrNs, addrs[0])
+				if err != nil {
+					continue
+				}
+				op := wire.OutPoint{Hash: d.Hash, Index: c.Index}
+				accounts[op] = acct
+
+				if isStake(pkScript) {
+					continue
+				}
+				encoded := addrs[0].EncodeAddress()
+				r, ok := reused[encoded]
+				if !ok {
+					r = &ReusedAddress{
+						Address: addrs[0],
+						Account: acct,
+					}
+					reused[encoded] = r
+				}
+				n := len(r.Transactions)
+				if n == 0 || r.Transactions[n-1] != d.Hash {
+					r.Transactions = append(
+						r.Transactions, d.Hash)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &PrivacyReport{Transactions: len(details)}
+	for _, r := range reused {
+		if len(r.Transactions) > 1 {
+			report.ReusedAddresses = append(
+				report.ReusedAddresses, *r)
+		}
+	}
+	sort.Slice(report.ReusedAddresses, func(i, j int) bool {
+		return report.ReusedAddresses[i].Address.EncodeAddress() <
+			report.ReusedAddresses[j].Address.EncodeAddress()
+	})
+
+	for i := range details {
+		d := &details[i]
+		if len(d.Debits) == 0 {
+			continue
+		}
+		if m, ok := mergedInputs(d, accounts); ok {
+			report.MergedInputs = append(report.MergedInputs, m)
+		}
+		if r, ok := roundChange(d); ok {
+			report.RoundChange = append(report.RoundChange, r)
+		}
+	}
+	return report, nil
+}
+
+// mergedInputs returns the accounts of the outputs spent by a transaction,
+// when it spends the outputs of more than one account.
+func mergedInputs(d *wtxmgr.TxDetails,
+	accounts map[wire.OutPoint]uint32) (MergedInputs, bool) {
+
+	seen := make(map[uint32]struct{})
+	var accts []uint32
+	for _, debit := range d.Debits {
+		prevOut := d.MsgTx.TxIn[debit.Index].PreviousOutPoint
+		acct, ok := accounts[prevOut]
+		if !ok {
+			continue
+		}
+		if _, ok := seen[acct]; ok {
+			continue
+		}
+		seen[acct] = struct{}{}
+		accts = append(accts, acct)
+	}
+	if len(accts) < 2 {
+		return MergedInputs{}, false
+	}
+	sort.Slice(accts, func(i, j int) bool { return accts[i] < accts[j] })
+	return MergedInputs{Hash: d.Hash, Accounts: accts}, true
+}
+
+// roundChange returns the change outputs of a transaction sent by the wallet,
+// when all its payments are of round amounts and none of its change is.
+func roundChange(d *wtxmgr.TxDetails) (RoundChange, bool) {
+	credited := make(map[uint32]bool)
+	var change []uint32
+	for _, c := range d.Credits {
+		credited[c.Index] = true
+		if !c.Change {
+			continue
+		}
+		if c.Amount%roundAmountUnit == 0 {
+			return RoundChange{}, false
+		}
+		change = append(change, c.Index)
+	}
+	if len(change) == 0 {
+		return RoundChange{}, false
+	}
+
+	var payments int
+	for i, output := range d.MsgTx.TxOut {
+		// Null data outputs pay nobody.
+		if credited[uint32(i)] || output.Value == 0 {
+			continue
+		}
+		if btcutil.Amount(output.Value)%roundAmountUnit != 0 {
+			return RoundChange{}, false
+		}
+		payments++
+	}
+	if payments == 0 {
+		return RoundChange{}, false
+	}
+	return RoundChange{Hash: d.Hash, Change: change}, true
+}
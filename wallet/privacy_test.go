@@ -0,0 +1,122 @@
+package wallet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/walletdb"
+	"github.com/lbryio/lbcwallet/wtxmgr"
+	"github.com/stretchr/testify/require"
+)
+
+// insertCredits adds a mined transaction to the wallet, recording the outputs
+// of credits as credits, which are change when mapped to true.
+func insertCredits(t *testing.T, w *Wallet, tx *wire.MsgTx,
+	credits map[uint32]bool) {
+
+	rec, err := wtxmgr.NewTxRecordFromMsgTx(tx, time.Now())
+	require.NoError(t, err)
+	block := &wtxmgr.BlockMeta{
+		Block: wtxmgr.Block{Hash: *testBlockHash, Height: testBlockHeight},
+		Time:  time.Unix(1387737310, 0),
+	}
+	err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		ns := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+		if err := w.TxStore.InsertTx(ns, rec, block); err != nil {
+			return err
+		}
+		for i, change := range credits {
+			err := w.TxStore.AddCredit(ns, rec, block, i, change)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+// TestPrivacyReport checks that the privacy report flags reused addresses,
+// inputs merged across accounts and payments of round amounts with change
+// that is not round.
+func TestPrivacyReport(t *testing.T) {
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	acct, err := w.NextAccount(waddrmgr.KeyScopeBIP0044, "savings")
+	require.NoError(t, err)
+
+	pkScript := func(account uint32) []byte {
+		addr, err := w.NewAddress(account, waddrmgr.KeyScopeBIP0044)
+		require.NoError(t, err)
+		script, err := txscript.PayToAddrScript(addr)
+		require.NoError(t, err)
+		return script
+	}
+	reused := pkScript(0)
+	savings := pkScript(acct)
+	external := []byte{txscript.OP_TRUE}
+
+	// The default account is paid twice to the same address, and the
+	// savings account once.
+	first := wire.NewMsgTx(wire.TxVersion)
+	first.AddTxIn(&wire.TxIn{})
+	first.AddTxOut(wire.NewTxOut(1000000, reused))
+	insertCredits(t, w, first, map[uint32]bool{0: false})
+
+	second := wire.NewMsgTx(wire.TxVersion)
+	second.AddTxIn(&wire.TxIn{Sequence: 1})
+	second.AddTxOut(wire.NewTxOut(2000000, reused))
+	second.AddTxOut(wire.NewTxOut(3000000, savings))
+	insertCredits(t, w, second, map[uint32]bool{0: false, 1: false})
+
+	// Spending the outputs of both accounts pays a round amount with
+	// change that is not round.
+	spend := wire.NewMsgTx(wire.TxVersion)
+	spend.AddTxIn(wire.NewTxIn(
+		&wire.OutPoint{Hash: first.TxHash(), Index: 0}, nil, nil))
+	spend.AddTxIn(wire.NewTxIn(
+		&wire.OutPoint{Hash: second.TxHash(), Index: 1}, nil, nil))
+	spend.AddTxOut(wire.NewTxOut(2500000, external))
+	spend.AddTxOut(wire.NewTxOut(1487654, pkScript(0)))
+	insertCredits(t, w, spend, map[uint32]bool{1: true})
+
+	report, err := w.PrivacyReport()
+	require.NoError(t, err)
+	require.Equal(t, 3, report.Transactions)
+
+	require.Len(t, report.ReusedAddresses, 1)
+	r := report.ReusedAddresses[0]
+	require.Equal(t, uint32(0), r.Account)
+	require.Equal(t, []chainhash.Hash{first.TxHash(), second.TxHash()},
+		r.Transactions)
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(reused, w.chainParams)
+	require.NoError(t, err)
+	require.Equal(t, addrs[0].EncodeAddress(), r.Address.EncodeAddress())
+
+	require.Equal(t, []MergedInputs{{
+		Hash:     spend.TxHash(),
+		Accounts: []uint32{0, acct},
+	}}, report.MergedInputs)
+
+	require.Equal(t, []RoundChange{{
+		Hash:   spend.TxHash(),
+		Change: []uint32{1},
+	}}, report.RoundChange)
+
+	// Change of a round amount is not told apart by its amount.
+	_, ok := roundChange(&wtxmgr.TxDetails{
+		TxRecord: wtxmgr.TxRecord{MsgTx: *spend},
+		Credits: []wtxmgr.CreditRecord{{
+			Index:  1,
+			Amount: btcutil.Amount(1500000),
+			Change: true,
+		}},
+	})
+	require.False(t, ok)
+}
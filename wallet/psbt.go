@@ -78,58 +78,6 @@ func (w *Wallet) FundPsbt(packet *psbt.Packet, keyScope *waddrmgr.KeyScope,
 		amt += output.Value
 	}
 
-	// addInputInfo is a helper function that fetches the UTXO information
-	// of an input and attaches it to the PSBT packet.
-	addInputInfo := func(inputs []*wire.TxIn) error {
-		packet.Inputs = make([]psbt.PInput, len(inputs))
-		for idx, in := range inputs {
-			tx, utxo, derivationPath, _, err := w.FetchInputInfo(
-				&in.PreviousOutPoint,
-			)
-			if err != nil {
-				return fmt.Errorf("error fetching UTXO: %v",
-					err)
-			}
-
-			// As a fix for CVE-2020-14199 we have to always include
-			// the full non-witness UTXO in the PSBT for segwit v0.
-			packet.Inputs[idx].NonWitnessUtxo = tx
-
-			// To make it more obvious that this is actually a
-			// witness output being spent, we also add the same
-			// information as the witness UTXO.
-			packet.Inputs[idx].WitnessUtxo = &wire.TxOut{
-				Value:    utxo.Value,
-				PkScript: utxo.PkScript,
-			}
-			packet.Inputs[idx].SighashType = txscript.SigHashAll
-
-			// Include the derivation path for each input.
-			packet.Inputs[idx].Bip32Derivation = []*psbt.Bip32Derivation{
-				derivationPath,
-			}
-
-			// We don't want to include the witness or any script
-			// on the unsigned TX just yet.
-			packet.UnsignedTx.TxIn[idx].Witness = wire.TxWitness{}
-			packet.UnsignedTx.TxIn[idx].SignatureScript = nil
-
-			// For nested P2WKH we need to add the redeem script to
-			// the input, otherwise an offline wallet won't be able
-			// to sign for it. For normal P2WKH this will be nil.
-			addr, witnessProgram, _, err := w.ScriptForOutput(utxo)
-			if err != nil {
-				return fmt.Errorf("error fetching UTXO "+
-					"script: %v", err)
-			}
-			if addr.AddrType() == waddrmgr.NestedWitnessPubKey {
-				packet.Inputs[idx].RedeemScript = witnessProgram
-			}
-		}
-
-		return nil
-	}
-
 	var tx *txauthor.AuthoredTx
 	switch {
 	// We need to do coin selection.
@@ -151,7 +99,7 @@ func (w *Wallet) FundPsbt(packet *psbt.Packet, keyScope *waddrmgr.KeyScope,
 		// include the witness as the resulting PSBT isn't expected not
 		// should be signed yet.
 		packet.UnsignedTx.TxIn = tx.Tx.TxIn
-		err = addInputInfo(tx.Tx.TxIn)
+		err = w.addPsbtInputInfo(packet, tx.Tx.TxIn)
 		if err != nil {
 			return 0, err
 		}
@@ -160,7 +108,7 @@ func (w *Wallet) FundPsbt(packet *psbt.Packet, keyScope *waddrmgr.KeyScope,
 	// a change output if necessary.
 	default:
 		// Make sure all inputs provided are actually ours.
-		err = addInputInfo(txIn)
+		err = w.addPsbtInputInfo(packet, txIn)
 		if err != nil {
 			return 0, err
 		}
@@ -247,6 +195,58 @@ func (w *Wallet) FundPsbt(packet *psbt.Packet, keyScope *waddrmgr.KeyScope,
 	return changeIndex, nil
 }
 
+// addPsbtInputInfo fetches the UTXO information of the given inputs of the
+// wallet and attaches it to the PSBT packet.
+func (w *Wallet) addPsbtInputInfo(packet *psbt.Packet,
+	inputs []*wire.TxIn) error {
+
+	packet.Inputs = make([]psbt.PInput, len(inputs))
+	for idx, in := range inputs {
+		tx, utxo, derivationPath, _, err := w.FetchInputInfo(
+			&in.PreviousOutPoint,
+		)
+		if err != nil {
+			return fmt.Errorf("error fetching UTXO: %v", err)
+		}
+
+		// As a fix for CVE-2020-14199 we have to always include the
+		// full non-witness UTXO in the PSBT for segwit v0.
+		packet.Inputs[idx].NonWitnessUtxo = tx
+
+		// To make it more obvious that this is actually a witness
+		// output being spent, we also add the same information as the
+		// witness UTXO.
+		packet.Inputs[idx].WitnessUtxo = &wire.TxOut{
+			Value:    utxo.Value,
+			PkScript: utxo.PkScript,
+		}
+		packet.Inputs[idx].SighashType = txscript.SigHashAll
+
+		// Include the derivation path for each input.
+		packet.Inputs[idx].Bip32Derivation = []*psbt.Bip32Derivation{
+			derivationPath,
+		}
+
+		// We don't want to include the witness or any script on the
+		// unsigned TX just yet.
+		packet.UnsignedTx.TxIn[idx].Witness = wire.TxWitness{}
+		packet.UnsignedTx.TxIn[idx].SignatureScript = nil
+
+		// For nested P2WKH we need to add the redeem script to the
+		// input, otherwise an offline wallet won't be able to sign for
+		// it. For normal P2WKH this will be nil.
+		addr, witnessProgram, _, err := w.ScriptForOutput(utxo)
+		if err != nil {
+			return fmt.Errorf("error fetching UTXO script: %v", err)
+		}
+		if addr.AddrType() == waddrmgr.NestedWitnessPubKey {
+			packet.Inputs[idx].RedeemScript = witnessProgram
+		}
+	}
+
+	return nil
+}
+
 // FinalizePsbt expects a partial transaction with all inputs and outputs fully
 // declared and tries to sign all inputs that belong to the wallet. Our wallet
 // must be the last signer of the transaction. That means, if there are any
@@ -0,0 +1,95 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNotRegtest describes an error where an operation only allowed on the
+// regression test network is attempted on another network.
+var ErrNotRegtest = errors.New("only allowed on the regression test network")
+
+// now returns the current time of the wallet clock, which is the system time
+// unless the wallet was fast-forwarded.  It determines when invoices and
+// withdrawals expire, the day of the spending limits and when output leases
+// expire.
+func (w *Wallet) now() time.Time {
+	w.clockOffsetMtx.Lock()
+	offset := w.clockOffset
+	w.clockOffsetMtx.Unlock()
+	return time.Now().Add(offset)
+}
+
+// ClockOffset returns how far the wallet clock was fast-forwarded.
+func (w *Wallet) ClockOffset() time.Duration {
+	w.clockOffsetMtx.Lock()
+	defer w.clockOffsetMtx.Unlock()
+	return w.clockOffset
+}
+
+// FastForward advances the wallet clock by d, so that tests can expire
+// invoices and output leases or start a new spending day without waiting.
+// The clock of the chain server is not changed, so neither are the times of
+// the mined blocks nor the locktimes they allow.  It is only allowed on the
+// regression test network, and returns the new time of the wallet clock.
+func (w *Wallet) FastForward(d time.Duration) (time.Time, error) {
+	if !w.isDevEnv() {
+		return time.Time{}, ErrNotRegtest
+	}
+	if d < 0 {
+		return time.Time{}, errors.New("the wallet clock can not be " +
+			"moved back")
+	}
+
+	w.clockOffsetMtx.Lock()
+	w.clockOffset += d
+	w.clockOffsetMtx.Unlock()
+
+	// Expire the invoices which are now past their expiry.
+	select {
+	case w.invoicesChanged <- struct{}{}:
+	default:
+	}
+	return w.now(), nil
+}
+
+// walletClock is the wallet clock as a clock.Clock, for the transaction
+// store.
+type walletClock struct {
+	w *Wallet
+}
+
+func (c walletClock) Now() time.Time {
+	return c.w.now()
+}
+
+func (c walletClock) TickAfter(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// WaitForHeight blocks until the wallet is synced to at least a height, for
+// tests which need the wallet to have processed the blocks they mined.
+func (w *Wallet) WaitForHeight(height int32, timeout time.Duration) error {
+	const pollInterval = 50 * time.Millisecond
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	quit := w.quitChan()
+	for {
+		if w.Manager.SyncedTo().Height >= height {
+			return nil
+		}
+		select {
+		case <-ticker.C:
+		case <-deadline.C:
+			return fmt.Errorf("the wallet did not sync to height %d "+
+				"within %v", height, timeout)
+		case <-quit:
+			return ErrWalletShuttingDown
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package wallet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lbryio/lbcd/chaincfg"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFastForward checks that the wallet clock is only fast-forwarded on
+// regtest, and that invoices expire by it.
+func TestFastForward(t *testing.T) {
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	_, err := w.FastForward(time.Hour)
+	require.ErrorIs(t, err, ErrNotRegtest)
+	require.Zero(t, w.ClockOffset())
+
+	w.chainParams = &chaincfg.RegressionNetParams
+
+	inv, err := w.CreateInvoice(
+		0, waddrmgr.KeyScopeBIP0044, 1000, "order", time.Hour,
+	)
+	require.NoError(t, err)
+
+	_, err = w.FastForward(-time.Hour)
+	require.Error(t, err)
+
+	now, err := w.FastForward(2 * time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, 2*time.Hour, w.ClockOffset())
+	require.True(t, now.After(inv.Expires))
+	require.WithinDuration(t, time.Now().Add(2*time.Hour), w.now(),
+		time.Minute)
+
+	_, err = w.expireInvoices(w.now())
+	require.NoError(t, err)
+	inv, err = w.Invoice(inv.ID)
+	require.NoError(t, err)
+	require.Equal(t, InvoiceExpired, inv.State)
+}
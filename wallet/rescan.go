@@ -38,6 +38,9 @@ type RescanJob struct {
 	OutPoints   map[wire.OutPoint]btcutil.Address
 	BlockStamp  waddrmgr.BlockStamp
 	err         chan error
+
+	// checkpoint is the ID of the checkpoint of a resumed rescan.
+	checkpoint uint64
 }
 
 // rescanBatch is a collection of one or more RescanJobs that were merged
@@ -48,6 +51,10 @@ type rescanBatch struct {
 	outpoints   map[wire.OutPoint]btcutil.Address
 	bs          waddrmgr.BlockStamp
 	errChans    []chan error
+
+	// checkpoint is the ID of the checkpoint of the batch, or zero when
+	// the batch is not checkpointed.
+	checkpoint uint64
 }
 
 // SubmitRescan submits a RescanJob to the RescanManager.  A channel is
@@ -119,6 +126,7 @@ func (w *Wallet) rescanBatchHandler() {
 				// Set current batch as this job and send
 				// request.
 				curBatch = job.batch()
+				w.checkpointRescan(curBatch, job)
 				select {
 				case w.rescanBatch <- curBatch:
 				case <-quit:
@@ -133,6 +141,7 @@ func (w *Wallet) rescanBatchHandler() {
 				} else {
 					nextBatch.merge(job)
 				}
+				w.checkpointRescan(nextBatch, job)
 			}
 
 		case n := <-w.rescanNotifications:
@@ -144,6 +153,11 @@ func (w *Wallet) rescanBatchHandler() {
 						"currently running")
 					continue
 				}
+				w.advanceRescanCheckpoint(curBatch,
+					&waddrmgr.BlockStamp{
+						Hash:   *n.Hash,
+						Height: n.Height,
+					})
 				select {
 				case w.rescanProgress <- &RescanProgressMsg{
 					Addresses:    curBatch.addrs,
@@ -175,6 +189,7 @@ func (w *Wallet) rescanBatchHandler() {
 					return
 				}
 
+				w.removeRescanCheckpoint(curBatch)
 				curBatch, nextBatch = nextBatch, nil
 
 				if curBatch != nil {
@@ -231,6 +246,64 @@ out:
 	w.wg.Done()
 }
 
+// rescanFilterMaxItems is the largest number of addresses and outpoints of a
+// rescan batch for which block filters are matched before the rescan.  Each
+// item adds to the false positive rate of the filters, so larger batches are
+// scanned in full by the backend.
+const rescanFilterMaxItems = 100
+
+// filterRescanStart matches the addresses and outpoints of a rescan batch
+// against the compact filters of the blocks from the start of the batch to
+// the best block, and returns the first block relevant to the batch.  The
+// rescan may start from this block, as no transaction of the batch is found
+// before it.  The best block is returned when none is relevant, since the
+// rescan must still be run to register the batch for notifications.
+func filterRescanStart(chainClient chain.Interface,
+	batch *rescanBatch) (waddrmgr.BlockStamp, error) {
+
+	bestHash, bestHeight, err := chainClient.GetBestBlock()
+	if err != nil {
+		return waddrmgr.BlockStamp{}, err
+	}
+
+	req := &chain.FilterBlocksRequest{
+		Addresses:        make(map[waddrmgr.ScopedIndex]btcutil.Address),
+		WatchedOutPoints: batch.outpoints,
+	}
+	for i, addr := range batch.addrs {
+		req.Addresses[waddrmgr.ScopedIndex{Index: uint32(i)}] = addr
+	}
+
+	for height := batch.bs.Height; height <= bestHeight; {
+		req.Blocks = req.Blocks[:0]
+		for ; height <= bestHeight; height++ {
+			if len(req.Blocks) == recoveryBatchSize {
+				break
+			}
+			hash, err := chainClient.GetBlockHash(int64(height))
+			if err != nil {
+				return waddrmgr.BlockStamp{}, err
+			}
+			req.Blocks = append(req.Blocks, wtxmgr.BlockMeta{
+				Block: wtxmgr.Block{Hash: *hash, Height: height},
+			})
+		}
+
+		resp, err := chainClient.FilterBlocks(req)
+		if err != nil {
+			return waddrmgr.BlockStamp{}, err
+		}
+		if resp != nil {
+			return waddrmgr.BlockStamp{
+				Hash:   resp.BlockMeta.Hash,
+				Height: resp.BlockMeta.Height,
+			}, nil
+		}
+	}
+
+	return waddrmgr.BlockStamp{Hash: *bestHash, Height: bestHeight}, nil
+}
+
 // rescanRPCHandler reads batch jobs sent by rescanBatchHandler and sends the
 // RPC requests to perform a rescan.  New jobs are not read until a rescan
 // finishes.
@@ -248,13 +321,27 @@ out:
 	for {
 		select {
 		case batch := <-w.rescanBatch:
+			// Skip the blocks which the compact filters show to be
+			// irrelevant to a small batch.
+			start := batch.bs
+			numItems := len(batch.addrs) + len(batch.outpoints)
+			if numItems != 0 && numItems <= rescanFilterMaxItems {
+				bs, err := filterRescanStart(chainClient, batch)
+				if err != nil {
+					log.Debugf("Unable to match rescan against "+
+						"block filters: %v", err)
+				} else {
+					start = bs
+				}
+			}
+
 			// Log the newly-started rescan.
 			numAddrs := len(batch.addrs)
 			noun := pickNoun(numAddrs, "address", "addresses")
 			log.Infof("Started rescan from block %v (height %d) for %d %s",
-				batch.bs.Hash, batch.bs.Height, numAddrs, noun)
+				start.Hash, start.Height, numAddrs, noun)
 
-			err := chainClient.Rescan(&batch.bs.Hash, batch.addrs,
+			err := chainClient.Rescan(&start.Hash, batch.addrs,
 				batch.outpoints)
 			if err != nil {
 				log.Errorf("Rescan for %d %s failed: %v", numAddrs,
@@ -0,0 +1,206 @@
+package wallet
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/chain"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/stretchr/testify/require"
+)
+
+// filterChainClient is a chain client whose block filters match a single
+// block of its chain.
+type filterChainClient struct {
+	mockChainClient
+
+	bestHeight  int32
+	matchHeight int32
+	filtered    []int32
+}
+
+func filterTestHash(height int64) *chainhash.Hash {
+	return &chainhash.Hash{byte(height), byte(height >> 8), 1}
+}
+
+func (c *filterChainClient) GetBestBlock() (*chainhash.Hash, int32, error) {
+	return filterTestHash(int64(c.bestHeight)), c.bestHeight, nil
+}
+
+func (c *filterChainClient) GetBlockHash(height int64) (*chainhash.Hash, error) {
+	return filterTestHash(height), nil
+}
+
+func (c *filterChainClient) FilterBlocks(req *chain.FilterBlocksRequest) (
+	*chain.FilterBlocksResponse, error) {
+
+	for i, blk := range req.Blocks {
+		c.filtered = append(c.filtered, blk.Height)
+		if blk.Height == c.matchHeight {
+			return &chain.FilterBlocksResponse{
+				BatchIndex: uint32(i),
+				BlockMeta:  blk,
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+// addressChainClient is a chain client deriving an address of a wallet each
+// time it filters blocks.
+type addressChainClient struct {
+	filterChainClient
+
+	w     *Wallet
+	addrs []btcutil.Address
+}
+
+func (c *addressChainClient) GetBlockHeader(*chainhash.Hash) (
+	*wire.BlockHeader, error) {
+
+	return &wire.BlockHeader{}, nil
+}
+
+func (c *addressChainClient) FilterBlocks(req *chain.FilterBlocksRequest) (
+	*chain.FilterBlocksResponse, error) {
+
+	addr, err := c.w.NewAddress(0, waddrmgr.KeyScopeBIP0084)
+	if err != nil {
+		return nil, err
+	}
+	c.addrs = append(c.addrs, addr)
+	return c.filterChainClient.FilterBlocks(req)
+}
+
+// TestRescanBlockchainWriteLock ensures addresses are derived while a rescan
+// of the blockchain filters blocks, the database write lock not being held
+// meanwhile.
+func TestRescanBlockchainWriteLock(t *testing.T) {
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	c := &addressChainClient{
+		filterChainClient: filterChainClient{
+			bestHeight: 100, matchHeight: 50,
+		},
+		w: w,
+	}
+	done := make(chan error, 1)
+	go func() {
+		_, _, err := w.RescanBlockchain(
+			context.Background(), c, 0, 100,
+		)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(time.Minute):
+		t.Fatal("rescan blocked deriving addresses")
+	}
+
+	// The blocks after the matched one are filtered again.
+	require.Len(t, c.addrs, 2)
+	require.Equal(t, int32(50), c.filtered[50])
+	require.Equal(t, int32(51), c.filtered[51])
+}
+
+// TestFilterRescanStart ensures a rescan starts from the first block matched
+// by the block filters, or from the best block when none is matched.
+func TestFilterRescanStart(t *testing.T) {
+	t.Parallel()
+
+	addr, err := btcutil.NewAddressPubKeyHash(
+		make([]byte, 20), &chainParams,
+	)
+	require.NoError(t, err)
+	batch := &rescanBatch{
+		addrs: []btcutil.Address{addr},
+		bs:    waddrmgr.BlockStamp{Height: 100},
+	}
+
+	c := &filterChainClient{bestHeight: 5000, matchHeight: 2500}
+	bs, err := filterRescanStart(c, batch)
+	require.NoError(t, err)
+	require.Equal(t, int32(2500), bs.Height)
+	require.Equal(t, *filterTestHash(2500), bs.Hash)
+	require.Equal(t, int32(100), c.filtered[0])
+	require.Equal(t, int32(2500), c.filtered[len(c.filtered)-1])
+
+	c = &filterChainClient{bestHeight: 5000, matchHeight: -1}
+	bs, err = filterRescanStart(c, batch)
+	require.NoError(t, err)
+	require.Equal(t, int32(5000), bs.Height)
+	require.Equal(t, *filterTestHash(5000), bs.Hash)
+	require.Len(t, c.filtered, 4901)
+}
+
+// TestRescanCheckpoints ensures the checkpoints of rescan batches record their
+// progress, and are resumed, merged and removed.
+func TestRescanCheckpoints(t *testing.T) {
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	addr, err := btcutil.NewAddressPubKeyHash(
+		make([]byte, 20), w.ChainParams(),
+	)
+	require.NoError(t, err)
+	op := wire.OutPoint{Hash: chainhash.Hash{1}, Index: 2}
+
+	// Jobs of the initial sync are not checkpointed.
+	initial := &RescanJob{InitialSync: true, Addrs: []btcutil.Address{addr}}
+	w.checkpointRescan(initial.batch(), initial)
+	jobs, err := w.rescanCheckpoints()
+	require.NoError(t, err)
+	require.Empty(t, jobs)
+
+	job := &RescanJob{
+		Addrs:      []btcutil.Address{addr},
+		OutPoints:  map[wire.OutPoint]btcutil.Address{op: addr},
+		BlockStamp: waddrmgr.BlockStamp{Height: 100},
+	}
+	batch := job.batch()
+	w.checkpointRescan(batch, job)
+	require.NotZero(t, batch.checkpoint)
+	w.advanceRescanCheckpoint(batch, &waddrmgr.BlockStamp{
+		Hash:   *filterTestHash(250),
+		Height: 250,
+	})
+
+	jobs, err = w.rescanCheckpoints()
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	resumed := jobs[0]
+	require.Equal(t, batch.checkpoint, resumed.checkpoint)
+	require.Equal(t, int32(250), resumed.BlockStamp.Height)
+	require.Equal(t, *filterTestHash(250), resumed.BlockStamp.Hash)
+	require.Equal(t, addr.EncodeAddress(), resumed.Addrs[0].EncodeAddress())
+	require.Equal(t, addr.EncodeAddress(),
+		resumed.OutPoints[op].EncodeAddress())
+
+	// A resumed job merged into another batch is checkpointed with it.
+	other := &RescanJob{
+		Addrs:      []btcutil.Address{addr},
+		OutPoints:  map[wire.OutPoint]btcutil.Address{},
+		BlockStamp: waddrmgr.BlockStamp{Height: 300},
+	}
+	merged := other.batch()
+	w.checkpointRescan(merged, other)
+	merged.merge(resumed)
+	w.checkpointRescan(merged, resumed)
+	jobs, err = w.rescanCheckpoints()
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	require.Equal(t, merged.checkpoint, jobs[0].checkpoint)
+	require.Equal(t, int32(250), jobs[0].BlockStamp.Height)
+	require.Len(t, jobs[0].Addrs, 2)
+
+	w.removeRescanCheckpoint(merged)
+	jobs, err = w.rescanCheckpoints()
+	require.NoError(t, err)
+	require.Empty(t, jobs)
+}
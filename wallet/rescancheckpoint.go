@@ -0,0 +1,256 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/walletdb"
+)
+
+// bucketRescanCheckpoints is the name of the sub bucket of the wallet
+// namespace that maps the IDs of rescan batches of imported addresses to
+// their serialized checkpoints.  A checkpoint is written when a batch is
+// submitted, advanced with the progress of its rescan, and removed when the
+// rescan finishes, so that the rescans interrupted by a crash or a restart
+// resume from the last block they processed.
+var bucketRescanCheckpoints = []byte("rescanckpts")
+
+// checkpointKey returns the key of a checkpoint in the checkpoints bucket.
+func checkpointKey(id uint64) []byte {
+	var k [8]byte
+	binary.BigEndian.PutUint64(k[:], id)
+	return k[:]
+}
+
+// serializeRescanCheckpoint returns the serialization of the checkpoint of a
+// rescan batch which processed the blocks up to bs:
+//
+//	[0:4]   height of the block (4 bytes)
+//	[4:36]  hash of the block (32 bytes)
+//	varint  number of addresses, followed by the addresses as varstrings
+//	varint  number of outpoints, followed by the outpoints (36 bytes each)
+//	        and their addresses as varstrings
+func serializeRescanCheckpoint(b *rescanBatch,
+	bs *waddrmgr.BlockStamp) ([]byte, error) {
+
+	var buf bytes.Buffer
+	var height [4]byte
+	binary.BigEndian.PutUint32(height[:], uint32(bs.Height))
+	buf.Write(height[:])
+	buf.Write(bs.Hash[:])
+
+	err := wire.WriteVarInt(&buf, 0, uint64(len(b.addrs)))
+	if err != nil {
+		return nil, err
+	}
+	for _, addr := range b.addrs {
+		err := wire.WriteVarString(&buf, 0, addr.EncodeAddress())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = wire.WriteVarInt(&buf, 0, uint64(len(b.outpoints)))
+	if err != nil {
+		return nil, err
+	}
+	for op, addr := range b.outpoints {
+		buf.Write(op.Hash[:])
+		var index [4]byte
+		binary.BigEndian.PutUint32(index[:], op.Index)
+		buf.Write(index[:])
+		err := wire.WriteVarString(&buf, 0, addr.EncodeAddress())
+		if err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// deserializeRescanCheckpoint decodes a checkpoint serialized by
+// serializeRescanCheckpoint into the job resuming its rescan.
+func (w *Wallet) deserializeRescanCheckpoint(k, v []byte) (*RescanJob, error) {
+	if len(k) != 8 || len(v) < 36 {
+		return nil, fmt.Errorf("short rescan checkpoint: %d bytes", len(v))
+	}
+	job := &RescanJob{
+		OutPoints:  make(map[wire.OutPoint]btcutil.Address),
+		checkpoint: binary.BigEndian.Uint64(k),
+	}
+	job.BlockStamp.Height = int32(binary.BigEndian.Uint32(v[0:4]))
+	copy(job.BlockStamp.Hash[:], v[4:36])
+
+	r := bytes.NewReader(v[36:])
+	readAddr := func() (btcutil.Address, error) {
+		s, err := wire.ReadVarString(r, 0)
+		if err != nil {
+			return nil, err
+		}
+		return btcutil.DecodeAddress(s, w.chainParams)
+	}
+
+	n, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return nil, err
+	}
+	for i := uint64(0); i < n; i++ {
+		addr, err := readAddr()
+		if err != nil {
+			return nil, err
+		}
+		job.Addrs = append(job.Addrs, addr)
+	}
+
+	n, err = wire.ReadVarInt(r, 0)
+	if err != nil {
+		return nil, err
+	}
+	for i := uint64(0); i < n; i++ {
+		var op wire.OutPoint
+		var index [4]byte
+		if _, err := io.ReadFull(r, op.Hash[:]); err != nil {
+			return nil, err
+		}
+		if _, err := io.ReadFull(r, index[:]); err != nil {
+			return nil, err
+		}
+		op.Index = binary.BigEndian.Uint32(index[:])
+		addr, err := readAddr()
+		if err != nil {
+			return nil, err
+		}
+		job.OutPoints[op] = addr
+	}
+	return job, nil
+}
+
+// checkpointRescan writes the checkpoint of a rescan batch after a job was
+// added to it.  The batch takes the ID of the checkpoint of a resumed job,
+// and the checkpoint of a resumed job merged into a batch with another ID is
+// removed, as the batch includes it.  Jobs of the initial sync are not
+// checkpointed, as the initial sync is repeated at each start.
+func (w *Wallet) checkpointRescan(b *rescanBatch, job *RescanJob) {
+	if job.InitialSync && job.checkpoint == 0 {
+		return
+	}
+	err := walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(walletNamespaceKey)
+		ckpts, err := ns.CreateBucketIfNotExists(bucketRescanCheckpoints)
+		if err != nil {
+			return err
+		}
+		if b.checkpoint == 0 {
+			b.checkpoint = job.checkpoint
+		}
+		if b.checkpoint == 0 {
+			b.checkpoint, err = ckpts.NextSequence()
+			if err != nil {
+				return err
+			}
+		}
+		v, err := serializeRescanCheckpoint(b, &b.bs)
+		if err != nil {
+			return err
+		}
+		err = ckpts.Put(checkpointKey(b.checkpoint), v)
+		if err != nil {
+			return err
+		}
+		if job.checkpoint != 0 && job.checkpoint != b.checkpoint {
+			return ckpts.Delete(checkpointKey(job.checkpoint))
+		}
+		return nil
+	})
+	if err != nil {
+		log.Errorf("Unable to checkpoint rescan: %v", err)
+	}
+}
+
+// advanceRescanCheckpoint records that the rescan of a batch processed the
+// blocks up to bs.
+func (w *Wallet) advanceRescanCheckpoint(b *rescanBatch,
+	bs *waddrmgr.BlockStamp) {
+
+	if b.checkpoint == 0 {
+		return
+	}
+	err := walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		ckpts := tx.ReadWriteBucket(walletNamespaceKey).
+			NestedReadWriteBucket(bucketRescanCheckpoints)
+		if ckpts == nil {
+			return nil
+		}
+		v, err := serializeRescanCheckpoint(b, bs)
+		if err != nil {
+			return err
+		}
+		return ckpts.Put(checkpointKey(b.checkpoint), v)
+	})
+	if err != nil {
+		log.Errorf("Unable to checkpoint rescan: %v", err)
+	}
+}
+
+// removeRescanCheckpoint removes the checkpoint of a finished rescan.
+func (w *Wallet) removeRescanCheckpoint(b *rescanBatch) {
+	if b.checkpoint == 0 {
+		return
+	}
+	err := walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		ckpts := tx.ReadWriteBucket(walletNamespaceKey).
+			NestedReadWriteBucket(bucketRescanCheckpoints)
+		if ckpts == nil {
+			return nil
+		}
+		return ckpts.Delete(checkpointKey(b.checkpoint))
+	})
+	if err != nil {
+		log.Errorf("Unable to remove rescan checkpoint: %v", err)
+	}
+}
+
+// rescanCheckpoints returns the jobs resuming the checkpointed rescans.
+func (w *Wallet) rescanCheckpoints() ([]*RescanJob, error) {
+	var jobs []*RescanJob
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		ckpts := tx.ReadBucket(walletNamespaceKey).
+			NestedReadBucket(bucketRescanCheckpoints)
+		if ckpts == nil {
+			return nil
+		}
+		return ckpts.ForEach(func(k, v []byte) error {
+			job, err := w.deserializeRescanCheckpoint(k, v)
+			if err != nil {
+				return err
+			}
+			jobs = append(jobs, job)
+			return nil
+		})
+	})
+	return jobs, err
+}
+
+// resumeRescans resubmits the rescans interrupted by the last shutdown of the
+// wallet, from the last block they processed.  It is only done once, after
+// the first sync with the chain, as later syncs follow reconnections while
+// the rescans are still running.
+func (w *Wallet) resumeRescans() {
+	w.resumeRescansOnce.Do(func() {
+		jobs, err := w.rescanCheckpoints()
+		if err != nil {
+			log.Errorf("Unable to read rescan checkpoints: %v", err)
+			return
+		}
+		for _, job := range jobs {
+			noun := pickNoun(len(job.Addrs), "address", "addresses")
+			log.Infof("Resuming rescan for %d %s from height %d",
+				len(job.Addrs), noun, job.BlockStamp.Height)
+			_ = w.SubmitRescan(job)
+		}
+	})
+}
@@ -0,0 +1,218 @@
+package wallet
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/walletdb"
+	"github.com/lbryio/lbcwallet/wtxmgr"
+)
+
+// ScriptTx is a wallet transaction paying to or spending from an output
+// script.
+type ScriptTx struct {
+	Hash chainhash.Hash
+
+	// Height is the height of the block the transaction is mined in, or
+	// -1 for unmined transactions.
+	Height int32
+
+	// UnminedInputs is set for unmined transactions which spend outputs
+	// of other unmined transactions.
+	UnminedInputs bool
+
+	// Fee is the fee of the transaction, or zero when not every input is
+	// spent from the wallet.
+	Fee btcutil.Amount
+}
+
+// ScriptOutput is an unspent output paying to an output script.
+type ScriptOutput struct {
+	OutPoint wire.OutPoint
+	Height   int32
+	Amount   btcutil.Amount
+}
+
+// ScriptActivity describes the wallet transactions of an output script.
+type ScriptActivity struct {
+	// Transactions are sorted by height with the unmined transactions
+	// last, and by hash within a height.
+	Transactions []ScriptTx
+
+	Unspent []ScriptOutput
+
+	// Confirmed is the value of the unspent outputs of mined transactions,
+	// ignoring unmined spends.  Unconfirmed is the value received by
+	// unmined transactions less the value they spend, which may be
+	// negative.
+	Confirmed   btcutil.Amount
+	Unconfirmed btcutil.Amount
+}
+
+// scriptCredit is an output paying to a script, with the height of the
+// transaction spending it, if any.
+type scriptCredit struct {
+	script     string
+	height     int32
+	amount     btcutil.Amount
+	spent      bool
+	spentMined bool
+}
+
+// ScriptActivity returns the activity of every output script paid by the
+// wallet transactions, keyed by the script with any claim prefix removed, so
+// that claims and supports are included in the activity of the address they
+// pay to.
+func (w *Wallet) ScriptActivity() (map[string]*ScriptActivity, error) {
+	var all []wtxmgr.TxDetails
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		txmgrNs := tx.ReadBucket(wtxmgrNamespaceKey)
+		return w.TxStore.RangeTransactions(txmgrNs, 0, -1,
+			func(details []wtxmgr.TxDetails) (bool, error) {
+				all = append(all, details...)
+				return false, nil
+			})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Index the credits first, since unmined transactions spending each
+	// other are not ordered.
+	credits := make(map[wire.OutPoint]*scriptCredit)
+	unmined := make(map[chainhash.Hash]struct{})
+	for i := range all {
+		details := &all[i]
+		if details.Block.Height == -1 {
+			unmined[details.Hash] = struct{}{}
+		}
+		for _, cred := range details.Credits {
+			if int(cred.Index) >= len(details.MsgTx.TxOut) {
+				continue
+			}
+			pkScript := details.MsgTx.TxOut[cred.Index].PkScript
+			op := wire.OutPoint{Hash: details.Hash, Index: cred.Index}
+			credits[op] = &scriptCredit{
+				script: string(txscript.StripClaimScriptPrefix(pkScript)),
+				height: details.Block.Height,
+				amount: cred.Amount,
+			}
+		}
+	}
+
+	activity := make(map[string]*ScriptActivity)
+	get := func(script string) *ScriptActivity {
+		a, ok := activity[script]
+		if !ok {
+			a = new(ScriptActivity)
+			activity[script] = a
+		}
+		return a
+	}
+	for i := range all {
+		details := &all[i]
+		height := details.Block.Height
+
+		// Collect the scripts paid or spent from by the transaction.
+		scripts := make(map[string]struct{})
+		for _, cred := range details.Credits {
+			op := wire.OutPoint{Hash: details.Hash, Index: cred.Index}
+			if c, ok := credits[op]; ok {
+				scripts[c.script] = struct{}{}
+				if height == -1 {
+					get(c.script).Unconfirmed += c.amount
+				}
+			}
+		}
+		var unminedInputs bool
+		var debitTotal btcutil.Amount
+		for _, txIn := range details.MsgTx.TxIn {
+			if _, ok := unmined[txIn.PreviousOutPoint.Hash]; ok {
+				unminedInputs = true
+			}
+		}
+		for _, deb := range details.Debits {
+			debitTotal += deb.Amount
+			if int(deb.Index) >= len(details.MsgTx.TxIn) {
+				continue
+			}
+			op := details.MsgTx.TxIn[deb.Index].PreviousOutPoint
+			c, ok := credits[op]
+			if !ok {
+				continue
+			}
+			scripts[c.script] = struct{}{}
+			c.spent = true
+			if height == -1 {
+				get(c.script).Unconfirmed -= c.amount
+			} else {
+				c.spentMined = true
+			}
+		}
+
+		var fee btcutil.Amount
+		if len(details.Debits) == len(details.MsgTx.TxIn) {
+			var outputTotal btcutil.Amount
+			for _, txOut := range details.MsgTx.TxOut {
+				outputTotal += btcutil.Amount(txOut.Value)
+			}
+			fee = debitTotal - outputTotal
+		}
+		for script := range scripts {
+			a := get(script)
+			a.Transactions = append(a.Transactions, ScriptTx{
+				Hash:          details.Hash,
+				Height:        height,
+				UnminedInputs: height == -1 && unminedInputs,
+				Fee:           fee,
+			})
+		}
+	}
+
+	for op, c := range credits {
+		a := get(c.script)
+		if c.height != -1 && !c.spentMined {
+			a.Confirmed += c.amount
+		}
+		if !c.spent {
+			a.Unspent = append(a.Unspent, ScriptOutput{
+				OutPoint: op,
+				Height:   c.height,
+				Amount:   c.amount,
+			})
+		}
+	}
+
+	for _, a := range activity {
+		sort.Slice(a.Transactions, func(i, j int) bool {
+			return lessByHeight(a.Transactions[i].Height,
+				a.Transactions[j].Height, &a.Transactions[i].Hash,
+				&a.Transactions[j].Hash)
+		})
+		sort.Slice(a.Unspent, func(i, j int) bool {
+			x, y := &a.Unspent[i], &a.Unspent[j]
+			if x.OutPoint.Hash == y.OutPoint.Hash {
+				return x.OutPoint.Index < y.OutPoint.Index
+			}
+			return lessByHeight(x.Height, y.Height, &x.OutPoint.Hash,
+				&y.OutPoint.Hash)
+		})
+	}
+	return activity, nil
+}
+
+// lessByHeight orders transactions by height with the unmined transactions
+// last, and by hash within a height.
+func lessByHeight(h1, h2 int32, hash1, hash2 *chainhash.Hash) bool {
+	if h1 != h2 {
+		if h1 == -1 || h2 == -1 {
+			return h2 == -1
+		}
+		return h1 < h2
+	}
+	return bytes.Compare(hash1[:], hash2[:]) < 0
+}
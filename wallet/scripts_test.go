@@ -0,0 +1,92 @@
+package wallet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/walletdb"
+	"github.com/lbryio/lbcwallet/wtxmgr"
+)
+
+// TestScriptActivity ensures the history, unspent outputs and balances of a
+// script include the transactions paying to and spending from it.
+func TestScriptActivity(t *testing.T) {
+	t.Parallel()
+
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	addr, err := w.NewAddress(0, waddrmgr.KeyScopeBIP0044)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addTx := func(msgTx *wire.MsgTx) *wtxmgr.TxRecord {
+		rec, err := wtxmgr.NewTxRecordFromMsgTx(msgTx, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+			return w.addRelevantTx(tx, rec, nil)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rec
+	}
+
+	// Receive 1000 and spend it, paying 600 back to the same script.
+	receive := wire.NewMsgTx(wire.TxVersion)
+	receive.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 1}, nil, nil))
+	receive.AddTxOut(wire.NewTxOut(1000, pkScript))
+	received := addTx(receive)
+
+	spend := wire.NewMsgTx(wire.TxVersion)
+	spend.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: received.Hash}, nil, nil))
+	spend.AddTxOut(wire.NewTxOut(600, pkScript))
+	spent := addTx(spend)
+
+	activity, err := w.ScriptActivity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := activity[string(pkScript)]
+	if a == nil {
+		t.Fatal("no activity for the script")
+	}
+	if len(a.Transactions) != 2 {
+		t.Fatalf("got %d transactions, want 2", len(a.Transactions))
+	}
+	for _, tx := range a.Transactions {
+		if tx.Height != -1 {
+			t.Errorf("transaction %v has height %d", tx.Hash, tx.Height)
+		}
+		switch tx.Hash {
+		case received.Hash:
+			if tx.UnminedInputs || tx.Fee != 0 {
+				t.Errorf("unexpected receive %+v", tx)
+			}
+		case spent.Hash:
+			if !tx.UnminedInputs || tx.Fee != 400 {
+				t.Errorf("unexpected spend %+v", tx)
+			}
+		default:
+			t.Errorf("unexpected transaction %v", tx.Hash)
+		}
+	}
+	if len(a.Unspent) != 1 || a.Unspent[0].OutPoint.Hash != spent.Hash ||
+		a.Unspent[0].Amount != 600 {
+
+		t.Errorf("unexpected unspent outputs %+v", a.Unspent)
+	}
+	if a.Confirmed != 0 || a.Unconfirmed != 600 {
+		t.Errorf("got balances %v confirmed and %v unconfirmed, want 0 "+
+			"and 600", a.Confirmed, a.Unconfirmed)
+	}
+}
@@ -0,0 +1,124 @@
+package wallet
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/lbryio/lbcd/chaincfg"
+	"github.com/lbryio/lbcutil/hdkeychain"
+	"github.com/lbryio/lbcwallet/internal/shamir"
+	"github.com/lbryio/lbcwallet/internal/zero"
+	"github.com/lbryio/lbcwallet/walletdb"
+)
+
+// The kinds of the secrets split into seed shares, which start the secrets.
+const (
+	// seedShareSeed is a wallet generation seed.
+	seedShareSeed byte = iota
+
+	// seedShareMasterKey is the chain code and private key of the master
+	// root key of a wallet.
+	seedShareMasterKey
+)
+
+// ErrSeedShares is returned when seed shares do not recover a seed or master
+// key.
+var ErrSeedShares = errors.New("the shares are not shares of a seed")
+
+// SplitSeed splits a wallet generation seed into count Shamir shares, any
+// threshold of which recover the master root key of the wallet with
+// CombineSeedShares.
+func SplitSeed(seed []byte, threshold, count int) ([]string, error) {
+	return splitSeedSecret(seedShareSeed, seed, threshold, count)
+}
+
+// SeedShares splits the master root key of the wallet into count Shamir
+// shares, any threshold of which recover it with CombineSeedShares.  The seed
+// of the wallet is not stored, but the master root key derived from it
+// recovers the same wallet.  The wallet must be unlocked.
+func (w *Wallet) SeedShares(threshold, count int) ([]string, error) {
+	var rootKey *hdkeychain.ExtendedKey
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
+		var err error
+		rootKey, err = w.Manager.RootPrivKey(addrmgrNs)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer rootKey.Zero()
+
+	privKey, err := rootKey.ECPrivKey()
+	if err != nil {
+		return nil, err
+	}
+	key := privKey.Serialize()
+	defer zero.Bytes(key)
+	secret := make([]byte, 0, len(key)*2)
+	secret = append(append(secret, rootKey.ChainCode()...), key...)
+	defer zero.Bytes(secret)
+	return splitSeedSecret(seedShareMasterKey, secret, threshold, count)
+}
+
+// splitSeedSecret splits a secret of the given kind into shares.
+func splitSeedSecret(kind byte, secret []byte, threshold,
+	count int) ([]string, error) {
+
+	data := append([]byte{kind}, secret...)
+	defer zero.Bytes(data)
+	shares, err := shamir.Split(data, threshold, count)
+	if err != nil {
+		return nil, err
+	}
+	encoded := make([]string, len(shares))
+	for i, s := range shares {
+		encoded[i] = s.String()
+		zero.Bytes(s.Value)
+	}
+	return encoded, nil
+}
+
+// CombineSeedShares recovers the master root key of a wallet from the shares
+// written by SplitSeed or SeedShares, which must include at least their
+// threshold.
+func CombineSeedShares(encoded []string,
+	params *chaincfg.Params) (*hdkeychain.ExtendedKey, error) {
+
+	shares := make([]shamir.Share, 0, len(encoded))
+	for _, e := range encoded {
+		if strings.TrimSpace(e) == "" {
+			continue
+		}
+		s, err := shamir.Parse(e)
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, s)
+	}
+	data, err := shamir.Combine(shares)
+	if err != nil {
+		return nil, err
+	}
+	defer zero.Bytes(data)
+
+	switch secret := data[1:]; data[0] {
+	case seedShareSeed:
+		return hdkeychain.NewMaster(secret, params)
+
+	case seedShareMasterKey:
+		if len(secret) != 64 {
+			return nil, ErrSeedShares
+		}
+		// The key keeps the slices it is created with.
+		key := append([]byte{}, secret[32:]...)
+		chainCode := append([]byte{}, secret[:32]...)
+		return hdkeychain.NewExtendedKey(
+			params.HDPrivateKeyID[:], key, chainCode,
+			[]byte{0, 0, 0, 0}, 0, 0, true,
+		), nil
+
+	default:
+		return nil, ErrSeedShares
+	}
+}
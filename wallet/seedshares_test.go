@@ -0,0 +1,51 @@
+package wallet
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lbryio/lbcutil/hdkeychain"
+	"github.com/lbryio/lbcwallet/internal/shamir"
+	"github.com/lbryio/lbcwallet/walletdb"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSeedShares checks that any threshold of the shares of a seed or of the
+// master key of a wallet recover its master key.
+func TestSeedShares(t *testing.T) {
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	seed := bytes.Repeat([]byte{7}, hdkeychain.RecommendedSeedLen)
+	want, err := hdkeychain.NewMaster(seed, w.ChainParams())
+	require.NoError(t, err)
+	shares, err := SplitSeed(seed, 2, 3)
+	require.NoError(t, err)
+	require.Len(t, shares, 3)
+	got, err := CombineSeedShares(shares[1:], w.ChainParams())
+	require.NoError(t, err)
+	require.Equal(t, want.String(), got.String())
+
+	_, err = CombineSeedShares(shares[:1], w.ChainParams())
+	require.ErrorIs(t, err, shamir.ErrTooFew)
+
+	var rootKey *hdkeychain.ExtendedKey
+	err = walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		var err error
+		rootKey, err = w.Manager.RootPrivKey(
+			tx.ReadBucket(waddrmgrNamespaceKey),
+		)
+		return err
+	})
+	require.NoError(t, err)
+	shares, err = w.SeedShares(3, 5)
+	require.NoError(t, err)
+	got, err = CombineSeedShares(
+		[]string{shares[4], "", shares[0], shares[2]}, w.ChainParams(),
+	)
+	require.NoError(t, err)
+	require.Equal(t, rootKey.String(), got.String())
+
+	_, err = w.SeedShares(4, 3)
+	require.ErrorIs(t, err, shamir.ErrThreshold)
+}
@@ -0,0 +1,251 @@
+package wallet
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/wallet/txauthor"
+	"github.com/lbryio/lbcwallet/wallet/txrules"
+	"github.com/lbryio/lbcwallet/wallet/txsizes"
+	"github.com/lbryio/lbcwallet/walletdb"
+)
+
+// PendingSendTimeout is how long a send previewed by PreviewSendOutputs waits
+// to be confirmed before it is canceled.
+const PendingSendTimeout = 10 * time.Minute
+
+// ErrPendingSendNotFound is returned when a handle names no pending send, as
+// one already confirmed, canceled or expired.
+var ErrPendingSendNotFound = errors.New("pending send not found")
+
+// PendingSend is the preview of an unsigned transaction created by
+// PreviewSendOutputs, which is signed and published once confirmed with
+// ConfirmSend.
+type PendingSend struct {
+	// Handle names the send to ConfirmSend and CancelSend.
+	Handle string
+
+	// Tx is the unsigned transaction, and InputValues the values of the
+	// outputs it spends, in the order of its inputs.
+	Tx          *wire.MsgTx
+	InputValues []btcutil.Amount
+
+	// ChangeIndexes are the indexes of the outputs paying the change back
+	// to the wallet.
+	ChangeIndexes []int
+
+	Fee btcutil.Amount
+
+	// VSize is the estimated virtual size of the signed transaction.
+	VSize int
+
+	// Expires is the time the send is canceled unless confirmed.
+	Expires time.Time
+}
+
+// pendingSend is a send waiting to be confirmed.
+type pendingSend struct {
+	preview PendingSend
+	tx      *txauthor.AuthoredTx
+	label   string
+	comment *TxComment
+	timer   *time.Timer
+}
+
+// SetSendPreview sets whether the sends requested over RPC are previewed, to
+// be published only once confirmed, instead of being published at once.
+func (w *Wallet) SetSendPreview(enabled bool) {
+	w.sendPreviewMtx.Lock()
+	w.sendPreview = enabled
+	w.sendPreviewMtx.Unlock()
+}
+
+// SendPreview returns whether sends are previewed, as set by SetSendPreview.
+func (w *Wallet) SendPreview() bool {
+	w.sendPreviewMtx.Lock()
+	defer w.sendPreviewMtx.Unlock()
+	return w.sendPreview
+}
+
+// PreviewSendOutputs creates the transaction SendOutputsWithComment would
+// send, without signing or publishing it, and returns its preview.  The
+// transaction is signed and published with its label and comment by
+// ConfirmSend, or discarded by CancelSend or once PendingSendTimeout has
+// elapsed.  The outputs it spends are locked until then, and its change
+// addresses are not handed out again even when it is discarded.
+func (w *Wallet) PreviewSendOutputs(ctx context.Context,
+	outputs []*wire.TxOut, keyScope *waddrmgr.KeyScope, account uint32,
+	minconf int32, satPerKb btcutil.Amount,
+	coinSelectionStrategy CoinSelectionStrategy, label string,
+	comment *TxComment) (*PendingSend, error) {
+
+	for _, output := range outputs {
+		err := txrules.CheckOutput(
+			output, txrules.DefaultRelayFeePerKb,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tx, err := w.createTx(ctx, createTxRequest{
+		keyScope:              keyScope,
+		account:               account,
+		outputs:               outputs,
+		minconf:               minconf,
+		feeSatPerKB:           satPerKb,
+		coinSelectionStrategy: coinSelectionStrategy,
+		unsigned:              true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var handle [16]byte
+	if _, err := rand.Read(handle[:]); err != nil {
+		w.unlockInputs(tx.Tx)
+		return nil, err
+	}
+	p := &pendingSend{
+		preview: PendingSend{
+			Handle:      hex.EncodeToString(handle[:]),
+			InputValues: tx.PrevInputValues,
+			Fee: tx.TotalInput -
+				txauthor.SumOutputValues(tx.Tx.TxOut),
+			VSize:   estimateSignedVSize(tx),
+			Expires: time.Now().Add(PendingSendTimeout),
+		},
+		tx:      tx,
+		label:   label,
+		comment: comment,
+	}
+	requested := make(map[*wire.TxOut]struct{}, len(outputs))
+	for _, output := range outputs {
+		requested[output] = struct{}{}
+	}
+	for i, output := range tx.Tx.TxOut {
+		if _, ok := requested[output]; !ok {
+			p.preview.ChangeIndexes = append(
+				p.preview.ChangeIndexes, i,
+			)
+		}
+	}
+	w.addPendingSend(p)
+
+	preview := p.preview
+	preview.Tx = tx.Tx.Copy()
+	return &preview, nil
+}
+
+// ConfirmSend signs and publishes the transaction of a send previewed by
+// PreviewSendOutputs.  The send is kept pending when the transaction cannot
+// be signed, as while the wallet is locked.
+func (w *Wallet) ConfirmSend(handle string) (*wire.MsgTx, error) {
+	p := w.takePendingSend(handle)
+	if p == nil {
+		return nil, ErrPendingSendNotFound
+	}
+
+	heldUnlock, err := w.holdUnlock()
+	if err != nil {
+		w.addPendingSend(p)
+		return nil, err
+	}
+	err = walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
+		return w.signAuthoredTx(addrmgrNs, p.tx)
+	})
+	heldUnlock.release()
+	if err != nil {
+		w.addPendingSend(p)
+		return nil, err
+	}
+
+	// Once published, the outputs are spent by a transaction of the
+	// wallet, which keeps them from being spent again.
+	_, err = w.reliablyPublishTransaction(
+		p.tx.Tx, p.label, p.comment, true, false,
+	)
+	w.unlockInputs(p.tx.Tx)
+	if err != nil {
+		return nil, err
+	}
+	return p.tx.Tx, nil
+}
+
+// CancelSend discards a send previewed by PreviewSendOutputs, and unlocks the
+// outputs it spends.
+func (w *Wallet) CancelSend(handle string) error {
+	p := w.takePendingSend(handle)
+	if p == nil {
+		return ErrPendingSendNotFound
+	}
+	w.unlockInputs(p.tx.Tx)
+	return nil
+}
+
+// addPendingSend records a pending send, which is canceled when it expires.
+func (w *Wallet) addPendingSend(p *pendingSend) {
+	handle := p.preview.Handle
+	w.pendingSendsMtx.Lock()
+	defer w.pendingSendsMtx.Unlock()
+
+	if w.pendingSends == nil {
+		w.pendingSends = make(map[string]*pendingSend)
+	}
+	w.pendingSends[handle] = p
+	p.timer = time.AfterFunc(time.Until(p.preview.Expires), func() {
+		if w.CancelSend(handle) == nil {
+			log.Infof("Pending send %v expired", handle)
+		}
+	})
+}
+
+// takePendingSend removes a pending send, or returns nil if there is none by
+// that handle.
+func (w *Wallet) takePendingSend(handle string) *pendingSend {
+	w.pendingSendsMtx.Lock()
+	defer w.pendingSendsMtx.Unlock()
+
+	p, ok := w.pendingSends[handle]
+	if !ok {
+		return nil
+	}
+	p.timer.Stop()
+	delete(w.pendingSends, handle)
+	return p
+}
+
+// unlockInputs unlocks the outputs spent by a transaction.
+func (w *Wallet) unlockInputs(tx *wire.MsgTx) {
+	for _, txIn := range tx.TxIn {
+		w.UnlockOutpoint(txIn.PreviousOutPoint)
+	}
+}
+
+// estimateSignedVSize estimates the virtual size of an unsigned transaction
+// once its inputs are signed, from the scripts of the outputs it spends.
+func estimateSignedVSize(tx *txauthor.AuthoredTx) int {
+	var p2pkh, p2wpkh, nested int
+	for _, pkScript := range tx.PrevScripts {
+		pkScript = txscript.StripClaimScriptPrefix(pkScript)
+		switch {
+		case txscript.IsPayToWitnessPubKeyHash(pkScript):
+			p2wpkh++
+		case txscript.IsPayToScriptHash(pkScript):
+			nested++
+		default:
+			p2pkh++
+		}
+	}
+	return txsizes.EstimateVirtualSize(
+		p2pkh, p2wpkh, nested, tx.Tx.TxOut, 0,
+	)
+}
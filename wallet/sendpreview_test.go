@@ -0,0 +1,83 @@
+package wallet
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSendPreview checks that a previewed send is only signed and published
+// once confirmed, and that its inputs stay locked until it is confirmed or
+// canceled.
+func TestSendPreview(t *testing.T) {
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	addr, err := w.CurrentAddress(0, waddrmgr.KeyScopeBIP0084)
+	require.NoError(t, err)
+	pkScript, err := txscript.PayToAddrScript(addr)
+	require.NoError(t, err)
+	addUtxo(t, w, &wire.MsgTx{
+		TxIn:  []*wire.TxIn{{}},
+		TxOut: []*wire.TxOut{wire.NewTxOut(1000000, pkScript)},
+	})
+
+	preview := func() *PendingSend {
+		p, err := w.PreviewSendOutputs(context.Background(),
+			[]*wire.TxOut{wire.NewTxOut(100000, pkScript)}, nil,
+			0, 1, 1000, CoinSelectionLargest, "",
+			&TxComment{Comment: "preview"},
+		)
+		require.NoError(t, err)
+		return p
+	}
+
+	p := preview()
+	require.Len(t, p.Tx.TxIn, 1)
+	require.Empty(t, p.Tx.TxIn[0].Witness)
+	require.Equal(t, []btcutil.Amount{1000000}, p.InputValues)
+	require.Len(t, p.ChangeIndexes, 1)
+	require.Equal(t, btcutil.Amount(1000000-100000)-
+		btcutil.Amount(p.Tx.TxOut[p.ChangeIndexes[0]].Value), p.Fee)
+	require.Positive(t, p.VSize)
+	op := p.Tx.TxIn[0].PreviousOutPoint
+	require.True(t, w.LockedOutpoint(op))
+
+	// Nothing is published before the send is confirmed, and the locked
+	// input funds no other send.
+	txHash := p.Tx.TxHash()
+	details, err := UnstableAPI(w).TxDetails(&txHash)
+	require.NoError(t, err)
+	require.Nil(t, details)
+	_, err = w.PreviewSendOutputs(context.Background(),
+		[]*wire.TxOut{wire.NewTxOut(100000, pkScript)}, nil, 0, 1,
+		1000, CoinSelectionLargest, "", nil,
+	)
+	require.Error(t, err)
+
+	require.NoError(t, w.CancelSend(p.Handle))
+	require.False(t, w.LockedOutpoint(op))
+	require.ErrorIs(t, w.CancelSend(p.Handle), ErrPendingSendNotFound)
+
+	p = preview()
+	tx, err := w.ConfirmSend(p.Handle)
+	require.NoError(t, err)
+	require.Equal(t, p.Tx.TxHash(), tx.TxHash())
+	require.NotEmpty(t, tx.TxIn[0].Witness)
+	require.False(t, w.LockedOutpoint(op))
+	txHash = tx.TxHash()
+	details, err = UnstableAPI(w).TxDetails(&txHash)
+	require.NoError(t, err)
+	require.NotNil(t, details)
+	comment, err := w.TxComment(&txHash)
+	require.NoError(t, err)
+	require.Equal(t, "preview", comment.Comment)
+
+	_, err = w.ConfirmSend(p.Handle)
+	require.ErrorIs(t, err, ErrPendingSendNotFound)
+}
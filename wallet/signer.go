@@ -11,6 +11,7 @@ import (
 	"github.com/lbryio/lbcd/txscript"
 	"github.com/lbryio/lbcd/wire"
 	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/internal/zero"
 	"github.com/lbryio/lbcwallet/waddrmgr"
 )
 
@@ -101,6 +102,7 @@ func (w *Wallet) ComputeInputScript(tx *wire.MsgTx, output *wire.TxOut,
 	if err != nil {
 		return nil, nil, err
 	}
+	defer zero.BigInt(privKey.D)
 
 	// If we need to maybe tweak our private key, do it now.
 	if tweaker != nil {
@@ -108,6 +110,7 @@ func (w *Wallet) ComputeInputScript(tx *wire.MsgTx, output *wire.TxOut,
 		if err != nil {
 			return nil, nil, err
 		}
+		defer zero.BigInt(privKey.D)
 	}
 
 	// Generate a valid witness stack for the input.
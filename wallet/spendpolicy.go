@@ -0,0 +1,553 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/walletdb"
+)
+
+var (
+	// bucketSpendPolicy is the name of the sub bucket of the wallet
+	// namespace that stores the spend policy and the daily spend totals.
+	bucketSpendPolicy = []byte("spendpolicy")
+
+	// bucketDailySpends is the name of the sub bucket of the spend policy
+	// bucket that maps a UTC day number to the total amount spent to
+	// external addresses on that day.  It also maps the hash of each
+	// transaction counted in the total to its day and amount, so that the
+	// transactions which fail to be published are taken out of it.
+	bucketDailySpends = []byte("daily")
+
+	// keySpendPolicy is the key under which the serialized spend policy
+	// is stored.
+	keySpendPolicy = []byte("policy")
+
+	// ErrSpendPolicyPassphrase is returned when a change loosening the
+	// spend policy is made without the private passphrase of the wallet.
+	ErrSpendPolicyPassphrase = errors.New("the wallet passphrase is " +
+		"required to loosen the spend policy")
+)
+
+// SpendPolicy describes the spending restrictions the wallet enforces on
+// every transaction it publishes.  Only the outputs which do not pay back to
+// addresses the wallet holds the keys of are subject to the policy.  A zero
+// amount disables the respective limit.
+type SpendPolicy struct {
+	// MaxTxAmount is the maximum total value a single transaction may pay
+	// to addresses which are not controlled by the wallet.
+	MaxTxAmount btcutil.Amount
+
+	// DailyLimit is the maximum total value that may be paid to addresses
+	// not controlled by the wallet during a single UTC day.
+	DailyLimit btcutil.Amount
+
+	// Allowlist, if not empty, is the only set of external addresses the
+	// wallet is permitted to pay.
+	Allowlist []string
+
+	// Denylist is a set of addresses the wallet must never pay.
+	Denylist []string
+}
+
+// Enabled returns whether the policy restricts any spend.
+func (p *SpendPolicy) Enabled() bool {
+	return p.MaxTxAmount != 0 || p.DailyLimit != 0 ||
+		len(p.Allowlist) != 0 || len(p.Denylist) != 0
+}
+
+// loosens returns whether p permits a spend prev does not: a limit is raised
+// or removed, the allowlist is cleared or gains an address, or an address is
+// removed from the denylist.  The addresses of both policies must be in the
+// same encoding.
+func (p *SpendPolicy) loosens(prev *SpendPolicy) bool {
+	raised := func(limit, prevLimit btcutil.Amount) bool {
+		return prevLimit != 0 && (limit == 0 || limit > prevLimit)
+	}
+	// missing returns whether any address of list is not in of.
+	missing := func(list, of []string) bool {
+		set := make(map[string]struct{}, len(of))
+		for _, addr := range of {
+			set[addr] = struct{}{}
+		}
+		for _, addr := range list {
+			if _, ok := set[addr]; !ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch {
+	case raised(p.MaxTxAmount, prev.MaxTxAmount),
+		raised(p.DailyLimit, prev.DailyLimit):
+		return true
+	case len(prev.Allowlist) != 0 && (len(p.Allowlist) == 0 ||
+		missing(p.Allowlist, prev.Allowlist)):
+		return true
+	default:
+		return missing(prev.Denylist, p.Denylist)
+	}
+}
+
+// ErrSpendPolicy is returned when publishing a transaction would violate the
+// wallet's spend policy.
+type ErrSpendPolicy struct {
+	reason string
+}
+
+// Error returns a human readable description of the policy violation.
+func (e *ErrSpendPolicy) Error() string {
+	return "spend policy violation: " + e.reason
+}
+
+// serializeSpendPolicy returns the serialization of a spend policy:
+//
+//	[0:8]   max tx amount (8 bytes)
+//	[8:16]  daily limit (8 bytes)
+//	[16:]   allowlist, then denylist, each as a varint count of varstrings
+func serializeSpendPolicy(p *SpendPolicy) ([]byte, error) {
+	var buf bytes.Buffer
+	var amounts [16]byte
+	binary.BigEndian.PutUint64(amounts[0:8], uint64(p.MaxTxAmount))
+	binary.BigEndian.PutUint64(amounts[8:16], uint64(p.DailyLimit))
+	buf.Write(amounts[:])
+
+	for _, list := range [][]string{p.Allowlist, p.Denylist} {
+		err := wire.WriteVarInt(&buf, 0, uint64(len(list)))
+		if err != nil {
+			return nil, err
+		}
+		for _, addr := range list {
+			if err := wire.WriteVarString(&buf, 0, addr); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// deserializeSpendPolicy decodes a spend policy serialized by
+// serializeSpendPolicy.
+func deserializeSpendPolicy(v []byte) (*SpendPolicy, error) {
+	if len(v) < 16 {
+		return nil, fmt.Errorf("short spend policy: %d bytes", len(v))
+	}
+
+	p := &SpendPolicy{
+		MaxTxAmount: btcutil.Amount(binary.BigEndian.Uint64(v[0:8])),
+		DailyLimit:  btcutil.Amount(binary.BigEndian.Uint64(v[8:16])),
+	}
+
+	r := bytes.NewReader(v[16:])
+	readList := func() ([]string, error) {
+		n, err := wire.ReadVarInt(r, 0)
+		if err != nil {
+			return nil, err
+		}
+		if n > uint64(r.Len()) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		list := make([]string, 0, n)
+		for i := uint64(0); i < n; i++ {
+			addr, err := wire.ReadVarString(r, 0)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, addr)
+		}
+		return list, nil
+	}
+
+	var err error
+	if p.Allowlist, err = readList(); err != nil {
+		return nil, err
+	}
+	if p.Denylist, err = readList(); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// spendDay returns the UTC day number of t used to key the daily spend
+// totals.
+func spendDay(t time.Time) []byte {
+	var k [4]byte
+	binary.BigEndian.PutUint32(k[:], uint32(t.Unix()/(24*60*60)))
+	return k[:]
+}
+
+// fetchSpendPolicy returns the stored spend policy, or an empty policy if
+// none has been set.
+func fetchSpendPolicy(ns walletdb.ReadBucket) (*SpendPolicy, error) {
+	policyBucket := ns.NestedReadBucket(bucketSpendPolicy)
+	if policyBucket == nil {
+		return &SpendPolicy{}, nil
+	}
+	v := policyBucket.Get(keySpendPolicy)
+	if v == nil {
+		return &SpendPolicy{}, nil
+	}
+	return deserializeSpendPolicy(v)
+}
+
+// fetchSpentOnDay returns the total amount paid to external addresses during
+// the given day.
+func fetchSpentOnDay(ns walletdb.ReadBucket, day []byte) btcutil.Amount {
+	policyBucket := ns.NestedReadBucket(bucketSpendPolicy)
+	if policyBucket == nil {
+		return 0
+	}
+	dailyBucket := policyBucket.NestedReadBucket(bucketDailySpends)
+	if dailyBucket == nil {
+		return 0
+	}
+	v := dailyBucket.Get(day)
+	if len(v) != 8 {
+		return 0
+	}
+	return btcutil.Amount(binary.BigEndian.Uint64(v))
+}
+
+// putSpentOnDay adds the amount paid to external addresses by the transaction
+// to the total spent during the given day.  Totals and transactions of all
+// other days are removed since they no longer affect any limit.
+func putSpentOnDay(ns walletdb.ReadWriteBucket, day []byte,
+	txHash *chainhash.Hash, amt btcutil.Amount) error {
+
+	policyBucket, err := ns.CreateBucketIfNotExists(bucketSpendPolicy)
+	if err != nil {
+		return err
+	}
+	dailyBucket, err := policyBucket.CreateBucketIfNotExists(
+		bucketDailySpends,
+	)
+	if err != nil {
+		return err
+	}
+
+	var stale [][]byte
+	err = dailyBucket.ForEach(func(k, v []byte) error {
+		switch {
+		case len(k) == len(day) && !bytes.Equal(k, day):
+			stale = append(stale, k)
+		case len(k) == chainhash.HashSize &&
+			(len(v) != 12 || !bytes.Equal(v[:4], day)):
+
+			stale = append(stale, k)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, k := range stale {
+		if err := dailyBucket.Delete(k); err != nil {
+			return err
+		}
+	}
+
+	var total, txSpend [8]byte
+	spent := fetchSpentOnDay(ns, day)
+	binary.BigEndian.PutUint64(total[:], uint64(spent+amt))
+	if err := dailyBucket.Put(day, total[:]); err != nil {
+		return err
+	}
+	binary.BigEndian.PutUint64(txSpend[:], uint64(amt))
+	return dailyBucket.Put(txHash[:], append(day[:4:4], txSpend[:]...))
+}
+
+// removeSpentOnDay takes the amount paid to external addresses by the
+// transaction out of the total of the day it was counted in, if it is still
+// the current one.
+func removeSpentOnDay(ns walletdb.ReadWriteBucket,
+	txHash *chainhash.Hash) error {
+
+	policyBucket := ns.NestedReadWriteBucket(bucketSpendPolicy)
+	if policyBucket == nil {
+		return nil
+	}
+	dailyBucket := policyBucket.NestedReadWriteBucket(bucketDailySpends)
+	if dailyBucket == nil {
+		return nil
+	}
+	v := dailyBucket.Get(txHash[:])
+	if len(v) != 12 {
+		return nil
+	}
+	day := append([]byte(nil), v[:4]...)
+	amt := btcutil.Amount(binary.BigEndian.Uint64(v[4:]))
+	if err := dailyBucket.Delete(txHash[:]); err != nil {
+		return err
+	}
+
+	spent := fetchSpentOnDay(ns, day)
+	if spent == 0 {
+		return nil
+	}
+	if amt > spent {
+		amt = spent
+	}
+	var total [8]byte
+	binary.BigEndian.PutUint64(total[:], uint64(spent-amt))
+	return dailyBucket.Put(day, total[:])
+}
+
+// check returns an ErrSpendPolicy if paying the given amounts to the
+// destination addresses, in addition to spentToday, violates the policy.  An
+// empty destination describes an output whose address could not be
+// determined.
+func (p *SpendPolicy) check(destinations map[string]btcutil.Amount,
+	spentToday btcutil.Amount) error {
+
+	var total btcutil.Amount
+	for dest, amt := range destinations {
+		total += amt
+
+		for _, denied := range p.Denylist {
+			if dest == denied {
+				return &ErrSpendPolicy{
+					fmt.Sprintf("address %s is denied", dest),
+				}
+			}
+		}
+
+		if len(p.Allowlist) == 0 {
+			continue
+		}
+		allowed := false
+		for _, a := range p.Allowlist {
+			if dest == a {
+				allowed = true
+				break
+			}
+		}
+		switch {
+		case allowed:
+		case dest == "":
+			return &ErrSpendPolicy{"output to an unknown " +
+				"address is not on the allowlist"}
+		default:
+			return &ErrSpendPolicy{
+				fmt.Sprintf("address %s is not on the "+
+					"allowlist", dest),
+			}
+		}
+	}
+
+	if p.MaxTxAmount != 0 && total > p.MaxTxAmount {
+		return &ErrSpendPolicy{fmt.Sprintf("transaction amount %v "+
+			"exceeds the per-transaction limit of %v", total,
+			p.MaxTxAmount)}
+	}
+	if p.DailyLimit != 0 && spentToday+total > p.DailyLimit {
+		return &ErrSpendPolicy{fmt.Sprintf("transaction amount %v "+
+			"exceeds the remaining daily limit of %v", total,
+			p.DailyLimit-spentToday)}
+	}
+
+	return nil
+}
+
+// externalOutputs returns the value paid by tx to each destination address
+// the wallet can not spend from, which includes its watch-only addresses.
+// Outputs with a script the address of which cannot be extracted are
+// reported under the empty string.
+func (w *Wallet) externalOutputs(addrmgrNs walletdb.ReadBucket,
+	tx *wire.MsgTx) (map[string]btcutil.Amount, error) {
+
+	destinations := make(map[string]btcutil.Amount)
+	for _, txOut := range tx.TxOut {
+		if txOut.Value == 0 {
+			continue
+		}
+
+		_, addrs, _, err := txscript.ExtractPkScriptAddrs(
+			txOut.PkScript, w.chainParams,
+		)
+		if err != nil || len(addrs) == 0 {
+			destinations[""] += btcutil.Amount(txOut.Value)
+			continue
+		}
+
+		// Watch-only addresses may be imported by the same callers
+		// the policy restrains, so only the outputs the wallet holds
+		// the keys of are exempt.
+		ours := false
+		for _, addr := range addrs {
+			maddr, err := w.Manager.Address(addrmgrNs, addr)
+			if waddrmgr.IsError(err, waddrmgr.ErrAddressNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			ours, err = w.Manager.HasPrivateKey(addrmgrNs, maddr)
+			if err != nil {
+				return nil, err
+			}
+			if ours {
+				break
+			}
+		}
+		if !ours {
+			dest := addrs[0].EncodeAddress()
+			destinations[dest] += btcutil.Amount(txOut.Value)
+		}
+	}
+
+	return destinations, nil
+}
+
+// applySpendPolicy checks tx against the wallet's spend policy and, if it is
+// permitted, adds its external outputs to the total spent today.  It must be
+// called within the database transaction that records tx as published, and
+// revertSpendPolicy called within the one removing it if it fails to be
+// published.
+func (w *Wallet) applySpendPolicy(dbtx walletdb.ReadWriteTx,
+	tx *wire.MsgTx) error {
+
+	ns := dbtx.ReadWriteBucket(walletNamespaceKey)
+	policy, err := fetchSpendPolicy(ns)
+	if err != nil {
+		return err
+	}
+
+	destinations, err := w.externalOutputs(
+		dbtx.ReadBucket(waddrmgrNamespaceKey), tx,
+	)
+	if err != nil {
+		return err
+	}
+
+	day := spendDay(w.now())
+	spentToday := fetchSpentOnDay(ns, day)
+	if err := policy.check(destinations, spentToday); err != nil {
+		return err
+	}
+
+	var total btcutil.Amount
+	for _, amt := range destinations {
+		total += amt
+	}
+	if total == 0 {
+		return nil
+	}
+	txHash := tx.TxHash()
+	return putSpentOnDay(ns, day, &txHash, total)
+}
+
+// revertSpendPolicy takes the external outputs of tx, which failed to be
+// published, out of the total spent on the day it was published.
+func revertSpendPolicy(dbtx walletdb.ReadWriteTx,
+	txHash *chainhash.Hash) error {
+
+	ns := dbtx.ReadWriteBucket(walletNamespaceKey)
+	return removeSpentOnDay(ns, txHash)
+}
+
+// SpendPolicy returns the wallet's current spend policy along with the total
+// amount paid to external addresses so far today.
+func (w *Wallet) SpendPolicy() (*SpendPolicy, btcutil.Amount, error) {
+	var (
+		policy     *SpendPolicy
+		spentToday btcutil.Amount
+	)
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		ns := tx.ReadBucket(walletNamespaceKey)
+
+		var err error
+		policy, err = fetchSpendPolicy(ns)
+		if err != nil {
+			return err
+		}
+		spentToday = fetchSpentOnDay(ns, spendDay(w.now()))
+		return nil
+	})
+	return policy, spentToday, err
+}
+
+// SetSpendPolicy replaces the wallet's spend policy.  All addresses of the
+// allow and deny lists must be valid for the wallet's network, and are stored
+// in the encoding the outputs of transactions are checked with.
+//
+// The policy restrains the callers of the wallet which don't know its private
+// passphrase, so changes loosening the policy fail with
+// ErrSpendPolicyPassphrase unless passphrase is given, and with
+// ErrWrongPassphrase if it is not the private passphrase.  Tightening the
+// policy doesn't require it, and passphrase may be nil.
+func (w *Wallet) SetSpendPolicy(policy *SpendPolicy, passphrase []byte) error {
+	if policy.MaxTxAmount < 0 || policy.DailyLimit < 0 {
+		return fmt.Errorf("spend limits must not be negative")
+	}
+	normalize := func(list []string) ([]string, error) {
+		encoded := make([]string, 0, len(list))
+		for _, s := range list {
+			addr, err := btcutil.DecodeAddress(s, w.chainParams)
+			if err != nil {
+				return nil, fmt.Errorf("invalid address %q: %v",
+					s, err)
+			}
+			if !addr.IsForNet(w.chainParams) {
+				return nil, fmt.Errorf("address %q is not "+
+					"intended for use on %s", s,
+					w.chainParams.Name)
+			}
+			encoded = append(encoded, addr.EncodeAddress())
+		}
+		return encoded, nil
+	}
+	normalized := *policy
+	var err error
+	normalized.Allowlist, err = normalize(policy.Allowlist)
+	if err != nil {
+		return err
+	}
+	normalized.Denylist, err = normalize(policy.Denylist)
+	if err != nil {
+		return err
+	}
+
+	v, err := serializeSpendPolicy(&normalized)
+	if err != nil {
+		return err
+	}
+
+	// The passphrase is checked outside of the database transaction, as
+	// deriving the key from it is slow.
+	if passphrase != nil {
+		if err := w.Manager.CheckPassphrase(passphrase); err != nil {
+			return err
+		}
+	}
+
+	return walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(walletNamespaceKey)
+		prev, err := fetchSpendPolicy(ns)
+		if err != nil {
+			return err
+		}
+		if passphrase == nil && normalized.loosens(prev) {
+			return ErrSpendPolicyPassphrase
+		}
+
+		policyBucket, err := ns.CreateBucketIfNotExists(
+			bucketSpendPolicy,
+		)
+		if err != nil {
+			return err
+		}
+		return policyBucket.Put(keySpendPolicy, v)
+	})
+}
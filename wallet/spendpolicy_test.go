@@ -0,0 +1,425 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lbryio/lbcd/btcec"
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+)
+
+// TestSpendPolicySerialization ensures a spend policy survives a round trip
+// through its database serialization.
+func TestSpendPolicySerialization(t *testing.T) {
+	t.Parallel()
+
+	policy := &SpendPolicy{
+		MaxTxAmount: 5 * btcutil.SatoshiPerBitcoin,
+		DailyLimit:  20 * btcutil.SatoshiPerBitcoin,
+		Allowlist:   []string{"a", "b"},
+		Denylist:    []string{"c"},
+	}
+	v, err := serializeSpendPolicy(policy)
+	if err != nil {
+		t.Fatalf("unable to serialize policy: %v", err)
+	}
+	got, err := deserializeSpendPolicy(v)
+	if err != nil {
+		t.Fatalf("unable to deserialize policy: %v", err)
+	}
+	if !reflect.DeepEqual(got, policy) {
+		t.Fatalf("policy mismatch: want %v, got %v", policy, got)
+	}
+
+	if _, err := deserializeSpendPolicy(v[:10]); err == nil {
+		t.Fatalf("expected error deserializing short policy")
+	}
+}
+
+// TestSpendPolicyCheck ensures the limits and address lists of a spend policy
+// are enforced.
+func TestSpendPolicyCheck(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		policy       SpendPolicy
+		destinations map[string]btcutil.Amount
+		spentToday   btcutil.Amount
+		valid        bool
+	}{
+		{
+			name:         "empty policy",
+			destinations: map[string]btcutil.Amount{"a": 1e8, "": 1},
+			valid:        true,
+		},
+		{
+			name:         "under tx limit",
+			policy:       SpendPolicy{MaxTxAmount: 2e8},
+			destinations: map[string]btcutil.Amount{"a": 1e8, "b": 1e8},
+			valid:        true,
+		},
+		{
+			name:         "over tx limit",
+			policy:       SpendPolicy{MaxTxAmount: 2e8},
+			destinations: map[string]btcutil.Amount{"a": 1e8, "b": 2e8},
+		},
+		{
+			name:         "over daily limit",
+			policy:       SpendPolicy{DailyLimit: 2e8},
+			destinations: map[string]btcutil.Amount{"a": 1e8},
+			spentToday:   15e7,
+		},
+		{
+			name:         "denied address",
+			policy:       SpendPolicy{Denylist: []string{"b"}},
+			destinations: map[string]btcutil.Amount{"b": 1},
+		},
+		{
+			name:         "allowed address",
+			policy:       SpendPolicy{Allowlist: []string{"a"}},
+			destinations: map[string]btcutil.Amount{"a": 1},
+			valid:        true,
+		},
+		{
+			name:         "address not allowed",
+			policy:       SpendPolicy{Allowlist: []string{"a"}},
+			destinations: map[string]btcutil.Amount{"b": 1},
+		},
+		{
+			name:         "unknown address not allowed",
+			policy:       SpendPolicy{Allowlist: []string{"a"}},
+			destinations: map[string]btcutil.Amount{"": 1},
+		},
+	}
+
+	for _, test := range tests {
+		err := test.policy.check(test.destinations, test.spentToday)
+		switch {
+		case test.valid && err != nil:
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+		case !test.valid && err == nil:
+			t.Errorf("%s: expected policy violation", test.name)
+		case err != nil:
+			if _, ok := err.(*ErrSpendPolicy); !ok {
+				t.Errorf("%s: unexpected error type %T",
+					test.name, err)
+			}
+		}
+	}
+}
+
+// TestSetSpendPolicy ensures the spend policy is persisted and that invalid
+// addresses are rejected.
+func TestSetSpendPolicy(t *testing.T) {
+	t.Parallel()
+
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	addr, err := w.CurrentAddress(0, waddrmgr.DefaultKeyScope)
+	if err != nil {
+		t.Fatalf("unable to get address: %v", err)
+	}
+
+	policy := &SpendPolicy{
+		MaxTxAmount: 1e8,
+		Denylist:    []string{addr.EncodeAddress()},
+	}
+	if err := w.SetSpendPolicy(policy, nil); err != nil {
+		t.Fatalf("unable to set policy: %v", err)
+	}
+	got, spent, err := w.SpendPolicy()
+	if err != nil {
+		t.Fatalf("unable to fetch policy: %v", err)
+	}
+	if got.MaxTxAmount != policy.MaxTxAmount ||
+		!reflect.DeepEqual(got.Denylist, policy.Denylist) || spent != 0 {
+
+		t.Fatalf("unexpected policy %v (spent %v)", got, spent)
+	}
+
+	invalid := &SpendPolicy{Allowlist: []string{"invalid"}}
+	err = w.SetSpendPolicy(invalid, nil)
+	if err == nil {
+		t.Fatalf("expected error setting invalid allowlist")
+	}
+
+	// The addresses are stored in the encoding the outputs are checked
+	// with.  Removing the limit and denylist loosens the policy, which
+	// requires the passphrase.
+	segwitAddr, err := w.CurrentAddress(0, waddrmgr.KeyScopeBIP0084)
+	if err != nil {
+		t.Fatalf("unable to get address: %v", err)
+	}
+	upper := strings.ToUpper(segwitAddr.EncodeAddress())
+	policy = &SpendPolicy{Allowlist: []string{upper}}
+	if err := w.SetSpendPolicy(policy, []byte("hello world")); err != nil {
+		t.Fatalf("unable to set policy: %v", err)
+	}
+	got, _, err = w.SpendPolicy()
+	if err != nil {
+		t.Fatalf("unable to fetch policy: %v", err)
+	}
+	want := []string{segwitAddr.EncodeAddress()}
+	if !reflect.DeepEqual(got.Allowlist, want) {
+		t.Fatalf("got allowlist %v, want %v", got.Allowlist, want)
+	}
+}
+
+// TestSpendPolicyPublishFailure ensures the transactions which fail to be
+// published don't count towards the daily limit.
+func TestSpendPolicyPublishFailure(t *testing.T) {
+	t.Parallel()
+
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	addr, err := w.CurrentAddress(0, waddrmgr.KeyScopeBIP0084)
+	if err != nil {
+		t.Fatalf("unable to get address: %v", err)
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatalf("unable to create script: %v", err)
+	}
+	addUtxo(t, w, &wire.MsgTx{
+		TxIn:  []*wire.TxIn{{}},
+		TxOut: []*wire.TxOut{wire.NewTxOut(1000000, pkScript)},
+	})
+
+	external, err := btcutil.NewAddressWitnessPubKeyHash(
+		make([]byte, 20), w.ChainParams(),
+	)
+	if err != nil {
+		t.Fatalf("unable to create address: %v", err)
+	}
+	externalScript, err := txscript.PayToAddrScript(external)
+	if err != nil {
+		t.Fatalf("unable to create script: %v", err)
+	}
+	outputs := []*wire.TxOut{wire.NewTxOut(100000, externalScript)}
+
+	err = w.SetSpendPolicy(&SpendPolicy{DailyLimit: 1e8}, nil)
+	if err != nil {
+		t.Fatalf("unable to set policy: %v", err)
+	}
+
+	var broadcastErr error
+	w.SetBroadcastPolicy(BroadcastPolicy{
+		Broadcast: func(*wire.MsgTx) error {
+			return broadcastErr
+		},
+	})
+
+	broadcastErr = errors.New("rejected")
+	_, err = w.SendOutputs(context.Background(), outputs, nil, 0, 1, 1000,
+		CoinSelectionLargest, "")
+	if err == nil {
+		t.Fatalf("expected the broadcast to fail")
+	}
+	if _, spent, err := w.SpendPolicy(); err != nil || spent != 0 {
+		t.Fatalf("got %v spent today (%v), want 0", spent, err)
+	}
+
+	broadcastErr = nil
+	_, err = w.SendOutputs(context.Background(), outputs, nil, 0, 1, 1000,
+		CoinSelectionLargest, "")
+	if err != nil {
+		t.Fatalf("unable to send: %v", err)
+	}
+	if _, spent, err := w.SpendPolicy(); err != nil || spent != 100000 {
+		t.Fatalf("got %v spent today (%v), want 100000", spent, err)
+	}
+}
+
+// TestSpendPolicyWatchOnly ensures the outputs paying watch-only addresses of
+// the wallet are subject to the spend policy, unlike those paying addresses
+// it holds the keys of.
+func TestSpendPolicyWatchOnly(t *testing.T) {
+	t.Parallel()
+
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	addr, err := w.CurrentAddress(0, waddrmgr.KeyScopeBIP0084)
+	if err != nil {
+		t.Fatalf("unable to get address: %v", err)
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatalf("unable to create script: %v", err)
+	}
+	addUtxo(t, w, &wire.MsgTx{
+		TxIn:  []*wire.TxIn{{}},
+		TxOut: []*wire.TxOut{wire.NewTxOut(1000000, pkScript)},
+	})
+
+	privKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to create key: %v", err)
+	}
+	watchAddr, err := w.ImportWatchOnlyPublicKey(
+		privKey.PubKey(), waddrmgr.WitnessPubKey, nil,
+	)
+	if err != nil {
+		t.Fatalf("unable to import public key: %v", err)
+	}
+	watchScript, err := txscript.PayToAddrScript(watchAddr)
+	if err != nil {
+		t.Fatalf("unable to create script: %v", err)
+	}
+
+	policy := &SpendPolicy{MaxTxAmount: 1000, DailyLimit: 1000}
+	if err := w.SetSpendPolicy(policy, nil); err != nil {
+		t.Fatalf("unable to set policy: %v", err)
+	}
+
+	outputs := []*wire.TxOut{wire.NewTxOut(100000, watchScript)}
+	_, err = w.SendOutputs(context.Background(), outputs, nil, 0, 1, 1000,
+		CoinSelectionLargest, "")
+	if _, ok := err.(*ErrSpendPolicy); !ok {
+		t.Fatalf("send to a watch-only address returned %v, want a "+
+			"spend policy violation", err)
+	}
+
+	outputs = []*wire.TxOut{wire.NewTxOut(100000, pkScript)}
+	_, err = w.SendOutputs(context.Background(), outputs, nil, 0, 1, 1000,
+		CoinSelectionLargest, "")
+	if err != nil {
+		t.Fatalf("unable to send to the wallet: %v", err)
+	}
+	if _, spent, err := w.SpendPolicy(); err != nil || spent != 0 {
+		t.Fatalf("got %v spent today (%v), want 0", spent, err)
+	}
+}
+
+// TestSpendPolicyLoosen ensures the changes loosening the spend policy
+// require the private passphrase, even while the wallet is locked, unlike
+// those tightening it.
+func TestSpendPolicyLoosen(t *testing.T) {
+	t.Parallel()
+
+	w, cleanup := testWallet(t)
+	defer cleanup()
+	w.Lock()
+
+	addrs := make([]string, 3)
+	for i := range addrs {
+		addr, err := btcutil.NewAddressWitnessPubKeyHash(
+			[]byte{byte(i), 19: 0}, w.ChainParams(),
+		)
+		if err != nil {
+			t.Fatalf("unable to create address: %v", err)
+		}
+		addrs[i] = addr.EncodeAddress()
+	}
+	base := SpendPolicy{
+		MaxTxAmount: 1e8,
+		DailyLimit:  2e8,
+		Allowlist:   addrs[:2],
+		Denylist:    addrs[2:],
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(p *SpendPolicy)
+		loosens bool
+	}{
+		{
+			name:   "unchanged",
+			modify: func(*SpendPolicy) {},
+		},
+		{
+			name: "lower limits",
+			modify: func(p *SpendPolicy) {
+				p.MaxTxAmount, p.DailyLimit = 1, 1
+			},
+		},
+		{
+			name: "remove allowed address",
+			modify: func(p *SpendPolicy) {
+				p.Allowlist = addrs[:1]
+			},
+		},
+		{
+			name:   "add denied address",
+			modify: func(p *SpendPolicy) { p.Denylist = addrs },
+		},
+		{
+			name:    "raise max tx amount",
+			modify:  func(p *SpendPolicy) { p.MaxTxAmount = 2e8 },
+			loosens: true,
+		},
+		{
+			name:    "clear daily limit",
+			modify:  func(p *SpendPolicy) { p.DailyLimit = 0 },
+			loosens: true,
+		},
+		{
+			name:    "add allowed address",
+			modify:  func(p *SpendPolicy) { p.Allowlist = addrs },
+			loosens: true,
+		},
+		{
+			name:    "clear allowlist",
+			modify:  func(p *SpendPolicy) { p.Allowlist = nil },
+			loosens: true,
+		},
+		{
+			name:    "clear denylist",
+			modify:  func(p *SpendPolicy) { p.Denylist = nil },
+			loosens: true,
+		},
+	}
+
+	for _, test := range tests {
+		err := w.SetSpendPolicy(&base, []byte("hello world"))
+		if err != nil {
+			t.Fatalf("%s: unable to reset policy: %v", test.name,
+				err)
+		}
+		policy := base
+		test.modify(&policy)
+
+		err = w.SetSpendPolicy(&policy, nil)
+		switch {
+		case test.loosens && err != ErrSpendPolicyPassphrase:
+			t.Fatalf("%s: got error %v without passphrase, want %v",
+				test.name, err, ErrSpendPolicyPassphrase)
+		case !test.loosens && err != nil:
+			t.Fatalf("%s: unable to set policy: %v", test.name, err)
+		case !test.loosens:
+			continue
+		}
+
+		err = w.SetSpendPolicy(&policy, []byte("wrong"))
+		if !waddrmgr.IsError(err, waddrmgr.ErrWrongPassphrase) {
+			t.Fatalf("%s: got error %v with a wrong passphrase",
+				test.name, err)
+		}
+		got, _, err := w.SpendPolicy()
+		if err != nil {
+			t.Fatalf("%s: unable to fetch policy: %v", test.name,
+				err)
+		}
+		if !reflect.DeepEqual(got, &base) {
+			t.Fatalf("%s: policy changed to %v", test.name, got)
+		}
+
+		err = w.SetSpendPolicy(&policy, []byte("hello world"))
+		if err != nil {
+			t.Fatalf("%s: unable to set policy with passphrase: %v",
+				test.name, err)
+		}
+	}
+	if !w.Locked() {
+		t.Fatal("checking the passphrase unlocked the wallet")
+	}
+}
@@ -0,0 +1,83 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lbryio/lbcd/txscript"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcutil/psbt"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/wallet/txauthor"
+	"github.com/lbryio/lbcwallet/walletdb"
+	"github.com/lbryio/lbcwallet/wtxmgr"
+)
+
+// ErrNothingToSweep is returned when an account has no spendable outputs
+// worth more than the fee of spending them.
+var ErrNothingToSweep = errors.New("no spendable outputs to sweep")
+
+// SweepAccountPsbt creates an unsigned PSBT packet spending every eligible
+// output of an account to a single output paying to the given address, less
+// the fee at the given rate.  The inputs carry their UTXO information and
+// BIP0032 derivation paths, so the packet can be signed by the holder of the
+// account's keys, such as a seed kept offline while the wallet only watches
+// the account.  The fee and the amount of the sweep output are returned with
+// the packet.
+//
+// NOTE: The inputs of the packet are not locked.
+func (w *Wallet) SweepAccountPsbt(keyScope *waddrmgr.KeyScope, account uint32,
+	addr btcutil.Address, minConf int32, feeSatPerKB btcutil.Amount) (
+	*psbt.Packet, btcutil.Amount, btcutil.Amount, error) {
+
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	var credits []wtxmgr.Credit
+	err = walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		bs := w.Manager.SyncedTo()
+		credits, err = w.findEligibleOutputs(
+			dbtx, keyScope, account, minConf, &bs,
+		)
+		return err
+	})
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if len(credits) == 0 {
+		return nil, 0, 0, ErrNothingToSweep
+	}
+
+	// Without any other output, the whole value of the inputs less the fee
+	// goes to the "change" output paying to the sweep address, which is
+	// dropped when it would be dust.
+	tx, err := txauthor.NewUnsignedTransaction(
+		nil, feeSatPerKB, constantInputSource(credits),
+		&txauthor.ChangeSource{
+			NewScript: func() ([]byte, error) {
+				return pkScript, nil
+			},
+			ScriptSize: len(pkScript),
+		},
+	)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("fee estimation not successful: %v",
+			err)
+	}
+	if tx.ChangeIndex < 0 {
+		return nil, 0, 0, ErrNothingToSweep
+	}
+
+	packet, err := psbt.NewFromUnsignedTx(tx.Tx)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if err := w.addPsbtInputInfo(packet, packet.UnsignedTx.TxIn); err != nil {
+		return nil, 0, 0, err
+	}
+
+	amount := btcutil.Amount(tx.Tx.TxOut[tx.ChangeIndex].Value)
+	return packet, tx.TotalInput - amount, amount, nil
+}
@@ -0,0 +1,193 @@
+package wallet
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/lbryio/lbcd/chaincfg"
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/walletdb"
+)
+
+var (
+	// bucketTaintedOutputs is the name of the sub bucket of the wallet
+	// namespace that maps the serialized outpoints of tainted outputs to
+	// the reason they were tainted.
+	bucketTaintedOutputs = []byte("taintedoutputs")
+
+	// bucketTaintedAddresses is the name of the sub bucket of the wallet
+	// namespace that maps the encoded tainted addresses to the reason they
+	// were tainted.
+	bucketTaintedAddresses = []byte("taintedaddrs")
+)
+
+// Taints are the outputs and the addresses which were marked as tainted, for
+// instance because they were received in a dust attack.  Tainted outputs, and
+// every output paying to a tainted address, are never chosen by coin
+// selection, so that they are only spent by the transactions which
+// explicitly spend them.  Unlike locked outputs, taints are kept in the
+// database.
+type Taints struct {
+	// Outputs and Addresses map the tainted outputs and the encoded
+	// tainted addresses to the reason they were tainted, which may be
+	// empty.
+	Outputs   map[wire.OutPoint]string
+	Addresses map[string]string
+}
+
+// Reason returns the reason an output paying to the encoded address was
+// tainted, and false if it is not tainted.  A taint of the output takes
+// precedence over a taint of its address.
+func (t *Taints) Reason(op wire.OutPoint, address string) (string, bool) {
+	if reason, ok := t.Outputs[op]; ok {
+		return reason, true
+	}
+	reason, ok := t.Addresses[address]
+	return reason, ok
+}
+
+// serializeOutPoint returns the key of an outpoint in the bucket of tainted
+// outputs:
+//
+//	[0:32]  transaction hash (32 bytes)
+//	[32:36] output index (4 bytes)
+func serializeOutPoint(op *wire.OutPoint) []byte {
+	k := make([]byte, chainhash.HashSize+4)
+	copy(k, op.Hash[:])
+	binary.BigEndian.PutUint32(k[chainhash.HashSize:], op.Index)
+	return k
+}
+
+// deserializeOutPoint decodes an outpoint serialized by serializeOutPoint.
+func deserializeOutPoint(k []byte) (wire.OutPoint, error) {
+	var op wire.OutPoint
+	if len(k) != chainhash.HashSize+4 {
+		return op, fmt.Errorf("invalid tainted output key: %d bytes",
+			len(k))
+	}
+	copy(op.Hash[:], k)
+	op.Index = binary.BigEndian.Uint32(k[chainhash.HashSize:])
+	return op, nil
+}
+
+// SetOutputTaint taints outputs with the given reason, or clears their taint
+// when tainted is false.
+func (w *Wallet) SetOutputTaint(ops []wire.OutPoint, tainted bool,
+	reason string) error {
+
+	return walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(walletNamespaceKey)
+		bucket, err := ns.CreateBucketIfNotExists(bucketTaintedOutputs)
+		if err != nil {
+			return err
+		}
+		for i := range ops {
+			k := serializeOutPoint(&ops[i])
+			if tainted {
+				err = bucket.Put(k, []byte(reason))
+			} else {
+				err = bucket.Delete(k)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SetAddressTaint taints every output paying to addresses, including those
+// received later, with the given reason, or clears the taint of the addresses
+// when tainted is false.
+func (w *Wallet) SetAddressTaint(addrs []btcutil.Address, tainted bool,
+	reason string) error {
+
+	return walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(walletNamespaceKey)
+		bucket, err := ns.CreateBucketIfNotExists(bucketTaintedAddresses)
+		if err != nil {
+			return err
+		}
+		for _, addr := range addrs {
+			k := []byte(addr.EncodeAddress())
+			if tainted {
+				err = bucket.Put(k, []byte(reason))
+			} else {
+				err = bucket.Delete(k)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Taints returns the tainted outputs and addresses.
+func (w *Wallet) Taints() (*Taints, error) {
+	var taints *Taints
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		var err error
+		taints, err = fetchTaints(tx.ReadBucket(walletNamespaceKey))
+		return err
+	})
+	return taints, err
+}
+
+// fetchTaints reads the tainted outputs and addresses from the wallet
+// namespace.
+func fetchTaints(ns walletdb.ReadBucket) (*Taints, error) {
+	taints := &Taints{
+		Outputs:   make(map[wire.OutPoint]string),
+		Addresses: make(map[string]string),
+	}
+	if bucket := ns.NestedReadBucket(bucketTaintedOutputs); bucket != nil {
+		err := bucket.ForEach(func(k, v []byte) error {
+			op, err := deserializeOutPoint(k)
+			if err != nil {
+				return err
+			}
+			taints.Outputs[op] = string(v)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if bucket := ns.NestedReadBucket(bucketTaintedAddresses); bucket != nil {
+		err := bucket.ForEach(func(k, v []byte) error {
+			taints.Addresses[string(k)] = string(v)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return taints, nil
+}
+
+// tainted returns whether an output is tainted, either itself or through the
+// address it pays to.
+func (t *Taints) tainted(op wire.OutPoint, pkScript []byte,
+	chainParams *chaincfg.Params) bool {
+
+	if _, ok := t.Outputs[op]; ok {
+		return true
+	}
+	if len(t.Addresses) == 0 {
+		return false
+	}
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(pkScript, chainParams)
+	if err != nil {
+		return false
+	}
+	for _, addr := range addrs {
+		if _, ok := t.Addresses[addr.EncodeAddress()]; ok {
+			return true
+		}
+	}
+	return false
+}
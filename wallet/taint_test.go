@@ -0,0 +1,77 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/walletdb"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTaints checks that tainted outputs, and the outputs paying to tainted
+// addresses, are excluded from coin selection until their taint is cleared.
+func TestTaints(t *testing.T) {
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	var addrs []btcutil.Address
+	incomingTx := &wire.MsgTx{TxIn: []*wire.TxIn{{}}}
+	for i := 0; i < 3; i++ {
+		addr, err := w.NewAddress(0, waddrmgr.KeyScopeBIP0084)
+		require.NoError(t, err)
+		pkScript, err := txscript.PayToAddrScript(addr)
+		require.NoError(t, err)
+		addrs = append(addrs, addr)
+		incomingTx.AddTxOut(wire.NewTxOut(int64(i+1)*100000, pkScript))
+	}
+	addUtxo(t, w, incomingTx)
+	hash := incomingTx.TxHash()
+
+	eligible := func() []uint32 {
+		var indexes []uint32
+		err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+			bs := w.Manager.SyncedTo()
+			credits, err := w.findEligibleOutputs(
+				tx, nil, 0, 0, &bs,
+			)
+			if err != nil {
+				return err
+			}
+			for _, c := range credits {
+				indexes = append(indexes, c.OutPoint.Index)
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		return indexes
+	}
+	require.ElementsMatch(t, []uint32{0, 1, 2}, eligible())
+
+	op := wire.OutPoint{Hash: hash, Index: 0}
+	require.NoError(t, w.SetOutputTaint([]wire.OutPoint{op}, true, "dust"))
+	require.NoError(t, w.SetAddressTaint(addrs[2:], true, ""))
+	require.Equal(t, []uint32{1}, eligible())
+
+	taints, err := w.Taints()
+	require.NoError(t, err)
+	require.Equal(t, map[wire.OutPoint]string{op: "dust"}, taints.Outputs)
+	require.Equal(t, map[string]string{addrs[2].EncodeAddress(): ""},
+		taints.Addresses)
+
+	reason, ok := taints.Reason(op, addrs[0].EncodeAddress())
+	require.True(t, ok)
+	require.Equal(t, "dust", reason)
+	_, ok = taints.Reason(wire.OutPoint{Hash: hash, Index: 2},
+		addrs[2].EncodeAddress())
+	require.True(t, ok)
+	_, ok = taints.Reason(wire.OutPoint{Hash: hash, Index: 1},
+		addrs[1].EncodeAddress())
+	require.False(t, ok)
+
+	require.NoError(t, w.SetOutputTaint([]wire.OutPoint{op}, false, ""))
+	require.NoError(t, w.SetAddressTaint(addrs[2:], false, ""))
+	require.ElementsMatch(t, []uint32{0, 1, 2}, eligible())
+}
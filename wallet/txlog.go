@@ -0,0 +1,506 @@
+package wallet
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/lbryio/lbcd/chaincfg"
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/chain"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/walletdb"
+	"github.com/lbryio/lbcwallet/wtxmgr"
+)
+
+// txLogVersion is the version of the transaction logs written by ExportTxLog.
+const txLogVersion = 1
+
+// ErrTxLogMismatch describes an error where the wallet replaying a
+// transaction log does not reconstruct the history it was exported from.
+var ErrTxLogMismatch = errors.New("the replayed history does not match " +
+	"the exported history")
+
+// TxLogSummary summarizes the history of a wallet, so that the history
+// reconstructed by the replay of a transaction log can be compared with the
+// one it was exported from.
+type TxLogSummary struct {
+	// Transactions is the number of wallet transactions.
+	Transactions int
+
+	// Balance is the balance of the wallet with one confirmation.
+	Balance btcutil.Amount
+
+	// Digest is the SHA256 digest of the transactions ordered by block
+	// height and hash, with their block heights and the credits and
+	// debits of the wallet.
+	Digest chainhash.Hash
+}
+
+// txLogBlock is a block of a transaction log.
+type txLogBlock struct {
+	Hash   string `json:"hash"`
+	Height int32  `json:"height"`
+	Time   int64  `json:"time"`
+}
+
+// txLogHeader is the first line of a transaction log.
+type txLogHeader struct {
+	Version      int        `json:"version"`
+	Net          string     `json:"net"`
+	Synced       txLogBlock `json:"synced"`
+	Transactions int        `json:"transactions"`
+	Balance      int64      `json:"balance"`
+	Digest       string     `json:"digest"`
+}
+
+// txLogEntry is a line of a transaction log following its header.
+type txLogEntry struct {
+	Hex      string      `json:"hex"`
+	Received int64       `json:"received"`
+	Block    *txLogBlock `json:"block,omitempty"`
+	Label    string      `json:"label,omitempty"`
+}
+
+// ExportTxLog writes the transactions of the wallet to w as a transaction
+// log, which ReplayTxLog replays into a fresh wallet created from the same
+// seed.  The log is made of JSON lines: a header with the network, the block
+// the wallet is synced to and the summary of its history, followed by one
+// line per transaction with its serialization, block and label.  The mined
+// transactions come first, in block order with the transactions they spend
+// before them, followed by the unmined ones.  It returns the summary of the
+// history written in the header.
+func (w *Wallet) ExportTxLog(wr io.Writer) (*TxLogSummary, error) {
+	var (
+		details []wtxmgr.TxDetails
+		summary *TxLogSummary
+	)
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		ns := tx.ReadBucket(wtxmgrNamespaceKey)
+		err := w.TxStore.RangeTransactions(ns, 0, -1,
+			func(d []wtxmgr.TxDetails) (bool, error) {
+				details = append(details, d...)
+				return false, nil
+			})
+		if err != nil {
+			return err
+		}
+		summary, err = w.txLogSummary(ns)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	synced := w.Manager.SyncedTo()
+	enc := json.NewEncoder(wr)
+	err = enc.Encode(&txLogHeader{
+		Version: txLogVersion,
+		Net:     w.chainParams.Name,
+		Synced: txLogBlock{
+			Hash:   synced.Hash.String(),
+			Height: synced.Height,
+			Time:   synced.Timestamp.Unix(),
+		},
+		Transactions: summary.Transactions,
+		Balance:      int64(summary.Balance),
+		Digest:       summary.Digest.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, d := range sortTxLog(details) {
+		var buf bytes.Buffer
+		if err := d.MsgTx.Serialize(&buf); err != nil {
+			return nil, err
+		}
+		entry := &txLogEntry{
+			Hex:      hex.EncodeToString(buf.Bytes()),
+			Received: d.Received.Unix(),
+			Label:    d.Label,
+		}
+		if d.Block.Height != -1 {
+			entry.Block = &txLogBlock{
+				Hash:   d.Block.Hash.String(),
+				Height: d.Block.Height,
+				Time:   d.Block.Time.Unix(),
+			}
+		}
+		if err := enc.Encode(entry); err != nil {
+			return nil, err
+		}
+	}
+	return summary, nil
+}
+
+// sortTxLog orders transactions by block height, the unmined ones last, and
+// places the transactions of a block after those they spend, so that their
+// replay finds the outputs they spend.
+func sortTxLog(details []wtxmgr.TxDetails) []wtxmgr.TxDetails {
+	height := func(d *wtxmgr.TxDetails) int64 {
+		if d.Block.Height == -1 {
+			return 1 << 32
+		}
+		return int64(d.Block.Height)
+	}
+	sort.SliceStable(details, func(i, j int) bool {
+		return height(&details[i]) < height(&details[j])
+	})
+
+	sorted := make([]wtxmgr.TxDetails, 0, len(details))
+	for start := 0; start < len(details); {
+		end := start
+		for end < len(details) &&
+			height(&details[end]) == height(&details[start]) {
+
+			end++
+		}
+
+		// The transactions of a block are added once every transaction
+		// of the block they spend has been added.
+		pending := make(map[chainhash.Hash]*wtxmgr.TxDetails)
+		for i := start; i < end; i++ {
+			pending[details[i].Hash] = &details[i]
+		}
+		for len(pending) != 0 {
+			for i := start; i < end; i++ {
+				d := &details[i]
+				if _, ok := pending[d.Hash]; !ok {
+					continue
+				}
+				ready := true
+				for _, in := range d.MsgTx.TxIn {
+					prev := in.PreviousOutPoint.Hash
+					if _, ok := pending[prev]; ok &&
+						prev != d.Hash {
+
+						ready = false
+						break
+					}
+				}
+				if ready || len(pending) == 1 {
+					sorted = append(sorted, *d)
+					delete(pending, d.Hash)
+				}
+			}
+		}
+		start = end
+	}
+	return sorted
+}
+
+// txLogSummary returns the summary of the history of the wallet.
+func (w *Wallet) txLogSummary(ns walletdb.ReadBucket) (*TxLogSummary,
+	error) {
+
+	var details []wtxmgr.TxDetails
+	err := w.TxStore.RangeTransactions(ns, 0, -1,
+		func(d []wtxmgr.TxDetails) (bool, error) {
+			details = append(details, d...)
+			return false, nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(details, func(i, j int) bool {
+		hi, hj := uint32(details[i].Block.Height),
+			uint32(details[j].Block.Height)
+		if hi != hj {
+			return hi < hj
+		}
+		return bytes.Compare(details[i].Hash[:], details[j].Hash[:]) < 0
+	})
+
+	h := sha256.New()
+	var b [8]byte
+	for _, d := range details {
+		h.Write(d.Hash[:])
+		binary.BigEndian.PutUint32(b[:4], uint32(d.Block.Height))
+		h.Write(b[:4])
+		for _, c := range d.Credits {
+			binary.BigEndian.PutUint32(b[:4], c.Index)
+			h.Write(b[:4])
+			binary.BigEndian.PutUint64(b[:], uint64(c.Amount))
+			h.Write(b[:])
+			h.Write([]byte{boolByte(c.Spent), boolByte(c.Change)})
+		}
+		for _, d := range d.Debits {
+			binary.BigEndian.PutUint32(b[:4], d.Index)
+			h.Write(b[:4])
+			binary.BigEndian.PutUint64(b[:], uint64(d.Amount))
+			h.Write(b[:])
+		}
+	}
+
+	summary := &TxLogSummary{Transactions: len(details)}
+	copy(summary.Digest[:], h.Sum(nil))
+	syncHeight := w.Manager.SyncedTo().Height
+	summary.Balance, _, err = w.TxStore.Balance(ns, 1, syncHeight)
+	if err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+func boolByte(b bool) byte {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// TxLogSummary returns the summary of the history of the wallet, as recorded
+// in the transaction logs it exports.
+func (w *Wallet) TxLogSummary() (*TxLogSummary, error) {
+	var summary *TxLogSummary
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		var err error
+		summary, err = w.txLogSummary(tx.ReadBucket(wtxmgrNamespaceKey))
+		return err
+	})
+	return summary, err
+}
+
+// replayChain serves the blocks of a transaction log to the address recovery
+// of a replay, which only filters blocks.
+type replayChain struct {
+	chain.Interface
+
+	params *chaincfg.Params
+	blocks map[chainhash.Hash]*wire.MsgBlock
+}
+
+func (c *replayChain) FilterBlocks(req *chain.FilterBlocksRequest) (
+	*chain.FilterBlocksResponse, error) {
+
+	blockFilterer := chain.NewBlockFilterer(c.params, req)
+	for i, blk := range req.Blocks {
+		if !blockFilterer.FilterBlock(c.blocks[blk.Hash]) {
+			continue
+		}
+		return &chain.FilterBlocksResponse{
+			BatchIndex:     uint32(i),
+			BlockMeta:      blk,
+			FoundAddresses: blockFilterer.FoundAddresses,
+			FoundOutPoints: blockFilterer.FoundOutPoints,
+			RelevantTxns:   blockFilterer.RelevantTxns,
+		}, nil
+	}
+	return nil, nil
+}
+
+// ReplayTxLog replays a transaction log written by ExportTxLog into the
+// wallet, which must be a fresh wallet created from the seed of the wallet
+// which exported it, so that reconstructing the balance and the history from
+// the same transactions can be checked to be deterministic, such as after a
+// database migration.  The transactions are found by the address recovery
+// used when restoring a wallet, with the recovery window of the wallet, and
+// the wallet is then marked synced to the block of the log.  The wallet must
+// be unlocked, so that the accounts found can be derived.  The addresses of
+// imported keys are not derived, so their transactions are not replayed.
+//
+// It returns the summaries of the exported history and of the replayed one,
+// along with ErrTxLogMismatch if they differ.
+func (w *Wallet) ReplayTxLog(r io.Reader) (exported,
+	replayed *TxLogSummary, err error) {
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 2*wire.MaxBlockPayload)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, errors.New("empty transaction log")
+	}
+	var header txLogHeader
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil {
+		return nil, nil, fmt.Errorf("invalid transaction log header: "+
+			"%v", err)
+	}
+	if header.Version != txLogVersion {
+		return nil, nil, fmt.Errorf("unknown transaction log version "+
+			"%d", header.Version)
+	}
+	if header.Net != w.chainParams.Name {
+		return nil, nil, fmt.Errorf("the transaction log is for %s, "+
+			"not %s", header.Net, w.chainParams.Name)
+	}
+	exported = &TxLogSummary{
+		Transactions: header.Transactions,
+		Balance:      btcutil.Amount(header.Balance),
+	}
+	err = chainhash.Decode(&exported.Digest, header.Digest)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid transaction log digest: "+
+			"%v", err)
+	}
+	syncedHash, err := chainhash.NewHashFromStr(header.Synced.Hash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid transaction log synced "+
+			"block: %v", err)
+	}
+
+	// The mined transactions are grouped into their blocks, in order.
+	backend := &replayChain{
+		params: w.chainParams,
+		blocks: make(map[chainhash.Hash]*wire.MsgBlock),
+	}
+	var (
+		batch   []wtxmgr.BlockMeta
+		unmined []*wire.MsgTx
+		labels  = make(map[chainhash.Hash]string)
+	)
+	for line := 2; scanner.Scan(); line++ {
+		var entry txLogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, nil, fmt.Errorf("invalid transaction log "+
+				"line %d: %v", line, err)
+		}
+		serialized, err := hex.DecodeString(entry.Hex)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid transaction log "+
+				"line %d: %v", line, err)
+		}
+		var msgTx wire.MsgTx
+		err = msgTx.Deserialize(bytes.NewReader(serialized))
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid transaction log "+
+				"line %d: %v", line, err)
+		}
+		if entry.Label != "" {
+			labels[msgTx.TxHash()] = entry.Label
+		}
+		if entry.Block == nil {
+			unmined = append(unmined, &msgTx)
+			continue
+		}
+
+		hash, err := chainhash.NewHashFromStr(entry.Block.Hash)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid transaction log "+
+				"line %d: %v", line, err)
+		}
+		block, ok := backend.blocks[*hash]
+		if !ok {
+			block = &wire.MsgBlock{}
+			backend.blocks[*hash] = block
+			batch = append(batch, wtxmgr.BlockMeta{
+				Block: wtxmgr.Block{
+					Hash:   *hash,
+					Height: entry.Block.Height,
+				},
+				Time: time.Unix(entry.Block.Time, 0),
+			})
+		}
+		block.Transactions = append(block.Transactions, &msgTx)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	scopedMgrs := make(map[waddrmgr.KeyScope]*waddrmgr.ScopedKeyManager)
+	for _, scopedMgr := range w.Manager.ActiveScopedKeyManagers() {
+		scopedMgrs[scopedMgr.Scope()] = scopedMgr
+	}
+	recoveryMgr := NewRecoveryManager(
+		w.recoveryWindow, recoveryBatchSize, w.chainParams,
+	)
+	err = walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		addrmgrNs := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		txmgrNs := tx.ReadWriteBucket(wtxmgrNamespaceKey)
+
+		// The whole replay is written in this transaction, as the
+		// blocks are filtered locally.
+		update := func(f func(tx walletdb.ReadWriteTx) error) error {
+			return f(tx)
+		}
+		state := recoveryMgr.State()
+		err := w.recoverScopedAddresses(context.Background(), backend,
+			update, batch, state, scopedMgrs)
+		if err != nil {
+			return err
+		}
+
+		// The unmined transactions are filtered like the transactions
+		// of a block without one.
+		if len(unmined) != 0 {
+			for scope, scopedMgr := range scopedMgrs {
+				err := expandScopeHorizons(addrmgrNs, scopedMgr,
+					state.StateForScope(scope))
+				if err != nil {
+					return err
+				}
+			}
+			req := newFilterBlocksRequest(nil, scopedMgrs, state)
+			filterer := chain.NewBlockFilterer(w.chainParams, req)
+			filterer.FilterBlock(&wire.MsgBlock{
+				Transactions: unmined,
+			})
+			resp := &chain.FilterBlocksResponse{
+				FoundAddresses: filterer.FoundAddresses,
+			}
+			err := extendFoundAddresses(addrmgrNs, resp, scopedMgrs,
+				state)
+			if err != nil {
+				return err
+			}
+			for _, msgTx := range filterer.RelevantTxns {
+				rec, err := wtxmgr.NewTxRecordFromMsgTx(msgTx,
+					w.now())
+				if err != nil {
+					return err
+				}
+				err = w.addRelevantTx(tx, rec, nil)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		// Only the labels of the transactions replayed are restored.
+		for hash, label := range labels {
+			hash := hash
+			details, err := w.TxStore.TxDetails(txmgrNs, &hash)
+			if err != nil {
+				return err
+			}
+			if details == nil {
+				continue
+			}
+			err = w.TxStore.PutTxLabel(txmgrNs, hash, label)
+			if err != nil {
+				return err
+			}
+		}
+
+		return w.Manager.SetSyncedTo(addrmgrNs, &waddrmgr.BlockStamp{
+			Hash:      *syncedHash,
+			Height:    header.Synced.Height,
+			Timestamp: time.Unix(header.Synced.Time, 0),
+		})
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	replayed, err = w.TxLogSummary()
+	if err != nil {
+		return nil, nil, err
+	}
+	if *replayed != *exported {
+		return exported, replayed, ErrTxLogMismatch
+	}
+	return exported, replayed, nil
+}
@@ -0,0 +1,87 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/lbryio/lbcd/chaincfg"
+	"github.com/lbryio/lbcwallet/walletdb"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReplayTxLog checks that replaying the transaction log of a wallet into
+// a fresh wallet created from the same seed reconstructs the same history.
+func TestReplayTxLog(t *testing.T) {
+	w := fixtureWallet(t, &FixtureParams{
+		Receives: 10, Spends: 6, Claims: 3,
+	})
+
+	// An unmined labeled deposit is replayed too.
+	w.chainClient = &mockChainClient{}
+	w.SetFaucet(&testFaucet{w: w})
+	req, err := w.RequestTestCoins(0, 5e7)
+	require.NoError(t, err)
+	err = walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(wtxmgrNamespaceKey)
+		return w.TxStore.PutTxLabel(ns, *req.TxHash, "faucet")
+	})
+	require.NoError(t, err)
+
+	var txLog bytes.Buffer
+	expected, err := w.ExportTxLog(&txLog)
+	require.NoError(t, err)
+	require.Equal(t, 20, expected.Transactions)
+
+	replay := func(txLog []byte) (*Wallet, *TxLogSummary, *TxLogSummary,
+		error) {
+
+		dir, err := ioutil.TempDir("", "test_replay")
+		require.NoError(t, err)
+		t.Cleanup(func() { os.RemoveAll(dir) })
+		loader := NewLoader(
+			&chaincfg.RegressionNetParams, dir, true,
+			defaultDBTimeout, 250,
+		)
+		fresh, err := loader.CreateNewWallet([]byte("password"),
+			FixtureSeed, FixtureStart)
+		require.NoError(t, err)
+		t.Cleanup(func() { loader.UnloadWallet() })
+		require.NoError(t, fresh.Unlock([]byte("password"), nil))
+
+		exported, replayed, err := fresh.ReplayTxLog(
+			bytes.NewReader(txLog),
+		)
+		return fresh, exported, replayed, err
+	}
+
+	fresh, exported, replayed, err := replay(txLog.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, expected, exported)
+	require.Equal(t, expected, replayed)
+	require.Equal(t, w.Manager.SyncedTo().Hash,
+		fresh.Manager.SyncedTo().Hash)
+	var label string
+	err = walletdb.View(fresh.db, func(tx walletdb.ReadTx) error {
+		ns := tx.ReadBucket(wtxmgrNamespaceKey)
+		label, err = fresh.TxStore.TxLabel(ns, *req.TxHash)
+		return err
+	})
+	require.NoError(t, err)
+	require.Equal(t, "faucet", label)
+
+	// A log whose summary differs from its transactions does not match.
+	lines := bytes.SplitN(txLog.Bytes(), []byte("\n"), 2)
+	var header txLogHeader
+	require.NoError(t, json.Unmarshal(lines[0], &header))
+	header.Balance++
+	tampered, err := json.Marshal(&header)
+	require.NoError(t, err)
+	tampered = append(append(tampered, '\n'), lines[1]...)
+	_, exported, replayed, err = replay(tampered)
+	require.ErrorIs(t, err, ErrTxLogMismatch)
+	require.Equal(t, expected.Balance+1, exported.Balance)
+	require.Equal(t, expected, replayed)
+}
@@ -0,0 +1,380 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/lbryio/lbcwallet/chain"
+	"github.com/lbryio/lbcwallet/walletdb"
+)
+
+// bucketUptime is the name of the sub bucket of the wallet namespace that maps
+// times to the serialized records of the uptime history.
+var bucketUptime = []byte("uptime")
+
+const (
+	// uptimePollInterval is how often the connection to the chain server
+	// is checked while the wallet runs.
+	uptimePollInterval = time.Minute
+
+	// uptimeSampleInterval is how often the sync lag is recorded while
+	// the connection to the chain server does not change.
+	uptimeSampleInterval = 10 * time.Minute
+
+	// uptimeRetention is how long the uptime history is kept.
+	uptimeRetention = 90 * 24 * time.Hour
+)
+
+// uptimeKind is the kind of a record of the uptime history.
+type uptimeKind byte
+
+const (
+	// uptimeStart records the start of the wallet.
+	uptimeStart uptimeKind = iota
+
+	// uptimeSample records the connection to the chain server, and the
+	// sync lag of the wallet while connected.
+	uptimeSample
+
+	// uptimeStop records the clean shutdown of the wallet.  A run without
+	// one ended with its last record.
+	uptimeStop
+)
+
+// uptimeRecord is a record of the uptime history.
+type uptimeRecord struct {
+	time      time.Time
+	kind      uptimeKind
+	connected bool
+	height    int32 // Height the wallet is synced to.
+	best      int32 // Best height of the chain server when connected.
+}
+
+// serializeUptimeRecord returns the key and value of a record in the uptime
+// bucket.  The key is the time in nanoseconds since the epoch (8 bytes), so
+// that the records are ordered by time, and the value is:
+//
+//	[0]    kind (1 byte)
+//	[1]    connected (1 byte)
+//	[2:6]  synced height (4 bytes)
+//	[6:10] best height of the chain server (4 bytes)
+func serializeUptimeRecord(r *uptimeRecord) ([]byte, []byte) {
+	k := make([]byte, 8)
+	binary.BigEndian.PutUint64(k, uint64(r.time.UnixNano()))
+	v := make([]byte, 10)
+	v[0] = byte(r.kind)
+	if r.connected {
+		v[1] = 1
+	}
+	binary.BigEndian.PutUint32(v[2:6], uint32(r.height))
+	binary.BigEndian.PutUint32(v[6:10], uint32(r.best))
+	return k, v
+}
+
+// deserializeUptimeRecord decodes a record serialized by
+// serializeUptimeRecord.
+func deserializeUptimeRecord(k, v []byte) (*uptimeRecord, error) {
+	if len(k) != 8 || len(v) < 10 {
+		return nil, fmt.Errorf("short uptime record: %d bytes", len(v))
+	}
+	return &uptimeRecord{
+		time:      time.Unix(0, int64(binary.BigEndian.Uint64(k))),
+		kind:      uptimeKind(v[0]),
+		connected: v[1] == 1,
+		height:    int32(binary.BigEndian.Uint32(v[2:6])),
+		best:      int32(binary.BigEndian.Uint32(v[6:10])),
+	}, nil
+}
+
+// putUptimeRecord adds a record to the uptime history.
+func (w *Wallet) putUptimeRecord(r *uptimeRecord) error {
+	return walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(walletNamespaceKey)
+		uptime, err := ns.CreateBucketIfNotExists(bucketUptime)
+		if err != nil {
+			return err
+		}
+		k, v := serializeUptimeRecord(r)
+		return uptime.Put(k, v)
+	})
+}
+
+// pruneUptime removes the records of the uptime history older than cutoff.
+func (w *Wallet) pruneUptime(cutoff time.Time) error {
+	return walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		uptime := tx.ReadWriteBucket(walletNamespaceKey).
+			NestedReadWriteBucket(bucketUptime)
+		if uptime == nil {
+			return nil
+		}
+		end := make([]byte, 8)
+		binary.BigEndian.PutUint64(end, uint64(cutoff.UnixNano()))
+		var keys [][]byte
+		c := uptime.ReadCursor()
+		k, _ := c.First()
+		for ; k != nil && bytes.Compare(k, end) < 0; k, _ = c.Next() {
+			keys = append(keys, append([]byte(nil), k...))
+		}
+		for _, k := range keys {
+			if err := uptime.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// uptimeRecords returns the uptime history in time order.
+func (w *Wallet) uptimeRecords() ([]*uptimeRecord, error) {
+	var records []*uptimeRecord
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		uptime := tx.ReadBucket(walletNamespaceKey).
+			NestedReadBucket(bucketUptime)
+		if uptime == nil {
+			return nil
+		}
+		return uptime.ForEach(func(k, v []byte) error {
+			r, err := deserializeUptimeRecord(k, v)
+			if err != nil {
+				return err
+			}
+			records = append(records, r)
+			return nil
+		})
+	})
+	return records, err
+}
+
+// DisconnectWindow is a period during which the wallet ran without a
+// connection to the chain server.
+type DisconnectWindow struct {
+	Start time.Time
+
+	// End is zero while the wallet is still disconnected.
+	End time.Time
+}
+
+// SyncLagSample is the number of blocks the wallet was synced behind the
+// chain server at a time.
+type SyncLagSample struct {
+	Time   time.Time
+	Height int32
+	Lag    int32
+}
+
+// UptimeStats quantifies the availability of the wallet over a period, as
+// recorded in its database while it runs.  The connection to the chain server
+// is checked every minute, and the sync lag recorded every ten minutes.  The
+// history is kept for 90 days.
+type UptimeStats struct {
+	// Since is the start of the period, which is no earlier than the
+	// oldest record of the history.
+	Since time.Time
+
+	// Starts are the times the wallet was started.
+	Starts []time.Time
+
+	// Uptime is how long the wallet ran, and Disconnected how long of
+	// that time it was not connected to the chain server.
+	Uptime       time.Duration
+	Disconnected time.Duration
+
+	// Disconnects are the periods the wallet ran without a connection
+	// to the chain server.
+	Disconnects []DisconnectWindow
+
+	// SyncLag is the history of the sync lag, and MaxSyncLag its
+	// maximum.
+	SyncLag    []SyncLagSample
+	MaxSyncLag int32
+}
+
+// UptimeStats returns the availability of the wallet since a time.
+func (w *Wallet) UptimeStats(since time.Time) (*UptimeStats, error) {
+	records, err := w.uptimeRecords()
+	if err != nil {
+		return nil, err
+	}
+	return uptimeStats(records, since, time.Now()), nil
+}
+
+// uptimeStats computes the availability of the wallet from its uptime
+// history.  The last run of the wallet which was not stopped is the current
+// one, which lasts until now, and the earlier ones ended with their last
+// record.
+func uptimeStats(records []*uptimeRecord, since, now time.Time) *UptimeStats {
+	stats := &UptimeStats{Since: since}
+	if len(records) != 0 && records[0].time.After(since) {
+		stats.Since = records[0].time
+	}
+
+	// clip returns the duration of the part of a period within the
+	// stats period.
+	clip := func(start, end time.Time) time.Duration {
+		if start.Before(stats.Since) {
+			start = stats.Since
+		}
+		if end.Before(start) {
+			return 0
+		}
+		return end.Sub(start)
+	}
+
+	var (
+		running    bool
+		runStart   time.Time
+		lastRecord time.Time
+		disconnect *DisconnectWindow
+	)
+	endDisconnect := func(end time.Time) {
+		if disconnect == nil {
+			return
+		}
+		disconnect.End = end
+		if !end.Before(stats.Since) {
+			stats.Disconnects = append(stats.Disconnects, *disconnect)
+			stats.Disconnected += clip(disconnect.Start, end)
+		}
+		disconnect = nil
+	}
+	endRun := func(end time.Time) {
+		endDisconnect(end)
+		stats.Uptime += clip(runStart, end)
+		running = false
+	}
+	for _, r := range records {
+		switch r.kind {
+		case uptimeStart:
+			if running {
+				endRun(lastRecord)
+			}
+			running = true
+			runStart = r.time
+			if !r.time.Before(stats.Since) {
+				stats.Starts = append(stats.Starts, r.time)
+			}
+
+		case uptimeSample:
+			// The start of a run may have been pruned.
+			if !running {
+				running = true
+				runStart = r.time
+			}
+			if !r.connected {
+				if disconnect == nil {
+					disconnect = &DisconnectWindow{Start: r.time}
+				}
+				break
+			}
+			endDisconnect(r.time)
+			if r.time.Before(stats.Since) {
+				break
+			}
+			lag := r.best - r.height
+			if lag < 0 {
+				lag = 0
+			}
+			stats.SyncLag = append(stats.SyncLag, SyncLagSample{
+				Time:   r.time,
+				Height: r.height,
+				Lag:    lag,
+			})
+			if lag > stats.MaxSyncLag {
+				stats.MaxSyncLag = lag
+			}
+
+		case uptimeStop:
+			if running {
+				endRun(r.time)
+			}
+		}
+		lastRecord = r.time
+	}
+	if running {
+		if disconnect != nil {
+			stats.Disconnects = append(stats.Disconnects, *disconnect)
+			stats.Disconnected += clip(disconnect.Start, now)
+		}
+		stats.Uptime += clip(runStart, now)
+	}
+	return stats
+}
+
+// uptimeSample checks the connection to the chain server and the sync lag of
+// the wallet.
+func (w *Wallet) uptimeSample() *uptimeRecord {
+	r := &uptimeRecord{
+		time:   time.Now(),
+		kind:   uptimeSample,
+		height: w.Manager.SyncedTo().Height,
+	}
+	chainClient, err := w.requireChainClient()
+	if err != nil {
+		return r
+	}
+	if client, ok := chainClient.(*chain.RPCClient); ok &&
+		!client.ConnStatus().Connected {
+
+		return r
+	}
+	_, best, err := chainClient.GetBestBlock()
+	if err != nil {
+		return r
+	}
+	r.connected = true
+	r.best = best
+	return r
+}
+
+// uptimeRecorder records the start of the wallet, its connection to the chain
+// server and its sync lag until the wallet is stopped.  Samples are only
+// recorded when the connection changes or every uptimeSampleInterval, so
+// that the database is not written to every minute.
+func (w *Wallet) uptimeRecorder() {
+	defer w.wg.Done()
+
+	now := time.Now()
+	if err := w.pruneUptime(now.Add(-uptimeRetention)); err != nil {
+		log.Errorf("Unable to prune the uptime history: %v", err)
+	}
+	err := w.putUptimeRecord(&uptimeRecord{time: now, kind: uptimeStart})
+	if err != nil {
+		log.Errorf("Unable to record the wallet start: %v", err)
+	}
+
+	ticker := time.NewTicker(uptimePollInterval)
+	defer ticker.Stop()
+
+	quit := w.quitChan()
+	var last *uptimeRecord
+	for {
+		select {
+		case <-ticker.C:
+			r := w.uptimeSample()
+			if last != nil && r.connected == last.connected &&
+				r.time.Sub(last.time) < uptimeSampleInterval {
+
+				continue
+			}
+			if err := w.putUptimeRecord(r); err != nil {
+				log.Errorf("Unable to record the uptime: %v",
+					err)
+				continue
+			}
+			last = r
+
+		case <-quit:
+			err := w.putUptimeRecord(&uptimeRecord{
+				time: time.Now(),
+				kind: uptimeStop,
+			})
+			if err != nil {
+				log.Errorf("Unable to record the wallet "+
+					"shutdown: %v", err)
+			}
+			return
+		}
+	}
+}
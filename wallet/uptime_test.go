@@ -0,0 +1,114 @@
+package wallet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestUptimeStats checks the availability computed from an uptime history
+// with a crash, a clean shutdown and disconnects.
+func TestUptimeStats(t *testing.T) {
+	t0 := time.Unix(1700000000, 0)
+	at := func(minutes int) time.Time {
+		return t0.Add(time.Duration(minutes) * time.Minute)
+	}
+	sample := func(minutes int, connected bool, height, best int32) *uptimeRecord {
+		return &uptimeRecord{
+			time:      at(minutes),
+			kind:      uptimeSample,
+			connected: connected,
+			height:    height,
+			best:      best,
+		}
+	}
+	records := []*uptimeRecord{
+		// A run which crashed after 20 minutes, disconnected for the
+		// last 10.
+		{time: at(0), kind: uptimeStart},
+		sample(1, true, 100, 103),
+		sample(10, false, 0, 0),
+		sample(20, false, 0, 0),
+
+		// A run stopped cleanly after an hour, with a disconnect of
+		// 5 minutes.
+		{time: at(30), kind: uptimeStart},
+		sample(31, true, 110, 110),
+		sample(40, false, 0, 0),
+		sample(45, true, 112, 120),
+		{time: at(90), kind: uptimeStop},
+
+		// The current run, disconnected since its last sample.
+		{time: at(100), kind: uptimeStart},
+		sample(101, true, 130, 130),
+		sample(110, false, 0, 0),
+	}
+	now := at(120)
+
+	stats := uptimeStats(records, time.Time{}, now)
+	require.Equal(t, at(0), stats.Since)
+	require.Equal(t, []time.Time{at(0), at(30), at(100)}, stats.Starts)
+	require.Equal(t, (20+60+20)*time.Minute, stats.Uptime)
+	require.Equal(t, (10+5+10)*time.Minute, stats.Disconnected)
+	require.Equal(t, []DisconnectWindow{
+		{Start: at(10), End: at(20)},
+		{Start: at(40), End: at(45)},
+		{Start: at(110)},
+	}, stats.Disconnects)
+	require.Equal(t, []SyncLagSample{
+		{Time: at(1), Height: 100, Lag: 3},
+		{Time: at(31), Height: 110, Lag: 0},
+		{Time: at(45), Height: 112, Lag: 8},
+		{Time: at(101), Height: 130, Lag: 0},
+	}, stats.SyncLag)
+	require.EqualValues(t, 8, stats.MaxSyncLag)
+
+	// Periods are clipped to the start of the stats.
+	stats = uptimeStats(records, at(42), now)
+	require.Equal(t, at(42), stats.Since)
+	require.Equal(t, []time.Time{at(100)}, stats.Starts)
+	require.Equal(t, (48+20)*time.Minute, stats.Uptime)
+	require.Equal(t, (3+10)*time.Minute, stats.Disconnected)
+	require.Len(t, stats.Disconnects, 2)
+	require.Len(t, stats.SyncLag, 2)
+	require.EqualValues(t, 8, stats.MaxSyncLag)
+}
+
+// TestUptimeHistory checks that the uptime history is stored in time order
+// and pruned.
+func TestUptimeHistory(t *testing.T) {
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	old := time.Now().Add(-2 * uptimeRetention)
+	records := []*uptimeRecord{
+		{time: old, kind: uptimeStart},
+		{time: old.Add(time.Minute), kind: uptimeSample,
+			connected: true, height: 10, best: 12},
+		{time: old.Add(time.Hour), kind: uptimeStop},
+	}
+	for i := len(records) - 1; i >= 0; i-- {
+		require.NoError(t, w.putUptimeRecord(records[i]))
+	}
+
+	stored, err := w.uptimeRecords()
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(stored), len(records))
+	for i, r := range records {
+		require.True(t, r.time.Equal(stored[i].time))
+		require.Equal(t, r.kind, stored[i].kind)
+		require.Equal(t, r.connected, stored[i].connected)
+		require.Equal(t, r.height, stored[i].height)
+		require.Equal(t, r.best, stored[i].best)
+	}
+
+	// Only the old records are pruned, leaving the start recorded by the
+	// running wallet.
+	require.NoError(t, w.pruneUptime(time.Now().Add(-uptimeRetention)))
+	stored, err = w.uptimeRecords()
+	require.NoError(t, err)
+	for _, r := range stored {
+		require.True(t, r.time.After(old.Add(time.Hour)))
+	}
+}
@@ -0,0 +1,602 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/lbryio/lbcd/btcec"
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/wallet/txrules"
+	"github.com/lbryio/lbcwallet/walletdb"
+	"github.com/lbryio/lbcwallet/wtxmgr"
+)
+
+var (
+	// bucketVaults is the name of the sub bucket of the wallet namespace
+	// that maps the names of vaults to their serialized parameters.
+	bucketVaults = []byte("vaults")
+
+	// bucketVaultAddrs is the name of the sub bucket of the wallet
+	// namespace that maps the encoded addresses of vaults to the name of
+	// the vault they belong to.
+	bucketVaultAddrs = []byte("vaultaddrs")
+)
+
+var (
+	// ErrVaultNotFound is returned when no vault has the given name.
+	ErrVaultNotFound = errors.New("vault not found")
+
+	// ErrVaultInsufficientFunds is returned when the outputs of a vault
+	// whose delay has elapsed are not worth the outputs of a send and its
+	// fee.
+	ErrVaultInsufficientFunds = errors.New("insufficient vault funds " +
+		"past their delay")
+
+	// ErrVaultRecoveryKey is returned when a vault is recovered with
+	// another key than its recovery key.
+	ErrVaultRecoveryKey = errors.New("not the recovery key of the vault")
+)
+
+// vaultSpendWitnessSize is the largest size of the witness spending a vault
+// output, less its witness script: the item count, a signature and the
+// branch selector, with their lengths.
+const vaultSpendWitnessSize = 1 + 1 + 73 + 1 + 1
+
+// p2wshScriptSize is the size of the output script paying to a witness
+// script hash.
+const p2wshScriptSize = 1 + 1 + 32
+
+// Vault is an account of the BIP0084 scope whose outputs pay to a witness
+// script spendable either by its keys, once the output has Delay
+// confirmations, or by a recovery key held outside of the wallet at any time.
+// A thief of the wallet keys must wait for the delay before spending the
+// outputs a vault receives, while the owner of the recovery key claws them
+// back.  The outputs of vaults are never chosen by coin selection, and are
+// only spent by SendFromVault and RecoverVault.
+type Vault struct {
+	Name        string
+	Account     uint32
+	RecoveryKey *btcec.PublicKey
+
+	// Delay is the relative timelock in blocks of the spends by the
+	// vault keys.
+	Delay uint16
+
+	// Balance is the value of the unspent outputs of the vault, including
+	// unconfirmed outputs, and Spendable the value of those with Delay
+	// confirmations.
+	Balance   btcutil.Amount
+	Spendable btcutil.Amount
+}
+
+// serializeVault returns the value of a vault in the bucket of vaults:
+//
+//	[0:4]   account number (4 bytes)
+//	[4:6]   delay (2 bytes)
+//	[6:39]  compressed recovery public key (33 bytes)
+func serializeVault(v *Vault) []byte {
+	b := make([]byte, 6, 6+btcec.PubKeyBytesLenCompressed)
+	binary.BigEndian.PutUint32(b[0:4], v.Account)
+	binary.BigEndian.PutUint16(b[4:6], v.Delay)
+	return append(b, v.RecoveryKey.SerializeCompressed()...)
+}
+
+// deserializeVault decodes a vault serialized by serializeVault.
+func deserializeVault(name string, b []byte) (*Vault, error) {
+	if len(b) != 6+btcec.PubKeyBytesLenCompressed {
+		return nil, fmt.Errorf("invalid vault %q: %d bytes", name,
+			len(b))
+	}
+	recoveryKey, err := btcec.ParsePubKey(b[6:], btcec.S256())
+	if err != nil {
+		return nil, err
+	}
+	return &Vault{
+		Name:        name,
+		Account:     binary.BigEndian.Uint32(b[0:4]),
+		Delay:       binary.BigEndian.Uint16(b[4:6]),
+		RecoveryKey: recoveryKey,
+	}, nil
+}
+
+// fetchVault returns the vault with the given name.
+func fetchVault(ns walletdb.ReadBucket, name string) (*Vault, error) {
+	vaults := ns.NestedReadBucket(bucketVaults)
+	if vaults == nil {
+		return nil, ErrVaultNotFound
+	}
+	b := vaults.Get([]byte(name))
+	if b == nil {
+		return nil, ErrVaultNotFound
+	}
+	return deserializeVault(name, b)
+}
+
+// vaultScript returns the witness script of a vault output paying to a key of
+// the vault:
+//
+//	OP_IF
+//	    <recovery key>
+//	OP_ELSE
+//	    <delay> OP_CHECKSEQUENCEVERIFY OP_DROP
+//	    <key>
+//	OP_ENDIF
+//	OP_CHECKSIG
+func vaultScript(v *Vault, key *btcec.PublicKey) ([]byte, error) {
+	return txscript.NewScriptBuilder().
+		AddOp(txscript.OP_IF).
+		AddData(v.RecoveryKey.SerializeCompressed()).
+		AddOp(txscript.OP_ELSE).
+		AddInt64(int64(v.Delay)).
+		AddOp(txscript.OP_CHECKSEQUENCEVERIFY).
+		AddOp(txscript.OP_DROP).
+		AddData(key.SerializeCompressed()).
+		AddOp(txscript.OP_ENDIF).
+		AddOp(txscript.OP_CHECKSIG).
+		Script()
+}
+
+// CreateVault creates a vault, an account named name whose outputs are spent
+// by its keys only once they have delay confirmations, and by the recovery
+// key at any time.  The wallet must be unlocked to create the account.
+func (w *Wallet) CreateVault(name string, recoveryKey *btcec.PublicKey,
+	delay uint16) (*Vault, error) {
+
+	if delay == 0 {
+		return nil, errors.New("vault delay must be positive")
+	}
+	if err := w.requireDiskSpace(); err != nil {
+		return nil, err
+	}
+	manager, err := w.Manager.FetchScopedKeyManager(
+		waddrmgr.KeyScopeBIP0084,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &Vault{
+		Name:        name,
+		RecoveryKey: recoveryKey,
+		Delay:       delay,
+	}
+	err = walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		addrmgrNs := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		v.Account, err = manager.NewAccount(addrmgrNs, name)
+		if err != nil {
+			return err
+		}
+
+		ns := tx.ReadWriteBucket(walletNamespaceKey)
+		vaults, err := ns.CreateBucketIfNotExists(bucketVaults)
+		if err != nil {
+			return err
+		}
+		return vaults.Put([]byte(name), serializeVault(v))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// NewVaultAddress returns a new address of a vault, paying to a witness
+// script with the next external key of its account.  The backend notifies the
+// transactions paying the address from then on.
+func (w *Wallet) NewVaultAddress(name string) (btcutil.Address, error) {
+	if err := w.requireDiskSpace(); err != nil {
+		return nil, err
+	}
+
+	var addr btcutil.Address
+	err := walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		v, err := fetchVault(tx.ReadBucket(walletNamespaceKey), name)
+		if err != nil {
+			return err
+		}
+		addr, err = w.newVaultAddress(tx, v)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return addr, w.watchImportedAddress(addr, nil)
+}
+
+// newVaultAddress derives the next external key of a vault and imports the
+// witness script paying to it, whose address is returned.
+func (w *Wallet) newVaultAddress(tx walletdb.ReadWriteTx,
+	v *Vault) (btcutil.Address, error) {
+
+	addrmgrNs := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+	keyAddr, _, err := w.newAddress(
+		addrmgrNs, v.Account, waddrmgr.KeyScopeBIP0084,
+	)
+	if err != nil {
+		return nil, err
+	}
+	ma, err := w.Manager.Address(addrmgrNs, keyAddr)
+	if err != nil {
+		return nil, err
+	}
+	script, err := vaultScript(
+		v, ma.(waddrmgr.ManagedPubKeyAddress).PubKey(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// The script is not secret, so that the vault is watched while the
+	// wallet is locked, and it has no history before the synced block.
+	manager, err := w.Manager.FetchScopedKeyManager(
+		waddrmgr.KeyScopeBIP0084,
+	)
+	if err != nil {
+		return nil, err
+	}
+	bs := w.Manager.SyncedTo()
+	msa, err := manager.ImportWitnessScript(
+		addrmgrNs, script, &bs, 0, false,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ns := tx.ReadWriteBucket(walletNamespaceKey)
+	addrs, err := ns.CreateBucketIfNotExists(bucketVaultAddrs)
+	if err != nil {
+		return nil, err
+	}
+	addr := msa.Address()
+	err = addrs.Put([]byte(addr.EncodeAddress()), []byte(v.Name))
+	if err != nil {
+		return nil, err
+	}
+	return addr, nil
+}
+
+// vaultCredit is an unspent output of a vault with its witness script.
+type vaultCredit struct {
+	wtxmgr.Credit
+	script []byte
+}
+
+// vaultCredits returns the unspent outputs of a vault, largest first.
+func (w *Wallet) vaultCredits(dbtx walletdb.ReadTx,
+	v *Vault) ([]vaultCredit, error) {
+
+	addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
+	txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+	addrs := dbtx.ReadBucket(walletNamespaceKey).NestedReadBucket(
+		bucketVaultAddrs,
+	)
+	if addrs == nil {
+		return nil, nil
+	}
+
+	unspent, err := w.TxStore.UnspentOutputs(txmgrNs)
+	if err != nil {
+		return nil, err
+	}
+	var credits []vaultCredit
+	for _, output := range unspent {
+		if !txscript.IsPayToWitnessScriptHash(output.PkScript) {
+			continue
+		}
+		_, outputAddrs, _, err := txscript.ExtractPkScriptAddrs(
+			output.PkScript, w.chainParams,
+		)
+		if err != nil || len(outputAddrs) != 1 {
+			continue
+		}
+		name := addrs.Get([]byte(outputAddrs[0].EncodeAddress()))
+		if string(name) != v.Name {
+			continue
+		}
+		ma, err := w.Manager.Address(addrmgrNs, outputAddrs[0])
+		if err != nil {
+			return nil, err
+		}
+		script, err := ma.(waddrmgr.ManagedScriptAddress).Script()
+		if err != nil {
+			return nil, err
+		}
+		credits = append(credits, vaultCredit{output, script})
+	}
+	sort.Slice(credits, func(i, j int) bool {
+		return credits[i].Amount > credits[j].Amount
+	})
+	return credits, nil
+}
+
+// Vaults returns every vault sorted by name, with its balances.
+func (w *Wallet) Vaults() ([]*Vault, error) {
+	var vaults []*Vault
+	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		ns := dbtx.ReadBucket(walletNamespaceKey)
+		bucket := ns.NestedReadBucket(bucketVaults)
+		if bucket == nil {
+			return nil
+		}
+		err := bucket.ForEach(func(k, b []byte) error {
+			v, err := deserializeVault(string(k), b)
+			if err != nil {
+				return err
+			}
+			vaults = append(vaults, v)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		bs := w.Manager.SyncedTo()
+		for _, v := range vaults {
+			credits, err := w.vaultCredits(dbtx, v)
+			if err != nil {
+				return err
+			}
+			for _, c := range credits {
+				v.Balance += c.Amount
+				delay := int32(v.Delay)
+				if confirmed(delay, c.Height, bs.Height) {
+					v.Spendable += c.Amount
+				}
+			}
+		}
+		return nil
+	})
+	return vaults, err
+}
+
+// vaultTxVSize returns the virtual size of a transaction once it spends the
+// vault outputs with the given witness scripts.
+func vaultTxVSize(tx *wire.MsgTx, scripts [][]byte) int {
+	// The weight of the witnesses includes their marker and flag.
+	weight := tx.SerializeSizeStripped()*4 + 2
+	for _, script := range scripts {
+		weight += vaultSpendWitnessSize + 1 + len(script)
+	}
+	return (weight + 3) / 4
+}
+
+// SendFromVault creates, signs and publishes a transaction paying the outputs
+// from the outputs of a vault which have its delay of confirmations, with a
+// fee at the given rate.  The change is paid to a new address of the vault.
+// The wallet must be unlocked to sign the transaction.
+func (w *Wallet) SendFromVault(name string, outputs []*wire.TxOut,
+	feeSatPerKB btcutil.Amount, label string) (*wire.MsgTx, error) {
+
+	var target btcutil.Amount
+	for _, output := range outputs {
+		err := txrules.CheckOutput(output, txrules.DefaultRelayFeePerKb)
+		if err != nil {
+			return nil, err
+		}
+		target += btcutil.Amount(output.Value)
+	}
+
+	heldUnlock, err := w.holdUnlock()
+	if err != nil {
+		return nil, err
+	}
+	defer heldUnlock.release()
+
+	var (
+		tx         *wire.MsgTx
+		changeAddr btcutil.Address
+	)
+	err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		v, err := fetchVault(dbtx.ReadBucket(walletNamespaceKey), name)
+		if err != nil {
+			return err
+		}
+		credits, err := w.vaultCredits(dbtx, v)
+		if err != nil {
+			return err
+		}
+
+		// The fee is estimated with a change output, which is left
+		// out when it would be dust.  Its address is only derived once
+		// the inputs are chosen, as a failed send rolls back the
+		// derivation but not the caches of the address manager.
+		tx = wire.NewMsgTx(2)
+		tx.TxOut = append(tx.TxOut, outputs...)
+		tx.AddTxOut(wire.NewTxOut(0, make([]byte, p2wshScriptSize)))
+		var (
+			spent   []vaultCredit
+			scripts [][]byte
+			input   btcutil.Amount
+			fee     btcutil.Amount
+		)
+		bs := w.Manager.SyncedTo()
+		for _, c := range credits {
+			if input >= target+fee {
+				break
+			}
+			if !confirmed(int32(v.Delay), c.Height, bs.Height) ||
+				w.LockedOutpoint(c.OutPoint) {
+
+				continue
+			}
+			op := c.OutPoint
+			txIn := wire.NewTxIn(&op, nil, nil)
+			txIn.Sequence = uint32(v.Delay)
+			tx.AddTxIn(txIn)
+			spent = append(spent, c)
+			scripts = append(scripts, c.script)
+			input += c.Amount
+			fee = txrules.FeeForSerializeSize(
+				feeSatPerKB, vaultTxVSize(tx, scripts),
+			)
+		}
+		if input < target+fee {
+			return ErrVaultInsufficientFunds
+		}
+		change := tx.TxOut[len(tx.TxOut)-1]
+		change.Value = int64(input - target - fee)
+		if txrules.IsDustOutput(change, txrules.DefaultRelayFeePerKb) {
+			tx.TxOut = tx.TxOut[:len(tx.TxOut)-1]
+		} else {
+			changeAddr, err = w.newVaultAddress(dbtx, v)
+			if err != nil {
+				return err
+			}
+			change.PkScript, err = txscript.PayToAddrScript(
+				changeAddr,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
+		for i, c := range spent {
+			key, err := w.vaultKey(addrmgrNs, c.script)
+			if err != nil {
+				return err
+			}
+			err = signVaultInput(tx, i, &c, key, false)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if changeAddr != nil {
+		err := w.watchImportedAddress(changeAddr, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = w.reliablyPublishTransaction(tx, label, nil, true, false)
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// vaultKey returns the private key of the vault key a witness script pays
+// to, which is the last key it pushes.
+func (w *Wallet) vaultKey(addrmgrNs walletdb.ReadBucket,
+	script []byte) (*btcec.PrivateKey, error) {
+
+	pushes, err := txscript.PushedData(script)
+	if err != nil {
+		return nil, err
+	}
+	if len(pushes) < 2 {
+		return nil, errors.New("invalid vault script")
+	}
+	keyHash := btcutil.Hash160(pushes[len(pushes)-1])
+	keyAddr, err := btcutil.NewAddressWitnessPubKeyHash(
+		keyHash, w.chainParams,
+	)
+	if err != nil {
+		return nil, err
+	}
+	ma, err := w.Manager.Address(addrmgrNs, keyAddr)
+	if err != nil {
+		return nil, err
+	}
+	mpka, ok := ma.(waddrmgr.ManagedPubKeyAddress)
+	if !ok {
+		return nil, fmt.Errorf("vault key %v is not a public key "+
+			"address", keyAddr)
+	}
+	return mpka.PrivKey()
+}
+
+// signVaultInput adds the witness spending a vault output to an input, by
+// its recovery branch or by the delayed branch of its vault key.
+func signVaultInput(tx *wire.MsgTx, i int, c *vaultCredit,
+	key *btcec.PrivateKey, recovery bool) error {
+
+	sig, err := txscript.RawTxInWitnessSignature(
+		tx, txscript.NewTxSigHashes(tx), i, int64(c.Amount), c.script,
+		txscript.SigHashAll, key,
+	)
+	if err != nil {
+		return err
+	}
+	var selector []byte
+	if recovery {
+		selector = []byte{1}
+	}
+	tx.TxIn[i].Witness = wire.TxWitness{sig, selector, c.script}
+	return nil
+}
+
+// RecoverVault sweeps every unspent output of a vault, confirmed or not, to an
+// address with a fee at the given rate, signing with the recovery key of the
+// vault.  Unlike its vault keys, the recovery key spends the outputs of the
+// vault without any delay, so that they are clawed back from a thief of the
+// wallet keys.  The transaction is published at once.
+func (w *Wallet) RecoverVault(name string, recoveryKey *btcec.PrivateKey,
+	addr btcutil.Address, feeSatPerKB btcutil.Amount) (*wire.MsgTx, error) {
+
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		credits []vaultCredit
+		scripts [][]byte
+		input   btcutil.Amount
+	)
+	err = walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		v, err := fetchVault(dbtx.ReadBucket(walletNamespaceKey), name)
+		if err != nil {
+			return err
+		}
+		pubKey := recoveryKey.PubKey().SerializeCompressed()
+		if !bytes.Equal(pubKey, v.RecoveryKey.SerializeCompressed()) {
+			return ErrVaultRecoveryKey
+		}
+		credits, err = w.vaultCredits(dbtx, v)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tx := wire.NewMsgTx(2)
+	for _, c := range credits {
+		op := c.OutPoint
+		tx.AddTxIn(wire.NewTxIn(&op, nil, nil))
+		scripts = append(scripts, c.script)
+		input += c.Amount
+	}
+	tx.AddTxOut(wire.NewTxOut(0, pkScript))
+	fee := txrules.FeeForSerializeSize(
+		feeSatPerKB, vaultTxVSize(tx, scripts),
+	)
+	tx.TxOut[0].Value = int64(input - fee)
+	dust := txrules.IsDustOutput(tx.TxOut[0], txrules.DefaultRelayFeePerKb)
+	if len(credits) == 0 || dust {
+		return nil, ErrNothingToSweep
+	}
+	for i := range credits {
+		err := signVaultInput(tx, i, &credits[i], recoveryKey, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = w.reliablyPublishTransaction(tx, "", nil, false, false)
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
@@ -0,0 +1,129 @@
+package wallet
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/lbryio/lbcd/btcec"
+	"github.com/lbryio/lbcd/chaincfg"
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/chain"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/stretchr/testify/require"
+)
+
+// TestVault checks that the outputs of a vault are only spent by the vault
+// keys once they have the delay of the vault, and by the recovery key at any
+// time.
+func TestVault(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test_vault")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	params := &chaincfg.RegressionNetParams
+	loader := NewLoader(params, dir, true, defaultDBTimeout, 250)
+	birthday := params.GenesisBlock.Header.Timestamp
+	w, err := loader.CreateNewWallet(
+		[]byte("password"), FixtureSeed, birthday,
+	)
+	require.NoError(t, err)
+	defer loader.UnloadWallet()
+
+	backend := chain.NewMockInterface(params)
+	require.NoError(t, backend.Start())
+	defer backend.Stop()
+	w.Start()
+	w.SynchronizeRPC(backend)
+	backend.MineBlock()
+	require.NoError(t, w.WaitForHeight(1, 5*time.Second))
+	require.NoError(t, w.Unlock([]byte("password"), nil))
+
+	recoveryKey, err := btcec.NewPrivateKey(btcec.S256())
+	require.NoError(t, err)
+	_, err = w.CreateVault("savings", recoveryKey.PubKey(), 3)
+	require.NoError(t, err)
+	addr, err := w.NewVaultAddress("savings")
+	require.NoError(t, err)
+	pkScript, err := txscript.PayToAddrScript(addr)
+	require.NoError(t, err)
+	require.True(t, txscript.IsPayToWitnessScriptHash(pkScript))
+
+	deposit := wire.NewMsgTx(wire.TxVersion)
+	deposit.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 1}, nil, nil))
+	deposit.AddTxOut(wire.NewTxOut(1e8, pkScript))
+	backend.MineBlock(deposit)
+	require.NoError(t, w.WaitForHeight(2, 5*time.Second))
+
+	vaults, err := w.Vaults()
+	require.NoError(t, err)
+	require.Len(t, vaults, 1)
+	require.Equal(t, "savings", vaults[0].Name)
+	require.Equal(t, btcutil.Amount(1e8), vaults[0].Balance)
+	require.Zero(t, vaults[0].Spendable)
+
+	// The deposit is spent neither by coin selection nor by the vault
+	// keys before the delay.
+	balance, _, err := w.CalculateBalance(1)
+	require.NoError(t, err)
+	require.Equal(t, btcutil.Amount(1e8), balance)
+	payee := wire.NewTxOut(4e7, []byte{txscript.OP_TRUE})
+	_, err = w.SendOutputs(
+		context.Background(), []*wire.TxOut{payee}, nil,
+		waddrmgr.ImportedAddrAccount, 1, 1000, CoinSelectionLargest, "",
+	)
+	require.Error(t, err)
+	_, err = w.SendFromVault("savings", []*wire.TxOut{payee}, 1000, "")
+	require.ErrorIs(t, err, ErrVaultInsufficientFunds)
+
+	backend.MineBlock()
+	backend.MineBlock()
+	require.NoError(t, w.WaitForHeight(4, 5*time.Second))
+	spend, err := w.SendFromVault(
+		"savings", []*wire.TxOut{payee}, 1000, "",
+	)
+	require.NoError(t, err)
+	require.Len(t, spend.TxIn, 1)
+	require.Equal(t, uint32(3), spend.TxIn[0].Sequence)
+	require.Len(t, spend.TxOut, 2)
+	verifyVaultInput(t, spend, 0, pkScript, 1e8)
+
+	// The change is paid back to the vault, and swept by the recovery
+	// key while unconfirmed.
+	vaults, err = w.Vaults()
+	require.NoError(t, err)
+	change := vaults[0].Balance
+	require.Equal(t, btcutil.Amount(spend.TxOut[1].Value), change)
+
+	otherKey, err := btcec.NewPrivateKey(btcec.S256())
+	require.NoError(t, err)
+	sweepAddr, err := btcutil.NewAddressPubKeyHash(
+		btcutil.Hash160(otherKey.PubKey().SerializeCompressed()),
+		params,
+	)
+	require.NoError(t, err)
+	_, err = w.RecoverVault("savings", otherKey, sweepAddr, 1000)
+	require.ErrorIs(t, err, ErrVaultRecoveryKey)
+	sweep, err := w.RecoverVault("savings", recoveryKey, sweepAddr, 1000)
+	require.NoError(t, err)
+	require.Len(t, sweep.TxIn, 1)
+	require.Len(t, sweep.TxOut, 1)
+	verifyVaultInput(t, sweep, 0, spend.TxOut[1].PkScript, int64(change))
+}
+
+// verifyVaultInput checks that an input spends a vault output by executing
+// its script.
+func verifyVaultInput(t *testing.T, tx *wire.MsgTx, i int, pkScript []byte,
+	amount int64) {
+
+	vm, err := txscript.NewEngine(
+		pkScript, tx, i, txscript.StandardVerifyFlags, nil,
+		txscript.NewTxSigHashes(tx), amount,
+	)
+	require.NoError(t, err)
+	require.NoError(t, vm.Execute())
+}
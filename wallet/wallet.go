@@ -7,9 +7,11 @@ package wallet
 
 import (
 	"bytes"
+	"context"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"runtime"
 	"sort"
 	"strings"
 	"sync"
@@ -26,6 +28,7 @@ import (
 	btcutil "github.com/lbryio/lbcutil"
 	"github.com/lbryio/lbcutil/hdkeychain"
 	"github.com/lbryio/lbcwallet/chain"
+	"github.com/lbryio/lbcwallet/internal/zero"
 	"github.com/lbryio/lbcwallet/waddrmgr"
 	"github.com/lbryio/lbcwallet/wallet/txauthor"
 	"github.com/lbryio/lbcwallet/wallet/txrules"
@@ -62,6 +65,10 @@ var (
 	// to true.
 	ErrTxLabelExists = errors.New("transaction already labelled")
 
+	// ErrTxNotFound is returned when a transaction looked up by its hash
+	// is not known to the wallet.
+	ErrTxNotFound = errors.New("transaction not found in wallet")
+
 	// ErrTxUnsigned is returned when a transaction is created in the
 	// watch-only mode where we can select coins but not sign any inputs.
 	ErrTxUnsigned = errors.New("watch-only wallet, transaction not signed")
@@ -69,6 +76,11 @@ var (
 	// Namespace bucket keys.
 	waddrmgrNamespaceKey = []byte("waddrmgr")
 	wtxmgrNamespaceKey   = []byte("wtxmgr")
+
+	// walletNamespaceKey is the key of the namespace bucket holding data
+	// owned by the wallet package itself rather than the address or
+	// transaction managers.
+	walletNamespaceKey = []byte("wallet")
 )
 
 type CoinSelectionStrategy int
@@ -101,6 +113,17 @@ type Wallet struct {
 	lockedOutpoints    map[wire.OutPoint]struct{}
 	lockedOutpointsMtx sync.Mutex
 
+	// unlockedUntil is when the wallet is relocked, or zero when it is
+	// locked or its timeout is unknown.
+	unlockedUntil    time.Time
+	unlockedUntilMtx sync.Mutex
+
+	// watchedAddrs holds the addresses not necessarily belonging to the
+	// wallet which clients watch, keyed by their encoding.  It is nil
+	// until an address is first watched.
+	watchedAddrs    map[string]*watchedAddr
+	watchedAddrsMtx sync.Mutex
+
 	recoveryWindow uint32
 
 	// Channels for rescan processing.  Requests are added and merged with
@@ -112,6 +135,10 @@ type Wallet struct {
 	rescanProgress      chan *RescanProgressMsg
 	rescanFinished      chan *RescanFinishedMsg
 
+	// resumeRescansOnce resumes the checkpointed rescans after the first
+	// sync with the chain.
+	resumeRescansOnce sync.Once
+
 	// Channel for transaction creation requests.
 	createTxRequests chan createTxRequest
 
@@ -122,6 +149,87 @@ type Wallet struct {
 	lockState          chan bool
 	changePassphrase   chan changePassphraseRequest
 
+	// changePolicy controls the change outputs of created transactions.
+	changePolicy    ChangePolicy
+	changePolicyMtx sync.Mutex
+
+	// minConfPolicy controls the confirmations of the outputs spent by
+	// created transactions.
+	minConfPolicy    MinConfPolicy
+	minConfPolicyMtx sync.Mutex
+
+	// withdrawalPolicy controls the sends requiring approval, and
+	// withdrawalsMtx serializes the approvals of withdrawals.
+	withdrawalPolicy    WithdrawalPolicy
+	withdrawalPolicyMtx sync.Mutex
+	withdrawalsMtx      sync.Mutex
+
+	// coldSigningPolicy controls whether sends are signed offline.
+	coldSigningPolicy    ColdSigningPolicy
+	coldSigningPolicyMtx sync.Mutex
+
+	// keyPoolSize is the number of addresses of each branch of the
+	// accounts whose keys are derived in advance, and keyPoolRefill is
+	// signalled when addresses are derived, so that they are replaced.
+	keyPoolSize    uint32
+	keyPoolSizeMtx sync.Mutex
+	keyPoolRefill  chan struct{}
+
+	// broadcastPolicy controls the broadcast of published transactions.
+	broadcastPolicy    BroadcastPolicy
+	broadcastPolicyMtx sync.Mutex
+
+	// delayedBroadcasts maps the hashes of the transactions waiting for
+	// the delay of the broadcast policy to the time they are broadcast.
+	delayedBroadcasts    map[chainhash.Hash]time.Time
+	delayedBroadcastsMtx sync.Mutex
+
+	// sendPreview is whether sends requested over RPC are previewed, and
+	// pendingSends maps the handles of previewed sends to the sends
+	// waiting to be confirmed.
+	sendPreview     bool
+	sendPreviewMtx  sync.Mutex
+	pendingSends    map[string]*pendingSend
+	pendingSendsMtx sync.Mutex
+
+	// sendsPaused is the reason the creation and publication of
+	// transactions is refused, or nil.
+	sendsPaused    error
+	sendsPausedMtx sync.Mutex
+
+	// claimAccount is the name of the account funding claims.
+	claimAccount    string
+	claimAccountMtx sync.Mutex
+
+	// backupPolicy controls the backups of the database, and
+	// backupPolicyChanged is signalled when it is set, so that the
+	// periodic backups are rescheduled.
+	backupPolicy        BackupPolicy
+	backupStatus        BackupStatus
+	backupMtx           sync.Mutex
+	backupPolicyChanged chan struct{}
+
+	// diskPolicy controls the monitoring of the free disk space, and
+	// diskPolicyChanged is signalled when it is set, so that the volume
+	// is checked at once.
+	diskPolicy        DiskPolicy
+	diskStatus        DiskStatus
+	diskMtx           sync.Mutex
+	diskPolicyChanged chan struct{}
+
+	// clockOffset is how far the wallet clock was fast-forwarded on
+	// regtest.
+	clockOffset    time.Duration
+	clockOffsetMtx sync.Mutex
+
+	// faucet pays the test coins requested by RequestTestCoins.
+	faucet    Faucet
+	faucetMtx sync.Mutex
+
+	// invoicesChanged is signalled when an invoice is created, so that
+	// its expiry is scheduled.
+	invoicesChanged chan struct{}
+
 	NtfnServer *NotificationServer
 
 	chainParams *chaincfg.Params
@@ -150,9 +258,14 @@ func (w *Wallet) Start() {
 	}
 	w.quitMu.Unlock()
 
-	w.wg.Add(2)
+	w.wg.Add(7)
 	go w.txCreator()
 	go w.walletLocker()
+	go w.invoiceExpirer()
+	go w.backupScheduler()
+	go w.diskMonitor()
+	go w.uptimeRecorder()
+	go w.keyPoolRefiller()
 }
 
 // SynchronizeRPC associates the wallet with the consensus RPC client,
@@ -524,7 +637,22 @@ func (w *Wallet) syncWithChain(birthdayStamp *waddrmgr.BlockStamp) error {
 		return err
 	}
 
-	return w.rescanWithTarget(addrs, unspent, nil)
+	// Watched addresses are only registered with the backend rather than
+	// rescanned, as their history is not recorded by the wallet.
+	if watched := w.WatchedAddresses(); len(watched) != 0 {
+		if err := chainClient.NotifyReceived(watched); err != nil {
+			return err
+		}
+	}
+
+	if err := w.rescanWithTarget(addrs, unspent, nil); err != nil {
+		return err
+	}
+
+	// The rescans of imported addresses interrupted by the last shutdown
+	// resume from where they left off rather than from their start.
+	w.resumeRescans()
+	return nil
 }
 
 // isDevEnv determines whether the wallet is currently under a local developer
@@ -673,8 +801,13 @@ func (w *Wallet) Recovery(chainClient chain.Interface) error {
 	return nil
 }
 
-func (w *Wallet) RescanBlockchain(chainClient chain.Interface,
-	startHeight int32, stopHeight int32) (int32, int32, error) {
+// RescanBlockchain recovers the addresses used by the blocks from startHeight
+// to stopHeight, and returns the heights scanned.  The rescan stops with the
+// error of ctx once it is done, leaving the wallet as synced by the batches of
+// blocks already recovered.
+func (w *Wallet) RescanBlockchain(ctx context.Context,
+	chainClient chain.Interface, startHeight int32,
+	stopHeight int32) (int32, int32, error) {
 
 	log.Infof("Rescanning blockchain from block %d to %d "+
 		"with recovery_window=%d", startHeight, stopHeight,
@@ -691,8 +824,16 @@ func (w *Wallet) RescanBlockchain(chainClient chain.Interface,
 		scopedMgrs[scopedMgr.Scope()] = scopedMgr
 	}
 
+	update := func(f func(tx walletdb.ReadWriteTx) error) error {
+		return walletdb.Update(w.db, f)
+	}
+
 	var blocks []*waddrmgr.BlockStamp
 	for height := startHeight; height <= stopHeight; height++ {
+		if err := ctx.Err(); err != nil {
+			return startHeight, stopHeight, err
+		}
+
 		hash, err := chainClient.GetBlockHash(int64(height))
 		if err != nil {
 			return startHeight, stopHeight, err
@@ -725,19 +866,14 @@ func (w *Wallet) RescanBlockchain(chainClient chain.Interface,
 			continue
 		}
 
-		err = walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
-			ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
-			for scope, scopedMgr := range scopedMgrs {
-				scopeState := recoveryMgr.State().StateForScope(scope)
-				err = expandScopeHorizons(ns, scopedMgr, scopeState)
-				if err != nil {
-					return err
-				}
-			}
-			return w.recoverScopedAddresses(chainClient, tx, ns,
-				recoveryBatch, recoveryMgr.State(), scopedMgrs,
-			)
-		})
+		// Each step of the recovery of the batch is written in a
+		// database transaction of its own, so that getting new
+		// addresses isn't blocked while the chain server filters the
+		// blocks.
+		err = w.recoverScopedAddresses(
+			ctx, chainClient, update, recoveryBatch,
+			recoveryMgr.State(), scopedMgrs,
+		)
 		if err != nil {
 			return startHeight, stopHeight, err
 		}
@@ -768,11 +904,16 @@ func (w *Wallet) RescanBlockchain(chainClient chain.Interface,
 //  5. Trim the range of blocks up to and including the one reporting the addrs.
 //  6. Repeat from (1) if there are still more blocks in the range.
 //
+// The database is accessed with update, which runs its function in a database
+// transaction.  The blocks are filtered outside of it, so that an update
+// opening a transaction of its own doesn't hold the database write lock while
+// the chain server is queried.
+//
 // TODO(conner): parallelize/pipeline/cache intermediate network requests
 func (w *Wallet) recoverScopedAddresses(
+	ctx context.Context,
 	chainClient chain.Interface,
-	tx walletdb.ReadWriteTx,
-	ns walletdb.ReadWriteBucket,
+	update func(func(tx walletdb.ReadWriteTx) error) error,
 	batch []wtxmgr.BlockMeta,
 	recoveryState *RecoveryState,
 	scopedMgrs map[waddrmgr.KeyScope]*waddrmgr.ScopedKeyManager) error {
@@ -783,6 +924,21 @@ func (w *Wallet) recoverScopedAddresses(
 	}
 
 expandHorizons:
+	err := update(func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		for scope, scopedMgr := range scopedMgrs {
+			scopeState := recoveryState.StateForScope(scope)
+			err := expandScopeHorizons(ns, scopedMgr, scopeState)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
 	log.Infof("Scanning %d blocks for recoverable addresses", len(batch))
 
 	// With the internal and external horizons properly expanded, we now
@@ -790,6 +946,7 @@ expandHorizons:
 	// of blocks we intend to scan, in addition to the scope-index -> addr
 	// map for all internal and external branches.
 	filterReq := newFilterBlocksRequest(batch, scopedMgrs, recoveryState)
+	filterReq.Context = ctx
 
 	// Initiate the filter blocks request using our chain backend. If an
 	// error occurs, we are unable to proceed with the recovery.
@@ -813,12 +970,42 @@ expandHorizons:
 	// Log any non-trivial findings of addresses or outpoints.
 	logFilterBlocksResp(block, filterResp)
 
-	// Report any external or internal addresses found as a result of the
-	// appropriate branch recovery state. Adding indexes above the
-	// last-found index of either will result in the horizons being expanded
-	// upon the next iteration. Any found addresses are also marked used
-	// using the scoped key manager.
-	err = extendFoundAddresses(ns, filterResp, scopedMgrs, recoveryState)
+	err = update(func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+
+		// Report any external or internal addresses found as a result
+		// of the appropriate branch recovery state. Adding indexes
+		// above the last-found index of either will result in the
+		// horizons being expanded upon the next iteration. Any found
+		// addresses are also marked used using the scoped key manager.
+		err := extendFoundAddresses(
+			ns, filterResp, scopedMgrs, recoveryState,
+		)
+		if err != nil {
+			return err
+		}
+
+		// Finally, record all of the relevant transactions that were
+		// returned in the filter blocks response. This ensures that
+		// these transactions and their outputs are tracked when the
+		// final rescan is performed.
+		for _, txn := range filterResp.RelevantTxns {
+			txRecord, err := wtxmgr.NewTxRecordFromMsgTx(
+				txn, filterResp.BlockMeta.Time,
+			)
+			if err != nil {
+				return err
+			}
+
+			err = w.addRelevantTx(
+				tx, txRecord, &filterResp.BlockMeta,
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		return err
 	}
@@ -830,23 +1017,6 @@ expandHorizons:
 		recoveryState.AddWatchedOutPoint(&outPoint, addr)
 	}
 
-	// Finally, record all of the relevant transactions that were returned
-	// in the filter blocks response. This ensures that these transactions
-	// and their outputs are tracked when the final rescan is performed.
-	for _, txn := range filterResp.RelevantTxns {
-		txRecord, err := wtxmgr.NewTxRecordFromMsgTx(
-			txn, filterResp.BlockMeta.Time,
-		)
-		if err != nil {
-			return err
-		}
-
-		err = w.addRelevantTx(tx, txRecord, &filterResp.BlockMeta)
-		if err != nil {
-			return err
-		}
-	}
-
 	// Update the batch to indicate that we've processed all block through
 	// the one that returned found addresses.
 	batch = batch[filterResp.BatchIndex+1:]
@@ -876,37 +1046,46 @@ func expandScopeHorizons(
 		if err != nil {
 			return err
 		}
+		addrType := waddrmgr.ScopeAddrMap[scopedMgr.Scope()].ExternalAddrType
 		for branchIndex, branchState := range accountState {
 			exHorizon, exWindow := branchState.ExtendHorizon()
-			count, addrIndex := uint32(0), exHorizon
+			if exWindow == 0 {
+				continue
+			}
 
+			// The lookahead only needs the public keys of the branch,
+			// which can be derived by several goroutines at once.
 			branchKey, err := acctKey.Derive(uint32(branchIndex))
 			if err != nil {
 				return err
 			}
+			branchPub, err := branchKey.Neuter()
+			if err != nil {
+				return err
+			}
 
+			count, addrIndex := uint32(0), exHorizon
 			for count < exWindow {
-				kp := keyPath(uint32(accountIndex), uint32(branchIndex), addrIndex)
-				indexKey, err := branchKey.Derive(addrIndex)
+				addrs, err := deriveBranchAddrs(
+					scopedMgr, branchPub, uint32(accountIndex),
+					uint32(branchIndex), addrIndex,
+					exWindow-count, addrType,
+				)
 				if err != nil {
 					return err
 				}
-				addrType := waddrmgr.ScopeAddrMap[scopedMgr.Scope()].ExternalAddrType
-				addr, err := scopedMgr.DeriveFromExtKeys(kp, indexKey, addrType)
-				switch {
-				case err == hdkeychain.ErrInvalidChild:
-					branchState.MarkInvalidChild(addrIndex)
-					addrIndex++
-					continue
 
-				case err != nil:
-					return err
+				// Record the addresses in index order, deriving
+				// another round for the few invalid children.
+				for _, addr := range addrs {
+					if addr == nil {
+						branchState.MarkInvalidChild(addrIndex)
+					} else {
+						branchState.AddAddr(addrIndex, addr)
+						count++
+					}
+					addrIndex++
 				}
-
-				branchState.AddAddr(addrIndex, addr.Address())
-
-				addrIndex++
-				count++
 			}
 		}
 	}
@@ -914,6 +1093,56 @@ func expandScopeHorizons(
 	return nil
 }
 
+// deriveBranchAddrs derives the addresses of the n children of a branch key
+// starting at index start, splitting the work across the available CPUs.  The
+// address of an invalid child is left nil.
+func deriveBranchAddrs(scopedMgr *waddrmgr.ScopedKeyManager,
+	branchKey *hdkeychain.ExtendedKey, account, branch, start, n uint32,
+	addrType waddrmgr.AddressType) ([]btcutil.Address, error) {
+
+	addrs := make([]btcutil.Address, n)
+	workers := uint32(runtime.NumCPU())
+	if workers > n {
+		workers = n
+	}
+
+	var (
+		wg      sync.WaitGroup
+		errOnce sync.Once
+		err     error
+	)
+	wg.Add(int(workers))
+	for worker := uint32(0); worker < workers; worker++ {
+		go func(worker uint32) {
+			defer wg.Done()
+
+			for i := worker; i < n; i += workers {
+				index := start + i
+				indexKey, e := branchKey.Derive(index)
+				if e == nil {
+					var addr waddrmgr.ManagedAddress
+					addr, e = scopedMgr.DeriveFromExtKeys(
+						keyPath(account, branch, index),
+						indexKey, addrType,
+					)
+					if e == nil {
+						addrs[i] = addr.Address()
+					}
+				}
+				switch {
+				case e == hdkeychain.ErrInvalidChild:
+				case e != nil:
+					errOnce.Do(func() { err = e })
+					return
+				}
+			}
+		}(worker)
+	}
+	wg.Wait()
+
+	return addrs, err
+}
+
 // keyPath returns the relative derivation path /account/branch/index.
 func keyPath(account, branch, index uint32) waddrmgr.DerivationPath {
 	return waddrmgr.DerivationPath{
@@ -966,38 +1195,47 @@ func extendFoundAddresses(ns walletdb.ReadWriteBucket,
 	scopedMgrs map[waddrmgr.KeyScope]*waddrmgr.ScopedKeyManager,
 	recoveryState *RecoveryState) error {
 
-	// Mark all recovered addresses as used. This will be done only for
-	// scopes that reported a non-zero number of addresses in this block.
+	// First, report all child indexes found in the block. This ensures
+	// that the last-found index of each branch will be updated to include
+	// the maximum child index seen thus far.
+	type branchID struct {
+		scope   waddrmgr.KeyScope
+		account uint32
+		branch  uint32
+	}
+	branches := make(map[branchID]*BranchRecoveryState)
 	for index := range filterResp.FoundAddresses {
-		scopedMgr := scopedMgrs[index.Scope]
-		// First, report all child indexes found for this scope. This
-		// ensures that the last-found index will be updated to include
-		// the maximum child index seen thus far.
 		scopeState := recoveryState.StateForScope(index.Scope)
 		branchState := scopeState[index.Account][index.Branch]
 		branchState.ReportFound(index.Index)
-		// Now, with all found addresses reported, derive and extend all
-		// external addresses up to and including the current last found
-		// index for this scope.
-		nextFound := branchState.NextUnfound()
+		branches[branchID{index.Scope, index.Account, index.Branch}] =
+			branchState
+	}
 
-		lastFound := nextFound
+	// Now, with all found addresses reported, derive and extend the
+	// addresses of each branch up to and including its last found index,
+	// once per branch rather than once per found address.
+	for key, branchState := range branches {
+		lastFound := branchState.NextUnfound()
 		if lastFound > 0 {
 			lastFound--
 		}
 
-		err := scopedMgr.ExtendAddresses(
-			ns, index.Account, index.Branch, lastFound,
+		err := scopedMgrs[key.scope].ExtendAddresses(
+			ns, key.account, key.branch, lastFound,
 		)
 		if err != nil {
 			return err
 		}
+	}
 
-		// Finally, with the scope's addresses extended, we mark used
-		// the addresses that were found in the block and belong to
-		// this scope.
+	// Finally, with the addresses extended, we mark used the addresses
+	// that were found in the block.
+	for index := range filterResp.FoundAddresses {
+		scopeState := recoveryState.StateForScope(index.Scope)
+		branchState := scopeState[index.Account][index.Branch]
 		addr := branchState.GetAddr(index.Index)
-		err = scopedMgr.MarkUsed(ns, addr)
+		err := scopedMgrs[index.Scope].MarkUsed(ns, addr)
 		if err != nil {
 			return err
 		}
@@ -1064,6 +1302,7 @@ type (
 		feeSatPerKB           btcutil.Amount
 		coinSelectionStrategy CoinSelectionStrategy
 		dryRun                bool
+		unsigned              bool
 		resp                  chan createTxResponse
 	}
 	createTxResponse struct {
@@ -1091,19 +1330,23 @@ out:
 			// If the wallet can be locked because it contains
 			// private key material, we need to prevent it from
 			// doing so while we are assembling the transaction.
+			// Unsigned transactions need no keys, so that they
+			// are also created by locked and watch-only wallets.
 			release := func() {}
-			heldUnlock, err := w.holdUnlock()
-			if err != nil {
-				txr.resp <- createTxResponse{nil, err}
-				continue
+			if !txr.unsigned {
+				heldUnlock, err := w.holdUnlock()
+				if err != nil {
+					txr.resp <- createTxResponse{nil, err}
+					continue
+				}
+				release = heldUnlock.release
 			}
 
-			release = heldUnlock.release
-
-			tx, err := w.txToOutputs(
+			tx, err := w.authorTxToOutputs(
 				txr.outputs, txr.keyScope, txr.account,
 				txr.minconf, txr.feeSatPerKB,
 				txr.coinSelectionStrategy, txr.dryRun,
+				!txr.unsigned,
 			)
 
 			release()
@@ -1133,7 +1376,21 @@ func (w *Wallet) CreateSimpleTx(keyScope *waddrmgr.KeyScope, account uint32,
 	coinSelectionStrategy CoinSelectionStrategy, dryRun bool) (
 	*txauthor.AuthoredTx, error) {
 
-	req := createTxRequest{
+	return w.createSimpleTx(
+		context.Background(), keyScope, account, outputs, minconf,
+		satPerKb, coinSelectionStrategy, dryRun,
+	)
+}
+
+// createSimpleTx is CreateSimpleTx returning the error of ctx when it is done
+// while the request waits for the creation of other transactions.
+func (w *Wallet) createSimpleTx(ctx context.Context,
+	keyScope *waddrmgr.KeyScope, account uint32, outputs []*wire.TxOut,
+	minconf int32, satPerKb btcutil.Amount,
+	coinSelectionStrategy CoinSelectionStrategy, dryRun bool) (
+	*txauthor.AuthoredTx, error) {
+
+	return w.createTx(ctx, createTxRequest{
 		keyScope:              keyScope,
 		account:               account,
 		outputs:               outputs,
@@ -1141,9 +1398,20 @@ func (w *Wallet) CreateSimpleTx(keyScope *waddrmgr.KeyScope, account uint32,
 		feeSatPerKB:           satPerKb,
 		coinSelectionStrategy: coinSelectionStrategy,
 		dryRun:                dryRun,
-		resp:                  make(chan createTxResponse),
+	})
+}
+
+// createTx has a transaction created by the txCreator, returning the error of
+// ctx when it is done while the request waits.
+func (w *Wallet) createTx(ctx context.Context, req createTxRequest) (
+	*txauthor.AuthoredTx, error) {
+
+	req.resp = make(chan createTxResponse)
+	select {
+	case w.createTxRequests <- req:
+	case <-ctx.Done():
+		return nil, ctx.Err()
 	}
-	w.createTxRequests <- req
 	resp := <-req.resp
 	return resp.tx, resp.err
 }
@@ -1152,6 +1420,7 @@ type (
 	unlockRequest struct {
 		passphrase []byte
 		lockAfter  <-chan time.Time // nil prevents the timeout.
+		until      time.Time        // When lockAfter fires, if known.
 		err        chan error
 	}
 
@@ -1186,6 +1455,7 @@ out:
 				continue
 			}
 			timeout = req.lockAfter
+			w.setUnlockedUntil(req.until)
 			if timeout == nil {
 				log.Info("The wallet has been unlocked without a time limit")
 			} else {
@@ -1239,6 +1509,7 @@ out:
 		// Select statement fell through by an explicit lock or the
 		// timer expiring.  Lock the manager here.
 		timeout = nil
+		w.setUnlockedUntil(time.Time{})
 		err := w.Manager.Lock()
 		if err != nil && !waddrmgr.IsError(err, waddrmgr.ErrLocked) {
 			log.Errorf("Could not lock wallet: %v", err)
@@ -1255,15 +1526,46 @@ out:
 // be locked if the passphrase is incorrect or any other error occurs during the
 // unlock.
 func (w *Wallet) Unlock(passphrase []byte, lock <-chan time.Time) error {
+	return w.unlock(passphrase, lock, time.Time{})
+}
+
+// UnlockUntil unlocks the wallet like Unlock, and relocks it at until, or
+// never if it is zero.  The time it is relocked is returned by UnlockedUntil.
+func (w *Wallet) UnlockUntil(passphrase []byte, until time.Time) error {
+	var lock <-chan time.Time
+	if !until.IsZero() {
+		lock = time.After(time.Until(until))
+	}
+	return w.unlock(passphrase, lock, until)
+}
+
+func (w *Wallet) unlock(passphrase []byte, lock <-chan time.Time,
+	until time.Time) error {
+
 	err := make(chan error, 1)
 	w.unlockRequests <- unlockRequest{
 		passphrase: passphrase,
 		lockAfter:  lock,
+		until:      until,
 		err:        err,
 	}
 	return <-err
 }
 
+// UnlockedUntil returns when the unlocked wallet is relocked.  It is zero when
+// the wallet is locked, or unlocked without a timeout or by Unlock.
+func (w *Wallet) UnlockedUntil() time.Time {
+	w.unlockedUntilMtx.Lock()
+	defer w.unlockedUntilMtx.Unlock()
+	return w.unlockedUntil
+}
+
+func (w *Wallet) setUnlockedUntil(until time.Time) {
+	w.unlockedUntilMtx.Lock()
+	w.unlockedUntil = until
+	w.unlockedUntilMtx.Unlock()
+}
+
 // Lock locks the wallet's address manager.
 func (w *Wallet) Lock() {
 	select {
@@ -1319,6 +1621,19 @@ func (w *Wallet) ChangePassphrase(old, new []byte) error {
 	return <-err
 }
 
+// ChangePublicPassphrase changes the public passphrase of the wallet from old
+// to new.  The public passphrase only protects public data such as addresses
+// and extended public keys, so this does not depend on the lock state of the
+// wallet.  Once changed, the wallet must be opened with the new passphrase.
+func (w *Wallet) ChangePublicPassphrase(old, new []byte) error {
+	return walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		addrmgrNs := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		return w.Manager.ChangePublicPassphrase(
+			addrmgrNs, old, new, &waddrmgr.DefaultScryptOptions,
+		)
+	})
+}
+
 // AccountAddresses returns the addresses for every created address for an
 // account.
 func (w *Wallet) AccountAddresses(account uint32, scope *waddrmgr.KeyScope) (
@@ -1426,6 +1741,21 @@ func (w *Wallet) CalculateAccountBalances(account uint32, confirms int32) (Balan
 	return bals, err
 }
 
+// TxCount returns the number of transactions of the wallet, both mined and
+// unmined.
+func (w *Wallet) TxCount() (int, error) {
+	var n int
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		txmgrNs := tx.ReadBucket(wtxmgrNamespaceKey)
+		return w.TxStore.RangeTransactions(txmgrNs, 0, -1,
+			func(details []wtxmgr.TxDetails) (bool, error) {
+				n += len(details)
+				return false, nil
+			})
+	})
+	return n, err
+}
+
 // CurrentAddress gets the most recently requested Bitcoin payment address
 // from a wallet for a particular key-chain scope.  If the address has already
 // been used (there is at least one transaction spending to it in the
@@ -1724,6 +2054,10 @@ func (w *Wallet) LookupAccount(name string) (waddrmgr.KeyScope, uint32, error) {
 
 // RenameAccount sets the name for an account number to newName.
 func (w *Wallet) RenameAccount(scope waddrmgr.KeyScope, account uint32, newName string) error {
+	if err := w.requireDiskSpace(); err != nil {
+		return err
+	}
+
 	manager, err := w.Manager.FetchScopedKeyManager(scope)
 	if err != nil {
 		return err
@@ -1751,6 +2085,10 @@ func (w *Wallet) RenameAccount(scope waddrmgr.KeyScope, account uint32, newName
 // accounts have no transaction history (this is a deviation from the BIP0044
 // spec, which allows no unused account gaps).
 func (w *Wallet) NextAccount(scope waddrmgr.KeyScope, name string) (uint32, error) {
+	if err := w.requireDiskSpace(); err != nil {
+		return 0, err
+	}
+
 	manager, err := w.Manager.FetchScopedKeyManager(scope)
 	if err != nil {
 		return 0, err
@@ -1962,13 +2300,20 @@ outputs:
 
 // ListSinceBlock returns a slice of objects with details about transactions
 // since the given block. If the block is -1 then all transactions are included.
-// This is intended to be used for listsinceblock RPC replies.
-func (w *Wallet) ListSinceBlock(accountName string, start, end, syncHeight int32) ([]btcjson.ListTransactionsResult, error) {
+// This is intended to be used for listsinceblock RPC replies.  The listing
+// stops with the error of ctx once it is done.
+func (w *Wallet) ListSinceBlock(ctx context.Context, accountName string,
+	start, end, syncHeight int32) ([]btcjson.ListTransactionsResult,
+	error) {
+
 	txList := []btcjson.ListTransactionsResult{}
 	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
 		txmgrNs := tx.ReadBucket(wtxmgrNamespaceKey)
 
 		rangeFn := func(details []wtxmgr.TxDetails) (bool, error) {
+			if err := ctx.Err(); err != nil {
+				return false, err
+			}
 			for _, detail := range details {
 				detail := detail
 
@@ -1988,8 +2333,10 @@ func (w *Wallet) ListSinceBlock(accountName string, start, end, syncHeight int32
 
 // ListTransactions returns a slice of objects with details about a recorded
 // transaction.  This is intended to be used for listtransactions RPC
-// replies.
-func (w *Wallet) ListTransactions(accountName string, from, count int) ([]btcjson.ListTransactionsResult, error) {
+// replies.  The listing stops with the error of ctx once it is done.
+func (w *Wallet) ListTransactions(ctx context.Context, accountName string,
+	from, count int) ([]btcjson.ListTransactionsResult, error) {
+
 	txList := []btcjson.ListTransactionsResult{}
 
 	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
@@ -2003,6 +2350,9 @@ func (w *Wallet) ListTransactions(accountName string, from, count int) ([]btcjso
 		// include the next count transactions.
 		skipped := 0
 		rangeFn := func(details []wtxmgr.TxDetails) (bool, error) {
+			if err := ctx.Err(); err != nil {
+				return false, err
+			}
 
 			for _, detail := range details {
 				if from > skipped {
@@ -2039,8 +2389,12 @@ func (w *Wallet) ListTransactions(accountName string, from, count int) ([]btcjso
 
 // ListAddressTransactions returns a slice of objects with details about
 // recorded transactions to or from any address belonging to a set.  This is
-// intended to be used for listaddresstransactions RPC replies.
-func (w *Wallet) ListAddressTransactions(accountName string, pkHashes map[string]struct{}) ([]btcjson.ListTransactionsResult, error) {
+// intended to be used for listaddresstransactions RPC replies.  The listing
+// stops with the error of ctx once it is done.
+func (w *Wallet) ListAddressTransactions(ctx context.Context,
+	accountName string, pkHashes map[string]struct{}) (
+	[]btcjson.ListTransactionsResult, error) {
+
 	txList := []btcjson.ListTransactionsResult{}
 	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
 		txmgrNs := tx.ReadBucket(wtxmgrNamespaceKey)
@@ -2049,6 +2403,10 @@ func (w *Wallet) ListAddressTransactions(accountName string, pkHashes map[string
 		// the number of tx confirmations.
 		syncBlock := w.Manager.SyncedTo()
 		rangeFn := func(details []wtxmgr.TxDetails) (bool, error) {
+			if err := ctx.Err(); err != nil {
+				return false, err
+			}
+
 		loopDetails:
 			for i := range details {
 				detail := &details[i]
@@ -2086,8 +2444,10 @@ func (w *Wallet) ListAddressTransactions(accountName string, pkHashes map[string
 
 // ListAllTransactions returns a slice of objects with details about a recorded
 // transaction.  This is intended to be used for listalltransactions RPC
-// replies.
-func (w *Wallet) ListAllTransactions(accountName string) ([]btcjson.ListTransactionsResult, error) {
+// replies.  The listing stops with the error of ctx once it is done.
+func (w *Wallet) ListAllTransactions(ctx context.Context, accountName string) (
+	[]btcjson.ListTransactionsResult, error) {
+
 	txList := []btcjson.ListTransactionsResult{}
 	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
 		txmgrNs := tx.ReadBucket(wtxmgrNamespaceKey)
@@ -2097,6 +2457,10 @@ func (w *Wallet) ListAllTransactions(accountName string) ([]btcjson.ListTransact
 		syncBlock := w.Manager.SyncedTo()
 
 		rangeFn := func(details []wtxmgr.TxDetails) (bool, error) {
+			if err := ctx.Err(); err != nil {
+				return false, err
+			}
+
 			// Iterate over transactions at this height in reverse order.
 			// This does nothing for unmined transactions, which are
 			// unsorted, but it will process mined transactions in the
@@ -2806,6 +3170,10 @@ func (w *Wallet) SortedActivePaymentAddresses() ([]string, error) {
 func (w *Wallet) NewAddress(account uint32,
 	scope waddrmgr.KeyScope) (btcutil.Address, error) {
 
+	if err := w.requireDiskSpace(); err != nil {
+		return nil, err
+	}
+
 	chainClient, err := w.requireChainClient()
 	if err != nil {
 		return nil, err
@@ -2836,6 +3204,76 @@ func (w *Wallet) NewAddress(account uint32,
 	return addr, nil
 }
 
+// ReceiveAddress is an external address derived for an account, with its
+// index on the external branch.
+type ReceiveAddress struct {
+	Address btcutil.Address
+	Index   uint32
+}
+
+// NewAddresses derives the next n external addresses of an account at once,
+// in the order of their derivation indexes.  The addresses are derived in a
+// single database transaction, so no other address of the account is derived
+// in between.
+func (w *Wallet) NewAddresses(account uint32, scope waddrmgr.KeyScope,
+	n uint32) ([]ReceiveAddress, error) {
+
+	if err := w.requireDiskSpace(); err != nil {
+		return nil, err
+	}
+
+	chainClient, err := w.requireChainClient()
+	if err != nil {
+		return nil, err
+	}
+	manager, err := w.Manager.FetchScopedKeyManager(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		addrs []ReceiveAddress
+		props *waddrmgr.AccountProperties
+	)
+	err = walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		addrmgrNs := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		maddrs, err := manager.NextAddresses(
+			addrmgrNs, account, waddrmgr.ExternalBranch, n,
+		)
+		if err != nil {
+			return err
+		}
+		w.signalKeyPoolRefill()
+		addrs = make([]ReceiveAddress, 0, len(maddrs))
+		for _, maddr := range maddrs {
+			addr := ReceiveAddress{Address: maddr.Address()}
+			if pka, ok := maddr.(waddrmgr.ManagedPubKeyAddress); ok {
+				_, path, _ := pka.DerivationInfo()
+				addr.Index = path.Index
+			}
+			addrs = append(addrs, addr)
+		}
+		props, err = manager.AccountProperties(addrmgrNs, account)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Notify the rpc server about the newly created addresses.
+	notify := make([]btcutil.Address, 0, len(addrs))
+	for _, addr := range addrs {
+		notify = append(notify, addr.Address)
+	}
+	if err := chainClient.NotifyReceived(notify); err != nil {
+		return nil, err
+	}
+
+	w.NtfnServer.notifyAccountProperties(props)
+
+	return addrs, nil
+}
+
 func (w *Wallet) newAddress(addrmgrNs walletdb.ReadWriteBucket, account uint32,
 	scope waddrmgr.KeyScope) (btcutil.Address, *waddrmgr.AccountProperties, error) {
 
@@ -2849,6 +3287,7 @@ func (w *Wallet) newAddress(addrmgrNs walletdb.ReadWriteBucket, account uint32,
 	if err != nil {
 		return nil, nil, err
 	}
+	w.signalKeyPoolRefill()
 
 	props, err := manager.AccountProperties(addrmgrNs, account)
 	if err != nil {
@@ -2907,6 +3346,7 @@ func (w *Wallet) newChangeAddress(addrmgrNs walletdb.ReadWriteBucket,
 	if err != nil {
 		return nil, err
 	}
+	w.signalKeyPoolRefill()
 
 	return addrs[0].Address(), nil
 }
@@ -3011,23 +3451,45 @@ func (w *Wallet) TotalReceivedForAccounts(scope waddrmgr.KeyScope,
 // returning the total amount of bitcoins received for a single wallet
 // address.
 func (w *Wallet) TotalReceivedForAddr(addr btcutil.Address, minConf int32) (btcutil.Amount, error) {
-	var amount btcutil.Amount
+	amounts, err := w.TotalReceivedForAddrs(context.Background(),
+		[]btcutil.Address{addr}, minConf)
+	if err != nil {
+		return 0, err
+	}
+	return amounts[0], nil
+}
+
+// TotalReceivedForAddrs returns the total amount received by each of a set
+// of addresses, in the order of the addresses, with a single pass over the
+// wallet's transaction history.
+func (w *Wallet) TotalReceivedForAddrs(ctx context.Context,
+	addrs []btcutil.Address, minConf int32) ([]btcutil.Amount, error) {
+
+	totals := make(map[string]btcutil.Amount, len(addrs))
+	for _, addr := range addrs {
+		totals[addr.EncodeAddress()] = 0
+	}
 	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
 		txmgrNs := tx.ReadBucket(wtxmgrNamespaceKey)
 
 		syncBlock := w.Manager.SyncedTo()
 
-		var (
-			addrStr    = addr.EncodeAddress()
-			stopHeight int32
-		)
-
+		// A stop height below the first block would range the
+		// blocks in reverse with the unmined transactions, when no
+		// transaction has enough confirmations.
+		var stopHeight int32
 		if minConf > 0 {
 			stopHeight = syncBlock.Height - minConf + 1
+			if stopHeight < 0 {
+				return nil
+			}
 		} else {
 			stopHeight = -1
 		}
 		rangeFn := func(details []wtxmgr.TxDetails) (bool, error) {
+			if err := ctx.Err(); err != nil {
+				return false, err
+			}
 			for i := range details {
 				detail := &details[i]
 				for _, cred := range detail.Credits {
@@ -3040,8 +3502,9 @@ func (w *Wallet) TotalReceivedForAddr(addr btcutil.Address, minConf int32) (btcu
 						continue
 					}
 					for _, a := range addrs {
-						if addrStr == a.EncodeAddress() {
-							amount += cred.Amount
+						addrStr := a.EncodeAddress()
+						if _, ok := totals[addrStr]; ok {
+							totals[addrStr] += cred.Amount
 							break
 						}
 					}
@@ -3051,7 +3514,15 @@ func (w *Wallet) TotalReceivedForAddr(addr btcutil.Address, minConf int32) (btcu
 		}
 		return w.TxStore.RangeTransactions(txmgrNs, 0, stopHeight, rangeFn)
 	})
-	return amount, err
+	if err != nil {
+		return nil, err
+	}
+
+	amounts := make([]btcutil.Amount, len(addrs))
+	for i, addr := range addrs {
+		amounts[i] = totals[addr.EncodeAddress()]
+	}
+	return amounts, nil
 }
 
 // SendOutputs creates and sends payment transactions. Coin selection is
@@ -3061,10 +3532,26 @@ func (w *Wallet) TotalReceivedForAddr(addr btcutil.Address, minConf int32) (btcu
 // selected. This is done to handle the default account case, where a user wants
 // to fund a PSBT with inputs regardless of their type (NP2WKH, P2WKH, etc.). It
 // returns the transaction upon success.
-func (w *Wallet) SendOutputs(outputs []*wire.TxOut, keyScope *waddrmgr.KeyScope,
-	account uint32, minconf int32, satPerKb btcutil.Amount,
-	coinSelectionStrategy CoinSelectionStrategy, label string) (
-	*wire.MsgTx, error) {
+//
+// The send is abandoned with the error of ctx when it is done before the
+// transaction is published.  A published transaction is never canceled.
+func (w *Wallet) SendOutputs(ctx context.Context, outputs []*wire.TxOut,
+	keyScope *waddrmgr.KeyScope, account uint32, minconf int32,
+	satPerKb btcutil.Amount, coinSelectionStrategy CoinSelectionStrategy,
+	label string) (*wire.MsgTx, error) {
+
+	return w.SendOutputsWithComment(ctx, outputs, keyScope, account,
+		minconf, satPerKb, coinSelectionStrategy, label, nil)
+}
+
+// SendOutputsWithComment is SendOutputs storing a comment for the
+// transaction, in the same database transaction as the transaction itself.
+// A nil comment stores none.
+func (w *Wallet) SendOutputsWithComment(ctx context.Context,
+	outputs []*wire.TxOut, keyScope *waddrmgr.KeyScope, account uint32,
+	minconf int32, satPerKb btcutil.Amount,
+	coinSelectionStrategy CoinSelectionStrategy, label string,
+	comment *TxComment) (*wire.MsgTx, error) {
 
 	// Ensure the outputs to be created adhere to the network's consensus
 	// rules.
@@ -3081,15 +3568,20 @@ func (w *Wallet) SendOutputs(outputs []*wire.TxOut, keyScope *waddrmgr.KeyScope,
 	// transaction will be added to the database in order to ensure that we
 	// continue to re-broadcast the transaction upon restarts until it has
 	// been confirmed.
-	createdTx, err := w.CreateSimpleTx(
-		keyScope, account, outputs, minconf, satPerKb,
+	createdTx, err := w.createSimpleTx(
+		ctx, keyScope, account, outputs, minconf, satPerKb,
 		coinSelectionStrategy, false,
 	)
 	if err != nil {
 		return nil, err
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
-	txHash, err := w.reliablyPublishTransaction(createdTx.Tx, label)
+	txHash, err := w.reliablyPublishTransaction(
+		createdTx.Tx, label, comment, true, isApprovedWithdrawal(ctx),
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -3123,6 +3615,15 @@ func (w *Wallet) SignTransaction(tx *wire.MsgTx, hashType txscript.SigHashType,
 	additionalKeysByAddress map[string]*btcutil.WIF,
 	p2shRedeemScriptsByAddress map[string][]byte) ([]SignatureError, error) {
 
+	// Private keys decrypted from the wallet are only needed while
+	// signing, so they are tracked to be cleared once done.
+	var walletKeys []*btcec.PrivateKey
+	defer func() {
+		for _, key := range walletKeys {
+			zero.BigInt(key.D)
+		}
+	}()
+
 	var signErrors []SignatureError
 	err := walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
 		addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
@@ -3172,6 +3673,7 @@ func (w *Wallet) SignTransaction(tx *wire.MsgTx, hashType txscript.SigHashType,
 				if err != nil {
 					return nil, false, err
 				}
+				walletKeys = append(walletKeys, key)
 
 				return key, pka.Compressed(), nil
 			})
@@ -3283,7 +3785,7 @@ func (e *ErrReplacement) Unwrap() error {
 // This function is unstable and will be removed once syncing code is moved out
 // of the wallet.
 func (w *Wallet) PublishTransaction(tx *wire.MsgTx, label string) error {
-	_, err := w.reliablyPublishTransaction(tx, label)
+	_, err := w.reliablyPublishTransaction(tx, label, nil, false, false)
 	return err
 }
 
@@ -3292,8 +3794,18 @@ func (w *Wallet) PublishTransaction(tx *wire.MsgTx, label string) error {
 // relevant database state, and finally possible removing the transaction from
 // the database (along with cleaning up all inputs used, and outputs created) if
 // the transaction is rejected by the backend.
+//
+// Transactions created by the wallet may be delayed, in which case their
+// broadcast happens after this returns, following the broadcast policy.  Only
+// the sends of approved withdrawals may pay more than the threshold of the
+// withdrawal policy.
 func (w *Wallet) reliablyPublishTransaction(tx *wire.MsgTx,
-	label string) (*chainhash.Hash, error) {
+	label string, comment *TxComment, delayable,
+	approved bool) (*chainhash.Hash, error) {
+
+	if err := w.requireSendsAllowed(); err != nil {
+		return nil, err
+	}
 
 	chainClient, err := w.requireChainClient()
 	if err != nil {
@@ -3314,6 +3826,26 @@ func (w *Wallet) reliablyPublishTransaction(tx *wire.MsgTx,
 	var ourAddrs []btcutil.Address
 	err = walletdb.Update(w.db, func(dbTx walletdb.ReadWriteTx) error {
 		addrmgrNs := dbTx.ReadWriteBucket(waddrmgrNamespaceKey)
+		txmgrNs := dbTx.ReadWriteBucket(wtxmgrNamespaceKey)
+
+		// Enforce the spend and withdrawal policies unless the
+		// transaction is already known, in which case it was checked
+		// when first published.
+		txHash := tx.TxHash()
+		details, err := w.TxStore.TxDetails(txmgrNs, &txHash)
+		if err != nil {
+			return err
+		}
+		if details == nil {
+			if err := w.applySpendPolicy(dbTx, tx); err != nil {
+				return err
+			}
+			err := w.applyWithdrawalPolicy(dbTx, tx, approved)
+			if err != nil {
+				return err
+			}
+		}
+
 		for _, txOut := range tx.TxOut {
 			_, addrs, _, err := txscript.ExtractPkScriptAddrs(
 				txOut.PkScript, w.chainParams,
@@ -3340,11 +3872,14 @@ func (w *Wallet) reliablyPublishTransaction(tx *wire.MsgTx,
 		// If there is a label we should write, get the namespace key
 		// and record it in the tx store.
 		if len(label) != 0 {
-			txmgrNs := dbTx.ReadWriteBucket(wtxmgrNamespaceKey)
 			if err = w.TxStore.PutTxLabel(txmgrNs, tx.TxHash(), label); err != nil {
 				return err
 			}
 		}
+		walletNs := dbTx.ReadWriteBucket(walletNamespaceKey)
+		if err := putTxComment(walletNs, &txHash, comment); err != nil {
+			return err
+		}
 
 		return w.addRelevantTx(dbTx, txRec, nil)
 	})
@@ -3359,6 +3894,11 @@ func (w *Wallet) reliablyPublishTransaction(tx *wire.MsgTx,
 		return nil, err
 	}
 
+	if delayable {
+		if txid, ok := w.publishTransactionDelayed(tx); ok {
+			return txid, nil
+		}
+	}
 	return w.publishTransaction(tx)
 }
 
@@ -3378,7 +3918,7 @@ func (w *Wallet) publishTransaction(tx *wire.MsgTx) (*chainhash.Hash, error) {
 		return strings.Contains(strings.ToLower(err.Error()), s)
 	}
 
-	_, err = chainClient.SendRawTransaction(tx, false)
+	err = w.sendRawTransaction(chainClient, tx)
 
 	// Determine if this was an RPC error thrown due to the transaction
 	// already confirming.
@@ -3539,12 +4079,21 @@ func (w *Wallet) publishTransaction(tx *wire.MsgTx) (*chainhash.Hash, error) {
 	// we'll remove it from the transaction store, as otherwise, we'll
 	// attempt to continually re-broadcast it, and the UTXO state of the
 	// wallet won't be accurate.
+	//
+	// Its external outputs no longer count towards the daily limit of the
+	// spend policy nor the window of the withdrawal policy either.
 	dbErr := walletdb.Update(w.db, func(dbTx walletdb.ReadWriteTx) error {
 		txmgrNs := dbTx.ReadWriteBucket(wtxmgrNamespaceKey)
 		txRec, err := wtxmgr.NewTxRecordFromMsgTx(tx, time.Now())
 		if err != nil {
 			return err
 		}
+		if err := revertSpendPolicy(dbTx, &txid); err != nil {
+			return err
+		}
+		if err := revertWithdrawalPolicy(dbTx, &txid); err != nil {
+			return err
+		}
 		return w.TxStore.RemoveUnminedTx(txmgrNs, txRec)
 	})
 	if dbErr != nil {
@@ -3624,6 +4173,14 @@ func create(db walletdb.DB, privPass []byte, rootKey *hdkeychain.ExtendedKey,
 		if err != nil {
 			return err
 		}
+		walletNs, err := tx.CreateTopLevelBucket(walletNamespaceKey)
+		if err != nil {
+			return err
+		}
+		err = putWalletNetwork(walletNs, params)
+		if err != nil {
+			return err
+		}
 
 		err = waddrmgr.Create(
 			addrmgrNs, rootKey, privPass, params, nil,
@@ -3646,10 +4203,22 @@ func create(db walletdb.DB, privPass []byte, rootKey *hdkeychain.ExtendedKey,
 	})
 }
 
-// Open loads an already-created wallet from the passed database and namespaces.
+// Open loads an already-created wallet from the passed database and namespaces
+// using the default public passphrase.
 func Open(db walletdb.DB, params *chaincfg.Params, recoveryWindow uint32) (
 	*Wallet, error) {
 
+	return OpenWithPublicPassphrase(
+		db, []byte(waddrmgr.DefaultPublicPassphrase), params,
+		recoveryWindow,
+	)
+}
+
+// OpenWithPublicPassphrase loads a wallet whose public passphrase has been
+// changed from the default.  See Open.
+func OpenWithPublicPassphrase(db walletdb.DB, pubPass []byte,
+	params *chaincfg.Params, recoveryWindow uint32) (*Wallet, error) {
+
 	var (
 		addrMgr *waddrmgr.Manager
 		txMgr   *wtxmgr.Store
@@ -3669,17 +4238,42 @@ func Open(db walletdb.DB, params *chaincfg.Params, recoveryWindow uint32) (
 			return errors.New("missing transaction manager namespace")
 		}
 
+		// The wallet namespace was introduced after the address and
+		// transaction managers, so create it for older databases.
+		walletNs, err := tx.CreateTopLevelBucket(walletNamespaceKey)
+		if err != nil {
+			return err
+		}
+
+		// Refuse to upgrade or open a wallet of another network, which
+		// would otherwise only fail later on when decoding addresses.
+		netRecorded, err := checkWalletNetwork(walletNs, params)
+		if err != nil {
+			return err
+		}
+
 		addrMgrUpgrader := waddrmgr.NewMigrationManager(addrMgrBucket)
 		txMgrUpgrader := wtxmgr.NewMigrationManager(txMgrBucket)
-		err := migration.Upgrade(txMgrUpgrader, addrMgrUpgrader)
+		err = migration.Upgrade(txMgrUpgrader, addrMgrUpgrader)
 		if err != nil {
 			return err
 		}
 
-		addrMgr, err = waddrmgr.Open(addrMgrBucket, params)
+		addrMgr, err = waddrmgr.OpenWithPublicPassphrase(
+			addrMgrBucket, pubPass, params,
+		)
 		if err != nil {
 			return err
 		}
+		if !netRecorded {
+			err = inferWalletNetwork(
+				walletNs, addrMgrBucket, addrMgr, params,
+			)
+			if err != nil {
+				addrMgr.Close()
+				return err
+			}
+		}
 		txMgr, err = wtxmgr.Open(txMgrBucket, params)
 		if err != nil {
 			return err
@@ -3710,11 +4304,16 @@ func Open(db walletdb.DB, params *chaincfg.Params, recoveryWindow uint32) (
 		holdUnlockRequests:  make(chan chan heldUnlock),
 		lockState:           make(chan bool),
 		changePassphrase:    make(chan changePassphraseRequest),
+		invoicesChanged:     make(chan struct{}, 1),
+		keyPoolRefill:       make(chan struct{}, 1),
+		backupPolicyChanged: make(chan struct{}, 1),
+		diskPolicyChanged:   make(chan struct{}, 1),
 		chainParams:         params,
 		quit:                make(chan struct{}),
 	}
 
 	w.NtfnServer = newNotificationServer(w)
+	w.TxStore.SetClock(walletClock{w})
 	w.TxStore.NotifyUnspent = func(hash *chainhash.Hash, index uint32) {
 		w.NtfnServer.notifyUnspentOutput(0, hash, index)
 	}
@@ -1,11 +1,13 @@
 package wallet
 
 import (
+	"context"
 	"encoding/hex"
 	"testing"
 	"time"
 
 	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/waddrmgr"
 	"github.com/lbryio/lbcwallet/walletdb"
 	"github.com/lbryio/lbcwallet/wtxmgr"
 )
@@ -202,3 +204,152 @@ func TestLabelTransaction(t *testing.T) {
 		})
 	}
 }
+
+// TestExpandScopeHorizons ensures the lookahead derived in parallel during
+// recovery matches the addresses derived by the address manager.
+func TestExpandScopeHorizons(t *testing.T) {
+	t.Parallel()
+
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	const recoveryWindow = 100
+	scope := waddrmgr.KeyScopeBIP0084
+	scopedMgr, err := w.Manager.FetchScopedKeyManager(scope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scopeState := NewRecoveryState(recoveryWindow).StateForScope(scope)
+	err = walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		if err := expandScopeHorizons(ns, scopedMgr, scopeState); err != nil {
+			return err
+		}
+
+		for branch, branchState := range scopeState[0] {
+			addrs := branchState.Addrs()
+			if len(addrs) != recoveryWindow {
+				t.Fatalf("branch %d: got %d addresses, want %d",
+					branch, len(addrs), recoveryWindow)
+			}
+			for index, addr := range addrs {
+				want, err := scopedMgr.DeriveFromKeyPath(
+					ns, keyPath(0, uint32(branch), index),
+				)
+				if err != nil {
+					return err
+				}
+				if addr.String() != want.Address().String() {
+					t.Errorf("branch %d index %d: got %v, want %v",
+						branch, index, addr, want.Address())
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestUnlockedUntil ensures the time an unlocked wallet is relocked is
+// reported until it is locked.
+func TestUnlockedUntil(t *testing.T) {
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	passphrase := []byte("hello world")
+	until := time.Now().Add(time.Hour).Truncate(time.Second)
+	if err := w.UnlockUntil(passphrase, until); err != nil {
+		t.Fatal(err)
+	}
+	if got := w.UnlockedUntil(); !got.Equal(until) {
+		t.Fatalf("unlocked until %v, want %v", got, until)
+	}
+
+	w.Lock()
+	if !w.Locked() {
+		t.Fatal("wallet not locked")
+	}
+	if got := w.UnlockedUntil(); !got.IsZero() {
+		t.Fatalf("locked wallet unlocked until %v", got)
+	}
+
+	if err := w.Unlock(passphrase, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := w.UnlockedUntil(); !got.IsZero() {
+		t.Fatalf("wallet unlocked without timeout until %v", got)
+	}
+}
+
+// TestTotalReceivedForAddrs checks that the totals received by a set of
+// addresses are those received by each address.
+func TestTotalReceivedForAddrs(t *testing.T) {
+	w := fixtureWallet(t, &FixtureParams{Receives: 5})
+
+	txs, err := w.ListAllTransactions(context.Background(), "*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := make(map[string]btcutil.Amount)
+	var addrs []btcutil.Address
+	for _, tx := range txs {
+		if _, ok := want[tx.Address]; !ok {
+			addr, err := btcutil.DecodeAddress(tx.Address,
+				w.ChainParams())
+			if err != nil {
+				t.Fatal(err)
+			}
+			addrs = append(addrs, addr)
+		}
+		amount, err := btcutil.NewAmount(tx.Amount)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want[tx.Address] += amount
+	}
+	if len(addrs) == 0 {
+		t.Fatal("no receiving addresses")
+	}
+
+	// An address of the set may be repeated or unknown to the wallet.
+	unknown, err := btcutil.NewAddressPubKeyHash(make([]byte, 20),
+		w.ChainParams())
+	if err != nil {
+		t.Fatal(err)
+	}
+	addrs = append(addrs, addrs[0], unknown)
+
+	amounts, err := w.TotalReceivedForAddrs(context.Background(), addrs, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, addr := range addrs {
+		if amounts[i] != want[addr.EncodeAddress()] {
+			t.Fatalf("%s received %v, want %v", addr, amounts[i],
+				want[addr.EncodeAddress()])
+		}
+		amount, err := w.TotalReceivedForAddr(addr, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if amount != amounts[i] {
+			t.Fatalf("%s received %v alone, %v in a set", addr,
+				amount, amounts[i])
+		}
+	}
+
+	// No receive has more confirmations than the history has blocks.
+	amounts, err = w.TotalReceivedForAddrs(context.Background(), addrs,
+		100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, amount := range amounts {
+		if amount != 0 {
+			t.Fatalf("%s received %v without enough confirmations",
+				addrs[i], amount)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package wallet
+
+import (
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcd/txscript"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/walletdb"
+	"github.com/lbryio/lbcwallet/wtxmgr"
+)
+
+// watchedAddr is an address watched by one or more clients.
+type watchedAddr struct {
+	addr     btcutil.Address
+	watchers int
+}
+
+// WatchedAddressNotification describes a transaction paying an address
+// watched with WatchAddresses.  It is sent when the transaction is accepted
+// to the mempool and again when it is mined.
+type WatchedAddressNotification struct {
+	Address     btcutil.Address
+	TxHash      chainhash.Hash
+	Transaction []byte
+
+	// Outputs are the indexes of the outputs paying Address, and Amount
+	// is their total value.
+	Outputs []uint32
+	Amount  btcutil.Amount
+
+	// BlockHash is nil and Height is -1 for unmined transactions.
+	BlockHash *chainhash.Hash
+	Height    int32
+}
+
+// WatchAddresses registers addresses, which do not need to belong to the
+// wallet, with the backend so that transactions paying them are sent to the
+// WatchedAddressNotifications clients.  These transactions are not recorded
+// by the wallet unless they are otherwise relevant to it.  Every call must be
+// balanced by a call to UnwatchAddresses once the addresses are no longer
+// needed.
+func (w *Wallet) WatchAddresses(addrs []btcutil.Address) error {
+	var added []btcutil.Address
+	w.watchedAddrsMtx.Lock()
+	if w.watchedAddrs == nil {
+		w.watchedAddrs = make(map[string]*watchedAddr)
+	}
+	for _, addr := range addrs {
+		key := addr.EncodeAddress()
+		watched, ok := w.watchedAddrs[key]
+		if !ok {
+			watched = &watchedAddr{addr: addr}
+			w.watchedAddrs[key] = watched
+			added = append(added, addr)
+		}
+		watched.watchers++
+	}
+	w.watchedAddrsMtx.Unlock()
+
+	// Addresses are registered with later backends when the wallet
+	// synchronizes with them.
+	chainClient := w.ChainClient()
+	if chainClient == nil || len(added) == 0 {
+		return nil
+	}
+	return chainClient.NotifyReceived(added)
+}
+
+// UnwatchAddresses removes the registrations of WatchAddresses.  The backend
+// may continue to notify transactions paying the addresses once they are no
+// longer watched, but these are ignored.
+func (w *Wallet) UnwatchAddresses(addrs []btcutil.Address) {
+	w.watchedAddrsMtx.Lock()
+	defer w.watchedAddrsMtx.Unlock()
+
+	for _, addr := range addrs {
+		key := addr.EncodeAddress()
+		watched, ok := w.watchedAddrs[key]
+		if !ok {
+			continue
+		}
+		watched.watchers--
+		if watched.watchers <= 0 {
+			delete(w.watchedAddrs, key)
+		}
+	}
+}
+
+// WatchedAddresses returns the addresses registered with WatchAddresses.
+func (w *Wallet) WatchedAddresses() []btcutil.Address {
+	w.watchedAddrsMtx.Lock()
+	defer w.watchedAddrsMtx.Unlock()
+
+	addrs := make([]btcutil.Address, 0, len(w.watchedAddrs))
+	for _, watched := range w.watchedAddrs {
+		addrs = append(addrs, watched.addr)
+	}
+	return addrs
+}
+
+// filteringWatchedAddrs returns whether addresses have been watched, in which
+// case the backend may notify transactions which are not relevant to the
+// wallet.
+func (w *Wallet) filteringWatchedAddrs() bool {
+	w.watchedAddrsMtx.Lock()
+	defer w.watchedAddrsMtx.Unlock()
+
+	return w.watchedAddrs != nil
+}
+
+// watchedAddrPayments returns a notification for every watched address paid
+// by a transaction.
+func (w *Wallet) watchedAddrPayments(rec *wtxmgr.TxRecord,
+	block *wtxmgr.BlockMeta) []*WatchedAddressNotification {
+
+	w.watchedAddrsMtx.Lock()
+	defer w.watchedAddrsMtx.Unlock()
+
+	var ntfns []*WatchedAddressNotification
+	byAddr := make(map[string]*WatchedAddressNotification)
+	for i, output := range rec.MsgTx.TxOut {
+		_, addrs, _, err := txscript.ExtractPkScriptAddrs(
+			output.PkScript, w.chainParams,
+		)
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			key := addr.EncodeAddress()
+			watched, ok := w.watchedAddrs[key]
+			if !ok {
+				continue
+			}
+			n, ok := byAddr[key]
+			if !ok {
+				n = &WatchedAddressNotification{
+					Address:     watched.addr,
+					TxHash:      rec.Hash,
+					Transaction: rec.SerializedTx,
+					Height:      -1,
+				}
+				if block != nil {
+					hash := block.Hash
+					n.BlockHash = &hash
+					n.Height = block.Height
+				}
+				byAddr[key] = n
+				ntfns = append(ntfns, n)
+			}
+			n.Outputs = append(n.Outputs, uint32(i))
+			n.Amount += btcutil.Amount(output.Value)
+		}
+	}
+	return ntfns
+}
+
+// relevantToWallet returns whether a transaction is already recorded by the
+// wallet, pays one of its addresses, or spends one of its outputs.
+func (w *Wallet) relevantToWallet(dbtx walletdb.ReadTx,
+	rec *wtxmgr.TxRecord) (bool, error) {
+
+	addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
+	txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+
+	details, err := w.TxStore.TxDetails(txmgrNs, &rec.Hash)
+	if err != nil {
+		return false, err
+	}
+	if details != nil {
+		return true, nil
+	}
+
+	for _, output := range rec.MsgTx.TxOut {
+		_, addrs, _, err := txscript.ExtractPkScriptAddrs(
+			output.PkScript, w.chainParams,
+		)
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			_, err := w.Manager.Address(addrmgrNs, addr)
+			if err == nil {
+				return true, nil
+			}
+			if !waddrmgr.IsError(err, waddrmgr.ErrAddressNotFound) {
+				return false, err
+			}
+		}
+	}
+
+	prevScripts, err := w.TxStore.PreviousPkScripts(txmgrNs, rec, nil)
+	if err != nil {
+		return false, err
+	}
+	return len(prevScripts) != 0, nil
+}
+
+// addChainTx records a transaction notified by the backend.  Payments to
+// watched addresses are sent to the watched address clients once dbtx is
+// committed, and, once addresses have been watched, transactions which are not
+// relevant to the wallet are not recorded.
+func (w *Wallet) addChainTx(dbtx walletdb.ReadWriteTx, rec *wtxmgr.TxRecord,
+	block *wtxmgr.BlockMeta) error {
+
+	if !w.filteringWatchedAddrs() {
+		return w.addRelevantTx(dbtx, rec, block)
+	}
+
+	payments := w.watchedAddrPayments(rec, block)
+	if len(payments) != 0 {
+		dbtx.OnCommit(func() {
+			for _, n := range payments {
+				w.NtfnServer.notifyWatchedAddress(n)
+			}
+		})
+	}
+
+	relevant, err := w.relevantToWallet(dbtx, rec)
+	if err != nil || !relevant {
+		return err
+	}
+	return w.addRelevantTx(dbtx, rec, block)
+}
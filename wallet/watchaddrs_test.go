@@ -0,0 +1,117 @@
+package wallet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lbryio/lbcd/chaincfg"
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/walletdb"
+	"github.com/lbryio/lbcwallet/wtxmgr"
+)
+
+// TestWatchAddresses ensures payments to watched addresses are notified
+// without being recorded by the wallet, while transactions relevant to the
+// wallet are still recorded.
+func TestWatchAddresses(t *testing.T) {
+	t.Parallel()
+
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	watched, err := btcutil.NewAddressPubKeyHash(
+		make([]byte, 20), &chaincfg.TestNet3Params,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	own, err := w.NewAddress(0, waddrmgr.KeyScopeBIP0044)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payTo := func(addr btcutil.Address, value int64) *wtxmgr.TxRecord {
+		pkScript, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgTx := wire.NewMsgTx(wire.TxVersion)
+		msgTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 1}, nil, nil))
+		msgTx.AddTxOut(wire.NewTxOut(value, pkScript))
+		rec, err := wtxmgr.NewTxRecordFromMsgTx(msgTx, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rec
+	}
+	addChainTx := func(rec *wtxmgr.TxRecord) {
+		err := walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+			return w.addChainTx(tx, rec, nil)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	recorded := func(rec *wtxmgr.TxRecord) bool {
+		var details *wtxmgr.TxDetails
+		err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+			ns := tx.ReadBucket(wtxmgrNamespaceKey)
+			var err error
+			details, err = w.TxStore.TxDetails(ns, &rec.Hash)
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return details != nil
+	}
+
+	if err := w.WatchAddresses([]btcutil.Address{watched}); err != nil {
+		t.Fatal(err)
+	}
+	client := w.NtfnServer.WatchedAddressNotifications()
+	defer client.Done()
+	ntfns := make(chan *WatchedAddressNotification, 1)
+	go func() {
+		for n := range client.C {
+			ntfns <- n
+		}
+	}()
+
+	rec := payTo(watched, 1000)
+	addChainTx(rec)
+	select {
+	case n := <-ntfns:
+		if n.TxHash != rec.Hash || n.Amount != 1000 ||
+			len(n.Outputs) != 1 || n.Height != -1 {
+
+			t.Fatalf("unexpected notification %+v", n)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("payment to watched address was not notified")
+	}
+	if recorded(rec) {
+		t.Fatal("payment to watched address was recorded")
+	}
+
+	rec = payTo(own, 2000)
+	addChainTx(rec)
+	if !recorded(rec) {
+		t.Fatal("payment to wallet address was not recorded")
+	}
+
+	// Payments are no longer notified once the address is unwatched.
+	w.UnwatchAddresses([]btcutil.Address{watched})
+	if len(w.WatchedAddresses()) != 0 {
+		t.Fatal("address is still watched")
+	}
+	addChainTx(payTo(watched, 3000))
+	select {
+	case n := <-ntfns:
+		t.Fatalf("unexpected notification %+v", n)
+	default:
+	}
+}
@@ -0,0 +1,817 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/wallet/txrules"
+	"github.com/lbryio/lbcwallet/walletdb"
+)
+
+var (
+	// bucketWithdrawals is the name of the sub bucket of the wallet
+	// namespace that maps withdrawal IDs to serialized withdrawals.
+	bucketWithdrawals = []byte("withdrawals")
+
+	// bucketWithdrawalWindow is the name of the sub bucket of the wallet
+	// namespace that maps the hash of each transaction published without
+	// approval to its publication time and the amount it paid to external
+	// addresses, for the window of the withdrawal policy.
+	bucketWithdrawalWindow = []byte("withdrawalwindow")
+)
+
+var (
+	// ErrWithdrawalNotFound is returned when a withdrawal does not exist.
+	ErrWithdrawalNotFound = errors.New("withdrawal not found")
+
+	// ErrWithdrawalClosed is returned when approving or canceling a
+	// withdrawal which was already sent, canceled or has expired.
+	ErrWithdrawalClosed = errors.New("withdrawal was already sent, " +
+		"canceled or has expired")
+
+	// ErrWithdrawalApproved is returned when a user approves a withdrawal
+	// twice, or approves the withdrawal they requested.
+	ErrWithdrawalApproved = errors.New("withdrawal already approved by " +
+		"this user")
+
+	// ErrApprovalRequired is returned when publishing a transaction which
+	// pays more than the threshold of the withdrawal policy without being
+	// the send of an approved withdrawal.
+	ErrApprovalRequired = errors.New("transaction requires the approval " +
+		"of a withdrawal")
+)
+
+// WithdrawalPolicy requires the sends of more than an amount to be approved by
+// a number of distinct users before the wallet signs them.
+type WithdrawalPolicy struct {
+	// Threshold is the total paid by a send above which it requires
+	// approval, or zero to never require it.
+	Threshold btcutil.Amount
+
+	// Approvals is the number of distinct users who must approve a
+	// withdrawal before it is sent.
+	Approvals int
+
+	// Expiry is how long a withdrawal waits for its approvals before it
+	// expires.
+	Expiry time.Duration
+
+	// Window is the period over which the amounts paid by the sends made
+	// without approval are added up, a send requiring approval when it
+	// brings their total above the threshold.  Zero compares each send
+	// with the threshold alone.
+	Window time.Duration
+}
+
+// Enabled returns whether the policy requires the approval of any send.
+func (p WithdrawalPolicy) Enabled() bool {
+	return p.Threshold > 0
+}
+
+// SetWithdrawalPolicy sets the policy for the approval of the sends requested
+// from now on.
+func (w *Wallet) SetWithdrawalPolicy(policy WithdrawalPolicy) {
+	w.withdrawalPolicyMtx.Lock()
+	w.withdrawalPolicy = policy
+	w.withdrawalPolicyMtx.Unlock()
+}
+
+// WithdrawalPolicy returns the policy for the approval of sends set by
+// SetWithdrawalPolicy.
+func (w *Wallet) WithdrawalPolicy() WithdrawalPolicy {
+	w.withdrawalPolicyMtx.Lock()
+	defer w.withdrawalPolicyMtx.Unlock()
+	return w.withdrawalPolicy
+}
+
+// RequiresApproval returns whether a send paying outputs must be requested
+// with RequestWithdrawal rather than sent at once, as it pays more than the
+// threshold of the policy along with the other sends of its window.
+func (w *Wallet) RequiresApproval(outputs []*wire.TxOut) (bool, error) {
+	policy := w.WithdrawalPolicy()
+	if !policy.Enabled() {
+		return false, nil
+	}
+	var total btcutil.Amount
+	for _, output := range outputs {
+		total += btcutil.Amount(output.Value)
+	}
+	if total > policy.Threshold {
+		return true, nil
+	}
+
+	var spent btcutil.Amount
+	err := walletdb.View(w.db, func(tx walletdb.ReadTx) error {
+		ns := tx.ReadBucket(walletNamespaceKey)
+		spent = fetchWindowSpent(ns, w.now(), policy.Window)
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return spent+total > policy.Threshold, nil
+}
+
+// approvedWithdrawalKey is the key of the contexts of the sends of approved
+// withdrawals.
+type approvedWithdrawalKey struct{}
+
+// isApprovedWithdrawal returns whether ctx is the one of the send of an
+// approved withdrawal.
+func isApprovedWithdrawal(ctx context.Context) bool {
+	approved, _ := ctx.Value(approvedWithdrawalKey{}).(bool)
+	return approved
+}
+
+// fetchWindowSpent returns the total paid to external addresses by the
+// transactions published without approval within the window before now.
+func fetchWindowSpent(ns walletdb.ReadBucket, now time.Time,
+	window time.Duration) btcutil.Amount {
+
+	bucket := ns.NestedReadBucket(bucketWithdrawalWindow)
+	if bucket == nil || window <= 0 {
+		return 0
+	}
+	start := now.Add(-window).Unix()
+
+	var spent btcutil.Amount
+	_ = bucket.ForEach(func(_, v []byte) error {
+		if len(v) != 16 {
+			return nil
+		}
+		if int64(binary.BigEndian.Uint64(v[:8])) > start {
+			spent += btcutil.Amount(binary.BigEndian.Uint64(v[8:]))
+		}
+		return nil
+	})
+	return spent
+}
+
+// putWindowSpent records the amount paid to external addresses by a
+// transaction published without approval at the given time.  The transactions
+// published before the window are removed since they no longer count.
+func putWindowSpent(ns walletdb.ReadWriteBucket, txHash *chainhash.Hash,
+	now time.Time, window time.Duration, amt btcutil.Amount) error {
+
+	bucket, err := ns.CreateBucketIfNotExists(bucketWithdrawalWindow)
+	if err != nil {
+		return err
+	}
+
+	start := now.Add(-window).Unix()
+	var stale [][]byte
+	err = bucket.ForEach(func(k, v []byte) error {
+		if len(v) != 16 ||
+			int64(binary.BigEndian.Uint64(v[:8])) <= start {
+
+			stale = append(stale, k)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, k := range stale {
+		if err := bucket.Delete(k); err != nil {
+			return err
+		}
+	}
+
+	var v [16]byte
+	binary.BigEndian.PutUint64(v[:8], uint64(now.Unix()))
+	binary.BigEndian.PutUint64(v[8:], uint64(amt))
+	return bucket.Put(txHash[:], v[:])
+}
+
+// applyWithdrawalPolicy returns ErrApprovalRequired when tx pays more than the
+// threshold of the withdrawal policy to external addresses, along with the
+// other transactions published without approval within its window, unless it
+// is the send of an approved withdrawal.  Otherwise the transaction is added
+// to the window.  It must be called within the database transaction that
+// records tx as published, and revertWithdrawalPolicy called within the one
+// removing it if it fails to be published.
+func (w *Wallet) applyWithdrawalPolicy(dbtx walletdb.ReadWriteTx,
+	tx *wire.MsgTx, approved bool) error {
+
+	policy := w.WithdrawalPolicy()
+	if !policy.Enabled() || approved {
+		return nil
+	}
+
+	destinations, err := w.externalOutputs(
+		dbtx.ReadBucket(waddrmgrNamespaceKey), tx,
+	)
+	if err != nil {
+		return err
+	}
+	var total btcutil.Amount
+	for _, amt := range destinations {
+		total += amt
+	}
+	if total == 0 {
+		return nil
+	}
+
+	ns := dbtx.ReadWriteBucket(walletNamespaceKey)
+	now := w.now()
+	if fetchWindowSpent(ns, now, policy.Window)+total > policy.Threshold {
+		return ErrApprovalRequired
+	}
+	if policy.Window <= 0 {
+		return nil
+	}
+	txHash := tx.TxHash()
+	return putWindowSpent(ns, &txHash, now, policy.Window, total)
+}
+
+// revertWithdrawalPolicy takes tx, which failed to be published, out of the
+// window of the withdrawal policy.
+func revertWithdrawalPolicy(dbtx walletdb.ReadWriteTx,
+	txHash *chainhash.Hash) error {
+
+	ns := dbtx.ReadWriteBucket(walletNamespaceKey)
+	bucket := ns.NestedReadWriteBucket(bucketWithdrawalWindow)
+	if bucket == nil {
+		return nil
+	}
+	return bucket.Delete(txHash[:])
+}
+
+// WithdrawalState describes how far a withdrawal went.
+type WithdrawalState uint8
+
+// Withdrawal states.  A pending withdrawal is approved once it has its
+// approvals, and is sent unless canceled.  A pending withdrawal expires when it
+// is not approved in time.
+const (
+	WithdrawalPending WithdrawalState = iota
+	WithdrawalApproved
+	WithdrawalSent
+	WithdrawalCanceled
+	WithdrawalExpired
+)
+
+// String returns the name of the state.
+func (s WithdrawalState) String() string {
+	switch s {
+	case WithdrawalPending:
+		return "pending"
+	case WithdrawalApproved:
+		return "approved"
+	case WithdrawalSent:
+		return "sent"
+	case WithdrawalCanceled:
+		return "canceled"
+	case WithdrawalExpired:
+		return "expired"
+	default:
+		return fmt.Sprintf("unknown (%d)", uint8(s))
+	}
+}
+
+// WithdrawalAction is what happened to a withdrawal in an event of its
+// history.
+type WithdrawalAction uint8
+
+// Withdrawal actions.
+const (
+	WithdrawalActionRequest WithdrawalAction = iota
+	WithdrawalActionApprove
+	WithdrawalActionSend
+	WithdrawalActionFail
+	WithdrawalActionCancel
+	WithdrawalActionExpire
+)
+
+// String returns the name of the action.
+func (a WithdrawalAction) String() string {
+	switch a {
+	case WithdrawalActionRequest:
+		return "request"
+	case WithdrawalActionApprove:
+		return "approve"
+	case WithdrawalActionSend:
+		return "send"
+	case WithdrawalActionFail:
+		return "fail"
+	case WithdrawalActionCancel:
+		return "cancel"
+	case WithdrawalActionExpire:
+		return "expire"
+	default:
+		return fmt.Sprintf("unknown (%d)", uint8(a))
+	}
+}
+
+// WithdrawalEvent is an event of the history of a withdrawal.
+type WithdrawalEvent struct {
+	Time   time.Time
+	Action WithdrawalAction
+
+	// User is the user who requested, approved or canceled the
+	// withdrawal, and is empty for the other actions.
+	User string
+
+	// Detail is the hash of the sent transaction, or the error of a
+	// failed send.
+	Detail string
+}
+
+// Withdrawal is a send above the threshold of the withdrawal policy, which
+// waits for the approvals of distinct users before the wallet signs and
+// publishes its transaction.  Withdrawals are kept once closed, with their
+// history, as the audit trail of the sends of the wallet.
+type Withdrawal struct {
+	ID uint64
+
+	// The parameters of the send, whose transaction is created once the
+	// withdrawal is approved.
+	Outputs  []*wire.TxOut
+	KeyScope *waddrmgr.KeyScope
+	Account  uint32
+	MinConf  int32
+	FeeRate  btcutil.Amount
+	Comment  TxComment
+
+	Requester string
+
+	// Required is the number of approvals the withdrawal needs.
+	Required int
+
+	Created time.Time
+	Expires time.Time
+	State   WithdrawalState
+
+	// TxHash is the hash of the transaction, and is zero unless the state
+	// is WithdrawalSent.
+	TxHash chainhash.Hash
+
+	History []WithdrawalEvent
+}
+
+// Amount returns the total paid by the withdrawal.
+func (wd *Withdrawal) Amount() btcutil.Amount {
+	var total btcutil.Amount
+	for _, output := range wd.Outputs {
+		total += btcutil.Amount(output.Value)
+	}
+	return total
+}
+
+// Approvers returns the users who approved the withdrawal, in the order they
+// approved it.
+func (wd *Withdrawal) Approvers() []string {
+	var users []string
+	for _, e := range wd.History {
+		if e.Action == WithdrawalActionApprove {
+			users = append(users, e.User)
+		}
+	}
+	return users
+}
+
+// addEvent appends an event to the history of the withdrawal.
+func (wd *Withdrawal) addEvent(now time.Time, action WithdrawalAction,
+	user, detail string) {
+
+	wd.History = append(wd.History, WithdrawalEvent{
+		Time:   time.Unix(now.Unix(), 0),
+		Action: action,
+		User:   user,
+		Detail: detail,
+	})
+}
+
+// expire marks a pending withdrawal as expired if it expired by now, and
+// returns whether it did.
+func (wd *Withdrawal) expire(now time.Time) bool {
+	if wd.State != WithdrawalPending || now.Before(wd.Expires) {
+		return false
+	}
+	wd.State = WithdrawalExpired
+	wd.addEvent(wd.Expires, WithdrawalActionExpire, "", "")
+	log.Infof("Withdrawal %d has expired", wd.ID)
+	return true
+}
+
+// withdrawalKey returns the key of a withdrawal in the withdrawals bucket.
+func withdrawalKey(id uint64) []byte {
+	var k [8]byte
+	binary.BigEndian.PutUint64(k[:], id)
+	return k[:]
+}
+
+// serializeWithdrawal returns the serialization of a withdrawal, without its
+// ID which is its key:
+//
+//	[0:8]    creation time (8 bytes)
+//	[8:16]   expiry time (8 bytes)
+//	[16:24]  fee rate (8 bytes)
+//	[24:28]  account (4 bytes)
+//	[28:32]  minconf (4 bytes)
+//	[32:36]  required approvals (4 bytes)
+//	[36]     state (1 byte)
+//	[37]     whether a key scope follows (1 byte)
+//	[38:46]  key scope purpose and coin (8 bytes)
+//	[46:78]  tx hash (32 bytes)
+//	[78:]    comment, recipient comment and requester as varstrings, a
+//	         varint count of outputs, each a value (8 bytes) and a
+//	         varbytes script, then a varint count of events, each a time
+//	         (8 bytes), an action (1 byte) and the user and detail as
+//	         varstrings
+func serializeWithdrawal(wd *Withdrawal) ([]byte, error) {
+	var buf bytes.Buffer
+	var fixed [78]byte
+	binary.BigEndian.PutUint64(fixed[0:8], uint64(wd.Created.Unix()))
+	binary.BigEndian.PutUint64(fixed[8:16], uint64(wd.Expires.Unix()))
+	binary.BigEndian.PutUint64(fixed[16:24], uint64(wd.FeeRate))
+	binary.BigEndian.PutUint32(fixed[24:28], wd.Account)
+	binary.BigEndian.PutUint32(fixed[28:32], uint32(wd.MinConf))
+	binary.BigEndian.PutUint32(fixed[32:36], uint32(wd.Required))
+	fixed[36] = byte(wd.State)
+	if wd.KeyScope != nil {
+		fixed[37] = 1
+		binary.BigEndian.PutUint32(fixed[38:42], wd.KeyScope.Purpose)
+		binary.BigEndian.PutUint32(fixed[42:46], wd.KeyScope.Coin)
+	}
+	copy(fixed[46:78], wd.TxHash[:])
+	buf.Write(fixed[:])
+
+	strs := []string{wd.Comment.Comment, wd.Comment.CommentTo, wd.Requester}
+	for _, s := range strs {
+		if err := wire.WriteVarString(&buf, 0, s); err != nil {
+			return nil, err
+		}
+	}
+	err := wire.WriteVarInt(&buf, 0, uint64(len(wd.Outputs)))
+	if err != nil {
+		return nil, err
+	}
+	for _, output := range wd.Outputs {
+		var value [8]byte
+		binary.BigEndian.PutUint64(value[:], uint64(output.Value))
+		buf.Write(value[:])
+		err := wire.WriteVarBytes(&buf, 0, output.PkScript)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = wire.WriteVarInt(&buf, 0, uint64(len(wd.History)))
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range wd.History {
+		var fixed [9]byte
+		binary.BigEndian.PutUint64(fixed[0:8], uint64(e.Time.Unix()))
+		fixed[8] = byte(e.Action)
+		buf.Write(fixed[:])
+		if err := wire.WriteVarString(&buf, 0, e.User); err != nil {
+			return nil, err
+		}
+		if err := wire.WriteVarString(&buf, 0, e.Detail); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// deserializeWithdrawal decodes a withdrawal serialized by
+// serializeWithdrawal.
+func deserializeWithdrawal(k, v []byte) (*Withdrawal, error) {
+	if len(k) != 8 || len(v) < 78 {
+		return nil, fmt.Errorf("short withdrawal: %d bytes", len(v))
+	}
+
+	wd := &Withdrawal{
+		ID:       binary.BigEndian.Uint64(k),
+		Created:  time.Unix(int64(binary.BigEndian.Uint64(v[0:8])), 0),
+		Expires:  time.Unix(int64(binary.BigEndian.Uint64(v[8:16])), 0),
+		FeeRate:  btcutil.Amount(binary.BigEndian.Uint64(v[16:24])),
+		Account:  binary.BigEndian.Uint32(v[24:28]),
+		MinConf:  int32(binary.BigEndian.Uint32(v[28:32])),
+		Required: int(binary.BigEndian.Uint32(v[32:36])),
+		State:    WithdrawalState(v[36]),
+	}
+	if v[37] != 0 {
+		wd.KeyScope = &waddrmgr.KeyScope{
+			Purpose: binary.BigEndian.Uint32(v[38:42]),
+			Coin:    binary.BigEndian.Uint32(v[42:46]),
+		}
+	}
+	copy(wd.TxHash[:], v[46:78])
+
+	r := bytes.NewReader(v[78:])
+	strs := []*string{
+		&wd.Comment.Comment, &wd.Comment.CommentTo, &wd.Requester,
+	}
+	for _, s := range strs {
+		var err error
+		if *s, err = wire.ReadVarString(r, 0); err != nil {
+			return nil, err
+		}
+	}
+	n, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(r.Len())/9 {
+		return nil, io.ErrUnexpectedEOF
+	}
+	wd.Outputs = make([]*wire.TxOut, n)
+	for i := range wd.Outputs {
+		var value [8]byte
+		if _, err := io.ReadFull(r, value[:]); err != nil {
+			return nil, err
+		}
+		pkScript, err := wire.ReadVarBytes(
+			r, 0, wire.MaxMessagePayload, "pkScript",
+		)
+		if err != nil {
+			return nil, err
+		}
+		wd.Outputs[i] = wire.NewTxOut(
+			int64(binary.BigEndian.Uint64(value[:])), pkScript,
+		)
+	}
+	n, err = wire.ReadVarInt(r, 0)
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(r.Len())/11 {
+		return nil, io.ErrUnexpectedEOF
+	}
+	wd.History = make([]WithdrawalEvent, n)
+	for i := range wd.History {
+		var fixed [9]byte
+		if _, err := io.ReadFull(r, fixed[:]); err != nil {
+			return nil, err
+		}
+		e := &wd.History[i]
+		t := int64(binary.BigEndian.Uint64(fixed[0:8]))
+		e.Time = time.Unix(t, 0)
+		e.Action = WithdrawalAction(fixed[8])
+		if e.User, err = wire.ReadVarString(r, 0); err != nil {
+			return nil, err
+		}
+		if e.Detail, err = wire.ReadVarString(r, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	return wd, nil
+}
+
+// putWithdrawal stores a withdrawal.
+func putWithdrawal(ns walletdb.ReadWriteBucket, wd *Withdrawal) error {
+	v, err := serializeWithdrawal(wd)
+	if err != nil {
+		return err
+	}
+	withdrawals, err := ns.CreateBucketIfNotExists(bucketWithdrawals)
+	if err != nil {
+		return err
+	}
+	return withdrawals.Put(withdrawalKey(wd.ID), v)
+}
+
+// fetchWithdrawal returns the withdrawal with the given ID.
+func fetchWithdrawal(ns walletdb.ReadBucket, id uint64) (*Withdrawal,
+	error) {
+
+	withdrawals := ns.NestedReadBucket(bucketWithdrawals)
+	if withdrawals == nil {
+		return nil, ErrWithdrawalNotFound
+	}
+	k := withdrawalKey(id)
+	v := withdrawals.Get(k)
+	if v == nil {
+		return nil, ErrWithdrawalNotFound
+	}
+	return deserializeWithdrawal(k, v)
+}
+
+// updateWithdrawal calls f with the withdrawal with the given ID, once expired
+// if it expired by now, and stores it unless f errors.
+func (w *Wallet) updateWithdrawal(id uint64,
+	f func(wd *Withdrawal) error) (*Withdrawal, error) {
+
+	var wd *Withdrawal
+	err := walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(walletNamespaceKey)
+
+		var err error
+		wd, err = fetchWithdrawal(ns, id)
+		if err != nil {
+			return err
+		}
+		wd.expire(w.now())
+		if err := f(wd); err != nil {
+			// An expiry is still recorded.
+			if putErr := putWithdrawal(ns, wd); putErr != nil {
+				return putErr
+			}
+			return err
+		}
+		return putWithdrawal(ns, wd)
+	})
+	return wd, err
+}
+
+// RequestWithdrawal queues a send of outputs requiring approval under the
+// withdrawal policy, on behalf of requester.  The parameters are those of
+// SendOutputsWithComment, with which the withdrawal is sent once approved by
+// ApproveWithdrawal.
+func (w *Wallet) RequestWithdrawal(outputs []*wire.TxOut,
+	keyScope *waddrmgr.KeyScope, account uint32, minconf int32,
+	satPerKb btcutil.Amount, comment *TxComment,
+	requester string) (*Withdrawal, error) {
+
+	for _, output := range outputs {
+		err := txrules.CheckOutput(
+			output, txrules.DefaultRelayFeePerKb,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+	policy := w.WithdrawalPolicy()
+	if policy.Approvals < 1 {
+		return nil, errors.New("withdrawals require at least one " +
+			"approval")
+	}
+
+	now := time.Unix(w.now().Unix(), 0)
+	wd := &Withdrawal{
+		Outputs:   outputs,
+		KeyScope:  keyScope,
+		Account:   account,
+		MinConf:   minconf,
+		FeeRate:   satPerKb,
+		Requester: requester,
+		Required:  policy.Approvals,
+		Created:   now,
+		Expires:   now.Add(policy.Expiry.Truncate(time.Second)),
+		State:     WithdrawalPending,
+	}
+	if comment != nil {
+		wd.Comment = *comment
+	}
+	wd.addEvent(now, WithdrawalActionRequest, requester, "")
+	err := walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(walletNamespaceKey)
+		withdrawals, err := ns.CreateBucketIfNotExists(
+			bucketWithdrawals,
+		)
+		if err != nil {
+			return err
+		}
+		if wd.ID, err = withdrawals.NextSequence(); err != nil {
+			return err
+		}
+		return putWithdrawal(ns, wd)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Withdrawal %d of %v requested by %s", wd.ID, wd.Amount(),
+		requester)
+	return wd, nil
+}
+
+// ApproveWithdrawal records the approval of a pending withdrawal by approver,
+// who must not be its requester nor have approved it already.  The withdrawal
+// is sent once it has its approvals.  When the send fails, as while the wallet
+// is locked, the withdrawal stays approved and its send is retried by the next
+// call, which adds no approval.
+func (w *Wallet) ApproveWithdrawal(ctx context.Context, id uint64,
+	approver string) (*Withdrawal, error) {
+
+	w.withdrawalsMtx.Lock()
+	defer w.withdrawalsMtx.Unlock()
+
+	wd, err := w.updateWithdrawal(id, func(wd *Withdrawal) error {
+		switch wd.State {
+		case WithdrawalApproved:
+			return nil
+		case WithdrawalPending:
+		default:
+			return ErrWithdrawalClosed
+		}
+		if approver == wd.Requester {
+			return ErrWithdrawalApproved
+		}
+		for _, user := range wd.Approvers() {
+			if user == approver {
+				return ErrWithdrawalApproved
+			}
+		}
+
+		wd.addEvent(w.now(), WithdrawalActionApprove, approver, "")
+		log.Infof("Withdrawal %d approved by %s", wd.ID, approver)
+		if len(wd.Approvers()) >= wd.Required {
+			wd.State = WithdrawalApproved
+		}
+		return nil
+	})
+	if err != nil || wd.State != WithdrawalApproved {
+		return wd, err
+	}
+
+	ctx = context.WithValue(ctx, approvedWithdrawalKey{}, true)
+	tx, sendErr := w.SendOutputsWithComment(
+		ctx, wd.Outputs, wd.KeyScope, wd.Account, wd.MinConf,
+		wd.FeeRate, CoinSelectionLargest, "", &wd.Comment,
+	)
+	wd, err = w.updateWithdrawal(id, func(wd *Withdrawal) error {
+		if sendErr != nil {
+			wd.addEvent(w.now(), WithdrawalActionFail, "",
+				sendErr.Error())
+			return nil
+		}
+		wd.State = WithdrawalSent
+		wd.TxHash = tx.TxHash()
+		wd.addEvent(w.now(), WithdrawalActionSend, "",
+			wd.TxHash.String())
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if sendErr != nil {
+		log.Errorf("Unable to send withdrawal %d: %v", id, sendErr)
+		return wd, sendErr
+	}
+	log.Infof("Withdrawal %d sent in transaction %v", id, wd.TxHash)
+	return wd, nil
+}
+
+// CancelWithdrawal cancels a withdrawal which was not sent yet on behalf of
+// user.
+func (w *Wallet) CancelWithdrawal(id uint64, user string) (*Withdrawal,
+	error) {
+
+	w.withdrawalsMtx.Lock()
+	defer w.withdrawalsMtx.Unlock()
+
+	return w.updateWithdrawal(id, func(wd *Withdrawal) error {
+		if wd.State != WithdrawalPending &&
+			wd.State != WithdrawalApproved {
+
+			return ErrWithdrawalClosed
+		}
+		wd.State = WithdrawalCanceled
+		wd.addEvent(w.now(), WithdrawalActionCancel, user, "")
+		log.Infof("Withdrawal %d canceled by %s", wd.ID, user)
+		return nil
+	})
+}
+
+// Withdrawals returns all withdrawals in the order they were requested, once
+// the pending withdrawals which expired by now are marked as expired.
+func (w *Wallet) Withdrawals() ([]*Withdrawal, error) {
+	w.withdrawalsMtx.Lock()
+	defer w.withdrawalsMtx.Unlock()
+
+	var wds []*Withdrawal
+	err := walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(walletNamespaceKey)
+		withdrawals := ns.NestedReadWriteBucket(bucketWithdrawals)
+		if withdrawals == nil {
+			return nil
+		}
+
+		var expired []*Withdrawal
+		err := withdrawals.ForEach(func(k, v []byte) error {
+			wd, err := deserializeWithdrawal(k, v)
+			if err != nil {
+				return err
+			}
+			if wd.expire(w.now()) {
+				expired = append(expired, wd)
+			}
+			wds = append(wds, wd)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, wd := range expired {
+			if err := putWithdrawal(ns, wd); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return wds, err
+}
@@ -0,0 +1,172 @@
+package wallet
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWithdrawals checks that a withdrawal is only sent once approved by
+// distinct users, and that unapproved withdrawals expire.
+func TestWithdrawals(t *testing.T) {
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	addr, err := w.CurrentAddress(0, waddrmgr.KeyScopeBIP0084)
+	require.NoError(t, err)
+	pkScript, err := txscript.PayToAddrScript(addr)
+	require.NoError(t, err)
+	addUtxo(t, w, &wire.MsgTx{
+		TxIn:  []*wire.TxIn{{}},
+		TxOut: []*wire.TxOut{wire.NewTxOut(1000000, pkScript)},
+	})
+
+	w.SetWithdrawalPolicy(WithdrawalPolicy{
+		Threshold: 100000,
+		Approvals: 2,
+		Expiry:    time.Hour,
+	})
+	small := []*wire.TxOut{wire.NewTxOut(100000, pkScript)}
+	large := []*wire.TxOut{wire.NewTxOut(200000, pkScript)}
+	requiresApproval := func(outputs []*wire.TxOut) bool {
+		requires, err := w.RequiresApproval(outputs)
+		require.NoError(t, err)
+		return requires
+	}
+	require.False(t, requiresApproval(small))
+	require.True(t, requiresApproval(large))
+
+	ctx := context.Background()
+	wd, err := w.RequestWithdrawal(large, nil, 0, 1, 1000,
+		&TxComment{Comment: "withdrawal"}, "operator")
+	require.NoError(t, err)
+	require.Equal(t, WithdrawalPending, wd.State)
+	require.Equal(t, 2, wd.Required)
+
+	_, err = w.ApproveWithdrawal(ctx, wd.ID, "operator")
+	require.ErrorIs(t, err, ErrWithdrawalApproved)
+	wd, err = w.ApproveWithdrawal(ctx, wd.ID, "alice")
+	require.NoError(t, err)
+	require.Equal(t, WithdrawalPending, wd.State)
+	_, err = w.ApproveWithdrawal(ctx, wd.ID, "alice")
+	require.ErrorIs(t, err, ErrWithdrawalApproved)
+
+	wd, err = w.ApproveWithdrawal(ctx, wd.ID, "bob")
+	require.NoError(t, err)
+	require.Equal(t, WithdrawalSent, wd.State)
+	require.Equal(t, []string{"alice", "bob"}, wd.Approvers())
+	details, err := UnstableAPI(w).TxDetails(&wd.TxHash)
+	require.NoError(t, err)
+	require.NotNil(t, details)
+	comment, err := w.TxComment(&wd.TxHash)
+	require.NoError(t, err)
+	require.Equal(t, "withdrawal", comment.Comment)
+	_, err = w.CancelWithdrawal(wd.ID, "operator")
+	require.ErrorIs(t, err, ErrWithdrawalClosed)
+
+	// A withdrawal without its approvals expires.
+	expiring, err := w.RequestWithdrawal(large, nil, 0, 1, 1000, nil,
+		"operator")
+	require.NoError(t, err)
+	_, err = w.ApproveWithdrawal(ctx, expiring.ID, "alice")
+	require.NoError(t, err)
+	w.clockOffsetMtx.Lock()
+	w.clockOffset = 2 * time.Hour
+	w.clockOffsetMtx.Unlock()
+
+	wds, err := w.Withdrawals()
+	require.NoError(t, err)
+	require.Len(t, wds, 2)
+	require.Equal(t, WithdrawalExpired, wds[1].State)
+	_, err = w.ApproveWithdrawal(ctx, expiring.ID, "bob")
+	require.ErrorIs(t, err, ErrWithdrawalClosed)
+
+	var actions []WithdrawalAction
+	for _, e := range wds[0].History {
+		actions = append(actions, e.Action)
+	}
+	require.Equal(t, []WithdrawalAction{
+		WithdrawalActionRequest, WithdrawalActionApprove,
+		WithdrawalActionApprove, WithdrawalActionSend,
+	}, actions)
+	require.Equal(t, wd.TxHash.String(), wds[0].History[3].Detail)
+	require.Equal(t, WithdrawalActionExpire,
+		wds[1].History[len(wds[1].History)-1].Action)
+}
+
+// TestWithdrawalWindow checks that the sends made without approval are added
+// up over the window of the withdrawal policy, and that the transactions paying
+// more than its threshold are only published as approved withdrawals.
+func TestWithdrawalWindow(t *testing.T) {
+	w, cleanup := testWallet(t)
+	defer cleanup()
+
+	addr, err := w.CurrentAddress(0, waddrmgr.KeyScopeBIP0084)
+	require.NoError(t, err)
+	pkScript, err := txscript.PayToAddrScript(addr)
+	require.NoError(t, err)
+	// Each send spends an output of its own, as the change of the previous
+	// ones is unconfirmed.
+	for i := int64(0); i < 5; i++ {
+		output := wire.NewTxOut(1000000+i, pkScript)
+		addUtxo(t, w, &wire.MsgTx{
+			TxIn:  []*wire.TxIn{{}},
+			TxOut: []*wire.TxOut{output},
+		})
+	}
+
+	external, err := btcutil.NewAddressWitnessPubKeyHash(
+		make([]byte, 20), w.ChainParams(),
+	)
+	require.NoError(t, err)
+	externalScript, err := txscript.PayToAddrScript(external)
+	require.NoError(t, err)
+	payment := []*wire.TxOut{wire.NewTxOut(100000, externalScript)}
+
+	w.SetWithdrawalPolicy(WithdrawalPolicy{
+		Threshold: 150000,
+		Approvals: 2,
+		Expiry:    time.Hour,
+		Window:    time.Hour,
+	})
+	ctx := context.Background()
+	send := func() error {
+		_, err := w.SendOutputs(ctx, payment, nil, 0, 1, 1000,
+			CoinSelectionLargest, "")
+		return err
+	}
+
+	// The second payment brings the total of the window above the
+	// threshold, whether or not it is checked before being sent.
+	requires, err := w.RequiresApproval(payment)
+	require.NoError(t, err)
+	require.False(t, requires)
+	require.NoError(t, send())
+	requires, err = w.RequiresApproval(payment)
+	require.NoError(t, err)
+	require.True(t, requires)
+	require.ErrorIs(t, send(), ErrApprovalRequired)
+
+	// The payment is sent once approved, and isn't added to the window.
+	wd, err := w.RequestWithdrawal(payment, nil, 0, 1, 1000, nil,
+		"operator")
+	require.NoError(t, err)
+	_, err = w.ApproveWithdrawal(ctx, wd.ID, "alice")
+	require.NoError(t, err)
+	wd, err = w.ApproveWithdrawal(ctx, wd.ID, "bob")
+	require.NoError(t, err)
+	require.Equal(t, WithdrawalSent, wd.State)
+
+	// Once the first payment left the window, a payment is sent again.
+	w.clockOffsetMtx.Lock()
+	w.clockOffset = 2 * time.Hour
+	w.clockOffsetMtx.Unlock()
+	require.NoError(t, send())
+	require.ErrorIs(t, send(), ErrApprovalRequired)
+}
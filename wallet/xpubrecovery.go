@@ -0,0 +1,162 @@
+package wallet
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/lbryio/lbcutil/hdkeychain"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/walletdb"
+)
+
+// DefaultRecoveryLookahead is the number of addresses of each branch of a
+// recovered account that are derived ahead of the rescan.
+const DefaultRecoveryLookahead = 1000
+
+// RecoveryAccount is an account extended public key to rebuild a watch-only
+// account from, with the key scope of the addresses it derives.
+type RecoveryAccount struct {
+	PubKey *hdkeychain.ExtendedKey
+	Scope  waddrmgr.KeyScope
+}
+
+// ParseRecoveryAccounts reads a list of account extended public keys, one per
+// line, each optionally followed by the address type it derives: "legacy",
+// "p2sh-segwit" or "bech32".  The address type of keys with a BIP0049 or
+// BIP0084 version follows from the version, and keys with the standard version
+// derive legacy addresses unless told otherwise.  Blank lines and lines
+// starting with # are skipped.
+func ParseRecoveryAccounts(r io.Reader) ([]RecoveryAccount, error) {
+	var accts []RecoveryAccount
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if len(fields) > 2 {
+			return nil, fmt.Errorf("line %d: expected an extended "+
+				"public key and an optional address type", line)
+		}
+
+		pubKey, err := hdkeychain.NewKeyFromString(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		var addrType string
+		if len(fields) == 2 {
+			addrType = strings.ToLower(fields[1])
+		}
+		scope, err := recoveryKeyScope(pubKey, addrType)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		accts = append(accts, RecoveryAccount{
+			PubKey: pubKey,
+			Scope:  scope,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return accts, nil
+}
+
+// recoveryKeyScope returns the key scope of the addresses of an account
+// extended public key given the name of their address type, if any.
+func recoveryKeyScope(pubKey *hdkeychain.ExtendedKey,
+	addrType string) (waddrmgr.KeyScope, error) {
+
+	var scope waddrmgr.KeyScope
+	switch waddrmgr.HDVersion(binary.BigEndian.Uint32(pubKey.Version())) {
+	case waddrmgr.HDVersionMainNetBIP0049, waddrmgr.HDVersionTestNetBIP0049:
+		scope = waddrmgr.KeyScopeBIP0049
+		if addrType == "" {
+			addrType = "p2sh-segwit"
+		}
+	case waddrmgr.HDVersionMainNetBIP0084, waddrmgr.HDVersionTestNetBIP0084:
+		scope = waddrmgr.KeyScopeBIP0084
+		if addrType == "" {
+			addrType = "bech32"
+		}
+	}
+
+	var typeScope waddrmgr.KeyScope
+	switch addrType {
+	case "", "legacy":
+		typeScope = waddrmgr.KeyScopeBIP0044
+	case "p2sh-segwit":
+		typeScope = waddrmgr.KeyScopeBIP0049
+	case "bech32":
+		typeScope = waddrmgr.KeyScopeBIP0084
+	default:
+		return scope, fmt.Errorf("unrecognized address type %q, must "+
+			"be legacy, p2sh-segwit or bech32", addrType)
+	}
+	if scope != (waddrmgr.KeyScope{}) && scope != typeScope {
+		return scope, fmt.Errorf("address type %q does not match the "+
+			"version of the key", addrType)
+	}
+	return typeScope, nil
+}
+
+// ImportRecoveryAccounts rebuilds a watch-only account, named "recovery-N"
+// after its position in the list, from each account extended public key, and
+// derives the first lookahead addresses of both of its branches so the rescan
+// of the wallet from its birthday finds their history.  The wallet may be
+// locked, as no private key is involved.
+func (w *Wallet) ImportRecoveryAccounts(accts []RecoveryAccount,
+	lookahead uint32) ([]*waddrmgr.AccountProperties, error) {
+
+	var props []*waddrmgr.AccountProperties
+	err := walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		for i, acct := range accts {
+			err := w.validateExtendedPubKey(acct.PubKey, true)
+			if err != nil {
+				return fmt.Errorf("account %d: %w", i, err)
+			}
+			schema := waddrmgr.ScopeAddrMap[acct.Scope]
+			p, err := w.importAccountScope(
+				ns, fmt.Sprintf("recovery-%d", i), acct.PubKey,
+				0, acct.Scope, &schema,
+			)
+			if err != nil {
+				return fmt.Errorf("account %d: %w", i, err)
+			}
+			scopedMgr, err := w.Manager.FetchScopedKeyManager(acct.Scope)
+			if err != nil {
+				return err
+			}
+			if lookahead > 0 {
+				for _, branch := range []uint32{
+					waddrmgr.ExternalBranch,
+					waddrmgr.InternalBranch,
+				} {
+					err := scopedMgr.ExtendAddresses(
+						ns, p.AccountNumber, branch,
+						lookahead-1,
+					)
+					if err != nil {
+						return err
+					}
+				}
+			}
+
+			// Refetch the properties for the derived key counts.
+			p, err = scopedMgr.AccountProperties(ns, p.AccountNumber)
+			if err != nil {
+				return err
+			}
+			props = append(props, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return props, nil
+}
@@ -0,0 +1,98 @@
+package wallet
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRecoveryAccounts checks that the accounts rebuilt from the extended
+// public keys of another wallet derive its addresses without their private
+// keys, and that their outputs are swept into an unsigned PSBT.
+func TestRecoveryAccounts(t *testing.T) {
+	cold, cleanupCold := testWallet(t)
+	defer cleanupCold()
+
+	legacyProps, err := cold.AccountProperties(waddrmgr.KeyScopeBIP0044, 0)
+	require.NoError(t, err)
+	segwitProps, err := cold.AccountProperties(waddrmgr.KeyScopeBIP0084, 0)
+	require.NoError(t, err)
+	legacyAddr, err := cold.NewAddress(0, waddrmgr.KeyScopeBIP0044)
+	require.NoError(t, err)
+	segwitAddr, err := cold.NewAddress(0, waddrmgr.KeyScopeBIP0084)
+	require.NoError(t, err)
+
+	list := "# cold storage\n\n" +
+		legacyProps.AccountPubKey.String() + "\n" +
+		segwitProps.AccountPubKey.String() + " bech32\n"
+	accts, err := ParseRecoveryAccounts(strings.NewReader(list))
+	require.NoError(t, err)
+	require.Len(t, accts, 2)
+	require.Equal(t, waddrmgr.KeyScopeBIP0044, accts[0].Scope)
+	require.Equal(t, waddrmgr.KeyScopeBIP0084, accts[1].Scope)
+
+	_, err = ParseRecoveryAccounts(strings.NewReader(
+		legacyProps.AccountPubKey.String() + " taproot\n",
+	))
+	require.Error(t, err)
+
+	// The accounts are rebuilt while the wallet is locked.
+	w, cleanup := testWallet(t)
+	defer cleanup()
+	w.Lock()
+	props, err := w.ImportRecoveryAccounts(accts, 5)
+	require.NoError(t, err)
+	require.Len(t, props, 2)
+	require.Equal(t, "recovery-0", props[0].AccountName)
+	require.Equal(t, uint32(1), props[0].AccountNumber)
+	require.Equal(t, uint32(5), props[0].ExternalKeyCount)
+	require.Equal(t, uint32(5), props[1].InternalKeyCount)
+
+	// The first addresses of the accounts are those of the cold wallet.
+	for i, addr := range []btcutil.Address{legacyAddr, segwitAddr} {
+		info, err := w.AddressInfo(addr)
+		require.NoError(t, err)
+		require.Equal(t, props[i].AccountNumber, info.InternalAccount())
+	}
+
+	// Unlocking the wallet doesn't make their private keys available.
+	require.NoError(t, w.Unlock([]byte("hello world"), time.After(time.Minute)))
+	_, err = w.DumpWIFPrivateKey(segwitAddr)
+	require.Error(t, err)
+
+	// Sweep the funds of the segwit account.
+	pkScript, err := txscript.PayToAddrScript(segwitAddr)
+	require.NoError(t, err)
+	incomingTx := &wire.MsgTx{
+		TxIn:  []*wire.TxIn{{}},
+		TxOut: []*wire.TxOut{wire.NewTxOut(1000000, pkScript)},
+	}
+	addUtxo(t, w, incomingTx)
+
+	dest, err := w.NewAddress(0, waddrmgr.KeyScopeBIP0084)
+	require.NoError(t, err)
+	scope := waddrmgr.KeyScopeBIP0084
+	_, _, _, err = w.SweepAccountPsbt(&waddrmgr.KeyScopeBIP0044,
+		props[0].AccountNumber, dest, 0, 1000)
+	require.ErrorIs(t, err, ErrNothingToSweep)
+
+	packet, fee, amount, err := w.SweepAccountPsbt(&scope,
+		props[1].AccountNumber, dest, 0, 1000)
+	require.NoError(t, err)
+	require.Len(t, packet.UnsignedTx.TxIn, 1)
+	require.Equal(t, incomingTx.TxHash(),
+		packet.UnsignedTx.TxIn[0].PreviousOutPoint.Hash)
+	require.Len(t, packet.UnsignedTx.TxOut, 1)
+	require.Equal(t, btcutil.Amount(1000000), fee+amount)
+	require.Positive(t, int64(fee))
+	require.Len(t, packet.Inputs[0].Bip32Derivation, 1)
+	require.Equal(t, []uint32{
+		84 + 0x80000000, 140 + 0x80000000, 0x80000000, 0, 0,
+	}, packet.Inputs[0].Bip32Derivation[0].Bip32Path)
+}
@@ -0,0 +1,307 @@
+package walletd
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lbryio/lbcwallet/wallet"
+	"golang.org/x/crypto/curve25519"
+)
+
+// defaultS3Region is the region of the requests to the backups3 endpoint,
+// which most S3-compatible services other than AWS ignore.
+const defaultS3Region = "us-east-1"
+
+// s3Timeout is the timeout of each request to the backups3 endpoint.
+const s3Timeout = 5 * time.Minute
+
+// parseBackupKey decodes the hex encoded public key of the backupkey option.
+func parseBackupKey(s string) (*[32]byte, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != 32 {
+		return nil, errors.New("backupkey must be a hex encoded 32 " +
+			"byte public key")
+	}
+	var key [32]byte
+	copy(key[:], b)
+	return &key, nil
+}
+
+// genBackupKey prints a new key pair for the backups: the public key for the
+// backupkey option, and the private key to be kept apart from the wallet, which
+// decrypts the backups.
+func genBackupKey() error {
+	var privateKey [32]byte
+	if _, err := rand.Read(privateKey[:]); err != nil {
+		return err
+	}
+	publicKey, err := curve25519.X25519(privateKey[:], curve25519.Basepoint)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("backupkey=%x\n", publicKey)
+	fmt.Printf("Private key, which decrypts the backups and must be kept "+
+		"apart from the wallet: %x\n", privateKey[:])
+	return nil
+}
+
+// decryptBackup decrypts a backup with the hex encoded private key read from
+// standard input, and writes the wallet database to the path of the backup
+// without its extension.
+func decryptBackup(path string) error {
+	sealed, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	dbPath := strings.TrimSuffix(path, wallet.BackupExt)
+	if dbPath == path {
+		dbPath += ".db"
+	}
+	if _, err := os.Stat(dbPath); err == nil {
+		return fmt.Errorf("%s already exists", dbPath)
+	}
+
+	fmt.Print("Enter the private key of the backup: ")
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return err
+	}
+	b, err := hex.DecodeString(strings.TrimSpace(line))
+	if err != nil || len(b) != 32 {
+		return errors.New("the private key must be 32 hex encoded bytes")
+	}
+	var privateKey, publicKey [32]byte
+	copy(privateKey[:], b)
+	pub, err := curve25519.X25519(privateKey[:], curve25519.Basepoint)
+	if err != nil {
+		return err
+	}
+	copy(publicKey[:], pub)
+
+	db, err := wallet.OpenBackup(sealed, &publicKey, &privateKey)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(dbPath, db, 0600); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote the wallet database to %s\n", dbPath)
+	return nil
+}
+
+// s3Store is a wallet.BackupStore writing the backups to a bucket of an
+// S3-compatible service, with path-style requests signed with AWS signature
+// version 4.  The requests are made over the proxy of the http client.
+type s3Store struct {
+	endpoint  *url.URL
+	bucket    string
+	prefix    string
+	region    string
+	accessKey string
+	secretKey string
+	client    *http.Client
+}
+
+// newS3Store returns a store for the backups3 URL, whose path is the bucket
+// followed by an optional prefix of the object keys, making its requests with
+// client.
+func newS3Store(rawURL, region, accessKey, secretKey string,
+	client *http.Client) (*s3Store, error) {
+
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") ||
+		u.Host == "" {
+
+		return nil, errors.New("backups3 must be an http or https URL")
+	}
+	path := strings.TrimPrefix(u.Path, "/")
+	bucket, prefix := path, ""
+	if i := strings.IndexByte(path, '/'); i >= 0 {
+		bucket, prefix = path[:i], path[i+1:]
+	}
+	if bucket == "" {
+		return nil, errors.New("backups3 must name a bucket")
+	}
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	if region == "" {
+		region = defaultS3Region
+	}
+	return &s3Store{
+		endpoint:  &url.URL{Scheme: u.Scheme, Host: u.Host},
+		bucket:    bucket,
+		prefix:    prefix,
+		region:    region,
+		accessKey: accessKey,
+		secretKey: secretKey,
+		client:    client,
+	}, nil
+}
+
+// String returns the URL of the bucket and prefix.
+func (s *s3Store) String() string {
+	return fmt.Sprintf("%s/%s/%s", s.endpoint, s.bucket, s.prefix)
+}
+
+// Put uploads a backup as an object.
+func (s *s3Store) Put(name string, data []byte) error {
+	_, err := s.do(http.MethodPut, s.prefix+name, nil, data)
+	return err
+}
+
+// Remove deletes the object of a backup.
+func (s *s3Store) Remove(name string) error {
+	_, err := s.do(http.MethodDelete, s.prefix+name, nil, nil)
+	return err
+}
+
+// List returns the names of the objects under the prefix, without the prefix.
+func (s *s3Store) List() ([]string, error) {
+	var names []string
+	query := url.Values{
+		"list-type": {"2"},
+		"prefix":    {s.prefix},
+	}
+	for {
+		body, err := s.do(http.MethodGet, "", query, nil)
+		if err != nil {
+			return nil, err
+		}
+		var result struct {
+			Contents []struct {
+				Key string
+			}
+			IsTruncated           bool
+			NextContinuationToken string
+		}
+		if err := xml.Unmarshal(body, &result); err != nil {
+			return nil, err
+		}
+		for _, c := range result.Contents {
+			names = append(names, strings.TrimPrefix(c.Key, s.prefix))
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return names, nil
+		}
+		query.Set("continuation-token", result.NextContinuationToken)
+	}
+}
+
+// do makes a signed request for an object key of the bucket, or for the
+// bucket when the key is empty, and returns the response body.
+func (s *s3Store) do(method, key string, query url.Values,
+	body []byte) ([]byte, error) {
+
+	u := *s.endpoint
+	u.Path = "/" + s.bucket
+	if key != "" {
+		u.Path += "/" + key
+	}
+	u.RawQuery = strings.ReplaceAll(query.Encode(), "+", "%20")
+
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	s.sign(req, body, time.Now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("%s %s: %s", method, u.Path, resp.Status)
+	}
+	return respBody, nil
+}
+
+// sign adds the AWS signature version 4 headers of a request made at time t.
+func (s *s3Store) sign(req *http.Request, body []byte, t time.Time) {
+	t = t.UTC()
+	amzDate := t.Format("20060102T150405Z")
+	date := t.Format("20060102")
+	payloadHash := sha256.Sum256(body)
+	payload := hex.EncodeToString(payloadHash[:])
+
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payload)
+
+	const signedHeaders = "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + payload,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		payload,
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" +
+		hex.EncodeToString(requestHash[:])
+
+	key := []byte("AWS4" + s.secretKey)
+	for _, part := range []string{date, s.region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 "+
+		"Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature))
+}
+
+// canonicalQuery returns the query string of a signed request, with the
+// parameters sorted by name and encoded as required by the signature.
+func canonicalQuery(query url.Values) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var params []string
+	for _, name := range names {
+		for _, value := range query[name] {
+			params = append(params, s3Escape(name)+"="+s3Escape(value))
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+// s3Escape escapes a query parameter of a signed request, which only leaves
+// unreserved characters unescaped.
+func s3Escape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of data with the key.
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
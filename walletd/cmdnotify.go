@@ -0,0 +1,168 @@
+package walletd
+
+import (
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/lbryio/lbcwallet/wallet"
+)
+
+const (
+	// cmdNotifyWorkers is the number of notification commands which may
+	// run at the same time.
+	cmdNotifyWorkers = 4
+
+	// cmdNotifyQueueSize is the number of commands queued while the
+	// workers are busy, after which further commands are dropped rather
+	// than blocking the wallet.
+	cmdNotifyQueueSize = 1024
+)
+
+// cmdNotifier runs the commands configured by blocknotify and walletnotify for
+// the blocks and transactions of a wallet, like the options of bitcoind, and
+// by disknotify for the free disk space of the wallets.
+type cmdNotifier struct {
+	blockCmd  string
+	walletCmd string
+	diskCmd   string
+	queue     chan string
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newCmdNotifier starts the workers running the commands.  Any command may be
+// empty.
+func newCmdNotifier(blockCmd, walletCmd, diskCmd string) *cmdNotifier {
+	n := &cmdNotifier{
+		blockCmd:  blockCmd,
+		walletCmd: walletCmd,
+		diskCmd:   diskCmd,
+		queue:     make(chan string, cmdNotifyQueueSize),
+		quit:      make(chan struct{}),
+	}
+	n.wg.Add(cmdNotifyWorkers)
+	for i := 0; i < cmdNotifyWorkers; i++ {
+		go n.worker()
+	}
+	return n
+}
+
+// run queues the commands for the transaction notifications of a wallet until
+// the notifier is closed.
+func (n *cmdNotifier) run(w *wallet.Wallet) {
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+
+		client := w.NtfnServer.TransactionNotifications()
+		defer client.Done()
+		for {
+			select {
+			case ntfn, ok := <-client.C:
+				if !ok {
+					return
+				}
+				n.notify(ntfn)
+			case <-n.quit:
+				return
+			}
+		}
+	}()
+}
+
+func (n *cmdNotifier) notify(ntfn *wallet.TransactionNotifications) {
+	for _, tx := range ntfn.UnminedTransactions {
+		n.notifyWallet(tx.Hash.String(), "unconfirmed", -1)
+	}
+	for _, block := range ntfn.AttachedBlocks {
+		for _, tx := range block.Transactions {
+			n.notifyWallet(tx.Hash.String(), block.Hash.String(),
+				block.Height)
+		}
+		if n.blockCmd != "" {
+			n.enqueue(strings.ReplaceAll(n.blockCmd, "%s",
+				block.Hash.String()))
+		}
+	}
+}
+
+// notifyWallet queues the walletnotify command of a transaction.  %s is
+// replaced by the txid, %b by the hash of the block it is mined in or
+// "unconfirmed", and %h by the block height or -1.
+func (n *cmdNotifier) notifyWallet(txid, blockHash string, height int32) {
+	if n.walletCmd == "" {
+		return
+	}
+	n.enqueue(strings.NewReplacer(
+		"%s", txid,
+		"%b", blockHash,
+		"%h", strconv.Itoa(int(height)),
+	).Replace(n.walletCmd))
+}
+
+// notifyDisk queues the disknotify command of the free disk space of a wallet.
+// %s is replaced by ok, warning or low, %f by the free space in MiB, and %w by
+// the path of the wallet database.
+func (n *cmdNotifier) notifyDisk(status wallet.DiskStatus) {
+	if n.diskCmd == "" {
+		return
+	}
+	level := "ok"
+	switch {
+	case status.Low:
+		level = "low"
+	case status.Warning:
+		level = "warning"
+	}
+	n.enqueue(strings.NewReplacer(
+		"%s", level,
+		"%f", strconv.FormatUint(status.Free>>20, 10),
+		"%w", status.Path,
+	).Replace(n.diskCmd))
+}
+
+func (n *cmdNotifier) enqueue(cmd string) {
+	select {
+	case n.queue <- cmd:
+	default:
+		log.Warnf("Too many notification commands are queued, not "+
+			"running %q", cmd)
+	}
+}
+
+func (n *cmdNotifier) worker() {
+	defer n.wg.Done()
+
+	for {
+		select {
+		case cmd := <-n.queue:
+			runNotifyCommand(cmd)
+		case <-n.quit:
+			return
+		}
+	}
+}
+
+// runNotifyCommand runs a command with the shell and waits for it to exit.
+func runNotifyCommand(cmd string) {
+	var c *exec.Cmd
+	if runtime.GOOS == "windows" {
+		c = exec.Command("cmd", "/C", cmd)
+	} else {
+		c = exec.Command("/bin/sh", "-c", cmd)
+	}
+	if err := c.Run(); err != nil {
+		log.Warnf("Notification command %q failed: %v", cmd, err)
+	}
+}
+
+// close stops queueing commands and waits for the running commands to exit.
+// Queued commands which have not started are not run.
+func (n *cmdNotifier) close() {
+	close(n.quit)
+	n.wg.Wait()
+}
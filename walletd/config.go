@@ -0,0 +1,1437 @@
+// Copyright (c) 2013-2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package walletd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	flags "github.com/jessevdk/go-flags"
+	"github.com/lbryio/lbcd/version"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/internal/cfgutil"
+	"github.com/lbryio/lbcwallet/netparams"
+	"github.com/lbryio/lbcwallet/rpc/legacyrpc"
+	"github.com/lbryio/lbcwallet/wallet"
+)
+
+const (
+	defaultCAFilename           = "lbcd.cert"
+	defaultConfigFilename       = "lbcwallet.conf"
+	defaultLogLevel             = "info"
+	defaultLogDirname           = "logs"
+	defaultLogFilename          = "lbcwallet.log"
+	defaultLogFormat            = "text"
+	defaultSyslogFacility       = "daemon"
+	defaultRPCMaxClients        = 10
+	defaultRPCMaxWebsockets     = 25
+	defaultPassphrase           = "password"
+	defaultTLSMinVersion        = "1.2"
+	defaultCertPollInterval     = time.Minute
+	defaultRPCCertValidity      = 10 * 365 * 24 * time.Hour
+	defaultRPCAuthFailures      = 5
+	defaultRPCAuthBanTime       = 10 * time.Minute
+	defaultShutdownTimeout      = 30 * time.Second
+	defaultFiatCurrency         = "USD"
+	defaultElectrumPort         = "50001"
+	defaultProxyPort            = "9050"
+	defaultTorControlPort       = "9051"
+	defaultI2PSAMPort           = "7656"
+	defaultBackupKeep           = 10
+	defaultDiskWarn             = 1024
+	defaultDiskFloor            = 256
+	defaultDiskCheckInterval    = time.Minute
+	defaultLbcdDialTimeout      = 30 * time.Second
+	defaultLbcdHandshakeTimeout = 30 * time.Second
+	defaultLbcdPingInterval     = time.Minute
+	defaultLbcdPongTimeout      = 30 * time.Second
+	defaultRPCSlowThreshold     = 5 * time.Second
+	defaultVerifyInterval       = time.Minute
+	defaultWithdrawalApprovals  = 2
+	defaultWithdrawalExpiry     = 24 * time.Hour
+	defaultWithdrawalWindow     = 24 * time.Hour
+	defaultKeyPoolSize          = 1000
+)
+
+var (
+	lbcdDefaultCAFile  = filepath.Join(btcutil.AppDataDir("lbcd", false), "rpc.cert")
+	defaultAppDataDir  = btcutil.AppDataDir("lbcwallet", false)
+	defaultConfigFile  = filepath.Join(defaultAppDataDir, defaultConfigFilename)
+	defaultRPCKeyFile  = filepath.Join(defaultAppDataDir, "rpc.key")
+	defaultRPCCertFile = filepath.Join(defaultAppDataDir, "rpc.cert")
+	defaultLogDir      = filepath.Join(defaultAppDataDir, defaultLogDirname)
+)
+
+// Config is the configuration of a wallet daemon, holding every option of the
+// config file and command line.  It is returned by LoadConfig.
+type Config struct {
+	// General application behavior
+	ConfigFile       *cfgutil.ExplicitString `short:"C" long:"configfile" description:"Path to configuration file"`
+	ShowVersion      bool                    `short:"V" long:"version" description:"Display version information and exit"`
+	DumpCfg          bool                    `long:"dumpcfg" description:"Write a commented config file with the effective value of every option to stdout and exit"`
+	ValidateCfg      bool                    `long:"validatecfg" description:"Check the config file and options, including addresses, paths and TLS material, and exit without starting the wallet"`
+	CollectDebugInfo bool                    `long:"collectdebuginfo" description:"Write a bundle of debug information for bug reports, with the version, the config with credentials redacted, the recent logs and the database stats, to the debug directory of appdata and exit"`
+	Create           bool                    `long:"create" description:"Create the wallet if it does not exist"`
+	CreateFromDump   string                  `long:"createfromdump" description:"Create the wallet from a file written by dumpwallet instead of a seed -- used with --create"`
+	CreateFromShares string                  `long:"createfromshares" description:"Create the wallet from a file of the Shamir shares of its seed written by --seedshares or exportseedshares, one per line, instead of a seed -- used with --create and optionally --recoverbirthday"`
+	SeedShares       string                  `long:"seedshares" description:"Split the seed of the created wallet into Shamir shares and print them, given as <threshold>-of-<count> such as 3-of-5 -- used with --create"`
+	RecoverXPubs     string                  `long:"recoverxpubs" description:"Create a watch-only recovery wallet from a file of account extended public keys, one per line optionally followed by legacy, p2sh-segwit or bech32, instead of a seed -- used with --create and --recoverbirthday"`
+	RecoverBirthday  string                  `long:"recoverbirthday" description:"The birthday (YYYY-MM-DD) from which the wallet created with --recoverxpubs or --createfromshares rescans the chain"`
+	RecoverWindow    uint32                  `long:"recoverwindow" description:"The number of addresses of each branch of the accounts of --recoverxpubs derived ahead of the rescan"`
+	CreateTemp       bool                    `long:"createtemp" description:"Create a temporary simulation wallet (pass=password) in the data directory indicated; must call with --datadir"`
+	CreateFixture    string                  `long:"createfixture" description:"Create a wallet from a fixed seed (pass=password) with a deterministic synthetic history of transactions, given as <receives>,<spends>,<claims> such as 100,50,10, in the data directory indicated and exit -- for tests and benchmarks; must call with --appdata on regtest or testnet"`
+	ReplayTxLog      string                  `long:"replaytxlog" description:"Replay a transaction log written by exporttxlog into a temporary wallet created from the seed prompted for, check that it reconstructs the same balance and history, and exit -- for verifying database migrations"`
+	AppDataDir       *cfgutil.ExplicitString `short:"A" long:"appdata" description:"Application data directory for wallet config, databases and logs"`
+	Wallets          []string                `long:"wallet" description:"Also load the named wallet, kept in the wallets directory of the network directory and served at the /wallet/<name> RPC path -- may be specified multiple times"`
+	TestNet3         bool                    `long:"testnet" description:"Use the test Bitcoin network (version 3) (default client port: 19244, server port: 19245)"`
+	Regtest          bool                    `long:"regtest" description:"Use the regression test network (default client port: 29244, server port: 29245)"`
+	Network          string                  `long:"network" description:"Use the network with this name, such as mainnet, testnet3 or regtest -- testnet and regtest are shortcuts for the test networks"`
+	RegtestHarness   bool                    `long:"regtest-harness" description:"Enable the RPC methods generatetowallet, fundaddress and fastforward, which mine blocks to the wallet, fund addresses and advance the wallet clock for integration tests -- used with --regtest"`
+	MiningAccount    string                  `long:"miningaccount" description:"Make the generate RPC method mine blocks paying new addresses of this account, and return once the wallet has processed them, rather than passing it through to lbcd -- used with --regtest"`
+	Faucet           string                  `long:"faucet" description:"HTTP endpoint of a faucet the requesttestcoins RPC method requests test coins from, POSTed the address and amount as a form ({address} and {amount} are also replaced in the URL) -- used with --testnet or --regtest"`
+	DebugLevel       string                  `short:"d" long:"debuglevel" description:"Logging level {trace, debug, info, warn, error, critical}"`
+	LogDir           string                  `long:"logdir" description:"Directory to log output."`
+	LogFormat        string                  `long:"logformat" description:"Log output format {text, json}"`
+	LogTargets       []string                `long:"logtarget" description:"Log output target {file, stdout, syslog} -- may be specified multiple times (default: file and stdout)"`
+	SyslogFacility   string                  `long:"syslogfacility" description:"Syslog facility used by the syslog log target {user, daemon, local0-local7, ...}"`
+	Profile          string                  `long:"profile" description:"Enable HTTP profiling on given [addr:]port -- NOTE port must be between 1024 and 65536"`
+	ProfileAuth      bool                    `long:"profileauth" description:"Require the RPC username and password to access the profile server"`
+	DBTimeout        time.Duration           `long:"dbtimeout" description:"The timeout value to use when opening the wallet database."`
+	SyncFreelist     bool                    `long:"syncfreelist" description:"Store the database freelist, so that large wallets open without scanning the whole database at the cost of slower writes"`
+	KeyPoolSize      uint32                  `long:"keypoolsize" description:"The number of keys of the next unused receiving and change addresses of each account derived in advance, so that new addresses are handed out without deriving keys (0 to disable)"`
+	ShutdownTimeout  time.Duration           `long:"shutdowntimeout" description:"How long to wait for in-flight RPCs to finish during shutdown, and then again for the wallet to close, before exiting forcibly (0 to wait without bound)"`
+
+	// Passphrase options
+	Passphrase       string `short:"p" long:"passphrase" default-mask:"-" description:"The wallet passphrase (default: \"passphrase\")"`
+	WalletPass       string `long:"walletpass" default-mask:"-" description:"The public wallet passphrase -- Only required if it was changed from the default"`
+	ChangeWalletPass bool   `long:"changewalletpass" description:"Prompt for a new public wallet passphrase, change it from walletpass, and exit"`
+
+	// Transaction options
+	MatchChangeType    bool          `long:"matchchangetype" description:"Pay change to an address of the type of the payment outputs when sending from every address type"`
+	SplitChange        bool          `long:"splitchange" description:"Split the change of sent transactions into two outputs of random amounts when it is large enough"`
+	BroadcastDelay     time.Duration `long:"broadcastdelay" description:"Delay the broadcast of each sent transaction by a random duration of up to this long (eg. 30s, 5m)"`
+	BroadcastIsolation bool          `long:"broadcastisolation" description:"Broadcast transactions over another proxy circuit than the lbcd connection, with new random proxy credentials for each"`
+	SendPreview        bool          `long:"sendpreview" description:"Make the send RPCs return a preview of the unsigned transaction and a handle, and sign and broadcast it only once confirmsend is called with the handle"`
+	MinConfCoinbase    int32         `long:"minconfcoinbase" description:"Only spend the outputs of coinbase transactions with at least this many confirmations, in addition to their maturity"`
+	MinConfClaim       int32         `long:"minconfclaim" description:"Only spend the outputs of transactions creating, updating or spending claims and supports with at least this many confirmations"`
+	MinConfChange      int32         `long:"minconfchange" description:"Only spend the outputs of other transactions spending only outputs of the wallet, such as change, with at least this many confirmations"`
+	MinConfExternal    int32         `long:"minconfexternal" description:"Only spend the outputs received from transactions of others with at least this many confirmations"`
+	ClaimAccount       string        `long:"claimaccount" description:"Fund claim, support and claim update transactions only from this account, with the coins it received from transactions of the wallet itself"`
+	WithdrawThreshold  float64       `long:"withdrawalthreshold" description:"Queue the sends of the send RPCs paying more than this many LBC along with the other sends of withdrawalwindow as withdrawals, which are only sent once approved by withdrawalapprovals distinct users of rpcapprover, and disable signrawtransaction, signpsbt, sendrawtransaction and the export of private keys with dumpprivkey, dumpwallet, dumpimportedaccount and exportseedshares (0 to disable)"`
+	WithdrawApprovals  int           `long:"withdrawalapprovals" description:"Number of distinct rpcapprover users who must approve a withdrawal before it is sent"`
+	WithdrawExpiry     time.Duration `long:"withdrawalexpiry" description:"How long a withdrawal waits for its approvals before it expires"`
+	WithdrawWindow     time.Duration `long:"withdrawalwindow" description:"Period over which the amounts of the sends made without approval are added up to be compared with withdrawalthreshold (0 to compare each send alone)"`
+	ColdSigning        bool          `long:"coldsigning" description:"Make the send RPCs return an unsigned PSBT, to be signed by signpsbt of an offline wallet holding the keys and sent with publishpsbt, for a watch-only wallet written by clonewallet"`
+	PsbtDir            string        `long:"psbtdir" description:"Also write each PSBT returned by the send RPCs to this directory, base64 encoded in a file named by the hash of its unsigned transaction -- used with --coldsigning"`
+
+	// RPC client options
+	RPCConnect           string                  `short:"c" long:"rpcconnect" description:"Hostname/IP and port of lbcd RPC server to connect to (default localhost:9245, testnet: localhost:19245, regtest: localhost:29245)"`
+	CAFile               *cfgutil.ExplicitString `long:"cafile" description:"File containing root certificates to authenticate a TLS connections with lbcd"`
+	DisableClientTLS     bool                    `long:"noclienttls" description:"Disable TLS for the RPC client"`
+	SkipVerify           bool                    `long:"skipverify" description:"Skip verifying TLS for the RPC client"`
+	Proxy                string                  `long:"proxy" description:"Connect via SOCKS5 proxy (eg. 127.0.0.1:9050)"`
+	ProxyUser            string                  `long:"proxyuser" description:"Username for proxy server"`
+	ProxyPass            string                  `long:"proxypass" default-mask:"-" description:"Password for proxy server"`
+	TorIsolation         bool                    `long:"torisolation" description:"Enable Tor stream isolation by randomizing the proxy credentials of each connection"`
+	TorControl           string                  `long:"torcontrol" description:"Tor control port (eg. 127.0.0.1:9051) used to serve the RPC and Electrum listeners over an ephemeral onion service, and to connect through the SOCKS port of Tor with stream isolation when no proxy is set"`
+	TorPassword          string                  `long:"torpassword" default-mask:"-" description:"Password for the Tor control port, when it does not use cookie authentication"`
+	OnlyNet              string                  `long:"onlynet" description:"Only make outbound connections to the given network, other than to the local host (onion)"`
+	I2PSAM               string                  `long:"i2psam" description:"SAM bridge of an I2P router (eg. 127.0.0.1:7656) used to connect to lbcd when rpcconnect is an I2P address"`
+	HTTPProxy            string                  `long:"httpproxy" description:"Connect to webhooks and price endpoints via this SOCKS5 proxy instead of the proxy for lbcd (eg. 127.0.0.1:9050)"`
+	HTTPProxyUser        string                  `long:"httpproxyuser" description:"Username for the webhook and price endpoint proxy server"`
+	HTTPProxyPass        string                  `long:"httpproxypass" default-mask:"-" description:"Password for the webhook and price endpoint proxy server"`
+	NoHTTPProxy          bool                    `long:"nohttpproxy" description:"Connect to webhooks and price endpoints directly, even when lbcd is connected to via a proxy"`
+	LbcdDialTimeout      time.Duration           `long:"lbcddialtimeout" description:"How long to wait for the TCP connection to lbcd, or to the proxy, to open before retrying (0 to wait without bound)"`
+	LbcdHandshakeTimeout time.Duration           `long:"lbcdhandshaketimeout" description:"How long to wait for the TLS and websocket handshakes with lbcd to complete before retrying (0 to wait without bound)"`
+	LbcdPingInterval     time.Duration           `long:"lbcdpinginterval" description:"How often to ping lbcd over the websocket connection to detect a dead link (0 to disable)"`
+	LbcdPongTimeout      time.Duration           `long:"lbcdpongtimeout" description:"How long to wait for lbcd to answer a ping before reconnecting (0 to disable pings)"`
+	FaultNotifyDelay     time.Duration           `long:"faultnotifydelay" description:"Delay each notification of lbcd by a random duration of up to this long, to test the resilience of the sync -- development only, not on mainnet"`
+	FaultDropEvery       int                     `long:"faultdropevery" description:"Drop the websocket connection to lbcd after every this many notifications -- development only, not on mainnet"`
+	FaultReorderWindow   int                     `long:"faultreorderwindow" description:"Shuffle runs of up to this many consecutive block connected notifications of lbcd -- development only, not on mainnet"`
+	FaultSeed            int64                   `long:"faultseed" description:"Seed of the random delays and orders of the injected faults, so that a run can be reproduced"`
+	VerifyConnect        string                  `long:"verifyconnect" description:"Hostname/IP and port of a second lbcd RPC server, only used to cross-check the blocks of rpcconnect"`
+	VerifyUser           string                  `long:"verifyuser" description:"Username for the verifyconnect server (default rpcuser)"`
+	VerifyPass           string                  `long:"verifypass" default-mask:"-" description:"Password for the verifyconnect server (default rpcpass)"`
+	VerifyCAFile         string                  `long:"verifycafile" description:"File containing root certificates to authenticate the TLS connection to the verifyconnect server (default cafile)"`
+	VerifyInterval       time.Duration           `long:"verifyinterval" description:"How often to cross-check the blocks of rpcconnect with the verifyconnect server"`
+	VerifyPauseSends     bool                    `long:"verifypausesends" description:"Pause sending transactions while the blocks of rpcconnect and the verifyconnect server diverge"`
+
+	// RPC server options
+	RPCCert                *cfgutil.ExplicitString `long:"rpccert" description:"File containing the certificate file"`
+	RPCKey                 *cfgutil.ExplicitString `long:"rpckey" description:"File containing the certificate key"`
+	OneTimeTLSKey          bool                    `long:"onetimetlskey" description:"Generate a new TLS certpair at startup, but only write the certificate to disk"`
+	RPCCertHosts           []string                `long:"rpccerthost" description:"Add this DNS name or IP address to the generated RPC certificate, besides the host name and loopback addresses -- may be specified multiple times"`
+	RPCCertValidity        time.Duration           `long:"rpccertvalidity" description:"How long the generated RPC certificate is valid for (eg. 8760h)"`
+	RegenCert              bool                    `long:"regen-cert" description:"Replace the RPC certificate and key with newly generated ones at startup, such as after changing rpccerthost"`
+	DisableServerTLS       bool                    `long:"noservertls" description:"Disable TLS for the RPC server"`
+	TLSMinVersion          string                  `long:"tlsminversion" description:"Minimum TLS version accepted by the RPC server {1.2, 1.3}"`
+	TLSCipherSuites        []string                `long:"tlsciphersuite" description:"Cipher suite allowed by the RPC server for TLS 1.2 connections, may be specified multiple times (default: Go's secure defaults)"`
+	NoTLSSessionTickets    bool                    `long:"notlssessiontickets" description:"Disable the resumption of TLS sessions with session tickets by RPC clients"`
+	CertPollInterval       time.Duration           `long:"certpollinterval" description:"How often the RPC certificate and key files are checked for changes and reloaded (0 to only reload on SIGHUP)"`
+	LegacyRPCListeners     []string                `long:"rpclisten" description:"Listen for legacy RPC connections on this IP address, IPv6 address with zone (fe80::1%eth0) or network interface name (eth0:9244), and port; listening on all interfaces (0.0.0.0, ::) requires rpcallowip (default port: 9244, testnet: 19244, regtest: 29244)"`
+	RPCAllowIPs            []string                `long:"rpcallowip" description:"Only accept legacy RPC connections from this IP address or network in CIDR notation (10.0.0.0/8), besides loopback addresses -- may be specified multiple times"`
+	LegacyRPCMaxClients    int64                   `long:"rpcmaxclients" description:"Max number of legacy RPC clients for standard connections"`
+	LegacyRPCMaxWebsockets int64                   `long:"rpcmaxwebsockets" description:"Max number of RPC websocket connections"`
+	RPCMaxRequestSize      int64                   `long:"rpcmaxrequestsize" description:"Max size in bytes of the body of an HTTP POST RPC request"`
+	RPCMaxMessageSize      int64                   `long:"rpcmaxwsmessagesize" description:"Max size in bytes of a message from an RPC websocket client, which is disconnected when exceeding it"`
+	RPCWSQueueSize         int                     `long:"rpcwsqueuesize" description:"Max number of notifications queued for an RPC websocket client, which is disconnected when falling further behind"`
+	ReadyMaxBlockLag       int32                   `long:"readymaxblocklag" description:"Max number of blocks the wallet may be synced behind lbcd while the /readyz endpoint of the RPC server reports it ready"`
+	RPCSlowThreshold       time.Duration           `long:"rpcslowthreshold" description:"Log RPC requests taking longer than this duration, with their database time and time waiting for the database write lock (0 to disable)"`
+	RPCMaxResultItems      int                     `long:"rpcmaxresultitems" description:"Max number of items in the result of an RPC request, such as the transactions of listtransactions, which fails when exceeding it (0 for no limit)"`
+	RPCUser                string                  `short:"u" long:"rpcuser" description:"Username for RPC and lbcd authentication"`
+	RPCPass                string                  `short:"P" long:"rpcpass" default-mask:"-" description:"Password for RPC and lbcd authentication"`
+	RPCAuthFailures        int                     `long:"rpcauthfailures" description:"Number of consecutive failed RPC authentication attempts after which a client IP is banned (0 to disable)"`
+	RPCAuthBanTime         time.Duration           `long:"rpcauthbantime" description:"How long a client IP is banned after too many failed RPC authentication attempts, doubled for every repeated ban"`
+	RPCAuthExempt          []string                `long:"rpcauthexempt" description:"Never ban this IP address or network in CIDR notation, such as that of a local reverse proxy, on its own -- its failed RPC authentication attempts still count against the limit of all clients together -- may be specified multiple times"`
+	RPCApprovers           []string                `long:"rpcapprover" default-mask:"-" description:"Username and password, separated by a colon, of an RPC user who may only list and approve withdrawals over HTTP POST -- may be specified multiple times"`
+
+	// Command notification options
+	BlockNotify  string `long:"blocknotify" description:"Execute this command when a block is connected (%s is replaced by the block hash)"`
+	WalletNotify string `long:"walletnotify" description:"Execute this command when a wallet transaction is first seen or mined (%s is replaced by the txid, %b by the block hash or 'unconfirmed' and %h by the block height or -1)"`
+
+	// Electrum server options
+	ElectrumListeners []string `long:"electrumlisten" description:"Serve the Electrum protocol for the wallet addresses on this interface/port, using TLS unless noservertls is set (default port: 50001)"`
+	ElectrumBanner    string   `long:"electrumbanner" description:"Banner returned to Electrum clients"`
+
+	// ZMQ notification options
+	ZMQPubHashTx    string `long:"zmqpubhashtx" description:"Publish the hash of wallet transactions on this ZMQ endpoint (eg. tcp://127.0.0.1:28332)"`
+	ZMQPubRawTx     string `long:"zmqpubrawtx" description:"Publish serialized wallet transactions on this ZMQ endpoint"`
+	ZMQPubHashBlock string `long:"zmqpubhashblock" description:"Publish the hash of connected blocks on this ZMQ endpoint"`
+	ZMQPubSequence  string `long:"zmqpubsequence" description:"Publish block connect and disconnect events on this ZMQ endpoint"`
+	ZMQPubWalletTx  string `long:"zmqpubwallettx" description:"Publish a JSON summary of wallet transactions on this ZMQ endpoint"`
+	ZMQPubClaim     string `long:"zmqpubclaim" description:"Publish the claims, supports and claim updates received by the wallet as JSON on this ZMQ endpoint"`
+	ZMQPubInvoice   string `long:"zmqpubinvoice" description:"Publish invoices as JSON on this ZMQ endpoint when they are paid or expire"`
+
+	// Exchange rate options
+	PriceURL     string `long:"priceurl" description:"HTTP endpoint returning the historical LBC exchange rate as JSON, recorded for wallet transactions ({currency}, {currencylower}, {unix}, {unixms}, {date} and {date-dmy} are replaced)"`
+	PriceField   string `long:"pricefield" description:"Dot separated path of the rate in the priceurl response ({currency} and {currencylower} are replaced)"`
+	FiatCurrency string `long:"fiatcurrency" description:"Currency of the exchange rates recorded for wallet transactions"`
+
+	// Backup options
+	BackupInterval    time.Duration `long:"backupinterval" description:"Back up each wallet database this often (eg. 6h), encrypted to backupkey"`
+	BackupKey         string        `long:"backupkey" description:"Hex encoded Curve25519 public key the backups are encrypted to, as printed by genbackupkey"`
+	BackupKeep        int           `long:"backupkeep" description:"Number of backups of each wallet to keep, the oldest being removed (0 keeps every backup)"`
+	BackupDir         string        `long:"backupdir" description:"Directory the backups are written to (default: backups in appdata)"`
+	BackupS3          string        `long:"backups3" description:"URL of an S3-compatible bucket, optionally followed by a key prefix, the backups are uploaded to instead of backupdir (eg. https://s3.example.com/bucket/lbcwallet)"`
+	BackupS3Region    string        `long:"backups3region" description:"Region of the backups3 bucket (default: us-east-1)"`
+	BackupS3AccessKey string        `long:"backups3accesskey" description:"Access key of the backups3 bucket"`
+	BackupS3SecretKey string        `long:"backups3secretkey" default-mask:"-" description:"Secret key of the backups3 bucket"`
+	GenBackupKey      bool          `long:"genbackupkey" description:"Print a new key pair for backupkey and exit"`
+	DecryptBackup     string        `long:"decryptbackup" description:"Decrypt a backup with its private key read from standard input, write the wallet database next to it and exit"`
+
+	// Disk space options
+	DiskWarn          uint64        `long:"diskwarn" description:"Log a warning and run disknotify when the free space of the volume holding a wallet database falls below this many MiB"`
+	DiskFloor         uint64        `long:"diskfloor" description:"Refuse to derive addresses, create transactions and import keys while the free space of the volume holding a wallet database is below this many MiB, leaving the space to the sync of the wallet (0 to never refuse)"`
+	DiskCheckInterval time.Duration `long:"diskcheckinterval" description:"How often the free disk space is checked (0 to only check before the writes refused below diskfloor)"`
+	DiskNotify        string        `long:"disknotify" description:"Execute this command when the free disk space falls below diskwarn or diskfloor, or rises above them again (%s is replaced by ok, warning or low, %f by the free space in MiB and %w by the path of the wallet database)"`
+
+	// Deprecated options
+	DataDir *cfgutil.ExplicitString `short:"b" long:"datadir" default-mask:"-" description:"DEPRECATED -- use appdata instead"`
+
+	// activeNet is the network selected by the testnet, regtest and
+	// network options.
+	activeNet *netparams.Params
+
+	// configFile is the path of the config file which was loaded, used to
+	// read it again when the configuration is reloaded.  It is empty when
+	// the configuration was not loaded by LoadConfig.
+	configFile string
+
+	// args are the command line options which were parsed, used to parse
+	// them again when the configuration is reloaded.
+	args []string
+
+	// options are the values of the options as parsed, in the config file
+	// format, used to find the options changed by a reload.
+	options map[string]string
+
+	// backupKey and backupStore are the decoded backupkey and the store
+	// of the backupdir or backups3 options.
+	backupKey   *[32]byte
+	backupStore wallet.BackupStore
+
+	// rpcAllowedIPs and rpcAuthExemptIPs are the parsed networks of the
+	// rpcallowip and rpcauthexempt options.
+	rpcAllowedIPs    []*net.IPNet
+	rpcAuthExemptIPs []*net.IPNet
+
+	// rpcApprovers map the usernames of the rpcapprover option to their
+	// passwords, and withdrawalThreshold is the withdrawalthreshold
+	// option.
+	rpcApprovers        map[string]string
+	withdrawalThreshold btcutil.Amount
+}
+
+// cleanAndExpandPath expands environement variables and leading ~ in the
+// passed path, cleans the result, and returns it.
+func cleanAndExpandPath(path string) string {
+	// NOTE: The os.ExpandEnv doesn't work with Windows cmd.exe-style
+	// %VARIABLE%, but they variables can still be expanded via POSIX-style
+	// $VARIABLE.
+	path = os.ExpandEnv(path)
+
+	if !strings.HasPrefix(path, "~") {
+		return filepath.Clean(path)
+	}
+
+	// Expand initial ~ to the current user's home directory, or ~otheruser
+	// to otheruser's home directory.  On Windows, both forward and backward
+	// slashes can be used.
+	path = path[1:]
+
+	var pathSeparators string
+	if runtime.GOOS == "windows" {
+		pathSeparators = string(os.PathSeparator) + "/"
+	} else {
+		pathSeparators = string(os.PathSeparator)
+	}
+
+	userName := ""
+	if i := strings.IndexAny(path, pathSeparators); i != -1 {
+		userName = path[:i]
+		path = path[i:]
+	}
+
+	homeDir := ""
+	var u *user.User
+	var err error
+	if userName == "" {
+		u, err = user.Current()
+	} else {
+		u, err = user.Lookup(userName)
+	}
+	if err == nil {
+		homeDir = u.HomeDir
+	}
+	// Fallback to CWD if user lookup fails or user has no home directory.
+	if homeDir == "" {
+		homeDir = "."
+	}
+
+	return filepath.Join(homeDir, path)
+}
+
+// validLogLevel returns whether or not logLevel is a valid debug log level.
+func validLogLevel(logLevel string) bool {
+	switch logLevel {
+	case "trace":
+		fallthrough
+	case "debug":
+		fallthrough
+	case "info":
+		fallthrough
+	case "warn":
+		fallthrough
+	case "error":
+		fallthrough
+	case "critical":
+		return true
+	}
+	return false
+}
+
+// supportedSubsystems returns a sorted slice of the supported subsystems for
+// logging purposes.
+func supportedSubsystems() []string {
+	// Convert the subsystemLoggers map keys to a slice.
+	subsystems := make([]string, 0, len(subsystemLoggers))
+	for subsysID := range subsystemLoggers {
+		subsystems = append(subsystems, subsysID)
+	}
+
+	// Sort the subsytems for stable display.
+	sort.Strings(subsystems)
+	return subsystems
+}
+
+// parseAndSetDebugLevels attempts to parse the specified debug level and set
+// the levels accordingly.  An appropriate error is returned if anything is
+// invalid.
+func parseAndSetDebugLevels(debugLevel string) error {
+	// When the specified string doesn't have any delimters, treat it as
+	// the log level for all subsystems.
+	if !strings.Contains(debugLevel, ",") && !strings.Contains(debugLevel, "=") {
+		// Validate debug log level.
+		if !validLogLevel(debugLevel) {
+			str := "the specified debug level [%v] is invalid"
+			return fmt.Errorf(str, debugLevel)
+		}
+
+		// Change the logging level for all subsystems.
+		setLogLevels(debugLevel)
+
+		return nil
+	}
+
+	// Split the specified string into subsystem/level pairs while detecting
+	// issues and update the log levels accordingly.
+	for _, logLevelPair := range strings.Split(debugLevel, ",") {
+		if !strings.Contains(logLevelPair, "=") {
+			str := "the specified debug level contains an invalid " +
+				"subsystem/level pair [%v]"
+			return fmt.Errorf(str, logLevelPair)
+		}
+
+		// Extract the specified subsystem and log level.
+		fields := strings.Split(logLevelPair, "=")
+		subsysID, logLevel := fields[0], fields[1]
+
+		// Validate subsystem.
+		if _, exists := subsystemLoggers[subsysID]; !exists {
+			str := "the specified subsystem [%v] is invalid -- " +
+				"supported subsytems %v"
+			return fmt.Errorf(str, subsysID, supportedSubsystems())
+		}
+
+		// Validate log level.
+		if !validLogLevel(logLevel) {
+			str := "the specified debug level [%v] is invalid"
+			return fmt.Errorf(str, logLevel)
+		}
+
+		setLogLevel(subsysID, logLevel)
+	}
+
+	return nil
+}
+
+// DefaultConfig returns the configuration used for all options which are not
+// set by the config file or command line.  Applications embedding a daemon
+// may change its options and pass it to New without a config file.
+func DefaultConfig() Config {
+	return Config{
+		DebugLevel:             defaultLogLevel,
+		ConfigFile:             cfgutil.NewExplicitString(defaultConfigFile),
+		AppDataDir:             cfgutil.NewExplicitString(defaultAppDataDir),
+		LogDir:                 defaultLogDir,
+		LogFormat:              defaultLogFormat,
+		SyslogFacility:         defaultSyslogFacility,
+		CAFile:                 cfgutil.NewExplicitString(""),
+		RPCKey:                 cfgutil.NewExplicitString(defaultRPCKeyFile),
+		RPCCert:                cfgutil.NewExplicitString(defaultRPCCertFile),
+		LegacyRPCMaxClients:    defaultRPCMaxClients,
+		LegacyRPCMaxWebsockets: defaultRPCMaxWebsockets,
+		RPCMaxRequestSize:      legacyrpc.DefaultMaxRequestSize,
+		RPCMaxMessageSize:      legacyrpc.DefaultMaxMessageSize,
+		RPCWSQueueSize:         legacyrpc.DefaultNotificationQueueSize,
+		ReadyMaxBlockLag:       legacyrpc.DefaultReadyMaxBlockLag,
+		RPCSlowThreshold:       defaultRPCSlowThreshold,
+		DataDir:                cfgutil.NewExplicitString(defaultAppDataDir),
+		DBTimeout:              wallet.DefaultDBTimeout,
+		ShutdownTimeout:        defaultShutdownTimeout,
+		Passphrase:             defaultPassphrase,
+		TLSMinVersion:          defaultTLSMinVersion,
+		CertPollInterval:       defaultCertPollInterval,
+		RPCCertValidity:        defaultRPCCertValidity,
+		RPCAuthFailures:        defaultRPCAuthFailures,
+		RPCAuthBanTime:         defaultRPCAuthBanTime,
+		FiatCurrency:           defaultFiatCurrency,
+		BackupKeep:             defaultBackupKeep,
+		DiskWarn:               defaultDiskWarn,
+		DiskFloor:              defaultDiskFloor,
+		DiskCheckInterval:      defaultDiskCheckInterval,
+		RecoverWindow:          wallet.DefaultRecoveryLookahead,
+		LbcdDialTimeout:        defaultLbcdDialTimeout,
+		LbcdHandshakeTimeout:   defaultLbcdHandshakeTimeout,
+		LbcdPingInterval:       defaultLbcdPingInterval,
+		LbcdPongTimeout:        defaultLbcdPongTimeout,
+		VerifyInterval:         defaultVerifyInterval,
+		WithdrawApprovals:      defaultWithdrawalApprovals,
+		WithdrawExpiry:         defaultWithdrawalExpiry,
+		WithdrawWindow:         defaultWithdrawalWindow,
+		KeyPoolSize:            defaultKeyPoolSize,
+	}
+}
+
+// copyExplicitStrings replaces the options of cfg holding an explicit string
+// with copies, so that adjusting them doesn't change the configuration cfg
+// was copied from.
+func copyExplicitStrings(cfg *Config) {
+	for _, s := range []**cfgutil.ExplicitString{
+		&cfg.ConfigFile, &cfg.AppDataDir, &cfg.CAFile, &cfg.RPCCert,
+		&cfg.RPCKey, &cfg.DataDir,
+	} {
+		if *s != nil {
+			c := **s
+			*s = &c
+		}
+	}
+}
+
+// LoadConfig initializes and parses the config using a config file and the
+// command line options args, which do not include the program name.
+//
+// The configuration proceeds as follows:
+//  1. Start with a default config with sane settings
+//  2. Pre-parse the command line to check for an alternative config file
+//  3. Load configuration file overwriting defaults with any specified options
+//  4. Parse CLI options and overwrite/add any specified options
+//
+// The above results in lbcwallet functioning properly without any config
+// settings while still allowing the user to override settings with config files
+// and command line options.  Command line options always take precedence.
+//
+// The options performing an action instead of running the daemon, such as
+// create or version, exit the process once the action completes.  The
+// configuration also initializes the logging of the process.
+func LoadConfig(args []string) (*Config, []string, error) {
+	// Default config.
+	cfg := DefaultConfig()
+
+	// Pre-parse the command line options to see if an alternative config
+	// file or the version flag was specified.
+	preCfg := cfg
+	preParser := flags.NewParser(&preCfg, flags.Default)
+	_, err := preParser.ParseArgs(args)
+	if err != nil {
+		if e, ok := err.(*flags.Error); !ok || e.Type != flags.ErrHelp {
+			preParser.WriteHelp(os.Stderr)
+		}
+		return nil, nil, err
+	}
+
+	// Show the version and exit if the version flag was specified.
+	funcName := "LoadConfig"
+	appName := filepath.Base(os.Args[0])
+	appName = strings.TrimSuffix(appName, filepath.Ext(appName))
+	usageMessage := fmt.Sprintf("Use %s -h to show usage", appName)
+	if preCfg.ShowVersion {
+		fmt.Println(appName, "version", version.Full())
+		os.Exit(0)
+	}
+
+	// Load additional config from file.
+	var configFileError error
+	parser := flags.NewParser(&cfg, flags.Default)
+	configFilePath := preCfg.ConfigFile.Value
+	if preCfg.ConfigFile.ExplicitlySet() {
+		configFilePath = cleanAndExpandPath(configFilePath)
+	} else {
+		appDataDir := preCfg.AppDataDir.Value
+		if !preCfg.AppDataDir.ExplicitlySet() && preCfg.DataDir.ExplicitlySet() {
+			appDataDir = cleanAndExpandPath(preCfg.DataDir.Value)
+		}
+		if appDataDir != defaultAppDataDir {
+			configFilePath = filepath.Join(appDataDir, defaultConfigFilename)
+		}
+	}
+	cfg.configFile = configFilePath
+	cfg.args = args
+	err = flags.NewIniParser(parser).ParseFile(configFilePath)
+	if err != nil {
+		if _, ok := err.(*os.PathError); !ok {
+			fmt.Fprintln(os.Stderr, err)
+			parser.WriteHelp(os.Stderr)
+			return nil, nil, err
+		}
+		configFileError = err
+	}
+
+	// Parse command line options again to ensure they take precedence.
+	remainingArgs, err := parser.ParseArgs(args)
+	if err != nil {
+		if e, ok := err.(*flags.Error); !ok || e.Type != flags.ErrHelp {
+			parser.WriteHelp(os.Stderr)
+		}
+		return nil, nil, err
+	}
+	cfg.options = optionValues(parser)
+
+	if cfg.DataDir.ExplicitlySet() {
+		fmt.Fprintln(os.Stderr, "datadir option has been replaced by "+
+			"appdata -- please update your config")
+	}
+	if err := initConfig(&cfg); err != nil {
+		err := fmt.Errorf("%s: %v", funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	if (cfg.DumpCfg || cfg.ValidateCfg) &&
+		(cfg.Create || cfg.CreateTemp || cfg.ChangeWalletPass) {
+
+		err := fmt.Errorf("%s: the flags --dumpcfg and --validatecfg "+
+			"can not be used with --create, --createtemp or "+
+			"--changewalletpass", funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// Write the configuration and exit if requested.  This is done before
+	// any option is adjusted for the active network so the output can be
+	// used as a config file.
+	if cfg.DumpCfg {
+		dumpConfig(os.Stdout, parser, false)
+		os.Exit(0)
+	}
+
+	// Append the network type to the log directory so it is "namespaced"
+	// per network.
+	cfg.LogDir = cleanAndExpandPath(cfg.LogDir)
+	cfg.LogDir = filepath.Join(cfg.LogDir, cfg.activeNet.Params.Name)
+
+	// Special show command to list supported subsystems and exit.
+	if cfg.DebugLevel == "show" {
+		fmt.Println("Supported subsystems", supportedSubsystems())
+		os.Exit(0)
+	}
+
+	// Select the log format before anything is logged.
+	switch cfg.LogFormat {
+	case "text":
+	case "json":
+		logJSON = true
+	default:
+		str := "%s: the specified log format [%v] is invalid"
+		err := fmt.Errorf(str, funcName, cfg.LogFormat)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// Select the log targets.  Without any, log to both the log file and
+	// standard output.
+	logTargets := cfg.LogTargets
+	if len(logTargets) == 0 {
+		logTargets = []string{"file", "stdout"}
+	}
+	logToFile := false
+	logToStdout = false
+	for _, target := range logTargets {
+		switch target {
+		case "file":
+			logToFile = true
+		case "stdout":
+			logToStdout = true
+		case "syslog":
+			if logSyslog != nil {
+				continue
+			}
+			logSyslog, err = newSyslogWriter(cfg.SyslogFacility,
+				"lbcwallet")
+			if err != nil {
+				err := fmt.Errorf("%s: unable to log to syslog: %v",
+					funcName, err)
+				fmt.Fprintln(os.Stderr, err)
+				return nil, nil, err
+			}
+		default:
+			str := "%s: the specified log target [%v] is invalid"
+			err := fmt.Errorf(str, funcName, target)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+	}
+
+	// Initialize log rotation.  After log rotation has been initialized, the
+	// logger variables may be used.  Validating the config never writes a
+	// log file.
+	if logToFile && !cfg.ValidateCfg {
+		initLogRotator(filepath.Join(cfg.LogDir, defaultLogFilename))
+	}
+
+	// Parse, validate, and set debug log level(s).
+	if err := parseAndSetDebugLevels(cfg.DebugLevel); err != nil {
+		err := fmt.Errorf("%s: %v", "LoadConfig", err.Error())
+		fmt.Fprintln(os.Stderr, err)
+		parser.WriteHelp(os.Stderr)
+		return nil, nil, err
+	}
+
+	// The backup keys are handled without any wallet.
+	if cfg.GenBackupKey {
+		if err := genBackupKey(); err != nil {
+			fmt.Fprintln(os.Stderr, "Unable to generate backup key:",
+				err)
+			return nil, nil, err
+		}
+		os.Exit(0)
+	}
+	if cfg.DecryptBackup != "" {
+		path := cleanAndExpandPath(cfg.DecryptBackup)
+		if err := decryptBackup(path); err != nil {
+			fmt.Fprintln(os.Stderr, "Unable to decrypt backup:", err)
+			return nil, nil, err
+		}
+		os.Exit(0)
+	}
+
+	if cfg.CreateTemp {
+		errMsg := "Tried to create a temporary simulation wallet"
+		// Exit if you try to use a simulation wallet with a standard
+		// data directory.
+		if !(cfg.AppDataDir.ExplicitlySet() || cfg.DataDir.ExplicitlySet()) {
+			errMsg += ", but failed to specify data directory!"
+			fmt.Fprintln(os.Stderr, errMsg)
+			os.Exit(0)
+		}
+
+		// Exit if you try to use a simulation wallet on anything other than
+		// regtest or testnet3.
+		if !cfg.activeNet.TestNet {
+			errMsg += "for network other than regtest, or testnet3"
+			fmt.Fprintln(os.Stderr, errMsg)
+			os.Exit(0)
+		}
+	}
+
+	if cfg.CreateFixture != "" {
+		fixture, err := parseFixture(cfg.CreateFixture)
+		switch {
+		case err != nil:
+		case !(cfg.AppDataDir.ExplicitlySet() ||
+			cfg.DataDir.ExplicitlySet()):
+
+			err = fmt.Errorf("the data directory must be specified")
+		case !cfg.activeNet.TestNet:
+			err = fmt.Errorf("fixture wallets are only created on " +
+				"regtest or testnet3")
+		default:
+			err = createFixtureWallet(&cfg, fixture)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Unable to create the fixture "+
+				"wallet:", err)
+			return nil, nil, err
+		}
+		os.Exit(0)
+	}
+	if cfg.ReplayTxLog != "" {
+		path := cleanAndExpandPath(cfg.ReplayTxLog)
+		if err := replayTxLog(&cfg, path); err != nil {
+			fmt.Fprintln(os.Stderr, "Unable to replay the "+
+				"transaction log:", err)
+			return nil, nil, err
+		}
+		os.Exit(0)
+	}
+
+	// Ensure the wallet exists or create it when the create flag is set.
+	netDir := networkDir(cfg.AppDataDir.Value, cfg.activeNet)
+	dbPath := filepath.Join(netDir, wallet.WalletDBName)
+
+	if cfg.CreateTemp && cfg.Create {
+		err := fmt.Errorf("the flags --create and --createtemp can not " +
+			"be specified together. Use --help for more information")
+		fmt.Fprintln(os.Stderr, err)
+		return nil, nil, err
+	}
+
+	if cfg.CreateFromDump != "" && !cfg.Create {
+		err := fmt.Errorf("the flag --createfromdump can only be " +
+			"specified with --create")
+		fmt.Fprintln(os.Stderr, err)
+		return nil, nil, err
+	}
+
+	if cfg.RecoverXPubs != "" {
+		var err error
+		switch {
+		case !cfg.Create:
+			err = fmt.Errorf("the flag --recoverxpubs can only be " +
+				"specified with --create")
+		case cfg.CreateFromDump != "":
+			err = fmt.Errorf("the flags --recoverxpubs and " +
+				"--createfromdump can not be specified together")
+		case cfg.RecoverBirthday == "":
+			err = fmt.Errorf("the flag --recoverxpubs requires " +
+				"--recoverbirthday")
+		default:
+			_, err = time.Parse(recoverBirthdayLayout,
+				cfg.RecoverBirthday)
+			if err != nil {
+				err = fmt.Errorf("invalid --recoverbirthday: %v",
+					err)
+			}
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return nil, nil, err
+		}
+	}
+
+	if cfg.CreateFromShares != "" {
+		var err error
+		switch {
+		case !cfg.Create:
+			err = fmt.Errorf("the flag --createfromshares can only " +
+				"be specified with --create")
+		case cfg.CreateFromDump != "" || cfg.RecoverXPubs != "":
+			err = fmt.Errorf("the flag --createfromshares can not " +
+				"be specified with --createfromdump or " +
+				"--recoverxpubs")
+		case cfg.RecoverBirthday != "":
+			_, err = time.Parse(recoverBirthdayLayout,
+				cfg.RecoverBirthday)
+			if err != nil {
+				err = fmt.Errorf("invalid --recoverbirthday: %v",
+					err)
+			}
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return nil, nil, err
+		}
+	}
+
+	if cfg.SeedShares != "" {
+		var err error
+		switch {
+		case !cfg.Create:
+			err = fmt.Errorf("the flag --seedshares can only be " +
+				"specified with --create")
+		case cfg.CreateFromDump != "" || cfg.RecoverXPubs != "" ||
+			cfg.CreateFromShares != "":
+			err = fmt.Errorf("the flag --seedshares can not be " +
+				"specified with --createfromdump, --recoverxpubs " +
+				"or --createfromshares")
+		default:
+			_, _, err = parseSeedShares(cfg.SeedShares)
+			if err != nil {
+				err = fmt.Errorf("invalid --seedshares: %v", err)
+			}
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return nil, nil, err
+		}
+	}
+
+	dbFileExists, err := cfgutil.FileExists(dbPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return nil, nil, err
+	}
+
+	if cfg.CreateTemp { // nolint:gocritic
+		tempWalletExists := false
+
+		if dbFileExists {
+			str := fmt.Sprintf("The wallet already exists. Loading this " +
+				"wallet instead.")
+			fmt.Fprintln(os.Stdout, str)
+			tempWalletExists = true
+		}
+
+		// Ensure the data directory for the network exists.
+		if err := checkCreateDir(netDir); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return nil, nil, err
+		}
+
+		if !tempWalletExists {
+			// Perform the initial wallet creation wizard.
+			if err := createSimulationWallet(&cfg); err != nil {
+				fmt.Fprintln(os.Stderr, "Unable to create wallet:", err)
+				return nil, nil, err
+			}
+		}
+	} else if cfg.Create {
+		// Create the default wallet and the named wallets which do
+		// not exist yet.  Error if the create flag is set and every
+		// wallet already exists.
+		var dbDirs []string
+		if !dbFileExists {
+			dbDirs = append(dbDirs, netDir)
+		}
+		for _, name := range cfg.Wallets {
+			dir := namedWalletDir(netDir, name)
+			exists, err := cfgutil.FileExists(
+				filepath.Join(dir, wallet.WalletDBName),
+			)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return nil, nil, err
+			}
+			if !exists {
+				dbDirs = append(dbDirs, dir)
+			}
+		}
+		if len(dbDirs) == 0 {
+			err := fmt.Errorf("the wallet database file `%v` "+
+				"already exists", dbPath)
+			if len(cfg.Wallets) != 0 {
+				err = fmt.Errorf("the default and named " +
+					"wallets already exist")
+			}
+			fmt.Fprintln(os.Stderr, err)
+			return nil, nil, err
+		}
+		if cfg.RecoverXPubs != "" && len(dbDirs) != 1 {
+			err := fmt.Errorf("the flag --recoverxpubs creates " +
+				"a single wallet, but several do not exist")
+			fmt.Fprintln(os.Stderr, err)
+			return nil, nil, err
+		}
+		if cfg.CreateFromShares != "" && len(dbDirs) != 1 {
+			err := fmt.Errorf("the flag --createfromshares creates " +
+				"a single wallet, but several do not exist")
+			fmt.Fprintln(os.Stderr, err)
+			return nil, nil, err
+		}
+		if cfg.CreateFromDump != "" && len(dbDirs) != 1 {
+			err := fmt.Errorf("the flag --createfromdump creates " +
+				"a single wallet, but several do not exist")
+			fmt.Fprintln(os.Stderr, err)
+			return nil, nil, err
+		}
+
+		for _, dir := range dbDirs {
+			// Ensure the data directory of the wallet exists.
+			if err := checkCreateDir(dir); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return nil, nil, err
+			}
+
+			// Perform the initial wallet creation wizard.
+			if err := createWallet(&cfg, dir); err != nil {
+				fmt.Fprintln(os.Stderr, "Unable to create "+
+					"wallet:", err)
+				return nil, nil, err
+			}
+		}
+
+		// Created successfully, so exit now with success.
+		os.Exit(0)
+	} else if cfg.ChangeWalletPass {
+		if !dbFileExists {
+			err := fmt.Errorf("the wallet database file `%v` "+
+				"does not exist", dbPath)
+			fmt.Fprintln(os.Stderr, err)
+			return nil, nil, err
+		}
+
+		if err := changeWalletPass(&cfg); err != nil {
+			fmt.Fprintln(os.Stderr, "Unable to change public "+
+				"passphrase:", err)
+			return nil, nil, err
+		}
+
+		os.Exit(0)
+	}
+
+	if err := checkConfig(&cfg); err != nil {
+		err := fmt.Errorf("%s: %v", funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// Report the result of the extended checks and exit if the config
+	// is only validated.
+	if cfg.ValidateCfg {
+		if configFileError != nil && preCfg.ConfigFile.ExplicitlySet() {
+			fmt.Fprintf(os.Stderr, "error: %v\n", configFileError)
+			return nil, nil, configFileError
+		}
+		if err := validateConfig(os.Stderr, &cfg); err != nil {
+			err := fmt.Errorf("%s: %v", funcName, err)
+			fmt.Fprintln(os.Stderr, err)
+			return nil, nil, err
+		}
+		if configFileError != nil {
+			fmt.Println("No config file found, the defaults and " +
+				"command line options are valid")
+		} else {
+			fmt.Printf("Config file %s is valid\n", configFilePath)
+		}
+		os.Exit(0)
+	}
+
+	// Write a debug info bundle and exit if requested.
+	if cfg.CollectDebugInfo {
+		path, err := collectDebugInfo(&cfg)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Unable to collect debug info:",
+				err)
+			return nil, nil, err
+		}
+		fmt.Printf("Debug info written to %s\n", path)
+		os.Exit(0)
+	}
+
+	// Warn about missing config file after the final command line parse
+	// succeeds.  This prevents the warning on help messages and invalid
+	// options.
+	if configFileError != nil {
+		log.Warnf("%v", configFileError)
+	}
+
+	return &cfg, remainingArgs, nil
+}
+
+// initConfig adjusts the options of cfg for its data directory and selects
+// the active network, and validates the options needed to create wallets.
+func initConfig(cfg *Config) error {
+	// Check deprecated aliases.  The new options receive priority when both
+	// are changed from the default.
+	if cfg.DataDir.ExplicitlySet() && !cfg.AppDataDir.ExplicitlySet() {
+		cfg.AppDataDir.Value = cfg.DataDir.Value
+	}
+
+	// If an alternate data directory was specified, and paths with defaults
+	// relative to the data dir are unchanged, modify each path to be
+	// relative to the new data dir.
+	if cfg.AppDataDir.ExplicitlySet() {
+		cfg.AppDataDir.Value = cleanAndExpandPath(cfg.AppDataDir.Value)
+		if !cfg.RPCKey.ExplicitlySet() {
+			cfg.RPCKey.Value = filepath.Join(
+				cfg.AppDataDir.Value, "rpc.key",
+			)
+		}
+		if !cfg.RPCCert.ExplicitlySet() {
+			cfg.RPCCert.Value = filepath.Join(
+				cfg.AppDataDir.Value, "rpc.cert",
+			)
+		}
+	}
+
+	// Choose the active network params based on the selected network.
+	// Multiple networks can't be selected simultaneously.
+	cfg.activeNet = &netparams.MainNetParams
+	numNets := 0
+	if cfg.TestNet3 {
+		cfg.activeNet = &netparams.TestNet3Params
+		numNets++
+	}
+	if cfg.Regtest {
+		cfg.activeNet = &netparams.RegTestParams
+		numNets++
+	}
+	if cfg.Network != "" {
+		net := netparams.ByName(cfg.Network)
+		if net == nil {
+			return fmt.Errorf("unknown network %q, which "+
+				"must be one of %s", cfg.Network,
+				strings.Join(netparams.Names(), ", "))
+		}
+		if net != cfg.activeNet {
+			cfg.activeNet = net
+			numNets++
+		}
+	}
+	if numNets > 1 {
+		return errors.New("more than one networks has been specified")
+	}
+
+	if cfg.RegtestHarness && !cfg.activeNet.Regtest {
+		return errors.New("the regtest-harness option requires " +
+			"--regtest")
+	}
+	if cfg.MiningAccount != "" && !cfg.activeNet.Regtest {
+		return errors.New("the miningaccount option requires " +
+			"--regtest")
+	}
+	if cfg.Faucet != "" && !cfg.activeNet.TestNet {
+		return errors.New("the faucet option requires --testnet " +
+			"or --regtest")
+	}
+
+	// Ensure the named wallets are distinct and can be used as directory
+	// names.
+	walletNames := make(map[string]struct{}, len(cfg.Wallets))
+	for _, name := range cfg.Wallets {
+		if err := checkWalletName(name); err != nil {
+			return err
+		}
+		if _, ok := walletNames[name]; ok {
+			return fmt.Errorf("wallet %q is specified more "+
+				"than once", name)
+		}
+		walletNames[name] = struct{}{}
+	}
+
+	return nil
+}
+
+// checkConfig validates and normalizes the options of cfg which are not
+// checked by initConfig, and sets the values parsed from them.
+func checkConfig(cfg *Config) error {
+	var err error
+
+	localhostListeners := map[string]struct{}{
+		"localhost": {},
+		"127.0.0.1": {},
+		"::1":       {},
+	}
+
+	if cfg.RPCConnect == "" {
+		cfg.RPCConnect = net.JoinHostPort(
+			"localhost", cfg.activeNet.RPCClientPort,
+		)
+	}
+
+	// Add default port to connect flag if missing.
+	cfg.RPCConnect, err = cfgutil.NormalizeAddress(cfg.RPCConnect,
+		cfg.activeNet.RPCClientPort)
+	if err != nil {
+		return fmt.Errorf("invalid rpcconnect network address: %v", err)
+	}
+
+	RPCHost, _, err := net.SplitHostPort(cfg.RPCConnect)
+	if err != nil {
+		return err
+	}
+
+	// Outbound connections through a proxy may be isolated from each
+	// other, and restricted to onion services so that none of them can
+	// leak over the clearnet.  The webhooks and price endpoints use the
+	// proxy for lbcd, unless they are given their own or none.
+	if cfg.Proxy != "" {
+		cfg.Proxy, err = cfgutil.NormalizeAddress(cfg.Proxy,
+			defaultProxyPort)
+		if err != nil {
+			return fmt.Errorf("invalid proxy network address: %v",
+				err)
+		}
+	}
+	if cfg.TorControl != "" {
+		cfg.TorControl, err = cfgutil.NormalizeAddress(cfg.TorControl,
+			defaultTorControlPort)
+		if err != nil {
+			return fmt.Errorf("invalid torcontrol network "+
+				"address: %v", err)
+		}
+	}
+	if cfg.I2PSAM != "" {
+		cfg.I2PSAM, err = cfgutil.NormalizeAddress(cfg.I2PSAM,
+			defaultI2PSAMPort)
+		if err != nil {
+			return fmt.Errorf("invalid i2psam network address: "+
+				"%v", err)
+		}
+	}
+	if isI2PHost(RPCHost) && cfg.I2PSAM == "" {
+		return fmt.Errorf("rpcconnect to the I2P address %s requires "+
+			"i2psam", RPCHost)
+	}
+	if cfg.VerifyConnect != "" {
+		cfg.VerifyConnect, err = cfgutil.NormalizeAddress(
+			cfg.VerifyConnect, cfg.activeNet.RPCClientPort)
+		if err != nil {
+			return fmt.Errorf("invalid verifyconnect network "+
+				"address: %v", err)
+		}
+		verifyHost, _, _ := net.SplitHostPort(cfg.VerifyConnect)
+		if isI2PHost(verifyHost) {
+			return errors.New("verifyconnect may not be an I2P " +
+				"address")
+		}
+		if cfg.VerifyConnect == cfg.RPCConnect {
+			return errors.New("verifyconnect must be another " +
+				"server than rpcconnect")
+		}
+		if cfg.VerifyInterval <= 0 {
+			return errors.New("verifyinterval must be positive")
+		}
+		if cfg.VerifyUser == "" {
+			cfg.VerifyUser = cfg.RPCUser
+		}
+		if cfg.VerifyPass == "" {
+			cfg.VerifyPass = cfg.RPCPass
+		}
+	} else if cfg.VerifyPauseSends {
+		return errors.New("the verifypausesends option requires " +
+			"verifyconnect")
+	}
+	if cfg.HTTPProxy != "" {
+		if cfg.NoHTTPProxy {
+			return errors.New("the httpproxy and nohttpproxy " +
+				"options can not be used together")
+		}
+		cfg.HTTPProxy, err = cfgutil.NormalizeAddress(cfg.HTTPProxy,
+			defaultProxyPort)
+		if err != nil {
+			return fmt.Errorf("invalid httpproxy network "+
+				"address: %v", err)
+		}
+	}
+	if cfg.TorIsolation {
+		if cfg.Proxy == "" && cfg.HTTPProxy == "" &&
+			cfg.TorControl == "" {
+
+			return errors.New("the torisolation option requires " +
+				"a proxy")
+		}
+		if cfg.ProxyUser != "" || cfg.ProxyPass != "" ||
+			cfg.HTTPProxyUser != "" || cfg.HTTPProxyPass != "" {
+
+			return errors.New("the torisolation option can not " +
+				"be used with proxy credentials")
+		}
+	}
+	switch cfg.OnlyNet {
+	case "":
+	case onlyNetOnion:
+		if cfg.Proxy == "" && cfg.HTTPProxy == "" &&
+			cfg.TorControl == "" {
+
+			return fmt.Errorf("onlynet=%s requires a proxy",
+				onlyNetOnion)
+		}
+		if err := checkOnlyNet(cfg.OnlyNet, RPCHost); err != nil {
+			return fmt.Errorf("invalid rpcconnect: %v", err)
+		}
+		if cfg.VerifyConnect != "" {
+			host, _, _ := net.SplitHostPort(cfg.VerifyConnect)
+			if err := checkOnlyNet(cfg.OnlyNet, host); err != nil {
+				return fmt.Errorf("invalid verifyconnect: %v",
+					err)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown network %q for onlynet, which may "+
+			"only be %q", cfg.OnlyNet, onlyNetOnion)
+	}
+	if !cfg.DisableClientTLS {
+		// If CAFile is unset, choose either the copy or local lbcd cert.
+		if !cfg.CAFile.ExplicitlySet() {
+			cfg.CAFile.Value = filepath.Join(
+				cfg.AppDataDir.Value, defaultCAFilename,
+			)
+
+			// If the CA copy does not exist, check if we're
+			// connecting to a local lbcd and switch to its RPC cert
+			// if it exists.
+			certExists, err := cfgutil.FileExists(cfg.CAFile.Value)
+			if err != nil {
+				return err
+			}
+			if !certExists {
+				if _, ok := localhostListeners[RPCHost]; ok {
+					lbcdCertExists, err := cfgutil.FileExists(
+						lbcdDefaultCAFile)
+					if err != nil {
+						return err
+					}
+					if lbcdCertExists {
+						cfg.CAFile.Value = lbcdDefaultCAFile
+					}
+				}
+			}
+		}
+	}
+
+	if len(cfg.LegacyRPCListeners) == 0 {
+		addrs, err := net.LookupHost("localhost")
+		if err != nil {
+			return err
+		}
+		cfg.LegacyRPCListeners = make([]string, 0, len(addrs))
+		for _, addr := range addrs {
+			addr = net.JoinHostPort(addr,
+				cfg.activeNet.RPCServerPort)
+			cfg.LegacyRPCListeners = append(cfg.LegacyRPCListeners, addr)
+		}
+	}
+
+	// Add default port to all rpc listener addresses if needed and remove
+	// duplicate addresses.
+	cfg.LegacyRPCListeners, err = cfgutil.NormalizeAddresses(
+		cfg.LegacyRPCListeners, cfg.activeNet.RPCServerPort)
+	if err != nil {
+		return fmt.Errorf("invalid network address in legacy RPC "+
+			"listeners: %v", err)
+	}
+
+	cfg.ElectrumListeners, err = cfgutil.NormalizeAddresses(
+		cfg.ElectrumListeners, defaultElectrumPort)
+	if err != nil {
+		return fmt.Errorf("invalid network address in Electrum "+
+			"listeners: %v", err)
+	}
+
+	if cfg.DisableServerTLS {
+		for _, addr := range cfg.LegacyRPCListeners {
+			_, _, err := net.SplitHostPort(addr)
+			if err != nil {
+				return fmt.Errorf("RPC listen interface '%s' "+
+					"is invalid: %v", addr, err)
+			}
+		}
+	}
+
+	// Validate the TLS options now so a typo is reported before any
+	// listener is started.
+	if !cfg.DisableServerTLS {
+		if _, err := parseTLSVersion(cfg.TLSMinVersion); err != nil {
+			return err
+		}
+		if _, err := parseCipherSuites(cfg.TLSCipherSuites); err != nil {
+			return err
+		}
+	}
+	if cfg.LbcdDialTimeout < 0 || cfg.LbcdHandshakeTimeout < 0 ||
+		cfg.LbcdPingInterval < 0 || cfg.LbcdPongTimeout < 0 {
+
+		return errors.New("lbcddialtimeout, lbcdhandshaketimeout, " +
+			"lbcdpinginterval and lbcdpongtimeout must not be " +
+			"negative")
+	}
+	if cfg.FaultNotifyDelay < 0 || cfg.FaultDropEvery < 0 ||
+		cfg.FaultReorderWindow < 0 {
+
+		return errors.New("faultnotifydelay, faultdropevery and " +
+			"faultreorderwindow must not be negative")
+	}
+	if (cfg.FaultNotifyDelay > 0 || cfg.FaultDropEvery > 0 ||
+		cfg.FaultReorderWindow > 0) && !cfg.activeNet.TestNet {
+
+		return errors.New("faults can only be injected with " +
+			"--testnet or --regtest")
+	}
+	if cfg.RPCMaxRequestSize <= 0 || cfg.RPCMaxMessageSize <= 0 ||
+		cfg.RPCMaxResultItems < 0 {
+
+		return errors.New("rpcmaxrequestsize and rpcmaxwsmessagesize " +
+			"must be positive, and rpcmaxresultitems must not be " +
+			"negative")
+	}
+	if cfg.RPCWSQueueSize <= 0 {
+		return errors.New("rpcwsqueuesize must be positive")
+	}
+	if cfg.ReadyMaxBlockLag < 0 {
+		return errors.New("readymaxblocklag must not be negative")
+	}
+	if cfg.RPCSlowThreshold < 0 {
+		return errors.New("rpcslowthreshold must not be negative")
+	}
+	if cfg.RPCCertValidity <= 0 {
+		return errors.New("rpccertvalidity must be positive")
+	}
+	if cfg.RegenCert && cfg.OneTimeTLSKey {
+		return errors.New("the regen-cert and onetimetlskey " +
+			"options can not be used together")
+	}
+	if cfg.CertPollInterval < 0 {
+		return errors.New("certpollinterval must not be negative")
+	}
+	if cfg.Profile != "" {
+		// A port without a host listens on all interfaces.
+		if _, _, err := net.SplitHostPort(cfg.Profile); err != nil {
+			cfg.Profile = net.JoinHostPort("", cfg.Profile)
+		}
+		if cfg.ProfileAuth && (cfg.RPCUser == "" || cfg.RPCPass == "") {
+			return errors.New("profileauth requires rpcuser and " +
+				"rpcpass to be set")
+		}
+	}
+	if cfg.PriceURL != "" {
+		u, err := url.Parse(cfg.PriceURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return errors.New("priceurl must be an http or https " +
+				"URL")
+		}
+		if err := checkOnlyNet(cfg.OnlyNet, u.Hostname()); err != nil {
+			return fmt.Errorf("invalid priceurl: %v", err)
+		}
+		if cfg.PriceField == "" || cfg.FiatCurrency == "" {
+			return errors.New("priceurl requires pricefield and " +
+				"fiatcurrency to be set")
+		}
+	}
+	if cfg.Faucet != "" {
+		u, err := url.Parse(cfg.Faucet)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return errors.New("faucet must be an http or https URL")
+		}
+		if err := checkOnlyNet(cfg.OnlyNet, u.Hostname()); err != nil {
+			return fmt.Errorf("invalid faucet: %v", err)
+		}
+	}
+	for _, endpoint := range zmqEndpoints(cfg) {
+		if err := checkZMQEndpoint(endpoint); err != nil {
+			return err
+		}
+	}
+	cfg.rpcApprovers = make(map[string]string, len(cfg.RPCApprovers))
+	for _, approver := range cfg.RPCApprovers {
+		username, password, ok := strings.Cut(approver, ":")
+		if !ok || username == "" || password == "" ||
+			username == cfg.RPCUser {
+
+			return errors.New("rpcapprover must be a username " +
+				"other than rpcuser and a password, " +
+				"separated by a colon")
+		}
+		cfg.rpcApprovers[username] = password
+	}
+	if err := checkReloadableOptions(cfg); err != nil {
+		return err
+	}
+	if cfg.BroadcastIsolation && cfg.Proxy == "" && cfg.TorControl == "" {
+		return errors.New("broadcastisolation requires a proxy")
+	}
+	if cfg.BroadcastIsolation && isI2PHost(RPCHost) {
+		return errors.New("broadcastisolation can not be used " +
+			"when lbcd is connected to over I2P")
+	}
+	if cfg.ShutdownTimeout < 0 {
+		return errors.New("shutdowntimeout must not be negative")
+	}
+
+	// Backups are encrypted to the public key, and written either to a
+	// directory or to an S3-compatible bucket.
+	if cfg.BackupKey != "" {
+		cfg.backupKey, err = parseBackupKey(cfg.BackupKey)
+		if err != nil {
+			return err
+		}
+	}
+	switch {
+	case cfg.BackupS3 != "" && cfg.BackupDir != "":
+		return errors.New("the backupdir and backups3 options can " +
+			"not be used together")
+
+	case cfg.BackupS3 != "":
+		if cfg.BackupS3AccessKey == "" || cfg.BackupS3SecretKey == "" {
+			return errors.New("backups3 requires " +
+				"backups3accesskey and backups3secretkey to " +
+				"be set")
+		}
+		store, err := newS3Store(cfg.BackupS3, cfg.BackupS3Region,
+			cfg.BackupS3AccessKey, cfg.BackupS3SecretKey,
+			newHTTPClient(cfg, s3Timeout))
+		if err != nil {
+			return err
+		}
+		host := store.endpoint.Hostname()
+		if err := checkOnlyNet(cfg.OnlyNet, host); err != nil {
+			return fmt.Errorf("invalid backups3: %v", err)
+		}
+		cfg.backupStore = store
+
+	default:
+		if cfg.BackupDir == "" {
+			cfg.BackupDir = filepath.Join(cfg.AppDataDir.Value,
+				"backups")
+		}
+		cfg.BackupDir = cleanAndExpandPath(cfg.BackupDir)
+		cfg.backupStore = wallet.BackupDir(cfg.BackupDir)
+	}
+
+	// Expand environment variable and leading ~ for filepaths.
+	cfg.CAFile.Value = cleanAndExpandPath(cfg.CAFile.Value)
+	if cfg.VerifyCAFile == "" {
+		cfg.VerifyCAFile = cfg.CAFile.Value
+	}
+	cfg.VerifyCAFile = cleanAndExpandPath(cfg.VerifyCAFile)
+	cfg.RPCCert.Value = cleanAndExpandPath(cfg.RPCCert.Value)
+	cfg.RPCKey.Value = cleanAndExpandPath(cfg.RPCKey.Value)
+
+	return nil
+}
@@ -0,0 +1,355 @@
+package walletd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	flags "github.com/jessevdk/go-flags"
+	"github.com/lbryio/lbcwallet/internal/cfgutil"
+	"github.com/lbryio/lbcwallet/wallet"
+)
+
+// noDumpOptions are the options which select an action rather than
+// configure the daemon, and are therefore not written by dumpConfig.
+var noDumpOptions = map[string]struct{}{
+	"configfile":       {},
+	"version":          {},
+	"create":           {},
+	"createtemp":       {},
+	"createfixture":    {},
+	"replaytxlog":      {},
+	"changewalletpass": {},
+	"dumpcfg":          {},
+	"validatecfg":      {},
+	"collectdebuginfo": {},
+	"datadir":          {},
+	"help":             {},
+}
+
+// certExpiryWarning is how long before the RPC certificate expires
+// validateConfig starts warning about it.
+const certExpiryWarning = 30 * 24 * time.Hour
+
+// redactedOptions are the options whose values are redacted from debug info
+// bundles, along with the passwords, which are masked in the help.
+var redactedOptions = map[string]struct{}{
+	"rpcuser":           {},
+	"proxyuser":         {},
+	"httpproxyuser":     {},
+	"verifyuser":        {},
+	"backups3accesskey": {},
+}
+
+// redactedValue replaces the values of redacted options.
+const redactedValue = "<redacted>"
+
+// dumpConfig writes every option of the parser in the config file format,
+// each preceded by its description.  Options which are unset, empty or false
+// are written commented out.  The values of passwords and other credentials
+// are replaced by redactedValue when redact is true.
+func dumpConfig(w io.Writer, parser *flags.Parser, redact bool) {
+	fmt.Fprintln(w, "[Application Options]")
+	for _, group := range parser.Groups() {
+		for _, option := range group.Options() {
+			if _, ok := noDumpOptions[option.LongName]; ok {
+				continue
+			}
+
+			fmt.Fprintln(w)
+			for _, line := range wrapComment(option.Description, 78) {
+				fmt.Fprintf(w, "; %s\n", line)
+			}
+			lines := optionLines(option)
+			if redact && isRedactedOption(option) {
+				for i, line := range lines {
+					if !strings.HasPrefix(line, ";") {
+						lines[i] = option.LongName + "=" +
+							redactedValue
+					}
+				}
+			}
+			for _, line := range lines {
+				fmt.Fprintln(w, line)
+			}
+		}
+	}
+}
+
+// isRedactedOption returns whether the value of option is a password or
+// another credential.
+func isRedactedOption(option *flags.Option) bool {
+	if option.DefaultMask == "-" {
+		return true
+	}
+	_, ok := redactedOptions[option.LongName]
+	return ok
+}
+
+// optionLines returns the config file lines setting option to its current
+// value.
+func optionLines(option *flags.Option) []string {
+	name := option.LongName
+	switch v := option.Value().(type) {
+	case bool:
+		if v {
+			return []string{name + "=1"}
+		}
+		return []string{"; " + name + "=1"}
+	case []string:
+		if len(v) == 0 {
+			return []string{"; " + name + "="}
+		}
+		lines := make([]string, 0, len(v))
+		for _, s := range v {
+			lines = append(lines, name+"="+s)
+		}
+		return lines
+	case *cfgutil.ExplicitString:
+		return valueLine(name, v.Value)
+	case string:
+		return valueLine(name, v)
+	default:
+		return []string{fmt.Sprintf("%s=%v", name, v)}
+	}
+}
+
+func valueLine(name, value string) []string {
+	if value == "" {
+		return []string{"; " + name + "="}
+	}
+	return []string{name + "=" + value}
+}
+
+// wrapComment splits text into lines no longer than width where possible.
+func wrapComment(text string, width int) []string {
+	var lines []string
+	var line string
+	for _, word := range strings.Fields(text) {
+		switch {
+		case line == "":
+			line = word
+		case len(line)+1+len(word) > width:
+			lines = append(lines, line)
+			line = word
+		default:
+			line += " " + word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// configProblems collects the errors and warnings found by validateConfig.
+type configProblems struct {
+	errors   []string
+	warnings []string
+}
+
+func (p *configProblems) errorf(format string, args ...interface{}) {
+	p.errors = append(p.errors, fmt.Sprintf(format, args...))
+}
+
+func (p *configProblems) warnf(format string, args ...interface{}) {
+	p.warnings = append(p.warnings, fmt.Sprintf(format, args...))
+}
+
+// validateConfig performs the checks of a loaded config which would
+// otherwise only fail once the daemon is starting: listen and connect
+// addresses, the data and log directories, the wallet database, and the TLS
+// certificates.  All problems are written to w, and an error is returned if
+// any of them would prevent the daemon from starting.
+func validateConfig(w io.Writer, cfg *Config) error {
+	var p configProblems
+
+	for _, addr := range cfg.LegacyRPCListeners {
+		checkAddress(&p, "rpclisten", addr, 1)
+	}
+	checkAddress(&p, "rpcconnect", cfg.RPCConnect, 1)
+	if cfg.VerifyConnect != "" {
+		checkAddress(&p, "verifyconnect", cfg.VerifyConnect, 1)
+	}
+	if cfg.TorControl != "" {
+		checkAddress(&p, "torcontrol", cfg.TorControl, 1)
+	}
+	if cfg.I2PSAM != "" {
+		checkAddress(&p, "i2psam", cfg.I2PSAM, 1)
+	}
+	if cfg.Profile != "" {
+		checkAddress(&p, "profile", cfg.Profile, 1024)
+	}
+
+	checkDir(&p, "appdata", cfg.AppDataDir.Value)
+	if len(cfg.LogTargets) == 0 || containsString(cfg.LogTargets, "file") {
+		checkDir(&p, "logdir", cfg.LogDir)
+	}
+	netDir := networkDir(cfg.AppDataDir.Value, cfg.activeNet)
+	dbPath := filepath.Join(netDir, wallet.WalletDBName)
+	if exists, err := cfgutil.FileExists(dbPath); err != nil {
+		p.errorf("wallet database %s: %v", dbPath, err)
+	} else if !exists {
+		p.warnf("wallet database %s does not exist -- create it "+
+			"with --create", dbPath)
+	}
+	for _, name := range cfg.Wallets {
+		dbPath := filepath.Join(namedWalletDir(netDir, name),
+			wallet.WalletDBName)
+		if exists, err := cfgutil.FileExists(dbPath); err != nil {
+			p.errorf("wallet %q database %s: %v", name, dbPath, err)
+		} else if !exists {
+			p.warnf("wallet %q database %s does not exist -- create "+
+				"it with --create", name, dbPath)
+		}
+	}
+
+	if !cfg.DisableServerTLS {
+		checkServerTLS(&p, cfg)
+	}
+	if !cfg.DisableClientTLS {
+		checkCAFile(&p, cfg.CAFile.Value)
+		if cfg.VerifyConnect != "" &&
+			cfg.VerifyCAFile != cfg.CAFile.Value {
+
+			checkCAFile(&p, cfg.VerifyCAFile)
+		}
+	}
+
+	for _, warning := range p.warnings {
+		fmt.Fprintf(w, "warning: %s\n", warning)
+	}
+	for _, e := range p.errors {
+		fmt.Fprintf(w, "error: %s\n", e)
+	}
+	if len(p.errors) != 0 {
+		return fmt.Errorf("configuration has %d error(s)", len(p.errors))
+	}
+	return nil
+}
+
+// checkAddress checks that addr is a host and port with a port no lower
+// than minPort.
+func checkAddress(p *configProblems, option, addr string, minPort int) {
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		p.errorf("%s address %q is invalid: %v", option, addr, err)
+		return
+	}
+	checkPort(p, option, port, minPort)
+}
+
+// checkPort checks that port is a number between minPort and 65535.
+func checkPort(p *configProblems, option, port string, minPort int) {
+	n, err := strconv.Atoi(port)
+	if err != nil || n < minPort || n > 65535 {
+		p.errorf("%s port %q must be between %d and 65535", option,
+			port, minPort)
+	}
+}
+
+// checkDir checks that path is a directory, or that the nearest existing
+// parent is a directory it can be created in.
+func checkDir(p *configProblems, option, path string) {
+	fi, err := os.Stat(path)
+	switch {
+	case err == nil && !fi.IsDir():
+		p.errorf("%s %s is not a directory", option, path)
+	case err == nil:
+	case os.IsNotExist(err):
+		parent := filepath.Dir(path)
+		for parent != filepath.Dir(parent) {
+			if _, err := os.Stat(parent); err == nil {
+				break
+			}
+			parent = filepath.Dir(parent)
+		}
+		if fi, err := os.Stat(parent); err != nil || !fi.IsDir() {
+			p.errorf("%s %s can not be created", option, path)
+			return
+		}
+		p.warnf("%s %s does not exist and will be created", option,
+			path)
+	default:
+		p.errorf("%s %s: %v", option, path, err)
+	}
+}
+
+// checkServerTLS checks the RPC server certificate and key the same way
+// openRPCKeyPair uses them, and warns when the certificate expires soon.
+func checkServerTLS(p *configProblems, cfg *Config) {
+	_, err := os.Stat(cfg.RPCKey.Value)
+	keyExists := !os.IsNotExist(err)
+	switch {
+	case cfg.OneTimeTLSKey && keyExists:
+		p.errorf("one time TLS keys are enabled, but TLS key %s "+
+			"already exists", cfg.RPCKey.Value)
+		return
+	case cfg.OneTimeTLSKey:
+		return
+	case !keyExists:
+		p.warnf("rpckey %s does not exist -- a new certificate and "+
+			"key will be generated", cfg.RPCKey.Value)
+		return
+	case cfg.RegenCert:
+		return
+	}
+
+	cert, err := tls.LoadX509KeyPair(cfg.RPCCert.Value, cfg.RPCKey.Value)
+	if err != nil {
+		p.errorf("unable to load RPC certificate %s and key %s: %v",
+			cfg.RPCCert.Value, cfg.RPCKey.Value, err)
+		return
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		p.errorf("unable to parse RPC certificate %s: %v",
+			cfg.RPCCert.Value, err)
+		return
+	}
+	now := time.Now()
+	switch {
+	case now.After(leaf.NotAfter):
+		p.errorf("RPC certificate %s expired on %v", cfg.RPCCert.Value,
+			leaf.NotAfter)
+	case now.Add(certExpiryWarning).After(leaf.NotAfter):
+		p.warnf("RPC certificate %s expires on %v", cfg.RPCCert.Value,
+			leaf.NotAfter)
+	}
+	for _, host := range missingCertHosts(leaf, cfg.RPCCertHosts) {
+		p.warnf("RPC certificate %s is not valid for rpccerthost %s "+
+			"-- replace it with --regen-cert", cfg.RPCCert.Value, host)
+	}
+}
+
+// checkCAFile checks that the certificates used to authenticate lbcd can be
+// read.  A missing file is only a warning as the daemon starts without the
+// chain server connection.
+func checkCAFile(p *configProblems, caFile string) {
+	pem, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		p.warnf("cannot open CA file: %v", err)
+		return
+	}
+	if !x509.NewCertPool().AppendCertsFromPEM(pem) {
+		p.errorf("CA file %s does not contain any PEM certificates",
+			caFile)
+	}
+}
+
+func containsString(strs []string, s string) bool {
+	for _, str := range strs {
+		if str == s {
+			return true
+		}
+	}
+	return false
+}
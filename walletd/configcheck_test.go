@@ -0,0 +1,337 @@
+package walletd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	flags "github.com/jessevdk/go-flags"
+	"github.com/lbryio/lbcwallet/wallet"
+)
+
+// TestValidateConfig ensures the problems which would prevent the daemon
+// from starting are reported as errors, and the others as warnings.
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name string
+
+		// modify changes the valid configuration of a regtest daemon
+		// in dir whose wallet database, certificates and directories
+		// exist.
+		modify func(t *testing.T, cfg *Config, dir string)
+
+		// errors and warnings are substrings of the reported
+		// problems, in order.
+		errors   []string
+		warnings []string
+	}{
+		{
+			name:   "valid",
+			modify: func(*testing.T, *Config, string) {},
+		},
+		{
+			name: "invalid listen port",
+			modify: func(_ *testing.T, cfg *Config, _ string) {
+				cfg.LegacyRPCListeners = []string{
+					"127.0.0.1:70000",
+				}
+			},
+			errors: []string{`rpclisten port "70000"`},
+		},
+		{
+			name: "connect address without port",
+			modify: func(_ *testing.T, cfg *Config, _ string) {
+				cfg.RPCConnect = "localhost"
+			},
+			errors: []string{`rpcconnect address "localhost"`},
+		},
+		{
+			name: "privileged profile port",
+			modify: func(_ *testing.T, cfg *Config, _ string) {
+				cfg.Profile = "localhost:80"
+			},
+			errors: []string{`profile port "80" must be between ` +
+				`1024 and 65535`},
+		},
+		{
+			name: "log directory is a file",
+			modify: func(t *testing.T, cfg *Config, dir string) {
+				cfg.LogDir = filepath.Join(dir, "logfile")
+				writeTestFile(t, cfg.LogDir, nil, time.Now())
+			},
+			errors: []string{"is not a directory"},
+		},
+		{
+			name: "log directory is created",
+			modify: func(_ *testing.T, cfg *Config, dir string) {
+				cfg.LogDir = filepath.Join(dir, "new", "logs")
+			},
+			warnings: []string{"will be created"},
+		},
+		{
+			name: "log directory unused",
+			modify: func(t *testing.T, cfg *Config, dir string) {
+				cfg.LogTargets = []string{"stdout"}
+				cfg.LogDir = filepath.Join(dir, "logfile")
+				writeTestFile(t, cfg.LogDir, nil, time.Now())
+			},
+		},
+		{
+			name: "missing wallets",
+			modify: func(t *testing.T, cfg *Config, _ string) {
+				netDir := networkDir(cfg.AppDataDir.Value,
+					cfg.activeNet)
+				dbPath := filepath.Join(netDir,
+					wallet.WalletDBName)
+				if err := os.Remove(dbPath); err != nil {
+					t.Fatal(err)
+				}
+				cfg.Wallets = []string{"named"}
+			},
+			warnings: []string{
+				"does not exist -- create it with --create",
+				`wallet "named" database`,
+			},
+		},
+		{
+			name: "one time TLS key already exists",
+			modify: func(_ *testing.T, cfg *Config, _ string) {
+				cfg.OneTimeTLSKey = true
+			},
+			errors: []string{"already exists"},
+		},
+		{
+			name: "missing TLS key",
+			modify: func(t *testing.T, cfg *Config, _ string) {
+				err := os.Remove(cfg.RPCKey.Value)
+				if err != nil {
+					t.Fatal(err)
+				}
+			},
+			warnings: []string{"new certificate and key will be " +
+				"generated"},
+		},
+		{
+			name: "unparsable certificate",
+			modify: func(t *testing.T, cfg *Config, _ string) {
+				writeTestFile(t, cfg.RPCCert.Value,
+					[]byte("invalid"), time.Now())
+			},
+			errors: []string{"unable to load RPC certificate"},
+		},
+		{
+			name: "certificate expires soon",
+			modify: func(t *testing.T, cfg *Config, _ string) {
+				writeTestKeyPair(t, cfg.RPCCert.Value,
+					cfg.RPCKey.Value,
+					time.Now().Add(24*time.Hour),
+					time.Now())
+			},
+			warnings: []string{"expires on"},
+		},
+		{
+			name: "certificate not valid for host",
+			modify: func(_ *testing.T, cfg *Config, _ string) {
+				cfg.RPCCertHosts = []string{"example.com"}
+			},
+			warnings: []string{"not valid for rpccerthost " +
+				"example.com"},
+		},
+		{
+			name: "missing CA file",
+			modify: func(t *testing.T, cfg *Config, _ string) {
+				err := os.Remove(cfg.CAFile.Value)
+				if err != nil {
+					t.Fatal(err)
+				}
+			},
+			warnings: []string{"cannot open CA file"},
+		},
+		{
+			name: "CA file without certificates",
+			modify: func(t *testing.T, cfg *Config, _ string) {
+				writeTestFile(t, cfg.CAFile.Value,
+					[]byte("invalid"), time.Now())
+			},
+			errors: []string{"does not contain any PEM " +
+				"certificates"},
+		},
+		{
+			name: "client TLS disabled",
+			modify: func(t *testing.T, cfg *Config, _ string) {
+				cfg.DisableClientTLS = true
+				writeTestFile(t, cfg.CAFile.Value,
+					[]byte("invalid"), time.Now())
+			},
+		},
+	}
+
+	for _, test := range tests {
+		dir := t.TempDir()
+		cfg := newValidTestConfig(t, dir)
+		test.modify(t, cfg, dir)
+
+		var out bytes.Buffer
+		err := validateConfig(&out, cfg)
+		if (err != nil) != (len(test.errors) != 0) {
+			t.Errorf("%s: got error %v, want %d error(s):\n%s",
+				test.name, err, len(test.errors), out.String())
+			continue
+		}
+
+		var errors, warnings []string
+		for _, line := range strings.Split(out.String(), "\n") {
+			switch {
+			case strings.HasPrefix(line, "error: "):
+				errors = append(errors, line)
+			case strings.HasPrefix(line, "warning: "):
+				warnings = append(warnings, line)
+			}
+		}
+		checkProblems(t, test.name, "errors", errors, test.errors)
+		checkProblems(t, test.name, "warnings", warnings,
+			test.warnings)
+	}
+}
+
+// checkProblems ensures each reported problem contains the matching
+// substring of want.
+func checkProblems(t *testing.T, name, kind string, got, want []string) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Errorf("%s: got %d %s, want %d:\n%s", name, len(got), kind,
+			len(want), strings.Join(got, "\n"))
+		return
+	}
+	for i := range got {
+		if !strings.Contains(got[i], want[i]) {
+			t.Errorf("%s: got %q, want it to contain %q", name,
+				got[i], want[i])
+		}
+	}
+}
+
+// newValidTestConfig returns the configuration of a regtest daemon in dir
+// for which validateConfig reports no problems.
+func newValidTestConfig(t *testing.T, dir string) *Config {
+	t.Helper()
+
+	cfg := DefaultConfig()
+	if err := cfg.AppDataDir.UnmarshalFlag(dir); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Regtest = true
+	if err := initConfig(&cfg); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkConfig(&cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	netDir := networkDir(cfg.AppDataDir.Value, cfg.activeNet)
+	if err := os.MkdirAll(netDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(cfg.LogDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	dbPath := filepath.Join(netDir, wallet.WalletDBName)
+	writeTestFile(t, dbPath, nil, time.Now())
+
+	cert := writeTestKeyPair(t, cfg.RPCCert.Value, cfg.RPCKey.Value,
+		time.Now().Add(2*certExpiryWarning), time.Now())
+	writeTestFile(t, cfg.CAFile.Value, cert, time.Now())
+	return &cfg
+}
+
+// TestDumpConfig ensures the dumped options are parsed back to the same
+// configuration, and credentials are only written when not redacted.
+func TestDumpConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.RPCUser, cfg.RPCPass = "user", "secret"
+	cfg.LegacyRPCListeners = []string{"127.0.0.1:1", "127.0.0.1:2"}
+	cfg.KeyPoolSize = 7
+	cfg.Regtest = true
+
+	tests := []struct {
+		redact bool
+		user   string
+		pass   string
+	}{
+		{redact: false, user: "user", pass: "secret"},
+		{redact: true, user: redactedValue, pass: redactedValue},
+	}
+
+	for _, test := range tests {
+		var out bytes.Buffer
+		dumpConfig(&out, flags.NewParser(&cfg, flags.None), test.redact)
+
+		path := filepath.Join(t.TempDir(), "lbcwallet.conf")
+		err := ioutil.WriteFile(path, out.Bytes(), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := DefaultConfig()
+		parser := flags.NewParser(&got, flags.None)
+		err = flags.NewIniParser(parser).ParseFile(path)
+		if err != nil {
+			t.Fatalf("redact %v: dumped config does not parse: %v",
+				test.redact, err)
+		}
+
+		if got.RPCUser != test.user || got.RPCPass != test.pass {
+			t.Errorf("redact %v: got credentials %q, %q, want "+
+				"%q, %q", test.redact, got.RPCUser, got.RPCPass,
+				test.user, test.pass)
+		}
+		if !got.Regtest || got.KeyPoolSize != cfg.KeyPoolSize ||
+			strings.Join(got.LegacyRPCListeners, ",") !=
+				strings.Join(cfg.LegacyRPCListeners, ",") {
+
+			t.Errorf("redact %v: dumped options were not parsed "+
+				"back", test.redact)
+		}
+	}
+}
+
+// TestWrapComment ensures descriptions are wrapped at word boundaries.
+func TestWrapComment(t *testing.T) {
+	tests := []struct {
+		text  string
+		width int
+		want  []string
+	}{
+		{text: "", width: 10},
+		{text: "one", width: 10, want: []string{"one"}},
+		{
+			text:  "one two three four",
+			width: 9,
+			want:  []string{"one two", "three", "four"},
+		},
+		{
+			text:  "  spaced\n\tout  words ",
+			width: 80,
+			want:  []string{"spaced out words"},
+		},
+		{
+			text:  "longerthanwidth word",
+			width: 5,
+			want:  []string{"longerthanwidth", "word"},
+		},
+	}
+
+	for _, test := range tests {
+		got := wrapComment(test.text, test.width)
+		if strings.Join(got, "|") != strings.Join(test.want, "|") ||
+			len(got) != len(test.want) {
+
+			t.Errorf("%q: got %q, want %q", test.text, got,
+				test.want)
+		}
+	}
+}
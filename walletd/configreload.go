@@ -0,0 +1,286 @@
+package walletd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	flags "github.com/jessevdk/go-flags"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/internal/cfgutil"
+	"github.com/lbryio/lbcwallet/rpc/legacyrpc"
+	"github.com/lbryio/lbcwallet/wallet"
+)
+
+// currentConfig returns the configuration of the daemon, including the
+// options reloaded since it started.
+func (d *Daemon) currentConfig() *Config {
+	return d.live.Load()
+}
+
+// reloadableOptions are the options which reloadConfig applies while the
+// daemon is running.  Changes to any other option are reported as requiring
+// a restart.
+var reloadableOptions = map[string]struct{}{
+	"debuglevel":          {},
+	"rpcauthfailures":     {},
+	"rpcauthbantime":      {},
+	"rpcauthexempt":       {},
+	"rpcallowip":          {},
+	"matchchangetype":     {},
+	"splitchange":         {},
+	"broadcastdelay":      {},
+	"sendpreview":         {},
+	"minconfcoinbase":     {},
+	"minconfclaim":        {},
+	"minconfchange":       {},
+	"minconfexternal":     {},
+	"claimaccount":        {},
+	"withdrawalthreshold": {},
+	"withdrawalapprovals": {},
+	"withdrawalexpiry":    {},
+	"withdrawalwindow":    {},
+	"coldsigning":         {},
+	"psbtdir":             {},
+	"keypoolsize":         {},
+	"backupinterval":      {},
+	"backupkeep":          {},
+	"diskwarn":            {},
+	"diskfloor":           {},
+	"diskcheckinterval":   {},
+}
+
+// copyReloadableOptions copies the options listed in reloadableOptions from
+// src to dst.
+func copyReloadableOptions(dst, src *Config) {
+	dst.DebugLevel = src.DebugLevel
+	dst.RPCAuthFailures = src.RPCAuthFailures
+	dst.RPCAuthBanTime = src.RPCAuthBanTime
+	dst.RPCAuthExempt = src.RPCAuthExempt
+	dst.RPCAllowIPs = src.RPCAllowIPs
+	dst.MatchChangeType = src.MatchChangeType
+	dst.SplitChange = src.SplitChange
+	dst.BroadcastDelay = src.BroadcastDelay
+	dst.SendPreview = src.SendPreview
+	dst.MinConfCoinbase = src.MinConfCoinbase
+	dst.MinConfClaim = src.MinConfClaim
+	dst.MinConfChange = src.MinConfChange
+	dst.MinConfExternal = src.MinConfExternal
+	dst.ClaimAccount = src.ClaimAccount
+	dst.WithdrawThreshold = src.WithdrawThreshold
+	dst.WithdrawApprovals = src.WithdrawApprovals
+	dst.WithdrawExpiry = src.WithdrawExpiry
+	dst.WithdrawWindow = src.WithdrawWindow
+	dst.ColdSigning = src.ColdSigning
+	dst.PsbtDir = src.PsbtDir
+	dst.KeyPoolSize = src.KeyPoolSize
+	dst.BackupInterval = src.BackupInterval
+	dst.BackupKeep = src.BackupKeep
+	dst.DiskWarn = src.DiskWarn
+	dst.DiskFloor = src.DiskFloor
+	dst.DiskCheckInterval = src.DiskCheckInterval
+}
+
+// checkReloadableOptions validates the options listed in reloadableOptions,
+// other than debuglevel, and sets the values parsed from them.  The other
+// options of cfg must already be validated.
+func checkReloadableOptions(cfg *Config) error {
+	// Listening on every interface exposes the RPC server to any network
+	// the host is connected to, so the clients it accepts must be listed.
+	var err error
+	cfg.rpcAllowedIPs, err = cfgutil.ParseIPNets(cfg.RPCAllowIPs)
+	if err != nil {
+		return fmt.Errorf("invalid rpcallowip: %v", err)
+	}
+	for _, addr := range cfg.LegacyRPCListeners {
+		if !cfgutil.IsUnspecifiedAddress(addr) ||
+			len(cfg.RPCAllowIPs) != 0 {
+
+			continue
+		}
+		return fmt.Errorf("listening for RPC connections on all "+
+			"interfaces (%s) requires rpcallowip, such as "+
+			"--rpcallowip=0.0.0.0/0 --rpcallowip=::/0 to allow "+
+			"every client", addr)
+	}
+
+	cfg.rpcAuthExemptIPs, err = cfgutil.ParseIPNets(cfg.RPCAuthExempt)
+	if err != nil {
+		return fmt.Errorf("invalid rpcauthexempt: %v", err)
+	}
+	if cfg.RPCAuthFailures < 0 || cfg.RPCAuthBanTime < 0 {
+		return errors.New("rpcauthfailures and rpcauthbantime must " +
+			"not be negative")
+	}
+	if cfg.BroadcastDelay < 0 {
+		return errors.New("broadcastdelay must not be negative")
+	}
+	if cfg.MinConfCoinbase < 0 || cfg.MinConfClaim < 0 ||
+		cfg.MinConfChange < 0 || cfg.MinConfExternal < 0 {
+
+		return errors.New("the minconf options must not be negative")
+	}
+
+	cfg.withdrawalThreshold, err = btcutil.NewAmount(cfg.WithdrawThreshold)
+	if err != nil || cfg.withdrawalThreshold < 0 {
+		return errors.New("withdrawalthreshold must be a " +
+			"non-negative amount")
+	}
+	if cfg.withdrawalThreshold > 0 && (cfg.WithdrawApprovals < 1 ||
+		cfg.WithdrawApprovals > len(cfg.rpcApprovers)) {
+
+		return errors.New("withdrawalapprovals must be between 1 and " +
+			"the number of rpcapprover users")
+	}
+	if cfg.WithdrawExpiry <= 0 {
+		return errors.New("withdrawalexpiry must be positive")
+	}
+	if cfg.WithdrawWindow < 0 {
+		return errors.New("withdrawalwindow must not be negative")
+	}
+	if cfg.KeyPoolSize > wallet.MaxKeyPoolSize {
+		return fmt.Errorf("keypoolsize must be at most %d",
+			wallet.MaxKeyPoolSize)
+	}
+	if cfg.ColdSigning && (cfg.SendPreview || cfg.withdrawalThreshold > 0) {
+		return errors.New("coldsigning can not be used with " +
+			"sendpreview or withdrawalthreshold")
+	}
+	if cfg.PsbtDir != "" {
+		if !cfg.ColdSigning {
+			return errors.New("psbtdir requires coldsigning")
+		}
+		cfg.PsbtDir = cleanAndExpandPath(cfg.PsbtDir)
+		if err := os.MkdirAll(cfg.PsbtDir, 0700); err != nil {
+			return fmt.Errorf("unable to create psbtdir: %v", err)
+		}
+	}
+
+	if cfg.DiskCheckInterval < 0 {
+		return errors.New("diskcheckinterval must not be negative")
+	}
+	if cfg.DiskWarn < cfg.DiskFloor {
+		return errors.New("diskwarn must not be below diskfloor")
+	}
+
+	if cfg.BackupInterval < 0 || cfg.BackupKeep < 0 {
+		return errors.New("backupinterval and backupkeep must not be " +
+			"negative")
+	}
+	if cfg.BackupInterval > 0 && cfg.BackupKey == "" {
+		return errors.New("backupinterval requires backupkey to be set")
+	}
+	return nil
+}
+
+// optionValues returns the values of the options of parser in the config file
+// format, keyed by their long names.
+func optionValues(parser *flags.Parser) map[string]string {
+	values := make(map[string]string)
+	for _, group := range parser.Groups() {
+		for _, option := range group.Options() {
+			if _, ok := noDumpOptions[option.LongName]; ok {
+				continue
+			}
+			lines := optionLines(option)
+			values[option.LongName] = strings.Join(lines, "\n")
+		}
+	}
+	return values
+}
+
+// restartOptions returns the sorted names of the options whose values differ
+// between running and parsed, and which are not reloaded.
+func restartOptions(running, parsed map[string]string) []string {
+	var names []string
+	for name, value := range parsed {
+		if _, ok := reloadableOptions[name]; ok {
+			continue
+		}
+		if running[name] != value {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// reloadConfig reads the config file and command line options again and
+// applies the options listed in reloadableOptions: the debug levels, the RPC
+// authentication limits and allowed networks, and the policies of the loaded
+// wallets, which are set by reloaded.  The changes to any other option are
+// logged as requiring a restart, and keep their value until then.  Invalid
+// options are logged and leave the running configuration untouched.  Nothing
+// is reloaded when the configuration was not loaded by LoadConfig.
+func (d *Daemon) reloadConfig(legacyServer *legacyrpc.Server,
+	reloaded func(*Config)) {
+
+	current := d.currentConfig()
+	if current.configFile == "" {
+		log.Info("Configuration was not loaded from a config file")
+		return
+	}
+
+	newCfg := DefaultConfig()
+	parser := flags.NewParser(&newCfg, flags.None)
+	err := flags.NewIniParser(parser).ParseFile(current.configFile)
+	if err != nil {
+		if _, ok := err.(*os.PathError); !ok {
+			log.Errorf("Unable to reload config file: %v", err)
+			return
+		}
+	}
+
+	// Parse command line options again to ensure they take precedence.
+	if _, err := parser.ParseArgs(current.args); err != nil {
+		log.Errorf("Unable to reload config: %v", err)
+		return
+	}
+	newOptions := optionValues(parser)
+
+	// The options which need a restart keep their running values, against
+	// which the reloaded options are validated.
+	next := *current
+	copyReloadableOptions(&next, &newCfg)
+	if err := checkReloadableOptions(&next); err != nil {
+		log.Errorf("Unable to reload config: %v", err)
+		return
+	}
+	next.options = make(map[string]string, len(current.options))
+	for name, value := range current.options {
+		next.options[name] = value
+	}
+	for name := range reloadableOptions {
+		next.options[name] = newOptions[name]
+	}
+
+	if next.DebugLevel != current.DebugLevel {
+		if err := parseAndSetDebugLevels(next.DebugLevel); err != nil {
+			log.Errorf("Unable to reload debug levels: %v", err)
+			return
+		}
+		log.Infof("Debug levels set to %s", next.DebugLevel)
+	}
+
+	d.live.Store(&next)
+
+	if legacyServer != nil {
+		legacyServer.SetAuthLimits(
+			next.RPCAuthFailures, next.RPCAuthBanTime,
+			next.rpcAuthExemptIPs,
+		)
+		legacyServer.SetAllowedIPs(next.rpcAllowedIPs)
+	}
+	if reloaded != nil {
+		reloaded(&next)
+	}
+
+	names := restartOptions(current.options, newOptions)
+	if len(names) != 0 {
+		log.Warnf("Options %s changed and require a restart to take "+
+			"effect", strings.Join(names, ", "))
+	}
+	log.Infof("Configuration reloaded")
+}
@@ -0,0 +1,103 @@
+package walletd
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	flags "github.com/jessevdk/go-flags"
+)
+
+// loadTestConfig returns a daemon whose running configuration is parsed from
+// the config file holding contents.
+func loadTestConfig(t *testing.T, path, contents string) *Daemon {
+	t.Helper()
+
+	if err := ioutil.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c := DefaultConfig()
+	parser := flags.NewParser(&c, flags.None)
+	if err := flags.NewIniParser(parser).ParseFile(path); err != nil {
+		t.Fatal(err)
+	}
+	c.configFile = path
+	c.options = optionValues(parser)
+	if err := checkReloadableOptions(&c); err != nil {
+		t.Fatal(err)
+	}
+	d := &Daemon{cfg: &c}
+	d.live.Store(&c)
+	return d
+}
+
+func TestReloadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lbcwallet.conf")
+	d := loadTestConfig(t, path, "keypoolsize=10\n"+
+		"rpclisten=127.0.0.1:9244\n")
+	running := d.currentConfig()
+
+	err := ioutil.WriteFile(path, []byte("keypoolsize=20\n"+
+		"rpcallowip=10.0.0.0/8\nrpcauthexempt=127.0.0.1\n"+
+		"rpclisten=127.0.0.1:9245\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var reloaded *Config
+	d.reloadConfig(nil, func(c *Config) { reloaded = c })
+
+	current := d.currentConfig()
+	if reloaded != current || current == running {
+		t.Fatal("reloaded configuration was not published")
+	}
+	if current.KeyPoolSize != 20 {
+		t.Errorf("keypoolsize is %d, want 20", current.KeyPoolSize)
+	}
+	if len(current.rpcAllowedIPs) != 1 ||
+		current.rpcAllowedIPs[0].String() != "10.0.0.0/8" {
+
+		t.Errorf("rpcallowip networks are %v, want [10.0.0.0/8]",
+			current.rpcAllowedIPs)
+	}
+	if len(current.rpcAuthExemptIPs) != 1 ||
+		current.rpcAuthExemptIPs[0].String() != "127.0.0.1/32" {
+
+		t.Errorf("rpcauthexempt networks are %v, want [127.0.0.1/32]",
+			current.rpcAuthExemptIPs)
+	}
+
+	// The options which need a restart keep their running value, and are
+	// reported on every reload until then.
+	want := []string{"127.0.0.1:9244"}
+	if !reflect.DeepEqual(current.LegacyRPCListeners, want) {
+		t.Errorf("rpclisten is %v, want %v",
+			current.LegacyRPCListeners, want)
+	}
+	if running.KeyPoolSize != 10 {
+		t.Error("running configuration was modified")
+	}
+	parsed := DefaultConfig()
+	parser := flags.NewParser(&parsed, flags.None)
+	if err := flags.NewIniParser(parser).ParseFile(path); err != nil {
+		t.Fatal(err)
+	}
+	names := restartOptions(current.options, optionValues(parser))
+	if !reflect.DeepEqual(names, []string{"rpclisten"}) {
+		t.Errorf("options requiring a restart are %v, want "+
+			"[rpclisten]", names)
+	}
+
+	// Invalid options leave the running configuration untouched.
+	err = ioutil.WriteFile(path, []byte("keypoolsize=30\n"+
+		"withdrawalexpiry=0\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.reloadConfig(nil, func(*Config) {
+		t.Error("invalid config reloaded")
+	})
+	if d.currentConfig() != current {
+		t.Error("invalid configuration was published")
+	}
+}
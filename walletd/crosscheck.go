@@ -0,0 +1,330 @@
+package walletd
+
+import (
+	"errors"
+	"expvar"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcd/rpcclient"
+	"github.com/lbryio/lbcwallet/wallet"
+)
+
+const (
+	// crossCheckDepth is the number of blocks below the lower of the best
+	// blocks of the two servers at which their chains are compared, so
+	// that a block still propagating to one of them is not taken for a
+	// divergence.
+	crossCheckDepth = 2
+
+	// crossCheckMaxLag is the number of blocks one server may be behind
+	// the other before a warning is logged.
+	crossCheckMaxLag = 6
+)
+
+// blockDivergence is a block which differs between the chain backend of a
+// wallet and the verifyconnect server.
+type blockDivergence struct {
+	height   int32
+	backend  chainhash.Hash
+	verifier chainhash.Hash
+	synced   bool // The block is the one the wallet is synced to.
+}
+
+func (d *blockDivergence) Error() string {
+	if d.synced {
+		return fmt.Sprintf("the wallet is synced to block %v at height "+
+			"%d, which is %v on the verifyconnect server",
+			d.backend, d.height, d.verifier)
+	}
+	return fmt.Sprintf("block %d is %v on lbcd but %v on the "+
+		"verifyconnect server", d.height, d.backend, d.verifier)
+}
+
+// crossChecker compares the chain of the lbcd server the wallets sync from
+// with that of a second lbcd server, which is only used to verify it: the
+// wallets neither sync from nor send transactions through it.  A divergence
+// is logged as an error, and pauses the sends of the wallets when configured,
+// until the chains agree again.  The counts of the checks are published as
+// the crosscheck expvar variable on the profile server.
+type crossChecker struct {
+	verifier   *rpcclient.Client
+	interval   time.Duration
+	pauseSends bool
+
+	mtx      sync.Mutex
+	wallets  map[*wallet.Wallet]struct{}
+	diverged error  // Divergence found by the last check, or nil.
+	lastErr  string // Error of the last check which failed.
+	lagging  bool
+
+	checks      atomic.Uint64
+	agreements  atomic.Uint64
+	divergences atomic.Uint64
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newCrossChecker connects to the verifyconnect server over HTTP POST, and
+// starts checking the wallets added with add.
+func newCrossChecker(cfg *Config) (*crossChecker, error) {
+	connConfig := &rpcclient.ConnConfig{
+		Host:         cfg.VerifyConnect,
+		Endpoint:     "ws",
+		User:         cfg.VerifyUser,
+		Pass:         cfg.VerifyPass,
+		DisableTLS:   cfg.DisableClientTLS,
+		SkipVerify:   cfg.SkipVerify,
+		HTTPPostMode: true,
+	}
+	if !cfg.DisableClientTLS {
+		certs, err := ioutil.ReadFile(cfg.VerifyCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read verifycafile: %v",
+				err)
+		}
+		connConfig.Certificates = certs
+	}
+
+	// Host names are resolved by the proxy, as for the connection to
+	// lbcd.
+	host, _, _ := net.SplitHostPort(cfg.VerifyConnect)
+	if cfg.Proxy != "" && !isLoopbackHost(host) {
+		u := &url.URL{Scheme: "socks5", Host: cfg.Proxy}
+		if cfg.ProxyUser != "" || cfg.ProxyPass != "" {
+			u.User = url.UserPassword(cfg.ProxyUser, cfg.ProxyPass)
+		}
+		connConfig.Proxy = u.String()
+	}
+	verifier, err := rpcclient.New(connConfig, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &crossChecker{
+		verifier:   verifier,
+		interval:   cfg.VerifyInterval,
+		pauseSends: cfg.VerifyPauseSends,
+		wallets:    make(map[*wallet.Wallet]struct{}),
+		quit:       make(chan struct{}),
+	}
+	expvar.Publish("crosscheck", expvar.Func(func() interface{} {
+		c.mtx.Lock()
+		diverged := c.diverged != nil
+		c.mtx.Unlock()
+		return map[string]interface{}{
+			"checks":      c.checks.Load(),
+			"agreements":  c.agreements.Load(),
+			"divergences": c.divergences.Load(),
+			"diverged":    diverged,
+		}
+	}))
+
+	c.wg.Add(1)
+	go c.run()
+	return c, nil
+}
+
+// add checks the chain backend of a loaded wallet, whose sends are paused at
+// once if the chains have diverged.
+func (c *crossChecker) add(w *wallet.Wallet) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.wallets[w] = struct{}{}
+	if c.diverged != nil && c.pauseSends {
+		w.PauseSends(c.diverged)
+	}
+}
+
+// remove stops checking the chain backend of a wallet being unloaded.
+func (c *crossChecker) remove(w *wallet.Wallet) {
+	c.mtx.Lock()
+	delete(c.wallets, w)
+	c.mtx.Unlock()
+}
+
+// close stops the checks and disconnects from the verifyconnect server.
+func (c *crossChecker) close() {
+	close(c.quit)
+	c.wg.Wait()
+	c.verifier.Shutdown()
+}
+
+func (c *crossChecker) run() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.checkWallets()
+		case <-c.quit:
+			return
+		}
+	}
+}
+
+// checkWallets checks the chain backends of the wallets, and pauses or
+// resumes their sends when the chains diverge or agree again.
+func (c *crossChecker) checkWallets() {
+	c.mtx.Lock()
+	wallets := make([]*wallet.Wallet, 0, len(c.wallets))
+	for w := range c.wallets {
+		wallets = append(wallets, w)
+	}
+	c.mtx.Unlock()
+
+	var diverged, failed error
+	lagging := false
+	checked := false
+	for _, w := range wallets {
+		lag, err := c.check(w)
+		var d *blockDivergence
+		switch {
+		case errors.As(err, &d):
+			diverged = err
+		case err != nil:
+			failed = err
+		default:
+			checked = checked || lag >= 0
+			lagging = lagging || lag > crossCheckMaxLag
+		}
+	}
+	if diverged == nil && !checked {
+		// Nothing could be compared, as the wallets are not
+		// connected or the servers did not answer.
+		if failed != nil {
+			c.logFailure(failed)
+		}
+		return
+	}
+
+	c.checks.Add(1)
+	if diverged != nil {
+		c.divergences.Add(1)
+	} else {
+		c.agreements.Add(1)
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.lastErr = ""
+	if lagging != c.lagging {
+		c.lagging = lagging
+		if lagging {
+			log.Warnf("lbcd and the verifyconnect server are more "+
+				"than %d blocks apart", crossCheckMaxLag)
+		}
+	}
+	switch {
+	case diverged != nil && c.diverged == nil:
+		log.Errorf("The chain of lbcd diverges from the "+
+			"verifyconnect server, lbcd may be compromised: %v",
+			diverged)
+		if c.pauseSends {
+			log.Errorf("Sends are paused until the chains agree " +
+				"again")
+		}
+	case diverged == nil && c.diverged != nil:
+		log.Infof("The chain of lbcd agrees with the verifyconnect " +
+			"server again")
+	}
+	c.diverged = diverged
+	if !c.pauseSends {
+		return
+	}
+	for w := range c.wallets {
+		if diverged != nil {
+			w.PauseSends(diverged)
+		} else {
+			w.ResumeSends()
+		}
+	}
+}
+
+// logFailure logs the error of a check, unless it failed with the same error
+// the previous time.
+func (c *crossChecker) logFailure(err error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if err.Error() != c.lastErr {
+		log.Warnf("Unable to cross-check the chain of lbcd: %v", err)
+		c.lastErr = err.Error()
+	}
+}
+
+// check compares the chain of the chain backend of a wallet with that of the
+// verifyconnect server, and the block the wallet is synced to.  The number of
+// blocks the servers are apart is returned, which is negative when there was
+// nothing to compare.  The error is a *blockDivergence when the chains
+// diverge.
+func (c *crossChecker) check(w *wallet.Wallet) (int32, error) {
+	chainClient := w.ChainClient()
+	if chainClient == nil {
+		return -1, nil
+	}
+	_, height, err := chainClient.GetBestBlock()
+	if err != nil {
+		return -1, fmt.Errorf("lbcd: %v", err)
+	}
+	_, verifierHeight, err := c.verifier.GetBestBlock()
+	if err != nil {
+		return -1, fmt.Errorf("verifyconnect server: %v", err)
+	}
+	lag := height - verifierHeight
+	if lag < 0 {
+		lag = -lag
+	}
+	if verifierHeight < height {
+		height = verifierHeight
+	}
+	height -= crossCheckDepth
+	if height < 0 {
+		return -1, nil
+	}
+
+	hash, err := chainClient.GetBlockHash(int64(height))
+	if err != nil {
+		return -1, fmt.Errorf("lbcd: %v", err)
+	}
+	verifierHash, err := c.verifier.GetBlockHash(int64(height))
+	if err != nil {
+		return -1, fmt.Errorf("verifyconnect server: %v", err)
+	}
+	if *hash != *verifierHash {
+		return lag, &blockDivergence{
+			height:   height,
+			backend:  *hash,
+			verifier: *verifierHash,
+		}
+	}
+
+	synced := w.Manager.SyncedTo()
+	if synced.Height > height {
+		return lag, nil
+	}
+	verifierHash, err = c.verifier.GetBlockHash(int64(synced.Height))
+	if err != nil {
+		return -1, fmt.Errorf("verifyconnect server: %v", err)
+	}
+	if synced.Hash != *verifierHash {
+		return lag, &blockDivergence{
+			height:   synced.Height,
+			backend:  synced.Hash,
+			verifier: *verifierHash,
+			synced:   true,
+		}
+	}
+	return lag, nil
+}
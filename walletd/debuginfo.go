@@ -0,0 +1,247 @@
+package walletd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"time"
+
+	flags "github.com/jessevdk/go-flags"
+	"github.com/lbryio/lbcd/version"
+	"github.com/lbryio/lbcwallet/wallet"
+	"github.com/lbryio/lbcwallet/walletdb"
+)
+
+// debugInfoLogBytes is the size of the tail of the log file included in debug
+// info bundles.
+const debugInfoLogBytes = 4 << 20
+
+// writeDebugInfo writes a gzipped tar bundle of the information useful for
+// bug reports: the version, the config with its credentials redacted, the
+// tail of the log file, the stack traces of all goroutines and the stats of
+// the wallet databases.  The loaded wallets are keyed by name, the default
+// wallet being named by the empty string, and may be nil when no wallet is
+// loaded.
+func writeDebugInfo(w io.Writer, cfg *Config,
+	wallets map[string]*wallet.Wallet) error {
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	now := time.Now()
+	add := func(name string, data []byte) error {
+		hdr := &tar.Header{
+			Name:    name,
+			Mode:    0600,
+			Size:    int64(len(data)),
+			ModTime: now,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "version: %s\n", version.Full())
+	fmt.Fprintf(&buf, "go: %s %s/%s\n", runtime.Version(), runtime.GOOS,
+		runtime.GOARCH)
+	fmt.Fprintf(&buf, "network: %s\n", cfg.activeNet.Params.Name)
+	fmt.Fprintf(&buf, "cpus: %d\n", runtime.NumCPU())
+	fmt.Fprintf(&buf, "goroutines: %d\n", runtime.NumGoroutine())
+	fmt.Fprintf(&buf, "time: %s\n", now.UTC().Format(time.RFC3339))
+	if err := add("version.txt", buf.Bytes()); err != nil {
+		return err
+	}
+
+	buf.Reset()
+	cfgCopy := *cfg
+	dumpConfig(&buf, flags.NewParser(&cfgCopy, flags.None), true)
+	if err := add("lbcwallet.conf", buf.Bytes()); err != nil {
+		return err
+	}
+
+	logTail, err := readLogTail(filepath.Join(cfg.LogDir,
+		defaultLogFilename), debugInfoLogBytes)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return err
+	default:
+		logTail = redactSecrets(logTail, cfg)
+		if err := add(defaultLogFilename, logTail); err != nil {
+			return err
+		}
+	}
+
+	buf.Reset()
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 2); err != nil {
+		return err
+	}
+	if err := add("goroutines.txt", buf.Bytes()); err != nil {
+		return err
+	}
+
+	buf.Reset()
+	writeDBStats(&buf, cfg, wallets)
+	if err := add("dbstats.txt", buf.Bytes()); err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// readLogTail returns the last n bytes of the log file, starting at a line.
+func readLogTail(path string, n int64) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	offset := fi.Size() - n
+	if offset < 0 {
+		offset = 0
+	}
+	b, err := io.ReadAll(io.NewSectionReader(f, offset, fi.Size()-offset))
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 {
+		if i := bytes.IndexByte(b, '\n'); i >= 0 {
+			b = b[i+1:]
+		}
+	}
+	return b, nil
+}
+
+// redactSecrets replaces the values of the redacted options which appear in
+// b, such as credentials logged as part of a URL.
+func redactSecrets(b []byte, cfg *Config) []byte {
+	secrets := []string{cfg.Passphrase, cfg.WalletPass, cfg.RPCUser,
+		cfg.RPCPass, cfg.ProxyUser, cfg.ProxyPass, cfg.TorPassword,
+		cfg.HTTPProxyUser, cfg.HTTPProxyPass, cfg.VerifyUser,
+		cfg.VerifyPass, cfg.BackupS3AccessKey, cfg.BackupS3SecretKey}
+
+	// Longer values are replaced first, so that a value containing
+	// another is not left partially redacted.
+	sort.Slice(secrets, func(i, j int) bool {
+		return len(secrets[i]) > len(secrets[j])
+	})
+	for _, s := range secrets {
+		// Short values would redact unrelated text.
+		if len(s) < 4 {
+			continue
+		}
+		b = bytes.ReplaceAll(b, []byte(s), []byte(redactedValue))
+	}
+	return b
+}
+
+// writeDBStats writes the sizes of the wallet databases, the stats of their
+// transactions and the sync state of the loaded wallets.
+func writeDBStats(w io.Writer, cfg *Config,
+	wallets map[string]*wallet.Wallet) {
+
+	netDir := networkDir(cfg.AppDataDir.Value, cfg.activeNet)
+	paths := map[string]string{
+		"": filepath.Join(netDir, wallet.WalletDBName),
+	}
+	for _, name := range cfg.Wallets {
+		paths[name] = filepath.Join(namedWalletDir(netDir, name),
+			wallet.WalletDBName)
+	}
+	for name := range wallets {
+		if _, ok := paths[name]; !ok {
+			paths[name] = filepath.Join(namedWalletDir(netDir,
+				name), wallet.WalletDBName)
+		}
+	}
+	names := make([]string, 0, len(paths))
+	for name := range paths {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(w, "wallet %q\n", name)
+		fmt.Fprintf(w, "  database: %s\n", paths[name])
+		if fi, err := os.Stat(paths[name]); err != nil {
+			fmt.Fprintf(w, "  size: %v\n", err)
+		} else {
+			fmt.Fprintf(w, "  size: %d bytes\n", fi.Size())
+		}
+
+		wal, ok := wallets[name]
+		if !ok {
+			fmt.Fprintf(w, "  loaded: false\n")
+			continue
+		}
+		synced := wal.Manager.SyncedTo()
+		fmt.Fprintf(w, "  loaded: true\n")
+		fmt.Fprintf(w, "  synced to: %d %v\n", synced.Height,
+			synced.Hash)
+		fmt.Fprintf(w, "  chain synced: %v\n", wal.ChainSynced())
+		fmt.Fprintf(w, "  locked: %v\n", wal.Locked())
+		disk := wal.DiskStatus()
+		if disk.Enabled && disk.LastError == "" &&
+			!disk.LastCheck.IsZero() {
+
+			fmt.Fprintf(w, "  free disk space: %d MiB\n",
+				disk.Free>>20)
+		}
+	}
+
+	stats := walletdb.ReadStats()
+	fmt.Fprintf(w, "\ntransactions of the loaded wallets\n")
+	fmt.Fprintf(w, "  views: %d in %v\n", stats.Views, stats.ViewTime)
+	fmt.Fprintf(w, "  updates: %d in %v, %v waiting for the write lock\n",
+		stats.Updates, stats.UpdateTime, stats.UpdateWait)
+}
+
+// collectDebugInfo writes a debug info bundle to the debug directory of the
+// application data directory, and returns its path.
+func collectDebugInfo(cfg *Config) (string, error) {
+	dir := filepath.Join(cfg.AppDataDir.Value, "debug")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	name := fmt.Sprintf("lbcwallet-debug-%s.tar.gz",
+		time.Now().UTC().Format("20060102-150405"))
+	path := filepath.Join(dir, name)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return "", err
+	}
+	if err := writeDebugInfo(f, cfg, nil); err != nil {
+		f.Close()
+		os.Remove(path)
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// debugInfo writes the debug info bundle returned by the collectdebuginfo
+// method of the RPC server.
+func (d *Daemon) debugInfo(w io.Writer,
+	wallets map[string]*wallet.Wallet) error {
+
+	return writeDebugInfo(w, d.currentConfig(), wallets)
+}
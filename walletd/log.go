@@ -2,7 +2,7 @@
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
-package main
+package walletd
 
 import (
 	"fmt"
@@ -14,18 +14,32 @@ import (
 	"github.com/jrick/logrotate/rotator"
 	"github.com/lbryio/lbcd/rpcclient"
 	"github.com/lbryio/lbcwallet/chain"
+	"github.com/lbryio/lbcwallet/rpc/electrum"
 	"github.com/lbryio/lbcwallet/rpc/legacyrpc"
 	"github.com/lbryio/lbcwallet/wallet"
 	"github.com/lbryio/lbcwallet/wtxmgr"
 )
 
-// logWriter implements an io.Writer that outputs to both standard output and
-// the write-end pipe of an initialized log rotator.
+// logWriter implements an io.Writer that outputs to each enabled log target:
+// standard output, the write-end pipe of an initialized log rotator, and
+// syslog.  When JSON logging is enabled, each log line is converted to a JSON
+// object first, except for syslog which receives plain messages.
 type logWriter struct{}
 
 func (logWriter) Write(p []byte) (n int, err error) {
-	_, _ = os.Stdout.Write(p)
-	_, _ = logRotatorPipe.Write(p)
+	out := p
+	if logJSON {
+		out = formatJSONLog(p)
+	}
+	if logToStdout {
+		_, _ = os.Stdout.Write(out)
+	}
+	if logRotatorPipe != nil {
+		_, _ = logRotatorPipe.Write(out)
+	}
+	if logSyslog != nil {
+		logSyslog.write(p)
+	}
 	return len(p), nil
 }
 
@@ -34,9 +48,10 @@ func (logWriter) Write(p []byte) (n int, err error) {
 // subsystems, add the subsystem logger variable here and to the
 // subsystemLoggers map.
 //
-// Loggers can not be used before the log rotator has been initialized with a
-// log file.  This must be performed early during application startup by calling
-// initLogRotator.
+// Loggers can not be used before the log targets have been initialized.  This
+// must be performed early during application startup by calling
+// initLogRotator, when logging to a file is enabled, and setting the other
+// log target variables.
 var (
 	// backendLog is the logging backend used to create all subsystem loggers.
 	// The backend must not be used before the log rotator has been initialized,
@@ -51,12 +66,23 @@ var (
 	// is written to by the Write method of the logWriter type.
 	logRotatorPipe *io.PipeWriter
 
+	// logJSON selects structured JSON output instead of plain text lines.
+	// It must only be set before the first message is logged.
+	logJSON bool
+
+	// logToStdout enables writing log output to standard output.
+	logToStdout = true
+
+	// logSyslog, if not nil, is the syslog target log output is sent to.
+	logSyslog *syslogWriter
+
 	log          = backendLog.Logger("BTCW")
 	walletLog    = backendLog.Logger("WLLT")
 	txmgrLog     = backendLog.Logger("TMGR")
 	chainLog     = backendLog.Logger("CHNS")
 	grpcLog      = backendLog.Logger("GRPC")
 	legacyRPCLog = backendLog.Logger("RPCS")
+	electrumLog  = backendLog.Logger("ELEC")
 	btcnLog      = backendLog.Logger("BTCN")
 )
 
@@ -67,6 +93,7 @@ func init() {
 	chain.UseLogger(chainLog)
 	rpcclient.UseLogger(chainLog)
 	legacyrpc.UseLogger(legacyRPCLog)
+	electrum.UseLogger(electrumLog)
 }
 
 // subsystemLoggers maps each subsystem identifier to its associated logger.
@@ -77,6 +104,7 @@ var subsystemLoggers = map[string]btclog.Logger{
 	"CHNS": chainLog,
 	"GRPC": grpcLog,
 	"RPCS": legacyRPCLog,
+	"ELEC": electrumLog,
 	"BTCN": btcnLog,
 }
 
@@ -103,6 +131,14 @@ func initLogRotator(logFile string) {
 	logRotatorPipe = pw
 }
 
+// CloseLog closes the log file opened by LoadConfig, once the daemons of the
+// process have shut down.
+func CloseLog() {
+	if logRotator != nil {
+		logRotator.Close()
+	}
+}
+
 // setLogLevel sets the logging level for provided subsystem.  Invalid
 // subsystems are ignored.  Uninitialized subsystems are dynamically created as
 // needed.
@@ -0,0 +1,99 @@
+package walletd
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+// btclogTimeFormat is the timestamp layout written by the btclog backend.
+const btclogTimeFormat = "2006-01-02 15:04:05.000"
+
+// logLevelNames maps the level tags written by the btclog backend to the
+// level names used by the debuglevel option.
+var logLevelNames = map[string]string{
+	"TRC": "trace",
+	"DBG": "debug",
+	"INF": "info",
+	"WRN": "warn",
+	"ERR": "error",
+	"CRT": "critical",
+}
+
+// logLine is a log message as written by the btclog backend.
+type logLine struct {
+	timestamp time.Time
+	level     string
+	subsystem string
+	message   string
+}
+
+// parseLogLine splits a log line as written by the btclog backend,
+//
+//	2006-01-02 15:04:05.000 [INF] BTCW: message
+//
+// into its parts.  False is returned if the line does not match the format.
+func parseLogLine(line []byte) (logLine, bool) {
+	line = bytes.TrimRight(line, "\n")
+
+	const tsLen = len(btclogTimeFormat)
+	if len(line) <= tsLen+7 || line[tsLen] != ' ' || line[tsLen+1] != '[' ||
+		line[tsLen+5] != ']' || line[tsLen+6] != ' ' {
+
+		return logLine{}, false
+	}
+
+	ts, err := time.ParseInLocation(
+		btclogTimeFormat, string(line[:tsLen]), time.Local,
+	)
+	if err != nil {
+		return logLine{}, false
+	}
+	level, ok := logLevelNames[string(line[tsLen+2:tsLen+5])]
+	if !ok {
+		return logLine{}, false
+	}
+	rest := line[tsLen+7:]
+	sep := bytes.Index(rest, []byte(": "))
+	if sep <= 0 {
+		return logLine{}, false
+	}
+
+	return logLine{
+		timestamp: ts,
+		level:     level,
+		subsystem: string(rest[:sep]),
+		message:   string(rest[sep+2:]),
+	}, true
+}
+
+// jsonLogEntry is a single structured log message.
+type jsonLogEntry struct {
+	Timestamp string `json:"timestamp"`
+	Level     string `json:"level"`
+	Subsystem string `json:"subsystem"`
+	Message   string `json:"message"`
+}
+
+// formatJSONLog converts a log line as written by the btclog backend into a
+// single line JSON object.  Lines which do not match the format are logged as
+// the message of an entry without level and subsystem so that no output is
+// lost.
+func formatJSONLog(line []byte) []byte {
+	entry := jsonLogEntry{
+		Timestamp: time.Now().Format(time.RFC3339Nano),
+		Message:   string(bytes.TrimRight(line, "\n")),
+	}
+	if l, ok := parseLogLine(line); ok {
+		entry.Timestamp = l.timestamp.Format(time.RFC3339Nano)
+		entry.Level = l.level
+		entry.Subsystem = l.subsystem
+		entry.Message = l.message
+	}
+
+	b, err := json.Marshal(&entry)
+	if err != nil {
+		return line
+	}
+	return append(b, '\n')
+}
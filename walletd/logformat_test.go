@@ -0,0 +1,116 @@
+package walletd
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// TestFormatJSONLog ensures lines written by the btclog backend are split
+// into the fields of a JSON entry, and other lines are kept as the message.
+func TestFormatJSONLog(t *testing.T) {
+	ts := time.Date(2021, 3, 4, 5, 6, 7, 8e6, time.Local)
+	tsText := ts.Format(btclogTimeFormat)
+	badTSText := "2021-13-04 05:06:07.008"
+
+	tests := []struct {
+		name string
+		line string
+		want jsonLogEntry
+
+		// parsed is whether the line matches the btclog format, and
+		// the timestamp is therefore the one of the line.
+		parsed bool
+	}{
+		{
+			name: "info",
+			line: tsText + " [INF] BTCW: Opened wallet\n",
+			want: jsonLogEntry{
+				Level:     "info",
+				Subsystem: "BTCW",
+				Message:   "Opened wallet",
+			},
+			parsed: true,
+		},
+		{
+			name: "critical with separator in message",
+			line: tsText + " [CRT] CHNS: failed: EOF: closed\n",
+			want: jsonLogEntry{
+				Level:     "critical",
+				Subsystem: "CHNS",
+				Message:   "failed: EOF: closed",
+			},
+			parsed: true,
+		},
+		{
+			name: "quotes and tabs are escaped",
+			line: tsText + " [WRN] RPCS: \"bad\"\tinput\n",
+			want: jsonLogEntry{
+				Level:     "warn",
+				Subsystem: "RPCS",
+				Message:   "\"bad\"\tinput",
+			},
+			parsed: true,
+		},
+		{
+			name: "unknown level",
+			line: tsText + " [XYZ] BTCW: message\n",
+			want: jsonLogEntry{
+				Message: tsText + " [XYZ] BTCW: message",
+			},
+		},
+		{
+			name: "missing subsystem",
+			line: tsText + " [INF] message\n",
+			want: jsonLogEntry{
+				Message: tsText + " [INF] message",
+			},
+		},
+		{
+			name: "invalid timestamp",
+			line: badTSText + " [INF] BTCW: message\n",
+			want: jsonLogEntry{
+				Message: badTSText + " [INF] BTCW: message",
+			},
+		},
+		{
+			name: "panic output",
+			line: "goroutine 1 [running]:\n",
+			want: jsonLogEntry{
+				Message: "goroutine 1 [running]:",
+			},
+		},
+		{
+			name: "empty line",
+			line: "\n",
+		},
+	}
+
+	for _, test := range tests {
+		out := formatJSONLog([]byte(test.line))
+		if len(out) == 0 || out[len(out)-1] != '\n' {
+			t.Errorf("%s: entry %q is not a single line", test.name,
+				out)
+			continue
+		}
+
+		var got jsonLogEntry
+		if err := json.Unmarshal(out, &got); err != nil {
+			t.Errorf("%s: invalid JSON %q: %v", test.name, out, err)
+			continue
+		}
+		gotTime, err := time.Parse(time.RFC3339Nano, got.Timestamp)
+		if err != nil {
+			t.Errorf("%s: invalid timestamp: %v", test.name, err)
+		} else if test.parsed && !gotTime.Equal(ts) {
+			t.Errorf("%s: got timestamp %v, want %v", test.name,
+				gotTime, ts)
+		}
+
+		got.Timestamp = ""
+		if got != test.want {
+			t.Errorf("%s: got entry %+v, want %+v", test.name, got,
+				test.want)
+		}
+	}
+}
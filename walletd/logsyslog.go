@@ -0,0 +1,71 @@
+//go:build !windows && !plan9 && !js
+// +build !windows,!plan9,!js
+
+package walletd
+
+import (
+	"fmt"
+	"log/syslog"
+	"strings"
+)
+
+// syslogFacilities maps the names accepted by the syslogfacility option to
+// their syslog priorities.
+var syslogFacilities = map[string]syslog.Priority{
+	"kern":   syslog.LOG_KERN,
+	"user":   syslog.LOG_USER,
+	"daemon": syslog.LOG_DAEMON,
+	"auth":   syslog.LOG_AUTH,
+	"local0": syslog.LOG_LOCAL0,
+	"local1": syslog.LOG_LOCAL1,
+	"local2": syslog.LOG_LOCAL2,
+	"local3": syslog.LOG_LOCAL3,
+	"local4": syslog.LOG_LOCAL4,
+	"local5": syslog.LOG_LOCAL5,
+	"local6": syslog.LOG_LOCAL6,
+	"local7": syslog.LOG_LOCAL7,
+}
+
+// syslogWriter forwards log lines to the local syslog daemon using the
+// severity of each message.
+type syslogWriter struct {
+	w *syslog.Writer
+}
+
+// newSyslogWriter connects to the local syslog daemon, tagging messages with
+// tag and logging them with the named facility.
+func newSyslogWriter(facility, tag string) (*syslogWriter, error) {
+	priority, ok := syslogFacilities[strings.ToLower(facility)]
+	if !ok {
+		return nil, fmt.Errorf("unknown syslog facility %q", facility)
+	}
+	w, err := syslog.New(priority|syslog.LOG_INFO, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &syslogWriter{w: w}, nil
+}
+
+// write sends a log line written by the btclog backend to syslog.  The
+// timestamp is dropped since syslog records its own.
+func (s *syslogWriter) write(line []byte) {
+	l, ok := parseLogLine(line)
+	if !ok {
+		_ = s.w.Info(strings.TrimRight(string(line), "\n"))
+		return
+	}
+
+	msg := l.subsystem + ": " + l.message
+	switch l.level {
+	case "trace", "debug":
+		_ = s.w.Debug(msg)
+	case "warn":
+		_ = s.w.Warning(msg)
+	case "error":
+		_ = s.w.Err(msg)
+	case "critical":
+		_ = s.w.Crit(msg)
+	default:
+		_ = s.w.Info(msg)
+	}
+}
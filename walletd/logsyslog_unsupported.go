@@ -0,0 +1,17 @@
+//go:build windows || plan9 || js
+// +build windows plan9 js
+
+package walletd
+
+import "errors"
+
+// syslogWriter is not available on this operating system.
+type syslogWriter struct{}
+
+// newSyslogWriter always errors since syslog is not supported on this
+// operating system.
+func newSyslogWriter(facility, tag string) (*syslogWriter, error) {
+	return nil, errors.New("syslog is not supported on this operating system")
+}
+
+func (*syslogWriter) write([]byte) {}
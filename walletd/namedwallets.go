@@ -0,0 +1,176 @@
+package walletd
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/lbryio/lbcwallet/rpc/legacyrpc"
+	"github.com/lbryio/lbcwallet/wallet"
+)
+
+// namedWallets holds the wallets loaded alongside the default wallet.  Each is
+// synced over its own connection to the consensus RPC server, since the chain
+// notifications of a connection are consumed by a single wallet.
+type namedWallets struct {
+	mtx       sync.Mutex
+	d         *Daemon
+	netDir    string
+	wallets   map[string]*loadedWallet
+	rpcServer *legacyrpc.Server
+	cmds      *cmdNotifier
+	checker   *crossChecker
+	closed    bool
+}
+
+// loadedWallet is a named wallet and the quit channel of its consensus RPC
+// connection loop.
+type loadedWallet struct {
+	loader *wallet.Loader
+	quit   chan struct{}
+}
+
+func newNamedWallets(d *Daemon, netDir string, rpcServer *legacyrpc.Server,
+	cmds *cmdNotifier, checker *crossChecker) *namedWallets {
+
+	return &namedWallets{
+		d:         d,
+		netDir:    netDir,
+		wallets:   make(map[string]*loadedWallet),
+		rpcServer: rpcServer,
+		cmds:      cmds,
+		checker:   checker,
+	}
+}
+
+// LoadWallet opens the named wallet, serves it over RPC and unlocks it with
+// the default or specified passphrase.
+func (n *namedWallets) LoadWallet(name string) error {
+	if err := checkWalletName(name); err != nil {
+		return err
+	}
+
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	if n.closed {
+		return fmt.Errorf("wallet %q can not be loaded during "+
+			"shutdown", name)
+	}
+	if _, ok := n.wallets[name]; ok {
+		return fmt.Errorf("wallet %q is already loaded", name)
+	}
+
+	cfg := n.d.cfg
+	dir := namedWalletDir(n.netDir, name)
+	loader := wallet.NewLoader(
+		cfg.activeNet.Params, dir,
+		!cfg.SyncFreelist, cfg.DBTimeout, 250,
+	)
+	if cfg.WalletPass != "" {
+		loader.SetPublicPassphrase([]byte(cfg.WalletPass))
+	}
+
+	// Opening a wallet which does not exist would create an empty
+	// database.
+	exists, err := loader.WalletExists()
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return legacyrpc.ErrWalletNotFound
+	}
+
+	loader.RunAfterLoad(func(w *wallet.Wallet) {
+		setWalletPolicies(w, n.d.currentConfig(), "wallet."+name, dir,
+			n.cmds)
+		w.SetFaucet(newFaucet(cfg))
+		if n.checker != nil {
+			n.checker.add(w)
+		}
+		if n.rpcServer != nil {
+			n.rpcServer.RegisterNamedWallet(name, w)
+		}
+		go unlockWallet(w, cfg.Passphrase)
+	})
+	if _, err := loader.OpenExistingWallet(); err != nil {
+		return fmt.Errorf("unable to open wallet %q: %v", name, err)
+	}
+	lw := &loadedWallet{loader: loader, quit: make(chan struct{})}
+	n.wallets[name] = lw
+
+	go n.d.rpcClientConnectLoop(nil, loader, lw.quit)
+
+	log.Infof("Loaded wallet %q", name)
+	return nil
+}
+
+// UnloadWallet stops serving the named wallet once the requests being handled
+// by it have finished, then locks the wallet, disconnects it from the
+// consensus RPC server and closes its database.
+func (n *namedWallets) UnloadWallet(name string) error {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	lw, ok := n.wallets[name]
+	if !ok {
+		return legacyrpc.ErrWalletNotFound
+	}
+	delete(n.wallets, name)
+
+	if n.rpcServer != nil {
+		n.rpcServer.UnregisterNamedWallet(name)
+	}
+	return n.unload(name, lw)
+}
+
+// unload closes a wallet removed from the loaded wallets.
+func (n *namedWallets) unload(name string, lw *loadedWallet) error {
+	close(lw.quit)
+
+	// Lock the wallet first so its private keys are cleared from memory.
+	if w, ok := lw.loader.LoadedWallet(); ok {
+		w.Lock()
+		if n.checker != nil {
+			n.checker.remove(w)
+		}
+	}
+	err := lw.loader.UnloadWallet()
+	if err != nil && err != wallet.ErrNotLoaded {
+		return fmt.Errorf("failed to close wallet %q: %v", name, err)
+	}
+	if err == nil {
+		log.Infof("Wallet %q database closed", name)
+	}
+	return nil
+}
+
+// setPolicies sets the policies of the configuration c on every named wallet.
+// The wallets loaded afterwards get the policies of the configuration current
+// then.
+func (n *namedWallets) setPolicies(c *Config) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	for name, lw := range n.wallets {
+		w, ok := lw.loader.LoadedWallet()
+		if !ok {
+			continue
+		}
+		setWalletPolicies(w, c, "wallet."+name,
+			namedWalletDir(n.netDir, name), n.cmds)
+	}
+}
+
+// close closes the database of every named wallet.
+func (n *namedWallets) close() {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	n.closed = true
+	for name, lw := range n.wallets {
+		delete(n.wallets, name)
+		if err := n.unload(name, lw); err != nil {
+			log.Error(err)
+		}
+	}
+}
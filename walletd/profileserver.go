@@ -0,0 +1,90 @@
+package walletd
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"expvar"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"time"
+
+	"github.com/lbryio/lbcd/version"
+)
+
+func init() {
+	expvar.NewString("version").Set(version.Full())
+}
+
+// startProfileServer serves the pprof profiles and the expvar variables on
+// the profile address, requiring the RPC credentials if profileauth is set.
+// The command line is not served since it may contain passwords.  It blocks
+// until the server fails and must be run as a goroutine.
+func startProfileServer(cfg *Config) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("/debug/vars", expvarHandler)
+	mux.Handle("/", http.RedirectHandler("/debug/pprof",
+		http.StatusSeeOther))
+
+	var handler http.Handler = mux
+	if cfg.ProfileAuth {
+		handler = profileAuth(mux, cfg.RPCUser, cfg.RPCPass)
+	}
+
+	// No write timeout is set since CPU profiles and traces are streamed
+	// for as long as requested.
+	server := &http.Server{
+		Addr:              cfg.Profile,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	log.Infof("Profile server listening on %s", cfg.Profile)
+	log.Errorf("%v", server.ListenAndServe())
+}
+
+// expvarHandler serves the published expvar variables like expvar.Handler,
+// except for the command line.
+func expvarHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	fmt.Fprintf(w, "{\n")
+	first := true
+	expvar.Do(func(kv expvar.KeyValue) {
+		if kv.Key == "cmdline" {
+			return
+		}
+		if !first {
+			fmt.Fprintf(w, ",\n")
+		}
+		first = false
+		fmt.Fprintf(w, "%q: %s", kv.Key, kv.Value)
+	})
+	fmt.Fprintf(w, "\n}\n")
+}
+
+// profileAuth wraps a handler to require HTTP basic authentication with the
+// given username and password.  The credentials are compared in constant
+// time.
+func profileAuth(h http.Handler, username, password string) http.Handler {
+	userHash := sha256.Sum256([]byte(username))
+	passHash := sha256.Sum256([]byte(password))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, _ := r.BasicAuth()
+		u := sha256.Sum256([]byte(user))
+		p := sha256.Sum256([]byte(pass))
+		userOK := subtle.ConstantTimeCompare(u[:], userHash[:]) == 1
+		passOK := subtle.ConstantTimeCompare(p[:], passHash[:]) == 1
+		if !userOK || !passOK {
+			log.Warnf("Unauthorized profile server request from %s",
+				r.RemoteAddr)
+			w.Header().Set("WWW-Authenticate",
+				`Basic realm="lbcwallet profile"`)
+			http.Error(w, "401 Unauthorized.", http.StatusUnauthorized)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
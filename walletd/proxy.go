@@ -0,0 +1,176 @@
+package walletd
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/btcsuite/go-socks/socks"
+	"github.com/lbryio/lbcd/rpcclient"
+	"github.com/lbryio/lbcd/wire"
+)
+
+// onlyNetOnion is the onlynet value restricting outbound connections to
+// onion services.
+const onlyNetOnion = "onion"
+
+// isLoopbackHost returns whether host names the local machine.  Connections
+// to it never leave the host, so they are neither proxied nor restricted by
+// the onlynet option.
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// isOnionHost returns whether host is the address of an onion service.
+func isOnionHost(host string) bool {
+	return strings.HasSuffix(strings.ToLower(host), ".onion")
+}
+
+// isI2PHost returns whether host is the address of an I2P destination.
+func isI2PHost(host string) bool {
+	return strings.HasSuffix(strings.ToLower(host), ".i2p")
+}
+
+// checkOnlyNet returns an error when the onlynet option forbids connecting to
+// host.
+func checkOnlyNet(onlyNet, host string) error {
+	if onlyNet == onlyNetOnion && !isLoopbackHost(host) &&
+		!isOnionHost(host) {
+
+		return fmt.Errorf("%s is not an onion address, and only onion "+
+			"addresses may be connected to with onlynet=%s", host,
+			onlyNetOnion)
+	}
+	return nil
+}
+
+// chainProxy returns the proxy used to connect to lbcd, or nil when the
+// connection is direct.
+func chainProxy(cfg *Config) *socks.Proxy {
+	host, _, err := net.SplitHostPort(cfg.RPCConnect)
+	if err != nil || cfg.Proxy == "" || isLoopbackHost(host) {
+		return nil
+	}
+	return &socks.Proxy{
+		Addr:         cfg.Proxy,
+		Username:     cfg.ProxyUser,
+		Password:     cfg.ProxyPass,
+		TorIsolation: cfg.TorIsolation,
+	}
+}
+
+// chainI2PSAM returns the SAM bridge used to connect to lbcd over I2P, or
+// an empty string when lbcd is not an I2P destination.
+func chainI2PSAM(cfg *Config) string {
+	host, _, err := net.SplitHostPort(cfg.RPCConnect)
+	if err != nil || !isI2PHost(host) {
+		return ""
+	}
+	return cfg.I2PSAM
+}
+
+// dialOutbound makes the outbound connections of the HTTP clients, such as
+// those of webhooks and price endpoints.  Connections go through the proxy
+// returned by httpProxy, which resolves the host names, so neither the
+// connections nor their DNS queries leak outside of it.
+func dialOutbound(cfg *Config, network, addr string) (net.Conn, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkOnlyNet(cfg.OnlyNet, host); err != nil {
+		return nil, err
+	}
+	proxy := httpProxy(cfg)
+	if proxy == nil || isLoopbackHost(host) {
+		return net.Dial(network, addr)
+	}
+	return proxy.Dial(network, addr)
+}
+
+// httpProxy returns the proxy of the connections made by dialOutbound, which
+// is the proxy set by the httpproxy option, or else the proxy for lbcd unless
+// the nohttpproxy option is set.  Nil is returned for direct connections.
+func httpProxy(cfg *Config) *socks.Proxy {
+	switch {
+	case cfg.HTTPProxy != "":
+		return &socks.Proxy{
+			Addr:         cfg.HTTPProxy,
+			Username:     cfg.HTTPProxyUser,
+			Password:     cfg.HTTPProxyPass,
+			TorIsolation: cfg.TorIsolation,
+		}
+	case cfg.Proxy != "" && !cfg.NoHTTPProxy:
+		return &socks.Proxy{
+			Addr:         cfg.Proxy,
+			Username:     cfg.ProxyUser,
+			Password:     cfg.ProxyPass,
+			TorIsolation: cfg.TorIsolation,
+		}
+	}
+	return nil
+}
+
+// newHTTPClient returns an HTTP client making its connections with
+// dialOutbound.  Proxies set by the environment are ignored.
+func newHTTPClient(cfg *Config, timeout time.Duration) *http.Client {
+	dial := func(network, addr string) (net.Conn, error) {
+		return dialOutbound(cfg, network, addr)
+	}
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: &http.Transport{Dial: dial},
+	}
+}
+
+// broadcastIsolated sends a transaction to lbcd with a single HTTP POST
+// request made through the proxy with new random credentials, so that Tor
+// carries it over another circuit than the websocket connection of the chain
+// backend.
+func broadcastIsolated(cfg *Config, tx *wire.MsgTx) error {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return err
+	}
+	proxyURL := &url.URL{
+		Scheme: "socks5",
+		User: url.UserPassword(hex.EncodeToString(b[:8]),
+			hex.EncodeToString(b[8:])),
+		Host: cfg.Proxy,
+	}
+
+	connConfig := &rpcclient.ConnConfig{
+		Host:         cfg.RPCConnect,
+		User:         cfg.RPCUser,
+		Pass:         cfg.RPCPass,
+		HTTPPostMode: true,
+		DisableTLS:   cfg.DisableClientTLS,
+		SkipVerify:   cfg.SkipVerify,
+		Proxy:        proxyURL.String(),
+	}
+	if !cfg.DisableClientTLS {
+		certs, err := ioutil.ReadFile(cfg.CAFile.Value)
+		if err != nil {
+			return err
+		}
+		connConfig.Certificates = certs
+	}
+	client, err := rpcclient.New(connConfig, nil)
+	if err != nil {
+		return err
+	}
+	defer client.Shutdown()
+
+	_, err = client.SendRawTransaction(tx, false)
+	return err
+}
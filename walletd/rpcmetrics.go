@@ -0,0 +1,101 @@
+package walletd
+
+import (
+	"crypto/tls"
+	"expvar"
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"github.com/lbryio/lbcwallet/rpc/legacyrpc"
+)
+
+// rpcMetrics counts the connections to the RPC servers, which are published
+// as the rpc expvar variable on the profile server.
+var rpcMetrics struct {
+	connections atomic.Uint64 // Accepted connections.
+	handshakes  atomic.Uint64 // Completed TLS handshakes.
+	resumptions atomic.Uint64 // TLS handshakes resuming a session.
+}
+
+// countListener counts the connections accepted by a listener.
+type countListener struct {
+	net.Listener
+}
+
+func (l countListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err == nil {
+		rpcMetrics.connections.Add(1)
+	}
+	return conn, err
+}
+
+// countHandshake is the VerifyConnection callback of the RPC servers, which
+// counts their full and resumed TLS handshakes.
+func countHandshake(cs tls.ConnectionState) error {
+	rpcMetrics.handshakes.Add(1)
+	if cs.DidResume {
+		rpcMetrics.resumptions.Add(1)
+	}
+	return nil
+}
+
+// rpcServers are the legacy RPC servers of the running daemons, whose
+// websocket clients and notifications are published with the RPC metrics.
+var rpcServers struct {
+	mtx     sync.Mutex
+	servers map[*legacyrpc.Server]struct{}
+	publish sync.Once
+}
+
+// publishRPCMetrics publishes the RPC metrics, and the number of websocket
+// clients of the legacy RPC server with the counts of their notifications,
+// added to those of the other daemons of the process.
+func publishRPCMetrics(legacyServer *legacyrpc.Server) {
+	rpcServers.mtx.Lock()
+	if rpcServers.servers == nil {
+		rpcServers.servers = make(map[*legacyrpc.Server]struct{})
+	}
+	rpcServers.servers[legacyServer] = struct{}{}
+	rpcServers.mtx.Unlock()
+
+	rpcServers.publish.Do(func() {
+		expvar.Publish("rpc", expvar.Func(rpcMetricsVar))
+	})
+}
+
+// unpublishRPCMetrics removes a stopped legacy RPC server from the published
+// RPC metrics.
+func unpublishRPCMetrics(legacyServer *legacyrpc.Server) {
+	rpcServers.mtx.Lock()
+	delete(rpcServers.servers, legacyServer)
+	rpcServers.mtx.Unlock()
+}
+
+// rpcMetricsVar returns the value of the rpc expvar variable.
+func rpcMetricsVar() interface{} {
+	var websockets int64
+	var ntfns legacyrpc.NotificationStats
+	rpcServers.mtx.Lock()
+	for server := range rpcServers.servers {
+		websockets += server.WebsocketClients()
+		stats := server.NotificationStats()
+		ntfns.Delivered += stats.Delivered
+		ntfns.Dropped += stats.Dropped
+		ntfns.SlowClients += stats.SlowClients
+	}
+	rpcServers.mtx.Unlock()
+
+	return map[string]interface{}{
+		"connections": rpcMetrics.connections.Load(),
+		"handshakes":  rpcMetrics.handshakes.Load(),
+		"resumptions": rpcMetrics.resumptions.Load(),
+		"websockets":  websockets,
+		"notifications": map[string]uint64{
+			"delivered":   ntfns.Delivered,
+			"dropped":     ntfns.Dropped,
+			"slowclients": ntfns.SlowClients,
+		},
+	}
+}
@@ -2,10 +2,11 @@
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
-package main
+package walletd
 
 import (
 	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -16,14 +17,16 @@ import (
 	"strings"
 	"time"
 
+	"github.com/lbryio/lbcd/version"
 	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/rpc/electrum"
 	"github.com/lbryio/lbcwallet/rpc/legacyrpc"
 	"github.com/lbryio/lbcwallet/wallet"
 )
 
-// openRPCKeyPair creates or loads the RPC TLS keypair specified by the
-// application config.  This function respects the cfg.OneTimeTLSKey setting.
-func openRPCKeyPair() (tls.Certificate, error) {
+// openRPCKeyPair creates or loads the RPC TLS keypair specified by cfg.  This
+// function respects the cfg.OneTimeTLSKey setting.
+func openRPCKeyPair(cfg *Config) (tls.Certificate, error) {
 	// Check for existence of the TLS key file.  If one time TLS keys are
 	// enabled but a key already exists, this function should error since
 	// it's possible that a persistent certificate was copied to a remote
@@ -40,18 +43,35 @@ func openRPCKeyPair() (tls.Certificate, error) {
 			"`%s` already exists", cfg.RPCKey.Value)
 		return tls.Certificate{}, err
 	case cfg.OneTimeTLSKey:
-		return generateRPCKeyPair(false)
+		return generateRPCKeyPair(cfg, false)
 	case !keyExists:
-		return generateRPCKeyPair(true)
-	default:
-		return tls.LoadX509KeyPair(cfg.RPCCert.Value, cfg.RPCKey.Value)
+		return generateRPCKeyPair(cfg, true)
+	case cfg.RegenCert:
+		log.Infof("Replacing TLS certificate %s", cfg.RPCCert.Value)
+		return generateRPCKeyPair(cfg, true)
 	}
+
+	keyPair, err := tls.LoadX509KeyPair(cfg.RPCCert.Value, cfg.RPCKey.Value)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	leaf, err := x509.ParseCertificate(keyPair.Certificate[0])
+	if err == nil {
+		for _, host := range missingCertHosts(leaf, cfg.RPCCertHosts) {
+			log.Warnf("TLS certificate %s is not valid for %s -- "+
+				"replace it with --regen-cert", cfg.RPCCert.Value,
+				host)
+		}
+	}
+	return keyPair, nil
 }
 
 // generateRPCKeyPair generates a new RPC TLS keypair and writes the cert and
-// possibly also the key in PEM format to the paths specified by the config.  If
+// possibly also the key in PEM format to the paths specified by cfg.  If
 // successful, the new keypair is returned.
-func generateRPCKeyPair(writeKey bool) (tls.Certificate, error) {
+func generateRPCKeyPair(cfg *Config, writeKey bool) (tls.Certificate,
+	error) {
+
 	log.Infof("Generating TLS certificates...")
 
 	// Create directories for cert and key files if they do not yet exist.
@@ -68,8 +88,9 @@ func generateRPCKeyPair(writeKey bool) (tls.Certificate, error) {
 
 	// Generate cert pair.
 	org := "lbcwallet autogenerated cert"
-	validUntil := time.Now().Add(time.Hour * 24 * 365 * 10)
-	cert, key, err := btcutil.NewTLSCertPair(org, validUntil, nil)
+	validUntil := time.Now().Add(cfg.RPCCertValidity)
+	cert, key, err := btcutil.NewTLSCertPair(org, validUntil,
+		cfg.RPCCertHosts)
 	if err != nil {
 		return tls.Certificate{}, err
 	}
@@ -99,29 +120,69 @@ func generateRPCKeyPair(writeKey bool) (tls.Certificate, error) {
 	return keyPair, nil
 }
 
-func startRPCServers(walletLoader *wallet.Loader) (*legacyrpc.Server, error) {
+// startRPCServers starts the RPC servers configured for the daemon, serving
+// the wallet of walletLoader.
+func (d *Daemon) startRPCServers(walletLoader *wallet.Loader) (
+	*legacyrpc.Server, *electrum.Server, error) {
+
+	cfg := d.cfg
 	var (
 		legacyServer *legacyrpc.Server
-		legacyListen = net.Listen
+		legacyListen = countingListen
 		keyPair      tls.Certificate
 		err          error
 	)
 	if cfg.DisableServerTLS {
 		log.Info("Server TLS is disabled.  Only legacy RPC may be used")
 	} else {
-		keyPair, err = openRPCKeyPair()
+		keyPair, err = openRPCKeyPair(cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		minVersion, err := parseTLSVersion(cfg.TLSMinVersion)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
+		}
+		cipherSuites, err := parseCipherSuites(cfg.TLSCipherSuites)
+		if err != nil {
+			return nil, nil, err
 		}
 
 		// Change the standard net.Listen function to the tls one.
 		tlsConfig := &tls.Config{
 			Certificates: []tls.Certificate{keyPair},
-			MinVersion:   tls.VersionTLS12,
+			MinVersion:   minVersion,
+			CipherSuites: cipherSuites,
 			NextProtos:   []string{"h2"}, // HTTP/2 over TLS
+
+			// Session tickets let clients reconnecting often, such
+			// as polling ones, skip the full handshake.
+			SessionTicketsDisabled: cfg.NoTLSSessionTickets,
+			VerifyConnection:       countHandshake,
+		}
+
+		// One time TLS keys only exist in memory, so there is nothing
+		// to reload.  Otherwise serve the certificate through a
+		// reloader which picks up replaced files on SIGHUP and, if
+		// enabled, by polling their modification times.
+		if !cfg.OneTimeTLSKey {
+			reloader := newCertReloader(keyPair, cfg.RPCCert.Value,
+				cfg.RPCKey.Value)
+			tlsConfig.Certificates = nil
+			tlsConfig.GetCertificate = reloader.getCertificate
+			d.addReloadHandler(reloader.reload)
+			if cfg.CertPollInterval > 0 {
+				go reloader.watch(cfg.CertPollInterval,
+					d.interruptHandlersDone)
+			}
 		}
 		legacyListen = func(net string, laddr string) (net.Listener, error) {
-			return tls.Listen(net, laddr, tlsConfig)
+			l, err := countingListen(net, laddr)
+			if err != nil {
+				return nil, err
+			}
+			return tls.NewListener(l, tlsConfig), nil
 		}
 
 	}
@@ -132,35 +193,86 @@ func startRPCServers(walletLoader *wallet.Loader) (*legacyrpc.Server, error) {
 		listeners := makeListeners(cfg.LegacyRPCListeners, legacyListen)
 		if len(listeners) == 0 {
 			err := errors.New("failed to create listeners for legacy RPC server")
-			return nil, err
+			return nil, nil, err
+		}
+		dial := func(network, addr string) (net.Conn, error) {
+			return dialOutbound(cfg, network, addr)
 		}
 		opts := legacyrpc.Options{
 			Username:            cfg.RPCUser,
 			Password:            cfg.RPCPass,
+			Approvers:           cfg.rpcApprovers,
 			MaxPOSTClients:      cfg.LegacyRPCMaxClients,
 			MaxWebsocketClients: cfg.LegacyRPCMaxWebsockets,
+			MaxRequestSize:      cfg.RPCMaxRequestSize,
+			MaxMessageSize:      cfg.RPCMaxMessageSize,
+			MaxResultItems:      cfg.RPCMaxResultItems,
+			ReadyMaxBlockLag:    cfg.ReadyMaxBlockLag,
+			SlowThreshold:       cfg.RPCSlowThreshold,
+
+			NotificationQueueSize: cfg.RPCWSQueueSize,
+
+			AuthFailureThreshold: cfg.RPCAuthFailures,
+			AuthBanDuration:      cfg.RPCAuthBanTime,
+			AuthExemptIPs:        cfg.rpcAuthExemptIPs,
+			AllowedIPs:           cfg.rpcAllowedIPs,
+
+			Dial:           dial,
+			DebugInfo:      d.debugInfo,
+			RegtestHarness: cfg.RegtestHarness,
+			MiningAccount:  cfg.MiningAccount,
 		}
 		legacyServer = legacyrpc.NewServer(&opts, walletLoader, listeners)
+		publishRPCMetrics(legacyServer)
 	}
 
 	// Error when neither the GRPC nor legacy RPC servers can be started.
 	if legacyServer == nil {
-		return nil, errors.New("no suitable RPC services can be started")
+		return nil, nil, errors.New("no suitable RPC services can be started")
+	}
+
+	// The Electrum server is optional, and uses the same TLS settings as
+	// the legacy RPC server.
+	var electrumServer *electrum.Server
+	if len(cfg.ElectrumListeners) != 0 {
+		listeners := makeListeners(cfg.ElectrumListeners, legacyListen)
+		if len(listeners) == 0 {
+			legacyServer.Stop()
+			err := errors.New("failed to create listeners for Electrum server")
+			return nil, nil, err
+		}
+		opts := electrum.Options{
+			Banner:  cfg.ElectrumBanner,
+			Version: version.Full(),
+		}
+		electrumServer = electrum.NewServer(&opts, listeners)
 	}
 
-	return legacyServer, nil
+	return legacyServer, electrumServer, nil
 }
 
 type listenFunc func(net string, laddr string) (net.Listener, error)
 
+// countingListen is net.Listen with the accepted connections counted in the
+// RPC metrics.
+func countingListen(network, laddr string) (net.Listener, error) {
+	l, err := net.Listen(network, laddr)
+	if err != nil {
+		return nil, err
+	}
+	return countListener{l}, nil
+}
+
 // makeListeners splits the normalized listen addresses into IPv4 and IPv6
 // addresses and creates new net.Listeners for each with the passed listen func.
-// Invalid addresses are logged and skipped.
+// A host which is not an IP address names a network interface, which is
+// listened on at each of its addresses.  Invalid addresses are logged and
+// skipped.
 func makeListeners(normalizedListenAddrs []string, listen listenFunc) []net.Listener {
 	ipv4Addrs := make([]string, 0, len(normalizedListenAddrs)*2)
 	ipv6Addrs := make([]string, 0, len(normalizedListenAddrs)*2)
 	for _, addr := range normalizedListenAddrs {
-		host, _, err := net.SplitHostPort(addr)
+		host, port, err := net.SplitHostPort(addr)
 		if err != nil {
 			// Shouldn't happen due to already being normalized.
 			log.Errorf("`%s` is not a normalized "+
@@ -187,6 +299,15 @@ func makeListeners(normalizedListenAddrs []string, listen listenFunc) []net.List
 
 		ip := net.ParseIP(host)
 		switch {
+		case ip == nil && zoneIndex == -1:
+			v4, v6, err := interfaceListenAddrs(host, port)
+			if err != nil {
+				log.Warnf("`%s` is not a valid IP address or "+
+					"network interface: %v", host, err)
+				continue
+			}
+			ipv4Addrs = append(ipv4Addrs, v4...)
+			ipv6Addrs = append(ipv6Addrs, v6...)
 		case ip == nil:
 			log.Warnf("`%s` is not a valid IP address", host)
 		case ip.To4() == nil:
@@ -215,10 +336,51 @@ func makeListeners(normalizedListenAddrs []string, listen listenFunc) []net.List
 	return listeners
 }
 
+// interfaceListenAddrs returns the IPv4 and IPv6 listen addresses with the
+// given port of each address of a network interface.  Link-local IPv6
+// addresses are qualified with the zone of the interface.
+func interfaceListenAddrs(name, port string) ([]string, []string, error) {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, nil, err
+	}
+	var ipv4Addrs, ipv6Addrs []string
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		host := ipNet.IP.String()
+		switch {
+		case ipNet.IP.To4() != nil:
+			ipv4Addrs = append(ipv4Addrs, net.JoinHostPort(host, port))
+		case ipNet.IP.IsLinkLocalUnicast():
+			host += "%" + iface.Name
+			fallthrough
+		default:
+			ipv6Addrs = append(ipv6Addrs, net.JoinHostPort(host, port))
+		}
+	}
+	if len(ipv4Addrs)+len(ipv6Addrs) == 0 {
+		return nil, nil, fmt.Errorf("interface %s has no IP address",
+			name)
+	}
+	return ipv4Addrs, ipv6Addrs, nil
+}
+
 // startWalletRPCServices associates each of the (optionally-nil) RPC servers
 // with a wallet to enable remote wallet access.  For the GRPC server, this
 // registers the WalletService service, and for the legacy JSON-RPC server it
 // enables methods that require a loaded wallet.
-func startWalletRPCServices(wallet *wallet.Wallet, legacyServer *legacyrpc.Server) {
+func startWalletRPCServices(wallet *wallet.Wallet, legacyServer *legacyrpc.Server,
+	electrumServer *electrum.Server) {
+
 	legacyServer.RegisterWallet(wallet)
+	if electrumServer != nil {
+		electrumServer.RegisterWallet(wallet)
+	}
 }
@@ -0,0 +1,159 @@
+package walletd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// parseTLSVersion returns the crypto/tls constant of a TLS version given in
+// its dotted form.
+func parseTLSVersion(version string) (uint16, error) {
+	switch version {
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("unsupported TLS version %q (must be "+
+			"1.2 or 1.3)", version)
+	}
+}
+
+// parseCipherSuites returns the IDs of the named cipher suites.  Only suites
+// considered secure by crypto/tls are accepted.  A nil slice is returned when
+// no names are given so that the crypto/tls defaults are used.
+func parseCipherSuites(names []string) ([]uint16, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	known := make(map[string]uint16)
+	for _, suite := range tls.CipherSuites() {
+		known[suite.Name] = suite.ID
+	}
+
+	ids := make([]uint16, 0, len(names))
+	for _, name := range names {
+		id, ok := known[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown or insecure TLS cipher "+
+				"suite %q", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// missingCertHosts returns the hosts, which are DNS names or IP addresses, a
+// certificate is not valid for.
+func missingCertHosts(cert *x509.Certificate, hosts []string) []string {
+	var missing []string
+	for _, host := range hosts {
+		if cert.VerifyHostname(host) != nil {
+			missing = append(missing, host)
+		}
+	}
+	return missing
+}
+
+// certReloader serves the RPC server certificate to TLS handshakes and
+// replaces it when the certificate and key files change on disk.
+type certReloader struct {
+	certFile string
+	keyFile  string
+
+	mtx      sync.RWMutex
+	cert     *tls.Certificate
+	certTime time.Time
+	keyTime  time.Time
+}
+
+// newCertReloader returns a certReloader initially serving cert, which must
+// have been loaded from certFile and keyFile.
+func newCertReloader(cert tls.Certificate, certFile, keyFile string) *certReloader {
+	r := &certReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		cert:     &cert,
+	}
+	r.certTime, r.keyTime = r.modTimes()
+	return r
+}
+
+// modTimes returns the modification times of the certificate and key files.
+// A zero time is returned for a file that cannot be read.
+func (r *certReloader) modTimes() (certTime, keyTime time.Time) {
+	if fi, err := os.Stat(r.certFile); err == nil {
+		certTime = fi.ModTime()
+	}
+	if fi, err := os.Stat(r.keyFile); err == nil {
+		keyTime = fi.ModTime()
+	}
+	return certTime, keyTime
+}
+
+// getCertificate implements the tls.Config GetCertificate callback.
+func (r *certReloader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	return r.cert, nil
+}
+
+// reload loads the certificate and key files and, if they form a valid key
+// pair, serves them to all new connections.  On failure the previous
+// certificate remains in use until the files are modified again.
+func (r *certReloader) reload() {
+	certTime, keyTime := r.modTimes()
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+
+	r.mtx.Lock()
+	r.certTime, r.keyTime = certTime, keyTime
+	if err == nil {
+		r.cert = &cert
+	}
+	r.mtx.Unlock()
+
+	if err != nil {
+		log.Errorf("Unable to reload RPC TLS certificate: %v", err)
+		return
+	}
+
+	log.Infof("Reloaded RPC TLS certificate %s", r.certFile)
+}
+
+// watch polls the certificate and key files every interval and reloads them
+// when either has been modified.  It returns once quit is closed and must be
+// run as a goroutine.
+func (r *certReloader) watch(interval time.Duration, quit <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.reloadIfChanged()
+
+		case <-quit:
+			return
+		}
+	}
+}
+
+// reloadIfChanged reloads the certificate and key files if either has been
+// modified since they were last loaded.
+func (r *certReloader) reloadIfChanged() {
+	certTime, keyTime := r.modTimes()
+	r.mtx.RLock()
+	changed := !certTime.Equal(r.certTime) || !keyTime.Equal(r.keyTime)
+	r.mtx.RUnlock()
+
+	// Both files are usually replaced together, so wait until neither is
+	// missing before reloading.
+	if changed && !certTime.IsZero() && !keyTime.IsZero() {
+		r.reload()
+	}
+}
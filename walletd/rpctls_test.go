@@ -0,0 +1,217 @@
+package walletd
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	btcutil "github.com/lbryio/lbcutil"
+)
+
+// writeTestKeyPair writes a new self-signed certificate valid until
+// validUntil and its key to certFile and keyFile, setting their modification
+// time to modTime.
+func writeTestKeyPair(t *testing.T, certFile, keyFile string,
+	validUntil, modTime time.Time) []byte {
+
+	t.Helper()
+
+	cert, key, err := btcutil.NewTLSCertPair("test", validUntil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, certFile, cert, modTime)
+	writeTestFile(t, keyFile, key, modTime)
+	return cert
+}
+
+// writeTestFile writes contents to path and sets its modification time.
+func writeTestFile(t *testing.T, path string, contents []byte,
+	modTime time.Time) {
+
+	t.Helper()
+
+	if err := ioutil.WriteFile(path, contents, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestCertReloader ensures the served certificate is replaced when the files
+// are modified, and kept when the new files are not a valid key pair.
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "rpc.cert")
+	keyFile := filepath.Join(dir, "rpc.key")
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+	validUntil := time.Now().Add(time.Hour)
+
+	writeTestKeyPair(t, certFile, keyFile, validUntil, start)
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := newCertReloader(cert, certFile, keyFile)
+	served := cert.Certificate[0]
+
+	tests := []struct {
+		name string
+
+		// modify changes the files, returning the PEM certificate
+		// which is expected to be served afterwards, or nil if the
+		// certificate served before must be kept.
+		modify func(modTime time.Time) []byte
+	}{
+		{
+			name: "unmodified files",
+			modify: func(time.Time) []byte {
+				return nil
+			},
+		},
+		{
+			name: "new key pair",
+			modify: func(modTime time.Time) []byte {
+				return writeTestKeyPair(t, certFile, keyFile,
+					validUntil, modTime)
+			},
+		},
+		{
+			name: "unparsable certificate",
+			modify: func(modTime time.Time) []byte {
+				writeTestFile(t, certFile, []byte("invalid"),
+					modTime)
+				return nil
+			},
+		},
+		{
+			name: "missing key",
+			modify: func(time.Time) []byte {
+				if err := os.Remove(keyFile); err != nil {
+					t.Fatal(err)
+				}
+				writeTestKeyPair(t, certFile,
+					filepath.Join(dir, "other.key"),
+					validUntil, start)
+				return nil
+			},
+		},
+		{
+			name: "key pair replaced again",
+			modify: func(modTime time.Time) []byte {
+				return writeTestKeyPair(t, certFile, keyFile,
+					validUntil, modTime)
+			},
+		},
+	}
+
+	for i, test := range tests {
+		want := test.modify(start.Add(time.Duration(i+1) * time.Minute))
+		r.reloadIfChanged()
+
+		got, err := r.getCertificate(nil)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if want != nil {
+			block, _ := pem.Decode(want)
+			served = block.Bytes
+		}
+		if !bytes.Equal(got.Certificate[0], served) {
+			t.Errorf("%s: served certificate was not the expected "+
+				"one", test.name)
+		}
+	}
+}
+
+// TestParseTLSVersion ensures only the supported TLS versions are accepted.
+func TestParseTLSVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    uint16
+		valid   bool
+	}{
+		{version: "1.2", want: tls.VersionTLS12, valid: true},
+		{version: "1.3", want: tls.VersionTLS13, valid: true},
+		{version: "1.1"},
+		{version: "1.0"},
+		{version: "tls1.2"},
+		{version: ""},
+	}
+
+	for _, test := range tests {
+		got, err := parseTLSVersion(test.version)
+		switch {
+		case test.valid && err != nil:
+			t.Errorf("%q: unexpected error: %v", test.version, err)
+		case !test.valid && err == nil:
+			t.Errorf("%q: version was accepted", test.version)
+		case got != test.want:
+			t.Errorf("%q: got version %#x, want %#x", test.version,
+				got, test.want)
+		}
+	}
+}
+
+// TestParseCipherSuites ensures the names of secure cipher suites are
+// accepted and unknown or insecure ones rejected.
+func TestParseCipherSuites(t *testing.T) {
+	tests := []struct {
+		name  string
+		names []string
+		want  []uint16
+		valid bool
+	}{
+		{
+			name:  "defaults",
+			valid: true,
+		},
+		{
+			name: "secure suites",
+			names: []string{
+				"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+				"TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256",
+			},
+			want: []uint16{
+				tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+				tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+			},
+			valid: true,
+		},
+		{
+			name:  "unknown suite",
+			names: []string{"TLS_NOT_A_SUITE"},
+		},
+		{
+			name:  "insecure suite",
+			names: []string{"TLS_RSA_WITH_RC4_128_SHA"},
+		},
+		{
+			name: "unknown suite after a secure one",
+			names: []string{
+				"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+				"tls_ecdhe_rsa_with_aes_128_gcm_sha256",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		got, err := parseCipherSuites(test.names)
+		switch {
+		case test.valid && err != nil:
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+		case !test.valid && err == nil:
+			t.Errorf("%s: cipher suites were accepted", test.name)
+		case !reflect.DeepEqual(got, test.want):
+			t.Errorf("%s: got cipher suites %#x, want %#x",
+				test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright (c) 2013-2014 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package walletd
+
+import (
+	"os"
+	"os/signal"
+)
+
+// signals defines the signals that are handled to do a clean shutdown.
+// Conditional compilation is used to also include SIGTERM on Unix.
+var signals = []os.Signal{os.Interrupt}
+
+// reloadSignals defines the signals that request reloading of the runtime
+// reloadable state, such as the RPC server certificates.  Conditional
+// compilation is used to include SIGHUP on Unix.
+var reloadSignals []os.Signal
+
+// shutdownHandlers are the channels of the interrupt and reload handlers of a
+// daemon.
+type shutdownHandlers struct {
+	// interruptChannel is used to receive SIGINT (Ctrl+C) signals once
+	// HandleSignals was called.
+	interruptChannel chan os.Signal
+
+	// addHandlerChannel is used to add an interrupt handler to the list of
+	// handlers to be invoked on SIGINT (Ctrl+C) signals.
+	addHandlerChannel chan func()
+
+	// interruptHandlersDone is closed after all interrupt handlers run the
+	// first time an interrupt is signaled.
+	interruptHandlersDone chan struct{}
+
+	// shutdownStarted is closed when the interrupt handlers start running.
+	shutdownStarted chan struct{}
+
+	// shutdownForced is closed when an interrupt is signaled again while
+	// the interrupt handlers are still running, requesting to exit without
+	// waiting for them.
+	shutdownForced chan struct{}
+
+	simulateInterruptChannel chan struct{}
+
+	// reloadChannel is used to receive reload signals once HandleSignals
+	// was called.
+	reloadChannel chan os.Signal
+
+	// addReloadHandlerChannel is used to add a reload handler to the list
+	// of handlers to be invoked on reload signals.
+	addReloadHandlerChannel chan func()
+
+	// simulateReloadChannel is used to request a reload by an internal
+	// component instead of a signal.
+	simulateReloadChannel chan struct{}
+}
+
+func newShutdownHandlers() shutdownHandlers {
+	return shutdownHandlers{
+		interruptChannel:         make(chan os.Signal, 1),
+		addHandlerChannel:        make(chan func()),
+		interruptHandlersDone:    make(chan struct{}),
+		shutdownStarted:          make(chan struct{}),
+		shutdownForced:           make(chan struct{}),
+		simulateInterruptChannel: make(chan struct{}, 1),
+		reloadChannel:            make(chan os.Signal, 1),
+		addReloadHandlerChannel:  make(chan func()),
+		simulateReloadChannel:    make(chan struct{}, 1),
+	}
+}
+
+// HandleSignals makes the daemon shut down on SIGINT (Ctrl+C), and SIGTERM on
+// Unix, and reload its configuration on SIGHUP on Unix, as lbcwallet does.
+// Applications embedding a daemon which handle these signals themselves
+// don't call it, and use Stop instead.  The signals are handled until the
+// shutdown completes.
+func (d *Daemon) HandleSignals() {
+	signal.Notify(d.interruptChannel, signals...)
+	if len(reloadSignals) != 0 {
+		signal.Notify(d.reloadChannel, reloadSignals...)
+	}
+}
+
+// stopSignals stops relaying the signals set by HandleSignals.
+func (d *Daemon) stopSignals() {
+	signal.Stop(d.interruptChannel)
+	signal.Stop(d.reloadChannel)
+}
+
+// simulateInterrupt requests invoking the clean termination process by an
+// internal component instead of a SIGINT.
+func (d *Daemon) simulateInterrupt() {
+	select {
+	case d.simulateInterruptChannel <- struct{}{}:
+	default:
+	}
+}
+
+// mainInterruptHandler listens for SIGINT (Ctrl+C) signals on the
+// interruptChannel and invokes the registered interruptCallbacks accordingly.
+// It also listens for callback registration.  A second signal received while
+// the callbacks run closes shutdownForced.  It must be run as a goroutine.
+func (d *Daemon) mainInterruptHandler() {
+	// interruptCallbacks is a list of callbacks to invoke when a
+	// SIGINT (Ctrl+C) is received.
+	var interruptCallbacks []func()
+	invokeCallbacks := func() {
+		// run handlers in LIFO order.
+		close(d.shutdownStarted)
+		for i := range interruptCallbacks {
+			idx := len(interruptCallbacks) - 1 - i
+			interruptCallbacks[idx]()
+		}
+		close(d.interruptHandlersDone)
+	}
+
+	for {
+		select {
+		case sig := <-d.interruptChannel:
+			log.Infof("Received signal (%s).  Shutting down...", sig)
+			go invokeCallbacks()
+			d.waitInterruptHandlers()
+			return
+		case <-d.simulateInterruptChannel:
+			log.Info("Received shutdown request.  Shutting down...")
+			go invokeCallbacks()
+			d.waitInterruptHandlers()
+			return
+
+		case handler := <-d.addHandlerChannel:
+			interruptCallbacks = append(interruptCallbacks, handler)
+		}
+	}
+}
+
+// waitInterruptHandlers waits for the interrupt handlers to finish, closing
+// shutdownForced if another interrupt is signaled first.  Handlers added
+// during shutdown are never invoked.
+func (d *Daemon) waitInterruptHandlers() {
+	for {
+		select {
+		case <-d.interruptHandlersDone:
+			return
+		case sig := <-d.interruptChannel:
+			log.Warnf("Received signal (%s) again.  Forcing "+
+				"shutdown...", sig)
+			close(d.shutdownForced)
+			return
+
+		case <-d.addHandlerChannel:
+		}
+	}
+}
+
+// addInterruptHandler adds a handler to call when a SIGINT (Ctrl+C) is
+// received.  The main interrupt handler must be running.
+func (d *Daemon) addInterruptHandler(handler func()) {
+	d.addHandlerChannel <- handler
+}
+
+// simulateReload requests invoking the reload handlers by an internal
+// component instead of a reload signal.
+func (d *Daemon) simulateReload() {
+	select {
+	case d.simulateReloadChannel <- struct{}{}:
+	default:
+	}
+}
+
+// mainReloadHandler listens for reload signals on the reloadChannel and
+// invokes the registered reload handlers in the order they were added.  It
+// also listens for handler registration.  It must be run as a goroutine.
+func (d *Daemon) mainReloadHandler() {
+	var reloadCallbacks []func()
+	invokeCallbacks := func() {
+		for _, callback := range reloadCallbacks {
+			callback()
+		}
+	}
+
+	for {
+		select {
+		case sig := <-d.reloadChannel:
+			log.Infof("Received signal (%s).  Reloading...", sig)
+			invokeCallbacks()
+		case <-d.simulateReloadChannel:
+			log.Info("Received reload request.  Reloading...")
+			invokeCallbacks()
+
+		case handler := <-d.addReloadHandlerChannel:
+			reloadCallbacks = append(reloadCallbacks, handler)
+
+		case <-d.interruptHandlersDone:
+			return
+		}
+	}
+}
+
+// addReloadHandler adds a handler to call when a reload signal (SIGHUP on
+// Unix) is received.  The main reload handler must be running.
+func (d *Daemon) addReloadHandler(handler func()) {
+	d.addReloadHandlerChannel <- handler
+}
@@ -5,7 +5,7 @@
 //go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
 // +build darwin dragonfly freebsd linux netbsd openbsd solaris
 
-package main
+package walletd
 
 import (
 	"os"
@@ -14,4 +14,5 @@ import (
 
 func init() {
 	signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	reloadSignals = []os.Signal{syscall.SIGHUP}
 }
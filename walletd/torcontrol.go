@@ -0,0 +1,98 @@
+package walletd
+
+import (
+	"errors"
+	"net"
+	"strconv"
+
+	"github.com/lbryio/lbcwallet/internal/torcontrol"
+	"github.com/lbryio/lbcwallet/rpc/electrum"
+	"github.com/lbryio/lbcwallet/rpc/legacyrpc"
+)
+
+// startTorControl connects to the control port of Tor set by the torcontrol
+// option of cfg.  Without a proxy, the SOCKS port of Tor becomes the proxy of
+// cfg, with stream isolation unless proxy credentials are set.  The RPC and
+// Electrum listeners are then served over an ephemeral onion service, which
+// lasts until the returned connection is closed, so that a remote wallet can
+// be reached without forwarding a port.
+func startTorControl(cfg *Config, legacyServer *legacyrpc.Server,
+	electrumServer *electrum.Server) (*torcontrol.Conn, error) {
+
+	tor, err := torcontrol.Dial(cfg.TorControl, cfg.TorPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Proxy == "" {
+		socks, err := tor.SOCKSListeners()
+		if err != nil {
+			tor.Close()
+			return nil, err
+		}
+		if len(socks) == 0 {
+			tor.Close()
+			return nil, errors.New("tor has no SOCKS port to " +
+				"connect through")
+		}
+		cfg.Proxy = socks[0]
+		if cfg.ProxyUser == "" && cfg.ProxyPass == "" &&
+			cfg.HTTPProxyUser == "" && cfg.HTTPProxyPass == "" {
+
+			cfg.TorIsolation = true
+		}
+		log.Infof("Connecting through the Tor SOCKS port %s",
+			cfg.Proxy)
+	}
+
+	var addrs []net.Addr
+	if legacyServer != nil {
+		addrs = append(addrs, legacyServer.ListenAddrs()...)
+	}
+	if electrumServer != nil {
+		addrs = append(addrs, electrumServer.ListenAddrs()...)
+	}
+	ports := onionPorts(addrs)
+	if len(ports) == 0 {
+		return tor, nil
+	}
+	onion, err := tor.AddOnion(ports)
+	if err != nil {
+		tor.Close()
+		return nil, err
+	}
+	for _, p := range ports {
+		log.Infof("Serving %s over Tor at %s", p.Target,
+			net.JoinHostPort(onion, strconv.Itoa(int(p.Virtual))))
+	}
+	return tor, nil
+}
+
+// onionPorts maps the ports of the listen addresses to themselves on the
+// onion service.  Tor connects to the loopback address of the family of an
+// unspecified address, and to the first listener of a port listened on more
+// than once.
+func onionPorts(addrs []net.Addr) []torcontrol.OnionPort {
+	var ports []torcontrol.OnionPort
+	seen := make(map[int]bool)
+	for _, addr := range addrs {
+		tcpAddr, ok := addr.(*net.TCPAddr)
+		if !ok || seen[tcpAddr.Port] {
+			continue
+		}
+		seen[tcpAddr.Port] = true
+
+		target := *tcpAddr
+		if target.IP.IsUnspecified() {
+			target.IP = net.IPv4(127, 0, 0, 1)
+			if tcpAddr.IP.To4() == nil {
+				target.IP = net.IPv6loopback
+			}
+		}
+		ports = append(ports, torcontrol.OnionPort{
+			Virtual: uint16(tcpAddr.Port),
+			Target:  target.String(),
+		})
+	}
+	return ports
+}
@@ -0,0 +1,740 @@
+// Copyright (c) 2013-2015 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package walletd runs a full LBC wallet daemon: the wallet loader, the
+// connection to lbcd, the RPC servers and the notifications configured.  It is
+// the daemon run by lbcwallet, and lets Go applications embed a wallet
+// in-process:
+//
+//	cfg := walletd.DefaultConfig()
+//	cfg.AppDataDir.UnmarshalFlag(dir)
+//	cfg.RPCUser, cfg.RPCPass = user, pass
+//	d, err := walletd.New(cfg)
+//	if err != nil {
+//		return err
+//	}
+//	if err := d.Start(); err != nil {
+//		return err
+//	}
+//	defer d.Stop()
+//
+// Each daemon keeps its own configuration and shutdown handlers, so several
+// daemons with different application data directories may run in a process.
+// The logging of the process is set up by LoadConfig, which parses the config
+// file and command line options as lbcwallet does.  Signals are only handled
+// by the daemons for which HandleSignals is called.
+package walletd
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lbryio/lbcwallet/chain"
+	"github.com/lbryio/lbcwallet/internal/faucet"
+	"github.com/lbryio/lbcwallet/internal/prices"
+	"github.com/lbryio/lbcwallet/internal/torcontrol"
+	"github.com/lbryio/lbcwallet/internal/zero"
+	"github.com/lbryio/lbcwallet/rpc/legacyrpc"
+	"github.com/lbryio/lbcwallet/wallet"
+
+	"github.com/lbryio/lbcd/version"
+	"github.com/lbryio/lbcd/wire"
+)
+
+// priceRequestTimeout is the longest a request for an exchange rate may take.
+const priceRequestTimeout = 30 * time.Second
+
+// faucetRequestTimeout is the longest a request for test coins may take.
+const faucetRequestTimeout = time.Minute
+
+var (
+	// ErrForcedShutdown is returned by Wait and Stop when the shutdown did
+	// not wait for every in-flight request or the wallet to finish
+	// closing.
+	ErrForcedShutdown = errors.New("forced shutdown")
+
+	// ErrDaemonStarted is returned by Start when the daemon was already
+	// started, even if the start failed.
+	ErrDaemonStarted = errors.New("wallet daemon already started")
+
+	// ErrNotStarted is returned by Wait and Stop when the daemon was not
+	// started successfully.
+	ErrNotStarted = errors.New("wallet daemon not started")
+)
+
+// Daemon is a wallet daemon created by New.  Its wallet is opened by Start,
+// and closed with the remaining components by Stop.
+type Daemon struct {
+	// cfg is the configuration the daemon was started with, and live
+	// replaces it as a whole with each reload, so that the options reloaded
+	// are read without racing with a reload.  The options which need a
+	// restart keep their value from cfg.
+	cfg  *Config
+	live atomic.Pointer[Config]
+
+	loader *wallet.Loader
+
+	starting int32
+	started  int32
+	drained  int32
+	openErr  error
+	openDone chan struct{}
+	deadline <-chan struct{}
+
+	waitOnce sync.Once
+	waitErr  error
+
+	shutdownHandlers
+}
+
+// New creates a wallet daemon with the configuration cfg, returned by
+// LoadConfig or DefaultConfig.  The options are validated as they are by
+// LoadConfig, and the daemon keeps its own copy of them.
+func New(cfg Config) (*Daemon, error) {
+	copyExplicitStrings(&cfg)
+	if err := initConfig(&cfg); err != nil {
+		return nil, err
+	}
+	if err := checkConfig(&cfg); err != nil {
+		return nil, err
+	}
+
+	dbDir := networkDir(cfg.AppDataDir.Value, cfg.activeNet)
+	loader := wallet.NewLoader(
+		cfg.activeNet.Params, dbDir, !cfg.SyncFreelist, cfg.DBTimeout,
+		250,
+	)
+	if cfg.WalletPass != "" {
+		loader.SetPublicPassphrase([]byte(cfg.WalletPass))
+	}
+
+	d := &Daemon{
+		cfg:              &cfg,
+		loader:           loader,
+		drained:          1,
+		openDone:         make(chan struct{}),
+		shutdownHandlers: newShutdownHandlers(),
+	}
+	d.live.Store(&cfg)
+	return d, nil
+}
+
+// Loader returns the loader of the wallet of the daemon.  The wallet is
+// loaded in the background once the daemon is started.
+func (d *Daemon) Loader() *wallet.Loader {
+	return d.loader
+}
+
+// Start starts the RPC servers, the connection to lbcd and the notifications
+// configured, and then opens the wallet in the background.  A wallet which
+// fails to open shuts the daemon down, and the error is returned by Wait.  A
+// daemon which failed to start cannot be started again.
+func (d *Daemon) Start() error {
+	if !atomic.CompareAndSwapInt32(&d.starting, 0, 1) {
+		return ErrDaemonStarted
+	}
+
+	// Show version at startup.
+	log.Infof("Version %s", version.Full())
+
+	cfg := d.cfg
+	if cfg.Profile != "" {
+		go startProfileServer(cfg)
+	}
+
+	loader := d.loader
+	dbDir := networkDir(cfg.AppDataDir.Value, cfg.activeNet)
+
+	go d.mainInterruptHandler()
+	go d.mainReloadHandler()
+
+	// The components started are stopped again when a later step fails,
+	// since no interrupt handler stops them yet, and the interrupt and
+	// reload handlers are then stopped with no handler to run.
+	var closers []func()
+	fail := func(err error) error {
+		for i := len(closers) - 1; i >= 0; i-- {
+			closers[i]()
+		}
+		d.simulateInterrupt()
+		return err
+	}
+
+	// Create and start HTTP server to serve wallet client connections.
+	// This will be updated with the wallet and chain server RPC client
+	// created below after each is created.
+	legacyRPCServer, electrumServer, err := d.startRPCServers(loader)
+	if err != nil {
+		log.Errorf("Unable to create RPC servers: %v", err)
+		return fail(err)
+	}
+	if legacyRPCServer != nil {
+		closers = append(closers, func() {
+			legacyRPCServer.Stop()
+			unpublishRPCMetrics(legacyRPCServer)
+		})
+	}
+	if electrumServer != nil {
+		closers = append(closers, electrumServer.Stop)
+	}
+
+	// The proxy may be taken from Tor, so it is started before the
+	// connection to lbcd.
+	var tor *torcontrol.Conn
+	if cfg.TorControl != "" {
+		tor, err = startTorControl(cfg, legacyRPCServer,
+			electrumServer)
+		if err != nil {
+			log.Errorf("Unable to use the Tor control port: %v", err)
+			return fail(err)
+		}
+		closers = append(closers, func() { tor.Close() })
+	}
+
+	// Publish wallet events to ZMQ subscribers when any endpoint is
+	// configured.
+	var zmq *zmqNotifier
+	if endpoints := zmqEndpoints(cfg); len(endpoints) != 0 {
+		zmq, err = newZMQNotifier(endpoints)
+		if err != nil {
+			log.Error(err)
+			return fail(err)
+		}
+		closers = append(closers, zmq.close)
+	}
+
+	// Cross-check the blocks of lbcd with a second server when one is
+	// configured.
+	var checker *crossChecker
+	if cfg.VerifyConnect != "" {
+		checker, err = newCrossChecker(cfg)
+		if err != nil {
+			log.Errorf("Unable to connect to the verifyconnect "+
+				"server: %v", err)
+			return fail(err)
+		}
+	}
+
+	go d.rpcClientConnectLoop(legacyRPCServer, loader, nil)
+
+	if zmq != nil {
+		loader.RunAfterLoad(zmq.run)
+	}
+
+	// Run the blocknotify, walletnotify and disknotify commands when
+	// configured.
+	var cmds *cmdNotifier
+	if cfg.BlockNotify != "" || cfg.WalletNotify != "" ||
+		cfg.DiskNotify != "" {
+
+		cmds = newCmdNotifier(cfg.BlockNotify, cfg.WalletNotify,
+			cfg.DiskNotify)
+		loader.RunAfterLoad(cmds.run)
+	}
+
+	if checker != nil {
+		loader.RunAfterLoad(checker.add)
+	}
+
+	// Record the exchange rate of wallet transactions when a price
+	// endpoint is configured.
+	if cfg.PriceURL != "" {
+		src := &prices.HTTPSource{
+			URL:    cfg.PriceURL,
+			Field:  cfg.PriceField,
+			Client: newHTTPClient(cfg, priceRequestTimeout),
+		}
+		loader.RunAfterLoad(func(w *wallet.Wallet) {
+			w.RecordFiatRates(src, cfg.FiatCurrency)
+		})
+	}
+
+	// The unlock runs separately, since the key derivation of the
+	// passphrase would otherwise delay the remaining startup tasks.
+	loader.RunAfterLoad(func(w *wallet.Wallet) {
+		setWalletPolicies(w, d.currentConfig(), "wallet", dbDir, cmds)
+		w.SetFaucet(newFaucet(cfg))
+		startWalletRPCServices(w, legacyRPCServer, electrumServer)
+		go unlockWallet(w, cfg.Passphrase)
+	})
+	named := newNamedWallets(d, dbDir, legacyRPCServer, cmds, checker)
+	if legacyRPCServer != nil {
+		legacyRPCServer.SetWalletManager(named)
+	}
+
+	// Reload the configuration on SIGHUP or when requested by an RPC
+	// client, and set the reloaded policies on the loaded wallets.
+	d.addReloadHandler(func() {
+		d.reloadConfig(legacyRPCServer, func(c *Config) {
+			if w, ok := loader.LoadedWallet(); ok {
+				setWalletPolicies(w, c, "wallet", dbDir, cmds)
+			}
+			named.setPolicies(c)
+		})
+	})
+	if legacyRPCServer != nil {
+		go func() {
+			for range legacyRPCServer.RequestReload() {
+				d.simulateReload()
+			}
+		}()
+	}
+
+	// Add interrupt handlers to shutdown the various process components
+	// before exiting.  Interrupt handlers run in LIFO order, so the wallet
+	// (which should be closed last) is added first, after the Tor
+	// connection serving the onion service.
+	if tor != nil {
+		d.addInterruptHandler(func() {
+			tor.Close()
+		})
+	}
+	d.addInterruptHandler(func() {
+		err := loader.UnloadWallet()
+		if err != nil && err != wallet.ErrNotLoaded {
+			log.Errorf("Failed to close wallet: %v", err)
+			return
+		}
+		if err == nil {
+			log.Info("Wallet database closed")
+		}
+	})
+	d.addInterruptHandler(func() {
+		named.close()
+	})
+	if checker != nil {
+		d.addInterruptHandler(func() {
+			log.Info("Stopping the cross-checks...")
+			checker.close()
+		})
+	}
+	if cmds != nil {
+		d.addInterruptHandler(func() {
+			log.Info("Waiting for notification commands...")
+			cmds.close()
+		})
+	}
+	if zmq != nil {
+		d.addInterruptHandler(func() {
+			log.Info("Stopping ZMQ notifications...")
+			zmq.close()
+		})
+	}
+	if electrumServer != nil {
+		d.addInterruptHandler(func() {
+			log.Info("Stopping Electrum server...")
+			electrumServer.Stop()
+			log.Info("Electrum server shutdown")
+		})
+	}
+	if legacyRPCServer != nil {
+		d.addInterruptHandler(func() {
+			// Stop accepting requests and let the in-flight ones
+			// finish before the wallet is stopped underneath them.
+			log.Info("Waiting for in-flight RPC requests...")
+			if !legacyRPCServer.Drain(cfg.ShutdownTimeout) {
+				log.Warnf("In-flight RPC requests did not finish "+
+					"within %v", cfg.ShutdownTimeout)
+				atomic.StoreInt32(&d.drained, 0)
+			}
+			log.Warn("Stopping legacy RPC server...")
+			legacyRPCServer.Stop()
+			unpublishRPCMetrics(legacyRPCServer)
+			log.Info("Legacy RPC server shutdown")
+		})
+		go func() {
+			<-legacyRPCServer.RequestProcessShutdown()
+			d.simulateInterrupt()
+		}()
+	}
+
+	d.deadline = d.shutdownDeadline(legacyRPCServer != nil)
+
+	// Open the wallet once everything else is running, so that the RPC
+	// servers answer while a large database is opened.  Requests for the
+	// wallet fail with a warmup error until then.
+	go func() {
+		defer close(d.openDone)
+
+		start := time.Now()
+		_, err := loader.OpenExistingWallet()
+		if err != nil {
+			log.Error(err)
+			d.openErr = err
+			d.simulateInterrupt()
+			return
+		}
+		log.Infof("Wallet loaded in %v", time.Since(start).Round(
+			time.Millisecond))
+
+		for _, name := range cfg.Wallets {
+			if err := named.LoadWallet(name); err != nil {
+				log.Error(err)
+				d.openErr = err
+				d.simulateInterrupt()
+				return
+			}
+		}
+	}()
+
+	atomic.StoreInt32(&d.started, 1)
+	return nil
+}
+
+// Stop shuts the daemon down as an interrupt signal would, and returns the
+// result of Wait.
+func (d *Daemon) Stop() error {
+	if atomic.LoadInt32(&d.started) == 0 {
+		return ErrNotStarted
+	}
+	d.simulateInterrupt()
+	return d.Wait()
+}
+
+// Wait blocks until the daemon is shut down, either by Stop, an interrupt
+// signal or the stop RPC.  ErrForcedShutdown is returned when the shutdown
+// did not complete cleanly, and the error of the wallet when it could not be
+// opened.  The signals set by HandleSignals are no longer handled once it
+// returns.
+func (d *Daemon) Wait() error {
+	if atomic.LoadInt32(&d.started) == 0 {
+		return ErrNotStarted
+	}
+	d.waitOnce.Do(func() {
+		d.waitErr = d.wait()
+		d.stopSignals()
+	})
+	return d.waitErr
+}
+
+func (d *Daemon) wait() error {
+	select {
+	case <-d.interruptHandlersDone:
+	case <-d.shutdownForced:
+		return ErrForcedShutdown
+	case <-d.deadline:
+		log.Errorf("Shutdown did not complete in time")
+		return ErrForcedShutdown
+	}
+	if atomic.LoadInt32(&d.drained) == 0 {
+		log.Warn("Shutdown complete, in-flight requests were abandoned")
+		return ErrForcedShutdown
+	}
+	select {
+	case <-d.openDone:
+		if d.openErr != nil {
+			return d.openErr
+		}
+	default:
+	}
+	log.Info("Shutdown complete")
+	return nil
+}
+
+// unlockWallet unlocks a loaded wallet with the default or specified
+// passphrase pass.
+func unlockWallet(w *wallet.Wallet, pass string) {
+	log.Infof("Unlocking wallet with the default or specified " +
+		"passphrase...")
+	passphrase := []byte(pass)
+	err := w.Unlock(passphrase, nil)
+	zero.Bytes(passphrase)
+	if err != nil {
+		log.Infof("Unable to unlock wallet: %v", err)
+	}
+}
+
+// setWalletPolicies sets the policies of the configuration c on a loaded
+// wallet, whose backups are named after prefix and whose database is kept in
+// dir.  Crossing the disk space levels runs the disknotify command with cmds,
+// which may be nil.
+func setWalletPolicies(w *wallet.Wallet, c *Config, prefix, dir string,
+	cmds *cmdNotifier) {
+
+	w.SetChangePolicy(changePolicy(c))
+	w.SetBroadcastPolicy(broadcastPolicy(c))
+	w.SetMinConfPolicy(minConfPolicy(c))
+	w.SetWithdrawalPolicy(withdrawalPolicy(c))
+	w.SetSendPreview(c.SendPreview)
+	w.SetColdSigningPolicy(coldSigningPolicy(c))
+	w.SetKeyPoolSize(c.KeyPoolSize)
+	w.SetClaimAccount(c.ClaimAccount)
+	w.SetBackupPolicy(backupPolicy(c, prefix))
+	w.SetDiskPolicy(diskPolicy(c, dir, cmds))
+}
+
+// changePolicy returns the policy for the change outputs of the transactions
+// of loaded wallets, set by the matchchangetype and splitchange options.
+func changePolicy(cfg *Config) wallet.ChangePolicy {
+	return wallet.ChangePolicy{
+		MatchOutputType: cfg.MatchChangeType,
+		SplitChange:     cfg.SplitChange,
+	}
+}
+
+// broadcastPolicy returns the policy for the broadcast of the transactions of
+// loaded wallets, set by the broadcastdelay and broadcastisolation options.
+func broadcastPolicy(cfg *Config) wallet.BroadcastPolicy {
+	policy := wallet.BroadcastPolicy{MaxDelay: cfg.BroadcastDelay}
+	if cfg.BroadcastIsolation && chainProxy(cfg) != nil {
+		policy.Broadcast = func(tx *wire.MsgTx) error {
+			return broadcastIsolated(cfg, tx)
+		}
+	}
+	return policy
+}
+
+// minConfPolicy returns the policy for the confirmations of the outputs spent
+// by loaded wallets, set by the minconf options.
+func minConfPolicy(cfg *Config) wallet.MinConfPolicy {
+	return wallet.MinConfPolicy{
+		Coinbase: cfg.MinConfCoinbase,
+		Claim:    cfg.MinConfClaim,
+		Change:   cfg.MinConfChange,
+		External: cfg.MinConfExternal,
+	}
+}
+
+// withdrawalPolicy returns the policy for the approval of the sends of loaded
+// wallets, set by the withdrawal options.
+func withdrawalPolicy(cfg *Config) wallet.WithdrawalPolicy {
+	return wallet.WithdrawalPolicy{
+		Threshold: cfg.withdrawalThreshold,
+		Approvals: cfg.WithdrawApprovals,
+		Expiry:    cfg.WithdrawExpiry,
+		Window:    cfg.WithdrawWindow,
+	}
+}
+
+// coldSigningPolicy returns the policy for the offline signing of the sends
+// of loaded wallets, set by the coldsigning options.
+func coldSigningPolicy(cfg *Config) wallet.ColdSigningPolicy {
+	return wallet.ColdSigningPolicy{
+		Enabled: cfg.ColdSigning,
+		Dir:     cfg.PsbtDir,
+	}
+}
+
+// newFaucet returns the faucet set by the faucet option, from which loaded
+// wallets request test coins, or nil when unset.
+func newFaucet(cfg *Config) wallet.Faucet {
+	if cfg.Faucet == "" {
+		return nil
+	}
+	return &faucet.HTTPFaucet{
+		URL:    cfg.Faucet,
+		Client: newHTTPClient(cfg, faucetRequestTimeout),
+	}
+}
+
+// backupPolicy returns the policy for the backups of a loaded wallet, whose
+// names start with prefix, set by the backup options.
+func backupPolicy(cfg *Config, prefix string) wallet.BackupPolicy {
+	return wallet.BackupPolicy{
+		Interval: cfg.BackupInterval,
+		Keep:     cfg.BackupKeep,
+		Key:      cfg.backupKey,
+		Store:    cfg.backupStore,
+		Prefix:   prefix,
+	}
+}
+
+// diskPolicy returns the policy for the monitoring of the free space of the
+// volume holding the database of a loaded wallet, kept in dir, set by the disk
+// space options.  Crossing the levels runs the disknotify command with cmds,
+// which may be nil.
+func diskPolicy(cfg *Config, dir string,
+	cmds *cmdNotifier) wallet.DiskPolicy {
+
+	policy := wallet.DiskPolicy{
+		Path:     filepath.Join(dir, wallet.WalletDBName),
+		Interval: cfg.DiskCheckInterval,
+		Warn:     cfg.DiskWarn << 20,
+		Floor:    cfg.DiskFloor << 20,
+	}
+	if cmds != nil {
+		policy.Notify = cmds.notifyDisk
+	}
+	return policy
+}
+
+// shutdownDeadline returns a channel which is closed once a shutdown has run
+// for longer than allowed by the shutdowntimeout option: once to drain the RPC
+// server, if any, and once more to stop the wallet.  The channel is never
+// closed when no timeout is configured.
+func (d *Daemon) shutdownDeadline(rpcServer bool) <-chan struct{} {
+	deadline := make(chan struct{})
+	if d.cfg.ShutdownTimeout == 0 {
+		return deadline
+	}
+
+	timeout := d.cfg.ShutdownTimeout
+	if rpcServer {
+		timeout *= 2
+	}
+	go func() {
+		select {
+		case <-d.shutdownStarted:
+		case <-d.interruptHandlersDone:
+			return
+		}
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			close(deadline)
+		case <-d.interruptHandlersDone:
+		}
+	}()
+	return deadline
+}
+
+// rpcClientConnectLoop continuously attempts a connection to the consensus RPC
+// server.  When a connection is established, the client is used to sync the
+// loaded wallet, either immediately or when loaded at a later time.
+//
+// The legacy RPC is optional.  If set, the connected RPC client will be
+// associated with the server for RPC passthrough and to enable additional
+// methods.
+//
+// The loop ends when quit is closed, which is used for wallets unloaded at
+// runtime.  A nil quit channel runs the loop until the wallet is stopped.
+func (d *Daemon) rpcClientConnectLoop(legacyRPCServer *legacyrpc.Server,
+	loader *wallet.Loader, quit <-chan struct{}) {
+
+	certs := readCAFile(d.cfg)
+
+	for {
+		var (
+			chainClient chain.Interface
+			err         error
+		)
+
+		select {
+		case <-quit:
+			return
+		default:
+		}
+
+		chainClient, err = startChainRPC(d.cfg, certs)
+		if err != nil {
+			log.Errorf("Unable to open connection to consensus RPC server: %v", err)
+			continue
+		}
+
+		// Disconnect when quitting, even if the client was never
+		// associated with a wallet that would stop it.
+		disconnected := make(chan struct{})
+		go func() {
+			select {
+			case <-quit:
+				chainClient.Stop()
+			case <-disconnected:
+			}
+		}()
+
+		// Rather than inlining this logic directly into the loader
+		// callback, a function variable is used to avoid running any of
+		// this after the client disconnects by setting it to nil.  This
+		// prevents the callback from associating a wallet loaded at a
+		// later time with a client that has already disconnected.  A
+		// mutex is used to make this concurrent safe.
+		associateRPCClient := func(w *wallet.Wallet) {
+			w.SynchronizeRPC(chainClient)
+			if legacyRPCServer != nil {
+				legacyRPCServer.SetChainServer(chainClient)
+			}
+		}
+		mu := new(sync.Mutex)
+		loader.RunAfterLoad(func(w *wallet.Wallet) {
+			mu.Lock()
+			associate := associateRPCClient
+			mu.Unlock()
+			if associate != nil {
+				associate(w)
+			}
+		})
+
+		chainClient.WaitForShutdown()
+		close(disconnected)
+
+		mu.Lock()
+		associateRPCClient = nil
+		mu.Unlock()
+
+		select {
+		case <-quit:
+			return
+		default:
+		}
+
+		loadedWallet, ok := loader.LoadedWallet()
+		if ok {
+			// Do not attempt a reconnect when the wallet was
+			// explicitly stopped.
+			if loadedWallet.ShuttingDown() {
+				return
+			}
+
+			loadedWallet.SetChainSynced(false)
+
+			// TODO: Rework the wallet so changing the RPC client
+			// does not require stopping and restarting everything.
+			loadedWallet.Stop()
+			loadedWallet.WaitForShutdown()
+			loadedWallet.Start()
+		}
+	}
+}
+
+func readCAFile(cfg *Config) []byte {
+	// Read certificate file if TLS is not disabled.
+	var certs []byte
+	if !cfg.DisableClientTLS {
+		var err error
+		certs, err = ioutil.ReadFile(cfg.CAFile.Value)
+		if err != nil {
+			log.Warnf("Cannot open CA file: %v", err)
+			// If there's an error reading the CA file, continue
+			// with nil certs and without the client connection.
+			certs = nil
+		}
+	} else {
+		log.Info("Chain server RPC TLS is disabled")
+	}
+
+	return certs
+}
+
+// startChainRPC opens a RPC client connection to a  server for blockchain
+// services.  This function uses the RPC options from cfg and there is no
+// recovery in case the server is not available or if there is an
+// authentication error.  Instead, all requests to the client will simply error.
+func startChainRPC(cfg *Config, certs []byte) (*chain.RPCClient, error) {
+	log.Infof("Attempting RPC client connection to %v", cfg.RPCConnect)
+	rpcc, err := chain.NewRPCClient(cfg.activeNet.Params, cfg.RPCConnect,
+		cfg.RPCUser, cfg.RPCPass, certs, cfg.DisableClientTLS,
+		cfg.SkipVerify, 0, chainProxy(cfg), chainI2PSAM(cfg),
+		chain.ConnTimeouts{
+			Dial:         cfg.LbcdDialTimeout,
+			Handshake:    cfg.LbcdHandshakeTimeout,
+			PingInterval: cfg.LbcdPingInterval,
+			PongTimeout:  cfg.LbcdPongTimeout,
+		})
+	if err != nil {
+		return nil, err
+	}
+	rpcc.InjectFaults(chain.Faults{
+		NotifyDelay:   cfg.FaultNotifyDelay,
+		DropEvery:     cfg.FaultDropEvery,
+		ReorderWindow: cfg.FaultReorderWindow,
+		Seed:          cfg.FaultSeed,
+	})
+	err = rpcc.Start()
+	return rpcc, err
+}
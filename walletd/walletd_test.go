@@ -0,0 +1,60 @@
+package walletd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/lbryio/lbcwallet/netparams"
+	"github.com/lbryio/lbcwallet/wallet"
+)
+
+// TestNew ensures daemons are created from a configuration built without
+// LoadConfig, each validating its own copy of the options.
+func TestNew(t *testing.T) {
+	dir := t.TempDir()
+	cfg := DefaultConfig()
+	if err := cfg.AppDataDir.UnmarshalFlag(dir); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Regtest = true
+
+	d1, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d2, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d1.cfg == d2.cfg || d1.cfg.AppDataDir == d2.cfg.AppDataDir {
+		t.Fatal("daemons share their configuration")
+	}
+	if d1.currentConfig() != d1.cfg {
+		t.Error("configuration of the daemon was not published")
+	}
+	if d1.cfg.activeNet != &netparams.RegTestParams {
+		t.Errorf("network is %s, want %s",
+			d1.cfg.activeNet.Params.Name,
+			netparams.RegTestParams.Params.Name)
+	}
+
+	// The paths relative to the data directory are adjusted in the copy
+	// of the daemon only.
+	want := filepath.Join(dir, "rpc.key")
+	if d1.cfg.RPCKey.Value != want {
+		t.Errorf("rpckey is %s, want %s", d1.cfg.RPCKey.Value, want)
+	}
+	if cfg.RPCKey.Value == want || cfg.activeNet != nil {
+		t.Error("configuration passed to New was modified")
+	}
+
+	if err := d1.Stop(); err != ErrNotStarted {
+		t.Errorf("Stop before Start returned %v, want %v", err,
+			ErrNotStarted)
+	}
+
+	cfg.KeyPoolSize = wallet.MaxKeyPoolSize + 1
+	if _, err := New(cfg); err == nil {
+		t.Error("invalid configuration was accepted")
+	}
+}
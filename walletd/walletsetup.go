@@ -0,0 +1,504 @@
+// Copyright (c) 2014-2015 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package walletd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lbryio/lbcutil/hdkeychain"
+	"github.com/lbryio/lbcwallet/internal/cfgutil"
+	"github.com/lbryio/lbcwallet/internal/prompt"
+	"github.com/lbryio/lbcwallet/internal/shamir"
+	"github.com/lbryio/lbcwallet/internal/zero"
+	"github.com/lbryio/lbcwallet/netparams"
+	"github.com/lbryio/lbcwallet/waddrmgr"
+	"github.com/lbryio/lbcwallet/wallet"
+	"github.com/lbryio/lbcwallet/walletdb"
+	_ "github.com/lbryio/lbcwallet/walletdb/bdb"
+)
+
+// recoverBirthdayLayout is the layout of the recoverbirthday option.
+const recoverBirthdayLayout = "2006-01-02"
+
+// parseSeedShares parses the <threshold>-of-<count> value of the seedshares
+// option.
+func parseSeedShares(s string) (int, int, error) {
+	var threshold, count int
+	_, err := fmt.Sscanf(s, "%d-of-%d", &threshold, &count)
+	if err != nil || fmt.Sprintf("%d-of-%d", threshold, count) != s {
+		return 0, 0, fmt.Errorf("%q is not <threshold>-of-<count>", s)
+	}
+	if threshold < 1 || threshold > count || count > shamir.MaxShares {
+		return 0, 0, shamir.ErrThreshold
+	}
+	return threshold, count, nil
+}
+
+// networkDir returns the directory name of a network directory to hold wallet
+// files.
+func networkDir(dataDir string, net *netparams.Params) string {
+	netname := net.Name
+	if net.DataDirName != "" {
+		netname = net.DataDirName
+	}
+	return filepath.Join(dataDir, netname)
+}
+
+// namedWalletDir returns the directory of the database of a wallet loaded with
+// the wallet option.
+func namedWalletDir(netDir, name string) string {
+	return filepath.Join(netDir, "wallets", name)
+}
+
+// checkWalletName returns an error when a wallet name can not be used as the
+// name of the directory of the wallet.
+func checkWalletName(name string) error {
+	switch {
+	case name == "", name == ".", name == "..":
+		return fmt.Errorf("invalid wallet name %q", name)
+	case strings.ContainsAny(name, `/\`):
+		return fmt.Errorf("wallet name %q may not contain path "+
+			"separators", name)
+	}
+	return nil
+}
+
+// createWallet prompts the user for information needed to generate a new wallet
+// and generates the wallet accordingly.  The new wallet will reside in the
+// provided directory.
+func createWallet(cfg *Config, dbDir string) error {
+	if dbDir != networkDir(cfg.AppDataDir.Value, cfg.activeNet) {
+		fmt.Printf("Creating the wallet in %s\n", dbDir)
+	}
+	loader := wallet.NewLoader(
+		cfg.activeNet.Params, dbDir, true, cfg.DBTimeout, 250,
+	)
+
+	// Start by prompting for the passphrase.
+	passphrase := []byte(cfg.Passphrase)
+	defer zero.Bytes(passphrase)
+
+	if cfg.CreateFromDump != "" {
+		return createWalletFromDump(loader, passphrase, cfg.CreateFromDump)
+	}
+	if cfg.RecoverXPubs != "" {
+		return createRecoveryWallet(loader, passphrase, cfg)
+	}
+	if cfg.CreateFromShares != "" {
+		return createWalletFromShares(loader, passphrase, cfg)
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	// Ascertain the wallet generation seed.  This will either be an
+	// automatically generated value the user has already confirmed or a
+	// value the user has entered which has already been validated.
+	seed, bday, err := prompt.Seed(reader)
+	if err != nil {
+		return err
+	}
+	defer zero.Bytes(seed)
+
+	// Split the seed before creating the wallet, so that no wallet is
+	// created without its shares.
+	var (
+		shares    []string
+		threshold int
+	)
+	if cfg.SeedShares != "" {
+		var count int
+		threshold, count, err = parseSeedShares(cfg.SeedShares)
+		if err != nil {
+			return err
+		}
+		shares, err = wallet.SplitSeed(seed, threshold, count)
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Println("Creating the wallet...")
+	w, err := loader.CreateNewWallet(passphrase, seed, bday)
+	if err != nil {
+		return err
+	}
+
+	w.Manager.Close()
+
+	fmt.Println("The wallet has been created successfully with birthday:", bday.Format(time.UnixDate))
+
+	if len(shares) != 0 {
+		fmt.Printf("\nThe seed was split into %d Shamir shares.  Keep "+
+			"each share in a different safe place, as any %d "+
+			"of them recover the wallet with --createfromshares:\n",
+			len(shares), threshold)
+		for _, s := range shares {
+			fmt.Println(s)
+		}
+	}
+
+	return nil
+}
+
+// createWalletFromShares creates a new wallet from the master key recovered
+// from the Shamir shares of the file of the createfromshares option, written
+// by the seedshares option or the exportseedshares method.  The transactions
+// are found by the rescan from the recovery birthday, or from the genesis
+// block without it, once the wallet syncs.
+func createWalletFromShares(loader *wallet.Loader, passphrase []byte,
+	cfg *Config) error {
+
+	b, err := ioutil.ReadFile(cfg.CreateFromShares)
+	if err != nil {
+		return err
+	}
+	rootKey, err := wallet.CombineSeedShares(
+		strings.Split(string(b), "\n"), cfg.activeNet.Params,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", cfg.CreateFromShares, err)
+	}
+	defer rootKey.Zero()
+
+	var bday time.Time
+	if cfg.RecoverBirthday != "" {
+		bday, err = time.Parse(recoverBirthdayLayout, cfg.RecoverBirthday)
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Println("Creating the wallet from the shares...")
+	w, err := loader.CreateNewWalletExtendedKey(passphrase, rootKey, bday)
+	if err != nil {
+		return err
+	}
+	w.Manager.Close()
+
+	fmt.Println("The wallet has been created successfully from the " +
+		"shares with birthday: " + bday.Format(time.UnixDate))
+	return nil
+}
+
+// createWalletFromDump creates a new wallet from the master key of a file
+// written by dumpwallet, and restores the accounts, imported keys and labels of
+// the dump.  The transactions are found by the rescan from the birthday block
+// of the dump once the wallet syncs.
+func createWalletFromDump(loader *wallet.Loader, passphrase []byte,
+	path string) error {
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var d wallet.Dump
+	if err := json.Unmarshal(b, &d); err != nil {
+		return wallet.ErrDumpFormat
+	}
+	if d.Format != wallet.DumpFormat {
+		return wallet.ErrDumpFormat
+	}
+	rootKey, err := hdkeychain.NewKeyFromString(d.MasterKey)
+	if err != nil {
+		return fmt.Errorf("invalid master key: %w", err)
+	}
+	bday := time.Unix(d.Birthday, 0)
+
+	fmt.Println("Creating the wallet from the dump...")
+	w, err := loader.CreateNewWalletExtendedKey(passphrase, rootKey, bday)
+	if err != nil {
+		return err
+	}
+	defer w.Manager.Close()
+
+	if err := w.Unlock(passphrase, nil); err != nil {
+		return err
+	}
+	defer w.Lock()
+	if err := w.ImportWallet(&d, false); err != nil {
+		return err
+	}
+
+	fmt.Printf("The wallet has been created successfully from the dump "+
+		"with %d accounts, %d imported keys and %d labels, and "+
+		"birthday: %s\n", len(d.Accounts), len(d.ImportedKeys),
+		len(d.Labels), bday.Format(time.UnixDate))
+	return nil
+}
+
+// createRecoveryWallet creates a wallet from a random seed, which never holds
+// funds, and rebuilds a watch-only account from each account extended public
+// key of the file of the recoverxpubs option.  The history of the accounts is
+// found by the rescan from the recovery birthday once the wallet syncs, and
+// their funds may then be swept with sweepaccountpsbt before their seed is
+// brought out of cold storage to sign.
+func createRecoveryWallet(loader *wallet.Loader, passphrase []byte,
+	cfg *Config) error {
+
+	f, err := os.Open(cfg.RecoverXPubs)
+	if err != nil {
+		return err
+	}
+	accts, err := wallet.ParseRecoveryAccounts(f)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("%s: %w", cfg.RecoverXPubs, err)
+	}
+	if len(accts) == 0 {
+		return fmt.Errorf("%s: no extended public keys", cfg.RecoverXPubs)
+	}
+	bday, err := time.Parse(recoverBirthdayLayout, cfg.RecoverBirthday)
+	if err != nil {
+		return err
+	}
+
+	seed, err := hdkeychain.GenerateSeed(hdkeychain.RecommendedSeedLen)
+	if err != nil {
+		return err
+	}
+	defer zero.Bytes(seed)
+
+	fmt.Println("Creating the recovery wallet...")
+	w, err := loader.CreateNewWallet(passphrase, seed, bday)
+	if err != nil {
+		return err
+	}
+	defer w.Manager.Close()
+
+	props, err := w.ImportRecoveryAccounts(accts, cfg.RecoverWindow)
+	if err != nil {
+		return err
+	}
+	for _, p := range props {
+		fmt.Printf("Account %q of scope %v watches %d external and %d "+
+			"internal addresses\n", p.AccountName, p.KeyScope,
+			p.ExternalKeyCount, p.InternalKeyCount)
+	}
+
+	fmt.Println("The recovery wallet has been created successfully with " +
+		"birthday: " + bday.Format(time.UnixDate))
+	return nil
+}
+
+// changeWalletPass prompts the user for a new public passphrase and
+// re-encrypts the public data of the existing wallet with it.
+func changeWalletPass(cfg *Config) error {
+	dbDir := networkDir(cfg.AppDataDir.Value, cfg.activeNet)
+	loader := wallet.NewLoader(
+		cfg.activeNet.Params, dbDir, true, cfg.DBTimeout, 0,
+	)
+	oldPass := []byte(cfg.WalletPass)
+	if len(oldPass) == 0 {
+		oldPass = []byte(waddrmgr.DefaultPublicPassphrase)
+	}
+	loader.SetPublicPassphrase(oldPass)
+
+	w, err := loader.OpenExistingWallet()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := loader.UnloadWallet(); err != nil {
+			fmt.Fprintln(os.Stderr, "Unable to close wallet:", err)
+		}
+	}()
+
+	newPass, err := prompt.PublicPassphrase()
+	if err != nil {
+		return err
+	}
+	defer zero.Bytes(newPass)
+
+	if err := w.ChangePublicPassphrase(oldPass, newPass); err != nil {
+		return err
+	}
+
+	fmt.Println("The public passphrase has been changed.  Start " +
+		"lbcwallet with the new walletpass from now on.")
+	return nil
+}
+
+// createSimulationWallet is intended to be called from the rpcclient
+// and used to create a wallet for actors involved in simulations.
+func createSimulationWallet(cfg *Config) error {
+	// Simulation wallet password is 'password'.
+	privPass := []byte("password")
+
+	netDir := networkDir(cfg.AppDataDir.Value, cfg.activeNet)
+
+	// Create the wallet.
+	dbPath := filepath.Join(netDir, wallet.WalletDBName)
+	fmt.Println("Creating the wallet...")
+
+	// Create the wallet database backed by bolt db.
+	db, err := walletdb.Create("bdb", dbPath, true, cfg.DBTimeout)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	// Create the wallet.
+	err = wallet.Create(db, privPass, nil, cfg.activeNet.Params, time.Now())
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("The wallet has been created successfully.")
+	return nil
+}
+
+// parseFixture parses the <receives>,<spends>,<claims> value of the
+// createfixture option.
+func parseFixture(s string) (*wallet.FixtureParams, error) {
+	var p wallet.FixtureParams
+	_, err := fmt.Sscanf(s, "%d,%d,%d", &p.Receives, &p.Spends, &p.Claims)
+	if err != nil || fmt.Sprintf("%d,%d,%d", p.Receives, p.Spends,
+		p.Claims) != s {
+
+		return nil, fmt.Errorf("%q is not <receives>,<spends>,<claims>",
+			s)
+	}
+	return &p, nil
+}
+
+// createFixtureWallet creates a wallet from the fixture seed with a
+// deterministic synthetic history, so that tests and benchmarks run against
+// the same state every time.  Its private passphrase is 'password', as for
+// simulation wallets.
+func createFixtureWallet(cfg *Config, p *wallet.FixtureParams) error {
+	netDir := networkDir(cfg.AppDataDir.Value, cfg.activeNet)
+	if err := checkCreateDir(netDir); err != nil {
+		return err
+	}
+	dbPath := filepath.Join(netDir, wallet.WalletDBName)
+	exists, err := cfgutil.FileExists(dbPath)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("the wallet %s already exists", dbPath)
+	}
+
+	fmt.Println("Creating the fixture wallet...")
+	db, err := walletdb.Create("bdb", dbPath, true, cfg.DBTimeout)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	rootKey, err := hdkeychain.NewMaster(wallet.FixtureSeed,
+		cfg.activeNet.Params)
+	if err != nil {
+		return err
+	}
+	err = wallet.Create(db, []byte("password"), rootKey,
+		cfg.activeNet.Params, wallet.FixtureStart)
+	if err != nil {
+		return err
+	}
+	w, err := wallet.Open(db, cfg.activeNet.Params, 0)
+	if err != nil {
+		return err
+	}
+	if err := w.SynthesizeHistory(p); err != nil {
+		return err
+	}
+
+	balance, _, err := w.CalculateBalance(1)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("The wallet has been created with %d receives, %d "+
+		"spends and %d claims, and a balance of %v.\n", p.Receives,
+		p.Spends, p.Claims, balance)
+	return nil
+}
+
+// replayTxLog replays a transaction log written by exporttxlog into a
+// temporary wallet created from the seed of the wallet which exported it, and
+// reports whether its balance and history were reconstructed the same.
+func replayTxLog(cfg *Config, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	seed, err := prompt.ExistingSeed(bufio.NewReader(os.Stdin))
+	if err != nil {
+		return err
+	}
+	defer zero.Bytes(seed)
+
+	dir, err := ioutil.TempDir("", "lbcwallet-replay")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	// The temporary wallet is deleted once replayed, so its passphrase
+	// does not matter.
+	privPass := []byte("password")
+	loader := wallet.NewLoader(
+		cfg.activeNet.Params, dir, true, cfg.DBTimeout, 250,
+	)
+	w, err := loader.CreateNewWallet(privPass, seed, time.Now())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := loader.UnloadWallet(); err != nil {
+			fmt.Fprintln(os.Stderr, "Unable to close wallet:", err)
+		}
+	}()
+	if err := w.Unlock(privPass, nil); err != nil {
+		return err
+	}
+
+	fmt.Println("Replaying the transaction log...")
+	exported, replayed, err := w.ReplayTxLog(f)
+	if exported != nil {
+		for _, s := range []struct {
+			name    string
+			summary *wallet.TxLogSummary
+		}{{"Exported", exported}, {"Replayed", replayed}} {
+			fmt.Printf("%s: %d transactions, balance %v, digest "+
+				"%v\n", s.name, s.summary.Transactions,
+				s.summary.Balance, s.summary.Digest)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Println("The history was reconstructed the same.")
+	return nil
+}
+
+// checkCreateDir checks that the path exists and is a directory.
+// If path does not exist, it is created.
+func checkCreateDir(path string) error {
+	if fi, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			// Attempt data directory creation
+			if err = os.MkdirAll(path, 0700); err != nil {
+				return fmt.Errorf("cannot create directory: %s", err)
+			}
+		} else {
+			return fmt.Errorf("error checking directory: %s", err)
+		}
+	} else {
+		if !fi.IsDir() {
+			return fmt.Errorf("path '%s' is not a directory", path)
+		}
+	}
+
+	return nil
+}
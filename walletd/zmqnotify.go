@@ -0,0 +1,411 @@
+package walletd
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcd/claimtrie/change"
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/internal/zmtp"
+	"github.com/lbryio/lbcwallet/wallet"
+)
+
+// ZMQ notification topics.  The hashtx, rawtx, hashblock and sequence topics
+// use the message formats of bitcoind so existing listeners can consume them.
+const (
+	zmqTopicHashTx    = "hashtx"
+	zmqTopicRawTx     = "rawtx"
+	zmqTopicHashBlock = "hashblock"
+	zmqTopicSequence  = "sequence"
+	zmqTopicWalletTx  = "wallettx"
+	zmqTopicClaim     = "claim"
+	zmqTopicInvoice   = "invoice"
+)
+
+// zmqEndpoints returns the configured endpoint of each ZMQ topic.
+func zmqEndpoints(cfg *Config) map[string]string {
+	endpoints := map[string]string{
+		zmqTopicHashTx:    cfg.ZMQPubHashTx,
+		zmqTopicRawTx:     cfg.ZMQPubRawTx,
+		zmqTopicHashBlock: cfg.ZMQPubHashBlock,
+		zmqTopicSequence:  cfg.ZMQPubSequence,
+		zmqTopicWalletTx:  cfg.ZMQPubWalletTx,
+		zmqTopicClaim:     cfg.ZMQPubClaim,
+		zmqTopicInvoice:   cfg.ZMQPubInvoice,
+	}
+	for topic, endpoint := range endpoints {
+		if endpoint == "" {
+			delete(endpoints, topic)
+		}
+	}
+	return endpoints
+}
+
+// zmqNotifier publishes wallet events to ZMQ subscribers.  Every message has
+// three frames: the topic, the body, and the 4 byte little endian sequence
+// number of the message within its topic.
+type zmqNotifier struct {
+	publishers map[string]*zmtp.Publisher // By topic
+	sequence   map[string]uint32
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newZMQNotifier binds a publisher to every endpoint.  Topics configured with
+// the same endpoint share its publisher.
+func newZMQNotifier(endpoints map[string]string) (*zmqNotifier, error) {
+	n := &zmqNotifier{
+		publishers: make(map[string]*zmtp.Publisher),
+		sequence:   make(map[string]uint32),
+		quit:       make(chan struct{}),
+	}
+	byEndpoint := make(map[string]*zmtp.Publisher)
+	for topic, endpoint := range endpoints {
+		p, ok := byEndpoint[endpoint]
+		if !ok {
+			var err error
+			p, err = zmtp.Listen(endpoint)
+			if err != nil {
+				n.close()
+				return nil, fmt.Errorf("unable to bind ZMQ endpoint "+
+					"%s: %v", endpoint, err)
+			}
+			byEndpoint[endpoint] = p
+		}
+		n.publishers[topic] = p
+		log.Infof("Publishing ZMQ %s notifications on %s", topic,
+			endpoint)
+	}
+	return n, nil
+}
+
+// run publishes the transaction and invoice notifications of a wallet until
+// the notifier is closed.
+func (n *zmqNotifier) run(w *wallet.Wallet) {
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+
+		client := w.NtfnServer.TransactionNotifications()
+		defer client.Done()
+		invoices := w.NtfnServer.InvoiceNotifications()
+		defer invoices.Done()
+		for {
+			select {
+			case ntfn, ok := <-client.C:
+				if !ok {
+					return
+				}
+				n.notify(ntfn)
+			case inv, ok := <-invoices.C:
+				if !ok {
+					return
+				}
+				n.notifyInvoice(inv)
+			case <-n.quit:
+				return
+			}
+		}
+	}()
+}
+
+// close stops publishing and disconnects all subscribers.
+func (n *zmqNotifier) close() {
+	select {
+	case <-n.quit:
+		return
+	default:
+	}
+	close(n.quit)
+	n.wg.Wait()
+
+	closed := make(map[*zmtp.Publisher]struct{})
+	for _, p := range n.publishers {
+		if _, ok := closed[p]; ok {
+			continue
+		}
+		closed[p] = struct{}{}
+		if err := p.Close(); err != nil {
+			log.Warnf("Unable to close ZMQ publisher: %v", err)
+		}
+	}
+}
+
+// publish sends a message on topic if it is configured.
+func (n *zmqNotifier) publish(topic string, body []byte) {
+	p, ok := n.publishers[topic]
+	if !ok {
+		return
+	}
+	var seq [4]byte
+	binary.LittleEndian.PutUint32(seq[:], n.sequence[topic])
+	n.sequence[topic]++
+	p.Publish([]byte(topic), body, seq[:])
+}
+
+// enabled returns whether any of the topics is configured.
+func (n *zmqNotifier) enabled(topics ...string) bool {
+	for _, topic := range topics {
+		if _, ok := n.publishers[topic]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// notify publishes the disconnected blocks, the transactions of the connected
+// blocks followed by the blocks themselves, and the unmined transactions.
+func (n *zmqNotifier) notify(ntfn *wallet.TransactionNotifications) {
+	for _, hash := range ntfn.DetachedBlocks {
+		n.publish(zmqTopicSequence, zmqSequenceBody(hash, 'D'))
+	}
+	for i := range ntfn.AttachedBlocks {
+		block := &ntfn.AttachedBlocks[i]
+		for j := range block.Transactions {
+			n.notifyTx(&block.Transactions[j], block)
+		}
+		n.publish(zmqTopicHashBlock, reversedHash(block.Hash))
+		n.publish(zmqTopicSequence, zmqSequenceBody(block.Hash, 'C'))
+	}
+	for i := range ntfn.UnminedTransactions {
+		n.notifyTx(&ntfn.UnminedTransactions[i], nil)
+	}
+}
+
+// notifyTx publishes a wallet transaction, which is mined in block if it is
+// not nil.
+func (n *zmqNotifier) notifyTx(tx *wallet.TransactionSummary,
+	block *wallet.Block) {
+
+	n.publish(zmqTopicHashTx, reversedHash(tx.Hash))
+	n.publish(zmqTopicRawTx, tx.Transaction)
+
+	if !n.enabled(zmqTopicWalletTx, zmqTopicClaim) {
+		return
+	}
+	var msgTx wire.MsgTx
+	if err := msgTx.Deserialize(bytes.NewReader(tx.Transaction)); err != nil {
+		log.Errorf("Unable to decode transaction %v for ZMQ "+
+			"notification: %v", tx.Hash, err)
+		return
+	}
+
+	if n.enabled(zmqTopicWalletTx) {
+		body, err := json.Marshal(newZMQWalletTx(tx, &msgTx, block))
+		if err != nil {
+			log.Errorf("Unable to encode ZMQ wallettx notification: %v",
+				err)
+		} else {
+			n.publish(zmqTopicWalletTx, body)
+		}
+	}
+	if n.enabled(zmqTopicClaim) {
+		for _, claim := range zmqClaims(tx, &msgTx, block) {
+			body, err := json.Marshal(claim)
+			if err != nil {
+				log.Errorf("Unable to encode ZMQ claim "+
+					"notification: %v", err)
+				continue
+			}
+			n.publish(zmqTopicClaim, body)
+		}
+	}
+}
+
+// zmqInvoice is the body of an invoice message, published when an invoice is
+// paid or expires.
+type zmqInvoice struct {
+	ID       uint64   `json:"id"`
+	Address  string   `json:"address"`
+	Amount   float64  `json:"amount"`
+	Memo     string   `json:"memo,omitempty"`
+	State    string   `json:"state"`
+	Received float64  `json:"received"`
+	TxIDs    []string `json:"txids"`
+}
+
+// notifyInvoice publishes an invoice which was paid or expired.
+func (n *zmqNotifier) notifyInvoice(inv *wallet.Invoice) {
+	if !n.enabled(zmqTopicInvoice) {
+		return
+	}
+	msg := &zmqInvoice{
+		ID:       inv.ID,
+		Address:  inv.Address.EncodeAddress(),
+		Amount:   inv.Amount.ToBTC(),
+		Memo:     inv.Memo,
+		State:    inv.State.String(),
+		Received: inv.Received().ToBTC(),
+		TxIDs:    make([]string, 0, len(inv.Payments)),
+	}
+	for _, p := range inv.Payments {
+		msg.TxIDs = append(msg.TxIDs, p.TxHash.String())
+	}
+	body, err := json.Marshal(msg)
+	if err != nil {
+		log.Errorf("Unable to encode ZMQ invoice notification: %v", err)
+		return
+	}
+	n.publish(zmqTopicInvoice, body)
+}
+
+// reversedHash returns the bytes of a hash in the byte order it is displayed
+// in, as published by bitcoind.
+func reversedHash(hash *chainhash.Hash) []byte {
+	b := make([]byte, chainhash.HashSize)
+	for i := range hash {
+		b[chainhash.HashSize-1-i] = hash[i]
+	}
+	return b
+}
+
+// zmqSequenceBody returns the body of a sequence message for a block
+// connected ('C') or disconnected ('D').
+func zmqSequenceBody(hash *chainhash.Hash, label byte) []byte {
+	return append(reversedHash(hash), label)
+}
+
+// zmqWalletTx is the body of a wallettx message.
+type zmqWalletTx struct {
+	TxID        string          `json:"txid"`
+	Time        int64           `json:"time"`
+	Fee         *float64        `json:"fee,omitempty"`
+	Label       string          `json:"label,omitempty"`
+	BlockHash   string          `json:"blockhash,omitempty"`
+	BlockHeight int32           `json:"blockheight,omitempty"`
+	Debits      []zmqWalletTxIO `json:"debits"`
+	Credits     []zmqWalletTxIO `json:"credits"`
+}
+
+// zmqWalletTxIO is a wallet input or output of a wallettx message.
+type zmqWalletTxIO struct {
+	Index   uint32  `json:"index"`
+	Account uint32  `json:"account"`
+	Amount  float64 `json:"amount"`
+	Change  bool    `json:"change,omitempty"`
+}
+
+func newZMQWalletTx(tx *wallet.TransactionSummary, msgTx *wire.MsgTx,
+	block *wallet.Block) *zmqWalletTx {
+
+	wtx := &zmqWalletTx{
+		TxID:    tx.Hash.String(),
+		Time:    tx.Timestamp,
+		Label:   tx.Label,
+		Debits:  make([]zmqWalletTxIO, 0, len(tx.MyInputs)),
+		Credits: make([]zmqWalletTxIO, 0, len(tx.MyOutputs)),
+	}
+	// The fee is only known when all inputs belong to the wallet.
+	if len(tx.MyInputs) == len(msgTx.TxIn) {
+		fee := tx.Fee.ToBTC()
+		wtx.Fee = &fee
+	}
+	if block != nil {
+		wtx.BlockHash = block.Hash.String()
+		wtx.BlockHeight = block.Height
+	}
+	for _, input := range tx.MyInputs {
+		wtx.Debits = append(wtx.Debits, zmqWalletTxIO{
+			Index:   input.Index,
+			Account: input.PreviousAccount,
+			Amount:  input.PreviousAmount.ToBTC(),
+		})
+	}
+	for _, output := range tx.MyOutputs {
+		if int(output.Index) >= len(msgTx.TxOut) {
+			continue
+		}
+		amount := btcutil.Amount(msgTx.TxOut[output.Index].Value)
+		wtx.Credits = append(wtx.Credits, zmqWalletTxIO{
+			Index:   output.Index,
+			Account: output.Account,
+			Amount:  amount.ToBTC(),
+			Change:  output.Internal,
+		})
+	}
+	return wtx
+}
+
+// zmqClaim is the body of a claim message, published for every claim,
+// support or claim update paid to the wallet.
+type zmqClaim struct {
+	Type        string  `json:"type"`
+	Name        string  `json:"name"`
+	ClaimID     string  `json:"claimid"`
+	TxID        string  `json:"txid"`
+	Vout        uint32  `json:"vout"`
+	Account     uint32  `json:"account"`
+	Amount      float64 `json:"amount"`
+	BlockHash   string  `json:"blockhash,omitempty"`
+	BlockHeight int32   `json:"blockheight,omitempty"`
+}
+
+func zmqClaims(tx *wallet.TransactionSummary, msgTx *wire.MsgTx,
+	block *wallet.Block) []*zmqClaim {
+
+	var claims []*zmqClaim
+	for _, output := range tx.MyOutputs {
+		if int(output.Index) >= len(msgTx.TxOut) {
+			continue
+		}
+		txOut := msgTx.TxOut[output.Index]
+		cs, err := txscript.ExtractClaimScript(txOut.PkScript)
+		if err != nil {
+			continue
+		}
+
+		claim := &zmqClaim{
+			Name:    string(cs.Name),
+			TxID:    tx.Hash.String(),
+			Vout:    output.Index,
+			Account: output.Account,
+			Amount:  btcutil.Amount(txOut.Value).ToBTC(),
+		}
+		switch cs.Opcode {
+		case txscript.OP_CLAIMNAME:
+			claim.Type = "claim"
+			op := wire.OutPoint{Hash: *tx.Hash, Index: output.Index}
+			claim.ClaimID = change.NewClaimID(op).String()
+		case txscript.OP_SUPPORTCLAIM:
+			claim.Type = "support"
+			claim.ClaimID = claimIDString(cs.ClaimID)
+		case txscript.OP_UPDATECLAIM:
+			claim.Type = "update"
+			claim.ClaimID = claimIDString(cs.ClaimID)
+		default:
+			continue
+		}
+		if block != nil {
+			claim.BlockHash = block.Hash.String()
+			claim.BlockHeight = block.Height
+		}
+		claims = append(claims, claim)
+	}
+	return claims
+}
+
+// claimIDString returns the display form of a claim ID as found in a claim
+// script.
+func claimIDString(id []byte) string {
+	var claimID change.ClaimID
+	copy(claimID[:], id)
+	return claimID.String()
+}
+
+// checkZMQEndpoint returns an error if endpoint is not a tcp:// endpoint with a
+// host and port.
+func checkZMQEndpoint(endpoint string) error {
+	if !strings.HasPrefix(endpoint, "tcp://") {
+		return fmt.Errorf("ZMQ endpoint %q must be of the form "+
+			"tcp://host:port", endpoint)
+	}
+	return nil
+}
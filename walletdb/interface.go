@@ -10,6 +10,8 @@ package walletdb
 import (
 	"fmt"
 	"io"
+	"sync/atomic"
+	"time"
 )
 
 // ReadTx represents a database transaction that can only be used for reads.  If
@@ -257,6 +259,11 @@ type BatchDB interface {
 // NOTE: For new code the database backend's View method should be used directly
 // as this package level function will be phased out in the future.
 func View(db DB, f func(tx ReadTx) error) error {
+	start := time.Now()
+	defer func() {
+		atomic.AddUint64(&stats.views, 1)
+		atomic.AddInt64(&stats.viewTime, int64(time.Since(start)))
+	}()
 	return db.View(f, func() {})
 }
 
@@ -270,7 +277,22 @@ func View(db DB, f func(tx ReadTx) error) error {
 // NOTE: For new code the database backend's Update method should be used
 // directly as this package level function will be phased out in the future.
 func Update(db DB, f func(tx ReadWriteTx) error) error {
-	return db.Update(f, func() {})
+	// The time until f is first called is spent waiting for the write
+	// lock.
+	start := time.Now()
+	waited := false
+	defer func() {
+		atomic.AddUint64(&stats.updates, 1)
+		atomic.AddInt64(&stats.updateTime, int64(time.Since(start)))
+	}()
+	return db.Update(func(tx ReadWriteTx) error {
+		if !waited {
+			waited = true
+			atomic.AddInt64(&stats.updateWait,
+				int64(time.Since(start)))
+		}
+		return f(tx)
+	}, func() {})
 }
 
 // Batch opens a database read/write transaction and executes the function f
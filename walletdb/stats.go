@@ -0,0 +1,55 @@
+package walletdb
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Stats are the cumulative counts and durations of the transactions run with
+// View and Update, used to diagnose slow operations.  The durations of two
+// readings are subtracted to measure the database time of an operation, which
+// includes that of the transactions run concurrently with it.
+type Stats struct {
+	// Views and ViewTime are the number and total duration of the
+	// read-only transactions.
+	Views    uint64
+	ViewTime time.Duration
+
+	// Updates and UpdateTime are the number and total duration of the
+	// read/write transactions, which include UpdateWait, the time waited
+	// for the write lock of the database, as only one read/write
+	// transaction runs at a time.
+	Updates    uint64
+	UpdateTime time.Duration
+	UpdateWait time.Duration
+}
+
+// Sub returns the difference of the stats with an earlier reading.
+func (s Stats) Sub(earlier Stats) Stats {
+	return Stats{
+		Views:      s.Views - earlier.Views,
+		ViewTime:   s.ViewTime - earlier.ViewTime,
+		Updates:    s.Updates - earlier.Updates,
+		UpdateTime: s.UpdateTime - earlier.UpdateTime,
+		UpdateWait: s.UpdateWait - earlier.UpdateWait,
+	}
+}
+
+var stats struct {
+	views      uint64
+	viewTime   int64
+	updates    uint64
+	updateTime int64
+	updateWait int64
+}
+
+// ReadStats returns the stats of the transactions run so far.
+func ReadStats() Stats {
+	return Stats{
+		Views:      atomic.LoadUint64(&stats.views),
+		ViewTime:   time.Duration(atomic.LoadInt64(&stats.viewTime)),
+		Updates:    atomic.LoadUint64(&stats.updates),
+		UpdateTime: time.Duration(atomic.LoadInt64(&stats.updateTime)),
+		UpdateWait: time.Duration(atomic.LoadInt64(&stats.updateWait)),
+	}
+}
@@ -0,0 +1,53 @@
+package walletdb_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lbryio/lbcwallet/walletdb"
+)
+
+// TestStats ensures the transactions run with View and Update are counted and
+// timed.
+func TestStats(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "stats.db")
+	db, err := walletdb.Create("bdb", dbPath, true, defaultDBTimeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	before := walletdb.ReadStats()
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		time.Sleep(10 * time.Millisecond)
+		_, err := tx.CreateTopLevelBucket([]byte("stats"))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = walletdb.View(db, func(tx walletdb.ReadTx) error {
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stats := walletdb.ReadStats().Sub(before)
+	if stats.Views < 1 || stats.Updates < 1 {
+		t.Fatalf("%d views and %d updates counted", stats.Views,
+			stats.Updates)
+	}
+	if stats.ViewTime < 10*time.Millisecond ||
+		stats.UpdateTime < 10*time.Millisecond {
+
+		t.Fatalf("views took %v and updates %v", stats.ViewTime,
+			stats.UpdateTime)
+	}
+	if stats.UpdateWait > stats.UpdateTime {
+		t.Fatalf("updates waited %v of %v", stats.UpdateWait,
+			stats.UpdateTime)
+	}
+}
@@ -99,6 +99,9 @@ func dropTransactionHistory(ns walletdb.ReadWriteBucket) error {
 	if err := ns.Delete(rootMinedBalance); err != nil {
 		return err
 	}
+	if err := ns.Delete(rootUTXOGen); err != nil {
+		return err
+	}
 
 	// With everything removed, we'll now recreate our buckets.
 	if err := createBuckets(ns); err != nil {
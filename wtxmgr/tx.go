@@ -200,6 +200,9 @@ type Store struct {
 	// Event callbacks.  These execute in the same goroutine as the wtxmgr
 	// caller.
 	NotifyUnspent func(hash *chainhash.Hash, index uint32)
+
+	// utxos caches the unspent credits.
+	utxos utxoCache
 }
 
 // Open opens the wallet transaction store from a walletdb namespace.  If the
@@ -212,10 +215,15 @@ func Open(ns walletdb.ReadBucket, chainParams *chaincfg.Params) (*Store, error)
 	if err != nil {
 		return nil, err
 	}
-	s := &Store{chainParams, clock.NewDefaultClock(), nil} // TODO: set callbacks
+	s := &Store{chainParams: chainParams, clock: clock.NewDefaultClock()} // TODO: set callbacks
 	return s, nil
 }
 
+// SetClock sets the clock used to determine when output locks have expired.
+func (s *Store) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
 // Create creates a new persistent transaction store in the walletdb namespace.
 // Creating the store when one already exists in this namespace will error with
 // ErrAlreadyExists.
@@ -376,7 +384,10 @@ func (s *Store) InsertTx(ns walletdb.ReadWriteBucket, rec *TxRecord,
 func (s *Store) InsertTxCheckIfExists(ns walletdb.ReadWriteBucket,
 	rec *TxRecord, block *BlockMeta) (bool, error) {
 
-	var err error
+	err := s.bumpUTXOGen(ns)
+	if err != nil {
+		return false, err
+	}
 	if block == nil {
 		if err = s.insertMemPoolTx(ns, rec); err == ErrDuplicateTx {
 			return true, nil
@@ -396,6 +407,10 @@ func (s *Store) InsertTxCheckIfExists(ns walletdb.ReadWriteBucket,
 // identified by the tx record, and also recursively remove all transactions
 // that depend on it.
 func (s *Store) RemoveUnminedTx(ns walletdb.ReadWriteBucket, rec *TxRecord) error {
+	if err := s.bumpUTXOGen(ns); err != nil {
+		return err
+	}
+
 	// As we already have a tx record, we can directly call the
 	// removeConflict method. This will do the job of recursively removing
 	// this unmined transaction, and any transactions that depend on it.
@@ -486,6 +501,9 @@ func (s *Store) AddCredit(ns walletdb.ReadWriteBucket, rec *TxRecord, block *Blo
 		str := "transaction output does not exist"
 		return storeError(ErrInput, str, nil)
 	}
+	if err := s.bumpUTXOGen(ns); err != nil {
+		return err
+	}
 
 	isNew, err := s.addCredit(ns, rec, block, index, change)
 	if err == nil && isNew && s.NotifyUnspent != nil {
@@ -569,6 +587,9 @@ func isStake(out *wire.TxOut) byte {
 // Rollback removes all blocks at height onwards, moving any transactions within
 // each block to the unconfirmed pool.
 func (s *Store) Rollback(ns walletdb.ReadWriteBucket, height int32) error {
+	if err := s.bumpUTXOGen(ns); err != nil {
+		return err
+	}
 	return s.rollback(ns, height)
 }
 
@@ -815,6 +836,27 @@ func (s *Store) rollback(ns walletdb.ReadWriteBucket, height int32) error {
 // UnspentOutputs returns all unspent received transaction outputs.
 // The order is undefined.
 func (s *Store) UnspentOutputs(ns walletdb.ReadBucket) ([]Credit, error) {
+	credits, _, err := s.unspentCredits(ns)
+	if err != nil {
+		return nil, err
+	}
+
+	// Locks expire without writes, so locked outputs are skipped here
+	// rather than when the credits are cached.
+	var unspent []Credit
+	now := s.clock.Now()
+	for _, cred := range credits {
+		if _, _, isLocked := isLockedOutput(ns, cred.OutPoint, now); isLocked {
+			continue
+		}
+		unspent = append(unspent, cred)
+	}
+	return unspent, nil
+}
+
+// unspentOutputs reads the unspent credits which are not spent by unmined
+// transactions, including locked outputs, from the database.
+func (s *Store) unspentOutputs(ns walletdb.ReadBucket) ([]Credit, error) {
 	var unspent []Credit
 
 	var op wire.OutPoint
@@ -825,12 +867,6 @@ func (s *Store) UnspentOutputs(ns walletdb.ReadBucket) ([]Credit, error) {
 			return err
 		}
 
-		// Skip the output if it's locked.
-		_, _, isLocked := isLockedOutput(ns, op, s.clock.Now())
-		if isLocked {
-			return nil
-		}
-
 		if existsRawUnminedInput(ns, k) != nil {
 			// Output is spent by an unmined transaction.
 			// Skip this k/v pair.
@@ -882,12 +918,6 @@ func (s *Store) UnspentOutputs(ns walletdb.ReadBucket) ([]Credit, error) {
 			return err
 		}
 
-		// Skip the output if it's locked.
-		_, _, isLocked := isLockedOutput(ns, op, s.clock.Now())
-		if isLocked {
-			return nil
-		}
-
 		if existsRawUnminedInput(ns, k) != nil {
 			// Output is spent by an unmined transaction.
 			// Skip to next unmined credit.
@@ -932,160 +962,67 @@ func (s *Store) UnspentOutputs(ns walletdb.ReadBucket) ([]Credit, error) {
 // Balance returns the spendable wallet balance (total value of all unspent
 // transaction outputs) given a minimum of minConf confirmations, calculated
 // at a current chain height of curHeight.  Coinbase outputs are only included
-// in the balance if maturity has been reached.
+// in the balance if maturity has been reached.  The staked balance of the
+// claim and support outputs is returned separately.
+//
+// The balance is summed from the totals of the unspent outputs of each block,
+// which are cached until the unspent outputs change.
 //
 // Balance may return unexpected results if syncHeight is lower than the block
 // height of the most recent mined transaction in the store.
 func (s *Store) Balance(ns walletdb.ReadBucket, minConf int32, syncHeight int32) (btcutil.Amount, btcutil.Amount, error) {
-	bal, err := fetchMinedBalance(ns)
+	credits, totals, err := s.unspentCredits(ns)
 	if err != nil {
 		return 0, 0, err
 	}
 
-	// Subtract the balance for each credit that is spent by an unmined transaction or moved to stake.
-	var staked btcutil.Amount
-	var op wire.OutPoint
-	var block Block
-	err = ns.NestedReadBucket(bucketUnspent).ForEach(func(k, v []byte) error {
-		err := readCanonicalOutPoint(k, &op)
-		if err != nil {
-			return err
-		}
-		err = readUnspentBlock(v, &block)
-		if err != nil {
-			return err
-		}
-
-		_, c := existsCredit(ns, &op.Hash, op.Index, &block)
-		amt, flags, err := fetchRawCreditAmountChange(c)
-		if err != nil {
-			return err
-		}
-
-		// Subtract the output's amount if it's locked.
-		_, _, isLocked := isLockedOutput(ns, op, s.clock.Now())
-		if isLocked {
-			bal -= amt
-
-			// To prevent decrementing the balance twice if the
-			// output has an unconfirmed spend, return now.
-			return nil
-		}
-
-		if existsRawUnminedInput(ns, k) != nil {
-			bal -= amt
-		} else if (flags & StakeFlag) > 0 {
-			bal -= amt
-			staked += amt
-		}
-
-		return nil
-	})
-	if err != nil {
-		if _, ok := err.(Error); ok {
-			return 0, 0, err
-		}
-		str := "failed iterating unspent outputs"
-		return 0, 0, storeError(ErrDatabase, str, err)
-	}
-
-	// Decrement the balance for any unspent credit with less than
-	// minConf confirmations and any (unspent) immature coinbase credit.
+	// An output is spendable once it has minConf confirmations, and a
+	// coinbase output once it has matured as well.
 	coinbaseMaturity := int32(s.chainParams.CoinbaseMaturity)
-	stopConf := minConf
-	if coinbaseMaturity > stopConf {
-		stopConf = coinbaseMaturity
+	spendable := func(height int32, coinbase bool) bool {
+		if height == -1 {
+			return minConf == 0
+		}
+		confs := syncHeight - height + 1
+		return confs >= minConf &&
+			(!coinbase || confs >= coinbaseMaturity)
 	}
-	lastHeight := syncHeight - stopConf
-	blockIt := makeReadReverseBlockIterator(ns)
-	for blockIt.prev() {
-		block := &blockIt.elem
 
-		if block.Height < lastHeight {
-			break
+	var bal, staked btcutil.Amount
+	for _, t := range totals.blocks {
+		staked += t.staked
+		if spendable(t.height, false) {
+			bal += t.spendable
 		}
-
-		for i := range block.transactions {
-			txHash := &block.transactions[i]
-			rec, err := fetchTxRecord(ns, txHash, &block.Block)
-			if err != nil {
-				return 0, 0, err
-			}
-			numOuts := uint32(len(rec.MsgTx.TxOut))
-			for i := uint32(0); i < numOuts; i++ {
-				// Avoid double decrementing the credit amount
-				// if it was already removed for being spent by
-				// an unmined tx or being locked.
-				op = wire.OutPoint{Hash: *txHash, Index: i}
-				_, _, isLocked := isLockedOutput(
-					ns, op, s.clock.Now(),
-				)
-				if isLocked {
-					continue
-				}
-				opKey := canonicalOutPoint(txHash, i)
-				if existsRawUnminedInput(ns, opKey) != nil {
-					continue
-				}
-
-				_, v := existsCredit(ns, txHash, i, &block.Block)
-				if v == nil {
-					continue
-				}
-				amt, spent, err := fetchRawCreditAmountSpent(v)
-				if err != nil {
-					return 0, 0, err
-				}
-				if spent {
-					continue
-				}
-				confs := syncHeight - block.Height + 1
-				if confs < minConf || (blockchain.IsCoinBaseTx(&rec.MsgTx) &&
-					confs < coinbaseMaturity) {
-					bal -= amt
-				}
-			}
+		if spendable(t.height, true) {
+			bal += t.coinbase
 		}
 	}
-	if blockIt.err != nil {
-		return 0, 0, blockIt.err
-	}
-
-	// If unmined outputs are included, increment the balance for each
-	// output that is unspent.
 	if minConf == 0 {
-		err = ns.NestedReadBucket(bucketUnminedCredits).ForEach(func(k, v []byte) error {
-			if err := readCanonicalOutPoint(k, &op); err != nil {
-				return err
-			}
-
-			// Skip adding the balance for this output if it's
-			// locked.
-			_, _, isLocked := isLockedOutput(ns, op, s.clock.Now())
-			if isLocked {
-				return nil
-			}
+		bal += totals.unmined
+	}
 
-			if existsRawUnminedInput(ns, k) != nil {
-				// Output is spent by an unmined transaction.
-				// Skip to next unmined credit.
-				return nil
-			}
+	// Locks expire without writes, so the locked outputs are subtracted
+	// here rather than from the cached totals.
+	now := s.clock.Now()
+	err = forEachLockedOutput(ns, func(op wire.OutPoint, _ LockID,
+		expiry time.Time) {
 
-			amount, err := fetchRawUnminedCreditAmount(v)
-			if err != nil {
-				return err
-			}
-			bal += amount
-			return nil
-		})
-		if err != nil {
-			if _, ok := err.(Error); ok {
-				return 0, 0, err
-			}
-			str := "failed to iterate over unmined credits bucket"
-			return 0, 0, storeError(ErrDatabase, str, err)
+		i, ok := totals.index[op]
+		if !ok || !now.Before(expiry) {
+			return
+		}
+		cred := &credits[i]
+		switch {
+		case cred.Height != -1 && isStakeCredit(cred):
+			staked -= cred.Amount
+		case spendable(cred.Height, cred.FromCoinBase):
+			bal -= cred.Amount
 		}
+	})
+	if err != nil {
+		str := "failed iterating locked outputs"
+		return 0, 0, storeError(ErrDatabase, str, err)
 	}
 
 	return bal, staked, nil
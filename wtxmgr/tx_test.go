@@ -7,6 +7,7 @@ package wtxmgr
 import (
 	"bytes"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -2848,3 +2849,218 @@ func TestOutputLocks(t *testing.T) {
 		})
 	}
 }
+
+// TestUnspentOutputsCache ensures the cached unspent outputs follow committed
+// writes and output locks, but not writes which are rolled back.
+func TestUnspentOutputsCache(t *testing.T) {
+	t.Parallel()
+
+	store, db, teardown, err := testStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardown()
+
+	assertUnspent := func(ns walletdb.ReadBucket, want int) {
+		t.Helper()
+
+		unspent, err := store.UnspentOutputs(ns)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(unspent) != want {
+			t.Fatalf("got %d unspent outputs, want %d",
+				len(unspent), want)
+		}
+	}
+	view := func(want int) {
+		t.Helper()
+
+		err := walletdb.View(db, func(tx walletdb.ReadTx) error {
+			assertUnspent(tx.ReadBucket(namespaceKey), want)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b100 := BlockMeta{Block: Block{Height: 100}, Time: time.Now()}
+	cb := newCoinBase(1e8)
+	cbRec, err := NewTxRecordFromMsgTx(cb, b100.Time)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitDBTx(t, store, db, func(ns walletdb.ReadWriteBucket) {
+		if err := store.InsertTx(ns, cbRec, &b100); err != nil {
+			t.Fatal(err)
+		}
+		err := store.AddCredit(ns, cbRec, &b100, 0, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+	view(1)
+	view(1)
+
+	// A spend seen by its own transaction is forgotten when the
+	// transaction is rolled back.
+	spendRec, err := NewTxRecordFromMsgTx(
+		spendOutput(&cbRec.Hash, 0, 1e7), time.Now(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	errRollback := errors.New("rollback")
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(namespaceKey)
+		if err := store.InsertTx(ns, spendRec, nil); err != nil {
+			return err
+		}
+		assertUnspent(ns, 0)
+		return errRollback
+	})
+	if err != errRollback {
+		t.Fatalf("got error %v, want %v", err, errRollback)
+	}
+	view(1)
+
+	// Locked outputs are skipped without a new generation of credits.
+	lockID := LockID{1}
+	commitDBTx(t, store, db, func(ns walletdb.ReadWriteBucket) {
+		op := wire.OutPoint{Hash: cbRec.Hash}
+		_, err := store.LockOutput(ns, lockID, op, time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+	view(0)
+	commitDBTx(t, store, db, func(ns walletdb.ReadWriteBucket) {
+		op := wire.OutPoint{Hash: cbRec.Hash}
+		if err := store.UnlockOutput(ns, lockID, op); err != nil {
+			t.Fatal(err)
+		}
+	})
+	view(1)
+
+	commitDBTx(t, store, db, func(ns walletdb.ReadWriteBucket) {
+		if err := store.InsertTx(ns, spendRec, nil); err != nil {
+			t.Fatal(err)
+		}
+	})
+	view(0)
+}
+
+// TestBalanceCache ensures balances are summed from the cached totals of the
+// unspent outputs, without reading the credits again until they change.
+func TestBalanceCache(t *testing.T) {
+	t.Parallel()
+
+	store, db, teardown, err := testStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardown()
+
+	loads := func() int {
+		store.utxos.mtx.Lock()
+		defer store.utxos.mtx.Unlock()
+		return store.utxos.loads
+	}
+	assertBalance := func(minConf, height int32, want btcutil.Amount) {
+		t.Helper()
+
+		err := walletdb.View(db, func(tx walletdb.ReadTx) error {
+			ns := tx.ReadBucket(namespaceKey)
+			bal, _, err := store.Balance(ns, minConf, height)
+			if err != nil {
+				return err
+			}
+			if bal != want {
+				t.Fatalf("got balance %v with %d "+
+					"confirmations at height %d, want %v",
+					bal, minConf, height, want)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b100 := BlockMeta{Block: Block{Height: 100}, Time: time.Now()}
+	cbRec, err := NewTxRecordFromMsgTx(newCoinBase(1e8), b100.Time)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recvRec, err := NewTxRecordFromMsgTx(
+		spendOutput(&chainhash.Hash{1}, 0, 2e8), b100.Time,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitDBTx(t, store, db, func(ns walletdb.ReadWriteBucket) {
+		for _, rec := range []*TxRecord{cbRec, recvRec} {
+			if err := store.InsertTx(ns, rec, &b100); err != nil {
+				t.Fatal(err)
+			}
+			err := store.AddCredit(ns, rec, &b100, 0, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+
+	maturity := int32(chaincfg.TestNet3Params.CoinbaseMaturity)
+	assertBalance(1, 100, 2e8)
+	start := loads()
+	assertBalance(0, 100, 2e8)
+	assertBalance(2, 100, 0)
+	assertBalance(1, 100+maturity-1, 3e8)
+	assertBalance(maturity+1, 100+maturity-1, 0)
+	if n := loads() - start; n != 0 {
+		t.Fatalf("balances read the unspent outputs %d times after "+
+			"warm-up", n)
+	}
+
+	// Locks expire without writes, so they are applied to the cached
+	// totals.
+	lockID := LockID{1}
+	op := wire.OutPoint{Hash: recvRec.Hash}
+	commitDBTx(t, store, db, func(ns walletdb.ReadWriteBucket) {
+		_, err := store.LockOutput(ns, lockID, op, time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+	assertBalance(1, 100+maturity-1, 1e8)
+	if n := loads() - start; n != 0 {
+		t.Fatalf("balances read the unspent outputs %d times after "+
+			"locking an output", n)
+	}
+	commitDBTx(t, store, db, func(ns walletdb.ReadWriteBucket) {
+		if err := store.UnlockOutput(ns, lockID, op); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	// Spending an output changes the unspent outputs, which are read
+	// again once.
+	spendRec, err := NewTxRecordFromMsgTx(
+		spendOutput(&recvRec.Hash, 0, 1e7), time.Now(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitDBTx(t, store, db, func(ns walletdb.ReadWriteBucket) {
+		if err := store.InsertTx(ns, spendRec, nil); err != nil {
+			t.Fatal(err)
+		}
+	})
+	assertBalance(1, 100+maturity-1, 1e8)
+	assertBalance(0, 100+maturity-1, 1e8)
+	if n := loads() - start; n != 1 {
+		t.Fatalf("balances read the unspent outputs %d times after "+
+			"a spend, want 1", n)
+	}
+}
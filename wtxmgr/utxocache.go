@@ -0,0 +1,188 @@
+package wtxmgr
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/lbryio/lbcd/wire"
+	btcutil "github.com/lbryio/lbcutil"
+	"github.com/lbryio/lbcwallet/walletdb"
+)
+
+// rootUTXOGen is the root bucket key of the generation of the unspent credits.
+// It is changed by every write which may add, spend or move a credit, so that
+// a cached copy of the credits can be checked against the view of any
+// database transaction with a single read.
+var rootUTXOGen = []byte("utxogen")
+
+// utxoCache is an in-memory copy of the unspent credits of the store, which
+// avoids reading and deserializing the transaction of every credit each time
+// the unspent outputs are listed for coin selection.  The totals of the
+// credits by block are kept with them, so that balances are summed without
+// visiting every credit.
+type utxoCache struct {
+	mtx sync.Mutex
+
+	// nextGen is the generation assigned by the next write.  Generations
+	// are never reused by a running store, even when the write is rolled
+	// back.
+	nextGen uint64
+
+	// gen is the generation of the cached credits and valid reports
+	// whether they have been loaded.
+	gen     uint64
+	valid   bool
+	credits []Credit
+	totals  creditTotals
+
+	// loads counts the reads of the unspent credits from the database.
+	loads int
+}
+
+// blockTotals are the totals of the unspent credits mined in a block which are
+// not spent by unmined transactions.
+type blockTotals struct {
+	height int32
+
+	// spendable is the total of the credits which are neither coinbase
+	// outputs nor staked, and coinbase the total of the coinbase outputs
+	// which are not staked.
+	spendable btcutil.Amount
+	coinbase  btcutil.Amount
+
+	// staked is the total of the claim and support outputs.
+	staked btcutil.Amount
+}
+
+// creditTotals are the totals of a generation of the unspent credits.
+type creditTotals struct {
+	// blocks are the totals of the credits of each block, sorted by
+	// height.
+	blocks []blockTotals
+
+	// unmined is the total of the unmined credits.
+	unmined btcutil.Amount
+
+	// index maps the outpoint of every credit to its position in the
+	// cached credits, so the locked ones are found without visiting the
+	// others.
+	index map[wire.OutPoint]int
+}
+
+// isStakeCredit returns whether a credit is a claim or support output, which
+// is staked rather than spendable.
+func isStakeCredit(cred *Credit) bool {
+	return isStake(&wire.TxOut{PkScript: cred.PkScript}) != 0
+}
+
+// newCreditTotals sums the unspent credits by block.
+func newCreditTotals(credits []Credit) creditTotals {
+	totals := creditTotals{
+		index: make(map[wire.OutPoint]int, len(credits)),
+	}
+	byHeight := make(map[int32]*blockTotals)
+	for i := range credits {
+		cred := &credits[i]
+		totals.index[cred.OutPoint] = i
+
+		if cred.Height == -1 {
+			totals.unmined += cred.Amount
+			continue
+		}
+		t, ok := byHeight[cred.Height]
+		if !ok {
+			t = &blockTotals{height: cred.Height}
+			byHeight[cred.Height] = t
+		}
+		switch {
+		case isStakeCredit(cred):
+			t.staked += cred.Amount
+		case cred.FromCoinBase:
+			t.coinbase += cred.Amount
+		default:
+			t.spendable += cred.Amount
+		}
+	}
+
+	totals.blocks = make([]blockTotals, 0, len(byHeight))
+	for _, t := range byHeight {
+		totals.blocks = append(totals.blocks, *t)
+	}
+	sort.Slice(totals.blocks, func(i, j int) bool {
+		return totals.blocks[i].height < totals.blocks[j].height
+	})
+	return totals
+}
+
+func fetchUTXOGen(ns walletdb.ReadBucket) uint64 {
+	v := ns.Get(rootUTXOGen)
+	if len(v) != 8 {
+		return 0
+	}
+	return byteOrder.Uint64(v)
+}
+
+// bumpUTXOGen records a new generation of the unspent credits.  It must be
+// called by every write which may modify the unspent credits.
+func (s *Store) bumpUTXOGen(ns walletdb.ReadWriteBucket) error {
+	s.utxos.mtx.Lock()
+	if s.utxos.nextGen <= fetchUTXOGen(ns) {
+		s.utxos.nextGen = fetchUTXOGen(ns) + 1
+	}
+	gen := s.utxos.nextGen
+	s.utxos.nextGen++
+	s.utxos.mtx.Unlock()
+
+	v := make([]byte, 8)
+	byteOrder.PutUint64(v, gen)
+	if err := ns.Put(rootUTXOGen, v); err != nil {
+		str := "failed to put unspent credits generation"
+		return storeError(ErrDatabase, str, err)
+	}
+	return nil
+}
+
+// cachedUnspent returns the cached unspent credits and their totals if they
+// are those of the generation seen by a database transaction.
+func (s *Store) cachedUnspent(gen uint64) ([]Credit, creditTotals, bool) {
+	s.utxos.mtx.Lock()
+	defer s.utxos.mtx.Unlock()
+
+	if !s.utxos.valid || s.utxos.gen != gen {
+		return nil, creditTotals{}, false
+	}
+	return s.utxos.credits, s.utxos.totals, true
+}
+
+// cacheUnspent replaces the cached unspent credits, and returns their totals.
+func (s *Store) cacheUnspent(gen uint64, credits []Credit) creditTotals {
+	totals := newCreditTotals(credits)
+
+	s.utxos.mtx.Lock()
+	s.utxos.gen = gen
+	s.utxos.valid = true
+	s.utxos.credits = credits
+	s.utxos.totals = totals
+	s.utxos.loads++
+	s.utxos.mtx.Unlock()
+
+	return totals
+}
+
+// unspentCredits returns the unspent credits which are not spent by unmined
+// transactions, including locked outputs, and their totals, from the cache
+// when it holds the generation seen by ns.
+func (s *Store) unspentCredits(ns walletdb.ReadBucket) ([]Credit,
+	creditTotals, error) {
+
+	gen := fetchUTXOGen(ns)
+	credits, totals, ok := s.cachedUnspent(gen)
+	if ok {
+		return credits, totals, nil
+	}
+	credits, err := s.unspentOutputs(ns)
+	if err != nil {
+		return nil, creditTotals{}, err
+	}
+	return credits, s.cacheUnspent(gen, credits), nil
+}